@@ -48,6 +48,11 @@ func (src *Cluster) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.Topology != nil {
 		dst.Spec.Topology = restored.Spec.Topology
 	}
+	dst.Status.Summary = restored.Status.Summary
+	dst.Status.Topology = restored.Status.Topology
+	dst.Spec.Pause = restored.Spec.Pause
+	dst.Spec.MetadataPropagation = restored.Spec.MetadataPropagation
+	dst.Spec.ControlPlaneEndpointRef = restored.Spec.ControlPlaneEndpointRef
 
 	return nil
 }
@@ -99,8 +104,14 @@ func (src *Machine) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.NodeDeletionTimeout = restored.Spec.NodeDeletionTimeout
 	dst.Spec.NodeVolumeDetachTimeout = restored.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Taints = restored.Spec.Taints
+	dst.Spec.InfrastructureReusePolicy = restored.Spec.InfrastructureReusePolicy
+	dst.Spec.NodeDrainOptions = restored.Spec.NodeDrainOptions
+	dst.Spec.AuxiliaryInfrastructureRefs = restored.Spec.AuxiliaryInfrastructureRefs
 	dst.Status.NodeInfo = restored.Status.NodeInfo
 	dst.Status.CertificatesExpiryDate = restored.Status.CertificatesExpiryDate
+	dst.Status.ConditionObservations = restored.Status.ConditionObservations
+	dst.Status.BootstrapProgress = restored.Status.BootstrapProgress
 	return nil
 }
 
@@ -144,6 +155,12 @@ func (src *MachineSet) ConvertTo(dstRaw conversion.Hub) error {
 	}
 	dst.Spec.Template.Spec.NodeDeletionTimeout = restored.Spec.Template.Spec.NodeDeletionTimeout
 	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.Taints = restored.Spec.Template.Spec.Taints
+	dst.Spec.Template.Spec.InfrastructureReusePolicy = restored.Spec.Template.Spec.InfrastructureReusePolicy
+	dst.Spec.Template.Spec.NodeDrainOptions = restored.Spec.Template.Spec.NodeDrainOptions
+	dst.Spec.Template.Spec.AuxiliaryInfrastructureRefs = restored.Spec.Template.Spec.AuxiliaryInfrastructureRefs
+	dst.Spec.MachineNamingStrategy = restored.Spec.MachineNamingStrategy
+	dst.Status.Rollout = restored.Status.Rollout
 	dst.Status.Conditions = restored.Status.Conditions
 	return nil
 }
@@ -199,8 +216,16 @@ func (src *MachineDeployment) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.Template.Spec.NodeDeletionTimeout = restored.Spec.Template.Spec.NodeDeletionTimeout
 	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.Taints = restored.Spec.Template.Spec.Taints
+	dst.Spec.Template.Spec.InfrastructureReusePolicy = restored.Spec.Template.Spec.InfrastructureReusePolicy
+	dst.Spec.Template.Spec.NodeDrainOptions = restored.Spec.Template.Spec.NodeDrainOptions
+	dst.Spec.Template.Spec.AuxiliaryInfrastructureRefs = restored.Spec.Template.Spec.AuxiliaryInfrastructureRefs
 	dst.Spec.RolloutAfter = restored.Spec.RolloutAfter
+	dst.Spec.RollbackTo = restored.Spec.RollbackTo
+	dst.Spec.MachineNamingStrategy = restored.Spec.MachineNamingStrategy
 	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.ProgressDeadline = restored.Status.ProgressDeadline
+	dst.Status.Rollout = restored.Status.Rollout
 	return nil
 }
 
@@ -247,6 +272,10 @@ func (src *MachineHealthCheck) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.UnhealthyRange != nil {
 		dst.Spec.UnhealthyRange = restored.Spec.UnhealthyRange
 	}
+	dst.Spec.UnhealthyNodeExpressions = restored.Spec.UnhealthyNodeExpressions
+	dst.Spec.Reboot = restored.Spec.Reboot
+	dst.Spec.Mode = restored.Spec.Mode
+	dst.Status.DryRunRemediationTargets = restored.Status.DryRunRemediationTargets
 
 	return nil
 }
@@ -288,6 +317,11 @@ func Convert_v1beta1_ClusterSpec_To_v1alpha3_ClusterSpec(in *clusterv1.ClusterSp
 	return autoConvert_v1beta1_ClusterSpec_To_v1alpha3_ClusterSpec(in, out, s)
 }
 
+func Convert_v1beta1_ClusterStatus_To_v1alpha3_ClusterStatus(in *clusterv1.ClusterStatus, out *ClusterStatus, s apiconversion.Scope) error {
+	// status.summary has been added with v1beta1.
+	return autoConvert_v1beta1_ClusterStatus_To_v1alpha3_ClusterStatus(in, out, s)
+}
+
 func Convert_v1alpha3_Bootstrap_To_v1beta1_Bootstrap(in *Bootstrap, out *clusterv1.Bootstrap, s apiconversion.Scope) error {
 	return autoConvert_v1alpha3_Bootstrap_To_v1beta1_Bootstrap(in, out, s)
 }
@@ -300,6 +334,11 @@ func Convert_v1beta1_MachineHealthCheckSpec_To_v1alpha3_MachineHealthCheckSpec(i
 	return autoConvert_v1beta1_MachineHealthCheckSpec_To_v1alpha3_MachineHealthCheckSpec(in, out, s)
 }
 
+func Convert_v1beta1_MachineHealthCheckStatus_To_v1alpha3_MachineHealthCheckStatus(in *clusterv1.MachineHealthCheckStatus, out *MachineHealthCheckStatus, s apiconversion.Scope) error {
+	// MachineHealthCheckStatus.DryRunRemediationTargets has been added in v1beta1.
+	return autoConvert_v1beta1_MachineHealthCheckStatus_To_v1alpha3_MachineHealthCheckStatus(in, out, s)
+}
+
 func Convert_v1alpha3_ClusterStatus_To_v1beta1_ClusterStatus(in *ClusterStatus, out *clusterv1.ClusterStatus, s apiconversion.Scope) error {
 	return autoConvert_v1alpha3_ClusterStatus_To_v1beta1_ClusterStatus(in, out, s)
 }
@@ -309,15 +348,27 @@ func Convert_v1alpha3_ObjectMeta_To_v1beta1_ObjectMeta(in *ObjectMeta, out *clus
 }
 
 func Convert_v1beta1_MachineStatus_To_v1alpha3_MachineStatus(in *clusterv1.MachineStatus, out *MachineStatus, s apiconversion.Scope) error {
+	// MachineStatus.ConditionObservations has been added in v1beta1.
+	// MachineStatus.BootstrapProgress has been added in v1beta1.
 	return autoConvert_v1beta1_MachineStatus_To_v1alpha3_MachineStatus(in, out, s)
 }
 
 func Convert_v1beta1_MachineSpec_To_v1alpha3_MachineSpec(in *clusterv1.MachineSpec, out *MachineSpec, s apiconversion.Scope) error {
 	// spec.nodeDeletionTimeout has been added with v1beta1.
+	// spec.taints has been added with v1beta1.
+	// spec.infrastructureReusePolicy has been added with v1beta1.
+	// spec.nodeDrainOptions has been added with v1beta1.
+	// spec.auxiliaryInfrastructureRefs has been added with v1beta1.
 	return autoConvert_v1beta1_MachineSpec_To_v1alpha3_MachineSpec(in, out, s)
 }
 
+func Convert_v1beta1_MachineSetSpec_To_v1alpha3_MachineSetSpec(in *clusterv1.MachineSetSpec, out *MachineSetSpec, s apiconversion.Scope) error {
+	// spec.machineNamingStrategy has been added with v1beta1.
+	return autoConvert_v1beta1_MachineSetSpec_To_v1alpha3_MachineSetSpec(in, out, s)
+}
+
 func Convert_v1beta1_MachineDeploymentSpec_To_v1alpha3_MachineDeploymentSpec(in *clusterv1.MachineDeploymentSpec, out *MachineDeploymentSpec, s apiconversion.Scope) error {
+	// spec.machineNamingStrategy has been added with v1beta1.
 	return autoConvert_v1beta1_MachineDeploymentSpec_To_v1alpha3_MachineDeploymentSpec(in, out, s)
 }
 
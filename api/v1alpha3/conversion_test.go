@@ -130,6 +130,7 @@ func clusterSpokeAfterMutation(c conversion.Convertible) {
 func ClusterJSONFuzzFuncs(_ runtimeserializer.CodecFactory) []interface{} {
 	return []interface{}{
 		ClusterVariableFuzzer,
+		ClusterTopologyVariableStatusFuzzer,
 	}
 }
 
@@ -139,3 +140,10 @@ func ClusterVariableFuzzer(in *clusterv1.ClusterVariable, c fuzz.Continue) {
 	// Not every random byte array is valid JSON, e.g. a string without `""`,so we're setting a valid value.
 	in.Value = apiextensionsv1.JSON{Raw: []byte("\"test-string\"")}
 }
+
+func ClusterTopologyVariableStatusFuzzer(in *clusterv1.ClusterTopologyVariableStatus, c fuzz.Continue) {
+	c.FuzzNoCustom(in)
+
+	// Not every random byte array is valid JSON, e.g. a string without `""`,so we're setting a valid value.
+	in.Value = &apiextensionsv1.JSON{Raw: []byte("\"test-string\"")}
+}
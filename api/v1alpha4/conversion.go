@@ -75,6 +75,11 @@ func (src *Cluster) ConvertTo(dstRaw conversion.Hub) error {
 			}
 		}
 	}
+	dst.Status.Summary = restored.Status.Summary
+	dst.Status.Topology = restored.Status.Topology
+	dst.Spec.Pause = restored.Spec.Pause
+	dst.Spec.MetadataPropagation = restored.Spec.MetadataPropagation
+	dst.Spec.ControlPlaneEndpointRef = restored.Spec.ControlPlaneEndpointRef
 
 	return nil
 }
@@ -121,6 +126,7 @@ func (src *ClusterClass) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.Patches = restored.Spec.Patches
 	dst.Spec.Variables = restored.Spec.Variables
+	dst.Spec.Lineage = restored.Spec.Lineage
 	dst.Spec.ControlPlane.MachineHealthCheck = restored.Spec.ControlPlane.MachineHealthCheck
 	dst.Spec.ControlPlane.NodeDrainTimeout = restored.Spec.ControlPlane.NodeDrainTimeout
 	dst.Spec.ControlPlane.NodeVolumeDetachTimeout = restored.Spec.ControlPlane.NodeVolumeDetachTimeout
@@ -183,7 +189,13 @@ func (src *Machine) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.NodeDeletionTimeout = restored.Spec.NodeDeletionTimeout
 	dst.Status.CertificatesExpiryDate = restored.Status.CertificatesExpiryDate
+	dst.Status.ConditionObservations = restored.Status.ConditionObservations
+	dst.Status.BootstrapProgress = restored.Status.BootstrapProgress
 	dst.Spec.NodeVolumeDetachTimeout = restored.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Taints = restored.Spec.Taints
+	dst.Spec.InfrastructureReusePolicy = restored.Spec.InfrastructureReusePolicy
+	dst.Spec.NodeDrainOptions = restored.Spec.NodeDrainOptions
+	dst.Spec.AuxiliaryInfrastructureRefs = restored.Spec.AuxiliaryInfrastructureRefs
 	return nil
 }
 
@@ -229,6 +241,12 @@ func (src *MachineSet) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.Template.Spec.NodeDeletionTimeout = restored.Spec.Template.Spec.NodeDeletionTimeout
 	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.Taints = restored.Spec.Template.Spec.Taints
+	dst.Spec.Template.Spec.InfrastructureReusePolicy = restored.Spec.Template.Spec.InfrastructureReusePolicy
+	dst.Spec.Template.Spec.NodeDrainOptions = restored.Spec.Template.Spec.NodeDrainOptions
+	dst.Spec.Template.Spec.AuxiliaryInfrastructureRefs = restored.Spec.Template.Spec.AuxiliaryInfrastructureRefs
+	dst.Spec.MachineNamingStrategy = restored.Spec.MachineNamingStrategy
+	dst.Status.Rollout = restored.Status.Rollout
 	return nil
 }
 
@@ -270,7 +288,15 @@ func (src *MachineDeployment) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.Template.Spec.NodeDeletionTimeout = restored.Spec.Template.Spec.NodeDeletionTimeout
 	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.Taints = restored.Spec.Template.Spec.Taints
+	dst.Spec.Template.Spec.InfrastructureReusePolicy = restored.Spec.Template.Spec.InfrastructureReusePolicy
+	dst.Spec.Template.Spec.NodeDrainOptions = restored.Spec.Template.Spec.NodeDrainOptions
+	dst.Spec.Template.Spec.AuxiliaryInfrastructureRefs = restored.Spec.Template.Spec.AuxiliaryInfrastructureRefs
 	dst.Spec.RolloutAfter = restored.Spec.RolloutAfter
+	dst.Spec.RollbackTo = restored.Spec.RollbackTo
+	dst.Spec.MachineNamingStrategy = restored.Spec.MachineNamingStrategy
+	dst.Status.ProgressDeadline = restored.Status.ProgressDeadline
+	dst.Status.Rollout = restored.Status.Rollout
 	return nil
 }
 
@@ -300,13 +326,32 @@ func (dst *MachineDeploymentList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *MachineHealthCheck) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*clusterv1.MachineHealthCheck)
 
-	return Convert_v1alpha4_MachineHealthCheck_To_v1beta1_MachineHealthCheck(src, dst, nil)
+	if err := Convert_v1alpha4_MachineHealthCheck_To_v1beta1_MachineHealthCheck(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &clusterv1.MachineHealthCheck{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.UnhealthyNodeExpressions = restored.Spec.UnhealthyNodeExpressions
+	dst.Spec.Reboot = restored.Spec.Reboot
+	dst.Spec.Mode = restored.Spec.Mode
+	dst.Status.DryRunRemediationTargets = restored.Status.DryRunRemediationTargets
+	return nil
 }
 
 func (dst *MachineHealthCheck) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*clusterv1.MachineHealthCheck)
 
-	return Convert_v1beta1_MachineHealthCheck_To_v1alpha4_MachineHealthCheck(src, dst, nil)
+	if err := Convert_v1beta1_MachineHealthCheck_To_v1alpha4_MachineHealthCheck(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
 }
 
 func (src *MachineHealthCheckList) ConvertTo(dstRaw conversion.Hub) error {
@@ -327,16 +372,26 @@ func Convert_v1alpha4_MachineStatus_To_v1beta1_MachineStatus(in *MachineStatus,
 }
 
 func Convert_v1beta1_ClusterClassSpec_To_v1alpha4_ClusterClassSpec(in *clusterv1.ClusterClassSpec, out *ClusterClassSpec, s apiconversion.Scope) error {
-	// spec.{variables,patches} has been added with v1beta1.
+	// spec.{variables,patches,lineage} has been added with v1beta1.
 	return autoConvert_v1beta1_ClusterClassSpec_To_v1alpha4_ClusterClassSpec(in, out, s)
 }
 
 func Convert_v1beta1_MachineSpec_To_v1alpha4_MachineSpec(in *clusterv1.MachineSpec, out *MachineSpec, s apiconversion.Scope) error {
 	// spec.nodeDeletionTimeout has been added with v1beta1.
+	// spec.taints has been added with v1beta1.
+	// spec.infrastructureReusePolicy has been added with v1beta1.
+	// spec.nodeDrainOptions has been added with v1beta1.
+	// spec.auxiliaryInfrastructureRefs has been added with v1beta1.
 	return autoConvert_v1beta1_MachineSpec_To_v1alpha4_MachineSpec(in, out, s)
 }
 
+func Convert_v1beta1_MachineSetSpec_To_v1alpha4_MachineSetSpec(in *clusterv1.MachineSetSpec, out *MachineSetSpec, s apiconversion.Scope) error {
+	// spec.machineNamingStrategy has been added with v1beta1.
+	return autoConvert_v1beta1_MachineSetSpec_To_v1alpha4_MachineSetSpec(in, out, s)
+}
+
 func Convert_v1beta1_MachineDeploymentSpec_To_v1alpha4_MachineDeploymentSpec(in *clusterv1.MachineDeploymentSpec, out *MachineDeploymentSpec, s apiconversion.Scope) error {
+	// spec.machineNamingStrategy has been added with v1beta1.
 	return autoConvert_v1beta1_MachineDeploymentSpec_To_v1alpha4_MachineDeploymentSpec(in, out, s)
 }
 
@@ -345,6 +400,16 @@ func Convert_v1beta1_Topology_To_v1alpha4_Topology(in *clusterv1.Topology, out *
 	return autoConvert_v1beta1_Topology_To_v1alpha4_Topology(in, out, s)
 }
 
+func Convert_v1beta1_MachineDeploymentStatus_To_v1alpha4_MachineDeploymentStatus(in *clusterv1.MachineDeploymentStatus, out *MachineDeploymentStatus, s apiconversion.Scope) error {
+	// status.progressDeadline and status.rollout have been added with v1beta1.
+	return autoConvert_v1beta1_MachineDeploymentStatus_To_v1alpha4_MachineDeploymentStatus(in, out, s)
+}
+
+func Convert_v1beta1_MachineSetStatus_To_v1alpha4_MachineSetStatus(in *clusterv1.MachineSetStatus, out *MachineSetStatus, s apiconversion.Scope) error {
+	// status.rollout has been added with v1beta1.
+	return autoConvert_v1beta1_MachineSetStatus_To_v1alpha4_MachineSetStatus(in, out, s)
+}
+
 // Convert_v1beta1_MachineDeploymentTopology_To_v1alpha4_MachineDeploymentTopology is an autogenerated conversion function.
 func Convert_v1beta1_MachineDeploymentTopology_To_v1alpha4_MachineDeploymentTopology(in *clusterv1.MachineDeploymentTopology, out *MachineDeploymentTopology, s apiconversion.Scope) error {
 	// MachineDeploymentTopology.FailureDomain has been added with v1beta1.
@@ -367,11 +432,33 @@ func Convert_v1beta1_ControlPlaneTopology_To_v1alpha4_ControlPlaneTopology(in *c
 }
 
 func Convert_v1beta1_MachineStatus_To_v1alpha4_MachineStatus(in *clusterv1.MachineStatus, out *MachineStatus, s apiconversion.Scope) error {
+	// MachineStatus.ConditionObservations has been added in v1beta1.
+	// MachineStatus.BootstrapProgress has been added in v1beta1.
 	// MachineStatus.CertificatesExpiryDate has been added in v1beta1.
 	return autoConvert_v1beta1_MachineStatus_To_v1alpha4_MachineStatus(in, out, s)
 }
 
+func Convert_v1beta1_MachineHealthCheckSpec_To_v1alpha4_MachineHealthCheckSpec(in *clusterv1.MachineHealthCheckSpec, out *MachineHealthCheckSpec, s apiconversion.Scope) error {
+	// MachineHealthCheckSpec.UnhealthyNodeExpressions, MachineHealthCheckSpec.Reboot and MachineHealthCheckSpec.Mode have been added in v1beta1.
+	return autoConvert_v1beta1_MachineHealthCheckSpec_To_v1alpha4_MachineHealthCheckSpec(in, out, s)
+}
+
+func Convert_v1beta1_MachineHealthCheckStatus_To_v1alpha4_MachineHealthCheckStatus(in *clusterv1.MachineHealthCheckStatus, out *MachineHealthCheckStatus, s apiconversion.Scope) error {
+	// MachineHealthCheckStatus.DryRunRemediationTargets has been added in v1beta1.
+	return autoConvert_v1beta1_MachineHealthCheckStatus_To_v1alpha4_MachineHealthCheckStatus(in, out, s)
+}
+
 func Convert_v1beta1_ClusterClass_To_v1alpha4_ClusterClass(in *clusterv1.ClusterClass, out *ClusterClass, s apiconversion.Scope) error {
 	// ClusterClass.Status has been added in v1beta1.
 	return autoConvert_v1beta1_ClusterClass_To_v1alpha4_ClusterClass(in, out, s)
 }
+
+func Convert_v1beta1_ClusterSpec_To_v1alpha4_ClusterSpec(in *clusterv1.ClusterSpec, out *ClusterSpec, s apiconversion.Scope) error {
+	// spec.pause and spec.metadataPropagation have been added with v1beta1.
+	return autoConvert_v1beta1_ClusterSpec_To_v1alpha4_ClusterSpec(in, out, s)
+}
+
+func Convert_v1beta1_ClusterStatus_To_v1alpha4_ClusterStatus(in *clusterv1.ClusterStatus, out *ClusterStatus, s apiconversion.Scope) error {
+	// status.summary has been added with v1beta1.
+	return autoConvert_v1beta1_ClusterStatus_To_v1alpha4_ClusterStatus(in, out, s)
+}
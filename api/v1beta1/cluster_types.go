@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -44,6 +45,12 @@ type ClusterSpec struct {
 	// +optional
 	Paused bool `json:"paused,omitempty"`
 
+	// Pause can be used to prevent controllers from processing the Cluster and all its associated objects,
+	// recording why and by whom the Cluster has been paused; if ExpiresAt is set, the Cluster is automatically
+	// unpaused when the pause expires.
+	// +optional
+	Pause *ClusterPause `json:"pause,omitempty"`
+
 	// Cluster network configuration.
 	// +optional
 	ClusterNetwork *ClusterNetwork `json:"clusterNetwork,omitempty"`
@@ -52,6 +59,13 @@ type ClusterSpec struct {
 	// +optional
 	ControlPlaneEndpoint APIEndpoint `json:"controlPlaneEndpoint,omitempty"`
 
+	// ControlPlaneEndpointRef is an optional reference to an object in the namespace of the Cluster the
+	// ControlPlaneEndpoint is sourced from, e.g. a Service of type LoadBalancer, or an object of a provider managing
+	// external load balancers or VIPs which reports the endpoint in status.controlPlaneEndpoint.
+	// When set, ControlPlaneEndpoint is set from the referenced object instead of from the InfrastructureCluster.
+	// +optional
+	ControlPlaneEndpointRef *corev1.ObjectReference `json:"controlPlaneEndpointRef,omitempty"`
+
 	// ControlPlaneRef is an optional reference to a provider-specific resource that holds
 	// the details for provisioning the Control Plane for a Cluster.
 	// +optional
@@ -68,6 +82,46 @@ type ClusterSpec struct {
 	// this feature is highly experimental, and parts of it might still be not implemented.
 	// +optional
 	Topology *Topology `json:"topology,omitempty"`
+
+	// MetadataPropagation defines the labels and annotations of the Cluster which are propagated to the
+	// objects of the Cluster, and kept in sync when they change.
+	// +optional
+	MetadataPropagation *ClusterMetadataPropagation `json:"metadataPropagation,omitempty"`
+}
+
+// ClusterMetadataPropagation defines which labels and annotations of a Cluster are propagated to the MachineDeployments,
+// MachineSets, MachinePools, Machines, bootstrap configs and Secrets of the Cluster.
+// Labels and annotations set on those objects by users or by other controllers take precedence over the values
+// propagated from the Cluster.
+type ClusterMetadataPropagation struct {
+	// Labels is the list of the keys of the Cluster labels to propagate.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// Annotations is the list of the keys of the Cluster annotations to propagate.
+	// +optional
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// ClusterPause describes why, by whom and until when a Cluster is paused.
+type ClusterPause struct {
+	// Reason is a human-readable explanation of why the Cluster is paused, e.g. a maintenance window.
+	// +kubebuilder:validation:MinLength=1
+	Reason string `json:"reason"`
+
+	// Actor identifies who paused the Cluster, e.g. a user or an external tool.
+	// +optional
+	Actor string `json:"actor,omitempty"`
+
+	// ExpiresAt is the time after which the Cluster is automatically unpaused; if not set, the Cluster
+	// stays paused until the pause is removed.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// IsActive returns true if the pause has not expired at the given time.
+func (p *ClusterPause) IsActive(now time.Time) bool {
+	return p != nil && (p.ExpiresAt == nil || now.Before(p.ExpiresAt.Time))
 }
 
 // Topology encapsulates the information of the managed resources.
@@ -340,6 +394,16 @@ type ClusterStatus struct {
 	// +optional
 	ControlPlaneReady bool `json:"controlPlaneReady"`
 
+	// Summary aggregates the replica counts of the control plane and of the workers of the cluster,
+	// together with the results of the MachineHealthChecks targeting it.
+	// +optional
+	Summary *ClusterSummary `json:"summary,omitempty"`
+
+	// Topology reports the resolved variable values and the patches applied to the objects of the managed
+	// topology in the last reconcile, if the Cluster has a managed topology.
+	// +optional
+	Topology *ClusterTopologyStatus `json:"topology,omitempty"`
+
 	// Conditions defines current service state of the cluster.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
@@ -351,6 +415,108 @@ type ClusterStatus struct {
 
 // ANCHOR_END: ClusterStatus
 
+// ANCHOR: ClusterSummary
+
+// ClusterSummary aggregates the health of the objects belonging to a Cluster, so it is
+// possible to monitor the cluster without walking the whole tree of descendants.
+type ClusterSummary struct {
+	// ControlPlane reports the replica counts of the control plane, if the control plane provider
+	// supports replicas.
+	// +optional
+	ControlPlane *ReplicasSummary `json:"controlPlane,omitempty"`
+
+	// Workers reports the replica counts of all the MachineDeployments and MachinePools belonging to the cluster.
+	// +optional
+	Workers ReplicasSummary `json:"workers,omitempty"`
+
+	// UnhealthyMachines is the number of Machines currently failing the MachineHealthChecks targeting the cluster;
+	// Machines still within the node startup timeout are not counted.
+	// +optional
+	UnhealthyMachines int32 `json:"unhealthyMachines,omitempty"`
+}
+
+// ReplicasSummary reports aggregated replica counts.
+type ReplicasSummary struct {
+	// Replicas is the total number of desired replicas.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the total number of ready replicas.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// UnavailableReplicas is the total number of unavailable replicas.
+	// +optional
+	UnavailableReplicas int32 `json:"unavailableReplicas,omitempty"`
+}
+
+// ANCHOR_END: ClusterSummary
+
+// ANCHOR: ClusterTopologyStatus
+
+// ClusterTopologyStatus reports how the managed topology of a Cluster has been computed in the last reconcile,
+// so it is possible to debug variable precedence and patch interactions.
+type ClusterTopologyStatus struct {
+	// Variables lists the resolved values of the variables, after defaulting, including
+	// the values overridden for individual MachineDeployment topologies.
+	// Values of variables with a schema using the password format are redacted.
+	// +optional
+	Variables []ClusterTopologyVariableStatus `json:"variables,omitempty"`
+
+	// Patches lists the patches of the ClusterClass, in the order they have been applied,
+	// together with the objects they modified.
+	// +optional
+	Patches []ClusterTopologyPatchStatus `json:"patches,omitempty"`
+}
+
+// ClusterTopologyVariableStatus reports the resolved value of a variable.
+type ClusterTopologyVariableStatus struct {
+	// Name of the variable.
+	Name string `json:"name"`
+
+	// DefinitionFrom specifies where the definition of this variable is from, if set in the Cluster topology.
+	// +optional
+	DefinitionFrom string `json:"definitionFrom,omitempty"`
+
+	// MachineDeployment is the name of the MachineDeployment topology the value is overridden for;
+	// it is empty for the values set at Cluster level.
+	// +optional
+	MachineDeployment string `json:"machineDeployment,omitempty"`
+
+	// Value of the variable; it is not set if the value is redacted.
+	// +optional
+	Value *apiextensionsv1.JSON `json:"value,omitempty"`
+
+	// Redacted is true if the value of the variable is not reported because it is sensitive.
+	// +optional
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// ClusterTopologyPatchStatus reports the objects modified by a patch of the ClusterClass.
+type ClusterTopologyPatchStatus struct {
+	// Name of the patch.
+	Name string `json:"name"`
+
+	// Targets lists the objects modified by the patch; it is empty if the patch did not modify any object,
+	// e.g. because it is not enabled or because its selectors do not match any template.
+	// +optional
+	Targets []ClusterTopologyPatchTarget `json:"targets,omitempty"`
+}
+
+// ClusterTopologyPatchTarget identifies an object modified by a patch through the object referencing it.
+type ClusterTopologyPatchTarget struct {
+	// HolderKind is the kind of the object referencing the modified object, e.g. Cluster or MachineDeployment.
+	HolderKind string `json:"holderKind"`
+
+	// HolderName is the name of the object referencing the modified object.
+	HolderName string `json:"holderName"`
+
+	// FieldPath is the path of the field referencing the modified object, e.g. spec.infrastructureRef.
+	FieldPath string `json:"fieldPath"`
+}
+
+// ANCHOR_END: ClusterTopologyStatus
+
 // SetTypedPhase sets the Phase field to the string representation of ClusterPhase.
 func (c *ClusterStatus) SetTypedPhase(p ClusterPhase) {
 	c.Phase = string(p)
@@ -71,6 +71,23 @@ type ClusterClassSpec struct {
 	// Note: Patches will be applied in the order of the array.
 	// +optional
 	Patches []ClusterClassPatch `json:"patches,omitempty"`
+
+	// Lineage identifies the ClusterClass as a revision of a family of ClusterClasses, e.g. my-class-v1
+	// and my-class-v2, so Clusters can be rebased to newer revisions gradually instead of mutating a
+	// shared ClusterClass in place.
+	// +optional
+	Lineage *ClusterClassLineage `json:"lineage,omitempty"`
+}
+
+// ClusterClassLineage identifies a revision of a family of ClusterClasses.
+type ClusterClassLineage struct {
+	// Name of the lineage; it is the same for all the revisions of the ClusterClass.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Revision of the ClusterClass in the lineage; newer revisions have a higher value.
+	// +kubebuilder:validation:Minimum=1
+	Revision int32 `json:"revision"`
 }
 
 // ControlPlaneClass defines the class for the control plane.
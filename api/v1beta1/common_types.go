@@ -61,6 +61,20 @@ const (
 	// update that disallows a pre-existing Cluster to be populated with Topology information and Class.
 	ClusterTopologyUnsafeUpdateClassNameAnnotation = "unsafe.topology.cluster.x-k8s.io/disable-update-class-name-check"
 
+	// ClusterTopologyUnsafeAllowClassDowngradeAnnotation can be used to disable the webhook check on update that
+	// disallows rebasing a Cluster to an older or to the same revision of the lineage of its ClusterClass, e.g. to
+	// roll back to the previous revision.
+	ClusterTopologyUnsafeAllowClassDowngradeAnnotation = "unsafe.topology.cluster.x-k8s.io/allow-class-downgrade"
+
+	// ClusterClassRolloutAcknowledgementRequiredAnnotation can be set on a ClusterClass to require an explicit
+	// acknowledgement for changes rolling out machines of the Clusters using the ClusterClass.
+	ClusterClassRolloutAcknowledgementRequiredAnnotation = "topology.cluster.x-k8s.io/rollout-acknowledgement-required"
+
+	// ClusterClassRolloutAcknowledgedAnnotation acknowledges a change to a ClusterClass rolling out machines of the
+	// Clusters using it. The value must be the token reported by the ClusterClass validation webhook for the change,
+	// so an acknowledgement applies only to the change it has been given for.
+	ClusterClassRolloutAcknowledgedAnnotation = "topology.cluster.x-k8s.io/rollout-acknowledged"
+
 	// ProviderNameLabel is the label set on components in the provider manifest.
 	// This label allows to easily identify all the components belonging to a provider; the clusterctl
 	// tool uses this label for implementing provider's lifecycle operations.
@@ -81,6 +95,10 @@ const (
 	// LabelsFromMachineAnnotation is the annotation set on nodes to track the labels originated from machines.
 	LabelsFromMachineAnnotation = "cluster.x-k8s.io/labels-from-machine"
 
+	// TaintsFromMachineAnnotation is the annotation set on nodes to track the taints originated from machines,
+	// in the key:effect format.
+	TaintsFromMachineAnnotation = "cluster.x-k8s.io/taints-from-machine"
+
 	// OwnerNameAnnotation is the annotation set on nodes identifying the owner name.
 	OwnerNameAnnotation = "cluster.x-k8s.io/owner-name"
 
@@ -91,11 +109,58 @@ const (
 	// on the reconciled object.
 	PausedAnnotation = "cluster.x-k8s.io/paused"
 
+	// PausedReasonAnnotation is an annotation that can be set together with the paused annotation to record
+	// why the object is paused.
+	PausedReasonAnnotation = "cluster.x-k8s.io/paused-reason"
+
+	// PausedByAnnotation is an annotation that can be set together with the paused annotation to record
+	// who paused the object, e.g. a user or an external tool.
+	PausedByAnnotation = "cluster.x-k8s.io/paused-by"
+
+	// PausedUntilAnnotation is an annotation that can be set together with the paused annotation to automatically
+	// unpause the object at the given time, in RFC3339 format; the paused annotations are removed once expired.
+	PausedUntilAnnotation = "cluster.x-k8s.io/paused-until"
+
+	// PreMoveHookAnnotation is an annotation set by "clusterctl move" on the objects being moved whose controllers are
+	// not quiesced by pausing the Cluster, e.g. MachineHealthChecks, external remediation or addon objects; controllers
+	// should not act on objects with this annotation, e.g. trigger remediation, while the move is in progress.
+	// The annotation is removed from the objects in the target management cluster after the Clusters are resumed.
+	PreMoveHookAnnotation = "cluster.x-k8s.io/pre-move-hook"
+
+	// AutoscalerPausedAnnotation is an annotation that can be set on MachineDeployments and MachinePools to temporarily
+	// exclude them from autoscaling, e.g. during an upgrade; MachineDeployments and MachinePools are also excluded
+	// from autoscaling while they or their Cluster are paused.
+	AutoscalerPausedAnnotation = "cluster.x-k8s.io/autoscaler-paused"
+
+	// AutoscalerPausedUntilAnnotation is an annotation that can be set together with the autoscaler-paused annotation
+	// to automatically resume autoscaling at the given time, in RFC3339 format; the annotations are removed once expired.
+	AutoscalerPausedUntilAnnotation = "cluster.x-k8s.io/autoscaler-paused-until"
+
+	// AutoscalerMinSizeAnnotation is the annotation used by the Kubernetes cluster autoscaler to detect the minimum
+	// size of a node group; together with AutoscalerMaxSizeAnnotation it enables autoscaling for the node group.
+	AutoscalerMinSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size"
+
+	// AutoscalerMaxSizeAnnotation is the annotation used by the Kubernetes cluster autoscaler to detect the maximum
+	// size of a node group; together with AutoscalerMinSizeAnnotation it enables autoscaling for the node group.
+	AutoscalerMaxSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
+
+	// AutoscalerPausedMinSizeAnnotation preserves the value of the autoscaler min size annotation while autoscaling
+	// is paused; the value is restored when autoscaling is resumed.
+	AutoscalerPausedMinSizeAnnotation = "cluster.x-k8s.io/autoscaler-paused-node-group-min-size"
+
+	// AutoscalerPausedMaxSizeAnnotation preserves the value of the autoscaler max size annotation while autoscaling
+	// is paused; the value is restored when autoscaling is resumed.
+	AutoscalerPausedMaxSizeAnnotation = "cluster.x-k8s.io/autoscaler-paused-node-group-max-size"
+
 	// DisableMachineCreateAnnotation is an annotation that can be used to signal a MachineSet to stop creating new machines.
 	// It is utilized in the OnDelete MachineDeploymentStrategy to allow the MachineDeployment controller to scale down
 	// older MachineSets when Machines are deleted and add the new replicas to the latest MachineSet.
 	DisableMachineCreateAnnotation = "cluster.x-k8s.io/disable-machine-create"
 
+	// RolloutPauseReasonAnnotation records why the rollout of a MachineDeployment or of a control plane has been paused,
+	// e.g. by "clusterctl alpha rollout pause --reason"; it is removed when the rollout is resumed.
+	RolloutPauseReasonAnnotation = "cluster.x-k8s.io/rollout-pause-reason"
+
 	// WatchLabel is a label othat can be applied to any Cluster API object.
 	//
 	// Controllers which allow for selective reconciliation may check this label and proceed
@@ -114,9 +179,81 @@ const (
 	// that was cloned for the machine. This annotation is set only during cloning a template. Older/adopted machines will not have this annotation.
 	TemplateClonedFromGroupKindAnnotation = "cluster.x-k8s.io/cloned-from-groupkind"
 
+	// TemplateClonedFromChecksumAnnotation is the infrastructure machine annotation that stores the checksum of the content of
+	// the infrastructure template resource that was cloned for the machine, i.e. of its kind and spec.template. This annotation
+	// is set only during cloning a template. Older/adopted machines will not have this annotation.
+	TemplateClonedFromChecksumAnnotation = "cluster.x-k8s.io/cloned-from-checksum"
+
 	// MachineSkipRemediationAnnotation is the annotation used to mark the machines that should not be considered for remediation by MachineHealthCheck reconciler.
 	MachineSkipRemediationAnnotation = "cluster.x-k8s.io/skip-remediation"
 
+	// RebootRequestedAnnotation is the annotation set by the MachineHealthCheck reconciler on infrastructure machines
+	// to request a reboot of the underlying host; the value is the RFC3339 timestamp of the request.
+	// The same annotation is set on the Machine to track the pending request.
+	// Infrastructure providers supporting reboots should reboot the host every time the annotation value changes;
+	// the annotation is removed by the MachineHealthCheck reconciler once the Machine is healthy again.
+	RebootRequestedAnnotation = "cluster.x-k8s.io/reboot-requested"
+
+	// ExternallyRemediatedAtAnnotation is the annotation set by the MachineHealthCheck reconciler on Machines whose
+	// external remediation request reported it succeeded; the value is the RFC3339 timestamp of the remediation.
+	// The timeouts of the unhealthy conditions and of the node startup are evaluated starting from this time, so a
+	// remediated Machine is given the time to become healthy again before being remediated again.
+	ExternallyRemediatedAtAnnotation = "cluster.x-k8s.io/externally-remediated-at"
+
+	// ExternalRemediationRetriesAnnotation is the annotation set by the MachineHealthCheck reconciler on Machines to
+	// track the number of external remediation requests which failed and have been retried; once the retries are
+	// exhausted, remediation is escalated to the owner of the Machine. The annotation is removed once the Machine
+	// is remediated or healthy again.
+	ExternalRemediationRetriesAnnotation = "cluster.x-k8s.io/external-remediation-retries"
+
+	// PropagatedLabelsAnnotation is the annotation set by the Cluster controller on the objects of a Cluster to track
+	// the labels propagated from the Cluster; the value is a JSON object with the propagated keys and values.
+	PropagatedLabelsAnnotation = "cluster.x-k8s.io/propagated-labels"
+
+	// PropagatedAnnotationsAnnotation is the annotation set by the Cluster controller on the objects of a Cluster to track
+	// the annotations propagated from the Cluster; the value is a JSON object with the propagated keys and values.
+	PropagatedAnnotationsAnnotation = "cluster.x-k8s.io/propagated-annotations"
+
+	// RemoteClientQPSAnnotation is an annotation that can be applied to a Cluster to override the maximum queries
+	// per second from the clients of the Cluster API controllers to the workload cluster, e.g. "50".
+	// NOTE: Changes are applied when the connection to the workload cluster is re-established.
+	RemoteClientQPSAnnotation = "cluster.x-k8s.io/remote-client-qps"
+
+	// RemoteClientBurstAnnotation is an annotation that can be applied to a Cluster to override the maximum burst
+	// for throttling the clients of the Cluster API controllers to the workload cluster, e.g. "100".
+	// NOTE: Changes are applied when the connection to the workload cluster is re-established.
+	RemoteClientBurstAnnotation = "cluster.x-k8s.io/remote-client-burst"
+
+	// NodeSnapshotLabel is the label set on the ConfigMaps storing the last-known state of the Node of a Machine
+	// captured before deletion; the value is the name of the Machine.
+	NodeSnapshotLabel = "cluster.x-k8s.io/node-snapshot"
+
+	// NodeSnapshotExpirationAnnotation is the annotation set on the ConfigMaps storing Node snapshots with the
+	// RFC3339 timestamp after which the ConfigMap is garbage collected by the Machine controller.
+	NodeSnapshotExpirationAnnotation = "cluster.x-k8s.io/node-snapshot-expiration"
+
+	// DeletionReasonAnnotation is the annotation recording why a Machine has been deleted, e.g. ScaleDown.
+	// It is set by the controller initiating the deletion, and propagated by the Machine controller to the
+	// infrastructure machine and the bootstrap config; Machines deleted without the annotation, e.g. by users,
+	// get the User reason.
+	DeletionReasonAnnotation = "cluster.x-k8s.io/deletion-reason"
+
+	// DeletionInitiatorAnnotation is the annotation recording who initiated the deletion of a Machine,
+	// in the form Kind/name, e.g. MachineSet/md-0-7d4b9c8f5.
+	DeletionInitiatorAnnotation = "cluster.x-k8s.io/deletion-initiator"
+
+	// RebootstrapAnnotation is set by users on a Machine to request to re-generate its bootstrap data and to re-run
+	// bootstrap on the existing infrastructure, e.g. after an OS-level repair, instead of deleting the Machine.
+	// The value is an opaque token identifying the request, e.g. a timestamp; a new request is issued by changing it.
+	// The Machine controller propagates it to the bootstrap config, and once the bootstrap provider has re-generated
+	// the bootstrap data, to the infrastructure machine.
+	RebootstrapAnnotation = "cluster.x-k8s.io/rebootstrap"
+
+	// ObservedRebootstrapAnnotation is set by bootstrap and infrastructure providers on their objects with the value of
+	// the RebootstrapAnnotation they have last acted upon, i.e. after re-generating the bootstrap data or after
+	// re-running bootstrap on the existing infrastructure.
+	ObservedRebootstrapAnnotation = "cluster.x-k8s.io/observed-rebootstrap"
+
 	// ClusterSecretType defines the type of secret created by core components.
 	// Note: This is used by core CAPI, CAPBK, and KCP to determine whether a secret is created by the controllers
 	// themselves or supplied by the user (e.g. bring your own certificates).
@@ -163,6 +300,30 @@ var NodeUninitializedTaint = corev1.Taint{
 	Effect: corev1.TaintEffectNoSchedule,
 }
 
+// NodeStandbyTaint is added by the MachineSet controller to standby Machines, and then synced to their Nodes,
+// to prevent workloads to be scheduled on Nodes before the Machine is promoted.
+var NodeStandbyTaint = corev1.Taint{
+	Key:    "node.cluster.x-k8s.io/standby",
+	Effect: corev1.TaintEffectNoSchedule,
+}
+
+const (
+	// DeletionReasonScaleDown documents a Machine deleted because its owner has been scaled down.
+	DeletionReasonScaleDown = "ScaleDown"
+
+	// DeletionReasonRollout documents a Machine deleted because it has been replaced during a rollout.
+	DeletionReasonRollout = "Rollout"
+
+	// DeletionReasonRemediation documents a Machine deleted because it has been remediated.
+	DeletionReasonRemediation = "Remediation"
+
+	// DeletionReasonClusterDeletion documents a Machine deleted because its Cluster is being deleted.
+	DeletionReasonClusterDeletion = "ClusterDeletion"
+
+	// DeletionReasonUser documents a Machine deleted by a user or by an external system.
+	DeletionReasonUser = "User"
+)
+
 const (
 	// TemplateSuffix is the object kind suffix used by template types.
 	TemplateSuffix = "Template"
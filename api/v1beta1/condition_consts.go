@@ -51,6 +51,23 @@ const (
 	// to be available.
 	// NOTE: This reason is used only as a fallback when the infrastructure object is not reporting its own ready condition.
 	WaitingForInfrastructureFallbackReason = "WaitingForInfrastructure"
+
+	// InfrastructureDeletedReason (Severity=Error) documents a cluster whose infrastructure object has been deleted,
+	// or externally removed, while the cluster and its machines still exist.
+	InfrastructureDeletedReason = "InfrastructureDeleted"
+)
+
+const (
+	// PausedCondition is true if the reconciliation of a Cluster API object is paused, either because the object
+	// has the paused annotation or because its Cluster is paused; it is removed once the object is unpaused.
+	// NOTE: Differently from other conditions, this condition is true when reconciliation is not progressing.
+	PausedCondition ConditionType = "Paused"
+
+	// ClusterPausedReason documents a Cluster API object paused because its Cluster is paused.
+	ClusterPausedReason = "ClusterPaused"
+
+	// ObjectPausedReason documents a Cluster API object paused because it has the paused annotation.
+	ObjectPausedReason = "ObjectPaused"
 )
 
 // ANCHOR_END: CommonConditions
@@ -104,6 +121,87 @@ const (
 	WaitingForControlPlaneAvailableReason = "WaitingForControlPlaneAvailable"
 )
 
+const (
+	// WorkloadClusterReachableCondition reports if the workload cluster's apiserver is reachable from the management
+	// cluster, as observed by the health checks of the ClusterCacheTracker.
+	WorkloadClusterReachableCondition ConditionType = "WorkloadClusterReachable"
+
+	// WorkloadClusterUnreachableReason (Severity=Error) documents a workload cluster whose apiserver failed
+	// a number of consecutive health checks.
+	WorkloadClusterUnreachableReason = "WorkloadClusterUnreachable"
+
+	// WorkloadClusterUnauthorizedReason (Severity=Error) documents a workload cluster whose apiserver rejected
+	// the credentials used by the management cluster, e.g. because the kubeconfig has been rotated.
+	WorkloadClusterUnauthorizedReason = "WorkloadClusterUnauthorized"
+
+	// WorkloadClusterConnectionFailedReason (Severity=Warning) documents a failure to establish a connection
+	// to the workload cluster's apiserver.
+	WorkloadClusterConnectionFailedReason = "WorkloadClusterConnectionFailed"
+)
+
+const (
+	// WorkersReadyCondition reports an aggregate of the readiness of the worker Machines of the cluster, as reported
+	// by the MachineDeployments and MachinePools belonging to it. See Cluster.status.summary.workers for the counts.
+	WorkersReadyCondition ConditionType = "WorkersReady"
+
+	// WaitingForWorkersReason (Severity=Info) documents a cluster where not all the desired worker replicas are ready yet.
+	WaitingForWorkersReason = "WaitingForWorkers"
+
+	// MachinesHealthyCondition reports an aggregate of the results of the MachineHealthChecks targeting the cluster.
+	// See Cluster.status.summary.unhealthyMachines for the count of Machines currently failing health checks.
+	MachinesHealthyCondition ConditionType = "MachinesHealthy"
+
+	// UnhealthyMachinesReason (Severity=Warning) documents a cluster where one or more Machines are failing
+	// their MachineHealthChecks.
+	UnhealthyMachinesReason = "UnhealthyMachines"
+
+	// NodeDriftDetectedCondition reports whether the Nodes of the workload cluster drifted from the state defined by the
+	// Machines of the cluster, e.g. because Nodes have been added manually or kubelets have been upgraded in place.
+	// NOTE: Differently from other conditions, this condition is True when drift is detected; it is set only if the
+	// NodeDriftDetection feature gate is enabled.
+	NodeDriftDetectedCondition ConditionType = "NodeDriftDetected"
+
+	// NodeDriftReason (Severity=Warning) documents a cluster with Nodes not matching the Machines of the cluster;
+	// details about the drift are reported in the condition message.
+	NodeDriftReason = "NodeDrift"
+
+	// DeletionProgressCondition reports the progress of the deletion of a Cluster; it is set only while the Cluster
+	// is being deleted, and its message reports the count of the remaining descendants for the current deletion step,
+	// as well as the objects whose deletion is blocked by finalizers not owned by Cluster API.
+	// NOTE: The Severity of the condition is Warning if the deletion is blocked by finalizers not owned by Cluster API.
+	DeletionProgressCondition ConditionType = "DeletionProgress"
+
+	// DeletingWorkersReason documents a cluster waiting for its MachinePools, MachineDeployments, MachineSets
+	// and worker Machines to be deleted.
+	DeletingWorkersReason = "DeletingWorkers"
+
+	// DeletingControlPlaneReason documents a cluster waiting for its control plane to be deleted, after all the
+	// workers have been deleted.
+	DeletingControlPlaneReason = "DeletingControlPlane"
+
+	// DeletingInfrastructureReason documents a cluster waiting for its infrastructure to be deleted, after the
+	// control plane has been deleted.
+	DeletingInfrastructureReason = "DeletingInfrastructure"
+
+	// ControlPlaneEndpointAvailableCondition reports whether the control plane endpoint of a cluster has been sourced
+	// from the object referenced by spec.controlPlaneEndpointRef; it is set only if spec.controlPlaneEndpointRef is set.
+	ControlPlaneEndpointAvailableCondition ConditionType = "ControlPlaneEndpointAvailable"
+
+	// ControlPlaneEndpointRefNotFoundReason (Severity=Warning) documents a cluster whose spec.controlPlaneEndpointRef
+	// references an object which does not exist.
+	ControlPlaneEndpointRefNotFoundReason = "ControlPlaneEndpointRefNotFound"
+
+	// WaitingForControlPlaneEndpointReason (Severity=Info) documents a cluster waiting for the object referenced by
+	// spec.controlPlaneEndpointRef to report the control plane endpoint, e.g. a Service of type LoadBalancer
+	// waiting for the load balancer to be provisioned.
+	WaitingForControlPlaneEndpointReason = "WaitingForControlPlaneEndpoint"
+
+	// ControlPlaneEndpointMismatchReason (Severity=Warning) documents a cluster whose control plane endpoint differs
+	// from the one reported by the object referenced by spec.controlPlaneEndpointRef; the control plane endpoint
+	// can't be changed once set, because it is used in certificates and kubeconfigs.
+	ControlPlaneEndpointMismatchReason = "ControlPlaneEndpointMismatch"
+)
+
 // Conditions and condition Reasons for the Machine object.
 
 const (
@@ -118,6 +216,23 @@ const (
 	// NOTE: This reason is used only as a fallback when the bootstrap object is not reporting its own ready condition.
 	WaitingForDataSecretFallbackReason = "WaitingForDataSecret"
 
+	// WaitingForRebootstrapReason (Severity=Info) documents a machine waiting for the bootstrap provider to re-generate
+	// the bootstrap data after a re-bootstrap has been requested.
+	WaitingForRebootstrapReason = "WaitingForRebootstrap"
+
+	// AuxiliaryInfrastructureReadyCondition reports if all the auxiliary infrastructure objects defined for this machine,
+	// e.g. a BMC object or network attachments, report status.ready; the condition is not set if the machine does not
+	// define auxiliary infrastructure objects.
+	AuxiliaryInfrastructureReadyCondition ConditionType = "AuxiliaryInfrastructureReady"
+
+	// WaitingForAuxiliaryInfrastructureReason (Severity=Info) documents a machine waiting for one or more auxiliary
+	// infrastructure objects to report status.ready.
+	WaitingForAuxiliaryInfrastructureReason = "WaitingForAuxiliaryInfrastructure"
+
+	// ClusterInfrastructureAvailableCondition is set to false on a machine when the infrastructure object of the cluster
+	// has been deleted while the machine still exists; the condition is not set when the cluster infrastructure is available.
+	ClusterInfrastructureAvailableCondition ConditionType = "ClusterInfrastructureAvailable"
+
 	// DrainingSucceededCondition provide evidence of the status of the node drain operation which happens during the machine
 	// deletion process.
 	DrainingSucceededCondition ConditionType = "DrainingSucceeded"
@@ -157,6 +272,10 @@ const (
 
 	// UnhealthyNodeConditionReason is the reason used when a machine's node has one of the MachineHealthCheck's unhealthy conditions.
 	UnhealthyNodeConditionReason = "UnhealthyNode"
+
+	// UnhealthyNodeExpressionReason is the reason used when one of the MachineHealthCheck's unhealthy node expressions
+	// evaluates to true for a machine's node.
+	UnhealthyNodeExpressionReason = "UnhealthyNodeExpression"
 )
 
 const (
@@ -186,6 +305,24 @@ const (
 
 	// ExternalRemediationRequestCreationFailedReason is the reason used when a machine health check fails to create external remediation request.
 	ExternalRemediationRequestCreationFailedReason = "ExternalRemediationRequestCreationFailed"
+
+	// MachineExternallyRemediatedCondition is set by the MachineHealthCheck controller on machines that have failed a healthcheck
+	// when using a RemediationTemplate, and it reports the state of the external remediation request.
+	// MachineExternallyRemediatedCondition is set to False while the external remediation is in progress, and it is set to True
+	// once the external remediation request reports it succeeded or the machine is healthy again.
+	MachineExternallyRemediatedCondition ConditionType = "ExternallyRemediated"
+
+	// WaitingForExternalRemediationReason (Severity=Warning) documents a machine waiting for an external remediation request
+	// to report its progress.
+	WaitingForExternalRemediationReason = "WaitingForExternalRemediation"
+
+	// ExternalRemediationRetryReason (Severity=Warning) documents a machine for which the external remediation request reported
+	// a failure which can be retried; a new external remediation request is going to be created.
+	ExternalRemediationRetryReason = "ExternalRemediationRetry"
+
+	// ExternalRemediationFailedReason (Severity=Error) documents a machine for which the external remediation request reported
+	// a permanent failure, or failed more times than allowed; the machine is going to be remediated by its owner.
+	ExternalRemediationFailedReason = "ExternalRemediationFailed"
 )
 
 // Conditions and condition Reasons for the Machine's Node object.
@@ -230,6 +367,15 @@ const (
 
 	// WaitingForAvailableMachinesReason (Severity=Warning) reflects the fact that the required minimum number of machines for a machinedeployment are not available.
 	WaitingForAvailableMachinesReason = "WaitingForAvailableMachines"
+
+	// ProgressingCondition documents whether the rollout or scale operation of a MachineDeployment or of a control plane
+	// is making progress within the progress deadline; it is true also when no operation is in progress.
+	// NOTE: Controllers do not perform any automatic action, e.g. rollback, when the progress deadline is exceeded.
+	ProgressingCondition ConditionType = "Progressing"
+
+	// ProgressDeadlineExceededReason (Severity=Warning) documents a rollout or scale operation not making progress
+	// within the progress deadline.
+	ProgressDeadlineExceededReason = "ProgressDeadlineExceeded"
 )
 
 // Conditions and condition Reasons for  MachineSets.
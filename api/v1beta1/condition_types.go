@@ -92,3 +92,31 @@ type Condition struct {
 type Conditions []Condition
 
 // ANCHOR_END: Conditions
+
+// ANCHOR: ConditionObservation
+
+// ConditionObservation reports the freshness of a condition derived from observations of the workload cluster,
+// so it is possible to distinguish a condition observed a few seconds ago from a condition that has not been
+// refreshed for a long time, e.g. because the workload cluster is not reachable.
+type ConditionObservation struct {
+	// LastObservedTime is the last time the condition has been refreshed from the workload cluster.
+	LastObservedTime metav1.Time `json:"lastObservedTime"`
+
+	// Stale is true if the condition has not been refreshed within the staleness threshold.
+	// +optional
+	Stale bool `json:"stale,omitempty"`
+
+	// Status is the status of the condition when LastObservedTime has been last updated.
+	// +optional
+	Status corev1.ConditionStatus `json:"status,omitempty"`
+
+	// Reason is the reason of the condition when LastObservedTime has been last updated.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// ObservedGeneration is the generation of the object when LastObservedTime has been last updated.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ANCHOR_END: ConditionObservation
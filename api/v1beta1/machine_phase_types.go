@@ -50,6 +50,16 @@ const (
 	// but its infrastructure has not yet been fully deleted.
 	MachinePhaseDeleting = MachinePhase("Deleting")
 
+	// MachinePhaseDraining is the Machine state when a delete
+	// request has been sent to the API Server,
+	// and the Node hosted on the Machine is being drained.
+	MachinePhaseDraining = MachinePhase("Draining")
+
+	// MachinePhaseWaitingForVolumeDetach is the Machine state when a delete
+	// request has been sent to the API Server, the Node hosted on the Machine
+	// has been drained, and its volumes are being detached.
+	MachinePhaseWaitingForVolumeDetach = MachinePhase("WaitingForVolumeDetach")
+
 	// MachinePhaseDeleted is the Machine state when the object
 	// and the related infrastructure is deleted and
 	// ready to be garbage collected by the API Server.
@@ -43,6 +43,10 @@ const (
 	// MachineDeploymentNameLabel is the label set on machines if they're controlled by MachineDeployment.
 	MachineDeploymentNameLabel = "cluster.x-k8s.io/deployment-name"
 
+	// MachinePoolNameLabel is the label set on the Nodes of a MachinePool, and used to select them.
+	// Note: The value of this label may be a hash if the MachinePool name is longer than 63 characters.
+	MachinePoolNameLabel = "cluster.x-k8s.io/pool-name"
+
 	// MachineControlPlaneNameLabel is the label set on machines if they're controlled by a ControlPlane.
 	// Note: The value of this label may be a hash if the control plane name is longer than 63 characters.
 	MachineControlPlaneNameLabel = "cluster.x-k8s.io/control-plane-name"
@@ -71,6 +75,25 @@ const (
 	NodeRestrictionLabelDomain = "node-restriction.kubernetes.io"
 	// ManagedNodeLabelDomain is one of the CAPI managed Node label domains.
 	ManagedNodeLabelDomain = "node.cluster.x-k8s.io"
+
+	// InfrastructureReleaseAnnotation is set on the InfraMachine of a Machine with the Release infrastructure reuse policy
+	// before the InfraMachine is deleted. Infrastructure providers supporting the reuse of hosts must release the host back
+	// to their pool, e.g. by deprovisioning it, instead of destroying it when deleting an InfraMachine with this annotation.
+	InfrastructureReleaseAnnotation = "machine.cluster.x-k8s.io/release-infrastructure"
+)
+
+// InfrastructureReusePolicy defines what happens to the infrastructure of a Machine when the Machine is deleted.
+type InfrastructureReusePolicy string
+
+const (
+	// InfrastructureReusePolicyDelete deletes the infrastructure of the Machine, e.g. the cloud instance is terminated.
+	InfrastructureReusePolicyDelete InfrastructureReusePolicy = "Delete"
+
+	// InfrastructureReusePolicyRelease releases the infrastructure of the Machine back to the infrastructure provider pool,
+	// so it can be reused by other Machines, e.g. a bare-metal host is deprovisioned and made available again.
+	// The bootstrap data secret of the Machine is deleted before releasing the infrastructure, so the credentials
+	// it contains can't be used anymore once the host is reused; new bootstrap data is generated for the next Machine.
+	InfrastructureReusePolicyRelease InfrastructureReusePolicy = "Release"
 )
 
 // ANCHOR: MachineSpec
@@ -89,6 +112,15 @@ type MachineSpec struct {
 	// offered by an infrastructure provider.
 	InfrastructureRef corev1.ObjectReference `json:"infrastructureRef"`
 
+	// AuxiliaryInfrastructureRefs is a list of optional references to additional custom resources offered by
+	// infrastructure providers, e.g. a BMC object next to a VM object, or network attachment objects for secondary NICs.
+	// Auxiliary infrastructure objects are owned by the Machine and deleted together with it; the Machine is not
+	// considered ready until all of them report status.ready, as surfaced by the AuxiliaryInfrastructureReady condition.
+	// In the templates of MachineSets and MachineDeployments these are references to templates, which are cloned
+	// for each Machine like the InfrastructureRef. Not supported in MachinePools.
+	// +optional
+	AuxiliaryInfrastructureRefs []corev1.ObjectReference `json:"auxiliaryInfrastructureRefs,omitempty"`
+
 	// Version defines the desired Kubernetes version.
 	// This field is meant to be optionally used by bootstrap providers.
 	// +optional
@@ -128,6 +160,67 @@ type MachineSpec struct {
 	// Defaults to 10 seconds.
 	// +optional
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+
+	// Taints are the taints to be set on the Node hosted by the Machine.
+	// Taints are identified by key and effect, and they are continuously reconciled:
+	// - taints in this list are added to the Node, or updated if the Node has a taint with the same
+	//   key and effect but a different value (the value from the Machine wins);
+	// - taints removed from this list are removed from the Node;
+	// - taints on the Node which have never been set from the Machine, e.g. taints set by the kubelet or by
+	//   other controllers, are preserved.
+	// NOTE: Changes to taints are propagated in-place from MachineDeployments and MachineSets to Machines,
+	// without triggering a rollout.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// InfrastructureReusePolicy defines what happens to the infrastructure of the Machine when the Machine is deleted.
+	// Delete (the default) deletes the infrastructure; Release releases it back to the infrastructure provider pool
+	// so it can be reused by other Machines, and it requires an infrastructure provider supporting the reuse of hosts.
+	// NOTE: Changes to the infrastructure reuse policy are propagated in-place from MachineDeployments and MachineSets
+	// to Machines, without triggering a rollout.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Release
+	InfrastructureReusePolicy InfrastructureReusePolicy `json:"infrastructureReusePolicy,omitempty"`
+
+	// NodeDrainOptions defines how the Node hosted by the Machine is drained before the Machine is deleted.
+	// If not set, Pods are evicted honoring their termination grace period and PodDisruptionBudgets, and Pods
+	// not managed by a controller are removed as well.
+	// NOTE: Changes to the drain options are propagated in-place from MachineDeployments and MachineSets to Machines,
+	// without triggering a rollout.
+	// +optional
+	NodeDrainOptions *NodeDrainOptions `json:"nodeDrainOptions,omitempty"`
+}
+
+// NodeDrainOptions defines how a Node is drained.
+// NOTE: DaemonSet-managed Pods and mirror Pods are never removed when draining a Node, given that they would be
+// immediately re-created, see IgnoreDaemonSets; Pods using emptyDir volumes are removed, and their local data is lost.
+type NodeDrainOptions struct {
+	// GracePeriodSeconds, if set, overrides the termination grace period of the Pods removed from the Node;
+	// if not set, the termination grace period defined in each Pod is used.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GracePeriodSeconds *int32 `json:"gracePeriodSeconds,omitempty"`
+
+	// SkipPodSelectors is a list of label selectors for Pods that must not be removed from the Node when draining,
+	// e.g. Pods which are expected to terminate together with the Node; a Pod is skipped if it matches any of them.
+	// +optional
+	SkipPodSelectors []metav1.LabelSelector `json:"skipPodSelectors,omitempty"`
+
+	// Force defines if Pods not managed by a controller, e.g. by a ReplicaSet or a StatefulSet, are removed
+	// from the Node; if false, the drain fails while such Pods exist on the Node.
+	// Defaults to true.
+	// +optional
+	Force *bool `json:"force,omitempty"`
+
+	// IgnoreDaemonSets defines if DaemonSet-managed Pods are ignored when draining the Node; if false, the drain
+	// fails while such Pods exist on the Node, e.g. to make sure DaemonSets are removed from the Node beforehand.
+	// Defaults to true.
+	// +optional
+	IgnoreDaemonSets *bool `json:"ignoreDaemonSets,omitempty"`
+
+	// DisableEviction, if true, deletes the Pods instead of evicting them, thus bypassing PodDisruptionBudgets.
+	// +optional
+	DisableEviction bool `json:"disableEviction,omitempty"`
 }
 
 // ANCHOR_END: MachineSpec
@@ -217,10 +310,35 @@ type MachineStatus struct {
 	// Conditions defines current service state of the Machine.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
+
+	// ConditionObservations reports when the conditions derived from observations of the workload cluster,
+	// e.g. NodeHealthy or EtcdMemberHealthy, have been last refreshed. The map is keyed by condition type.
+	// +optional
+	ConditionObservations map[ConditionType]ConditionObservation `json:"conditionObservations,omitempty"`
+
+	// BootstrapProgress reports the progress of the bootstrap process running on the machine, e.g. downloading
+	// binaries or running kubeadm, if reported by the bootstrap provider. It is removed once the Machine has a Node.
+	// +optional
+	BootstrapProgress *MachineBootstrapProgress `json:"bootstrapProgress,omitempty"`
 }
 
 // ANCHOR_END: MachineStatus
 
+// MachineBootstrapProgress reports the progress of the bootstrap process running on a machine.
+type MachineBootstrapProgress struct {
+	// Phase is the current phase of the bootstrap process, e.g. DownloadingBinaries, RunningKubeadm or WaitingForNode.
+	// Phases are defined by the bootstrap provider.
+	Phase string `json:"phase"`
+
+	// Message is a human-readable message with details about the current phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastHeartbeatTime is the last time the bootstrap process reported its progress.
+	// +optional
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+}
+
 // SetTypedPhase sets the Phase field to the string representation of MachinePhase.
 func (m *MachineStatus) SetTypedPhase(p MachinePhase) {
 	m.Phase = string(p)
@@ -236,6 +354,8 @@ func (m *MachineStatus) GetTypedPhase() MachinePhase {
 		MachinePhaseProvisioned,
 		MachinePhaseRunning,
 		MachinePhaseDeleting,
+		MachinePhaseDraining,
+		MachinePhaseWaitingForVolumeDetach,
 		MachinePhaseDeleted,
 		MachinePhaseFailed:
 		return phase
@@ -293,6 +413,16 @@ func (m *Machine) SetConditions(conditions Conditions) {
 	m.Status.Conditions = conditions
 }
 
+// GetConditionObservations returns the condition observations for this object.
+func (m *Machine) GetConditionObservations() map[ConditionType]ConditionObservation {
+	return m.Status.ConditionObservations
+}
+
+// SetConditionObservations sets the condition observations on this object.
+func (m *Machine) SetConditionObservations(observations map[ConditionType]ConditionObservation) {
+	m.Status.ConditionObservations = observations
+}
+
 // +kubebuilder:object:root=true
 
 // MachineList contains a list of Machine.
@@ -21,9 +21,11 @@ import (
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -60,6 +62,12 @@ func (m *Machine) Default() {
 		m.Spec.InfrastructureRef.Namespace = m.Namespace
 	}
 
+	for i := range m.Spec.AuxiliaryInfrastructureRefs {
+		if m.Spec.AuxiliaryInfrastructureRefs[i].Namespace == "" {
+			m.Spec.AuxiliaryInfrastructureRefs[i].Namespace = m.Namespace
+		}
+	}
+
 	if m.Spec.Version != nil && !strings.HasPrefix(*m.Spec.Version, "v") {
 		normalizedVersion := "v" + *m.Spec.Version
 		m.Spec.Version = &normalizedVersion
@@ -137,8 +145,90 @@ func (m *Machine) validate(old *Machine) error {
 		}
 	}
 
+	allErrs = append(allErrs, validateMachineTaints(m.Spec.Taints, specPath.Child("taints"))...)
+	allErrs = append(allErrs, validateNodeDrainOptions(m.Spec.NodeDrainOptions, specPath.Child("nodeDrainOptions"))...)
+	allErrs = append(allErrs, validateAuxiliaryInfrastructureRefs(m.Spec.AuxiliaryInfrastructureRefs, m.Namespace, specPath.Child("auxiliaryInfrastructureRefs"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return apierrors.NewInvalid(GroupVersion.WithKind("Machine").GroupKind(), m.Name, allErrs)
 }
+
+// validateAuxiliaryInfrastructureRefs validates the references to the auxiliary infrastructure objects of a Machine.
+func validateAuxiliaryInfrastructureRefs(refs []corev1.ObjectReference, namespace string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := map[string]bool{}
+	for i, ref := range refs {
+		idxPath := fldPath.Index(i)
+		if ref.Kind == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("kind"), "kind must be set"))
+		}
+		if ref.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name must be set"))
+		}
+		if ref.Namespace != "" && ref.Namespace != namespace {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("namespace"), ref.Namespace, "must match metadata.namespace"))
+		}
+		id := fmt.Sprintf("%s/%s", ref.GroupVersionKind().GroupKind(), ref.Name)
+		if seen[id] {
+			allErrs = append(allErrs, field.Duplicate(idxPath, id))
+		}
+		seen[id] = true
+	}
+	return allErrs
+}
+
+// validateNodeDrainOptions validates the options used to drain the Node hosted by a Machine.
+func validateNodeDrainOptions(options *NodeDrainOptions, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if options == nil {
+		return allErrs
+	}
+	if options.GracePeriodSeconds != nil && *options.GracePeriodSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("gracePeriodSeconds"), *options.GracePeriodSeconds, "must be greater than or equal to 0"))
+	}
+	for i := range options.SkipPodSelectors {
+		if _, err := metav1.LabelSelectorAsSelector(&options.SkipPodSelectors[i]); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("skipPodSelectors").Index(i), options.SkipPodSelectors[i], err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// validateMachineTaints validates the taints to be set on the Node hosted by a Machine.
+func validateMachineTaints(taints []corev1.Taint, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := map[string]bool{}
+	for i, taint := range taints {
+		idxPath := fldPath.Index(i)
+		for _, msg := range validation.IsQualifiedName(taint.Key) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("key"), taint.Key, msg))
+		}
+		if taint.Value != "" {
+			for _, msg := range validation.IsValidLabelValue(taint.Value) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("value"), taint.Value, msg))
+			}
+		}
+		switch taint.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("effect"), taint.Effect, []string{
+				string(corev1.TaintEffectNoSchedule),
+				string(corev1.TaintEffectPreferNoSchedule),
+				string(corev1.TaintEffectNoExecute),
+			}))
+		}
+		// The NodeUninitializedTaint is managed by Cluster API, and it is removed from the Node as soon as
+		// labels from the Machine have been reconciled.
+		if taint.Key == NodeUninitializedTaint.Key {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("key"), fmt.Sprintf("taint %q is reserved to Cluster API", taint.Key)))
+		}
+		id := fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+		if seen[id] {
+			allErrs = append(allErrs, field.Duplicate(idxPath, id))
+		}
+		seen[id] = true
+	}
+	return allErrs
+}
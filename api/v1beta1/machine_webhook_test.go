@@ -248,3 +248,199 @@ func TestMachineVersionValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineTaintsValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		taints    []corev1.Taint
+		expectErr bool
+	}{
+		{
+			name: "should succeed when given valid taints",
+			taints: []corev1.Taint{
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoExecute},
+				{Key: "dedicated", Effect: corev1.TaintEffectPreferNoSchedule},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error when given a duplicated key and effect",
+			taints: []corev1.Taint{
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "example.com/gpu", Value: "false", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when given an invalid key",
+			taints: []corev1.Taint{
+				{Key: "example.com/gpu/", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when given an invalid value",
+			taints: []corev1.Taint{
+				{Key: "example.com/gpu", Value: "not a valid value", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when given an invalid effect",
+			taints: []corev1.Taint{
+				{Key: "example.com/gpu", Effect: "NoEffect"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when given the taint reserved to Cluster API",
+			taints: []corev1.Taint{
+				NodeUninitializedTaint,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &Machine{
+				Spec: MachineSpec{
+					Taints:    tt.taints,
+					Bootstrap: Bootstrap{ConfigRef: nil, DataSecretName: pointer.String("test")},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(m.ValidateCreate()).NotTo(Succeed())
+				g.Expect(m.ValidateUpdate(m)).NotTo(Succeed())
+			} else {
+				g.Expect(m.ValidateCreate()).To(Succeed())
+				g.Expect(m.ValidateUpdate(m)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestMachineNodeDrainOptionsValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   *NodeDrainOptions
+		expectErr bool
+	}{
+		{
+			name: "should succeed when given valid drain options",
+			options: &NodeDrainOptions{
+				GracePeriodSeconds: pointer.Int32(30),
+				SkipPodSelectors: []metav1.LabelSelector{
+					{MatchLabels: map[string]string{"app": "log-shipper"}},
+					{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"node"}}}},
+				},
+				Force:           pointer.Bool(false),
+				DisableEviction: true,
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error when given a negative grace period",
+			options: &NodeDrainOptions{
+				GracePeriodSeconds: pointer.Int32(-1),
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when given an invalid pod selector",
+			options: &NodeDrainOptions{
+				SkipPodSelectors: []metav1.LabelSelector{
+					{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "NotAnOperator"}}},
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &Machine{
+				Spec: MachineSpec{
+					NodeDrainOptions: tt.options,
+					Bootstrap:        Bootstrap{ConfigRef: nil, DataSecretName: pointer.String("test")},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(m.ValidateCreate()).NotTo(Succeed())
+				g.Expect(m.ValidateUpdate(m)).NotTo(Succeed())
+			} else {
+				g.Expect(m.ValidateCreate()).To(Succeed())
+				g.Expect(m.ValidateUpdate(m)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestMachineAuxiliaryInfrastructureRefsValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		refs      []corev1.ObjectReference
+		expectErr bool
+	}{
+		{
+			name: "should succeed when given valid references",
+			refs: []corev1.ObjectReference{
+				{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericBMC", Name: "bmc", Namespace: "foobar"},
+				{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericNetworkAttachment", Name: "nic"},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error when a reference has no name",
+			refs: []corev1.ObjectReference{
+				{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericBMC"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when a reference is in another namespace",
+			refs: []corev1.ObjectReference{
+				{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericBMC", Name: "bmc", Namespace: "other"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when given duplicated references",
+			refs: []corev1.ObjectReference{
+				{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericBMC", Name: "bmc"},
+				{APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4", Kind: "GenericBMC", Name: "bmc"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &Machine{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "foobar"},
+				Spec: MachineSpec{
+					AuxiliaryInfrastructureRefs: tt.refs,
+					Bootstrap:                   Bootstrap{ConfigRef: nil, DataSecretName: pointer.String("test")},
+					InfrastructureRef:           corev1.ObjectReference{Namespace: "foobar"},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(m.ValidateCreate()).NotTo(Succeed())
+				g.Expect(m.ValidateUpdate(m)).NotTo(Succeed())
+			} else {
+				g.Expect(m.ValidateCreate()).To(Succeed())
+				g.Expect(m.ValidateUpdate(m)).To(Succeed())
+			}
+		})
+	}
+}
@@ -44,6 +44,19 @@ const (
 	// RevisionHistoryAnnotation maintains the history of all old revisions that a machine set has served for a machine deployment.
 	RevisionHistoryAnnotation = "machinedeployment.clusters.x-k8s.io/revision-history"
 
+	// RolloutReasonAnnotation records why a rollout happened, i.e. why a new MachineSet has been created.
+	// Users can set this annotation on a MachineDeployment before changing it, similarly to the kubernetes.io/change-cause
+	// annotation on Deployments; otherwise the MachineDeployment controller records a summary of the changes to the machine
+	// template, e.g. "infraTemplate changed: instanceType t3.large→t3.xlarge".
+	// The annotation is set on the new MachineSet and propagated to its Machines.
+	RolloutReasonAnnotation = "machinedeployment.clusters.x-k8s.io/rollout-reason"
+
+	// TemplatesChecksumAnnotation records the checksum of the content of the infrastructure and bootstrap templates
+	// referenced by a MachineDeployment; it is set by the MachineDeployment controller and propagated to its MachineSets.
+	// A MachineSet referencing templates with different names but the same checksum as the MachineDeployment is reused
+	// instead of rolling out new Machines, e.g. when a template has been re-created with an identical spec under a new name.
+	TemplatesChecksumAnnotation = "machinedeployment.clusters.x-k8s.io/templates-checksum"
+
 	// DesiredReplicasAnnotation is the desired replicas for a machine deployment recorded as an annotation
 	// in its machine sets. Helps in separating scaling events from the rollout process and for
 	// determining if the new machine set for a deployment is really saturated.
@@ -138,13 +151,25 @@ type MachineDeploymentSpec struct {
 	// +optional
 	Paused bool `json:"paused,omitempty"`
 
+	// RollbackTo is the config this MachineDeployment is rolling back to.
+	// When set, the machine template of the MachineSet with the given revision is copied
+	// into the MachineDeployment and the field is cleared.
+	// +optional
+	RollbackTo *MachineDeploymentRollbackConfig `json:"rollbackTo,omitempty"`
+
 	// The maximum time in seconds for a deployment to make progress before it
 	// is considered to be failed. The deployment controller will continue to
-	// process failed deployments and a condition with a ProgressDeadlineExceeded
-	// reason will be surfaced in the deployment status. Note that progress will
-	// not be estimated during the time a deployment is paused. Defaults to 600s.
+	// process failed deployments and a Progressing condition with a ProgressDeadlineExceeded
+	// reason will be surfaced in the deployment status, together with an event; no
+	// automatic rollback is performed. Note that progress will not be estimated during
+	// the time a deployment is paused. Defaults to 600s.
 	// +optional
 	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// MachineNamingStrategy allows changing the naming pattern used when creating
+	// the MachineSets and the Machines of this MachineDeployment.
+	// +optional
+	MachineNamingStrategy *MachineNamingStrategy `json:"machineNamingStrategy,omitempty"`
 }
 
 // ANCHOR_END: MachineDeploymentSpec
@@ -168,6 +193,38 @@ type MachineDeploymentStrategy struct {
 
 // ANCHOR_END: MachineDeploymentStrategy
 
+// ANCHOR: MachineDeploymentRollbackConfig
+
+// MachineDeploymentRollbackConfig describes the revision a MachineDeployment is rolling back to.
+type MachineDeploymentRollbackConfig struct {
+	// Revision is the revision to rollback to. If set to 0, rollback to the last revision.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// ANCHOR_END: MachineDeploymentRollbackConfig
+
+// ANCHOR: MachineNamingStrategy
+
+// MachineNamingStrategy allows changing the naming pattern used when creating
+// MachineSets and Machines.
+type MachineNamingStrategy struct {
+	// Template defines the template to use for generating the names of the MachineSets
+	// and of the Machines. The following variables can be referenced:
+	// * `.cluster`: the name of the Cluster
+	// * `.machineDeployment`: the name of the MachineDeployment, if any
+	// * `.machineSet`: the name of the MachineSet; it is empty when generating MachineSet names
+	// * `.random`: a random alphanumeric string of 5 characters; it must be used in the template
+	// to guarantee the uniqueness of the generated names.
+	// The generated names must be valid DNS subdomains not longer than 63 characters.
+	// Example: "{{ .cluster }}-{{ .machineDeployment }}-{{ .random }}".
+	// +optional
+	Template string `json:"template,omitempty"`
+}
+
+// ANCHOR_END: MachineNamingStrategy
+
 // ANCHOR: MachineRollingUpdateDeployment
 
 // MachineRollingUpdateDeployment is used to control the desired behavior of rolling update.
@@ -258,6 +315,19 @@ type MachineDeploymentStatus struct {
 	// +optional
 	Phase string `json:"phase,omitempty"`
 
+	// ProgressDeadline is the time by which the rollout or scale operation in progress is expected to make
+	// further progress; if no progress is made before this time, the progress deadline is considered exceeded.
+	// It is not set when no operation is in progress.
+	// +optional
+	ProgressDeadline *metav1.Time `json:"progressDeadline,omitempty"`
+
+	// Rollout reports the machines of the MachineDeployment that are holding a rollout or scale operation,
+	// aggregated from all its MachineSets, e.g. machines still being provisioned or machines that cannot be drained,
+	// together with the reason why.
+	// It is not set when there are no such machines.
+	// +optional
+	Rollout *MachineRolloutStatus `json:"rollout,omitempty"`
+
 	// Conditions defines current service state of the MachineDeployment.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
@@ -22,6 +22,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/admission/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -37,6 +38,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"sigs.k8s.io/cluster-api/internal/util/naming"
 	"sigs.k8s.io/cluster-api/util/version"
 )
 
@@ -260,6 +262,21 @@ func (m *MachineDeployment) validate(old *MachineDeployment) error {
 		}
 	}
 
+	allErrs = append(allErrs, validateMachineTaints(m.Spec.Template.Spec.Taints, specPath.Child("template", "spec", "taints"))...)
+	allErrs = append(allErrs, validateNodeDrainOptions(m.Spec.Template.Spec.NodeDrainOptions, specPath.Child("template", "spec", "nodeDrainOptions"))...)
+	allErrs = append(allErrs, validateAuxiliaryInfrastructureRefs(m.Spec.Template.Spec.AuxiliaryInfrastructureRefs, m.Namespace, specPath.Child("template", "spec", "auxiliaryInfrastructureRefs"))...)
+
+	if m.Spec.MachineNamingStrategy != nil && m.Spec.MachineNamingStrategy.Template != "" {
+		// Render the template to surface invalid templates and names early; the random part of the
+		// name does not affect the validation.
+		if _, err := naming.Generate(m.Spec.MachineNamingStrategy.Template, naming.Values{
+			Cluster:           m.Spec.ClusterName,
+			MachineDeployment: m.Name,
+		}); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("machineNamingStrategy", "template"), m.Spec.MachineNamingStrategy.Template, err.Error()))
+		}
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -271,8 +288,8 @@ func (m *MachineDeployment) validate(old *MachineDeployment) error {
 // "Cluster API group" than "cluster.x-k8s.io" via the "CAPI_GROUP" environment variable.
 // We only handle the default group in our implementation.
 const (
-	autoscalerMinSize = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size"
-	autoscalerMaxSize = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
+	autoscalerMinSize = AutoscalerMinSizeAnnotation
+	autoscalerMaxSize = AutoscalerMaxSizeAnnotation
 )
 
 // calculateMachineDeploymentReplicas calculates the default value of the replicas field.
@@ -314,60 +331,12 @@ func calculateMachineDeploymentReplicas(ctx context.Context, oldMD *MachineDeplo
 	log := ctrl.LoggerFrom(ctx).WithValues("MachineDeployment", klog.KObj(newMD))
 
 	// If both autoscaler annotations are set, use them to calculate the default value.
-	minSizeString, hasMinSizeAnnotation := newMD.Annotations[autoscalerMinSize]
-	maxSizeString, hasMaxSizeAnnotation := newMD.Annotations[autoscalerMaxSize]
-	if hasMinSizeAnnotation && hasMaxSizeAnnotation {
-		minSize, err := strconv.ParseInt(minSizeString, 10, 32)
-		if err != nil {
-			return 0, errors.Wrapf(err, "failed to caculate MachineDeployment replicas value: could not parse the value of the %q annotation", autoscalerMinSize)
-		}
-		maxSize, err := strconv.ParseInt(maxSizeString, 10, 32)
-		if err != nil {
-			return 0, errors.Wrapf(err, "failed to caculate MachineDeployment replicas value: could not parse the value of the %q annotation", autoscalerMaxSize)
-		}
-
-		// If it's a new MachineDeployment => Use the min size.
-		// Note: This will result in a scale up to get into the range where autoscaler takes over.
-		if oldMD == nil {
-			if !dryRun {
-				log.V(2).Info(fmt.Sprintf("Replica field has been defaulted to %d based on the %s annotation (MD is a new MD)", minSize, autoscalerMinSize))
-			}
-			return int32(minSize), nil
-		}
-
-		// Otherwise we are handing over the control for the replicas field for an existing MachineDeployment
-		// to the autoscaler.
-
-		switch {
-		// If the old MachineDeployment doesn't have replicas set => Use the min size.
-		// Note: As defaulting always sets the replica field, this case should not be possible
-		// We only have this handling to be 100% safe against panics.
-		case oldMD.Spec.Replicas == nil:
-			if !dryRun {
-				log.V(2).Info(fmt.Sprintf("Replica field has been defaulted to %d based on the %s annotation (old MD didn't have replicas set)", minSize, autoscalerMinSize))
-			}
-			return int32(minSize), nil
-		// If the old MachineDeployment replicas are lower than min size => Use the min size.
-		// Note: This will result in a scale up to get into the range where autoscaler takes over.
-		case *oldMD.Spec.Replicas < int32(minSize):
-			if !dryRun {
-				log.V(2).Info(fmt.Sprintf("Replica field has been defaulted to %d based on the %s annotation (old MD had replicas below min size)", minSize, autoscalerMinSize))
-			}
-			return int32(minSize), nil
-		// If the old MachineDeployment replicas are higher than max size => Use the max size.
-		// Note: This will result in a scale down to get into the range where autoscaler takes over.
-		case *oldMD.Spec.Replicas > int32(maxSize):
-			if !dryRun {
-				log.V(2).Info(fmt.Sprintf("Replica field has been defaulted to %d based on the %s annotation (old MD had replicas above max size)", maxSize, autoscalerMaxSize))
-			}
-			return int32(maxSize), nil
-		// If the old MachineDeployment replicas are between min and max size => Keep the current value.
-		default:
-			if !dryRun {
-				log.V(2).Info(fmt.Sprintf("Replica field has been defaulted to %d based on replicas of the old MachineDeployment (old MD had replicas within min size / max size range)", *oldMD.Spec.Replicas))
-			}
-			return *oldMD.Spec.Replicas, nil
-		}
+	var oldReplicas *int32
+	if oldMD != nil {
+		oldReplicas = oldMD.Spec.Replicas
+	}
+	if replicas, ok, err := calculateAutoscalerReplicas(log, "MachineDeployment", newMD.Annotations, oldMD == nil, oldReplicas, dryRun); err != nil || ok {
+		return replicas, err
 	}
 
 	// If neither the default nor the autoscaler annotations are set => Default to 1.
@@ -376,3 +345,66 @@ func calculateMachineDeploymentReplicas(ctx context.Context, oldMD *MachineDeplo
 	}
 	return 1, nil
 }
+
+// calculateAutoscalerReplicas calculates the default value of the replicas field of a MachineDeployment or of a
+// MachineSet based on the autoscaler min size and max size annotations, see calculateMachineDeploymentReplicas;
+// it returns false if the annotations are not set.
+func calculateAutoscalerReplicas(log logr.Logger, kind string, annotations map[string]string, isNew bool, oldReplicas *int32, dryRun bool) (int32, bool, error) {
+	minSizeString, hasMinSizeAnnotation := annotations[autoscalerMinSize]
+	maxSizeString, hasMaxSizeAnnotation := annotations[autoscalerMaxSize]
+	if !hasMinSizeAnnotation || !hasMaxSizeAnnotation {
+		return 0, false, nil
+	}
+
+	minSize, err := strconv.ParseInt(minSizeString, 10, 32)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "failed to calculate %s replicas value: could not parse the value of the %q annotation", kind, autoscalerMinSize)
+	}
+	maxSize, err := strconv.ParseInt(maxSizeString, 10, 32)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "failed to calculate %s replicas value: could not parse the value of the %q annotation", kind, autoscalerMaxSize)
+	}
+
+	// If it's a new object => Use the min size.
+	// Note: This will result in a scale up to get into the range where autoscaler takes over.
+	if isNew {
+		if !dryRun {
+			log.V(2).Info(fmt.Sprintf("Replica field has been defaulted to %d based on the %s annotation (new %s)", minSize, autoscalerMinSize, kind))
+		}
+		return int32(minSize), true, nil
+	}
+
+	// Otherwise we are handing over the control for the replicas field for an existing object
+	// to the autoscaler.
+
+	switch {
+	// If the old object doesn't have replicas set => Use the min size.
+	// Note: As defaulting always sets the replica field, this case should not be possible
+	// We only have this handling to be 100% safe against panics.
+	case oldReplicas == nil:
+		if !dryRun {
+			log.V(2).Info(fmt.Sprintf("Replica field has been defaulted to %d based on the %s annotation (old %s didn't have replicas set)", minSize, autoscalerMinSize, kind))
+		}
+		return int32(minSize), true, nil
+	// If the old replicas are lower than min size => Use the min size.
+	// Note: This will result in a scale up to get into the range where autoscaler takes over.
+	case *oldReplicas < int32(minSize):
+		if !dryRun {
+			log.V(2).Info(fmt.Sprintf("Replica field has been defaulted to %d based on the %s annotation (old %s had replicas below min size)", minSize, autoscalerMinSize, kind))
+		}
+		return int32(minSize), true, nil
+	// If the old replicas are higher than max size => Use the max size.
+	// Note: This will result in a scale down to get into the range where autoscaler takes over.
+	case *oldReplicas > int32(maxSize):
+		if !dryRun {
+			log.V(2).Info(fmt.Sprintf("Replica field has been defaulted to %d based on the %s annotation (old %s had replicas above max size)", maxSize, autoscalerMaxSize, kind))
+		}
+		return int32(maxSize), true, nil
+	// If the old replicas are between min and max size => Keep the current value.
+	default:
+		if !dryRun {
+			log.V(2).Info(fmt.Sprintf("Replica field has been defaulted to %d based on replicas of the old %s (old %s had replicas within min size / max size range)", *oldReplicas, kind, kind))
+		}
+		return *oldReplicas, true, nil
+	}
+}
@@ -545,3 +545,58 @@ func defaultValidateTestCustomDefaulter(object admission.Validator, customDefaul
 		})
 	}
 }
+
+func TestMachineDeploymentMachineNamingStrategyValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		expectErr bool
+	}{
+		{
+			name:      "should succeed when given a valid template",
+			template:  "{{ .cluster }}-{{ .machineDeployment }}-{{ .random }}",
+			expectErr: false,
+		},
+		{
+			name:      "should return error when the template does not reference random",
+			template:  "{{ .cluster }}-{{ .machineDeployment }}",
+			expectErr: true,
+		},
+		{
+			name:      "should return error when the template cannot be parsed",
+			template:  "{{ .cluster }-{{ .random }}",
+			expectErr: true,
+		},
+		{
+			name:      "should return error when the generated name is not valid",
+			template:  "{{ .cluster }}_{{ .random }}",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: MachineDeploymentSpec{
+					ClusterName: "test-cluster",
+					MachineNamingStrategy: &MachineNamingStrategy{
+						Template: tt.template,
+					},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(obj.ValidateCreate()).NotTo(Succeed())
+				g.Expect(obj.ValidateUpdate(obj)).NotTo(Succeed())
+			} else {
+				g.Expect(obj.ValidateCreate()).To(Succeed())
+				g.Expect(obj.ValidateUpdate(obj)).To(Succeed())
+			}
+		})
+	}
+}
@@ -17,11 +17,24 @@ limitations under the License.
 package v1beta1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+var (
+	// DefaultNodeStartupTimeout is the time allowed for a node to start up.
+	// Can be made longer as part of spec if required for particular provider.
+	// 10 minutes should allow the instance to start and the node to join the
+	// cluster on most providers.
+	DefaultNodeStartupTimeout = metav1.Duration{Duration: 10 * time.Minute}
+	// DefaultRebootTimeout is the time allowed for a node to become healthy
+	// after a reboot has been requested.
+	DefaultRebootTimeout = metav1.Duration{Duration: 10 * time.Minute}
+)
+
 // ANCHOR: MachineHealthCheckSpec
 
 // MachineHealthCheckSpec defines the desired state of MachineHealthCheck.
@@ -40,6 +53,13 @@ type MachineHealthCheckSpec struct {
 	// +kubebuilder:validation:MinItems=1
 	UnhealthyConditions []UnhealthyCondition `json:"unhealthyConditions"`
 
+	// UnhealthyNodeExpressions contains a list of CEL expressions evaluated against the node, which
+	// extend UnhealthyConditions for criteria that cannot be expressed as a condition type and status, e.g.
+	// low allocatable resources or a kubelet version mismatch. The expressions are combined in a logical OR
+	// with UnhealthyConditions, i.e. if any of the expressions evaluates to true, the node is unhealthy.
+	// +optional
+	UnhealthyNodeExpressions []UnhealthyNodeExpression `json:"unhealthyNodeExpressions,omitempty"`
+
 	// Any further remediation is only allowed if at most "MaxUnhealthy" machines selected by
 	// "selector" are not healthy.
 	// +optional
@@ -69,10 +89,66 @@ type MachineHealthCheckSpec struct {
 	// a controller that lives outside of Cluster API.
 	// +optional
 	RemediationTemplate *corev1.ObjectReference `json:"remediationTemplate,omitempty"`
+
+	// Reboot enables escalating remediation: when set, the MachineHealthCheck controller first requests
+	// a reboot of the infrastructure machine backing an unhealthy Machine, and only falls back to
+	// regular remediation (or to the RemediationTemplate, if defined) when the Node does not become
+	// healthy within the configured timeout.
+	// Reboots are requested by setting the "cluster.x-k8s.io/reboot-requested" annotation on the
+	// infrastructure machine; providers not supporting this contract will simply ignore the request.
+	// +optional
+	Reboot *MachineHealthCheckReboot `json:"reboot,omitempty"`
+
+	// Mode defines whether the MachineHealthCheck remediates unhealthy machines; in DryRun mode
+	// the MachineHealthCheck evaluates the health of the machines and reports the machines it would
+	// have remediated, but it does not perform any remediation.
+	// If not set, this value is defaulted to Enforce.
+	// +kubebuilder:validation:Enum=Enforce;DryRun
+	// +optional
+	Mode MachineHealthCheckMode `json:"mode,omitempty"`
 }
 
 // ANCHOR_END: MachineHealthCHeckSpec
 
+// MachineHealthCheckMode defines whether a MachineHealthCheck remediates unhealthy machines.
+type MachineHealthCheckMode string
+
+const (
+	// MachineHealthCheckEnforceMode remediates unhealthy machines.
+	MachineHealthCheckEnforceMode MachineHealthCheckMode = "Enforce"
+
+	// MachineHealthCheckDryRunMode evaluates the health of machines and reports the machines that
+	// would have been remediated, without remediating them.
+	MachineHealthCheckDryRunMode MachineHealthCheckMode = "DryRun"
+)
+
+const (
+	// ExternalRemediationSucceededPhase is the value an external remediation request sets in status.phase
+	// to report that the Machine has been remediated.
+	ExternalRemediationSucceededPhase = "Succeeded"
+
+	// ExternalRemediationFailedPhase is the value an external remediation request sets in status.phase
+	// to report that the remediation failed. The MachineHealthCheck controller retries failed remediations
+	// a limited number of times, unless status.failureReason is set too; in this case, or once the retries
+	// are exhausted, the failure is considered permanent and the Machine is remediated by its owner,
+	// i.e. it gets deleted.
+	ExternalRemediationFailedPhase = "Failed"
+)
+
+// ANCHOR: MachineHealthCheckReboot
+
+// MachineHealthCheckReboot defines how the MachineHealthCheck controller requests
+// a reboot of unhealthy machines before remediating them.
+type MachineHealthCheckReboot struct {
+	// Timeout is the time to wait for the Node to become healthy after a reboot has been requested;
+	// when this time expires the Machine is remediated.
+	// If not set, this value is defaulted to 10 minutes.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ANCHOR_END: MachineHealthCheckReboot
+
 // ANCHOR: UnhealthyCondition
 
 // UnhealthyCondition represents a Node condition type and value with a timeout
@@ -92,6 +168,28 @@ type UnhealthyCondition struct {
 
 // ANCHOR_END: UnhealthyCondition
 
+// ANCHOR: UnhealthyNodeExpression
+
+// UnhealthyNodeExpression represents a CEL expression over a Node; when the expression
+// evaluates to true, the node is considered unhealthy.
+type UnhealthyNodeExpression struct {
+	// Name identifies the expression, and it is reported in the MachineHealthCheckSucceeded condition
+	// of machines whose node is considered unhealthy because of the expression.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Expression is a CEL expression which must evaluate to a bool. The following variables are available:
+	// "node", the Node object, and "now", the evaluation time as a timestamp; the "quantity" function parses
+	// resource quantities into numbers.
+	// Eg. "quantity(node.status.allocatable['ephemeral-storage']) < quantity('10Gi')"
+	// or "node.status.conditions.exists(c, c.type == 'KernelDeadlock' && c.status == 'True' && now - timestamp(c.lastTransitionTime) > duration('5m'))".
+	// Expressions failing to evaluate, e.g. because they access a field not set on the node, are ignored.
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+}
+
+// ANCHOR_END: UnhealthyNodeExpression
+
 // ANCHOR: MachineHealthCheckStatus
 
 // MachineHealthCheckStatus defines the observed state of MachineHealthCheck.
@@ -120,6 +218,11 @@ type MachineHealthCheckStatus struct {
 	// +optional
 	Targets []string `json:"targets,omitempty"`
 
+	// DryRunRemediationTargets shows the current list of machines that would have been remediated
+	// if the machine health check was not in DryRun mode.
+	// +optional
+	DryRunRemediationTargets []string `json:"dryRunRemediationTargets,omitempty"`
+
 	// Conditions defines current service state of the MachineHealthCheck.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
@@ -135,6 +238,7 @@ type MachineHealthCheckStatus struct {
 // +kubebuilder:printcolumn:name="ExpectedMachines",type="integer",JSONPath=".status.expectedMachines",description="Number of machines currently monitored"
 // +kubebuilder:printcolumn:name="MaxUnhealthy",type="string",JSONPath=".spec.maxUnhealthy",description="Maximum number of unhealthy machines allowed"
 // +kubebuilder:printcolumn:name="CurrentHealthy",type="integer",JSONPath=".status.currentHealthy",description="Current observed healthy machines"
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode",description="Whether unhealthy machines are remediated",priority=10
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of MachineHealthCheck"
 
 // MachineHealthCheck is the Schema for the machinehealthchecks API.
@@ -29,6 +29,37 @@ const (
 	// MachineSetTopologyFinalizer is the finalizer used by the topology MachineDeployment controller to
 	// clean up referenced template resources if necessary when a MachineSet is being deleted.
 	MachineSetTopologyFinalizer = "machineset.topology.cluster.x-k8s.io"
+
+	// NodeReuseAnnotation is an annotation that can be set on MachineSets and MachineDeployments to opt-in the node reuse
+	// policy: the infrastructure hosts released by Machines deleted during remediation or scale down are preferably
+	// reused by the Machines created afterwards, e.g. for bare-metal environments where hosts are scarce and data
+	// locality matters.
+	NodeReuseAnnotation = "machineset.cluster.x-k8s.io/node-reuse"
+
+	// ReleasedProviderIDsAnnotation is an annotation used by the MachineSet controller to record on the MachineDeployment,
+	// or on the MachineSet if it is not owned by a MachineDeployment, the comma separated list of the provider IDs
+	// released by deleted Machines and not yet reused by other Machines.
+	ReleasedProviderIDsAnnotation = "machineset.cluster.x-k8s.io/released-provider-ids"
+
+	// ReuseProviderIDAnnotation is an annotation set on the InfraMachines created under the node reuse policy.
+	// It is a hint for the infrastructure provider to prefer reusing the host identified by the given provider ID.
+	ReuseProviderIDAnnotation = "cluster.x-k8s.io/reuse-provider-id"
+
+	// StandbyReplicasAnnotation is an annotation that can be set on MachineSets and MachineDeployments to keep a pool
+	// of pre-provisioned standby Machines, e.g. "2". Standby Machines join the cluster with the NodeStandbyTaint and
+	// are promoted instead of creating new Machines during scale up or remediation, reducing the replacement latency.
+	// NOTE: When the MachineSet is owned by a MachineDeployment, standby Machines are kept only for the latest MachineSet.
+	StandbyReplicasAnnotation = "machineset.cluster.x-k8s.io/standby-replicas"
+
+	// MachineStandbyLabel is the label set by the MachineSet controller on standby Machines; it is removed when the
+	// Machine is promoted.
+	MachineStandbyLabel = "cluster.x-k8s.io/standby"
+
+	// AdoptMachinesAnnotation is an annotation that can be set on MachineSets to opt-in the adoption of orphaned Machines,
+	// i.e. Machines without a controller, matching the label selector in the annotation value, e.g.
+	// "cluster.x-k8s.io/set-name=old-machineset". Machines are adopted only if they are compatible with the
+	// MachineSet template; adopted Machines get the labels of the MachineSet template.
+	AdoptMachinesAnnotation = "machineset.cluster.x-k8s.io/adopt-machines"
 )
 
 // ANCHOR: MachineSetSpec
@@ -41,9 +72,22 @@ type MachineSetSpec struct {
 
 	// Replicas is the number of desired replicas.
 	// This is a pointer to distinguish between explicit zero and unspecified.
-	// Defaults to 1.
+	//
+	// Defaults to:
+	// * if the Kubernetes autoscaler min size and max size annotations are set:
+	//   - if it's a new MachineSet, use min size
+	//   - if the replicas field of the old MachineSet is < min size, use min size
+	//   - if the replicas field of the old MachineSet is > max size, use max size
+	//   - if the replicas field of the old MachineSet is in the (min size, max size) range, keep the value from the oldMS
+	// * otherwise use 1
+	// Note: Defaulting will be run whenever the replicas field is not set:
+	// * A new MachineSet is created with replicas not set.
+	// * On an existing MachineSet the replicas field was first set and is now unset.
+	// Those cases are especially relevant for the following Kubernetes autoscaler use cases:
+	// * A new MachineSet is created and replicas should be managed by the autoscaler
+	// * An existing MachineSet which initially wasn't controlled by the autoscaler
+	//   should be later controlled by the autoscaler
 	// +optional
-	// +kubebuilder:default=1
 	Replicas *int32 `json:"replicas,omitempty"`
 
 	// MinReadySeconds is the minimum number of seconds for which a newly created machine should be ready.
@@ -68,6 +112,11 @@ type MachineSetSpec struct {
 	// Object references to custom resources are treated as templates.
 	// +optional
 	Template MachineTemplateSpec `json:"template,omitempty"`
+
+	// MachineNamingStrategy allows changing the naming pattern used when creating
+	// the Machines of this MachineSet.
+	// +optional
+	MachineNamingStrategy *MachineNamingStrategy `json:"machineNamingStrategy,omitempty"`
 }
 
 // ANCHOR_END: MachineSetSpec
@@ -168,6 +217,13 @@ type MachineSetStatus struct {
 	FailureReason *capierrors.MachineSetStatusError `json:"failureReason,omitempty"`
 	// +optional
 	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Rollout reports the machines of the MachineSet that are holding a rollout or scale operation, e.g. machines
+	// still being provisioned or machines that cannot be drained, together with the reason why.
+	// It is not set when there are no such machines.
+	// +optional
+	Rollout *MachineRolloutStatus `json:"rollout,omitempty"`
+
 	// Conditions defines current service state of the MachineSet.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
@@ -175,6 +231,70 @@ type MachineSetStatus struct {
 
 // ANCHOR_END: MachineSetStatus
 
+// MachineRolloutStatus reports the machines holding a rollout or scale operation of a MachineSet or of a MachineDeployment.
+// Each list reports at most 10 machines, sorted by name; the corresponding count field reports the total number of machines.
+type MachineRolloutStatus struct {
+	// PendingCreation lists the machines which are still being provisioned, i.e. the machines without a Node yet.
+	// +optional
+	PendingCreation []MachineRolloutBlocker `json:"pendingCreation,omitempty"`
+
+	// PendingCreationCount is the total number of machines which are still being provisioned,
+	// which might be greater than the number of machines listed in PendingCreation.
+	// +optional
+	PendingCreationCount int32 `json:"pendingCreationCount,omitempty"`
+
+	// PendingDeletion lists the machines which are being deleted, e.g. the machines waiting for a deletion hook,
+	// for the Node to be drained or for the volumes to be detached.
+	// +optional
+	PendingDeletion []MachineRolloutBlocker `json:"pendingDeletion,omitempty"`
+
+	// PendingDeletionCount is the total number of machines which are being deleted,
+	// which might be greater than the number of machines listed in PendingDeletion.
+	// +optional
+	PendingDeletionCount int32 `json:"pendingDeletionCount,omitempty"`
+
+	// FailingDrain lists the machines being deleted for which draining the Node is failing,
+	// e.g. because of a PodDisruptionBudget.
+	// +optional
+	FailingDrain []MachineRolloutBlocker `json:"failingDrain,omitempty"`
+
+	// FailingDrainCount is the total number of machines for which draining the Node is failing,
+	// which might be greater than the number of machines listed in FailingDrain.
+	// +optional
+	FailingDrainCount int32 `json:"failingDrainCount,omitempty"`
+
+	// FailingInfrastructureProvisioning lists the machines for which the infrastructure provider is reporting
+	// a failure while provisioning the infrastructure.
+	// +optional
+	FailingInfrastructureProvisioning []MachineRolloutBlocker `json:"failingInfrastructureProvisioning,omitempty"`
+
+	// FailingInfrastructureProvisioningCount is the total number of machines for which the infrastructure provider
+	// is reporting a failure, which might be greater than the number of machines listed in FailingInfrastructureProvisioning.
+	// +optional
+	FailingInfrastructureProvisioningCount int32 `json:"failingInfrastructureProvisioningCount,omitempty"`
+}
+
+// MachineRolloutBlocker is a machine holding a rollout or scale operation.
+type MachineRolloutBlocker struct {
+	// Name is the name of the Machine.
+	Name string `json:"name"`
+
+	// Reason is a brief CamelCase reason why the machine is holding the operation, as reported
+	// by the conditions of the Machine.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable message indicating details about why the machine is holding the operation.
+	// Messages longer than 256 characters are truncated.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// IsEmpty returns true if no machine is holding the rollout or scale operation.
+func (r *MachineRolloutStatus) IsEmpty() bool {
+	return r == nil || len(r.PendingCreation)+len(r.PendingDeletion)+len(r.FailingDrain)+len(r.FailingInfrastructureProvisioning) == 0
+}
+
 // Validate validates the MachineSet fields.
 func (m *MachineSet) Validate() field.ErrorList {
 	errors := field.ErrorList{}
@@ -17,40 +17,92 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/admission/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	capilabels "sigs.k8s.io/cluster-api/internal/labels"
+	"sigs.k8s.io/cluster-api/internal/util/naming"
 	"sigs.k8s.io/cluster-api/util/version"
 )
 
 func (m *MachineSet) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	// This registers MachineSet as a validating webhook and
+	// machineSetDefaulter as a defaulting webhook.
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(m).
+		WithDefaulter(MachineSetDefaulter(mgr.GetScheme())).
 		Complete()
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-cluster-x-k8s-io-v1beta1-machineset,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=machinesets,versions=v1beta1,name=validation.machineset.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-cluster-x-k8s-io-v1beta1-machineset,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=machinesets,versions=v1beta1,name=default.machineset.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
-var _ webhook.Defaulter = &MachineSet{}
+var _ webhook.CustomDefaulter = &machineSetDefaulter{}
 var _ webhook.Validator = &MachineSet{}
 
-// Default sets default MachineSet field values.
-func (m *MachineSet) Default() {
+// MachineSetDefaulter creates a new CustomDefaulter for MachineSets.
+func MachineSetDefaulter(scheme *runtime.Scheme) webhook.CustomDefaulter {
+	// Note: The error return parameter is always nil and will be dropped with the next CR release.
+	decoder, _ := admission.NewDecoder(scheme)
+	return &machineSetDefaulter{
+		decoder: decoder,
+	}
+}
+
+// machineSetDefaulter implements a defaulting webhook for MachineSet.
+type machineSetDefaulter struct {
+	decoder *admission.Decoder
+}
+
+// Default implements webhook.CustomDefaulter.
+func (webhook *machineSetDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	m, ok := obj.(*MachineSet)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a MachineSet but got a %T", obj))
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	dryRun := false
+	if req.DryRun != nil {
+		dryRun = *req.DryRun
+	}
+
+	var oldMS *MachineSet
+	if req.Operation == v1.Update {
+		oldMS = &MachineSet{}
+		if err := webhook.decoder.DecodeRaw(req.OldObject, oldMS); err != nil {
+			return errors.Wrapf(err, "failed to decode oldObject to MachineSet")
+		}
+	}
+
 	if m.Labels == nil {
 		m.Labels = make(map[string]string)
 	}
 	m.Labels[ClusterNameLabel] = m.Spec.ClusterName
 
+	replicas, err := calculateMachineSetReplicas(ctx, oldMS, m, dryRun)
+	if err != nil {
+		return err
+	}
+	m.Spec.Replicas = pointer.Int32(replicas)
+
 	if m.Spec.DeletePolicy == "" {
 		randomPolicy := string(RandomMachineSetDeletePolicy)
 		m.Spec.DeletePolicy = randomPolicy
@@ -74,6 +126,8 @@ func (m *MachineSet) Default() {
 		normalizedVersion := "v" + *m.Spec.Template.Spec.Version
 		m.Spec.Template.Spec.Version = &normalizedVersion
 	}
+
+	return nil
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -142,9 +196,70 @@ func (m *MachineSet) validate(old *MachineSet) error {
 		}
 	}
 
+	allErrs = append(allErrs, validateMachineTaints(m.Spec.Template.Spec.Taints, specPath.Child("template", "spec", "taints"))...)
+	allErrs = append(allErrs, validateNodeDrainOptions(m.Spec.Template.Spec.NodeDrainOptions, specPath.Child("template", "spec", "nodeDrainOptions"))...)
+	allErrs = append(allErrs, validateAuxiliaryInfrastructureRefs(m.Spec.Template.Spec.AuxiliaryInfrastructureRefs, m.Namespace, specPath.Child("template", "spec", "auxiliaryInfrastructureRefs"))...)
+
+	if m.Spec.MachineNamingStrategy != nil && m.Spec.MachineNamingStrategy.Template != "" {
+		// Render the template to surface invalid templates and names early; the random part of the
+		// name does not affect the validation.
+		if _, err := naming.Generate(m.Spec.MachineNamingStrategy.Template, naming.Values{
+			Cluster:           m.Spec.ClusterName,
+			MachineDeployment: m.Labels[MachineDeploymentNameLabel],
+			MachineSet:        m.Name,
+		}); err != nil {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					specPath.Child("machineNamingStrategy", "template"),
+					m.Spec.MachineNamingStrategy.Template,
+					err.Error(),
+				),
+			)
+		}
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 
 	return apierrors.NewInvalid(GroupVersion.WithKind("MachineSet").GroupKind(), m.Name, allErrs)
 }
+
+// calculateMachineSetReplicas calculates the default value of the replicas field.
+// The value will be calculated based on the following logic:
+// * if replicas is already set on newMS, keep the current value
+// * if the autoscaler min size and max size annotations are set:
+//   - if it's a new MachineSet, use min size
+//   - if the replicas field of the old MachineSet is < min size, use min size
+//   - if the replicas field of the old MachineSet is > max size, use max size
+//   - if the replicas field of the old MachineSet is in the (min size, max size) range, keep the value from the oldMS
+//
+// * otherwise use 1
+//
+// The goal of this logic is to provide a smoother UX for clusters using the Kubernetes autoscaler with standalone
+// MachineSets; see calculateMachineDeploymentReplicas for the supported use cases.
+// Note: Autoscaler only takes over control of the replicas field if the replicas value is in the (min size, max size) range.
+func calculateMachineSetReplicas(ctx context.Context, oldMS *MachineSet, newMS *MachineSet, dryRun bool) (int32, error) {
+	// If replicas is already set => Keep the current value.
+	if newMS.Spec.Replicas != nil {
+		return *newMS.Spec.Replicas, nil
+	}
+
+	log := ctrl.LoggerFrom(ctx).WithValues("MachineSet", klog.KObj(newMS))
+
+	// If both autoscaler annotations are set, use them to calculate the default value.
+	var oldReplicas *int32
+	if oldMS != nil {
+		oldReplicas = oldMS.Spec.Replicas
+	}
+	if replicas, ok, err := calculateAutoscalerReplicas(log, "MachineSet", newMS.Annotations, oldMS == nil, oldReplicas, dryRun); err != nil || ok {
+		return replicas, err
+	}
+
+	// If neither the default nor the autoscaler annotations are set => Default to 1.
+	if !dryRun {
+		log.V(2).Info("Replica field has been defaulted to 1")
+	}
+	return 1, nil
+}
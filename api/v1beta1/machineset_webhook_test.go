@@ -17,13 +17,14 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
-
-	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func TestMachineSetDefault(t *testing.T) {
@@ -40,16 +41,191 @@ func TestMachineSetDefault(t *testing.T) {
 			},
 		},
 	}
-	t.Run("for MachineSet", utildefaulting.DefaultValidateTest(ms))
-	ms.Default()
+
+	scheme, err := SchemeBuilder.Build()
+	g.Expect(err).ToNot(HaveOccurred())
+	defaulter := MachineSetDefaulter(scheme)
+
+	t.Run("for MachineSet", defaultValidateTestCustomDefaulter(ms, defaulter))
+
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+		},
+	})
+	g.Expect(defaulter.Default(ctx, ms)).To(Succeed())
 
 	g.Expect(ms.Labels[ClusterNameLabel]).To(Equal(ms.Spec.ClusterName))
+	g.Expect(ms.Spec.Replicas).To(Equal(pointer.Int32(1)))
 	g.Expect(ms.Spec.DeletePolicy).To(Equal(string(RandomMachineSetDeletePolicy)))
 	g.Expect(ms.Spec.Selector.MatchLabels).To(HaveKeyWithValue(MachineSetNameLabel, "test-ms"))
 	g.Expect(ms.Spec.Template.Labels).To(HaveKeyWithValue(MachineSetNameLabel, "test-ms"))
 	g.Expect(*ms.Spec.Template.Spec.Version).To(Equal("v1.19.10"))
 }
 
+func TestCalculateMachineSetReplicas(t *testing.T) {
+	tests := []struct {
+		name             string
+		newMS            *MachineSet
+		oldMS            *MachineSet
+		expectedReplicas int32
+		expectErr        bool
+	}{
+		{
+			name: "if new MS has replicas set, keep that value",
+			newMS: &MachineSet{
+				Spec: MachineSetSpec{
+					Replicas: pointer.Int32(5),
+				},
+			},
+			expectedReplicas: 5,
+		},
+		{
+			name:             "if new MS does not have replicas set and no annotations, use 1",
+			newMS:            &MachineSet{},
+			expectedReplicas: 1,
+		},
+		{
+			name: "if new MS only has min size annotation, fallback to 1",
+			newMS: &MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						autoscalerMinSize: "3",
+					},
+				},
+			},
+			expectedReplicas: 1,
+		},
+		{
+			name: "if new MS only has max size annotation, fallback to 1",
+			newMS: &MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						autoscalerMaxSize: "7",
+					},
+				},
+			},
+			expectedReplicas: 1,
+		},
+		{
+			name: "if new MS has min and max size annotation and min size is invalid, fail",
+			newMS: &MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						autoscalerMinSize: "abc",
+						autoscalerMaxSize: "7",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "if new MS has min and max size annotation and max size is invalid, fail",
+			newMS: &MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						autoscalerMinSize: "3",
+						autoscalerMaxSize: "abc",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "if new MS has min and max size annotation and new MS is a new MS, use min size",
+			newMS: &MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						autoscalerMinSize: "3",
+						autoscalerMaxSize: "7",
+					},
+				},
+			},
+			expectedReplicas: 3,
+		},
+		{
+			name: "if new MS has min and max size annotation and old MS doesn't have replicas set, use min size",
+			newMS: &MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						autoscalerMinSize: "3",
+						autoscalerMaxSize: "7",
+					},
+				},
+			},
+			oldMS:            &MachineSet{},
+			expectedReplicas: 3,
+		},
+		{
+			name: "if new MS has min and max size annotation and old MS replicas is below min size, use min size",
+			newMS: &MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						autoscalerMinSize: "3",
+						autoscalerMaxSize: "7",
+					},
+				},
+			},
+			oldMS: &MachineSet{
+				Spec: MachineSetSpec{
+					Replicas: pointer.Int32(1),
+				},
+			},
+			expectedReplicas: 3,
+		},
+		{
+			name: "if new MS has min and max size annotation and old MS replicas is above max size, use max size",
+			newMS: &MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						autoscalerMinSize: "3",
+						autoscalerMaxSize: "7",
+					},
+				},
+			},
+			oldMS: &MachineSet{
+				Spec: MachineSetSpec{
+					Replicas: pointer.Int32(15),
+				},
+			},
+			expectedReplicas: 7,
+		},
+		{
+			name: "if new MS has min and max size annotation and old MS replicas is between min and max size, use old MS replicas",
+			newMS: &MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						autoscalerMinSize: "3",
+						autoscalerMaxSize: "7",
+					},
+				},
+			},
+			oldMS: &MachineSet{
+				Spec: MachineSetSpec{
+					Replicas: pointer.Int32(4),
+				},
+			},
+			expectedReplicas: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			replicas, err := calculateMachineSetReplicas(context.Background(), tt.oldMS, tt.newMS, false)
+
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(replicas).To(Equal(tt.expectedReplicas))
+		})
+	}
+}
+
 func TestMachineSetLabelSelectorMatchValidation(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -218,3 +394,58 @@ func TestMachineSetVersionValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineSetMachineNamingStrategyValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		expectErr bool
+	}{
+		{
+			name:      "should succeed when given a valid template",
+			template:  "{{ .cluster }}-{{ .machineDeployment }}-{{ .random }}",
+			expectErr: false,
+		},
+		{
+			name:      "should return error when the template does not reference random",
+			template:  "{{ .cluster }}-{{ .machineDeployment }}",
+			expectErr: true,
+		},
+		{
+			name:      "should return error when the template cannot be parsed",
+			template:  "{{ .cluster }-{{ .random }}",
+			expectErr: true,
+		},
+		{
+			name:      "should return error when the generated name is not valid",
+			template:  "{{ .cluster }}_{{ .random }}",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: MachineSetSpec{
+					ClusterName: "test-cluster",
+					MachineNamingStrategy: &MachineNamingStrategy{
+						Template: tt.template,
+					},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(obj.ValidateCreate()).NotTo(Succeed())
+				g.Expect(obj.ValidateUpdate(obj)).NotTo(Succeed())
+			} else {
+				g.Expect(obj.ValidateCreate()).To(Succeed())
+				g.Expect(obj.ValidateUpdate(obj)).To(Succeed())
+			}
+		})
+	}
+}
@@ -124,6 +124,21 @@ func (in *ClusterClass) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClassLineage) DeepCopyInto(out *ClusterClassLineage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClassLineage.
+func (in *ClusterClassLineage) DeepCopy() *ClusterClassLineage {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClassLineage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterClassList) DeepCopyInto(out *ClusterClassList) {
 	*out = *in
@@ -208,6 +223,11 @@ func (in *ClusterClassSpec) DeepCopyInto(out *ClusterClassSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Lineage != nil {
+		in, out := &in.Lineage, &out.Lineage
+		*out = new(ClusterClassLineage)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClassSpec.
@@ -335,6 +355,31 @@ func (in *ClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMetadataPropagation) DeepCopyInto(out *ClusterMetadataPropagation) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMetadataPropagation.
+func (in *ClusterMetadataPropagation) DeepCopy() *ClusterMetadataPropagation {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMetadataPropagation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterNetwork) DeepCopyInto(out *ClusterNetwork) {
 	*out = *in
@@ -365,15 +410,44 @@ func (in *ClusterNetwork) DeepCopy() *ClusterNetwork {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPause) DeepCopyInto(out *ClusterPause) {
+	*out = *in
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPause.
+func (in *ClusterPause) DeepCopy() *ClusterPause {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPause)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	*out = *in
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(ClusterPause)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ClusterNetwork != nil {
 		in, out := &in.ClusterNetwork, &out.ClusterNetwork
 		*out = new(ClusterNetwork)
 		(*in).DeepCopyInto(*out)
 	}
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.ControlPlaneEndpointRef != nil {
+		in, out := &in.ControlPlaneEndpointRef, &out.ControlPlaneEndpointRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
 	if in.ControlPlaneRef != nil {
 		in, out := &in.ControlPlaneRef, &out.ControlPlaneRef
 		*out = new(v1.ObjectReference)
@@ -389,6 +463,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(Topology)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MetadataPropagation != nil {
+		in, out := &in.MetadataPropagation, &out.MetadataPropagation
+		*out = new(ClusterMetadataPropagation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -421,6 +500,16 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(ClusterSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = new(ClusterTopologyStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(Conditions, len(*in))
@@ -440,6 +529,111 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSummary) DeepCopyInto(out *ClusterSummary) {
+	*out = *in
+	if in.ControlPlane != nil {
+		in, out := &in.ControlPlane, &out.ControlPlane
+		*out = new(ReplicasSummary)
+		**out = **in
+	}
+	out.Workers = in.Workers
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSummary.
+func (in *ClusterSummary) DeepCopy() *ClusterSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTopologyPatchStatus) DeepCopyInto(out *ClusterTopologyPatchStatus) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]ClusterTopologyPatchTarget, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTopologyPatchStatus.
+func (in *ClusterTopologyPatchStatus) DeepCopy() *ClusterTopologyPatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTopologyPatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTopologyPatchTarget) DeepCopyInto(out *ClusterTopologyPatchTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTopologyPatchTarget.
+func (in *ClusterTopologyPatchTarget) DeepCopy() *ClusterTopologyPatchTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTopologyPatchTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTopologyStatus) DeepCopyInto(out *ClusterTopologyStatus) {
+	*out = *in
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make([]ClusterTopologyVariableStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]ClusterTopologyPatchStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTopologyStatus.
+func (in *ClusterTopologyStatus) DeepCopy() *ClusterTopologyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTopologyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTopologyVariableStatus) DeepCopyInto(out *ClusterTopologyVariableStatus) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTopologyVariableStatus.
+func (in *ClusterTopologyVariableStatus) DeepCopy() *ClusterTopologyVariableStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTopologyVariableStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterVariable) DeepCopyInto(out *ClusterVariable) {
 	*out = *in
@@ -472,6 +666,22 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionObservation) DeepCopyInto(out *ConditionObservation) {
+	*out = *in
+	in.LastObservedTime.DeepCopyInto(&out.LastObservedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionObservation.
+func (in *ConditionObservation) DeepCopy() *ConditionObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Conditions) DeepCopyInto(out *Conditions) {
 	{
@@ -871,6 +1081,25 @@ func (in MachineAddresses) DeepCopy() MachineAddresses {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineBootstrapProgress) DeepCopyInto(out *MachineBootstrapProgress) {
+	*out = *in
+	if in.LastHeartbeatTime != nil {
+		in, out := &in.LastHeartbeatTime, &out.LastHeartbeatTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineBootstrapProgress.
+func (in *MachineBootstrapProgress) DeepCopy() *MachineBootstrapProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineBootstrapProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineDeployment) DeepCopyInto(out *MachineDeployment) {
 	*out = *in
@@ -999,6 +1228,21 @@ func (in *MachineDeploymentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineDeploymentRollbackConfig) DeepCopyInto(out *MachineDeploymentRollbackConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDeploymentRollbackConfig.
+func (in *MachineDeploymentRollbackConfig) DeepCopy() *MachineDeploymentRollbackConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineDeploymentRollbackConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineDeploymentSpec) DeepCopyInto(out *MachineDeploymentSpec) {
 	*out = *in
@@ -1028,11 +1272,21 @@ func (in *MachineDeploymentSpec) DeepCopyInto(out *MachineDeploymentSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(MachineDeploymentRollbackConfig)
+		**out = **in
+	}
 	if in.ProgressDeadlineSeconds != nil {
 		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MachineNamingStrategy != nil {
+		in, out := &in.MachineNamingStrategy, &out.MachineNamingStrategy
+		*out = new(MachineNamingStrategy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineDeploymentSpec.
@@ -1048,6 +1302,15 @@ func (in *MachineDeploymentSpec) DeepCopy() *MachineDeploymentSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineDeploymentStatus) DeepCopyInto(out *MachineDeploymentStatus) {
 	*out = *in
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = (*in).DeepCopy()
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(MachineRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(Conditions, len(*in))
@@ -1269,6 +1532,26 @@ func (in *MachineHealthCheckList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineHealthCheckReboot) DeepCopyInto(out *MachineHealthCheckReboot) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineHealthCheckReboot.
+func (in *MachineHealthCheckReboot) DeepCopy() *MachineHealthCheckReboot {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineHealthCheckReboot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineHealthCheckSpec) DeepCopyInto(out *MachineHealthCheckSpec) {
 	*out = *in
@@ -1278,6 +1561,11 @@ func (in *MachineHealthCheckSpec) DeepCopyInto(out *MachineHealthCheckSpec) {
 		*out = make([]UnhealthyCondition, len(*in))
 		copy(*out, *in)
 	}
+	if in.UnhealthyNodeExpressions != nil {
+		in, out := &in.UnhealthyNodeExpressions, &out.UnhealthyNodeExpressions
+		*out = make([]UnhealthyNodeExpression, len(*in))
+		copy(*out, *in)
+	}
 	if in.MaxUnhealthy != nil {
 		in, out := &in.MaxUnhealthy, &out.MaxUnhealthy
 		*out = new(intstr.IntOrString)
@@ -1298,6 +1586,11 @@ func (in *MachineHealthCheckSpec) DeepCopyInto(out *MachineHealthCheckSpec) {
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.Reboot != nil {
+		in, out := &in.Reboot, &out.Reboot
+		*out = new(MachineHealthCheckReboot)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineHealthCheckSpec.
@@ -1318,6 +1611,11 @@ func (in *MachineHealthCheckStatus) DeepCopyInto(out *MachineHealthCheckStatus)
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DryRunRemediationTargets != nil {
+		in, out := &in.DryRunRemediationTargets, &out.DryRunRemediationTargets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(Conditions, len(*in))
@@ -1390,6 +1688,21 @@ func (in *MachineList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineNamingStrategy) DeepCopyInto(out *MachineNamingStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineNamingStrategy.
+func (in *MachineNamingStrategy) DeepCopy() *MachineNamingStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineNamingStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineRollingUpdateDeployment) DeepCopyInto(out *MachineRollingUpdateDeployment) {
 	*out = *in
@@ -1420,6 +1733,56 @@ func (in *MachineRollingUpdateDeployment) DeepCopy() *MachineRollingUpdateDeploy
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineRolloutBlocker) DeepCopyInto(out *MachineRolloutBlocker) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineRolloutBlocker.
+func (in *MachineRolloutBlocker) DeepCopy() *MachineRolloutBlocker {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineRolloutBlocker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineRolloutStatus) DeepCopyInto(out *MachineRolloutStatus) {
+	*out = *in
+	if in.PendingCreation != nil {
+		in, out := &in.PendingCreation, &out.PendingCreation
+		*out = make([]MachineRolloutBlocker, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingDeletion != nil {
+		in, out := &in.PendingDeletion, &out.PendingDeletion
+		*out = make([]MachineRolloutBlocker, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailingDrain != nil {
+		in, out := &in.FailingDrain, &out.FailingDrain
+		*out = make([]MachineRolloutBlocker, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailingInfrastructureProvisioning != nil {
+		in, out := &in.FailingInfrastructureProvisioning, &out.FailingInfrastructureProvisioning
+		*out = make([]MachineRolloutBlocker, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineRolloutStatus.
+func (in *MachineRolloutStatus) DeepCopy() *MachineRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineSet) DeepCopyInto(out *MachineSet) {
 	*out = *in
@@ -1489,6 +1852,11 @@ func (in *MachineSetSpec) DeepCopyInto(out *MachineSetSpec) {
 	}
 	in.Selector.DeepCopyInto(&out.Selector)
 	in.Template.DeepCopyInto(&out.Template)
+	if in.MachineNamingStrategy != nil {
+		in, out := &in.MachineNamingStrategy, &out.MachineNamingStrategy
+		*out = new(MachineNamingStrategy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSetSpec.
@@ -1514,6 +1882,11 @@ func (in *MachineSetStatus) DeepCopyInto(out *MachineSetStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(MachineRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(Conditions, len(*in))
@@ -1538,6 +1911,11 @@ func (in *MachineSpec) DeepCopyInto(out *MachineSpec) {
 	*out = *in
 	in.Bootstrap.DeepCopyInto(&out.Bootstrap)
 	out.InfrastructureRef = in.InfrastructureRef
+	if in.AuxiliaryInfrastructureRefs != nil {
+		in, out := &in.AuxiliaryInfrastructureRefs, &out.AuxiliaryInfrastructureRefs
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.Version != nil {
 		in, out := &in.Version, &out.Version
 		*out = new(string)
@@ -1568,6 +1946,18 @@ func (in *MachineSpec) DeepCopyInto(out *MachineSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]v1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeDrainOptions != nil {
+		in, out := &in.NodeDrainOptions, &out.NodeDrainOptions
+		*out = new(NodeDrainOptions)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSpec.
@@ -1623,6 +2013,18 @@ func (in *MachineStatus) DeepCopyInto(out *MachineStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ConditionObservations != nil {
+		in, out := &in.ConditionObservations, &out.ConditionObservations
+		*out = make(map[ConditionType]ConditionObservation, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.BootstrapProgress != nil {
+		in, out := &in.BootstrapProgress, &out.BootstrapProgress
+		*out = new(MachineBootstrapProgress)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineStatus.
@@ -1672,6 +2074,43 @@ func (in *NetworkRanges) DeepCopy() *NetworkRanges {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDrainOptions) DeepCopyInto(out *NodeDrainOptions) {
+	*out = *in
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SkipPodSelectors != nil {
+		in, out := &in.SkipPodSelectors, &out.SkipPodSelectors
+		*out = make([]metav1.LabelSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Force != nil {
+		in, out := &in.Force, &out.Force
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IgnoreDaemonSets != nil {
+		in, out := &in.IgnoreDaemonSets, &out.IgnoreDaemonSets
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDrainOptions.
+func (in *NodeDrainOptions) DeepCopy() *NodeDrainOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDrainOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectMeta) DeepCopyInto(out *ObjectMeta) {
 	*out = *in
@@ -1780,6 +2219,21 @@ func (in *PatchSelectorMatchMachineDeploymentClass) DeepCopy() *PatchSelectorMat
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicasSummary) DeepCopyInto(out *ReplicasSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicasSummary.
+func (in *ReplicasSummary) DeepCopy() *ReplicasSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicasSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Topology) DeepCopyInto(out *Topology) {
 	*out = *in
@@ -1828,6 +2282,21 @@ func (in *UnhealthyCondition) DeepCopy() *UnhealthyCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnhealthyNodeExpression) DeepCopyInto(out *UnhealthyNodeExpression) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnhealthyNodeExpression.
+func (in *UnhealthyNodeExpression) DeepCopy() *UnhealthyNodeExpression {
+	if in == nil {
+		return nil
+	}
+	out := new(UnhealthyNodeExpression)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VariableSchema) DeepCopyInto(out *VariableSchema) {
 	*out = *in
@@ -34,6 +34,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"sigs.k8s.io/cluster-api/api/v1beta1.Bootstrap":                                schema_sigsk8sio_cluster_api_api_v1beta1_Bootstrap(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.Cluster":                                  schema_sigsk8sio_cluster_api_api_v1beta1_Cluster(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterClass":                             schema_sigsk8sio_cluster_api_api_v1beta1_ClusterClass(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassLineage":                      schema_sigsk8sio_cluster_api_api_v1beta1_ClusterClassLineage(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassList":                         schema_sigsk8sio_cluster_api_api_v1beta1_ClusterClassList(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassPatch":                        schema_sigsk8sio_cluster_api_api_v1beta1_ClusterClassPatch(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassSpec":                         schema_sigsk8sio_cluster_api_api_v1beta1_ClusterClassSpec(ref),
@@ -42,11 +43,19 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassStatusVariableDefinition":     schema_sigsk8sio_cluster_api_api_v1beta1_ClusterClassStatusVariableDefinition(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassVariable":                     schema_sigsk8sio_cluster_api_api_v1beta1_ClusterClassVariable(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterList":                              schema_sigsk8sio_cluster_api_api_v1beta1_ClusterList(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterMetadataPropagation":               schema_sigsk8sio_cluster_api_api_v1beta1_ClusterMetadataPropagation(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterNetwork":                           schema_sigsk8sio_cluster_api_api_v1beta1_ClusterNetwork(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterPause":                             schema_sigsk8sio_cluster_api_api_v1beta1_ClusterPause(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterSpec":                              schema_sigsk8sio_cluster_api_api_v1beta1_ClusterSpec(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterStatus":                            schema_sigsk8sio_cluster_api_api_v1beta1_ClusterStatus(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterSummary":                           schema_sigsk8sio_cluster_api_api_v1beta1_ClusterSummary(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyPatchStatus":               schema_sigsk8sio_cluster_api_api_v1beta1_ClusterTopologyPatchStatus(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyPatchTarget":               schema_sigsk8sio_cluster_api_api_v1beta1_ClusterTopologyPatchTarget(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyStatus":                    schema_sigsk8sio_cluster_api_api_v1beta1_ClusterTopologyStatus(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyVariableStatus":            schema_sigsk8sio_cluster_api_api_v1beta1_ClusterTopologyVariableStatus(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ClusterVariable":                          schema_sigsk8sio_cluster_api_api_v1beta1_ClusterVariable(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.Condition":                                schema_sigsk8sio_cluster_api_api_v1beta1_Condition(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.ConditionObservation":                     schema_sigsk8sio_cluster_api_api_v1beta1_ConditionObservation(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ControlPlaneClass":                        schema_sigsk8sio_cluster_api_api_v1beta1_ControlPlaneClass(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ControlPlaneTopology":                     schema_sigsk8sio_cluster_api_api_v1beta1_ControlPlaneTopology(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ExternalPatchDefinition":                  schema_sigsk8sio_cluster_api_api_v1beta1_ExternalPatchDefinition(ref),
@@ -57,10 +66,12 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"sigs.k8s.io/cluster-api/api/v1beta1.LocalObjectTemplate":                      schema_sigsk8sio_cluster_api_api_v1beta1_LocalObjectTemplate(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.Machine":                                  schema_sigsk8sio_cluster_api_api_v1beta1_Machine(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineAddress":                           schema_sigsk8sio_cluster_api_api_v1beta1_MachineAddress(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineBootstrapProgress":                 schema_sigsk8sio_cluster_api_api_v1beta1_MachineBootstrapProgress(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDeployment":                        schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeployment(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentClass":                   schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentClass(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentClassTemplate":           schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentClassTemplate(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentList":                    schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentList(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentRollbackConfig":          schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentRollbackConfig(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentSpec":                    schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentSpec(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentStatus":                  schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentStatus(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentStrategy":                schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentStrategy(ref),
@@ -69,11 +80,15 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheck":                       schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheck(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckClass":                  schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckClass(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckList":                   schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckList(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckReboot":                 schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckReboot(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckSpec":                   schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckSpec(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckStatus":                 schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckStatus(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckTopology":               schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckTopology(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineList":                              schema_sigsk8sio_cluster_api_api_v1beta1_MachineList(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineNamingStrategy":                    schema_sigsk8sio_cluster_api_api_v1beta1_MachineNamingStrategy(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineRollingUpdateDeployment":           schema_sigsk8sio_cluster_api_api_v1beta1_MachineRollingUpdateDeployment(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineRolloutBlocker":                    schema_sigsk8sio_cluster_api_api_v1beta1_MachineRolloutBlocker(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.MachineRolloutStatus":                     schema_sigsk8sio_cluster_api_api_v1beta1_MachineRolloutStatus(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineSet":                               schema_sigsk8sio_cluster_api_api_v1beta1_MachineSet(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineSetList":                           schema_sigsk8sio_cluster_api_api_v1beta1_MachineSetList(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineSetSpec":                           schema_sigsk8sio_cluster_api_api_v1beta1_MachineSetSpec(ref),
@@ -82,13 +97,16 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineStatus":                            schema_sigsk8sio_cluster_api_api_v1beta1_MachineStatus(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.MachineTemplateSpec":                      schema_sigsk8sio_cluster_api_api_v1beta1_MachineTemplateSpec(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.NetworkRanges":                            schema_sigsk8sio_cluster_api_api_v1beta1_NetworkRanges(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.NodeDrainOptions":                         schema_sigsk8sio_cluster_api_api_v1beta1_NodeDrainOptions(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.ObjectMeta":                               schema_sigsk8sio_cluster_api_api_v1beta1_ObjectMeta(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.PatchDefinition":                          schema_sigsk8sio_cluster_api_api_v1beta1_PatchDefinition(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.PatchSelector":                            schema_sigsk8sio_cluster_api_api_v1beta1_PatchSelector(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.PatchSelectorMatch":                       schema_sigsk8sio_cluster_api_api_v1beta1_PatchSelectorMatch(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.PatchSelectorMatchMachineDeploymentClass": schema_sigsk8sio_cluster_api_api_v1beta1_PatchSelectorMatchMachineDeploymentClass(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.ReplicasSummary":                          schema_sigsk8sio_cluster_api_api_v1beta1_ReplicasSummary(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.Topology":                                 schema_sigsk8sio_cluster_api_api_v1beta1_Topology(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyCondition":                       schema_sigsk8sio_cluster_api_api_v1beta1_UnhealthyCondition(ref),
+		"sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyNodeExpression":                  schema_sigsk8sio_cluster_api_api_v1beta1_UnhealthyNodeExpression(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.VariableSchema":                           schema_sigsk8sio_cluster_api_api_v1beta1_VariableSchema(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.WorkersClass":                             schema_sigsk8sio_cluster_api_api_v1beta1_WorkersClass(ref),
 		"sigs.k8s.io/cluster-api/api/v1beta1.WorkersTopology":                          schema_sigsk8sio_cluster_api_api_v1beta1_WorkersTopology(ref),
@@ -248,6 +266,36 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterClass(ref common.ReferenceC
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterClassLineage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterClassLineage identifies a revision of a family of ClusterClasses.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the lineage; it is the same for all the revisions of the ClusterClass.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"revision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Revision of the ClusterClass in the lineage; newer revisions have a higher value.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"name", "revision"},
+			},
+		},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterClassList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -411,11 +459,17 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterClassSpec(ref common.Refere
 							},
 						},
 					},
+					"lineage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Lineage identifies the ClusterClass as a revision of a family of ClusterClasses, e.g. my-class-v1 and my-class-v2, so Clusters can be rebased to newer revisions gradually instead of mutating a shared ClusterClass in place.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassLineage"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassPatch", "sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassVariable", "sigs.k8s.io/cluster-api/api/v1beta1.ControlPlaneClass", "sigs.k8s.io/cluster-api/api/v1beta1.LocalObjectTemplate", "sigs.k8s.io/cluster-api/api/v1beta1.WorkersClass"},
+			"sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassLineage", "sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassPatch", "sigs.k8s.io/cluster-api/api/v1beta1.ClusterClassVariable", "sigs.k8s.io/cluster-api/api/v1beta1.ControlPlaneClass", "sigs.k8s.io/cluster-api/api/v1beta1.LocalObjectTemplate", "sigs.k8s.io/cluster-api/api/v1beta1.WorkersClass"},
 	}
 }
 
@@ -642,6 +696,49 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterList(ref common.ReferenceCa
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterMetadataPropagation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterMetadataPropagation defines which labels and annotations of a Cluster are propagated to the MachineDeployments, MachineSets, MachinePools, Machines, bootstrap configs and Secrets of the Cluster. Labels and annotations set on those objects by users or by other controllers take precedence over the values propagated from the Cluster.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"labels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Labels is the list of the keys of the Cluster labels to propagate.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"annotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Annotations is the list of the keys of the Cluster annotations to propagate.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterNetwork(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -683,6 +780,43 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterNetwork(ref common.Referenc
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterPause(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterPause describes why, by whom and until when a Cluster is paused.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason is a human-readable explanation of why the Cluster is paused, e.g. a maintenance window.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"actor": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Actor identifies who paused the Cluster, e.g. a user or an external tool.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"expiresAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExpiresAt is the time after which the Cluster is automatically unpaused; if not set, the Cluster stays paused until the pause is removed.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"reason"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -697,6 +831,12 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterSpec(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
+					"pause": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Pause can be used to prevent controllers from processing the Cluster and all its associated objects, recording why and by whom the Cluster has been paused; if ExpiresAt is set, the Cluster is automatically unpaused when the pause expires.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.ClusterPause"),
+						},
+					},
 					"clusterNetwork": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Cluster network configuration.",
@@ -710,6 +850,12 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterSpec(ref common.ReferenceCa
 							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.APIEndpoint"),
 						},
 					},
+					"controlPlaneEndpointRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ControlPlaneEndpointRef is an optional reference to an object in the namespace of the Cluster the ControlPlaneEndpoint is sourced from, e.g. a Service of type LoadBalancer, or an object of a provider managing external load balancers or VIPs which reports the endpoint in status.controlPlaneEndpoint. When set, ControlPlaneEndpoint is set from the referenced object instead of from the InfrastructureCluster.",
+							Ref:         ref("k8s.io/api/core/v1.ObjectReference"),
+						},
+					},
 					"controlPlaneRef": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ControlPlaneRef is an optional reference to a provider-specific resource that holds the details for provisioning the Control Plane for a Cluster.",
@@ -728,11 +874,17 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterSpec(ref common.ReferenceCa
 							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.Topology"),
 						},
 					},
+					"metadataPropagation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MetadataPropagation defines the labels and annotations of the Cluster which are propagated to the objects of the Cluster, and kept in sync when they change.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.ClusterMetadataPropagation"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.ObjectReference", "sigs.k8s.io/cluster-api/api/v1beta1.APIEndpoint", "sigs.k8s.io/cluster-api/api/v1beta1.ClusterNetwork", "sigs.k8s.io/cluster-api/api/v1beta1.Topology"},
+			"k8s.io/api/core/v1.ObjectReference", "sigs.k8s.io/cluster-api/api/v1beta1.APIEndpoint", "sigs.k8s.io/cluster-api/api/v1beta1.ClusterMetadataPropagation", "sigs.k8s.io/cluster-api/api/v1beta1.ClusterNetwork", "sigs.k8s.io/cluster-api/api/v1beta1.ClusterPause", "sigs.k8s.io/cluster-api/api/v1beta1.Topology"},
 	}
 }
 
@@ -795,6 +947,18 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterStatus(ref common.Reference
 							Format:      "",
 						},
 					},
+					"summary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Summary aggregates the replica counts of the control plane and of the workers of the cluster, together with the results of the MachineHealthChecks targeting it.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.ClusterSummary"),
+						},
+					},
+					"topology": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Topology reports the resolved variable values and the patches applied to the objects of the managed topology in the last reconcile, if the Cluster has a managed topology.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyStatus"),
+						},
+					},
 					"conditions": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Conditions defines current service state of the cluster.",
@@ -820,7 +984,212 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterStatus(ref common.Reference
 			},
 		},
 		Dependencies: []string{
-			"sigs.k8s.io/cluster-api/api/v1beta1.Condition", "sigs.k8s.io/cluster-api/api/v1beta1.FailureDomainSpec"},
+			"sigs.k8s.io/cluster-api/api/v1beta1.ClusterSummary", "sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyStatus", "sigs.k8s.io/cluster-api/api/v1beta1.Condition", "sigs.k8s.io/cluster-api/api/v1beta1.FailureDomainSpec"},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterSummary(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterSummary aggregates the health of the objects belonging to a Cluster, so it is possible to monitor the cluster without walking the whole tree of descendants.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"controlPlane": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ControlPlane reports the replica counts of the control plane, if the control plane provider supports replicas.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.ReplicasSummary"),
+						},
+					},
+					"workers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Workers reports the replica counts of all the MachineDeployments and MachinePools belonging to the cluster.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.ReplicasSummary"),
+						},
+					},
+					"unhealthyMachines": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UnhealthyMachines is the number of Machines currently failing the MachineHealthChecks targeting the cluster; Machines still within the node startup timeout are not counted.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"sigs.k8s.io/cluster-api/api/v1beta1.ReplicasSummary"},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterTopologyPatchStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterTopologyPatchStatus reports the objects modified by a patch of the ClusterClass.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the patch.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"targets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Targets lists the objects modified by the patch; it is empty if the patch did not modify any object, e.g. because it is not enabled or because its selectors do not match any template.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyPatchTarget"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyPatchTarget"},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterTopologyPatchTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterTopologyPatchTarget identifies an object modified by a patch through the object referencing it.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"holderKind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HolderKind is the kind of the object referencing the modified object, e.g. Cluster or MachineDeployment.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"holderName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HolderName is the name of the object referencing the modified object.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"fieldPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FieldPath is the path of the field referencing the modified object, e.g. spec.infrastructureRef.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"holderKind", "holderName", "fieldPath"},
+			},
+		},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterTopologyStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterTopologyStatus reports how the managed topology of a Cluster has been computed in the last reconcile, so it is possible to debug variable precedence and patch interactions.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"variables": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Variables lists the resolved values of the variables, after defaulting, including the values overridden for individual MachineDeployment topologies. Values of variables with a schema using the password format are redacted.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyVariableStatus"),
+									},
+								},
+							},
+						},
+					},
+					"patches": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Patches lists the patches of the ClusterClass, in the order they have been applied, together with the objects they modified.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyPatchStatus"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyPatchStatus", "sigs.k8s.io/cluster-api/api/v1beta1.ClusterTopologyVariableStatus"},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_ClusterTopologyVariableStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterTopologyVariableStatus reports the resolved value of a variable.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the variable.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"definitionFrom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DefinitionFrom specifies where the definition of this variable is from, if set in the Cluster topology.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"machineDeployment": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MachineDeployment is the name of the MachineDeployment topology the value is overridden for; it is empty for the values set at Cluster level.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value of the variable; it is not set if the value is redacted.",
+							Ref:         ref("k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON"),
+						},
+					},
+					"redacted": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Redacted is true if the value of the variable is not reported because it is sensitive.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON"},
 	}
 }
 
@@ -877,44 +1246,95 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_Condition(ref common.ReferenceCall
 							Format:      "",
 						},
 					},
-					"status": {
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status of the condition, one of True, False, Unknown.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"severity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Severity provides an explicit classification of Reason code, so the users or machines can immediately understand the current situation and act accordingly. The Severity field MUST be set only when Status=False.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Last time the condition transitioned from one status to another. This should be when the underlying condition changed. If that is not known, then using the time when the API field changed is acceptable.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The reason for the condition's last transition in CamelCase. The specific API may choose whether or not this field is considered a guaranteed API. This field may not be empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A human readable message indicating details about the transition. This field may be empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"type", "status", "lastTransitionTime"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_ConditionObservation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConditionObservation reports the freshness of a condition derived from observations of the workload cluster, so it is possible to distinguish a condition observed a few seconds ago from a condition that has not been refreshed for a long time, e.g. because the workload cluster is not reachable.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"lastObservedTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastObservedTime is the last time the condition has been refreshed from the workload cluster.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"stale": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Status of the condition, one of True, False, Unknown.",
-							Default:     "",
-							Type:        []string{"string"},
+							Description: "Stale is true if the condition has not been refreshed within the staleness threshold.",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"severity": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Severity provides an explicit classification of Reason code, so the users or machines can immediately understand the current situation and act accordingly. The Severity field MUST be set only when Status=False.",
+							Description: "Status is the status of the condition when LastObservedTime has been last updated.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"lastTransitionTime": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Last time the condition transitioned from one status to another. This should be when the underlying condition changed. If that is not known, then using the time when the API field changed is acceptable.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
-						},
-					},
 					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The reason for the condition's last transition in CamelCase. The specific API may choose whether or not this field is considered a guaranteed API. This field may not be empty.",
+							Description: "Reason is the reason of the condition when LastObservedTime has been last updated.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"message": {
+					"observedGeneration": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A human readable message indicating details about the transition. This field may be empty.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "ObservedGeneration is the generation of the object when LastObservedTime has been last updated.",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
 				},
-				Required: []string{"type", "status", "lastTransitionTime"},
+				Required: []string{"lastObservedTime"},
 			},
 		},
 		Dependencies: []string{
@@ -1473,6 +1893,43 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineAddress(ref common.Referenc
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineBootstrapProgress(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineBootstrapProgress reports the progress of the bootstrap process running on a machine.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase is the current phase of the bootstrap process, e.g. DownloadingBinaries, RunningKubeadm or WaitingForNode. Phases are defined by the bootstrap provider.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a human-readable message with details about the current phase.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastHeartbeatTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastHeartbeatTime is the last time the bootstrap process reported its progress.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"phase"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeployment(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1681,6 +2138,26 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentList(ref common.R
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentRollbackConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineDeploymentRollbackConfig describes the revision a MachineDeployment is rolling back to.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"revision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Revision is the revision to rollback to. If set to 0, rollback to the last revision.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1750,19 +2227,31 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentSpec(ref common.R
 							Format:      "",
 						},
 					},
+					"rollbackTo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RollbackTo is the config this MachineDeployment is rolling back to. When set, the machine template of the MachineSet with the given revision is copied into the MachineDeployment and the field is cleared.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentRollbackConfig"),
+						},
+					},
 					"progressDeadlineSeconds": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The maximum time in seconds for a deployment to make progress before it is considered to be failed. The deployment controller will continue to process failed deployments and a condition with a ProgressDeadlineExceeded reason will be surfaced in the deployment status. Note that progress will not be estimated during the time a deployment is paused. Defaults to 600s.",
+							Description: "The maximum time in seconds for a deployment to make progress before it is considered to be failed. The deployment controller will continue to process failed deployments and a Progressing condition with a ProgressDeadlineExceeded reason will be surfaced in the deployment status, together with an event; no automatic rollback is performed. Note that progress will not be estimated during the time a deployment is paused. Defaults to 600s.",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
+					"machineNamingStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MachineNamingStrategy allows changing the naming pattern used when creating the MachineSets and the Machines of this MachineDeployment.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineNamingStrategy"),
+						},
+					},
 				},
 				Required: []string{"clusterName", "selector", "template"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "k8s.io/apimachinery/pkg/apis/meta/v1.Time", "sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentStrategy", "sigs.k8s.io/cluster-api/api/v1beta1.MachineTemplateSpec"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "k8s.io/apimachinery/pkg/apis/meta/v1.Time", "sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentRollbackConfig", "sigs.k8s.io/cluster-api/api/v1beta1.MachineDeploymentStrategy", "sigs.k8s.io/cluster-api/api/v1beta1.MachineNamingStrategy", "sigs.k8s.io/cluster-api/api/v1beta1.MachineTemplateSpec"},
 	}
 }
 
@@ -1834,6 +2323,18 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentStatus(ref common
 							Format:      "",
 						},
 					},
+					"progressDeadline": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ProgressDeadline is the time by which the rollout or scale operation in progress is expected to make further progress; if no progress is made before this time, the progress deadline is considered exceeded. It is not set when no operation is in progress.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"rollout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rollout reports the machines of the MachineDeployment that are holding a rollout or scale operation, aggregated from all its MachineSets, e.g. machines still being provisioned or machines that cannot be drained, together with the reason why. It is not set when there are no such machines.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineRolloutStatus"),
+						},
+					},
 					"conditions": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Conditions defines current service state of the MachineDeployment.",
@@ -1852,7 +2353,7 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineDeploymentStatus(ref common
 			},
 		},
 		Dependencies: []string{
-			"sigs.k8s.io/cluster-api/api/v1beta1.Condition"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "sigs.k8s.io/cluster-api/api/v1beta1.Condition", "sigs.k8s.io/cluster-api/api/v1beta1.MachineRolloutStatus"},
 	}
 }
 
@@ -2161,6 +2662,27 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckList(ref common.
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckReboot(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineHealthCheckReboot defines how the MachineHealthCheck controller requests a reboot of unhealthy machines before remediating them.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeout is the time to wait for the Node to become healthy after a reboot has been requested; when this time expires the Machine is remediated. If not set, this value is defaulted to 10 minutes.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2197,6 +2719,20 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckSpec(ref common.
 							},
 						},
 					},
+					"unhealthyNodeExpressions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UnhealthyNodeExpressions contains a list of CEL expressions evaluated against the node, which extend UnhealthyConditions for criteria that cannot be expressed as a condition type and status, e.g. low allocatable resources or a kubelet version mismatch. The expressions are combined in a logical OR with UnhealthyConditions, i.e. if any of the expressions evaluates to true, the node is unhealthy.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyNodeExpression"),
+									},
+								},
+							},
+						},
+					},
 					"maxUnhealthy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Any further remediation is only allowed if at most \"MaxUnhealthy\" machines selected by \"selector\" are not healthy.",
@@ -2222,12 +2758,25 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckSpec(ref common.
 							Ref:         ref("k8s.io/api/core/v1.ObjectReference"),
 						},
 					},
+					"reboot": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reboot enables escalating remediation: when set, the MachineHealthCheck controller first requests a reboot of the infrastructure machine backing an unhealthy Machine, and only falls back to regular remediation (or to the RemediationTemplate, if defined) when the Node does not become healthy within the configured timeout. Reboots are requested by setting the \"cluster.x-k8s.io/reboot-requested\" annotation on the infrastructure machine; providers not supporting this contract will simply ignore the request.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckReboot"),
+						},
+					},
+					"mode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Mode defines whether the MachineHealthCheck remediates unhealthy machines; in DryRun mode the MachineHealthCheck evaluates the health of the machines and reports the machines it would have remediated, but it does not perform any remediation. If not set, this value is defaulted to Enforce.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"clusterName", "selector", "unhealthyConditions"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "k8s.io/apimachinery/pkg/util/intstr.IntOrString", "sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyCondition"},
+			"k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "k8s.io/apimachinery/pkg/util/intstr.IntOrString", "sigs.k8s.io/cluster-api/api/v1beta1.MachineHealthCheckReboot", "sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyCondition", "sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyNodeExpression"},
 	}
 }
 
@@ -2284,6 +2833,21 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckStatus(ref commo
 							},
 						},
 					},
+					"dryRunRemediationTargets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DryRunRemediationTargets shows the current list of machines that would have been remediated if the machine health check was not in DryRun mode.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 					"conditions": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Conditions defines current service state of the MachineHealthCheck.",
@@ -2342,111 +2906,266 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineHealthCheckTopology(ref com
 					},
 					"unhealthyRange": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Any further remediation is only allowed if the number of machines selected by \"selector\" as not healthy is within the range of \"UnhealthyRange\". Takes precedence over MaxUnhealthy. Eg. \"[3-5]\" - This means that remediation will be allowed only when: (a) there are at least 3 unhealthy machines (and) (b) there are at most 5 unhealthy machines",
+							Description: "Any further remediation is only allowed if the number of machines selected by \"selector\" as not healthy is within the range of \"UnhealthyRange\". Takes precedence over MaxUnhealthy. Eg. \"[3-5]\" - This means that remediation will be allowed only when: (a) there are at least 3 unhealthy machines (and) (b) there are at most 5 unhealthy machines",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"nodeStartupTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Machines older than this duration without a node will be considered to have failed and will be remediated. If you wish to disable this feature, set the value explicitly to 0.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"remediationTemplate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RemediationTemplate is a reference to a remediation template provided by an infrastructure provider.\n\nThis field is completely optional, when filled, the MachineHealthCheck controller creates a new object from the template referenced and hands off remediation of the machine to a controller that lives outside of Cluster API.",
+							Ref:         ref("k8s.io/api/core/v1.ObjectReference"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/util/intstr.IntOrString", "sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyCondition"},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineList contains a list of Machine.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.Machine"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "sigs.k8s.io/cluster-api/api/v1beta1.Machine"},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineNamingStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineNamingStrategy allows changing the naming pattern used when creating MachineSets and Machines.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"template": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Template defines the template to use for generating the names of the MachineSets and of the Machines. The following variables can be referenced: * `.cluster`: the name of the Cluster * `.machineDeployment`: the name of the MachineDeployment, if any * `.machineSet`: the name of the MachineSet; it is empty when generating MachineSet names * `.random`: a random alphanumeric string of 5 characters; it must be used in the template to guarantee the uniqueness of the generated names. The generated names must be valid DNS subdomains not longer than 63 characters. Example: \"{{ .cluster }}-{{ .machineDeployment }}-{{ .random }}\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineRollingUpdateDeployment(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineRollingUpdateDeployment is used to control the desired behavior of rolling update.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxUnavailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The maximum number of machines that can be unavailable during the update. Value can be an absolute number (ex: 5) or a percentage of desired machines (ex: 10%). Absolute number is calculated from percentage by rounding down. This can not be 0 if MaxSurge is 0. Defaults to 0. Example: when this is set to 30%, the old MachineSet can be scaled down to 70% of desired machines immediately when the rolling update starts. Once new machines are ready, old MachineSet can be scaled down further, followed by scaling up the new MachineSet, ensuring that the total number of machines available at all times during the update is at least 70% of desired machines.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+					"maxSurge": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The maximum number of machines that can be scheduled above the desired number of machines. Value can be an absolute number (ex: 5) or a percentage of desired machines (ex: 10%). This can not be 0 if MaxUnavailable is 0. Absolute number is calculated from percentage by rounding up. Defaults to 1. Example: when this is set to 30%, the new MachineSet can be scaled up immediately when the rolling update starts, such that the total number of old and new machines do not exceed 130% of desired machines. Once old machines have been killed, new MachineSet can be scaled up further, ensuring that total number of machines running at any time during the update is at most 130% of desired machines.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+					"deletePolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletePolicy defines the policy used by the MachineDeployment to identify nodes to delete when downscaling. Valid values are \"Random, \"Newest\", \"Oldest\" When no value is supplied, the default DeletePolicy of MachineSet is used",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/util/intstr.IntOrString"},
+	}
+}
+
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineRolloutBlocker(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachineRolloutBlocker is a machine holding a rollout or scale operation.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the Machine.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"nodeStartupTimeout": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Machines older than this duration without a node will be considered to have failed and will be remediated. If you wish to disable this feature, set the value explicitly to 0.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+							Description: "Reason is a brief CamelCase reason why the machine is holding the operation, as reported by the conditions of the Machine.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"remediationTemplate": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RemediationTemplate is a reference to a remediation template provided by an infrastructure provider.\n\nThis field is completely optional, when filled, the MachineHealthCheck controller creates a new object from the template referenced and hands off remediation of the machine to a controller that lives outside of Cluster API.",
-							Ref:         ref("k8s.io/api/core/v1.ObjectReference"),
+							Description: "Message is a human readable message indicating details about why the machine is holding the operation. Messages longer than 256 characters are truncated.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"name"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/util/intstr.IntOrString", "sigs.k8s.io/cluster-api/api/v1beta1.UnhealthyCondition"},
 	}
 }
 
-func schema_sigsk8sio_cluster_api_api_v1beta1_MachineList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_sigsk8sio_cluster_api_api_v1beta1_MachineRolloutStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "MachineList contains a list of Machine.",
+				Description: "MachineRolloutStatus reports the machines holding a rollout or scale operation of a MachineSet or of a MachineDeployment. Each list reports at most 10 machines, sorted by name; the corresponding count field reports the total number of machines.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"pendingCreation": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "PendingCreation lists the machines which are still being provisioned, i.e. the machines without a Node yet.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineRolloutBlocker"),
+									},
+								},
+							},
 						},
 					},
-					"apiVersion": {
+					"pendingCreationCount": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "PendingCreationCount is the total number of machines which are still being provisioned, which might be greater than the number of machines listed in PendingCreation.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"metadata": {
+					"pendingDeletion": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Description: "PendingDeletion lists the machines which are being deleted, e.g. the machines waiting for a deletion hook, for the Node to be drained or for the volumes to be detached.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineRolloutBlocker"),
+									},
+								},
+							},
 						},
 					},
-					"items": {
+					"pendingDeletionCount": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "PendingDeletionCount is the total number of machines which are being deleted, which might be greater than the number of machines listed in PendingDeletion.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"failingDrain": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailingDrain lists the machines being deleted for which draining the Node is failing, e.g. because of a PodDisruptionBudget.",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.Machine"),
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineRolloutBlocker"),
 									},
 								},
 							},
 						},
 					},
-				},
-				Required: []string{"items"},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "sigs.k8s.io/cluster-api/api/v1beta1.Machine"},
-	}
-}
-
-func schema_sigsk8sio_cluster_api_api_v1beta1_MachineRollingUpdateDeployment(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "MachineRollingUpdateDeployment is used to control the desired behavior of rolling update.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"maxUnavailable": {
+					"failingDrainCount": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The maximum number of machines that can be unavailable during the update. Value can be an absolute number (ex: 5) or a percentage of desired machines (ex: 10%). Absolute number is calculated from percentage by rounding down. This can not be 0 if MaxSurge is 0. Defaults to 0. Example: when this is set to 30%, the old MachineSet can be scaled down to 70% of desired machines immediately when the rolling update starts. Once new machines are ready, old MachineSet can be scaled down further, followed by scaling up the new MachineSet, ensuring that the total number of machines available at all times during the update is at least 70% of desired machines.",
-							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+							Description: "FailingDrainCount is the total number of machines for which draining the Node is failing, which might be greater than the number of machines listed in FailingDrain.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"maxSurge": {
+					"failingInfrastructureProvisioning": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The maximum number of machines that can be scheduled above the desired number of machines. Value can be an absolute number (ex: 5) or a percentage of desired machines (ex: 10%). This can not be 0 if MaxUnavailable is 0. Absolute number is calculated from percentage by rounding up. Defaults to 1. Example: when this is set to 30%, the new MachineSet can be scaled up immediately when the rolling update starts, such that the total number of old and new machines do not exceed 130% of desired machines. Once old machines have been killed, new MachineSet can be scaled up further, ensuring that total number of machines running at any time during the update is at most 130% of desired machines.",
-							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+							Description: "FailingInfrastructureProvisioning lists the machines for which the infrastructure provider is reporting a failure while provisioning the infrastructure.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineRolloutBlocker"),
+									},
+								},
+							},
 						},
 					},
-					"deletePolicy": {
+					"failingInfrastructureProvisioningCount": {
 						SchemaProps: spec.SchemaProps{
-							Description: "DeletePolicy defines the policy used by the MachineDeployment to identify nodes to delete when downscaling. Valid values are \"Random, \"Newest\", \"Oldest\" When no value is supplied, the default DeletePolicy of MachineSet is used",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "FailingInfrastructureProvisioningCount is the total number of machines for which the infrastructure provider is reporting a failure, which might be greater than the number of machines listed in FailingInfrastructureProvisioning.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/util/intstr.IntOrString"},
+			"sigs.k8s.io/cluster-api/api/v1beta1.MachineRolloutBlocker"},
 	}
 }
 
@@ -2563,7 +3282,7 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineSetSpec(ref common.Referenc
 					},
 					"replicas": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Replicas is the number of desired replicas. This is a pointer to distinguish between explicit zero and unspecified. Defaults to 1.",
+							Description: "Replicas is the number of desired replicas. This is a pointer to distinguish between explicit zero and unspecified.\n\nDefaults to: * if the Kubernetes autoscaler min size and max size annotations are set:\n  - if it's a new MachineSet, use min size\n  - if the replicas field of the old MachineSet is < min size, use min size\n  - if the replicas field of the old MachineSet is > max size, use max size\n  - if the replicas field of the old MachineSet is in the (min size, max size) range, keep the value from the oldMS\n* otherwise use 1 Note: Defaulting will be run whenever the replicas field is not set: * A new MachineSet is created with replicas not set. * On an existing MachineSet the replicas field was first set and is now unset. Those cases are especially relevant for the following Kubernetes autoscaler use cases: * A new MachineSet is created and replicas should be managed by the autoscaler * An existing MachineSet which initially wasn't controlled by the autoscaler\n  should be later controlled by the autoscaler",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
@@ -2596,12 +3315,18 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineSetSpec(ref common.Referenc
 							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineTemplateSpec"),
 						},
 					},
+					"machineNamingStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MachineNamingStrategy allows changing the naming pattern used when creating the Machines of this MachineSet.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineNamingStrategy"),
+						},
+					},
 				},
 				Required: []string{"clusterName", "selector"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "sigs.k8s.io/cluster-api/api/v1beta1.MachineTemplateSpec"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "sigs.k8s.io/cluster-api/api/v1beta1.MachineNamingStrategy", "sigs.k8s.io/cluster-api/api/v1beta1.MachineTemplateSpec"},
 	}
 }
 
@@ -2671,6 +3396,12 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineSetStatus(ref common.Refere
 							Format: "",
 						},
 					},
+					"rollout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rollout reports the machines of the MachineSet that are holding a rollout or scale operation, e.g. machines still being provisioned or machines that cannot be drained, together with the reason why. It is not set when there are no such machines.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineRolloutStatus"),
+						},
+					},
 					"conditions": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Conditions defines current service state of the MachineSet.",
@@ -2689,7 +3420,7 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineSetStatus(ref common.Refere
 			},
 		},
 		Dependencies: []string{
-			"sigs.k8s.io/cluster-api/api/v1beta1.Condition"},
+			"sigs.k8s.io/cluster-api/api/v1beta1.Condition", "sigs.k8s.io/cluster-api/api/v1beta1.MachineRolloutStatus"},
 	}
 }
 
@@ -2722,6 +3453,20 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineSpec(ref common.ReferenceCa
 							Ref:         ref("k8s.io/api/core/v1.ObjectReference"),
 						},
 					},
+					"auxiliaryInfrastructureRefs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AuxiliaryInfrastructureRefs is a list of optional references to additional custom resources offered by infrastructure providers, e.g. a BMC object next to a VM object, or network attachment objects for secondary NICs. Auxiliary infrastructure objects are owned by the Machine and deleted together with it; the Machine is not considered ready until all of them report status.ready, as surfaced by the AuxiliaryInfrastructureReady condition. In the templates of MachineSets and MachineDeployments these are references to templates, which are cloned for each Machine like the InfrastructureRef. Not supported in MachinePools.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.ObjectReference"),
+									},
+								},
+							},
+						},
+					},
 					"version": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Version defines the desired Kubernetes version. This field is meant to be optionally used by bootstrap providers.",
@@ -2761,12 +3506,39 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineSpec(ref common.ReferenceCa
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
+					"taints": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Taints are the taints to be set on the Node hosted by the Machine. Taints are identified by key and effect, and they are continuously reconciled: - taints in this list are added to the Node, or updated if the Node has a taint with the same\n  key and effect but a different value (the value from the Machine wins);\n- taints removed from this list are removed from the Node; - taints on the Node which have never been set from the Machine, e.g. taints set by the kubelet or by\n  other controllers, are preserved.\nNOTE: Changes to taints are propagated in-place from MachineDeployments and MachineSets to Machines, without triggering a rollout.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.Taint"),
+									},
+								},
+							},
+						},
+					},
+					"infrastructureReusePolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InfrastructureReusePolicy defines what happens to the infrastructure of the Machine when the Machine is deleted. Delete (the default) deletes the infrastructure; Release releases it back to the infrastructure provider pool so it can be reused by other Machines, and it requires an infrastructure provider supporting the reuse of hosts. NOTE: Changes to the infrastructure reuse policy are propagated in-place from MachineDeployments and MachineSets to Machines, without triggering a rollout.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"nodeDrainOptions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeDrainOptions defines how the Node hosted by the Machine is drained before the Machine is deleted. If not set, Pods are evicted honoring their termination grace period and PodDisruptionBudgets, and Pods not managed by a controller are removed as well. NOTE: Changes to the drain options are propagated in-place from MachineDeployments and MachineSets to Machines, without triggering a rollout.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.NodeDrainOptions"),
+						},
+					},
 				},
 				Required: []string{"clusterName", "bootstrap", "infrastructureRef"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "sigs.k8s.io/cluster-api/api/v1beta1.Bootstrap"},
+			"k8s.io/api/core/v1.ObjectReference", "k8s.io/api/core/v1.Taint", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "sigs.k8s.io/cluster-api/api/v1beta1.Bootstrap", "sigs.k8s.io/cluster-api/api/v1beta1.NodeDrainOptions"},
 	}
 }
 
@@ -2873,11 +3645,32 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_MachineStatus(ref common.Reference
 							},
 						},
 					},
+					"conditionObservations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConditionObservations reports when the conditions derived from observations of the workload cluster, e.g. NodeHealthy or EtcdMemberHealthy, have been last refreshed. The map is keyed by condition type.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("sigs.k8s.io/cluster-api/api/v1beta1.ConditionObservation"),
+									},
+								},
+							},
+						},
+					},
+					"bootstrapProgress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BootstrapProgress reports the progress of the bootstrap process running on the machine, e.g. downloading binaries or running kubeadm, if reported by the bootstrap provider. It is removed once the Machine has a Node.",
+							Ref:         ref("sigs.k8s.io/cluster-api/api/v1beta1.MachineBootstrapProgress"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.NodeSystemInfo", "k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Time", "sigs.k8s.io/cluster-api/api/v1beta1.Condition", "sigs.k8s.io/cluster-api/api/v1beta1.MachineAddress"},
+			"k8s.io/api/core/v1.NodeSystemInfo", "k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Time", "sigs.k8s.io/cluster-api/api/v1beta1.Condition", "sigs.k8s.io/cluster-api/api/v1beta1.ConditionObservation", "sigs.k8s.io/cluster-api/api/v1beta1.MachineAddress", "sigs.k8s.io/cluster-api/api/v1beta1.MachineBootstrapProgress"},
 	}
 }
 
@@ -2938,6 +3731,63 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_NetworkRanges(ref common.Reference
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_NodeDrainOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeDrainOptions defines how a Node is drained. NOTE: DaemonSet-managed Pods and mirror Pods are never removed when draining a Node, given that they would be immediately re-created; Pods using emptyDir volumes are removed, and their local data is lost.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"gracePeriodSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GracePeriodSeconds, if set, overrides the termination grace period of the Pods removed from the Node; if not set, the termination grace period defined in each Pod is used.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"skipPodSelectors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SkipPodSelectors is a list of label selectors for Pods that must not be removed from the Node when draining, e.g. Pods which are expected to terminate together with the Node; a Pod is skipped if it matches any of them.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+									},
+								},
+							},
+						},
+					},
+					"force": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Force defines if Pods not managed by a controller, e.g. by a ReplicaSet or a StatefulSet, are removed from the Node; if false, the drain fails while such Pods exist on the Node. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"ignoreDaemonSets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IgnoreDaemonSets defines if DaemonSet-managed Pods are ignored when draining the Node; if false, the drain fails while such Pods exist on the Node, e.g. to make sure DaemonSets are removed from the Node beforehand. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"disableEviction": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DisableEviction, if true, deletes the Pods instead of evicting them, thus bypassing PodDisruptionBudgets.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_ObjectMeta(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3122,6 +3972,40 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_PatchSelectorMatchMachineDeploymen
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_ReplicasSummary(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ReplicasSummary reports aggregated replica counts.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"replicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Replicas is the total number of desired replicas.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"readyReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadyReplicas is the total number of ready replicas.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"unavailableReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UnavailableReplicas is the total number of unavailable replicas.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_Topology(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3223,6 +4107,36 @@ func schema_sigsk8sio_cluster_api_api_v1beta1_UnhealthyCondition(ref common.Refe
 	}
 }
 
+func schema_sigsk8sio_cluster_api_api_v1beta1_UnhealthyNodeExpression(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UnhealthyNodeExpression represents a CEL expression over a Node; when the expression evaluates to true, the node is considered unhealthy.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name identifies the expression, and it is reported in the MachineHealthCheckSucceeded condition of machines whose node is considered unhealthy because of the expression.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"expression": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Expression is a CEL expression which must evaluate to a bool. The following variables are available: \"node\", the Node object, and \"now\", the evaluation time as a timestamp; the \"quantity\" function parses resource quantities into numbers. Eg. \"quantity(node.status.allocatable['ephemeral-storage']) < quantity('10Gi')\" or \"node.status.conditions.exists(c, c.type == 'KernelDeadlock' && c.status == 'True' && now - timestamp(c.lastTransitionTime) > duration('5m'))\". Expressions failing to evaluate, e.g. because they access a field not set on the node, are ignored.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "expression"},
+			},
+		},
+	}
+}
+
 func schema_sigsk8sio_cluster_api_api_v1beta1_VariableSchema(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
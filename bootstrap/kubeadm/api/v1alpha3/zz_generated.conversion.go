@@ -54,16 +54,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*FileSource)(nil), (*v1beta1.FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha3_FileSource_To_v1beta1_FileSource(a.(*FileSource), b.(*v1beta1.FileSource), scope)
-	}); err != nil {
-		return err
-	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.FileSource)(nil), (*FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_FileSource_To_v1alpha3_FileSource(a.(*v1beta1.FileSource), b.(*FileSource), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Filesystem)(nil), (*v1beta1.Filesystem)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Filesystem_To_v1beta1_Filesystem(a.(*Filesystem), b.(*v1beta1.Filesystem), scope)
 	}); err != nil {
@@ -99,11 +89,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.KubeadmConfigStatus)(nil), (*KubeadmConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_KubeadmConfigStatus_To_v1alpha3_KubeadmConfigStatus(a.(*v1beta1.KubeadmConfigStatus), b.(*KubeadmConfigStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*KubeadmConfigTemplate)(nil), (*v1beta1.KubeadmConfigTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_KubeadmConfigTemplate_To_v1beta1_KubeadmConfigTemplate(a.(*KubeadmConfigTemplate), b.(*v1beta1.KubeadmConfigTemplate), scope)
 	}); err != nil {
@@ -189,6 +174,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*FileSource)(nil), (*v1beta1.FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_FileSource_To_v1beta1_FileSource(a.(*FileSource), b.(*v1beta1.FileSource), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*KubeadmConfigStatus)(nil), (*v1beta1.KubeadmConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_KubeadmConfigStatus_To_v1beta1_KubeadmConfigStatus(a.(*KubeadmConfigStatus), b.(*v1beta1.KubeadmConfigStatus), scope)
 	}); err != nil {
@@ -204,6 +194,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.FileSource)(nil), (*FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FileSource_To_v1alpha3_FileSource(a.(*v1beta1.FileSource), b.(*FileSource), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.InitConfiguration)(nil), (*upstreamv1beta1.InitConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_InitConfiguration_To_upstreamv1beta1_InitConfiguration(a.(*v1beta1.InitConfiguration), b.(*upstreamv1beta1.InitConfiguration), scope)
 	}); err != nil {
@@ -219,6 +214,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.KubeadmConfigStatus)(nil), (*KubeadmConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KubeadmConfigStatus_To_v1alpha3_KubeadmConfigStatus(a.(*v1beta1.KubeadmConfigStatus), b.(*KubeadmConfigStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.KubeadmConfigTemplateResource)(nil), (*KubeadmConfigTemplateResource)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_KubeadmConfigTemplateResource_To_v1alpha3_KubeadmConfigTemplateResource(a.(*v1beta1.KubeadmConfigTemplateResource), b.(*KubeadmConfigTemplateResource), scope)
 	}); err != nil {
@@ -260,7 +260,15 @@ func autoConvert_v1alpha3_File_To_v1beta1_File(in *File, out *v1beta1.File, s co
 	out.Permissions = in.Permissions
 	out.Encoding = v1beta1.Encoding(in.Encoding)
 	out.Content = in.Content
-	out.ContentFrom = (*v1beta1.FileSource)(unsafe.Pointer(in.ContentFrom))
+	if in.ContentFrom != nil {
+		in, out := &in.ContentFrom, &out.ContentFrom
+		*out = new(v1beta1.FileSource)
+		if err := Convert_v1alpha3_FileSource_To_v1beta1_FileSource(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ContentFrom = nil
+	}
 	return nil
 }
 
@@ -276,34 +284,30 @@ func autoConvert_v1beta1_File_To_v1alpha3_File(in *v1beta1.File, out *File, s co
 	out.Encoding = Encoding(in.Encoding)
 	// WARNING: in.Append requires manual conversion: does not exist in peer-type
 	out.Content = in.Content
-	out.ContentFrom = (*FileSource)(unsafe.Pointer(in.ContentFrom))
+	if in.ContentFrom != nil {
+		in, out := &in.ContentFrom, &out.ContentFrom
+		*out = new(FileSource)
+		if err := Convert_v1beta1_FileSource_To_v1alpha3_FileSource(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ContentFrom = nil
+	}
 	return nil
 }
 
 func autoConvert_v1alpha3_FileSource_To_v1beta1_FileSource(in *FileSource, out *v1beta1.FileSource, s conversion.Scope) error {
-	if err := Convert_v1alpha3_SecretFileSource_To_v1beta1_SecretFileSource(&in.Secret, &out.Secret, s); err != nil {
-		return err
-	}
+	// WARNING: in.Secret requires manual conversion: inconvertible types (sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha3.SecretFileSource vs *sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1.SecretFileSource)
 	return nil
 }
 
-// Convert_v1alpha3_FileSource_To_v1beta1_FileSource is an autogenerated conversion function.
-func Convert_v1alpha3_FileSource_To_v1beta1_FileSource(in *FileSource, out *v1beta1.FileSource, s conversion.Scope) error {
-	return autoConvert_v1alpha3_FileSource_To_v1beta1_FileSource(in, out, s)
-}
-
 func autoConvert_v1beta1_FileSource_To_v1alpha3_FileSource(in *v1beta1.FileSource, out *FileSource, s conversion.Scope) error {
-	if err := Convert_v1beta1_SecretFileSource_To_v1alpha3_SecretFileSource(&in.Secret, &out.Secret, s); err != nil {
-		return err
-	}
+	// WARNING: in.Secret requires manual conversion: inconvertible types (*sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1.SecretFileSource vs sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha3.SecretFileSource)
+	// WARNING: in.ConfigMap requires manual conversion: does not exist in peer-type
+	// WARNING: in.Template requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_FileSource_To_v1alpha3_FileSource is an autogenerated conversion function.
-func Convert_v1beta1_FileSource_To_v1alpha3_FileSource(in *v1beta1.FileSource, out *FileSource, s conversion.Scope) error {
-	return autoConvert_v1beta1_FileSource_To_v1alpha3_FileSource(in, out, s)
-}
-
 func autoConvert_v1alpha3_Filesystem_To_v1beta1_Filesystem(in *Filesystem, out *v1beta1.Filesystem, s conversion.Scope) error {
 	out.Device = in.Device
 	out.Filesystem = in.Filesystem
@@ -530,11 +534,15 @@ func autoConvert_v1beta1_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec(in *v1b
 	} else {
 		out.Users = nil
 	}
+	// WARNING: in.Groups requires manual conversion: does not exist in peer-type
 	out.NTP = (*NTP)(unsafe.Pointer(in.NTP))
 	out.Format = Format(in.Format)
+	// WARNING: in.OSFamily requires manual conversion: does not exist in peer-type
 	out.Verbosity = (*int32)(unsafe.Pointer(in.Verbosity))
 	out.UseExperimentalRetryJoin = in.UseExperimentalRetryJoin
 	// WARNING: in.Ignition requires manual conversion: does not exist in peer-type
+	// WARNING: in.KubeletCredentialProviders requires manual conversion: does not exist in peer-type
+	// WARNING: in.Snippets requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -576,14 +584,10 @@ func autoConvert_v1beta1_KubeadmConfigStatus_To_v1alpha3_KubeadmConfigStatus(in
 	} else {
 		out.Conditions = nil
 	}
+	// WARNING: in.BootstrapProgress requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_KubeadmConfigStatus_To_v1alpha3_KubeadmConfigStatus is an autogenerated conversion function.
-func Convert_v1beta1_KubeadmConfigStatus_To_v1alpha3_KubeadmConfigStatus(in *v1beta1.KubeadmConfigStatus, out *KubeadmConfigStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_KubeadmConfigStatus_To_v1alpha3_KubeadmConfigStatus(in, out, s)
-}
-
 func autoConvert_v1alpha3_KubeadmConfigTemplate_To_v1beta1_KubeadmConfigTemplate(in *KubeadmConfigTemplate, out *v1beta1.KubeadmConfigTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_KubeadmConfigTemplateSpec_To_v1beta1_KubeadmConfigTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -778,6 +782,8 @@ func autoConvert_v1alpha3_User_To_v1beta1_User(in *User, out *v1beta1.User, s co
 	out.LockPassword = (*bool)(unsafe.Pointer(in.LockPassword))
 	out.Sudo = (*string)(unsafe.Pointer(in.Sudo))
 	out.SSHAuthorizedKeys = *(*[]string)(unsafe.Pointer(&in.SSHAuthorizedKeys))
+	// WARNING: in.SSHAuthorizedKeysFrom requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExpireDate requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -799,5 +805,7 @@ func autoConvert_v1beta1_User_To_v1alpha3_User(in *v1beta1.User, out *User, s co
 	out.LockPassword = (*bool)(unsafe.Pointer(in.LockPassword))
 	out.Sudo = (*string)(unsafe.Pointer(in.Sudo))
 	out.SSHAuthorizedKeys = *(*[]string)(unsafe.Pointer(&in.SSHAuthorizedKeys))
+	// WARNING: in.SSHAuthorizedKeysFrom requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExpireDate requires manual conversion: does not exist in peer-type
 	return nil
 }
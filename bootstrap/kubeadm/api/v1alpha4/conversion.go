@@ -49,6 +49,11 @@ func (src *KubeadmConfig) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.Ignition = restored.Spec.Ignition
+	dst.Spec.OSFamily = restored.Spec.OSFamily
+	dst.Spec.KubeletCredentialProviders = restored.Spec.KubeletCredentialProviders
+	dst.Spec.Snippets = restored.Spec.Snippets
+	dst.Spec.Groups = restored.Spec.Groups
+	dst.Status.BootstrapProgress = restored.Status.BootstrapProgress
 	if restored.Spec.InitConfiguration != nil {
 		if dst.Spec.InitConfiguration == nil {
 			dst.Spec.InitConfiguration = &bootstrapv1.InitConfiguration{}
@@ -130,6 +135,10 @@ func (src *KubeadmConfigTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
 
 	dst.Spec.Template.Spec.Ignition = restored.Spec.Template.Spec.Ignition
+	dst.Spec.Template.Spec.OSFamily = restored.Spec.Template.Spec.OSFamily
+	dst.Spec.Template.Spec.KubeletCredentialProviders = restored.Spec.Template.Spec.KubeletCredentialProviders
+	dst.Spec.Template.Spec.Snippets = restored.Spec.Template.Spec.Snippets
+	dst.Spec.Template.Spec.Groups = restored.Spec.Template.Spec.Groups
 	if restored.Spec.Template.Spec.InitConfiguration != nil {
 		if dst.Spec.Template.Spec.InitConfiguration == nil {
 			dst.Spec.Template.Spec.InitConfiguration = &bootstrapv1.InitConfiguration{}
@@ -187,6 +196,10 @@ func (dst *KubeadmConfigTemplateList) ConvertFrom(srcRaw conversion.Hub) error {
 // Convert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec is an autogenerated conversion function.
 func Convert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec(in *bootstrapv1.KubeadmConfigSpec, out *KubeadmConfigSpec, s apiconversion.Scope) error {
 	// KubeadmConfigSpec.Ignition does not exist in kubeadm v1alpha4 API.
+	// KubeadmConfigSpec.OSFamily does not exist in kubeadm v1alpha4 API.
+	// KubeadmConfigSpec.KubeletCredentialProviders does not exist in kubeadm v1alpha4 API.
+	// KubeadmConfigSpec.Snippets does not exist in kubeadm v1alpha4 API.
+	// KubeadmConfigSpec.Groups does not exist in kubeadm v1alpha4 API.
 	return autoConvert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec(in, out, s)
 }
 
@@ -200,13 +213,33 @@ func Convert_v1beta1_JoinConfiguration_To_v1alpha4_JoinConfiguration(in *bootstr
 	return autoConvert_v1beta1_JoinConfiguration_To_v1alpha4_JoinConfiguration(in, out, s)
 }
 
+func Convert_v1beta1_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus(in *bootstrapv1.KubeadmConfigStatus, out *KubeadmConfigStatus, s apiconversion.Scope) error {
+	// KubeadmConfigStatus.BootstrapProgress does not exist in kubeadm v1alpha4 API.
+	return autoConvert_v1beta1_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus(in, out, s)
+}
+
 func Convert_v1beta1_File_To_v1alpha4_File(in *bootstrapv1.File, out *File, s apiconversion.Scope) error {
 	// File.Append does not exist in kubeadm v1alpha4 API.
 	return autoConvert_v1beta1_File_To_v1alpha4_File(in, out, s)
 }
 
+func Convert_v1alpha4_FileSource_To_v1beta1_FileSource(in *FileSource, out *bootstrapv1.FileSource, s apiconversion.Scope) error {
+	out.Secret = &bootstrapv1.SecretFileSource{}
+	return Convert_v1alpha4_SecretFileSource_To_v1beta1_SecretFileSource(&in.Secret, out.Secret, s)
+}
+
+func Convert_v1beta1_FileSource_To_v1alpha4_FileSource(in *bootstrapv1.FileSource, out *FileSource, s apiconversion.Scope) error {
+	// FileSource.ConfigMap and FileSource.Template do not exist in kubeadm v1alpha4 API.
+	if in.Secret == nil {
+		return nil
+	}
+	return Convert_v1beta1_SecretFileSource_To_v1alpha4_SecretFileSource(in.Secret, &out.Secret, s)
+}
+
 func Convert_v1beta1_User_To_v1alpha4_User(in *bootstrapv1.User, out *User, s apiconversion.Scope) error {
 	// User.PasswdFrom does not exist in kubeadm v1alpha4 API.
+	// User.SSHAuthorizedKeysFrom does not exist in kubeadm v1alpha4 API.
+	// User.ExpireDate does not exist in kubeadm v1alpha4 API.
 	return autoConvert_v1beta1_User_To_v1alpha4_User(in, out, s)
 }
 
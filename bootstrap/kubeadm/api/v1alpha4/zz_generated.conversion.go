@@ -185,16 +185,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*FileSource)(nil), (*v1beta1.FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_FileSource_To_v1beta1_FileSource(a.(*FileSource), b.(*v1beta1.FileSource), scope)
-	}); err != nil {
-		return err
-	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.FileSource)(nil), (*FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_FileSource_To_v1alpha4_FileSource(a.(*v1beta1.FileSource), b.(*FileSource), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Filesystem)(nil), (*v1beta1.Filesystem)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_Filesystem_To_v1beta1_Filesystem(a.(*Filesystem), b.(*v1beta1.Filesystem), scope)
 	}); err != nil {
@@ -275,11 +265,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.KubeadmConfigStatus)(nil), (*KubeadmConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus(a.(*v1beta1.KubeadmConfigStatus), b.(*KubeadmConfigStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*KubeadmConfigTemplate)(nil), (*v1beta1.KubeadmConfigTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_KubeadmConfigTemplate_To_v1beta1_KubeadmConfigTemplate(a.(*KubeadmConfigTemplate), b.(*v1beta1.KubeadmConfigTemplate), scope)
 	}); err != nil {
@@ -375,11 +360,21 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*FileSource)(nil), (*v1beta1.FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_FileSource_To_v1beta1_FileSource(a.(*FileSource), b.(*v1beta1.FileSource), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.File)(nil), (*File)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_File_To_v1alpha4_File(a.(*v1beta1.File), b.(*File), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.FileSource)(nil), (*FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FileSource_To_v1alpha4_FileSource(a.(*v1beta1.FileSource), b.(*FileSource), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.InitConfiguration)(nil), (*InitConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_InitConfiguration_To_v1alpha4_InitConfiguration(a.(*v1beta1.InitConfiguration), b.(*InitConfiguration), scope)
 	}); err != nil {
@@ -395,6 +390,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.KubeadmConfigStatus)(nil), (*KubeadmConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus(a.(*v1beta1.KubeadmConfigStatus), b.(*KubeadmConfigStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.KubeadmConfigTemplateResource)(nil), (*KubeadmConfigTemplateResource)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_KubeadmConfigTemplateResource_To_v1alpha4_KubeadmConfigTemplateResource(a.(*v1beta1.KubeadmConfigTemplateResource), b.(*KubeadmConfigTemplateResource), scope)
 	}); err != nil {
@@ -775,7 +775,15 @@ func autoConvert_v1alpha4_File_To_v1beta1_File(in *File, out *v1beta1.File, s co
 	out.Permissions = in.Permissions
 	out.Encoding = v1beta1.Encoding(in.Encoding)
 	out.Content = in.Content
-	out.ContentFrom = (*v1beta1.FileSource)(unsafe.Pointer(in.ContentFrom))
+	if in.ContentFrom != nil {
+		in, out := &in.ContentFrom, &out.ContentFrom
+		*out = new(v1beta1.FileSource)
+		if err := Convert_v1alpha4_FileSource_To_v1beta1_FileSource(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ContentFrom = nil
+	}
 	return nil
 }
 
@@ -791,7 +799,15 @@ func autoConvert_v1beta1_File_To_v1alpha4_File(in *v1beta1.File, out *File, s co
 	out.Encoding = Encoding(in.Encoding)
 	// WARNING: in.Append requires manual conversion: does not exist in peer-type
 	out.Content = in.Content
-	out.ContentFrom = (*FileSource)(unsafe.Pointer(in.ContentFrom))
+	if in.ContentFrom != nil {
+		in, out := &in.ContentFrom, &out.ContentFrom
+		*out = new(FileSource)
+		if err := Convert_v1beta1_FileSource_To_v1alpha4_FileSource(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ContentFrom = nil
+	}
 	return nil
 }
 
@@ -816,29 +832,17 @@ func Convert_v1beta1_FileDiscovery_To_v1alpha4_FileDiscovery(in *v1beta1.FileDis
 }
 
 func autoConvert_v1alpha4_FileSource_To_v1beta1_FileSource(in *FileSource, out *v1beta1.FileSource, s conversion.Scope) error {
-	if err := Convert_v1alpha4_SecretFileSource_To_v1beta1_SecretFileSource(&in.Secret, &out.Secret, s); err != nil {
-		return err
-	}
+	// WARNING: in.Secret requires manual conversion: inconvertible types (sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4.SecretFileSource vs *sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1.SecretFileSource)
 	return nil
 }
 
-// Convert_v1alpha4_FileSource_To_v1beta1_FileSource is an autogenerated conversion function.
-func Convert_v1alpha4_FileSource_To_v1beta1_FileSource(in *FileSource, out *v1beta1.FileSource, s conversion.Scope) error {
-	return autoConvert_v1alpha4_FileSource_To_v1beta1_FileSource(in, out, s)
-}
-
 func autoConvert_v1beta1_FileSource_To_v1alpha4_FileSource(in *v1beta1.FileSource, out *FileSource, s conversion.Scope) error {
-	if err := Convert_v1beta1_SecretFileSource_To_v1alpha4_SecretFileSource(&in.Secret, &out.Secret, s); err != nil {
-		return err
-	}
+	// WARNING: in.Secret requires manual conversion: inconvertible types (*sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1.SecretFileSource vs sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4.SecretFileSource)
+	// WARNING: in.ConfigMap requires manual conversion: does not exist in peer-type
+	// WARNING: in.Template requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_FileSource_To_v1alpha4_FileSource is an autogenerated conversion function.
-func Convert_v1beta1_FileSource_To_v1alpha4_FileSource(in *v1beta1.FileSource, out *FileSource, s conversion.Scope) error {
-	return autoConvert_v1beta1_FileSource_To_v1alpha4_FileSource(in, out, s)
-}
-
 func autoConvert_v1alpha4_Filesystem_To_v1beta1_Filesystem(in *Filesystem, out *v1beta1.Filesystem, s conversion.Scope) error {
 	out.Device = in.Device
 	out.Filesystem = in.Filesystem
@@ -1183,11 +1187,15 @@ func autoConvert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec(in *v1b
 	} else {
 		out.Users = nil
 	}
+	// WARNING: in.Groups requires manual conversion: does not exist in peer-type
 	out.NTP = (*NTP)(unsafe.Pointer(in.NTP))
 	out.Format = Format(in.Format)
+	// WARNING: in.OSFamily requires manual conversion: does not exist in peer-type
 	out.Verbosity = (*int32)(unsafe.Pointer(in.Verbosity))
 	out.UseExperimentalRetryJoin = in.UseExperimentalRetryJoin
 	// WARNING: in.Ignition requires manual conversion: does not exist in peer-type
+	// WARNING: in.KubeletCredentialProviders requires manual conversion: does not exist in peer-type
+	// WARNING: in.Snippets requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1233,14 +1241,10 @@ func autoConvert_v1beta1_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus(in
 	} else {
 		out.Conditions = nil
 	}
+	// WARNING: in.BootstrapProgress requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus is an autogenerated conversion function.
-func Convert_v1beta1_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus(in *v1beta1.KubeadmConfigStatus, out *KubeadmConfigStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_KubeadmConfigStatus_To_v1alpha4_KubeadmConfigStatus(in, out, s)
-}
-
 func autoConvert_v1alpha4_KubeadmConfigTemplate_To_v1beta1_KubeadmConfigTemplate(in *KubeadmConfigTemplate, out *v1beta1.KubeadmConfigTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha4_KubeadmConfigTemplateSpec_To_v1beta1_KubeadmConfigTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1515,6 +1519,8 @@ func autoConvert_v1alpha4_User_To_v1beta1_User(in *User, out *v1beta1.User, s co
 	out.LockPassword = (*bool)(unsafe.Pointer(in.LockPassword))
 	out.Sudo = (*string)(unsafe.Pointer(in.Sudo))
 	out.SSHAuthorizedKeys = *(*[]string)(unsafe.Pointer(&in.SSHAuthorizedKeys))
+	// WARNING: in.SSHAuthorizedKeysFrom requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExpireDate requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1536,5 +1542,7 @@ func autoConvert_v1beta1_User_To_v1alpha4_User(in *v1beta1.User, out *User, s co
 	out.LockPassword = (*bool)(unsafe.Pointer(in.LockPassword))
 	out.Sudo = (*string)(unsafe.Pointer(in.Sudo))
 	out.SSHAuthorizedKeys = *(*[]string)(unsafe.Pointer(&in.SSHAuthorizedKeys))
+	// WARNING: in.SSHAuthorizedKeysFrom requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExpireDate requires manual conversion: does not exist in peer-type
 	return nil
 }
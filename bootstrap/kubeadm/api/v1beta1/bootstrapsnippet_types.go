@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BootstrapSnippetSpec defines the desired state of BootstrapSnippet.
+type BootstrapSnippetSpec struct {
+	// Files specifies extra files to be passed to user_data upon creation.
+	// +optional
+	Files []File `json:"files,omitempty"`
+
+	// PreKubeadmCommands specifies extra commands to run before kubeadm runs.
+	// +optional
+	PreKubeadmCommands []string `json:"preKubeadmCommands,omitempty"`
+
+	// PostKubeadmCommands specifies extra commands to run after kubeadm runs.
+	// +optional
+	PostKubeadmCommands []string `json:"postKubeadmCommands,omitempty"`
+}
+
+// BootstrapSnippetReference is a reference to a BootstrapSnippet in the same namespace.
+type BootstrapSnippetReference struct {
+	// Name of the BootstrapSnippet.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=bootstrapsnippets,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of BootstrapSnippet"
+
+// BootstrapSnippet is the Schema for the bootstrapsnippets API.
+// A BootstrapSnippet contains files and commands that KubeadmConfigs and KubeadmConfigTemplates
+// in the same namespace can reference and compose, so common node preparation logic is maintained in a single place.
+type BootstrapSnippet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BootstrapSnippetSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BootstrapSnippetList contains a list of BootstrapSnippet.
+type BootstrapSnippetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BootstrapSnippet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BootstrapSnippet{}, &BootstrapSnippetList{})
+}
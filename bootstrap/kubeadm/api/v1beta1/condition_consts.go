@@ -35,6 +35,22 @@ const (
 	// the KubeadmConfig controller ensure this pre-condition is satisfied.
 	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
 
+	// WaitingForInfrastructureAddressesReason (Severity=Info) documents a bootstrap secret generation process
+	// waiting for the infrastructure provider to report the Machine addresses.
+	//
+	// NOTE: This applies only to KubeadmConfigs with the WaitForInfrastructureAddressesAnnotation.
+	WaitingForInfrastructureAddressesReason = "WaitingForInfrastructureAddresses"
+
+	// WaitingForAttestationReason (Severity=Info) documents a bootstrap secret generation process waiting for
+	// the attestation of the host identity to be verified.
+	//
+	// NOTE: This applies only if the KubeadmConfig controller is configured with an attestation verifier.
+	WaitingForAttestationReason = "WaitingForAttestation"
+
+	// AttestationFailedReason (Severity=Warning) documents a KubeadmConfig controller detecting an error while
+	// verifying the attestation of the host identity.
+	AttestationFailedReason = "AttestationFailed"
+
 	// DataSecretGenerationFailedReason (Severity=Warning) documents a KubeadmConfig controller detecting
 	// an error while generating a data secret; those kind of errors are usually due to misconfigurations
 	// and user intervention is required to get them fixed.
@@ -59,3 +75,59 @@ const (
 	// an error while retrieving certificates for a joining node.
 	CertificatesCorruptedReason = "CertificatesCorrupted"
 )
+
+const (
+	// FileSourcesUpToDateCondition documents that the content of the files populated from Secrets or ConfigMaps
+	// has not changed since the bootstrap data has been generated.
+	//
+	// NOTE: This condition exists only for KubeadmConfigs with files populated from Secrets or ConfigMaps; when it is
+	// false, the Machine must be replaced to pick up the changes; KubeadmControlPlane does so automatically, and a rollout
+	// is requested for the MachineDeployment the Machine belongs to, if any.
+	FileSourcesUpToDateCondition clusterv1.ConditionType = "FileSourcesUpToDate"
+
+	// FileSourcesChangedReason (Severity=Warning) documents a KubeadmConfig whose files are populated from
+	// Secrets or ConfigMaps that changed after the bootstrap data has been generated.
+	FileSourcesChangedReason = "FileSourcesChanged"
+
+	// FileSourcesUnavailableReason (Severity=Warning) documents a KubeadmConfig whose files are populated from
+	// Secrets or ConfigMaps that can't be read anymore, e.g. because they have been deleted.
+	FileSourcesUnavailableReason = "FileSourcesUnavailable"
+)
+
+const (
+	// UserSourcesUpToDateCondition documents that the content of the passwords and of the ssh authorized keys
+	// populated from Secrets has not changed since the bootstrap data has been generated.
+	//
+	// NOTE: This condition exists only for KubeadmConfigs with users populated from Secrets; when it is
+	// false, the Machine must be replaced to pick up the changes; KubeadmControlPlane does so automatically, and a rollout
+	// is requested for the MachineDeployment the Machine belongs to, if any.
+	UserSourcesUpToDateCondition clusterv1.ConditionType = "UserSourcesUpToDate"
+
+	// UserSourcesChangedReason (Severity=Warning) documents a KubeadmConfig whose users are populated from
+	// Secrets that changed after the bootstrap data has been generated, e.g. because ssh keys have been rotated.
+	UserSourcesChangedReason = "UserSourcesChanged"
+
+	// UserSourcesUnavailableReason (Severity=Warning) documents a KubeadmConfig whose users are populated from
+	// Secrets that can't be read anymore, e.g. because they have been deleted.
+	UserSourcesUnavailableReason = "UserSourcesUnavailable"
+)
+
+const (
+	// BootstrapHeartbeatCondition documents that the machine is reporting the progress of the bootstrap process.
+	//
+	// NOTE: This condition exists only for KubeadmConfigs with the ReportBootstrapProgressAnnotation, until the
+	// machine has a Node.
+	BootstrapHeartbeatCondition clusterv1.ConditionType = "BootstrapHeartbeat"
+
+	// WaitingForBootstrapHeartbeatReason (Severity=Info) documents a KubeadmConfig waiting for the machine to report
+	// the progress of the bootstrap process for the first time, e.g. because the machine is still being provisioned.
+	WaitingForBootstrapHeartbeatReason = "WaitingForBootstrapHeartbeat"
+
+	// BootstrapHeartbeatStaleReason (Severity=Warning) documents a KubeadmConfig whose machine stopped reporting the
+	// progress of the bootstrap process before completing it, e.g. because the bootstrap process failed or the machine
+	// is not running anymore.
+	BootstrapHeartbeatStaleReason = "BootstrapHeartbeatStale"
+
+	// BootstrapFailedReason (Severity=Error) documents a KubeadmConfig whose machine reported that kubeadm failed.
+	BootstrapFailedReason = "BootstrapFailed"
+)
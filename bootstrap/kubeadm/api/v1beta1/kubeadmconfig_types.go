@@ -34,6 +34,85 @@ const (
 	Ignition Format = "ignition"
 )
 
+// OSFamily specifies the operating system family of the machine the bootstrap data is generated for.
+// +kubebuilder:validation:Enum=linux;windows
+type OSFamily string
+
+const (
+	// LinuxOSFamily makes the bootstrap data to be generated for Linux machines.
+	LinuxOSFamily OSFamily = "linux"
+
+	// WindowsOSFamily makes the bootstrap data to be generated for Windows machines, as a PowerShell
+	// script compatible with cloudbase-init.
+	WindowsOSFamily OSFamily = "windows"
+)
+
+const (
+	// WaitForInfrastructureAddressesAnnotation can be set on a KubeadmConfig to defer the generation of the bootstrap data
+	// until the infrastructure provider reports the Machine addresses. The first InternalIP is then used as a default for
+	// the kubelet node-ip and, for control plane nodes, for the API server advertise address.
+	// NOTE: This requires an infrastructure provider reporting status.addresses before the bootstrap data is available.
+	WaitForInfrastructureAddressesAnnotation = "bootstrap.cluster.x-k8s.io/wait-for-infrastructure-addresses"
+
+	// FileSourcesChecksumAnnotation is set on a KubeadmConfig with the checksum of the content of the files
+	// populated from Secrets or ConfigMaps when the bootstrap data has been generated; it is used to detect
+	// changes to the referenced content afterwards.
+	FileSourcesChecksumAnnotation = "bootstrap.cluster.x-k8s.io/file-sources-checksum"
+
+	// UserSourcesChecksumAnnotation is set on a KubeadmConfig with the checksum of the content of the passwords
+	// and the SSH authorized keys populated from Secrets when the bootstrap data has been generated; it is used
+	// to detect changes to the referenced content afterwards, e.g. when rotating SSH keys.
+	UserSourcesChecksumAnnotation = "bootstrap.cluster.x-k8s.io/user-sources-checksum"
+
+	// ReportBootstrapProgressAnnotation can be set on a KubeadmConfig to make the bootstrap data report the progress of
+	// the bootstrap process running on the machine, which is then surfaced in status.bootstrapProgress.
+	// NOTE: This applies only to machines joining an existing cluster, and not to MachinePools; machines report their
+	// progress to the API server of the workload cluster, so they must be able to reach it using curl.
+	ReportBootstrapProgressAnnotation = "bootstrap.cluster.x-k8s.io/report-bootstrap-progress"
+
+	// WaitForJoinPermitAnnotation can be set on a KubeadmConfig together with the ReportBootstrapProgressAnnotation to make
+	// the bootstrap data wait, before running kubeadm join, until the join is permitted by setting JoinPermittedBootstrapProgressKey
+	// to "true" in the ConfigMap where the machine reports the progress of the bootstrap process; this allows e.g. to provision
+	// the infrastructure of multiple machines in parallel while serializing their joins.
+	WaitForJoinPermitAnnotation = "bootstrap.cluster.x-k8s.io/wait-for-join-permit"
+)
+
+const (
+	// BootstrapProgressConfigMapNamePrefix is the prefix of the name of the ConfigMaps in the kube-system namespace of the
+	// workload cluster where machines report the progress of the bootstrap process; the name of the ConfigMap is completed
+	// with the name of the KubeadmConfig.
+	BootstrapProgressConfigMapNamePrefix = "cluster-api-bootstrap-progress-"
+
+	// JoinPermittedBootstrapProgressKey is the key of the ConfigMap where the machine reports the progress of the bootstrap
+	// process used to permit the join of a machine bootstrapped with a KubeadmConfig with the WaitForJoinPermitAnnotation.
+	JoinPermittedBootstrapProgressKey = "joinPermitted"
+)
+
+// Phases of the bootstrap process reported by machines bootstrapped with a KubeadmConfig with the
+// ReportBootstrapProgressAnnotation.
+const (
+	// RunningPreKubeadmCommandsBootstrapPhase documents a machine running the pre kubeadm commands, e.g.
+	// downloading binaries.
+	RunningPreKubeadmCommandsBootstrapPhase = "RunningPreKubeadmCommands"
+
+	// WaitingForJoinPermitBootstrapPhase documents a machine bootstrapped with a KubeadmConfig with the WaitForJoinPermitAnnotation
+	// which completed the pre kubeadm commands, waiting for the join to be permitted.
+	WaitingForJoinPermitBootstrapPhase = "WaitingForJoinPermit"
+
+	// RunningKubeadmBootstrapPhase documents a machine running kubeadm join.
+	RunningKubeadmBootstrapPhase = "RunningKubeadm"
+
+	// RunningPostKubeadmCommandsBootstrapPhase documents a machine running the post kubeadm commands.
+	RunningPostKubeadmCommandsBootstrapPhase = "RunningPostKubeadmCommands"
+
+	// WaitingForNodeBootstrapPhase documents a machine which completed the bootstrap process, waiting for the
+	// Machine to be linked to its Node.
+	WaitingForNodeBootstrapPhase = "WaitingForNode"
+
+	// KubeadmFailedBootstrapPhase documents a machine where kubeadm join failed.
+	KubeadmFailedBootstrapPhase = "KubeadmFailed"
+)
+
 // KubeadmConfigSpec defines the desired state of KubeadmConfig.
 // Either ClusterConfiguration and InitConfiguration should be defined or the JoinConfiguration should be defined.
 type KubeadmConfigSpec struct {
@@ -73,6 +152,10 @@ type KubeadmConfigSpec struct {
 	// +optional
 	Users []User `json:"users,omitempty"`
 
+	// Groups specifies extra groups to add
+	// +optional
+	Groups []Group `json:"groups,omitempty"`
+
 	// NTP specifies NTP configuration
 	// +optional
 	NTP *NTP `json:"ntp,omitempty"`
@@ -81,6 +164,14 @@ type KubeadmConfigSpec struct {
 	// +optional
 	Format Format `json:"format,omitempty"`
 
+	// OSFamily specifies the operating system family of the machine the bootstrap data is generated for.
+	// When set to windows, the bootstrap data is a PowerShell script compatible with cloudbase-init;
+	// only worker nodes joining the cluster are supported in this case, and users, groups, ntp,
+	// kubeletCredentialProviders and useExperimentalRetryJoin must not be set.
+	// Defaults to linux if not set.
+	// +optional
+	OSFamily OSFamily `json:"osFamily,omitempty"`
+
 	// Verbosity is the number for the kubeadm log level verbosity.
 	// It overrides the `--v` flag in kubeadm commands.
 	// +optional
@@ -105,6 +196,19 @@ type KubeadmConfigSpec struct {
 	// Ignition contains Ignition specific configuration.
 	// +optional
 	Ignition *IgnitionSpec `json:"ignition,omitempty"`
+
+	// KubeletCredentialProviders configures the kubelet image credential providers, used by the kubelet
+	// for dynamically retrieving credentials for pulling images from private registries.
+	// The CredentialProviderConfig file and the provider binaries are installed during bootstrap, and
+	// the corresponding kubelet flags are added to the nodeRegistration of the init and join configurations.
+	// +optional
+	KubeletCredentialProviders *KubeletCredentialProviders `json:"kubeletCredentialProviders,omitempty"`
+
+	// Snippets is an ordered list of references to BootstrapSnippets in the same namespace.
+	// The files and commands of the snippets are composed in order, and they are added before
+	// the Files, PreKubeadmCommands and PostKubeadmCommands defined in this spec.
+	// +optional
+	Snippets []BootstrapSnippetReference `json:"snippets,omitempty"`
 }
 
 // IgnitionSpec contains Ignition specific configuration.
@@ -130,6 +234,79 @@ type ContainerLinuxConfig struct {
 	Strict bool `json:"strict,omitempty"`
 }
 
+// KubeletCredentialProviders configures the kubelet image credential providers.
+type KubeletCredentialProviders struct {
+	// BinDir is the directory on the node where the credential provider binaries are installed.
+	// Defaults to /etc/kubernetes/credential-providers.
+	// +optional
+	BinDir string `json:"binDir,omitempty"`
+
+	// ConfigPath is the path on the node of the CredentialProviderConfig file.
+	// Defaults to /etc/kubernetes/credential-provider-config.yaml.
+	// +optional
+	ConfigPath string `json:"configPath,omitempty"`
+
+	// Providers is the list of credential providers to be used by the kubelet.
+	// +kubebuilder:validation:MinItems=1
+	Providers []KubeletCredentialProvider `json:"providers"`
+}
+
+// KubeletCredentialProvider defines a kubelet image credential provider.
+type KubeletCredentialProvider struct {
+	// Name is the name of the credential provider; it must match the name of the provider binary.
+	Name string `json:"name"`
+
+	// MatchImages is the list of image patterns the credential provider is invoked for,
+	// e.g. "*.dkr.ecr.*.amazonaws.com" or "registry.example.com:5000/*".
+	// +kubebuilder:validation:MinItems=1
+	MatchImages []string `json:"matchImages"`
+
+	// DefaultCacheDuration is the duration the kubelet caches credentials for, if the provider
+	// does not return a cache duration.
+	// Defaults to 5m.
+	// +optional
+	DefaultCacheDuration *metav1.Duration `json:"defaultCacheDuration,omitempty"`
+
+	// APIVersion is the version of the CredentialProviderRequest API supported by the provider,
+	// e.g. credentialprovider.kubelet.k8s.io/v1.
+	// Defaults to the latest version supported by the Kubernetes version of the machine.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Args are the arguments passed to the provider binary.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env are the environment variables set when invoking the provider binary.
+	// +optional
+	Env []KubeletCredentialProviderEnvVar `json:"env,omitempty"`
+
+	// Binary defines where the provider binary is downloaded from during bootstrap.
+	// If not set, the binary is expected to be already available in BinDir, e.g. in the machine image.
+	// +optional
+	Binary *KubeletCredentialProviderBinary `json:"binary,omitempty"`
+}
+
+// KubeletCredentialProviderEnvVar is an environment variable set when invoking a credential provider.
+type KubeletCredentialProviderEnvVar struct {
+	// Name is the name of the environment variable.
+	Name string `json:"name"`
+
+	// Value is the value of the environment variable.
+	Value string `json:"value"`
+}
+
+// KubeletCredentialProviderBinary defines where a credential provider binary is downloaded from.
+type KubeletCredentialProviderBinary struct {
+	// URL is the URL the provider binary is downloaded from.
+	URL string `json:"url"`
+
+	// SHA256 is the hex encoded SHA-256 checksum of the provider binary; bootstrap fails if the checksum
+	// of the downloaded binary does not match.
+	// +kubebuilder:validation:Pattern=`^[a-fA-F0-9]{64}$`
+	SHA256 string `json:"sha256"`
+}
+
 // KubeadmConfigStatus defines the observed state of KubeadmConfig.
 type KubeadmConfigStatus struct {
 	// Ready indicates the BootstrapData field is ready to be consumed
@@ -155,6 +332,11 @@ type KubeadmConfigStatus struct {
 	// Conditions defines current service state of the KubeadmConfig.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// BootstrapProgress reports the progress of the bootstrap process running on the machine, if the KubeadmConfig
+	// has the bootstrap.cluster.x-k8s.io/report-bootstrap-progress annotation. It is removed once the machine has a Node.
+	// +optional
+	BootstrapProgress *clusterv1.MachineBootstrapProgress `json:"bootstrapProgress,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -240,11 +422,22 @@ type File struct {
 }
 
 // FileSource is a union of all possible external source types for file data.
-// Only one field may be populated in any given instance. Developers adding new
+// Only one of Secret or ConfigMap may be populated in any given instance. Developers adding new
 // sources of data for target systems should add them here.
 type FileSource struct {
 	// Secret represents a secret that should populate this file.
-	Secret SecretFileSource `json:"secret"`
+	// +optional
+	Secret *SecretFileSource `json:"secret,omitempty"`
+
+	// ConfigMap represents a ConfigMap that should populate this file.
+	// +optional
+	ConfigMap *ConfigMapFileSource `json:"configMap,omitempty"`
+
+	// Template specifies whether the referenced content is a Go template to be rendered before populating the file.
+	// The template can reference .ClusterName, .Namespace and .Data, a map with all the keys of the referenced
+	// Secret or ConfigMap, e.g. {{ index .Data "ca.crt" }}.
+	// +optional
+	Template bool `json:"template,omitempty"`
 }
 
 // SecretFileSource adapts a Secret into a FileSource.
@@ -259,6 +452,15 @@ type SecretFileSource struct {
 	Key string `json:"key"`
 }
 
+// ConfigMapFileSource adapts a ConfigMap into a FileSource.
+type ConfigMapFileSource struct {
+	// Name of the ConfigMap in the KubeadmBootstrapConfig's namespace to use.
+	Name string `json:"name"`
+
+	// Key is the key in the ConfigMap's data or binaryData map for this value.
+	Key string `json:"key"`
+}
+
 // PasswdSource is a union of all possible external source types for passwd data.
 // Only one field may be populated in any given instance. Developers adding new
 // sources of data for target systems should add them here.
@@ -327,6 +529,45 @@ type User struct {
 	// SSHAuthorizedKeys specifies a list of ssh authorized keys for the user
 	// +optional
 	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+
+	// SSHAuthorizedKeysFrom is a referenced source of ssh authorized keys for the user, one key per line;
+	// empty lines and lines starting with # are ignored.
+	// The keys are added to the ones specified in SSHAuthorizedKeys.
+	// +optional
+	SSHAuthorizedKeysFrom *SSHAuthorizedKeysSource `json:"sshAuthorizedKeysFrom,omitempty"`
+
+	// ExpireDate specifies the date on which the user account will be disabled, in the YYYY-MM-DD format.
+	// NOTE: This field is not supported when using the Ignition format.
+	// +optional
+	ExpireDate *string `json:"expireDate,omitempty"`
+}
+
+// SSHAuthorizedKeysSource is a union of all possible external source types for ssh authorized keys.
+// Only one field may be populated in any given instance. Developers adding new
+// sources of data for target systems should add them here.
+type SSHAuthorizedKeysSource struct {
+	// Secret represents a secret that should populate the ssh authorized keys.
+	Secret SecretSSHAuthorizedKeysSource `json:"secret"`
+}
+
+// SecretSSHAuthorizedKeysSource adapts a Secret into a SSHAuthorizedKeysSource.
+type SecretSSHAuthorizedKeysSource struct {
+	// Name of the secret in the KubeadmBootstrapConfig's namespace to use.
+	Name string `json:"name"`
+
+	// Key is the key in the secret's data map for this value.
+	Key string `json:"key"`
+}
+
+// Group defines the input for a generated group in cloud-init.
+type Group struct {
+	// Name specifies the group name
+	Name string `json:"name"`
+
+	// Members specifies the existing users to add to the group
+	// NOTE: This field is not supported when using the Ignition format; use the groups field of the users instead.
+	// +optional
+	Members []string `json:"members,omitempty"`
 }
 
 // NTP defines input for generated ntp in cloud-init.
@@ -18,6 +18,9 @@ package v1beta1
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -30,9 +33,16 @@ import (
 
 var (
 	cannotUseWithIgnition                            = fmt.Sprintf("not supported when spec.format is set to %q", Ignition)
+	cannotUseWithWindows                             = fmt.Sprintf("not supported when spec.osFamily is set to %q", WindowsOSFamily)
 	conflictingFileSourceMsg                         = "only one of content or contentFrom may be specified for a single file"
+	conflictingFileContentSourceMsg                  = "only one of secret or configMap may be specified for a single file source"
 	conflictingUserSourceMsg                         = "only one of passwd or passwdFrom may be specified for a single user"
+	groupConflictMsg                                 = "name property must be unique among all groups"
+	invalidExpireDateMsg                             = "must be a date in the YYYY-MM-DD format"
 	kubeadmBootstrapFormatIgnitionFeatureDisabledMsg = "can be set only if the KubeadmBootstrapFormatIgnition feature gate is enabled"
+	missingConfigMapNameMsg                          = "configMap file source must specify non-empty configMap name"
+	missingConfigMapKeyMsg                           = "configMap file source must specify non-empty configMap key"
+	missingFileContentSourceMsg                      = "one of secret or configMap must be specified for a single file source"
 	missingSecretNameMsg                             = "secret file source must specify non-empty secret name"
 	missingSecretKeyMsg                              = "secret file source must specify non-empty secret key"
 	pathConflictMsg                                  = "path property must be unique among all files"
@@ -102,6 +112,8 @@ func (c *KubeadmConfigSpec) Validate(pathPrefix *field.Path) field.ErrorList {
 	allErrs = append(allErrs, c.validateFiles(pathPrefix)...)
 	allErrs = append(allErrs, c.validateUsers(pathPrefix)...)
 	allErrs = append(allErrs, c.validateIgnition(pathPrefix)...)
+	allErrs = append(allErrs, c.validateWindows(pathPrefix)...)
+	allErrs = append(allErrs, c.validateKubeletCredentialProviders(pathPrefix)...)
 
 	return allErrs
 }
@@ -123,25 +135,65 @@ func (c *KubeadmConfigSpec) validateFiles(pathPrefix *field.Path) field.ErrorLis
 				),
 			)
 		}
-		// n.b.: if we ever add types besides Secret as a ContentFrom
-		// Source, we must add webhook validation here for one of the
+		// n.b.: if we ever add types besides Secret and ConfigMap as a ContentFrom
+		// Source, we must add webhook validation here for exactly one of the
 		// sources being non-nil.
 		if file.ContentFrom != nil {
-			if file.ContentFrom.Secret.Name == "" {
+			contentFromPath := pathPrefix.Child("files").Index(i).Child("contentFrom")
+			switch {
+			case file.ContentFrom.Secret != nil && file.ContentFrom.ConfigMap != nil:
 				allErrs = append(
 					allErrs,
-					field.Required(
-						pathPrefix.Child("files").Index(i).Child("contentFrom", "secret", "name"),
-						missingSecretNameMsg,
+					field.Invalid(
+						contentFromPath,
+						file.ContentFrom,
+						conflictingFileContentSourceMsg,
 					),
 				)
-			}
-			if file.ContentFrom.Secret.Key == "" {
+			case file.ContentFrom.Secret != nil:
+				if file.ContentFrom.Secret.Name == "" {
+					allErrs = append(
+						allErrs,
+						field.Required(
+							contentFromPath.Child("secret", "name"),
+							missingSecretNameMsg,
+						),
+					)
+				}
+				if file.ContentFrom.Secret.Key == "" {
+					allErrs = append(
+						allErrs,
+						field.Required(
+							contentFromPath.Child("secret", "key"),
+							missingSecretKeyMsg,
+						),
+					)
+				}
+			case file.ContentFrom.ConfigMap != nil:
+				if file.ContentFrom.ConfigMap.Name == "" {
+					allErrs = append(
+						allErrs,
+						field.Required(
+							contentFromPath.Child("configMap", "name"),
+							missingConfigMapNameMsg,
+						),
+					)
+				}
+				if file.ContentFrom.ConfigMap.Key == "" {
+					allErrs = append(
+						allErrs,
+						field.Required(
+							contentFromPath.Child("configMap", "key"),
+							missingConfigMapKeyMsg,
+						),
+					)
+				}
+			default:
 				allErrs = append(
 					allErrs,
 					field.Required(
-						pathPrefix.Child("files").Index(i).Child("contentFrom", "secret", "key"),
-						missingSecretKeyMsg,
+						contentFromPath,
+						missingFileContentSourceMsg,
 					),
 				)
 			}
@@ -201,6 +253,53 @@ func (c *KubeadmConfigSpec) validateUsers(pathPrefix *field.Path) field.ErrorLis
 				)
 			}
 		}
+		if user.SSHAuthorizedKeysFrom != nil {
+			if user.SSHAuthorizedKeysFrom.Secret.Name == "" {
+				allErrs = append(
+					allErrs,
+					field.Required(
+						pathPrefix.Child("users").Index(i).Child("sshAuthorizedKeysFrom", "secret", "name"),
+						missingSecretNameMsg,
+					),
+				)
+			}
+			if user.SSHAuthorizedKeysFrom.Secret.Key == "" {
+				allErrs = append(
+					allErrs,
+					field.Required(
+						pathPrefix.Child("users").Index(i).Child("sshAuthorizedKeysFrom", "secret", "key"),
+						missingSecretKeyMsg,
+					),
+				)
+			}
+		}
+		if user.ExpireDate != nil {
+			if _, err := time.Parse("2006-01-02", *user.ExpireDate); err != nil {
+				allErrs = append(
+					allErrs,
+					field.Invalid(
+						pathPrefix.Child("users").Index(i).Child("expireDate"),
+						*user.ExpireDate,
+						invalidExpireDateMsg,
+					),
+				)
+			}
+		}
+	}
+
+	knownGroups := map[string]struct{}{}
+	for i, group := range c.Groups {
+		if _, conflict := knownGroups[group.Name]; conflict {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					pathPrefix.Child("groups").Index(i).Child("name"),
+					group.Name,
+					groupConflictMsg,
+				),
+			)
+		}
+		knownGroups[group.Name] = struct{}{}
 	}
 
 	return allErrs
@@ -248,6 +347,27 @@ func (c *KubeadmConfigSpec) validateIgnition(pathPrefix *field.Path) field.Error
 				),
 			)
 		}
+		if user.ExpireDate != nil {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(
+					pathPrefix.Child("users").Index(i).Child("expireDate"),
+					cannotUseWithIgnition,
+				),
+			)
+		}
+	}
+
+	for i, group := range c.Groups {
+		if len(group.Members) > 0 {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(
+					pathPrefix.Child("groups").Index(i).Child("members"),
+					cannotUseWithIgnition,
+				),
+			)
+		}
 	}
 
 	if c.UseExperimentalRetryJoin {
@@ -317,3 +437,129 @@ func (c *KubeadmConfigSpec) validateIgnition(pathPrefix *field.Path) field.Error
 
 	return allErrs
 }
+
+func (c *KubeadmConfigSpec) validateWindows(pathPrefix *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if c.OSFamily != WindowsOSFamily {
+		return allErrs
+	}
+
+	if c.Format == Ignition {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("format"), cannotUseWithWindows))
+	}
+
+	// Windows machines can only join the cluster as worker nodes.
+	if c.InitConfiguration != nil {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("initConfiguration"), cannotUseWithWindows))
+	}
+	if c.JoinConfiguration != nil && c.JoinConfiguration.ControlPlane != nil {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("joinConfiguration", "controlPlane"), cannotUseWithWindows))
+	}
+
+	if len(c.Users) > 0 {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("users"), cannotUseWithWindows))
+	}
+	if len(c.Groups) > 0 {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("groups"), cannotUseWithWindows))
+	}
+	if c.NTP != nil {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("ntp"), cannotUseWithWindows))
+	}
+	if c.UseExperimentalRetryJoin {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("useExperimentalRetryJoin"), cannotUseWithWindows))
+	}
+	if c.KubeletCredentialProviders != nil {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("kubeletCredentialProviders"), cannotUseWithWindows))
+	}
+
+	if c.DiskSetup != nil {
+		for i, partition := range c.DiskSetup.Partitions {
+			if _, err := strconv.Atoi(partition.Device); err != nil {
+				allErrs = append(
+					allErrs,
+					field.Invalid(
+						pathPrefix.Child("diskSetup", "partitions").Index(i).Child("device"),
+						partition.Device,
+						fmt.Sprintf("must be a disk number when spec.osFamily is set to %q", WindowsOSFamily),
+					),
+				)
+			}
+		}
+
+		for i, fs := range c.DiskSetup.Filesystems {
+			if _, err := strconv.Atoi(fs.Device); err != nil {
+				allErrs = append(
+					allErrs,
+					field.Invalid(
+						pathPrefix.Child("diskSetup", "filesystems").Index(i).Child("device"),
+						fs.Device,
+						fmt.Sprintf("must be a disk number when spec.osFamily is set to %q", WindowsOSFamily),
+					),
+				)
+			}
+			if fs.Partition != nil {
+				if _, err := strconv.Atoi(*fs.Partition); err != nil {
+					allErrs = append(
+						allErrs,
+						field.Invalid(
+							pathPrefix.Child("diskSetup", "filesystems").Index(i).Child("partition"),
+							*fs.Partition,
+							fmt.Sprintf("must be a partition number when spec.osFamily is set to %q", WindowsOSFamily),
+						),
+					)
+				}
+			}
+			if fs.ReplaceFS != nil {
+				allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("diskSetup", "filesystems").Index(i).Child("replaceFS"), cannotUseWithWindows))
+			}
+			if len(fs.ExtraOpts) > 0 {
+				allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("diskSetup", "filesystems").Index(i).Child("extraOpts"), cannotUseWithWindows))
+			}
+		}
+	}
+
+	for i, mount := range c.Mounts {
+		if len(mount) != 2 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					pathPrefix.Child("mounts").Index(i),
+					mount,
+					fmt.Sprintf("must be a filesystem label and a mount point when spec.osFamily is set to %q", WindowsOSFamily),
+				),
+			)
+		}
+	}
+
+	return allErrs
+}
+
+func (c *KubeadmConfigSpec) validateKubeletCredentialProviders(pathPrefix *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if c.KubeletCredentialProviders == nil {
+		return allErrs
+	}
+
+	path := pathPrefix.Child("kubeletCredentialProviders")
+	if c.KubeletCredentialProviders.BinDir != "" && !strings.HasPrefix(c.KubeletCredentialProviders.BinDir, "/") {
+		allErrs = append(allErrs, field.Invalid(path.Child("binDir"), c.KubeletCredentialProviders.BinDir, "must be an absolute path"))
+	}
+	if c.KubeletCredentialProviders.ConfigPath != "" && !strings.HasPrefix(c.KubeletCredentialProviders.ConfigPath, "/") {
+		allErrs = append(allErrs, field.Invalid(path.Child("configPath"), c.KubeletCredentialProviders.ConfigPath, "must be an absolute path"))
+	}
+
+	knownNames := map[string]struct{}{}
+	for i, provider := range c.KubeletCredentialProviders.Providers {
+		if provider.Name == "" || strings.ContainsAny(provider.Name, "/\\") {
+			allErrs = append(allErrs, field.Invalid(path.Child("providers").Index(i).Child("name"), provider.Name, "must be a non-empty binary name, not a path"))
+		}
+		if _, conflict := knownNames[provider.Name]; conflict {
+			allErrs = append(allErrs, field.Duplicate(path.Child("providers").Index(i).Child("name"), provider.Name))
+		}
+		knownNames[provider.Name] = struct{}{}
+	}
+
+	return allErrs
+}
@@ -90,7 +90,7 @@ func TestKubeadmConfigValidate(t *testing.T) {
 					Files: []File{
 						{
 							ContentFrom: &FileSource{
-								Secret: SecretFileSource{
+								Secret: &SecretFileSource{
 									Name: "foo",
 									Key:  "bar",
 								},
@@ -100,6 +100,88 @@ func TestKubeadmConfigValidate(t *testing.T) {
 				},
 			},
 		},
+		"valid contentFrom with a configMap": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							ContentFrom: &FileSource{
+								ConfigMap: &ConfigMapFileSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+								Template: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		"invalid contentFrom with both a secret and a configMap": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							ContentFrom: &FileSource{
+								Secret: &SecretFileSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+								ConfigMap: &ConfigMapFileSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid contentFrom without a source": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							ContentFrom: &FileSource{},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid contentFrom with a configMap without key": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							ContentFrom: &FileSource{
+								ConfigMap: &ConfigMapFileSource{
+									Name: "foo",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
 		"invalid content and contentFrom": {
 			in: &KubeadmConfig{
 				ObjectMeta: metav1.ObjectMeta{
@@ -127,7 +209,7 @@ func TestKubeadmConfigValidate(t *testing.T) {
 					Files: []File{
 						{
 							ContentFrom: &FileSource{
-								Secret: SecretFileSource{
+								Secret: &SecretFileSource{
 									Key: "bar",
 								},
 							},
@@ -148,7 +230,7 @@ func TestKubeadmConfigValidate(t *testing.T) {
 					Files: []File{
 						{
 							ContentFrom: &FileSource{
-								Secret: SecretFileSource{
+								Secret: &SecretFileSource{
 									Name: "foo",
 								},
 							},
@@ -272,6 +354,78 @@ func TestKubeadmConfigValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"valid sshAuthorizedKeysFrom and expireDate": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Users: []User{
+						{
+							SSHAuthorizedKeysFrom: &SSHAuthorizedKeysSource{
+								Secret: SecretSSHAuthorizedKeysSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+							},
+							ExpireDate: pointer.String("2030-12-31"),
+						},
+					},
+				},
+			},
+		},
+		"invalid sshAuthorizedKeysFrom without key": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Users: []User{
+						{
+							SSHAuthorizedKeysFrom: &SSHAuthorizedKeysSource{
+								Secret: SecretSSHAuthorizedKeysSource{
+									Name: "foo",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid expireDate": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Users: []User{
+						{
+							ExpireDate: pointer.String("31/12/2030"),
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid duplicated groups": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Groups: []Group{
+						{Name: "admins"},
+						{Name: "admins", Members: []string{"foo"}},
+					},
+				},
+			},
+			expectErr: true,
+		},
 		"Ignition field is set, format is not Ignition": {
 			enableIgnitionFeature: true,
 			in: &KubeadmConfig{
@@ -315,6 +469,40 @@ func TestKubeadmConfigValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"format is Ignition, user has an expire date": {
+			enableIgnitionFeature: true,
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					Users: []User{
+						{
+							ExpireDate: pointer.String("2030-12-31"),
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"format is Ignition, group has members": {
+			enableIgnitionFeature: true,
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					Groups: []Group{
+						{Name: "admins", Members: []string{"foo"}},
+					},
+				},
+			},
+			expectErr: true,
+		},
 		"format is Ignition, non-GPT partition configured": {
 			enableIgnitionFeature: true,
 			in: &KubeadmConfig{
@@ -452,6 +640,205 @@ func TestKubeadmConfigValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"valid Windows worker config": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					OSFamily:           WindowsOSFamily,
+					JoinConfiguration:  &JoinConfiguration{},
+					PreKubeadmCommands: []string{"Start-Service containerd"},
+					DiskSetup: &DiskSetup{
+						Partitions: []Partition{
+							{Device: "1", Layout: true},
+						},
+						Filesystems: []Filesystem{
+							{Device: "1", Filesystem: "ntfs", Label: "data", Partition: pointer.String("2")},
+						},
+					},
+					Mounts: []MountPoints{
+						{"LABEL=data", "D:"},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		"Windows with init configuration": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					OSFamily:          WindowsOSFamily,
+					InitConfiguration: &InitConfiguration{},
+				},
+			},
+			expectErr: true,
+		},
+		"Windows with control plane join configuration": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					OSFamily: WindowsOSFamily,
+					JoinConfiguration: &JoinConfiguration{
+						ControlPlane: &JoinControlPlane{},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"Windows with users": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					OSFamily: WindowsOSFamily,
+					Users: []User{
+						{Name: "foo"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"Windows with groups": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					OSFamily: WindowsOSFamily,
+					Groups: []Group{
+						{Name: "foo"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"Windows with a device which is not a disk number": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					OSFamily: WindowsOSFamily,
+					DiskSetup: &DiskSetup{
+						Partitions: []Partition{
+							{Device: "/dev/sdb", Layout: true},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"Windows with a mount point with options": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					OSFamily: WindowsOSFamily,
+					Mounts: []MountPoints{
+						{"LABEL=data", "D:", "auto"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"valid kubelet credential providers": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					KubeletCredentialProviders: &KubeletCredentialProviders{
+						BinDir: "/opt/credential-providers",
+						Providers: []KubeletCredentialProvider{
+							{Name: "ecr-credential-provider", MatchImages: []string{"*.dkr.ecr.*.amazonaws.com"}},
+							{Name: "registry-credential-provider", MatchImages: []string{"registry.example.com"}},
+						},
+					},
+				},
+			},
+		},
+		"kubelet credential providers with a relative bin dir": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					KubeletCredentialProviders: &KubeletCredentialProviders{
+						BinDir: "credential-providers",
+						Providers: []KubeletCredentialProvider{
+							{Name: "ecr-credential-provider", MatchImages: []string{"*.dkr.ecr.*.amazonaws.com"}},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"kubelet credential providers with a duplicated name": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					KubeletCredentialProviders: &KubeletCredentialProviders{
+						Providers: []KubeletCredentialProvider{
+							{Name: "ecr-credential-provider", MatchImages: []string{"*.dkr.ecr.*.amazonaws.com"}},
+							{Name: "ecr-credential-provider", MatchImages: []string{"*.dkr.ecr.*.amazonaws.com.cn"}},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"kubelet credential providers with a name containing a path separator": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					KubeletCredentialProviders: &KubeletCredentialProviders{
+						Providers: []KubeletCredentialProvider{
+							{Name: "../ecr-credential-provider", MatchImages: []string{"*.dkr.ecr.*.amazonaws.com"}},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"Windows with kubelet credential providers": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					OSFamily: WindowsOSFamily,
+					KubeletCredentialProviders: &KubeletCredentialProviders{
+						Providers: []KubeletCredentialProvider{
+							{Name: "ecr-credential-provider", MatchImages: []string{"*.dkr.ecr.*.amazonaws.com"}},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for name, tt := range cases {
@@ -69,6 +69,111 @@ func (in *APIServer) DeepCopy() *APIServer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapSnippet) DeepCopyInto(out *BootstrapSnippet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapSnippet.
+func (in *BootstrapSnippet) DeepCopy() *BootstrapSnippet {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapSnippet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BootstrapSnippet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapSnippetList) DeepCopyInto(out *BootstrapSnippetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BootstrapSnippet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapSnippetList.
+func (in *BootstrapSnippetList) DeepCopy() *BootstrapSnippetList {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapSnippetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BootstrapSnippetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapSnippetReference) DeepCopyInto(out *BootstrapSnippetReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapSnippetReference.
+func (in *BootstrapSnippetReference) DeepCopy() *BootstrapSnippetReference {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapSnippetReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapSnippetSpec) DeepCopyInto(out *BootstrapSnippetSpec) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]File, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreKubeadmCommands != nil {
+		in, out := &in.PreKubeadmCommands, &out.PreKubeadmCommands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostKubeadmCommands != nil {
+		in, out := &in.PostKubeadmCommands, &out.PostKubeadmCommands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapSnippetSpec.
+func (in *BootstrapSnippetSpec) DeepCopy() *BootstrapSnippetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapSnippetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapToken) DeepCopyInto(out *BootstrapToken) {
 	*out = *in
@@ -211,6 +316,21 @@ func (in *ClusterStatus) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapFileSource) DeepCopyInto(out *ConfigMapFileSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapFileSource.
+func (in *ConfigMapFileSource) DeepCopy() *ConfigMapFileSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapFileSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerLinuxConfig) DeepCopyInto(out *ContainerLinuxConfig) {
 	*out = *in
@@ -379,7 +499,7 @@ func (in *File) DeepCopyInto(out *File) {
 	if in.ContentFrom != nil {
 		in, out := &in.ContentFrom, &out.ContentFrom
 		*out = new(FileSource)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -411,7 +531,16 @@ func (in *FileDiscovery) DeepCopy() *FileDiscovery {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FileSource) DeepCopyInto(out *FileSource) {
 	*out = *in
-	out.Secret = in.Secret
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretFileSource)
+		**out = **in
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapFileSource)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileSource.
@@ -459,6 +588,26 @@ func (in *Filesystem) DeepCopy() *Filesystem {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Group) DeepCopyInto(out *Group) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Group.
+func (in *Group) DeepCopy() *Group {
+	if in == nil {
+		return nil
+	}
+	out := new(Group)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostPathMount) DeepCopyInto(out *HostPathMount) {
 	*out = *in
@@ -726,6 +875,13 @@ func (in *KubeadmConfigSpec) DeepCopyInto(out *KubeadmConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]Group, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.NTP != nil {
 		in, out := &in.NTP, &out.NTP
 		*out = new(NTP)
@@ -741,6 +897,16 @@ func (in *KubeadmConfigSpec) DeepCopyInto(out *KubeadmConfigSpec) {
 		*out = new(IgnitionSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.KubeletCredentialProviders != nil {
+		in, out := &in.KubeletCredentialProviders, &out.KubeletCredentialProviders
+		*out = new(KubeletCredentialProviders)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Snippets != nil {
+		in, out := &in.Snippets, &out.Snippets
+		*out = make([]BootstrapSnippetReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmConfigSpec.
@@ -768,6 +934,11 @@ func (in *KubeadmConfigStatus) DeepCopyInto(out *KubeadmConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.BootstrapProgress != nil {
+		in, out := &in.BootstrapProgress, &out.BootstrapProgress
+		*out = new(apiv1beta1.MachineBootstrapProgress)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmConfigStatus.
@@ -871,6 +1042,98 @@ func (in *KubeadmConfigTemplateSpec) DeepCopy() *KubeadmConfigTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletCredentialProvider) DeepCopyInto(out *KubeletCredentialProvider) {
+	*out = *in
+	if in.MatchImages != nil {
+		in, out := &in.MatchImages, &out.MatchImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultCacheDuration != nil {
+		in, out := &in.DefaultCacheDuration, &out.DefaultCacheDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]KubeletCredentialProviderEnvVar, len(*in))
+		copy(*out, *in)
+	}
+	if in.Binary != nil {
+		in, out := &in.Binary, &out.Binary
+		*out = new(KubeletCredentialProviderBinary)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletCredentialProvider.
+func (in *KubeletCredentialProvider) DeepCopy() *KubeletCredentialProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletCredentialProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletCredentialProviderBinary) DeepCopyInto(out *KubeletCredentialProviderBinary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletCredentialProviderBinary.
+func (in *KubeletCredentialProviderBinary) DeepCopy() *KubeletCredentialProviderBinary {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletCredentialProviderBinary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletCredentialProviderEnvVar) DeepCopyInto(out *KubeletCredentialProviderEnvVar) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletCredentialProviderEnvVar.
+func (in *KubeletCredentialProviderEnvVar) DeepCopy() *KubeletCredentialProviderEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletCredentialProviderEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletCredentialProviders) DeepCopyInto(out *KubeletCredentialProviders) {
+	*out = *in
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]KubeletCredentialProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletCredentialProviders.
+func (in *KubeletCredentialProviders) DeepCopy() *KubeletCredentialProviders {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletCredentialProviders)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalEtcd) DeepCopyInto(out *LocalEtcd) {
 	*out = *in
@@ -1053,6 +1316,22 @@ func (in *Patches) DeepCopy() *Patches {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHAuthorizedKeysSource) DeepCopyInto(out *SSHAuthorizedKeysSource) {
+	*out = *in
+	out.Secret = in.Secret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHAuthorizedKeysSource.
+func (in *SSHAuthorizedKeysSource) DeepCopy() *SSHAuthorizedKeysSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHAuthorizedKeysSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretFileSource) DeepCopyInto(out *SecretFileSource) {
 	*out = *in
@@ -1083,6 +1362,21 @@ func (in *SecretPasswdSource) DeepCopy() *SecretPasswdSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSSHAuthorizedKeysSource) DeepCopyInto(out *SecretSSHAuthorizedKeysSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSSHAuthorizedKeysSource.
+func (in *SecretSSHAuthorizedKeysSource) DeepCopy() *SecretSSHAuthorizedKeysSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSSHAuthorizedKeysSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
@@ -1141,6 +1435,16 @@ func (in *User) DeepCopyInto(out *User) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SSHAuthorizedKeysFrom != nil {
+		in, out := &in.SSHAuthorizedKeysFrom, &out.SSHAuthorizedKeysFrom
+		*out = new(SSHAuthorizedKeysSource)
+		**out = **in
+	}
+	if in.ExpireDate != nil {
+		in, out := &in.ExpireDate, &out.ExpireDate
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new User.
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package attestation defines the extension point used by the KubeadmConfig controller to require a successful
+// attestation of the host identity, e.g. using a TPM or a device attestation service, before bootstrap data are released.
+package attestation
+
+import (
+	"context"
+	"time"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	bsutil "sigs.k8s.io/cluster-api/bootstrap/util"
+)
+
+// Verifier verifies the identity of the host a KubeadmConfig is generating bootstrap data for.
+// The bootstrap data, which include a bootstrap token, are generated and stored in the bootstrap data secret
+// read by the infrastructure provider only after the Verifier reports the attestation as verified.
+//
+// NOTE: Implementations are responsible for deciding which KubeadmConfigs require attestation, e.g. based on labels,
+// and should return a verified Result for the others.
+type Verifier interface {
+	// Verify returns the result of the attestation for the host the KubeadmConfig is generating bootstrap data for.
+	// Errors are surfaced in the DataSecretAvailable condition of the KubeadmConfig, and Verify is retried with
+	// exponential backoff.
+	Verify(ctx context.Context, req *Request) (*Result, error)
+}
+
+// Request is the input of an attestation.
+type Request struct {
+	// Cluster is the Cluster the KubeadmConfig belongs to.
+	Cluster *clusterv1.Cluster
+
+	// Config is the KubeadmConfig the bootstrap data are generated for.
+	Config *bootstrapv1.KubeadmConfig
+
+	// ConfigOwner is the owner of the KubeadmConfig, i.e. a Machine or a MachinePool.
+	ConfigOwner *bsutil.ConfigOwner
+}
+
+// Result is the result of an attestation.
+type Result struct {
+	// Verified is true if the identity of the host has been verified and bootstrap data can be released.
+	Verified bool
+
+	// Message describes why the attestation is not verified yet, e.g. waiting for the host to provide evidence;
+	// it is surfaced in the DataSecretAvailable condition of the KubeadmConfig.
+	Message string
+
+	// RequeueAfter, if not zero, is the time after which the attestation is verified again if it is not verified yet.
+	// NOTE: If zero, implementations are expected to trigger a new reconcile of the KubeadmConfig when the
+	// attestation result changes, e.g. by updating an annotation on the KubeadmConfig.
+	RequeueAfter time.Duration
+}
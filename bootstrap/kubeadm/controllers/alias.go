@@ -24,7 +24,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/attestation"
 	kubeadmbootstrapcontrollers "sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/controllers"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 )
 
 // Following types provides access to reconcilers implemented in internal/controllers, thus
@@ -44,13 +46,21 @@ type KubeadmConfigReconciler struct {
 
 	// TokenTTL is the amount of time a bootstrap token (and therefore a KubeadmConfig) will be valid.
 	TokenTTL time.Duration
+
+	// AttestationVerifier, if set, is required to verify the identity of the host before bootstrap data are generated.
+	AttestationVerifier attestation.Verifier
+
+	// Tracker provides the clients used for reporting the progress of the bootstrap process in the workload cluster.
+	Tracker *remote.ClusterCacheTracker
 }
 
 // SetupWithManager sets up the reconciler with the Manager.
 func (r *KubeadmConfigReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&kubeadmbootstrapcontrollers.KubeadmConfigReconciler{
-		Client:           r.Client,
-		WatchFilterValue: r.WatchFilterValue,
-		TokenTTL:         r.TokenTTL,
+		Client:              r.Client,
+		WatchFilterValue:    r.WatchFilterValue,
+		TokenTTL:            r.TokenTTL,
+		AttestationVerifier: r.AttestationVerifier,
+		Tracker:             r.Tracker,
 	}).SetupWithManager(ctx, mgr, options)
 }
@@ -48,6 +48,7 @@ type BaseUserData struct {
 	AdditionalFiles      []bootstrapv1.File
 	WriteFiles           []bootstrapv1.File
 	Users                []bootstrapv1.User
+	Groups               []bootstrapv1.Group
 	NTP                  *bootstrapv1.NTP
 	DiskSetup            *bootstrapv1.DiskSetup
 	Mounts               []bootstrapv1.MountPoints
@@ -92,6 +93,10 @@ func generate(kind string, tpl string, data interface{}) ([]byte, error) {
 		return nil, errors.Wrap(err, "failed to parse users template")
 	}
 
+	if _, err := tm.Parse(groupsTemplate); err != nil {
+		return nil, errors.Wrap(err, "failed to parse groups template")
+	}
+
 	if _, err := tm.Parse(diskSetupTemplate); err != nil {
 		return nil, errors.Wrap(err, "failed to parse disk setup template")
 	}
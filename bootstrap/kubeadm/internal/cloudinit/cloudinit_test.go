@@ -125,6 +125,54 @@ func TestNewInitControlPlaneCommands(t *testing.T) {
 	}
 }
 
+func TestNewInitControlPlaneUsersAndGroups(t *testing.T) {
+	g := NewWithT(t)
+
+	cpinput := &ControlPlaneInput{
+		BaseUserData: BaseUserData{
+			Header: "test",
+			Users: []bootstrapv1.User{
+				{
+					Name:              "foo",
+					Groups:            pointer.String("admins, docker"),
+					ExpireDate:        pointer.String("2030-12-31"),
+					SSHAuthorizedKeys: []string{"ssh-rsa foo"},
+				},
+			},
+			Groups: []bootstrapv1.Group{
+				{
+					Name: "docker",
+				},
+				{
+					Name:    "admins",
+					Members: []string{"root", "bar"},
+				},
+			},
+		},
+		Certificates:         secret.Certificates{},
+		ClusterConfiguration: "my-cluster-config",
+		InitConfiguration:    "my-init-config",
+	}
+
+	out, err := NewInitControlPlane(cpinput)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	expectedUsers := `users:
+  - name: foo
+    groups: admins, docker
+    expiredate: 2030-12-31
+    ssh_authorized_keys:
+      - ssh-rsa foo`
+	g.Expect(out).To(ContainSubstring(expectedUsers))
+
+	expectedGroups := `groups:
+  - docker
+  - admins:
+      - root
+      - bar`
+	g.Expect(out).To(ContainSubstring(expectedGroups))
+}
+
 func TestNewInitControlPlaneDiskMounts(t *testing.T) {
 	g := NewWithT(t)
 
@@ -40,6 +40,7 @@ runcmd:
   - 'kubeadm init --config /run/kubeadm/kubeadm.yaml {{.KubeadmVerbosity}} && {{ .SentinelFileCommand }}'
 {{- template "commands" .PostKubeadmCommands }}
 {{- template "ntp" .NTP }}
+{{- template "groups" .Groups }}
 {{- template "users" .Users }}
 {{- template "disk_setup" .DiskSetup}}
 {{- template "fs_setup" .DiskSetup}}
@@ -34,6 +34,7 @@ runcmd:
   - {{ .KubeadmCommand }} && {{ .SentinelFileCommand }}
 {{- template "commands" .PostKubeadmCommands }}
 {{- template "ntp" .NTP }}
+{{- template "groups" .Groups }}
 {{- template "users" .Users }}
 {{- template "disk_setup" .DiskSetup}}
 {{- template "fs_setup" .DiskSetup}}
@@ -45,6 +45,9 @@ users:{{ range . }}
     {{- if .PrimaryGroup }}
     primary_group: {{ .PrimaryGroup }}
     {{- end -}}
+    {{- if .ExpireDate }}
+    expiredate: {{ .ExpireDate }}
+    {{- end -}}
     {{- if .Sudo }}
     sudo: {{ .Sudo }}
     {{- end -}}
@@ -56,5 +59,19 @@ users:{{ range . }}
 {{- end -}}
 {{- end -}}
 {{- end -}}
+`
+	groupsTemplate = `{{ define "groups" -}}
+{{- if . }}
+groups:{{ range . }}
+  {{- if .Members }}
+  - {{ .Name }}:{{ range .Members }}
+      - {{ . }}
+    {{- end -}}
+  {{- else }}
+  - {{ .Name }}
+  {{- end -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
 `
 )
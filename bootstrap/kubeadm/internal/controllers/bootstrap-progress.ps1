@@ -0,0 +1,75 @@
+# Copyright 2023 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Reports a phase of the bootstrap process to the workload cluster; while the bootstrap process
+# is running, the current phase is reported again periodically as a heartbeat.
+# When reporting the WaitingForJoinPermit phase, the script waits until the join is permitted.
+# Usage: & '{{ .ScriptPath }}' -Phase <phase>
+param(
+  [string]$Phase,
+  [int]$Parent = 0
+)
+
+$ErrorActionPreference = 'Continue'
+$phaseFile = '/run/cluster-api/bootstrap-phase'
+
+function Send-BootstrapPhase([string]$current) {
+  $now = (Get-Date).ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')
+  # The body is passed using a file, because Windows PowerShell drops the double quotes in native command arguments.
+  $body = New-TemporaryFile
+  Set-Content -Path $body -NoNewline -Value ('{"data":{"phase":"' + $current + '","lastHeartbeatTime":"' + $now + '"}}')
+  & curl.exe -sS --max-time 10 --cacert '{{ .CACertPath }}' `
+    -H 'Authorization: Bearer {{ .Token }}' `
+    -H 'Content-Type: application/merge-patch+json' `
+    -X PATCH --data-binary "@$($body.FullName)" `
+    'https://{{ .Endpoint }}/api/v1/namespaces/kube-system/configmaps/{{ .ConfigMapName }}' 2>&1 | Out-Null
+  Remove-Item -Path $body -Force
+}
+
+function Test-JoinPermitted {
+  $configMap = & curl.exe -sS --max-time 10 --cacert '{{ .CACertPath }}' `
+    -H 'Authorization: Bearer {{ .Token }}' `
+    'https://{{ .Endpoint }}/api/v1/namespaces/kube-system/configmaps/{{ .ConfigMapName }}' 2>$null | Out-String
+  return $configMap -match '"{{ .JoinPermittedKey }}":\s*"true"'
+}
+
+# Heartbeat, running while the bootstrap process is running and not completed.
+if ($Parent -ne 0) {
+  while (Get-Process -Id $Parent -ErrorAction SilentlyContinue) {
+    Start-Sleep -Seconds {{ .HeartbeatInterval }}
+    $current = Get-Content -Path $phaseFile
+    if ($current -eq 'WaitingForNode' -or $current -eq 'KubeadmFailed') {
+      exit 0
+    }
+    Send-BootstrapPhase $current
+  }
+  exit 0
+}
+
+New-Item -ItemType Directory -Force -Path (Split-Path -Parent $phaseFile) | Out-Null
+Set-Content -Path $phaseFile -Value $Phase
+Send-BootstrapPhase $Phase
+
+# Wait for the join to be permitted.
+if ($Phase -eq 'WaitingForJoinPermit') {
+  while (-not (Test-JoinPermitted)) {
+    Start-Sleep -Seconds {{ .HeartbeatInterval }}
+  }
+}
+
+# Start the heartbeat with the first phase.
+if ($Phase -eq 'RunningPreKubeadmCommands') {
+  Start-Process -FilePath powershell.exe -WindowStyle Hidden `
+    -ArgumentList '-NoProfile', '-ExecutionPolicy', 'Bypass', '-File', $PSCommandPath, '-Parent', $PID
+}
@@ -0,0 +1,68 @@
+#!/bin/sh
+# Copyright 2023 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Reports a phase of the bootstrap process to the workload cluster; while the bootstrap process
+# is running, the current phase is reported again periodically as a heartbeat.
+# When reporting the WaitingForJoinPermit phase, the script waits until the join is permitted.
+# Usage: {{ .ScriptPath }} <phase>
+
+phase_file=/run/cluster-api/bootstrap-phase
+
+report() {
+  now=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+  curl -sS --max-time 10 --cacert '{{ .CACertPath }}' \
+    -H 'Authorization: Bearer {{ .Token }}' \
+    -H 'Content-Type: application/merge-patch+json' \
+    -X PATCH --data "{\"data\":{\"phase\":\"${1}\",\"lastHeartbeatTime\":\"${now}\"}}" \
+    'https://{{ .Endpoint }}/api/v1/namespaces/kube-system/configmaps/{{ .ConfigMapName }}' >/dev/null 2>&1 || true
+}
+
+join_permitted() {
+  curl -sS --max-time 10 --cacert '{{ .CACertPath }}' \
+    -H 'Authorization: Bearer {{ .Token }}' \
+    'https://{{ .Endpoint }}/api/v1/namespaces/kube-system/configmaps/{{ .ConfigMapName }}' 2>/dev/null |
+    grep -q '"{{ .JoinPermittedKey }}": *"true"'
+}
+
+phase="${1}"
+# Post kubeadm commands run also if kubeadm fails, so check if kubeadm succeeded.
+if [ "${phase}" = "RunningPostKubeadmCommands" ] && [ ! -f /run/cluster-api/bootstrap-success.complete ]; then
+  phase="KubeadmFailed"
+fi
+mkdir -p /run/cluster-api
+echo "${phase}" > "${phase_file}"
+report "${phase}"
+
+# Wait for the join to be permitted.
+if [ "${phase}" = "WaitingForJoinPermit" ]; then
+  until join_permitted; do
+    sleep {{ .HeartbeatInterval }}
+  done
+fi
+
+# Start the heartbeat with the first phase; it stops when the bootstrap process exits or completes.
+if [ "${phase}" = "RunningPreKubeadmCommands" ]; then
+  parent="${PPID}"
+  (
+    while kill -0 "${parent}" 2>/dev/null; do
+      sleep {{ .HeartbeatInterval }}
+      phase=$(cat "${phase_file}")
+      case "${phase}" in
+      WaitingForNode | KubeadmFailed) exit 0 ;;
+      esac
+      report "${phase}"
+    done
+  ) >/dev/null 2>&1 &
+fi
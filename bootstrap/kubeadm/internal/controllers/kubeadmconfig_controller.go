@@ -17,9 +17,14 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strconv"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/blang/semver"
@@ -42,9 +47,11 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/attestation"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/ignition"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/locking"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/powershell"
 	kubeadmtypes "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types"
 	bsutil "sigs.k8s.io/cluster-api/bootstrap/util"
 	"sigs.k8s.io/cluster-api/controllers/remote"
@@ -55,6 +62,7 @@ import (
 	"sigs.k8s.io/cluster-api/util/conditions"
 	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/paused"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	"sigs.k8s.io/cluster-api/util/secret"
 )
@@ -67,6 +75,10 @@ const (
 const (
 	// DefaultTokenTTL is the default TTL used for tokens.
 	DefaultTokenTTL = 15 * time.Minute
+
+	// fileSourcesCheckInterval is the interval used for checking changes to the content of files and users
+	// populated from Secrets or ConfigMaps after the bootstrap data has been generated.
+	fileSourcesCheckInterval = 5 * time.Minute
 )
 
 // InitLocker is a lock that is used around kubeadm init.
@@ -76,7 +88,9 @@ type InitLocker interface {
 }
 
 // +kubebuilder:rbac:groups=bootstrap.cluster.x-k8s.io,resources=kubeadmconfigs;kubeadmconfigs/status;kubeadmconfigs/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=bootstrap.cluster.x-k8s.io,resources=bootstrapsnippets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status;machinesets;machines;machines/status;machinepools;machinepools/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups="",resources=secrets;events;configmaps,verbs=get;list;watch;create;update;patch;delete
 
 // KubeadmConfigReconciler reconciles a KubeadmConfig object.
@@ -90,6 +104,12 @@ type KubeadmConfigReconciler struct {
 	// TokenTTL is the amount of time a bootstrap token (and therefore a KubeadmConfig) will be valid.
 	TokenTTL time.Duration
 
+	// AttestationVerifier, if set, is required to verify the identity of the host before bootstrap data are generated.
+	AttestationVerifier attestation.Verifier
+
+	// Tracker provides the clients used for reporting the progress of the bootstrap process in the workload cluster.
+	Tracker *remote.ClusterCacheTracker
+
 	remoteClientGetter remote.ClusterClientGetter
 }
 
@@ -99,6 +119,10 @@ type Scope struct {
 	Config      *bootstrapv1.KubeadmConfig
 	ConfigOwner *bsutil.ConfigOwner
 	Cluster     *clusterv1.Cluster
+
+	// Addresses are the Machine addresses reported by the infrastructure provider; they are
+	// set only if the KubeadmConfig waits for the infrastructure addresses.
+	Addresses clusterv1.MachineAddresses
 }
 
 // SetupWithManager sets up the reconciler with the Manager.
@@ -109,6 +133,9 @@ func (r *KubeadmConfigReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 	if r.remoteClientGetter == nil {
 		r.remoteClientGetter = remote.NewClusterClient
 	}
+	if r.Tracker == nil {
+		return errors.New("cluster cache tracker is nil, cannot report the progress of the bootstrap process")
+	}
 	if r.TokenTTL == 0 {
 		r.TokenTTL = DefaultTokenTTL
 	}
@@ -119,6 +146,10 @@ func (r *KubeadmConfigReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 		Watches(
 			&source.Kind{Type: &clusterv1.Machine{}},
 			handler.EnqueueRequestsFromMapFunc(r.MachineToBootstrapMapFunc),
+		).
+		Watches(
+			&source.Kind{Type: &bootstrapv1.BootstrapSnippet{}},
+			handler.EnqueueRequestsFromMapFunc(r.BootstrapSnippetToKubeadmConfigs),
 		).WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue))
 
 	if feature.Gates.Enabled(feature.MachinePool) {
@@ -133,7 +164,10 @@ func (r *KubeadmConfigReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 		handler.EnqueueRequestsFromMapFunc(r.ClusterToKubeadmConfigs),
 		builder.WithPredicates(
 			predicates.All(ctrl.LoggerFrom(ctx),
-				predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx)),
+				predicates.Any(ctrl.LoggerFrom(ctx),
+					predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx)),
+					predicates.ClusterPauseChanged(ctrl.LoggerFrom(ctx)),
+				),
 				predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 			),
 		),
@@ -200,9 +234,14 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
-	if annotations.IsPaused(cluster, config) {
+	// Return early if the object or Cluster is paused.
+	isPaused, requeueAfter, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, config)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if isPaused {
 		log.Info("Reconciliation is paused for this object")
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	scope := &Scope{
@@ -249,6 +288,13 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		log.Info("Cluster infrastructure is not ready, waiting")
 		conditions.MarkFalse(config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.WaitingForClusterInfrastructureReason, clusterv1.ConditionSeverityInfo, "")
 		return ctrl.Result{}, nil
+	// Re-generate the bootstrap data if a re-bootstrap of the Machine has been requested, even if it has already been generated.
+	// NOTE: Re-bootstrap is not supported for MachinePools.
+	case annotations.IsRebootstrapRequested(config) && !configOwner.IsMachinePool():
+		log.Info("Re-bootstrap requested, re-generating bootstrap data")
+		if err := r.prepareRebootstrap(ctx, config, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
 	// Reconcile status for machines that already have a secret reference, but our status isn't up to date.
 	// This case solves the pivoting scenario (or a backup restore) which doesn't preserve the status subresource on objects.
 	case configOwner.DataSecretName() != nil && (!config.Status.Ready || config.Status.DataSecretName == nil):
@@ -258,12 +304,36 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, nil
 	// Status is ready means a config has been generated.
 	case config.Status.Ready:
+		// Surface changes to the content of files and users populated from Secrets or ConfigMaps, so they can trigger a rollout.
+		r.reconcileFileSources(ctx, config)
+		r.reconcileUserSources(ctx, config)
+		if err := r.rolloutMachineDeploymentForSources(ctx, config, configOwner); err != nil {
+			return ctrl.Result{}, err
+		}
+
 		if config.Spec.JoinConfiguration != nil && config.Spec.JoinConfiguration.Discovery.BootstrapToken != nil {
 			if !configOwner.HasNodeRefs() {
 				// If the BootstrapToken has been generated for a join but the config owner has no nodeRefs,
 				// this indicates that the node has not yet joined and the token in the join config has not
 				// been consumed and it may need a refresh.
-				return r.refreshBootstrapToken(ctx, config, cluster)
+				res, err := r.refreshBootstrapToken(ctx, config, cluster)
+				if err != nil || !reportsBootstrapProgress(config, configOwner) {
+					return res, err
+				}
+				// Surface the progress of the bootstrap process reported by the machine, and periodically check for changes.
+				if err := r.reconcileBootstrapProgress(ctx, config, cluster); err != nil {
+					return ctrl.Result{}, err
+				}
+				if res.RequeueAfter == 0 || res.RequeueAfter > bootstrapProgressCheckInterval {
+					res.RequeueAfter = bootstrapProgressCheckInterval
+				}
+				return res, nil
+			}
+			// Once the node has joined, the progress of the bootstrap process is not reported anymore.
+			if config.Status.BootstrapProgress != nil || conditions.Has(config, bootstrapv1.BootstrapHeartbeatCondition) {
+				if err := r.deleteBootstrapProgress(ctx, config, cluster); err != nil {
+					return ctrl.Result{}, err
+				}
 			}
 			if configOwner.IsMachinePool() {
 				// If the BootstrapToken has been generated and infrastructure is ready but the configOwner is a MachinePool,
@@ -271,10 +341,41 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 				return r.rotateMachinePoolBootstrapToken(ctx, config, cluster, scope)
 			}
 		}
-		// In any other case just return as the config is already generated and need not be generated again.
+		// In any other case just return as the config is already generated and need not be generated again;
+		// if there are files or users populated from Secrets or ConfigMaps, periodically check for changes to their content.
+		if _, ok := config.Annotations[bootstrapv1.FileSourcesChecksumAnnotation]; ok {
+			return ctrl.Result{RequeueAfter: fileSourcesCheckInterval}, nil
+		}
+		if _, ok := config.Annotations[bootstrapv1.UserSourcesChecksumAnnotation]; ok {
+			return ctrl.Result{RequeueAfter: fileSourcesCheckInterval}, nil
+		}
 		return ctrl.Result{}, nil
 	}
 
+	// Wait for the infrastructure provider to report the Machine addresses, if requested.
+	// NOTE: The KubeadmConfig is reconciled again when the addresses are surfaced on the Machine status.
+	if _, ok := config.Annotations[bootstrapv1.WaitForInfrastructureAddressesAnnotation]; ok && !configOwner.IsMachinePool() {
+		addresses := configOwner.Addresses()
+		if len(addresses) == 0 {
+			log.Info("Waiting for the infrastructure provider to report the Machine addresses")
+			conditions.MarkFalse(config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.WaitingForInfrastructureAddressesReason, clusterv1.ConditionSeverityInfo, "")
+			return ctrl.Result{}, nil
+		}
+		scope.Addresses = addresses
+	}
+
+	// Control plane machines are not supported on Windows.
+	if config.Spec.OSFamily == bootstrapv1.WindowsOSFamily && configOwner.IsControlPlaneMachine() {
+		err := errors.Errorf("%s is a control plane machine, but spec.osFamily is set to %q in the KubeadmConfig object", configOwner.GetKind(), bootstrapv1.WindowsOSFamily)
+		conditions.MarkFalse(config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	// Wait for the attestation of the host identity to be verified, if required.
+	if verified, res, err := r.verifyAttestation(ctx, scope); err != nil || !verified {
+		return res, err
+	}
+
 	// Note: can't use IsFalse here because we need to handle the absence of the condition as well as false.
 	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
 		return r.handleClusterNotInitialized(ctx, scope)
@@ -301,6 +402,38 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	return r.joinWorker(ctx, scope)
 }
 
+// verifyAttestation calls the AttestationVerifier, if any, and returns true if bootstrap data can be generated.
+// NOTE: Bootstrap data, including the bootstrap token, are not generated until the attestation is verified, so
+// they are never released to the infrastructure provider for hosts with an identity not verified.
+func (r *KubeadmConfigReconciler) verifyAttestation(ctx context.Context, scope *Scope) (bool, ctrl.Result, error) {
+	if r.AttestationVerifier == nil {
+		return true, ctrl.Result{}, nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	res, err := r.AttestationVerifier.Verify(ctx, &attestation.Request{
+		Cluster:     scope.Cluster,
+		Config:      scope.Config,
+		ConfigOwner: scope.ConfigOwner,
+	})
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.AttestationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return false, ctrl.Result{}, errors.Wrap(err, "failed to verify attestation")
+	}
+	if res == nil || !res.Verified {
+		message := ""
+		requeueAfter := time.Duration(0)
+		if res != nil {
+			message = res.Message
+			requeueAfter = res.RequeueAfter
+		}
+		log.Info("Waiting for the attestation of the host identity to be verified", "message", message)
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.WaitingForAttestationReason, clusterv1.ConditionSeverityInfo, message)
+		return false, ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	return true, ctrl.Result{}, nil
+}
+
 func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 	token := config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
@@ -320,6 +453,33 @@ func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, con
 	}, nil
 }
 
+// prepareRebootstrap ensures the bootstrap token used by a Machine to join the cluster again is still valid,
+// otherwise the token is dropped from the JoinConfiguration, so a new one is created when re-generating the bootstrap data.
+func (r *KubeadmConfigReconciler) prepareRebootstrap(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) ||
+		config.Spec.JoinConfiguration == nil || config.Spec.JoinConfiguration.Discovery.BootstrapToken == nil ||
+		config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token == "" {
+		return nil
+	}
+
+	remoteClient, err := r.remoteClientGetter(ctx, KubeadmConfigControllerName, r.Client, util.ObjectKey(cluster))
+	if err != nil {
+		return err
+	}
+
+	shouldRotate, err := shouldRotate(ctx, remoteClient, config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token, r.TokenTTL)
+	if err != nil {
+		return err
+	}
+	if shouldRotate {
+		log.Info("Bootstrap token is expired or about to expire, a new one will be created for re-bootstrap")
+		config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token = ""
+	}
+	return nil
+}
+
 func (r *KubeadmConfigReconciler) rotateMachinePoolBootstrapToken(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster, scope *Scope) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 	log.V(2).Info("Config is owned by a MachinePool, checking if token should be rotated")
@@ -414,7 +574,13 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 		}
 	}
 
-	initdata, err := kubeadmtypes.MarshalInitConfigurationForVersion(scope.Config.Spec.InitConfiguration, parsedVersion)
+	// Default the node addresses from the ones reported by the infrastructure provider, if any.
+	// DeepCopy the InitConfiguration to prevent updating the actual KubeadmConfig.
+	initConfiguration := scope.Config.Spec.InitConfiguration.DeepCopy()
+	setNodeAddressDefaults(&initConfiguration.NodeRegistration, &initConfiguration.LocalAPIEndpoint, scope.Addresses)
+	setKubeletCredentialProvidersArgs(&initConfiguration.NodeRegistration, scope.Config.Spec.KubeletCredentialProviders, parsedVersion)
+
+	initdata, err := kubeadmtypes.MarshalInitConfigurationForVersion(initConfiguration, parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal init configuration")
 		return ctrl.Result{}, err
@@ -471,20 +637,40 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
+	setFileSourcesChecksum(scope.Config, files)
+
+	snippets, err := r.resolveSnippets(ctx, scope.Config)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	credentialProviderFiles, preKubeadmCommands, err := kubeletCredentialProvidersBootstrapData(scope.Config.Spec.KubeletCredentialProviders, parsedVersion)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	files = append(snippets.Files, files...)
+	files = append(files, credentialProviderFiles...)
+	preKubeadmCommands = append(preKubeadmCommands, snippets.PreKubeadmCommands...)
+	preKubeadmCommands = append(preKubeadmCommands, scope.Config.Spec.PreKubeadmCommands...)
+	postKubeadmCommands := append(snippets.PostKubeadmCommands, scope.Config.Spec.PostKubeadmCommands...)
 
 	users, err := r.resolveUsers(ctx, scope.Config)
 	if err != nil {
 		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
+	setUserSourcesChecksum(scope.Config, users)
 
 	controlPlaneInput := &cloudinit.ControlPlaneInput{
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:     files,
 			NTP:                 scope.Config.Spec.NTP,
-			PreKubeadmCommands:  scope.Config.Spec.PreKubeadmCommands,
-			PostKubeadmCommands: scope.Config.Spec.PostKubeadmCommands,
+			PreKubeadmCommands:  preKubeadmCommands,
+			PostKubeadmCommands: postKubeadmCommands,
 			Users:               users,
+			Groups:              scope.Config.Spec.Groups,
 			Mounts:              scope.Config.Spec.Mounts,
 			DiskSetup:           scope.Config.Spec.DiskSetup,
 			KubeadmVerbosity:    verbosityFlag,
@@ -558,6 +744,8 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 	if !hasTaint(joinConfiguration.NodeRegistration.Taints, clusterv1.NodeUninitializedTaint) {
 		joinConfiguration.NodeRegistration.Taints = append(joinConfiguration.NodeRegistration.Taints, clusterv1.NodeUninitializedTaint)
 	}
+	setNodeAddressDefaults(&joinConfiguration.NodeRegistration, nil, scope.Addresses)
+	setKubeletCredentialProvidersArgs(&joinConfiguration.NodeRegistration, scope.Config.Spec.KubeletCredentialProviders, parsedVersion)
 
 	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(joinConfiguration, parsedVersion)
 	if err != nil {
@@ -579,20 +767,48 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
+	setFileSourcesChecksum(scope.Config, files)
+
+	snippets, err := r.resolveSnippets(ctx, scope.Config)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	credentialProviderFiles, preKubeadmCommands, err := kubeletCredentialProvidersBootstrapData(scope.Config.Spec.KubeletCredentialProviders, parsedVersion)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	files = append(snippets.Files, files...)
+	files = append(files, credentialProviderFiles...)
+	preKubeadmCommands = append(preKubeadmCommands, snippets.PreKubeadmCommands...)
+	preKubeadmCommands = append(preKubeadmCommands, scope.Config.Spec.PreKubeadmCommands...)
+	postKubeadmCommands := append(snippets.PostKubeadmCommands, scope.Config.Spec.PostKubeadmCommands...)
+
+	if reportsBootstrapProgress(scope.Config, scope.ConfigOwner) {
+		files, preKubeadmCommands, postKubeadmCommands, err = r.setupBootstrapProgress(ctx, scope, certificates.GetByPurpose(secret.ClusterCA).KeyPair.Cert, files, preKubeadmCommands, postKubeadmCommands)
+		if err != nil {
+			conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return ctrl.Result{}, err
+		}
+	}
 
 	users, err := r.resolveUsers(ctx, scope.Config)
 	if err != nil {
 		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
+	setUserSourcesChecksum(scope.Config, users)
 
 	nodeInput := &cloudinit.NodeInput{
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:      files,
 			NTP:                  scope.Config.Spec.NTP,
-			PreKubeadmCommands:   scope.Config.Spec.PreKubeadmCommands,
-			PostKubeadmCommands:  scope.Config.Spec.PostKubeadmCommands,
+			PreKubeadmCommands:   preKubeadmCommands,
+			PostKubeadmCommands:  postKubeadmCommands,
 			Users:                users,
+			Groups:               scope.Config.Spec.Groups,
 			Mounts:               scope.Config.Spec.Mounts,
 			DiskSetup:            scope.Config.Spec.DiskSetup,
 			KubeadmVerbosity:     verbosityFlag,
@@ -602,8 +818,10 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 	}
 
 	var bootstrapJoinData []byte
-	switch scope.Config.Spec.Format {
-	case bootstrapv1.Ignition:
+	switch {
+	case scope.Config.Spec.OSFamily == bootstrapv1.WindowsOSFamily:
+		bootstrapJoinData, err = powershell.NewNode(nodeInput)
+	case scope.Config.Spec.Format == bootstrapv1.Ignition:
 		bootstrapJoinData, _, err = ignition.NewNode(&ignition.NodeInput{
 			NodeInput: nodeInput,
 			Ignition:  scope.Config.Spec.Ignition,
@@ -665,7 +883,13 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		return ctrl.Result{}, errors.Wrapf(err, "failed to parse kubernetes version %q", kubernetesVersion)
 	}
 
-	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(scope.Config.Spec.JoinConfiguration, parsedVersion)
+	// Default the node addresses from the ones reported by the infrastructure provider, if any.
+	// DeepCopy the JoinConfiguration to prevent updating the actual KubeadmConfig.
+	joinConfiguration := scope.Config.Spec.JoinConfiguration.DeepCopy()
+	setNodeAddressDefaults(&joinConfiguration.NodeRegistration, &joinConfiguration.ControlPlane.LocalAPIEndpoint, scope.Addresses)
+	setKubeletCredentialProvidersArgs(&joinConfiguration.NodeRegistration, scope.Config.Spec.KubeletCredentialProviders, parsedVersion)
+
+	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(joinConfiguration, parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal join configuration")
 		return ctrl.Result{}, err
@@ -681,12 +905,39 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
+	setFileSourcesChecksum(scope.Config, files)
+
+	snippets, err := r.resolveSnippets(ctx, scope.Config)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	credentialProviderFiles, preKubeadmCommands, err := kubeletCredentialProvidersBootstrapData(scope.Config.Spec.KubeletCredentialProviders, parsedVersion)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	files = append(snippets.Files, files...)
+	files = append(files, credentialProviderFiles...)
+	preKubeadmCommands = append(preKubeadmCommands, snippets.PreKubeadmCommands...)
+	preKubeadmCommands = append(preKubeadmCommands, scope.Config.Spec.PreKubeadmCommands...)
+	postKubeadmCommands := append(snippets.PostKubeadmCommands, scope.Config.Spec.PostKubeadmCommands...)
+
+	if reportsBootstrapProgress(scope.Config, scope.ConfigOwner) {
+		files, preKubeadmCommands, postKubeadmCommands, err = r.setupBootstrapProgress(ctx, scope, certificates.GetByPurpose(secret.ClusterCA).KeyPair.Cert, files, preKubeadmCommands, postKubeadmCommands)
+		if err != nil {
+			conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return ctrl.Result{}, err
+		}
+	}
 
 	users, err := r.resolveUsers(ctx, scope.Config)
 	if err != nil {
 		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
+	setUserSourcesChecksum(scope.Config, users)
 
 	controlPlaneJoinInput := &cloudinit.ControlPlaneJoinInput{
 		JoinConfiguration: joinData,
@@ -694,9 +945,10 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:      files,
 			NTP:                  scope.Config.Spec.NTP,
-			PreKubeadmCommands:   scope.Config.Spec.PreKubeadmCommands,
-			PostKubeadmCommands:  scope.Config.Spec.PostKubeadmCommands,
+			PreKubeadmCommands:   preKubeadmCommands,
+			PostKubeadmCommands:  postKubeadmCommands,
 			Users:                users,
+			Groups:               scope.Config.Spec.Groups,
 			Mounts:               scope.Config.Spec.Mounts,
 			DiskSetup:            scope.Config.Spec.DiskSetup,
 			KubeadmVerbosity:     verbosityFlag,
@@ -736,7 +988,7 @@ func (r *KubeadmConfigReconciler) resolveFiles(ctx context.Context, cfg *bootstr
 	for i := range cfg.Spec.Files {
 		in := cfg.Spec.Files[i]
 		if in.ContentFrom != nil {
-			data, err := r.resolveSecretFileContent(ctx, cfg.Namespace, in)
+			data, err := r.resolveFileContent(ctx, cfg, in)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to resolve file source")
 			}
@@ -749,21 +1001,232 @@ func (r *KubeadmConfigReconciler) resolveFiles(ctx context.Context, cfg *bootstr
 	return collected, nil
 }
 
-// resolveSecretFileContent returns file content fetched from a referenced secret object.
-func (r *KubeadmConfigReconciler) resolveSecretFileContent(ctx context.Context, ns string, source bootstrapv1.File) ([]byte, error) {
-	secret := &corev1.Secret{}
-	key := types.NamespacedName{Namespace: ns, Name: source.ContentFrom.Secret.Name}
-	if err := r.Client.Get(ctx, key, secret); err != nil {
-		if apierrors.IsNotFound(err) {
-			return nil, errors.Wrapf(err, "secret not found: %s", key)
+// resolveSnippets composes the BootstrapSnippets referenced in .Spec.Snippets, in order, into
+// a single BootstrapSnippetSpec, resolving any file object references along the way.
+func (r *KubeadmConfigReconciler) resolveSnippets(ctx context.Context, cfg *bootstrapv1.KubeadmConfig) (*bootstrapv1.BootstrapSnippetSpec, error) {
+	collected := &bootstrapv1.BootstrapSnippetSpec{}
+
+	for _, ref := range cfg.Spec.Snippets {
+		snippet := &bootstrapv1.BootstrapSnippet{}
+		key := types.NamespacedName{Namespace: cfg.Namespace, Name: ref.Name}
+		if err := r.Client.Get(ctx, key, snippet); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "BootstrapSnippet not found: %s", key)
+			}
+			return nil, errors.Wrapf(err, "failed to retrieve BootstrapSnippet %q", key)
 		}
-		return nil, errors.Wrapf(err, "failed to retrieve Secret %q", key)
+
+		for i := range snippet.Spec.Files {
+			in := snippet.Spec.Files[i]
+			if in.ContentFrom != nil {
+				data, err := r.resolveFileContent(ctx, cfg, in)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to resolve file source for BootstrapSnippet %q", key)
+				}
+				in.ContentFrom = nil
+				in.Content = string(data)
+			}
+			collected.Files = append(collected.Files, in)
+		}
+		collected.PreKubeadmCommands = append(collected.PreKubeadmCommands, snippet.Spec.PreKubeadmCommands...)
+		collected.PostKubeadmCommands = append(collected.PostKubeadmCommands, snippet.Spec.PostKubeadmCommands...)
 	}
-	data, ok := secret.Data[source.ContentFrom.Secret.Key]
+
+	return collected, nil
+}
+
+// fileTemplateData is the data available to the templates of files populated from Secrets or ConfigMaps.
+type fileTemplateData struct {
+	// ClusterName is the name of the Cluster the KubeadmConfig belongs to.
+	ClusterName string
+
+	// Namespace is the namespace of the KubeadmConfig.
+	Namespace string
+
+	// Data contains all the keys of the referenced Secret or ConfigMap.
+	Data map[string]string
+}
+
+// resolveFileContent returns file content fetched from a referenced Secret or ConfigMap, rendering it
+// as a Go template if requested.
+func (r *KubeadmConfigReconciler) resolveFileContent(ctx context.Context, cfg *bootstrapv1.KubeadmConfig, source bootstrapv1.File) ([]byte, error) {
+	var data map[string][]byte
+	switch {
+	case source.ContentFrom.Secret != nil:
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: cfg.Namespace, Name: source.ContentFrom.Secret.Name}
+		if err := r.Client.Get(ctx, key, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "secret not found: %s", key)
+			}
+			return nil, errors.Wrapf(err, "failed to retrieve Secret %q", key)
+		}
+		if _, ok := secret.Data[source.ContentFrom.Secret.Key]; !ok {
+			return nil, errors.Errorf("secret references non-existent secret key: %q", source.ContentFrom.Secret.Key)
+		}
+		data = secret.Data
+		if !source.ContentFrom.Template {
+			return data[source.ContentFrom.Secret.Key], nil
+		}
+		return renderFileTemplate(cfg, data[source.ContentFrom.Secret.Key], data)
+	case source.ContentFrom.ConfigMap != nil:
+		configMap := &corev1.ConfigMap{}
+		key := types.NamespacedName{Namespace: cfg.Namespace, Name: source.ContentFrom.ConfigMap.Name}
+		if err := r.Client.Get(ctx, key, configMap); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "configmap not found: %s", key)
+			}
+			return nil, errors.Wrapf(err, "failed to retrieve ConfigMap %q", key)
+		}
+		data = make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+		for k, v := range configMap.Data {
+			data[k] = []byte(v)
+		}
+		for k, v := range configMap.BinaryData {
+			data[k] = v
+		}
+		if _, ok := data[source.ContentFrom.ConfigMap.Key]; !ok {
+			return nil, errors.Errorf("configmap references non-existent configmap key: %q", source.ContentFrom.ConfigMap.Key)
+		}
+		if !source.ContentFrom.Template {
+			return data[source.ContentFrom.ConfigMap.Key], nil
+		}
+		return renderFileTemplate(cfg, data[source.ContentFrom.ConfigMap.Key], data)
+	default:
+		return nil, errors.Errorf("file source for %q must reference a Secret or a ConfigMap", source.Path)
+	}
+}
+
+// renderFileTemplate renders the content of a file populated from a Secret or a ConfigMap as a Go template.
+func renderFileTemplate(cfg *bootstrapv1.KubeadmConfig, content []byte, data map[string][]byte) ([]byte, error) {
+	tpl, err := template.New("file").Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse file template")
+	}
+
+	templateData := fileTemplateData{
+		ClusterName: cfg.Labels[clusterv1.ClusterNameLabel],
+		Namespace:   cfg.Namespace,
+		Data:        make(map[string]string, len(data)),
+	}
+	for k, v := range data {
+		templateData.Data[k] = string(v)
+	}
+
+	var out bytes.Buffer
+	if err := tpl.Execute(&out, templateData); err != nil {
+		return nil, errors.Wrap(err, "failed to render file template")
+	}
+	return out.Bytes(), nil
+}
+
+// fileSourcesChecksum returns the checksum of the content of the files populated from Secrets or ConfigMaps,
+// or an empty string if there are no such files; resolved must be the result of resolveFiles for files.
+func fileSourcesChecksum(files, resolved []bootstrapv1.File) string {
+	hasher := sha256.New()
+	found := false
+	for i := range files {
+		if files[i].ContentFrom == nil {
+			continue
+		}
+		found = true
+		_, _ = hasher.Write([]byte(resolved[i].Path))
+		_, _ = hasher.Write([]byte{0})
+		_, _ = hasher.Write([]byte(resolved[i].Content))
+		_, _ = hasher.Write([]byte{0})
+	}
+	if !found {
+		return ""
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// setFileSourcesChecksum records the checksum of the content of the files populated from Secrets or ConfigMaps
+// used for generating the bootstrap data.
+func setFileSourcesChecksum(cfg *bootstrapv1.KubeadmConfig, resolved []bootstrapv1.File) {
+	checksum := fileSourcesChecksum(cfg.Spec.Files, resolved)
+	if checksum == "" {
+		delete(cfg.Annotations, bootstrapv1.FileSourcesChecksumAnnotation)
+		conditions.Delete(cfg, bootstrapv1.FileSourcesUpToDateCondition)
+		return
+	}
+	if cfg.Annotations == nil {
+		cfg.Annotations = map[string]string{}
+	}
+	cfg.Annotations[bootstrapv1.FileSourcesChecksumAnnotation] = checksum
+	conditions.MarkTrue(cfg, bootstrapv1.FileSourcesUpToDateCondition)
+}
+
+// reconcileFileSources checks if the content of the files populated from Secrets or ConfigMaps changed after the
+// bootstrap data has been generated, and surfaces it in the FileSourcesUpToDate condition.
+func (r *KubeadmConfigReconciler) reconcileFileSources(ctx context.Context, cfg *bootstrapv1.KubeadmConfig) {
+	log := ctrl.LoggerFrom(ctx)
+
+	checksum, ok := cfg.Annotations[bootstrapv1.FileSourcesChecksumAnnotation]
 	if !ok {
-		return nil, errors.Errorf("secret references non-existent secret key: %q", source.ContentFrom.Secret.Key)
+		return
 	}
-	return data, nil
+
+	resolved, err := r.resolveFiles(ctx, cfg)
+	if err != nil {
+		log.Error(err, "Failed to resolve files for checking changes to the referenced content")
+		conditions.MarkFalse(cfg, bootstrapv1.FileSourcesUpToDateCondition, bootstrapv1.FileSourcesUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return
+	}
+	if fileSourcesChecksum(cfg.Spec.Files, resolved) != checksum {
+		conditions.MarkFalse(cfg, bootstrapv1.FileSourcesUpToDateCondition, bootstrapv1.FileSourcesChangedReason, clusterv1.ConditionSeverityWarning,
+			"The content of files populated from Secrets or ConfigMaps changed after the bootstrap data has been generated")
+		return
+	}
+	conditions.MarkTrue(cfg, bootstrapv1.FileSourcesUpToDateCondition)
+}
+
+// rolloutMachineDeploymentForSources requests a rollout of the MachineDeployment the Machine belongs to by setting
+// spec.rolloutAfter, when the content of the files populated from Secrets or ConfigMaps, or the passwords or the ssh
+// authorized keys populated from Secrets, changed after the bootstrap data has been generated; the rollout is requested
+// only if it has not been requested after the Machine was created.
+// NOTE: KubeadmControlPlane rolls out its Machines on its own, and MachinePools are not supported.
+func (r *KubeadmConfigReconciler) rolloutMachineDeploymentForSources(ctx context.Context, cfg *bootstrapv1.KubeadmConfig, configOwner *bsutil.ConfigOwner) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	var changed string
+	switch {
+	case conditions.GetReason(cfg, bootstrapv1.FileSourcesUpToDateCondition) == bootstrapv1.FileSourcesChangedReason:
+		changed = "the content of files populated from Secrets or ConfigMaps"
+	case conditions.GetReason(cfg, bootstrapv1.UserSourcesUpToDateCondition) == bootstrapv1.UserSourcesChangedReason:
+		changed = "the passwords or the ssh authorized keys populated from Secrets"
+	default:
+		return nil
+	}
+	if configOwner.IsMachinePool() || configOwner.IsControlPlaneMachine() {
+		return nil
+	}
+	mdName, ok := configOwner.GetLabels()[clusterv1.MachineDeploymentNameLabel]
+	if !ok {
+		return nil
+	}
+
+	md := &clusterv1.MachineDeployment{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: cfg.Namespace, Name: mdName}, md); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get MachineDeployment %s", mdName)
+	}
+	if md.Spec.RolloutAfter != nil && md.Spec.RolloutAfter.After(configOwner.GetCreationTimestamp().Time) {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(md, r.Client)
+	if err != nil {
+		return err
+	}
+	md.Spec.RolloutAfter = &metav1.Time{Time: time.Now()}
+	if err := patchHelper.Patch(ctx, md); err != nil {
+		return errors.Wrapf(err, "failed to request a rollout of MachineDeployment %s", mdName)
+	}
+	log.Info(fmt.Sprintf("Requested a rollout of the MachineDeployment because %s changed", changed), "MachineDeployment", klog.KObj(md))
+	return nil
 }
 
 // resolveUsers maps .Spec.Users into cloudinit.Users, resolving any object references
@@ -782,6 +1245,14 @@ func (r *KubeadmConfigReconciler) resolveUsers(ctx context.Context, cfg *bootstr
 			passwdContent := string(data)
 			in.Passwd = &passwdContent
 		}
+		if in.SSHAuthorizedKeysFrom != nil {
+			data, err := r.resolveSecretSSHAuthorizedKeysContent(ctx, cfg.Namespace, in)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to resolve ssh authorized keys source")
+			}
+			in.SSHAuthorizedKeysFrom = nil
+			in.SSHAuthorizedKeys = append(append([]string{}, in.SSHAuthorizedKeys...), parseSSHAuthorizedKeys(data)...)
+		}
 		collected = append(collected, in)
 	}
 
@@ -805,6 +1276,136 @@ func (r *KubeadmConfigReconciler) resolveSecretPasswordContent(ctx context.Conte
 	return data, nil
 }
 
+// resolveSecretSSHAuthorizedKeysContent returns the ssh authorized keys fetched from a referenced secret object.
+func (r *KubeadmConfigReconciler) resolveSecretSSHAuthorizedKeysContent(ctx context.Context, ns string, source bootstrapv1.User) ([]byte, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: ns, Name: source.SSHAuthorizedKeysFrom.Secret.Name}
+	if err := r.Client.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "secret not found: %s", key)
+		}
+		return nil, errors.Wrapf(err, "failed to retrieve Secret %q", key)
+	}
+	data, ok := secret.Data[source.SSHAuthorizedKeysFrom.Secret.Key]
+	if !ok {
+		return nil, errors.Errorf("secret references non-existent secret key: %q", source.SSHAuthorizedKeysFrom.Secret.Key)
+	}
+	return data, nil
+}
+
+// parseSSHAuthorizedKeys returns the ssh authorized keys in data, one per line, ignoring empty lines and comments.
+func parseSSHAuthorizedKeys(data []byte) []string {
+	keys := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, line)
+	}
+	return keys
+}
+
+// userSourcesChecksum returns the checksum of the passwords and of the ssh authorized keys populated from Secrets,
+// or an empty string if there are no such users; resolved must be the result of resolveUsers for users.
+func userSourcesChecksum(users, resolved []bootstrapv1.User) string {
+	hasher := sha256.New()
+	found := false
+	for i := range users {
+		if users[i].PasswdFrom == nil && users[i].SSHAuthorizedKeysFrom == nil {
+			continue
+		}
+		found = true
+		_, _ = hasher.Write([]byte(resolved[i].Name))
+		_, _ = hasher.Write([]byte{0})
+		if users[i].PasswdFrom != nil && resolved[i].Passwd != nil {
+			_, _ = hasher.Write([]byte(*resolved[i].Passwd))
+		}
+		_, _ = hasher.Write([]byte{0})
+		if users[i].SSHAuthorizedKeysFrom != nil {
+			for _, key := range resolved[i].SSHAuthorizedKeys {
+				_, _ = hasher.Write([]byte(key))
+				_, _ = hasher.Write([]byte{0})
+			}
+		}
+	}
+	if !found {
+		return ""
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// setUserSourcesChecksum records the checksum of the passwords and of the ssh authorized keys populated from Secrets
+// used for generating the bootstrap data.
+func setUserSourcesChecksum(cfg *bootstrapv1.KubeadmConfig, resolved []bootstrapv1.User) {
+	checksum := userSourcesChecksum(cfg.Spec.Users, resolved)
+	if checksum == "" {
+		delete(cfg.Annotations, bootstrapv1.UserSourcesChecksumAnnotation)
+		conditions.Delete(cfg, bootstrapv1.UserSourcesUpToDateCondition)
+		return
+	}
+	if cfg.Annotations == nil {
+		cfg.Annotations = map[string]string{}
+	}
+	cfg.Annotations[bootstrapv1.UserSourcesChecksumAnnotation] = checksum
+	conditions.MarkTrue(cfg, bootstrapv1.UserSourcesUpToDateCondition)
+}
+
+// reconcileUserSources checks if the passwords or the ssh authorized keys populated from Secrets changed after the
+// bootstrap data has been generated, and surfaces it in the UserSourcesUpToDate condition.
+func (r *KubeadmConfigReconciler) reconcileUserSources(ctx context.Context, cfg *bootstrapv1.KubeadmConfig) {
+	log := ctrl.LoggerFrom(ctx)
+
+	checksum, ok := cfg.Annotations[bootstrapv1.UserSourcesChecksumAnnotation]
+	if !ok {
+		return
+	}
+
+	resolved, err := r.resolveUsers(ctx, cfg)
+	if err != nil {
+		log.Error(err, "Failed to resolve users for checking changes to the referenced content")
+		conditions.MarkFalse(cfg, bootstrapv1.UserSourcesUpToDateCondition, bootstrapv1.UserSourcesUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return
+	}
+	if userSourcesChecksum(cfg.Spec.Users, resolved) != checksum {
+		conditions.MarkFalse(cfg, bootstrapv1.UserSourcesUpToDateCondition, bootstrapv1.UserSourcesChangedReason, clusterv1.ConditionSeverityWarning,
+			"The passwords or the ssh authorized keys populated from Secrets changed after the bootstrap data has been generated")
+		return
+	}
+	conditions.MarkTrue(cfg, bootstrapv1.UserSourcesUpToDateCondition)
+}
+
+// BootstrapSnippetToKubeadmConfigs is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// of the KubeadmConfigs referencing a BootstrapSnippet, e.g. for generating the bootstrap data of KubeadmConfigs
+// which were waiting for the BootstrapSnippet to be created.
+// NOTE: BootstrapSnippets are resolved only when generating the bootstrap data, so KubeadmConfigs with bootstrap
+// data already generated are ignored.
+func (r *KubeadmConfigReconciler) BootstrapSnippetToKubeadmConfigs(o client.Object) []ctrl.Request {
+	snippet, ok := o.(*bootstrapv1.BootstrapSnippet)
+	if !ok {
+		panic(fmt.Sprintf("Expected a BootstrapSnippet but got a %T", o))
+	}
+
+	configList := &bootstrapv1.KubeadmConfigList{}
+	if err := r.Client.List(context.TODO(), configList, client.InNamespace(snippet.Namespace)); err != nil {
+		return nil
+	}
+
+	result := []ctrl.Request{}
+	for _, config := range configList.Items {
+		if config.Status.DataSecretName != nil {
+			continue
+		}
+		for _, ref := range config.Spec.Snippets {
+			if ref.Name == snippet.Name {
+				result = append(result, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&config)})
+				break
+			}
+		}
+	}
+	return result
+}
+
 // ClusterToKubeadmConfigs is a handler.ToRequestsFunc to be used to enqueue
 // requests for reconciliation of KubeadmConfigs.
 func (r *KubeadmConfigReconciler) ClusterToKubeadmConfigs(o client.Object) []ctrl.Request {
@@ -997,6 +1598,31 @@ func (r *KubeadmConfigReconciler) reconcileTopLevelObjectSettings(ctx context.Co
 	}
 }
 
+// setNodeAddressDefaults defaults the kubelet node-ip and, if a local API endpoint is given, the API server advertise address
+// to the first InternalIP reported by the infrastructure provider. Values provided by the user are preserved.
+func setNodeAddressDefaults(nodeRegistration *bootstrapv1.NodeRegistrationOptions, localAPIEndpoint *bootstrapv1.APIEndpoint, addresses clusterv1.MachineAddresses) {
+	nodeIP := ""
+	for _, address := range addresses {
+		if address.Type == clusterv1.MachineInternalIP {
+			nodeIP = address.Address
+			break
+		}
+	}
+	if nodeIP == "" {
+		return
+	}
+
+	if _, ok := nodeRegistration.KubeletExtraArgs["node-ip"]; !ok {
+		if nodeRegistration.KubeletExtraArgs == nil {
+			nodeRegistration.KubeletExtraArgs = map[string]string{}
+		}
+		nodeRegistration.KubeletExtraArgs["node-ip"] = nodeIP
+	}
+	if localAPIEndpoint != nil && localAPIEndpoint.AdvertiseAddress == "" {
+		localAPIEndpoint.AdvertiseAddress = nodeIP
+	}
+}
+
 // storeBootstrapData creates a new secret with the data passed in as input,
 // sets the reference in the configuration status and ready to true.
 func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope *Scope, data []byte) error {
@@ -1039,6 +1665,8 @@ func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope
 	}
 	scope.Config.Status.DataSecretName = pointer.String(secret.Name)
 	scope.Config.Status.Ready = true
+	// Record that the bootstrap data has been generated for the current re-bootstrap request, if any.
+	annotations.MarkRebootstrapObserved(scope.Config)
 	conditions.MarkTrue(scope.Config, bootstrapv1.DataSecretAvailableCondition)
 	return nil
 }
@@ -0,0 +1,352 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"net"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	bootstraputil "k8s.io/cluster-bootstrap/token/util"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	bsutil "sigs.k8s.io/cluster-api/bootstrap/util"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	bootstrapProgressScriptPath        = "/usr/local/bin/cluster-api-bootstrap-progress"
+	bootstrapProgressWindowsScriptPath = "/run/cluster-api/bootstrap-progress.ps1"
+	bootstrapProgressCACertPath        = "/run/cluster-api/bootstrap-progress-ca.crt"
+
+	bootstrapProgressPhaseKey     = "phase"
+	bootstrapProgressHeartbeatKey = "lastHeartbeatTime"
+
+	// bootstrapHeartbeatInterval is the interval machines report the current phase of the bootstrap process at.
+	bootstrapHeartbeatInterval = 30 * time.Second
+
+	// bootstrapHeartbeatTimeout is the time after which the last heartbeat of a machine not completing the bootstrap
+	// process is considered stale.
+	bootstrapHeartbeatTimeout = 5 * time.Minute
+
+	// bootstrapProgressCheckInterval is the interval used for checking the progress of the bootstrap process.
+	bootstrapProgressCheckInterval = 30 * time.Second
+)
+
+var (
+	//go:embed bootstrap-progress.sh
+	bootstrapProgressScript string
+
+	//go:embed bootstrap-progress.ps1
+	bootstrapProgressWindowsScript string
+)
+
+// bootstrapProgressScriptInput is the context used to generate the script reporting the progress of the bootstrap process.
+type bootstrapProgressScriptInput struct {
+	ScriptPath        string
+	CACertPath        string
+	Token             string
+	Endpoint          string
+	ConfigMapName     string
+	HeartbeatInterval string
+	JoinPermittedKey  string
+}
+
+// reportsBootstrapProgress returns true if the bootstrap data of a KubeadmConfig reports the progress of the bootstrap
+// process; this requires a join using a bootstrap token, and it is not supported for MachinePools.
+func reportsBootstrapProgress(config *bootstrapv1.KubeadmConfig, configOwner *bsutil.ConfigOwner) bool {
+	if _, ok := config.Annotations[bootstrapv1.ReportBootstrapProgressAnnotation]; !ok {
+		return false
+	}
+	return !configOwner.IsMachinePool() &&
+		config.Spec.JoinConfiguration != nil &&
+		config.Spec.JoinConfiguration.Discovery.BootstrapToken != nil
+}
+
+func bootstrapProgressConfigMapName(config *bootstrapv1.KubeadmConfig) string {
+	return bootstrapv1.BootstrapProgressConfigMapNamePrefix + config.Name
+}
+
+// bootstrapProgressBootstrapData returns the files and the commands reporting the progress of the bootstrap process,
+// wrapping the given pre and post kubeadm commands.
+func bootstrapProgressBootstrapData(scope *Scope, caCert []byte, files []bootstrapv1.File, preKubeadmCommands, postKubeadmCommands []string) ([]bootstrapv1.File, []string, []string, error) {
+	input := bootstrapProgressScriptInput{
+		ScriptPath:        bootstrapProgressScriptPath,
+		CACertPath:        bootstrapProgressCACertPath,
+		Token:             scope.Config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token,
+		Endpoint:          net.JoinHostPort(scope.Cluster.Spec.ControlPlaneEndpoint.Host, strconv.Itoa(int(scope.Cluster.Spec.ControlPlaneEndpoint.Port))),
+		ConfigMapName:     bootstrapProgressConfigMapName(scope.Config),
+		HeartbeatInterval: strconv.Itoa(int(bootstrapHeartbeatInterval.Seconds())),
+		JoinPermittedKey:  bootstrapv1.JoinPermittedBootstrapProgressKey,
+	}
+	script, permissions := bootstrapProgressScript, "0700"
+	reportCommand := func(phase string) string {
+		return fmt.Sprintf("%s %s", bootstrapProgressScriptPath, phase)
+	}
+	if scope.Config.Spec.OSFamily == bootstrapv1.WindowsOSFamily {
+		input.ScriptPath = bootstrapProgressWindowsScriptPath
+		script, permissions = bootstrapProgressWindowsScript, ""
+		reportCommand = func(phase string) string {
+			return fmt.Sprintf("& '%s' -Phase %s", bootstrapProgressWindowsScriptPath, phase)
+		}
+	}
+
+	tpl, err := template.New("BootstrapProgress").Parse(script)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to parse bootstrap progress script template")
+	}
+	var content bytes.Buffer
+	if err := tpl.Execute(&content, input); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to generate bootstrap progress script")
+	}
+
+	files = append(files,
+		bootstrapv1.File{
+			Path:        input.ScriptPath,
+			Owner:       "root:root",
+			Permissions: permissions,
+			Content:     content.String(),
+		},
+		bootstrapv1.File{
+			Path:        bootstrapProgressCACertPath,
+			Owner:       "root:root",
+			Permissions: "0644",
+			Content:     string(caCert),
+		},
+	)
+
+	pre := []string{reportCommand(bootstrapv1.RunningPreKubeadmCommandsBootstrapPhase)}
+	pre = append(pre, preKubeadmCommands...)
+	if _, ok := scope.Config.Annotations[bootstrapv1.WaitForJoinPermitAnnotation]; ok {
+		pre = append(pre, reportCommand(bootstrapv1.WaitingForJoinPermitBootstrapPhase))
+	}
+	pre = append(pre, reportCommand(bootstrapv1.RunningKubeadmBootstrapPhase))
+
+	post := []string{reportCommand(bootstrapv1.RunningPostKubeadmCommandsBootstrapPhase)}
+	post = append(post, postKubeadmCommands...)
+	post = append(post, reportCommand(bootstrapv1.WaitingForNodeBootstrapPhase))
+
+	return files, pre, post, nil
+}
+
+// setupBootstrapProgress prepares the workload cluster for the machine to report the progress of the bootstrap process,
+// and returns the files and the commands doing so, wrapping the given pre and post kubeadm commands.
+func (r *KubeadmConfigReconciler) setupBootstrapProgress(ctx context.Context, scope *Scope, caCert []byte, files []bootstrapv1.File, preKubeadmCommands, postKubeadmCommands []string) ([]bootstrapv1.File, []string, []string, error) {
+	if err := r.reconcileBootstrapProgressConfigMap(ctx, scope); err != nil {
+		return nil, nil, nil, err
+	}
+	scope.Config.Status.BootstrapProgress = nil
+	setBootstrapHeartbeatCondition(scope.Config, time.Now())
+
+	return bootstrapProgressBootstrapData(scope, caCert, files, preKubeadmCommands, postKubeadmCommands)
+}
+
+// bootstrapProgressUser returns the user the machine authenticates as when reporting the progress of the bootstrap
+// process, i.e. the user of the bootstrap token used for joining the machine.
+func bootstrapProgressUser(config *bootstrapv1.KubeadmConfig) (string, error) {
+	substrs := bootstraputil.BootstrapTokenRegexp.FindStringSubmatch(config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token)
+	if len(substrs) != 3 {
+		return "", errors.New("failed to parse the bootstrap token used for joining the machine")
+	}
+	return bootstrapapi.BootstrapUserPrefix + substrs[1], nil
+}
+
+// reconcileBootstrapProgressConfigMap creates the ConfigMap where the machine reports the progress of the bootstrap
+// process, together with the Role and the RoleBinding allowing only the bootstrap token of the machine to update it;
+// the ConfigMap is reset if it already exists, e.g. when re-bootstrapping a Machine.
+func (r *KubeadmConfigReconciler) reconcileBootstrapProgressConfigMap(ctx context.Context, scope *Scope) error {
+	user, err := bootstrapProgressUser(scope.Config)
+	if err != nil {
+		return err
+	}
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(scope.Cluster))
+	if err != nil {
+		return errors.Wrap(err, "failed to get remote client")
+	}
+
+	name := bootstrapProgressConfigMapName(scope.Config)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel: scope.Cluster.Name,
+			},
+		},
+	}
+	if err := remoteClient.Create(ctx, configMap); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create bootstrap progress ConfigMap %s", name)
+		}
+		if err := remoteClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap); err != nil {
+			return errors.Wrapf(err, "failed to get bootstrap progress ConfigMap %s", name)
+		}
+		configMap.Data = nil
+		if err := remoteClient.Update(ctx, configMap); err != nil {
+			return errors.Wrapf(err, "failed to reset bootstrap progress ConfigMap %s", name)
+		}
+	}
+
+	// The Role and the RoleBinding are owned by the ConfigMap, so they are deleted together with it.
+	ownerRef := metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       configMap.Name,
+		UID:        configMap.UID,
+		Controller: pointer.Bool(true),
+	}
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       metav1.NamespaceSystem,
+			OwnerReferences: []metav1.OwnerReference{ownerRef},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{""},
+				Resources:     []string{"configmaps"},
+				ResourceNames: []string{name},
+				Verbs:         []string{"get", "patch"},
+			},
+		},
+	}
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       metav1.NamespaceSystem,
+			OwnerReferences: []metav1.OwnerReference{ownerRef},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.UserKind,
+				Name:     user,
+			},
+		},
+	}
+	if err := remoteClient.Create(ctx, role); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create bootstrap progress Role %s", name)
+	}
+	if err := remoteClient.Create(ctx, roleBinding); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create bootstrap progress RoleBinding %s", name)
+		}
+		// The RoleBinding already exists, e.g. when re-bootstrapping a Machine; make sure it refers
+		// to the bootstrap token currently used by the machine.
+		subjects := roleBinding.Subjects
+		if err := remoteClient.Get(ctx, client.ObjectKeyFromObject(roleBinding), roleBinding); err != nil {
+			return errors.Wrapf(err, "failed to get bootstrap progress RoleBinding %s", name)
+		}
+		roleBinding.Subjects = subjects
+		if err := remoteClient.Update(ctx, roleBinding); err != nil {
+			return errors.Wrapf(err, "failed to update bootstrap progress RoleBinding %s", name)
+		}
+	}
+	return nil
+}
+
+// reconcileBootstrapProgress surfaces the progress of the bootstrap process reported by the machine in the
+// KubeadmConfig status.
+func (r *KubeadmConfigReconciler) reconcileBootstrapProgress(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster) error {
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return errors.Wrap(err, "failed to get remote client")
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: bootstrapProgressConfigMapName(config)}
+	if err := remoteClient.Get(ctx, key, configMap); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get bootstrap progress ConfigMap %s", key.Name)
+	}
+
+	config.Status.BootstrapProgress = nil
+	if phase := configMap.Data[bootstrapProgressPhaseKey]; phase != "" {
+		config.Status.BootstrapProgress = &clusterv1.MachineBootstrapProgress{Phase: phase}
+		if heartbeat, err := time.Parse(time.RFC3339, configMap.Data[bootstrapProgressHeartbeatKey]); err == nil {
+			config.Status.BootstrapProgress.LastHeartbeatTime = &metav1.Time{Time: heartbeat}
+		}
+	}
+	setBootstrapHeartbeatCondition(config, time.Now())
+	return nil
+}
+
+// setBootstrapHeartbeatCondition sets the BootstrapHeartbeat condition according to the progress of the bootstrap
+// process reported by the machine.
+func setBootstrapHeartbeatCondition(config *bootstrapv1.KubeadmConfig, now time.Time) {
+	progress := config.Status.BootstrapProgress
+	switch {
+	case progress == nil:
+		conditions.MarkFalse(config, bootstrapv1.BootstrapHeartbeatCondition, bootstrapv1.WaitingForBootstrapHeartbeatReason, clusterv1.ConditionSeverityInfo, "")
+	case progress.Phase == bootstrapv1.KubeadmFailedBootstrapPhase:
+		conditions.MarkFalse(config, bootstrapv1.BootstrapHeartbeatCondition, bootstrapv1.BootstrapFailedReason, clusterv1.ConditionSeverityError, "kubeadm join failed on the machine")
+	case progress.Phase == bootstrapv1.WaitingForNodeBootstrapPhase:
+		conditions.MarkTrue(config, bootstrapv1.BootstrapHeartbeatCondition)
+	case progress.LastHeartbeatTime == nil || now.Sub(progress.LastHeartbeatTime.Time) > bootstrapHeartbeatTimeout:
+		lastHeartbeat := "never"
+		if progress.LastHeartbeatTime != nil {
+			lastHeartbeat = progress.LastHeartbeatTime.UTC().Format(time.RFC3339)
+		}
+		conditions.MarkFalse(config, bootstrapv1.BootstrapHeartbeatCondition, bootstrapv1.BootstrapHeartbeatStaleReason, clusterv1.ConditionSeverityWarning,
+			"The machine stopped reporting the progress of the bootstrap process in phase %s, last heartbeat: %s", progress.Phase, lastHeartbeat)
+	default:
+		conditions.MarkTrue(config, bootstrapv1.BootstrapHeartbeatCondition)
+	}
+}
+
+// deleteBootstrapProgress deletes the ConfigMap where the machine reported the progress of the bootstrap process,
+// and removes the progress from the KubeadmConfig status; this is done once the machine has a Node.
+func (r *KubeadmConfigReconciler) deleteBootstrapProgress(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster) error {
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return errors.Wrap(err, "failed to get remote client")
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapProgressConfigMapName(config),
+			Namespace: metav1.NamespaceSystem,
+		},
+	}
+	if err := remoteClient.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete bootstrap progress ConfigMap %s", configMap.Name)
+	}
+
+	config.Status.BootstrapProgress = nil
+	conditions.Delete(config, bootstrapv1.BootstrapHeartbeatCondition)
+	return nil
+}
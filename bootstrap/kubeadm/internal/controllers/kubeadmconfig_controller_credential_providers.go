@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+const (
+	defaultKubeletCredentialProvidersBinDir     = "/etc/kubernetes/credential-providers"
+	defaultKubeletCredentialProvidersConfigPath = "/etc/kubernetes/credential-provider-config.yaml"
+	defaultKubeletCredentialProviderCacheTime   = 5 * time.Minute
+)
+
+var (
+	// kubeletCredentialProvidersV1Version is the first Kubernetes version supporting the v1 CredentialProviderConfig API.
+	kubeletCredentialProvidersV1Version = semver.MustParse("1.26.0")
+
+	// kubeletCredentialProvidersV1Beta1Version is the first Kubernetes version supporting the v1beta1 CredentialProviderConfig API;
+	// it is also the first version enabling the KubeletCredentialProviders feature gate by default.
+	kubeletCredentialProvidersV1Beta1Version = semver.MustParse("1.24.0")
+)
+
+// credentialProviderConfig is the kubelet CredentialProviderConfig file.
+type credentialProviderConfig struct {
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Providers  []credentialProvider `json:"providers"`
+}
+
+type credentialProvider struct {
+	Name                 string                                        `json:"name"`
+	MatchImages          []string                                      `json:"matchImages"`
+	DefaultCacheDuration metav1.Duration                               `json:"defaultCacheDuration"`
+	APIVersion           string                                        `json:"apiVersion"`
+	Args                 []string                                      `json:"args,omitempty"`
+	Env                  []bootstrapv1.KubeletCredentialProviderEnvVar `json:"env,omitempty"`
+}
+
+// kubeletCredentialProvidersBootstrapData returns the files and the commands installing the kubelet credential providers
+// on the machine; the returned commands must be run before the kubeadm commands.
+func kubeletCredentialProvidersBootstrapData(providers *bootstrapv1.KubeletCredentialProviders, version semver.Version) ([]bootstrapv1.File, []string, error) {
+	if providers == nil {
+		return nil, nil, nil
+	}
+
+	configAPIVersion, providerAPIVersion := kubeletCredentialProviderAPIVersions(version)
+	config := credentialProviderConfig{
+		APIVersion: configAPIVersion,
+		Kind:       "CredentialProviderConfig",
+	}
+	binDir := kubeletCredentialProvidersBinDir(providers)
+	commands := []string{}
+	for _, provider := range providers.Providers {
+		p := credentialProvider{
+			Name:                 provider.Name,
+			MatchImages:          provider.MatchImages,
+			DefaultCacheDuration: metav1.Duration{Duration: defaultKubeletCredentialProviderCacheTime},
+			APIVersion:           provider.APIVersion,
+			Args:                 provider.Args,
+			Env:                  provider.Env,
+		}
+		if provider.DefaultCacheDuration != nil {
+			p.DefaultCacheDuration = *provider.DefaultCacheDuration
+		}
+		if p.APIVersion == "" {
+			p.APIVersion = providerAPIVersion
+		}
+		config.Providers = append(config.Providers, p)
+
+		if provider.Binary != nil {
+			binary := path.Join(binDir, provider.Name)
+			commands = append(commands,
+				fmt.Sprintf("curl -fsSL --retry 5 -o %s %s", shellQuote(binary), shellQuote(provider.Binary.URL)),
+				fmt.Sprintf("echo %s | sha256sum -c -", shellQuote(fmt.Sprintf("%s  %s", strings.ToLower(provider.Binary.SHA256), binary))),
+				fmt.Sprintf("chmod 0755 %s", shellQuote(binary)),
+			)
+		}
+	}
+	if len(commands) > 0 {
+		commands = append([]string{fmt.Sprintf("mkdir -p %s", shellQuote(binDir))}, commands...)
+	}
+
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal kubelet CredentialProviderConfig")
+	}
+	files := []bootstrapv1.File{
+		{
+			Path:        kubeletCredentialProvidersConfigPath(providers),
+			Owner:       "root:root",
+			Permissions: "0644",
+			Content:     string(content),
+		},
+	}
+	return files, commands, nil
+}
+
+// setKubeletCredentialProvidersArgs sets the kubelet flags required for using the kubelet credential providers.
+// Values provided by the user are preserved.
+func setKubeletCredentialProvidersArgs(nodeRegistration *bootstrapv1.NodeRegistrationOptions, providers *bootstrapv1.KubeletCredentialProviders, version semver.Version) {
+	if providers == nil {
+		return
+	}
+
+	args := map[string]string{
+		"image-credential-provider-config":  kubeletCredentialProvidersConfigPath(providers),
+		"image-credential-provider-bin-dir": kubeletCredentialProvidersBinDir(providers),
+	}
+	if version.LT(kubeletCredentialProvidersV1Beta1Version) {
+		args["feature-gates"] = "KubeletCredentialProviders=true"
+	}
+
+	if nodeRegistration.KubeletExtraArgs == nil {
+		nodeRegistration.KubeletExtraArgs = map[string]string{}
+	}
+	for name, value := range args {
+		current, ok := nodeRegistration.KubeletExtraArgs[name]
+		switch {
+		case !ok:
+			nodeRegistration.KubeletExtraArgs[name] = value
+		case name == "feature-gates" && !strings.Contains(current, "KubeletCredentialProviders="):
+			nodeRegistration.KubeletExtraArgs[name] = current + "," + value
+		}
+	}
+}
+
+// kubeletCredentialProviderAPIVersions returns the apiVersion of the CredentialProviderConfig and the default
+// apiVersion of the credential providers for a Kubernetes version.
+func kubeletCredentialProviderAPIVersions(version semver.Version) (string, string) {
+	switch {
+	case version.GTE(kubeletCredentialProvidersV1Version):
+		return "kubelet.config.k8s.io/v1", "credentialprovider.kubelet.k8s.io/v1"
+	case version.GTE(kubeletCredentialProvidersV1Beta1Version):
+		return "kubelet.config.k8s.io/v1beta1", "credentialprovider.kubelet.k8s.io/v1beta1"
+	default:
+		return "kubelet.config.k8s.io/v1alpha1", "credentialprovider.kubelet.k8s.io/v1alpha1"
+	}
+}
+
+func kubeletCredentialProvidersBinDir(providers *bootstrapv1.KubeletCredentialProviders) string {
+	if providers.BinDir != "" {
+		return providers.BinDir
+	}
+	return defaultKubeletCredentialProvidersBinDir
+}
+
+func kubeletCredentialProvidersConfigPath(providers *bootstrapv1.KubeletCredentialProviders) string {
+	if providers.ConfigPath != "" {
+		return providers.ConfigPath
+	}
+	return defaultKubeletCredentialProvidersConfigPath
+}
+
+// shellQuote quotes a string so it can be safely used as a single argument of a shell command.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blang/semver"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+func TestKubeletCredentialProvidersBootstrapData(t *testing.T) {
+	providers := &bootstrapv1.KubeletCredentialProviders{
+		Providers: []bootstrapv1.KubeletCredentialProvider{
+			{
+				Name:        "ecr-credential-provider",
+				MatchImages: []string{"*.dkr.ecr.*.amazonaws.com"},
+				Args:        []string{"get-credentials"},
+				Env:         []bootstrapv1.KubeletCredentialProviderEnvVar{{Name: "AWS_PROFILE", Value: "default"}},
+				Binary: &bootstrapv1.KubeletCredentialProviderBinary{
+					URL:    "https://example.com/ecr-credential-provider",
+					SHA256: "0123456789ABCDEF0123456789abcdef0123456789abcdef0123456789abcdef",
+				},
+			},
+			{
+				Name:                 "registry-credential-provider",
+				MatchImages:          []string{"registry.example.com"},
+				DefaultCacheDuration: &metav1.Duration{Duration: time.Hour},
+				APIVersion:           "credentialprovider.kubelet.k8s.io/v1beta1",
+			},
+		},
+	}
+
+	t.Run("returns nothing if kubelet credential providers are not configured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		files, commands, err := kubeletCredentialProvidersBootstrapData(nil, semver.MustParse("1.26.0"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(files).To(BeEmpty())
+		g.Expect(commands).To(BeEmpty())
+	})
+
+	t.Run("returns the CredentialProviderConfig file and the commands installing the provider binaries", func(t *testing.T) {
+		g := NewWithT(t)
+
+		files, commands, err := kubeletCredentialProvidersBootstrapData(providers, semver.MustParse("1.26.0"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(files).To(HaveLen(1))
+		g.Expect(files[0].Path).To(Equal(defaultKubeletCredentialProvidersConfigPath))
+		g.Expect(files[0].Content).To(Equal(`apiVersion: kubelet.config.k8s.io/v1
+kind: CredentialProviderConfig
+providers:
+- apiVersion: credentialprovider.kubelet.k8s.io/v1
+  args:
+  - get-credentials
+  defaultCacheDuration: 5m0s
+  env:
+  - name: AWS_PROFILE
+    value: default
+  matchImages:
+  - '*.dkr.ecr.*.amazonaws.com'
+  name: ecr-credential-provider
+- apiVersion: credentialprovider.kubelet.k8s.io/v1beta1
+  defaultCacheDuration: 1h0m0s
+  matchImages:
+  - registry.example.com
+  name: registry-credential-provider
+`))
+		g.Expect(commands).To(Equal([]string{
+			"mkdir -p '/etc/kubernetes/credential-providers'",
+			"curl -fsSL --retry 5 -o '/etc/kubernetes/credential-providers/ecr-credential-provider' 'https://example.com/ecr-credential-provider'",
+			"echo '0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef  /etc/kubernetes/credential-providers/ecr-credential-provider' | sha256sum -c -",
+			"chmod 0755 '/etc/kubernetes/credential-providers/ecr-credential-provider'",
+		}))
+	})
+
+	t.Run("uses the CredentialProviderConfig API supported by the Kubernetes version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		files, _, err := kubeletCredentialProvidersBootstrapData(providers, semver.MustParse("1.24.3"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(files[0].Content).To(HavePrefix("apiVersion: kubelet.config.k8s.io/v1beta1\n"))
+		g.Expect(files[0].Content).To(ContainSubstring("- apiVersion: credentialprovider.kubelet.k8s.io/v1beta1\n  args:"))
+
+		files, _, err = kubeletCredentialProvidersBootstrapData(providers, semver.MustParse("1.23.0"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(files[0].Content).To(HavePrefix("apiVersion: kubelet.config.k8s.io/v1alpha1\n"))
+	})
+}
+
+func TestSetKubeletCredentialProvidersArgs(t *testing.T) {
+	providers := &bootstrapv1.KubeletCredentialProviders{
+		ConfigPath: "/etc/kubelet/credential-providers.yaml",
+		Providers: []bootstrapv1.KubeletCredentialProvider{
+			{Name: "ecr-credential-provider", MatchImages: []string{"*.dkr.ecr.*.amazonaws.com"}},
+		},
+	}
+
+	t.Run("sets the kubelet flags", func(t *testing.T) {
+		g := NewWithT(t)
+
+		nodeRegistration := &bootstrapv1.NodeRegistrationOptions{}
+		setKubeletCredentialProvidersArgs(nodeRegistration, providers, semver.MustParse("1.26.0"))
+		g.Expect(nodeRegistration.KubeletExtraArgs).To(Equal(map[string]string{
+			"image-credential-provider-config":  "/etc/kubelet/credential-providers.yaml",
+			"image-credential-provider-bin-dir": defaultKubeletCredentialProvidersBinDir,
+		}))
+	})
+
+	t.Run("enables the feature gate on Kubernetes versions not enabling it by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		nodeRegistration := &bootstrapv1.NodeRegistrationOptions{
+			KubeletExtraArgs: map[string]string{
+				"feature-gates":                    "RotateKubeletServerCertificate=true",
+				"image-credential-provider-config": "/custom/config.yaml",
+			},
+		}
+		setKubeletCredentialProvidersArgs(nodeRegistration, providers, semver.MustParse("1.23.0"))
+		g.Expect(nodeRegistration.KubeletExtraArgs).To(Equal(map[string]string{
+			"feature-gates":                     "RotateKubeletServerCertificate=true,KubeletCredentialProviders=true",
+			"image-credential-provider-config":  "/custom/config.yaml",
+			"image-credential-provider-bin-dir": defaultKubeletCredentialProvidersBinDir,
+		}))
+	})
+
+	t.Run("does nothing if kubelet credential providers are not configured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		nodeRegistration := &bootstrapv1.NodeRegistrationOptions{}
+		setKubeletCredentialProvidersArgs(nodeRegistration, nil, semver.MustParse("1.26.0"))
+		g.Expect(nodeRegistration.KubeletExtraArgs).To(BeNil())
+	})
+}
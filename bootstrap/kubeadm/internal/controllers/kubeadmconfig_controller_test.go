@@ -39,7 +39,9 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/attestation"
 	bootstrapbuilder "sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/builder"
+	bsutil "sigs.k8s.io/cluster-api/bootstrap/util"
 	fakeremote "sigs.k8s.io/cluster-api/controllers/remote/fake"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
@@ -124,6 +126,44 @@ func TestKubeadmConfigReconciler_Reconcile_ReturnEarlyIfKubeadmConfigIsReady(t *
 }
 
 // Reconcile should update owner references on bootstrap secrets on creation and update.
+// Reconcile returns early and requeues at the pause expiry if the Cluster is paused, and reports the Paused condition.
+func TestKubeadmConfigReconciler_Reconcile_ReturnEarlyIfClusterIsPaused(t *testing.T) {
+	g := NewWithT(t)
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+	cluster.Status.InfrastructureReady = true
+	cluster.Spec.Pause = &clusterv1.ClusterPause{Reason: "maintenance", ExpiresAt: &metav1.Time{Time: time.Now().Add(time.Hour)}}
+	machine := builder.Machine(metav1.NamespaceDefault, "m1").WithClusterName("cluster1").Build()
+	config := newKubeadmConfig(metav1.NamespaceDefault, "cfg")
+	addKubeadmConfigToMachine(config, machine)
+
+	objects := []client.Object{
+		cluster,
+		machine,
+		config,
+	}
+	myclient := fake.NewClientBuilder().WithObjects(objects...).Build()
+
+	k := &KubeadmConfigReconciler{
+		Client: myclient,
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "cfg",
+		},
+	}
+	result, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically(">", 59*time.Minute))
+	g.Expect(result.RequeueAfter).To(BeNumerically("<=", time.Hour))
+
+	gotConfig := &bootstrapv1.KubeadmConfig{}
+	g.Expect(myclient.Get(ctx, request.NamespacedName, gotConfig)).To(Succeed())
+	g.Expect(conditions.IsTrue(gotConfig, clusterv1.PausedCondition)).To(BeTrue())
+	g.Expect(gotConfig.Status.Ready).To(BeFalse())
+}
+
 func TestKubeadmConfigReconciler_TestSecretOwnerReferenceReconciliation(t *testing.T) {
 	g := NewWithT(t)
 
@@ -708,6 +748,276 @@ func TestReconcileIfJoinCertificatesAvailableConditioninNodesAndControlPlaneIsRe
 	}
 }
 
+func TestKubeadmConfigReconciler_Reconcile_WaitForInfrastructureAddresses(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster").Build()
+	cluster.Status.InfrastructureReady = true
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	machine := newControlPlaneMachine(cluster, "control-plane-join-machine")
+	config := newControlPlaneJoinKubeadmConfig(machine.Namespace, "control-plane-join-cfg")
+	config.Annotations = map[string]string{bootstrapv1.WaitForInfrastructureAddressesAnnotation: ""}
+	addKubeadmConfigToMachine(config, machine)
+
+	objects := []client.Object{
+		cluster,
+		machine,
+		config,
+	}
+	objects = append(objects, createSecrets(t, cluster, config)...)
+	myclient := fake.NewClientBuilder().WithObjects(objects...).Build()
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		remoteClientGetter: fakeremote.NewClusterClient,
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKeyFromObject(config),
+	}
+
+	// The bootstrap data is not generated until the Machine reports addresses.
+	result, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{}))
+
+	cfg, err := getKubeadmConfig(myclient, config.Name, metav1.NamespaceDefault)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeFalse())
+	g.Expect(cfg.Status.DataSecretName).To(BeNil())
+	assertHasFalseCondition(g, myclient, request, bootstrapv1.DataSecretAvailableCondition, clusterv1.ConditionSeverityInfo, bootstrapv1.WaitingForInfrastructureAddressesReason)
+
+	// Once the addresses are reported, the bootstrap data is generated using the InternalIP.
+	machine.Status.Addresses = clusterv1.MachineAddresses{
+		{Type: clusterv1.MachineExternalIP, Address: "1.2.3.4"},
+		{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+	}
+	g.Expect(myclient.Status().Update(ctx, machine)).To(Succeed())
+
+	result, err = k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{}))
+
+	cfg, err = getKubeadmConfig(myclient, config.Name, metav1.NamespaceDefault)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+	assertHasTrueCondition(g, myclient, request, bootstrapv1.DataSecretAvailableCondition)
+
+	// The node addresses are defaulted only in the bootstrap data, not in the KubeadmConfig.
+	g.Expect(cfg.Spec.JoinConfiguration.NodeRegistration.KubeletExtraArgs).To(BeEmpty())
+	g.Expect(cfg.Spec.JoinConfiguration.ControlPlane.LocalAPIEndpoint.AdvertiseAddress).To(BeEmpty())
+
+	s := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: *cfg.Status.DataSecretName}, s)).To(Succeed())
+	g.Expect(string(s.Data["value"])).To(ContainSubstring("node-ip: 10.0.0.1"))
+	g.Expect(string(s.Data["value"])).To(ContainSubstring("advertiseAddress: 10.0.0.1"))
+}
+
+type fakeAttestationVerifier struct {
+	result *attestation.Result
+	err    error
+}
+
+func (v *fakeAttestationVerifier) Verify(_ context.Context, _ *attestation.Request) (*attestation.Result, error) {
+	return v.result, v.err
+}
+
+func TestKubeadmConfigReconciler_Reconcile_WaitForAttestation(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster").Build()
+	cluster.Status.InfrastructureReady = true
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	machine := newControlPlaneMachine(cluster, "control-plane-join-machine")
+	config := newControlPlaneJoinKubeadmConfig(machine.Namespace, "control-plane-join-cfg")
+	addKubeadmConfigToMachine(config, machine)
+
+	objects := []client.Object{
+		cluster,
+		machine,
+		config,
+	}
+	objects = append(objects, createSecrets(t, cluster, config)...)
+	myclient := fake.NewClientBuilder().WithObjects(objects...).Build()
+	verifier := &fakeAttestationVerifier{}
+	k := &KubeadmConfigReconciler{
+		Client:              myclient,
+		KubeadmInitLock:     &myInitLocker{},
+		AttestationVerifier: verifier,
+		remoteClientGetter:  fakeremote.NewClusterClient,
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKeyFromObject(config),
+	}
+
+	// The bootstrap data is not generated if the attestation fails.
+	verifier.err = fmt.Errorf("attestation service unavailable")
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).To(HaveOccurred())
+	assertHasFalseCondition(g, myclient, request, bootstrapv1.DataSecretAvailableCondition, clusterv1.ConditionSeverityWarning, bootstrapv1.AttestationFailedReason)
+
+	// The bootstrap data is not generated until the attestation is verified.
+	verifier.err = nil
+	verifier.result = &attestation.Result{Verified: false, Message: "waiting for evidence", RequeueAfter: time.Minute}
+	result, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+
+	cfg, err := getKubeadmConfig(myclient, config.Name, metav1.NamespaceDefault)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeFalse())
+	g.Expect(cfg.Status.DataSecretName).To(BeNil())
+	assertHasFalseCondition(g, myclient, request, bootstrapv1.DataSecretAvailableCondition, clusterv1.ConditionSeverityInfo, bootstrapv1.WaitingForAttestationReason)
+
+	// Once the attestation is verified, the bootstrap data is generated.
+	verifier.result = &attestation.Result{Verified: true}
+	_, err = k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err = getKubeadmConfig(myclient, config.Name, metav1.NamespaceDefault)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+	assertHasTrueCondition(g, myclient, request, bootstrapv1.DataSecretAvailableCondition)
+}
+
+func TestKubeadmConfigReconciler_Reconcile_Rebootstrap(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster").Build()
+	cluster.Status.InfrastructureReady = true
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	machine := newWorkerMachineForCluster(cluster)
+	config := newWorkerJoinKubeadmConfig(metav1.NamespaceDefault, "worker-join-cfg")
+	addKubeadmConfigToMachine(config, machine)
+
+	objects := []client.Object{
+		cluster,
+		machine,
+		config,
+	}
+	objects = append(objects, createSecrets(t, cluster, config)...)
+	myclient := fake.NewClientBuilder().WithObjects(objects...).Build()
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		remoteClientGetter: fakeremote.NewClusterClient,
+		TokenTTL:           DefaultTokenTTL,
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: config.GetNamespace(),
+			Name:      "worker-join-cfg",
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, "worker-join-cfg", metav1.NamespaceDefault)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	token := cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
+	g.Expect(token).NotTo(BeEmpty())
+
+	// Simulate the bootstrap data being consumed, then the token being garbage collected in the workload cluster.
+	tokens := &corev1.SecretList{}
+	g.Expect(myclient.List(ctx, tokens, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+	g.Expect(tokens.Items).To(HaveLen(1))
+	g.Expect(myclient.Delete(ctx, &tokens.Items[0])).To(Succeed())
+
+	bootstrapData := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: *cfg.Status.DataSecretName}, bootstrapData)).To(Succeed())
+	bootstrapData.Data["value"] = []byte("stale")
+	g.Expect(myclient.Update(ctx, bootstrapData)).To(Succeed())
+
+	// Request a re-bootstrap.
+	cfg.Annotations = map[string]string{clusterv1.RebootstrapAnnotation: "1"}
+	g.Expect(myclient.Update(ctx, cfg)).To(Succeed())
+
+	_, err = k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err = getKubeadmConfig(myclient, "worker-join-cfg", metav1.NamespaceDefault)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	g.Expect(cfg.Annotations).To(HaveKeyWithValue(clusterv1.ObservedRebootstrapAnnotation, "1"))
+	g.Expect(cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token).NotTo(Equal(token))
+	g.Expect(myclient.Get(ctx, client.ObjectKeyFromObject(bootstrapData), bootstrapData)).To(Succeed())
+	g.Expect(string(bootstrapData.Data["value"])).To(ContainSubstring(cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token))
+
+	// The bootstrap data is not re-generated again for the same request.
+	bootstrapData.Data["value"] = []byte("unchanged")
+	g.Expect(myclient.Update(ctx, bootstrapData)).To(Succeed())
+
+	_, err = k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(myclient.Get(ctx, client.ObjectKeyFromObject(bootstrapData), bootstrapData)).To(Succeed())
+	g.Expect(string(bootstrapData.Data["value"])).To(Equal("unchanged"))
+}
+
+func TestSetNodeAddressDefaults(t *testing.T) {
+	addresses := clusterv1.MachineAddresses{
+		{Type: clusterv1.MachineHostName, Address: "node"},
+		{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+		{Type: clusterv1.MachineInternalIP, Address: "10.0.0.2"},
+	}
+
+	tests := []struct {
+		name                 string
+		nodeRegistration     bootstrapv1.NodeRegistrationOptions
+		localAPIEndpoint     *bootstrapv1.APIEndpoint
+		addresses            clusterv1.MachineAddresses
+		wantKubeletExtraArgs map[string]string
+		wantLocalAPIEndpoint *bootstrapv1.APIEndpoint
+	}{
+		{
+			name:                 "no addresses",
+			localAPIEndpoint:     &bootstrapv1.APIEndpoint{},
+			wantLocalAPIEndpoint: &bootstrapv1.APIEndpoint{},
+		},
+		{
+			name:                 "defaults from the first InternalIP",
+			localAPIEndpoint:     &bootstrapv1.APIEndpoint{},
+			addresses:            addresses,
+			wantKubeletExtraArgs: map[string]string{"node-ip": "10.0.0.1"},
+			wantLocalAPIEndpoint: &bootstrapv1.APIEndpoint{AdvertiseAddress: "10.0.0.1"},
+		},
+		{
+			name:                 "defaults only the node-ip without a local API endpoint",
+			addresses:            addresses,
+			wantKubeletExtraArgs: map[string]string{"node-ip": "10.0.0.1"},
+		},
+		{
+			name: "preserves values provided by the user",
+			nodeRegistration: bootstrapv1.NodeRegistrationOptions{
+				KubeletExtraArgs: map[string]string{"node-ip": "10.0.0.3"},
+			},
+			localAPIEndpoint:     &bootstrapv1.APIEndpoint{AdvertiseAddress: "10.0.0.3"},
+			addresses:            addresses,
+			wantKubeletExtraArgs: map[string]string{"node-ip": "10.0.0.3"},
+			wantLocalAPIEndpoint: &bootstrapv1.APIEndpoint{AdvertiseAddress: "10.0.0.3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			setNodeAddressDefaults(&tt.nodeRegistration, tt.localAPIEndpoint, tt.addresses)
+			g.Expect(tt.nodeRegistration.KubeletExtraArgs).To(Equal(tt.wantKubeletExtraArgs))
+			g.Expect(tt.localAPIEndpoint).To(Equal(tt.wantLocalAPIEndpoint))
+		})
+	}
+}
+
 func TestReconcileIfJoinNodePoolsAndControlPlaneIsReady(t *testing.T) {
 	_ = feature.MutableGates.Set("MachinePool=true")
 
@@ -907,6 +1217,81 @@ func TestBootstrapDataFormat(t *testing.T) {
 	}
 }
 
+func TestKubeadmConfigReconciler_Reconcile_WindowsOSFamily(t *testing.T) {
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster").Build()
+	cluster.Status.InfrastructureReady = true
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	t.Run("generates a PowerShell script for a worker node", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := newWorkerMachineForCluster(cluster)
+		config := newWorkerJoinKubeadmConfig(metav1.NamespaceDefault, "worker-join-cfg")
+		config.Spec.OSFamily = bootstrapv1.WindowsOSFamily
+		config.Spec.PreKubeadmCommands = []string{"Start-Service containerd"}
+		addKubeadmConfigToMachine(config, machine)
+
+		objects := []client.Object{
+			cluster,
+			machine,
+			config,
+		}
+		objects = append(objects, createSecrets(t, cluster, config)...)
+		myclient := fake.NewClientBuilder().WithObjects(objects...).Build()
+		k := &KubeadmConfigReconciler{
+			Client:             myclient,
+			KubeadmInitLock:    &myInitLocker{},
+			remoteClientGetter: fakeremote.NewClusterClient,
+		}
+
+		request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(config)}
+		_, err := k.Reconcile(ctx, request)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		cfg, err := getKubeadmConfig(myclient, config.Name, metav1.NamespaceDefault)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(cfg.Status.Ready).To(BeTrue())
+		g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+
+		secret := &corev1.Secret{}
+		g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: *cfg.Status.DataSecretName}, secret)).To(Succeed())
+		g.Expect(string(secret.Data["value"])).To(HavePrefix("#ps1_sysnative\n"))
+		g.Expect(string(secret.Data["value"])).To(ContainSubstring("Start-Service containerd\nkubeadm join --config /run/kubeadm/kubeadm-join-config.yaml"))
+	})
+
+	t.Run("fails for a control plane node", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := newControlPlaneMachine(cluster, "control-plane-join-machine")
+		config := newControlPlaneJoinKubeadmConfig(metav1.NamespaceDefault, "control-plane-join-cfg")
+		config.Spec.OSFamily = bootstrapv1.WindowsOSFamily
+		addKubeadmConfigToMachine(config, machine)
+
+		objects := []client.Object{
+			cluster,
+			machine,
+			config,
+		}
+		objects = append(objects, createSecrets(t, cluster, config)...)
+		myclient := fake.NewClientBuilder().WithObjects(objects...).Build()
+		k := &KubeadmConfigReconciler{
+			Client:             myclient,
+			KubeadmInitLock:    &myInitLocker{},
+			remoteClientGetter: fakeremote.NewClusterClient,
+		}
+
+		request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(config)}
+		_, err := k.Reconcile(ctx, request)
+		g.Expect(err).To(HaveOccurred())
+
+		cfg, err := getKubeadmConfig(myclient, config.Name, metav1.NamespaceDefault)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(cfg.Status.Ready).To(BeFalse())
+		assertHasFalseCondition(g, myclient, request, bootstrapv1.DataSecretAvailableCondition, clusterv1.ConditionSeverityWarning, bootstrapv1.DataSecretGenerationFailedReason)
+	})
+}
+
 // during kubeadmconfig reconcile it is possible that bootstrap secret gets created
 // but kubeadmconfig is not patched, do not error if secret already exists.
 // ignore the alreadyexists error and update the status to ready.
@@ -1735,6 +2120,29 @@ func TestKubeadmConfigReconciler_Reconcile_AlwaysCheckCAVerificationUnlessReques
 
 // If a cluster object changes then all associated KubeadmConfigs should be re-reconciled.
 // This allows us to not requeue a kubeadm config while we wait for InfrastructureReady.
+func TestKubeadmConfigReconciler_BootstrapSnippetToKubeadmConfigs(t *testing.T) {
+	g := NewWithT(t)
+
+	snippet := &bootstrapv1.BootstrapSnippet{
+		ObjectMeta: metav1.ObjectMeta{Name: "snippet", Namespace: metav1.NamespaceDefault},
+	}
+	referencing := newKubeadmConfig(metav1.NamespaceDefault, "referencing")
+	referencing.Spec.Snippets = []bootstrapv1.BootstrapSnippetReference{{Name: "other"}, {Name: snippet.Name}}
+	withBootstrapData := newKubeadmConfig(metav1.NamespaceDefault, "with-bootstrap-data")
+	withBootstrapData.Spec.Snippets = []bootstrapv1.BootstrapSnippetReference{{Name: snippet.Name}}
+	withBootstrapData.Status.DataSecretName = pointer.String("with-bootstrap-data")
+	notReferencing := newKubeadmConfig(metav1.NamespaceDefault, "not-referencing")
+	otherNamespace := newKubeadmConfig("other-namespace", "referencing")
+	otherNamespace.Spec.Snippets = []bootstrapv1.BootstrapSnippetReference{{Name: snippet.Name}}
+
+	reconciler := &KubeadmConfigReconciler{
+		Client: fake.NewClientBuilder().WithObjects(referencing, withBootstrapData, notReferencing, otherNamespace).Build(),
+	}
+
+	requests := reconciler.BootstrapSnippetToKubeadmConfigs(snippet)
+	g.Expect(requests).To(ConsistOf(ctrl.Request{NamespacedName: client.ObjectKeyFromObject(referencing)}))
+}
+
 func TestKubeadmConfigReconciler_ClusterToKubeadmConfigs(t *testing.T) {
 	_ = feature.MutableGates.Set("MachinePool=true")
 	g := NewWithT(t)
@@ -1935,6 +2343,18 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 			"key": []byte("foo"),
 		},
 	}
+	testConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "source",
+		},
+		Data: map[string]string{
+			"key":      "bar",
+			"template": `cluster: {{ .ClusterName }}, key: {{ index .Data "key" }}`,
+		},
+		BinaryData: map[string][]byte{
+			"binary": []byte("baz"),
+		},
+	}
 
 	cases := map[string]struct {
 		cfg     *bootstrapv1.KubeadmConfig
@@ -1969,7 +2389,7 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 					Files: []bootstrapv1.File{
 						{
 							ContentFrom: &bootstrapv1.FileSource{
-								Secret: bootstrapv1.SecretFileSource{
+								Secret: &bootstrapv1.SecretFileSource{
 									Name: "source",
 									Key:  "key",
 								},
@@ -2003,7 +2423,7 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 						},
 						{
 							ContentFrom: &bootstrapv1.FileSource{
-								Secret: bootstrapv1.SecretFileSource{
+								Secret: &bootstrapv1.SecretFileSource{
 									Name: "source",
 									Key:  "key",
 								},
@@ -2031,6 +2451,75 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 			},
 			objects: []client.Object{testSecret},
 		},
+		"contentFrom a configMap should convert correctly": {
+			cfg: &bootstrapv1.KubeadmConfig{
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Files: []bootstrapv1.File{
+						{
+							ContentFrom: &bootstrapv1.FileSource{
+								ConfigMap: &bootstrapv1.ConfigMapFileSource{
+									Name: "source",
+									Key:  "key",
+								},
+							},
+							Path:        "/path",
+							Owner:       "root:root",
+							Permissions: "0600",
+						},
+						{
+							ContentFrom: &bootstrapv1.FileSource{
+								ConfigMap: &bootstrapv1.ConfigMapFileSource{
+									Name: "source",
+									Key:  "binary",
+								},
+							},
+							Path: "/binary",
+						},
+					},
+				},
+			},
+			expect: []bootstrapv1.File{
+				{
+					Content:     "bar",
+					Path:        "/path",
+					Owner:       "root:root",
+					Permissions: "0600",
+				},
+				{
+					Content: "baz",
+					Path:    "/binary",
+				},
+			},
+			objects: []client.Object{testConfigMap},
+		},
+		"contentFrom with template should be rendered": {
+			cfg: &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{clusterv1.ClusterNameLabel: "my-cluster"},
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Files: []bootstrapv1.File{
+						{
+							ContentFrom: &bootstrapv1.FileSource{
+								ConfigMap: &bootstrapv1.ConfigMapFileSource{
+									Name: "source",
+									Key:  "template",
+								},
+								Template: true,
+							},
+							Path: "/path",
+						},
+					},
+				},
+			},
+			expect: []bootstrapv1.File{
+				{
+					Content: "cluster: my-cluster, key: bar",
+					Path:    "/path",
+				},
+			},
+			objects: []client.Object{testConfigMap},
+		},
 	}
 
 	for name, tc := range cases {
@@ -2067,6 +2556,236 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 	}
 }
 
+func TestKubeadmConfigReconciler_ReconcileFileSources(t *testing.T) {
+	g := NewWithT(t)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string]string{
+			"key": "foo",
+		},
+	}
+	cfg := &bootstrapv1.KubeadmConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cfg",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: bootstrapv1.KubeadmConfigSpec{
+			Files: []bootstrapv1.File{
+				{
+					Content: "bar",
+					Path:    "/bar",
+				},
+				{
+					ContentFrom: &bootstrapv1.FileSource{
+						ConfigMap: &bootstrapv1.ConfigMapFileSource{
+							Name: "source",
+							Key:  "key",
+						},
+					},
+					Path: "/path",
+				},
+			},
+		},
+	}
+
+	myclient := fake.NewClientBuilder().WithObjects(configMap).Build()
+	k := &KubeadmConfigReconciler{
+		Client:          myclient,
+		KubeadmInitLock: &myInitLocker{},
+	}
+
+	// The checksum of the file sources is recorded when generating the bootstrap data.
+	files, err := k.resolveFiles(ctx, cfg)
+	g.Expect(err).NotTo(HaveOccurred())
+	setFileSourcesChecksum(cfg, files)
+	g.Expect(cfg.Annotations).To(HaveKey(bootstrapv1.FileSourcesChecksumAnnotation))
+	g.Expect(conditions.IsTrue(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(BeTrue())
+
+	// The checksum does not depend on files with inline content.
+	checksum := cfg.Annotations[bootstrapv1.FileSourcesChecksumAnnotation]
+	files[0].Content = "changed"
+	g.Expect(fileSourcesChecksum(cfg.Spec.Files, files)).To(Equal(checksum))
+
+	// Nothing changes if the content of the file sources is unchanged.
+	k.reconcileFileSources(ctx, cfg)
+	g.Expect(conditions.IsTrue(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(BeTrue())
+
+	// Changes to the content of the file sources are surfaced.
+	configMap.Data["key"] = "changed"
+	g.Expect(myclient.Update(ctx, configMap)).To(Succeed())
+	k.reconcileFileSources(ctx, cfg)
+	g.Expect(conditions.IsFalse(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(Equal(bootstrapv1.FileSourcesChangedReason))
+
+	// File sources that cannot be retrieved are surfaced.
+	g.Expect(myclient.Delete(ctx, configMap)).To(Succeed())
+	k.reconcileFileSources(ctx, cfg)
+	g.Expect(conditions.GetReason(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(Equal(bootstrapv1.FileSourcesUnavailableReason))
+
+	// The checksum is removed if there are no more file sources.
+	cfg.Spec.Files = cfg.Spec.Files[:1]
+	setFileSourcesChecksum(cfg, cfg.Spec.Files)
+	g.Expect(cfg.Annotations).ToNot(HaveKey(bootstrapv1.FileSourcesChecksumAnnotation))
+	g.Expect(conditions.Has(cfg, bootstrapv1.FileSourcesUpToDateCondition)).To(BeFalse())
+}
+
+func TestKubeadmConfigReconciler_RolloutMachineDeploymentForSources(t *testing.T) {
+	t.Run("requests a rollout if the file sources changed", func(t *testing.T) {
+		testRolloutMachineDeploymentForSources(t, bootstrapv1.FileSourcesUpToDateCondition, bootstrapv1.FileSourcesChangedReason)
+	})
+	t.Run("requests a rollout if the user sources changed", func(t *testing.T) {
+		testRolloutMachineDeploymentForSources(t, bootstrapv1.UserSourcesUpToDateCondition, bootstrapv1.UserSourcesChangedReason)
+	})
+}
+
+func testRolloutMachineDeploymentForSources(t *testing.T, conditionType clusterv1.ConditionType, changedReason string) {
+	t.Helper()
+	g := NewWithT(t)
+
+	machineCreation := time.Now().Add(-time.Hour).Truncate(time.Second)
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "md",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	machine := &clusterv1.Machine{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Machine",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "machine",
+			Namespace:         metav1.NamespaceDefault,
+			CreationTimestamp: metav1.Time{Time: machineCreation},
+			Labels: map[string]string{
+				clusterv1.MachineDeploymentNameLabel: "md",
+			},
+		},
+	}
+	cfg := &bootstrapv1.KubeadmConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cfg",
+			Namespace: metav1.NamespaceDefault,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Machine",
+					Name:       "machine",
+				},
+			},
+		},
+	}
+
+	myclient := fake.NewClientBuilder().WithObjects(md, machine, cfg).Build()
+	k := &KubeadmConfigReconciler{
+		Client: myclient,
+	}
+	configOwner, err := bsutil.GetConfigOwner(ctx, myclient, cfg)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// No rollout is requested if the sources are up to date.
+	conditions.MarkTrue(cfg, conditionType)
+	g.Expect(k.rolloutMachineDeploymentForSources(ctx, cfg, configOwner)).To(Succeed())
+	gotMD := &clusterv1.MachineDeployment{}
+	g.Expect(myclient.Get(ctx, client.ObjectKeyFromObject(md), gotMD)).To(Succeed())
+	g.Expect(gotMD.Spec.RolloutAfter).To(BeNil())
+
+	// A rollout is requested if the sources changed.
+	conditions.MarkFalse(cfg, conditionType, changedReason, clusterv1.ConditionSeverityWarning, "")
+	g.Expect(k.rolloutMachineDeploymentForSources(ctx, cfg, configOwner)).To(Succeed())
+	g.Expect(myclient.Get(ctx, client.ObjectKeyFromObject(md), gotMD)).To(Succeed())
+	g.Expect(gotMD.Spec.RolloutAfter).ToNot(BeNil())
+	g.Expect(gotMD.Spec.RolloutAfter.After(machineCreation)).To(BeTrue())
+
+	// The rollout is not requested again if it has already been requested after the Machine was created.
+	rolloutAfter := gotMD.Spec.RolloutAfter.DeepCopy()
+	g.Expect(k.rolloutMachineDeploymentForSources(ctx, cfg, configOwner)).To(Succeed())
+	g.Expect(myclient.Get(ctx, client.ObjectKeyFromObject(md), gotMD)).To(Succeed())
+	g.Expect(gotMD.Spec.RolloutAfter.Equal(rolloutAfter)).To(BeTrue())
+}
+
+func TestKubeadmConfigReconciler_ReconcileUserSources(t *testing.T) {
+	g := NewWithT(t)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string][]byte{
+			"keys": []byte("ssh-rsa foo"),
+		},
+	}
+	cfg := &bootstrapv1.KubeadmConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cfg",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: bootstrapv1.KubeadmConfigSpec{
+			Users: []bootstrapv1.User{
+				{
+					Name:              "foo",
+					SSHAuthorizedKeys: []string{"ssh-rsa inline"},
+				},
+				{
+					Name: "bar",
+					SSHAuthorizedKeysFrom: &bootstrapv1.SSHAuthorizedKeysSource{
+						Secret: bootstrapv1.SecretSSHAuthorizedKeysSource{
+							Name: "source",
+							Key:  "keys",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	myclient := fake.NewClientBuilder().WithObjects(secret).Build()
+	k := &KubeadmConfigReconciler{
+		Client:          myclient,
+		KubeadmInitLock: &myInitLocker{},
+	}
+
+	// The checksum of the user sources is recorded when generating the bootstrap data.
+	users, err := k.resolveUsers(ctx, cfg)
+	g.Expect(err).NotTo(HaveOccurred())
+	setUserSourcesChecksum(cfg, users)
+	g.Expect(cfg.Annotations).To(HaveKey(bootstrapv1.UserSourcesChecksumAnnotation))
+	g.Expect(conditions.IsTrue(cfg, bootstrapv1.UserSourcesUpToDateCondition)).To(BeTrue())
+
+	// The checksum does not depend on users without sources.
+	checksum := cfg.Annotations[bootstrapv1.UserSourcesChecksumAnnotation]
+	users[0].SSHAuthorizedKeys = []string{"ssh-rsa changed"}
+	g.Expect(userSourcesChecksum(cfg.Spec.Users, users)).To(Equal(checksum))
+
+	// Nothing changes if the content of the user sources is unchanged.
+	k.reconcileUserSources(ctx, cfg)
+	g.Expect(conditions.IsTrue(cfg, bootstrapv1.UserSourcesUpToDateCondition)).To(BeTrue())
+
+	// Rotated ssh keys are surfaced.
+	secret.Data["keys"] = []byte("ssh-rsa rotated")
+	g.Expect(myclient.Update(ctx, secret)).To(Succeed())
+	k.reconcileUserSources(ctx, cfg)
+	g.Expect(conditions.IsFalse(cfg, bootstrapv1.UserSourcesUpToDateCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cfg, bootstrapv1.UserSourcesUpToDateCondition)).To(Equal(bootstrapv1.UserSourcesChangedReason))
+
+	// User sources that cannot be retrieved are surfaced.
+	g.Expect(myclient.Delete(ctx, secret)).To(Succeed())
+	k.reconcileUserSources(ctx, cfg)
+	g.Expect(conditions.GetReason(cfg, bootstrapv1.UserSourcesUpToDateCondition)).To(Equal(bootstrapv1.UserSourcesUnavailableReason))
+
+	// The checksum is removed if there are no more user sources.
+	cfg.Spec.Users = cfg.Spec.Users[:1]
+	setUserSourcesChecksum(cfg, cfg.Spec.Users)
+	g.Expect(cfg.Annotations).ToNot(HaveKey(bootstrapv1.UserSourcesChecksumAnnotation))
+	g.Expect(conditions.Has(cfg, bootstrapv1.UserSourcesUpToDateCondition)).To(BeFalse())
+}
+
 func TestKubeadmConfigReconciler_ResolveUsers(t *testing.T) {
 	fakePasswd := "bar"
 	testSecret := &corev1.Secret{
@@ -2074,7 +2793,8 @@ func TestKubeadmConfigReconciler_ResolveUsers(t *testing.T) {
 			Name: "source",
 		},
 		Data: map[string][]byte{
-			"key": []byte(fakePasswd),
+			"key":  []byte(fakePasswd),
+			"keys": []byte("ssh-rsa foo\n\n# comment\nssh-ed25519 bar\n"),
 		},
 	}
 
@@ -2157,6 +2877,31 @@ func TestKubeadmConfigReconciler_ResolveUsers(t *testing.T) {
 			},
 			objects: []client.Object{testSecret},
 		},
+		"sshAuthorizedKeysFrom should be merged with sshAuthorizedKeys": {
+			cfg: &bootstrapv1.KubeadmConfig{
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Users: []bootstrapv1.User{
+						{
+							Name:              "foo",
+							SSHAuthorizedKeys: []string{"ssh-rsa inline"},
+							SSHAuthorizedKeysFrom: &bootstrapv1.SSHAuthorizedKeysSource{
+								Secret: bootstrapv1.SecretSSHAuthorizedKeysSource{
+									Name: "source",
+									Key:  "keys",
+								},
+							},
+						},
+					},
+				},
+			},
+			expect: []bootstrapv1.User{
+				{
+					Name:              "foo",
+					SSHAuthorizedKeys: []string{"ssh-rsa inline", "ssh-rsa foo", "ssh-ed25519 bar"},
+				},
+			},
+			objects: []client.Object{testSecret},
+		},
 	}
 
 	for name, tc := range cases {
@@ -2193,6 +2938,114 @@ func TestKubeadmConfigReconciler_ResolveUsers(t *testing.T) {
 	}
 }
 
+func TestKubeadmConfigReconciler_ResolveSnippets(t *testing.T) {
+	testSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string][]byte{
+			"key": []byte("foo"),
+		},
+	}
+	proxySnippet := &bootstrapv1.BootstrapSnippet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "proxy",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: bootstrapv1.BootstrapSnippetSpec{
+			Files: []bootstrapv1.File{
+				{
+					ContentFrom: &bootstrapv1.FileSource{
+						Secret: &bootstrapv1.SecretFileSource{
+							Name: "source",
+							Key:  "key",
+						},
+					},
+					Path: "/etc/proxy.env",
+				},
+			},
+			PreKubeadmCommands:  []string{"source /etc/proxy.env"},
+			PostKubeadmCommands: []string{"echo proxy done"},
+		},
+	}
+	hardeningSnippet := &bootstrapv1.BootstrapSnippet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hardening",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: bootstrapv1.BootstrapSnippetSpec{
+			PreKubeadmCommands:  []string{"sysctl --system"},
+			PostKubeadmCommands: []string{"echo hardening done"},
+		},
+	}
+
+	cases := map[string]struct {
+		snippets  []bootstrapv1.BootstrapSnippetReference
+		objects   []client.Object
+		expect    *bootstrapv1.BootstrapSnippetSpec
+		expectErr bool
+	}{
+		"no snippets should return an empty spec": {
+			expect: &bootstrapv1.BootstrapSnippetSpec{},
+		},
+		"snippets should be composed in order": {
+			snippets: []bootstrapv1.BootstrapSnippetReference{{Name: "hardening"}, {Name: "proxy"}},
+			objects:  []client.Object{testSecret, proxySnippet, hardeningSnippet},
+			expect: &bootstrapv1.BootstrapSnippetSpec{
+				Files: []bootstrapv1.File{
+					{
+						Content: "foo",
+						Path:    "/etc/proxy.env",
+					},
+				},
+				PreKubeadmCommands:  []string{"sysctl --system", "source /etc/proxy.env"},
+				PostKubeadmCommands: []string{"echo hardening done", "echo proxy done"},
+			},
+		},
+		"missing snippet should return an error": {
+			snippets:  []bootstrapv1.BootstrapSnippetReference{{Name: "hardening"}, {Name: "does-not-exist"}},
+			objects:   []client.Object{hardeningSnippet},
+			expectErr: true,
+		},
+		"snippet with missing file source should return an error": {
+			snippets:  []bootstrapv1.BootstrapSnippetReference{{Name: "proxy"}},
+			objects:   []client.Object{proxySnippet},
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			myclient := fake.NewClientBuilder().WithObjects(tc.objects...).Build()
+			k := &KubeadmConfigReconciler{
+				Client:          myclient,
+				KubeadmInitLock: &myInitLocker{},
+			}
+
+			cfg := &bootstrapv1.KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cfg",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Snippets: tc.snippets,
+				},
+			}
+
+			snippets, err := k.resolveSnippets(ctx, cfg)
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(snippets).To(Equal(tc.expect))
+		})
+	}
+}
+
 // test utils.
 
 // newWorkerMachineForCluster returns a Machine with the passed Cluster's information and a pre-configured name.
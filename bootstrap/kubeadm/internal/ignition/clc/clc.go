@@ -53,8 +53,9 @@ import (
 
 const (
 	clcTemplate = `---
-{{- if .Users }}
+{{- if or .Users .Groups }}
 passwd:
+  {{- if .Users }}
   users:
     {{- range .Users }}
     - name: {{ .Name }}
@@ -86,6 +87,13 @@ passwd:
         {{- end }}
       {{- end }}
     {{- end }}
+  {{- end }}
+  {{- if .Groups }}
+  groups:
+    {{- range .Groups }}
+    - name: {{ .Name }}
+    {{- end }}
+  {{- end }}
 {{- end }}
 systemd:
   units:
@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package powershell
+
+// NOTE: Devices are disk numbers as reported by Get-Disk; disks are initialized and partitioned only if they are raw,
+// and file systems are created only on partitions without a file system, unless overwrite is set.
+const (
+	diskSetupTemplate = `{{ define "disk_setup" -}}
+{{- if . }}
+{{- range .Partitions }}
+$disk = Get-Disk -Number {{ .Device }}
+if ($disk.PartitionStyle -eq 'RAW' -or ${{ Bool .Overwrite }}) {
+  if ($disk.PartitionStyle -ne 'RAW') {
+    Clear-Disk -Number {{ .Device }} -RemoveData -RemoveOEM -Confirm:$false
+  }
+  Initialize-Disk -Number {{ .Device }} -PartitionStyle {{ if eq (Deref .TableType) "gpt" }}GPT{{ else }}MBR{{ end }}
+  {{- if .Layout }}
+  New-Partition -DiskNumber {{ .Device }} -UseMaximumSize | Out-Null
+  {{- end }}
+}
+{{- end }}
+{{- range .Filesystems }}
+{{- if .Partition }}
+$partition = Get-Partition -DiskNumber {{ .Device }} -PartitionNumber {{ Deref .Partition }}
+{{- else }}
+$partition = Get-Partition -DiskNumber {{ .Device }} | Where-Object Type -ne 'Reserved' | Select-Object -Last 1
+{{- end }}
+if ((Get-Volume -Partition $partition).FileSystemType -eq 'Unknown' -or ${{ Bool .Overwrite }}) {
+  Format-Volume -Partition $partition -FileSystem {{ Quote .Filesystem }}{{ if and (ne .Label "") (ne .Label "None") }} -NewFileSystemLabel {{ Quote .Label }}{{ end }} -Force -Confirm:$false | Out-Null
+}
+{{- end }}
+{{- end }}
+{{- end -}}
+`
+)
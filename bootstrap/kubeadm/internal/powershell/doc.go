@@ -0,0 +1,23 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package powershell implements the generation of kubeadm bootstrap data for Windows machines, as PowerShell scripts
+// compatible with cloudbase-init.
+//
+// Only worker nodes joining the cluster are supported on Windows, so only NewNode is implemented among the functions
+// of the internal/cloudinit package; initializing or joining control plane nodes is rejected by the KubeadmConfig
+// and KubeadmControlPlane webhooks and by the KubeadmConfig controller.
+package powershell
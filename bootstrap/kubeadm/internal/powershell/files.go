@@ -0,0 +1,34 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package powershell
+
+// NOTE: Owner and permissions of the files are not supported on Windows, where ACLs are inherited from the parent directory.
+const (
+	filesTemplate = `{{ define "files" -}}
+{{ range . }}
+New-Item -ItemType Directory -Force -Path (Split-Path -Parent {{ Quote .Path }}) | Out-Null
+$content = [Convert]::FromBase64String({{ FileContent . | Quote }})
+{{- if .Append }}
+$stream = [IO.File]::Open({{ Quote .Path }}, [IO.FileMode]::Append)
+try { $stream.Write($content, 0, $content.Length) } finally { $stream.Close() }
+{{- else }}
+[IO.File]::WriteAllBytes({{ Quote .Path }}, $content)
+{{- end }}
+{{- end -}}
+{{- end -}}
+`
+)
@@ -0,0 +1,34 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package powershell
+
+// NOTE: Mount points are defined as a file system label, optionally prefixed by LABEL=, and either
+// a drive letter or a directory where the volume is mounted.
+const (
+	mountsTemplate = `{{ define "mounts" -}}
+{{ range . }}
+$partition = Get-Volume -FileSystemLabel {{ index . 0 | TrimLabel | Quote }} | Get-Partition
+{{- if IsDriveLetter (index . 1) }}
+Set-Partition -InputObject $partition -NewDriveLetter {{ slice (index . 1) 0 1 | Quote }}
+{{- else }}
+New-Item -ItemType Directory -Force -Path {{ index . 1 | Quote }} | Out-Null
+Add-PartitionAccessPath -InputObject $partition -AccessPath {{ index . 1 | Quote }}
+{{- end }}
+{{- end -}}
+{{- end -}}
+`
+)
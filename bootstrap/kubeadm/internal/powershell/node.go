@@ -0,0 +1,35 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package powershell
+
+const (
+	nodeScript = `{{.Header}}
+$ErrorActionPreference = 'Stop'
+{{- template "disk_setup" .DiskSetup }}
+{{- template "mounts" .Mounts }}
+{{- template "files" .WriteFiles }}
+{{- template "services" }}
+{{- template "commands" .PreKubeadmCommands }}
+{{ .KubeadmCommand }}
+if ($LASTEXITCODE -ne 0) {
+  throw "kubeadm join failed with exit code $LASTEXITCODE"
+}
+New-Item -ItemType Directory -Force -Path (Split-Path -Parent {{ Quote .SentinelFilePath }}) | Out-Null
+Set-Content -Path {{ Quote .SentinelFilePath }} -Value 'success'
+{{- template "commands" .PostKubeadmCommands }}
+`
+)
@@ -0,0 +1,168 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package powershell
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+)
+
+func TestNewNode(t *testing.T) {
+	g := NewWithT(t)
+
+	input := &cloudinit.NodeInput{
+		BaseUserData: cloudinit.BaseUserData{
+			AdditionalFiles: []bootstrapv1.File{
+				{
+					Path:    "c:/k/config.txt",
+					Content: "it's a file",
+				},
+				{
+					Path:     "c:/k/encoded.txt",
+					Content:  "ZW5jb2RlZA==",
+					Encoding: bootstrapv1.Base64,
+					Append:   true,
+				},
+			},
+			DiskSetup: &bootstrapv1.DiskSetup{
+				Partitions: []bootstrapv1.Partition{
+					{
+						Device:    "1",
+						Layout:    true,
+						TableType: pointer.String("gpt"),
+					},
+				},
+				Filesystems: []bootstrapv1.Filesystem{
+					{
+						Device:     "1",
+						Filesystem: "ntfs",
+						Label:      "data",
+					},
+				},
+			},
+			Mounts: []bootstrapv1.MountPoints{
+				{"LABEL=data", "D:"},
+			},
+			PreKubeadmCommands:  []string{"Start-Service containerd"},
+			PostKubeadmCommands: []string{"Write-Output done"},
+			KubeadmVerbosity:    "--v 5",
+		},
+		JoinConfiguration: "kind: JoinConfiguration",
+	}
+
+	out, err := NewNode(input)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(out)).To(Equal(`#ps1_sysnative
+$ErrorActionPreference = 'Stop'
+$disk = Get-Disk -Number 1
+if ($disk.PartitionStyle -eq 'RAW' -or $false) {
+  if ($disk.PartitionStyle -ne 'RAW') {
+    Clear-Disk -Number 1 -RemoveData -RemoveOEM -Confirm:$false
+  }
+  Initialize-Disk -Number 1 -PartitionStyle GPT
+  New-Partition -DiskNumber 1 -UseMaximumSize | Out-Null
+}
+$partition = Get-Partition -DiskNumber 1 | Where-Object Type -ne 'Reserved' | Select-Object -Last 1
+if ((Get-Volume -Partition $partition).FileSystemType -eq 'Unknown' -or $false) {
+  Format-Volume -Partition $partition -FileSystem 'ntfs' -NewFileSystemLabel 'data' -Force -Confirm:$false | Out-Null
+}
+$partition = Get-Volume -FileSystemLabel 'data' | Get-Partition
+Set-Partition -InputObject $partition -NewDriveLetter 'D'
+New-Item -ItemType Directory -Force -Path (Split-Path -Parent 'c:/k/config.txt') | Out-Null
+$content = [Convert]::FromBase64String('aXQncyBhIGZpbGU=')
+[IO.File]::WriteAllBytes('c:/k/config.txt', $content)
+New-Item -ItemType Directory -Force -Path (Split-Path -Parent 'c:/k/encoded.txt') | Out-Null
+$content = [Convert]::FromBase64String('ZW5jb2RlZA==')
+$stream = [IO.File]::Open('c:/k/encoded.txt', [IO.FileMode]::Append)
+try { $stream.Write($content, 0, $content.Length) } finally { $stream.Close() }
+New-Item -ItemType Directory -Force -Path (Split-Path -Parent '/run/kubeadm/kubeadm-join-config.yaml') | Out-Null
+$content = [Convert]::FromBase64String('LS0tCmtpbmQ6IEpvaW5Db25maWd1cmF0aW9u')
+[IO.File]::WriteAllBytes('/run/kubeadm/kubeadm-join-config.yaml', $content)
+if (Get-Service -Name kubelet -ErrorAction SilentlyContinue) {
+  Set-Service -Name kubelet -StartupType Automatic
+}
+Start-Service containerd
+kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml --v 5
+if ($LASTEXITCODE -ne 0) {
+  throw "kubeadm join failed with exit code $LASTEXITCODE"
+}
+New-Item -ItemType Directory -Force -Path (Split-Path -Parent '/run/cluster-api/bootstrap-success.complete') | Out-Null
+Set-Content -Path '/run/cluster-api/bootstrap-success.complete' -Value 'success'
+Write-Output done
+`))
+}
+
+func TestFileContent(t *testing.T) {
+	var gzipped bytes.Buffer
+	w := gzip.NewWriter(&gzipped)
+	_, _ = w.Write([]byte("content"))
+	_ = w.Close()
+
+	tests := []struct {
+		name    string
+		file    bootstrapv1.File
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "plain content",
+			file: bootstrapv1.File{Content: "content"},
+			want: "Y29udGVudA==",
+		},
+		{
+			name: "base64 content",
+			file: bootstrapv1.File{Content: "Y29udGVudA==", Encoding: bootstrapv1.Base64},
+			want: "Y29udGVudA==",
+		},
+		{
+			name: "gzip content",
+			file: bootstrapv1.File{Content: gzipped.String(), Encoding: bootstrapv1.Gzip},
+			want: "Y29udGVudA==",
+		},
+		{
+			name: "gzip+base64 content",
+			file: bootstrapv1.File{Content: base64.StdEncoding.EncodeToString(gzipped.Bytes()), Encoding: bootstrapv1.GzipBase64},
+			want: "Y29udGVudA==",
+		},
+		{
+			name:    "invalid gzip content",
+			file:    bootstrapv1.File{Content: "content", Encoding: bootstrapv1.Gzip},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := fileContent(tt.file)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
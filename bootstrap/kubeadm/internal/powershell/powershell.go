@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package powershell
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+)
+
+const (
+	// scriptHeader makes cloudbase-init run the bootstrap data as a PowerShell script.
+	scriptHeader = "#ps1_sysnative"
+
+	joinConfigurationPath = "/run/kubeadm/kubeadm-join-config.yaml"
+	kubeadmJoinCommand    = "kubeadm join --config " + joinConfigurationPath + " %s"
+
+	// sentinelFilePath is the file written to signal successful Kubernetes bootstrapping; it is the same path
+	// used for Linux machines, resolved on the current drive, e.g. C:\run\cluster-api\bootstrap-success.complete.
+	sentinelFilePath = "/run/cluster-api/bootstrap-success.complete"
+)
+
+// driveLetterRegex matches mount points that are drive letters, e.g. D or D:.
+var driveLetterRegex = regexp.MustCompile(`^[a-zA-Z]:?$`)
+
+// scriptInput is the context used to generate the PowerShell script.
+type scriptInput struct {
+	Header              string
+	WriteFiles          []bootstrapv1.File
+	DiskSetup           *bootstrapv1.DiskSetup
+	Mounts              []bootstrapv1.MountPoints
+	PreKubeadmCommands  []string
+	PostKubeadmCommands []string
+	KubeadmCommand      string
+	SentinelFilePath    string
+}
+
+// NewNode returns the PowerShell script to be used as user data on a Windows node instance.
+func NewNode(input *cloudinit.NodeInput) ([]byte, error) {
+	if input == nil {
+		return nil, errors.New("input can't be nil")
+	}
+
+	// NOTE: Mount points are validated by the KubeadmConfig webhook too, but the mounts template can't render
+	// incomplete mount points, so they are checked again before generating the script.
+	for i, mount := range input.Mounts {
+		if len(mount) != 2 {
+			return nil, errors.Errorf("mount point %d must be a filesystem label and a mount point, got %v", i, mount)
+		}
+	}
+
+	writeFiles := append([]bootstrapv1.File{}, input.AdditionalFiles...)
+	writeFiles = append(writeFiles, bootstrapv1.File{
+		Path:    joinConfigurationPath,
+		Content: "---\n" + input.JoinConfiguration,
+	})
+
+	return generate("Node", nodeScript, &scriptInput{
+		Header:              scriptHeader,
+		WriteFiles:          writeFiles,
+		DiskSetup:           input.DiskSetup,
+		Mounts:              input.Mounts,
+		PreKubeadmCommands:  input.PreKubeadmCommands,
+		PostKubeadmCommands: input.PostKubeadmCommands,
+		KubeadmCommand:      strings.TrimSpace(fmt.Sprintf(kubeadmJoinCommand, input.KubeadmVerbosity)),
+		SentinelFilePath:    sentinelFilePath,
+	})
+}
+
+func generate(kind string, tpl string, data interface{}) ([]byte, error) {
+	tm := template.New(kind).Funcs(template.FuncMap{
+		"Quote":         quote,
+		"FileContent":   fileContent,
+		"TrimLabel":     trimLabel,
+		"IsDriveLetter": driveLetterRegex.MatchString,
+		"Deref":         deref,
+		"Bool":          boolValue,
+	})
+
+	if _, err := tm.Parse(filesTemplate); err != nil {
+		return nil, errors.Wrap(err, "failed to parse files template")
+	}
+
+	if _, err := tm.Parse(commandsTemplate); err != nil {
+		return nil, errors.Wrap(err, "failed to parse commands template")
+	}
+
+	if _, err := tm.Parse(diskSetupTemplate); err != nil {
+		return nil, errors.Wrap(err, "failed to parse disk setup template")
+	}
+
+	if _, err := tm.Parse(mountsTemplate); err != nil {
+		return nil, errors.Wrap(err, "failed to parse mounts template")
+	}
+
+	if _, err := tm.Parse(servicesTemplate); err != nil {
+		return nil, errors.Wrap(err, "failed to parse services template")
+	}
+
+	t, err := tm.Parse(tpl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s template", kind)
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return nil, errors.Wrapf(err, "failed to generate %s template", kind)
+	}
+
+	return out.Bytes(), nil
+}
+
+// quote returns a PowerShell single-quoted string literal.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// boolValue returns the value of an optional bool, to be used as a PowerShell boolean, e.g. $true.
+func boolValue(b *bool) string {
+	return strconv.FormatBool(pointer.BoolDeref(b, false))
+}
+
+// deref returns the value of an optional string.
+func deref(s *string) string {
+	return pointer.StringDeref(s, "")
+}
+
+// trimLabel returns the file system label from a mount device, e.g. LABEL=data.
+func trimLabel(device string) string {
+	return strings.TrimPrefix(device, "LABEL=")
+}
+
+// fileContent returns the base64 encoded content of a file, after decoding it according to the file encoding.
+func fileContent(file bootstrapv1.File) (string, error) {
+	var content []byte
+	switch file.Encoding {
+	case bootstrapv1.Base64:
+		return file.Content, nil
+	case bootstrapv1.Gzip:
+		decompressed, err := gunzip([]byte(file.Content))
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to decode content of file %q", file.Path)
+		}
+		content = decompressed
+	case bootstrapv1.GzipBase64:
+		compressed, err := base64.StdEncoding.DecodeString(file.Content)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to decode content of file %q", file.Path)
+		}
+		decompressed, err := gunzip(compressed)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to decode content of file %q", file.Path)
+		}
+		content = decompressed
+	default:
+		content = []byte(file.Content)
+	}
+	return base64.StdEncoding.EncodeToString(content), nil
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
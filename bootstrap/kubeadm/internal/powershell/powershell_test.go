@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package powershell
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+)
+
+func TestNewNodeInvalidMounts(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewNode(&cloudinit.NodeInput{
+		BaseUserData: cloudinit.BaseUserData{
+			Mounts: []bootstrapv1.MountPoints{
+				{"LABEL=data"},
+			},
+		},
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("mount point 0 must be a filesystem label and a mount point")))
+}
+
+func TestMountsTemplate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mounts []bootstrapv1.MountPoints
+		want   string
+	}{
+		{
+			name:   "drive letter",
+			mounts: []bootstrapv1.MountPoints{{"LABEL=data", "d"}},
+			want: `
+$partition = Get-Volume -FileSystemLabel 'data' | Get-Partition
+Set-Partition -InputObject $partition -NewDriveLetter 'd'`,
+		},
+		{
+			name:   "directory",
+			mounts: []bootstrapv1.MountPoints{{"logs", "C:\\var\\log's"}},
+			want: `
+$partition = Get-Volume -FileSystemLabel 'logs' | Get-Partition
+New-Item -ItemType Directory -Force -Path 'C:\var\log''s' | Out-Null
+Add-PartitionAccessPath -InputObject $partition -AccessPath 'C:\var\log''s'`,
+		},
+		{
+			name: "no mounts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			out, err := generate("Mounts", `{{- template "mounts" . }}`, tt.mounts)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(out)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestDiskSetupTemplate(t *testing.T) {
+	g := NewWithT(t)
+
+	out, err := generate("DiskSetup", `{{- template "disk_setup" . }}`, &bootstrapv1.DiskSetup{
+		Partitions: []bootstrapv1.Partition{
+			{
+				Device:    "2",
+				Overwrite: pointer.Bool(true),
+			},
+		},
+		Filesystems: []bootstrapv1.Filesystem{
+			{
+				Device:     "2",
+				Partition:  pointer.String("1"),
+				Filesystem: "ntfs",
+				Label:      "None",
+			},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(out)).To(Equal(`
+$disk = Get-Disk -Number 2
+if ($disk.PartitionStyle -eq 'RAW' -or $true) {
+  if ($disk.PartitionStyle -ne 'RAW') {
+    Clear-Disk -Number 2 -RemoveData -RemoveOEM -Confirm:$false
+  }
+  Initialize-Disk -Number 2 -PartitionStyle MBR
+}
+$partition = Get-Partition -DiskNumber 2 -PartitionNumber 1
+if ((Get-Volume -Partition $partition).FileSystemType -eq 'Unknown' -or $false) {
+  Format-Volume -Partition $partition -FileSystem 'ntfs' -Force -Confirm:$false | Out-Null
+}`))
+}
+
+func TestQuote(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(quote("plain")).To(Equal("'plain'"))
+	g.Expect(quote("it's")).To(Equal("'it''s'"))
+	g.Expect(quote("$env:PATH")).To(Equal("'$env:PATH'"))
+}
@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/external"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util"
 )
 
 // ConfigOwner provides a data interface for different config owner types.
@@ -47,6 +48,16 @@ func (co ConfigOwner) IsInfrastructureReady() bool {
 	return infrastructureReady
 }
 
+// Addresses extracts the addresses reported by the infrastructure provider from the config owner.
+// NOTE: MachinePools do not report addresses, so an empty list is always returned for them.
+func (co ConfigOwner) Addresses() clusterv1.MachineAddresses {
+	addresses := clusterv1.MachineAddresses{}
+	if err := util.UnstructuredUnmarshalField(co.Unstructured, &addresses, "status", "addresses"); err != nil {
+		return nil
+	}
+	return addresses
+}
+
 // HasNodeRefs checks if the config owner has nodeRefs. For a Machine this means
 // that it has a nodeRef. For a MachinePool it means that it has as many nodeRefs
 // as there are replicas.
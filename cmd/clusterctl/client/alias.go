@@ -41,6 +41,10 @@ type Template repository.Template
 // UpgradePlan defines a list of possible upgrade targets for a management cluster.
 type UpgradePlan cluster.UpgradePlan
 
+// UpgradePath defines the sequence of upgrade steps required to upgrade a management cluster to a target version
+// of the core provider.
+type UpgradePath cluster.UpgradePath
+
 // CertManagerUpgradePlan defines the upgrade plan if cert-manager needs to be
 // upgraded to a different version.
 type CertManagerUpgradePlan cluster.CertManagerUpgradePlan
@@ -21,11 +21,15 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	capilabels "sigs.k8s.io/cluster-api/internal/labels"
 )
 
 // getKubeadmControlPlane retrieves the KubeadmControlPlane object corresponding to the name and namespace specified.
@@ -72,3 +76,57 @@ func patchKubeadmControlPlane(proxy cluster.Proxy, name, namespace string, patch
 	}
 	return nil
 }
+
+// findKubeadmControlPlaneRevision finds the specific revision in the ControllerRevisions.
+func findKubeadmControlPlaneRevision(toRevision int64, allRevisions []*appsv1.ControllerRevision) (*appsv1.ControllerRevision, error) {
+	var (
+		latestRevision   *appsv1.ControllerRevision
+		previousRevision *appsv1.ControllerRevision
+	)
+	for _, revision := range allRevisions {
+		if toRevision == 0 {
+			if latestRevision == nil || latestRevision.Revision < revision.Revision {
+				// newest one we've seen so far
+				previousRevision = latestRevision
+				latestRevision = revision
+			} else if previousRevision == nil || previousRevision.Revision < revision.Revision {
+				// second newest one we've seen so far
+				previousRevision = revision
+			}
+		} else if toRevision == revision.Revision {
+			return revision, nil
+		}
+	}
+
+	if toRevision > 0 {
+		return nil, errors.Errorf("unable to find specified KubeadmControlPlane revision: %v", toRevision)
+	}
+
+	if previousRevision == nil {
+		return nil, errors.Errorf("no rollout history found for KubeadmControlPlane")
+	}
+	return previousRevision, nil
+}
+
+// getControllerRevisionsForKubeadmControlPlane returns the list of ControllerRevisions recording the revision history
+// of a KubeadmControlPlane.
+func getControllerRevisionsForKubeadmControlPlane(proxy cluster.Proxy, kcp *controlplanev1.KubeadmControlPlane) ([]*appsv1.ControllerRevision, error) {
+	c, err := proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := c.List(ctx, revisions, client.InNamespace(kcp.Namespace), client.MatchingLabels{clusterv1.MachineControlPlaneNameLabel: capilabels.MustFormatValue(kcp.Name)}); err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*appsv1.ControllerRevision, 0, len(revisions.Items))
+	for idx := range revisions.Items {
+		// Skip this ControllerRevision if its controller ref is not pointing to this KubeadmControlPlane
+		if !metav1.IsControlledBy(&revisions.Items[idx], kcp) {
+			continue
+		}
+		filtered = append(filtered, &revisions.Items[idx])
+	}
+	return filtered, nil
+}
@@ -17,7 +17,14 @@ limitations under the License.
 package alpha
 
 import (
+	"fmt"
+
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
@@ -36,14 +43,20 @@ var validResourceTypes = []string{
 
 var validRollbackResourceTypes = []string{
 	MachineDeployment,
+	KubeadmControlPlane,
+}
+
+var validHistoryResourceTypes = []string{
+	MachineDeployment,
 }
 
 // Rollout defines the behavior of a rollout implementation.
 type Rollout interface {
 	ObjectRestarter(cluster.Proxy, corev1.ObjectReference) error
-	ObjectPauser(cluster.Proxy, corev1.ObjectReference) error
-	ObjectResumer(cluster.Proxy, corev1.ObjectReference) error
+	ObjectPauser(cluster.Proxy, corev1.ObjectReference, string) error
+	ObjectResumer(cluster.Proxy, corev1.ObjectReference, string) error
 	ObjectRollbacker(cluster.Proxy, corev1.ObjectReference, int64) error
+	ObjectHistory(cluster.Proxy, corev1.ObjectReference) ([]RolloutRevision, error)
 }
 
 var _ Rollout = &rollout{}
@@ -53,3 +66,43 @@ type rollout struct{}
 func newRolloutClient() Rollout {
 	return &rollout{}
 }
+
+// recordRolloutEvent records an event on a cluster-api resource, so the rollout operations performed with clusterctl
+// show up alongside the events recorded by the controllers.
+func recordRolloutEvent(proxy cluster.Proxy, obj client.Object, reason, messageFmt string, args ...interface{}) error {
+	c, err := proxy.NewClient()
+	if err != nil {
+		return err
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return errors.Wrapf(err, "failed to get GroupVersionKind for %s", obj.GetName())
+	}
+
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%v.%x", obj.GetName(), now.UnixNano()),
+			Namespace: obj.GetNamespace(),
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      gvk.GroupVersion().String(),
+			Kind:            gvk.Kind,
+			Name:            obj.GetName(),
+			Namespace:       obj.GetNamespace(),
+			UID:             obj.GetUID(),
+			ResourceVersion: obj.GetResourceVersion(),
+		},
+		Reason:         reason,
+		Message:        fmt.Sprintf(messageFmt, args...),
+		Type:           corev1.EventTypeNormal,
+		Source:         corev1.EventSource{Component: "clusterctl"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if err := c.Create(ctx, event); err != nil {
+		return errors.Wrapf(err, "failed to record %s event for %s %s", reason, gvk.Kind, klog.KObj(obj))
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+// RolloutRevision describes a revision of a cluster-api resource.
+type RolloutRevision struct {
+	// Revision is the revision number.
+	Revision int64
+
+	// Name is the name of the object backing the revision, e.g. the MachineSet for a MachineDeployment.
+	Name string
+
+	// Reason is why the revision was rolled out, if known.
+	Reason string
+}
+
+// ObjectHistory returns the rollout history of the specified cluster-api resource, sorted by revision.
+func (r *rollout) ObjectHistory(proxy cluster.Proxy, ref corev1.ObjectReference) ([]RolloutRevision, error) {
+	switch ref.Kind {
+	case MachineDeployment:
+		deployment, err := getMachineDeployment(proxy, ref.Name, ref.Namespace)
+		if err != nil || deployment == nil {
+			return nil, errors.Wrapf(err, "failed to get %v/%v", ref.Kind, ref.Name)
+		}
+		return machineDeploymentHistory(proxy, deployment)
+	default:
+		return nil, errors.Errorf("invalid resource type %q, valid values are %v", ref.Kind, validHistoryResourceTypes)
+	}
+}
+
+// machineDeploymentHistory returns the revisions of a MachineDeployment, one for each of its MachineSets.
+func machineDeploymentHistory(proxy cluster.Proxy, md *clusterv1.MachineDeployment) ([]RolloutRevision, error) {
+	msList, err := getMachineSetsForDeployment(proxy, md)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]RolloutRevision, 0, len(msList))
+	for _, ms := range msList {
+		v, err := revision(ms)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse revision of MachineSet %s", ms.Name)
+		}
+		revisions = append(revisions, RolloutRevision{
+			Revision: v,
+			Name:     ms.Name,
+			Reason:   ms.Annotations[clusterv1.RolloutReasonAnnotation],
+		})
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
@@ -17,7 +17,7 @@ limitations under the License.
 package alpha
 
 import (
-	"fmt"
+	"encoding/json"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -26,11 +26,15 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
-	"sigs.k8s.io/cluster-api/util/annotations"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 )
 
-// ObjectPauser will issue a pause on the specified cluster-api resource.
-func (r *rollout) ObjectPauser(proxy cluster.Proxy, ref corev1.ObjectReference) error {
+// ObjectPauser will issue a pause on the rollout of the specified cluster-api resource;
+// the reason is recorded in an annotation and in an event on the resource.
+func (r *rollout) ObjectPauser(proxy cluster.Proxy, ref corev1.ObjectReference, reason string) error {
+	if reason == "" {
+		return errors.New("a reason is required for pausing a rollout")
+	}
 	switch ref.Kind {
 	case MachineDeployment:
 		deployment, err := getMachineDeployment(proxy, ref.Name, ref.Namespace)
@@ -40,34 +44,54 @@ func (r *rollout) ObjectPauser(proxy cluster.Proxy, ref corev1.ObjectReference)
 		if deployment.Spec.Paused {
 			return errors.Errorf("MachineDeployment is already paused: %v/%v\n", ref.Kind, ref.Name) //nolint:revive // MachineDeployment is intentionally capitalized.
 		}
-		if err := pauseMachineDeployment(proxy, ref.Name, ref.Namespace); err != nil {
+		if err := pauseMachineDeployment(proxy, ref.Name, ref.Namespace, reason); err != nil {
 			return err
 		}
+		return recordRolloutEvent(proxy, deployment, "RolloutPaused", "Rollout paused: %s", reason)
 	case KubeadmControlPlane:
 		kcp, err := getKubeadmControlPlane(proxy, ref.Name, ref.Namespace)
 		if err != nil || kcp == nil {
 			return errors.Wrapf(err, "failed to fetch %v/%v", ref.Kind, ref.Name)
 		}
-		if annotations.HasPaused(kcp.GetObjectMeta()) {
+		if _, ok := kcp.Annotations[controlplanev1.RolloutPausedAnnotation]; ok {
 			return errors.Errorf("KubeadmControlPlane is already paused: %v/%v\n", ref.Kind, ref.Name) //nolint:revive // KubeadmControlPlane is intentionally capitalized.
 		}
-		if err := pauseKubeadmControlPlane(proxy, ref.Name, ref.Namespace); err != nil {
+		if err := pauseKubeadmControlPlane(proxy, ref.Name, ref.Namespace, reason); err != nil {
 			return err
 		}
+		return recordRolloutEvent(proxy, kcp, "RolloutPaused", "Rollout paused: %s", reason)
 	default:
 		return errors.Errorf("Invalid resource type %q, valid values are %v", ref.Kind, validResourceTypes)
 	}
-	return nil
 }
 
-// pauseMachineDeployment sets Paused to true in the MachineDeployment's spec.
-func pauseMachineDeployment(proxy cluster.Proxy, name, namespace string) error {
-	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf("{\"spec\":{\"paused\":%t}}", true)))
-	return patchMachineDeployment(proxy, name, namespace, patch)
+// pauseMachineDeployment sets Paused to true in the MachineDeployment's spec and records the reason.
+func pauseMachineDeployment(proxy cluster.Proxy, name, namespace, reason string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{clusterv1.RolloutPauseReasonAnnotation: reason},
+		},
+		"spec": map[string]interface{}{"paused": true},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal patch")
+	}
+	return patchMachineDeployment(proxy, name, namespace, client.RawPatch(types.MergePatchType, patch))
 }
 
-// pauseKubeadmControlPlane sets paused annotation to true.
-func pauseKubeadmControlPlane(proxy cluster.Proxy, name, namespace string) error {
-	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf("{\"metadata\":{\"annotations\":{%q: \"%t\"}}}", clusterv1.PausedAnnotation, true)))
-	return patchKubeadmControlPlane(proxy, name, namespace, patch)
+// pauseKubeadmControlPlane sets the rollout paused annotation and records the reason.
+// NOTE: The KubeadmControlPlane is not paused, so it keeps being reconciled, e.g. for scaling and remediation.
+func pauseKubeadmControlPlane(proxy cluster.Proxy, name, namespace, reason string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				controlplanev1.RolloutPausedAnnotation: "true",
+				clusterv1.RolloutPauseReasonAnnotation: reason,
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal patch")
+	}
+	return patchKubeadmControlPlane(proxy, name, namespace, client.RawPatch(types.MergePatchType, patch))
 }
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,7 +29,6 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
-	"sigs.k8s.io/cluster-api/util/annotations"
 )
 
 func Test_ObjectPauser(t *testing.T) {
@@ -39,6 +39,7 @@ func Test_ObjectPauser(t *testing.T) {
 	tests := []struct {
 		name       string
 		fields     fields
+		reason     string
 		wantErr    bool
 		wantPaused bool
 	}{
@@ -62,6 +63,7 @@ func Test_ObjectPauser(t *testing.T) {
 					Namespace: "default",
 				},
 			},
+			reason:     "change freeze",
 			wantErr:    false,
 			wantPaused: true,
 		},
@@ -88,6 +90,7 @@ func Test_ObjectPauser(t *testing.T) {
 					Namespace: "default",
 				},
 			},
+			reason:     "change freeze",
 			wantErr:    true,
 			wantPaused: false,
 		},
@@ -111,6 +114,7 @@ func Test_ObjectPauser(t *testing.T) {
 					Namespace: "default",
 				},
 			},
+			reason:     "change freeze",
 			wantErr:    false,
 			wantPaused: true,
 		},
@@ -126,7 +130,8 @@ func Test_ObjectPauser(t *testing.T) {
 							Namespace: "default",
 							Name:      "kcp",
 							Annotations: map[string]string{
-								clusterv1.PausedAnnotation: "true",
+								controlplanev1.RolloutPausedAnnotation: "true",
+								clusterv1.RolloutPauseReasonAnnotation: "change freeze",
 							},
 						},
 					},
@@ -137,6 +142,31 @@ func Test_ObjectPauser(t *testing.T) {
 					Namespace: "default",
 				},
 			},
+			reason:     "change freeze",
+			wantErr:    true,
+			wantPaused: false,
+		},
+		{
+			name: "pausing without a reason should return error",
+			fields: fields{
+				objs: []client.Object{
+					&clusterv1.MachineDeployment{
+						TypeMeta: metav1.TypeMeta{
+							Kind: "MachineDeployment",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "md-1",
+						},
+					},
+				},
+				ref: corev1.ObjectReference{
+					Kind:      MachineDeployment,
+					Name:      "md-1",
+					Namespace: "default",
+				},
+			},
+			reason:     "",
 			wantErr:    true,
 			wantPaused: false,
 		},
@@ -146,7 +176,7 @@ func Test_ObjectPauser(t *testing.T) {
 			g := NewWithT(t)
 			r := newRolloutClient()
 			proxy := test.NewFakeProxy().WithObjs(tt.fields.objs...)
-			err := r.ObjectPauser(proxy, tt.fields.ref)
+			err := r.ObjectPauser(proxy, tt.fields.ref, tt.reason)
 			if tt.wantErr {
 				g.Expect(err).To(HaveOccurred())
 				return
@@ -162,13 +192,30 @@ func Test_ObjectPauser(t *testing.T) {
 					err = cl.Get(context.TODO(), key, md)
 					g.Expect(err).ToNot(HaveOccurred())
 					g.Expect(md.Spec.Paused).To(Equal(tt.wantPaused))
+					g.Expect(md.Annotations).To(rolloutPauseReasonMatcher(tt.wantPaused, tt.reason))
 				case *controlplanev1.KubeadmControlPlane:
 					kcp := &controlplanev1.KubeadmControlPlane{}
 					err = cl.Get(context.TODO(), key, kcp)
 					g.Expect(err).ToNot(HaveOccurred())
-					g.Expect(annotations.HasPaused(kcp.GetObjectMeta())).To(Equal(tt.wantPaused))
+					g.Expect(kcp.Annotations).To(rolloutPauseReasonMatcher(tt.wantPaused, tt.reason))
+					_, paused := kcp.Annotations[controlplanev1.RolloutPausedAnnotation]
+					g.Expect(paused).To(Equal(tt.wantPaused))
 				}
+				events := &corev1.EventList{}
+				g.Expect(cl.List(context.TODO(), events, client.InNamespace(obj.GetNamespace()))).To(Succeed())
+				g.Expect(events.Items).To(HaveLen(1))
+				g.Expect(events.Items[0].InvolvedObject.Name).To(Equal(obj.GetName()))
+				g.Expect(events.Items[0].Reason).To(Equal("RolloutPaused"))
+				g.Expect(events.Items[0].Message).To(ContainSubstring(tt.reason))
 			}
 		})
 	}
 }
+
+// rolloutPauseReasonMatcher matches the annotations of a resource with a paused or with a resumed rollout.
+func rolloutPauseReasonMatcher(paused bool, reason string) types.GomegaMatcher {
+	if paused {
+		return HaveKeyWithValue(clusterv1.RolloutPauseReasonAnnotation, reason)
+	}
+	return Not(HaveKey(clusterv1.RolloutPauseReasonAnnotation))
+}
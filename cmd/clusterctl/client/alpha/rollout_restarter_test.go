@@ -171,6 +171,33 @@ func Test_ObjectRestarter(t *testing.T) {
 			wantErr:     true,
 			wantRollout: false,
 		},
+		{
+			name: "kubeadmcontrolplane with a paused rollout should not have rolloutAfter",
+			fields: fields{
+				objs: []client.Object{
+					&controlplanev1.KubeadmControlPlane{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "KubeadmControlPlane",
+							APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "kcp",
+							Annotations: map[string]string{
+								controlplanev1.RolloutPausedAnnotation: "true",
+							},
+						},
+					},
+				},
+				ref: corev1.ObjectReference{
+					Kind:      KubeadmControlPlane,
+					Name:      "kcp",
+					Namespace: "default",
+				},
+			},
+			wantErr:     true,
+			wantRollout: false,
+		},
 		{
 			name: "kubeadmcontrolplane with spec.rolloutAfter should not be updatable",
 			fields: fields{
@@ -17,8 +17,7 @@ limitations under the License.
 package alpha
 
 import (
-	"fmt"
-	"strings"
+	"encoding/json"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -27,11 +26,15 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
-	"sigs.k8s.io/cluster-api/util/annotations"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 )
 
-// ObjectResumer will issue a resume on the specified cluster-api resource.
-func (r *rollout) ObjectResumer(proxy cluster.Proxy, ref corev1.ObjectReference) error {
+// ObjectResumer will issue a resume on the rollout of the specified cluster-api resource;
+// the reason is recorded in an event on the resource.
+func (r *rollout) ObjectResumer(proxy cluster.Proxy, ref corev1.ObjectReference, reason string) error {
+	if reason == "" {
+		return errors.New("a reason is required for resuming a rollout")
+	}
 	switch ref.Kind {
 	case MachineDeployment:
 		deployment, err := getMachineDeployment(proxy, ref.Name, ref.Namespace)
@@ -44,35 +47,55 @@ func (r *rollout) ObjectResumer(proxy cluster.Proxy, ref corev1.ObjectReference)
 		if err := resumeMachineDeployment(proxy, ref.Name, ref.Namespace); err != nil {
 			return err
 		}
+		return recordRolloutEvent(proxy, deployment, "RolloutResumed", "Rollout resumed: %s", reason)
 	case KubeadmControlPlane:
 		kcp, err := getKubeadmControlPlane(proxy, ref.Name, ref.Namespace)
 		if err != nil || kcp == nil {
 			return errors.Wrapf(err, "failed to fetch %v/%v", ref.Kind, ref.Name)
 		}
-		if !annotations.HasPaused(kcp.GetObjectMeta()) {
+		_, rolloutPaused := kcp.Annotations[controlplanev1.RolloutPausedAnnotation]
+		// KubeadmControlPlanes paused by previous versions of clusterctl have the paused annotation instead.
+		_, legacyPaused := kcp.Annotations[clusterv1.PausedAnnotation]
+		if !rolloutPaused && !legacyPaused {
 			return errors.Errorf("KubeadmControlPlane is not currently paused: %v/%v\n", ref.Kind, ref.Name) //nolint:revive // KubeadmControlPlane is intentionally capitalized.
 		}
 		if err := resumeKubeadmControlPlane(proxy, ref.Name, ref.Namespace); err != nil {
 			return err
 		}
+		return recordRolloutEvent(proxy, kcp, "RolloutResumed", "Rollout resumed: %s", reason)
 	default:
 		return errors.Errorf("invalid resource type %q, valid values are %v", ref.Kind, validResourceTypes)
 	}
-	return nil
 }
 
-// resumeMachineDeployment sets Paused to true in the MachineDeployment's spec.
+// resumeMachineDeployment sets Paused to false in the MachineDeployment's spec and removes the pause reason.
 func resumeMachineDeployment(proxy cluster.Proxy, name, namespace string) error {
-	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf("{\"spec\":{\"paused\":%t}}", false)))
-
-	return patchMachineDeployment(proxy, name, namespace, patch)
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{clusterv1.RolloutPauseReasonAnnotation: nil},
+		},
+		"spec": map[string]interface{}{"paused": false},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal patch")
+	}
+	return patchMachineDeployment(proxy, name, namespace, client.RawPatch(types.MergePatchType, patch))
 }
 
-// resumeKubeadmControlPlane removes paused annotation.
+// resumeKubeadmControlPlane removes the rollout paused annotation, the paused annotation set by previous versions
+// of clusterctl and the pause reason.
 func resumeKubeadmControlPlane(proxy cluster.Proxy, name, namespace string) error {
-	// In the paused annotation we must replace slashes to ~1, see https://datatracker.ietf.org/doc/html/rfc6901#section-3.
-	pausedAnnotation := strings.Replace(clusterv1.PausedAnnotation, "/", "~1", -1)
-	patch := client.RawPatch(types.JSONPatchType, []byte(fmt.Sprintf("[{\"op\": \"remove\", \"path\": \"/metadata/annotations/%s\"}]", pausedAnnotation)))
-
-	return patchKubeadmControlPlane(proxy, name, namespace, patch)
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				controlplanev1.RolloutPausedAnnotation: nil,
+				clusterv1.PausedAnnotation:             nil,
+				clusterv1.RolloutPauseReasonAnnotation: nil,
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal patch")
+	}
+	return patchKubeadmControlPlane(proxy, name, namespace, client.RawPatch(types.MergePatchType, patch))
 }
@@ -28,7 +28,6 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
-	"sigs.k8s.io/cluster-api/util/annotations"
 )
 
 func Test_ObjectResumer(t *testing.T) {
@@ -39,6 +38,7 @@ func Test_ObjectResumer(t *testing.T) {
 	tests := []struct {
 		name       string
 		fields     fields
+		reason     string
 		wantErr    bool
 		wantPaused bool
 	}{
@@ -53,6 +53,9 @@ func Test_ObjectResumer(t *testing.T) {
 						ObjectMeta: metav1.ObjectMeta{
 							Namespace: "default",
 							Name:      "md-1",
+							Annotations: map[string]string{
+								clusterv1.RolloutPauseReasonAnnotation: "change freeze",
+							},
 						},
 						Spec: clusterv1.MachineDeploymentSpec{
 							Paused: true,
@@ -65,6 +68,7 @@ func Test_ObjectResumer(t *testing.T) {
 					Namespace: "default",
 				},
 			},
+			reason:     "change freeze",
 			wantErr:    false,
 			wantPaused: false,
 		},
@@ -91,11 +95,40 @@ func Test_ObjectResumer(t *testing.T) {
 					Namespace: "default",
 				},
 			},
+			reason:     "change freeze",
 			wantErr:    true,
 			wantPaused: false,
 		},
 		{
 			name: "paused kubeadmcontrolplane should be unpaused",
+			fields: fields{
+				objs: []client.Object{
+					&controlplanev1.KubeadmControlPlane{
+						TypeMeta: metav1.TypeMeta{
+							Kind: "KubeadmControlPlane",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "kcp",
+							Annotations: map[string]string{
+								controlplanev1.RolloutPausedAnnotation: "true",
+								clusterv1.RolloutPauseReasonAnnotation: "change freeze",
+							},
+						},
+					},
+				},
+				ref: corev1.ObjectReference{
+					Kind:      KubeadmControlPlane,
+					Name:      "kcp",
+					Namespace: "default",
+				},
+			},
+			reason:     "change freeze",
+			wantErr:    false,
+			wantPaused: false,
+		},
+		{
+			name: "kubeadmcontrolplane paused by a previous clusterctl version should be unpaused",
 			fields: fields{
 				objs: []client.Object{
 					&controlplanev1.KubeadmControlPlane{
@@ -117,6 +150,7 @@ func Test_ObjectResumer(t *testing.T) {
 					Namespace: "default",
 				},
 			},
+			reason:     "change freeze",
 			wantErr:    false,
 			wantPaused: false,
 		},
@@ -140,6 +174,7 @@ func Test_ObjectResumer(t *testing.T) {
 					Namespace: "default",
 				},
 			},
+			reason:     "change freeze",
 			wantErr:    true,
 			wantPaused: false,
 		},
@@ -149,7 +184,7 @@ func Test_ObjectResumer(t *testing.T) {
 			g := NewWithT(t)
 			r := newRolloutClient()
 			proxy := test.NewFakeProxy().WithObjs(tt.fields.objs...)
-			err := r.ObjectResumer(proxy, tt.fields.ref)
+			err := r.ObjectResumer(proxy, tt.fields.ref, tt.reason)
 			if tt.wantErr {
 				g.Expect(err).To(HaveOccurred())
 				return
@@ -165,12 +200,22 @@ func Test_ObjectResumer(t *testing.T) {
 					err = cl.Get(context.TODO(), key, md)
 					g.Expect(err).ToNot(HaveOccurred())
 					g.Expect(md.Spec.Paused).To(Equal(tt.wantPaused))
+					g.Expect(md.Annotations).To(rolloutPauseReasonMatcher(tt.wantPaused, tt.reason))
 				case *controlplanev1.KubeadmControlPlane:
 					kcp := &controlplanev1.KubeadmControlPlane{}
 					err = cl.Get(context.TODO(), key, kcp)
 					g.Expect(err).ToNot(HaveOccurred())
-					g.Expect(annotations.HasPaused(kcp.GetObjectMeta())).To(Equal(tt.wantPaused))
+					g.Expect(kcp.Annotations).To(rolloutPauseReasonMatcher(tt.wantPaused, tt.reason))
+					_, paused := kcp.Annotations[controlplanev1.RolloutPausedAnnotation]
+					g.Expect(paused).To(Equal(tt.wantPaused))
+					g.Expect(kcp.Annotations).ToNot(HaveKey(clusterv1.PausedAnnotation))
 				}
+				events := &corev1.EventList{}
+				g.Expect(cl.List(context.TODO(), events, client.InNamespace(obj.GetNamespace()))).To(Succeed())
+				g.Expect(events.Items).To(HaveLen(1))
+				g.Expect(events.Items[0].InvolvedObject.Name).To(Equal(obj.GetName()))
+				g.Expect(events.Items[0].Reason).To(Equal("RolloutResumed"))
+				g.Expect(events.Items[0].Message).To(ContainSubstring(tt.reason))
 			}
 		})
 	}
@@ -17,12 +17,16 @@ limitations under the License.
 package alpha
 
 import (
+	"encoding/json"
+
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
 
@@ -40,6 +44,20 @@ func (r *rollout) ObjectRollbacker(proxy cluster.Proxy, ref corev1.ObjectReferen
 		if err := rollbackMachineDeployment(proxy, deployment, toRevision); err != nil {
 			return err
 		}
+	case KubeadmControlPlane:
+		kcp, err := getKubeadmControlPlane(proxy, ref.Name, ref.Namespace)
+		if err != nil || kcp == nil {
+			return errors.Wrapf(err, "failed to get %v/%v", ref.Kind, ref.Name)
+		}
+		if annotations.HasPaused(kcp.GetObjectMeta()) {
+			return errors.Errorf("can't rollback a paused KubeadmControlPlane (remove annotation 'cluster.x-k8s.io/paused' first): %v/%v", ref.Kind, ref.Name)
+		}
+		if _, ok := kcp.Annotations[controlplanev1.RolloutPausedAnnotation]; ok {
+			return errors.Errorf("can't rollback a KubeadmControlPlane with a paused rollout: please run 'clusterctl alpha rollout resume %v/%v' first", ref.Kind, ref.Name)
+		}
+		if err := rollbackKubeadmControlPlane(proxy, kcp, toRevision); err != nil {
+			return err
+		}
 	default:
 		return errors.Errorf("invalid resource type %q, valid values are %v", ref.Kind, validRollbackResourceTypes)
 	}
@@ -78,3 +96,38 @@ func rollbackMachineDeployment(proxy cluster.Proxy, md *clusterv1.MachineDeploym
 	md.Spec.Template = revMSTemplate
 	return patchHelper.Patch(ctx, md)
 }
+
+// rollbackKubeadmControlPlane will rollback to a previous revision of the machine template and KubeadmConfigSpec
+// used by this KubeadmControlPlane, as recorded in the revision history.
+func rollbackKubeadmControlPlane(proxy cluster.Proxy, kcp *controlplanev1.KubeadmControlPlane, toRevision int64) error {
+	log := logf.Log
+	c, err := proxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	if toRevision < 0 {
+		return errors.Errorf("revision number cannot be negative: %v", toRevision)
+	}
+	revisions, err := getControllerRevisionsForKubeadmControlPlane(proxy, kcp)
+	if err != nil {
+		return err
+	}
+	log.V(7).Info("Found ControllerRevisions", "count", len(revisions))
+	revisionForRollback, err := findKubeadmControlPlaneRevision(toRevision, revisions)
+	if err != nil {
+		return err
+	}
+	log.V(7).Info("Found revision", "revision", revisionForRollback.Revision)
+	revisionKCP := &controlplanev1.KubeadmControlPlane{}
+	if err := json.Unmarshal(revisionForRollback.Data.Raw, revisionKCP); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal ControllerRevision %s", revisionForRollback.Name)
+	}
+	patchHelper, err := patch.NewHelper(kcp, c)
+	if err != nil {
+		return err
+	}
+	kcp.Spec.MachineTemplate = revisionKCP.Spec.MachineTemplate
+	kcp.Spec.KubeadmConfigSpec = revisionKCP.Spec.KubeadmConfigSpec
+	return patchHelper.Patch(ctx, kcp)
+}
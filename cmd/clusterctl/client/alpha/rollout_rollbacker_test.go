@@ -18,16 +18,21 @@ package alpha
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 )
 
 func Test_ObjectRollbacker(t *testing.T) {
@@ -259,3 +264,135 @@ func Test_ObjectRollbacker(t *testing.T) {
 		})
 	}
 }
+
+func Test_ObjectRollbackerKubeadmControlPlane(t *testing.T) {
+	kcp := &controlplanev1.KubeadmControlPlane{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "KubeadmControlPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kcp",
+			Namespace: "default",
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+				InfrastructureRef: corev1.ObjectReference{
+					APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+					Kind:       "InfrastructureMachineTemplate",
+					Name:       "kcp-template",
+				},
+			},
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				PreKubeadmCommands: []string{"echo current"},
+			},
+		},
+	}
+	newRevision := func(name string, revision int64, infraTemplate string, preKubeadmCommand string) *appsv1.ControllerRevision {
+		data, err := json.Marshal(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"machineTemplate": controlplanev1.KubeadmControlPlaneMachineTemplate{
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+						Kind:       "InfrastructureMachineTemplate",
+						Name:       infraTemplate,
+					},
+				},
+				"kubeadmConfigSpec": bootstrapv1.KubeadmConfigSpec{
+					PreKubeadmCommands: []string{preKubeadmCommand},
+				},
+			},
+		})
+		if err != nil {
+			panic(err)
+		}
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.MachineControlPlaneNameLabel: "kcp",
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind("KubeadmControlPlane")),
+				},
+			},
+			Data:     runtime.RawExtension{Raw: data},
+			Revision: revision,
+		}
+	}
+	tests := []struct {
+		name                  string
+		objs                  []client.Object
+		toRevision            int64
+		wantErr               bool
+		wantInfraTemplate     string
+		wantPreKubeadmCommand string
+	}{
+		{
+			name: "kubeadmcontrolplane should rollback to the previous revision",
+			objs: []client.Object{
+				kcp,
+				newRevision("kcp-1", 1, "kcp-template-1", "echo 1"),
+				newRevision("kcp-2", 2, "kcp-template-2", "echo 2"),
+				newRevision("kcp-3", 3, "kcp-template", "echo current"),
+			},
+			toRevision:            0,
+			wantInfraTemplate:     "kcp-template-2",
+			wantPreKubeadmCommand: "echo 2",
+		},
+		{
+			name: "kubeadmcontrolplane should rollback to revision=1",
+			objs: []client.Object{
+				kcp,
+				newRevision("kcp-1", 1, "kcp-template-1", "echo 1"),
+				newRevision("kcp-2", 2, "kcp-template-2", "echo 2"),
+				newRevision("kcp-3", 3, "kcp-template", "echo current"),
+			},
+			toRevision:            1,
+			wantInfraTemplate:     "kcp-template-1",
+			wantPreKubeadmCommand: "echo 1",
+		},
+		{
+			name: "kubeadmcontrolplane should not rollback because there is no previous revision",
+			objs: []client.Object{
+				kcp,
+				newRevision("kcp-1", 1, "kcp-template", "echo current"),
+			},
+			toRevision: 0,
+			wantErr:    true,
+		},
+		{
+			name: "kubeadmcontrolplane should not rollback because the specified version does not exist",
+			objs: []client.Object{
+				kcp,
+				newRevision("kcp-1", 1, "kcp-template", "echo current"),
+			},
+			toRevision: 999,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newRolloutClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.objs...)
+			ref := corev1.ObjectReference{
+				Kind:      KubeadmControlPlane,
+				Name:      "kcp",
+				Namespace: "default",
+			}
+			err := r.ObjectRollbacker(proxy, ref, tt.toRevision)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			cl, err := proxy.NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+			got := &controlplanev1.KubeadmControlPlane{}
+			g.Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(kcp), got)).To(Succeed())
+			g.Expect(got.Spec.MachineTemplate.InfrastructureRef.Name).To(Equal(tt.wantInfraTemplate))
+			g.Expect(got.Spec.KubeadmConfigSpec.PreKubeadmCommands).To(ConsistOf(tt.wantPreKubeadmCommand))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// BackupOptions carries the options supported by backup.
+type BackupOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the objects describing the workload clusters exist. If unspecified, objects
+	// from all the namespaces are backed up.
+	Namespace string
+
+	// File is the path of the backup archive to be written.
+	File string
+}
+
+// RestoreOptions carries the options supported by restore.
+type RestoreOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// File is the path of the backup archive to be restored.
+	File string
+
+	// Clusters defines the Clusters to be restored, by name or in the namespace/name format. If empty,
+	// all the Clusters in the backup archive are restored.
+	Clusters []string
+}
+
+func (c *clusterctlClient) Backup(options BackupOptions) error {
+	if options.File == "" {
+		return errors.New("backup file must be set")
+	}
+
+	fromCluster, err := c.getClusterClient(options.Kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	return fromCluster.ObjectMover().Backup(options.Namespace, options.File)
+}
+
+func (c *clusterctlClient) Restore(options RestoreOptions) error {
+	if options.File == "" {
+		return errors.New("backup file must be set")
+	}
+
+	toCluster, err := c.getClusterClient(options.Kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(options.File); err != nil {
+		return errors.Wrapf(err, "failed to read backup file %s", options.File)
+	}
+
+	return toCluster.ObjectMover().Restore(toCluster, options.File, options.Clusters...)
+}
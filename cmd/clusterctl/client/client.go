@@ -17,6 +17,8 @@ limitations under the License.
 package client
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/alpha"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
@@ -54,15 +56,32 @@ type Client interface {
 	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
 	Move(options MoveOptions) error
 
+	// Backup writes all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a backup archive.
+	Backup(options BackupOptions) error
+
+	// Restore restores the Cluster API objects from a backup archive into a management cluster.
+	Restore(options RestoreOptions) error
+
 	// PlanUpgrade returns a set of suggested Upgrade plans for the cluster.
 	PlanUpgrade(options PlanUpgradeOptions) ([]UpgradePlan, error)
 
+	// PlanUpgradePath returns the sequence of upgrade steps required to upgrade the cluster to a target version of the core provider.
+	PlanUpgradePath(options PlanUpgradePathOptions) (UpgradePath, error)
+
 	// PlanCertManagerUpgrade returns a CertManagerUpgradePlan.
 	PlanCertManagerUpgrade(options PlanUpgradeOptions) (CertManagerUpgradePlan, error)
 
 	// ApplyUpgrade executes an upgrade plan.
 	ApplyUpgrade(options ApplyUpgradeOptions) error
 
+	// ApplyStagedUpgrade executes an upgrade plan one provider at a time, verifying each provider after the upgrade
+	// and rolling it back to the previous version if the verification fails.
+	ApplyStagedUpgrade(options ApplyUpgradeOptions) (*UpgradeResult, error)
+
+	// ApplyProvidersConfig installs and upgrades the providers in a management cluster according to a providers config,
+	// returning the changes required to reconcile the installed providers with it.
+	ApplyProvidersConfig(options ApplyProvidersConfigOptions) ([]ProviderChange, error)
+
 	// ProcessYAML provides a direct way to process a yaml and inspect its
 	// variables.
 	ProcessYAML(options ProcessYAMLOptions) (YamlPrinter, error)
@@ -70,6 +89,9 @@ type Client interface {
 	// DescribeCluster returns the object tree representing the status of a Cluster API cluster.
 	DescribeCluster(options DescribeClusterOptions) (*tree.ObjectTree, error)
 
+	// GetClusters returns a summary of the status of the Cluster API clusters existing in a management cluster.
+	GetClusters(options GetClustersOptions) ([]tree.ClusterSummary, error)
+
 	// AlphaClient is an Interface for alpha features in clusterctl
 	AlphaClient
 }
@@ -84,8 +106,20 @@ type AlphaClient interface {
 	RolloutResume(options RolloutResumeOptions) error
 	// RolloutUndo provides rollout rollback of cluster-api resources
 	RolloutUndo(options RolloutUndoOptions) error
+	// RolloutHistory returns the rollout history of cluster-api resources
+	RolloutHistory(options RolloutHistoryOptions) ([]RolloutHistory, error)
 	// TopologyPlan dry runs the topology reconciler
 	TopologyPlan(options TopologyPlanOptions) (*TopologyPlanOutput, error)
+	// TopologyRebase rebases Clusters with a managed topology to a newer revision of their ClusterClass
+	TopologyRebase(options TopologyRebaseOptions) (*TopologyRebaseOutput, error)
+	// TopologyOwnership reports the field ownership for the objects managed by the topology controller for a Cluster
+	TopologyOwnership(options TopologyOwnershipOptions) (*TopologyOwnershipOutput, error)
+	// LintTemplate statically checks a cluster template for common issues
+	LintTemplate(options LintTemplateOptions) (*LintTemplateOutput, error)
+	// ProvidersAudit reports the deprecated and stale API versions used by the objects of the provider CRDs
+	ProvidersAudit(options ProvidersAuditOptions) ([]ProvidersAuditResult, error)
+	// ClusterClone creates a copy of a Cluster with a new name and/or namespace
+	ClusterClone(options ClusterCloneOptions) ([]unstructured.Unstructured, error)
 }
 
 // YamlPrinter exposes methods that prints the processed template and
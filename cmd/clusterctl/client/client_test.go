@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -108,10 +109,22 @@ func (f fakeClient) Move(options MoveOptions) error {
 	return f.internalClient.Move(options)
 }
 
+func (f fakeClient) Backup(options BackupOptions) error {
+	return f.internalClient.Backup(options)
+}
+
+func (f fakeClient) Restore(options RestoreOptions) error {
+	return f.internalClient.Restore(options)
+}
+
 func (f fakeClient) PlanUpgrade(options PlanUpgradeOptions) ([]UpgradePlan, error) {
 	return f.internalClient.PlanUpgrade(options)
 }
 
+func (f fakeClient) PlanUpgradePath(options PlanUpgradePathOptions) (UpgradePath, error) {
+	return f.internalClient.PlanUpgradePath(options)
+}
+
 func (f fakeClient) PlanCertManagerUpgrade(options PlanUpgradeOptions) (CertManagerUpgradePlan, error) {
 	return f.internalClient.PlanCertManagerUpgrade(options)
 }
@@ -120,6 +133,14 @@ func (f fakeClient) ApplyUpgrade(options ApplyUpgradeOptions) error {
 	return f.internalClient.ApplyUpgrade(options)
 }
 
+func (f fakeClient) ApplyStagedUpgrade(options ApplyUpgradeOptions) (*UpgradeResult, error) {
+	return f.internalClient.ApplyStagedUpgrade(options)
+}
+
+func (f fakeClient) ApplyProvidersConfig(options ApplyProvidersConfigOptions) ([]ProviderChange, error) {
+	return f.internalClient.ApplyProvidersConfig(options)
+}
+
 func (f fakeClient) ProcessYAML(options ProcessYAMLOptions) (YamlPrinter, error) {
 	return f.internalClient.ProcessYAML(options)
 }
@@ -132,6 +153,10 @@ func (f fakeClient) DescribeCluster(options DescribeClusterOptions) (*tree.Objec
 	return f.internalClient.DescribeCluster(options)
 }
 
+func (f fakeClient) GetClusters(options GetClustersOptions) ([]tree.ClusterSummary, error) {
+	return f.internalClient.GetClusters(options)
+}
+
 func (f fakeClient) RolloutPause(options RolloutPauseOptions) error {
 	return f.internalClient.RolloutPause(options)
 }
@@ -144,10 +169,34 @@ func (f fakeClient) RolloutUndo(options RolloutUndoOptions) error {
 	return f.internalClient.RolloutUndo(options)
 }
 
+func (f fakeClient) RolloutHistory(options RolloutHistoryOptions) ([]RolloutHistory, error) {
+	return f.internalClient.RolloutHistory(options)
+}
+
 func (f fakeClient) TopologyPlan(options TopologyPlanOptions) (*cluster.TopologyPlanOutput, error) {
 	return f.internalClient.TopologyPlan(options)
 }
 
+func (f fakeClient) TopologyRebase(options TopologyRebaseOptions) (*cluster.TopologyRebaseOutput, error) {
+	return f.internalClient.TopologyRebase(options)
+}
+
+func (f fakeClient) TopologyOwnership(options TopologyOwnershipOptions) (*cluster.TopologyOwnershipOutput, error) {
+	return f.internalClient.TopologyOwnership(options)
+}
+
+func (f fakeClient) LintTemplate(options LintTemplateOptions) (*LintTemplateOutput, error) {
+	return f.internalClient.LintTemplate(options)
+}
+
+func (f fakeClient) ProvidersAudit(options ProvidersAuditOptions) ([]ProvidersAuditResult, error) {
+	return f.internalClient.ProvidersAudit(options)
+}
+
+func (f fakeClient) ClusterClone(options ClusterCloneOptions) ([]unstructured.Unstructured, error) {
+	return f.internalClient.ClusterClone(options)
+}
+
 // newFakeClient returns a clusterctl client that allows to execute tests on a set of fake config, fake repositories and fake clusters.
 // you can use WithCluster and WithRepository to prepare for the test case.
 func newFakeClient(configClient config.Client) *fakeClient {
@@ -174,10 +223,17 @@ func newFakeClient(configClient config.Client) *fakeClient {
 		InjectConfig(fake.configClient),
 		InjectClusterClientFactory(clusterClientFactory),
 		InjectRepositoryFactory(func(input RepositoryClientFactoryInput) (repository.Client, error) {
-			if _, ok := fake.repositories[input.Provider.ManifestLabel()]; !ok {
+			repo, ok := fake.repositories[input.Provider.ManifestLabel()]
+			if !ok {
 				return nil, errors.Errorf("repository for kubeconfig %q does not exist", input.Provider.ManifestLabel())
 			}
-			return fake.repositories[input.Provider.ManifestLabel()], nil
+			// Use the processor passed in input, if any, like the repository client would do.
+			if fakeRepo, ok := repo.(*fakeRepositoryClient); ok && input.Processor != nil {
+				withProcessor := *fakeRepo
+				withProcessor.processor = input.Processor
+				return &withProcessor, nil
+			}
+			return repo, nil
 		}),
 	)
 
@@ -322,6 +378,10 @@ func (f *fakeClusterClient) Topology() cluster.TopologyClient {
 	return f.internalclient.Topology()
 }
 
+func (f *fakeClusterClient) StorageVersionAuditor() cluster.StorageVersionAuditor {
+	return f.internalclient.StorageVersionAuditor()
+}
+
 func (f *fakeClusterClient) WithObjs(objs ...client.Object) *fakeClusterClient {
 	f.fakeProxy.WithObjs(objs...)
 	return f
@@ -384,6 +444,10 @@ func (f fakeConfigClient) ImageMeta() config.ImageMetaClient {
 	return f.internalclient.ImageMeta()
 }
 
+func (f fakeConfigClient) NetworkPolicies() config.NetworkPoliciesClient {
+	return f.internalclient.NetworkPolicies()
+}
+
 func (f *fakeConfigClient) WithVar(key, value string) *fakeConfigClient {
 	f.fakeReader.WithVar(key, value)
 	return f
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+// ClusterCloneOptions carries the options supported by ClusterClone.
+type ClusterCloneOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the Cluster to be cloned exists. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// ClusterName is the name of the Cluster to be cloned.
+	ClusterName string
+
+	// ToNamespace is the namespace of the clone. If unspecified, the namespace of the source Cluster will be used.
+	ToNamespace string
+
+	// ToName is the name of the clone.
+	ToName string
+
+	// Variables defines the values of the topology variables of the clone (variable name -> value); values are
+	// parsed as JSON, and used as plain strings if they are not valid JSON.
+	Variables map[string]string
+
+	// DryRun means the objects of the clone are returned without creating them.
+	DryRun bool
+}
+
+// ClusterClone creates a copy of a Cluster with a new name and/or namespace, and returns the objects of the clone.
+func (c *clusterctlClient) ClusterClone(options ClusterCloneOptions) ([]unstructured.Unstructured, error) {
+	if options.ClusterName == "" {
+		return nil, errors.New("the name of the Cluster to be cloned must be set")
+	}
+	if options.ToName == "" {
+		return nil, errors.New("the name of the clone must be set")
+	}
+
+	clusterClient, err := c.getClusterClient(options.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	return clusterClient.ObjectMover().Clone(cluster.CloneOptions{
+		Namespace:   options.Namespace,
+		Name:        options.ClusterName,
+		ToNamespace: options.ToNamespace,
+		ToName:      options.ToName,
+		Variables:   options.Variables,
+		DryRun:      options.DryRun,
+	})
+}
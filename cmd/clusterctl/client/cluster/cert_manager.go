@@ -19,20 +19,26 @@ package cluster
 import (
 	"context"
 	_ "embed"
+	"strings"
 	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/errorcodes"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/util/container"
 	utilresource "sigs.k8s.io/cluster-api/util/resource"
 	"sigs.k8s.io/cluster-api/util/version"
 	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
@@ -41,7 +47,8 @@ import (
 const (
 	waitCertManagerInterval = 1 * time.Second
 
-	certManagerNamespace = "cert-manager"
+	certManagerInjectCAFromSecretAnnotation = "cert-manager.io/inject-ca-from-secret"
+	certManagerInjectCAFromAnnotation       = "cert-manager.io/inject-ca-from"
 
 	// This is maintained only for supporting upgrades from cluster created with clusterctl v1alpha3.
 	//
@@ -104,8 +111,13 @@ func newCertManagerClient(configClient config.Client, repositoryClientFactory Re
 
 // Images return the list of images required for installing the cert-manager.
 func (cm *certManagerClient) Images() ([]string, error) {
+	config, err := cm.configClient.CertManager().Get()
+	if err != nil {
+		return nil, err
+	}
+
 	// If cert manager already exists in the cluster, there is no need of additional images for cert-manager.
-	exists, err := cm.certManagerNamespaceExists()
+	exists, err := cm.certManagerNamespaceExists(config.Namespace())
 	if err != nil {
 		return nil, err
 	}
@@ -114,11 +126,6 @@ func (cm *certManagerClient) Images() ([]string, error) {
 	}
 
 	// Otherwise, retrieve the images from the cert-manager manifest.
-	config, err := cm.configClient.CertManager().Get()
-	if err != nil {
-		return nil, err
-	}
-
 	objs, err := cm.getManifestObjs(config)
 	if err != nil {
 		return nil, err
@@ -131,9 +138,9 @@ func (cm *certManagerClient) Images() ([]string, error) {
 	return images, nil
 }
 
-func (cm *certManagerClient) certManagerNamespaceExists() (bool, error) {
+func (cm *certManagerClient) certManagerNamespaceExists(namespace string) (bool, error) {
 	ns := &corev1.Namespace{}
-	key := client.ObjectKey{Name: certManagerNamespace}
+	key := client.ObjectKey{Name: namespace}
 	c, err := cm.proxy.NewClient()
 	if err != nil {
 		return false, err
@@ -203,7 +210,12 @@ func (cm *certManagerClient) install() error {
 func (cm *certManagerClient) PlanUpgrade() (CertManagerUpgradePlan, error) {
 	log := logf.Log
 
-	objs, err := cm.proxy.ListResources(map[string]string{clusterctlv1.ClusterctlCoreLabel: clusterctlv1.ClusterctlCoreLabelCertManagerValue}, certManagerNamespace)
+	config, err := cm.configClient.CertManager().Get()
+	if err != nil {
+		return CertManagerUpgradePlan{}, err
+	}
+
+	objs, err := cm.proxy.ListResources(map[string]string{clusterctlv1.ClusterctlCoreLabel: clusterctlv1.ClusterctlCoreLabelCertManagerValue}, config.Namespace())
 	if err != nil {
 		return CertManagerUpgradePlan{}, errors.Wrap(err, "failed get cert manager components")
 	}
@@ -232,7 +244,12 @@ func (cm *certManagerClient) PlanUpgrade() (CertManagerUpgradePlan, error) {
 func (cm *certManagerClient) EnsureLatestVersion() error {
 	log := logf.Log
 
-	objs, err := cm.proxy.ListResources(map[string]string{clusterctlv1.ClusterctlCoreLabel: clusterctlv1.ClusterctlCoreLabelCertManagerValue}, certManagerNamespace)
+	config, err := cm.configClient.CertManager().Get()
+	if err != nil {
+		return err
+	}
+
+	objs, err := cm.proxy.ListResources(map[string]string{clusterctlv1.ClusterctlCoreLabel: clusterctlv1.ClusterctlCoreLabelCertManagerValue}, config.Namespace())
 	if err != nil {
 		return errors.Wrap(err, "failed get cert manager components")
 	}
@@ -419,13 +436,28 @@ func (cm *certManagerClient) getManifestObjs(certManagerConfig config.CertManage
 	}
 
 	// Apply image overrides.
+	// NOTE: The image repository defined in the cert-manager configuration is applied first, so image overrides
+	// defined for the cert-manager component take precedence.
 	objs, err = util.FixImages(objs, func(image string) (string, error) {
+		if repository := certManagerConfig.Customization().ImageRepository; repository != "" {
+			modifiedImage, err := container.ModifyImageRepository(image, repository)
+			if err != nil {
+				return "", err
+			}
+			image = modifiedImage
+		}
 		return cm.configClient.ImageMeta().AlterImage(config.CertManagerImageComponent, image)
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to apply image override to the cert-manager manifest")
 	}
 
+	// Apply the customizations defined in the clusterctl configuration.
+	objs, err = customizeCertManager(objs, certManagerConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to customize the cert-manager manifest")
+	}
+
 	// Add cert manager labels and annotations.
 	objs = addCerManagerLabel(objs)
 	objs = addCerManagerAnnotations(objs, certManagerConfig.Version())
@@ -433,6 +465,154 @@ func (cm *certManagerClient) getManifestObjs(certManagerConfig config.CertManage
 	return objs, nil
 }
 
+// customizeCertManager applies the customizations defined in the cert-manager configuration to the cert-manager manifest.
+func customizeCertManager(objs []unstructured.Unstructured, certManagerConfig config.CertManager) ([]unstructured.Unstructured, error) {
+	if namespace := certManagerConfig.Namespace(); namespace != "" && namespace != config.CertManagerDefaultNamespace {
+		for i := range objs {
+			if err := fixCertManagerNamespace(&objs[i], config.CertManagerDefaultNamespace, namespace); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	customization := certManagerConfig.Customization()
+	for i := range objs {
+		if err := customizeCertManagerDeployment(&objs[i], customization); err != nil {
+			return nil, err
+		}
+	}
+	return objs, nil
+}
+
+// fixCertManagerNamespace moves a cert-manager object from the namespace defined in the cert-manager manifest to the
+// target namespace, and fixes all the references to the cert-manager namespace.
+// NOTE: Objects in other namespaces, e.g. the leader election Roles in kube-system, are not moved.
+func fixCertManagerNamespace(o *unstructured.Unstructured, originalNamespace, targetNamespace string) error {
+	if o.GetKind() == "Namespace" && o.GetName() == originalNamespace {
+		o.SetName(targetNamespace)
+	}
+	if o.GetNamespace() == originalNamespace {
+		o.SetNamespace(targetNamespace)
+	}
+
+	// Fix the CA injection annotations, in the <namespace>/<name> format.
+	annotations := o.GetAnnotations()
+	for _, a := range []string{certManagerInjectCAFromSecretAnnotation, certManagerInjectCAFromAnnotation} {
+		if v, ok := annotations[a]; ok && strings.HasPrefix(v, originalNamespace+"/") {
+			annotations[a] = targetNamespace + strings.TrimPrefix(v, originalNamespace)
+		}
+	}
+	if len(annotations) > 0 {
+		o.SetAnnotations(annotations)
+	}
+
+	switch o.GetKind() {
+	case "RoleBinding", "ClusterRoleBinding":
+		return fixNestedNamespaces(o, []string{"subjects"}, []string{"namespace"}, originalNamespace, targetNamespace)
+	case "MutatingWebhookConfiguration", "ValidatingWebhookConfiguration":
+		return fixNestedNamespaces(o, []string{"webhooks"}, []string{"clientConfig", "service", "namespace"}, originalNamespace, targetNamespace)
+	case "CustomResourceDefinition":
+		namespace, ok, err := unstructured.NestedString(o.Object, "spec", "conversion", "webhook", "clientConfig", "service", "namespace")
+		if err != nil || !ok || namespace != originalNamespace {
+			return err
+		}
+		return unstructured.SetNestedField(o.Object, targetNamespace, "spec", "conversion", "webhook", "clientConfig", "service", "namespace")
+	case "Deployment":
+		return fixCertManagerDeploymentArgs(o, originalNamespace, targetNamespace)
+	}
+	return nil
+}
+
+// fixNestedNamespaces fixes the namespace at the given path of all the items in the list at listPath.
+func fixNestedNamespaces(o *unstructured.Unstructured, listPath, namespacePath []string, originalNamespace, targetNamespace string) error {
+	items, ok, err := unstructured.NestedSlice(o.Object, listPath...)
+	if err != nil || !ok {
+		return err
+	}
+	for i := range items {
+		item, ok := items[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		namespace, ok, err := unstructured.NestedString(item, namespacePath...)
+		if err != nil {
+			return err
+		}
+		if !ok || namespace != originalNamespace {
+			continue
+		}
+		if err := unstructured.SetNestedField(item, targetNamespace, namespacePath...); err != nil {
+			return err
+		}
+	}
+	return unstructured.SetNestedSlice(o.Object, items, listPath...)
+}
+
+// fixCertManagerDeploymentArgs fixes the service DNS names in the args of the cert-manager containers, e.g.
+// --dynamic-serving-dns-names=cert-manager-webhook,cert-manager-webhook.cert-manager,cert-manager-webhook.cert-manager.svc.
+func fixCertManagerDeploymentArgs(o *unstructured.Unstructured, originalNamespace, targetNamespace string) error {
+	d := &appsv1.Deployment{}
+	if err := scheme.Scheme.Convert(o, d, nil); err != nil {
+		return err
+	}
+
+	for i := range d.Spec.Template.Spec.Containers {
+		args := d.Spec.Template.Spec.Containers[i].Args
+		for j := range args {
+			flag, value, ok := strings.Cut(args[j], "=")
+			if !ok {
+				continue
+			}
+			names := strings.Split(value, ",")
+			for k := range names {
+				parts := strings.Split(names[k], ".")
+				if len(parts) >= 2 && parts[1] == originalNamespace {
+					parts[1] = targetNamespace
+					names[k] = strings.Join(parts, ".")
+				}
+			}
+			args[j] = flag + "=" + strings.Join(names, ",")
+		}
+	}
+
+	return scheme.Scheme.Convert(d, o, nil)
+}
+
+// customizeCertManagerDeployment applies replicas, node selector, tolerations and resources to a cert-manager Deployment.
+func customizeCertManagerDeployment(o *unstructured.Unstructured, customization config.CertManagerCustomization) error {
+	if o.GetKind() != "Deployment" {
+		return nil
+	}
+	if customization.Replicas == nil && len(customization.NodeSelector) == 0 && len(customization.Tolerations) == 0 && customization.Resources == nil {
+		return nil
+	}
+
+	d := &appsv1.Deployment{}
+	if err := scheme.Scheme.Convert(o, d, nil); err != nil {
+		return err
+	}
+
+	if customization.Replicas != nil {
+		d.Spec.Replicas = pointer.Int32(*customization.Replicas)
+	}
+	if len(customization.NodeSelector) > 0 {
+		if d.Spec.Template.Spec.NodeSelector == nil {
+			d.Spec.Template.Spec.NodeSelector = map[string]string{}
+		}
+		for k, v := range customization.NodeSelector {
+			d.Spec.Template.Spec.NodeSelector[k] = v
+		}
+	}
+	d.Spec.Template.Spec.Tolerations = append(d.Spec.Template.Spec.Tolerations, customization.Tolerations...)
+	if customization.Resources != nil {
+		for i := range d.Spec.Template.Spec.Containers {
+			d.Spec.Template.Spec.Containers[i].Resources = *customization.Resources.DeepCopy()
+		}
+	}
+
+	return scheme.Scheme.Convert(d, o, nil)
+}
+
 func addCerManagerLabel(objs []unstructured.Unstructured) []unstructured.Unstructured {
 	for _, o := range objs {
 		labels := o.GetLabels()
@@ -554,7 +734,7 @@ func (cm *certManagerClient) waitForAPIReady(_ context.Context, retry bool) erro
 			}
 			return true, nil
 		}); err != nil {
-			return err
+			return errorcodes.Wrap(errorcodes.WebhookCertNotReady, errors.Wrap(err, "cert-manager is not ready"))
 		}
 	}
 	deleteCertManagerBackoff := newWriteBackoff()
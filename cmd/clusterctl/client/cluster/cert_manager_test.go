@@ -28,9 +28,11 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
@@ -164,6 +166,110 @@ func Test_getManifestObjs(t *testing.T) {
 	}
 }
 
+func Test_customizeCertManager(t *testing.T) {
+	g := NewWithT(t)
+
+	manifest := []byte(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: cert-manager
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: cert-manager-webhook
+  namespace: cert-manager
+spec:
+  replicas: 1
+  template:
+    spec:
+      nodeSelector:
+        kubernetes.io/os: linux
+      containers:
+      - name: cert-manager-webhook
+        image: quay.io/jetstack/cert-manager-webhook:v1.11.1
+        args:
+        - --v=2
+        - --dynamic-serving-ca-secret-namespace=$(POD_NAMESPACE)
+        - --dynamic-serving-dns-names=cert-manager-webhook,cert-manager-webhook.cert-manager,cert-manager-webhook.cert-manager.svc
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: cert-manager-webhook:leaderelection
+  namespace: kube-system
+subjects:
+- kind: ServiceAccount
+  name: cert-manager-webhook
+  namespace: cert-manager
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: cert-manager-webhook
+  annotations:
+    cert-manager.io/inject-ca-from-secret: cert-manager/cert-manager-webhook-ca
+webhooks:
+- name: webhook.cert-manager.io
+  clientConfig:
+    service:
+      name: cert-manager-webhook
+      namespace: cert-manager
+`)
+	objs, err := utilyaml.ToUnstructured(manifest)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	customization := config.CertManagerCustomization{
+		Replicas:     pointer.Int32(3),
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+		Tolerations: []corev1.Toleration{
+			{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		},
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		},
+	}
+	certManagerConfig := config.NewCustomizedCertManager(config.CertManagerDefaultURL, config.CertManagerDefaultVersion, config.CertManagerDefaultTimeout.String(), "custom-cert-manager", customization)
+
+	got, err := customizeCertManager(objs, certManagerConfig)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(HaveLen(4))
+
+	// Assert the namespace is moved.
+	g.Expect(got[0].GetName()).To(Equal("custom-cert-manager"))
+
+	// Assert the Deployment is moved and customized.
+	d := &appsv1.Deployment{}
+	g.Expect(scheme.Scheme.Convert(&got[1], d, nil)).To(Succeed())
+	g.Expect(d.Namespace).To(Equal("custom-cert-manager"))
+	g.Expect(d.Spec.Replicas).To(Equal(pointer.Int32(3)))
+	g.Expect(d.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{
+		"kubernetes.io/os":              "linux",
+		"node-role.kubernetes.io/infra": "",
+	}))
+	g.Expect(d.Spec.Template.Spec.Tolerations).To(Equal(customization.Tolerations))
+	g.Expect(d.Spec.Template.Spec.Containers[0].Resources).To(Equal(*customization.Resources))
+	g.Expect(d.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{
+		"--v=2",
+		"--dynamic-serving-ca-secret-namespace=$(POD_NAMESPACE)",
+		"--dynamic-serving-dns-names=cert-manager-webhook,cert-manager-webhook.custom-cert-manager,cert-manager-webhook.custom-cert-manager.svc",
+	}))
+
+	// Assert objects in other namespaces are not moved, but their references to the cert-manager namespace are fixed.
+	g.Expect(got[2].GetNamespace()).To(Equal("kube-system"))
+	subjects, _, err := unstructured.NestedSlice(got[2].Object, "subjects")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(subjects[0]).To(HaveKeyWithValue("namespace", "custom-cert-manager"))
+
+	// Assert webhook references to the cert-manager namespace are fixed.
+	g.Expect(got[3].GetAnnotations()).To(HaveKeyWithValue("cert-manager.io/inject-ca-from-secret", "custom-cert-manager/cert-manager-webhook-ca"))
+	webhooks, _, err := unstructured.NestedSlice(got[3].Object, "webhooks")
+	g.Expect(err).ToNot(HaveOccurred())
+	namespace, _, err := unstructured.NestedString(webhooks[0].(map[string]interface{}), "clientConfig", "service", "namespace")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(namespace).To(Equal("custom-cert-manager"))
+}
+
 func Test_GetTimeout(t *testing.T) {
 	pollImmediateWaiter := func(interval, timeout time.Duration, condition wait.ConditionFunc) error {
 		return nil
@@ -751,7 +857,8 @@ func Test_certManagerClient_EnsureLatestVersion(t *testing.T) {
 			g := NewWithT(t)
 
 			cm := &certManagerClient{
-				proxy: tt.fields.proxy,
+				configClient: newFakeConfig(),
+				proxy:        tt.fields.proxy,
 			}
 
 			err := cm.EnsureLatestVersion()
@@ -797,6 +904,10 @@ func (f fakeConfigClient) ImageMeta() config.ImageMetaClient {
 	return f.internalclient.ImageMeta()
 }
 
+func (f fakeConfigClient) NetworkPolicies() config.NetworkPoliciesClient {
+	return f.internalclient.NetworkPolicies()
+}
+
 func (f *fakeConfigClient) WithVar(key, value string) *fakeConfigClient {
 	f.fakeReader.WithVar(key, value)
 	return f
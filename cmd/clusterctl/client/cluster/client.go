@@ -86,6 +86,10 @@ type Client interface {
 
 	// Topology returns a TopologyClient that can be used for performing dry run executions of the topology reconciler.
 	Topology() TopologyClient
+
+	// StorageVersionAuditor returns a StorageVersionAuditor that can be used for auditing the API versions used by
+	// the objects of the CRDs installed by clusterctl, and for migrating them to the current storage version.
+	StorageVersionAuditor() StorageVersionAuditor
 }
 
 // PollImmediateWaiter tries a condition func until it returns true, an error, or the timeout is reached.
@@ -151,6 +155,10 @@ func (c *clusterClient) Topology() TopologyClient {
 	return newTopologyClient(c.proxy, c.ProviderInventory())
 }
 
+func (c *clusterClient) StorageVersionAuditor() StorageVersionAuditor {
+	return newStorageVersionAuditor(c.proxy)
+}
+
 // Option is a configuration option supplied to New.
 type Option func(*clusterClient)
 
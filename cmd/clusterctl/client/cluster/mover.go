@@ -35,22 +35,44 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/yaml"
 )
 
+// ResourceMutatorFunc holds the type for mutators to be applied on resources during a move operation.
+type ResourceMutatorFunc func(u *unstructured.Unstructured) error
+
 // ObjectMover defines methods for moving Cluster API objects to another management cluster.
 type ObjectMover interface {
 	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
-	Move(namespace string, toCluster Client, dryRun bool) error
+	// Only the objects selected by the filter are moved; mutators, if any, are applied to all the objects before they are created
+	// in the target management cluster.
+	Move(namespace string, toCluster Client, dryRun bool, filter MoveFilter, mutators ...ResourceMutatorFunc) error
 
-	// ToDirectory writes all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target directory.
-	ToDirectory(namespace string, directory string) error
+	// ToDirectory writes all the Cluster API objects existing in a namespace (or from all the namespaces if empty) and selected by
+	// the filter to a target directory.
+	ToDirectory(namespace string, directory string, filter MoveFilter) error
 
 	// FromDirectory reads all the Cluster API objects existing in a configured directory to a target management cluster.
 	FromDirectory(toCluster Client, directory string) error
+
+	// Backup writes all the Cluster API objects existing in a namespace (or from all the namespaces if empty), including
+	// their status and the secrets they depend on, to a versioned backup archive together with the list of installed providers.
+	Backup(namespace string, file string) error
+
+	// Restore reads the Cluster API objects from a backup archive into a target management cluster, after checking that
+	// the providers installed at the time of the backup exist in the target cluster with a version >= of the backed up version.
+	// If clusters are specified, only the objects belonging to those Clusters and the objects not belonging to any Cluster
+	// (e.g. ClusterClasses) are restored.
+	Restore(toCluster Client, file string, clusters ...string) error
+
+	// Clone creates a copy of the spec objects of a Cluster, e.g. templates, control plane and MachineDeployments, with a new
+	// name and/or namespace in the same management cluster; instances and secrets generated by Cluster API are not copied, so
+	// the clone is provisioned from scratch. It returns the objects of the clone.
+	Clone(options CloneOptions) ([]unstructured.Unstructured, error)
 }
 
 // objectMover implements the ObjectMover interface.
@@ -63,7 +85,7 @@ type objectMover struct {
 // ensure objectMover implements the ObjectMover interface.
 var _ ObjectMover = &objectMover{}
 
-func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool) error {
+func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool, filter MoveFilter, mutators ...ResourceMutatorFunc) error {
 	log := logf.Log
 	log.Info("Performing move...")
 	o.dryRun = dryRun
@@ -80,7 +102,7 @@ func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool) erro
 		}
 	}
 
-	objectGraph, err := o.getObjectGraph(namespace)
+	objectGraph, err := o.getObjectGraph(namespace, filter)
 	if err != nil {
 		return errors.Wrap(err, "failed to get object graph")
 	}
@@ -91,14 +113,14 @@ func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool) erro
 		proxy = toCluster.Proxy()
 	}
 
-	return o.move(objectGraph, proxy)
+	return o.move(objectGraph, proxy, mutators...)
 }
 
-func (o *objectMover) ToDirectory(namespace string, directory string) error {
+func (o *objectMover) ToDirectory(namespace string, directory string, filter MoveFilter) error {
 	log := logf.Log
 	log.Info("Moving to directory...")
 
-	objectGraph, err := o.getObjectGraph(namespace)
+	objectGraph, err := o.getObjectGraph(namespace, filter)
 	if err != nil {
 		return errors.Wrap(err, "failed to get object graph")
 	}
@@ -177,7 +199,7 @@ func (o *objectMover) filesToObjs(dir string) ([]unstructured.Unstructured, erro
 	return objs, nil
 }
 
-func (o *objectMover) getObjectGraph(namespace string) (*objectGraph, error) {
+func (o *objectMover) getObjectGraph(namespace string, filter MoveFilter) (*objectGraph, error) {
 	objectGraph := newObjectGraph(o.fromProxy, o.fromProviderInventory)
 
 	// Gets all the types defined by the CRDs installed by clusterctl plus the ConfigMap/Secret core types.
@@ -193,6 +215,11 @@ func (o *objectMover) getObjectGraph(namespace string) (*objectGraph, error) {
 		return nil, errors.Wrap(err, "failed to discover the object graph")
 	}
 
+	// Removes from the object graph the objects not selected for the move/toDirectory operation.
+	if err := objectGraph.filter(filter); err != nil {
+		return nil, errors.Wrap(err, "failed to filter the object graph")
+	}
+
 	// Checks if Cluster API has already completed the provisioning of the infrastructure for the objects involved in the move/toDirectory operation.
 	// This is required because if the infrastructure is provisioned, then we can reasonably assume that the objects we are moving/backing up are
 	// not currently waiting for long-running reconciliation loops, and so we can safely rely on the pause field on the Cluster object
@@ -308,7 +335,7 @@ func getMachineObj(proxy Proxy, machine *node, machineObj *clusterv1.Machine) er
 }
 
 // Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
-func (o *objectMover) move(graph *objectGraph, toProxy Proxy) error {
+func (o *objectMover) move(graph *objectGraph, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
 	log := logf.Log
 
 	clusters := graph.getClusters()
@@ -328,9 +355,18 @@ func (o *objectMover) move(graph *objectGraph, toProxy Proxy) error {
 		return errors.Wrap(err, "error pausing ClusterClasses")
 	}
 
+	// Sets the pre-move hook annotation on the objects whose controllers are not quiesced by pausing the Cluster, e.g.
+	// MachineHealthChecks, so they do not act on objects while the move is in progress; the annotation is moved together
+	// with the objects.
+	preMoveHookNodes := graph.getPreMoveHookNodes()
+	log.V(1).Info("Setting the pre-move hook on the source objects", "Objects", len(preMoveHookNodes))
+	if err := setPreMoveHook(o.fromProxy, preMoveHookNodes, true, o.dryRun); err != nil {
+		return errors.Wrap(err, "error setting the pre-move hook")
+	}
+
 	// Ensure all the expected target namespaces are in place before creating objects.
 	log.V(1).Info("Creating target namespaces, if missing")
-	if err := o.ensureNamespaces(graph, toProxy); err != nil {
+	if err := o.ensureNamespaces(graph, toProxy, mutators...); err != nil {
 		return err
 	}
 
@@ -344,7 +380,7 @@ func (o *objectMover) move(graph *objectGraph, toProxy Proxy) error {
 	// Create all objects group by group, ensuring all the ownerReferences are re-created.
 	log.Info("Creating objects in the target cluster")
 	for groupIndex := 0; groupIndex < len(moveSequence.groups); groupIndex++ {
-		if err := o.createGroup(moveSequence.getGroup(groupIndex), toProxy); err != nil {
+		if err := o.createGroup(moveSequence.getGroup(groupIndex), toProxy, mutators...); err != nil {
 			return err
 		}
 	}
@@ -359,13 +395,20 @@ func (o *objectMover) move(graph *objectGraph, toProxy Proxy) error {
 
 	// Resume the ClusterClasses in the target management cluster, so the controllers start reconciling it.
 	log.V(1).Info("Resuming the target ClusterClasses")
-	if err := setClusterClassPause(toProxy, clusterClasses, false, o.dryRun); err != nil {
+	if err := setClusterClassPause(toProxy, clusterClasses, false, o.dryRun, mutators...); err != nil {
 		return errors.Wrap(err, "error resuming ClusterClasses")
 	}
 
 	// Reset the pause field on the Cluster object in the target management cluster, so the controllers start reconciling it.
 	log.V(1).Info("Resuming the target cluster")
-	return setClusterPause(toProxy, clusters, false, o.dryRun)
+	if err := setClusterPause(toProxy, clusters, false, o.dryRun, mutators...); err != nil {
+		return err
+	}
+
+	// Remove the pre-move hook annotation from the objects in the target management cluster only after the Clusters
+	// are resumed, so e.g. MachineHealthChecks start remediating only when Machines are reconciled again.
+	log.V(1).Info("Removing the pre-move hook from the target objects")
+	return setPreMoveHook(toProxy, preMoveHookNodes, false, o.dryRun, mutators...)
 }
 
 func (o *objectMover) toDirectory(graph *objectGraph, directory string) error {
@@ -532,7 +575,8 @@ func getMoveSequence(graph *objectGraph) *moveSequence {
 }
 
 // setClusterPause sets the paused field on nodes referring to Cluster objects.
-func setClusterPause(proxy Proxy, clusters []*node, value bool, dryRun bool) error {
+// Mutators, if any, are used to identify the Cluster objects in a target management cluster.
+func setClusterPause(proxy Proxy, clusters []*node, value bool, dryRun bool, mutators ...ResourceMutatorFunc) error {
 	if dryRun {
 		return nil
 	}
@@ -553,7 +597,7 @@ func setClusterPause(proxy Proxy, clusters []*node, value bool, dryRun bool) err
 
 		// Nb. The operation is wrapped in a retry loop to make setClusterPause more resilient to unexpected conditions.
 		if err := retryWithExponentialBackoff(setClusterPauseBackoff, func() error {
-			return patchCluster(proxy, cluster, patch)
+			return patchCluster(proxy, cluster, patch, mutators...)
 		}); err != nil {
 			return errors.Wrapf(err, "error setting Cluster.Spec.Paused=%t", value)
 		}
@@ -562,7 +606,8 @@ func setClusterPause(proxy Proxy, clusters []*node, value bool, dryRun bool) err
 }
 
 // setClusterClassPause sets the paused annotation on nodes referring to ClusterClass objects.
-func setClusterClassPause(proxy Proxy, clusterclasses []*node, pause bool, dryRun bool) error {
+// Mutators, if any, are used to identify the ClusterClass objects in a target management cluster.
+func setClusterClassPause(proxy Proxy, clusterclasses []*node, pause bool, dryRun bool, mutators ...ResourceMutatorFunc) error {
 	if dryRun {
 		return nil
 	}
@@ -580,7 +625,7 @@ func setClusterClassPause(proxy Proxy, clusterclasses []*node, pause bool, dryRu
 
 		// Nb. The operation is wrapped in a retry loop to make setClusterClassPause more resilient to unexpected conditions.
 		if err := retryWithExponentialBackoff(setClusterClassPauseBackoff, func() error {
-			return pauseClusterClass(proxy, clusterclass, pause)
+			return pauseClusterClass(proxy, clusterclass, pause, mutators...)
 		}); err != nil {
 			return errors.Wrapf(err, "error updating ClusterClass %s/%s", clusterclass.identity.Namespace, clusterclass.identity.Name)
 		}
@@ -588,17 +633,77 @@ func setClusterClassPause(proxy Proxy, clusterclasses []*node, pause bool, dryRu
 	return nil
 }
 
+// setPreMoveHook sets or removes the pre-move hook annotation on nodes.
+// Mutators, if any, are used to identify the objects in a target management cluster.
+func setPreMoveHook(proxy Proxy, nodes []*node, value bool, dryRun bool, mutators ...ResourceMutatorFunc) error {
+	if dryRun {
+		return nil
+	}
+
+	log := logf.Log
+
+	setPreMoveHookBackoff := newWriteBackoff()
+	for i := range nodes {
+		n := nodes[i]
+		log.V(5).Info("Set pre-move hook annotation", "value", value, n.identity.Kind, klog.KRef(n.identity.Namespace, n.identity.Name))
+
+		// Nb. The operation is wrapped in a retry loop to make setPreMoveHook more resilient to unexpected conditions.
+		if err := retryWithExponentialBackoff(setPreMoveHookBackoff, func() error {
+			return patchPreMoveHook(proxy, n, value, mutators...)
+		}); err != nil {
+			return errors.Wrapf(err, "error updating %s %s/%s", n.identity.Kind, n.identity.Namespace, n.identity.Name)
+		}
+	}
+	return nil
+}
+
+// patchPreMoveHook sets or removes the pre-move hook annotation on a node.
+func patchPreMoveHook(proxy Proxy, n *node, value bool, mutators ...ResourceMutatorFunc) error {
+	c, err := proxy.NewClient()
+	if err != nil {
+		return errors.Wrap(err, "error creating client")
+	}
+
+	objKey, err := mutatedObjectKey(n, mutators...)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(n.identity.APIVersion)
+	obj.SetKind(n.identity.Kind)
+	if err := c.Get(ctx, objKey, obj); err != nil {
+		return errors.Wrapf(err, "error reading %s %s/%s", n.identity.Kind, objKey.Namespace, objKey.Name)
+	}
+
+	original := obj.DeepCopy()
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if value {
+		annotations[clusterv1.PreMoveHookAnnotation] = ""
+	} else {
+		delete(annotations, clusterv1.PreMoveHookAnnotation)
+	}
+	obj.SetAnnotations(annotations)
+
+	if err := c.Patch(ctx, obj, client.MergeFrom(original)); err != nil {
+		return errors.Wrapf(err, "error patching %s %s/%s", n.identity.Kind, objKey.Namespace, objKey.Name)
+	}
+	return nil
+}
+
 // patchCluster applies a patch to a node referring to a Cluster object.
-func patchCluster(proxy Proxy, cluster *node, patch client.Patch) error {
+func patchCluster(proxy Proxy, cluster *node, patch client.Patch, mutators ...ResourceMutatorFunc) error {
 	cFrom, err := proxy.NewClient()
 	if err != nil {
 		return err
 	}
 
 	clusterObj := &clusterv1.Cluster{}
-	clusterObjKey := client.ObjectKey{
-		Namespace: cluster.identity.Namespace,
-		Name:      cluster.identity.Name,
+	clusterObjKey, err := mutatedObjectKey(cluster, mutators...)
+	if err != nil {
+		return err
 	}
 
 	if err := cFrom.Get(ctx, clusterObjKey, clusterObj); err != nil {
@@ -614,7 +719,7 @@ func patchCluster(proxy Proxy, cluster *node, patch client.Patch) error {
 	return nil
 }
 
-func pauseClusterClass(proxy Proxy, n *node, pause bool) error {
+func pauseClusterClass(proxy Proxy, n *node, pause bool, mutators ...ResourceMutatorFunc) error {
 	cFrom, err := proxy.NewClient()
 	if err != nil {
 		return errors.Wrap(err, "error creating client")
@@ -622,17 +727,17 @@ func pauseClusterClass(proxy Proxy, n *node, pause bool) error {
 
 	// Get the ClusterClass from the server
 	clusterClass := &clusterv1.ClusterClass{}
-	clusterClassObjKey := client.ObjectKey{
-		Name:      n.identity.Name,
-		Namespace: n.identity.Namespace,
+	clusterClassObjKey, err := mutatedObjectKey(n, mutators...)
+	if err != nil {
+		return err
 	}
 	if err := cFrom.Get(ctx, clusterClassObjKey, clusterClass); err != nil {
-		return errors.Wrapf(err, "error reading ClusterClass %s/%s", n.identity.Namespace, n.identity.Name)
+		return errors.Wrapf(err, "error reading ClusterClass %s/%s", clusterClassObjKey.Namespace, clusterClassObjKey.Name)
 	}
 
 	patchHelper, err := patch.NewHelper(clusterClass, cFrom)
 	if err != nil {
-		return errors.Wrapf(err, "error creating patcher for ClusterClass %s/%s", n.identity.Namespace, n.identity.Name)
+		return errors.Wrapf(err, "error creating patcher for ClusterClass %s/%s", clusterClassObjKey.Namespace, clusterClassObjKey.Name)
 	}
 
 	// Update the annotation to the desired state
@@ -651,14 +756,15 @@ func pauseClusterClass(proxy Proxy, n *node, pause bool) error {
 	// Update the ClusterClass with the new annotations.
 	clusterClass.SetAnnotations(ccAnnotations)
 	if err := patchHelper.Patch(ctx, clusterClass); err != nil {
-		return errors.Wrapf(err, "error patching ClusterClass %s/%s", n.identity.Namespace, n.identity.Name)
+		return errors.Wrapf(err, "error patching ClusterClass %s/%s", clusterClassObjKey.Namespace, clusterClassObjKey.Name)
 	}
 
 	return nil
 }
 
 // ensureNamespaces ensures all the expected target namespaces are in place before creating objects.
-func (o *objectMover) ensureNamespaces(graph *objectGraph, toProxy Proxy) error {
+// Mutators, if any, are used to compute the target namespaces.
+func (o *objectMover) ensureNamespaces(graph *objectGraph, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
 	if o.dryRun {
 		return nil
 	}
@@ -671,7 +777,11 @@ func (o *objectMover) ensureNamespaces(graph *objectGraph, toProxy Proxy) error
 			continue
 		}
 
-		namespace := node.identity.Namespace
+		key, err := mutatedObjectKey(node, mutators...)
+		if err != nil {
+			return err
+		}
+		namespace := key.Namespace
 
 		// If the namespace was already processed, skip it.
 		if namespaces.Has(namespace) {
@@ -753,7 +863,7 @@ func (o *objectMover) ensureNamespace(toProxy Proxy, namespace string) error {
 }
 
 // createGroup creates all the Kubernetes objects into the target management cluster corresponding to the object graph nodes in a moveGroup.
-func (o *objectMover) createGroup(group moveGroup, toProxy Proxy) error {
+func (o *objectMover) createGroup(group moveGroup, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
 	createTargetObjectBackoff := newWriteBackoff()
 	errList := []error{}
 
@@ -761,7 +871,7 @@ func (o *objectMover) createGroup(group moveGroup, toProxy Proxy) error {
 		// Creates the Kubernetes object corresponding to the nodeToCreate.
 		// Nb. The operation is wrapped in a retry loop to make move more resilient to unexpected conditions.
 		err := retryWithExponentialBackoff(createTargetObjectBackoff, func() error {
-			return o.createTargetObject(nodeToCreate, toProxy)
+			return o.createTargetObject(nodeToCreate, toProxy, mutators...)
 		})
 		if err != nil {
 			errList = append(errList, err)
@@ -820,7 +930,8 @@ func (o *objectMover) restoreGroup(group moveGroup, toProxy Proxy) error {
 }
 
 // createTargetObject creates the Kubernetes object in the target Management cluster corresponding to the object graph node, taking care of restoring the OwnerReference with the owner nodes, if any.
-func (o *objectMover) createTargetObject(nodeToCreate *node, toProxy Proxy) error {
+// Mutators, if any, are applied to the object before creating it.
+func (o *objectMover) createTargetObject(nodeToCreate *node, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
 	log := logf.Log
 	log.V(1).Info("Creating", nodeToCreate.identity.Kind, nodeToCreate.identity.Name, "Namespace", nodeToCreate.identity.Namespace)
 
@@ -856,6 +967,12 @@ func (o *objectMover) createTargetObject(nodeToCreate *node, toProxy Proxy) erro
 	// Rebuild the owne reference chain
 	o.buildOwnerChain(obj, nodeToCreate)
 
+	// Apply the mutators, if any, and use the mutated object key when looking for the object in the target management cluster.
+	if err := applyMutators(obj, mutators...); err != nil {
+		return err
+	}
+	objKey = client.ObjectKeyFromObject(obj)
+
 	// FIXME Workaround for https://github.com/kubernetes/kubernetes/issues/32220. Remove when the issue is fixed.
 	// If the resource already exists, the API server ordinarily returns an AlreadyExists error. Due to the above issue, if the resource has a non-empty metadata.generateName field, the API server returns a ServerTimeoutError. To ensure that the API server returns an AlreadyExists error, we set the metadata.generateName field to an empty string.
 	if len(obj.GetName()) > 0 && len(obj.GetGenerateName()) > 0 {
@@ -1005,19 +1122,64 @@ func (o *objectMover) restoreTargetObject(nodeToCreate *node, toProxy Proxy) err
 	// Rebuild the owner reference chain
 	o.buildOwnerChain(obj, nodeToCreate)
 
+	// The status is ignored on create, so it must be restored after the object is created.
+	status, hasStatus, err := unstructured.NestedFieldCopy(obj.Object, "status")
+	if err != nil {
+		return errors.Wrapf(err, "error reading the status of %q %s/%s",
+			obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	}
+
+	created := true
 	if err := cTo.Create(ctx, obj); err != nil {
 		if !apierrors.IsAlreadyExists(err) {
 			return errors.Wrapf(err, "error creating %q %s/%s",
 				obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
 		}
+		created = false
 	}
 
 	// Stores the newUID assigned to the newly created object.
 	nodeToCreate.newUID = obj.GetUID()
 
+	if created && hasStatus {
+		if err := unstructured.SetNestedField(obj.Object, status, "status"); err != nil {
+			return errors.Wrapf(err, "error setting the status of %q %s/%s",
+				obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+		}
+		// NotFound is returned for types without the status subresource, which have the status restored on create.
+		if err := cTo.Status().Update(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "error restoring the status of %q %s/%s",
+				obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+		}
+	}
+
+	return nil
+}
+
+// applyMutators applies the mutators, in order, to obj.
+func applyMutators(obj *unstructured.Unstructured, mutators ...ResourceMutatorFunc) error {
+	for _, mutator := range mutators {
+		if err := mutator(obj); err != nil {
+			return errors.Wrapf(err, "error applying resource mutator to %q %s/%s",
+				obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+		}
+	}
 	return nil
 }
 
+// mutatedObjectKey returns the key of the object corresponding to the object graph node after applying the mutators, if any.
+func mutatedObjectKey(n *node, mutators ...ResourceMutatorFunc) (client.ObjectKey, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(n.identity.APIVersion)
+	obj.SetKind(n.identity.Kind)
+	obj.SetNamespace(n.identity.Namespace)
+	obj.SetName(n.identity.Name)
+	if err := applyMutators(obj, mutators...); err != nil {
+		return client.ObjectKey{}, err
+	}
+	return client.ObjectKeyFromObject(obj), nil
+}
+
 // Recreate all the OwnerReferences using the newUID of the owner nodes.
 func (o *objectMover) buildOwnerChain(obj *unstructured.Unstructured, n *node) {
 	if len(n.owners) > 0 {
@@ -1138,17 +1300,21 @@ func (o *objectMover) checkTargetProviders(toInventory InventoryClient) error {
 		return errors.Wrapf(err, "failed to get provider list from the target cluster")
 	}
 
-	// Checks all the providers installed in the source cluster
+	return checkProviderVersions(fromProviders.Items, toProviders.Items, "source cluster")
+}
+
+// checkProviderVersions checks that all the source providers exists in the target providers as well (with a version >= of the source version).
+func checkProviderVersions(fromProviders, toProviders []clusterctlv1.Provider, source string) error {
 	errList := []error{}
-	for _, sourceProvider := range fromProviders.Items {
+	for _, sourceProvider := range fromProviders {
 		sourceVersion, err := version.ParseSemantic(sourceProvider.Version)
 		if err != nil {
-			return errors.Wrapf(err, "unable to parse version %q for the %s provider in the source cluster", sourceProvider.Version, sourceProvider.InstanceName())
+			return errors.Wrapf(err, "unable to parse version %q for the %s provider in the %s", sourceProvider.Version, sourceProvider.InstanceName(), source)
 		}
 
 		// Check corresponding providers in the target cluster and gets the latest version installed.
 		var maxTargetVersion *version.Version
-		for _, targetProvider := range toProviders.Items {
+		for _, targetProvider := range toProviders {
 			// Skips other providers.
 			if !sourceProvider.SameAs(targetProvider) {
 				continue
@@ -1168,7 +1334,7 @@ func (o *objectMover) checkTargetProviders(toInventory InventoryClient) error {
 		}
 
 		if !maxTargetVersion.AtLeast(sourceVersion) {
-			errList = append(errList, errors.Errorf("provider %s in the target cluster is older than in the source cluster (source: %s, target: %s)", sourceProvider.Name, sourceVersion.String(), maxTargetVersion.String()))
+			errList = append(errList, errors.Errorf("provider %s in the target cluster is older than in the %s (%s: %s, target: %s)", sourceProvider.Name, source, source, sourceVersion.String(), maxTargetVersion.String()))
 		}
 	}
 
@@ -0,0 +1,380 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kyaml "sigs.k8s.io/yaml"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/util/yaml"
+	"sigs.k8s.io/cluster-api/version"
+)
+
+const (
+	// BackupArchiveVersion is the version of the format of the backup archives written by clusterctl.
+	BackupArchiveVersion = "v1"
+
+	// backupMetadataFile is the name of the file describing the backup in a backup archive.
+	backupMetadataFile = "metadata.yaml"
+
+	// backupObjectsDir is the directory containing the backed up objects in a backup archive.
+	backupObjectsDir = "objects"
+)
+
+// BackupMetadata describes a backup archive.
+type BackupMetadata struct {
+	// Version is the version of the format of the backup archive.
+	Version string `json:"version"`
+
+	// ClusterctlVersion is the version of clusterctl used for the backup.
+	ClusterctlVersion string `json:"clusterctlVersion"`
+
+	// CreationTimestamp is the time the backup was created.
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+
+	// Namespace is the namespace of the backed up objects; it is empty if objects from all the namespaces were backed up.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Providers are the providers installed in the management cluster at the time of the backup.
+	Providers []BackupProvider `json:"providers"`
+
+	// Clusters are the Clusters included in the backup, in the namespace/name format.
+	Clusters []string `json:"clusters"`
+}
+
+// BackupProvider describes a provider installed in the management cluster at the time of a backup.
+type BackupProvider struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	ProviderName string `json:"providerName"`
+	Type         string `json:"type"`
+	Version      string `json:"version"`
+}
+
+func (o *objectMover) Backup(namespace string, file string) error {
+	log := logf.Log
+	log.Info("Performing backup...")
+
+	objectGraph, err := o.getObjectGraph(namespace, MoveFilter{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get object graph")
+	}
+
+	providers, err := o.fromProviderInventory.List()
+	if err != nil {
+		return errors.Wrap(err, "failed to get provider list from the management cluster")
+	}
+
+	metadata := &BackupMetadata{
+		Version:           BackupArchiveVersion,
+		ClusterctlVersion: version.Get().GitVersion,
+		CreationTimestamp: metav1.NewTime(time.Now().UTC()),
+		Namespace:         namespace,
+		Providers:         []BackupProvider{},
+		Clusters:          []string{},
+	}
+	for _, p := range providers.Items {
+		metadata.Providers = append(metadata.Providers, BackupProvider{
+			Name:         p.Name,
+			Namespace:    p.Namespace,
+			ProviderName: p.ProviderName,
+			Type:         p.Type,
+			Version:      p.Version,
+		})
+	}
+	for _, cluster := range objectGraph.getClusters() {
+		metadata.Clusters = append(metadata.Clusters, types.NamespacedName{Namespace: cluster.identity.Namespace, Name: cluster.identity.Name}.String())
+	}
+	sort.Strings(metadata.Clusters)
+
+	// Objects are first saved into a temporary directory, reusing the toDirectory sequence, and then archived.
+	directory, err := os.MkdirTemp("", "clusterctl-backup")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(directory)
+
+	if err := o.toDirectory(objectGraph, directory); err != nil {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("Writing backup archive %s", file))
+	return writeBackupArchive(file, metadata, directory)
+}
+
+func (o *objectMover) Restore(toCluster Client, file string, clusters ...string) error {
+	log := logf.Log
+	log.Info("Performing restore...")
+
+	metadata, objs, err := readBackupArchive(file)
+	if err != nil {
+		return err
+	}
+	log.Info("Restoring backup", "Version", metadata.Version, "CreationTimestamp", metadata.CreationTimestamp.String(), "Clusters", len(metadata.Clusters))
+
+	// Checks that all the providers installed at the time of the backup exist in the target cluster as well,
+	// with a version >= of the backed up version, so all the objects can be restored.
+	backupProviders := make([]clusterctlv1.Provider, 0, len(metadata.Providers))
+	for _, p := range metadata.Providers {
+		backupProviders = append(backupProviders, clusterctlv1.Provider{
+			ObjectMeta:   metav1.ObjectMeta{Name: p.Name, Namespace: p.Namespace},
+			ProviderName: p.ProviderName,
+			Type:         p.Type,
+			Version:      p.Version,
+		})
+	}
+	toProviders, err := toCluster.ProviderInventory().List()
+	if err != nil {
+		return errors.Wrap(err, "failed to get provider list from the target cluster")
+	}
+	if err := checkProviderVersions(backupProviders, toProviders.Items, "backup"); err != nil {
+		return errors.Wrap(err, "failed to check providers in target cluster")
+	}
+
+	// Build an empty object graph used for the restore sequence not tied to a specific namespace
+	objectGraph := newObjectGraph(o.fromProxy, o.fromProviderInventory)
+
+	// Gets all the types defined by the CRDs installed by clusterctl plus the ConfigMap/Secret core types.
+	if err := objectGraph.getDiscoveryTypes(); err != nil {
+		return errors.Wrap(err, "failed to retrieve discovery types")
+	}
+
+	for i := range objs {
+		if err := objectGraph.addRestoredObj(&objs[i]); err != nil {
+			return err
+		}
+	}
+
+	// Completes rebuilding the graph from the archive by searching for soft ownership relations such as secrets linked to the cluster
+	// by a naming convention (without any explicit OwnerReference).
+	objectGraph.setSoftOwnership()
+
+	// Completes the graph by setting for each node the list of tenants the node belongs to.
+	objectGraph.setTenants()
+
+	// Check whether nodes are not included in GVK considered for restore.
+	objectGraph.checkVirtualNode()
+
+	if len(clusters) > 0 {
+		if err := objectGraph.filterClusters(clusters); err != nil {
+			return err
+		}
+	}
+
+	return o.fromDirectory(objectGraph, toCluster.Proxy())
+}
+
+// filterClusters removes from the object graph all the nodes belonging only to Clusters not included in the list;
+// nodes not belonging to any Cluster, e.g. ClusterClasses, ClusterResourceSets or global identities, are preserved.
+// Clusters can be identified by name or in the namespace/name format.
+func (o *objectGraph) filterClusters(clusters []string) error {
+	selected := map[*node]empty{}
+	found := sets.Set[string]{}
+	for _, cluster := range o.getClusters() {
+		key := types.NamespacedName{Namespace: cluster.identity.Namespace, Name: cluster.identity.Name}.String()
+		for _, c := range clusters {
+			if c == cluster.identity.Name || c == key {
+				selected[cluster] = empty{}
+				found.Insert(c)
+			}
+		}
+	}
+	if missing := sets.New[string](clusters...).Difference(found); missing.Len() > 0 {
+		return errors.Errorf("clusters %s not found in the backup", strings.Join(sets.List(missing), ", "))
+	}
+
+	o.removeUnselectedClusters(selected)
+	return nil
+}
+
+// removeUnselectedClusters removes from the object graph all the nodes belonging only to Clusters not included in selected;
+// nodes not belonging to any Cluster are preserved.
+func (o *objectGraph) removeUnselectedClusters(selected map[*node]empty) {
+	isCluster := map[*node]empty{}
+	for _, cluster := range o.getClusters() {
+		isCluster[cluster] = empty{}
+	}
+
+	removed := map[*node]empty{}
+	for uid, n := range o.uidToNode {
+		belongsToCluster, belongsToSelectedCluster := false, false
+		for tenant := range n.tenant {
+			if _, ok := isCluster[tenant]; !ok {
+				continue
+			}
+			belongsToCluster = true
+			if _, ok := selected[tenant]; ok {
+				belongsToSelectedCluster = true
+			}
+		}
+		if belongsToCluster && !belongsToSelectedCluster {
+			removed[n] = empty{}
+			delete(o.uidToNode, uid)
+		}
+	}
+
+	// Drop references to the removed nodes, so they are not considered when defining the move or restore sequence.
+	for _, n := range o.uidToNode {
+		for owner := range n.owners {
+			if _, ok := removed[owner]; ok {
+				delete(n.owners, owner)
+			}
+		}
+		for owner := range n.softOwners {
+			if _, ok := removed[owner]; ok {
+				delete(n.softOwners, owner)
+			}
+		}
+	}
+}
+
+// writeBackupArchive writes a gzipped tar archive with the backup metadata and the objects saved in a directory.
+func writeBackupArchive(file string, metadata *BackupMetadata, directory string) (reterr error) {
+	metadataYAML, err := kyaml.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal backup metadata")
+	}
+
+	// The archive contains secrets, so it must be readable only by the current user.
+	f, err := os.OpenFile(filepath.Clean(file), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create backup archive %s", file)
+	}
+	defer func() {
+		if err := f.Close(); err != nil && reterr == nil {
+			reterr = errors.Wrapf(err, "failed to write backup archive %s", file)
+		}
+	}()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	writeFile := func(name string, content []byte) error {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(content)),
+			ModTime: metadata.CreationTimestamp.Time,
+		}); err != nil {
+			return err
+		}
+		_, err := tw.Write(content)
+		return err
+	}
+
+	if err := writeFile(backupMetadataFile, metadataYAML); err != nil {
+		return errors.Wrapf(err, "failed to write backup archive %s", file)
+	}
+
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		return err
+	}
+	for i := range files {
+		content, err := os.ReadFile(filepath.Clean(filepath.Join(directory, files[i].Name())))
+		if err != nil {
+			return err
+		}
+		if err := writeFile(path.Join(backupObjectsDir, files[i].Name()), content); err != nil {
+			return errors.Wrapf(err, "failed to write backup archive %s", file)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write backup archive %s", file)
+	}
+	if err := gw.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write backup archive %s", file)
+	}
+	return nil
+}
+
+// readBackupArchive reads the backup metadata and the objects from a backup archive.
+func readBackupArchive(file string) (*BackupMetadata, []unstructured.Unstructured, error) {
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to open backup archive %s", file)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read backup archive %s", file)
+	}
+	defer gr.Close()
+
+	var metadata *BackupMetadata
+	rawYAMLs := make([][]byte, 0)
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read backup archive %s", file)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read %s from backup archive %s", header.Name, file)
+		}
+
+		switch {
+		case header.Name == backupMetadataFile:
+			metadata = &BackupMetadata{}
+			if err := kyaml.UnmarshalStrict(content, metadata); err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to parse %s from backup archive %s", header.Name, file)
+			}
+		case path.Dir(header.Name) == backupObjectsDir:
+			rawYAMLs = append(rawYAMLs, content)
+		}
+	}
+
+	if metadata == nil {
+		return nil, nil, errors.Errorf("invalid backup archive %s: %s not found", file, backupMetadataFile)
+	}
+	if metadata.Version != BackupArchiveVersion {
+		return nil, nil, errors.Errorf("unsupported backup archive version %q, supported version is %q", metadata.Version, BackupArchiveVersion)
+	}
+
+	objs, err := yaml.ToUnstructured(yaml.JoinYaml(rawYAMLs...))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse objects from backup archive %s", file)
+	}
+	return metadata, objs, nil
+}
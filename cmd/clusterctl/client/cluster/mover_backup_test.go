@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_backupArchive(t *testing.T) {
+	g := NewWithT(t)
+
+	directory := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(directory, "Cluster_ns1_foo.yaml"), []byte(`{"apiVersion":"cluster.x-k8s.io/v1beta1","kind":"Cluster","metadata":{"name":"foo","namespace":"ns1"},"status":{"phase":"Provisioned"}}`), 0600)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(directory, "Secret_ns1_foo-kubeconfig.yaml"), []byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"foo-kubeconfig","namespace":"ns1"}}`), 0600)).To(Succeed())
+
+	metadata := &BackupMetadata{
+		Version:           BackupArchiveVersion,
+		CreationTimestamp: metav1.Now(),
+		Providers: []BackupProvider{
+			{Name: "cluster-api", Namespace: "capi-system", ProviderName: "cluster-api", Type: string(clusterctlv1.CoreProviderType), Version: "v1.4.0"},
+		},
+		Clusters: []string{"ns1/foo"},
+	}
+	file := filepath.Join(t.TempDir(), "backup.tar.gz")
+	g.Expect(writeBackupArchive(file, metadata, directory)).To(Succeed())
+
+	info, err := os.Stat(file)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+	gotMetadata, objs, err := readBackupArchive(file)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gotMetadata.Providers).To(Equal(metadata.Providers))
+	g.Expect(gotMetadata.Clusters).To(Equal(metadata.Clusters))
+	g.Expect(objs).To(HaveLen(2))
+	g.Expect(objs[0].GetKind()).To(Equal("Cluster"))
+	g.Expect(objs[0].Object["status"]).To(HaveKeyWithValue("phase", "Provisioned"))
+	g.Expect(objs[1].GetKind()).To(Equal("Secret"))
+
+	// Archives with an unknown format version are rejected.
+	metadata.Version = "v0"
+	g.Expect(writeBackupArchive(file, metadata, directory)).To(Succeed())
+	_, _, err = readBackupArchive(file)
+	g.Expect(err).To(MatchError(ContainSubstring("unsupported backup archive version")))
+}
+
+func Test_objectGraph_filterClusters(t *testing.T) {
+	objs := []client.Object{}
+	objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+	objs = append(objs, test.NewFakeCluster("ns1", "bar").Objs()...)
+	objs = append(objs, test.NewFakeCluster("ns2", "foo").Objs()...)
+
+	t.Run("keeps only the objects belonging to the selected Clusters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := getObjectGraphWithObjs(objs)
+		g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+		g.Expect(graph.Discovery("")).To(Succeed())
+
+		g.Expect(graph.filterClusters([]string{"ns1/foo"})).To(Succeed())
+
+		moveNodes := graph.getMoveNodes()
+		g.Expect(moveNodes).To(HaveLen(4))
+		for _, n := range moveNodes {
+			g.Expect(n.identity.Namespace).To(Equal("ns1"))
+			g.Expect(strings.HasPrefix(n.identity.Name, "foo")).To(BeTrue(), n.identityStr())
+		}
+
+		moveSequence := getMoveSequence(graph)
+		g.Expect(moveSequence.groups).To(HaveLen(2))
+	})
+
+	t.Run("selects Clusters by name in all the namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := getObjectGraphWithObjs(objs)
+		g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+		g.Expect(graph.Discovery("")).To(Succeed())
+
+		g.Expect(graph.filterClusters([]string{"foo"})).To(Succeed())
+		g.Expect(graph.getClusters()).To(HaveLen(2))
+	})
+
+	t.Run("fails if a Cluster is not in the backup", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := getObjectGraphWithObjs(objs)
+		g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+		g.Expect(graph.Discovery("")).To(Succeed())
+
+		g.Expect(graph.filterClusters([]string{"ns1/foo", "baz"})).To(MatchError(ContainSubstring("clusters baz not found in the backup")))
+	})
+}
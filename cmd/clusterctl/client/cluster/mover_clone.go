@@ -0,0 +1,377 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	secretutil "sigs.k8s.io/cluster-api/util/secret"
+)
+
+// CloneOptions defines the options for cloning a Cluster.
+type CloneOptions struct {
+	// Namespace of the Cluster to be cloned.
+	Namespace string
+
+	// Name of the Cluster to be cloned.
+	Name string
+
+	// ToNamespace is the namespace of the clone; if empty, the namespace of the source Cluster is used.
+	ToNamespace string
+
+	// ToName is the name of the clone.
+	ToName string
+
+	// Variables are the values for the topology variables of the clone (variable name -> value); values are parsed
+	// as JSON, and used as plain strings if they are not valid JSON.
+	Variables map[string]string
+
+	// DryRun means the objects of the clone are computed but not created.
+	DryRun bool
+}
+
+func (o *objectMover) Clone(options CloneOptions) ([]unstructured.Unstructured, error) {
+	log := logf.Log
+	log.Info("Performing clone...")
+	o.dryRun = options.DryRun
+
+	objectGraph := newObjectGraph(o.fromProxy, o.fromProviderInventory)
+	if err := objectGraph.getDiscoveryTypes(); err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve discovery types")
+	}
+	if err := objectGraph.Discovery(options.Namespace); err != nil {
+		return nil, errors.Wrap(err, "failed to discover the object graph")
+	}
+
+	return o.clone(objectGraph, options)
+}
+
+// clone creates a copy of the spec objects of a Cluster, e.g. the InfrastructureCluster, the control plane,
+// MachineDeployments, MachinePools, MachineHealthChecks, templates and user provided secrets, with a new name
+// and/or namespace.
+// Objects representing the instances of the source Cluster, i.e. Machines, MachineSets and the objects they own,
+// the secrets generated by Cluster API, e.g. certificates, kubeconfig and bootstrap data, and the objects generated
+// by the topology controller are not cloned, so the clone is provisioned from scratch with fresh secrets.
+func (o *objectMover) clone(graph *objectGraph, options CloneOptions) ([]unstructured.Unstructured, error) {
+	log := logf.Log
+
+	var source *node
+	for _, cluster := range graph.getClusters() {
+		if cluster.identity.Namespace == options.Namespace && cluster.identity.Name == options.Name {
+			source = cluster
+		}
+	}
+	if source == nil {
+		return nil, errors.Errorf("Cluster %s/%s not found", options.Namespace, options.Name)
+	}
+
+	if options.ToName == "" {
+		return nil, errors.New("the name of the clone must be set")
+	}
+	toNamespace := options.ToNamespace
+	if toNamespace == "" {
+		toNamespace = source.identity.Namespace
+	}
+	if toNamespace == source.identity.Namespace && options.ToName == source.identity.Name {
+		return nil, errors.New("the clone must have a different name or namespace than the source Cluster")
+	}
+
+	cFrom, err := o.fromProxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the objects to be cloned, sorted so owners are created before the objects they own.
+	nodes := getCloneNodes(graph, source)
+	objs := map[*node]*unstructured.Unstructured{}
+	renames := map[string]string{}
+	readBackoff := newReadBackoff()
+	for _, n := range nodes {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(n.identity.APIVersion)
+		obj.SetKind(n.identity.Kind)
+		if err := retryWithExponentialBackoff(readBackoff, func() error {
+			return cFrom.Get(ctx, client.ObjectKey{Namespace: n.identity.Namespace, Name: n.identity.Name}, obj)
+		}); err != nil {
+			return nil, errors.Wrapf(err, "error reading %s", n.identityStr())
+		}
+
+		if isGeneratedCloneObject(obj, source.identity.Name) {
+			log.V(5).Info("Excluding object from clone", n.identity.Kind, n.identity.Name, "Namespace", n.identity.Namespace)
+			continue
+		}
+		objs[n] = obj
+		renames[obj.GetName()] = cloneName(obj.GetName(), source.identity.Name, options.ToName)
+	}
+
+	if err := prepareCloneCluster(objs[source], objs, options.Variables); err != nil {
+		return nil, err
+	}
+
+	var cTo client.Client
+	if !o.dryRun {
+		if toNamespace != source.identity.Namespace {
+			if err := o.ensureNamespace(o.fromProxy, toNamespace); err != nil {
+				return nil, err
+			}
+		}
+		if cTo, err = o.fromProxy.NewClient(); err != nil {
+			return nil, err
+		}
+	}
+
+	mutator := NewRenameMutator(toNamespace, renames)
+	cloned := []unstructured.Unstructured{}
+	for _, n := range nodes {
+		obj, ok := objs[n]
+		if !ok {
+			continue
+		}
+
+		// Owner references are preserved only if the owner is cloned too, or if it is a global object.
+		ownerRefs := []metav1.OwnerReference{}
+		for _, ref := range obj.GetOwnerReferences() {
+			owner, ok := graph.uidToNode[ref.UID]
+			if !ok {
+				continue
+			}
+			if _, isCloned := objs[owner]; isCloned {
+				ref.UID = owner.newUID
+				ownerRefs = append(ownerRefs, ref)
+				continue
+			}
+			if owner.isGlobal {
+				ownerRefs = append(ownerRefs, ref)
+			}
+		}
+
+		cleanCloneObject(obj)
+		obj.SetOwnerReferences(ownerRefs)
+		if err := mutator(obj); err != nil {
+			return nil, err
+		}
+
+		if !o.dryRun {
+			log.V(1).Info("Creating", obj.GetKind(), obj.GetName(), "Namespace", obj.GetNamespace())
+			if err := retryWithExponentialBackoff(newWriteBackoff(), func() error {
+				return cTo.Create(ctx, obj)
+			}); err != nil {
+				return nil, errors.Wrapf(err, "error creating %q %s/%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+			}
+			n.newUID = obj.GetUID()
+		}
+		cloned = append(cloned, *obj)
+	}
+	return cloned, nil
+}
+
+// getCloneNodes returns the nodes belonging to a Cluster, excluding the Machines, MachineSets and all the objects
+// owned by them, sorted so owners come before the objects they own.
+func getCloneNodes(graph *objectGraph, cluster *node) []*node {
+	excluded := map[*node]bool{}
+	var isExcluded func(n *node) bool
+	isExcluded = func(n *node) bool {
+		if v, ok := excluded[n]; ok {
+			return v
+		}
+		excluded[n] = false
+		gk := n.identity.GroupVersionKind().GroupKind()
+		if gk == clusterv1.GroupVersion.WithKind("Machine").GroupKind() || gk == clusterv1.GroupVersion.WithKind("MachineSet").GroupKind() {
+			excluded[n] = true
+			return true
+		}
+		for owner := range n.owners {
+			if isExcluded(owner) {
+				excluded[n] = true
+				return true
+			}
+		}
+		return false
+	}
+
+	candidates := []*node{}
+	for _, n := range graph.getMoveNodes() {
+		if _, ok := n.tenant[cluster]; !ok {
+			continue
+		}
+		if n.virtual || n.isGlobal || n.isGlobalHierarchy || isExcluded(n) {
+			continue
+		}
+		// ClusterResourceSetBindings are not cloned; the clone is selected by the same ClusterResourceSets of the
+		// source Cluster, and the ClusterResourceSet controller creates new bindings when applying the resources.
+		if n.identity.GroupVersionKind().Kind == "ClusterResourceSetBinding" {
+			continue
+		}
+		candidates = append(candidates, n)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].identityStr() < candidates[j].identityStr()
+	})
+
+	// Sort the nodes so owners come first.
+	sorted := []*node{}
+	added := map[*node]bool{}
+	for len(sorted) < len(candidates) {
+		progress := false
+		for _, n := range candidates {
+			if added[n] {
+				continue
+			}
+			ready := true
+			for owner := range n.owners {
+				if !added[owner] && containsNode(candidates, owner) {
+					ready = false
+				}
+			}
+			if ready {
+				sorted = append(sorted, n)
+				added[n] = true
+				progress = true
+			}
+		}
+		if !progress {
+			// Ownership cycles are not expected; add the remaining nodes as they are.
+			for _, n := range candidates {
+				if !added[n] {
+					sorted = append(sorted, n)
+					added[n] = true
+				}
+			}
+		}
+	}
+	return sorted
+}
+
+func containsNode(nodes []*node, n *node) bool {
+	for _, other := range nodes {
+		if other == n {
+			return true
+		}
+	}
+	return false
+}
+
+// isGeneratedCloneObject returns true for objects that must be generated again for the clone instead of being copied,
+// i.e. the secrets generated by Cluster API and the objects generated by the topology controller.
+func isGeneratedCloneObject(obj *unstructured.Unstructured, clusterName string) bool {
+	if _, ok := obj.GetLabels()[clusterv1.ClusterTopologyOwnedLabel]; ok {
+		return true
+	}
+	if obj.GroupVersionKind().GroupKind() != corev1.SchemeGroupVersion.WithKind("Secret").GroupKind() {
+		return false
+	}
+	if secretType, _, _ := unstructured.NestedString(obj.Object, "type"); secretType == string(clusterv1.ClusterSecretType) {
+		return true
+	}
+	if name, _, err := secretutil.ParseSecretName(obj.GetName()); err == nil && name == clusterName {
+		return true
+	}
+	return false
+}
+
+// cloneName returns the name of an object in the clone; the name of the source Cluster, when used as a prefix,
+// is replaced by the name of the clone, otherwise the name of the clone is added as a prefix.
+func cloneName(name, fromCluster, toCluster string) string {
+	if name == fromCluster {
+		return toCluster
+	}
+	if strings.HasPrefix(name, fromCluster+"-") {
+		return toCluster + strings.TrimPrefix(name, fromCluster)
+	}
+	return fmt.Sprintf("%s-%s", toCluster, name)
+}
+
+// prepareCloneCluster sets the topology variables of the clone and drops the control plane endpoints of the
+// source Cluster, so a new endpoint is provisioned for the clone.
+func prepareCloneCluster(cluster *unstructured.Unstructured, objs map[*node]*unstructured.Unstructured, variables map[string]string) error {
+	infraRef, ok, _ := unstructured.NestedStringMap(cluster.Object, "spec", "infrastructureRef")
+	if ok {
+		for n, obj := range objs {
+			if n.identity.Kind == infraRef["kind"] && n.identity.Name == infraRef["name"] {
+				unstructured.RemoveNestedField(obj.Object, "spec", "controlPlaneEndpoint")
+			}
+		}
+	}
+	unstructured.RemoveNestedField(cluster.Object, "spec", "controlPlaneEndpoint")
+
+	if len(variables) == 0 {
+		return nil
+	}
+	if _, ok, _ := unstructured.NestedMap(cluster.Object, "spec", "topology"); !ok {
+		return errors.Errorf("variables can't be set, Cluster %s/%s does not have a managed topology", cluster.GetNamespace(), cluster.GetName())
+	}
+
+	clusterVariables, _, err := unstructured.NestedSlice(cluster.Object, "spec", "topology", "variables")
+	if err != nil {
+		return errors.Wrap(err, "failed to read topology variables")
+	}
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var value interface{}
+		if err := json.Unmarshal([]byte(variables[name]), &value); err != nil {
+			value = variables[name]
+		}
+
+		found := false
+		for i := range clusterVariables {
+			v, ok := clusterVariables[i].(map[string]interface{})
+			if !ok || v["name"] != name {
+				continue
+			}
+			v["value"] = value
+			found = true
+		}
+		if !found {
+			clusterVariables = append(clusterVariables, map[string]interface{}{"name": name, "value": value})
+		}
+	}
+	return unstructured.SetNestedSlice(cluster.Object, clusterVariables, "spec", "topology", "variables")
+}
+
+// cleanCloneObject drops from an object the fields that are not part of its spec, e.g. status and
+// the metadata set by the API server.
+func cleanCloneObject(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	obj.SetUID("")
+	obj.SetResourceVersion("")
+	obj.SetGeneration(0)
+	obj.SetCreationTimestamp(metav1.Time{})
+	obj.SetManagedFields(nil)
+	obj.SetFinalizers(nil)
+	obj.SetGenerateName("")
+
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		delete(annotations, corev1.LastAppliedConfigAnnotation)
+		delete(annotations, clusterv1.PausedAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+}
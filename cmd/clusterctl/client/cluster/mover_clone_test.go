@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_objectMover_clone(t *testing.T) {
+	objs := test.NewFakeCluster("ns1", "foo").
+		WithCredentialSecret().
+		WithControlPlane(test.NewFakeControlPlane("cp1").WithMachines(test.NewFakeMachine("cp1-m1"))).
+		WithMachineDeployments(test.NewFakeMachineDeployment("md1").
+			WithMachineSets(test.NewFakeMachineSet("ms1").
+				WithMachines(test.NewFakeMachine("m1")))).
+		Objs()
+
+	cloneGraph := func(g *WithT) (*objectMover, *objectGraph) {
+		graph := getObjectGraphWithObjs(objs)
+		g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+		g.Expect(graph.Discovery("")).To(Succeed())
+		return newObjectMover(graph.proxy, graph.providerInventory), graph
+	}
+
+	t.Run("clones the spec objects of a Cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mover, graph := cloneGraph(g)
+		cloned, err := mover.clone(graph, CloneOptions{Namespace: "ns1", Name: "foo", ToName: "bar"})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got := map[string]unstructured.Unstructured{}
+		for _, obj := range cloned {
+			g.Expect(obj.GetNamespace()).To(Equal("ns1"))
+			got[fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())] = obj
+		}
+		g.Expect(got).To(HaveKey("Cluster/bar"))
+		g.Expect(got).To(HaveKey("GenericInfrastructureCluster/bar"))
+		g.Expect(got).To(HaveKey("GenericControlPlane/bar-cp1"))
+		g.Expect(got).To(HaveKey("MachineDeployment/bar-md1"))
+		g.Expect(got).To(HaveKey("Secret/bar-credentials"))
+		for key := range got {
+			// Machines, MachineSets and the secrets generated by Cluster API are not cloned.
+			g.Expect(key).ToNot(HavePrefix("Machine/"))
+			g.Expect(key).ToNot(HavePrefix("MachineSet/"))
+			g.Expect(key).ToNot(Equal("Secret/bar-kubeconfig"))
+			g.Expect(key).ToNot(Equal("Secret/bar-ca"))
+		}
+
+		md := got["MachineDeployment/bar-md1"]
+		clusterName, _, _ := unstructured.NestedString(md.Object, "spec", "clusterName")
+		g.Expect(clusterName).To(Equal("bar"))
+		g.Expect(md.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "bar"))
+		g.Expect(md.Object).ToNot(HaveKey("status"))
+		g.Expect(md.GetOwnerReferences()).To(HaveLen(1))
+		g.Expect(md.GetOwnerReferences()[0].Name).To(Equal("bar"))
+
+		// The objects of the clone are created and the source objects are preserved.
+		c, err := graph.proxy.NewClient()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "bar"}, &clusterv1.Cluster{})).To(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "foo"}, &clusterv1.Cluster{})).To(Succeed())
+		machines := &clusterv1.MachineList{}
+		g.Expect(c.List(ctx, machines)).To(Succeed())
+		g.Expect(machines.Items).To(HaveLen(2))
+	})
+
+	t.Run("clones a Cluster into another namespace in dry run", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mover, graph := cloneGraph(g)
+		mover.dryRun = true
+		cloned, err := mover.clone(graph, CloneOptions{Namespace: "ns1", Name: "foo", ToNamespace: "ns2", ToName: "foo"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cloned).ToNot(BeEmpty())
+		for _, obj := range cloned {
+			g.Expect(obj.GetNamespace()).To(Equal("ns2"))
+		}
+
+		c, err := graph.proxy.NewClient()
+		g.Expect(err).ToNot(HaveOccurred())
+		clusters := &clusterv1.ClusterList{}
+		g.Expect(c.List(ctx, clusters, client.InNamespace("ns2"))).To(Succeed())
+		g.Expect(clusters.Items).To(BeEmpty())
+	})
+
+	t.Run("fails if the clone has the same name and namespace of the source Cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mover, graph := cloneGraph(g)
+		_, err := mover.clone(graph, CloneOptions{Namespace: "ns1", Name: "foo", ToName: "foo"})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("fails if variables are set for a Cluster without a managed topology", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mover, graph := cloneGraph(g)
+		mover.dryRun = true
+		_, err := mover.clone(graph, CloneOptions{Namespace: "ns1", Name: "foo", ToName: "bar", Variables: map[string]string{"foo": "bar"}})
+		g.Expect(err).To(MatchError(ContainSubstring("does not have a managed topology")))
+	})
+}
+
+func Test_prepareCloneCluster_variables(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"controlPlaneEndpoint": map[string]interface{}{"host": "1.2.3.4", "port": int64(6443)},
+			"topology": map[string]interface{}{
+				"variables": []interface{}{
+					map[string]interface{}{"name": "imageRepository", "value": "registry.k8s.io"},
+				},
+			},
+		},
+	}}
+
+	g.Expect(prepareCloneCluster(cluster, nil, map[string]string{
+		"imageRepository": "registry.example.com",
+		"replicas":        "3",
+		"proxy":           `{"http":"http://proxy:3128"}`,
+	})).To(Succeed())
+
+	g.Expect(cluster.Object["spec"]).ToNot(HaveKey("controlPlaneEndpoint"))
+	variables, _, err := unstructured.NestedSlice(cluster.Object, "spec", "topology", "variables")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(variables).To(ConsistOf(
+		map[string]interface{}{"name": "imageRepository", "value": "registry.example.com"},
+		map[string]interface{}{"name": "proxy", "value": map[string]interface{}{"http": "http://proxy:3128"}},
+		map[string]interface{}{"name": "replicas", "value": float64(3)},
+	))
+}
+
+func Test_cloneName(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(cloneName("foo", "foo", "bar")).To(Equal("bar"))
+	g.Expect(cloneName("foo-md-0", "foo", "bar")).To(Equal("bar-md-0"))
+	g.Expect(cloneName("foobar", "foo", "bar")).To(Equal("bar-foobar"))
+	g.Expect(cloneName("credentials", "foo", "bar")).To(Equal("bar-credentials"))
+}
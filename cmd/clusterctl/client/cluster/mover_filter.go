@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// MoveFilter defines which of the Cluster API objects discovered in the source management cluster should be moved.
+// The zero value does not filter any object.
+type MoveFilter struct {
+	// ClusterSelector selects the Clusters to be moved; objects belonging only to Clusters not matching the selector are
+	// not moved, while objects not belonging to any Cluster, e.g. ClusterClasses, are always moved.
+	// If nil, all the Clusters are moved.
+	ClusterSelector labels.Selector
+
+	// IncludeKinds, if not empty, restricts the move to the objects of the given kinds.
+	// Kinds can be specified as Kind or Kind.group, e.g. MachineDeployment.cluster.x-k8s.io; "*" can be used
+	// as a Kind for selecting all the kinds in a group, e.g. *.cluster.x-k8s.io.
+	IncludeKinds []string
+
+	// ExcludeKinds excludes from the move the objects of the given kinds, using the same format as IncludeKinds.
+	ExcludeKinds []string
+}
+
+// isEmpty returns true if the filter does not filter any object.
+func (f MoveFilter) isEmpty() bool {
+	return f.ClusterSelector == nil && len(f.IncludeKinds) == 0 && len(f.ExcludeKinds) == 0
+}
+
+// kindMatcher matches the nodes with a given Kind and, if defined, a given group.
+type kindMatcher struct {
+	kind  string
+	group string
+	// anyGroup is true if the kind was specified without a group.
+	anyGroup bool
+}
+
+// parseKindMatchers parses a list of kinds in the Kind or Kind.group format.
+func parseKindMatchers(kinds []string) ([]kindMatcher, error) {
+	matchers := make([]kindMatcher, 0, len(kinds))
+	for _, k := range kinds {
+		kind, group, hasGroup := strings.Cut(strings.TrimSpace(k), ".")
+		if kind == "" || (hasGroup && group == "") {
+			return nil, errors.Errorf("invalid kind %q: kinds must be in the Kind or Kind.group format", k)
+		}
+		if kind == "*" && !hasGroup {
+			return nil, errors.Errorf("invalid kind %q: a group must be specified when using *", k)
+		}
+		matchers = append(matchers, kindMatcher{kind: kind, group: group, anyGroup: !hasGroup})
+	}
+	return matchers, nil
+}
+
+func (m kindMatcher) matches(n *node) bool {
+	gk := n.identity.GroupVersionKind().GroupKind()
+	if !m.anyGroup && m.group != gk.Group {
+		return false
+	}
+	return m.kind == "*" || strings.EqualFold(m.kind, gk.Kind)
+}
+
+func matchesAny(matchers []kindMatcher, n *node) bool {
+	for _, m := range matchers {
+		if m.matches(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter removes from the object graph the nodes not selected by the filter; then it checks that the filter does not split
+// an ownership subtree, i.e. that none of the remaining nodes depends on a node excluded by kind, and that none of the nodes
+// excluded by kind depends on a remaining node.
+func (o *objectGraph) filter(filter MoveFilter) error {
+	if filter.isEmpty() {
+		return nil
+	}
+
+	if len(filter.IncludeKinds) > 0 && len(filter.ExcludeKinds) > 0 {
+		return errors.New("include kinds and exclude kinds can't be used together")
+	}
+
+	if filter.ClusterSelector != nil {
+		if err := o.filterClustersBySelector(filter.ClusterSelector); err != nil {
+			return err
+		}
+	}
+
+	includeKinds, err := parseKindMatchers(filter.IncludeKinds)
+	if err != nil {
+		return err
+	}
+	excludeKinds, err := parseKindMatchers(filter.ExcludeKinds)
+	if err != nil {
+		return err
+	}
+	if len(includeKinds) == 0 && len(excludeKinds) == 0 {
+		return nil
+	}
+
+	removed := map[*node]empty{}
+	for uid, n := range o.uidToNode {
+		// Virtual nodes are never moved, so there is no need to filter them.
+		if n.virtual {
+			continue
+		}
+		if (len(includeKinds) > 0 && !matchesAny(includeKinds, n)) || matchesAny(excludeKinds, n) {
+			removed[n] = empty{}
+			delete(o.uidToNode, uid)
+		}
+	}
+
+	// Moving an object without the objects it depends on leads to dangling references in the target management cluster,
+	// e.g. a MachineSet without its MachineDeployment, so the filter is rejected instead of silently dropping dependents.
+	var errList []error
+	for _, n := range o.uidToNode {
+		for owner := range n.owners {
+			if _, ok := removed[owner]; ok {
+				errList = append(errList, errors.Errorf("%s is owned by %s, which is excluded from move", n.identityStr(), owner.identityStr()))
+			}
+		}
+		for owner := range n.softOwners {
+			if _, ok := removed[owner]; ok {
+				errList = append(errList, errors.Errorf("%s depends on %s, which is excluded from move", n.identityStr(), owner.identityStr()))
+			}
+		}
+	}
+	// Moving an object without its dependents leads to data loss, because the dependents left behind are garbage collected
+	// in the source management cluster when move deletes their owner, e.g. the InfrastructureMachine of a moved Machine.
+	for n := range removed {
+		for owner := range n.owners {
+			if o.isMoved(owner) {
+				errList = append(errList, errors.Errorf("%s is excluded from move, but it is owned by %s, which is moved", n.identityStr(), owner.identityStr()))
+			}
+		}
+		for owner := range n.softOwners {
+			if o.isMoved(owner) {
+				errList = append(errList, errors.Errorf("%s is excluded from move, but it depends on %s, which is moved", n.identityStr(), owner.identityStr()))
+			}
+		}
+	}
+	if len(errList) > 0 {
+		// Sort errors to get a stable message.
+		sort.Slice(errList, func(i, j int) bool { return errList[i].Error() < errList[j].Error() })
+		return errors.Wrap(kerrors.NewAggregate(errList), "the objects selected for move are not self-consistent")
+	}
+	return nil
+}
+
+// isMoved returns true if the node is still part of the object graph and it is not a virtual node.
+func (o *objectGraph) isMoved(n *node) bool {
+	return !n.virtual && o.uidToNode[n.identity.UID] == n
+}
+
+// filterClustersBySelector removes from the object graph all the nodes belonging only to Clusters not matching the selector;
+// nodes not belonging to any Cluster, e.g. ClusterClasses, ClusterResourceSets or global identities, are preserved.
+func (o *objectGraph) filterClustersBySelector(selector labels.Selector) error {
+	selected := map[*node]empty{}
+	for _, cluster := range o.getClusters() {
+		clusterLabels, _ := cluster.additionalInfo[clusterLabelsKey].(labels.Set)
+		if selector.Matches(clusterLabels) {
+			selected[cluster] = empty{}
+		}
+	}
+	if len(selected) == 0 {
+		return errors.Errorf("no Clusters matching the selector %q found", selector.String())
+	}
+
+	o.removeUnselectedClusters(selected)
+	return nil
+}
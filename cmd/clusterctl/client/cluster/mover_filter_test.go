@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func Test_objectGraph_filter(t *testing.T) {
+	objs := []client.Object{}
+	objs = append(objs, test.NewFakeCluster("ns1", "foo").WithLabels(map[string]string{"env": "dev"}).Objs()...)
+	objs = append(objs, test.NewFakeCluster("ns1", "bar").
+		WithMachineDeployments(
+			test.NewFakeMachineDeployment("md1").
+				WithMachineSets(
+					test.NewFakeMachineSet("ms1").
+						WithMachines(test.NewFakeMachine("m1")),
+				),
+		).Objs()...)
+	objs = append(objs, test.NewFakeClusterResourceSet("ns1", "crs1").WithConfigMap("cm1").Objs()...)
+
+	newGraph := func(g *WithT) *objectGraph {
+		graph := getObjectGraphWithObjs(objs)
+		g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+		g.Expect(graph.Discovery("")).To(Succeed())
+		return graph
+	}
+
+	t.Run("an empty filter keeps all the objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newGraph(g)
+		moveNodes := len(graph.getMoveNodes())
+
+		g.Expect(graph.filter(MoveFilter{})).To(Succeed())
+		g.Expect(graph.getMoveNodes()).To(HaveLen(moveNodes))
+	})
+
+	t.Run("keeps only the objects belonging to the Clusters matching the selector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newGraph(g)
+		g.Expect(graph.filter(MoveFilter{ClusterSelector: labels.SelectorFromSet(labels.Set{"env": "dev"})})).To(Succeed())
+
+		clusters := graph.getClusters()
+		g.Expect(clusters).To(HaveLen(1))
+		g.Expect(clusters[0].identity.Name).To(Equal("foo"))
+		// Objects not belonging to any Cluster, e.g. ClusterResourceSets, are always moved.
+		crss := graph.getCRSs()
+		g.Expect(crss).To(HaveLen(1))
+		for _, n := range graph.getMoveNodes() {
+			_, belongsToFoo := n.tenant[clusters[0]]
+			_, belongsToCRS := n.tenant[crss[0]]
+			g.Expect(belongsToFoo || belongsToCRS).To(BeTrue(), n.identityStr())
+		}
+	})
+
+	t.Run("fails if no Cluster matches the selector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newGraph(g)
+		g.Expect(graph.filter(MoveFilter{ClusterSelector: labels.SelectorFromSet(labels.Set{"env": "prod"})})).
+			To(MatchError(ContainSubstring(`no Clusters matching the selector "env=prod" found`)))
+	})
+
+	t.Run("keeps only the objects of the included kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newGraph(g)
+		g.Expect(graph.filter(MoveFilter{IncludeKinds: []string{"*.addons.cluster.x-k8s.io", "ConfigMap"}})).To(Succeed())
+
+		for _, n := range graph.getMoveNodes() {
+			gk := n.identity.GroupVersionKind().GroupKind()
+			g.Expect(gk.Group == addonsv1.GroupVersion.Group || gk.Kind == "ConfigMap").To(BeTrue(), n.identityStr())
+		}
+		g.Expect(graph.getCRSs()).To(HaveLen(1))
+		g.Expect(graph.getClusters()).To(BeEmpty())
+	})
+
+	t.Run("removes the objects of the excluded kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newGraph(g)
+		g.Expect(graph.filter(MoveFilter{ExcludeKinds: []string{"clusterresourceset.addons.cluster.x-k8s.io", "ConfigMap"}})).To(Succeed())
+
+		for _, n := range graph.getMoveNodes() {
+			g.Expect(n.identity.Kind).ToNot(Equal("ClusterResourceSet"))
+			g.Expect(n.identity.Kind).ToNot(Equal("ConfigMap"))
+		}
+		g.Expect(graph.getClusters()).To(HaveLen(2))
+		g.Expect(graph.getMachines()).To(HaveLen(1))
+	})
+
+	t.Run("fails if an excluded object is owned by a selected object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newGraph(g)
+		g.Expect(graph.filter(MoveFilter{ExcludeKinds: []string{"genericinfrastructurecluster.infrastructure.cluster.x-k8s.io"}})).
+			To(MatchError(ContainSubstring("Kind=GenericInfrastructureCluster, Name=foo, Namespace=ns1 is excluded from move, but it is owned by Kind=Cluster, Name=foo, Namespace=ns1, which is moved")))
+	})
+
+	t.Run("fails if the included kinds split an ownership subtree", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newGraph(g)
+		g.Expect(graph.filter(MoveFilter{IncludeKinds: []string{"*.cluster.x-k8s.io", "GenericInfrastructureCluster"}})).
+			To(MatchError(ContainSubstring("Kind=GenericInfrastructureMachine, Name=m1, Namespace=ns1 is excluded from move, but it is owned by Kind=Machine, Name=m1, Namespace=ns1, which is moved")))
+	})
+
+	t.Run("fails if a selected object is owned by an excluded object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newGraph(g)
+		g.Expect(graph.filter(MoveFilter{ExcludeKinds: []string{"MachineDeployment"}})).
+			To(MatchError(ContainSubstring("Kind=MachineSet, Name=ms1, Namespace=ns1 is owned by Kind=MachineDeployment, Name=md1, Namespace=ns1, which is excluded from move")))
+	})
+
+	t.Run("fails if a selected object depends on an excluded object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newGraph(g)
+		g.Expect(graph.filter(MoveFilter{IncludeKinds: []string{"Secret"}})).
+			To(MatchError(ContainSubstring("depends on Kind=Cluster, Name=foo, Namespace=ns1, which is excluded from move")))
+	})
+
+	t.Run("fails if both include and exclude kinds are set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newGraph(g)
+		g.Expect(graph.filter(MoveFilter{IncludeKinds: []string{"Cluster"}, ExcludeKinds: []string{"Secret"}})).
+			To(MatchError(ContainSubstring("can't be used together")))
+	})
+}
+
+func Test_parseKindMatchers(t *testing.T) {
+	tests := []struct {
+		name    string
+		kinds   []string
+		want    []kindMatcher
+		wantErr bool
+	}{
+		{
+			name:  "kind",
+			kinds: []string{"Cluster"},
+			want:  []kindMatcher{{kind: "Cluster", anyGroup: true}},
+		},
+		{
+			name:  "kind and group",
+			kinds: []string{"KubeadmControlPlane.controlplane.cluster.x-k8s.io"},
+			want:  []kindMatcher{{kind: "KubeadmControlPlane", group: "controlplane.cluster.x-k8s.io"}},
+		},
+		{
+			name:  "all the kinds in a group",
+			kinds: []string{"*.cluster.x-k8s.io"},
+			want:  []kindMatcher{{kind: "*", group: "cluster.x-k8s.io"}},
+		},
+		{
+			name:    "all the kinds without a group",
+			kinds:   []string{"*"},
+			wantErr: true,
+		},
+		{
+			name:    "empty group",
+			kinds:   []string{"Cluster."},
+			wantErr: true,
+		},
+		{
+			name:    "empty kind",
+			kinds:   []string{""},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := parseKindMatchers(tt.kinds)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
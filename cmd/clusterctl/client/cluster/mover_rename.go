@@ -0,0 +1,215 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+// clusterSecretPurposes are the purposes of the well-known Secrets whose name is derived from the Cluster name,
+// i.e. <cluster>-<purpose>; those Secrets are renamed together with their Cluster.
+var clusterSecretPurposes = []secret.Purpose{
+	secret.ClusterCA,
+	secret.Kubeconfig,
+	secret.EtcdCA,
+	secret.ServiceAccount,
+	secret.FrontProxyCA,
+}
+
+// nameLabels are the well-known labels whose value is the name of a Cluster API object.
+var nameLabels = sets.NewString(
+	clusterv1.ClusterNameLabel,
+	clusterv1.MachineDeploymentNameLabel,
+	clusterv1.MachineSetNameLabel,
+	clusterv1.MachineControlPlaneNameLabel,
+	clusterv1.MachinePoolNameLabel,
+)
+
+// NewRenameMutator returns a ResourceMutatorFunc rewriting the objects being moved so they are created
+// in toNamespace (if not empty) and with the names defined in the renames map (old name -> new name).
+//
+// Besides the object's own namespace and name, the mutator rewrites:
+//   - the names in owner references;
+//   - the values of the well-known name labels, e.g. cluster.x-k8s.io/cluster-name, including the ones in
+//     nested label maps like the MachineDeployment selector or the Machine template labels;
+//   - the name and namespace of object references, i.e. ref, *Ref and *Refs fields (e.g. infrastructureRef,
+//     configRef, template references), the MachineHealthCheck remediationTemplate and the ClusterResourceSet resources;
+//   - clusterName and dataSecretName fields, and the ClusterClass referenced by a Cluster topology.
+//
+// The well-known Secrets of a renamed Cluster, i.e. <cluster>-ca, <cluster>-kubeconfig, <cluster>-etcd, <cluster>-sa
+// and <cluster>-proxy, are renamed accordingly, unless explicitly renamed.
+//
+// Namespaces are rewritten only if they match the namespace of the object being moved, so references to objects in
+// other namespaces are preserved; global objects are never moved to another namespace.
+func NewRenameMutator(toNamespace string, renames map[string]string) ResourceMutatorFunc {
+	return func(u *unstructured.Unstructured) error {
+		r := &renamer{
+			kind:          u.GetKind(),
+			fromNamespace: u.GetNamespace(),
+			toNamespace:   toNamespace,
+			renames:       renames,
+		}
+
+		if r.fromNamespace != "" && r.toNamespace != "" {
+			u.SetNamespace(r.toNamespace)
+		}
+		if u.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("Secret") {
+			u.SetName(r.secretName(u))
+		} else {
+			u.SetName(r.name(u.GetName()))
+		}
+
+		ownerRefs := u.GetOwnerReferences()
+		for i := range ownerRefs {
+			ownerRefs[i].Name = r.name(ownerRefs[i].Name)
+		}
+		u.SetOwnerReferences(ownerRefs)
+
+		if labels := u.GetLabels(); labels != nil {
+			for k, v := range labels {
+				labels[k] = r.labelValue(k, v)
+			}
+			u.SetLabels(labels)
+		}
+
+		// Status is not restored when creating objects, so only the object spec (or the equivalent top level fields
+		// for types without a spec, e.g. Secrets) needs to be rewritten.
+		for k, v := range u.Object {
+			switch k {
+			case "apiVersion", "kind", "metadata", "status":
+				continue
+			}
+			u.Object[k] = r.rewrite(k, v)
+		}
+		return nil
+	}
+}
+
+// renamer implements the rewrite rules for a single object.
+type renamer struct {
+	kind          string
+	fromNamespace string
+	toNamespace   string
+	renames       map[string]string
+}
+
+// name returns the new name for name, if any.
+func (r *renamer) name(name string) string {
+	if newName, ok := r.renames[name]; ok {
+		return newName
+	}
+	return name
+}
+
+// secretName returns the new name for a Secret; explicit renames take precedence over the rename of the
+// well-known Secrets of a renamed Cluster.
+func (r *renamer) secretName(u *unstructured.Unstructured) string {
+	if newName, ok := r.renames[u.GetName()]; ok {
+		return newName
+	}
+
+	clusterName, ok := u.GetLabels()[clusterv1.ClusterNameLabel]
+	if !ok {
+		for _, ref := range u.GetOwnerReferences() {
+			if ref.Kind == "Cluster" && strings.HasPrefix(ref.APIVersion, clusterv1.GroupVersion.Group+"/") {
+				clusterName = ref.Name
+				break
+			}
+		}
+	}
+	newClusterName, ok := r.renames[clusterName]
+	if clusterName == "" || !ok {
+		return u.GetName()
+	}
+	for _, purpose := range clusterSecretPurposes {
+		if u.GetName() == secret.Name(clusterName, purpose) {
+			return secret.Name(newClusterName, purpose)
+		}
+	}
+	return u.GetName()
+}
+
+// labelValue returns the new value for a label; only the well-known name labels are rewritten.
+func (r *renamer) labelValue(key, value string) string {
+	if !nameLabels.Has(key) {
+		return value
+	}
+	return r.name(value)
+}
+
+// rewrite walks a field value rewriting names and namespaces according to the rules documented in NewRenameMutator.
+func (r *renamer) rewrite(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if key == "labels" || key == "matchLabels" {
+			for lk, lv := range v {
+				if s, ok := lv.(string); ok {
+					v[lk] = r.labelValue(lk, s)
+				}
+			}
+			return v
+		}
+
+		if class, ok := v["class"].(string); ok && key == "topology" {
+			v["class"] = r.name(class)
+		}
+		if name, ok := v["name"].(string); ok && r.isReference(key) {
+			v["name"] = r.name(name)
+			if namespace, ok := v["namespace"].(string); ok && r.toNamespace != "" && namespace == r.fromNamespace {
+				v["namespace"] = r.toNamespace
+			}
+		}
+		for k, nested := range v {
+			if k == "name" || k == "namespace" || (k == "class" && key == "topology") {
+				continue
+			}
+			v[k] = r.rewrite(k, nested)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = r.rewrite(key, v[i])
+		}
+		return v
+	case string:
+		if key == "clusterName" || key == "dataSecretName" {
+			return r.name(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// isReference returns true if the field under key is an object reference, i.e. a ref, *Ref or *Refs field,
+// a MachineHealthCheck remediationTemplate or a ClusterResourceSet resource.
+func (r *renamer) isReference(key string) bool {
+	switch key {
+	case "ref", "remediationTemplate":
+		return true
+	case "resources":
+		return r.kind == "ClusterResourceSet"
+	}
+	return strings.HasSuffix(key, "Ref") || strings.HasSuffix(key, "Refs")
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func Test_NewRenameMutator(t *testing.T) {
+	tests := []struct {
+		name        string
+		toNamespace string
+		renames     map[string]string
+		obj         map[string]interface{}
+		want        map[string]interface{}
+	}{
+		{
+			name:        "moves a namespaced object to another namespace",
+			toNamespace: "tenant-a",
+			obj: map[string]interface{}{
+				"apiVersion": "cluster.x-k8s.io/v1beta1",
+				"kind":       "Machine",
+				"metadata": map[string]interface{}{
+					"name":      "m1",
+					"namespace": "ns1",
+				},
+				"spec": map[string]interface{}{
+					"clusterName": "foo",
+					"infrastructureRef": map[string]interface{}{
+						"kind":      "GenericInfrastructureMachine",
+						"name":      "m1",
+						"namespace": "ns1",
+					},
+					"bootstrap": map[string]interface{}{
+						"configRef": map[string]interface{}{
+							"kind":      "GenericBootstrapConfig",
+							"name":      "m1",
+							"namespace": "other",
+						},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "cluster.x-k8s.io/v1beta1",
+				"kind":       "Machine",
+				"metadata": map[string]interface{}{
+					"name":      "m1",
+					"namespace": "tenant-a",
+				},
+				"spec": map[string]interface{}{
+					"clusterName": "foo",
+					"infrastructureRef": map[string]interface{}{
+						"kind":      "GenericInfrastructureMachine",
+						"name":      "m1",
+						"namespace": "tenant-a",
+					},
+					"bootstrap": map[string]interface{}{
+						"configRef": map[string]interface{}{
+							"kind":      "GenericBootstrapConfig",
+							"name":      "m1",
+							"namespace": "other", // references to other namespaces are preserved.
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "does not move global objects to another namespace",
+			toNamespace: "tenant-a",
+			obj: map[string]interface{}{
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"kind":       "GenericClusterExternalObject",
+				"metadata": map[string]interface{}{
+					"name": "eo1",
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"kind":       "GenericClusterExternalObject",
+				"metadata": map[string]interface{}{
+					"name": "eo1",
+				},
+			},
+		},
+		{
+			name:    "renames an object and its references",
+			renames: map[string]string{"foo": "bar", "md1": "md2", "foo-class": "bar-class", "foo-bootstrap-data": "bar-bootstrap-data"},
+			obj: map[string]interface{}{
+				"apiVersion": "cluster.x-k8s.io/v1beta1",
+				"kind":       "MachineDeployment",
+				"metadata": map[string]interface{}{
+					"name":      "md1",
+					"namespace": "ns1",
+					"labels": map[string]interface{}{
+						"cluster.x-k8s.io/cluster-name": "foo",
+						"app":                           "foo",
+					},
+					"ownerReferences": []interface{}{
+						map[string]interface{}{
+							"apiVersion": "cluster.x-k8s.io/v1beta1",
+							"kind":       "Cluster",
+							"name":       "foo",
+							"uid":        "1",
+						},
+					},
+				},
+				"spec": map[string]interface{}{
+					"clusterName": "foo",
+					"selector": map[string]interface{}{
+						"matchLabels": map[string]interface{}{
+							"cluster.x-k8s.io/deployment-name": "md1",
+						},
+					},
+					"topology": map[string]interface{}{
+						"class": "foo-class",
+					},
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"bootstrap": map[string]interface{}{
+								"dataSecretName": "foo-bootstrap-data",
+							},
+						},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "cluster.x-k8s.io/v1beta1",
+				"kind":       "MachineDeployment",
+				"metadata": map[string]interface{}{
+					"name":      "md2",
+					"namespace": "ns1",
+					"labels": map[string]interface{}{
+						"cluster.x-k8s.io/cluster-name": "bar",
+						"app":                           "foo", // labels outside of the cluster.x-k8s.io domain are preserved.
+					},
+					"ownerReferences": []interface{}{
+						map[string]interface{}{
+							"apiVersion": "cluster.x-k8s.io/v1beta1",
+							"kind":       "Cluster",
+							"name":       "bar",
+							"uid":        "1",
+						},
+					},
+				},
+				"spec": map[string]interface{}{
+					"clusterName": "bar",
+					"selector": map[string]interface{}{
+						"matchLabels": map[string]interface{}{
+							"cluster.x-k8s.io/deployment-name": "md2",
+						},
+					},
+					"topology": map[string]interface{}{
+						"class": "bar-class",
+					},
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"bootstrap": map[string]interface{}{
+								"dataSecretName": "bar-bootstrap-data",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "rewrites only well-known name labels and reference fields",
+			renames: map[string]string{"foo": "bar"},
+			obj: map[string]interface{}{
+				"apiVersion": "bootstrap.cluster.x-k8s.io/v1beta1",
+				"kind":       "KubeadmConfig",
+				"metadata": map[string]interface{}{
+					"name":      "m1",
+					"namespace": "ns1",
+					"labels": map[string]interface{}{
+						"cluster.x-k8s.io/cluster-name": "foo",
+						"cluster.x-k8s.io/provider":     "foo",
+					},
+				},
+				"spec": map[string]interface{}{
+					"files": []interface{}{
+						map[string]interface{}{
+							"contentFrom": map[string]interface{}{
+								"secret": map[string]interface{}{
+									"name": "foo",
+									"key":  "value",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "bootstrap.cluster.x-k8s.io/v1beta1",
+				"kind":       "KubeadmConfig",
+				"metadata": map[string]interface{}{
+					"name":      "m1",
+					"namespace": "ns1",
+					"labels": map[string]interface{}{
+						"cluster.x-k8s.io/cluster-name": "bar",
+						"cluster.x-k8s.io/provider":     "foo",
+					},
+				},
+				"spec": map[string]interface{}{
+					"files": []interface{}{
+						map[string]interface{}{
+							"contentFrom": map[string]interface{}{
+								"secret": map[string]interface{}{
+									"name": "foo",
+									"key":  "value",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "rewrites ClusterResourceSet resources",
+			renames: map[string]string{"foo-addons": "bar-addons"},
+			obj: map[string]interface{}{
+				"apiVersion": "addons.cluster.x-k8s.io/v1beta1",
+				"kind":       "ClusterResourceSet",
+				"metadata": map[string]interface{}{
+					"name":      "crs1",
+					"namespace": "ns1",
+				},
+				"spec": map[string]interface{}{
+					"resources": []interface{}{
+						map[string]interface{}{
+							"kind": "ConfigMap",
+							"name": "foo-addons",
+						},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "addons.cluster.x-k8s.io/v1beta1",
+				"kind":       "ClusterResourceSet",
+				"metadata": map[string]interface{}{
+					"name":      "crs1",
+					"namespace": "ns1",
+				},
+				"spec": map[string]interface{}{
+					"resources": []interface{}{
+						map[string]interface{}{
+							"kind": "ConfigMap",
+							"name": "bar-addons",
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			u := &unstructured.Unstructured{Object: tt.obj}
+			g.Expect(NewRenameMutator(tt.toNamespace, tt.renames)(u)).To(Succeed())
+
+			g.Expect(u.Object).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_NewRenameMutator_ownerReferences(t *testing.T) {
+	g := NewWithT(t)
+
+	u := &unstructured.Unstructured{}
+	u.SetNamespace("ns1")
+	u.SetName("foo-kubeconfig")
+	u.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Cluster", Name: "foo"}})
+
+	g.Expect(NewRenameMutator("", map[string]string{"foo": "bar", "foo-kubeconfig": "bar-kubeconfig"})(u)).To(Succeed())
+	g.Expect(u.GetNamespace()).To(Equal("ns1"))
+	g.Expect(u.GetName()).To(Equal("bar-kubeconfig"))
+	g.Expect(u.GetOwnerReferences()).To(ConsistOf(metav1.OwnerReference{Kind: "Cluster", Name: "bar"}))
+}
+
+func Test_NewRenameMutator_clusterSecrets(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		labels    map[string]string
+		ownerRefs []metav1.OwnerReference
+		renames   map[string]string
+		want      string
+	}{
+		{
+			name:    "renames the well-known secrets of a renamed Cluster",
+			secret:  "foo-ca",
+			labels:  map[string]string{clusterv1.ClusterNameLabel: "foo"},
+			renames: map[string]string{"foo": "bar"},
+			want:    "bar-ca",
+		},
+		{
+			name:      "renames the well-known secrets owned by a renamed Cluster",
+			secret:    "foo-kubeconfig",
+			ownerRefs: []metav1.OwnerReference{{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "foo"}},
+			renames:   map[string]string{"foo": "bar"},
+			want:      "bar-kubeconfig",
+		},
+		{
+			name:    "explicit renames take precedence",
+			secret:  "foo-sa",
+			labels:  map[string]string{clusterv1.ClusterNameLabel: "foo"},
+			renames: map[string]string{"foo": "bar", "foo-sa": "baz-sa"},
+			want:    "baz-sa",
+		},
+		{
+			name:    "does not rename other secrets of a renamed Cluster",
+			secret:  "foo-bootstrap-data",
+			labels:  map[string]string{clusterv1.ClusterNameLabel: "foo"},
+			renames: map[string]string{"foo": "bar"},
+			want:    "foo-bootstrap-data",
+		},
+		{
+			name:    "does not rename secrets not belonging to the renamed Cluster",
+			secret:  "foo-etcd",
+			labels:  map[string]string{clusterv1.ClusterNameLabel: "other"},
+			renames: map[string]string{"foo": "bar"},
+			want:    "foo-etcd",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			u := &unstructured.Unstructured{}
+			u.SetAPIVersion("v1")
+			u.SetKind("Secret")
+			u.SetNamespace("ns1")
+			u.SetName(tt.secret)
+			u.SetLabels(tt.labels)
+			u.SetOwnerReferences(tt.ownerRefs)
+
+			g.Expect(NewRenameMutator("", tt.renames)(u)).To(Succeed())
+			g.Expect(u.GetName()).To(Equal(tt.want))
+		})
+	}
+}
@@ -1235,6 +1235,66 @@ func Test_objectMover_move(t *testing.T) {
 	}
 }
 
+func Test_objectMover_move_withMutators(t *testing.T) {
+	g := NewWithT(t)
+
+	// Create an objectGraph bound a source cluster with all the CRDs for the types involved in the test.
+	graph := getObjectGraphWithObjs(test.NewFakeCluster("ns1", "cluster1").
+		WithMachines(
+			test.NewFakeMachine("m1"),
+			test.NewFakeMachine("m2"),
+		).Objs())
+
+	// Get all the types to be considered for discovery
+	g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+
+	// trigger discovery the content of the source cluster
+	g.Expect(graph.Discovery("")).To(Succeed())
+
+	// gets a fakeProxy to an empty cluster with all the required CRDs
+	toProxy := getFakeProxyWithCRDs()
+
+	// Run move moving all the objects to the tenant-a namespace and renaming m1
+	mover := objectMover{
+		fromProxy: graph.proxy,
+	}
+	mutator := NewRenameMutator("tenant-a", map[string]string{"m1": "m1-renamed"})
+
+	g.Expect(mover.move(graph, toProxy, mutator)).To(Succeed())
+
+	csTo, err := toProxy.NewClient()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// objects are created in the target cluster using the mutated keys
+	for _, node := range graph.uidToNode {
+		key, err := mutatedObjectKey(node, mutator)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(key.Namespace).To(Equal("tenant-a"))
+
+		oTo := &unstructured.Unstructured{}
+		oTo.SetAPIVersion(node.identity.APIVersion)
+		oTo.SetKind(node.identity.Kind)
+		g.Expect(csTo.Get(ctx, key, oTo)).To(Succeed(), "%v not created in target cluster", key)
+
+		for _, ownerRef := range oTo.GetOwnerReferences() {
+			g.Expect(ownerRef.Name).ToNot(Equal("m1"))
+		}
+	}
+
+	// references are rewritten
+	machine := &clusterv1.Machine{}
+	g.Expect(csTo.Get(ctx, client.ObjectKey{Namespace: "tenant-a", Name: "m1-renamed"}, machine)).To(Succeed())
+	g.Expect(machine.Spec.InfrastructureRef.Name).To(Equal("m1-renamed"))
+	g.Expect(machine.Spec.InfrastructureRef.Namespace).To(Equal("tenant-a"))
+	g.Expect(machine.Spec.Bootstrap.ConfigRef.Name).To(Equal("m1-renamed"))
+	g.Expect(machine.Spec.Bootstrap.ConfigRef.Namespace).To(Equal("tenant-a"))
+
+	// the Cluster in the target cluster is resumed
+	cluster := &clusterv1.Cluster{}
+	g.Expect(csTo.Get(ctx, client.ObjectKey{Namespace: "tenant-a", Name: "cluster1"}, cluster)).To(Succeed())
+	g.Expect(cluster.Spec.Paused).To(BeFalse())
+}
+
 func Test_objectMover_checkProvisioningCompleted(t *testing.T) {
 	type fields struct {
 		objs []client.Object
@@ -2087,3 +2147,41 @@ func Test_deleteSourceObject(t *testing.T) {
 		})
 	}
 }
+
+func Test_setPreMoveHook(t *testing.T) {
+	g := NewWithT(t)
+
+	proxy := test.NewFakeProxy().WithObjs(
+		&clusterv1.MachineHealthCheck{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "ns1",
+			},
+		},
+	)
+	n := &node{
+		identity: corev1.ObjectReference{
+			Kind:       "MachineHealthCheck",
+			Namespace:  "ns1",
+			Name:       "foo",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+	}
+	c, err := proxy.NewClient()
+	g.Expect(err).NotTo(HaveOccurred())
+	key := client.ObjectKey{Namespace: "ns1", Name: "foo"}
+
+	// The annotation is not set in dry run.
+	g.Expect(setPreMoveHook(proxy, []*node{n}, true, true)).To(Succeed())
+	mhc := &clusterv1.MachineHealthCheck{}
+	g.Expect(c.Get(ctx, key, mhc)).To(Succeed())
+	g.Expect(mhc.Annotations).ToNot(HaveKey(clusterv1.PreMoveHookAnnotation))
+
+	g.Expect(setPreMoveHook(proxy, []*node{n}, true, false)).To(Succeed())
+	g.Expect(c.Get(ctx, key, mhc)).To(Succeed())
+	g.Expect(mhc.Annotations).To(HaveKey(clusterv1.PreMoveHookAnnotation))
+
+	g.Expect(setPreMoveHook(proxy, []*node{n}, false, false)).To(Succeed())
+	g.Expect(c.Get(ctx, key, mhc)).To(Succeed())
+	g.Expect(mhc.Annotations).ToNot(HaveKey(clusterv1.PreMoveHookAnnotation))
+}
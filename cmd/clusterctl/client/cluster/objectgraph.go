@@ -26,6 +26,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -38,6 +39,7 @@ import (
 
 const clusterTopologyNameKey = "cluster.spec.topology.class"
 const clusterResourceSetBindingClusterNameKey = "clusterresourcesetbinding.spec.clustername"
+const clusterLabelsKey = "cluster.metadata.labels"
 
 type empty struct{}
 
@@ -139,12 +141,14 @@ func (n *node) captureAdditionalInformation(obj *unstructured.Unstructured) erro
 		if err := localScheme.Convert(obj, cluster, nil); err != nil {
 			return errors.Wrapf(err, "failed to convert object %s to Cluster", n.identityStr())
 		}
+		if n.additionalInfo == nil {
+			n.additionalInfo = map[string]interface{}{}
+		}
 		if cluster.Spec.Topology != nil {
-			if n.additionalInfo == nil {
-				n.additionalInfo = map[string]interface{}{}
-			}
 			n.additionalInfo[clusterTopologyNameKey] = cluster.Spec.Topology.Class
 		}
+		// Capture the labels of the cluster, so Clusters can be selected by label when moving.
+		n.additionalInfo[clusterLabelsKey] = labels.Set(cluster.Labels)
 	}
 
 	// If the node is a ClusterResourceSetBinding capture the name of the cluster it is referencing to.
@@ -570,6 +574,30 @@ func (o *objectGraph) getMoveNodes() []*node {
 	return nodes
 }
 
+// getPreMoveHookNodes returns the list of nodes to be moved whose controllers are not quiesced by pausing the Cluster,
+// i.e. MachineHealthChecks, addon objects and objects reconciled by external controllers, e.g. remediation objects,
+// which are not part of an API group of a Cluster API provider.
+func (o *objectGraph) getPreMoveHookNodes() []*node {
+	nodes := []*node{}
+	for _, node := range o.getMoveNodes() {
+		if node.virtual || node.isGlobal {
+			continue
+		}
+		gk := node.identity.GroupVersionKind().GroupKind()
+		switch {
+		case gk == clusterv1.GroupVersion.WithKind("MachineHealthCheck").GroupKind():
+			nodes = append(nodes, node)
+		case gk.Group == addonsv1.GroupVersion.Group:
+			nodes = append(nodes, node)
+		case gk.Group == "", gk.Group == clusterv1.GroupVersion.Group, strings.HasSuffix(gk.Group, "."+clusterv1.GroupVersion.Group):
+			continue
+		default:
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
 // getMachines returns the list of Machine existing in the object graph.
 func (o *objectGraph) getMachines() []*node {
 	machines := []*node{}
@@ -37,6 +37,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/errorcodes"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
 	"sigs.k8s.io/cluster-api/version"
 )
@@ -172,7 +173,7 @@ func (k *proxy) NewClient() (client.Client, error) {
 		}
 		return nil
 	}); err != nil {
-		return nil, errors.Wrap(err, "failed to connect to the management cluster")
+		return nil, errorcodes.Wrap(errorcodes.ManagementClusterUnreachable, errors.Wrap(err, "failed to connect to the management cluster"))
 	}
 
 	return c, nil
@@ -190,10 +191,10 @@ func (k *proxy) CheckClusterAvailable() error {
 	}
 
 	connectBackoff := newShortConnectBackoff()
-	return retryWithExponentialBackoff(connectBackoff, func() error {
+	return errorcodes.Wrap(errorcodes.ManagementClusterUnreachable, retryWithExponentialBackoff(connectBackoff, func() error {
 		_, err := client.New(config, client.Options{Scheme: localScheme})
 		return err
-	})
+	}))
 }
 
 // ListResources lists namespaced and cluster-wide resources for a component matching the labels. Namespaced resources are only listed
@@ -0,0 +1,244 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// StorageVersionAudit is the result of the audit of the API versions used by the objects of a provider CRD.
+type StorageVersionAudit struct {
+	// CRD is the name of the CustomResourceDefinition.
+	CRD string `json:"crd"`
+
+	// Kind is the kind defined by the CRD.
+	Kind string `json:"kind"`
+
+	// StorageVersion is the API version currently used to store the objects in etcd.
+	StorageVersion string `json:"storageVersion"`
+
+	// StoredVersions are the API versions that have ever been used to store objects in etcd, as reported by the CRD status.
+	StoredVersions []string `json:"storedVersions"`
+
+	// StaleVersions are the stored versions other than the current storage version; objects possibly stored with
+	// those versions must be migrated before the versions can be dropped by the CRD.
+	StaleVersions []string `json:"staleVersions,omitempty"`
+
+	// DeprecatedVersions are the API versions marked as deprecated in the CRD that are still in use, either as stored
+	// versions or because they are used by clients writing the objects (as reported by the object's managed fields).
+	DeprecatedVersions []string `json:"deprecatedVersions,omitempty"`
+
+	// Objects are the objects which require a storage migration, in the namespace/name format.
+	// NOTE: the apiserver does not expose the version each object is stored with, so all the objects
+	// of a CRD with stale versions are reported.
+	Objects []string `json:"objects,omitempty"`
+
+	// Migrated is true if the objects have been migrated to the storage version and the stale versions
+	// have been dropped from the CRD status.
+	Migrated bool `json:"migrated,omitempty"`
+}
+
+// RequiresMigration returns true if the objects of the CRD require a storage migration.
+func (a *StorageVersionAudit) RequiresMigration() bool {
+	return len(a.StaleVersions) > 0
+}
+
+// StorageVersionAuditor audits the API versions used by the objects of the CRDs installed by clusterctl.
+type StorageVersionAuditor interface {
+	// Audit returns the audit of the API versions for each of the CRDs installed by clusterctl.
+	Audit() ([]StorageVersionAudit, error)
+
+	// Migrate re-writes the objects of the CRDs which require a storage migration, so they are stored
+	// with the current storage version, and then drops the stale versions from the CRD status.
+	Migrate(audits []StorageVersionAudit) error
+}
+
+// storageVersionAuditor implements StorageVersionAuditor.
+type storageVersionAuditor struct {
+	proxy Proxy
+}
+
+// ensure storageVersionAuditor implements StorageVersionAuditor.
+var _ StorageVersionAuditor = &storageVersionAuditor{}
+
+// newStorageVersionAuditor returns a storageVersionAuditor.
+func newStorageVersionAuditor(proxy Proxy) *storageVersionAuditor {
+	return &storageVersionAuditor{
+		proxy: proxy,
+	}
+}
+
+func (a *storageVersionAuditor) Audit() ([]StorageVersionAudit, error) {
+	c, err := a.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := retryWithExponentialBackoff(newReadBackoff(), func() error {
+		return getCRDList(a.proxy, crdList)
+	}); err != nil {
+		return nil, err
+	}
+
+	audits := []StorageVersionAudit{}
+	for i := range crdList.Items {
+		audit, err := auditCRD(c, &crdList.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		audits = append(audits, *audit)
+	}
+
+	sort.Slice(audits, func(i, j int) bool {
+		return audits[i].CRD < audits[j].CRD
+	})
+	return audits, nil
+}
+
+// auditCRD returns the audit of the API versions used by the objects of a CRD.
+func auditCRD(c client.Client, crd *apiextensionsv1.CustomResourceDefinition) (*StorageVersionAudit, error) {
+	storageVersion, err := storageVersionForCRD(crd)
+	if err != nil {
+		return nil, err
+	}
+
+	deprecatedVersions := sets.Set[string]{}
+	for _, version := range crd.Spec.Versions {
+		if version.Deprecated {
+			deprecatedVersions.Insert(version.Name)
+		}
+	}
+
+	storedVersions := sets.New[string](crd.Status.StoredVersions...)
+	staleVersions := storedVersions.Clone().Delete(storageVersion)
+	deprecatedVersionsInUse := deprecatedVersions.Intersection(storedVersions)
+
+	objects := []string{}
+	if err := listCRDObjects(c, crd, storageVersion, func(obj *unstructured.Unstructured) {
+		if staleVersions.Len() > 0 {
+			objects = append(objects, objectNamespacedName(obj))
+		}
+		for _, managedFields := range obj.GetManagedFields() {
+			gv, err := schema.ParseGroupVersion(managedFields.APIVersion)
+			if err != nil || gv.Group != crd.Spec.Group {
+				continue
+			}
+			if deprecatedVersions.Has(gv.Version) {
+				deprecatedVersionsInUse.Insert(gv.Version)
+			}
+		}
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(objects)
+
+	return &StorageVersionAudit{
+		CRD:                crd.Name,
+		Kind:               crd.Spec.Names.Kind,
+		StorageVersion:     storageVersion,
+		StoredVersions:     crd.Status.StoredVersions,
+		StaleVersions:      sets.List(staleVersions),
+		DeprecatedVersions: sets.List(deprecatedVersionsInUse),
+		Objects:            objects,
+	}, nil
+}
+
+// listCRDObjects calls fn for all the objects of a CRD, read using the given version.
+func listCRDObjects(c client.Client, crd *apiextensionsv1.CustomResourceDefinition, version string, fn func(obj *unstructured.Unstructured)) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   crd.Spec.Group,
+		Version: version,
+		Kind:    crd.Spec.Names.ListKind,
+	})
+
+	for {
+		if err := retryWithExponentialBackoff(newReadBackoff(), func() error {
+			return c.List(ctx, list, client.Continue(list.GetContinue()))
+		}); err != nil {
+			return errors.Wrapf(err, "failed to list %q", list.GetKind())
+		}
+
+		for i := range list.Items {
+			fn(&list.Items[i])
+		}
+
+		if list.GetContinue() == "" {
+			return nil
+		}
+	}
+}
+
+func objectNamespacedName(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+func (a *storageVersionAuditor) Migrate(audits []StorageVersionAudit) error {
+	log := logf.Log
+
+	c, err := a.proxy.NewClient()
+	if err != nil {
+		return err
+	}
+	m := newCRDMigrator(c)
+
+	for i := range audits {
+		audit := &audits[i]
+		if !audit.RequiresMigration() {
+			continue
+		}
+
+		// Read the CRD again so the storage version and the stored versions are up to date.
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := retryWithExponentialBackoff(newReadBackoff(), func() error {
+			return c.Get(ctx, client.ObjectKey{Name: audit.CRD}, crd)
+		}); err != nil {
+			return errors.Wrapf(err, "failed to get CRD %q", audit.CRD)
+		}
+		storageVersion, err := storageVersionForCRD(crd)
+		if err != nil {
+			return err
+		}
+
+		log.Info("Migrating stored versions", "kind", audit.Kind, "staleVersions", audit.StaleVersions, "storageVersion", storageVersion)
+		if err := m.migrateResourcesForCRD(ctx, crd, storageVersion); err != nil {
+			return err
+		}
+		if err := m.patchCRDStoredVersions(ctx, crd, storageVersion); err != nil {
+			return err
+		}
+
+		audit.StorageVersion = storageVersion
+		audit.StoredVersions = []string{storageVersion}
+		audit.StaleVersions = nil
+		audit.Migrated = true
+	}
+	return nil
+}
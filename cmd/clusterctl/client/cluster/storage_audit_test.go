@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_storageVersionAuditor(t *testing.T) {
+	g := NewWithT(t)
+
+	newCRD := func(name, kind string, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{clusterctlv1.ClusterctlLabel: ""},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "foo",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: kind, ListKind: kind + "List"},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1beta1", Storage: true},
+					{Name: "v1alpha4", Deprecated: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+		}
+	}
+	newObj := func(kind, name, managerAPIVersion string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("foo/v1beta1")
+		obj.SetKind(kind)
+		obj.SetNamespace("ns1")
+		obj.SetName(name)
+		obj.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "test", APIVersion: managerAPIVersion}})
+		return obj
+	}
+
+	objs := []client.Object{
+		newCRD("foos.foo", "Foo", "v1alpha4", "v1beta1"),
+		newCRD("bars.foo", "Bar", "v1beta1"),
+		newObj("Foo", "foo2", "foo/v1beta1"),
+		newObj("Foo", "foo1", "foo/v1beta1"),
+		newObj("Bar", "bar1", "foo/v1alpha4"),
+		// CRDs not installed by clusterctl are ignored.
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "others.bar"}},
+	}
+	proxy := test.NewFakeProxy().WithObjs(objs...)
+	auditor := newStorageVersionAuditor(proxy)
+
+	audits, err := auditor.Audit()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(audits).To(Equal([]StorageVersionAudit{
+		{
+			CRD:                "bars.foo",
+			Kind:               "Bar",
+			StorageVersion:     "v1beta1",
+			StoredVersions:     []string{"v1beta1"},
+			StaleVersions:      []string{},
+			DeprecatedVersions: []string{"v1alpha4"}, // Used by a client writing bar1.
+			Objects:            []string{},
+		},
+		{
+			CRD:                "foos.foo",
+			Kind:               "Foo",
+			StorageVersion:     "v1beta1",
+			StoredVersions:     []string{"v1alpha4", "v1beta1"},
+			StaleVersions:      []string{"v1alpha4"},
+			DeprecatedVersions: []string{"v1alpha4"},
+			Objects:            []string{"ns1/foo1", "ns1/foo2"},
+		},
+	}))
+	g.Expect(audits[0].RequiresMigration()).To(BeFalse())
+	g.Expect(audits[1].RequiresMigration()).To(BeTrue())
+
+	g.Expect(auditor.Migrate(audits)).To(Succeed())
+	g.Expect(audits[0].Migrated).To(BeFalse())
+	g.Expect(audits[1].Migrated).To(BeTrue())
+	g.Expect(audits[1].RequiresMigration()).To(BeFalse())
+
+	c, err := proxy.NewClient()
+	g.Expect(err).ToNot(HaveOccurred())
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Name: "foos.foo"}, crd)).To(Succeed())
+	g.Expect(crd.Status.StoredVersions).To(Equal([]string{"v1beta1"}))
+}
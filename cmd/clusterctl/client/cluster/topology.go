@@ -56,6 +56,8 @@ const (
 // TopologyClient has methods to work with ClusterClass and ManagedTopologies.
 type TopologyClient interface {
 	Plan(in *TopologyPlanInput) (*TopologyPlanOutput, error)
+	Rebase(in *TopologyRebaseInput) (*TopologyRebaseOutput, error)
+	Ownership(in *TopologyOwnershipInput) (*TopologyOwnershipOutput, error)
 }
 
 // topologyClient implements TopologyClient.
@@ -0,0 +1,326 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/structuredmerge"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/contract"
+	"sigs.k8s.io/cluster-api/util/labels"
+)
+
+// TopologyOwnershipInput defines the input for the Ownership function.
+type TopologyOwnershipInput struct {
+	// Namespace of the Cluster.
+	Namespace string
+
+	// ClusterName is the name of the Cluster with a managed topology to report the field ownership for.
+	ClusterName string
+}
+
+// TopologyOwnershipOutput defines the output of the Ownership function.
+type TopologyOwnershipOutput struct {
+	// Objects is the field ownership report for each object managed by the topology controller,
+	// including the Cluster itself.
+	Objects []TopologyOwnershipObject
+}
+
+// TopologyOwnershipObject defines the field ownership report for an object managed by the topology controller.
+type TopologyOwnershipObject struct {
+	// Object is a reference to the managed object.
+	Object corev1.ObjectReference
+
+	// Managers lists the fields owned by each field manager, sorted by manager name.
+	Managers []TopologyFieldManager
+
+	// Conflicts lists the fields owned by the topology controller which are also owned by other field managers;
+	// those fields are going to be reset to the value computed by the topology controller on the next reconcile.
+	Conflicts []TopologyFieldConflict
+}
+
+// TopologyFieldManager defines the fields owned by a field manager.
+type TopologyFieldManager struct {
+	// Manager is the name of the field manager.
+	Manager string
+
+	// Operation is the type of the operation which led to the field ownership, Apply or Update.
+	Operation metav1.ManagedFieldsOperationType
+
+	// Subresource is the name of the subresource the fields have been set with, e.g. status; it is empty
+	// for the main resource.
+	Subresource string
+
+	// Fields is the sorted list of the paths of the owned fields.
+	Fields []string
+}
+
+// TopologyFieldConflict defines a field owned by the topology controller together with other field managers.
+type TopologyFieldConflict struct {
+	// Field is the path of the field.
+	Field string
+
+	// Managers is the list of the other field managers owning the field.
+	Managers []string
+}
+
+// Ownership reports the server side apply field ownership for the objects managed by the topology controller
+// for a Cluster, and flags the fields co-owned by the topology controller and other field managers.
+func (t *topologyClient) Ownership(in *TopologyOwnershipInput) (*TopologyOwnershipOutput, error) {
+	ctx := context.TODO()
+
+	if in.ClusterName == "" {
+		return nil, errors.New("the name of the Cluster must be specified")
+	}
+
+	c, err := t.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &clusterv1.Cluster{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: in.Namespace, Name: in.ClusterName}, cluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Cluster %s/%s", in.Namespace, in.ClusterName)
+	}
+	if cluster.Spec.Topology == nil {
+		return nil, errors.Errorf("Cluster %s/%s does not have a managed topology", in.Namespace, in.ClusterName)
+	}
+
+	objs, err := getTopologyManagedObjects(ctx, c, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &TopologyOwnershipOutput{}
+	for _, obj := range objs {
+		report, err := fieldOwnership(obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compute field ownership for %s %s", obj.GetKind(), klog.KObj(obj))
+		}
+		out.Objects = append(out.Objects, *report)
+	}
+	return out, nil
+}
+
+// getTopologyManagedObjects returns the Cluster and all the objects managed by the topology controller for it, i.e. the
+// InfrastructureCluster, the ControlPlane and its InfrastructureMachineTemplate, the MachineDeployments with their templates
+// and the MachineHealthChecks.
+// NOTE: Objects not owned by the topology, e.g. an externally managed InfrastructureCluster, are not included.
+func getTopologyManagedObjects(ctx context.Context, c client.Client, cluster *clusterv1.Cluster) ([]*unstructured.Unstructured, error) {
+	objs := []*unstructured.Unstructured{}
+
+	clusterObj := &unstructured.Unstructured{}
+	if err := c.Scheme().Convert(cluster, clusterObj, nil); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert Cluster %s to unstructured", klog.KObj(cluster))
+	}
+	clusterObj.SetGroupVersionKind(clusterv1.GroupVersion.WithKind("Cluster"))
+	objs = append(objs, clusterObj)
+
+	addRef := func(ref *corev1.ObjectReference) (*unstructured.Unstructured, error) {
+		obj, err := external.Get(ctx, c, ref, cluster.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if labels.IsTopologyOwned(obj) && !annotations.IsExternallyManaged(obj) {
+			objs = append(objs, obj)
+		}
+		return obj, nil
+	}
+
+	if cluster.Spec.InfrastructureRef != nil {
+		if _, err := addRef(cluster.Spec.InfrastructureRef); err != nil {
+			return nil, err
+		}
+	}
+
+	if cluster.Spec.ControlPlaneRef != nil {
+		controlPlane, err := addRef(cluster.Spec.ControlPlaneRef)
+		if err != nil {
+			return nil, err
+		}
+		machineInfrastructureRef, err := contract.ControlPlane().MachineTemplate().InfrastructureRef().Get(controlPlane)
+		if err != nil && !contract.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get InfrastructureMachineTemplate reference for %s", klog.KObj(controlPlane))
+		}
+		if machineInfrastructureRef != nil {
+			if _, err := addRef(machineInfrastructureRef); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	topologyLabels := client.MatchingLabels{
+		clusterv1.ClusterNameLabel:          cluster.Name,
+		clusterv1.ClusterTopologyOwnedLabel: "",
+	}
+
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := c.List(ctx, machineDeployments, client.InNamespace(cluster.Namespace), topologyLabels); err != nil {
+		return nil, errors.Wrap(err, "failed to list MachineDeployments")
+	}
+	for i := range machineDeployments.Items {
+		md := &machineDeployments.Items[i]
+		mdObj := &unstructured.Unstructured{}
+		if err := c.Scheme().Convert(md, mdObj, nil); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert MachineDeployment %s to unstructured", klog.KObj(md))
+		}
+		mdObj.SetGroupVersionKind(clusterv1.GroupVersion.WithKind("MachineDeployment"))
+		objs = append(objs, mdObj)
+
+		if md.Spec.Template.Spec.Bootstrap.ConfigRef != nil {
+			if _, err := addRef(md.Spec.Template.Spec.Bootstrap.ConfigRef); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := addRef(&md.Spec.Template.Spec.InfrastructureRef); err != nil {
+			return nil, err
+		}
+	}
+
+	machineHealthChecks := &clusterv1.MachineHealthCheckList{}
+	if err := c.List(ctx, machineHealthChecks, client.InNamespace(cluster.Namespace), topologyLabels); err != nil {
+		return nil, errors.Wrap(err, "failed to list MachineHealthChecks")
+	}
+	for i := range machineHealthChecks.Items {
+		mhc := &machineHealthChecks.Items[i]
+		mhcObj := &unstructured.Unstructured{}
+		if err := c.Scheme().Convert(mhc, mhcObj, nil); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert MachineHealthCheck %s to unstructured", klog.KObj(mhc))
+		}
+		mhcObj.SetGroupVersionKind(clusterv1.GroupVersion.WithKind("MachineHealthCheck"))
+		objs = append(objs, mhcObj)
+	}
+
+	return objs, nil
+}
+
+// fieldOwnership computes the field ownership report for an object from its managed fields.
+func fieldOwnership(obj *unstructured.Unstructured) (*TopologyOwnershipObject, error) {
+	report := &TopologyOwnershipObject{
+		Object: corev1.ObjectReference{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		},
+	}
+
+	topologyFields := sets.Set[string]{}
+	otherManagers := map[string]sets.Set[string]{}
+	for _, managedField := range obj.GetManagedFields() {
+		fields := []string{}
+		if managedField.FieldsV1 != nil && len(managedField.FieldsV1.Raw) > 0 {
+			fieldsV1 := map[string]interface{}{}
+			if err := json.Unmarshal(managedField.FieldsV1.Raw, &fieldsV1); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal managed fields of manager %s", managedField.Manager)
+			}
+			fields = managedFieldPaths("", fieldsV1)
+			sort.Strings(fields)
+		}
+
+		report.Managers = append(report.Managers, TopologyFieldManager{
+			Manager:     managedField.Manager,
+			Operation:   managedField.Operation,
+			Subresource: managedField.Subresource,
+			Fields:      fields,
+		})
+
+		// Fields set on subresources, e.g. status, are never set by the topology controller, so they can't conflict.
+		if managedField.Subresource != "" {
+			continue
+		}
+		if managedField.Manager == structuredmerge.TopologyManagerName && managedField.Operation == metav1.ManagedFieldsOperationApply {
+			topologyFields.Insert(fields...)
+			continue
+		}
+		if _, ok := otherManagers[managedField.Manager]; !ok {
+			otherManagers[managedField.Manager] = sets.Set[string]{}
+		}
+		otherManagers[managedField.Manager].Insert(fields...)
+	}
+
+	sort.SliceStable(report.Managers, func(i, j int) bool {
+		return report.Managers[i].Manager < report.Managers[j].Manager
+	})
+
+	for _, field := range sets.List(topologyFields) {
+		managers := []string{}
+		for manager, fields := range otherManagers {
+			if fields.Has(field) {
+				managers = append(managers, manager)
+			}
+		}
+		if len(managers) == 0 {
+			continue
+		}
+		sort.Strings(managers)
+		report.Conflicts = append(report.Conflicts, TopologyFieldConflict{Field: field, Managers: managers})
+	}
+	return report, nil
+}
+
+// managedFieldPaths returns the paths of the fields in a FieldsV1 set; only leaf fields and fields owned as a whole
+// (e.g. an item of a list identified by a key) are returned.
+// Paths use dots as separators, list items are identified by key, value or index in square brackets, e.g.
+// spec.template.spec.containers[{"name":"manager"}].image.
+func managedFieldPaths(prefix string, fieldsV1 map[string]interface{}) []string {
+	paths := []string{}
+	for key, value := range fieldsV1 {
+		if key == "." {
+			continue
+		}
+
+		var path string
+		switch {
+		case strings.HasPrefix(key, "f:"):
+			path = strings.TrimPrefix(key, "f:")
+			if prefix != "" {
+				path = fmt.Sprintf("%s.%s", prefix, path)
+			}
+		case strings.HasPrefix(key, "k:"), strings.HasPrefix(key, "v:"), strings.HasPrefix(key, "i:"):
+			path = fmt.Sprintf("%s[%s]", prefix, key[2:])
+		default:
+			path = fmt.Sprintf("%s[%s]", prefix, key)
+		}
+
+		children, ok := value.(map[string]interface{})
+		if !ok || len(children) == 0 {
+			paths = append(paths, path)
+			continue
+		}
+		if _, ok := children["."]; ok {
+			paths = append(paths, path)
+		}
+		paths = append(paths, managedFieldPaths(path, children)...)
+	}
+	return paths
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_fieldOwnership(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta1")
+	obj.SetKind("GenericInfrastructureCluster")
+	obj.SetNamespace("ns1")
+	obj.SetName("foo")
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:   "capi-topology",
+			Operation: metav1.ManagedFieldsOperationApply,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:topology.cluster.x-k8s.io/owned":{}}},"f:spec":{"f:region":{},"f:subnets":{"k:{\"name\":\"a\"}":{".":{},"f:name":{},"f:cidr":{}}}}}`)},
+		},
+		{
+			Manager:   "kubectl-edit",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:region":{},"f:zone":{}}}`)},
+		},
+		{
+			Manager:     "infra-controller",
+			Operation:   metav1.ManagedFieldsOperationUpdate,
+			Subresource: "status",
+			FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:ready":{}}}`)},
+		},
+	})
+
+	got, err := fieldOwnership(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(got.Object.Kind).To(Equal("GenericInfrastructureCluster"))
+	g.Expect(got.Object.Name).To(Equal("foo"))
+	g.Expect(got.Managers).To(Equal([]TopologyFieldManager{
+		{
+			Manager:   "capi-topology",
+			Operation: metav1.ManagedFieldsOperationApply,
+			Fields: []string{
+				"metadata.labels.topology.cluster.x-k8s.io/owned",
+				"spec.region",
+				`spec.subnets[{"name":"a"}]`,
+				`spec.subnets[{"name":"a"}].cidr`,
+				`spec.subnets[{"name":"a"}].name`,
+			},
+		},
+		{
+			Manager:     "infra-controller",
+			Operation:   metav1.ManagedFieldsOperationUpdate,
+			Subresource: "status",
+			Fields:      []string{"status.ready"},
+		},
+		{
+			Manager:   "kubectl-edit",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			Fields:    []string{"spec.region", "spec.zone"},
+		},
+	}))
+	g.Expect(got.Conflicts).To(Equal([]TopologyFieldConflict{
+		{Field: "spec.region", Managers: []string{"kubectl-edit"}},
+	}))
+}
+
+func Test_topologyClient_Ownership(t *testing.T) {
+	objs := []client.Object{
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "without-topology"},
+		},
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns1",
+				Name:      "with-topology",
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					{
+						Manager:   "capi-topology",
+						Operation: metav1.ManagedFieldsOperationApply,
+						FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:infrastructureRef":{}}}`)},
+					},
+				},
+			},
+			Spec: clusterv1.ClusterSpec{
+				Topology: &clusterv1.Topology{Class: "class", Version: "v1.26.0"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		clusterName string
+		wantObjects int
+		wantErr     bool
+	}{
+		{
+			name:        "report the field ownership for a Cluster with a managed topology",
+			clusterName: "with-topology",
+			wantObjects: 1,
+		},
+		{
+			name:        "fail for a Cluster without a managed topology",
+			clusterName: "without-topology",
+			wantErr:     true,
+		},
+		{
+			name:        "fail if the Cluster does not exist",
+			clusterName: "does-not-exist",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			proxy := test.NewFakeProxy().WithObjs(objs...)
+			topologyClient := newTopologyClient(proxy, nil)
+
+			got, err := topologyClient.Ownership(&TopologyOwnershipInput{Namespace: "ns1", ClusterName: tt.clusterName})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Objects).To(HaveLen(tt.wantObjects))
+			g.Expect(got.Objects[0].Object.Kind).To(Equal("Cluster"))
+		})
+	}
+}
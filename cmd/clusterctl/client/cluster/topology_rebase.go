@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// TopologyRebaseInput defines the input for the Rebase function.
+type TopologyRebaseInput struct {
+	// Namespace of the Clusters to rebase.
+	Namespace string
+
+	// ClusterNames is the list of the Clusters to rebase; it can't be used together with Selector.
+	ClusterNames []string
+
+	// Selector selects the Clusters to rebase, so they can be rebased gradually, e.g. in waves.
+	Selector labels.Selector
+
+	// ToClass is the name of the ClusterClass to rebase the Clusters to; if empty, Clusters are rebased
+	// to the latest revision of the lineage of their current ClusterClass.
+	ToClass string
+
+	// DryRun validates the rebase, including the compatibility checks implemented by webhooks, without
+	// changing the Clusters.
+	DryRun bool
+
+	// AllowDowngrade allows rebasing Clusters to an older revision of the lineage of their current ClusterClass, e.g. to
+	// roll back a rebase, by setting the ClusterTopologyUnsafeAllowClassDowngradeAnnotation on the Clusters.
+	AllowDowngrade bool
+}
+
+// TopologyRebaseOutput defines the output of the Rebase function.
+type TopologyRebaseOutput struct {
+	// Clusters is the result of the rebase for each selected Cluster.
+	Clusters []TopologyRebaseResult
+}
+
+// TopologyRebaseResult defines the result of the rebase of a Cluster.
+type TopologyRebaseResult struct {
+	// Cluster is the rebased Cluster.
+	Cluster client.ObjectKey
+	// FromClass is the ClusterClass used by the Cluster before the rebase.
+	FromClass string
+	// ToClass is the ClusterClass the Cluster is rebased to.
+	ToClass string
+	// Rebased is true if the Cluster has been rebased, or if it would be rebased in dry run.
+	Rebased bool
+	// Error reports why the Cluster can't be rebased, if any.
+	Error error
+}
+
+// Rebase changes the ClusterClass of Clusters with a managed topology to a newer revision.
+// NOTE: Errors rebasing a Cluster, e.g. because the new ClusterClass is not compatible, are reported in the
+// result for the Cluster, and they do not prevent rebasing the other Clusters.
+func (t *topologyClient) Rebase(in *TopologyRebaseInput) (*TopologyRebaseOutput, error) {
+	ctx := context.TODO()
+	log := logf.Log
+
+	if len(in.ClusterNames) > 0 && in.Selector != nil && !in.Selector.Empty() {
+		return nil, errors.New("cluster names and selector can't be used together")
+	}
+	if len(in.ClusterNames) == 0 && (in.Selector == nil || in.Selector.Empty()) {
+		return nil, errors.New("at least one cluster name or a selector must be specified")
+	}
+
+	c, err := t.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := t.getClustersToRebase(ctx, c, in)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterClasses := &clusterv1.ClusterClassList{}
+	if err := c.List(ctx, clusterClasses, client.InNamespace(in.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list ClusterClasses")
+	}
+
+	out := &TopologyRebaseOutput{}
+	for i := range clusters {
+		cluster := &clusters[i]
+		result := TopologyRebaseResult{Cluster: client.ObjectKeyFromObject(cluster)}
+		if cluster.Spec.Topology == nil {
+			result.Error = errors.New("the Cluster does not have a managed topology")
+			out.Clusters = append(out.Clusters, result)
+			continue
+		}
+		result.FromClass = cluster.Spec.Topology.Class
+
+		toClass, err := rebaseTargetClass(cluster.Spec.Topology.Class, in.ToClass, clusterClasses.Items)
+		if err != nil {
+			result.Error = err
+			out.Clusters = append(out.Clusters, result)
+			continue
+		}
+		result.ToClass = toClass
+		if toClass == cluster.Spec.Topology.Class {
+			out.Clusters = append(out.Clusters, result)
+			continue
+		}
+
+		log.V(1).Info("Rebasing Cluster", "Cluster", result.Cluster, "from", result.FromClass, "to", toClass)
+		patch := client.MergeFrom(cluster.DeepCopy())
+		cluster.Spec.Topology.Class = toClass
+		if in.AllowDowngrade {
+			annotations := cluster.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[clusterv1.ClusterTopologyUnsafeAllowClassDowngradeAnnotation] = ""
+			cluster.SetAnnotations(annotations)
+		}
+		opts := []client.PatchOption{}
+		if in.DryRun {
+			opts = append(opts, client.DryRunAll)
+		}
+		if err := c.Patch(ctx, cluster, patch, opts...); err != nil {
+			result.Error = errors.Wrapf(err, "failed to rebase Cluster to ClusterClass %s", toClass)
+			out.Clusters = append(out.Clusters, result)
+			continue
+		}
+		result.Rebased = true
+		out.Clusters = append(out.Clusters, result)
+	}
+	return out, nil
+}
+
+// getClustersToRebase returns the Clusters selected by the input.
+func (t *topologyClient) getClustersToRebase(ctx context.Context, c client.Client, in *TopologyRebaseInput) ([]clusterv1.Cluster, error) {
+	if len(in.ClusterNames) == 0 {
+		clusters := &clusterv1.ClusterList{}
+		if err := c.List(ctx, clusters, client.InNamespace(in.Namespace), client.MatchingLabelsSelector{Selector: in.Selector}); err != nil {
+			return nil, errors.Wrap(err, "failed to list Clusters")
+		}
+		return clusters.Items, nil
+	}
+
+	clusters := []clusterv1.Cluster{}
+	for _, name := range in.ClusterNames {
+		cluster := &clusterv1.Cluster{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: in.Namespace, Name: name}, cluster); err != nil {
+			return nil, errors.Wrapf(err, "failed to get Cluster %s/%s", in.Namespace, name)
+		}
+		clusters = append(clusters, *cluster)
+	}
+	return clusters, nil
+}
+
+// rebaseTargetClass returns the ClusterClass a Cluster using currentClass should be rebased to; if toClass is not set,
+// it is the latest revision in the lineage of currentClass.
+func rebaseTargetClass(currentClass, toClass string, clusterClasses []clusterv1.ClusterClass) (string, error) {
+	if toClass != "" {
+		for _, clusterClass := range clusterClasses {
+			if clusterClass.Name == toClass {
+				return toClass, nil
+			}
+		}
+		return "", errors.Errorf("ClusterClass %s does not exist", toClass)
+	}
+
+	var current *clusterv1.ClusterClass
+	for i := range clusterClasses {
+		if clusterClasses[i].Name == currentClass {
+			current = &clusterClasses[i]
+			break
+		}
+	}
+	if current == nil {
+		return "", errors.Errorf("ClusterClass %s does not exist", currentClass)
+	}
+	if current.Spec.Lineage == nil {
+		return "", errors.Errorf("ClusterClass %s does not have a lineage, the ClusterClass to rebase to must be specified", currentClass)
+	}
+
+	latest := current
+	for i := range clusterClasses {
+		clusterClass := &clusterClasses[i]
+		if clusterClass.Spec.Lineage == nil || clusterClass.Spec.Lineage.Name != current.Spec.Lineage.Name {
+			continue
+		}
+		if clusterClass.Spec.Lineage.Revision > latest.Spec.Lineage.Revision {
+			latest = clusterClass
+		}
+	}
+	return latest.Name, nil
+}
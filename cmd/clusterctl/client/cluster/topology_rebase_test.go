@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_topologyClient_Rebase(t *testing.T) {
+	clusterClass := func(name, lineage string, revision int32) *clusterv1.ClusterClass {
+		cc := &clusterv1.ClusterClass{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name}}
+		if lineage != "" {
+			cc.Spec.Lineage = &clusterv1.ClusterClassLineage{Name: lineage, Revision: revision}
+		}
+		return cc
+	}
+	cluster := func(name, class string, labels map[string]string) *clusterv1.Cluster {
+		c := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name, Labels: labels}}
+		if class != "" {
+			c.Spec.Topology = &clusterv1.Topology{Class: class, Version: "v1.26.0"}
+		}
+		return c
+	}
+	objs := []client.Object{
+		clusterClass("class-v1", "class", 1),
+		clusterClass("class-v2", "class", 2),
+		clusterClass("class-v3", "class", 3),
+		clusterClass("other", "", 0),
+		cluster("cluster1", "class-v1", map[string]string{"wave": "1"}),
+		cluster("cluster2", "class-v1", map[string]string{"wave": "2"}),
+		cluster("cluster3", "other", nil),
+		cluster("cluster4", "", nil),
+		cluster("cluster5", "class-v3", nil),
+	}
+
+	tests := []struct {
+		name        string
+		in          *TopologyRebaseInput
+		wantResults []TopologyRebaseResult
+		wantErr     bool
+	}{
+		{
+			name: "rebase to the latest revision of the lineage",
+			in: &TopologyRebaseInput{
+				Namespace:    "ns1",
+				ClusterNames: []string{"cluster1"},
+			},
+			wantResults: []TopologyRebaseResult{
+				{Cluster: client.ObjectKey{Namespace: "ns1", Name: "cluster1"}, FromClass: "class-v1", ToClass: "class-v3", Rebased: true},
+			},
+		},
+		{
+			name: "rebase clusters matching a selector to a given class",
+			in: &TopologyRebaseInput{
+				Namespace: "ns1",
+				Selector:  labels.SelectorFromSet(labels.Set{"wave": "2"}),
+				ToClass:   "class-v2",
+			},
+			wantResults: []TopologyRebaseResult{
+				{Cluster: client.ObjectKey{Namespace: "ns1", Name: "cluster2"}, FromClass: "class-v1", ToClass: "class-v2", Rebased: true},
+			},
+		},
+		{
+			name: "rebase to an older revision if downgrades are allowed",
+			in: &TopologyRebaseInput{
+				Namespace:      "ns1",
+				ClusterNames:   []string{"cluster5"},
+				ToClass:        "class-v1",
+				AllowDowngrade: true,
+			},
+			wantResults: []TopologyRebaseResult{
+				{Cluster: client.ObjectKey{Namespace: "ns1", Name: "cluster5"}, FromClass: "class-v3", ToClass: "class-v1", Rebased: true},
+			},
+		},
+		{
+			name: "fail if the cluster names and a selector are used together",
+			in: &TopologyRebaseInput{
+				Namespace:    "ns1",
+				ClusterNames: []string{"cluster1"},
+				Selector:     labels.SelectorFromSet(labels.Set{"wave": "2"}),
+			},
+			wantErr: true,
+		},
+		{
+			name: "fail if no cluster is selected",
+			in: &TopologyRebaseInput{
+				Namespace: "ns1",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			proxy := test.NewFakeProxy().WithObjs(objs...)
+			tc := newTopologyClient(proxy, nil)
+
+			out, err := tc.Rebase(tt.in)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(out.Clusters).To(Equal(tt.wantResults))
+
+			c, err := proxy.NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+			for _, r := range tt.wantResults {
+				got := &clusterv1.Cluster{}
+				g.Expect(c.Get(ctx, r.Cluster, got)).To(Succeed())
+				g.Expect(got.Spec.Topology.Class).To(Equal(r.ToClass))
+				if tt.in.AllowDowngrade {
+					g.Expect(got.Annotations).To(HaveKey(clusterv1.ClusterTopologyUnsafeAllowClassDowngradeAnnotation))
+				} else {
+					g.Expect(got.Annotations).ToNot(HaveKey(clusterv1.ClusterTopologyUnsafeAllowClassDowngradeAnnotation))
+				}
+			}
+		})
+	}
+}
+
+func Test_topologyClient_Rebase_Errors(t *testing.T) {
+	g := NewWithT(t)
+
+	proxy := test.NewFakeProxy().WithObjs(
+		&clusterv1.ClusterClass{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "other"}},
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cluster1"},
+			Spec:       clusterv1.ClusterSpec{Topology: &clusterv1.Topology{Class: "other", Version: "v1.26.0"}},
+		},
+		&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cluster2"}},
+	)
+	tc := newTopologyClient(proxy, nil)
+
+	out, err := tc.Rebase(&TopologyRebaseInput{Namespace: "ns1", ClusterNames: []string{"cluster1", "cluster2"}})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(out.Clusters).To(HaveLen(2))
+
+	// A ClusterClass without a lineage requires the ClusterClass to rebase to.
+	g.Expect(out.Clusters[0].Rebased).To(BeFalse())
+	g.Expect(out.Clusters[0].Error).To(HaveOccurred())
+
+	// A Cluster without a managed topology can't be rebased.
+	g.Expect(out.Clusters[1].Rebased).To(BeFalse())
+	g.Expect(out.Clusters[1].Error).To(HaveOccurred())
+}
@@ -32,6 +32,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/errorcodes"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
 )
@@ -41,11 +42,22 @@ type ProviderUpgrader interface {
 	// Plan returns a set of suggested Upgrade plans for the management cluster.
 	Plan() ([]UpgradePlan, error)
 
+	// PlanPath returns the sequence of upgrade steps required to upgrade the management cluster to the target
+	// version of the core provider, possibly going through several API Versions of Cluster API (contract).
+	PlanPath(targetVersion string) (*UpgradePath, error)
+
 	// ApplyPlan executes an upgrade following an UpgradePlan generated by clusterctl.
 	ApplyPlan(opts UpgradeOptions, clusterAPIVersion string) error
 
 	// ApplyCustomPlan plan executes an upgrade using the UpgradeItems provided by the user.
 	ApplyCustomPlan(opts UpgradeOptions, providersToUpgrade ...UpgradeItem) error
+
+	// ApplyStagedPlan executes an upgrade following an UpgradePlan generated by clusterctl one provider at a time,
+	// verifying each provider after the upgrade and rolling it back to the previous version if the verification fails.
+	ApplyStagedPlan(opts UpgradeOptions, clusterAPIVersion string) (*UpgradeResult, error)
+
+	// ApplyStagedCustomPlan executes a staged upgrade using the UpgradeItems provided by the user.
+	ApplyStagedCustomPlan(opts UpgradeOptions, providersToUpgrade ...UpgradeItem) (*UpgradeResult, error)
 }
 
 // UpgradePlan defines a list of possible upgrade targets for a management cluster.
@@ -280,7 +292,7 @@ func (u *providerUpgrader) createCustomPlan(upgradeItems []UpgradeItem) (*Upgrad
 		}
 
 		if contract != targetContract {
-			return nil, errors.Errorf("unable to complete that upgrade: the target version for the provider %s supports the %s API Version of Cluster API (contract), while the management cluster is using %s", upgradeItem.InstanceName(), contract, targetContract)
+			return nil, errorcodes.Wrap(errorcodes.ContractMismatch, errors.Errorf("unable to complete that upgrade: the target version for the provider %s supports the %s API Version of Cluster API (contract), while the management cluster is using %s", upgradeItem.InstanceName(), contract, targetContract))
 		}
 
 		upgradePlan.Providers = append(upgradePlan.Providers, upgradeItem)
@@ -301,7 +313,7 @@ func (u *providerUpgrader) createCustomPlan(upgradeItems []UpgradeItem) (*Upgrad
 		}
 
 		if contract != targetContract {
-			return nil, errors.Errorf("unable to complete that upgrade: the provider %s supports the %s API Version of Cluster API (contract), while the management cluster is being updated to %s. Please include the %[1]s provider in the upgrade", provider.InstanceName(), contract, targetContract)
+			return nil, errorcodes.Wrap(errorcodes.ContractMismatch, errors.Errorf("unable to complete that upgrade: the provider %s supports the %s API Version of Cluster API (contract), while the management cluster is being updated to %s. Please include the %[1]s provider in the upgrade", provider.InstanceName(), contract, targetContract))
 		}
 	}
 	return upgradePlan, nil
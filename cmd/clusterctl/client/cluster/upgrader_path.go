@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// UpgradePath defines the sequence of upgrade steps required to upgrade a management cluster
+// to a target version of the core provider.
+type UpgradePath struct {
+	// CoreProvider is the core provider of the management cluster.
+	CoreProvider clusterctlv1.Provider
+
+	// TargetVersion is the target version of the core provider.
+	TargetVersion string
+
+	// Steps are the upgrade steps to be executed in order.
+	Steps []UpgradeStep
+}
+
+// UpgradeStep defines a step of an UpgradePath, i.e. the upgrade of the providers to the API Version of Cluster API (contract)
+// of the step. Every step must be completed before starting the next one.
+type UpgradeStep struct {
+	// UpgradePlan defines the upgrade of the providers in the step; the version of each provider is the version
+	// before the step, i.e. the version reached in the previous step.
+	UpgradePlan
+
+	// PreviousContract is the API Version of Cluster API (contract) of the management cluster before the step.
+	PreviousContract string
+
+	// ClusterctlVersion is the version of clusterctl to be used for executing the step.
+	// It is empty if the core provider is not Cluster API, given that the clusterctl version cannot be determined.
+	ClusterctlVersion string
+
+	// Blockers are the issues preventing the step to be executed, e.g. providers without releases for the contract.
+	Blockers []string
+}
+
+// IsContractChange returns true if the step changes the API Version of Cluster API (contract) of the management cluster.
+// When the contract changes, the storage version of the provider's CRDs changes as well, and clusterctl migrates
+// the existing objects to the new storage version while upgrading.
+func (s *UpgradeStep) IsContractChange() bool {
+	return s.PreviousContract != s.Contract
+}
+
+// PlanPath computes the sequence of upgrade steps required to upgrade the management cluster to the target version
+// of the core provider, going through all the API Versions of Cluster API (contract) in between; for every contract,
+// the providers are upgraded to the latest version available, except for the core provider in the last step, which is upgraded
+// to the target version.
+func (u *providerUpgrader) PlanPath(targetVersion string) (*UpgradePath, error) {
+	log := logf.Log
+	log.Info("Computing the upgrade path...")
+
+	providerList, err := u.providerInventory.List()
+	if err != nil {
+		return nil, err
+	}
+
+	coreProviders := providerList.FilterCore()
+	if len(coreProviders) != 1 {
+		return nil, errors.Errorf("invalid management cluster: there should a core provider, found %d", len(coreProviders))
+	}
+	coreProvider := coreProviders[0]
+
+	// Gets the upgrade info for all the providers.
+	upgradeInfos := map[string]*upgradeInfo{}
+	for _, provider := range providerList.Items {
+		upgradeInfo, err := u.getUpgradeInfo(provider)
+		if err != nil {
+			return nil, err
+		}
+		upgradeInfos[provider.InstanceName()] = upgradeInfo
+	}
+	coreUpgradeInfo := upgradeInfos[coreProvider.InstanceName()]
+
+	targetSemVersion, err := version.ParseSemantic(targetVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse target version for the %s provider", coreProvider.InstanceName())
+	}
+	if !coreUpgradeInfo.hasNextVersion(targetSemVersion) {
+		return nil, errors.Errorf("invalid target version: version %s is not available for upgrading the %s provider from version %s", targetVersion, coreProvider.InstanceName(), coreProvider.Version)
+	}
+
+	targetContract := coreUpgradeInfo.metadata.GetReleaseSeriesForVersion(targetSemVersion).Contract
+	path := &UpgradePath{
+		CoreProvider:  coreProvider,
+		TargetVersion: versionTag(targetSemVersion),
+	}
+
+	// Keeps track of the version of each provider after every step, so each step starts from the versions
+	// reached in the previous one.
+	currentVersions := map[string]string{}
+	for _, provider := range providerList.Items {
+		currentVersions[provider.InstanceName()] = provider.Version
+	}
+
+	previousContract := coreUpgradeInfo.currentContract
+	for _, contract := range coreUpgradeInfo.getContractsInPath(targetSemVersion) {
+		step := UpgradeStep{
+			UpgradePlan:      UpgradePlan{Contract: contract},
+			PreviousContract: previousContract,
+		}
+
+		for _, provider := range providerList.Items {
+			providerUpgradeInfo := upgradeInfos[provider.InstanceName()]
+			provider.Version = currentVersions[provider.InstanceName()]
+
+			nextVersion := providerUpgradeInfo.getLatestNextVersion(contract)
+			if provider.InstanceName() == coreProvider.InstanceName() && contract == targetContract {
+				nextVersion = targetSemVersion
+			}
+			if nextVersion != nil {
+				currentVersions[provider.InstanceName()] = versionTag(nextVersion)
+			}
+
+			if nextVersion == nil && providerUpgradeInfo.currentContract != contract {
+				step.Blockers = append(step.Blockers, fmt.Sprintf("there are no releases of the %s provider supporting the %s API Version of Cluster API (contract)", provider.InstanceName(), contract))
+			}
+
+			step.Providers = append(step.Providers, UpgradeItem{
+				Provider:    provider,
+				NextVersion: versionTag(nextVersion),
+			})
+		}
+
+		if coreProvider.ProviderName == config.ClusterAPIProviderName {
+			for _, item := range step.Providers {
+				if item.InstanceName() == coreProvider.InstanceName() {
+					step.ClusterctlVersion = item.NextVersion
+				}
+			}
+		}
+
+		path.Steps = append(path.Steps, step)
+		previousContract = contract
+	}
+
+	return path, nil
+}
+
+// hasNextVersion returns true if the version is one of the versions available for upgrades.
+func (i *upgradeInfo) hasNextVersion(v *version.Version) bool {
+	for j := range i.nextVersions {
+		if !i.nextVersions[j].LessThan(v) && !v.LessThan(&i.nextVersions[j]) {
+			return true
+		}
+	}
+	return false
+}
+
+// getContractsInPath returns the ordered list of API Versions of Cluster API (contract) a provider has to go through when
+// upgrading to the target version. The current contract is included only if it is the contract of the target version,
+// given that, when changing contract, it is possible to upgrade directly to the next contract.
+func (i *upgradeInfo) getContractsInPath(targetVersion *version.Version) []string {
+	contracts := []string{}
+	for _, releaseSeries := range i.metadata.ReleaseSeries {
+		// Skip the release series older than the current version, and stop at the release series of the target version.
+		if i.currentVersion.Major() > releaseSeries.Major || (i.currentVersion.Major() == releaseSeries.Major && i.currentVersion.Minor() > releaseSeries.Minor) {
+			continue
+		}
+		if targetVersion.Major() < releaseSeries.Major || (targetVersion.Major() == releaseSeries.Major && targetVersion.Minor() < releaseSeries.Minor) {
+			break
+		}
+
+		if len(contracts) == 0 || contracts[len(contracts)-1] != releaseSeries.Contract {
+			contracts = append(contracts, releaseSeries.Contract)
+		}
+	}
+
+	if len(contracts) > 1 && contracts[0] == i.currentContract {
+		contracts = contracts[1:]
+	}
+	return contracts
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterv1alpha3 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_providerUpgrader_PlanPath(t *testing.T) {
+	releaseSeries := []clusterctlv1.ReleaseSeries{
+		{Major: 1, Minor: 0, Contract: clusterv1alpha3.GroupVersion.Version},
+		{Major: 2, Minor: 0, Contract: test.PreviousCAPIContractNotSupported},
+		{Major: 3, Minor: 0, Contract: test.CurrentCAPIContract},
+		{Major: 3, Minor: 1, Contract: test.CurrentCAPIContract},
+	}
+
+	type fields struct {
+		reader     config.Reader
+		repository map[string]repository.Repository
+		proxy      Proxy
+	}
+	tests := []struct {
+		name          string
+		fields        fields
+		targetVersion string
+		want          []UpgradeStep
+		wantErr       bool
+	}{
+		{
+			name: "Upgrade path across contracts",
+			fields: fields{
+				reader: test.NewFakeReader().
+					WithProvider("cluster-api", clusterctlv1.CoreProviderType, "https://somewhere.com").
+					WithProvider("infra", clusterctlv1.InfrastructureProviderType, "https://somewhere.com"),
+				repository: map[string]repository.Repository{
+					"cluster-api": repository.NewMemoryRepository().
+						WithVersions("v1.0.0", "v1.0.1", "v2.0.0", "v2.0.1", "v3.0.0", "v3.1.0", "v3.1.1").
+						WithMetadata("v3.1.1", &clusterctlv1.Metadata{ReleaseSeries: releaseSeries}),
+					"infrastructure-infra": repository.NewMemoryRepository().
+						WithVersions("v1.0.0", "v2.0.0", "v3.1.0").
+						WithMetadata("v3.1.0", &clusterctlv1.Metadata{ReleaseSeries: releaseSeries}),
+				},
+				proxy: test.NewFakeProxy().
+					WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system").
+					WithProviderInventory("infra", clusterctlv1.InfrastructureProviderType, "v1.0.0", "infra-system"),
+			},
+			targetVersion: "v3.1.0",
+			want: []UpgradeStep{
+				{
+					UpgradePlan: UpgradePlan{
+						Contract: test.PreviousCAPIContractNotSupported,
+						Providers: []UpgradeItem{
+							{
+								Provider:    fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system"),
+								NextVersion: "v2.0.1",
+							},
+							{
+								Provider:    fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v1.0.0", "infra-system"),
+								NextVersion: "v2.0.0",
+							},
+						},
+					},
+					PreviousContract:  clusterv1alpha3.GroupVersion.Version,
+					ClusterctlVersion: "v2.0.1",
+				},
+				{
+					UpgradePlan: UpgradePlan{
+						Contract: test.CurrentCAPIContract,
+						Providers: []UpgradeItem{
+							{
+								Provider:    fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v2.0.1", "cluster-api-system"),
+								NextVersion: "v3.1.0",
+							},
+							{
+								Provider:    fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system"),
+								NextVersion: "v3.1.0",
+							},
+						},
+					},
+					PreviousContract:  test.PreviousCAPIContractNotSupported,
+					ClusterctlVersion: "v3.1.0",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Upgrade path blocked by a provider without releases for a contract",
+			fields: fields{
+				reader: test.NewFakeReader().
+					WithProvider("cluster-api", clusterctlv1.CoreProviderType, "https://somewhere.com").
+					WithProvider("infra", clusterctlv1.InfrastructureProviderType, "https://somewhere.com"),
+				repository: map[string]repository.Repository{
+					"cluster-api": repository.NewMemoryRepository().
+						WithVersions("v2.0.0", "v3.0.0").
+						WithMetadata("v3.0.0", &clusterctlv1.Metadata{ReleaseSeries: releaseSeries}),
+					"infrastructure-infra": repository.NewMemoryRepository().
+						WithVersions("v2.0.0").
+						WithMetadata("v2.0.0", &clusterctlv1.Metadata{ReleaseSeries: releaseSeries}),
+				},
+				proxy: test.NewFakeProxy().
+					WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v2.0.0", "cluster-api-system").
+					WithProviderInventory("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system"),
+			},
+			targetVersion: "v3.0.0",
+			want: []UpgradeStep{
+				{
+					UpgradePlan: UpgradePlan{
+						Contract: test.CurrentCAPIContract,
+						Providers: []UpgradeItem{
+							{
+								Provider:    fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v2.0.0", "cluster-api-system"),
+								NextVersion: "v3.0.0",
+							},
+							{
+								Provider:    fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system"),
+								NextVersion: "",
+							},
+						},
+					},
+					PreviousContract:  test.PreviousCAPIContractNotSupported,
+					ClusterctlVersion: "v3.0.0",
+					Blockers: []string{
+						"there are no releases of the infra-system/infrastructure-infra provider supporting the " + test.CurrentCAPIContract + " API Version of Cluster API (contract)",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Fails if the target version is not available",
+			fields: fields{
+				reader: test.NewFakeReader().
+					WithProvider("cluster-api", clusterctlv1.CoreProviderType, "https://somewhere.com"),
+				repository: map[string]repository.Repository{
+					"cluster-api": repository.NewMemoryRepository().
+						WithVersions("v2.0.0", "v3.0.0").
+						WithMetadata("v3.0.0", &clusterctlv1.Metadata{ReleaseSeries: releaseSeries}),
+				},
+				proxy: test.NewFakeProxy().
+					WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v2.0.0", "cluster-api-system"),
+			},
+			targetVersion: "v3.1.0",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			configClient, _ := config.New("", config.InjectReader(tt.fields.reader))
+
+			u := &providerUpgrader{
+				configClient: configClient,
+				repositoryClientFactory: func(provider config.Provider, configClient config.Client, options ...repository.Option) (repository.Client, error) {
+					return repository.New(provider, configClient, repository.InjectRepository(tt.fields.repository[provider.ManifestLabel()]))
+				},
+				providerInventory: newInventoryClient(tt.fields.proxy, nil),
+			}
+			got, err := u.PlanPath(tt.targetVersion)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.TargetVersion).To(Equal(tt.targetVersion))
+			g.Expect(got.Steps).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,513 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+const (
+	// defaultStagedUpgradeVerifyTimeout is the time to wait for a provider to pass verification after an upgrade,
+	// if a timeout is not specified.
+	defaultStagedUpgradeVerifyTimeout = 5 * time.Minute
+)
+
+// verifyProviderInterval is the interval between subsequent verifications of a provider after an upgrade.
+var verifyProviderInterval = 2 * time.Second
+
+// ProviderUpgradeStatus defines the outcome of the upgrade of a provider.
+type ProviderUpgradeStatus string
+
+const (
+	// ProviderUpgraded documents a provider upgraded to the target version.
+	ProviderUpgraded ProviderUpgradeStatus = "Upgraded"
+
+	// ProviderUpToDate documents a provider without a target version, i.e. already up-to-date.
+	ProviderUpToDate ProviderUpgradeStatus = "UpToDate"
+
+	// ProviderNotUpgraded documents a provider which has not been upgraded, e.g. because the upgrade
+	// of a provider before it in the plan failed.
+	ProviderNotUpgraded ProviderUpgradeStatus = "NotUpgraded"
+
+	// ProviderRolledBack documents a provider which failed the upgrade, and it has been rolled back
+	// to the previous version.
+	ProviderRolledBack ProviderUpgradeStatus = "RolledBack"
+
+	// ProviderRollbackFailed documents a provider which failed the upgrade, and it could not be rolled back
+	// to the previous version; the provider requires manual intervention.
+	ProviderRollbackFailed ProviderUpgradeStatus = "RollbackFailed"
+)
+
+// UpgradeCheckType defines the verifications performed on a provider after an upgrade.
+type UpgradeCheckType string
+
+const (
+	// DeploymentsAvailableCheck verifies that all the Deployments of the provider are available.
+	DeploymentsAvailableCheck UpgradeCheckType = "DeploymentsAvailable"
+
+	// WebhooksReachableCheck verifies that the Services backing the webhooks of the provider, including
+	// conversion webhooks, have ready endpoints.
+	WebhooksReachableCheck UpgradeCheckType = "WebhooksReachable"
+
+	// StorageVersionsMigratedCheck verifies that all the versions used to store objects of the provider CRDs
+	// are served by the upgraded CRDs.
+	StorageVersionsMigratedCheck UpgradeCheckType = "StorageVersionsMigrated"
+)
+
+// UpgradeCheck is the result of a verification performed on a provider after an upgrade.
+type UpgradeCheck struct {
+	Type    UpgradeCheckType `json:"type"`
+	Passed  bool             `json:"passed"`
+	Message string           `json:"message,omitempty"`
+}
+
+// ProviderUpgradeResult is the result of the upgrade of a provider.
+type ProviderUpgradeResult struct {
+	// Provider is the instance name of the provider.
+	Provider string `json:"provider"`
+
+	// Type is the type of the provider.
+	Type string `json:"type"`
+
+	// FromVersion is the version of the provider before the upgrade.
+	FromVersion string `json:"fromVersion"`
+
+	// ToVersion is the target version of the upgrade, if any.
+	ToVersion string `json:"toVersion,omitempty"`
+
+	// Status is the outcome of the upgrade.
+	Status ProviderUpgradeStatus `json:"status"`
+
+	// Checks are the verifications performed on the provider after the upgrade, if any.
+	Checks []UpgradeCheck `json:"checks,omitempty"`
+
+	// Error is the error that caused the upgrade to fail, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// UpgradeResult is the result of an upgrade of the providers in a management cluster.
+type UpgradeResult struct {
+	// Contract is the API Version of Cluster API (contract) of the upgrade.
+	Contract string `json:"contract"`
+
+	// Providers are the results of the upgrade of each provider, in the order providers are upgraded.
+	Providers []ProviderUpgradeResult `json:"providers"`
+}
+
+// newUpgradeResult returns an UpgradeResult for a plan where no provider has been upgraded yet.
+func newUpgradeResult(upgradePlan *UpgradePlan) *UpgradeResult {
+	result := &UpgradeResult{
+		Contract:  upgradePlan.Contract,
+		Providers: make([]ProviderUpgradeResult, 0, len(upgradePlan.Providers)),
+	}
+	for _, upgradeItem := range upgradePlan.Providers {
+		status := ProviderNotUpgraded
+		if upgradeItem.NextVersion == "" {
+			status = ProviderUpToDate
+		}
+		result.Providers = append(result.Providers, ProviderUpgradeResult{
+			Provider:    upgradeItem.InstanceName(),
+			Type:        upgradeItem.Type,
+			FromVersion: upgradeItem.Version,
+			ToVersion:   upgradeItem.NextVersion,
+			Status:      status,
+		})
+	}
+	return result
+}
+
+func (u *providerUpgrader) ApplyStagedPlan(opts UpgradeOptions, contract string) (*UpgradeResult, error) {
+	if contract != clusterv1.GroupVersion.Version {
+		return nil, errors.Errorf("current version of clusterctl could only upgrade to %s contract, requested %s", clusterv1.GroupVersion.Version, contract)
+	}
+
+	log := logf.Log
+	log.Info("Performing staged upgrade...")
+
+	// Gets the upgrade plan for the selected API Version of Cluster API (contract).
+	providerList, err := u.providerInventory.List()
+	if err != nil {
+		return nil, err
+	}
+
+	upgradePlan, err := u.getUpgradePlan(providerList.Items, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	// Do the upgrade
+	return u.doStagedUpgrade(upgradePlan, opts)
+}
+
+func (u *providerUpgrader) ApplyStagedCustomPlan(opts UpgradeOptions, upgradeItems ...UpgradeItem) (*UpgradeResult, error) {
+	log := logf.Log
+	log.Info("Performing staged upgrade...")
+
+	// Create a custom upgrade plan from the upgrade items, taking care of ensuring all the providers in a management
+	// cluster are consistent with the API Version of Cluster API (contract).
+	upgradePlan, err := u.createCustomPlan(upgradeItems)
+	if err != nil {
+		return nil, err
+	}
+
+	// Do the upgrade
+	return u.doStagedUpgrade(upgradePlan, opts)
+}
+
+// doStagedUpgrade upgrades the providers one at a time; after each upgrade the provider is verified, and if
+// the verification fails the provider is rolled back to the previous version and the upgrade is stopped.
+func (u *providerUpgrader) doStagedUpgrade(upgradePlan *UpgradePlan, opts UpgradeOptions) (*UpgradeResult, error) {
+	log := logf.Log
+
+	// Check for multiple instances of the same provider if current contract is v1alpha3.
+	if upgradePlan.Contract == clusterv1.GroupVersion.Version {
+		if err := u.providerInventory.CheckSingleProviderInstance(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Ensure Providers are updated in the following order: Core, Bootstrap, ControlPlane, Infrastructure.
+	providers := upgradePlan.Providers
+	sort.Slice(providers, func(a, b int) bool {
+		return providers[a].GetProviderType().Order() < providers[b].GetProviderType().Order()
+	})
+
+	timeout := opts.WaitProviderTimeout
+	if timeout == 0 {
+		timeout = defaultStagedUpgradeVerifyTimeout
+	}
+
+	result := newUpgradeResult(upgradePlan)
+	for i, upgradeItem := range upgradePlan.Providers {
+		// If there is not a specified next version, skip it (we are already up-to-date).
+		if upgradeItem.NextVersion == "" {
+			continue
+		}
+		providerResult := &result.Providers[i]
+
+		// Gets the provider components for the target version.
+		components, err := u.getUpgradeComponents(upgradeItem)
+		if err != nil {
+			providerResult.Error = err.Error()
+			return result, err
+		}
+
+		upgradeErr := u.upgradeProvider(upgradeItem, components)
+		if upgradeErr == nil {
+			log.Info("Verifying", "Provider", upgradeItem.InstanceName(), "Version", upgradeItem.NextVersion)
+			c, err := u.proxy.NewClient()
+			if err != nil {
+				return result, err
+			}
+			providerResult.Checks = verifyProvider(ctx, c, components.Objs(), timeout)
+			if failed := failedChecks(providerResult.Checks); len(failed) > 0 {
+				upgradeErr = errors.Errorf("verification failed: %s", strings.Join(failed, ", "))
+			}
+		}
+		if upgradeErr == nil {
+			providerResult.Status = ProviderUpgraded
+			continue
+		}
+
+		// The upgrade failed, roll back the provider to the previous version and stop the upgrade.
+		providerResult.Error = upgradeErr.Error()
+		log.Info("Upgrade failed, rolling back", "Provider", upgradeItem.InstanceName(), "Version", upgradeItem.Version, "Error", upgradeErr.Error())
+		if err := u.rollbackProvider(upgradeItem); err != nil {
+			providerResult.Status = ProviderRollbackFailed
+			providerResult.Error = fmt.Sprintf("%s; rollback failed: %v", providerResult.Error, err)
+			return result, errors.Wrapf(err, "failed to upgrade provider %s to %s and to roll it back to %s", upgradeItem.InstanceName(), upgradeItem.NextVersion, upgradeItem.Version)
+		}
+		providerResult.Status = ProviderRolledBack
+		return result, errors.Wrapf(upgradeErr, "failed to upgrade provider %s to %s, the provider has been rolled back to %s", upgradeItem.InstanceName(), upgradeItem.NextVersion, upgradeItem.Version)
+	}
+
+	// Delete webhook namespace since it's not needed from v1alpha4.
+	if upgradePlan.Contract == clusterv1.GroupVersion.Version {
+		if err := u.providerComponents.DeleteWebhookNamespace(); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// upgradeProvider upgrades a provider to the given components.
+func (u *providerUpgrader) upgradeProvider(upgradeItem UpgradeItem, components repository.Components) error {
+	c, err := u.proxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	// Migrate CRs to the storage version of the new CRDs, if necessary.
+	// Note: We have to do this before the provider is scaled down so conversion webhooks still work.
+	if err := newCRDMigrator(c).Run(ctx, components.Objs()); err != nil {
+		return err
+	}
+
+	return u.replaceProvider(upgradeItem, components)
+}
+
+// rollbackProvider restores the previous version of a provider.
+// NOTE: CRDs are rolled back as well, but CRD storedVersions can't be rolled back; if objects have already been
+// stored with a version which is not defined in the previous CRDs, e.g. because they have been migrated to a new
+// storage version, the rollback is blocked before touching the provider and it requires manual intervention.
+func (u *providerUpgrader) rollbackProvider(upgradeItem UpgradeItem) error {
+	previous := upgradeItem
+	previous.NextVersion = upgradeItem.Version
+
+	components, err := u.getUpgradeComponents(previous)
+	if err != nil {
+		return err
+	}
+
+	c, err := u.proxy.NewClient()
+	if err != nil {
+		return err
+	}
+	if err := checkCRDsCanBeRolledBack(ctx, c, components.Objs()); err != nil {
+		return err
+	}
+
+	return u.replaceProvider(upgradeItem, components)
+}
+
+// checkCRDsCanBeRolledBack checks that all the versions used to store the objects of the CRDs in the cluster are
+// defined in the CRDs of the components to roll back to, given that the API server rejects CRDs which do not
+// define all the versions in status.storedVersions.
+func checkCRDsCanBeRolledBack(ctx context.Context, c client.Client, objs []unstructured.Unstructured) error {
+	notDefined := []string{}
+	for _, obj := range objs {
+		if obj.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, client.ObjectKey{Name: obj.GetName()}, crd); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to get CustomResourceDefinition %s", obj.GetName())
+		}
+
+		defined := sets.NewString()
+		versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+		for _, version := range versions {
+			if versionMap, ok := version.(map[string]interface{}); ok {
+				name, _, _ := unstructured.NestedString(versionMap, "name")
+				defined.Insert(name)
+			}
+		}
+		for _, storedVersion := range crd.Status.StoredVersions {
+			if !defined.Has(storedVersion) {
+				notDefined = append(notDefined, fmt.Sprintf("%s stored with %s", crd.Name, storedVersion))
+			}
+		}
+	}
+
+	if len(notDefined) > 0 {
+		return errors.Errorf("rollback is not possible because objects are stored with versions not defined in the previous CRDs: %s; "+
+			"the provider requires manual intervention", strings.Join(notDefined, ", "))
+	}
+	return nil
+}
+
+// replaceProvider scales down and deletes the provider components, preserving CRDs, namespace and inventory,
+// and then installs the given components.
+func (u *providerUpgrader) replaceProvider(upgradeItem UpgradeItem, components repository.Components) error {
+	// Scale down the provider, so all the Pods of the "old" provider Deployments have been deleted before
+	// installing the new ones.
+	if err := u.scaleDownProvider(upgradeItem.Provider); err != nil {
+		return err
+	}
+
+	if err := u.providerComponents.Delete(DeleteOptions{
+		Provider:         upgradeItem.Provider,
+		IncludeNamespace: false,
+		IncludeCRDs:      false,
+		SkipInventory:    true,
+	}); err != nil {
+		return err
+	}
+
+	return installComponentsAndUpdateInventory(components, u.providerComponents, u.providerInventory)
+}
+
+// verifyProvider verifies the provider components after an upgrade, waiting up to timeout for all
+// the checks to pass; it returns the result of the last verification.
+func verifyProvider(ctx context.Context, c client.Client, objs []unstructured.Unstructured, timeout time.Duration) []UpgradeCheck {
+	var checks []UpgradeCheck
+	_ = wait.PollImmediate(verifyProviderInterval, timeout, func() (bool, error) {
+		checks = []UpgradeCheck{
+			checkDeploymentsAvailable(ctx, c, objs),
+			checkWebhooksReachable(ctx, c, objs),
+			checkStorageVersionsMigrated(ctx, c, objs),
+		}
+		return len(failedChecks(checks)) == 0, nil
+	})
+	return checks
+}
+
+// failedChecks returns the type of the checks which did not pass.
+func failedChecks(checks []UpgradeCheck) []string {
+	failed := []string{}
+	for _, check := range checks {
+		if !check.Passed {
+			failed = append(failed, fmt.Sprintf("%s (%s)", check.Type, check.Message))
+		}
+	}
+	return failed
+}
+
+// checkDeploymentsAvailable checks that all the Deployments in the provider components are available.
+func checkDeploymentsAvailable(ctx context.Context, c client.Client, objs []unstructured.Unstructured) UpgradeCheck {
+	check := UpgradeCheck{Type: DeploymentsAvailableCheck}
+
+	notAvailable := []string{}
+	for _, obj := range objs {
+		if obj.GetKind() != "Deployment" {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}, deployment); err != nil {
+			notAvailable = append(notAvailable, fmt.Sprintf("%s/%s: %v", obj.GetNamespace(), obj.GetName(), err))
+			continue
+		}
+		available := false
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == appsv1.DeploymentAvailable && condition.Status == corev1.ConditionTrue {
+				available = true
+			}
+		}
+		if !available {
+			notAvailable = append(notAvailable, fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName()))
+		}
+	}
+
+	check.Passed = len(notAvailable) == 0
+	if !check.Passed {
+		check.Message = fmt.Sprintf("Deployments not available: %s", strings.Join(notAvailable, ", "))
+	}
+	return check
+}
+
+// checkWebhooksReachable checks that the Services referenced by the webhook configurations and by the conversion
+// webhooks of the CRDs in the provider components have at least a ready endpoint.
+func checkWebhooksReachable(ctx context.Context, c client.Client, objs []unstructured.Unstructured) UpgradeCheck {
+	check := UpgradeCheck{Type: WebhooksReachableCheck}
+
+	services := sets.NewString()
+	for _, obj := range objs {
+		switch obj.GetKind() {
+		case "MutatingWebhookConfiguration", "ValidatingWebhookConfiguration":
+			webhooks, _, _ := unstructured.NestedSlice(obj.Object, "webhooks")
+			for _, webhook := range webhooks {
+				if webhookMap, ok := webhook.(map[string]interface{}); ok {
+					addWebhookService(services, webhookMap, "clientConfig", "service")
+				}
+			}
+		case "CustomResourceDefinition":
+			addWebhookService(services, obj.Object, "spec", "conversion", "webhook", "clientConfig", "service")
+		}
+	}
+
+	unreachable := []string{}
+	for _, service := range services.List() {
+		namespace, name, _ := strings.Cut(service, "/")
+		endpoints := &corev1.Endpoints{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, endpoints); err != nil {
+			unreachable = append(unreachable, fmt.Sprintf("%s: %v", service, err))
+			continue
+		}
+		ready := false
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) > 0 {
+				ready = true
+			}
+		}
+		if !ready {
+			unreachable = append(unreachable, service)
+		}
+	}
+
+	check.Passed = len(unreachable) == 0
+	if !check.Passed {
+		check.Message = fmt.Sprintf("webhook Services without ready endpoints: %s", strings.Join(unreachable, ", "))
+	}
+	return check
+}
+
+// addWebhookService adds to services the namespace/name of the webhook Service at the given path, if any.
+func addWebhookService(services sets.String, obj map[string]interface{}, fields ...string) {
+	namespace, _, _ := unstructured.NestedString(obj, append(fields, "namespace")...)
+	name, _, _ := unstructured.NestedString(obj, append(fields, "name")...)
+	if name == "" {
+		return
+	}
+	services.Insert(fmt.Sprintf("%s/%s", namespace, name))
+}
+
+// checkStorageVersionsMigrated checks that all the versions used to store the objects of the CRDs in the provider
+// components are served by the CRDs, so the objects can still be read after the upgrade.
+func checkStorageVersionsMigrated(ctx context.Context, c client.Client, objs []unstructured.Unstructured) UpgradeCheck {
+	check := UpgradeCheck{Type: StorageVersionsMigratedCheck}
+
+	notMigrated := []string{}
+	for _, obj := range objs {
+		if obj.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, client.ObjectKey{Name: obj.GetName()}, crd); err != nil {
+			notMigrated = append(notMigrated, fmt.Sprintf("%s: %v", obj.GetName(), err))
+			continue
+		}
+
+		served := sets.NewString()
+		for _, version := range crd.Spec.Versions {
+			if version.Served {
+				served.Insert(version.Name)
+			}
+		}
+		for _, storedVersion := range crd.Status.StoredVersions {
+			if !served.Has(storedVersion) {
+				notMigrated = append(notMigrated, fmt.Sprintf("%s stored with %s", crd.Name, storedVersion))
+			}
+		}
+	}
+
+	check.Passed = len(notMigrated) == 0
+	if !check.Passed {
+		check.Message = fmt.Sprintf("CRDs with objects stored with a version which is not served: %s", strings.Join(notMigrated, ", "))
+	}
+	return check
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_newUpgradeResult(t *testing.T) {
+	g := NewWithT(t)
+
+	plan := &UpgradePlan{
+		Contract: test.CurrentCAPIContract,
+		Providers: []UpgradeItem{
+			{
+				Provider:    fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system"),
+				NextVersion: "v1.0.1",
+			},
+			{
+				Provider:    fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system"),
+				NextVersion: "",
+			},
+		},
+	}
+
+	result := newUpgradeResult(plan)
+	g.Expect(result.Contract).To(Equal(test.CurrentCAPIContract))
+	g.Expect(result.Providers).To(HaveLen(2))
+	g.Expect(result.Providers[0].Provider).To(Equal("cluster-api-system/cluster-api"))
+	g.Expect(result.Providers[0].FromVersion).To(Equal("v1.0.0"))
+	g.Expect(result.Providers[0].ToVersion).To(Equal("v1.0.1"))
+	g.Expect(result.Providers[0].Status).To(Equal(ProviderNotUpgraded))
+	g.Expect(result.Providers[1].Status).To(Equal(ProviderUpToDate))
+}
+
+func Test_checkDeploymentsAvailable(t *testing.T) {
+	deploymentObj := unstructured.Unstructured{}
+	deploymentObj.SetKind("Deployment")
+	deploymentObj.SetNamespace("ns1")
+	deploymentObj.SetName("controller")
+
+	deployment := func(available corev1.ConditionStatus) client.Object {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: appsv1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "controller"},
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: available}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		objs       []client.Object
+		wantPassed bool
+	}{
+		{
+			name:       "pass if the deployment is available",
+			objs:       []client.Object{deployment(corev1.ConditionTrue)},
+			wantPassed: true,
+		},
+		{
+			name:       "fail if the deployment is not available",
+			objs:       []client.Object{deployment(corev1.ConditionFalse)},
+			wantPassed: false,
+		},
+		{
+			name:       "fail if the deployment does not exist",
+			wantPassed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, err := test.NewFakeProxy().WithObjs(tt.objs...).NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			check := checkDeploymentsAvailable(ctx, c, []unstructured.Unstructured{deploymentObj})
+			g.Expect(check.Type).To(Equal(DeploymentsAvailableCheck))
+			g.Expect(check.Passed).To(Equal(tt.wantPassed))
+		})
+	}
+}
+
+func Test_checkWebhooksReachable(t *testing.T) {
+	webhookObj := unstructured.Unstructured{Object: map[string]interface{}{
+		"webhooks": []interface{}{
+			map[string]interface{}{
+				"clientConfig": map[string]interface{}{
+					"service": map[string]interface{}{
+						"namespace": "ns1",
+						"name":      "webhook-service",
+					},
+				},
+			},
+		},
+	}}
+	webhookObj.SetKind("ValidatingWebhookConfiguration")
+	webhookObj.SetName("validating-webhook")
+
+	endpoints := func(addresses ...corev1.EndpointAddress) client.Object {
+		return &corev1.Endpoints{
+			TypeMeta:   metav1.TypeMeta{Kind: "Endpoints", APIVersion: corev1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "webhook-service"},
+			Subsets:    []corev1.EndpointSubset{{Addresses: addresses}},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		objs       []client.Object
+		wantPassed bool
+	}{
+		{
+			name:       "pass if the webhook service has ready endpoints",
+			objs:       []client.Object{endpoints(corev1.EndpointAddress{IP: "10.0.0.1"})},
+			wantPassed: true,
+		},
+		{
+			name:       "fail if the webhook service does not have ready endpoints",
+			objs:       []client.Object{endpoints()},
+			wantPassed: false,
+		},
+		{
+			name:       "fail if the webhook service endpoints do not exist",
+			wantPassed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, err := test.NewFakeProxy().WithObjs(tt.objs...).NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			check := checkWebhooksReachable(ctx, c, []unstructured.Unstructured{webhookObj})
+			g.Expect(check.Type).To(Equal(WebhooksReachableCheck))
+			g.Expect(check.Passed).To(Equal(tt.wantPassed))
+		})
+	}
+}
+
+func Test_checkStorageVersionsMigrated(t *testing.T) {
+	crdObj := unstructured.Unstructured{}
+	crdObj.SetKind("CustomResourceDefinition")
+	crdObj.SetName("foos.bar")
+
+	crd := func(storedVersions ...string) client.Object {
+		return &apiextensionsv1.CustomResourceDefinition{
+			TypeMeta:   metav1.TypeMeta{Kind: "CustomResourceDefinition", APIVersion: apiextensionsv1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Name: "foos.bar"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: false},
+					{Name: "v1beta1", Served: true, Storage: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		objs       []client.Object
+		wantPassed bool
+	}{
+		{
+			name:       "pass if all the stored versions are served",
+			objs:       []client.Object{crd("v1beta1")},
+			wantPassed: true,
+		},
+		{
+			name:       "fail if a stored version is not served",
+			objs:       []client.Object{crd("v1alpha1", "v1beta1")},
+			wantPassed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, err := test.NewFakeProxy().WithObjs(tt.objs...).NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			check := checkStorageVersionsMigrated(ctx, c, []unstructured.Unstructured{crdObj})
+			g.Expect(check.Type).To(Equal(StorageVersionsMigratedCheck))
+			g.Expect(check.Passed).To(Equal(tt.wantPassed))
+		})
+	}
+}
+
+func Test_checkCRDsCanBeRolledBack(t *testing.T) {
+	crdObj := unstructured.Unstructured{}
+	crdObj.SetKind("CustomResourceDefinition")
+	crdObj.SetName("foos.bar")
+	g := NewWithT(t)
+	g.Expect(unstructured.SetNestedSlice(crdObj.Object, []interface{}{
+		map[string]interface{}{"name": "v1alpha1"},
+		map[string]interface{}{"name": "v1beta1"},
+	}, "spec", "versions")).To(Succeed())
+
+	crd := func(storedVersions ...string) client.Object {
+		return &apiextensionsv1.CustomResourceDefinition{
+			TypeMeta:   metav1.TypeMeta{Kind: "CustomResourceDefinition", APIVersion: apiextensionsv1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Name: "foos.bar"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1beta1", Served: true},
+					{Name: "v1beta2", Served: true, Storage: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		objs    []client.Object
+		wantErr bool
+	}{
+		{
+			name:    "pass if the CRD does not exist",
+			objs:    []client.Object{},
+			wantErr: false,
+		},
+		{
+			name:    "pass if all the stored versions are defined in the previous CRD",
+			objs:    []client.Object{crd("v1beta1")},
+			wantErr: false,
+		},
+		{
+			name:    "fail if a stored version is not defined in the previous CRD",
+			objs:    []client.Object{crd("v1beta2")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, err := test.NewFakeProxy().WithObjs(tt.objs...).NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = checkCRDsCanBeRolledBack(ctx, c, []unstructured.Unstructured{crdObj})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
@@ -27,6 +27,9 @@ import (
 type WorkloadCluster interface {
 	// GetKubeconfig returns the kubeconfig of the workload cluster.
 	GetKubeconfig(workloadClusterName string, namespace string) (string, error)
+
+	// GetUserKubeconfig returns a kubeconfig of the workload cluster with a client certificate signed for the given user.
+	GetUserKubeconfig(workloadClusterName string, namespace string, user utilkubeconfig.User) (string, error)
 }
 
 // workloadCluster implements WorkloadCluster.
@@ -57,3 +60,20 @@ func (p *workloadCluster) GetKubeconfig(workloadClusterName string, namespace st
 	}
 	return string(dataBytes), nil
 }
+
+func (p *workloadCluster) GetUserKubeconfig(workloadClusterName string, namespace string, user utilkubeconfig.User) (string, error) {
+	cs, err := p.proxy.NewClient()
+	if err != nil {
+		return "", err
+	}
+
+	obj := client.ObjectKey{
+		Namespace: namespace,
+		Name:      workloadClusterName,
+	}
+	dataBytes, err := utilkubeconfig.GenerateForUser(ctx, cs, obj, user)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to generate a kubeconfig for user %q for workload cluster %s/%s", user.Name, namespace, workloadClusterName)
+	}
+	return string(dataBytes), nil
+}
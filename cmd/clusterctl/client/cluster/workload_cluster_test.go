@@ -17,14 +17,23 @@ limitations under the License.
 package cluster
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/util/certs"
+	utilkubeconfig "sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/secret"
 )
 
@@ -97,3 +106,117 @@ users:
 		})
 	}
 }
+
+func Test_WorkloadCluster_GetUserKubeconfig(t *testing.T) {
+	g := NewWithT(t)
+
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+	caCert, err := newTestCACert(caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	kubeconfigSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1-kubeconfig",
+			Namespace: "test",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "test1"},
+		},
+		Data: map[string][]byte{
+			secret.KubeconfigDataName: []byte(`
+clusters:
+- cluster:
+    certificate-authority-data: c3R1ZmY=
+    server: https://test-cluster-api:6443
+  name: test1
+contexts:
+- context:
+    cluster: test1
+    user: test1-admin
+  name: test1-admin@test1
+current-context: test1-admin@test1
+kind: Config
+users:
+- name: test1-admin
+  user:
+    client-certificate-data: c3R1ZmYtY2VydC1kYXRh
+    client-key-data: c3R1ZmYta2V5LWRhdGE=
+`),
+		},
+	}
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1-ca",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			secret.TLSKeyDataName: certs.EncodePrivateKeyPEM(caKey),
+			secret.TLSCrtDataName: certs.EncodeCertPEM(caCert),
+		},
+	}
+	user := utilkubeconfig.User{Name: "jane", Groups: []string{"developers"}, TTL: time.Hour}
+
+	tests := []struct {
+		name      string
+		expectErr bool
+		proxy     Proxy
+	}{
+		{
+			name:      "return a kubeconfig for the user",
+			expectErr: false,
+			proxy:     test.NewFakeProxy().WithObjs(kubeconfigSecret, caSecret),
+		},
+		{
+			name:      "return error if cannot find the CA secret",
+			expectErr: true,
+			proxy:     test.NewFakeProxy().WithObjs(kubeconfigSecret),
+		},
+		{
+			name:      "return error if cannot find the kubeconfig secret",
+			expectErr: true,
+			proxy:     test.NewFakeProxy().WithObjs(caSecret),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			wc := newWorkloadCluster(tt.proxy)
+			data, err := wc.GetUserKubeconfig("test1", "test", user)
+
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			config, err := clientcmd.Load([]byte(data))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(config.CurrentContext).To(Equal("jane@test1"))
+			g.Expect(config.Clusters["test1"].Server).To(Equal("https://test-cluster-api:6443"))
+
+			cert, err := certs.DecodeCertPEM(config.AuthInfos["jane"].ClientCertificateData)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cert.Subject.CommonName).To(Equal("jane"))
+			g.Expect(cert.Subject.Organization).To(ConsistOf("developers"))
+		})
+	}
+}
+
+func newTestCACert(key *rsa.PrivateKey) (*x509.Certificate, error) {
+	now := time.Now().UTC()
+	tmpl := x509.Certificate{
+		SerialNumber:          new(big.Int).SetInt64(0),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             now.Add(time.Minute * -5),
+		NotAfter:              now.Add(time.Hour * 24),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	b, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(b)
+}
@@ -145,6 +145,11 @@ type GetClusterTemplateOptions struct {
 	// It can be set through the cli flag, WORKER_MACHINE_COUNT environment variable or will default to 0
 	WorkerMachineCount *int64
 
+	// Variables are the values of the variables used when processing the template, e.g. read with ReadVariablesFiles;
+	// they override the values defined in the clusterctl configuration or in environment variables, while the values
+	// derived from the other options, e.g. ClusterName, take precedence over them.
+	Variables map[string]string
+
 	// ListVariablesOnly sets the GetClusterTemplate method to return the list of variables expected by the template
 	// without executing any further processing.
 	ListVariablesOnly bool
@@ -152,6 +157,12 @@ type GetClusterTemplateOptions struct {
 	// YamlProcessor defines the yaml processor to use for the cluster
 	// template processing. If not defined, SimpleProcessor will be used.
 	YamlProcessor Processor
+
+	// KustomizeOverlay is the path of a local directory with a kustomize overlay to be applied on top of the
+	// cluster template as part of the template processing, after the template variables are processed; the overlay
+	// does not apply to the ClusterClasses added to the template.
+	// The overlay is ignored when ListVariablesOnly is set, given that the template is not processed.
+	KustomizeOverlay string
 }
 
 // numSources return the number of template sources currently set on a GetClusterTemplateOptions.
@@ -216,6 +227,15 @@ func (c *clusterctlClient) GetClusterTemplate(options GetClusterTemplateOptions)
 		options.ProviderRepositorySource = &ProviderRepositorySourceOptions{}
 	}
 
+	// If a kustomize overlay is set, apply it as part of the template processing.
+	if options.KustomizeOverlay != "" {
+		processor := options.YamlProcessor
+		if processor == nil {
+			processor = yaml.NewSimpleProcessor()
+		}
+		options.YamlProcessor = yaml.NewKustomizeProcessor(processor, options.KustomizeOverlay)
+	}
+
 	// Gets  the client for the current management cluster
 	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{options.Kubeconfig, options.YamlProcessor})
 	if err != nil {
@@ -341,7 +361,16 @@ func (c *clusterctlClient) getTemplateFromRepository(cluster cluster.Client, opt
 		return nil, err
 	}
 
-	clusterClassClient := repo.ClusterClasses(version)
+	// ClusterClasses are added to the template without applying the kustomize overlay, if any, so the resources
+	// added by the overlay are not duplicated for each ClusterClass.
+	clusterClassRepo := repo
+	if kustomizeProcessor, ok := processor.(*yaml.KustomizeProcessor); ok {
+		clusterClassRepo, err = c.repositoryClientFactory(RepositoryClientFactoryInput{Provider: providerConfig, Processor: kustomizeProcessor.Processor})
+		if err != nil {
+			return nil, err
+		}
+	}
+	clusterClassClient := clusterClassRepo.ClusterClasses(version)
 
 	template, err = addClusterClassIfMissing(template, clusterClassClient, cluster, targetNamespace, listVariablesOnly)
 	if err != nil {
@@ -377,6 +406,12 @@ func (c *clusterctlClient) getTemplateFromURL(cluster cluster.Client, source URL
 
 // templateOptionsToVariables injects some of the templateOptions to the configClient so they can be consumed as a variables from the template.
 func (c *clusterctlClient) templateOptionsToVariables(options GetClusterTemplateOptions) error {
+	// the Variables, e.g. read from variables files, override the values from os env variables/the clusterctl config file;
+	// they are injected first, so the values derived from the other templateOptions take precedence over them.
+	for name, value := range options.Variables {
+		c.configClient.Variables().Set(name, value)
+	}
+
 	// the TargetNamespace, if valid, can be used in templates using the ${ NAMESPACE } variable.
 	if err := validateDNS1123Label(options.TargetNamespace); err != nil {
 		return errors.Wrapf(err, "invalid target-namespace")
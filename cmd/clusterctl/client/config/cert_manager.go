@@ -16,6 +16,10 @@ limitations under the License.
 
 package config
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 // CertManager defines cert-manager configuration.
 type CertManager interface {
 	// URL returns the name of the cert-manager repository.
@@ -29,13 +33,42 @@ type CertManager interface {
 	// Timeout returns the timeout for cert-manager to start.
 	// If empty, 10m will be used.
 	Timeout() string
+
+	// Namespace returns the namespace cert-manager is installed in.
+	// If empty, "cert-manager" will be used.
+	Namespace() string
+
+	// Customization returns the customizations to apply to the cert-manager components on install and upgrade.
+	Customization() CertManagerCustomization
+}
+
+// CertManagerCustomization defines customizations to apply to the cert-manager components on install and upgrade.
+type CertManagerCustomization struct {
+	// ImageRepository sets the container registry to pull the cert-manager images from, e.g. a mirror
+	// in air-gapped environments.
+	// NOTE: image overrides defined for the cert-manager component in the images configuration take precedence.
+	ImageRepository string `json:"imageRepository,omitempty"`
+
+	// Replicas sets the number of replicas of the cert-manager Deployments.
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// NodeSelector sets the node selector of the cert-manager Deployments.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations sets the tolerations of the cert-manager Deployments.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Resources sets the resource requirements of the cert-manager containers.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // certManager implements CertManager.
 type certManager struct {
-	url     string
-	version string
-	timeout string
+	url           string
+	version       string
+	timeout       string
+	namespace     string
+	customization CertManagerCustomization
 }
 
 // ensure certManager implements CertManager.
@@ -53,11 +86,27 @@ func (p *certManager) Timeout() string {
 	return p.timeout
 }
 
+func (p *certManager) Namespace() string {
+	return p.namespace
+}
+
+func (p *certManager) Customization() CertManagerCustomization {
+	return p.customization
+}
+
 // NewCertManager creates a new CertManager with the given configuration.
 func NewCertManager(url, version, timeout string) CertManager {
+	return NewCustomizedCertManager(url, version, timeout, CertManagerDefaultNamespace, CertManagerCustomization{})
+}
+
+// NewCustomizedCertManager creates a new CertManager with the given configuration, installed in the given namespace
+// and with the given customizations.
+func NewCustomizedCertManager(url, version, timeout, namespace string, customization CertManagerCustomization) CertManager {
 	return &certManager{
-		url:     url,
-		version: version,
-		timeout: timeout,
+		url:           url,
+		version:       version,
+		timeout:       timeout,
+		namespace:     namespace,
+		customization: customization,
 	}
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"time"
 
@@ -38,6 +39,9 @@ const (
 
 	// CertManagerDefaultTimeout defines the default cert-manager timeout to be used by clusterctl.
 	CertManagerDefaultTimeout = 10 * time.Minute
+
+	// CertManagerDefaultNamespace defines the default namespace cert-manager is installed in by clusterctl.
+	CertManagerDefaultNamespace = "cert-manager"
 )
 
 // CertManagerClient has methods to work with cert-manager configurations.
@@ -62,20 +66,36 @@ func newCertManagerClient(reader Reader) *certManagerClient {
 
 // configCertManager mirrors config.CertManager interface and allows serialization of the corresponding info.
 type configCertManager struct {
-	URL     string `json:"url,omitempty"`
-	Version string `json:"version,omitempty"`
-	Timeout string `json:"timeout,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Timeout   string `json:"timeout,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+
+	CertManagerCustomization `json:",inline"`
 }
 
 func (p *certManagerClient) Get() (CertManager, error) {
 	url := CertManagerDefaultURL
 	version := CertManagerDefaultVersion
 	timeout := CertManagerDefaultTimeout.String()
+	namespace := CertManagerDefaultNamespace
 
-	userCertManager := &configCertManager{}
-	if err := p.reader.UnmarshalKey(CertManagerConfigKey, &userCertManager); err != nil {
+	// NOTE: The cert-manager configuration is read as a generic map and then converted using its JSON representation,
+	// so Kubernetes types like tolerations and resource quantities are decoded the same way as in Kubernetes manifests.
+	var rawCertManager map[string]interface{}
+	if err := p.reader.UnmarshalKey(CertManagerConfigKey, &rawCertManager); err != nil {
 		return nil, errors.Wrap(err, "failed to unmarshal certManager from the clusterctl configuration file")
 	}
+	userCertManager := &configCertManager{}
+	if rawCertManager != nil {
+		data, err := json.Marshal(rawCertManager)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal certManager from the clusterctl configuration file")
+		}
+		if err := json.Unmarshal(data, userCertManager); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal certManager from the clusterctl configuration file")
+		}
+	}
 	if userCertManager.URL != "" {
 		url = userCertManager.URL
 	}
@@ -91,6 +111,9 @@ func (p *certManagerClient) Get() (CertManager, error) {
 	if userCertManager.Timeout != "" {
 		timeout = userCertManager.Timeout
 	}
+	if userCertManager.Namespace != "" {
+		namespace = userCertManager.Namespace
+	}
 
-	return NewCertManager(url, version, timeout), nil
+	return NewCustomizedCertManager(url, version, timeout, namespace, userCertManager.CertManagerCustomization), nil
 }
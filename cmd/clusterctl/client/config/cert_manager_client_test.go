@@ -21,6 +21,9 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
 )
@@ -71,6 +74,56 @@ func TestCertManagerGet(t *testing.T) {
 			want:    NewCertManager(CertManagerDefaultURL, CertManagerDefaultVersion, "5m"),
 			wantErr: false,
 		},
+		{
+			name: "return namespace and customizations if defined",
+			fields: fields{
+				reader: test.NewFakeReader().WithVar(CertManagerConfigKey, `
+namespace: custom-cert-manager
+imageRepository: my-registry.io/jetstack
+replicas: 2
+nodeSelector:
+  node-role.kubernetes.io/infra: ""
+tolerations:
+- key: node-role.kubernetes.io/infra
+  operator: Exists
+  effect: NoSchedule
+resources:
+  requests:
+    cpu: 100m
+    memory: 128Mi
+`),
+			},
+			want: NewCustomizedCertManager(CertManagerDefaultURL, CertManagerDefaultVersion, CertManagerDefaultTimeout.String(), "custom-cert-manager", CertManagerCustomization{
+				ImageRepository: "my-registry.io/jetstack",
+				Replicas:        pointer.Int32(2),
+				NodeSelector:    map[string]string{"node-role.kubernetes.io/infra": ""},
+				Tolerations: []corev1.Toleration{
+					{
+						Key:      "node-role.kubernetes.io/infra",
+						Operator: corev1.TolerationOpExists,
+						Effect:   corev1.TaintEffectNoSchedule,
+					},
+				},
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("100m"),
+						corev1.ResourceMemory: resource.MustParse("128Mi"),
+					},
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "return error if customizations are invalid",
+			fields: fields{
+				reader: test.NewFakeReader().WithVar(CertManagerConfigKey, `
+resources:
+  requests:
+    cpu: not-a-quantity
+`),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
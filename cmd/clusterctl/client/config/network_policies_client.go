@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	// NetworkPoliciesConfigKey defines the name of the top level config key for NetworkPolicies configuration.
+	NetworkPoliciesConfigKey = "network-policies"
+
+	// NetworkPoliciesDefaultMetricsPort defines the default port used by the provider controllers to expose metrics.
+	NetworkPoliciesDefaultMetricsPort = 8080
+)
+
+// NetworkPolicies defines the configuration of the NetworkPolicies deployed by clusterctl in the provider namespaces.
+type NetworkPolicies struct {
+	// Enabled instructs clusterctl to deploy a default-deny ingress NetworkPolicy in each provider namespace, together
+	// with the NetworkPolicies allowing webhook traffic, health probes and metrics scraping.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MetricsPort is the port used by the provider controllers to expose metrics.
+	// If empty, 8080 will be used.
+	MetricsPort int32 `json:"metricsPort,omitempty"`
+
+	// MetricsNamespaceSelector selects the namespaces allowed to scrape metrics, e.g. the namespace of Prometheus.
+	// If empty, metrics can be scraped from all the namespaces.
+	MetricsNamespaceSelector map[string]string `json:"metricsNamespaceSelector,omitempty"`
+}
+
+// NetworkPoliciesClient has methods to work with NetworkPolicies configurations.
+type NetworkPoliciesClient interface {
+	// Get returns the NetworkPolicies configuration.
+	Get() (*NetworkPolicies, error)
+}
+
+// networkPoliciesClient implements NetworkPoliciesClient.
+type networkPoliciesClient struct {
+	reader Reader
+}
+
+// ensure networkPoliciesClient implements NetworkPoliciesClient.
+var _ NetworkPoliciesClient = &networkPoliciesClient{}
+
+func newNetworkPoliciesClient(reader Reader) *networkPoliciesClient {
+	return &networkPoliciesClient{
+		reader: reader,
+	}
+}
+
+func (p *networkPoliciesClient) Get() (*NetworkPolicies, error) {
+	networkPolicies := &NetworkPolicies{}
+	if err := p.reader.UnmarshalKey(NetworkPoliciesConfigKey, &networkPolicies); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal network-policies from the clusterctl configuration file")
+	}
+	if networkPolicies.MetricsPort == 0 {
+		networkPolicies.MetricsPort = NetworkPoliciesDefaultMetricsPort
+	}
+	return networkPolicies, nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func TestNetworkPoliciesGet(t *testing.T) {
+	tests := []struct {
+		name   string
+		reader Reader
+		want   *NetworkPolicies
+	}{
+		{
+			name:   "return disabled NetworkPolicies if no custom config is provided",
+			reader: test.NewFakeReader(),
+			want: &NetworkPolicies{
+				MetricsPort: NetworkPoliciesDefaultMetricsPort,
+			},
+		},
+		{
+			name: "return custom config if defined",
+			reader: test.NewFakeReader().WithVar(NetworkPoliciesConfigKey, `
+enabled: true
+metricsPort: 8443
+metricsNamespaceSelector:
+  name: monitoring
+`),
+			want: &NetworkPolicies{
+				Enabled:                  true,
+				MetricsPort:              8443,
+				MetricsNamespaceSelector: map[string]string{"name": "monitoring"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			p := &networkPoliciesClient{
+				reader: tt.reader,
+			}
+			got, err := p.Get()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// SOPSBinaryEnvVar is the name of the environment variable which can be used to set the sops binary used for
+// decrypting SOPS encrypted clusterctl config files and variables files; if not set, sops is looked up in the PATH.
+const SOPSBinaryEnvVar = "CLUSTERCTL_SOPS_BINARY"
+
+// sopsMetadata is the metadata added by SOPS to the files it encrypts.
+type sopsMetadata struct {
+	Sops *struct {
+		MAC string `json:"mac"`
+	} `json:"sops"`
+}
+
+// IsSOPSEncrypted returns true if the given YAML or JSON content has been encrypted with SOPS.
+func IsSOPSEncrypted(data []byte) bool {
+	metadata := &sopsMetadata{}
+	if err := yaml.Unmarshal(data, metadata); err != nil {
+		return false
+	}
+	return metadata.Sops != nil && metadata.Sops.MAC != ""
+}
+
+// DecryptSOPSFile decrypts a SOPS encrypted file using the sops binary; the keys for decrypting the file, e.g. age keys
+// or cloud KMS credentials, are read by sops as usual, e.g. from the SOPS_AGE_KEY_FILE environment variable.
+func DecryptSOPSFile(path string) ([]byte, error) {
+	sops := "sops"
+	if binary, ok := os.LookupEnv(SOPSBinaryEnvVar); ok && binary != "" {
+		sops = binary
+	}
+	if _, err := exec.LookPath(sops); err != nil {
+		return nil, errors.Errorf("the sops binary is required for decrypting the SOPS encrypted file %s; install sops or set %s", path, SOPSBinaryEnvVar)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(sops, "--decrypt", path) //nolint:gosec // No security issue: the sops binary is set by the user.
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt the SOPS encrypted file %s: %s", path, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const sopsEncryptedConfig = `LICENSE_KEY: ENC[AES256_GCM,data:c2VjcmV0,iv:aXY=,tag:dGFn,type:str]
+sops:
+  age:
+  - recipient: age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+  lastmodified: "2023-05-02T10:00:00Z"
+  mac: ENC[AES256_GCM,data:bWFj,iv:aXY=,tag:dGFn,type:str]
+  version: 3.7.3
+`
+
+// fakeSOPS configures a fake sops binary, printing the given content when decrypting a file.
+func fakeSOPS(t *testing.T, decrypted string) {
+	t.Helper()
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "decrypted.yaml"), []byte(decrypted), 0600)).To(Succeed())
+	sops := filepath.Join(dir, "sops")
+	g.Expect(os.WriteFile(sops, []byte("#!/bin/sh\ncat "+filepath.Join(dir, "decrypted.yaml")+"\n"), 0700)).To(Succeed()) //nolint:gosec
+	t.Setenv(SOPSBinaryEnvVar, sops)
+}
+
+func TestIsSOPSEncrypted(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{
+			name: "SOPS encrypted YAML",
+			data: sopsEncryptedConfig,
+			want: true,
+		},
+		{
+			name: "SOPS encrypted JSON",
+			data: `{"LICENSE_KEY": "ENC[AES256_GCM,data:c2VjcmV0,type:str]", "sops": {"mac": "ENC[AES256_GCM,data:bWFj,type:str]"}}`,
+			want: true,
+		},
+		{
+			name: "plain YAML",
+			data: "LICENSE_KEY: secret\n",
+			want: false,
+		},
+		{
+			name: "plain YAML with a sops key",
+			data: "sops:\n  version: 3.7.3\n",
+			want: false,
+		},
+		{
+			name: "invalid YAML",
+			data: "bad-contents",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(IsSOPSEncrypted([]byte(tt.data))).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_viperReader_InitWithSOPSEncryptedConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "clusterctl.yaml")
+	g.Expect(os.WriteFile(configFile, []byte(sopsEncryptedConfig), 0600)).To(Succeed())
+
+	t.Run("reads the decrypted config", func(t *testing.T) {
+		gs := NewWithT(t)
+		fakeSOPS(t, "LICENSE_KEY: secret\n")
+
+		v := newViperReader(injectConfigPaths([]string{dir}))
+		gs.Expect(v.Init(configFile)).To(Succeed())
+
+		got, err := v.Get("LICENSE_KEY")
+		gs.Expect(err).NotTo(HaveOccurred())
+		gs.Expect(got).To(Equal("secret"))
+		_, err = v.Get("sops")
+		gs.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("fails if the sops binary is not available", func(t *testing.T) {
+		gs := NewWithT(t)
+		t.Setenv(SOPSBinaryEnvVar, filepath.Join(dir, "do-not-exist"))
+
+		v := newViperReader(injectConfigPaths([]string{dir}))
+		err := v.Init(configFile)
+		gs.Expect(err).To(HaveOccurred())
+		gs.Expect(err.Error()).To(ContainSubstring(SOPSBinaryEnvVar))
+	})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -129,10 +130,36 @@ func (v *viperReader) Init(path string) error {
 	if err := viper.ReadInConfig(); err != nil {
 		return err
 	}
+	if err := v.decryptConfig(); err != nil {
+		return err
+	}
 	log.V(5).Info("Using configuration", "File", viper.ConfigFileUsed())
 	return nil
 }
 
+// decryptConfig replaces the configuration read by viper with the decrypted one, if the config file
+// has been encrypted with SOPS, so secrets are not required to be stored in plaintext.
+func (v *viperReader) decryptConfig() error {
+	configFile := viper.ConfigFileUsed()
+	data, err := os.ReadFile(configFile) //nolint:gosec // No security issue: configFile is the file already read by viper.
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the clusterctl config file %s", configFile)
+	}
+	if !IsSOPSEncrypted(data) {
+		return nil
+	}
+
+	decrypted, err := DecryptSOPSFile(configFile)
+	if err != nil {
+		return err
+	}
+	if err := viper.ReadConfig(bytes.NewReader(decrypted)); err != nil {
+		return errors.Wrapf(err, "failed to read the decrypted clusterctl config file %s", configFile)
+	}
+	logf.Log.V(5).Info("Decrypted SOPS encrypted configuration", "File", configFile)
+	return nil
+}
+
 func downloadFile(url string, filepath string) error {
 	ctx := context.TODO()
 
@@ -19,6 +19,10 @@ package config
 const (
 	// GitHubTokenVariable defines a variable hosting the GitHub access token.
 	GitHubTokenVariable = "github-token"
+
+	// RepositoryCredentialsVariable defines a variable hosting the path of the file with the credentials
+	// for authenticated HTTPS and OCI provider repositories.
+	RepositoryCredentialsVariable = "repository-credentials"
 )
 
 // VariablesClient has methods to work with environment variables and with variables defined in the clusterctl configuration file.
@@ -316,6 +316,30 @@ func Test_clusterctlClient_templateOptionsToVariables(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "pass (using Variables, overriding env variables but not other template options)",
+			args: args{
+				options: GetClusterTemplateOptions{
+					ClusterName:     "foo",
+					TargetNamespace: "bar",
+					Variables: map[string]string{
+						"CLUSTER_NAME":         "baz",
+						"KUBERNETES_VERSION":   "v1.2.3",
+						"WORKER_MACHINE_COUNT": "3",
+						"AWS_REGION":           "eu-west-1",
+					},
+				},
+			},
+			wantVars: map[string]string{
+				"CLUSTER_NAME":                "foo",
+				"NAMESPACE":                   "bar",
+				"KUBERNETES_VERSION":          "v1.2.3",
+				"CONTROL_PLANE_MACHINE_COUNT": "1",
+				"WORKER_MACHINE_COUNT":        "3",
+				"AWS_REGION":                  "eu-west-1",
+			},
+			wantErr: false,
+		},
 		{
 			name: "pass (using defaults for machine counts)",
 			args: args{
@@ -681,6 +705,93 @@ func Test_clusterctlClient_GetClusterTemplate_withClusterClass(t *testing.T) {
 	g.Expect(got.TargetNamespace()).To(Equal("ns1"))
 	g.Expect(got.Objs()).To(ContainElement(MatchClusterClass("dev", "ns1")))
 }
+
+func Test_clusterctlClient_GetClusterTemplate_withKustomizeOverlay(t *testing.T) {
+	g := NewWithT(t)
+
+	rawTemplate := templateYAML("ns3", "${ CLUSTER_NAME }")
+	config1 := newFakeConfig().WithProvider(infraProviderConfig)
+
+	repository1 := newFakeRepository(infraProviderConfig, config1).
+		WithPaths("root", "components").
+		WithDefaultVersion("v3.0.0").
+		WithFile("v3.0.0", "cluster-template.yaml", rawTemplate)
+
+	cluster1 := newFakeCluster(cluster.Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}, config1).
+		WithProviderInventory(infraProviderConfig.Name(), infraProviderConfig.Type(), "v3.0.0", "foo").
+		WithObjs(test.FakeCAPISetupObjects()...)
+
+	client := newFakeClient(config1).
+		WithCluster(cluster1).
+		WithRepository(repository1)
+
+	overlayDir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(overlayDir, "kustomization.yaml"), []byte("commonLabels:\n  env: prod\n"), 0600)).To(Succeed())
+
+	got, err := client.GetClusterTemplate(GetClusterTemplateOptions{
+		Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+		ProviderRepositorySource: &ProviderRepositorySourceOptions{
+			InfrastructureProvider: "infra:v3.0.0",
+		},
+		ClusterName:              "test",
+		TargetNamespace:          "ns1",
+		ControlPlaneMachineCount: pointer.Int64(1),
+		KustomizeOverlay:         overlayDir,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.Variables()).To(Equal([]string{"CLUSTER_NAME"}))
+	g.Expect(got.TargetNamespace()).To(Equal("ns1"))
+	g.Expect(got.Objs()).To(HaveLen(1))
+	g.Expect(got.Objs()[0].GetName()).To(Equal("test"))
+	g.Expect(got.Objs()[0].GetNamespace()).To(Equal("ns1"))
+	g.Expect(got.Objs()[0].GetLabels()).To(Equal(map[string]string{"env": "prod"}))
+}
+
+func Test_clusterctlClient_GetClusterTemplate_withClusterClassAndKustomizeOverlay(t *testing.T) {
+	g := NewWithT(t)
+
+	rawTemplate := mangedTopologyTemplateYAML("ns4", "${CLUSTER_NAME}", "dev")
+	rawClusterClassTemplate := clusterClassYAML("ns4", "dev")
+	config1 := newFakeConfig().WithProvider(infraProviderConfig)
+
+	repository1 := newFakeRepository(infraProviderConfig, config1).
+		WithPaths("root", "components").
+		WithDefaultVersion("v3.0.0").
+		WithFile("v3.0.0", "cluster-template-dev.yaml", rawTemplate).
+		WithFile("v3.0.0", "clusterclass-dev.yaml", rawClusterClassTemplate)
+
+	cluster1 := newFakeCluster(cluster.Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}, config1).
+		WithProviderInventory(infraProviderConfig.Name(), infraProviderConfig.Type(), "v3.0.0", "ns4").
+		WithObjs(test.FakeCAPISetupObjects()...)
+
+	client := newFakeClient(config1).
+		WithCluster(cluster1).
+		WithRepository(repository1)
+
+	overlayDir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(overlayDir, "kustomization.yaml"), []byte("commonLabels:\n  env: prod\n"), 0600)).To(Succeed())
+
+	got, err := client.GetClusterTemplate(GetClusterTemplateOptions{
+		Kubeconfig:      Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+		ClusterName:     "test",
+		TargetNamespace: "ns1",
+		ProviderRepositorySource: &ProviderRepositorySourceOptions{
+			Flavor: "dev",
+		},
+		KustomizeOverlay: overlayDir,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got.Objs()).To(HaveLen(2))
+	// The overlay applies to the cluster template, but not to the ClusterClasses added to it.
+	for _, obj := range got.Objs() {
+		if obj.GetKind() == "ClusterClass" {
+			g.Expect(obj.GetLabels()).ToNot(HaveKey("env"))
+			continue
+		}
+		g.Expect(obj.GetLabels()).To(HaveKeyWithValue("env", "prod"))
+	}
+}
+
 func Test_clusterctlClient_GetClusterTemplate_onEmptyCluster(t *testing.T) {
 	g := NewWithT(t)
 
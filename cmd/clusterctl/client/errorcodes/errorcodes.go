@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errorcodes defines the stable codes used by clusterctl to classify errors, together with
+// troubleshooting hints for each of them.
+package errorcodes
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Code is a stable identifier for a class of clusterctl errors.
+// NOTE: Codes are part of the clusterctl user interface, e.g. they can be used in automation; existing
+// codes must not be changed or removed.
+type Code string
+
+const (
+	// ManagementClusterUnreachable is used when clusterctl can't connect to the management cluster.
+	ManagementClusterUnreachable Code = "ManagementClusterUnreachable"
+
+	// ContractMismatch is used when the Cluster API contract of the management cluster or of a provider
+	// is not supported by the current version of clusterctl.
+	ContractMismatch Code = "ContractMismatch"
+
+	// ProviderRepositoryUnavailable is used when clusterctl can't read from a provider repository.
+	ProviderRepositoryUnavailable Code = "ProviderRepositoryUnavailable"
+
+	// ProviderRepositoryRateLimited is used when the API rate limit of a provider repository is exceeded.
+	ProviderRepositoryRateLimited Code = "ProviderRepositoryRateLimited"
+
+	// WebhookCertNotReady is used when the cert-manager webhook is not able to serve requests, usually
+	// because its certificates are not yet issued or injected.
+	WebhookCertNotReady Code = "WebhookCertNotReady"
+
+	// MissingVariables is used when the values for the variables of a template or of provider components are not set.
+	MissingVariables Code = "MissingVariables"
+)
+
+// Explanation provides troubleshooting hints for a Code.
+type Explanation struct {
+	// Code of the error.
+	Code Code
+
+	// Summary is a short description of the error.
+	Summary string
+
+	// Remediation are the steps that can be taken to fix the error.
+	Remediation []string
+}
+
+var explanations = map[Code]Explanation{
+	ManagementClusterUnreachable: {
+		Summary: "clusterctl can't connect to the management cluster.",
+		Remediation: []string{
+			"Check that the kubeconfig file and the context passed with --kubeconfig and --kubeconfig-context, or the default ones, point to the management cluster.",
+			"Check that the API server of the management cluster is running and reachable from the machine running clusterctl, e.g. with 'kubectl cluster-info'.",
+			"Check that the credentials in the kubeconfig file are not expired.",
+		},
+	},
+	ContractMismatch: {
+		Summary: "The Cluster API contract of the management cluster or of a provider is not supported by this version of clusterctl.",
+		Remediation: []string{
+			"Check the Cluster API version installed in the management cluster with 'clusterctl upgrade plan'.",
+			"Use the version of clusterctl matching the Cluster API contract of the management cluster, or upgrade the management cluster with 'clusterctl upgrade apply'.",
+			"Check that the versions of the providers support the same Cluster API contract as the core provider.",
+		},
+	},
+	ProviderRepositoryUnavailable: {
+		Summary: "clusterctl can't read the provider components, metadata or templates from a provider repository.",
+		Remediation: []string{
+			"Check the network connectivity to the provider repository, including proxy settings.",
+			"Check the repository URL of the provider with 'clusterctl config repositories'.",
+			"Check that the requested provider version is published in the repository, including the metadata.yaml file.",
+			"Use a local repository or an overrides layer if the repository can't be reached, e.g. in air-gapped environments.",
+		},
+	},
+	ProviderRepositoryRateLimited: {
+		Summary: "The API rate limit of a provider repository has been reached.",
+		Remediation: []string{
+			"Wait for the rate limit to be reset, usually one hour.",
+			"Set a personal API token in the GITHUB_TOKEN environment variable to get a higher rate limit.",
+		},
+	},
+	WebhookCertNotReady: {
+		Summary: "The cert-manager webhook is not able to serve requests, usually because its certificates are not yet issued or injected.",
+		Remediation: []string{
+			"Check that the cert-manager Deployments in the cert-manager namespace are available.",
+			"Check the logs of the cert-manager cainjector and webhook Pods for errors.",
+			"Increase the cert-manager timeout with the cert-manager.timeout field in the clusterctl configuration file if the management cluster is slow to start cert-manager.",
+		},
+	},
+	MissingVariables: {
+		Summary: "The values for some of the variables of a template or of provider components are not set.",
+		Remediation: []string{
+			"Use 'clusterctl generate cluster --list-variables' to get the list of variables required by a cluster template.",
+			"Set the missing variables as environment variables, in the clusterctl configuration file or in a file passed with --var-file.",
+		},
+	},
+}
+
+func init() {
+	for code, explanation := range explanations {
+		explanation.Code = code
+		explanations[code] = explanation
+	}
+}
+
+// Explain returns the troubleshooting hints for a Code, if the code is known.
+func Explain(code Code) (Explanation, bool) {
+	explanation, ok := explanations[code]
+	return explanation, ok
+}
+
+// All returns the troubleshooting hints for all the known codes, sorted by code.
+func All() []Explanation {
+	all := make([]Explanation, 0, len(explanations))
+	for _, explanation := range explanations {
+		all = append(all, explanation)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Code < all[j].Code
+	})
+	return all
+}
+
+// coder is implemented by errors carrying a Code.
+type coder interface {
+	ErrorCode() Code
+}
+
+// Error is an error with a Code.
+type Error struct {
+	code Code
+	err  error
+}
+
+// Error returns the message of the underlying error.
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// ErrorCode returns the Code of the error.
+func (e *Error) ErrorCode() Code {
+	return e.code
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Wrap returns an error with the given Code wrapping err; it returns nil if err is nil.
+// NOTE: If err already has a Code, err is returned unchanged, so the most specific Code,
+// i.e. the one closest to the root cause, is preserved.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := CodeOf(err); ok {
+		return err
+	}
+	return &Error{code: code, err: err}
+}
+
+// CodeOf returns the Code of the first error with a Code in the chain of err, if any.
+func CodeOf(err error) (Code, bool) {
+	var c coder
+	if errors.As(err, &c) {
+		return c.ErrorCode(), true
+	}
+	return "", false
+}
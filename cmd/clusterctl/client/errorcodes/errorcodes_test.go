@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errorcodes
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestWrap(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Wrap(ContractMismatch, nil)).To(BeNil())
+
+	_, ok := CodeOf(errors.New("foo"))
+	g.Expect(ok).To(BeFalse())
+
+	root := errors.New("root cause")
+	err := errors.Wrap(Wrap(ContractMismatch, root), "failed to upgrade")
+	g.Expect(err.Error()).To(Equal("failed to upgrade: root cause"))
+	g.Expect(errors.Is(err, root)).To(BeTrue())
+
+	code, ok := CodeOf(err)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(code).To(Equal(ContractMismatch))
+
+	// The most specific code is preserved.
+	code, ok = CodeOf(Wrap(ProviderRepositoryUnavailable, Wrap(ProviderRepositoryRateLimited, root)))
+	g.Expect(ok).To(BeTrue())
+	g.Expect(code).To(Equal(ProviderRepositoryRateLimited))
+}
+
+func TestExplain(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, explanation := range All() {
+		got, ok := Explain(explanation.Code)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(got.Code).To(Equal(explanation.Code))
+		g.Expect(got.Summary).ToNot(BeEmpty())
+		g.Expect(got.Remediation).ToNot(BeEmpty())
+	}
+
+	_, ok := Explain("Unknown")
+	g.Expect(ok).To(BeFalse())
+}
@@ -22,6 +22,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/errorcodes"
 )
 
 func (c *clusterctlClient) GenerateProvider(provider string, providerType clusterctlv1.ProviderType, options ComponentsOptions) (Components, error) {
@@ -60,7 +61,7 @@ func (c *clusterctlClient) GenerateProvider(provider string, providerType cluste
 	}
 
 	if releaseSeries.Contract != clusterv1.GroupVersion.Version {
-		return nil, errors.Errorf("current version of clusterctl is only compatible with %s providers, detected %s for provider %s", clusterv1.GroupVersion.Version, releaseSeries.Contract, providerName)
+		return nil, errorcodes.Wrap(errorcodes.ContractMismatch, errors.Errorf("current version of clusterctl is only compatible with %s providers, detected %s for provider %s", clusterv1.GroupVersion.Version, releaseSeries.Contract, providerName))
 	}
 
 	return c.GetProviderComponents(provider, providerType, options)
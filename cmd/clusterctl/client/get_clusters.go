@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+)
+
+// GetClustersOptions carries the options supported by GetClusters.
+type GetClustersOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the workload clusters are located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// AllNamespaces instructs GetClusters to list workload clusters in all the namespaces, ignoring Namespace.
+	AllNamespaces bool
+}
+
+// GetClusters returns a summary of the status of the Cluster API clusters existing in a management cluster.
+func (c *clusterctlClient) GetClusters(options GetClustersOptions) ([]tree.ClusterSummary, error) {
+	// gets access to the management cluster
+	cluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract.
+	if err := cluster.ProviderInventory().CheckCAPIContract(); err != nil {
+		return nil, err
+	}
+
+	// If listing clusters in all the namespaces, ignore the Namespace option; otherwise,
+	// if the option specifying the Namespace is empty, try to detect it.
+	if options.AllNamespaces {
+		options.Namespace = ""
+	} else if options.Namespace == "" {
+		currentNamespace, err := cluster.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	// Fetch the Cluster client.
+	client, err := cluster.Proxy().NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.DiscoverClusters(context.TODO(), client, options.Namespace)
+}
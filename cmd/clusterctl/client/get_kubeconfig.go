@@ -17,7 +17,11 @@ limitations under the License.
 package client
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
+
+	utilkubeconfig "sigs.k8s.io/cluster-api/util/kubeconfig"
 )
 
 // GetKubeconfigOptions carries all the options supported by GetKubeconfig.
@@ -31,6 +35,17 @@ type GetKubeconfigOptions struct {
 
 	// WorkloadClusterName is the name of the workload cluster.
 	WorkloadClusterName string
+
+	// User is the name of the user to generate a kubeconfig for. If empty, the admin kubeconfig
+	// of the workload cluster is returned; otherwise, a client certificate for the user is signed
+	// with the workload cluster CA, and permissions are granted by the RBAC rules of the workload cluster.
+	User string
+
+	// Groups are the groups the User belongs to.
+	Groups []string
+
+	// TTL is the lifespan of the client certificate generated for the User.
+	TTL time.Duration
 }
 
 func (c *clusterctlClient) GetKubeconfig(options GetKubeconfigOptions) (string, error) {
@@ -56,5 +71,19 @@ func (c *clusterctlClient) GetKubeconfig(options GetKubeconfigOptions) (string,
 		options.Namespace = currentNamespace
 	}
 
-	return clusterClient.WorkloadCluster().GetKubeconfig(options.WorkloadClusterName, options.Namespace)
+	if options.User == "" {
+		if len(options.Groups) > 0 || options.TTL != 0 {
+			return "", errors.New("groups and TTL can be set only when generating a kubeconfig for a user")
+		}
+		return clusterClient.WorkloadCluster().GetKubeconfig(options.WorkloadClusterName, options.Namespace)
+	}
+
+	if options.TTL < 0 {
+		return "", errors.New("TTL cannot be negative")
+	}
+	return clusterClient.WorkloadCluster().GetUserKubeconfig(options.WorkloadClusterName, options.Namespace, utilkubeconfig.User{
+		Name:   options.User,
+		Groups: options.Groups,
+		TTL:    options.TTL,
+	})
 }
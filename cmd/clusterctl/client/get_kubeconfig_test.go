@@ -18,6 +18,7 @@ package client
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -34,6 +35,11 @@ func Test_clusterctlClient_GetKubeconfig(t *testing.T) {
 	clusterClient.fakeProxy = test.NewFakeProxy().WithNamespace("").WithFakeCAPISetup()
 	badClient := newFakeClient(configClient).WithCluster(clusterClient)
 
+	// create a clusterctl client where the proxy returns a valid namespace
+	goodClusterClient := newFakeCluster(cluster.Kubeconfig{Path: "cluster2"}, configClient)
+	goodClusterClient.fakeProxy = test.NewFakeProxy().WithFakeCAPISetup()
+	goodClient := newFakeClient(configClient).WithCluster(goodClusterClient)
+
 	tests := []struct {
 		name      string
 		client    *fakeClient
@@ -51,6 +57,18 @@ func Test_clusterctlClient_GetKubeconfig(t *testing.T) {
 			options:   GetKubeconfigOptions{Kubeconfig: Kubeconfig(kubeconfig)},
 			expectErr: true,
 		},
+		{
+			name:      "returns error if groups are set without a user",
+			client:    goodClient,
+			options:   GetKubeconfigOptions{Kubeconfig: Kubeconfig{Path: "cluster2"}, WorkloadClusterName: "test1", Groups: []string{"developers"}},
+			expectErr: true,
+		},
+		{
+			name:      "returns error if TTL is negative",
+			client:    goodClient,
+			options:   GetKubeconfigOptions{Kubeconfig: Kubeconfig{Path: "cluster2"}, WorkloadClusterName: "test1", User: "jane", TTL: -time.Hour},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
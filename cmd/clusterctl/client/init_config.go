@@ -0,0 +1,350 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/yaml"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// ProvidersConfig is the desired state of the providers installed in a management cluster.
+type ProvidersConfig struct {
+	// Providers is the list of providers that should be installed in the management cluster.
+	Providers []ProvidersConfigProvider `json:"providers"`
+
+	// Variables are the values of the variables used when processing the provider components, in addition
+	// to the ones defined in the clusterctl configuration or in environment variables.
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// ProvidersConfigProvider is a provider that should be installed in the management cluster.
+type ProvidersConfigProvider struct {
+	// Name of the provider, e.g. aws.
+	Name string `json:"name"`
+
+	// Type of the provider, e.g. InfrastructureProvider.
+	Type clusterctlv1.ProviderType `json:"type"`
+
+	// Version of the provider, e.g. v2.0.0. If empty, the latest release is installed; providers already
+	// installed are left unchanged.
+	Version string `json:"version,omitempty"`
+
+	// Namespace where the provider should be installed. If empty, the provider components' default namespace is used.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ReadProvidersConfig reads a ProvidersConfig from a file.
+func ReadProvidersConfig(path string) (*ProvidersConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read providers config %q", path)
+	}
+	providersConfig := &ProvidersConfig{}
+	if err := yaml.UnmarshalStrict(data, providersConfig); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse providers config %q", path)
+	}
+	return providersConfig, nil
+}
+
+// ProviderChangeAction is the action required to reconcile a provider with the desired state.
+type ProviderChangeAction string
+
+const (
+	// ProviderInstall means the provider is not installed and it is going to be installed.
+	ProviderInstall ProviderChangeAction = "Install"
+
+	// ProviderUpgrade means the provider is installed with a different version and it is going to be upgraded.
+	ProviderUpgrade ProviderChangeAction = "Upgrade"
+
+	// ProviderUnchanged means the provider is already installed as desired.
+	ProviderUnchanged ProviderChangeAction = "Unchanged"
+
+	// ProviderNotInConfig means the provider is installed but it is not listed in the providers config;
+	// such providers are reported but never deleted.
+	ProviderNotInConfig ProviderChangeAction = "NotInConfig"
+)
+
+// ProviderChange describes the change required to reconcile a provider with the desired state.
+type ProviderChange struct {
+	Name           string
+	Type           clusterctlv1.ProviderType
+	Namespace      string
+	CurrentVersion string
+	DesiredVersion string
+	Action         ProviderChangeAction
+}
+
+// ApplyProvidersConfigOptions carries the options supported by ApplyProvidersConfig.
+type ApplyProvidersConfigOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// ProvidersConfig is the desired state of the providers.
+	ProvidersConfig ProvidersConfig
+
+	// DryRun, if true, only computes the changes without applying them.
+	DryRun bool
+
+	// WaitProviders instructs the command to wait till the providers are successfully installed or upgraded.
+	WaitProviders bool
+
+	// WaitProviderTimeout sets the timeout per provider installation or upgrade.
+	WaitProviderTimeout time.Duration
+
+	// IgnoreValidationErrors allows for skipping the validation of provider installs.
+	IgnoreValidationErrors bool
+}
+
+// ApplyProvidersConfig computes the difference between the providers installed in the management cluster and
+// the desired state defined in a providers config, and applies only the required changes, i.e. it installs the
+// missing providers and upgrades the providers with a different version.
+// Providers installed but not listed in the providers config are reported but never deleted.
+func (c *clusterctlClient) ApplyProvidersConfig(options ApplyProvidersConfigOptions) ([]ProviderChange, error) {
+	log := logf.Log
+
+	if err := validateProvidersConfig(options.ProvidersConfig); err != nil {
+		return nil, err
+	}
+
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the providers installed in the management cluster; if the inventory does not exist yet,
+	// no providers are installed.
+	installed := &clusterctlv1.ProviderList{}
+	if err := clusterClient.Proxy().CheckClusterAvailable(); err != nil {
+		return nil, err
+	}
+	if capiInstalled, err := clusterClient.ProviderInventory().CheckCAPIInstalled(); err != nil {
+		return nil, err
+	} else if capiInstalled {
+		if installed, err = clusterClient.ProviderInventory().List(); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := computeProviderChanges(options.ProvidersConfig, installed)
+	if err != nil {
+		return nil, err
+	}
+	if options.DryRun {
+		return changes, nil
+	}
+
+	for k, v := range options.ProvidersConfig.Variables {
+		c.configClient.Variables().Set(k, v)
+	}
+
+	// Install the missing providers; providers are installed by namespace, starting with the namespace
+	// of the core provider, if any.
+	installs := map[string]*InitOptions{}
+	var namespaces []string
+	for _, change := range changes {
+		if change.Action != ProviderInstall {
+			continue
+		}
+		initOptions, ok := installs[change.Namespace]
+		if !ok {
+			initOptions = &InitOptions{
+				Kubeconfig:             options.Kubeconfig,
+				TargetNamespace:        change.Namespace,
+				WaitProviders:          options.WaitProviders,
+				WaitProviderTimeout:    options.WaitProviderTimeout,
+				IgnoreValidationErrors: options.IgnoreValidationErrors,
+			}
+			installs[change.Namespace] = initOptions
+			namespaces = append(namespaces, change.Namespace)
+		}
+		addProviderToInitOptions(initOptions, change)
+	}
+	sort.SliceStable(namespaces, func(i, j int) bool {
+		return installs[namespaces[i]].CoreProvider != "" && installs[namespaces[j]].CoreProvider == ""
+	})
+	for _, namespace := range namespaces {
+		initOptions := installs[namespace]
+		// Only the providers listed in the providers config are installed, so opt-out from the automatic
+		// installation of the default bootstrap and control plane providers.
+		if len(initOptions.BootstrapProviders) == 0 {
+			initOptions.BootstrapProviders = []string{NoopProvider}
+		}
+		if len(initOptions.ControlPlaneProviders) == 0 {
+			initOptions.ControlPlaneProviders = []string{NoopProvider}
+		}
+		if _, err := c.Init(*initOptions); err != nil {
+			return changes, err
+		}
+	}
+
+	// Upgrade the providers with a different version.
+	upgradeOptions := ApplyUpgradeOptions{
+		Kubeconfig:          options.Kubeconfig,
+		WaitProviders:       options.WaitProviders,
+		WaitProviderTimeout: options.WaitProviderTimeout,
+	}
+	upgrades := 0
+	for _, change := range changes {
+		if change.Action != ProviderUpgrade {
+			continue
+		}
+		upgrades++
+		provider := fmt.Sprintf("%s/%s:%s", change.Namespace, change.Name, change.DesiredVersion)
+		switch change.Type {
+		case clusterctlv1.CoreProviderType:
+			upgradeOptions.CoreProvider = provider
+		case clusterctlv1.BootstrapProviderType:
+			upgradeOptions.BootstrapProviders = append(upgradeOptions.BootstrapProviders, provider)
+		case clusterctlv1.ControlPlaneProviderType:
+			upgradeOptions.ControlPlaneProviders = append(upgradeOptions.ControlPlaneProviders, provider)
+		case clusterctlv1.InfrastructureProviderType:
+			upgradeOptions.InfrastructureProviders = append(upgradeOptions.InfrastructureProviders, provider)
+		case clusterctlv1.IPAMProviderType:
+			upgradeOptions.IPAMProviders = append(upgradeOptions.IPAMProviders, provider)
+		case clusterctlv1.RuntimeExtensionProviderType:
+			upgradeOptions.RuntimeExtensionProviders = append(upgradeOptions.RuntimeExtensionProviders, provider)
+		}
+	}
+	if upgrades > 0 {
+		log.Info("Upgrading providers", "Count", upgrades)
+		if err := c.ApplyUpgrade(upgradeOptions); err != nil {
+			return changes, err
+		}
+	}
+	return changes, nil
+}
+
+// validateProvidersConfig validates a providers config.
+func validateProvidersConfig(providersConfig ProvidersConfig) error {
+	seen := map[string]bool{}
+	cores := 0
+	for _, p := range providersConfig.Providers {
+		if p.Name == "" {
+			return errors.New("invalid providers config: providers must have a name")
+		}
+		switch p.Type {
+		case clusterctlv1.CoreProviderType:
+			cores++
+		case clusterctlv1.BootstrapProviderType, clusterctlv1.ControlPlaneProviderType, clusterctlv1.InfrastructureProviderType,
+			clusterctlv1.IPAMProviderType, clusterctlv1.RuntimeExtensionProviderType:
+		default:
+			return errors.Errorf("invalid providers config: provider %q has an invalid type %q", p.Name, p.Type)
+		}
+		if p.Version != "" {
+			if _, err := version.ParseSemantic(p.Version); err != nil {
+				return errors.Wrapf(err, "invalid providers config: provider %q has an invalid version %q", p.Name, p.Version)
+			}
+		}
+		key := fmt.Sprintf("%s/%s", p.Type, p.Name)
+		if seen[key] {
+			return errors.Errorf("invalid providers config: provider %q of type %q is listed more than once", p.Name, p.Type)
+		}
+		seen[key] = true
+	}
+	if cores > 1 {
+		return errors.New("invalid providers config: only one core provider can be listed")
+	}
+	return nil
+}
+
+// computeProviderChanges computes the changes required to reconcile the installed providers with the providers config.
+func computeProviderChanges(providersConfig ProvidersConfig, installed *clusterctlv1.ProviderList) ([]ProviderChange, error) {
+	changes := []ProviderChange{}
+	inConfig := map[string]bool{}
+	for _, p := range providersConfig.Providers {
+		inConfig[clusterctlv1.ManifestLabel(p.Name, p.Type)] = true
+
+		change := ProviderChange{
+			Name:           p.Name,
+			Type:           p.Type,
+			Namespace:      p.Namespace,
+			DesiredVersion: p.Version,
+			Action:         ProviderInstall,
+		}
+		current := installed.FilterByProviderNameAndType(p.Name, p.Type)
+		if len(current) > 0 {
+			change.CurrentVersion = current[0].Version
+			if p.Namespace != "" && p.Namespace != current[0].Namespace {
+				return nil, errors.Errorf("provider %q of type %q is installed in namespace %q, but namespace %q is requested; changing the namespace of a provider is not supported",
+					p.Name, p.Type, current[0].Namespace, p.Namespace)
+			}
+			change.Namespace = current[0].Namespace
+			change.Action = ProviderUnchanged
+			if p.Version != "" {
+				desiredVersion, _ := version.ParseSemantic(p.Version)
+				currentVersion, err := version.ParseSemantic(current[0].Version)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to parse the version of provider %q of type %q", p.Name, p.Type)
+				}
+				switch cmp, _ := desiredVersion.Compare(currentVersion.String()); {
+				case cmp < 0:
+					return nil, errors.Errorf("provider %q of type %q is installed with version %s, but version %s is requested; downgrading a provider is not supported",
+						p.Name, p.Type, current[0].Version, p.Version)
+				case cmp > 0:
+					change.Action = ProviderUpgrade
+				}
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	for _, p := range installed.Items {
+		if inConfig[clusterctlv1.ManifestLabel(p.ProviderName, p.GetProviderType())] {
+			continue
+		}
+		changes = append(changes, ProviderChange{
+			Name:           p.ProviderName,
+			Type:           p.GetProviderType(),
+			Namespace:      p.Namespace,
+			CurrentVersion: p.Version,
+			Action:         ProviderNotInConfig,
+		})
+	}
+	return changes, nil
+}
+
+// addProviderToInitOptions adds a provider to be installed to the InitOptions.
+func addProviderToInitOptions(options *InitOptions, change ProviderChange) {
+	provider := change.Name
+	if change.DesiredVersion != "" {
+		provider = fmt.Sprintf("%s:%s", change.Name, change.DesiredVersion)
+	}
+	switch change.Type {
+	case clusterctlv1.CoreProviderType:
+		options.CoreProvider = provider
+	case clusterctlv1.BootstrapProviderType:
+		options.BootstrapProviders = append(options.BootstrapProviders, provider)
+	case clusterctlv1.ControlPlaneProviderType:
+		options.ControlPlaneProviders = append(options.ControlPlaneProviders, provider)
+	case clusterctlv1.InfrastructureProviderType:
+		options.InfrastructureProviders = append(options.InfrastructureProviders, provider)
+	case clusterctlv1.IPAMProviderType:
+		options.IPAMProviders = append(options.IPAMProviders, provider)
+	case clusterctlv1.RuntimeExtensionProviderType:
+		options.RuntimeExtensionProviders = append(options.RuntimeExtensionProviders, provider)
+	}
+}
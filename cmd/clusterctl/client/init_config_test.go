@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func TestReadProvidersConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "providers.yaml")
+	g.Expect(os.WriteFile(path, []byte(`providers:
+- name: cluster-api
+  type: CoreProvider
+  version: v1.1.0
+- name: infra
+  type: InfrastructureProvider
+  namespace: infra-system
+variables:
+  SOME_VARIABLE: value
+`), 0600)).To(Succeed())
+
+	providersConfig, err := ReadProvidersConfig(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(providersConfig).To(Equal(&ProvidersConfig{
+		Providers: []ProvidersConfigProvider{
+			{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Version: "v1.1.0"},
+			{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Namespace: "infra-system"},
+		},
+		Variables: map[string]string{"SOME_VARIABLE": "value"},
+	}))
+
+	// Unknown fields are rejected.
+	g.Expect(os.WriteFile(path, []byte("providers:\n- name: infra\n  kind: InfrastructureProvider\n"), 0600)).To(Succeed())
+	_, err = ReadProvidersConfig(path)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func Test_clusterctlClient_ApplyProvidersConfig(t *testing.T) {
+	kubeconfig := Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}
+	withFakeCAPISetup := func(client *fakeClient) *fakeClient {
+		client.clusters[cluster.Kubeconfig(kubeconfig)].Proxy().(*test.FakeProxy).WithFakeCAPISetup()
+		return client
+	}
+
+	tests := []struct {
+		name            string
+		client          *fakeClient
+		providersConfig ProvidersConfig
+		want            []ProviderChange
+		wantErr         bool
+	}{
+		{
+			name:   "installed providers not changed and not listed are reported",
+			client: withFakeCAPISetup(fakeInitializedCluster()),
+			providersConfig: ProvidersConfig{
+				Providers: []ProvidersConfigProvider{
+					{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Version: "v1.0.0"},
+					{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Version: "v3.0.0", Namespace: "infra-system"},
+				},
+			},
+			want: []ProviderChange{
+				{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Namespace: "capi-system", CurrentVersion: "v1.0.0", DesiredVersion: "v1.0.0", Action: ProviderUnchanged},
+				{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Namespace: "infra-system", DesiredVersion: "v3.0.0", Action: ProviderInstall},
+			},
+		},
+		{
+			name:   "providers with a newer version are upgraded",
+			client: withFakeCAPISetup(fakeInitializedCluster()),
+			providersConfig: ProvidersConfig{
+				Providers: []ProvidersConfigProvider{
+					{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Version: "v1.1.0"},
+				},
+			},
+			want: []ProviderChange{
+				{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Namespace: "capi-system", CurrentVersion: "v1.0.0", DesiredVersion: "v1.1.0", Action: ProviderUpgrade},
+			},
+		},
+		{
+			name:   "providers without a version are left unchanged",
+			client: withFakeCAPISetup(fakeInitializedCluster()),
+			providersConfig: ProvidersConfig{
+				Providers: []ProvidersConfigProvider{
+					{Name: "infra", Type: clusterctlv1.InfrastructureProviderType},
+				},
+			},
+			want: []ProviderChange{
+				{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Action: ProviderInstall},
+				{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Namespace: "capi-system", CurrentVersion: "v1.0.0", Action: ProviderNotInConfig},
+			},
+		},
+		{
+			name:   "fails when downgrading a provider",
+			client: withFakeCAPISetup(fakeInitializedCluster()),
+			providersConfig: ProvidersConfig{
+				Providers: []ProvidersConfigProvider{
+					{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Version: "v0.9.0"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "fails when changing the namespace of a provider",
+			client: withFakeCAPISetup(fakeInitializedCluster()),
+			providersConfig: ProvidersConfig{
+				Providers: []ProvidersConfigProvider{
+					{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Namespace: "another-namespace"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "fails with an invalid provider type",
+			client: fakeEmptyCluster(),
+			providersConfig: ProvidersConfig{
+				Providers: []ProvidersConfigProvider{
+					{Name: "infra", Type: "InvalidProvider"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "fails with duplicated providers",
+			client: fakeEmptyCluster(),
+			providersConfig: ProvidersConfig{
+				Providers: []ProvidersConfigProvider{
+					{Name: "infra", Type: clusterctlv1.InfrastructureProviderType},
+					{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Version: "v3.0.0"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := tt.client.ApplyProvidersConfig(ApplyProvidersConfigOptions{
+				Kubeconfig:      kubeconfig,
+				ProvidersConfig: tt.providersConfig,
+				DryRun:          true,
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+
+	t.Run("installs only the providers listed in the providers config", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client := withFakeCAPISetup(fakeEmptyCluster())
+		_, err := client.ApplyProvidersConfig(ApplyProvidersConfigOptions{
+			Kubeconfig: kubeconfig,
+			ProvidersConfig: ProvidersConfig{
+				Providers: []ProvidersConfigProvider{
+					{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Version: "v3.0.0", Namespace: "infra-system"},
+					{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Version: "v1.0.0", Namespace: "capi-system"},
+				},
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// Applying the same providers config again does not require any change.
+		got, err := client.ApplyProvidersConfig(ApplyProvidersConfigOptions{
+			Kubeconfig: kubeconfig,
+			ProvidersConfig: ProvidersConfig{
+				Providers: []ProvidersConfigProvider{
+					{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Version: "v1.0.0"},
+					{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Version: "v3.0.0"},
+				},
+			},
+			DryRun: true,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(Equal([]ProviderChange{
+			{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Namespace: "capi-system", CurrentVersion: "v1.0.0", DesiredVersion: "v1.0.0", Action: ProviderUnchanged},
+			{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Namespace: "infra-system", CurrentVersion: "v3.0.0", DesiredVersion: "v3.0.0", Action: ProviderUnchanged},
+		}))
+	})
+}
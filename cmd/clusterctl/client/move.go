@@ -20,6 +20,7 @@ import (
 	"os"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
@@ -46,6 +47,25 @@ type MoveOptions struct {
 
 	// DryRun means the move action is a dry run, no real action will be performed.
 	DryRun bool
+
+	// ToNamespace defines the namespace where the objects should be moved to in the target management cluster.
+	// If empty, objects are moved to the same namespace they have in the source management cluster.
+	ToNamespace string
+
+	// Renames defines how objects should be renamed when moved to the target management cluster (old name -> new name);
+	// names are rewritten consistently across the object graph, including owner references and references between objects.
+	Renames map[string]string
+
+	// Selector is a label selector used to filter the Clusters to be moved, together with all the objects belonging to them;
+	// objects not belonging to any Cluster, e.g. ClusterClasses, are always moved. If empty, all the Clusters are moved.
+	Selector string
+
+	// IncludeKinds, if not empty, restricts the move to the objects of the given kinds, in the Kind or Kind.group format;
+	// "*" can be used as a Kind for selecting all the kinds in a group, e.g. *.cluster.x-k8s.io.
+	IncludeKinds []string
+
+	// ExcludeKinds excludes from the move the objects of the given kinds, in the same format as IncludeKinds.
+	ExcludeKinds []string
 }
 
 func (c *clusterctlClient) Move(options MoveOptions) error {
@@ -61,6 +81,20 @@ func (c *clusterctlClient) Move(options MoveOptions) error {
 		return errors.Errorf("at least one of FromDirectory, ToDirectory and ToKubeconfig must be set")
 	}
 
+	// Namespace and name remapping are supported only when moving objects between management clusters.
+	if (options.ToNamespace != "" || len(options.Renames) > 0) && (options.FromDirectory != "" || options.ToDirectory != "") {
+		return errors.Errorf("ToNamespace and Renames can't be used with FromDirectory or ToDirectory")
+	}
+
+	// Filters are applied when discovering objects in the source management cluster, so they can't be used when reading from a directory.
+	if (options.Selector != "" || len(options.IncludeKinds) > 0 || len(options.ExcludeKinds) > 0) && options.FromDirectory != "" {
+		return errors.Errorf("Selector, IncludeKinds and ExcludeKinds can't be used with FromDirectory")
+	}
+
+	if len(options.IncludeKinds) > 0 && len(options.ExcludeKinds) > 0 {
+		return errors.Errorf("can't set both IncludeKinds and ExcludeKinds")
+	}
+
 	if options.ToDirectory != "" {
 		return c.toDirectory(options)
 	} else if options.FromDirectory != "" {
@@ -94,7 +128,33 @@ func (c *clusterctlClient) move(options MoveOptions) error {
 		}
 	}
 
-	return fromCluster.ObjectMover().Move(options.Namespace, toCluster, options.DryRun)
+	filter, err := options.moveFilter()
+	if err != nil {
+		return err
+	}
+
+	var mutators []cluster.ResourceMutatorFunc
+	if options.ToNamespace != "" || len(options.Renames) > 0 {
+		mutators = append(mutators, cluster.NewRenameMutator(options.ToNamespace, options.Renames))
+	}
+
+	return fromCluster.ObjectMover().Move(options.Namespace, toCluster, options.DryRun, filter, mutators...)
+}
+
+// moveFilter returns the filter defining which objects should be moved.
+func (o MoveOptions) moveFilter() (cluster.MoveFilter, error) {
+	filter := cluster.MoveFilter{
+		IncludeKinds: o.IncludeKinds,
+		ExcludeKinds: o.ExcludeKinds,
+	}
+	if o.Selector != "" {
+		selector, err := labels.Parse(o.Selector)
+		if err != nil {
+			return cluster.MoveFilter{}, errors.Wrapf(err, "invalid selector %q", o.Selector)
+		}
+		filter.ClusterSelector = selector
+	}
+	return filter, nil
 }
 
 func (c *clusterctlClient) fromDirectory(options MoveOptions) error {
@@ -129,7 +189,12 @@ func (c *clusterctlClient) toDirectory(options MoveOptions) error {
 		return err
 	}
 
-	return fromCluster.ObjectMover().ToDirectory(options.Namespace, options.ToDirectory)
+	filter, err := options.moveFilter()
+	if err != nil {
+		return err
+	}
+
+	return fromCluster.ObjectMover().ToDirectory(options.Namespace, options.ToDirectory, filter)
 }
 
 func (c *clusterctlClient) getClusterClient(kubeconfig Kubeconfig) (cluster.Client, error) {
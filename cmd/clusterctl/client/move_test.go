@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
@@ -54,6 +55,65 @@ func Test_clusterctlClient_Move(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "does not return error if ToNamespace and Renames are set",
+			fields: fields{
+				client: fakeClientForMove(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					ToNamespace:    "tenant-a",
+					Renames:        map[string]string{"foo": "bar"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "does not return error if Selector and ExcludeKinds are set",
+			fields: fields{
+				client: fakeClientForMove(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					Selector:       "env=dev",
+					ExcludeKinds:   []string{"MachineHealthCheck"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns an error if Selector is invalid",
+			fields: fields{
+				client: fakeClientForMove(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					Selector:       "env in (dev",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if both IncludeKinds and ExcludeKinds are set",
+			fields: fields{
+				client: fakeClientForMove(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					IncludeKinds:   []string{"Cluster"},
+					ExcludeKinds:   []string{"Secret"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "returns an error if from cluster client is not found",
 			fields: fields{
@@ -186,6 +246,20 @@ func Test_clusterctlClient_ToDirectory(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "returns an error if ToNamespace is set",
+			fields: fields{
+				client: fakeClientForMove(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToDirectory:    dir,
+					ToNamespace:    "tenant-a",
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -296,24 +370,31 @@ type fakeObjectMover struct {
 	moveErr          error
 	toDirectoryErr   error
 	fromDirectoryErr error
+	backupErr        error
+	restoreErr       error
+	cloneErr         error
 }
 
-func (f *fakeObjectMover) Move(_ string, _ cluster.Client, _ bool) error {
+func (f *fakeObjectMover) Move(_ string, _ cluster.Client, _ bool, _ cluster.MoveFilter, _ ...cluster.ResourceMutatorFunc) error {
 	return f.moveErr
 }
 
-func (f *fakeObjectMover) ToDirectory(_ string, _ string) error {
+func (f *fakeObjectMover) ToDirectory(_ string, _ string, _ cluster.MoveFilter) error {
 	return f.toDirectoryErr
 }
 
 func (f *fakeObjectMover) Backup(_ string, _ string) error {
-	return f.toDirectoryErr
+	return f.backupErr
 }
 
 func (f *fakeObjectMover) FromDirectory(_ cluster.Client, _ string) error {
 	return f.fromDirectoryErr
 }
 
-func (f *fakeObjectMover) Restore(_ cluster.Client, _ string) error {
-	return f.fromDirectoryErr
+func (f *fakeObjectMover) Restore(_ cluster.Client, _ string, _ ...string) error {
+	return f.restoreErr
+}
+
+func (f *fakeObjectMover) Clone(_ cluster.CloneOptions) ([]unstructured.Unstructured, error) {
+	return nil, f.cloneErr
 }
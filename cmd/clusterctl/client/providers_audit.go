@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+// ProvidersAuditOptions carries the options supported by ProvidersAudit.
+type ProvidersAuditOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Migrate, if true, re-writes the objects of the CRDs with stale stored versions so they are stored with the
+	// current storage version, and then drops the stale versions from the CRD status.
+	Migrate bool
+}
+
+// ProvidersAuditResult is the result of the audit of the API versions used by the objects of a provider CRD.
+type ProvidersAuditResult = cluster.StorageVersionAudit
+
+// ProvidersAudit reports the deprecated and stale API versions used by the objects of the CRDs installed
+// by clusterctl, and optionally migrates the objects to the current storage version.
+func (c *clusterctlClient) ProvidersAudit(options ProvidersAuditOptions) ([]ProvidersAuditResult, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clusterClient.Proxy().CheckClusterAvailable(); err != nil {
+		return nil, err
+	}
+
+	auditor := clusterClient.StorageVersionAuditor()
+	audits, err := auditor.Audit()
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Migrate {
+		if err := auditor.Migrate(audits); err != nil {
+			return audits, err
+		}
+	}
+	return audits, nil
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/pkg/errors"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/errorcodes"
 	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
 )
 
@@ -133,7 +134,7 @@ func newRepositoryClient(provider config.Provider, configClient config.Client, o
 	if client.repository == nil {
 		r, err := repositoryFactory(provider, configClient.Variables())
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to get repository client for the %s with name %s", provider.Type(), provider.Name())
+			return nil, errorcodes.Wrap(errorcodes.ProviderRepositoryUnavailable, errors.Wrapf(err, "failed to get repository client for the %s with name %s", provider.Type(), provider.Name()))
 		}
 		client.repository = r
 	}
@@ -193,7 +194,21 @@ func repositoryFactory(providerConfig config.Provider, configVariablesClient con
 			return repo, err
 		}
 
-		return nil, errors.Errorf("invalid provider url. Only GitHub and GitLab are supported for %q schema", rURL.Scheme)
+		// otherwise the url is a generic HTTPS repository, e.g. an Artifactory instance mirroring the provider releases
+		repo, err := newHTTPSRepository(providerConfig, configVariablesClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the HTTPS repository client")
+		}
+		return repo, err
+	}
+
+	// if the url is an OCI repository
+	if rURL.Scheme == ociScheme {
+		repo, err := newOCIRepository(providerConfig, configVariablesClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the OCI repository client")
+		}
+		return repo, err
 	}
 
 	// if the url is a local filesystem repository
@@ -47,6 +47,9 @@ const (
 	mutatingWebhookConfigurationKind   = "MutatingWebhookConfiguration"
 	validatingWebhookConfigurationKind = "ValidatingWebhookConfiguration"
 	customResourceDefinitionKind       = "CustomResourceDefinition"
+	serviceKind                        = "Service"
+	deploymentKind                     = "Deployment"
+	networkPolicyKind                  = "NetworkPolicy"
 )
 
 // Components wraps a YAML file that defines the provider components
@@ -191,7 +194,8 @@ type ComponentsInput struct {
 // 2. The variables replacement can be skipped using the SkipTemplateProcess flag in the input options
 // 3. Ensure all the provider components are deployed in the target namespace (apply only to namespaced objects)
 // 4. Ensure all the ClusterRoleBinding which are referencing namespaced objects have the name prefixed with the namespace name
-// 5. Adds labels to all the components in order to allow easy identification of the provider objects.
+// 5. If enabled, adds NetworkPolicies denying all the ingress traffic to the target namespace except the traffic required by the provider
+// 6. Adds labels to all the components in order to allow easy identification of the provider objects.
 func NewComponents(input ComponentsInput) (Components, error) {
 	variables, err := input.Processor.GetVariables(input.RawYaml)
 	if err != nil {
@@ -256,6 +260,18 @@ func NewComponents(input ComponentsInput) (Components, error) {
 		return nil, errors.Wrap(err, "failed to set the TargetNamespace on the components")
 	}
 
+	// Add NetworkPolicies for the target namespace, if enabled.
+	networkPolicies, err := input.ConfigClient.NetworkPolicies().Get()
+	if err != nil {
+		return nil, err
+	}
+	if networkPolicies.Enabled {
+		objs, err = addNetworkPolicies(objs, input.Options.TargetNamespace, networkPolicies)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to add NetworkPolicies to the components")
+		}
+	}
+
 	// Add common labels.
 	objs = addCommonLabels(objs, input.Provider)
 
@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
+)
+
+// healthzPortName is the name of the container port used by the provider controllers for health probes.
+const healthzPortName = "healthz"
+
+// addNetworkPolicies adds to the provider components the NetworkPolicies denying all the ingress traffic to the
+// provider namespace except:
+// - the traffic to the ports exposed by the provider Services, e.g. webhooks called by the apiserver.
+// - the traffic to the health probe ports of the provider controllers.
+// - the metrics scraping from the namespaces selected in the NetworkPolicies configuration.
+func addNetworkPolicies(objs []unstructured.Unstructured, targetNamespace string, networkPolicies *config.NetworkPolicies) ([]unstructured.Unstructured, error) {
+	servicePorts, err := inspectServicePorts(objs)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := []*networkingv1.NetworkPolicy{
+		newNetworkPolicy(targetNamespace, "default-deny-ingress", nil),
+		newNetworkPolicy(targetNamespace, "allow-metrics", []networkingv1.NetworkPolicyIngressRule{
+			{
+				From: []networkingv1.NetworkPolicyPeer{
+					{
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: networkPolicies.MetricsNamespaceSelector},
+					},
+				},
+				Ports: []networkingv1.NetworkPolicyPort{
+					networkPolicyPort(intstr.FromInt(int(networkPolicies.MetricsPort))),
+				},
+			},
+		}),
+	}
+	if len(servicePorts) > 0 {
+		ports := make([]networkingv1.NetworkPolicyPort, 0, len(servicePorts))
+		for _, port := range servicePorts {
+			ports = append(ports, networkPolicyPort(port))
+		}
+		// NOTE: the traffic from the apiserver can't be selected using pod or namespace selectors, so the
+		// traffic to the Service ports is allowed from every source.
+		policies = append(policies, newNetworkPolicy(targetNamespace, "allow-services", []networkingv1.NetworkPolicyIngressRule{
+			{Ports: ports},
+		}))
+	}
+	if hasHealthzPort(objs) {
+		policies = append(policies, newNetworkPolicy(targetNamespace, "allow-health-probes", []networkingv1.NetworkPolicyIngressRule{
+			{
+				Ports: []networkingv1.NetworkPolicyPort{
+					networkPolicyPort(intstr.FromString(healthzPortName)),
+				},
+			},
+		}))
+	}
+
+	for _, policy := range policies {
+		u := unstructured.Unstructured{}
+		if err := scheme.Scheme.Convert(policy, &u, nil); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert NetworkPolicy %s to unstructured", policy.Name)
+		}
+		objs = append(objs, u)
+	}
+	return objs, nil
+}
+
+// newNetworkPolicy returns a NetworkPolicy selecting all the pods in the namespace with the given ingress rules.
+func newNetworkPolicy(namespace, name string, ingress []networkingv1.NetworkPolicyIngressRule) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       networkPolicyKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			Ingress:     ingress,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+}
+
+func networkPolicyPort(port intstr.IntOrString) networkingv1.NetworkPolicyPort {
+	protocol := corev1.ProtocolTCP
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &port,
+	}
+}
+
+// inspectServicePorts returns the target ports of the Services in the provider components.
+func inspectServicePorts(objs []unstructured.Unstructured) ([]intstr.IntOrString, error) {
+	seen := sets.Set[string]{}
+	ports := []intstr.IntOrString{}
+	for i := range objs {
+		o := objs[i]
+		if o.GetKind() != serviceKind {
+			continue
+		}
+
+		service := &corev1.Service{}
+		if err := scheme.Scheme.Convert(&o, service, nil); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert Service %s to typed object", o.GetName())
+		}
+		for _, p := range service.Spec.Ports {
+			port := p.TargetPort
+			// If the target port is not set, it defaults to the service port.
+			if port.Type == intstr.Int && port.IntVal == 0 {
+				port = intstr.FromInt(int(p.Port))
+			}
+			if seen.Has(port.String()) {
+				continue
+			}
+			seen.Insert(port.String())
+			ports = append(ports, port)
+		}
+	}
+	return ports, nil
+}
+
+// hasHealthzPort returns true if one of the Deployments in the provider components exposes a health probe port.
+func hasHealthzPort(objs []unstructured.Unstructured) bool {
+	for i := range objs {
+		o := objs[i]
+		if o.GetKind() != deploymentKind {
+			continue
+		}
+
+		deployment := &appsv1.Deployment{}
+		if err := scheme.Scheme.Convert(&o, deployment, nil); err != nil {
+			continue
+		}
+		for _, c := range deployment.Spec.Template.Spec.Containers {
+			for _, p := range c.Ports {
+				if p.Name == healthzPortName {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -638,6 +638,87 @@ func Test_addNamespaceIfMissing(t *testing.T) {
 	}
 }
 
+func Test_addNetworkPolicies(t *testing.T) {
+	g := NewWithT(t)
+
+	objs := []unstructured.Unstructured{
+		{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       serviceKind,
+				"metadata": map[string]interface{}{
+					"name":      "webhook-service",
+					"namespace": "foo",
+				},
+				"spec": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{
+							"port":       int64(443),
+							"targetPort": "webhook-server",
+						},
+					},
+				},
+			},
+		},
+		{
+			Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       deploymentKind,
+				"metadata": map[string]interface{}{
+					"name":      "controller-manager",
+					"namespace": "foo",
+				},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name": "manager",
+									"ports": []interface{}{
+										map[string]interface{}{
+											"containerPort": int64(9440),
+											"name":          "healthz",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := addNetworkPolicies(objs, "foo", &config.NetworkPolicies{
+		Enabled:                  true,
+		MetricsPort:              8080,
+		MetricsNamespaceSelector: map[string]string{"name": "monitoring"},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	policies := map[string]unstructured.Unstructured{}
+	for _, o := range got {
+		if o.GetKind() == networkPolicyKind {
+			g.Expect(o.GetNamespace()).To(Equal("foo"))
+			policies[o.GetName()] = o
+		}
+	}
+	g.Expect(policies).To(HaveLen(4))
+	g.Expect(policies).To(HaveKey("default-deny-ingress"))
+
+	servicePort, _, err := unstructured.NestedFieldNoCopy(policies["allow-services"].Object, "spec", "ingress")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(servicePort).To(ConsistOf(HaveKeyWithValue("ports", ConsistOf(HaveKeyWithValue("port", "webhook-server")))))
+
+	healthzPort, _, err := unstructured.NestedFieldNoCopy(policies["allow-health-probes"].Object, "spec", "ingress")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(healthzPort).To(ConsistOf(HaveKeyWithValue("ports", ConsistOf(HaveKeyWithValue("port", "healthz")))))
+
+	metricsSelector, _, err := unstructured.NestedFieldNoCopy(policies["allow-metrics"].Object, "spec", "ingress")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(metricsSelector).To(ConsistOf(HaveKeyWithValue("from", ConsistOf(HaveKeyWithValue("namespaceSelector", HaveKeyWithValue("matchLabels", HaveKeyWithValue("name", "monitoring")))))))
+}
+
 func Test_addCommonLabels(t *testing.T) {
 	type args struct {
 		objs         []unstructured.Unstructured
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+// repositoryCredentialsFile defines the credentials for authenticated HTTPS and OCI provider repositories.
+//
+// Example:
+//
+//	hosts:
+//	- host: artifactory.example.com
+//	  token: ${ARTIFACTORY_TOKEN}
+//	- host: harbor.example.com
+//	  username: robot$clusterctl
+//	  password: ${HARBOR_PASSWORD}
+//	- host: internal.example.com
+//	  certFile: /home/user/.cluster-api/client.crt
+//	  keyFile: /home/user/.cluster-api/client.key
+//	  caFile: /home/user/.cluster-api/ca.crt
+//
+// Environment variables in token, username and password are expanded, so secrets are not required to be stored in the file.
+type repositoryCredentialsFile struct {
+	Hosts []repositoryCredentials `json:"hosts"`
+}
+
+// repositoryCredentials defines the credentials for a repository host.
+type repositoryCredentials struct {
+	// Host is the host of the repository, e.g. "harbor.example.com" or "harbor.example.com:8443".
+	Host string `json:"host"`
+
+	// Token is a bearer token used to authenticate to the repository.
+	Token string `json:"token,omitempty"`
+
+	// Username and Password are used to authenticate to the repository with basic authentication or,
+	// for OCI registries, to get a bearer token from the registry authorization service.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// CertFile and KeyFile are the paths of the client certificate and key used to authenticate to the repository with mTLS.
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+
+	// CAFile is the path of the CA bundle used to verify the repository certificate.
+	CAFile string `json:"caFile,omitempty"`
+}
+
+// getRepositoryCredentials returns the credentials for a repository host, if defined in the file referenced by
+// the repository-credentials variable; nil is returned if the variable is not set or the host has no credentials.
+func getRepositoryCredentials(configVariablesClient config.VariablesClient, host string) (*repositoryCredentials, error) {
+	path, err := configVariablesClient.Get(config.RepositoryCredentialsVariable)
+	if err != nil || path == "" {
+		return nil, nil //nolint:nilerr
+	}
+
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read repository credentials file %q", path)
+	}
+	credentialsFile := &repositoryCredentialsFile{}
+	if err := yaml.UnmarshalStrict(content, credentialsFile); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse repository credentials file %q", path)
+	}
+
+	for i := range credentialsFile.Hosts {
+		credentials := credentialsFile.Hosts[i]
+		if credentials.Host != host {
+			continue
+		}
+		credentials.Token = os.ExpandEnv(credentials.Token)
+		credentials.Username = os.ExpandEnv(credentials.Username)
+		credentials.Password = os.ExpandEnv(credentials.Password)
+		if (credentials.CertFile == "") != (credentials.KeyFile == "") {
+			return nil, errors.Errorf("invalid repository credentials for host %q: certFile and keyFile must be set together", host)
+		}
+		return &credentials, nil
+	}
+	return nil, nil
+}
+
+// newHTTPClient returns an HTTP client configured with the TLS settings of the repository credentials.
+func (c *repositoryCredentials) newHTTPClient() (*http.Client, error) {
+	if c == nil || (c.CertFile == "" && c.CAFile == "") {
+		return http.DefaultClient, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if c.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load client certificate for host %q", c.Host)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	if c.CAFile != "" {
+		ca, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read CA file for host %q", c.Host)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.Errorf("failed to parse CA file for host %q", c.Host)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
+
+// authorize adds the authentication header to a request, if the credentials define a token or a username.
+func (c *repositoryCredentials) authorize(request *http.Request) {
+	if c == nil {
+		return
+	}
+	switch {
+	case c.Token != "":
+		request.Header.Set("Authorization", "Bearer "+c.Token)
+	case c.Username != "":
+		request.SetBasicAuth(c.Username, c.Password)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+const (
+	// httpsVersionsFile is the file listing the versions available in an HTTPS repository, one per line.
+	httpsVersionsFile = "versions.txt"
+)
+
+// httpsRepository provides support for providers hosted on a generic HTTPS server, e.g. an Artifactory
+// or Nexus instance mirroring the provider release assets.
+// The directories containing provider specific data must adhere to the following layout:
+// https://{host}/{basepath}/{version}/{components.yaml}
+//
+// The versions available in the repository are read from the optional {basepath}/versions.txt file,
+// listing one version per line; if the file does not exist, only the version in the URL is available.
+// The version in the URL can be "latest" only if the versions.txt file exists.
+// Credentials for the host are read from the file referenced by the repository-credentials variable.
+type httpsRepository struct {
+	providerConfig        config.Provider
+	configVariablesClient config.VariablesClient
+	httpClient            *http.Client
+	credentials           *repositoryCredentials
+	baseURL               string
+	defaultVersion        string
+	componentsPath        string
+}
+
+var _ Repository = &httpsRepository{}
+
+// newHTTPSRepository returns a httpsRepository implementation.
+func newHTTPSRepository(providerConfig config.Provider, configVariablesClient config.VariablesClient) (*httpsRepository, error) {
+	if configVariablesClient == nil {
+		return nil, errors.New("invalid arguments: configVariablesClient can't be nil")
+	}
+
+	rURL, err := url.Parse(providerConfig.URL())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid url")
+	}
+
+	urlSplit := strings.Split(strings.TrimPrefix(rURL.EscapedPath(), "/"), "/")
+	if rURL.Scheme != httpsScheme || len(urlSplit) < 3 {
+		return nil, errors.New("invalid url: an HTTPS repository url should be in the form https://{host}/{basepath}/{version}/{componentsPath}")
+	}
+
+	defaultVersion := urlSplit[len(urlSplit)-2]
+	if defaultVersion != latestVersionTag {
+		if _, err := version.ParseSemantic(defaultVersion); err != nil {
+			return nil, errors.Errorf("invalid version: %q. Version must obey the syntax and semantics of the \"Semantic Versioning\" specification (http://semver.org/) and path format https://{host}/{basepath}/{version}/{componentsPath}", defaultVersion)
+		}
+	}
+
+	credentials, err := getRepositoryCredentials(configVariablesClient, rURL.Host)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := credentials.newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &httpsRepository{
+		providerConfig:        providerConfig,
+		configVariablesClient: configVariablesClient,
+		httpClient:            httpClient,
+		credentials:           credentials,
+		baseURL:               fmt.Sprintf("%s://%s/%s", rURL.Scheme, rURL.Host, strings.Join(urlSplit[:len(urlSplit)-2], "/")),
+		defaultVersion:        defaultVersion,
+		componentsPath:        urlSplit[len(urlSplit)-1],
+	}
+
+	if defaultVersion == latestVersionTag {
+		repo.defaultVersion, err = latestContractRelease(repo, clusterv1.GroupVersion.Version)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get latest version")
+		}
+	}
+	return repo, nil
+}
+
+// DefaultVersion returns the default version for the HTTPS repository.
+func (r *httpsRepository) DefaultVersion() string {
+	return r.defaultVersion
+}
+
+// RootPath returns the empty string as it is not applicable to HTTPS repositories.
+func (r *httpsRepository) RootPath() string {
+	return ""
+}
+
+// ComponentsPath returns the path to the components file for the HTTPS repository.
+func (r *httpsRepository) ComponentsPath() string {
+	return r.componentsPath
+}
+
+// GetVersions returns the list of versions that are available in the HTTPS repository.
+func (r *httpsRepository) GetVersions() ([]string, error) {
+	content, err := r.get(fmt.Sprintf("%s/%s", r.baseURL, httpsVersionsFile))
+	if err != nil {
+		if errors.Is(err, errHTTPNotFound) && r.defaultVersion != latestVersionTag {
+			return []string{r.defaultVersion}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the list of versions")
+	}
+
+	versions := []string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		v := strings.TrimSpace(line)
+		if v == "" || strings.HasPrefix(v, "#") {
+			continue
+		}
+		if _, err := version.ParseSemantic(v); err != nil {
+			// discard releases with tags that are not a valid semantic versions (the user can point explicitly to such releases)
+			continue
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// GetFile returns a file for a given provider version.
+func (r *httpsRepository) GetFile(version, path string) ([]byte, error) {
+	var err error
+	if version == latestVersionTag {
+		version, err = latestRelease(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the latest release")
+		}
+	} else if version == "" {
+		version = r.defaultVersion
+	}
+
+	url := fmt.Sprintf("%s/%s/%s", r.baseURL, version, path)
+	if content, ok := cacheFiles[url]; ok {
+		return content, nil
+	}
+
+	content, err := r.get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get file %q with version %q", path, version)
+	}
+
+	cacheFiles[url] = content
+	return content, nil
+}
+
+// errHTTPNotFound is returned when the requested file does not exist in the repository.
+var errHTTPNotFound = errors.New("not found")
+
+func (r *httpsRepository) get(url string) ([]byte, error) {
+	ctx := context.TODO()
+
+	timeoutctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	request, err := http.NewRequestWithContext(timeoutctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q: failed to create request", url)
+	}
+	r.credentials.authorize(request)
+
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q", url)
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errors.Wrapf(errHTTPNotFound, "failed to get %q", url)
+	default:
+		return nil, errors.Errorf("failed to get %q, got %d", url, response.StatusCode)
+	}
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q", url)
+	}
+	return content, nil
+}
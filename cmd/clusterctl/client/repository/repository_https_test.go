@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_httpsRepository(t *testing.T) {
+	g := NewWithT(t)
+
+	const token = "my-token"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/artifactory/capi/versions.txt":
+			fmt.Fprint(w, "# cluster-api releases\nv1.3.0\nv1.4.0\nnot-a-version\n")
+		case "/artifactory/capi/v1.4.0/core-components.yaml":
+			fmt.Fprint(w, "content")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	configVariablesClient := test.NewFakeVariableClient().WithVar(config.RepositoryCredentialsVariable, writeRepositoryCredentials(t, server, fmt.Sprintf("token: %s", token)))
+
+	providerConfig := config.NewProvider("test", fmt.Sprintf("%s/artifactory/capi/latest/core-components.yaml", server.URL), clusterctlv1.CoreProviderType)
+	repo, err := repositoryFactory(providerConfig, configVariablesClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(repo).To(BeAssignableToTypeOf(&httpsRepository{}))
+
+	g.Expect(repo.DefaultVersion()).To(Equal("v1.4.0"))
+	g.Expect(repo.ComponentsPath()).To(Equal("core-components.yaml"))
+	g.Expect(repo.GetVersions()).To(Equal([]string{"v1.3.0", "v1.4.0"}))
+
+	content, err := repo.GetFile("v1.4.0", "core-components.yaml")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(content)).To(Equal("content"))
+
+	_, err = repo.GetFile("v1.3.0", "core-components.yaml")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func Test_newHTTPSRepository(t *testing.T) {
+	tests := []struct {
+		name               string
+		url                string
+		wantDefaultVersion string
+		wantBaseURL        string
+		wantErr            bool
+	}{
+		{
+			name:               "can create a new HTTPS repository",
+			url:                "https://artifactory.example.com/artifactory/capi/v1.4.0/core-components.yaml",
+			wantDefaultVersion: "v1.4.0",
+			wantBaseURL:        "https://artifactory.example.com/artifactory/capi",
+		},
+		{
+			name:    "fails if the url does not contain a version",
+			url:     "https://artifactory.example.com/core-components.yaml",
+			wantErr: true,
+		},
+		{
+			name:    "fails if the version is not a semantic version",
+			url:     "https://artifactory.example.com/artifactory/capi/main/core-components.yaml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			repo, err := newHTTPSRepository(config.NewProvider("test", tt.url, clusterctlv1.CoreProviderType), test.NewFakeVariableClient())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(repo.DefaultVersion()).To(Equal(tt.wantDefaultVersion))
+			g.Expect(repo.baseURL).To(Equal(tt.wantBaseURL))
+			g.Expect(repo.ComponentsPath()).To(Equal("core-components.yaml"))
+		})
+	}
+}
+
+// writeRepositoryCredentials writes a repository credentials file for the test server, trusting its certificate,
+// and returns the path of the file.
+func writeRepositoryCredentials(t *testing.T, server *httptest.Server, credentials string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	credentialsFile := filepath.Join(dir, "credentials.yaml")
+	content := fmt.Sprintf("hosts:\n- host: %s\n  caFile: %s\n  %s\n", strings.TrimPrefix(server.URL, "https://"), caFile, credentials)
+	if err := os.WriteFile(credentialsFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return credentialsFile
+}
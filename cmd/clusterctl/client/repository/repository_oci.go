@@ -0,0 +1,362 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+const (
+	ociScheme = "oci"
+
+	ociImageManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ociImageTitleAnnotation   = "org.opencontainers.image.title"
+)
+
+// ociRepository provides support for providers hosted on an OCI registry, e.g. Harbor, as OCI artifacts.
+// Each provider version is an artifact tagged with the version, and each file of the release is a layer of the artifact
+// annotated with the file name in the org.opencontainers.image.title annotation, as done by `oras push`.
+// The repository URL must adhere to the following layout:
+// oci://{registry}/{repository}:{version}/{components.yaml}
+//
+// The versions available in the repository are the tags of the repository which are valid semantic versions;
+// "latest" is also an acceptable value for the version in the URL.
+// Credentials for the registry are read from the file referenced by the repository-credentials variable.
+type ociRepository struct {
+	providerConfig        config.Provider
+	configVariablesClient config.VariablesClient
+	httpClient            *http.Client
+	credentials           *repositoryCredentials
+	registry              string
+	repository            string
+	defaultVersion        string
+	componentsPath        string
+
+	// bearerToken is the token obtained from the registry authorization service.
+	bearerToken string
+}
+
+var _ Repository = &ociRepository{}
+
+// ociManifest is the subset of an OCI image manifest clusterctl needs to locate the files of an artifact.
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+// ociDescriptor is the subset of an OCI content descriptor clusterctl needs to fetch a layer.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociTagList is the response of the OCI distribution tags list API.
+type ociTagList struct {
+	Tags []string `json:"tags"`
+}
+
+// newOCIRepository returns an ociRepository implementation.
+func newOCIRepository(providerConfig config.Provider, configVariablesClient config.VariablesClient) (*ociRepository, error) {
+	if configVariablesClient == nil {
+		return nil, errors.New("invalid arguments: configVariablesClient can't be nil")
+	}
+
+	rURL, err := url.Parse(providerConfig.URL())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid url")
+	}
+
+	path := strings.TrimPrefix(rURL.Path, "/")
+	separator := strings.LastIndex(path, "/")
+	if rURL.Scheme != ociScheme || rURL.Host == "" || separator < 0 {
+		return nil, errors.New("invalid url: an OCI repository url should be in the form oci://{registry}/{repository}:{version}/{componentsPath}")
+	}
+	reference, componentsPath := path[:separator], path[separator+1:]
+	tagSeparator := strings.LastIndex(reference, ":")
+	if tagSeparator <= 0 || strings.Contains(reference[tagSeparator:], "/") || componentsPath == "" {
+		return nil, errors.New("invalid url: an OCI repository url should be in the form oci://{registry}/{repository}:{version}/{componentsPath}")
+	}
+	repository, defaultVersion := reference[:tagSeparator], reference[tagSeparator+1:]
+	if defaultVersion != latestVersionTag {
+		if _, err := version.ParseSemantic(defaultVersion); err != nil {
+			return nil, errors.Errorf("invalid version: %q. Version must obey the syntax and semantics of the \"Semantic Versioning\" specification (http://semver.org/) and url format oci://{registry}/{repository}:{version}/{componentsPath}", defaultVersion)
+		}
+	}
+
+	credentials, err := getRepositoryCredentials(configVariablesClient, rURL.Host)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := credentials.newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &ociRepository{
+		providerConfig:        providerConfig,
+		configVariablesClient: configVariablesClient,
+		httpClient:            httpClient,
+		credentials:           credentials,
+		registry:              rURL.Host,
+		repository:            repository,
+		defaultVersion:        defaultVersion,
+		componentsPath:        componentsPath,
+	}
+
+	if defaultVersion == latestVersionTag {
+		repo.defaultVersion, err = latestContractRelease(repo, clusterv1.GroupVersion.Version)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get latest version")
+		}
+	}
+	return repo, nil
+}
+
+// DefaultVersion returns the default version for the OCI repository.
+func (r *ociRepository) DefaultVersion() string {
+	return r.defaultVersion
+}
+
+// RootPath returns the empty string as it is not applicable to OCI repositories.
+func (r *ociRepository) RootPath() string {
+	return ""
+}
+
+// ComponentsPath returns the path to the components file for the OCI repository.
+func (r *ociRepository) ComponentsPath() string {
+	return r.componentsPath
+}
+
+// GetVersions returns the list of versions that are available in the OCI repository.
+func (r *ociRepository) GetVersions() ([]string, error) {
+	content, err := r.get(fmt.Sprintf("/v2/%s/tags/list", r.repository), "application/json")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the list of tags of %s/%s", r.registry, r.repository)
+	}
+	tagList := &ociTagList{}
+	if err := json.Unmarshal(content, tagList); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the list of tags of %s/%s", r.registry, r.repository)
+	}
+
+	versions := []string{}
+	for _, tag := range tagList.Tags {
+		if _, err := version.ParseSemantic(tag); err != nil {
+			// discard releases with tags that are not a valid semantic versions (the user can point explicitly to such releases)
+			continue
+		}
+		versions = append(versions, tag)
+	}
+	return versions, nil
+}
+
+// GetFile returns a file for a given provider version.
+func (r *ociRepository) GetFile(version, path string) ([]byte, error) {
+	var err error
+	if version == latestVersionTag {
+		version, err = latestRelease(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the latest release")
+		}
+	} else if version == "" {
+		version = r.defaultVersion
+	}
+
+	cacheID := fmt.Sprintf("%s://%s/%s:%s/%s", ociScheme, r.registry, r.repository, version, path)
+	if content, ok := cacheFiles[cacheID]; ok {
+		return content, nil
+	}
+
+	content, err := r.get(fmt.Sprintf("/v2/%s/manifests/%s", r.repository, version), ociImageManifestMediaType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get file %q with version %q: failed to get the artifact manifest", path, version)
+	}
+	manifest := &ociManifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to get file %q with version %q: failed to parse the artifact manifest", path, version)
+	}
+
+	var layer *ociDescriptor
+	for i := range manifest.Layers {
+		if manifest.Layers[i].Annotations[ociImageTitleAnnotation] == path {
+			layer = &manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return nil, errors.Errorf("failed to get file %q with version %q: the artifact has no layer with %s %q", path, version, ociImageTitleAnnotation, path)
+	}
+
+	content, err = r.get(fmt.Sprintf("/v2/%s/blobs/%s", r.repository, layer.Digest), layer.MediaType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get file %q with version %q", path, version)
+	}
+	if algorithm, encoded, ok := strings.Cut(layer.Digest, ":"); ok && algorithm == "sha256" {
+		if sum := sha256.Sum256(content); hex.EncodeToString(sum[:]) != encoded {
+			return nil, errors.Errorf("failed to get file %q with version %q: digest does not match %s", path, version, layer.Digest)
+		}
+	}
+
+	cacheFiles[cacheID] = content
+	return content, nil
+}
+
+// get returns the content of a path of the registry API.
+// If the registry requires authentication, the request is retried according to the WWW-Authenticate challenge,
+// either using basic authentication or getting a bearer token from the registry authorization service.
+func (r *ociRepository) get(path, accept string) ([]byte, error) {
+	ctx := context.TODO()
+
+	timeoutctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	url := fmt.Sprintf("https://%s%s", r.registry, path)
+	response, err := r.do(timeoutctx, url, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusUnauthorized {
+		if err := r.authenticate(timeoutctx, response.Header.Get("WWW-Authenticate")); err != nil {
+			return nil, errors.Wrapf(err, "failed to authenticate to %q", r.registry)
+		}
+		response, err = r.do(timeoutctx, url, accept)
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get %q, got %d", url, response.StatusCode)
+	}
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q", url)
+	}
+	return content, nil
+}
+
+func (r *ociRepository) do(ctx context.Context, url, accept string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q: failed to create request", url)
+	}
+	request.Header.Set("Accept", accept)
+	if r.bearerToken != "" {
+		request.Header.Set("Authorization", "Bearer "+r.bearerToken)
+	} else {
+		r.credentials.authorize(request)
+	}
+
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q", url)
+	}
+	return response, nil
+}
+
+// authenticate gets a bearer token from the registry authorization service according to the WWW-Authenticate challenge,
+// e.g. `Bearer realm="https://harbor.example.com/service/token",service="harbor-registry",scope="repository:capi/core:pull"`.
+func (r *ociRepository) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return errors.Errorf("unsupported authentication challenge %q", challenge)
+	}
+	if r.bearerToken != "" || (r.credentials != nil && r.credentials.Token != "") {
+		return errors.New("the token has been rejected")
+	}
+
+	attributes := parseAuthenticateParams(params)
+	realm, err := url.Parse(attributes["realm"])
+	if err != nil || realm.Host == "" {
+		return errors.Errorf("invalid realm in authentication challenge %q", challenge)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := attributes[key]; ok {
+			query.Set(key, value)
+		}
+	}
+	if _, ok := attributes["scope"]; !ok {
+		query.Set("scope", fmt.Sprintf("repository:%s:pull", r.repository))
+	}
+	realm.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), http.NoBody)
+	if err != nil {
+		return errors.Wrap(err, "failed to create token request")
+	}
+	r.credentials.authorize(request)
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "failed to get token")
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to get token from %q, got %d", realm.Host, response.StatusCode)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return errors.Wrap(err, "failed to parse token")
+	}
+	r.bearerToken = token.Token
+	if r.bearerToken == "" {
+		r.bearerToken = token.AccessToken
+	}
+	if r.bearerToken == "" {
+		return errors.Errorf("no token returned from %q", realm.Host)
+	}
+	return nil
+}
+
+// parseAuthenticateParams parses the comma separated key="value" params of a WWW-Authenticate challenge.
+func parseAuthenticateParams(params string) map[string]string {
+	attributes := map[string]string{}
+	for params != "" {
+		var key, value string
+		key, params, _ = strings.Cut(strings.TrimLeft(params, " ,"), "=")
+		if strings.HasPrefix(params, `"`) {
+			value, params, _ = strings.Cut(params[1:], `"`)
+		} else {
+			value, params, _ = strings.Cut(params, ",")
+		}
+		if key != "" {
+			attributes[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_ociRepository(t *testing.T) {
+	g := NewWithT(t)
+
+	const (
+		username = "robot"
+		password = "secret"
+		token    = "registry-token"
+		content  = "content"
+	)
+	sum := sha256.Sum256([]byte(content))
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != username || p != password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("scope") != "repository:capi/core:pull" || r.URL.Query().Get("service") != "registry" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"token": %q}`, token)
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:capi/core:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/capi/core/tags/list":
+			g.Expect(json.NewEncoder(w).Encode(ociTagList{Tags: []string{"v1.3.0", "v1.4.0", "main"}})).To(Succeed())
+		case "/v2/capi/core/manifests/v1.4.0":
+			g.Expect(r.Header.Get("Accept")).To(Equal(ociImageManifestMediaType))
+			g.Expect(json.NewEncoder(w).Encode(ociManifest{Layers: []ociDescriptor{
+				{
+					MediaType:   "application/vnd.oci.image.layer.v1.tar",
+					Digest:      digest,
+					Annotations: map[string]string{ociImageTitleAnnotation: "core-components.yaml"},
+				},
+			}})).To(Succeed())
+		case "/v2/capi/core/blobs/" + digest:
+			fmt.Fprint(w, content)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	server = httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	configVariablesClient := test.NewFakeVariableClient().WithVar(config.RepositoryCredentialsVariable, writeRepositoryCredentials(t, server, fmt.Sprintf("username: %s\n  password: %s", username, password)))
+
+	registry := strings.TrimPrefix(server.URL, "https://")
+	providerConfig := config.NewProvider("test", fmt.Sprintf("oci://%s/capi/core:latest/core-components.yaml", registry), clusterctlv1.CoreProviderType)
+	repo, err := repositoryFactory(providerConfig, configVariablesClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(repo).To(BeAssignableToTypeOf(&ociRepository{}))
+
+	g.Expect(repo.DefaultVersion()).To(Equal("v1.4.0"))
+	g.Expect(repo.ComponentsPath()).To(Equal("core-components.yaml"))
+	g.Expect(repo.GetVersions()).To(Equal([]string{"v1.3.0", "v1.4.0"}))
+
+	got, err := repo.GetFile("v1.4.0", "core-components.yaml")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(got)).To(Equal(content))
+
+	_, err = repo.GetFile("v1.4.0", "metadata.yaml")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func Test_newOCIRepository(t *testing.T) {
+	tests := []struct {
+		name               string
+		url                string
+		wantRepository     string
+		wantDefaultVersion string
+		wantErr            bool
+	}{
+		{
+			name:               "can create a new OCI repository",
+			url:                "oci://harbor.example.com:8443/capi/core:v1.4.0/core-components.yaml",
+			wantRepository:     "capi/core",
+			wantDefaultVersion: "v1.4.0",
+		},
+		{
+			name:    "fails if the url does not contain a version",
+			url:     "oci://harbor.example.com/capi/core/core-components.yaml",
+			wantErr: true,
+		},
+		{
+			name:    "fails if the url does not contain the components path",
+			url:     "oci://harbor.example.com/capi/core:v1.4.0",
+			wantErr: true,
+		},
+		{
+			name:    "fails if the version is not a semantic version",
+			url:     "oci://harbor.example.com/capi/core:main/core-components.yaml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			repo, err := newOCIRepository(config.NewProvider("test", tt.url, clusterctlv1.CoreProviderType), test.NewFakeVariableClient())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(repo.repository).To(Equal(tt.wantRepository))
+			g.Expect(repo.DefaultVersion()).To(Equal(tt.wantDefaultVersion))
+			g.Expect(repo.ComponentsPath()).To(Equal("core-components.yaml"))
+		})
+	}
+}
+
+func Test_parseAuthenticateParams(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(parseAuthenticateParams(`realm="https://harbor.example.com/service/token",service="harbor-registry",scope="repository:capi/core:pull,push"`)).To(Equal(map[string]string{
+		"realm":   "https://harbor.example.com/service/token",
+		"service": "harbor-registry",
+		"scope":   "repository:capi/core:pull,push",
+	}))
+}
@@ -22,6 +22,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/alpha"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
 )
@@ -52,6 +53,9 @@ type RolloutPauseOptions struct {
 	// Namespace where the resource(s) live. If unspecified, the namespace name will be inferred
 	// from the current configuration.
 	Namespace string
+
+	// Reason why the rollout is paused; it is recorded in an event on the resource(s).
+	Reason string
 }
 
 // RolloutResumeOptions carries the options supported by RolloutResume.
@@ -66,6 +70,9 @@ type RolloutResumeOptions struct {
 	// Namespace where the resource(s) live. If unspecified, the namespace name will be inferred
 	// from the current configuration.
 	Namespace string
+
+	// Reason why the rollout is resumed; it is recorded in an event on the resource(s).
+	Reason string
 }
 
 // RolloutUndoOptions carries the options supported by RolloutUndo.
@@ -85,6 +92,32 @@ type RolloutUndoOptions struct {
 	ToRevision int64
 }
 
+// RolloutHistoryOptions carries the options supported by RolloutHistory.
+type RolloutHistoryOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Resources for the rollout command
+	Resources []string
+
+	// Namespace where the resource(s) live. If unspecified, the namespace name will be inferred
+	// from the current configuration.
+	Namespace string
+}
+
+// RolloutRevision describes a revision of a cluster-api resource.
+type RolloutRevision = alpha.RolloutRevision
+
+// RolloutHistory defines the rollout history of a cluster-api resource.
+type RolloutHistory struct {
+	// Object is the cluster-api resource.
+	Object corev1.ObjectReference
+
+	// Revisions of the cluster-api resource, sorted by revision.
+	Revisions []RolloutRevision
+}
+
 func (c *clusterctlClient) RolloutRestart(options RolloutRestartOptions) error {
 	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
@@ -112,7 +145,7 @@ func (c *clusterctlClient) RolloutPause(options RolloutPauseOptions) error {
 		return err
 	}
 	for _, ref := range objRefs {
-		if err := c.alphaClient.Rollout().ObjectPauser(clusterClient.Proxy(), ref); err != nil {
+		if err := c.alphaClient.Rollout().ObjectPauser(clusterClient.Proxy(), ref, options.Reason); err != nil {
 			return err
 		}
 	}
@@ -129,7 +162,7 @@ func (c *clusterctlClient) RolloutResume(options RolloutResumeOptions) error {
 		return err
 	}
 	for _, ref := range objRefs {
-		if err := c.alphaClient.Rollout().ObjectResumer(clusterClient.Proxy(), ref); err != nil {
+		if err := c.alphaClient.Rollout().ObjectResumer(clusterClient.Proxy(), ref, options.Reason); err != nil {
 			return err
 		}
 	}
@@ -153,6 +186,26 @@ func (c *clusterctlClient) RolloutUndo(options RolloutUndoOptions) error {
 	return nil
 }
 
+func (c *clusterctlClient) RolloutHistory(options RolloutHistoryOptions) ([]RolloutHistory, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+	objRefs, err := getObjectRefs(clusterClient, options.Namespace, options.Resources)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]RolloutHistory, 0, len(objRefs))
+	for _, ref := range objRefs {
+		revisions, err := c.alphaClient.Rollout().ObjectHistory(clusterClient.Proxy(), ref)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, RolloutHistory{Object: ref, Revisions: revisions})
+	}
+	return history, nil
+}
+
 func getObjectRefs(clusterClient cluster.Client, namespace string, resources []string) ([]corev1.ObjectReference, error) {
 	// If the option specifying the Namespace is empty, try to detect it.
 	if namespace == "" {
@@ -0,0 +1,453 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/drone/envsubst/v2"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+	"sigs.k8s.io/cluster-api/util/version"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+// LintSeverity is the severity of a LintFinding.
+type LintSeverity string
+
+const (
+	// LintSeverityError identifies issues that prevent the template from being used.
+	LintSeverityError LintSeverity = "error"
+
+	// LintSeverityWarning identifies issues that are likely to cause problems when using the template.
+	LintSeverityWarning LintSeverity = "warning"
+
+	// LintSeverityInfo identifies details worth checking when using the template.
+	LintSeverityInfo LintSeverity = "info"
+)
+
+// Rules checked by LintTemplate.
+const (
+	// LintRuleInvalidTemplate reports templates that cannot be parsed.
+	LintRuleInvalidTemplate = "invalid-template"
+
+	// LintRuleUnknownVariable reports variables without a value in the clusterctl config or in the environment.
+	LintRuleUnknownVariable = "unknown-variable"
+
+	// LintRuleDeprecatedAPIVersion reports objects using deprecated Cluster API versions.
+	LintRuleDeprecatedAPIVersion = "deprecated-api-version"
+
+	// LintRuleMissingRequiredField reports objects missing fields required by the Cluster API contract.
+	LintRuleMissingRequiredField = "missing-required-field"
+
+	// LintRuleDanglingReference reports references to objects not included in the template.
+	LintRuleDanglingReference = "dangling-reference"
+
+	// LintRuleVersionMismatch reports MachineDeployments with a version not compatible with the control plane.
+	LintRuleVersionMismatch = "version-mismatch"
+
+	// LintRulePlaintextSecret reports credentials embedded in plaintext in the template.
+	LintRulePlaintextSecret = "plaintext-secret"
+)
+
+// maxKubeletVersionSkew is the maximum number of minor versions kubelets can be older than the control plane.
+const maxKubeletVersionSkew = 2
+
+// deprecatedAPIVersions are the Cluster API versions deprecated in favor of v1beta1.
+var deprecatedAPIVersions = sets.New[string]("v1alpha3", "v1alpha4")
+
+// clusterAPIGroups are the API groups of the objects provided by Cluster API.
+var clusterAPIGroups = sets.New[string](
+	clusterv1.GroupVersion.Group,
+	"bootstrap."+clusterv1.GroupVersion.Group,
+	"controlplane."+clusterv1.GroupVersion.Group,
+	"addons."+clusterv1.GroupVersion.Group,
+)
+
+// LintTemplateOptions carries the options supported by LintTemplate.
+type LintTemplateOptions struct {
+	// ReaderSource to be used for reading the template.
+	ReaderSource *ReaderSourceOptions
+}
+
+// LintFinding is an issue found in a template.
+type LintFinding struct {
+	// Severity of the finding.
+	Severity LintSeverity `json:"severity"`
+
+	// Rule that generated the finding.
+	Rule string `json:"rule"`
+
+	// Object the finding refers to, in the Kind/name format; empty for findings about the whole template.
+	Object string `json:"object,omitempty"`
+
+	// Field the finding refers to, if any.
+	Field string `json:"field,omitempty"`
+
+	// Message describing the finding.
+	Message string `json:"message"`
+}
+
+// LintTemplateOutput defines the output of the template lint operation.
+type LintTemplateOutput struct {
+	// Findings is the list of issues found in the template, sorted by severity.
+	Findings []LintFinding `json:"findings"`
+}
+
+// HasFindings returns true if there is at least a finding with the given severity or a higher one.
+func (o *LintTemplateOutput) HasFindings(severity LintSeverity) bool {
+	for _, f := range o.Findings {
+		if severityRank(f.Severity) <= severityRank(severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// LintTemplate statically checks a cluster template for common issues.
+// NOTE: This operation does not require access to a management cluster nor to provider repositories.
+func (c *clusterctlClient) LintTemplate(options LintTemplateOptions) (*LintTemplateOutput, error) {
+	if options.ReaderSource == nil {
+		return nil, errors.New("unable to read the template. Please specify a template source")
+	}
+
+	// NOTE: Beware of potentially reading in large files all at once
+	// since this is inefficient and increases memory utilziation.
+	rawTemplate, err := io.ReadAll(options.ReaderSource.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the template")
+	}
+
+	return lintTemplate(rawTemplate, c.configClient.Variables()), nil
+}
+
+// templateLinter checks the objects of a template.
+type templateLinter struct {
+	variables config.VariablesClient
+	defaults  map[string]*string
+	objs      []unstructured.Unstructured
+	findings  []LintFinding
+}
+
+func lintTemplate(rawTemplate []byte, variables config.VariablesClient) *LintTemplateOutput {
+	l := &templateLinter{variables: variables}
+
+	defaults, err := yaml.NewSimpleProcessor().GetVariableMap(rawTemplate)
+	if err != nil {
+		l.addFinding(LintSeverityError, LintRuleInvalidTemplate, nil, "", fmt.Sprintf("failed to parse the variables of the template: %v", err))
+		return l.output()
+	}
+	l.defaults = defaults
+	l.lintVariables()
+
+	// NOTE: Objects are checked before variable substitution, so it is possible to detect e.g.
+	// secrets embedded in plaintext instead of being provided using variables.
+	objs, err := utilyaml.ToUnstructured(rawTemplate)
+	if err != nil {
+		l.addFinding(LintSeverityError, LintRuleInvalidTemplate, nil, "", fmt.Sprintf("failed to parse the template: %v", err))
+		return l.output()
+	}
+	l.objs = objs
+
+	for i := range l.objs {
+		obj := &l.objs[i]
+		l.lintAPIVersion(obj)
+		l.lintRequiredFields(obj)
+		l.lintReferences(obj)
+		l.lintSecrets(obj)
+	}
+	l.lintVersions()
+
+	return l.output()
+}
+
+func (l *templateLinter) lintVariables() {
+	names := make([]string, 0, len(l.defaults))
+	for name := range l.defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := l.variables.Get(name); err == nil {
+			continue
+		}
+		if l.defaults[name] == nil {
+			l.addFinding(LintSeverityWarning, LintRuleUnknownVariable, nil, "",
+				fmt.Sprintf("variable %s is not set in the clusterctl config nor in the environment and it does not have a default value", name))
+			continue
+		}
+		l.addFinding(LintSeverityInfo, LintRuleUnknownVariable, nil, "",
+			fmt.Sprintf("variable %s is not set, the default value %q will be used", name, *l.defaults[name]))
+	}
+}
+
+func (l *templateLinter) lintAPIVersion(obj *unstructured.Unstructured) {
+	gv, err := schema.ParseGroupVersion(obj.GetAPIVersion())
+	if err != nil {
+		l.addFinding(LintSeverityError, LintRuleInvalidTemplate, obj, "apiVersion", fmt.Sprintf("invalid apiVersion: %v", err))
+		return
+	}
+	if !clusterAPIGroups.Has(gv.Group) || !deprecatedAPIVersions.Has(gv.Version) {
+		return
+	}
+	l.addFinding(LintSeverityWarning, LintRuleDeprecatedAPIVersion, obj, "apiVersion",
+		fmt.Sprintf("%s is deprecated, use %s/%s instead", gv.String(), gv.Group, clusterv1.GroupVersion.Version))
+}
+
+func (l *templateLinter) lintRequiredFields(obj *unstructured.Unstructured) {
+	var required [][]string
+	switch obj.GetKind() {
+	case "Cluster":
+		if _, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "topology"); ok {
+			required = [][]string{{"spec", "topology", "class"}, {"spec", "topology", "version"}}
+		} else {
+			required = [][]string{{"spec", "infrastructureRef"}}
+		}
+	case "KubeadmControlPlane":
+		required = [][]string{{"spec", "version"}, {"spec", "machineTemplate", "infrastructureRef"}}
+	case "MachineDeployment", "MachineSet":
+		required = [][]string{{"spec", "clusterName"}, {"spec", "template", "spec", "clusterName"}, {"spec", "template", "spec", "infrastructureRef"}}
+		l.lintBootstrap(obj, "spec", "template", "spec", "bootstrap")
+	case "Machine":
+		required = [][]string{{"spec", "clusterName"}, {"spec", "infrastructureRef"}}
+		l.lintBootstrap(obj, "spec", "bootstrap")
+	}
+
+	for _, fields := range required {
+		if _, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, fields...); !ok {
+			l.addFinding(LintSeverityError, LintRuleMissingRequiredField, obj, strings.Join(fields, "."), "required field is not set")
+		}
+	}
+}
+
+func (l *templateLinter) lintBootstrap(obj *unstructured.Unstructured, fields ...string) {
+	_, hasConfigRef, _ := unstructured.NestedFieldNoCopy(obj.Object, append(fields, "configRef")...)
+	_, hasDataSecretName, _ := unstructured.NestedFieldNoCopy(obj.Object, append(fields, "dataSecretName")...)
+	if !hasConfigRef && !hasDataSecretName {
+		l.addFinding(LintSeverityError, LintRuleMissingRequiredField, obj, strings.Join(fields, "."), "one of configRef or dataSecretName must be set")
+	}
+}
+
+func (l *templateLinter) lintReferences(obj *unstructured.Unstructured) {
+	var refs [][]string
+	switch obj.GetKind() {
+	case "Cluster":
+		refs = [][]string{{"spec", "infrastructureRef"}, {"spec", "controlPlaneRef"}}
+	case "KubeadmControlPlane":
+		refs = [][]string{{"spec", "machineTemplate", "infrastructureRef"}}
+	case "MachineDeployment", "MachineSet":
+		refs = [][]string{{"spec", "template", "spec", "infrastructureRef"}, {"spec", "template", "spec", "bootstrap", "configRef"}}
+	case "Machine":
+		refs = [][]string{{"spec", "infrastructureRef"}, {"spec", "bootstrap", "configRef"}}
+	}
+
+	for _, fields := range refs {
+		ref, ok, _ := unstructured.NestedStringMap(obj.Object, fields...)
+		if !ok {
+			continue
+		}
+		if l.findObject(ref["kind"], ref["name"]) == nil {
+			l.addFinding(LintSeverityWarning, LintRuleDanglingReference, obj, strings.Join(fields, "."),
+				fmt.Sprintf("referenced object %s/%s is not defined in the template", ref["kind"], ref["name"]))
+		}
+	}
+}
+
+func (l *templateLinter) lintSecrets(obj *unstructured.Unstructured) {
+	if obj.GetKind() == "Secret" && obj.GetAPIVersion() == "v1" {
+		// Secrets used by ClusterResourceSets carry manifests to be applied to the workload cluster, not credentials.
+		if secretType, _, _ := unstructured.NestedString(obj.Object, "type"); secretType != "addons.cluster.x-k8s.io/resource-set" {
+			for _, field := range []string{"data", "stringData"} {
+				data, _, _ := unstructured.NestedStringMap(obj.Object, field)
+				keys := make([]string, 0, len(data))
+				for k := range data {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					if !strings.Contains(data[k], "${") {
+						l.addFinding(LintSeverityWarning, LintRulePlaintextSecret, obj, field+"."+k,
+							"secret value is embedded in plaintext, use a variable instead")
+					}
+				}
+			}
+		}
+	}
+
+	walkStrings(obj.Object, nil, func(fields []string, value string) {
+		if strings.Contains(value, "PRIVATE KEY-----") {
+			l.addFinding(LintSeverityError, LintRulePlaintextSecret, obj, strings.Join(fields, "."),
+				"a private key is embedded in plaintext, use a variable instead")
+		}
+	})
+}
+
+// lintVersions checks that the versions of the MachineDeployments are compatible with the version of
+// the KubeadmControlPlane of the same Cluster, according to the Kubernetes version skew policy.
+func (l *templateLinter) lintVersions() {
+	controlPlaneVersions := map[string]string{}
+	for i := range l.objs {
+		cluster := &l.objs[i]
+		if cluster.GetKind() != "Cluster" {
+			continue
+		}
+		ref, ok, _ := unstructured.NestedStringMap(cluster.Object, "spec", "controlPlaneRef")
+		if !ok {
+			continue
+		}
+		controlPlane := l.findObject(ref["kind"], ref["name"])
+		if controlPlane == nil || controlPlane.GetKind() != "KubeadmControlPlane" {
+			continue
+		}
+		if v, ok, _ := unstructured.NestedString(controlPlane.Object, "spec", "version"); ok {
+			controlPlaneVersions[cluster.GetName()] = v
+		}
+	}
+
+	for i := range l.objs {
+		md := &l.objs[i]
+		if md.GetKind() != "MachineDeployment" {
+			continue
+		}
+		clusterName, _, _ := unstructured.NestedString(md.Object, "spec", "clusterName")
+		rawControlPlaneVersion, ok := controlPlaneVersions[clusterName]
+		if !ok {
+			continue
+		}
+		rawMachineVersion, ok, _ := unstructured.NestedString(md.Object, "spec", "template", "spec", "version")
+		if !ok || rawMachineVersion == rawControlPlaneVersion {
+			continue
+		}
+
+		controlPlaneVersion, controlPlaneResolved := l.resolve(rawControlPlaneVersion)
+		machineVersion, machineResolved := l.resolve(rawMachineVersion)
+		if !controlPlaneResolved || !machineResolved {
+			l.addFinding(LintSeverityInfo, LintRuleVersionMismatch, md, "spec.template.spec.version",
+				fmt.Sprintf("version %s cannot be compared with the control plane version %s because of unset variables", rawMachineVersion, rawControlPlaneVersion))
+			continue
+		}
+		cpv, err := version.ParseMajorMinorPatchTolerant(controlPlaneVersion)
+		if err != nil {
+			continue
+		}
+		mv, err := version.ParseMajorMinorPatchTolerant(machineVersion)
+		if err != nil {
+			l.addFinding(LintSeverityError, LintRuleVersionMismatch, md, "spec.template.spec.version", fmt.Sprintf("version %q is not a valid semantic version", machineVersion))
+			continue
+		}
+
+		switch {
+		case mv.GT(cpv):
+			l.addFinding(LintSeverityError, LintRuleVersionMismatch, md, "spec.template.spec.version",
+				fmt.Sprintf("version %s is newer than the control plane version %s", machineVersion, controlPlaneVersion))
+		case cpv.Major != mv.Major || cpv.Minor-mv.Minor > maxKubeletVersionSkew:
+			l.addFinding(LintSeverityWarning, LintRuleVersionMismatch, md, "spec.template.spec.version",
+				fmt.Sprintf("version %s is more than %d minor versions older than the control plane version %s", machineVersion, maxKubeletVersionSkew, controlPlaneVersion))
+		}
+	}
+}
+
+// resolve replaces the variables in value using the clusterctl config or the default values;
+// it returns false if some of the variables do not have a value.
+func (l *templateLinter) resolve(value string) (string, bool) {
+	resolved := true
+	out, err := envsubst.Eval(value, func(name string) string {
+		if v, err := l.variables.Get(name); err == nil {
+			return v
+		}
+		if l.defaults[name] == nil {
+			resolved = false
+		}
+		return ""
+	})
+	if err != nil {
+		return "", false
+	}
+	return out, resolved
+}
+
+func (l *templateLinter) findObject(kind, name string) *unstructured.Unstructured {
+	for i := range l.objs {
+		if l.objs[i].GetKind() == kind && l.objs[i].GetName() == name {
+			return &l.objs[i]
+		}
+	}
+	return nil
+}
+
+func (l *templateLinter) addFinding(severity LintSeverity, rule string, obj *unstructured.Unstructured, field, message string) {
+	finding := LintFinding{
+		Severity: severity,
+		Rule:     rule,
+		Field:    field,
+		Message:  message,
+	}
+	if obj != nil {
+		finding.Object = fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+	}
+	l.findings = append(l.findings, finding)
+}
+
+func (l *templateLinter) output() *LintTemplateOutput {
+	findings := append([]LintFinding{}, l.findings...)
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityRank(findings[i].Severity) < severityRank(findings[j].Severity)
+	})
+	return &LintTemplateOutput{Findings: findings}
+}
+
+func severityRank(severity LintSeverity) int {
+	switch severity {
+	case LintSeverityError:
+		return 0
+	case LintSeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// walkStrings calls f for all the string values nested in obj.
+func walkStrings(obj interface{}, fields []string, f func(fields []string, value string)) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walkStrings(v[k], append(fields, k), f)
+		}
+	case []interface{}:
+		for i := range v {
+			walkStrings(v[i], append(fields, fmt.Sprintf("%d", i)), f)
+		}
+	case string:
+		f(fields, v)
+	}
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package client
 
 import (
+	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
@@ -60,3 +62,105 @@ func (c *clusterctlClient) TopologyPlan(options TopologyPlanOptions) (*TopologyP
 
 	return out, err
 }
+
+// TopologyRebaseOptions define options for TopologyRebase.
+type TopologyRebaseOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the Clusters are located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// ClusterNames is the list of the Clusters to rebase; it can't be used together with Selector.
+	ClusterNames []string
+
+	// Selector is a label selector for the Clusters to rebase, e.g. "fleet-wave=1".
+	Selector string
+
+	// ToClass is the name of the ClusterClass to rebase the Clusters to. If empty, Clusters are rebased
+	// to the latest revision of the lineage of their current ClusterClass.
+	ToClass string
+
+	// DryRun validates the rebase without changing the Clusters.
+	DryRun bool
+
+	// AllowDowngrade allows rebasing Clusters to an older revision of the lineage of their current ClusterClass.
+	AllowDowngrade bool
+}
+
+// TopologyRebaseOutput defines the output of the topology rebase operation.
+type TopologyRebaseOutput = cluster.TopologyRebaseOutput
+
+// TopologyRebase changes the ClusterClass of Clusters with a managed topology to a newer revision.
+func (c *clusterctlClient) TopologyRebase(options TopologyRebaseOptions) (*TopologyRebaseOutput, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// If the option specifying the Namespace is empty, default it.
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	selector := labels.Everything()
+	if options.Selector != "" {
+		selector, err = labels.Parse(options.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid selector %q", options.Selector)
+		}
+	}
+
+	return clusterClient.Topology().Rebase(&cluster.TopologyRebaseInput{
+		Namespace:      options.Namespace,
+		ClusterNames:   options.ClusterNames,
+		Selector:       selector,
+		ToClass:        options.ToClass,
+		DryRun:         options.DryRun,
+		AllowDowngrade: options.AllowDowngrade,
+	})
+}
+
+// TopologyOwnershipOptions define options for TopologyOwnership.
+type TopologyOwnershipOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the Cluster is located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// ClusterName is the name of the Cluster to report the field ownership for.
+	ClusterName string
+}
+
+// TopologyOwnershipOutput defines the output of the topology ownership operation.
+type TopologyOwnershipOutput = cluster.TopologyOwnershipOutput
+
+// TopologyOwnership reports the server side apply field ownership for the objects managed by the topology
+// controller for a Cluster, and flags the fields co-owned by the topology controller and other field managers.
+func (c *clusterctlClient) TopologyOwnership(options TopologyOwnershipOptions) (*TopologyOwnershipOutput, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// If the option specifying the Namespace is empty, default it.
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	return clusterClient.Topology().Ownership(&cluster.TopologyOwnershipInput{
+		Namespace:   options.Namespace,
+		ClusterName: options.ClusterName,
+	})
+}
@@ -50,5 +50,8 @@ a tree of Kubernetes objects; each object gets a set of annotation, reflecting i
 e.g is virtual node, is group node, meta name etc.
 
 The Discovery object uses the ObjectTree to build the "at glance" view of a Cluster API.
+
+The ObjectNode type provides a machine-readable representation of the ObjectTree, including conditions,
+versions and ownership, that can be serialized to JSON or YAML.
 */
 package tree
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ObjectNode is a machine-readable representation of an object in the ObjectTree, including
+// its children, so the "at glance" view can be consumed by UIs and scripts.
+type ObjectNode struct {
+	// APIVersion of the object; virtual objects use the virtual.cluster.x-k8s.io group.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the object, e.g. Machine; group objects use the kind of the grouped objects
+	// with the Group suffix, e.g. MachineGroup.
+	Kind string `json:"kind"`
+
+	// Name of the object.
+	Name string `json:"name"`
+
+	// Namespace of the object.
+	Namespace string `json:"namespace,omitempty"`
+
+	// UID of the object.
+	UID types.UID `json:"uid"`
+
+	// MetaName is the name used for the object in the presentation layer, if any, e.g. ControlPlane.
+	MetaName string `json:"metaName,omitempty"`
+
+	// Virtual is true if the object does not correspond to any real object, e.g. Workers.
+	Virtual bool `json:"virtual,omitempty"`
+
+	// GroupItems is the list of the names of the objects included in a group object.
+	GroupItems []string `json:"groupItems,omitempty"`
+
+	// Version is the Kubernetes version of the object, if any, e.g. the version of a Machine.
+	Version string `json:"version,omitempty"`
+
+	// DeletionTimestamp is set if the object is being deleted.
+	DeletionTimestamp *metav1.Time `json:"deletionTimestamp,omitempty"`
+
+	// OwnerReferences of the object.
+	OwnerReferences []metav1.OwnerReference `json:"ownerReferences,omitempty"`
+
+	// Ready is the ready condition of the object, if any.
+	Ready *clusterv1.Condition `json:"ready,omitempty"`
+
+	// Conditions of the object except the ready condition.
+	Conditions []clusterv1.Condition `json:"conditions,omitempty"`
+
+	// Children of the object in the ObjectTree.
+	Children []ObjectNode `json:"children,omitempty"`
+}
+
+// ToObjectNode returns the machine-readable representation of the ObjectTree, starting from its root.
+// Children are sorted using the same ordering of the text representation, i.e. by z-order from highest to lowest,
+// and then by kind and name.
+func (od ObjectTree) ToObjectNode() ObjectNode {
+	return od.toObjectNode(od.GetRoot())
+}
+
+func (od ObjectTree) toObjectNode(obj client.Object) ObjectNode {
+	node := ObjectNode{
+		APIVersion:        obj.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+		Kind:              obj.GetObjectKind().GroupVersionKind().Kind,
+		Name:              obj.GetName(),
+		Namespace:         obj.GetNamespace(),
+		UID:               obj.GetUID(),
+		MetaName:          GetMetaName(obj),
+		Virtual:           IsVirtualObject(obj),
+		Version:           getVersion(obj),
+		DeletionTimestamp: obj.GetDeletionTimestamp(),
+		OwnerReferences:   obj.GetOwnerReferences(),
+		Ready:             GetReadyCondition(obj),
+	}
+
+	if IsGroupObject(obj) {
+		node.GroupItems = strings.Split(GetGroupItems(obj), GroupItemsSeparator)
+	}
+
+	for _, c := range GetOtherConditions(obj) {
+		node.Conditions = append(node.Conditions, *c)
+	}
+
+	children := od.GetObjectsByParent(obj.GetUID())
+	sort.Slice(children, func(i, j int) bool {
+		if GetZOrder(children[i]) != GetZOrder(children[j]) {
+			return GetZOrder(children[i]) > GetZOrder(children[j])
+		}
+		if children[i].GetObjectKind().GroupVersionKind().Kind != children[j].GetObjectKind().GroupVersionKind().Kind {
+			return children[i].GetObjectKind().GroupVersionKind().Kind < children[j].GetObjectKind().GroupVersionKind().Kind
+		}
+		return children[i].GetName() < children[j].GetName()
+	})
+	for _, child := range children {
+		node.Children = append(node.Children, od.toObjectNode(child))
+	}
+
+	return node
+}
+
+// getVersion returns the Kubernetes version of an object, if any, reading spec.version or,
+// for managed topologies, spec.topology.version.
+func getVersion(obj client.Object) string {
+	var content map[string]interface{}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		content = u.Object
+	} else {
+		var err error
+		content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return ""
+		}
+	}
+
+	if version, ok, _ := unstructured.NestedString(content, "spec", "version"); ok {
+		return version
+	}
+	if version, ok, _ := unstructured.NestedString(content, "spec", "topology", "version"); ok {
+		return version
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ToObjectNode(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := fakeCluster("my-cluster",
+		withClusterCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+		withClusterCondition(conditions.FalseCondition(clusterv1.InfrastructureReadyCondition, "Provisioning", clusterv1.ConditionSeverityInfo, "")),
+	)
+	cluster.Spec.Topology = &clusterv1.Topology{Version: "v1.27.0"}
+
+	tree := NewObjectTree(cluster, ObjectTreeOptions{Grouping: true})
+
+	workers := VirtualObject("ns", "WorkerGroup", "Workers")
+	tree.Add(cluster, workers, GroupingObject(true))
+
+	machine := fakeMachine("my-machine",
+		withMachineCondition(conditions.FalseCondition(clusterv1.ReadyCondition, "Bootstrapping", clusterv1.ConditionSeverityWarning, "")),
+	)
+	machine.Spec.Version = pointer.String("v1.27.0")
+	machine.OwnerReferences = []metav1.OwnerReference{{Kind: "Cluster", Name: cluster.Name, UID: cluster.UID}}
+	tree.Add(workers, machine, ObjectMetaName("Worker"))
+
+	for _, name := range []string{"machine-a", "machine-b"} {
+		tree.Add(workers, fakeMachine(name,
+			withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+		))
+	}
+
+	controlPlane := fakeMachine("control-plane", withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition)))
+	tree.Add(cluster, controlPlane, ZOrder(1))
+
+	node := tree.ToObjectNode()
+
+	g.Expect(node.Kind).To(Equal("Cluster"))
+	g.Expect(node.Name).To(Equal("my-cluster"))
+	g.Expect(node.Version).To(Equal("v1.27.0"))
+	g.Expect(node.Ready).ToNot(BeNil())
+	g.Expect(node.Ready.Type).To(Equal(clusterv1.ReadyCondition))
+	g.Expect(node.Conditions).To(HaveLen(1))
+	g.Expect(node.Conditions[0].Type).To(Equal(clusterv1.InfrastructureReadyCondition))
+
+	// Children are sorted by z-order, then by kind and name.
+	g.Expect(node.Children).To(HaveLen(2))
+	g.Expect(node.Children[0].Name).To(Equal("control-plane"))
+	g.Expect(node.Children[1].Name).To(Equal("Workers"))
+	g.Expect(node.Children[1].Virtual).To(BeTrue())
+
+	workersNode := node.Children[1]
+	g.Expect(workersNode.Children).To(HaveLen(2))
+
+	groupNode := workersNode.Children[1]
+	g.Expect(groupNode.Kind).To(Equal("MachineGroup"))
+	g.Expect(groupNode.Virtual).To(BeTrue())
+	g.Expect(groupNode.GroupItems).To(Equal([]string{"machine-a", "machine-b"}))
+
+	machineNode := workersNode.Children[0]
+	g.Expect(machineNode.Kind).To(Equal("Machine"))
+	g.Expect(machineNode.Name).To(Equal("my-machine"))
+	g.Expect(machineNode.MetaName).To(Equal("Worker"))
+	g.Expect(machineNode.Version).To(Equal("v1.27.0"))
+	g.Expect(machineNode.OwnerReferences).To(HaveLen(1))
+	g.Expect(machineNode.Ready.Reason).To(Equal("Bootstrapping"))
+	g.Expect(machineNode.Children).To(BeEmpty())
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// ClusterSummary provides a summary of the status of a Cluster API cluster.
+type ClusterSummary struct {
+	// Namespace of the Cluster.
+	Namespace string
+
+	// Name of the Cluster.
+	Name string
+
+	// Phase of the Cluster.
+	Phase string
+
+	// Ready is the status of the Cluster's Ready condition, Unknown if not yet reported.
+	Ready corev1.ConditionStatus
+
+	// Version is the Kubernetes version of the Cluster, read from the Cluster topology or from the control plane object.
+	Version string
+
+	// ControlPlaneReady is the number of control plane Machines with the Ready condition set to true.
+	ControlPlaneReady int32
+
+	// ControlPlaneTotal is the number of control plane Machines.
+	ControlPlaneTotal int32
+
+	// WorkersReady is the number of worker Machines with the Ready condition set to true,
+	// plus the ready replicas of MachinePools.
+	WorkersReady int32
+
+	// WorkersTotal is the number of worker Machines, plus the replicas of MachinePools.
+	WorkersTotal int32
+
+	// InfrastructureProvider is the kind of the Cluster infrastructure object.
+	InfrastructureProvider string
+
+	// CreationTimestamp of the Cluster.
+	CreationTimestamp metav1.Time
+}
+
+// DiscoverClusters returns a summary of the status of all the Cluster API clusters in a namespace
+// (or in all the namespaces if empty), sorted by namespace and name.
+func DiscoverClusters(ctx context.Context, c client.Client, namespace string) ([]ClusterSummary, error) {
+	clusterList := &clusterv1.ClusterList{}
+	if err := c.List(ctx, clusterList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]ClusterSummary, 0, len(clusterList.Items))
+	for i := range clusterList.Items {
+		summary, err := discoverClusterSummary(ctx, c, &clusterList.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Namespace != summaries[j].Namespace {
+			return summaries[i].Namespace < summaries[j].Namespace
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries, nil
+}
+
+func discoverClusterSummary(ctx context.Context, c client.Client, cluster *clusterv1.Cluster) (ClusterSummary, error) {
+	summary := ClusterSummary{
+		Namespace:         cluster.Namespace,
+		Name:              cluster.Name,
+		Phase:             cluster.Status.Phase,
+		Ready:             corev1.ConditionUnknown,
+		CreationTimestamp: cluster.CreationTimestamp,
+	}
+
+	if readyCondition := conditions.Get(cluster, clusterv1.ReadyCondition); readyCondition != nil {
+		summary.Ready = readyCondition.Status
+	}
+
+	if cluster.Spec.InfrastructureRef != nil {
+		summary.InfrastructureProvider = cluster.Spec.InfrastructureRef.Kind
+	}
+
+	if cluster.Spec.Topology != nil {
+		summary.Version = cluster.Spec.Topology.Version
+	} else if cluster.Spec.ControlPlaneRef != nil {
+		controlPlane, err := external.Get(ctx, c, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return ClusterSummary{}, errors.Wrapf(err, "failed to get the control plane of Cluster %s", klog.KObj(cluster))
+		}
+		// Use spec fields guaranteed in contract; the version is left empty if the control plane does not exist yet.
+		if err == nil {
+			if version, found, err := unstructured.NestedString(controlPlane.UnstructuredContent(), "spec", "version"); err == nil && found {
+				summary.Version = version
+			}
+		}
+	}
+
+	machinesList, err := getMachinesInCluster(ctx, c, cluster.Namespace, cluster.Name)
+	if err != nil {
+		return ClusterSummary{}, err
+	}
+	controlPlaneMachines := selectControlPlaneMachines(machinesList)
+	controlPlaneMachineNames := map[string]bool{}
+	for _, m := range controlPlaneMachines {
+		controlPlaneMachineNames[m.Name] = true
+		summary.ControlPlaneTotal++
+		if conditions.IsTrue(m, clusterv1.ReadyCondition) {
+			summary.ControlPlaneReady++
+		}
+	}
+	for i := range machinesList.Items {
+		m := &machinesList.Items[i]
+		if controlPlaneMachineNames[m.Name] {
+			continue
+		}
+		summary.WorkersTotal++
+		if conditions.IsTrue(m, clusterv1.ReadyCondition) {
+			summary.WorkersReady++
+		}
+	}
+
+	machinePoolList, err := getMachinePoolsInCluster(ctx, c, cluster.Namespace, cluster.Name)
+	if err != nil {
+		return ClusterSummary{}, err
+	}
+	for i := range machinePoolList.Items {
+		mp := &machinePoolList.Items[i]
+		summary.WorkersTotal += mp.Status.Replicas
+		summary.WorkersReady += mp.Status.ReadyReplicas
+	}
+
+	return summary, nil
+}
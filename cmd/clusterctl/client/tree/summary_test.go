@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_DiscoverClusters(t *testing.T) {
+	cluster1Objs := test.NewFakeCluster("ns1", "cluster1").
+		WithControlPlane(
+			test.NewFakeControlPlane("cp").
+				WithMachines(
+					test.NewFakeMachine("cp1"),
+				),
+		).
+		WithMachineDeployments(
+			test.NewFakeMachineDeployment("md1").
+				WithMachineSets(
+					test.NewFakeMachineSet("ms1").
+						WithMachines(
+							test.NewFakeMachine("m1"),
+							test.NewFakeMachine("m2"),
+						),
+				),
+		).
+		Objs()
+	cluster2Objs := test.NewFakeCluster("ns2", "cluster2").Objs()
+	// cluster3 references a control plane which does not exist yet.
+	cluster3Objs := test.NewFakeCluster("ns3", "cluster3").Objs()
+
+	objs := append(cluster1Objs, cluster2Objs...)
+	objs = append(objs, cluster3Objs...)
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *clusterv1.Cluster:
+			if o.Name == "cluster1" {
+				o.Status.Phase = string(clusterv1.ClusterPhaseProvisioned)
+				conditions.MarkTrue(o, clusterv1.ReadyCondition)
+			}
+			if o.Name == "cluster3" {
+				o.Spec.ControlPlaneRef = &corev1.ObjectReference{
+					APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+					Kind:       "GenericControlPlane",
+					Name:       "cp",
+					Namespace:  "ns3",
+				}
+			}
+		case *clusterv1.Machine:
+			if o.Name == "cp1" || o.Name == "m1" {
+				conditions.MarkTrue(o, clusterv1.ReadyCondition)
+			}
+		}
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		want      []ClusterSummary
+	}{
+		{
+			name:      "Discover clusters in all the namespaces",
+			namespace: "",
+			want: []ClusterSummary{
+				{
+					Namespace:              "ns1",
+					Name:                   "cluster1",
+					Phase:                  string(clusterv1.ClusterPhaseProvisioned),
+					Ready:                  corev1.ConditionTrue,
+					ControlPlaneReady:      1,
+					ControlPlaneTotal:      1,
+					WorkersReady:           1,
+					WorkersTotal:           2,
+					InfrastructureProvider: "GenericInfrastructureCluster",
+				},
+				{
+					Namespace:              "ns2",
+					Name:                   "cluster2",
+					Ready:                  corev1.ConditionUnknown,
+					InfrastructureProvider: "GenericInfrastructureCluster",
+				},
+				{
+					Namespace:              "ns3",
+					Name:                   "cluster3",
+					Ready:                  corev1.ConditionUnknown,
+					InfrastructureProvider: "GenericInfrastructureCluster",
+				},
+			},
+		},
+		{
+			name:      "Discover clusters in a namespace",
+			namespace: "ns2",
+			want: []ClusterSummary{
+				{
+					Namespace:              "ns2",
+					Name:                   "cluster2",
+					Ready:                  corev1.ConditionUnknown,
+					InfrastructureProvider: "GenericInfrastructureCluster",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, err := test.NewFakeProxy().WithObjs(objs...).NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got, err := DiscoverClusters(context.TODO(), c, tt.namespace)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(HaveLen(len(tt.want)))
+			for i := range got {
+				// Ignore the creation timestamp set by the fake client.
+				got[i].CreationTimestamp = tt.want[i].CreationTimestamp
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
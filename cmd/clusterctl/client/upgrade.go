@@ -89,6 +89,39 @@ func (c *clusterctlClient) PlanUpgrade(options PlanUpgradeOptions) ([]UpgradePla
 	return aliasUpgradePlan, nil
 }
 
+// PlanUpgradePathOptions carries the options supported by upgrade plan when simulating an upgrade path.
+type PlanUpgradePathOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty, default discovery rules apply.
+	Kubeconfig Kubeconfig
+
+	// TargetVersion defines the version of the core provider (e.g. v1.4.0) the management cluster should be upgraded to.
+	TargetVersion string
+}
+
+func (c *clusterctlClient) PlanUpgradePath(options PlanUpgradePathOptions) (UpgradePath, error) {
+	if options.TargetVersion == "" {
+		return UpgradePath{}, errors.New("target version must be set")
+	}
+
+	// Get the client for interacting with the management cluster.
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return UpgradePath{}, err
+	}
+
+	// Ensures the custom resource definitions required by clusterctl are in place.
+	if err := clusterClient.ProviderInventory().EnsureCustomResourceDefinitions(); err != nil {
+		return UpgradePath{}, err
+	}
+
+	upgradePath, err := clusterClient.ProviderUpgrader().PlanPath(options.TargetVersion)
+	if err != nil {
+		return UpgradePath{}, err
+	}
+
+	return UpgradePath(*upgradePath), nil
+}
+
 // ApplyUpgradeOptions carries the options supported by upgrade apply.
 type ApplyUpgradeOptions struct {
 	// Kubeconfig to use for accessing the management cluster. If empty, default discovery rules apply.
@@ -125,18 +158,68 @@ type ApplyUpgradeOptions struct {
 	WaitProviders bool
 
 	// WaitProviderTimeout sets the timeout per provider upgrade.
+	// In case of staged upgrades, it sets the timeout for the verification of each provider.
 	WaitProviderTimeout time.Duration
 }
 
+// UpgradeResult is the result of a staged upgrade of the providers in a management cluster.
+type UpgradeResult = cluster.UpgradeResult
+
+// ProviderUpgradeResult is the result of the staged upgrade of a provider.
+type ProviderUpgradeResult = cluster.ProviderUpgradeResult
+
 func (c *clusterctlClient) ApplyUpgrade(options ApplyUpgradeOptions) error {
+	clusterClient, upgradeItems, err := c.prepareUpgrade(options)
+	if err != nil {
+		return err
+	}
+
+	opts := cluster.UpgradeOptions{
+		WaitProviders:       options.WaitProviders,
+		WaitProviderTimeout: options.WaitProviderTimeout,
+	}
+
+	// If we are upgrading a specific set of providers only, call ApplyCustomPlan.
+	if len(upgradeItems) > 0 {
+		return clusterClient.ProviderUpgrader().ApplyCustomPlan(opts, upgradeItems...)
+	}
+
+	// Otherwise we are upgrading a whole management cluster according to a clusterctl generated upgrade plan.
+	return clusterClient.ProviderUpgrader().ApplyPlan(opts, options.Contract)
+}
+
+func (c *clusterctlClient) ApplyStagedUpgrade(options ApplyUpgradeOptions) (*UpgradeResult, error) {
+	clusterClient, upgradeItems, err := c.prepareUpgrade(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: staged upgrades always wait for the providers, given that the providers are verified after each upgrade.
+	opts := cluster.UpgradeOptions{
+		WaitProviders:       true,
+		WaitProviderTimeout: options.WaitProviderTimeout,
+	}
+
+	// If we are upgrading a specific set of providers only, call ApplyStagedCustomPlan.
+	if len(upgradeItems) > 0 {
+		return clusterClient.ProviderUpgrader().ApplyStagedCustomPlan(opts, upgradeItems...)
+	}
+
+	// Otherwise we are upgrading a whole management cluster according to a clusterctl generated upgrade plan.
+	return clusterClient.ProviderUpgrader().ApplyStagedPlan(opts, options.Contract)
+}
+
+// prepareUpgrade validates the management cluster and the upgrade options, and returns the cluster client
+// and the UpgradeItems for a custom upgrade, if any.
+func (c *clusterctlClient) prepareUpgrade(options ApplyUpgradeOptions) (cluster.Client, []cluster.UpgradeItem, error) {
 	if options.Contract != "" && options.Contract != clusterv1.GroupVersion.Version {
-		return errors.Errorf("current version of clusterctl could only upgrade to %s contract, requested %s", clusterv1.GroupVersion.Version, options.Contract)
+		return nil, nil, errors.Errorf("current version of clusterctl could only upgrade to %s contract, requested %s", clusterv1.GroupVersion.Version, options.Contract)
 	}
 
 	// Get the client for interacting with the management cluster.
 	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Ensure this command only runs against management clusters with the current Cluster API contract (default) or the previous one.
@@ -146,12 +229,12 @@ func (c *clusterctlClient) ApplyUpgrade(options ApplyUpgradeOptions) error {
 		cluster.AllowCAPIContract{Contract: clusterv1alpha3.GroupVersion.Version},
 		cluster.AllowCAPIContract{Contract: clusterv1alpha4.GroupVersion.Version},
 	); err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Ensures the custom resource definitions required by clusterctl are in place.
 	if err := clusterClient.ProviderInventory().EnsureCustomResourceDefinitions(); err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Ensures the latest version of cert-manager.
@@ -160,7 +243,7 @@ func (c *clusterctlClient) ApplyUpgrade(options ApplyUpgradeOptions) error {
 	// should continue to work with the latest cert-manager.
 	certManager := clusterClient.CertManager()
 	if err := certManager.EnsureLatestVersion(); err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Check if the user want a custom upgrade
@@ -171,49 +254,39 @@ func (c *clusterctlClient) ApplyUpgrade(options ApplyUpgradeOptions) error {
 		len(options.IPAMProviders) > 0 ||
 		len(options.RuntimeExtensionProviders) > 0
 
-	opts := cluster.UpgradeOptions{
-		WaitProviders:       options.WaitProviders,
-		WaitProviderTimeout: options.WaitProviderTimeout,
-	}
-
-	// If we are upgrading a specific set of providers only, process the providers and call ApplyCustomPlan.
+	// If we are upgrading a specific set of providers only, process the providers.
+	upgradeItems := []cluster.UpgradeItem{}
 	if isCustomUpgrade {
 		// Converts upgrade references back into an UpgradeItem.
-		upgradeItems := []cluster.UpgradeItem{}
-
 		if options.CoreProvider != "" {
 			upgradeItems, err = addUpgradeItems(clusterClient, upgradeItems, clusterctlv1.CoreProviderType, options.CoreProvider)
 			if err != nil {
-				return err
+				return nil, nil, err
 			}
 		}
 		upgradeItems, err = addUpgradeItems(clusterClient, upgradeItems, clusterctlv1.BootstrapProviderType, options.BootstrapProviders...)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		upgradeItems, err = addUpgradeItems(clusterClient, upgradeItems, clusterctlv1.ControlPlaneProviderType, options.ControlPlaneProviders...)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		upgradeItems, err = addUpgradeItems(clusterClient, upgradeItems, clusterctlv1.InfrastructureProviderType, options.InfrastructureProviders...)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		upgradeItems, err = addUpgradeItems(clusterClient, upgradeItems, clusterctlv1.IPAMProviderType, options.IPAMProviders...)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		upgradeItems, err = addUpgradeItems(clusterClient, upgradeItems, clusterctlv1.RuntimeExtensionProviderType, options.RuntimeExtensionProviders...)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
-
-		// Execute the upgrade using the custom upgrade items
-		return clusterClient.ProviderUpgrader().ApplyCustomPlan(opts, upgradeItems...)
 	}
 
-	// Otherwise we are upgrading a whole management cluster according to a clusterctl generated upgrade plan.
-	return clusterClient.ProviderUpgrader().ApplyPlan(opts, options.Contract)
+	return clusterClient, upgradeItems, nil
 }
 
 func addUpgradeItems(clusterClient cluster.Client, upgradeItems []cluster.UpgradeItem, providerType clusterctlv1.ProviderType, providers ...string) ([]cluster.UpgradeItem, error) {
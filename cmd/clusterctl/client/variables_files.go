@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+// ReadVariablesFiles reads the values of template variables from a list of YAML files; values defined in later files
+// override the ones defined in earlier files; files encrypted with SOPS are decrypted using the sops binary.
+// Nested keys are flattened into a variable name by joining the keys with an underscore and converting them to upper
+// case, e.g. the key region nested in aws defines the AWS_REGION variable.
+func ReadVariablesFiles(paths ...string) (map[string]string, error) {
+	variables := map[string]string{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path) //nolint:gosec
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read variables file %q", path)
+		}
+		if config.IsSOPSEncrypted(data) {
+			if data, err = config.DecryptSOPSFile(path); err != nil {
+				return nil, err
+			}
+		}
+
+		values := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &values, func(d *json.Decoder) *json.Decoder {
+			// Preserve numbers as they are written in the file, e.g. avoid converting big integers to floats.
+			d.UseNumber()
+			return d
+		}); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse variables file %q", path)
+		}
+
+		if err := flattenVariables("", values, variables); err != nil {
+			return nil, errors.Wrapf(err, "invalid variables file %q", path)
+		}
+	}
+	return variables, nil
+}
+
+// flattenVariables adds to variables the values in a (nested) map, using as a name the keys joined with an underscore.
+func flattenVariables(prefix string, values map[string]interface{}, variables map[string]string) error {
+	for key, value := range values {
+		name := strings.ToUpper(key)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if err := flattenVariables(name, v, variables); err != nil {
+				return err
+			}
+		case []interface{}:
+			return errors.Errorf("variable %s is a list, only scalar values and nested keys are supported", name)
+		case nil:
+			variables[name] = ""
+		default:
+			variables[name] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
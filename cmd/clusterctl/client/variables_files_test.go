@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+func TestReadVariablesFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(g *WithT, name, content string) string {
+		path := filepath.Join(dir, name)
+		g.Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		return path
+	}
+
+	t.Run("flattens nested keys and lets later files override earlier ones", func(t *testing.T) {
+		g := NewWithT(t)
+
+		common := writeFile(g, "common.yaml", `KUBERNETES_VERSION: v1.26.0
+worker_machine_count: 3
+aws:
+  region: eu-west-1
+  control_plane:
+    machine_type: t3.large
+  ssh_key_name: default
+`)
+		production := writeFile(g, "production.yaml", `worker_machine_count: 10
+aws:
+  control_plane:
+    machine_type: m5.xlarge
+  encrypted: true
+  ssh_key_name:
+`)
+
+		variables, err := ReadVariablesFiles(common, production)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(variables).To(Equal(map[string]string{
+			"KUBERNETES_VERSION":             "v1.26.0",
+			"WORKER_MACHINE_COUNT":           "10",
+			"AWS_REGION":                     "eu-west-1",
+			"AWS_CONTROL_PLANE_MACHINE_TYPE": "m5.xlarge",
+			"AWS_ENCRYPTED":                  "true",
+			"AWS_SSH_KEY_NAME":               "",
+		}))
+	})
+
+	t.Run("preserves numbers as written in the file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		path := writeFile(g, "numbers.yaml", "BIG: 12345678901234567890\nFLOAT: 1.5\n")
+
+		variables, err := ReadVariablesFiles(path)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(variables).To(Equal(map[string]string{
+			"BIG":   "12345678901234567890",
+			"FLOAT": "1.5",
+		}))
+	})
+
+	t.Run("fails for lists", func(t *testing.T) {
+		g := NewWithT(t)
+
+		path := writeFile(g, "list.yaml", "aws:\n  zones:\n  - a\n  - b\n")
+
+		_, err := ReadVariablesFiles(path)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("AWS_ZONES"))
+	})
+
+	t.Run("decrypts SOPS encrypted files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		path := writeFile(g, "secrets.yaml", `vsphere:
+  password: ENC[AES256_GCM,data:c2VjcmV0,iv:aXY=,tag:dGFn,type:str]
+sops:
+  mac: ENC[AES256_GCM,data:bWFj,iv:aXY=,tag:dGFn,type:str]
+  version: 3.7.3
+`)
+		decrypted := writeFile(g, "decrypted.yaml", "vsphere:\n  password: secret\n")
+		sops := writeFile(g, "sops", "#!/bin/sh\ncat "+decrypted+"\n")
+		g.Expect(os.Chmod(sops, 0700)).To(Succeed()) //nolint:gosec
+		t.Setenv(config.SOPSBinaryEnvVar, sops)
+
+		variables, err := ReadVariablesFiles(path)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(variables).To(Equal(map[string]string{
+			"VSPHERE_PASSWORD": "secret",
+		}))
+	})
+
+	t.Run("fails for missing files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := ReadVariablesFiles(filepath.Join(dir, "missing.yaml"))
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamlprocessor
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+)
+
+// KustomizeTemplateFileName is the name of the file the processed template is written to
+// before applying the kustomize overlay; the file is automatically added to the resources
+// of the overlay, and the overlay must not contain a file with the same name.
+const KustomizeTemplateFileName = "cluster-template.yaml"
+
+// overlayRoot is the path of the kustomize overlay in the in-memory file system.
+const overlayRoot = "/overlay"
+
+// KustomizeProcessor is a yaml processor that applies a kustomize overlay on top of
+// the yaml processed by another processor, e.g. to add labels, to change resource limits
+// or to add extra manifests to a cluster template without forking it.
+// The overlay is read from a local directory and it is applied in memory, without requiring
+// the kustomize binary; the overlay must be self-contained, i.e. it can't reference files
+// outside of its directory.
+type KustomizeProcessor struct {
+	Processor
+
+	overlayDir string
+}
+
+var _ Processor = &KustomizeProcessor{}
+
+// NewKustomizeProcessor returns a new kustomize template processor applying the overlay
+// in overlayDir on top of the yaml processed by processor.
+func NewKustomizeProcessor(processor Processor, overlayDir string) *KustomizeProcessor {
+	return &KustomizeProcessor{
+		Processor:  processor,
+		overlayDir: overlayDir,
+	}
+}
+
+// Process returns the final yaml with all the variables replaced with their
+// respective values by the underlying processor, and with the kustomize overlay applied.
+func (tp *KustomizeProcessor) Process(rawArtifact []byte, variablesClient func(string) (string, error)) ([]byte, error) {
+	processed, err := tp.Processor.Process(rawArtifact, variablesClient)
+	if err != nil {
+		return processed, err
+	}
+	return tp.kustomize(processed)
+}
+
+// kustomize applies the kustomize overlay on top of yaml which is already processed.
+func (tp *KustomizeProcessor) kustomize(processed []byte) ([]byte, error) {
+	fSys := filesys.MakeFsInMemory()
+	if err := copyDirToFs(tp.overlayDir, fSys, overlayRoot); err != nil {
+		return nil, errors.Wrapf(err, "failed to read kustomize overlay from %s", tp.overlayDir)
+	}
+
+	templatePath := path.Join(overlayRoot, KustomizeTemplateFileName)
+	if fSys.Exists(templatePath) {
+		return nil, errors.Errorf("kustomize overlay %s must not contain a %s file", tp.overlayDir, KustomizeTemplateFileName)
+	}
+	if err := fSys.WriteFile(templatePath, processed); err != nil {
+		return nil, err
+	}
+	if err := addResourceToKustomization(fSys, overlayRoot, KustomizeTemplateFileName); err != nil {
+		return nil, errors.Wrapf(err, "invalid kustomize overlay %s", tp.overlayDir)
+	}
+
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, overlayRoot)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to apply kustomize overlay %s", tp.overlayDir)
+	}
+	return resMap.AsYaml()
+}
+
+// copyDirToFs copies all the files in dir to root in the target file system.
+func copyDirToFs(dir string, fSys filesys.FileSystem, root string) error {
+	return filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		target := path.Join(root, filepath.ToSlash(rel))
+		if d.IsDir() {
+			return fSys.MkdirAll(target)
+		}
+		data, err := os.ReadFile(p) //nolint:gosec // The overlay directory is provided by the user.
+		if err != nil {
+			return err
+		}
+		return fSys.WriteFile(target, data)
+	})
+}
+
+// addResourceToKustomization adds resource as the first entry in the resources of the kustomization in dir.
+func addResourceToKustomization(fSys filesys.FileSystem, dir, resource string) error {
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		kustomizationPath := path.Join(dir, name)
+		if !fSys.Exists(kustomizationPath) {
+			continue
+		}
+
+		data, err := fSys.ReadFile(kustomizationPath)
+		if err != nil {
+			return err
+		}
+		kustomization := &types.Kustomization{}
+		if err := yaml.Unmarshal(data, kustomization); err != nil {
+			return errors.Wrapf(err, "failed to parse %s", name)
+		}
+		kustomization.Resources = append([]string{resource}, kustomization.Resources...)
+		data, err = yaml.Marshal(kustomization)
+		if err != nil {
+			return err
+		}
+		return fSys.WriteFile(kustomizationPath, data)
+	}
+	return errors.Errorf("missing kustomization file, expected one of %v", konfig.RecognizedKustomizationFileNames())
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamlprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func TestKustomizeProcessor_Process(t *testing.T) {
+	template := "apiVersion: v1\n" +
+		"kind: ConfigMap\n" +
+		"metadata:\n" +
+		"  name: ${NAME}\n" +
+		"data:\n" +
+		"  script: echo $${HOME}\n"
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "applies labels and adds extra manifests",
+			files: map[string]string{
+				"kustomization.yaml": "commonLabels:\n" +
+					"  env: prod\n" +
+					"resources:\n" +
+					"- extra/secret.yaml\n",
+				"extra/secret.yaml": "apiVersion: v1\n" +
+					"kind: Secret\n" +
+					"metadata:\n" +
+					"  name: extra\n",
+			},
+			want: "apiVersion: v1\n" +
+				"data:\n" +
+				"  script: echo ${HOME}\n" +
+				"kind: ConfigMap\n" +
+				"metadata:\n" +
+				"  labels:\n" +
+				"    env: prod\n" +
+				"  name: foo\n" +
+				"---\n" +
+				"apiVersion: v1\n" +
+				"kind: Secret\n" +
+				"metadata:\n" +
+				"  labels:\n" +
+				"    env: prod\n" +
+				"  name: extra\n",
+		},
+		{
+			name: "applies patches to the template",
+			files: map[string]string{
+				"kustomization.yaml": "patches:\n" +
+					"- path: patch.yaml\n",
+				"patch.yaml": "apiVersion: v1\n" +
+					"kind: ConfigMap\n" +
+					"metadata:\n" +
+					"  name: foo\n" +
+					"data:\n" +
+					"  extra: value\n",
+			},
+			want: "apiVersion: v1\n" +
+				"data:\n" +
+				"  extra: value\n" +
+				"  script: echo ${HOME}\n" +
+				"kind: ConfigMap\n" +
+				"metadata:\n" +
+				"  name: foo\n",
+		},
+		{
+			name: "fails without a kustomization file",
+			files: map[string]string{
+				"patch.yaml": "",
+			},
+			wantErr: true,
+		},
+		{
+			name: "fails if the overlay contains a file with the name of the template",
+			files: map[string]string{
+				"kustomization.yaml":      "",
+				KustomizeTemplateFileName: "",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			overlayDir := t.TempDir()
+			for name, content := range tt.files {
+				g.Expect(os.MkdirAll(filepath.Dir(filepath.Join(overlayDir, name)), 0o750)).To(Succeed())
+				g.Expect(os.WriteFile(filepath.Join(overlayDir, name), []byte(content), 0o600)).To(Succeed())
+			}
+
+			p := NewKustomizeProcessor(NewSimpleProcessor(), overlayDir)
+			got, err := p.Process([]byte(template), test.NewFakeVariableClient().WithVar("NAME", "foo").Get)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestKustomizeProcessor_GetVariables(t *testing.T) {
+	g := NewWithT(t)
+
+	// Variables are detected by the underlying processor.
+	p := NewKustomizeProcessor(NewSimpleProcessor(), t.TempDir())
+	g.Expect(p.GetVariables([]byte("${A} ${B:=default}"))).To(Equal([]string{"A", "B"}))
+	g.Expect(p.GetTemplateName("", "dev")).To(Equal("cluster-template-dev.yaml"))
+}
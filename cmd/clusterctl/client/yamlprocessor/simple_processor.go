@@ -24,6 +24,8 @@ import (
 
 	"github.com/drone/envsubst/v2"
 	"github.com/drone/envsubst/v2/parse"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/errorcodes"
 )
 
 // SimpleProcessor is a yaml processor that uses envsubst to substitute values
@@ -134,6 +136,11 @@ type errMissingVariables struct {
 	Missing []string
 }
 
+// ErrorCode returns the error code for missing variables.
+func (e *errMissingVariables) ErrorCode() errorcodes.Code {
+	return errorcodes.MissingVariables
+}
+
 func (e *errMissingVariables) Error() string {
 	sort.Strings(e.Missing)
 	return fmt.Sprintf(
@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/errorcodes"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
 )
 
@@ -265,6 +266,8 @@ func TestSimpleProcessor_Process(t *testing.T) {
 					e, ok := err.(*errMissingVariables)
 					g.Expect(ok).To(BeTrue())
 					g.Expect(e.Missing).To(ConsistOf(tt.missingVariables))
+					code, _ := errorcodes.CodeOf(err)
+					g.Expect(code).To(Equal(errorcodes.MissingVariables))
 				}
 				// we want to ensure that we keep returning the original yaml
 				// as per the intended behavior of Process
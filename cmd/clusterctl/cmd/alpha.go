@@ -31,6 +31,9 @@ func init() {
 	// Alpha commands should be added here.
 	alphaCmd.AddCommand(rolloutCmd)
 	alphaCmd.AddCommand(topologyCmd)
+	alphaCmd.AddCommand(templateCmd)
+	alphaCmd.AddCommand(providersCmd)
+	alphaCmd.AddCommand(clusterCmd)
 
 	RootCmd.AddCommand(alphaCmd)
 }
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type backupOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+	file              string
+}
+
+var bo = &backupOptions{}
+
+var backupCmd = &cobra.Command{
+	Use:     "backup",
+	GroupID: groupManagement,
+	Short:   "Backup Cluster API objects and all dependencies from a management cluster to an archive",
+	Long: LongDesc(`
+		Backup Cluster API objects and all dependencies from a management cluster to an archive.
+
+		The archive contains all the objects, including their status and the secrets they depend on, together with
+		the list of the providers installed in the management cluster, so it can be used for disaster recovery with
+		clusterctl restore.
+
+		Note: The archive contains secrets, e.g. the workload cluster kubeconfigs and certificate authorities, and must be
+		stored securely.`),
+
+	Example: Examples(`
+		Backup Cluster API objects from all the namespaces of a management cluster.
+		clusterctl backup --file backup.tar.gz
+
+		Backup Cluster API objects from the foo namespace of a management cluster.
+		clusterctl backup --file backup.tar.gz --namespace foo`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBackup()
+	},
+}
+
+func init() {
+	backupCmd.Flags().StringVar(&bo.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file for the management cluster. If unspecified, default discovery rules apply.")
+	backupCmd.Flags().StringVar(&bo.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file for the management cluster. If empty, current context will be used.")
+	backupCmd.Flags().StringVarP(&bo.namespace, "namespace", "n", "",
+		"The namespace where the workload clusters are hosted. If unspecified, objects from all the namespaces are backed up.")
+	backupCmd.Flags().StringVarP(&bo.file, "file", "f", "",
+		"Path of the backup archive to be written.")
+	if err := backupCmd.MarkFlagRequired("file"); err != nil {
+		panic(err)
+	}
+
+	RootCmd.AddCommand(backupCmd)
+}
+
+func runBackup() error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.Backup(client.BackupOptions{
+		Kubeconfig: client.Kubeconfig{Path: bo.kubeconfig, Context: bo.kubeconfigContext},
+		Namespace:  bo.namespace,
+		File:       bo.file,
+	})
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+type clusterCloneOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+	toName            string
+	toNamespace       string
+	variables         []string
+	dryRun            bool
+}
+
+var cco = &clusterCloneOptions{}
+
+var clusterCloneCmd = &cobra.Command{
+	Use:   "clone CLUSTER",
+	Short: "Clone a workload cluster",
+	Long: LongDesc(`
+		Clone a workload cluster, creating a copy of the objects defining it, e.g. the InfrastructureCluster, the
+		control plane, MachineDeployments, MachineHealthChecks, templates and user provided secrets, with a new name
+		and/or namespace in the same management cluster.
+
+		Machines and the objects they own, the secrets generated by Cluster API, e.g. certificates and kubeconfig,
+		and the control plane endpoint are not copied, so the clone is provisioned from scratch with fresh secrets.
+		For clusters with a managed topology, the values of the topology variables of the clone can be changed
+		with --set-variable.
+
+		Use --dry-run to print the objects of the clone without creating them.`),
+
+	Example: Examples(`
+		# Clone my-cluster into a new cluster named my-cluster-test in the same namespace.
+		clusterctl alpha cluster clone my-cluster --to-name my-cluster-test
+
+		# Clone my-cluster into the staging namespace, changing the value of the imageRepository variable.
+		clusterctl alpha cluster clone my-cluster --to-name my-cluster --to-namespace staging --set-variable imageRepository=registry.example.com
+
+		# Print the objects of the clone without creating them.
+		clusterctl alpha cluster clone my-cluster --to-name my-cluster-test --dry-run`),
+
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runClusterClone(os.Stdout, args[0])
+	},
+}
+
+func init() {
+	clusterCloneCmd.Flags().StringVar(&cco.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If empty, default discovery rules apply.")
+	clusterCloneCmd.Flags().StringVar(&cco.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	clusterCloneCmd.Flags().StringVarP(&cco.namespace, "namespace", "n", "",
+		"Namespace where the cluster to be cloned is located. If unspecified, the current namespace will be used.")
+	clusterCloneCmd.Flags().StringVar(&cco.toName, "to-name", "",
+		"Name of the clone.")
+	clusterCloneCmd.Flags().StringVar(&cco.toNamespace, "to-namespace", "",
+		"Namespace of the clone. If unspecified, the namespace of the cluster to be cloned will be used.")
+	clusterCloneCmd.Flags().StringArrayVar(&cco.variables, "set-variable", nil,
+		"Value of a topology variable of the clone in the name=value format; the value is parsed as JSON, or used as a string if it is not valid JSON. The flag can be repeated.")
+	clusterCloneCmd.Flags().BoolVar(&cco.dryRun, "dry-run", false,
+		"Print the objects of the clone without creating them.")
+	_ = clusterCloneCmd.MarkFlagRequired("to-name")
+
+	// completions
+	clusterCloneCmd.ValidArgsFunction = resourceNameCompletionFunc(
+		clusterCloneCmd.Flags().Lookup("kubeconfig"),
+		clusterCloneCmd.Flags().Lookup("kubeconfig-context"),
+		clusterCloneCmd.Flags().Lookup("namespace"),
+		clusterv1.GroupVersion.String(),
+		"cluster",
+	)
+
+	clusterCmd.AddCommand(clusterCloneCmd)
+}
+
+func runClusterClone(w io.Writer, clusterName string) error {
+	variables, err := parseCloneVariables(cco.variables)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	objs, err := c.ClusterClone(client.ClusterCloneOptions{
+		Kubeconfig:  client.Kubeconfig{Path: cco.kubeconfig, Context: cco.kubeconfigContext},
+		Namespace:   cco.namespace,
+		ClusterName: clusterName,
+		ToNamespace: cco.toNamespace,
+		ToName:      cco.toName,
+		Variables:   variables,
+		DryRun:      cco.dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if cco.dryRun {
+		out, err := utilyaml.FromUnstructured(objs)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	}
+
+	for _, obj := range objs {
+		fmt.Fprintf(w, "%s/%s created\n", obj.GetKind(), obj.GetName())
+	}
+	return nil
+}
+
+// parseCloneVariables parses a list of variables in the name=value format.
+func parseCloneVariables(values []string) (map[string]string, error) {
+	variables := map[string]string{}
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			return nil, errors.Errorf("invalid variable %q, expected name=value", v)
+		}
+		variables[name] = value
+	}
+	return variables, nil
+}
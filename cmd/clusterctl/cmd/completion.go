@@ -21,12 +21,15 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 )
@@ -48,10 +51,13 @@ const completionBoilerPlate = `# Copyright 2021 The Kubernetes Authors.
 
 var (
 	completionLong = LongDesc(`
-		Output shell completion code for the specified shell (bash or zsh).
+		Output shell completion code for the specified shell (bash, zsh or fish).
 		The shell code must be evaluated to provide interactive completion of
 		clusterctl commands. This can be done by sourcing it from the
-		.bash_profile.`)
+		.bash_profile.
+
+		Besides commands and flags, the names of clusters, namespaces, ClusterClasses and
+		providers are completed by querying the management cluster.`)
 
 	completionExample = Examples(`
 		Bash:
@@ -76,12 +82,16 @@ var (
 		# To load completions for each session, execute once:
 		clusterctl completion zsh > "${fpath[1]}/_clusterctl"
 
-		# You will need to start a new shell for this setup to take effect.`)
+		# You will need to start a new shell for this setup to take effect.
+
+		Fish:
+		# To load completions for each session, execute once:
+		clusterctl completion fish > ~/.config/fish/completions/clusterctl.fish`)
 
 	completionCmd = &cobra.Command{
-		Use:     "completion [bash|zsh]",
+		Use:     "completion [bash|zsh|fish]",
 		GroupID: groupOther,
-		Short:   "Output shell completion code for the specified shell (bash or zsh)",
+		Short:   "Output shell completion code for the specified shell (bash, zsh or fish)",
 		Long:    LongDesc(completionLong),
 		Example: completionExample,
 		Args: func(cmd *cobra.Command, args []string) error {
@@ -96,9 +106,20 @@ var (
 		ValidArgs: GetSupportedShells(),
 	}
 
+	// providerFlags maps the flags used for selecting providers to the corresponding provider type.
+	providerFlags = map[string]clusterctlv1.ProviderType{
+		"core":              clusterctlv1.CoreProviderType,
+		"infrastructure":    clusterctlv1.InfrastructureProviderType,
+		"bootstrap":         clusterctlv1.BootstrapProviderType,
+		"control-plane":     clusterctlv1.ControlPlaneProviderType,
+		"ipam":              clusterctlv1.IPAMProviderType,
+		"runtime-extension": clusterctlv1.RuntimeExtensionProviderType,
+	}
+
 	completionShells = map[string]func(out io.Writer, cmd *cobra.Command) error{
 		"bash": runCompletionBash,
 		"zsh":  runCompletionZsh,
+		"fish": runCompletionFish,
 	}
 )
 
@@ -151,6 +172,12 @@ func runCompletionZsh(out io.Writer, cmd *cobra.Command) error {
 	return nil
 }
 
+func runCompletionFish(out io.Writer, cmd *cobra.Command) error {
+	fmt.Fprintf(out, "%s\n", completionBoilerPlate)
+
+	return cmd.Root().GenFishCompletion(out, true)
+}
+
 func contextCompletionFunc(kubeconfigFlag *pflag.Flag) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		configClient, err := config.New(cfgFile)
@@ -198,6 +225,72 @@ func resourceNameCompletionFunc(kubeconfigFlag, contextFlag, namespaceFlag *pfla
 	}
 }
 
+// installedProviderCompletionFunc completes the names of the providers of the given type installed in the management cluster.
+func installedProviderCompletionFunc(kubeconfigFlag, contextFlag *pflag.Flag, providerType clusterctlv1.ProviderType) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		configClient, err := config.New(cfgFile)
+		if err != nil {
+			return completionError(err)
+		}
+
+		clusterClient := cluster.New(cluster.Kubeconfig{Path: kubeconfigFlag.Value.String(), Context: contextFlag.Value.String()}, configClient)
+		providerList, err := clusterClient.ProviderInventory().List()
+		if err != nil {
+			return completionError(err)
+		}
+
+		// The same provider can be installed in more than one namespace.
+		names := sets.Set[string]{}
+		for _, provider := range providerList.FilterByType(providerType) {
+			names.Insert(provider.ProviderName)
+		}
+
+		return completeProviderNames(sets.List(names), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// providerCompletionFunc completes the names of the providers of the given type defined in the clusterctl configuration,
+// i.e. the providers which can be installed in a management cluster.
+func providerCompletionFunc(providerType clusterctlv1.ProviderType) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		configClient, err := config.New(cfgFile)
+		if err != nil {
+			return completionError(err)
+		}
+
+		providers, err := configClient.Providers().List()
+		if err != nil {
+			return completionError(err)
+		}
+
+		names := []string{}
+		for _, provider := range providers {
+			if provider.Type() == providerType {
+				names = append(names, provider.Name())
+			}
+		}
+
+		return completeProviderNames(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeProviderNames returns the provider names which begin with toComplete.
+// Provider flags accept a comma separated list of providers, so only the last provider in the list is completed.
+func completeProviderNames(names []string, toComplete string) []string {
+	var previous string
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		previous, toComplete = toComplete[:i+1], toComplete[i+1:]
+	}
+
+	var comps []string
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			comps = append(comps, previous+name)
+		}
+	}
+	return comps
+}
+
 func completionError(err error) ([]string, cobra.ShellCompDirective) {
 	cobra.CompError(err.Error())
 	return nil, cobra.ShellCompDirectiveError
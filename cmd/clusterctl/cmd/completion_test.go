@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_runCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			g := NewWithT(t)
+
+			out := &bytes.Buffer{}
+			g.Expect(runCompletion(out, completionCmd, shell)).To(Succeed())
+			g.Expect(out.String()).To(ContainSubstring("clusterctl"))
+		})
+	}
+
+	t.Run("unsupported shell", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(runCompletion(&bytes.Buffer{}, completionCmd, "powershell")).ToNot(Succeed())
+	})
+}
+
+func Test_completeProviderNames(t *testing.T) {
+	names := []string{"aws", "azure", "docker"}
+
+	tests := []struct {
+		name       string
+		toComplete string
+		want       []string
+	}{
+		{
+			name:       "complete all the providers",
+			toComplete: "",
+			want:       []string{"aws", "azure", "docker"},
+		},
+		{
+			name:       "complete the providers with a prefix",
+			toComplete: "a",
+			want:       []string{"aws", "azure"},
+		},
+		{
+			name:       "complete the last provider in a list",
+			toComplete: "aws:v2.0.1,d",
+			want:       []string{"aws:v2.0.1,docker"},
+		},
+		{
+			name:       "no providers with a prefix",
+			toComplete: "vsphere",
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(completeProviderNames(names, tt.toComplete)).To(Equal(tt.want))
+		})
+	}
+}
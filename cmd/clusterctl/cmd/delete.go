@@ -113,6 +113,15 @@ func init() {
 	deleteCmd.Flags().BoolVar(&dd.deleteAll, "all", false,
 		"Force deletion of all the providers")
 
+	// completions
+	for flagName, providerType := range providerFlags {
+		_ = deleteCmd.RegisterFlagCompletionFunc(flagName, installedProviderCompletionFunc(
+			deleteCmd.Flags().Lookup("kubeconfig"),
+			deleteCmd.Flags().Lookup("kubeconfig-context"),
+			providerType,
+		))
+	}
+
 	RootCmd.AddCommand(deleteCmd)
 }
 
@@ -17,7 +17,9 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -31,6 +33,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/duration"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
@@ -44,6 +47,20 @@ const (
 	pipe            = `│ `
 )
 
+const (
+	// DescribeClusterOutputText is an option used to print the cluster tree in text format.
+	DescribeClusterOutputText = "text"
+	// DescribeClusterOutputJSON is an option used to print the cluster tree in json format.
+	DescribeClusterOutputJSON = "json"
+	// DescribeClusterOutputYaml is an option used to print the cluster tree in yaml format.
+	DescribeClusterOutputYaml = "yaml"
+)
+
+var (
+	// DescribeClusterOutputs is a list of valid describe cluster outputs.
+	DescribeClusterOutputs = []string{DescribeClusterOutputText, DescribeClusterOutputJSON, DescribeClusterOutputYaml}
+)
+
 var (
 	gray   = color.New(color.FgHiBlack)
 	red    = color.New(color.FgRed)
@@ -65,6 +82,7 @@ type describeClusterOptions struct {
 	grouping                bool
 	disableGrouping         bool
 	color                   bool
+	output                  string
 }
 
 var dc = &describeClusterOptions{}
@@ -93,7 +111,10 @@ var describeClusterClusterCmd = &cobra.Command{
 
 		# Describe the cluster named test-1 showing the MachineInfrastructure and BootstrapConfig objects
 		# also when their status is the same as the status of the corresponding machine object.
-		clusterctl describe cluster test-1 --echo`),
+		clusterctl describe cluster test-1 --echo
+
+		# Describe the cluster named test-1 in a machine-readable format, including all the conditions.
+		clusterctl describe cluster test-1 --output json`),
 
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
@@ -132,6 +153,8 @@ func init() {
 	_ = describeClusterClusterCmd.Flags().MarkDeprecated("disable-grouping",
 		"use --grouping instead.")
 	describeClusterClusterCmd.Flags().BoolVarP(&dc.color, "color", "c", false, "Enable or disable color output; if not set color is enabled by default only if using tty. The flag is overridden by the NO_COLOR env variable if set.")
+	describeClusterClusterCmd.Flags().StringVarP(&dc.output, "output", "o", DescribeClusterOutputText,
+		fmt.Sprintf("Output format. Valid values: %v.", DescribeClusterOutputs))
 
 	// completions
 	describeClusterClusterCmd.ValidArgsFunction = resourceNameCompletionFunc(
@@ -146,6 +169,10 @@ func init() {
 }
 
 func runDescribeCluster(cmd *cobra.Command, name string) error {
+	if dc.output != DescribeClusterOutputText && dc.output != DescribeClusterOutputJSON && dc.output != DescribeClusterOutputYaml {
+		return errors.Errorf("invalid output format %q, valid values: %v", dc.output, DescribeClusterOutputs)
+	}
+
 	c, err := client.New(cfgFile)
 	if err != nil {
 		return err
@@ -167,6 +194,11 @@ func runDescribeCluster(cmd *cobra.Command, name string) error {
 		return err
 	}
 
+	switch dc.output {
+	case DescribeClusterOutputJSON, DescribeClusterOutputYaml:
+		return printObjectNode(os.Stdout, tree, dc.output)
+	}
+
 	if cmd.Flags().Changed("color") {
 		color.NoColor = !dc.color
 	}
@@ -175,6 +207,29 @@ func runDescribeCluster(cmd *cobra.Command, name string) error {
 	return nil
 }
 
+// printObjectNode prints the machine-readable representation of the cluster status to the given writer.
+func printObjectNode(out io.Writer, objectTree *tree.ObjectTree, output string) error {
+	node := objectTree.ToObjectNode()
+
+	var data []byte
+	var err error
+	switch output {
+	case DescribeClusterOutputJSON:
+		data, err = json.MarshalIndent(node, "", "  ")
+		data = append(data, '\n')
+	case DescribeClusterOutputYaml:
+		data, err = yaml.Marshal(node)
+	default:
+		return errors.Errorf("invalid output format %q, valid values: %v", output, DescribeClusterOutputs)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the cluster tree")
+	}
+
+	_, err = out.Write(data)
+	return err
+}
+
 // printObjectTree prints the cluster status to stdout.
 func printObjectTree(tree *tree.ObjectTree) {
 	// Creates the output table
@@ -224,6 +279,13 @@ func addObjectRow(prefix string, tbl *tablewriter.Table, objectTree *tree.Object
 		}
 	}
 
+	// If the object is a MachineSet without a condition message, show why the MachineSet has been rolled out, if known.
+	if obj.GetObjectKind().GroupVersionKind().Kind == "MachineSet" && readyDescriptor.message == "" {
+		if reason, ok := obj.GetAnnotations()[clusterv1.RolloutReasonAnnotation]; ok {
+			readyDescriptor.message = gray.Sprintf("Rollout reason: %s", reason)
+		}
+	}
+
 	// Gets the row name for the object.
 	// NOTE: The object name gets manipulated in order to improve readability.
 	name := getRowName(obj)
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -26,9 +27,11 @@ import (
 	"github.com/olekukonko/tablewriter"
 	. "github.com/onsi/gomega"
 	gtype "github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
@@ -294,6 +297,62 @@ func Test_TreePrefix(t *testing.T) {
 	}
 }
 
+func Test_printObjectNode(t *testing.T) {
+	objectTree := func() *tree.ObjectTree {
+		root := fakeObject("root", withCondition(conditions.TrueCondition(clusterv1.ReadyCondition)))
+		objectTree := tree.NewObjectTree(root, tree.ObjectTreeOptions{})
+		objectTree.Add(root, fakeObject("child1", withCondition(conditions.FalseCondition("C1.1", "Reason", clusterv1.ConditionSeverityWarning, ""))))
+		return objectTree
+	}()
+
+	tests := []struct {
+		name      string
+		output    string
+		unmarshal func([]byte, interface{}) error
+		wantErr   bool
+	}{
+		{
+			name:      "json output",
+			output:    DescribeClusterOutputJSON,
+			unmarshal: json.Unmarshal,
+		},
+		{
+			name:      "yaml output",
+			output:    DescribeClusterOutputYaml,
+			unmarshal: func(data []byte, v interface{}) error { return yaml.Unmarshal(data, v) },
+		},
+		{
+			name:    "invalid output",
+			output:  "invalid",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var output bytes.Buffer
+
+			err := printObjectNode(&output, objectTree, tt.output)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got := tree.ObjectNode{}
+			g.Expect(tt.unmarshal(output.Bytes(), &got)).To(Succeed())
+			g.Expect(got.Kind).To(Equal("Object"))
+			g.Expect(got.Name).To(Equal("root"))
+			g.Expect(got.Ready).ToNot(BeNil())
+			g.Expect(got.Ready.Status).To(Equal(corev1.ConditionTrue))
+			g.Expect(got.Children).To(HaveLen(1))
+			g.Expect(got.Children[0].Name).To(Equal("child1"))
+			g.Expect(got.Children[0].Conditions).To(HaveLen(1))
+			g.Expect(got.Children[0].Conditions[0].Type).To(Equal(clusterv1.ConditionType("C1.1")))
+		})
+	}
+}
+
 type objectOption func(object ctrlclient.Object)
 
 func fakeObject(name string, options ...objectOption) ctrlclient.Object {
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/errorcodes"
+)
+
+var explainErrorCmd = &cobra.Command{
+	Use:     "explain-error [CODE]",
+	GroupID: groupDebug,
+	Short:   "Print troubleshooting hints for a clusterctl error code",
+	Long: LongDesc(`
+		Print troubleshooting hints for a clusterctl error code.
+
+		When an error is classified, clusterctl prints a stable error code alongside the error
+		message; this command describes the error and the steps that can be taken to fix it.
+		If no code is provided, all the known error codes are printed.`),
+
+	Example: Examples(`
+		# Print troubleshooting hints for the ContractMismatch error code.
+		clusterctl explain-error ContractMismatch
+
+		# Print all the known error codes.
+		clusterctl explain-error`),
+
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runExplainError(os.Stdout, args)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(explainErrorCmd)
+}
+
+func runExplainError(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		for _, explanation := range errorcodes.All() {
+			fmt.Fprintf(w, "%s\t%s\n", explanation.Code, explanation.Summary)
+		}
+		return nil
+	}
+
+	explanation, ok := errorcodes.Explain(errorcodes.Code(args[0]))
+	if !ok {
+		return errors.Errorf("unknown error code %q, run 'clusterctl explain-error' to get the list of known error codes", args[0])
+	}
+
+	fmt.Fprintf(w, "%s: %s\n\nRemediation:\n", explanation.Code, explanation.Summary)
+	for _, step := range explanation.Remediation {
+		fmt.Fprintf(w, "  - %s\n", step)
+	}
+	return nil
+}
+
+// printErrorCode prints the code of err, if any, with a hint to get troubleshooting hints for it.
+func printErrorCode(w io.Writer, err error) {
+	if code, ok := errorcodes.CodeOf(err); ok {
+		fmt.Fprintf(w, "Error code: %s (run 'clusterctl explain-error %s' for troubleshooting hints)\n", code, code)
+	}
+}
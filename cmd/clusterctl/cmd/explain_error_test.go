@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/errorcodes"
+)
+
+func Test_runExplainError(t *testing.T) {
+	g := NewWithT(t)
+
+	buf := &bytes.Buffer{}
+	g.Expect(runExplainError(buf, nil)).To(Succeed())
+	g.Expect(buf.String()).To(ContainSubstring("ContractMismatch\t"))
+
+	buf.Reset()
+	g.Expect(runExplainError(buf, []string{"ContractMismatch"})).To(Succeed())
+	g.Expect(buf.String()).To(HavePrefix("ContractMismatch: "))
+	g.Expect(buf.String()).To(ContainSubstring("Remediation:\n  - "))
+
+	g.Expect(runExplainError(buf, []string{"Unknown"})).ToNot(Succeed())
+}
+
+func Test_printErrorCode(t *testing.T) {
+	g := NewWithT(t)
+
+	buf := &bytes.Buffer{}
+	printErrorCode(buf, errors.New("foo"))
+	g.Expect(buf.String()).To(BeEmpty())
+
+	printErrorCode(buf, errors.Wrap(errorcodes.Wrap(errorcodes.MissingVariables, errors.New("foo")), "bar"))
+	g.Expect(buf.String()).To(Equal("Error code: MissingVariables (run 'clusterctl explain-error MissingVariables' for troubleshooting hints)\n"))
+}
@@ -22,7 +22,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
 )
 
 type generateClusterOptions struct {
@@ -41,6 +43,10 @@ type generateClusterOptions struct {
 	configMapName      string
 	configMapDataKey   string
 
+	variablesFiles []string
+
+	kustomizeOverlay string
+
 	listVariables bool
 
 	output string
@@ -88,6 +94,14 @@ var generateClusterClusterCmd = &cobra.Command{
 		# Generates a yaml file for creating workload clusters using a template stored locally.
 		clusterctl generate cluster my-cluster --from ~/workspace/cluster-template.yaml
 
+		# Generates a yaml file for creating workload clusters using the variables defined in YAML files,
+		# e.g. with values shared by all the environments and values specific to the production environment.
+		clusterctl generate cluster my-cluster --var-file common.yaml --var-file production.yaml
+
+		# Generates a yaml file for creating workload clusters applying a local kustomize overlay on top of the template,
+		# e.g. for adding labels or extra manifests without forking the template.
+		clusterctl generate cluster my-cluster --kustomize-overlay ./overlays/production
+
 		# Prints the list of variables required by the yaml file for creating workload cluster.
 		clusterctl generate cluster my-cluster --list-variables`),
 
@@ -137,10 +151,21 @@ func init() {
 		fmt.Sprintf("The ConfigMap.Data key where the workload cluster template is hosted. If unspecified, %q will be used", client.DefaultCustomTemplateConfigMapKey))
 
 	// other flags
+	generateClusterClusterCmd.Flags().StringArrayVar(&gc.variablesFiles, "var-file", nil,
+		"Path to a YAML file with the values of the template variables; nested keys are joined with an underscore and converted to upper case, e.g. aws.region defines AWS_REGION. "+
+			"The flag can be repeated, and values in later files override the ones in earlier files, in environment variables and in the clusterctl config file. "+
+			"Files encrypted with SOPS are decrypted using the sops binary.")
+
+	generateClusterClusterCmd.Flags().StringVar(&gc.kustomizeOverlay, "kustomize-overlay", "",
+		"Path to a local directory with a kustomize overlay to be applied on top of the workload cluster template; the processed template is added to the resources of the overlay as "+yamlprocessor.KustomizeTemplateFileName+".")
+
 	generateClusterClusterCmd.Flags().BoolVar(&gc.listVariables, "list-variables", false,
 		"Returns the list of variables expected by the template instead of the template yaml")
 	generateClusterClusterCmd.Flags().StringVar(&gc.output, "write-to", "", "Specify the output file to write the template to, defaults to STDOUT if the flag is not set")
 
+	// completions
+	_ = generateClusterClusterCmd.RegisterFlagCompletionFunc("infrastructure", providerCompletionFunc(clusterctlv1.InfrastructureProviderType))
+
 	generateCmd.AddCommand(generateClusterClusterCmd)
 }
 
@@ -156,6 +181,15 @@ func runGenerateClusterTemplate(cmd *cobra.Command, name string) error {
 		TargetNamespace:   gc.targetNamespace,
 		KubernetesVersion: gc.kubernetesVersion,
 		ListVariablesOnly: gc.listVariables,
+		KustomizeOverlay:  gc.kustomizeOverlay,
+	}
+
+	if len(gc.variablesFiles) > 0 {
+		variables, err := client.ReadVariablesFiles(gc.variablesFiles...)
+		if err != nil {
+			return err
+		}
+		templateOptions.Variables = variables
 	}
 
 	if cmd.Flags().Changed("control-plane-machine-count") {
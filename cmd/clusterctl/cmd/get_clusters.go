@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+)
+
+type getClustersOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+	allNamespaces     bool
+}
+
+var gcl = &getClustersOptions{}
+
+var getClustersCmd = &cobra.Command{
+	Use:   "clusters",
+	Args:  cobra.NoArgs,
+	Short: "List the workload clusters with a summary of their status",
+	Long: LongDesc(`
+		List the workload clusters existing in a management cluster with a summary of their status,
+		including phase, Kubernetes version, ready control plane and worker machines, and infrastructure provider.`),
+
+	Example: Examples(`
+		# List the workload clusters in the current namespace.
+		clusterctl get clusters
+
+		# List the workload clusters in a particular namespace.
+		clusterctl get clusters --namespace foo
+
+		# List the workload clusters in all the namespaces.
+		clusterctl get clusters -A`),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGetClusters(os.Stdout)
+	},
+}
+
+func init() {
+	getClustersCmd.Flags().StringVarP(&gcl.namespace, "namespace", "n", "",
+		"The namespace where the workload clusters are located. If unspecified, the current namespace will be used.")
+	getClustersCmd.Flags().BoolVarP(&gcl.allNamespaces, "all-namespaces", "A", false,
+		"List the workload clusters in all the namespaces.")
+	getClustersCmd.Flags().StringVar(&gcl.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	getClustersCmd.Flags().StringVar(&gcl.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+
+	getClustersCmd.MarkFlagsMutuallyExclusive("namespace", "all-namespaces")
+
+	getCmd.AddCommand(getClustersCmd)
+}
+
+func runGetClusters(out io.Writer) error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	clusters, err := c.GetClusters(client.GetClustersOptions{
+		Kubeconfig:    client.Kubeconfig{Path: gcl.kubeconfig, Context: gcl.kubeconfigContext},
+		Namespace:     gcl.namespace,
+		AllNamespaces: gcl.allNamespaces,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printClusters(out, clusters, gcl.allNamespaces)
+}
+
+// printClusters prints a table with the summary of the status of the workload clusters.
+func printClusters(out io.Writer, clusters []tree.ClusterSummary, showNamespace bool) error {
+	if len(clusters) == 0 {
+		fmt.Fprintln(out, "No clusters found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tPHASE\tREADY\tVERSION\tCONTROL PLANE\tWORKERS\tINFRASTRUCTURE\tAGE")
+	for _, c := range clusters {
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", c.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%d\t%d/%d\t%s\t%s\n",
+			c.Name,
+			c.Phase,
+			c.Ready,
+			c.Version,
+			c.ControlPlaneReady, c.ControlPlaneTotal,
+			c.WorkersReady, c.WorkersTotal,
+			c.InfrastructureProvider,
+			duration.HumanDuration(time.Since(c.CreationTimestamp.Time)),
+		)
+	}
+	return w.Flush()
+}
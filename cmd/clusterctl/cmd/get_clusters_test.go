@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+)
+
+func Test_printClusters(t *testing.T) {
+	clusters := []tree.ClusterSummary{
+		{
+			Namespace:              "ns1",
+			Name:                   "cluster1",
+			Phase:                  "Provisioned",
+			Ready:                  corev1.ConditionTrue,
+			Version:                "v1.26.0",
+			ControlPlaneReady:      3,
+			ControlPlaneTotal:      3,
+			WorkersReady:           1,
+			WorkersTotal:           2,
+			InfrastructureProvider: "DockerCluster",
+			CreationTimestamp:      metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+		},
+	}
+
+	tests := []struct {
+		name          string
+		clusters      []tree.ClusterSummary
+		showNamespace bool
+		want          string
+	}{
+		{
+			name:     "prints a message if there are no clusters",
+			clusters: nil,
+			want:     "No clusters found\n",
+		},
+		{
+			name:     "prints clusters",
+			clusters: clusters,
+			want: "NAME       PHASE         READY     VERSION   CONTROL PLANE   WORKERS   INFRASTRUCTURE   AGE\n" +
+				"cluster1   Provisioned   True      v1.26.0   3/3             1/2       DockerCluster    120m\n",
+		},
+		{
+			name:          "prints clusters with namespace",
+			clusters:      clusters,
+			showNamespace: true,
+			want: "NAMESPACE   NAME       PHASE         READY     VERSION   CONTROL PLANE   WORKERS   INFRASTRUCTURE   AGE\n" +
+				"ns1         cluster1   Provisioned   True      v1.26.0   3/3             1/2       DockerCluster    120m\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			buf := &bytes.Buffer{}
+			g.Expect(printClusters(buf, tt.clusters, tt.showNamespace)).To(Succeed())
+			g.Expect(buf.String()).To(Equal(tt.want))
+		})
+	}
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -30,22 +31,36 @@ type getKubeconfigOptions struct {
 	kubeconfig        string
 	kubeconfigContext string
 	namespace         string
+	user              string
+	groups            []string
+	ttl               time.Duration
 }
 
 var gk = &getKubeconfigOptions{}
 
+// defaultUserKubeconfigTTL is the default lifespan of the client certificate in a user kubeconfig.
+const defaultUserKubeconfigTTL = 24 * time.Hour
+
 var getKubeconfigCmd = &cobra.Command{
 	Use:   "kubeconfig NAME",
 	Short: "Gets the kubeconfig file for accessing a workload cluster",
 	Long: LongDesc(`
-		Gets the kubeconfig file for accessing a workload cluster`),
+		Gets the kubeconfig file for accessing a workload cluster.
+
+		By default the admin kubeconfig of the workload cluster is returned. When a user is specified,
+		a short-lived kubeconfig is generated instead, using a client certificate for the user signed
+		with the workload cluster CA; permissions of the user must be granted by RBAC rules in the
+		workload cluster.`),
 
 	Example: Examples(`
 		# Get the workload cluster's kubeconfig.
 		clusterctl get kubeconfig <name of workload cluster>
 
 		# Get the workload cluster's kubeconfig in a particular namespace.
-		clusterctl get kubeconfig <name of workload cluster> --namespace foo`),
+		clusterctl get kubeconfig <name of workload cluster> --namespace foo
+
+		# Get a kubeconfig for the user jane, member of the developers group, valid for 8 hours.
+		clusterctl get kubeconfig <name of workload cluster> --user jane --group developers --ttl 8h`),
 
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
@@ -65,6 +80,12 @@ func init() {
 		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
 	getKubeconfigCmd.Flags().StringVar(&gk.kubeconfigContext, "kubeconfig-context", "",
 		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	getKubeconfigCmd.Flags().StringVar(&gk.user, "user", "",
+		"Name of the user to generate a kubeconfig with a signed client certificate for. If empty, the admin kubeconfig of the workload cluster is returned.")
+	getKubeconfigCmd.Flags().StringSliceVar(&gk.groups, "group", nil,
+		"Group the user belongs to. Can be repeated, and can be used only together with --user.")
+	getKubeconfigCmd.Flags().DurationVar(&gk.ttl, "ttl", 0,
+		fmt.Sprintf("Lifespan of the client certificate generated for the user. If not set, it defaults to %s; can be used only together with --user.", defaultUserKubeconfigTTL))
 
 	// completions
 	getKubeconfigCmd.ValidArgsFunction = resourceNameCompletionFunc(
@@ -88,6 +109,12 @@ func runGetKubeconfig(workloadClusterName string) error {
 		Kubeconfig:          client.Kubeconfig{Path: gk.kubeconfig, Context: gk.kubeconfigContext},
 		WorkloadClusterName: workloadClusterName,
 		Namespace:           gk.namespace,
+		User:                gk.user,
+		Groups:              gk.groups,
+		TTL:                 gk.ttl,
+	}
+	if options.User != "" && options.TTL == 0 {
+		options.TTL = defaultUserKubeconfigTTL
 	}
 
 	out, err := c.GetKubeconfig(options)
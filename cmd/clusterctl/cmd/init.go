@@ -17,8 +17,13 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"os"
 	"time"
 
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
@@ -37,6 +42,8 @@ type initOptions struct {
 	validate                  bool
 	waitProviders             bool
 	waitProviderTimeout       int
+	providersConfig           string
+	dryRun                    bool
 }
 
 var initOpts = &initOptions{}
@@ -79,9 +86,21 @@ var initCmd = &cobra.Command{
 		clusterctl init --infrastructure=aws,vsphere
 
 		# Initialize a management cluster with a custom target namespace for the provider resources.
-		clusterctl init --infrastructure aws --target-namespace foo`),
+		clusterctl init --infrastructure aws --target-namespace foo
+
+		# Install and upgrade the providers of a management cluster according to the desired state defined in a file.
+		clusterctl init --providers-config providers.yaml
+
+		# Show the changes required to reconcile the providers of a management cluster with a providers config file.
+		clusterctl init --providers-config providers.yaml --dry-run`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if initOpts.providersConfig != "" {
+			return runInitFromProvidersConfig(os.Stdout)
+		}
+		if initOpts.dryRun {
+			return errors.New("--dry-run can only be used with --providers-config")
+		}
 		return runInit()
 	},
 }
@@ -111,6 +130,19 @@ func init() {
 		"Wait timeout per provider installation in seconds. This value is ignored if --wait-providers is false")
 	initCmd.Flags().BoolVar(&initOpts.validate, "validate", true,
 		"If true, clusterctl will validate that the deployments will succeed on the management cluster.")
+	initCmd.Flags().StringVar(&initOpts.providersConfig, "providers-config", "",
+		"Path to a file defining the desired providers, versions, namespaces and variables for the management cluster. Only the providers that are missing or that have a different version are installed or upgraded.")
+	initCmd.Flags().BoolVar(&initOpts.dryRun, "dry-run", false,
+		"If true, only print the changes required to reconcile the management cluster with the providers config, without applying them.")
+
+	for _, flag := range []string{"core", "infrastructure", "bootstrap", "control-plane", "ipam", "runtime-extension", "target-namespace"} {
+		initCmd.MarkFlagsMutuallyExclusive("providers-config", flag)
+	}
+
+	// completions
+	for flagName, providerType := range providerFlags {
+		_ = initCmd.RegisterFlagCompletionFunc(flagName, providerCompletionFunc(providerType))
+	}
 
 	initCmd.AddCommand(initListImagesCmd)
 	RootCmd.AddCommand(initCmd)
@@ -142,3 +174,59 @@ func runInit() error {
 	}
 	return nil
 }
+
+func runInitFromProvidersConfig(w io.Writer) error {
+	providersConfig, err := client.ReadProvidersConfig(initOpts.providersConfig)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	changes, err := c.ApplyProvidersConfig(client.ApplyProvidersConfigOptions{
+		Kubeconfig:             client.Kubeconfig{Path: initOpts.kubeconfig, Context: initOpts.kubeconfigContext},
+		ProvidersConfig:        *providersConfig,
+		DryRun:                 initOpts.dryRun,
+		WaitProviders:          initOpts.waitProviders,
+		WaitProviderTimeout:    time.Duration(initOpts.waitProviderTimeout) * time.Second,
+		IgnoreValidationErrors: !initOpts.validate,
+	})
+	if err != nil {
+		return err
+	}
+
+	printProviderChanges(w, changes)
+	return nil
+}
+
+func printProviderChanges(w io.Writer, changes []client.ProviderChange) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Name", "Type", "Namespace", "Current Version", "Desired Version", "Action"})
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+
+	for _, change := range changes {
+		table.Append([]string{change.Name, string(change.Type), stringOrDash(change.Namespace), stringOrDash(change.CurrentVersion), stringOrDash(change.DesiredVersion), string(change.Action)})
+	}
+	table.Render()
+
+	if initOpts.dryRun {
+		fmt.Fprintln(w, "\nDry run: no changes have been applied to the management cluster.")
+	}
+}
+
+func stringOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -32,6 +32,11 @@ type moveOptions struct {
 	fromDirectory         string
 	toDirectory           string
 	dryRun                bool
+	toNamespace           string
+	renames               map[string]string
+	selector              string
+	includeKinds          []string
+	excludeKinds          []string
 }
 
 var mo = &moveOptions{}
@@ -54,6 +59,18 @@ var moveCmd = &cobra.Command{
 
 		Read Cluster API objects and all dependencies from a directory into a management cluster.
 		clusterctl move --from-directory /tmp/backup-directory
+
+		Move Cluster API objects and all dependencies into the tenant-a namespace of the target management cluster.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --to-namespace=tenant-a
+
+		Move Cluster API objects and all dependencies between management clusters, renaming some of them.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --rename my-cluster=tenant-a-cluster
+
+		Move only the Clusters with the env=dev label and all their dependencies between management clusters.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --selector env=dev
+
+		Move only the ClusterResourceSets and the ConfigMaps they own between management clusters.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --include-kinds '*.addons.cluster.x-k8s.io,ConfigMap'
 	`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -78,10 +95,28 @@ func init() {
 		"Write Cluster API objects and all dependencies from a management cluster to directory.")
 	moveCmd.Flags().StringVar(&mo.fromDirectory, "from-directory", "",
 		"Read Cluster API objects and all dependencies from a directory into a management cluster.")
+	moveCmd.Flags().StringVar(&mo.toNamespace, "to-namespace", "",
+		"The namespace where Cluster API objects should be moved to in the destination management cluster. If unspecified, objects are moved to the same namespace.")
+	moveCmd.Flags().StringToStringVar(&mo.renames, "rename", nil,
+		"Comma separated list of old=new names used to rename Cluster API objects in the destination management cluster. References between objects are updated accordingly.")
+	moveCmd.Flags().StringVarP(&mo.selector, "selector", "l", "",
+		"Label selector used to filter the Clusters to be moved, together with all the objects belonging to them. Objects not belonging to any Cluster, e.g. ClusterClasses, are always moved.")
+	moveCmd.Flags().StringSliceVar(&mo.includeKinds, "include-kinds", nil,
+		"Comma separated list of kinds, in the Kind or Kind.group format, to be moved; other kinds are not moved. Use *.group to select all the kinds in a group, e.g. *.cluster.x-k8s.io.")
+	moveCmd.Flags().StringSliceVar(&mo.excludeKinds, "exclude-kinds", nil,
+		"Comma separated list of kinds, in the Kind or Kind.group format, not to be moved.")
 
 	moveCmd.MarkFlagsMutuallyExclusive("to-directory", "to-kubeconfig")
 	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "to-directory")
 	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "kubeconfig")
+	moveCmd.MarkFlagsMutuallyExclusive("to-directory", "to-namespace")
+	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "to-namespace")
+	moveCmd.MarkFlagsMutuallyExclusive("to-directory", "rename")
+	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "rename")
+	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "selector")
+	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "include-kinds")
+	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "exclude-kinds")
+	moveCmd.MarkFlagsMutuallyExclusive("include-kinds", "exclude-kinds")
 
 	RootCmd.AddCommand(moveCmd)
 }
@@ -106,5 +141,10 @@ func runMove() error {
 		ToDirectory:    mo.toDirectory,
 		Namespace:      mo.namespace,
 		DryRun:         mo.dryRun,
+		ToNamespace:    mo.toNamespace,
+		Renames:        mo.renames,
+		Selector:       mo.selector,
+		IncludeKinds:   mo.includeKinds,
+		ExcludeKinds:   mo.excludeKinds,
 	})
 }
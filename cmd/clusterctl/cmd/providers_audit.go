@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type providersAuditOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	migrate           bool
+	showObjects       bool
+	output            string
+}
+
+var pao = &providersAuditOptions{}
+
+var providersAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report the deprecated and stale API versions used by the objects of the installed providers",
+	Long: LongDesc(`
+		Report, for each CRD installed by clusterctl, the API versions that have been used to store objects
+		in etcd other than the current storage version, the deprecated API versions still in use and
+		the objects which require a storage migration before an upgrade dropping those API versions.
+
+		Using --migrate, the objects are re-written so they are stored with the current storage version,
+		and the stale versions are dropped from the CRD status.`),
+
+	Example: Examples(`
+		# Report the API versions used by the objects of the installed providers.
+		clusterctl alpha providers audit
+
+		# Report the API versions and list the objects which require a storage migration.
+		clusterctl alpha providers audit --show-objects
+
+		# Migrate the objects to the current storage version.
+		clusterctl alpha providers audit --migrate`),
+
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runProvidersAudit(os.Stdout)
+	},
+}
+
+func init() {
+	providersAuditCmd.Flags().StringVar(&pao.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If empty, default discovery rules apply.")
+	providersAuditCmd.Flags().StringVar(&pao.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	providersAuditCmd.Flags().BoolVar(&pao.migrate, "migrate", false,
+		"Re-write the objects of the CRDs with stale stored versions, so they are stored with the current storage version.")
+	providersAuditCmd.Flags().BoolVar(&pao.showObjects, "show-objects", false,
+		"List the objects which require a storage migration.")
+	providersAuditCmd.Flags().StringVarP(&pao.output, "output", "o", "text",
+		"Output format; available options are 'text' and 'json'")
+
+	providersCmd.AddCommand(providersAuditCmd)
+}
+
+func runProvidersAudit(w io.Writer) error {
+	if pao.output != "text" && pao.output != "json" {
+		return errors.Errorf("invalid output format: %s", pao.output)
+	}
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	audits, err := c.ProvidersAudit(client.ProvidersAuditOptions{
+		Kubeconfig: client.Kubeconfig{Path: pao.kubeconfig, Context: pao.kubeconfigContext},
+		Migrate:    pao.migrate,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printProvidersAuditOutput(w, audits)
+}
+
+func printProvidersAuditOutput(w io.Writer, audits []client.ProvidersAuditResult) error {
+	if pao.output == "json" {
+		b, err := json.MarshalIndent(audits, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(b))
+		return nil
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"CRD", "Storage Version", "Stored Versions", "Deprecated Versions In Use", "Objects To Migrate"})
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+
+	requireMigration := 0
+	for _, a := range audits {
+		objects := "-"
+		switch {
+		case a.Migrated:
+			objects = "migrated"
+		case a.RequiresMigration():
+			objects = fmt.Sprintf("%d", len(a.Objects))
+			requireMigration++
+		}
+		table.Append([]string{a.CRD, a.StorageVersion, strings.Join(a.StoredVersions, ","), orDash(a.DeprecatedVersions), objects})
+	}
+	table.Render()
+
+	if pao.showObjects {
+		for _, a := range audits {
+			if !a.RequiresMigration() || len(a.Objects) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "\n%s objects stored with %s:\n", a.Kind, strings.Join(a.StaleVersions, ","))
+			for _, o := range a.Objects {
+				fmt.Fprintf(w, "  %s\n", o)
+			}
+		}
+	}
+
+	if requireMigration > 0 {
+		fmt.Fprintf(w, "\n%d CRDs require a storage migration; run the command with --migrate to migrate the objects to the current storage version.\n", requireMigration)
+	}
+	return nil
+}
+
+func orDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ",")
+}
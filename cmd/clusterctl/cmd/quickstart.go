@@ -0,0 +1,414 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// quickstartCNILabel is the label used by the ClusterResourceSet installing the CNI to select
+	// the workload cluster created by clusterctl quickstart.
+	quickstartCNILabel = "cni"
+
+	// quickstartPollInterval is the interval used when waiting for the workload cluster to be ready.
+	quickstartPollInterval = 10 * time.Second
+)
+
+type quickstartOptions struct {
+	kubeconfig             string
+	kubeconfigContext      string
+	managementClusterName  string
+	clusterName            string
+	targetNamespace        string
+	kubernetesVersion      string
+	controlPlaneMachines   int64
+	workerMachines         int64
+	infrastructureProvider string
+	bootstrapProviders     []string
+	controlPlaneProviders  []string
+	flavor                 string
+	cniManifest            string
+	kubeconfigOutput       string
+	timeout                time.Duration
+}
+
+var qs = &quickstartOptions{}
+
+var quickstartCmd = &cobra.Command{
+	Use:     "quickstart",
+	GroupID: groupManagement,
+	Short:   "Create a management cluster and a workload cluster with a single command",
+	Long: LongDesc(`
+		Create a management cluster and a workload cluster with a single command.
+
+		Creates a kind management cluster, initializes it with the selected providers, generates and
+		creates a workload cluster using the selected flavor, installs a CNI into the workload cluster
+		using a ClusterResourceSet and waits for the workload cluster to be ready.
+
+		This command is opinionated and intended for demos, for getting started with Cluster API and for
+		smoke testing provider combinations; use 'clusterctl init' and 'clusterctl generate cluster'
+		for production setups.
+
+		Creating the management cluster requires the kind binary in the PATH; use --kubeconfig to use an
+		existing management cluster instead.`),
+
+	Example: Examples(`
+		# Create a management cluster and a workload cluster using the Docker provider.
+		clusterctl quickstart
+
+		# Create a workload cluster named my-cluster with a specific Kubernetes version.
+		clusterctl quickstart --name my-cluster --kubernetes-version v1.26.0
+
+		# Use an existing management cluster and a different CNI.
+		clusterctl quickstart --kubeconfig ~/.kube/config --cni-manifest https://example.com/cni.yaml`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runQuickstart()
+	},
+}
+
+func init() {
+	quickstartCmd.Flags().StringVar(&qs.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for an existing management cluster. If unspecified, a kind management cluster is created.")
+	quickstartCmd.Flags().StringVar(&qs.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	quickstartCmd.Flags().StringVar(&qs.managementClusterName, "management-cluster-name", "capi-management",
+		"The name of the kind management cluster to be created. This value is ignored if --kubeconfig is set.")
+	quickstartCmd.Flags().StringVar(&qs.clusterName, "name", "capi-quickstart",
+		"The name of the workload cluster to be created.")
+	quickstartCmd.Flags().StringVarP(&qs.targetNamespace, "target-namespace", "n", "default",
+		"The namespace to use for the workload cluster.")
+	quickstartCmd.Flags().StringVar(&qs.kubernetesVersion, "kubernetes-version", "",
+		"The Kubernetes version to use for the workload cluster. If unspecified, the value from OS env variables or the $HOME/.cluster-api/clusterctl.yaml config file will be used.")
+	quickstartCmd.Flags().Int64Var(&qs.controlPlaneMachines, "control-plane-machine-count", 1,
+		"The number of control plane machines for the workload cluster.")
+	quickstartCmd.Flags().Int64Var(&qs.workerMachines, "worker-machine-count", 1,
+		"The number of worker machines for the workload cluster.")
+	quickstartCmd.Flags().StringVarP(&qs.infrastructureProvider, "infrastructure", "i", "docker",
+		"The infrastructure provider (e.g. docker:v1.4.0) to add to the management cluster and to use for the workload cluster.")
+	quickstartCmd.Flags().StringSliceVarP(&qs.bootstrapProviders, "bootstrap", "b", nil,
+		"Bootstrap providers and versions (e.g. kubeadm:v1.1.5) to add to the management cluster. If unspecified, Kubeadm bootstrap provider's latest release is used.")
+	quickstartCmd.Flags().StringSliceVarP(&qs.controlPlaneProviders, "control-plane", "c", nil,
+		"Control plane providers and versions (e.g. kubeadm:v1.1.5) to add to the management cluster. If unspecified, the Kubeadm control plane provider's latest release is used.")
+	quickstartCmd.Flags().StringVarP(&qs.flavor, "flavor", "f", "",
+		"The workload cluster template variant to be used. If unspecified, the development flavor is used for the Docker provider and the default flavor for other providers.")
+	quickstartCmd.Flags().StringVar(&qs.cniManifest, "cni-manifest", "https://raw.githubusercontent.com/projectcalico/calico/v3.24.4/manifests/calico.yaml",
+		"URL of the manifest installing the CNI into the workload cluster.")
+	quickstartCmd.Flags().StringVar(&qs.kubeconfigOutput, "kubeconfig-output", "",
+		"Path where the kubeconfig of the workload cluster is written. If unspecified, <name>.kubeconfig is used.")
+	quickstartCmd.Flags().DurationVar(&qs.timeout, "timeout", 30*time.Minute,
+		"Time to wait for the workload cluster to be ready.")
+
+	RootCmd.AddCommand(quickstartCmd)
+}
+
+func runQuickstart() error {
+	ctx := context.Background()
+	log := logf.Log
+
+	c, err := clusterctlclient.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	// Create the management cluster, if not provided.
+	kubeconfig := clusterctlclient.Kubeconfig{Path: qs.kubeconfig, Context: qs.kubeconfigContext}
+	if kubeconfig.Path == "" {
+		log.Info("Creating the kind management cluster", "Name", qs.managementClusterName)
+		kubeconfig.Path, err = createKindManagementCluster(qs.managementClusterName, providerName(qs.infrastructureProvider))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Initialize the management cluster; ClusterClass is required by the flavors used by default.
+	if _, ok := os.LookupEnv("CLUSTER_TOPOLOGY"); !ok {
+		if err := os.Setenv("CLUSTER_TOPOLOGY", "true"); err != nil {
+			return errors.Wrap(err, "failed to set CLUSTER_TOPOLOGY")
+		}
+	}
+	if _, err := c.Init(clusterctlclient.InitOptions{
+		Kubeconfig:              kubeconfig,
+		BootstrapProviders:      qs.bootstrapProviders,
+		ControlPlaneProviders:   qs.controlPlaneProviders,
+		InfrastructureProviders: []string{qs.infrastructureProvider},
+		WaitProviders:           true,
+		WaitProviderTimeout:     5 * time.Minute,
+	}); err != nil {
+		return err
+	}
+
+	configClient, err := config.New(cfgFile)
+	if err != nil {
+		return err
+	}
+	managementClient, err := cluster.New(cluster.Kubeconfig(kubeconfig), configClient).Proxy().NewClient()
+	if err != nil {
+		return err
+	}
+
+	// Generate and create the workload cluster.
+	flavor := qs.flavor
+	if flavor == "" && providerName(qs.infrastructureProvider) == "docker" {
+		flavor = "development"
+	}
+	log.Info("Creating the workload cluster", "Cluster", qs.clusterName, "Namespace", qs.targetNamespace, "Flavor", flavor)
+	template, err := c.GetClusterTemplate(clusterctlclient.GetClusterTemplateOptions{
+		Kubeconfig:               kubeconfig,
+		ClusterName:              qs.clusterName,
+		TargetNamespace:          qs.targetNamespace,
+		KubernetesVersion:        qs.kubernetesVersion,
+		ControlPlaneMachineCount: &qs.controlPlaneMachines,
+		WorkerMachineCount:       &qs.workerMachines,
+		ProviderRepositorySource: &clusterctlclient.ProviderRepositorySourceOptions{
+			InfrastructureProvider: qs.infrastructureProvider,
+			Flavor:                 flavor,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for i := range template.Objs() {
+		obj := template.Objs()[i]
+		if obj.GetKind() == "Cluster" && obj.GroupVersionKind().Group == clusterv1.GroupVersion.Group {
+			labels := obj.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[quickstartCNILabel] = quickstartCNIName(qs.clusterName)
+			obj.SetLabels(labels)
+		}
+		if err := managementClient.Create(ctx, &obj); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create %s %s", obj.GetKind(), client.ObjectKeyFromObject(&obj))
+		}
+	}
+
+	// Install the CNI into the workload cluster.
+	log.Info("Creating the ClusterResourceSet installing the CNI", "Manifest", qs.cniManifest)
+	cniManifest, err := fetchCNIManifest(ctx, qs.cniManifest)
+	if err != nil {
+		return err
+	}
+	for _, obj := range quickstartCNIObjects(qs.clusterName, qs.targetNamespace, cniManifest) {
+		if err := managementClient.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create %s", client.ObjectKeyFromObject(obj))
+		}
+	}
+
+	// Wait for the workload cluster to be ready.
+	log.Info("Waiting for the workload cluster to be ready", "Timeout", qs.timeout)
+	expectedMachines := int(qs.controlPlaneMachines + qs.workerMachines)
+	if err := wait.PollImmediateWithContext(ctx, quickstartPollInterval, qs.timeout, func(ctx context.Context) (bool, error) {
+		return workloadClusterReady(ctx, managementClient, qs.targetNamespace, qs.clusterName, expectedMachines)
+	}); err != nil {
+		return errors.Wrapf(err, "failed to wait for the workload cluster %s/%s to be ready", qs.targetNamespace, qs.clusterName)
+	}
+
+	out, err := c.GetKubeconfig(clusterctlclient.GetKubeconfigOptions{
+		Kubeconfig:          kubeconfig,
+		WorkloadClusterName: qs.clusterName,
+		Namespace:           qs.targetNamespace,
+	})
+	if err != nil {
+		return err
+	}
+	kubeconfigOutput := qs.kubeconfigOutput
+	if kubeconfigOutput == "" {
+		kubeconfigOutput = fmt.Sprintf("%s.kubeconfig", qs.clusterName)
+	}
+	if err := os.WriteFile(kubeconfigOutput, []byte(out), 0600); err != nil {
+		return errors.Wrapf(err, "failed to write the kubeconfig of the workload cluster to %s", kubeconfigOutput)
+	}
+
+	fmt.Printf("\nYour workload cluster %s is ready!\n\n", qs.clusterName)
+	fmt.Printf("You can access it using:\n\n  kubectl --kubeconfig=%s get nodes\n\n", kubeconfigOutput)
+	fmt.Printf("The management cluster can be accessed using:\n\n  kubectl --kubeconfig=%s get clusters -A\n\n", kubeconfig.Path)
+	return nil
+}
+
+// createKindManagementCluster creates a kind cluster to be used as a management cluster and returns
+// the path of its kubeconfig file.
+func createKindManagementCluster(name, infrastructureProvider string) (string, error) {
+	if _, err := exec.LookPath("kind"); err != nil {
+		return "", errors.New("the kind binary is required for creating the management cluster; install kind or use --kubeconfig to use an existing management cluster")
+	}
+
+	kindConfig, err := os.CreateTemp("", "kind-config-*.yaml")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create the kind config file")
+	}
+	defer os.Remove(kindConfig.Name())
+	if _, err := kindConfig.WriteString(kindClusterConfig(infrastructureProvider)); err != nil {
+		return "", errors.Wrap(err, "failed to write the kind config file")
+	}
+	if err := kindConfig.Close(); err != nil {
+		return "", errors.Wrap(err, "failed to write the kind config file")
+	}
+
+	kubeconfigPath := fmt.Sprintf("%s.kubeconfig", name)
+	cmd := exec.Command("kind", "create", "cluster", "--name", name, "--config", kindConfig.Name(), "--kubeconfig", kubeconfigPath) //nolint:gosec
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "failed to create the kind cluster %s", name)
+	}
+	return kubeconfigPath, nil
+}
+
+// kindClusterConfig returns the kind configuration for the management cluster; when using the Docker provider,
+// the Docker socket of the host is mounted into the management cluster.
+func kindClusterConfig(infrastructureProvider string) string {
+	var b strings.Builder
+	b.WriteString("kind: Cluster\n")
+	b.WriteString("apiVersion: kind.x-k8s.io/v1alpha4\n")
+	b.WriteString("nodes:\n")
+	b.WriteString("- role: control-plane\n")
+	if infrastructureProvider == "docker" {
+		b.WriteString("  extraMounts:\n")
+		b.WriteString("  - hostPath: /var/run/docker.sock\n")
+		b.WriteString("    containerPath: /var/run/docker.sock\n")
+	}
+	return b.String()
+}
+
+// providerName returns the name of a provider given a provider string in the name[:version] format.
+func providerName(provider string) string {
+	name, _, _ := strings.Cut(provider, ":")
+	return name
+}
+
+// fetchCNIManifest reads the CNI manifest from the given URL.
+func fetchCNIManifest(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create the request for the CNI manifest %s", url)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the CNI manifest %s", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to get the CNI manifest %s: got status %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read the CNI manifest %s", url)
+	}
+	return string(data), nil
+}
+
+func quickstartCNIName(clusterName string) string {
+	return fmt.Sprintf("%s-cni", clusterName)
+}
+
+// quickstartCNIObjects returns the ConfigMap containing the CNI manifest and the ClusterResourceSet
+// installing it into the workload cluster.
+func quickstartCNIObjects(clusterName, namespace, manifest string) []client.Object {
+	name := quickstartCNIName(clusterName)
+	return []client.Object{
+		&corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: corev1.SchemeGroupVersion.String(),
+				Kind:       "ConfigMap",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Data: map[string]string{
+				"cni.yaml": manifest,
+			},
+		},
+		&addonsv1.ClusterResourceSet{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: addonsv1.GroupVersion.String(),
+				Kind:       "ClusterResourceSet",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{quickstartCNILabel: name},
+				},
+				Resources: []addonsv1.ResourceRef{
+					{
+						Name: name,
+						Kind: string(addonsv1.ConfigMapClusterResourceSetResourceKind),
+					},
+				},
+				Strategy: string(addonsv1.ClusterResourceSetStrategyApplyOnce),
+			},
+		},
+	}
+}
+
+// workloadClusterReady returns true if the Cluster is ready and all the expected Machines have a healthy Node.
+func workloadClusterReady(ctx context.Context, c client.Client, namespace, name string, expectedMachines int) (bool, error) {
+	log := logf.Log
+
+	workloadCluster := &clusterv1.Cluster{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, workloadCluster); err != nil {
+		// Tolerate transient errors while the management cluster is busy.
+		log.V(5).Info("Failed to get the Cluster", "Error", err.Error())
+		return false, nil
+	}
+	if !conditions.IsTrue(workloadCluster, clusterv1.ReadyCondition) {
+		log.V(1).Info("Waiting for the Cluster to be ready")
+		return false, nil
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := c.List(ctx, machines, client.InNamespace(namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: name}); err != nil {
+		log.V(5).Info("Failed to list the Machines", "Error", err.Error())
+		return false, nil
+	}
+	healthy := 0
+	for i := range machines.Items {
+		if conditions.IsTrue(&machines.Items[i], clusterv1.MachineNodeHealthyCondition) {
+			healthy++
+		}
+	}
+	log.V(1).Info("Waiting for the Machines to have a healthy Node", "Healthy", healthy, "Expected", expectedMachines)
+	return healthy >= expectedMachines, nil
+}
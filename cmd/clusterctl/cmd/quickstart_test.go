@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func Test_kindClusterConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(kindClusterConfig("docker")).To(ContainSubstring("containerPath: /var/run/docker.sock"))
+	g.Expect(kindClusterConfig("aws")).ToNot(ContainSubstring("extraMounts"))
+}
+
+func Test_providerName(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(providerName("docker")).To(Equal("docker"))
+	g.Expect(providerName("docker:v1.4.0")).To(Equal("docker"))
+}
+
+func Test_quickstartCNIObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	objs := quickstartCNIObjects("my-cluster", "ns1", "cni-manifest")
+	g.Expect(objs).To(HaveLen(2))
+
+	crs, ok := objs[1].(*addonsv1.ClusterResourceSet)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(crs.Namespace).To(Equal("ns1"))
+	g.Expect(crs.Spec.ClusterSelector.MatchLabels).To(HaveKeyWithValue(quickstartCNILabel, "my-cluster-cni"))
+	g.Expect(crs.Spec.Resources).To(ConsistOf(addonsv1.ResourceRef{Name: objs[0].GetName(), Kind: "ConfigMap"}))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type restoreOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	file              string
+	clusters          []string
+}
+
+var ro = &restoreOptions{}
+
+var restoreCmd = &cobra.Command{
+	Use:     "restore",
+	GroupID: groupManagement,
+	Short:   "Restore Cluster API objects and all dependencies from an archive into a management cluster",
+	Long: LongDesc(`
+		Restore Cluster API objects and all dependencies from an archive created with clusterctl backup into a management cluster.
+
+		Before restoring, clusterctl checks that all the providers installed at the time of the backup are installed in
+		the management cluster, with the same or a newer version.
+
+		Note: The management cluster MUST have the required provider components installed.`),
+
+	Example: Examples(`
+		Restore all the Cluster API objects from an archive into a management cluster.
+		clusterctl restore --file backup.tar.gz
+
+		Restore only the objects of the Cluster bar in the namespace foo, plus the objects not belonging to any Cluster, e.g. ClusterClasses.
+		clusterctl restore --file backup.tar.gz --cluster foo/bar`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRestore()
+	},
+}
+
+func init() {
+	restoreCmd.Flags().StringVar(&ro.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file for the management cluster. If unspecified, default discovery rules apply.")
+	restoreCmd.Flags().StringVar(&ro.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file for the management cluster. If empty, current context will be used.")
+	restoreCmd.Flags().StringVarP(&ro.file, "file", "f", "",
+		"Path of the backup archive to be restored.")
+	restoreCmd.Flags().StringSliceVar(&ro.clusters, "cluster", nil,
+		"Comma separated list of Clusters to be restored, by name or in the namespace/name format. If unspecified, all the Clusters are restored.")
+	if err := restoreCmd.MarkFlagRequired("file"); err != nil {
+		panic(err)
+	}
+
+	RootCmd.AddCommand(restoreCmd)
+}
+
+func runRestore() error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.Restore(client.RestoreOptions{
+		Kubeconfig: client.Kubeconfig{Path: ro.kubeconfig, Context: ro.kubeconfigContext},
+		File:       ro.file,
+		Clusters:   ro.clusters,
+	})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+// historyOptions is the start of the data required to perform the operation.
+type historyOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	resources         []string
+	namespace         string
+}
+
+var historyOpt = &historyOptions{}
+
+var (
+	historyLong = templates.LongDesc(`
+		View the rollout history of a cluster-api resource, including the reason of each rollout.`)
+
+	historyExample = templates.Examples(`
+		# View the rollout history of a machinedeployment
+		clusterctl alpha rollout history machinedeployment/my-md-0`)
+)
+
+// NewCmdRolloutHistory returns a Command instance for 'rollout history' sub command.
+func NewCmdRolloutHistory(cfgFile string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "history RESOURCE",
+		DisableFlagsInUseLine: true,
+		Short:                 "View the rollout history of a cluster-api resource",
+		Long:                  historyLong,
+		Example:               historyExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHistory(cfgFile, args)
+		},
+	}
+	cmd.Flags().StringVar(&historyOpt.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	cmd.Flags().StringVar(&historyOpt.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	cmd.Flags().StringVarP(&historyOpt.namespace, "namespace", "n", "", "Namespace where the resource(s) reside. If unspecified, the defult namespace will be used.")
+
+	return cmd
+}
+
+func runHistory(cfgFile string, args []string) error {
+	historyOpt.resources = args
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	history, err := c.RolloutHistory(client.RolloutHistoryOptions{
+		Kubeconfig: client.Kubeconfig{Path: historyOpt.kubeconfig, Context: historyOpt.kubeconfigContext},
+		Namespace:  historyOpt.namespace,
+		Resources:  historyOpt.resources,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, h := range history {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s/%s\n", strings.ToLower(h.Object.Kind), h.Object.Name)
+		w := tabwriter.NewWriter(os.Stdout, 10, 4, 3, ' ', 0)
+		fmt.Fprintln(w, "REVISION\tNAME\tROLLOUT REASON")
+		for _, r := range h.Revisions {
+			reason := r.Reason
+			if reason == "" {
+				reason = "<none>"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", r.Revision, r.Name, reason)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
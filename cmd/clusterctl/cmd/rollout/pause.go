@@ -30,22 +30,23 @@ type pauseOptions struct {
 	kubeconfigContext string
 	resources         []string
 	namespace         string
+	reason            string
 }
 
 var pauseOpt = &pauseOptions{}
 
 var (
 	pauseLong = templates.LongDesc(`
-		Mark the provided cluster-api resource as paused.
+		Pause the rollout of the provided cluster-api resource.
 
-	        Paused resources will not be reconciled by a controller. Use "clusterctl alpha rollout resume" to resume a paused resource. Currently only MachineDeployments and KubeadmControlPlanes support being paused.`)
+	        Resources with a paused rollout keep being reconciled, e.g. for scaling, but machines with an outdated spec are not rolled out. The reason is recorded in the "cluster.x-k8s.io/rollout-pause-reason" annotation and in an event on the resource. Use "clusterctl alpha rollout resume" to resume the rollout. Currently only MachineDeployments and KubeadmControlPlanes support pausing the rollout.`)
 
 	pauseExample = templates.Examples(`
-		# Mark the machinedeployment as paused.
-		clusterctl alpha rollout pause machinedeployment/my-md-0
+		# Pause the rollout of the machinedeployment.
+		clusterctl alpha rollout pause machinedeployment/my-md-0 --reason "change freeze"
 
-		# Mark the KubeadmControlPlane as paused.
-		clusterctl alpha rollout pause kubeadmcontrolplane/my-kcp`)
+		# Pause the rollout of the KubeadmControlPlane.
+		clusterctl alpha rollout pause kubeadmcontrolplane/my-kcp --reason "change freeze"`)
 )
 
 // NewCmdRolloutPause returns a Command instance for 'rollout pause' sub command.
@@ -53,7 +54,7 @@ func NewCmdRolloutPause(cfgFile string) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:                   "pause RESOURCE",
 		DisableFlagsInUseLine: true,
-		Short:                 "Pause a cluster-api resource",
+		Short:                 "Pause the rollout of a cluster-api resource",
 		Long:                  pauseLong,
 		Example:               pauseExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -65,6 +66,8 @@ func NewCmdRolloutPause(cfgFile string) *cobra.Command {
 	cmd.Flags().StringVar(&pauseOpt.kubeconfigContext, "kubeconfig-context", "",
 		"Context to be used within the kubeconfig file. If empty, current context will be used.")
 	cmd.Flags().StringVarP(&pauseOpt.namespace, "namespace", "n", "", "Namespace where the resource(s) reside. If unspecified, the defult namespace will be used.")
+	cmd.Flags().StringVar(&pauseOpt.reason, "reason", "", "Reason why the rollout is paused; it is recorded in an event on the resource(s).")
+	_ = cmd.MarkFlagRequired("reason")
 
 	return cmd
 }
@@ -81,5 +84,6 @@ func runPause(cfgFile string, args []string) error {
 		Kubeconfig: client.Kubeconfig{Path: pauseOpt.kubeconfig, Context: pauseOpt.kubeconfigContext},
 		Namespace:  pauseOpt.namespace,
 		Resources:  pauseOpt.resources,
+		Reason:     pauseOpt.reason,
 	})
 }
@@ -29,22 +29,23 @@ type resumeOptions struct {
 	kubeconfigContext string
 	resources         []string
 	namespace         string
+	reason            string
 }
 
 var resumeOpt = &resumeOptions{}
 
 var (
 	resumeLong = templates.LongDesc(`
-		Resume a paused cluster-api resource
+		Resume the paused rollout of a cluster-api resource
 
-	        Paused resources will not be reconciled by a controller. By resuming a resource, we allow it to be reconciled again. Currently only MachineDeployments and KubeadmControlPlanes support being resumed.`)
+	        Machines with an outdated spec are not rolled out while the rollout is paused. By resuming the rollout, we allow them to be rolled out again. The reason is recorded in an event on the resource. Currently only MachineDeployments and KubeadmControlPlanes support resuming the rollout.`)
 
 	resumeExample = templates.Examples(`
-		# Resume an already paused machinedeployment
-		clusterctl alpha rollout resume machinedeployment/my-md-0
+		# Resume the paused rollout of a machinedeployment
+		clusterctl alpha rollout resume machinedeployment/my-md-0 --reason "change freeze is over"
 
-		# Resume a kubeadmcontrolplane
-		clusterctl alpha rollout resume kubeadmcontrolplane/my-kcp`)
+		# Resume the paused rollout of a kubeadmcontrolplane
+		clusterctl alpha rollout resume kubeadmcontrolplane/my-kcp --reason "change freeze is over"`)
 )
 
 // NewCmdRolloutResume returns a Command instance for 'rollout resume' sub command.
@@ -52,7 +53,7 @@ func NewCmdRolloutResume(cfgFile string) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:                   "resume RESOURCE",
 		DisableFlagsInUseLine: true,
-		Short:                 "Resume a cluster-api resource",
+		Short:                 "Resume the rollout of a cluster-api resource",
 		Long:                  resumeLong,
 		Example:               resumeExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -64,6 +65,8 @@ func NewCmdRolloutResume(cfgFile string) *cobra.Command {
 	cmd.Flags().StringVar(&resumeOpt.kubeconfigContext, "kubeconfig-context", "",
 		"Context to be used within the kubeconfig file. If empty, current context will be used.")
 	cmd.Flags().StringVarP(&resumeOpt.namespace, "namespace", "n", "", "Namespace where the resource(s) reside. If unspecified, the defult namespace will be used.")
+	cmd.Flags().StringVar(&resumeOpt.reason, "reason", "", "Reason why the rollout is resumed; it is recorded in an event on the resource(s).")
+	_ = cmd.MarkFlagRequired("reason")
 
 	return cmd
 }
@@ -80,5 +83,6 @@ func runResume(cfgFile string, args []string) error {
 		Kubeconfig: client.Kubeconfig{Path: resumeOpt.kubeconfig, Context: resumeOpt.kubeconfigContext},
 		Namespace:  resumeOpt.namespace,
 		Resources:  resumeOpt.resources,
+		Reason:     resumeOpt.reason,
 	})
 }
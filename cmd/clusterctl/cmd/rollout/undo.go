@@ -43,7 +43,10 @@ var (
 		clusterctl alpha rollout undo machinedeployment/my-md-0
 
 		# Rollback to previous machinedeployment --to-revision=3
-		clusterctl alpha rollout undo machinedeployment/my-md-0 --to-revision=3`)
+		clusterctl alpha rollout undo machinedeployment/my-md-0 --to-revision=3
+
+		# Rollback to the previous kubeadmcontrolplane
+		clusterctl alpha rollout undo kubeadmcontrolplane/my-kcp`)
 )
 
 // NewCmdRolloutUndo returns a Command instance for 'rollout undo' sub command.
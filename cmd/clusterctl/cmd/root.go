@@ -103,6 +103,7 @@ var RootCmd = &cobra.Command{
 // Execute executes the root command.
 func Execute() {
 	if err := RootCmd.Execute(); err != nil {
+		printErrorCode(os.Stderr, err)
 		if verbosity != nil && *verbosity >= 5 {
 			if err, ok := err.(stackTracer); ok {
 				for _, f := range err.StackTrace() {
@@ -174,7 +175,7 @@ func registerCompletionFuncForCommonFlags() {
 
 			if contextFlag := cmd.Flags().Lookup("kubeconfig-context"); contextFlag != nil {
 				// namespace
-				for _, flagName := range []string{"namespace", "target-namespace", "from-config-map-namespace"} {
+				for _, flagName := range []string{"namespace", "target-namespace", "to-namespace", "from-config-map-namespace"} {
 					_ = cmd.RegisterFlagCompletionFunc(flagName, resourceNameCompletionFunc(kubeconfigFlag, contextFlag, nil, "v1", "namespace"))
 				}
 			}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type templateLintOptions struct {
+	from   string
+	output string
+	failOn string
+}
+
+var tlo = &templateLintOptions{}
+
+var templateLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check a cluster template for common issues",
+	Long: LongDesc(`
+		Statically check a cluster template for common issues, e.g. variables without a value,
+		deprecated API versions, missing required fields, references to objects not defined in the template,
+		MachineDeployment versions not compatible with the control plane version and secrets embedded in plaintext.
+
+		Variable values are either sourced from the clusterctl config file or from environment variables.
+
+		This command does not require access to a management cluster nor to provider repositories.`),
+
+	Example: Examples(`
+		# Check a template stored locally.
+		clusterctl alpha template lint --from ~/workspace/cluster-template.yaml
+
+		# Check a template passed in via stdin and print the findings in JSON format.
+		cat ~/workspace/cluster-template.yaml | clusterctl alpha template lint -o json
+
+		# Fail only if there are findings with error severity.
+		clusterctl alpha template lint --from ~/workspace/cluster-template.yaml --fail-on error`),
+
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTemplateLint(os.Stdin, os.Stdout)
+	},
+}
+
+func init() {
+	templateLintCmd.Flags().StringVar(&tlo.from, "from", "-",
+		"The path of the template to check. It defaults to '-' which reads from stdin.")
+	templateLintCmd.Flags().StringVarP(&tlo.output, "output", "o", "text",
+		"Output format; available options are 'text' and 'json'")
+	templateLintCmd.Flags().StringVar(&tlo.failOn, "fail-on", string(client.LintSeverityWarning),
+		"The minimum severity of the findings causing the command to fail; available options are 'error', 'warning', 'info' and 'none'")
+
+	templateCmd.AddCommand(templateLintCmd)
+}
+
+func runTemplateLint(r io.Reader, w io.Writer) error {
+	switch tlo.failOn {
+	case string(client.LintSeverityError), string(client.LintSeverityWarning), string(client.LintSeverityInfo), "none":
+	default:
+		return errors.Errorf("invalid fail-on severity: %s", tlo.failOn)
+	}
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	if tlo.from != "-" {
+		f, err := os.Open(tlo.from)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read template %q", tlo.from)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	out, err := c.LintTemplate(client.LintTemplateOptions{
+		ReaderSource: &client.ReaderSourceOptions{Reader: r},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := printTemplateLintOutput(w, out); err != nil {
+		return err
+	}
+
+	if tlo.failOn != "none" && out.HasFindings(client.LintSeverity(tlo.failOn)) {
+		return errors.Errorf("template has findings with severity %s or higher", tlo.failOn)
+	}
+	return nil
+}
+
+func printTemplateLintOutput(w io.Writer, out *client.LintTemplateOutput) error {
+	switch tlo.output {
+	case "text":
+		if len(out.Findings) == 0 {
+			fmt.Fprintln(w, "No issues found.")
+			return nil
+		}
+		table := tablewriter.NewWriter(w)
+		table.SetHeader([]string{"Severity", "Rule", "Object", "Field", "Message"})
+		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAutoWrapText(false)
+		table.SetCenterSeparator("")
+		table.SetColumnSeparator("")
+		table.SetRowSeparator("")
+		table.SetHeaderLine(false)
+		table.SetBorder(false)
+		for _, f := range out.Findings {
+			table.Append([]string{string(f.Severity), f.Rule, f.Object, f.Field, f.Message})
+		}
+		table.Render()
+		return nil
+	case "json":
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(b))
+		return nil
+	default:
+		return errors.Errorf("invalid output format: %s", tlo.output)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type topologyOwnershipOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+	showFields        bool
+}
+
+var to = &topologyOwnershipOptions{}
+
+var topologyOwnershipCmd = &cobra.Command{
+	Use:   "ownership CLUSTER",
+	Short: "Report the field ownership for the objects managed by the topology controller",
+	Long: LongDesc(`
+		Report the server side apply field ownership for the objects managed by the topology controller for a
+		Cluster with a managed topology, i.e. the Cluster, the InfrastructureCluster, the ControlPlane, the
+		MachineDeployments, their templates and the MachineHealthChecks.
+
+		Fields owned by the topology controller which are also owned by other field managers, e.g. because
+		they have been set by a user with kubectl, are reported as conflicts: those fields are going to be reset
+		to the value defined by the ClusterClass and the Cluster topology on the next reconcile.`),
+
+	Example: Examples(`
+		# Report the field managers for the objects of my-cluster and the conflicting fields.
+		clusterctl alpha topology ownership my-cluster
+
+		# Report all the fields owned by each field manager.
+		clusterctl alpha topology ownership my-cluster --show-fields`),
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTopologyOwnership(args[0])
+	},
+}
+
+func init() {
+	topologyOwnershipCmd.Flags().StringVar(&to.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for the management cluster. If unspecified, default discovery rules apply.")
+	topologyOwnershipCmd.Flags().StringVar(&to.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	topologyOwnershipCmd.Flags().StringVarP(&to.namespace, "namespace", "n", "",
+		"Namespace where the cluster is located. If unspecified, the current namespace will be used.")
+	topologyOwnershipCmd.Flags().BoolVar(&to.showFields, "show-fields", false,
+		"Show the fields owned by each field manager instead of the number of fields.")
+
+	// completions
+	topologyOwnershipCmd.ValidArgsFunction = resourceNameCompletionFunc(
+		topologyOwnershipCmd.Flags().Lookup("kubeconfig"),
+		topologyOwnershipCmd.Flags().Lookup("kubeconfig-context"),
+		topologyOwnershipCmd.Flags().Lookup("namespace"),
+		clusterv1.GroupVersion.String(),
+		"cluster",
+	)
+
+	topologyCmd.AddCommand(topologyOwnershipCmd)
+}
+
+func runTopologyOwnership(clusterName string) error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	out, err := c.TopologyOwnership(client.TopologyOwnershipOptions{
+		Kubeconfig:  client.Kubeconfig{Path: to.kubeconfig, Context: to.kubeconfigContext},
+		Namespace:   to.namespace,
+		ClusterName: clusterName,
+	})
+	if err != nil {
+		return err
+	}
+
+	table := newTopologyOwnershipTable([]string{"Object", "Manager", "Operation", "Fields"})
+	conflicts := 0
+	for _, obj := range out.Objects {
+		name := fmt.Sprintf("%s/%s", obj.Object.Kind, obj.Object.Name)
+		for _, m := range obj.Managers {
+			manager := m.Manager
+			if m.Subresource != "" {
+				manager = fmt.Sprintf("%s (%s)", m.Manager, m.Subresource)
+			}
+			fields := fmt.Sprintf("%d", len(m.Fields))
+			if to.showFields {
+				fields = strings.Join(m.Fields, "\n")
+			}
+			table.Append([]string{name, manager, string(m.Operation), fields})
+			name = ""
+		}
+		conflicts += len(obj.Conflicts)
+	}
+	table.Render()
+
+	if conflicts == 0 {
+		fmt.Println("\nNo fields owned by the topology controller are owned by other field managers.")
+		return nil
+	}
+
+	fmt.Printf("\n%d fields owned by the topology controller are also owned by other field managers, and they are going to be reset on the next reconcile:\n\n", conflicts)
+	table = newTopologyOwnershipTable([]string{"Object", "Field", "Also owned by"})
+	for _, obj := range out.Objects {
+		name := fmt.Sprintf("%s/%s", obj.Object.Kind, obj.Object.Name)
+		for _, conflict := range obj.Conflicts {
+			table.Append([]string{name, conflict.Field, strings.Join(conflict.Managers, ", ")})
+			name = ""
+		}
+	}
+	table.Render()
+	return nil
+}
+
+func newTopologyOwnershipTable(header []string) *tablewriter.Table {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	return table
+}
@@ -33,6 +33,7 @@ import (
 	"k8s.io/utils/exec"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
@@ -98,6 +99,15 @@ func init() {
 	topologyPlanCmd.Flags().StringVarP(&tp.namespace, "namespace", "n", "", "target namespace for the operation. If specified, it is used as default namespace for objects with missing namespace")
 	topologyPlanCmd.Flags().StringVarP(&tp.outDir, "output-directory", "o", "", "output directory to write details about created/modified objects")
 
+	// completions
+	_ = topologyPlanCmd.RegisterFlagCompletionFunc("cluster", resourceNameCompletionFunc(
+		topologyPlanCmd.Flags().Lookup("kubeconfig"),
+		topologyPlanCmd.Flags().Lookup("kubeconfig-context"),
+		topologyPlanCmd.Flags().Lookup("namespace"),
+		clusterv1.GroupVersion.String(),
+		"cluster",
+	))
+
 	if err := topologyPlanCmd.MarkFlagRequired("file"); err != nil {
 		panic(err)
 	}
@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type topologyRebaseOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	namespace         string
+	selector          string
+	toClass           string
+	dryRun            bool
+	allowDowngrade    bool
+}
+
+var tr = &topologyRebaseOptions{}
+
+var topologyRebaseCmd = &cobra.Command{
+	Use:   "rebase [CLUSTER...]",
+	Short: "Rebase clusters to a newer revision of their ClusterClass",
+	Long: LongDesc(`
+		Rebase clusters with a managed topology to a newer revision of their ClusterClass.
+
+		Revisions of a ClusterClass are ClusterClasses with the same spec.lineage.name and a different
+		spec.lineage.revision. If --to-class is not specified, clusters are rebased to the latest revision
+		in the lineage of their current ClusterClass. Rebasing to an older revision requires --allow-downgrade.
+
+		Clusters can be selected by name or using a label selector, so a new revision can be rolled out to
+		the fleet gradually. Compatibility checks are executed by the Cluster API webhooks; use --dry-run
+		to run them without changing the clusters.`),
+
+	Example: Examples(`
+		# Rebase my-cluster to the latest revision in the lineage of its ClusterClass.
+		clusterctl alpha topology rebase my-cluster
+
+		# Check if the clusters with the label fleet-wave=1 can be rebased to my-class-v2.
+		clusterctl alpha topology rebase --selector fleet-wave=1 --to-class my-class-v2 --dry-run
+
+		# Roll back my-cluster to my-class-v1, an older revision of its ClusterClass.
+		clusterctl alpha topology rebase my-cluster --to-class my-class-v1 --allow-downgrade`),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTopologyRebase(args)
+	},
+}
+
+func init() {
+	topologyRebaseCmd.Flags().StringVar(&tr.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for the management cluster. If unspecified, default discovery rules apply.")
+	topologyRebaseCmd.Flags().StringVar(&tr.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	topologyRebaseCmd.Flags().StringVarP(&tr.namespace, "namespace", "n", "",
+		"Namespace where the clusters are located. If unspecified, the current namespace will be used.")
+	topologyRebaseCmd.Flags().StringVarP(&tr.selector, "selector", "l", "",
+		"Label selector for the clusters to rebase, e.g. fleet-wave=1; it can't be used together with cluster names.")
+	topologyRebaseCmd.Flags().StringVar(&tr.toClass, "to-class", "",
+		"Name of the ClusterClass to rebase the clusters to. If unspecified, the latest revision in the lineage of the current ClusterClass is used.")
+	topologyRebaseCmd.Flags().BoolVar(&tr.dryRun, "dry-run", false,
+		"Validate the rebase, including webhook compatibility checks, without changing the clusters.")
+	topologyRebaseCmd.Flags().BoolVar(&tr.allowDowngrade, "allow-downgrade", false,
+		"Allow rebasing the clusters to an older revision of their ClusterClass, e.g. to roll back a rebase. It sets the unsafe.topology.cluster.x-k8s.io/allow-class-downgrade annotation on the clusters.")
+
+	// completions
+	topologyRebaseCmd.ValidArgsFunction = resourceNameCompletionFunc(
+		topologyRebaseCmd.Flags().Lookup("kubeconfig"),
+		topologyRebaseCmd.Flags().Lookup("kubeconfig-context"),
+		topologyRebaseCmd.Flags().Lookup("namespace"),
+		clusterv1.GroupVersion.String(),
+		"cluster",
+	)
+	_ = topologyRebaseCmd.RegisterFlagCompletionFunc("to-class", resourceNameCompletionFunc(
+		topologyRebaseCmd.Flags().Lookup("kubeconfig"),
+		topologyRebaseCmd.Flags().Lookup("kubeconfig-context"),
+		topologyRebaseCmd.Flags().Lookup("namespace"),
+		clusterv1.GroupVersion.String(),
+		"clusterclass",
+	))
+
+	topologyCmd.AddCommand(topologyRebaseCmd)
+}
+
+func runTopologyRebase(args []string) error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	out, err := c.TopologyRebase(client.TopologyRebaseOptions{
+		Kubeconfig:     client.Kubeconfig{Path: tr.kubeconfig, Context: tr.kubeconfigContext},
+		Namespace:      tr.namespace,
+		ClusterNames:   args,
+		Selector:       tr.selector,
+		ToClass:        tr.toClass,
+		DryRun:         tr.dryRun,
+		AllowDowngrade: tr.allowDowngrade,
+	})
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Namespace", "Cluster", "From", "To", "Result"})
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+
+	failed := 0
+	for _, r := range out.Clusters {
+		result := "up-to-date"
+		switch {
+		case r.Error != nil:
+			result = fmt.Sprintf("failed: %v", r.Error)
+			failed++
+		case r.Rebased && tr.dryRun:
+			result = "rebase validated (dry run)"
+		case r.Rebased:
+			result = "rebased"
+		}
+		table.Append([]string{r.Cluster.Namespace, r.Cluster.Name, r.FromClass, r.ToClass, result})
+	}
+	table.Render()
+
+	if failed > 0 {
+		return errors.Errorf("failed to rebase %d of %d clusters", failed, len(out.Clusters))
+	}
+	return nil
+}
@@ -17,8 +17,13 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
 	"time"
 
+	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
@@ -37,6 +42,8 @@ type upgradeApplyOptions struct {
 	runtimeExtensionProviders []string
 	waitProviders             bool
 	waitProviderTimeout       int
+	staged                    bool
+	output                    string
 }
 
 var ua = &upgradeApplyOptions{}
@@ -58,10 +65,14 @@ var upgradeApplyCmd = &cobra.Command{
 		clusterctl upgrade apply --contract v1alpha4
 
 		# Upgrades only the aws provider to the v2.0.1 version.
-		clusterctl upgrade apply --infrastructure aws:v2.0.1`),
+		clusterctl upgrade apply --infrastructure aws:v2.0.1
+
+		# Upgrades the providers one at a time, verifying each provider after the upgrade
+		# and rolling it back to the previous version if the verification fails.
+		clusterctl upgrade apply --contract v1beta1 --staged`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runUpgradeApply()
+		return runUpgradeApply(os.Stdout)
 	},
 }
 
@@ -88,10 +99,28 @@ func init() {
 	upgradeApplyCmd.Flags().BoolVar(&ua.waitProviders, "wait-providers", false,
 		"Wait for providers to be upgraded.")
 	upgradeApplyCmd.Flags().IntVar(&ua.waitProviderTimeout, "wait-provider-timeout", 5*60,
-		"Wait timeout per provider upgrade in seconds. This value is ignored if --wait-providers is false")
+		"Wait timeout per provider upgrade in seconds. This value is ignored if --wait-providers is false, unless --staged is set")
+	upgradeApplyCmd.Flags().BoolVar(&ua.staged, "staged", false,
+		"Upgrade the providers one at a time, verifying deployments availability, webhooks reachability and CRD storage versions after each upgrade; "+
+			"if the verification fails, the provider is rolled back to the previous version and the upgrade is stopped.")
+	upgradeApplyCmd.Flags().StringVarP(&ua.output, "output", "o", "text",
+		"Output format for the result of a staged upgrade; available options are 'text' and 'json'. This value is ignored if --staged is false")
+
+	// completions
+	for flagName, providerType := range providerFlags {
+		_ = upgradeApplyCmd.RegisterFlagCompletionFunc(flagName, installedProviderCompletionFunc(
+			upgradeApplyCmd.Flags().Lookup("kubeconfig"),
+			upgradeApplyCmd.Flags().Lookup("kubeconfig-context"),
+			providerType,
+		))
+	}
 }
 
-func runUpgradeApply() error {
+func runUpgradeApply(w io.Writer) error {
+	if ua.output != "text" && ua.output != "json" {
+		return errors.Errorf("invalid output format: %s", ua.output)
+	}
+
 	c, err := client.New(cfgFile)
 	if err != nil {
 		return err
@@ -111,7 +140,7 @@ func runUpgradeApply() error {
 		return errors.New("The --contract flag can't be used in combination with --core, --bootstrap, --control-plane, --infrastructure, --ipam, --extension")
 	}
 
-	return c.ApplyUpgrade(client.ApplyUpgradeOptions{
+	options := client.ApplyUpgradeOptions{
 		Kubeconfig:                client.Kubeconfig{Path: ua.kubeconfig, Context: ua.kubeconfigContext},
 		Contract:                  ua.contract,
 		CoreProvider:              ua.coreProvider,
@@ -122,5 +151,53 @@ func runUpgradeApply() error {
 		RuntimeExtensionProviders: ua.runtimeExtensionProviders,
 		WaitProviders:             ua.waitProviders,
 		WaitProviderTimeout:       time.Duration(ua.waitProviderTimeout) * time.Second,
-	})
+	}
+
+	if !ua.staged {
+		return c.ApplyUpgrade(options)
+	}
+
+	result, err := c.ApplyStagedUpgrade(options)
+	if result != nil {
+		if printErr := printUpgradeResult(w, result); printErr != nil && err == nil {
+			err = printErr
+		}
+	}
+	return err
+}
+
+func printUpgradeResult(w io.Writer, result *client.UpgradeResult) error {
+	if ua.output == "json" {
+		b, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(b))
+		return nil
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Provider", "Type", "From Version", "To Version", "Status", "Error"})
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+
+	for _, p := range result.Providers {
+		toVersion := p.ToVersion
+		if toVersion == "" {
+			toVersion = "-"
+		}
+		errMsg := p.Error
+		if errMsg == "" {
+			errMsg = "-"
+		}
+		table.Append([]string{p.Provider, p.Type, p.FromVersion, toVersion, string(p.Status), errMsg})
+	}
+	table.Render()
+	return nil
 }
@@ -21,6 +21,7 @@ import (
 	"os"
 	"text/tabwriter"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -30,6 +31,8 @@ import (
 type upgradePlanOptions struct {
 	kubeconfig        string
 	kubeconfigContext string
+	to                string
+	simulate          bool
 }
 
 var up = &upgradePlanOptions{}
@@ -46,11 +49,19 @@ var upgradePlanCmd = &cobra.Command{
 
 		Then, for each provider, the following upgrade options are provided:
 		- The latest patch release for the current API Version of Cluster API (contract).
-		- The latest patch release for the next API Version of Cluster API (contract), if available.`),
+		- The latest patch release for the next API Version of Cluster API (contract), if available.
+
+		When using --simulate with --to, the command instead computes the full upgrade path to the target version
+		of the core provider, which could span several API Versions of Cluster API (contract), listing for each step the
+		target versions of the providers, the version of clusterctl to be used, the contract changes requiring the
+		migration of objects to new CRD storage versions and the issues blocking the step, if any.`),
 
 	Example: Examples(`
 		# Gets the recommended target versions for upgrading Cluster API providers.
-		clusterctl upgrade plan`),
+		clusterctl upgrade plan
+
+		# Simulates the upgrade of the management cluster to Cluster API v1.5.0, listing all the required steps.
+		clusterctl upgrade plan --to v1.5.0 --simulate`),
 
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runUpgradePlan()
@@ -62,14 +73,26 @@ func init() {
 		"Path to the kubeconfig file to use for accessing the management cluster. If empty, default discovery rules apply.")
 	upgradePlanCmd.Flags().StringVar(&up.kubeconfigContext, "kubeconfig-context", "",
 		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	upgradePlanCmd.Flags().StringVar(&up.to, "to", "",
+		"Target version of the core provider for simulating the upgrade path, e.g. v1.5.0. Requires --simulate.")
+	upgradePlanCmd.Flags().BoolVar(&up.simulate, "simulate", false,
+		"Compute and print all the steps required for upgrading the management cluster to the version defined with --to.")
 }
 
 func runUpgradePlan() error {
+	if up.simulate != (up.to != "") {
+		return errors.New("--to and --simulate must be used together")
+	}
+
 	c, err := client.New(cfgFile)
 	if err != nil {
 		return err
 	}
 
+	if up.simulate {
+		return runUpgradePlanSimulate(c)
+	}
+
 	certManUpgradePlan, err := c.PlanCertManagerUpgrade(client.PlanUpgradeOptions{
 		Kubeconfig: client.Kubeconfig{Path: up.kubeconfig, Context: up.kubeconfigContext},
 	})
@@ -138,3 +161,54 @@ func runUpgradePlan() error {
 
 	return nil
 }
+
+func runUpgradePlanSimulate(c client.Client) error {
+	upgradePath, err := c.PlanUpgradePath(client.PlanUpgradePathOptions{
+		Kubeconfig:    client.Kubeconfig{Path: up.kubeconfig, Context: up.kubeconfigContext},
+		TargetVersion: up.to,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("")
+	fmt.Printf("Upgrade path for %s from %s to %s:\n", upgradePath.CoreProvider.InstanceName(), upgradePath.CoreProvider.Version, upgradePath.TargetVersion)
+
+	blocked := false
+	for i := range upgradePath.Steps {
+		step := upgradePath.Steps[i]
+		// ensure provider are sorted consistently (by Type, Name, Namespace).
+		sortUpgradeItems(client.UpgradePlan(step.UpgradePlan))
+
+		fmt.Println("")
+		fmt.Printf("Step %d: upgrade to the %s API Version of Cluster API (contract)\n", i+1, step.Contract)
+		fmt.Println("")
+		w := tabwriter.NewWriter(os.Stdout, 10, 4, 3, ' ', 0)
+		fmt.Fprintln(w, "NAME\tNAMESPACE\tTYPE\tCURRENT VERSION\tTARGET VERSION")
+		for _, upgradeItem := range step.Providers {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", upgradeItem.Provider.Name, upgradeItem.Provider.Namespace, upgradeItem.Provider.Type, upgradeItem.Provider.Version, prettifyTargetVersion(upgradeItem.NextVersion))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Println("")
+
+		if step.IsContractChange() {
+			fmt.Printf("- Contract change from %s to %s: the objects of the providers will be migrated to the new storage version of the CRDs during the upgrade.\n", step.PreviousContract, step.Contract)
+		}
+		if step.ClusterctlVersion != "" {
+			fmt.Printf("- Use clusterctl %s for applying this step.\n", step.ClusterctlVersion)
+		}
+		for _, blocker := range step.Blockers {
+			blocked = true
+			fmt.Printf("- Blocker: %s\n", blocker)
+		}
+	}
+	fmt.Println("")
+
+	if blocked {
+		fmt.Println("The upgrade path is blocked; please address the blockers before starting the upgrade.")
+		fmt.Println("")
+	}
+	return nil
+}
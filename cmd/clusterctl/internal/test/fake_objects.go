@@ -53,6 +53,7 @@ type FakeCluster struct {
 	withCloudConfigSecret bool
 	withCredentialSecret  bool
 	topologyClass         *string
+	labels                map[string]string
 }
 
 // NewFakeCluster return a FakeCluster that can generate a cluster object, all its own ancillary objects:
@@ -108,6 +109,11 @@ func (f *FakeCluster) WithTopologyClass(class string) *FakeCluster {
 	return f
 }
 
+func (f *FakeCluster) WithLabels(labels map[string]string) *FakeCluster {
+	f.labels = labels
+	return f
+}
+
 func (f *FakeCluster) Objs() []client.Object {
 	clusterInfrastructure := &fakeinfrastructure.GenericInfrastructureCluster{
 		TypeMeta: metav1.TypeMeta{
@@ -130,6 +136,7 @@ func (f *FakeCluster) Objs() []client.Object {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      f.name,
 			Namespace: f.namespace,
+			Labels:    f.labels,
 			// Labels: cluster.x-k8s.io/cluster-name=cluster MISSING??
 		},
 		Spec: clusterv1.ClusterSpec{
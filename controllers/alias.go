@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,8 +42,10 @@ import (
 
 // ClusterReconciler reconciles a Cluster object.
 type ClusterReconciler struct {
-	Client    client.Client
-	APIReader client.Reader
+	Client                    client.Client
+	UnstructuredCachingClient client.Client
+	APIReader                 client.Reader
+	Tracker                   *remote.ClusterCacheTracker
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
@@ -50,9 +53,11 @@ type ClusterReconciler struct {
 
 func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&clustercontroller.Reconciler{
-		Client:           r.Client,
-		APIReader:        r.APIReader,
-		WatchFilterValue: r.WatchFilterValue,
+		Client:                    r.Client,
+		UnstructuredCachingClient: r.UnstructuredCachingClient,
+		APIReader:                 r.APIReader,
+		Tracker:                   r.Tracker,
+		WatchFilterValue:          r.WatchFilterValue,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -64,14 +69,28 @@ type MachineReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// AdditionalSyncMachineLabelDomains is a list of additional label domains which are synced from
+	// Machines to Nodes.
+	AdditionalSyncMachineLabelDomains []string
+
+	// NodeSnapshotTTL is the duration for which the snapshot of the Node of a Machine, captured before deletion,
+	// is retained; snapshots are not captured if it is zero.
+	NodeSnapshotTTL time.Duration
+
+	// RuntimeClient is a client for calling runtime extensions.
+	RuntimeClient runtimeclient.Client
 }
 
 func (r *MachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&machinecontroller.Reconciler{
-		Client:           r.Client,
-		APIReader:        r.APIReader,
-		Tracker:          r.Tracker,
-		WatchFilterValue: r.WatchFilterValue,
+		Client:                            r.Client,
+		APIReader:                         r.APIReader,
+		Tracker:                           r.Tracker,
+		WatchFilterValue:                  r.WatchFilterValue,
+		AdditionalSyncMachineLabelDomains: r.AdditionalSyncMachineLabelDomains,
+		NodeSnapshotTTL:                   r.NodeSnapshotTTL,
+		RuntimeClient:                     r.RuntimeClient,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
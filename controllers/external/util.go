@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/hash"
 )
 
 // Get uses the client and reference to get an external, unstructured object.
@@ -150,6 +151,11 @@ func GenerateTemplate(in *GenerateTemplateInput) (*unstructured.Unstructured, er
 		return nil, errors.Wrapf(err, "failed to retrieve Spec.Template map on %v %q", in.Template.GroupVersionKind(), in.Template.GetName())
 	}
 
+	checksum, err := hash.ComputeTemplatesChecksum(in.Template)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute the checksum of %v %q", in.Template.GroupVersionKind(), in.Template.GetName())
+	}
+
 	// Create the unstructured object from the template.
 	to := &unstructured.Unstructured{Object: template}
 	to.SetResourceVersion("")
@@ -169,6 +175,7 @@ func GenerateTemplate(in *GenerateTemplateInput) (*unstructured.Unstructured, er
 	}
 	annotations[clusterv1.TemplateClonedFromNameAnnotation] = in.TemplateRef.Name
 	annotations[clusterv1.TemplateClonedFromGroupKindAnnotation] = in.TemplateRef.GroupVersionKind().GroupKind().String()
+	annotations[clusterv1.TemplateClonedFromChecksumAnnotation] = checksum
 	to.SetAnnotations(annotations)
 
 	// Set labels.
@@ -216,6 +216,7 @@ func TestCloneTemplateResourceFound(t *testing.T) {
 
 	g.Expect(cloneAnnotations).To(HaveKeyWithValue(clusterv1.TemplateClonedFromNameAnnotation, templateRef.Name))
 	g.Expect(cloneAnnotations).To(HaveKeyWithValue(clusterv1.TemplateClonedFromGroupKindAnnotation, templateRef.GroupVersionKind().GroupKind().String()))
+	g.Expect(cloneAnnotations).To(HaveKey(clusterv1.TemplateClonedFromChecksumAnnotation))
 }
 
 func TestCloneTemplateResourceFoundNoOwner(t *testing.T) {
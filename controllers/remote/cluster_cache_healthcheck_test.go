@@ -75,8 +75,9 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 			t.Log("Setting up a ClusterCacheTracker")
 			log := klogr.New()
 			cct, err = NewClusterCacheTracker(mgr, ClusterCacheTrackerOptions{
-				Log:     &log,
-				Indexes: DefaultIndexes,
+				Log:                &log,
+				Indexes:            DefaultIndexes,
+				ReportReachability: true,
 			})
 			g.Expect(err).NotTo(HaveOccurred())
 
@@ -144,6 +145,15 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 				_, ok := cct.loadAccessor(testClusterKey)
 				return ok
 			}, 5*time.Second, 1*time.Second).Should(BeTrue())
+
+			// The Cluster should be reported as reachable.
+			g.Eventually(func() bool {
+				cluster := &clusterv1.Cluster{}
+				if err := k8sClient.Get(ctx, testClusterKey, cluster); err != nil {
+					return false
+				}
+				return conditions.IsTrue(cluster, clusterv1.WorkloadClusterReachableCondition)
+			}, 5*time.Second, 1*time.Second).Should(BeTrue())
 		})
 
 		t.Run("during creation of a new cluster accessor", func(t *testing.T) {
@@ -195,6 +205,18 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 				_, ok := cct.loadAccessor(testClusterKey)
 				return ok
 			}, 5*time.Second, 1*time.Second).Should(BeFalse())
+
+			// The Cluster should be reported as unreachable.
+			g.Eventually(func() string {
+				cluster := &clusterv1.Cluster{}
+				if err := k8sClient.Get(ctx, testClusterKey, cluster); err != nil {
+					return ""
+				}
+				if !conditions.IsFalse(cluster, clusterv1.WorkloadClusterReachableCondition) {
+					return ""
+				}
+				return conditions.GetReason(cluster, clusterv1.WorkloadClusterReachableCondition)
+			}, 5*time.Second, 1*time.Second).Should(Equal(clusterv1.WorkloadClusterUnreachableReason))
 		})
 
 		t.Run("with an invalid config", func(t *testing.T) {
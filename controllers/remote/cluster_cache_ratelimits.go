@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// clientRateLimits returns the QPS and burst to be used for the client and the cache of the given workload cluster.
+// The rate limits of the ClusterCacheTracker are used, unless they are overridden by annotations on the Cluster.
+func (t *ClusterCacheTracker) clientRateLimits(ctx context.Context, cluster client.ObjectKey) (float32, int, error) {
+	c := &clusterv1.Cluster{}
+	if err := t.client.Get(ctx, cluster, c); err != nil {
+		if apierrors.IsNotFound(err) {
+			return t.clientQPS, t.clientBurst, nil
+		}
+		return 0, 0, errors.Wrapf(err, "error getting Cluster %q to compute the client rate limits", cluster.String())
+	}
+
+	qps, burst := clientRateLimitsFromAnnotations(ctrl.LoggerFrom(ctx), c.GetAnnotations(), t.clientQPS, t.clientBurst)
+	return qps, burst, nil
+}
+
+// clientRateLimitsFromAnnotations returns the QPS and burst set with the RemoteClientQPSAnnotation and the
+// RemoteClientBurstAnnotation, or the given defaults if the annotations are not set or have invalid values.
+func clientRateLimitsFromAnnotations(log logr.Logger, annotations map[string]string, defaultQPS float32, defaultBurst int) (float32, int) {
+	qps, burst := defaultQPS, defaultBurst
+
+	if value, ok := annotations[clusterv1.RemoteClientQPSAnnotation]; ok {
+		v, err := strconv.ParseFloat(value, 32)
+		if err != nil || v <= 0 {
+			log.Info("Ignoring invalid value of the remote client QPS annotation, it must be a positive number", "annotation", clusterv1.RemoteClientQPSAnnotation, "value", value)
+		} else {
+			qps = float32(v)
+		}
+	}
+
+	if value, ok := annotations[clusterv1.RemoteClientBurstAnnotation]; ok {
+		v, err := strconv.Atoi(value)
+		if err != nil || v <= 0 {
+			log.Info("Ignoring invalid value of the remote client burst annotation, it must be a positive integer", "annotation", clusterv1.RemoteClientBurstAnnotation, "value", value)
+		} else {
+			burst = v
+		}
+	}
+
+	return qps, burst
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestClientRateLimitsFromAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantQPS     float32
+		wantBurst   int
+	}{
+		{
+			name:      "uses the defaults if the annotations are not set",
+			wantQPS:   20,
+			wantBurst: 30,
+		},
+		{
+			name: "uses the values of the annotations",
+			annotations: map[string]string{
+				clusterv1.RemoteClientQPSAnnotation:   "50.5",
+				clusterv1.RemoteClientBurstAnnotation: "100",
+			},
+			wantQPS:   50.5,
+			wantBurst: 100,
+		},
+		{
+			name: "overrides only the QPS",
+			annotations: map[string]string{
+				clusterv1.RemoteClientQPSAnnotation: "5",
+			},
+			wantQPS:   5,
+			wantBurst: 30,
+		},
+		{
+			name: "ignores invalid values",
+			annotations: map[string]string{
+				clusterv1.RemoteClientQPSAnnotation:   "fast",
+				clusterv1.RemoteClientBurstAnnotation: "-1",
+			},
+			wantQPS:   20,
+			wantBurst: 30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			qps, burst := clientRateLimitsFromAnnotations(logr.Discard(), tt.annotations, 20, 30)
+			g.Expect(qps).To(Equal(tt.wantQPS))
+			g.Expect(burst).To(Equal(tt.wantBurst))
+		})
+	}
+}
+
+func TestClusterCacheTracker_clientRateLimits(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+			Annotations: map[string]string{
+				clusterv1.RemoteClientQPSAnnotation:   "100",
+				clusterv1.RemoteClientBurstAnnotation: "200",
+			},
+		},
+	}
+	tracker := &ClusterCacheTracker{
+		client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build(),
+		clientQPS:   20,
+		clientBurst: 30,
+	}
+
+	qps, burst, err := tracker.clientRateLimits(ctx, client.ObjectKeyFromObject(cluster))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(qps).To(Equal(float32(100)))
+	g.Expect(burst).To(Equal(200))
+
+	// The defaults of the tracker are used if the Cluster does not exist.
+	qps, burst, err = tracker.clientRateLimits(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "does-not-exist"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(qps).To(Equal(float32(20)))
+	g.Expect(burst).To(Equal(30))
+}
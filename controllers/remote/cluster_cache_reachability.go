@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(workloadClusterReachabilityTransitionsTotal)
+}
+
+// workloadClusterReachabilityTransitionsTotal reports the transitions of the WorkloadClusterReachable condition.
+var workloadClusterReachabilityTransitionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Subsystem: "capi_cluster_cache_tracker",
+	Name:      "workload_cluster_reachability_transitions_total",
+	Help:      "Number of transitions of the reachability of workload clusters, partitioned by cluster and reachability.",
+}, []string{"name", "namespace", "reachable"})
+
+// markWorkloadClusterReachable sets the WorkloadClusterReachable condition on the Cluster to true.
+func (t *ClusterCacheTracker) markWorkloadClusterReachable(ctx context.Context, cluster *clusterv1.Cluster, latency time.Duration) {
+	if !t.reportReachability || conditions.IsTrue(cluster, clusterv1.WorkloadClusterReachableCondition) {
+		return
+	}
+
+	t.setWorkloadClusterReachable(ctx, cluster, func() {
+		conditions.MarkTrue(cluster, clusterv1.WorkloadClusterReachableCondition)
+	})
+	t.recordEventf(cluster, corev1.EventTypeNormal, string(clusterv1.WorkloadClusterReachableCondition), "Workload cluster is reachable, health check latency %s", latency.Round(time.Millisecond))
+}
+
+// markWorkloadClusterUnreachable sets the WorkloadClusterReachable condition on the Cluster to false
+// with the given reason and error.
+func (t *ClusterCacheTracker) markWorkloadClusterUnreachable(ctx context.Context, cluster *clusterv1.Cluster, reason string, severity clusterv1.ConditionSeverity, err error) {
+	if !t.reportReachability {
+		return
+	}
+	if conditions.IsFalse(cluster, clusterv1.WorkloadClusterReachableCondition) && conditions.GetReason(cluster, clusterv1.WorkloadClusterReachableCondition) == reason {
+		return
+	}
+
+	t.setWorkloadClusterReachable(ctx, cluster, func() {
+		conditions.MarkFalse(cluster, clusterv1.WorkloadClusterReachableCondition, reason, severity, "%v", err)
+	})
+	t.recordEventf(cluster, corev1.EventTypeWarning, reason, "Workload cluster is not reachable: %v", err)
+}
+
+// setWorkloadClusterReachable patches the WorkloadClusterReachable condition on the Cluster after it has been
+// changed by the given func and increments the reachability transitions metric.
+// NOTE: Errors are only logged given that reporting reachability is best effort and must not interfere with the health checks.
+func (t *ClusterCacheTracker) setWorkloadClusterReachable(ctx context.Context, cluster *clusterv1.Cluster, setCondition func()) {
+	log := t.log.WithValues("Cluster", klog.KObj(cluster))
+
+	patchHelper, err := patch.NewHelper(cluster, t.client)
+	if err != nil {
+		log.Error(err, "Failed to update WorkloadClusterReachable condition")
+		return
+	}
+	setCondition()
+	if err := patchHelper.Patch(ctx, cluster, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+		clusterv1.WorkloadClusterReachableCondition,
+	}}); err != nil {
+		log.Error(err, "Failed to update WorkloadClusterReachable condition")
+		return
+	}
+
+	reachable := conditions.IsTrue(cluster, clusterv1.WorkloadClusterReachableCondition)
+	log.V(2).Info("Workload cluster reachability changed", "reachable", reachable)
+	workloadClusterReachabilityTransitionsTotal.WithLabelValues(cluster.Name, cluster.Namespace, strconv.FormatBool(reachable)).Inc()
+}
+
+// recordEventf records an event on the Cluster, if an event recorder is available.
+func (t *ClusterCacheTracker) recordEventf(cluster *clusterv1.Cluster, eventType string, reason string, messageFmt string, args ...interface{}) {
+	if t.recorder == nil {
+		return
+	}
+	t.recorder.Eventf(cluster, eventType, reason, messageFmt, args...)
+}
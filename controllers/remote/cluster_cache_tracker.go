@@ -36,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -48,6 +49,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/profiling"
 	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
@@ -69,6 +71,15 @@ type ClusterCacheTracker struct {
 	clientUncachedObjects []client.Object
 	client                client.Client
 	scheme                *runtime.Scheme
+	recorder              record.EventRecorder
+
+	// reportReachability is true if the reachability of workload clusters should be reported on the Cluster objects.
+	reportReachability bool
+
+	// clientQPS and clientBurst are the default rate limits of the clients to workload clusters; if not set,
+	// the client-go defaults are used.
+	clientQPS   float32
+	clientBurst int
 
 	// clusterAccessorsLock is used to lock the access to the clusterAccessors map.
 	clusterAccessorsLock sync.RWMutex
@@ -99,6 +110,22 @@ type ClusterCacheTrackerOptions struct {
 	// Defaults to never caching ConfigMap and Secret if not set.
 	ClientUncachedObjects []client.Object
 	Indexes               []Index
+
+	// ReportReachability instructs the ClusterCacheTracker to report the reachability of workload clusters
+	// as observed by the health checks using the WorkloadClusterReachable condition on the Cluster object,
+	// events and metrics.
+	// NOTE: This should be enabled only in the manager owning the Cluster objects, i.e. the core Cluster API manager.
+	ReportReachability bool
+
+	// ClientQPS is the maximum queries per second from the clients and caches to a workload cluster.
+	// It can be overridden for a single Cluster using the RemoteClientQPSAnnotation.
+	// If not set, the client-go default is used.
+	ClientQPS float32
+
+	// ClientBurst is the maximum burst for throttling the clients and caches to a workload cluster.
+	// It can be overridden for a single Cluster using the RemoteClientBurstAnnotation.
+	// If not set, the client-go default is used.
+	ClientBurst int
 }
 
 func setDefaultOptions(opts *ClusterCacheTrackerOptions) {
@@ -140,9 +167,13 @@ func NewClusterCacheTracker(manager ctrl.Manager, options ClusterCacheTrackerOpt
 		clientUncachedObjects: options.ClientUncachedObjects,
 		client:                manager.GetClient(),
 		scheme:                manager.GetScheme(),
+		recorder:              manager.GetEventRecorderFor(clusterCacheControllerName),
 		clusterAccessors:      make(map[client.ObjectKey]*clusterAccessor),
 		clusterLock:           newKeyedMutex(),
 		indexes:               options.Indexes,
+		reportReachability:    options.ReportReachability,
+		clientQPS:             options.ClientQPS,
+		clientBurst:           options.ClientBurst,
 	}, nil
 }
 
@@ -153,6 +184,9 @@ func (t *ClusterCacheTracker) GetClient(ctx context.Context, cluster client.Obje
 		return nil, err
 	}
 
+	if profiling.Enabled() {
+		return profiling.NewPhaseClient(accessor.client, profiling.PhaseRemote), nil
+	}
 	return accessor.client, nil
 }
 
@@ -232,6 +266,7 @@ func (t *ClusterCacheTracker) getClusterAccessor(ctx context.Context, cluster cl
 	log.V(4).Info("Creating new cluster accessor")
 	accessor, err := t.newClusterAccessor(ctx, cluster, indexes...)
 	if err != nil {
+		t.markConnectionFailed(ctx, cluster, err)
 		return nil, errors.Wrap(err, "failed to create cluster accessor")
 	}
 
@@ -240,6 +275,24 @@ func (t *ClusterCacheTracker) getClusterAccessor(ctx context.Context, cluster cl
 	return accessor, nil
 }
 
+// markConnectionFailed reports on the Cluster that it was not possible to connect to the workload cluster.
+// NOTE: failures are reported only for clusters that are expected to be reachable, i.e. after the control plane
+// has been initialized.
+func (t *ClusterCacheTracker) markConnectionFailed(ctx context.Context, cluster client.ObjectKey, err error) {
+	if !t.reportReachability {
+		return
+	}
+
+	c := &clusterv1.Cluster{}
+	if err := t.client.Get(ctx, cluster, c); err != nil {
+		return
+	}
+	if !c.Status.InfrastructureReady || !conditions.IsTrue(c, clusterv1.ControlPlaneInitializedCondition) {
+		return
+	}
+	t.markWorkloadClusterUnreachable(ctx, c, clusterv1.WorkloadClusterConnectionFailedReason, clusterv1.ConditionSeverityWarning, err)
+}
+
 // newClusterAccessor creates a new clusterAccessor.
 func (t *ClusterCacheTracker) newClusterAccessor(ctx context.Context, cluster client.ObjectKey, indexes ...Index) (*clusterAccessor, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -250,6 +303,12 @@ func (t *ClusterCacheTracker) newClusterAccessor(ctx context.Context, cluster cl
 		return nil, errors.Wrapf(err, "error fetching REST client config for remote cluster %q", cluster.String())
 	}
 
+	// Set the rate limits for the client and the cache of the remote cluster.
+	config.QPS, config.Burst, err = t.clientRateLimits(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a client and a mapper for the cluster.
 	c, mapper, err := t.createClient(config, cluster)
 	if err != nil {
@@ -550,21 +609,26 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 
 		// An error here means there was either an issue connecting or the API returned an error.
 		// If no error occurs, reset the unhealthy counter.
+		start := time.Now()
 		_, err := restClient.Get().AbsPath(in.path).Timeout(in.requestTimeout).DoRaw(ctx)
 		if err != nil {
 			if apierrors.IsUnauthorized(err) {
 				// Unauthorized means that the underlying kubeconfig is not authorizing properly anymore, which
 				// usually is the result of automatic kubeconfig refreshes, meaning that we have to throw away the
 				// clusterAccessor and rely on the creation of a new one (with a refreshed kubeconfig)
+				t.markWorkloadClusterUnreachable(ctx, cluster, clusterv1.WorkloadClusterUnauthorizedReason, clusterv1.ConditionSeverityError, err)
 				return false, err
 			}
 			unhealthyCount++
 		} else {
 			unhealthyCount = 0
+			t.markWorkloadClusterReachable(ctx, cluster, time.Since(start))
 		}
 
 		if unhealthyCount >= in.unhealthyThreshold {
 			// Cluster is now considered unhealthy.
+			t.markWorkloadClusterUnreachable(ctx, cluster, clusterv1.WorkloadClusterUnreachableReason, clusterv1.ConditionSeverityError,
+				errors.Wrapf(err, "%d consecutive health checks failed", unhealthyCount))
 			return false, err
 		}
 
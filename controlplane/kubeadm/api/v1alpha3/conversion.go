@@ -43,6 +43,7 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.KubeadmConfigSpec.Files = restored.Spec.KubeadmConfigSpec.Files
 	dst.Spec.KubeadmConfigSpec.Users = restored.Spec.KubeadmConfigSpec.Users
 	dst.Spec.MachineTemplate.NodeVolumeDetachTimeout = restored.Spec.MachineTemplate.NodeVolumeDetachTimeout
+	dst.Spec.MachineTemplate.HostCleanup = restored.Spec.MachineTemplate.HostCleanup
 	dst.Status.Version = restored.Status.Version
 
 	if restored.Spec.KubeadmConfigSpec.Users != nil {
@@ -68,6 +69,10 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.KubeadmConfigSpec.Ignition = restored.Spec.KubeadmConfigSpec.Ignition
+	dst.Spec.KubeadmConfigSpec.OSFamily = restored.Spec.KubeadmConfigSpec.OSFamily
+	dst.Spec.KubeadmConfigSpec.KubeletCredentialProviders = restored.Spec.KubeadmConfigSpec.KubeletCredentialProviders
+	dst.Spec.KubeadmConfigSpec.Snippets = restored.Spec.KubeadmConfigSpec.Snippets
+	dst.Spec.KubeadmConfigSpec.Groups = restored.Spec.KubeadmConfigSpec.Groups
 	if restored.Spec.KubeadmConfigSpec.InitConfiguration != nil {
 		if dst.Spec.KubeadmConfigSpec.InitConfiguration == nil {
 			dst.Spec.KubeadmConfigSpec.InitConfiguration = &bootstrapv1.InitConfiguration{}
@@ -106,6 +111,24 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Status.LastRemediation = restored.Status.LastRemediation
 	}
 
+	if restored.Spec.RolloutStrategy != nil && dst.Spec.RolloutStrategy != nil {
+		dst.Spec.RolloutStrategy.Order = restored.Spec.RolloutStrategy.Order
+	}
+	dst.Status.RolloutOrder = restored.Status.RolloutOrder
+
+	dst.Spec.EncryptionAtRest = restored.Spec.EncryptionAtRest
+	dst.Status.EncryptionAtRest = restored.Status.EncryptionAtRest
+	dst.Spec.CorefileOverrides = restored.Spec.CorefileOverrides
+	dst.Spec.InitialProvisioningStrategy = restored.Spec.InitialProvisioningStrategy
+	dst.Spec.InitConfigOverrides = restored.Spec.InitConfigOverrides
+	dst.Spec.JoinConfigOverrides = restored.Spec.JoinConfigOverrides
+	dst.Spec.ProgressDeadlineSeconds = restored.Spec.ProgressDeadlineSeconds
+	dst.Spec.UpgradePolicy = restored.Spec.UpgradePolicy
+	dst.Spec.InPlaceUpgrade = restored.Spec.InPlaceUpgrade
+	dst.Status.ProgressDeadline = restored.Status.ProgressDeadline
+	dst.Status.CertificatesExpiryDate = restored.Status.CertificatesExpiryDate
+	dst.Status.InitialProvisioningCompleted = restored.Status.InitialProvisioningCompleted
+
 	return nil
 }
 
@@ -154,3 +177,8 @@ func Convert_v1alpha3_KubeadmControlPlaneSpec_To_v1beta1_KubeadmControlPlaneSpec
 	out.MachineTemplate.NodeDrainTimeout = in.NodeDrainTimeout
 	return autoConvert_v1alpha3_KubeadmControlPlaneSpec_To_v1beta1_KubeadmControlPlaneSpec(in, out, s)
 }
+
+func Convert_v1beta1_RolloutStrategy_To_v1alpha3_RolloutStrategy(in *controlplanev1.RolloutStrategy, out *RolloutStrategy, s apiconversion.Scope) error {
+	// .Order was added in v1beta1.
+	return autoConvert_v1beta1_RolloutStrategy_To_v1alpha3_RolloutStrategy(in, out, s)
+}
@@ -81,11 +81,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.RolloutStrategy)(nil), (*RolloutStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_RolloutStrategy_To_v1alpha3_RolloutStrategy(a.(*v1beta1.RolloutStrategy), b.(*RolloutStrategy), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddConversionFunc((*KubeadmControlPlaneSpec)(nil), (*v1beta1.KubeadmControlPlaneSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_KubeadmControlPlaneSpec_To_v1beta1_KubeadmControlPlaneSpec(a.(*KubeadmControlPlaneSpec), b.(*v1beta1.KubeadmControlPlaneSpec), scope)
 	}); err != nil {
@@ -101,6 +96,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.RolloutStrategy)(nil), (*RolloutStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RolloutStrategy_To_v1alpha3_RolloutStrategy(a.(*v1beta1.RolloutStrategy), b.(*RolloutStrategy), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -187,7 +187,15 @@ func autoConvert_v1alpha3_KubeadmControlPlaneSpec_To_v1beta1_KubeadmControlPlane
 	}
 	// WARNING: in.UpgradeAfter requires manual conversion: does not exist in peer-type
 	// WARNING: in.NodeDrainTimeout requires manual conversion: does not exist in peer-type
-	out.RolloutStrategy = (*v1beta1.RolloutStrategy)(unsafe.Pointer(in.RolloutStrategy))
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(v1beta1.RolloutStrategy)
+		if err := Convert_v1alpha3_RolloutStrategy_To_v1beta1_RolloutStrategy(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RolloutStrategy = nil
+	}
 	return nil
 }
 
@@ -200,8 +208,24 @@ func autoConvert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha3_KubeadmControlPlane
 	}
 	// WARNING: in.RolloutBefore requires manual conversion: does not exist in peer-type
 	// WARNING: in.RolloutAfter requires manual conversion: does not exist in peer-type
-	out.RolloutStrategy = (*RolloutStrategy)(unsafe.Pointer(in.RolloutStrategy))
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategy)
+		if err := Convert_v1beta1_RolloutStrategy_To_v1alpha3_RolloutStrategy(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RolloutStrategy = nil
+	}
 	// WARNING: in.RemediationStrategy requires manual conversion: does not exist in peer-type
+	// WARNING: in.EncryptionAtRest requires manual conversion: does not exist in peer-type
+	// WARNING: in.CorefileOverrides requires manual conversion: does not exist in peer-type
+	// WARNING: in.InitialProvisioningStrategy requires manual conversion: does not exist in peer-type
+	// WARNING: in.InitConfigOverrides requires manual conversion: does not exist in peer-type
+	// WARNING: in.JoinConfigOverrides requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProgressDeadlineSeconds requires manual conversion: does not exist in peer-type
+	// WARNING: in.UpgradePolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.InPlaceUpgrade requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -259,6 +283,11 @@ func autoConvert_v1beta1_KubeadmControlPlaneStatus_To_v1alpha3_KubeadmControlPla
 		out.Conditions = nil
 	}
 	// WARNING: in.LastRemediation requires manual conversion: does not exist in peer-type
+	// WARNING: in.RolloutOrder requires manual conversion: does not exist in peer-type
+	// WARNING: in.EncryptionAtRest requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProgressDeadline requires manual conversion: does not exist in peer-type
+	// WARNING: in.CertificatesExpiryDate requires manual conversion: does not exist in peer-type
+	// WARNING: in.InitialProvisioningCompleted requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -296,10 +325,6 @@ func Convert_v1alpha3_RolloutStrategy_To_v1beta1_RolloutStrategy(in *RolloutStra
 func autoConvert_v1beta1_RolloutStrategy_To_v1alpha3_RolloutStrategy(in *v1beta1.RolloutStrategy, out *RolloutStrategy, s conversion.Scope) error {
 	out.Type = RolloutStrategyType(in.Type)
 	out.RollingUpdate = (*RollingUpdate)(unsafe.Pointer(in.RollingUpdate))
+	// WARNING: in.Order requires manual conversion: does not exist in peer-type
 	return nil
 }
-
-// Convert_v1beta1_RolloutStrategy_To_v1alpha3_RolloutStrategy is an autogenerated conversion function.
-func Convert_v1beta1_RolloutStrategy_To_v1alpha3_RolloutStrategy(in *v1beta1.RolloutStrategy, out *RolloutStrategy, s conversion.Scope) error {
-	return autoConvert_v1beta1_RolloutStrategy_To_v1alpha3_RolloutStrategy(in, out, s)
-}
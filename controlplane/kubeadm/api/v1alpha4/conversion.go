@@ -51,6 +51,10 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.KubeadmConfigSpec.Ignition = restored.Spec.KubeadmConfigSpec.Ignition
+	dst.Spec.KubeadmConfigSpec.OSFamily = restored.Spec.KubeadmConfigSpec.OSFamily
+	dst.Spec.KubeadmConfigSpec.KubeletCredentialProviders = restored.Spec.KubeadmConfigSpec.KubeletCredentialProviders
+	dst.Spec.KubeadmConfigSpec.Snippets = restored.Spec.KubeadmConfigSpec.Snippets
+	dst.Spec.KubeadmConfigSpec.Groups = restored.Spec.KubeadmConfigSpec.Groups
 	if restored.Spec.KubeadmConfigSpec.InitConfiguration != nil {
 		if dst.Spec.KubeadmConfigSpec.InitConfiguration == nil {
 			dst.Spec.KubeadmConfigSpec.InitConfiguration = &bootstrapv1.InitConfiguration{}
@@ -69,6 +73,7 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.MachineTemplate.NodeDeletionTimeout = restored.Spec.MachineTemplate.NodeDeletionTimeout
 	dst.Spec.RolloutBefore = restored.Spec.RolloutBefore
 	dst.Spec.MachineTemplate.NodeVolumeDetachTimeout = restored.Spec.MachineTemplate.NodeVolumeDetachTimeout
+	dst.Spec.MachineTemplate.HostCleanup = restored.Spec.MachineTemplate.HostCleanup
 
 	if restored.Spec.KubeadmConfigSpec.JoinConfiguration != nil && restored.Spec.KubeadmConfigSpec.JoinConfiguration.NodeRegistration.ImagePullPolicy != "" {
 		if dst.Spec.KubeadmConfigSpec.JoinConfiguration == nil {
@@ -91,6 +96,24 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Status.LastRemediation = restored.Status.LastRemediation
 	}
 
+	if restored.Spec.RolloutStrategy != nil && dst.Spec.RolloutStrategy != nil {
+		dst.Spec.RolloutStrategy.Order = restored.Spec.RolloutStrategy.Order
+	}
+	dst.Status.RolloutOrder = restored.Status.RolloutOrder
+
+	dst.Spec.EncryptionAtRest = restored.Spec.EncryptionAtRest
+	dst.Status.EncryptionAtRest = restored.Status.EncryptionAtRest
+	dst.Spec.CorefileOverrides = restored.Spec.CorefileOverrides
+	dst.Spec.InitialProvisioningStrategy = restored.Spec.InitialProvisioningStrategy
+	dst.Spec.InitConfigOverrides = restored.Spec.InitConfigOverrides
+	dst.Spec.JoinConfigOverrides = restored.Spec.JoinConfigOverrides
+	dst.Spec.ProgressDeadlineSeconds = restored.Spec.ProgressDeadlineSeconds
+	dst.Spec.UpgradePolicy = restored.Spec.UpgradePolicy
+	dst.Spec.InPlaceUpgrade = restored.Spec.InPlaceUpgrade
+	dst.Status.ProgressDeadline = restored.Status.ProgressDeadline
+	dst.Status.CertificatesExpiryDate = restored.Status.CertificatesExpiryDate
+	dst.Status.InitialProvisioningCompleted = restored.Status.InitialProvisioningCompleted
+
 	return nil
 }
 
@@ -133,6 +156,10 @@ func (src *KubeadmControlPlaneTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.Template.Spec.KubeadmConfigSpec.Files = restored.Spec.Template.Spec.KubeadmConfigSpec.Files
 	dst.Spec.Template.Spec.KubeadmConfigSpec.Users = restored.Spec.Template.Spec.KubeadmConfigSpec.Users
 	dst.Spec.Template.Spec.KubeadmConfigSpec.Ignition = restored.Spec.Template.Spec.KubeadmConfigSpec.Ignition
+	dst.Spec.Template.Spec.KubeadmConfigSpec.OSFamily = restored.Spec.Template.Spec.KubeadmConfigSpec.OSFamily
+	dst.Spec.Template.Spec.KubeadmConfigSpec.KubeletCredentialProviders = restored.Spec.Template.Spec.KubeadmConfigSpec.KubeletCredentialProviders
+	dst.Spec.Template.Spec.KubeadmConfigSpec.Snippets = restored.Spec.Template.Spec.KubeadmConfigSpec.Snippets
+	dst.Spec.Template.Spec.KubeadmConfigSpec.Groups = restored.Spec.Template.Spec.KubeadmConfigSpec.Groups
 	dst.Spec.Template.Spec.MachineTemplate = restored.Spec.Template.Spec.MachineTemplate
 
 	if restored.Spec.Template.Spec.KubeadmConfigSpec.Users != nil {
@@ -167,6 +194,7 @@ func (src *KubeadmControlPlaneTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	} else if restored.Spec.Template.Spec.MachineTemplate != nil {
 		dst.Spec.Template.Spec.MachineTemplate.NodeDeletionTimeout = restored.Spec.Template.Spec.MachineTemplate.NodeDeletionTimeout
 		dst.Spec.Template.Spec.MachineTemplate.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.MachineTemplate.NodeVolumeDetachTimeout
+		dst.Spec.Template.Spec.MachineTemplate.HostCleanup = restored.Spec.Template.Spec.MachineTemplate.HostCleanup
 	}
 
 	dst.Spec.Template.Spec.RolloutBefore = restored.Spec.Template.Spec.RolloutBefore
@@ -189,6 +217,19 @@ func (src *KubeadmControlPlaneTemplate) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.Template.Spec.RemediationStrategy = restored.Spec.Template.Spec.RemediationStrategy
 	}
 
+	if restored.Spec.Template.Spec.RolloutStrategy != nil && dst.Spec.Template.Spec.RolloutStrategy != nil {
+		dst.Spec.Template.Spec.RolloutStrategy.Order = restored.Spec.Template.Spec.RolloutStrategy.Order
+	}
+
+	dst.Spec.Template.Spec.EncryptionAtRest = restored.Spec.Template.Spec.EncryptionAtRest
+	dst.Spec.Template.Spec.CorefileOverrides = restored.Spec.Template.Spec.CorefileOverrides
+	dst.Spec.Template.Spec.InitialProvisioningStrategy = restored.Spec.Template.Spec.InitialProvisioningStrategy
+	dst.Spec.Template.Spec.InitConfigOverrides = restored.Spec.Template.Spec.InitConfigOverrides
+	dst.Spec.Template.Spec.JoinConfigOverrides = restored.Spec.Template.Spec.JoinConfigOverrides
+	dst.Spec.Template.Spec.ProgressDeadlineSeconds = restored.Spec.Template.Spec.ProgressDeadlineSeconds
+	dst.Spec.Template.Spec.UpgradePolicy = restored.Spec.Template.Spec.UpgradePolicy
+	dst.Spec.Template.Spec.InPlaceUpgrade = restored.Spec.Template.Spec.InPlaceUpgrade
+
 	return nil
 }
 
@@ -279,11 +320,24 @@ func Convert_v1beta1_KubeadmControlPlaneMachineTemplate_To_v1alpha4_KubeadmContr
 func Convert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in *controlplanev1.KubeadmControlPlaneSpec, out *KubeadmControlPlaneSpec, scope apiconversion.Scope) error {
 	// .RolloutBefore was added in v1beta1.
 	// .RemediationStrategy was added in v1beta1.
+	// .EncryptionAtRest was added in v1beta1.
+	// .CorefileOverrides was added in v1beta1.
+	// .InitialProvisioningStrategy was added in v1beta1.
+	// .InitConfigOverrides was added in v1beta1.
+	// .JoinConfigOverrides was added in v1beta1.
+	// .ProgressDeadlineSeconds was added in v1beta1.
+	// .UpgradePolicy was added in v1beta1.
+	// .InPlaceUpgrade was added in v1beta1.
 	return autoConvert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in, out, scope)
 }
 
 func Convert_v1beta1_KubeadmControlPlaneStatus_To_v1alpha4_KubeadmControlPlaneStatus(in *controlplanev1.KubeadmControlPlaneStatus, out *KubeadmControlPlaneStatus, scope apiconversion.Scope) error {
 	// .LastRemediation was added in v1beta1.
+	// .RolloutOrder was added in v1beta1.
+	// .EncryptionAtRest was added in v1beta1.
+	// .ProgressDeadline was added in v1beta1.
+	// .CertificatesExpiryDate was added in v1beta1.
+	// .InitialProvisioningCompleted was added in v1beta1.
 	return autoConvert_v1beta1_KubeadmControlPlaneStatus_To_v1alpha4_KubeadmControlPlaneStatus(in, out, scope)
 }
 
@@ -291,3 +345,8 @@ func Convert_v1beta1_KubeadmControlPlaneTemplateResource_To_v1alpha4_KubeadmCont
 	// .metadata and .spec.machineTemplate.metadata was added in v1beta1.
 	return autoConvert_v1beta1_KubeadmControlPlaneTemplateResource_To_v1alpha4_KubeadmControlPlaneTemplateResource(in, out, scope)
 }
+
+func Convert_v1beta1_RolloutStrategy_To_v1alpha4_RolloutStrategy(in *controlplanev1.RolloutStrategy, out *RolloutStrategy, s apiconversion.Scope) error {
+	// .Order was added in v1beta1.
+	return autoConvert_v1beta1_RolloutStrategy_To_v1alpha4_RolloutStrategy(in, out, s)
+}
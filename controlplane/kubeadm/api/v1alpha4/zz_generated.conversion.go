@@ -127,11 +127,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.RolloutStrategy)(nil), (*RolloutStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_RolloutStrategy_To_v1alpha4_RolloutStrategy(a.(*v1beta1.RolloutStrategy), b.(*RolloutStrategy), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddConversionFunc((*KubeadmControlPlaneSpec)(nil), (*v1beta1.KubeadmControlPlaneTemplateResourceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_KubeadmControlPlaneSpec_To_v1beta1_KubeadmControlPlaneTemplateResourceSpec(a.(*KubeadmControlPlaneSpec), b.(*v1beta1.KubeadmControlPlaneTemplateResourceSpec), scope)
 	}); err != nil {
@@ -162,6 +157,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.RolloutStrategy)(nil), (*RolloutStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RolloutStrategy_To_v1alpha4_RolloutStrategy(a.(*v1beta1.RolloutStrategy), b.(*RolloutStrategy), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -261,6 +261,7 @@ func autoConvert_v1beta1_KubeadmControlPlaneMachineTemplate_To_v1alpha4_KubeadmC
 	out.NodeDrainTimeout = (*v1.Duration)(unsafe.Pointer(in.NodeDrainTimeout))
 	// WARNING: in.NodeVolumeDetachTimeout requires manual conversion: does not exist in peer-type
 	// WARNING: in.NodeDeletionTimeout requires manual conversion: does not exist in peer-type
+	// WARNING: in.HostCleanup requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -274,7 +275,15 @@ func autoConvert_v1alpha4_KubeadmControlPlaneSpec_To_v1beta1_KubeadmControlPlane
 		return err
 	}
 	out.RolloutAfter = (*v1.Time)(unsafe.Pointer(in.RolloutAfter))
-	out.RolloutStrategy = (*v1beta1.RolloutStrategy)(unsafe.Pointer(in.RolloutStrategy))
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(v1beta1.RolloutStrategy)
+		if err := Convert_v1alpha4_RolloutStrategy_To_v1beta1_RolloutStrategy(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RolloutStrategy = nil
+	}
 	return nil
 }
 
@@ -294,8 +303,24 @@ func autoConvert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlane
 	}
 	// WARNING: in.RolloutBefore requires manual conversion: does not exist in peer-type
 	out.RolloutAfter = (*v1.Time)(unsafe.Pointer(in.RolloutAfter))
-	out.RolloutStrategy = (*RolloutStrategy)(unsafe.Pointer(in.RolloutStrategy))
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategy)
+		if err := Convert_v1beta1_RolloutStrategy_To_v1alpha4_RolloutStrategy(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RolloutStrategy = nil
+	}
 	// WARNING: in.RemediationStrategy requires manual conversion: does not exist in peer-type
+	// WARNING: in.EncryptionAtRest requires manual conversion: does not exist in peer-type
+	// WARNING: in.CorefileOverrides requires manual conversion: does not exist in peer-type
+	// WARNING: in.InitialProvisioningStrategy requires manual conversion: does not exist in peer-type
+	// WARNING: in.InitConfigOverrides requires manual conversion: does not exist in peer-type
+	// WARNING: in.JoinConfigOverrides requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProgressDeadlineSeconds requires manual conversion: does not exist in peer-type
+	// WARNING: in.UpgradePolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.InPlaceUpgrade requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -354,6 +379,11 @@ func autoConvert_v1beta1_KubeadmControlPlaneStatus_To_v1alpha4_KubeadmControlPla
 		out.Conditions = nil
 	}
 	// WARNING: in.LastRemediation requires manual conversion: does not exist in peer-type
+	// WARNING: in.RolloutOrder requires manual conversion: does not exist in peer-type
+	// WARNING: in.EncryptionAtRest requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProgressDeadline requires manual conversion: does not exist in peer-type
+	// WARNING: in.CertificatesExpiryDate requires manual conversion: does not exist in peer-type
+	// WARNING: in.InitialProvisioningCompleted requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -503,10 +533,6 @@ func Convert_v1alpha4_RolloutStrategy_To_v1beta1_RolloutStrategy(in *RolloutStra
 func autoConvert_v1beta1_RolloutStrategy_To_v1alpha4_RolloutStrategy(in *v1beta1.RolloutStrategy, out *RolloutStrategy, s conversion.Scope) error {
 	out.Type = RolloutStrategyType(in.Type)
 	out.RollingUpdate = (*RollingUpdate)(unsafe.Pointer(in.RollingUpdate))
+	// WARNING: in.Order requires manual conversion: does not exist in peer-type
 	return nil
 }
-
-// Convert_v1beta1_RolloutStrategy_To_v1alpha4_RolloutStrategy is an autogenerated conversion function.
-func Convert_v1beta1_RolloutStrategy_To_v1alpha4_RolloutStrategy(in *v1beta1.RolloutStrategy, out *RolloutStrategy, s conversion.Scope) error {
-	return autoConvert_v1beta1_RolloutStrategy_To_v1alpha4_RolloutStrategy(in, out, s)
-}
@@ -54,6 +54,25 @@ const (
 	// RollingUpdateInProgressReason (Severity=Warning) documents a KubeadmControlPlane object executing a
 	// rolling upgrade for aligning the machines spec to the desired state.
 	RollingUpdateInProgressReason = "RollingUpdateInProgress"
+
+	// RolloutPausedReason (Severity=Info) documents a KubeadmControlPlane object with machines with an outdated spec
+	// not being rolled out because the rollout is paused with the RolloutPausedAnnotation.
+	RolloutPausedReason = "RolloutPaused"
+
+	// InPlaceUpgradeInProgressReason (Severity=Warning) documents a KubeadmControlPlane object upgrading machines
+	// in place to a new patch version.
+	InPlaceUpgradeInProgressReason = "InPlaceUpgradeInProgress"
+)
+
+const (
+	// CertificateSANsUpToDateCondition documents that the API server serving certificates of all the machines
+	// controlled by the KubeadmControlPlane include the SANs defined in ClusterConfiguration.APIServer.CertSANs.
+	// When this condition is false, the condition message documents the SANs currently served vs the desired ones.
+	CertificateSANsUpToDateCondition clusterv1.ConditionType = "CertificateSANsUpToDate"
+
+	// CertificateSANsRolloutInProgressReason (Severity=Warning) documents a KubeadmControlPlane object rolling out
+	// machines for regenerating API server serving certificates with the desired SANs.
+	CertificateSANsRolloutInProgressReason = "CertificateSANsRolloutInProgress"
 )
 
 const (
@@ -149,3 +168,18 @@ const (
 	// generate a machine object.
 	MachineGenerationFailedReason = "MachineGenerationFailed"
 )
+
+const (
+	// EtcdCertificatesRotatedCondition documents that the etcd certificates have been rotated in place on all the
+	// control plane machines, as requested by the RotateEtcdCertificatesAnnotation.
+	// NOTE: This condition exists only if a rotation has been requested.
+	EtcdCertificatesRotatedCondition clusterv1.ConditionType = "EtcdCertificatesRotated"
+
+	// EtcdCertificatesRotationInProgressReason (Severity=Info) documents a KubeadmControlPlane rotating the etcd
+	// certificates on its machines, one machine at a time.
+	EtcdCertificatesRotationInProgressReason = "EtcdCertificatesRotationInProgress"
+
+	// EtcdCertificatesRotationFailedReason (Severity=Error) documents a KubeadmControlPlane failing to rotate the etcd
+	// certificates on a machine; the rotation does not proceed until the failed rotation Pod in the workload cluster is deleted.
+	EtcdCertificatesRotationFailedReason = "EtcdCertificatesRotationFailed"
+)
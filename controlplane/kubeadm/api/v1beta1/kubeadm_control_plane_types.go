@@ -37,6 +37,49 @@ const (
 	RollingUpdateStrategyType RolloutStrategyType = "RollingUpdate"
 )
 
+// InitialProvisioningStrategyType defines how control plane machines are provisioned when a KubeadmControlPlane is created.
+type InitialProvisioningStrategyType string
+
+const (
+	// SequentialInitialProvisioningStrategyType creates a control plane machine only when the previous one
+	// has joined the control plane and it is healthy.
+	SequentialInitialProvisioningStrategyType InitialProvisioningStrategyType = "Sequential"
+
+	// ParallelInitialProvisioningStrategyType creates all the control plane machines joining the control plane
+	// at once after the first control plane machine has been initialized, thus provisioning their infrastructure in parallel.
+	ParallelInitialProvisioningStrategyType InitialProvisioningStrategyType = "Parallel"
+)
+
+// RolloutOrder defines the order in which control plane machines are rolled out.
+type RolloutOrder string
+
+const (
+	// FailureDomainRoundRobinRolloutOrder rolls out first the oldest machine in the failure domain with the most
+	// machines, thus preserving the balance of machines across failure domains through the rollout.
+	FailureDomainRoundRobinRolloutOrder RolloutOrder = "FailureDomainRoundRobin"
+
+	// OldestFirstRolloutOrder rolls out machines from the oldest to the newest, regardless of failure domains.
+	OldestFirstRolloutOrder RolloutOrder = "OldestFirst"
+
+	// LeaderLastRolloutOrder rolls out the machine hosting the etcd leader last, thus minimizing the number
+	// of etcd leader elections during the rollout; other machines are rolled out in FailureDomainRoundRobin order.
+	LeaderLastRolloutOrder RolloutOrder = "LeaderLast"
+)
+
+// EncryptionProvider defines the provider used by the API server to encrypt data at rest in etcd.
+type EncryptionProvider string
+
+const (
+	// AESCBCEncryptionProvider encrypts data using AES-CBC with PKCS#7 padding.
+	AESCBCEncryptionProvider EncryptionProvider = "aescbc"
+
+	// AESGCMEncryptionProvider encrypts data using AES-GCM with a random nonce.
+	AESGCMEncryptionProvider EncryptionProvider = "aesgcm"
+
+	// SecretboxEncryptionProvider encrypts data using XSalsa20 and Poly1305.
+	SecretboxEncryptionProvider EncryptionProvider = "secretbox"
+)
+
 const (
 	// KubeadmControlPlaneFinalizer is the finalizer applied to KubeadmControlPlane resources
 	// by its managing controller.
@@ -45,6 +88,11 @@ const (
 	// SkipCoreDNSAnnotation annotation explicitly skips reconciling CoreDNS if set.
 	SkipCoreDNSAnnotation = "controlplane.cluster.x-k8s.io/skip-coredns"
 
+	// RolloutPausedAnnotation is set on a KubeadmControlPlane to pause the rollout of machines with an outdated spec,
+	// e.g. during a change freeze. Differently from the paused annotation, the KubeadmControlPlane keeps being reconciled,
+	// so scaling, remediation and status updates keep working.
+	RolloutPausedAnnotation = "controlplane.cluster.x-k8s.io/rollout-paused"
+
 	// SkipKubeProxyAnnotation annotation explicitly skips reconciling kube-proxy if set.
 	SkipKubeProxyAnnotation = "controlplane.cluster.x-k8s.io/skip-kube-proxy"
 
@@ -52,6 +100,17 @@ const (
 	// This annotation is used to detect any changes in ClusterConfiguration and trigger machine rollout in KCP.
 	KubeadmClusterConfigurationAnnotation = "controlplane.cluster.x-k8s.io/kubeadm-cluster-configuration"
 
+	// InfrastructureTemplateChecksumAnnotation records the checksum of the content of the infrastructure machine template
+	// referenced by a KubeadmControlPlane; it is set by the KubeadmControlPlane controller. Machines whose infrastructure
+	// machine has been cloned from a template with a different name but the same checksum are not rolled out, e.g. when
+	// the template has been re-created with an identical spec under a new name.
+	InfrastructureTemplateChecksumAnnotation = "controlplane.cluster.x-k8s.io/infrastructure-template-checksum"
+
+	// KubeadmConfigSpecChecksumAnnotation is a KubeadmConfig annotation that stores the checksum of the KCP KubeadmConfigSpec
+	// the KubeadmConfig has been generated from. Machines whose KubeadmConfig has been generated from a KubeadmConfigSpec with
+	// the same checksum as the current one are not rolled out, regardless of the changes applied to the KubeadmConfig afterwards.
+	KubeadmConfigSpecChecksumAnnotation = "controlplane.cluster.x-k8s.io/kubeadm-config-spec-checksum"
+
 	// RemediationInProgressAnnotation is used to keep track that a KCP remediation is in progress, and more
 	// specifically it tracks that the system is in between having deleted an unhealthy machine and recreating its replacement.
 	// NOTE: if something external to CAPI removes this annotation the system cannot detect the above situation; this can lead to
@@ -65,6 +124,28 @@ const (
 	// failures in updating remediation retry (the counter restarts from zero).
 	RemediationForAnnotation = "controlplane.cluster.x-k8s.io/remediation-for"
 
+	// RotateEtcdCertificatesAnnotation is set by users on a KubeadmControlPlane to request to rotate the etcd
+	// certificates in place on all the control plane machines, without replacing them.
+	// The value is an opaque token identifying the request, e.g. a timestamp; a new request is issued by changing it.
+	// NOTE: This requires the EtcdCertificatesRotation feature gate to be enabled and a stacked etcd cluster.
+	RotateEtcdCertificatesAnnotation = "controlplane.cluster.x-k8s.io/rotate-etcd-certificates"
+
+	// EtcdCertificatesRotatedAnnotation is set on control plane machines with the value of the RotateEtcdCertificatesAnnotation
+	// once the etcd certificates have been rotated on the machine; machines created after the request get it on creation.
+	EtcdCertificatesRotatedAnnotation = "controlplane.cluster.x-k8s.io/etcd-certificates-rotated"
+
+	// HostCleanupPreTerminateHookAnnotation is the pre-terminate hook set on control plane machines when a host cleanup
+	// is configured; KCP removes it once the host of a machine being deleted has been cleaned up.
+	HostCleanupPreTerminateHookAnnotation = clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/kcp-host-cleanup"
+
+	// InPlaceUpgradeFailedAnnotation is set on control plane machines for which the in-place upgrade failed, with the
+	// value of the target version; these machines are replaced instead of being upgraded in place to that version.
+	InPlaceUpgradeFailedAnnotation = "controlplane.cluster.x-k8s.io/in-place-upgrade-failed"
+
+	// JoinPermittedAnnotation is set by KCP on the KubeadmConfigs of the control plane machines created in parallel during
+	// the initial provisioning once the machine is permitted to run kubeadm join; joins are permitted one at a time.
+	JoinPermittedAnnotation = "controlplane.cluster.x-k8s.io/join-permitted"
+
 	// DefaultMinHealthyPeriod defines the default minimum period before we consider a remediation on a
 	// machine unrelated from the previous remediation.
 	DefaultMinHealthyPeriod = 1 * time.Hour
@@ -117,6 +198,102 @@ type KubeadmControlPlaneSpec struct {
 	// The RemediationStrategy that controls how control plane machine remediation happens.
 	// +optional
 	RemediationStrategy *RemediationStrategy `json:"remediationStrategy,omitempty"`
+
+	// EncryptionAtRest configures the encryption of the resources stored in etcd by the API server.
+	// When set, KCP generates the encryption keys, stores the corresponding EncryptionConfiguration
+	// in the <name>-encryption-config Secret, where name is the name of the KubeadmControlPlane,
+	// and mounts it on the control plane machines. Encryption at rest cannot be disabled once enabled.
+	// +optional
+	EncryptionAtRest *EncryptionAtRest `json:"encryptionAtRest,omitempty"`
+
+	// CorefileOverrides are structured changes KCP merges into the CoreDNS Corefile generated by kubeadm.
+	// Overrides are re-applied after the Corefile is migrated during CoreDNS upgrades, so they are
+	// preserved across CoreDNS versions without replacing the whole Corefile.
+	// +optional
+	CorefileOverrides *CorefileOverrides `json:"corefileOverrides,omitempty"`
+
+	// InitialProvisioningStrategy defines how control plane machines are provisioned when the control plane is created,
+	// i.e. until the desired number of machines is created for the first time. Defaults to Sequential, which creates a
+	// machine only when the previous one has joined the control plane and it is healthy.
+	// With Parallel, all the machines joining the control plane are created at once as soon as the first machine
+	// is initialized, so their infrastructure is provisioned in parallel, while kubeadm join is run on one machine
+	// at a time, each one waiting for the previous one to join the control plane and for the control plane to be healthy.
+	// NOTE: With Parallel, machines must be able to reach the API server of the workload cluster using curl.
+	// +kubebuilder:validation:Enum=Sequential;Parallel
+	// +optional
+	InitialProvisioningStrategy InitialProvisioningStrategyType `json:"initialProvisioningStrategy,omitempty"`
+
+	// InitConfigOverrides defines configuration applied on top of kubeadmConfigSpec only for the KubeadmConfig of the
+	// first control plane machine, the one initializing the cluster with kubeadm init, e.g. files or commands
+	// required only to bootstrap the cluster.
+	// NOTE: kubeadmConfigSpec.initConfiguration and kubeadmConfigSpec.joinConfiguration, including patches,
+	// nodeRegistration and the API server advertise address, are already used respectively only for the first
+	// control plane machine and for the joining ones.
+	// +optional
+	InitConfigOverrides *KubeadmConfigOverrides `json:"initConfigOverrides,omitempty"`
+
+	// JoinConfigOverrides defines configuration applied on top of kubeadmConfigSpec only for the KubeadmConfigs of
+	// the control plane machines joining the cluster with kubeadm join.
+	// +optional
+	JoinConfigOverrides *KubeadmConfigOverrides `json:"joinConfigOverrides,omitempty"`
+
+	// ProgressDeadlineSeconds is the maximum time in seconds for a rollout or scale operation of the control plane
+	// to make progress before it is considered to be failed. If no progress is made within the deadline, the
+	// Progressing condition is set to false with the ProgressDeadlineExceeded reason and a warning event is emitted;
+	// no automatic action is performed. If not set, the progress of rollout and scale operations is not tracked.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// UpgradePolicy defines how control plane machines are upgraded to a new Kubernetes version. Defaults to Replace,
+	// which replaces the machines as for any other change.
+	// With InPlacePatch, machines whose only change is an upgrade to a newer patch version of the same minor version
+	// are upgraded in place, one machine at a time: the kubeadm and kubelet binaries of the new version are installed
+	// on the host, kubeadm upgrade node is run and the kubelet is restarted. Machines failing the in-place upgrade are
+	// replaced.
+	// +kubebuilder:validation:Enum=Replace;InPlacePatch
+	// +optional
+	UpgradePolicy UpgradePolicyType `json:"upgradePolicy,omitempty"`
+
+	// InPlaceUpgrade configures how control plane machines are upgraded in place when UpgradePolicy is InPlacePatch.
+	// +optional
+	InPlaceUpgrade *InPlaceUpgrade `json:"inPlaceUpgrade,omitempty"`
+}
+
+// UpgradePolicyType defines how control plane machines are upgraded to a new Kubernetes version.
+type UpgradePolicyType string
+
+const (
+	// UpgradePolicyReplace upgrades control plane machines by replacing them.
+	UpgradePolicyReplace UpgradePolicyType = "Replace"
+
+	// UpgradePolicyInPlacePatch upgrades control plane machines in place when only the patch version changes,
+	// and replaces them otherwise.
+	UpgradePolicyInPlacePatch UpgradePolicyType = "InPlacePatch"
+)
+
+// InPlaceUpgrade defines how control plane machines are upgraded in place.
+// The upgrade is run by a privileged Pod scheduled on the Node of the machine; the host must provide curl and sha256sum
+// for downloading and verifying the binaries of the new version, and systemd for restarting the kubelet.
+type InPlaceUpgrade struct {
+	// BinariesURL is the base URL the kubeadm, kubelet and kubectl binaries are downloaded from; binaries are
+	// downloaded from <binariesURL>/<version>/bin/linux/<arch>/<binary>, so a mirror of the Kubernetes release
+	// binaries can be used in air-gapped environments.
+	// Defaults to the --in-place-upgrade-binaries-url flag of the KCP controller, https://dl.k8s.io/release by default;
+	// air-gapped clusters must use a mirror reachable from the control plane machines.
+	// +optional
+	BinariesURL string `json:"binariesURL,omitempty"`
+
+	// Image is the image used for the Pod running the upgrade; it must provide the chroot and sh commands.
+	// If not set, the image configured for the controller is used.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Timeout is the maximum time for the in-place upgrade of a machine to complete; after this time the machine
+	// is replaced instead.
+	// Defaults to 10m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // KubeadmControlPlaneMachineTemplate defines the template for Machines
@@ -147,6 +324,46 @@ type KubeadmControlPlaneMachineTemplate struct {
 	// If no value is provided, the default value for this property of the Machine resource will be used.
 	// +optional
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+
+	// HostCleanup, if set, runs a cleanup phase on the host of a control plane machine when the machine is deleted,
+	// removing the static pod manifests and the etcd data directory. This prevents stale static pods and zombie etcd
+	// members when hosts are reused, e.g. when bare metal hosts are recycled.
+	// +optional
+	HostCleanup *HostCleanup `json:"hostCleanup,omitempty"`
+}
+
+// HostCleanupMode defines how the host of a control plane machine is cleaned up.
+type HostCleanupMode string
+
+const (
+	// HostCleanupModeRemoveManifests removes the static pod manifests and the etcd data directory from the host.
+	HostCleanupModeRemoveManifests HostCleanupMode = "RemoveManifests"
+
+	// HostCleanupModeKubeadmReset runs kubeadm reset on the host, which also removes the static pod manifests and
+	// the etcd data directory.
+	HostCleanupModeKubeadmReset HostCleanupMode = "KubeadmReset"
+)
+
+// HostCleanup defines the cleanup phase run on the host of a control plane machine when the machine is deleted.
+// The cleanup is run by a privileged Pod scheduled on the Node of the machine after it has been drained, and the
+// deletion of the machine waits for the Pod to complete, or for the timeout to expire.
+type HostCleanup struct {
+	// Mode defines how the host is cleaned up.
+	// Defaults to RemoveManifests.
+	// +kubebuilder:validation:Enum=RemoveManifests;KubeadmReset
+	// +optional
+	Mode HostCleanupMode `json:"mode,omitempty"`
+
+	// Image is the image used for the Pod running the cleanup; it must provide the chroot and sh commands.
+	// If not set, the image configured for the controller is used.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Timeout is the maximum time the deletion of a machine waits for the cleanup to complete; after this time
+	// the deletion proceeds even if the cleanup failed or did not complete, e.g. because the Node is unreachable.
+	// Defaults to 5m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // RolloutBefore describes when a rollout should be performed on the KCP machines.
@@ -170,6 +387,13 @@ type RolloutStrategy struct {
 	// RolloutStrategyType = RollingUpdate.
 	// +optional
 	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
+
+	// Order defines the order in which machines are rolled out.
+	// Valid values are "FailureDomainRoundRobin", "OldestFirst" and "LeaderLast".
+	// Defaults to FailureDomainRoundRobin.
+	// +kubebuilder:validation:Enum=FailureDomainRoundRobin;OldestFirst;LeaderLast
+	// +optional
+	Order RolloutOrder `json:"order,omitempty"`
 }
 
 // RollingUpdate is used to control the desired behavior of rolling update.
@@ -228,6 +452,127 @@ type RemediationStrategy struct {
 	MinHealthyPeriod *metav1.Duration `json:"minHealthyPeriod,omitempty"`
 }
 
+// EncryptionAtRest defines how the API server encrypts the resources stored in etcd.
+type EncryptionAtRest struct {
+	// Provider is the encryption provider used to encrypt new data.
+	// Valid values are "aescbc", "aesgcm" and "secretbox".
+	// Defaults to aescbc.
+	// +kubebuilder:validation:Enum=aescbc;aesgcm;secretbox
+	// +optional
+	Provider EncryptionProvider `json:"provider,omitempty"`
+
+	// Resources is the list of resources to encrypt, e.g. "secrets" or "configmaps".
+	// Defaults to ["secrets"].
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+
+	// RotateKeysAfter is a field to indicate the encryption keys should be rotated
+	// if they have been generated before the specified time.
+	// The rotation is performed with two rollouts of the control plane machines: the first one
+	// distributes the new key to all the API servers, the second one uses it to encrypt new data.
+	// Previous keys are preserved in order to decrypt existing data until all the encrypted resources
+	// have been rewritten with the new key.
+	// Example: In the YAML the time can be specified in the RFC3339 format.
+	// To specify the rotateKeysAfter target as March 9, 2023, at 9 am UTC
+	// use "2023-03-09T09:00:00Z".
+	// +optional
+	RotateKeysAfter *metav1.Time `json:"rotateKeysAfter,omitempty"`
+}
+
+// KubeadmConfigOverrides defines configuration applied on top of the KubeadmConfigSpec of a KubeadmControlPlane
+// for the KubeadmConfigs of the control plane machines with a given role, i.e. initializing or joining the cluster.
+type KubeadmConfigOverrides struct {
+	// Files specifies extra files to be passed to user_data upon creation, in addition to kubeadmConfigSpec.files.
+	// A file with the same path of a file in kubeadmConfigSpec.files replaces it.
+	// +optional
+	Files []bootstrapv1.File `json:"files,omitempty"`
+
+	// PreKubeadmCommands specifies extra commands to run before kubeadm runs,
+	// after the ones in kubeadmConfigSpec.preKubeadmCommands.
+	// +optional
+	PreKubeadmCommands []string `json:"preKubeadmCommands,omitempty"`
+
+	// PostKubeadmCommands specifies extra commands to run after kubeadm runs,
+	// after the ones in kubeadmConfigSpec.postKubeadmCommands.
+	// +optional
+	PostKubeadmCommands []string `json:"postKubeadmCommands,omitempty"`
+
+	// KubeletExtraArgs specifies extra arguments for the kubelet, in addition to the ones in the nodeRegistration of
+	// kubeadmConfigSpec.initConfiguration or kubeadmConfigSpec.joinConfiguration; an argument with the same name replaces it.
+	// +optional
+	KubeletExtraArgs map[string]string `json:"kubeletExtraArgs,omitempty"`
+
+	// Patches specifies the patches applied to the components deployed by kubeadm, replacing the patches
+	// of kubeadmConfigSpec.initConfiguration or kubeadmConfigSpec.joinConfiguration.
+	// +optional
+	Patches *bootstrapv1.Patches `json:"patches,omitempty"`
+}
+
+// CorefileOverrides defines structured changes to the CoreDNS Corefile.
+// Plugins and forward servers are applied to the default server block, i.e. the one serving the root zone.
+type CorefileOverrides struct {
+	// AddPlugins is the list of plugins to add to the default server block.
+	// If a plugin with the same name already exists, it is replaced.
+	// +optional
+	AddPlugins []CorefilePlugin `json:"addPlugins,omitempty"`
+
+	// RemovePlugins is the list of names of the plugins to remove from the default server block.
+	// +optional
+	RemovePlugins []string `json:"removePlugins,omitempty"`
+
+	// ForwardServers is the list of upstream servers the default server block forwards queries to,
+	// e.g. "8.8.8.8" or "tls://1.1.1.1", replacing the ones defined in the forward plugin.
+	// +optional
+	ForwardServers []string `json:"forwardServers,omitempty"`
+
+	// Zones is the list of additional server blocks, e.g. for forwarding queries for a domain
+	// to a dedicated DNS server.
+	// +optional
+	Zones []CorefileZone `json:"zones,omitempty"`
+}
+
+// CorefilePlugin defines a plugin in a CoreDNS server block.
+type CorefilePlugin struct {
+	// Name of the plugin, e.g. "log" or "hosts".
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Args are the arguments of the plugin.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Options are the options of the plugin, defined in the plugin block.
+	// +optional
+	Options []CorefilePluginOption `json:"options,omitempty"`
+}
+
+// CorefilePluginOption defines an option of a CoreDNS plugin.
+type CorefilePluginOption struct {
+	// Name of the option, e.g. "fallthrough".
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Args are the arguments of the option.
+	// +optional
+	Args []string `json:"args,omitempty"`
+}
+
+// CorefileZone defines an additional CoreDNS server block.
+type CorefileZone struct {
+	// Name is the zone served by the server block, e.g. "example.com" or "example.com:53".
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ForwardServers is the list of upstream servers queries for the zone are forwarded to.
+	// +optional
+	ForwardServers []string `json:"forwardServers,omitempty"`
+
+	// Plugins is the list of additional plugins of the server block.
+	// The errors and cache plugins are always added.
+	// +optional
+	Plugins []CorefilePlugin `json:"plugins,omitempty"`
+}
+
 // KubeadmControlPlaneStatus defines the observed state of KubeadmControlPlane.
 type KubeadmControlPlaneStatus struct {
 	// Selector is the label selector in string format to avoid introspection
@@ -297,6 +642,51 @@ type KubeadmControlPlaneStatus struct {
 	// LastRemediation stores info about last remediation performed.
 	// +optional
 	LastRemediation *LastRemediationStatus `json:"lastRemediation,omitempty"`
+
+	// RolloutOrder lists the names of the machines pending rollout, in the order
+	// they are going to be rolled out according to spec.rolloutStrategy.order.
+	// +optional
+	RolloutOrder []string `json:"rolloutOrder,omitempty"`
+
+	// EncryptionAtRest reports the status of the encryption of the resources stored in etcd.
+	// +optional
+	EncryptionAtRest *EncryptionAtRestStatus `json:"encryptionAtRest,omitempty"`
+
+	// ProgressDeadline is the time by which the rollout or scale operation in progress is expected to make further
+	// progress, if spec.progressDeadlineSeconds is set. It is not set when no operation is in progress.
+	// +optional
+	ProgressDeadline *metav1.Time `json:"progressDeadline,omitempty"`
+
+	// CertificatesExpiryDate is the earliest expiry date of the certificates of the control plane machines,
+	// as reported by the Machines' status.certificatesExpiryDate.
+	// +optional
+	CertificatesExpiryDate *metav1.Time `json:"certificatesExpiryDate,omitempty"`
+
+	// InitialProvisioningCompleted is true once the desired number of control plane machines has been created for the
+	// first time; from then on spec.initialProvisioningStrategy does not apply anymore.
+	// +optional
+	InitialProvisioningCompleted bool `json:"initialProvisioningCompleted,omitempty"`
+}
+
+// EncryptionAtRestStatus reports the status of the encryption of the resources stored in etcd.
+type EncryptionAtRestStatus struct {
+	// ConfigHash is the hash of the EncryptionConfiguration that control plane machines are expected to use.
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// LastKeyRotationTime is the time the encryption keys have been generated or rotated last.
+	// +optional
+	LastKeyRotationTime *metav1.Time `json:"lastKeyRotationTime,omitempty"`
+
+	// KeyRotationInProgress is true while a new key is being distributed to the control plane machines
+	// before being used to encrypt new data.
+	// +optional
+	KeyRotationInProgress bool `json:"keyRotationInProgress,omitempty"`
+
+	// StorageMigratedKey is the name of the encryption key all the encrypted resources in the workload cluster
+	// have been rewritten with; previous keys are dropped only after all the resources have been rewritten.
+	// +optional
+	StorageMigratedKey string `json:"storageMigratedKey,omitempty"`
 }
 
 // LastRemediationStatus  stores info about last remediation performed.
@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/blang/semver"
@@ -28,6 +29,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -165,12 +167,17 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) error {
 		{spec, kubeadmConfigSpec, files},
 		{spec, kubeadmConfigSpec, "verbosity"},
 		{spec, kubeadmConfigSpec, users},
+		{spec, kubeadmConfigSpec, "groups"},
 		{spec, kubeadmConfigSpec, ntp},
 		{spec, kubeadmConfigSpec, ntp, "*"},
 		{spec, kubeadmConfigSpec, ignition},
 		{spec, kubeadmConfigSpec, ignition, "*"},
 		{spec, kubeadmConfigSpec, diskSetup},
 		{spec, kubeadmConfigSpec, diskSetup, "*"},
+		{spec, kubeadmConfigSpec, "kubeletCredentialProviders"},
+		{spec, kubeadmConfigSpec, "kubeletCredentialProviders", "*"},
+		{spec, kubeadmConfigSpec, "snippets"},
+		{spec, kubeadmConfigSpec, "snippets", "*"},
 		{spec, kubeadmConfigSpec, "format"},
 		{spec, kubeadmConfigSpec, "mounts"},
 		{spec, "machineTemplate", "metadata"},
@@ -181,6 +188,8 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) error {
 		{spec, "machineTemplate", "nodeDrainTimeout"},
 		{spec, "machineTemplate", "nodeVolumeDetachTimeout"},
 		{spec, "machineTemplate", "nodeDeletionTimeout"},
+		{spec, "machineTemplate", "hostCleanup"},
+		{spec, "machineTemplate", "hostCleanup", "*"},
 		{spec, "replicas"},
 		{spec, "version"},
 		{spec, "remediationStrategy"},
@@ -190,6 +199,18 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) error {
 		{spec, "rolloutBefore", "*"},
 		{spec, "rolloutStrategy"},
 		{spec, "rolloutStrategy", "*"},
+		{spec, "encryptionAtRest"},
+		{spec, "encryptionAtRest", "*"},
+		{spec, "corefileOverrides"},
+		{spec, "corefileOverrides", "*"},
+		{spec, "initialProvisioningStrategy"},
+		{spec, "initConfigOverrides"},
+		{spec, "initConfigOverrides", "*"},
+		{spec, "joinConfigOverrides"},
+		{spec, "joinConfigOverrides", "*"},
+		{spec, "upgradePolicy"},
+		{spec, "inPlaceUpgrade"},
+		{spec, "inPlaceUpgrade", "*"},
 	}
 
 	allErrs := validateKubeadmControlPlaneSpec(in.Spec, in.Namespace, field.NewPath("spec"))
@@ -233,6 +254,11 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) error {
 		}
 	}
 
+	// Encryption at rest cannot be disabled, otherwise the API servers won't be able to read the encrypted data.
+	if prev.Spec.EncryptionAtRest != nil && in.Spec.EncryptionAtRest == nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath(spec, "encryptionAtRest"), "cannot be removed once set"))
+	}
+
 	allErrs = append(allErrs, in.validateVersion(prev.Spec.Version)...)
 	allErrs = append(allErrs, validateClusterConfiguration(in.Spec.KubeadmConfigSpec.ClusterConfiguration, prev.Spec.KubeadmConfigSpec.ClusterConfiguration, field.NewPath("spec", "kubeadmConfigSpec", "clusterConfiguration"))...)
 	allErrs = append(allErrs, in.validateCoreDNSVersion(prev)...)
@@ -269,6 +295,17 @@ func validateKubeadmControlPlaneSpec(s KubeadmControlPlaneSpec, namespace string
 		)
 	}
 
+	// Control plane machines are not supported on Windows.
+	if s.KubeadmConfigSpec.OSFamily == bootstrapv1.WindowsOSFamily {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(
+				pathPrefix.Child("kubeadmConfigSpec", "osFamily"),
+				fmt.Sprintf("control plane machines are not supported on %q", bootstrapv1.WindowsOSFamily),
+			),
+		)
+	}
+
 	externalEtcd := false
 	if s.KubeadmConfigSpec.ClusterConfiguration != nil {
 		if s.KubeadmConfigSpec.ClusterConfiguration.Etcd.External != nil {
@@ -335,6 +372,11 @@ func validateKubeadmControlPlaneSpec(s KubeadmControlPlaneSpec, namespace string
 
 	allErrs = append(allErrs, validateRolloutBefore(s.RolloutBefore, pathPrefix.Child("rolloutBefore"))...)
 	allErrs = append(allErrs, validateRolloutStrategy(s.RolloutStrategy, s.Replicas, pathPrefix.Child("rolloutStrategy"))...)
+	allErrs = append(allErrs, validateCorefileOverrides(s.CorefileOverrides, pathPrefix.Child("corefileOverrides"))...)
+	allErrs = append(allErrs, validateHostCleanup(s.MachineTemplate.HostCleanup, pathPrefix.Child("machineTemplate", "hostCleanup"))...)
+	allErrs = append(allErrs, validateKubeadmConfigOverrides(s.InitConfigOverrides, pathPrefix.Child("initConfigOverrides"))...)
+	allErrs = append(allErrs, validateKubeadmConfigOverrides(s.JoinConfigOverrides, pathPrefix.Child("joinConfigOverrides"))...)
+	allErrs = append(allErrs, validateInPlaceUpgrade(s.InPlaceUpgrade, pathPrefix.Child("inPlaceUpgrade"))...)
 
 	return allErrs
 }
@@ -355,6 +397,127 @@ func validateRolloutBefore(rolloutBefore *RolloutBefore, pathPrefix *field.Path)
 	return allErrs
 }
 
+func validateHostCleanup(hostCleanup *HostCleanup, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if hostCleanup == nil {
+		return allErrs
+	}
+
+	if hostCleanup.Timeout != nil && hostCleanup.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("timeout"), hostCleanup.Timeout.Duration.String(), "must be greater than 0"))
+	}
+
+	return allErrs
+}
+
+func validateInPlaceUpgrade(inPlaceUpgrade *InPlaceUpgrade, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if inPlaceUpgrade == nil {
+		return allErrs
+	}
+
+	if inPlaceUpgrade.BinariesURL != "" {
+		if u, err := url.Parse(inPlaceUpgrade.BinariesURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Child("binariesURL"), inPlaceUpgrade.BinariesURL, "must be a valid http or https URL"))
+		}
+	}
+
+	if inPlaceUpgrade.Timeout != nil && inPlaceUpgrade.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("timeout"), inPlaceUpgrade.Timeout.Duration.String(), "must be greater than 0"))
+	}
+
+	return allErrs
+}
+
+func validateKubeadmConfigOverrides(overrides *KubeadmConfigOverrides, pathPrefix *field.Path) field.ErrorList {
+	if overrides == nil {
+		return field.ErrorList{}
+	}
+
+	// The files in the overrides are subject to the same validation of the files in a KubeadmConfigSpec.
+	spec := bootstrapv1.KubeadmConfigSpec{Files: overrides.Files}
+	return spec.Validate(pathPrefix)
+}
+
+func validateCorefileOverrides(overrides *CorefileOverrides, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if overrides == nil {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validateCorefilePlugins(overrides.AddPlugins, pathPrefix.Child("addPlugins"))...)
+
+	removed := sets.Set[string]{}
+	for i, name := range overrides.RemovePlugins {
+		if name == "" {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Child("removePlugins").Index(i), name, "cannot be empty"))
+			continue
+		}
+		removed.Insert(name)
+	}
+	for i, plugin := range overrides.AddPlugins {
+		if removed.Has(plugin.Name) {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Child("addPlugins").Index(i).Child("name"), plugin.Name, "cannot be both added and removed"))
+		}
+	}
+	if len(overrides.ForwardServers) > 0 && removed.Has("forward") {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("forwardServers"), "cannot be set when the forward plugin is removed"))
+	}
+	allErrs = append(allErrs, validateCorefileForwardServers(overrides.ForwardServers, pathPrefix.Child("forwardServers"))...)
+
+	zones := sets.Set[string]{}
+	for i, zone := range overrides.Zones {
+		if zone.Name == "" {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Child("zones").Index(i).Child("name"), zone.Name, "cannot be empty"))
+		} else if zones.Has(zone.Name) {
+			allErrs = append(allErrs, field.Duplicate(pathPrefix.Child("zones").Index(i).Child("name"), zone.Name))
+		}
+		zones.Insert(zone.Name)
+		allErrs = append(allErrs, validateCorefileForwardServers(zone.ForwardServers, pathPrefix.Child("zones").Index(i).Child("forwardServers"))...)
+		allErrs = append(allErrs, validateCorefilePlugins(zone.Plugins, pathPrefix.Child("zones").Index(i).Child("plugins"))...)
+	}
+
+	return allErrs
+}
+
+func validateCorefilePlugins(plugins []CorefilePlugin, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.Set[string]{}
+	for i, plugin := range plugins {
+		if plugin.Name == "" {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Index(i).Child("name"), plugin.Name, "cannot be empty"))
+			continue
+		}
+		if names.Has(plugin.Name) {
+			allErrs = append(allErrs, field.Duplicate(pathPrefix.Index(i).Child("name"), plugin.Name))
+		}
+		names.Insert(plugin.Name)
+		for j, option := range plugin.Options {
+			if option.Name == "" {
+				allErrs = append(allErrs, field.Invalid(pathPrefix.Index(i).Child("options").Index(j).Child("name"), option.Name, "cannot be empty"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateCorefileForwardServers(servers []string, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, server := range servers {
+		if server == "" || strings.ContainsAny(server, " \t\n{}") {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Index(i), server, "must be a valid upstream server"))
+		}
+	}
+
+	return allErrs
+}
+
 func validateRolloutStrategy(rolloutStrategy *RolloutStrategy, replicas *int32, pathPrefix *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -152,6 +152,65 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 	validIgnitionConfiguration.Spec.KubeadmConfigSpec.Format = bootstrapv1.Ignition
 	validIgnitionConfiguration.Spec.KubeadmConfigSpec.Ignition = &bootstrapv1.IgnitionSpec{}
 
+	invalidCorefileOverrides := valid.DeepCopy()
+	invalidCorefileOverrides.Spec.CorefileOverrides = &CorefileOverrides{
+		AddPlugins:     []CorefilePlugin{{Name: "log"}, {Name: "log"}},
+		RemovePlugins:  []string{"forward"},
+		ForwardServers: []string{"8.8.8.8"},
+		Zones: []CorefileZone{
+			{Name: "example.com"},
+			{Name: "example.com"},
+		},
+	}
+
+	windowsOSFamily := valid.DeepCopy()
+	windowsOSFamily.Spec.KubeadmConfigSpec.OSFamily = bootstrapv1.WindowsOSFamily
+
+	validHostCleanup := valid.DeepCopy()
+	validHostCleanup.Spec.MachineTemplate.HostCleanup = &HostCleanup{
+		Mode:    HostCleanupModeKubeadmReset,
+		Timeout: &metav1.Duration{Duration: 10 * time.Minute},
+	}
+
+	invalidHostCleanupTimeout := valid.DeepCopy()
+	invalidHostCleanupTimeout.Spec.MachineTemplate.HostCleanup = &HostCleanup{
+		Timeout: &metav1.Duration{Duration: -time.Minute},
+	}
+
+	validInPlaceUpgrade := valid.DeepCopy()
+	validInPlaceUpgrade.Spec.UpgradePolicy = UpgradePolicyInPlacePatch
+	validInPlaceUpgrade.Spec.InPlaceUpgrade = &InPlaceUpgrade{
+		BinariesURL: "https://mirror.example.com/kubernetes/release",
+		Timeout:     &metav1.Duration{Duration: 15 * time.Minute},
+	}
+
+	invalidInPlaceUpgradeBinariesURL := valid.DeepCopy()
+	invalidInPlaceUpgradeBinariesURL.Spec.InPlaceUpgrade = &InPlaceUpgrade{
+		BinariesURL: "mirror.example.com/kubernetes/release",
+	}
+
+	invalidInPlaceUpgradeTimeout := valid.DeepCopy()
+	invalidInPlaceUpgradeTimeout.Spec.InPlaceUpgrade = &InPlaceUpgrade{
+		Timeout: &metav1.Duration{Duration: -time.Minute},
+	}
+
+	validConfigOverrides := valid.DeepCopy()
+	validConfigOverrides.Spec.InitConfigOverrides = &KubeadmConfigOverrides{
+		Files:              []bootstrapv1.File{{Path: "/etc/kubernetes/init-only.yaml", Content: "init"}},
+		PreKubeadmCommands: []string{"echo init"},
+	}
+	validConfigOverrides.Spec.JoinConfigOverrides = &KubeadmConfigOverrides{
+		PostKubeadmCommands: []string{"echo join"},
+	}
+
+	invalidJoinConfigOverridesFiles := valid.DeepCopy()
+	invalidJoinConfigOverridesFiles.Spec.JoinConfigOverrides = &KubeadmConfigOverrides{
+		Files: []bootstrapv1.File{
+			{Path: "/etc/kubernetes/join-only.yaml", Content: "join"},
+			{Path: "/etc/kubernetes/join-only.yaml", Content: "join"},
+		},
+	}
+
 	tests := []struct {
 		name                  string
 		enableIgnitionFeature bool
@@ -173,6 +232,11 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       missingReplicas,
 		},
+		{
+			name:      "should return error when osFamily is windows",
+			expectErr: true,
+			kcp:       windowsOSFamily,
+		},
 		{
 			name:      "should return error when replicas is zero",
 			expectErr: true,
@@ -223,6 +287,46 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       invalidRolloutBeforeCertificateExpiryDays,
 		},
+		{
+			name:      "should return error when given invalid corefileOverrides",
+			expectErr: true,
+			kcp:       invalidCorefileOverrides,
+		},
+		{
+			name:      "should succeed when given a valid hostCleanup",
+			expectErr: false,
+			kcp:       validHostCleanup,
+		},
+		{
+			name:      "should return error when given a negative hostCleanup.timeout",
+			expectErr: true,
+			kcp:       invalidHostCleanupTimeout,
+		},
+		{
+			name:      "should succeed when given a valid inPlaceUpgrade",
+			expectErr: false,
+			kcp:       validInPlaceUpgrade,
+		},
+		{
+			name:      "should return error when given an invalid inPlaceUpgrade.binariesURL",
+			expectErr: true,
+			kcp:       invalidInPlaceUpgradeBinariesURL,
+		},
+		{
+			name:      "should return error when given a negative inPlaceUpgrade.timeout",
+			expectErr: true,
+			kcp:       invalidInPlaceUpgradeTimeout,
+		},
+		{
+			name:      "should succeed when given valid initConfigOverrides and joinConfigOverrides",
+			expectErr: false,
+			kcp:       validConfigOverrides,
+		},
+		{
+			name:      "should return error when joinConfigOverrides has files with the same path",
+			expectErr: true,
+			kcp:       invalidJoinConfigOverridesFiles,
+		},
 
 		{
 			name:                  "should return error when Ignition configuration is invalid",
@@ -625,6 +729,39 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	unsetRolloutBefore := before.DeepCopy()
 	unsetRolloutBefore.Spec.RolloutBefore = nil
 
+	setEncryptionAtRest := before.DeepCopy()
+	setEncryptionAtRest.Spec.EncryptionAtRest = &EncryptionAtRest{
+		Provider: AESCBCEncryptionProvider,
+	}
+
+	rotateEncryptionKeys := setEncryptionAtRest.DeepCopy()
+	rotateEncryptionKeys.Spec.EncryptionAtRest.RotateKeysAfter = &metav1.Time{Time: time.Now()}
+
+	setConfigOverrides := before.DeepCopy()
+	setConfigOverrides.Spec.InitConfigOverrides = &KubeadmConfigOverrides{
+		Files: []bootstrapv1.File{{Path: "/etc/kubernetes/init-only.yaml", Content: "init"}},
+	}
+	setConfigOverrides.Spec.JoinConfigOverrides = &KubeadmConfigOverrides{
+		PreKubeadmCommands: []string{"echo join"},
+	}
+
+	setInPlaceUpgrade := before.DeepCopy()
+	setInPlaceUpgrade.Spec.UpgradePolicy = UpgradePolicyInPlacePatch
+	setInPlaceUpgrade.Spec.InPlaceUpgrade = &InPlaceUpgrade{
+		Image:   "busybox",
+		Timeout: &metav1.Duration{Duration: 15 * time.Minute},
+	}
+
+	setCorefileOverrides := before.DeepCopy()
+	setCorefileOverrides.Spec.CorefileOverrides = &CorefileOverrides{
+		AddPlugins:     []CorefilePlugin{{Name: "log"}},
+		RemovePlugins:  []string{"loop"},
+		ForwardServers: []string{"8.8.8.8", "tls://1.1.1.1"},
+		Zones: []CorefileZone{
+			{Name: "example.com", ForwardServers: []string{"10.0.0.10"}},
+		},
+	}
+
 	invalidIgnitionConfiguration := before.DeepCopy()
 	invalidIgnitionConfiguration.Spec.KubeadmConfigSpec.Ignition = &bootstrapv1.IgnitionSpec{}
 
@@ -993,6 +1130,60 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 			before:    before,
 			kcp:       unsetRolloutBefore,
 		},
+		{
+			name:      "should allow setting encryptionAtRest",
+			expectErr: false,
+			before:    before,
+			kcp:       setEncryptionAtRest,
+		},
+		{
+			name:      "should allow rotating the encryption keys",
+			expectErr: false,
+			before:    setEncryptionAtRest,
+			kcp:       rotateEncryptionKeys,
+		},
+		{
+			name:      "should return error when unsetting encryptionAtRest",
+			expectErr: true,
+			before:    setEncryptionAtRest,
+			kcp:       before,
+		},
+		{
+			name:      "should allow setting corefileOverrides",
+			expectErr: false,
+			before:    before,
+			kcp:       setCorefileOverrides,
+		},
+		{
+			name:      "should allow unsetting corefileOverrides",
+			expectErr: false,
+			before:    setCorefileOverrides,
+			kcp:       before,
+		},
+		{
+			name:      "should allow setting initConfigOverrides and joinConfigOverrides",
+			expectErr: false,
+			before:    before,
+			kcp:       setConfigOverrides,
+		},
+		{
+			name:      "should allow unsetting initConfigOverrides and joinConfigOverrides",
+			expectErr: false,
+			before:    setConfigOverrides,
+			kcp:       before,
+		},
+		{
+			name:      "should allow setting upgradePolicy and inPlaceUpgrade",
+			expectErr: false,
+			before:    before,
+			kcp:       setInPlaceUpgrade,
+		},
+		{
+			name:      "should allow unsetting upgradePolicy and inPlaceUpgrade",
+			expectErr: false,
+			before:    setInPlaceUpgrade,
+			kcp:       before,
+		},
 		{
 			name:                  "should return error when Ignition configuration is invalid",
 			enableIgnitionFeature: true,
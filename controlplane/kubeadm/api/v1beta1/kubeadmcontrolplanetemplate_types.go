@@ -101,6 +101,44 @@ type KubeadmControlPlaneTemplateResourceSpec struct {
 	// The RemediationStrategy that controls how control plane machine remediation happens.
 	// +optional
 	RemediationStrategy *RemediationStrategy `json:"remediationStrategy,omitempty"`
+
+	// EncryptionAtRest configures the encryption of the resources stored in etcd by the API server.
+	// +optional
+	EncryptionAtRest *EncryptionAtRest `json:"encryptionAtRest,omitempty"`
+
+	// CorefileOverrides are structured changes KCP merges into the CoreDNS Corefile generated by kubeadm.
+	// +optional
+	CorefileOverrides *CorefileOverrides `json:"corefileOverrides,omitempty"`
+
+	// InitialProvisioningStrategy defines how control plane machines are provisioned when the control plane is created.
+	// +kubebuilder:validation:Enum=Sequential;Parallel
+	// +optional
+	InitialProvisioningStrategy InitialProvisioningStrategyType `json:"initialProvisioningStrategy,omitempty"`
+
+	// InitConfigOverrides defines configuration applied on top of kubeadmConfigSpec only for the KubeadmConfig of the
+	// first control plane machine, the one initializing the cluster.
+	// +optional
+	InitConfigOverrides *KubeadmConfigOverrides `json:"initConfigOverrides,omitempty"`
+
+	// JoinConfigOverrides defines configuration applied on top of kubeadmConfigSpec only for the KubeadmConfigs of
+	// the control plane machines joining the cluster.
+	// +optional
+	JoinConfigOverrides *KubeadmConfigOverrides `json:"joinConfigOverrides,omitempty"`
+
+	// ProgressDeadlineSeconds is the maximum time in seconds for a rollout or scale operation of the control plane
+	// to make progress before it is considered to be failed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// UpgradePolicy defines how control plane machines are upgraded to a new Kubernetes version.
+	// +kubebuilder:validation:Enum=Replace;InPlacePatch
+	// +optional
+	UpgradePolicy UpgradePolicyType `json:"upgradePolicy,omitempty"`
+
+	// InPlaceUpgrade configures how control plane machines are upgraded in place when UpgradePolicy is InPlacePatch.
+	// +optional
+	InPlaceUpgrade *InPlaceUpgrade `json:"inPlaceUpgrade,omitempty"`
 }
 
 // KubeadmControlPlaneTemplateMachineTemplate defines the template for Machines
@@ -131,4 +169,10 @@ type KubeadmControlPlaneTemplateMachineTemplate struct {
 	// If no value is provided, the default value for this property of the Machine resource will be used.
 	// +optional
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+
+	// HostCleanup, if set, runs a cleanup phase on the host of a control plane machine when the machine is deleted,
+	// removing the static pod manifests and the etcd data directory. This prevents stale static pods and zombie etcd
+	// members when hosts are reused, e.g. when bare metal hosts are recycled.
+	// +optional
+	HostCleanup *HostCleanup `json:"hostCleanup,omitempty"`
 }
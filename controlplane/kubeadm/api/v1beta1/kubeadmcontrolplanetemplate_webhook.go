@@ -106,6 +106,13 @@ func validateKubeadmControlPlaneTemplateResourceSpec(s KubeadmControlPlaneTempla
 
 	allErrs = append(allErrs, validateRolloutBefore(s.RolloutBefore, pathPrefix.Child("rolloutBefore"))...)
 	allErrs = append(allErrs, validateRolloutStrategy(s.RolloutStrategy, nil, pathPrefix.Child("rolloutStrategy"))...)
+	allErrs = append(allErrs, validateCorefileOverrides(s.CorefileOverrides, pathPrefix.Child("corefileOverrides"))...)
+	allErrs = append(allErrs, validateKubeadmConfigOverrides(s.InitConfigOverrides, pathPrefix.Child("initConfigOverrides"))...)
+	allErrs = append(allErrs, validateKubeadmConfigOverrides(s.JoinConfigOverrides, pathPrefix.Child("joinConfigOverrides"))...)
+	allErrs = append(allErrs, validateInPlaceUpgrade(s.InPlaceUpgrade, pathPrefix.Child("inPlaceUpgrade"))...)
+	if s.MachineTemplate != nil {
+		allErrs = append(allErrs, validateHostCleanup(s.MachineTemplate.HostCleanup, pathPrefix.Child("machineTemplate", "hostCleanup"))...)
+	}
 
 	return allErrs
 }
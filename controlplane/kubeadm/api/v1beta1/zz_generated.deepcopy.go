@@ -26,8 +26,249 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	kubeadmapiv1beta1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorefileOverrides) DeepCopyInto(out *CorefileOverrides) {
+	*out = *in
+	if in.AddPlugins != nil {
+		in, out := &in.AddPlugins, &out.AddPlugins
+		*out = make([]CorefilePlugin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RemovePlugins != nil {
+		in, out := &in.RemovePlugins, &out.RemovePlugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForwardServers != nil {
+		in, out := &in.ForwardServers, &out.ForwardServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]CorefileZone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorefileOverrides.
+func (in *CorefileOverrides) DeepCopy() *CorefileOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(CorefileOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorefilePlugin) DeepCopyInto(out *CorefilePlugin) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]CorefilePluginOption, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorefilePlugin.
+func (in *CorefilePlugin) DeepCopy() *CorefilePlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(CorefilePlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorefilePluginOption) DeepCopyInto(out *CorefilePluginOption) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorefilePluginOption.
+func (in *CorefilePluginOption) DeepCopy() *CorefilePluginOption {
+	if in == nil {
+		return nil
+	}
+	out := new(CorefilePluginOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorefileZone) DeepCopyInto(out *CorefileZone) {
+	*out = *in
+	if in.ForwardServers != nil {
+		in, out := &in.ForwardServers, &out.ForwardServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]CorefilePlugin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorefileZone.
+func (in *CorefileZone) DeepCopy() *CorefileZone {
+	if in == nil {
+		return nil
+	}
+	out := new(CorefileZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionAtRest) DeepCopyInto(out *EncryptionAtRest) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RotateKeysAfter != nil {
+		in, out := &in.RotateKeysAfter, &out.RotateKeysAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionAtRest.
+func (in *EncryptionAtRest) DeepCopy() *EncryptionAtRest {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionAtRest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionAtRestStatus) DeepCopyInto(out *EncryptionAtRestStatus) {
+	*out = *in
+	if in.LastKeyRotationTime != nil {
+		in, out := &in.LastKeyRotationTime, &out.LastKeyRotationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionAtRestStatus.
+func (in *EncryptionAtRestStatus) DeepCopy() *EncryptionAtRestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionAtRestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostCleanup) DeepCopyInto(out *HostCleanup) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostCleanup.
+func (in *HostCleanup) DeepCopy() *HostCleanup {
+	if in == nil {
+		return nil
+	}
+	out := new(HostCleanup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InPlaceUpgrade) DeepCopyInto(out *InPlaceUpgrade) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InPlaceUpgrade.
+func (in *InPlaceUpgrade) DeepCopy() *InPlaceUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(InPlaceUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeadmConfigOverrides) DeepCopyInto(out *KubeadmConfigOverrides) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]kubeadmapiv1beta1.File, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreKubeadmCommands != nil {
+		in, out := &in.PreKubeadmCommands, &out.PreKubeadmCommands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostKubeadmCommands != nil {
+		in, out := &in.PostKubeadmCommands, &out.PostKubeadmCommands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KubeletExtraArgs != nil {
+		in, out := &in.KubeletExtraArgs, &out.KubeletExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = new(kubeadmapiv1beta1.Patches)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmConfigOverrides.
+func (in *KubeadmConfigOverrides) DeepCopy() *KubeadmConfigOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeadmConfigOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeadmControlPlane) DeepCopyInto(out *KubeadmControlPlane) {
 	*out = *in
@@ -107,6 +348,11 @@ func (in *KubeadmControlPlaneMachineTemplate) DeepCopyInto(out *KubeadmControlPl
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.HostCleanup != nil {
+		in, out := &in.HostCleanup, &out.HostCleanup
+		*out = new(HostCleanup)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmControlPlaneMachineTemplate.
@@ -148,6 +394,36 @@ func (in *KubeadmControlPlaneSpec) DeepCopyInto(out *KubeadmControlPlaneSpec) {
 		*out = new(RemediationStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(EncryptionAtRest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CorefileOverrides != nil {
+		in, out := &in.CorefileOverrides, &out.CorefileOverrides
+		*out = new(CorefileOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitConfigOverrides != nil {
+		in, out := &in.InitConfigOverrides, &out.InitConfigOverrides
+		*out = new(KubeadmConfigOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JoinConfigOverrides != nil {
+		in, out := &in.JoinConfigOverrides, &out.JoinConfigOverrides
+		*out = new(KubeadmConfigOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InPlaceUpgrade != nil {
+		in, out := &in.InPlaceUpgrade, &out.InPlaceUpgrade
+		*out = new(InPlaceUpgrade)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmControlPlaneSpec.
@@ -185,6 +461,24 @@ func (in *KubeadmControlPlaneStatus) DeepCopyInto(out *KubeadmControlPlaneStatus
 		*out = new(LastRemediationStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RolloutOrder != nil {
+		in, out := &in.RolloutOrder, &out.RolloutOrder
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(EncryptionAtRestStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = (*in).DeepCopy()
+	}
+	if in.CertificatesExpiryDate != nil {
+		in, out := &in.CertificatesExpiryDate, &out.CertificatesExpiryDate
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmControlPlaneStatus.
@@ -274,6 +568,11 @@ func (in *KubeadmControlPlaneTemplateMachineTemplate) DeepCopyInto(out *KubeadmC
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.HostCleanup != nil {
+		in, out := &in.HostCleanup, &out.HostCleanup
+		*out = new(HostCleanup)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmControlPlaneTemplateMachineTemplate.
@@ -331,6 +630,36 @@ func (in *KubeadmControlPlaneTemplateResourceSpec) DeepCopyInto(out *KubeadmCont
 		*out = new(RemediationStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(EncryptionAtRest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CorefileOverrides != nil {
+		in, out := &in.CorefileOverrides, &out.CorefileOverrides
+		*out = new(CorefileOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitConfigOverrides != nil {
+		in, out := &in.InitConfigOverrides, &out.InitConfigOverrides
+		*out = new(KubeadmConfigOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JoinConfigOverrides != nil {
+		in, out := &in.JoinConfigOverrides, &out.JoinConfigOverrides
+		*out = new(KubeadmConfigOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InPlaceUpgrade != nil {
+		in, out := &in.InPlaceUpgrade, &out.InPlaceUpgrade
+		*out = new(InPlaceUpgrade)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmControlPlaneTemplateResourceSpec.
@@ -37,6 +37,14 @@ type KubeadmControlPlaneReconciler struct {
 	EtcdDialTimeout time.Duration
 	EtcdCallTimeout time.Duration
 
+	// HostCommandImage is the image used for the privileged Pods running commands on the hosts of control plane
+	// machines, i.e. for rotating the etcd certificates, cleaning up the hosts and upgrading in place.
+	HostCommandImage string
+
+	// InPlaceUpgradeBinariesURL is the base URL the binaries are downloaded from when upgrading in place, if not set in
+	// the KubeadmControlPlane, e.g. a mirror of the Kubernetes release binaries reachable from air-gapped clusters.
+	InPlaceUpgradeBinariesURL string
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 }
@@ -50,5 +58,8 @@ func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mg
 		EtcdDialTimeout:  r.EtcdDialTimeout,
 		EtcdCallTimeout:  r.EtcdCallTimeout,
 		WatchFilterValue: r.WatchFilterValue,
+
+		HostCommandImage:          r.HostCommandImage,
+		InPlaceUpgradeBinariesURL: r.InPlaceUpgradeBinariesURL,
 	}).SetupWithManager(ctx, mgr, options)
 }
@@ -160,12 +160,13 @@ func (m *Management) GetWorkloadCluster(ctx context.Context, clusterKey client.O
 		MinVersion:   tls.VersionTLS12,
 	}
 	tlsConfig.InsecureSkipVerify = true
-	return &Workload{
-		restConfig:          restConfig,
-		Client:              c,
-		CoreDNSMigrator:     &CoreDNSMigrator{},
-		etcdClientGenerator: NewEtcdClientGenerator(restConfig, tlsConfig, m.EtcdDialTimeout, m.EtcdCallTimeout),
-	}, nil
+	workload := &Workload{
+		restConfig:      restConfig,
+		Client:          c,
+		CoreDNSMigrator: &CoreDNSMigrator{},
+	}
+	workload.etcdClientGenerator = NewEtcdClientGenerator(restConfig, tlsConfig, m.EtcdDialTimeout, m.EtcdCallTimeout, workload.getEtcdClientEndpoint)
+	return workload, nil
 }
 
 func (m *Management) getEtcdCAKeyPair(ctx context.Context, clusterKey client.ObjectKey) ([]byte, []byte, error) {
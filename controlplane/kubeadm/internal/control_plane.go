@@ -18,6 +18,7 @@ package internal
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -51,6 +52,10 @@ type ControlPlane struct {
 	// See discussion on https://github.com/kubernetes-sigs/cluster-api/pull/3405
 	KubeadmConfigs map[string]*bootstrapv1.KubeadmConfig
 	InfraResources map[string]*unstructured.Unstructured
+
+	// etcdLeaderNodeName is the name of the node hosting the etcd leader as observed while updating
+	// the etcd conditions; it is empty if the etcd leader is not known.
+	etcdLeaderNodeName string
 }
 
 // NewControlPlane returns an instantiated ControlPlane.
@@ -102,6 +107,71 @@ func (c *ControlPlane) MachineInFailureDomainWithMostMachines(machines collectio
 	return machineToMark, nil
 }
 
+// RolloutOrder returns the order in which machines are rolled out.
+func (c *ControlPlane) RolloutOrder() controlplanev1.RolloutOrder {
+	if c.KCP.Spec.RolloutStrategy == nil || c.KCP.Spec.RolloutStrategy.Order == "" {
+		return controlplanev1.FailureDomainRoundRobinRolloutOrder
+	}
+	return c.KCP.Spec.RolloutStrategy.Order
+}
+
+// NextMachineForRollout returns the machine to be rolled out first among the given machines,
+// according to the rollout order.
+func (c *ControlPlane) NextMachineForRollout(machines collections.Machines) (*clusterv1.Machine, error) {
+	switch c.RolloutOrder() {
+	case controlplanev1.OldestFirstRolloutOrder:
+		machineToMark := machines.Oldest()
+		if machineToMark == nil {
+			return nil, errors.New("failed to pick control plane Machine to mark for deletion")
+		}
+		return machineToMark, nil
+	case controlplanev1.LeaderLastRolloutOrder:
+		// Defer the machine hosting the etcd leader until it is the last one to be rolled out.
+		if leader := c.EtcdLeaderMachine(); leader != nil && machines.Len() > 1 {
+			machines = machines.Filter(func(machine *clusterv1.Machine) bool {
+				return machine.Name != leader.Name
+			})
+		}
+	}
+	return c.MachineInFailureDomainWithMostMachines(machines)
+}
+
+// MachinesRolloutOrder returns the names of the given machines in the order they are going to be rolled out.
+// NOTE: the order is computed assuming that the machines are rolled out one at a time and that no other
+// machines are deleted in the meantime.
+func (c *ControlPlane) MachinesRolloutOrder(machines collections.Machines) []string {
+	names := make([]string, 0, machines.Len())
+	simulated := &ControlPlane{
+		KCP:                c.KCP,
+		Cluster:            c.Cluster,
+		Machines:           c.Machines,
+		etcdLeaderNodeName: c.etcdLeaderNodeName,
+	}
+	for machines.Len() > 0 {
+		machine, err := simulated.NextMachineForRollout(machines)
+		if err != nil {
+			break
+		}
+		names = append(names, machine.Name)
+		machines = machines.Difference(collections.FromMachines(machine))
+		simulated.Machines = simulated.Machines.Difference(collections.FromMachines(machine))
+	}
+	return names
+}
+
+// EtcdLeaderMachine returns the machine hosting the etcd leader, if known.
+func (c *ControlPlane) EtcdLeaderMachine() *clusterv1.Machine {
+	if c.etcdLeaderNodeName == "" {
+		return nil
+	}
+	for _, machine := range c.Machines {
+		if machine.Status.NodeRef != nil && machine.Status.NodeRef.Name == c.etcdLeaderNodeName {
+			return machine
+		}
+	}
+	return nil
+}
+
 // MachineWithDeleteAnnotation returns a machine that has been annotated with DeleteMachineAnnotation key.
 func (c *ControlPlane) MachineWithDeleteAnnotation(machines collections.Machines) collections.Machines {
 	// See if there are any machines with DeleteMachineAnnotation key.
@@ -134,10 +204,37 @@ func (c *ControlPlane) NextFailureDomainForScaleUp() *string {
 	return failuredomains.PickFewest(c.FailureDomains().FilterControlPlane(), c.UpToDateMachines())
 }
 
+// NextFailureDomainsForScaleUp returns the failure domains for the next n machines to be created when scaling up;
+// failure domains are picked as if the machines were created one after the other, so they are spread across failure domains.
+func (c *ControlPlane) NextFailureDomainsForScaleUp(n int) []*string {
+	failureDomains := make([]*string, n)
+	if len(c.Cluster.Status.FailureDomains.FilterControlPlane()) == 0 {
+		return failureDomains
+	}
+	machines := c.UpToDateMachines()
+	for i := range failureDomains {
+		failureDomains[i] = failuredomains.PickFewest(c.FailureDomains().FilterControlPlane(), machines)
+		// Account for the machine to be created in the picked failure domain.
+		machines.Insert(&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("scale-up-%d", i)},
+			Spec:       clusterv1.MachineSpec{FailureDomain: failureDomains[i]},
+		})
+	}
+	return failureDomains
+}
+
 // InitialControlPlaneConfig returns a new KubeadmConfigSpec that is to be used for an initializing control plane.
 func (c *ControlPlane) InitialControlPlaneConfig() *bootstrapv1.KubeadmConfigSpec {
 	bootstrapSpec := c.KCP.Spec.KubeadmConfigSpec.DeepCopy()
 	bootstrapSpec.JoinConfiguration = nil
+	if _, ok := encryptionConfigPath(c.KCP); ok {
+		if bootstrapSpec.ClusterConfiguration == nil {
+			bootstrapSpec.ClusterConfiguration = &bootstrapv1.ClusterConfiguration{}
+		}
+		bootstrapSpec.ClusterConfiguration.APIServer = APIServerWithEncryptionAtRest(c.KCP, bootstrapSpec.ClusterConfiguration.APIServer)
+	}
+	withInitConfigOverrides(bootstrapSpec, c.KCP.Spec.InitConfigOverrides)
+	withEncryptionConfigFile(c.KCP, bootstrapSpec)
 	return bootstrapSpec
 }
 
@@ -148,6 +245,9 @@ func (c *ControlPlane) JoinControlPlaneConfig() *bootstrapv1.KubeadmConfigSpec {
 	// NOTE: For the joining we are preserving the ClusterConfiguration in order to determine if the
 	// cluster is using an external etcd in the kubeadm bootstrap provider (even if this is not required by kubeadm Join).
 	// TODO: Determine if this copy of cluster configuration can be used for rollouts (thus allowing to remove the annotation at machine level)
+	// NOTE: The API server of joining machines is configured to use the EncryptionConfiguration via the kubeadm-config ConfigMap.
+	withJoinConfigOverrides(bootstrapSpec, c.KCP.Spec.JoinConfigOverrides)
+	withEncryptionConfigFile(c.KCP, bootstrapSpec)
 	return bootstrapSpec
 }
 
@@ -173,6 +273,20 @@ func (c *ControlPlane) MachinesNeedingRollout() collections.Machines {
 	)
 }
 
+// MachinesNeedingInPlaceUpgrade returns the machines that can be upgraded in place to the KCP version, excluding the
+// machines for which an in-place upgrade to the KCP version already failed.
+func (c *ControlPlane) MachinesNeedingInPlaceUpgrade() collections.Machines {
+	// Ignore machines to be deleted.
+	machines := c.Machines.Filter(collections.Not(collections.HasDeletionTimestamp))
+
+	return machines.Filter(
+		func(machine *clusterv1.Machine) bool {
+			return machine.Annotations[controlplanev1.InPlaceUpgradeFailedAnnotation] != c.KCP.Spec.Version
+		},
+		NeedsInPlacePatchUpgrade(&c.reconciliationTime, c.InfraResources, c.KubeadmConfigs, c.KCP),
+	)
+}
+
 // UpToDateMachines returns the machines that are up to date with the control
 // plane's configuration and therefore do not require rollout.
 func (c *ControlPlane) UpToDateMachines() collections.Machines {
@@ -18,9 +18,12 @@ package internal
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
@@ -62,6 +65,117 @@ func TestControlPlane(t *testing.T) {
 	})
 }
 
+func TestNextFailureDomainsForScaleUp(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := &ControlPlane{
+		KCP: &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{Version: "v1.26.0"},
+		},
+		Cluster: &clusterv1.Cluster{
+			Status: clusterv1.ClusterStatus{
+				FailureDomains: clusterv1.FailureDomains{
+					"one":   failureDomain(true),
+					"two":   failureDomain(true),
+					"three": failureDomain(true),
+					"four":  failureDomain(false),
+				},
+			},
+		},
+		Machines: collections.FromMachines(
+			machine("machine-1", withFailureDomain("one"), func(m *clusterv1.Machine) {
+				m.Spec.Version = pointer.String("v1.26.0")
+			}),
+		),
+	}
+
+	// Machines are spread across control plane failure domains, taking into account the existing machines.
+	failureDomains := map[string]int{"one": 1}
+	for i, fd := range controlPlane.NextFailureDomainsForScaleUp(5) {
+		g.Expect(fd).ToNot(BeNil())
+		if i < 2 {
+			g.Expect(*fd).To(BeElementOf("two", "three"))
+		}
+		failureDomains[*fd]++
+	}
+	g.Expect(failureDomains).To(Equal(map[string]int{"one": 2, "two": 2, "three": 2}))
+
+	t.Run("Without failure domains", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane.Cluster.Status.FailureDomains = nil
+		g.Expect(controlPlane.NextFailureDomainsForScaleUp(2)).To(Equal([]*string{nil, nil}))
+	})
+}
+
+func TestControlPlaneRolloutOrder(t *testing.T) {
+	now := time.Now()
+	newControlPlane := func(order controlplanev1.RolloutOrder) *ControlPlane {
+		return &ControlPlane{
+			KCP: &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					RolloutStrategy: &controlplanev1.RolloutStrategy{
+						Order: order,
+					},
+				},
+			},
+			Cluster: &clusterv1.Cluster{
+				Status: clusterv1.ClusterStatus{
+					FailureDomains: clusterv1.FailureDomains{
+						"one": failureDomain(true),
+						"two": failureDomain(true),
+					},
+				},
+			},
+			Machines: collections.FromMachines(
+				machine("machine-1", withFailureDomain("one"), withCreationTimestamp(now.Add(-3*time.Hour)), withMachineNodeRef("node-1")),
+				machine("machine-2", withFailureDomain("two"), withCreationTimestamp(now.Add(-2*time.Hour)), withMachineNodeRef("node-2")),
+				machine("machine-3", withFailureDomain("two"), withCreationTimestamp(now.Add(-1*time.Hour)), withMachineNodeRef("node-3")),
+			),
+			etcdLeaderNodeName: "node-2",
+		}
+	}
+
+	t.Run("Defaults to FailureDomainRoundRobin", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane := newControlPlane("")
+		g.Expect(controlPlane.RolloutOrder()).To(Equal(controlplanev1.FailureDomainRoundRobinRolloutOrder))
+
+		order := controlPlane.MachinesRolloutOrder(controlPlane.Machines)
+		g.Expect(order).To(ConsistOf("machine-1", "machine-2", "machine-3"))
+		// The oldest machine in the failure domain with most machines is rolled out first.
+		g.Expect(order[0]).To(Equal("machine-2"))
+	})
+
+	t.Run("OldestFirst", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane := newControlPlane(controlplanev1.OldestFirstRolloutOrder)
+		g.Expect(controlPlane.MachinesRolloutOrder(controlPlane.Machines)).To(Equal([]string{"machine-1", "machine-2", "machine-3"}))
+	})
+
+	t.Run("LeaderLast", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane := newControlPlane(controlplanev1.LeaderLastRolloutOrder)
+		g.Expect(controlPlane.EtcdLeaderMachine().Name).To(Equal("machine-2"))
+		g.Expect(controlPlane.MachinesRolloutOrder(controlPlane.Machines)).To(Equal([]string{"machine-3", "machine-1", "machine-2"}))
+	})
+
+	t.Run("LeaderLast without a known leader falls back to FailureDomainRoundRobin", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane := newControlPlane(controlplanev1.LeaderLastRolloutOrder)
+		controlPlane.etcdLeaderNodeName = ""
+		g.Expect(controlPlane.EtcdLeaderMachine()).To(BeNil())
+
+		machine, err := controlPlane.NextMachineForRollout(controlPlane.Machines)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(machine.Name).To(Equal("machine-2"))
+	})
+}
+
 func TestHasUnhealthyMachine(t *testing.T) {
 	// healthy machine (without MachineHealthCheckSucceded condition)
 	healthyMachine1 := &clusterv1.Machine{}
@@ -103,6 +217,18 @@ func withFailureDomain(fd string) machineOpt {
 	}
 }
 
+func withCreationTimestamp(timestamp time.Time) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.CreationTimestamp = metav1.NewTime(timestamp)
+	}
+}
+
+func withMachineNodeRef(name string) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.Status.NodeRef = &corev1.ObjectReference{Name: name}
+	}
+}
+
 func machine(name string, opts ...machineOpt) *clusterv1.Machine {
 	m := &clusterv1.Machine{
 		ObjectMeta: metav1.ObjectMeta{
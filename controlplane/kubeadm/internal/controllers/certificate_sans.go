@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileCertificateSANsCondition sets the CertificateSANsUpToDate condition documenting the API server certificate
+// SANs currently served by the control plane machines vs the desired ones.
+// NOTE: Machines serving outdated SANs are rolled out as any other machine with an outdated ClusterConfiguration;
+// when joining, new machines generate the API server serving certificate from the kubeadm-config ConfigMap,
+// which is updated with the desired SANs before scaling up (see upgradeControlPlane).
+func reconcileCertificateSANsCondition(controlPlane *internal.ControlPlane) {
+	desired := sets.Set[string]{}
+	if controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration != nil {
+		desired.Insert(controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer.CertSANs...)
+	}
+
+	// Group machines serving outdated SANs by the SANs they are serving.
+	machinesByServedSANs := map[string][]string{}
+	machines := controlPlane.Machines.Filter(collections.Not(collections.HasDeletionTimestamp))
+	for _, m := range machines {
+		served, ok := servedCertSANs(m)
+		if !ok || served.Equal(desired) {
+			continue
+		}
+		key := formatCertSANs(served)
+		machinesByServedSANs[key] = append(machinesByServedSANs[key], m.Name)
+	}
+
+	if len(machinesByServedSANs) == 0 {
+		// NOTE: we are checking the condition already exists in order to avoid to set this condition
+		// for control planes that never changed their SANs.
+		if conditions.Has(controlPlane.KCP, controlplanev1.CertificateSANsUpToDateCondition) {
+			conditions.MarkTrue(controlPlane.KCP, controlplanev1.CertificateSANsUpToDateCondition)
+		}
+		return
+	}
+
+	served := make([]string, 0, len(machinesByServedSANs))
+	for sans, names := range machinesByServedSANs {
+		sort.Strings(names)
+		served = append(served, fmt.Sprintf("%s served by %s", sans, strings.Join(names, ", ")))
+	}
+	sort.Strings(served)
+	conditions.MarkFalse(controlPlane.KCP, controlplanev1.CertificateSANsUpToDateCondition, controlplanev1.CertificateSANsRolloutInProgressReason, clusterv1.ConditionSeverityWarning,
+		"Desired certificate SANs %s; %s", formatCertSANs(desired), strings.Join(served, "; "))
+}
+
+// servedCertSANs returns the API server certificate SANs served by a machine, as recorded in the ClusterConfiguration
+// annotation set when the machine was created; false is returned when it is not possible to determine them
+// (e.g. the machine is old or adopted).
+func servedCertSANs(machine *clusterv1.Machine) (sets.Set[string], bool) {
+	clusterConfigStr, ok := machine.GetAnnotations()[controlplanev1.KubeadmClusterConfigurationAnnotation]
+	if !ok {
+		return nil, false
+	}
+
+	clusterConfig := &bootstrapv1.ClusterConfiguration{}
+	if err := json.Unmarshal([]byte(clusterConfigStr), &clusterConfig); err != nil {
+		return nil, false
+	}
+	if clusterConfig == nil {
+		return sets.Set[string]{}, true
+	}
+	return sets.New[string](clusterConfig.APIServer.CertSANs...), true
+}
+
+func formatCertSANs(sans sets.Set[string]) string {
+	return fmt.Sprintf("[%s]", strings.Join(sets.List(sans), ", "))
+}
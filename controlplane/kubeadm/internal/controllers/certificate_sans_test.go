@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileCertificateSANsCondition(t *testing.T) {
+	machineWithSANs := func(name, clusterConfiguration string) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		if clusterConfiguration != "" {
+			m.Annotations = map[string]string{
+				controlplanev1.KubeadmClusterConfigurationAnnotation: clusterConfiguration,
+			}
+		}
+		return m
+	}
+
+	tests := []struct {
+		name                string
+		desiredSANs         []string
+		machines            []*clusterv1.Machine
+		existingCondition   bool
+		wantCondition       bool
+		wantConditionStatus corev1.ConditionStatus
+		wantMessage         string
+	}{
+		{
+			name:        "does not set the condition if all the machines are serving the desired SANs",
+			desiredSANs: []string{"b.example.com", "a.example.com"},
+			machines: []*clusterv1.Machine{
+				machineWithSANs("m1", `{"apiServer":{"certSANs":["a.example.com","b.example.com"]}}`),
+			},
+			wantCondition: false,
+		},
+		{
+			name:        "sets the condition to true if all the machines are serving the desired SANs after a rollout",
+			desiredSANs: []string{"a.example.com"},
+			machines: []*clusterv1.Machine{
+				machineWithSANs("m1", `{"apiServer":{"certSANs":["a.example.com"]}}`),
+			},
+			existingCondition:   true,
+			wantCondition:       true,
+			wantConditionStatus: corev1.ConditionTrue,
+		},
+		{
+			name:        "ignores machines without the ClusterConfiguration annotation",
+			desiredSANs: []string{"a.example.com"},
+			machines: []*clusterv1.Machine{
+				machineWithSANs("m1", ""),
+			},
+			wantCondition: false,
+		},
+		{
+			name:        "sets the condition to false documenting served vs desired SANs",
+			desiredSANs: []string{"a.example.com", "c.example.com"},
+			machines: []*clusterv1.Machine{
+				machineWithSANs("m1", `{"apiServer":{"certSANs":["a.example.com","b.example.com"]}}`),
+				machineWithSANs("m2", `{"apiServer":{"certSANs":["a.example.com","b.example.com"]}}`),
+				machineWithSANs("m3", `{"apiServer":{}}`),
+				machineWithSANs("m4", `{"apiServer":{"certSANs":["c.example.com","a.example.com"]}}`),
+			},
+			wantCondition:       true,
+			wantConditionStatus: corev1.ConditionFalse,
+			wantMessage:         "Desired certificate SANs [a.example.com, c.example.com]; [] served by m3; [a.example.com, b.example.com] served by m1, m2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kcp := &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+						ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+							APIServer: bootstrapv1.APIServer{
+								CertSANs: tt.desiredSANs,
+							},
+						},
+					},
+				},
+			}
+			if tt.existingCondition {
+				conditions.MarkFalse(kcp, controlplanev1.CertificateSANsUpToDateCondition, controlplanev1.CertificateSANsRolloutInProgressReason, clusterv1.ConditionSeverityWarning, "")
+			}
+			controlPlane := &internal.ControlPlane{
+				KCP:      kcp,
+				Cluster:  &clusterv1.Cluster{},
+				Machines: collections.FromMachines(tt.machines...),
+			}
+
+			reconcileCertificateSANsCondition(controlPlane)
+
+			if !tt.wantCondition {
+				g.Expect(conditions.Has(kcp, controlplanev1.CertificateSANsUpToDateCondition)).To(BeFalse())
+				return
+			}
+			c := conditions.Get(kcp, controlplanev1.CertificateSANsUpToDateCondition)
+			g.Expect(c).ToNot(BeNil())
+			g.Expect(c.Status).To(Equal(tt.wantConditionStatus))
+			g.Expect(c.Message).To(Equal(tt.wantMessage))
+		})
+	}
+}
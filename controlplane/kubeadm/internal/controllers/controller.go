@@ -45,13 +45,16 @@ import (
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
-	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/profiling"
+	"sigs.k8s.io/cluster-api/internal/util/deletion"
+	"sigs.k8s.io/cluster-api/internal/util/progress"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
-	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/contract"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/paused"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/cluster-api/util/version"
@@ -60,6 +63,10 @@ import (
 const (
 	kcpManagerName          = "capi-kubeadmcontrolplane"
 	kubeadmControlPlaneKind = "KubeadmControlPlane"
+
+	// defaultHostCommandImage is the image used for the privileged Pods running commands on the hosts of control plane
+	// machines, if not configured otherwise.
+	defaultHostCommandImage = "docker.io/library/busybox:1.36"
 )
 
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
@@ -68,6 +75,7 @@ const (
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
 
 // KubeadmControlPlaneReconciler reconciles a KubeadmControlPlane object.
 type KubeadmControlPlaneReconciler struct {
@@ -79,6 +87,14 @@ type KubeadmControlPlaneReconciler struct {
 	EtcdDialTimeout time.Duration
 	EtcdCallTimeout time.Duration
 
+	// HostCommandImage is the image used for the privileged Pods running commands on the hosts of control plane
+	// machines, i.e. for rotating the etcd certificates, cleaning up the hosts and upgrading in place.
+	HostCommandImage string
+
+	// InPlaceUpgradeBinariesURL is the base URL the binaries are downloaded from when upgrading in place, if not set in
+	// the KubeadmControlPlane, e.g. a mirror of the Kubernetes release binaries reachable from air-gapped clusters.
+	InPlaceUpgradeBinariesURL string
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
@@ -105,7 +121,10 @@ func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mg
 			builder.WithPredicates(
 				predicates.All(ctrl.LoggerFrom(ctx),
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
-					predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx)),
+					predicates.Any(ctrl.LoggerFrom(ctx),
+						predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx)),
+						predicates.ClusterPauseChanged(ctrl.LoggerFrom(ctx)),
+					),
 				),
 			),
 		).Build(r)
@@ -139,8 +158,13 @@ func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mg
 func (r *KubeadmControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, reterr error) {
 	log := ctrl.LoggerFrom(ctx)
 
+	ctx, done := profiling.StartReconcile(ctx, "kubeadmcontrolplane")
+	defer done()
+
 	// Fetch the KubeadmControlPlane instance.
 	kcp := &controlplanev1.KubeadmControlPlane{}
+	fetchDone := profiling.StartPhase(ctx, profiling.PhaseFetch)
+	defer fetchDone()
 	if err := r.Client.Get(ctx, req.NamespacedName, kcp); err != nil {
 		if apierrors.IsNotFound(err) {
 			return ctrl.Result{}, nil
@@ -160,10 +184,17 @@ func (r *KubeadmControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.
 	}
 	log = log.WithValues("Cluster", klog.KObj(cluster))
 	ctx = ctrl.LoggerInto(ctx, log)
+	profiling.SetCluster(ctx, client.ObjectKeyFromObject(cluster))
+	fetchDone()
 
-	if annotations.IsPaused(cluster, kcp) {
+	// Return early if the object or Cluster is paused.
+	isPaused, requeueAfter, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, kcp)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if isPaused {
 		log.Info("Reconciliation is paused for this object")
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	// Initialize the patch helper.
@@ -202,10 +233,12 @@ func (r *KubeadmControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.
 		}
 
 		// Always attempt to Patch the KubeadmControlPlane object and status after each reconciliation.
+		patchDone := profiling.StartPhase(ctx, profiling.PhasePatch)
 		if err := patchKubeadmControlPlane(ctx, patchHelper, kcp); err != nil {
 			log.Error(err, "Failed to patch KubeadmControlPlane")
 			reterr = kerrors.NewAggregate([]error{reterr, err})
 		}
+		patchDone()
 
 		// TODO: remove this as soon as we have a proper remote cluster cache in place.
 		// Make KCP to requeue in case status is not ready, so we can check for node status without waiting for a full resync (by default 10 minutes).
@@ -215,6 +248,17 @@ func (r *KubeadmControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.
 				res = ctrl.Result{RequeueAfter: 20 * time.Second}
 			}
 		}
+
+		// Requeue when the progress deadline expires, so operations not making progress are detected even if nothing changes.
+		if reterr == nil && kcp.ObjectMeta.DeletionTimestamp.IsZero() {
+			res = util.LowestNonZeroResult(res, ctrl.Result{RequeueAfter: progress.RequeueAfter(kcp.Status.ProgressDeadline, time.Now())})
+		}
+
+		// Requeue when the certificates of a machine are about to expire, so the rollout required by
+		// spec.rolloutBefore.certificatesExpiryDays starts on time.
+		if reterr == nil && kcp.ObjectMeta.DeletionTimestamp.IsZero() {
+			res = util.LowestNonZeroResult(res, ctrl.Result{RequeueAfter: certificatesRolloutRequeueAfter(kcp, time.Now())})
+		}
 	}()
 
 	if !kcp.ObjectMeta.DeletionTimestamp.IsZero() {
@@ -265,6 +309,8 @@ func patchKubeadmControlPlane(ctx context.Context, patchHelper *patch.Helper, kc
 			controlplanev1.MachinesReadyCondition,
 			controlplanev1.AvailableCondition,
 			controlplanev1.CertificatesAvailableCondition,
+			controlplanev1.CertificateSANsUpToDateCondition,
+			clusterv1.ProgressingCondition,
 		}},
 		patch.WithStatusObservedGeneration{},
 	)
@@ -276,7 +322,19 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 	log.Info("Reconcile KubeadmControlPlane")
 
 	// Make sure to reconcile the external infrastructure reference.
-	if err := r.reconcileExternalReference(ctx, cluster, &kcp.Spec.MachineTemplate.InfrastructureRef); err != nil {
+	infraTemplate, err := r.reconcileExternalReference(ctx, cluster, &kcp.Spec.MachineTemplate.InfrastructureRef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Record the checksum of the content of the infrastructure template, so machines cloned from a template with
+	// the same content but a different name are not rolled out.
+	if err := setInfrastructureTemplateChecksum(kcp, infraTemplate); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Record the current machine template and KubeadmConfigSpec in the revision history.
+	if err := r.reconcileRevisionHistory(ctx, kcp); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -349,6 +407,12 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 		}
 	}
 
+	// Reconcile the EncryptionConfiguration before checking if machines need rollout, given that changes to the
+	// EncryptionConfiguration, e.g. key rotations, are rolled out to the control plane machines.
+	if err := r.reconcileEncryptionAtRest(ctx, controlPlane); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile encryption at rest")
+	}
+
 	// Aggregate the operational state of all the machines; while aggregating we are adding the
 	// source ref (reason@machine/name) so the problem can be easily tracked down to its source machine.
 	conditions.SetAggregate(controlPlane.KCP, controlplanev1.MachinesReadyCondition, ownedMachines.ConditionGetters(), conditions.AddSourceRef(), conditions.WithStepCounterIf(false))
@@ -359,6 +423,11 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 		return result, err
 	}
 
+	// Cleans up the hosts of the machines being deleted, if a host cleanup is configured.
+	if result, err := r.reconcileHostCleanup(ctx, controlPlane); err != nil || !result.IsZero() {
+		return result, err
+	}
+
 	// Ensures the number of etcd members is in sync with the number of machines/nodes.
 	// NOTE: This is usually required after a machine deletion.
 	if result, err := r.reconcileEtcdMembers(ctx, controlPlane); err != nil || !result.IsZero() {
@@ -371,19 +440,40 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 		return result, err
 	}
 
+	// Permits the joins of the control plane machines created in parallel during the initial provisioning, one at a time.
+	if result, err := r.reconcileJoinPermits(ctx, controlPlane); err != nil || !result.IsZero() {
+		return result, err
+	}
+
 	// Reconcile certificate expiry for machines that don't have the expiry annotation on KubeadmConfig yet.
 	if result, err := r.reconcileCertificateExpiries(ctx, controlPlane); err != nil || !result.IsZero() {
 		return result, err
 	}
 
+	// Documents the API server certificate SANs served by the machines vs the desired ones; machines serving
+	// outdated SANs are rolled out below.
+	reconcileCertificateSANsCondition(controlPlane)
+
+	// With the InPlacePatch upgrade policy, machines only requiring a patch version upgrade are upgraded in place
+	// before rolling out the remaining ones.
+	if result, err := r.reconcileInPlaceUpgrade(ctx, controlPlane); err != nil || !result.IsZero() {
+		return result, err
+	}
+
 	// Control plane machines rollout due to configuration changes (e.g. upgrades) takes precedence over other operations.
 	needRollout := controlPlane.MachinesNeedingRollout()
 	switch {
+	case len(needRollout) > 0 && isRolloutPaused(kcp):
+		log.Info("Rollout of Control Plane machines is paused", "needRollout", needRollout.Names())
+		controlPlane.KCP.Status.RolloutOrder = nil
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition, controlplanev1.RolloutPausedReason, clusterv1.ConditionSeverityInfo, "Rollout of %d replicas with outdated spec is paused%s", len(needRollout), rolloutPauseReasonMessage(kcp))
 	case len(needRollout) > 0:
 		log.Info("Rolling out Control Plane machines", "needRollout", needRollout.Names())
+		controlPlane.KCP.Status.RolloutOrder = controlPlane.MachinesRolloutOrder(needRollout)
 		conditions.MarkFalse(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition, controlplanev1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityWarning, "Rolling %d replicas with outdated spec (%d replicas up to date)", len(needRollout), len(controlPlane.Machines)-len(needRollout))
 		return r.upgradeControlPlane(ctx, cluster, kcp, controlPlane, needRollout)
 	default:
+		controlPlane.KCP.Status.RolloutOrder = nil
 		// make sure last upgrade operation is marked as completed.
 		// NOTE: we are checking the condition already exists in order to avoid to set this condition at the first
 		// reconciliation/before a rolling upgrade actually starts.
@@ -445,6 +535,11 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 		return ctrl.Result{}, errors.Wrap(err, "failed to update CoreDNS deployment")
 	}
 
+	// Rotate etcd certificates in place, if requested; this happens only when no rollout or scaling operation is in progress.
+	if result, err := r.reconcileEtcdCertificatesRotation(ctx, controlPlane, workloadCluster); err != nil || !result.IsZero() {
+		return result, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -501,13 +596,27 @@ func (r *KubeadmControlPlaneReconciler) reconcileDelete(ctx context.Context, clu
 		return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
 	}
 
+	// Machines are deleted because of the Cluster deletion, unless the KubeadmControlPlane has been deleted directly.
+	deletionReason := clusterv1.DeletionReasonUser
+	if !cluster.DeletionTimestamp.IsZero() {
+		deletionReason = clusterv1.DeletionReasonClusterDeletion
+	}
+
+	// Hosts are not cleaned up when the whole control plane is deleted, given that the workload cluster is going away;
+	// remove the host cleanup hook, so it does not block the deletion of the machines.
+	for _, m := range ownedMachines.Filter(collections.HasAnnotationKey(controlplanev1.HostCleanupPreTerminateHookAnnotation)) {
+		if err := r.removeHostCleanupHook(ctx, m); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Delete control plane machines in parallel
 	machinesToDelete := ownedMachines.Filter(collections.Not(collections.HasDeletionTimestamp))
 	var errs []error
 	for i := range machinesToDelete {
 		m := machinesToDelete[i]
 		logger := log.WithValues("Machine", klog.KObj(m))
-		if err := r.Client.Delete(ctx, machinesToDelete[i]); err != nil && !apierrors.IsNotFound(err) {
+		if err := deletion.Delete(ctx, r.Client, r.recorder, machinesToDelete[i], deletionReason, deletion.Initiator(kubeadmControlPlaneKind, kcp)); err != nil && !apierrors.IsNotFound(err) {
 			logger.Error(err, "Failed to cleanup owned machine")
 			errs = append(errs, err)
 		}
@@ -522,6 +631,22 @@ func (r *KubeadmControlPlaneReconciler) reconcileDelete(ctx context.Context, clu
 	return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
 }
 
+// hostCommandImage returns the image used for the privileged Pods running commands on the hosts of control plane machines.
+func (r *KubeadmControlPlaneReconciler) hostCommandImage() string {
+	if r.HostCommandImage != "" {
+		return r.HostCommandImage
+	}
+	return defaultHostCommandImage
+}
+
+// inPlaceUpgradeBinariesURL returns the base URL the binaries are downloaded from when upgrading control plane machines in place.
+func (r *KubeadmControlPlaneReconciler) inPlaceUpgradeBinariesURL() string {
+	if r.InPlaceUpgradeBinariesURL != "" {
+		return r.InPlaceUpgradeBinariesURL
+	}
+	return defaultInPlaceUpgradeBinariesURL
+}
+
 // ClusterToKubeadmControlPlane is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
 // for KubeadmControlPlane based on updates to a Cluster.
 func (r *KubeadmControlPlaneReconciler) ClusterToKubeadmControlPlane(o client.Object) []ctrl.Request {
@@ -630,8 +755,18 @@ func (r *KubeadmControlPlaneReconciler) reconcileControlPlaneConditions(ctx cont
 		return ctrl.Result{}, nil
 	}
 
+	// Mark the conditions of the control plane machines as stale if they were not refreshed recently,
+	// e.g. because the workload cluster is not reachable.
+	for _, m := range controlPlane.Machines {
+		conditions.MarkStaleObservations(m, conditions.DefaultStaleObservationThreshold)
+	}
+
 	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
 	if err != nil {
+		// Patch machines to surface the stale conditions.
+		if patchErr := controlPlane.PatchMachines(ctx); patchErr != nil {
+			return ctrl.Result{}, kerrors.NewAggregate([]error{errors.Wrap(err, "cannot get remote client to workload cluster"), patchErr})
+		}
 		return ctrl.Result{}, errors.Wrap(err, "cannot get remote client to workload cluster")
 	}
 
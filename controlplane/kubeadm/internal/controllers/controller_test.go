@@ -52,13 +52,13 @@ import (
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
-	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/certs"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/contract"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/secret"
@@ -385,6 +385,21 @@ func TestReconcilePaused(t *testing.T) {
 	g.Expect(fakeClient.List(ctx, machineList, client.InNamespace(metav1.NamespaceDefault))).To(Succeed())
 	g.Expect(machineList.Items).To(BeEmpty())
 
+	gotKCP := &controlplanev1.KubeadmControlPlane{}
+	g.Expect(fakeClient.Get(ctx, util.ObjectKey(kcp), gotKCP)).To(Succeed())
+	g.Expect(conditions.IsTrue(gotKCP, clusterv1.PausedCondition)).To(BeTrue())
+
+	// Test: cluster is paused with an expiry, reconcile is requeued when the pause expires
+	cluster.Spec.Paused = false
+	cluster.Spec.Pause = &clusterv1.ClusterPause{Reason: "maintenance", ExpiresAt: &metav1.Time{Time: time.Now().Add(time.Hour)}}
+	fakeClient = newFakeClient(kcp.DeepCopy(), cluster.DeepCopy())
+	r.Client = fakeClient
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: util.ObjectKey(kcp)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically(">", 59*time.Minute))
+	g.Expect(result.RequeueAfter).To(BeNumerically("<=", time.Hour))
+	cluster.Spec.Pause = nil
+
 	// Test: kcp is paused and cluster is not
 	cluster.Spec.Paused = false
 	kcp.ObjectMeta.Annotations = map[string]string{}
@@ -0,0 +1,365 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiserverconfigv1 "k8s.io/apiserver/pkg/apis/config/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+const (
+	// encryptionKeysRotatedAtAnnotation is set on the encryption config Secret and tracks the time encryption keys
+	// have been generated or rotated last.
+	encryptionKeysRotatedAtAnnotation = "controlplane.cluster.x-k8s.io/encryption-keys-rotated-at"
+
+	// encryptionKeyPendingAnnotation is set on the encryption config Secret while a key rotation is in progress,
+	// and tracks the name of the new key which is going to be used for encrypting data.
+	encryptionKeyPendingAnnotation = "controlplane.cluster.x-k8s.io/encryption-key-pending"
+
+	// encryptionStorageMigratedKeyAnnotation is set on the encryption config Secret and tracks the name of the key
+	// all the encrypted resources in the workload cluster have been rewritten with.
+	encryptionStorageMigratedKeyAnnotation = "controlplane.cluster.x-k8s.io/encryption-storage-migrated-key"
+
+	// maxEncryptionKeys is the maximum number of keys preserved in the EncryptionConfiguration once all the encrypted
+	// resources have been rewritten with the primary key; until then, all the previous keys are preserved so it is
+	// still possible to decrypt data encrypted before a key rotation.
+	maxEncryptionKeys = 3
+
+	// encryptionKeySize is the size of the generated encryption keys, in bytes.
+	encryptionKeySize = 32
+)
+
+// encryptionKey is a key in the EncryptionConfiguration, together with the provider using it.
+type encryptionKey struct {
+	provider controlplanev1.EncryptionProvider
+	key      apiserverconfigv1.Key
+}
+
+// reconcileEncryptionAtRest ensures the Secret storing the EncryptionConfiguration for the control plane exists and
+// it is up to date with the KCP spec, and it surfaces the current state in the KCP status.
+//
+// Enabling encryption on an existing control plane and key rotations, triggered by spec.encryptionAtRest.rotateKeysAfter
+// or by a change of the provider, happen in two phases, each one of them rolling out control plane machines:
+// - first a new key is added to the EncryptionConfiguration as a secondary key, so all the API servers can decrypt data with it.
+// - then, once all the machines are up to date, the new key is promoted to primary key and used for encrypting data.
+// Previous keys are preserved in the EncryptionConfiguration so existing data can still be decrypted; once all the machines
+// are up to date again, all the encrypted resources in the workload cluster are rewritten with the primary key, and
+// only after this the oldest keys exceeding maxEncryptionKeys are dropped.
+func (r *KubeadmControlPlaneReconciler) reconcileEncryptionAtRest(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	log := ctrl.LoggerFrom(ctx)
+	kcp := controlPlane.KCP
+
+	if kcp.Spec.EncryptionAtRest == nil {
+		kcp.Status.EncryptionAtRest = nil
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	secretKey := client.ObjectKey{Namespace: kcp.Namespace, Name: internal.EncryptionConfigSecretName(kcp.Name)}
+	if err := r.Client.Get(ctx, secretKey, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get encryption config Secret %s", klog.KRef(secretKey.Namespace, secretKey.Name))
+		}
+		secret = nil
+	}
+
+	var keys []encryptionKey
+	rotatedAt := metav1.Now()
+	pending := ""
+	migrated := ""
+	if secret != nil {
+		var err error
+		keys, err = parseEncryptionKeys(secret.Data[internal.EncryptionConfigSecretKey])
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse EncryptionConfiguration from Secret %s", klog.KObj(secret))
+		}
+		if t, err := time.Parse(time.RFC3339, secret.Annotations[encryptionKeysRotatedAtAnnotation]); err == nil {
+			rotatedAt = metav1.NewTime(t)
+		}
+		pending = secret.Annotations[encryptionKeyPendingAnnotation]
+		migrated = secret.Annotations[encryptionStorageMigratedKeyAnnotation]
+	}
+
+	provider := kcp.Spec.EncryptionAtRest.Provider
+	if provider == "" {
+		provider = controlplanev1.AESCBCEncryptionProvider
+	}
+	rotateKeysAfter := kcp.Spec.EncryptionAtRest.RotateKeysAfter
+
+	resources := kcp.Spec.EncryptionAtRest.Resources
+	if len(resources) == 0 {
+		resources = []string{"secrets"}
+	}
+
+	switch {
+	case len(keys) == 0:
+		key, err := newEncryptionKey(provider)
+		if err != nil {
+			return err
+		}
+		keys = []encryptionKey{key}
+		rotatedAt = metav1.Now()
+		// If there are already control plane machines, the new key is used only for decrypting data until all the API servers
+		// are aware of it, otherwise API servers still using only the identity provider won't be able to read data encrypted with it.
+		// If there are no control plane machines yet, there is no data to be rewritten with the new key.
+		if len(controlPlane.Machines) > 0 {
+			log.Info("Enabling encryption at rest", "key", key.key.Name, "provider", provider)
+			pending = key.key.Name
+		} else {
+			migrated = key.key.Name
+		}
+	case pending != "":
+		// Promote the pending key only when all the machines are using the EncryptionConfiguration including it,
+		// otherwise API servers not yet aware of the new key won't be able to decrypt data encrypted with it.
+		if len(controlPlane.MachinesNeedingRollout()) > 0 {
+			break
+		}
+		log.Info("Promoting new encryption key", "key", pending)
+		keys = promoteEncryptionKey(keys, pending)
+		pending = ""
+	case migrated != keys[0].key.Name && kcp.Status.Initialized && len(controlPlane.MachinesNeedingRollout()) == 0:
+		// Rewrite the encrypted resources only when all the machines are encrypting data with the primary key,
+		// otherwise resources could be written again with a previous key.
+		workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
+		if err != nil {
+			return errors.Wrap(err, "cannot get remote client to workload cluster")
+		}
+		log.Info("Rewriting encrypted resources with the primary encryption key", "key", keys[0].key.Name, "resources", resources)
+		if err := workloadCluster.MigrateEncryptedResources(ctx, resources); err != nil {
+			return errors.Wrap(err, "failed to rewrite encrypted resources")
+		}
+		migrated = keys[0].key.Name
+		// Previous keys are not required anymore to decrypt data, so the oldest ones can be dropped.
+		if len(keys) > maxEncryptionKeys {
+			keys = keys[:maxEncryptionKeys]
+		}
+	case keys[0].provider != provider || (rotateKeysAfter != nil && rotatedAt.Before(rotateKeysAfter) && !rotateKeysAfter.After(time.Now())):
+		key, err := newEncryptionKey(provider)
+		if err != nil {
+			return err
+		}
+		log.Info("Rotating encryption keys", "key", key.key.Name, "provider", provider)
+		keys = append(keys, key)
+		pending = key.key.Name
+		rotatedAt = metav1.Now()
+	}
+
+	config, err := renderEncryptionConfig(resources, keys, pending)
+	if err != nil {
+		return err
+	}
+
+	annotations := map[string]string{
+		encryptionKeysRotatedAtAnnotation: rotatedAt.UTC().Format(time.RFC3339),
+	}
+	if pending != "" {
+		annotations[encryptionKeyPendingAnnotation] = pending
+	}
+	if migrated != "" {
+		annotations[encryptionStorageMigratedKeyAnnotation] = migrated
+	}
+
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        secretKey.Name,
+				Namespace:   secretKey.Namespace,
+				Labels:      map[string]string{clusterv1.ClusterNameLabel: controlPlane.Cluster.Name},
+				Annotations: annotations,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind(kubeadmControlPlaneKind)),
+				},
+			},
+			Type: clusterv1.ClusterSecretType,
+			Data: map[string][]byte{
+				internal.EncryptionConfigSecretKey: config,
+			},
+		}
+		if err := r.Client.Create(ctx, secret); err != nil {
+			return errors.Wrapf(err, "failed to create encryption config Secret %s", klog.KObj(secret))
+		}
+	} else if !bytes.Equal(secret.Data[internal.EncryptionConfigSecretKey], config) ||
+		secret.Annotations[encryptionKeysRotatedAtAnnotation] != annotations[encryptionKeysRotatedAtAnnotation] ||
+		secret.Annotations[encryptionKeyPendingAnnotation] != pending ||
+		secret.Annotations[encryptionStorageMigratedKeyAnnotation] != migrated {
+		patch := client.MergeFrom(secret.DeepCopy())
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		delete(secret.Annotations, encryptionKeyPendingAnnotation)
+		for k, v := range annotations {
+			secret.Annotations[k] = v
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[internal.EncryptionConfigSecretKey] = config
+		if err := r.Client.Patch(ctx, secret, patch); err != nil {
+			return errors.Wrapf(err, "failed to update encryption config Secret %s", klog.KObj(secret))
+		}
+	}
+
+	kcp.Status.EncryptionAtRest = &controlplanev1.EncryptionAtRestStatus{
+		ConfigHash:            fmt.Sprintf("%x", sha256.Sum256(config))[:10],
+		LastKeyRotationTime:   &rotatedAt,
+		KeyRotationInProgress: pending != "",
+		StorageMigratedKey:    migrated,
+	}
+	return nil
+}
+
+// newEncryptionKey generates a new random key for the given provider.
+func newEncryptionKey(provider controlplanev1.EncryptionProvider) (encryptionKey, error) {
+	secret := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(secret); err != nil {
+		return encryptionKey{}, errors.Wrap(err, "failed to generate encryption key")
+	}
+	return encryptionKey{
+		provider: provider,
+		key: apiserverconfigv1.Key{
+			Name:   fmt.Sprintf("key-%d", time.Now().UnixNano()),
+			Secret: base64.StdEncoding.EncodeToString(secret),
+		},
+	}, nil
+}
+
+// promoteEncryptionKey moves the key with the given name first, so it is used for encrypting data.
+// NOTE: Previous keys are preserved, given that data encrypted with them has not been rewritten yet.
+func promoteEncryptionKey(keys []encryptionKey, name string) []encryptionKey {
+	promoted := []encryptionKey{}
+	for _, k := range keys {
+		if k.key.Name == name {
+			promoted = append(promoted, k)
+		}
+	}
+	for _, k := range keys {
+		if k.key.Name != name {
+			promoted = append(promoted, k)
+		}
+	}
+	return promoted
+}
+
+// renderEncryptionConfig renders the EncryptionConfiguration for the given resources and keys; the first key is used
+// for encrypting data, all the keys can be used for decrypting data.
+// NOTE: The identity provider is always appended last, so data written before enabling encryption can still be read,
+// except while encryption is being enabled, i.e. when the only key is pending; in this case the identity provider
+// comes first, so data is not encrypted until the pending key is promoted.
+func renderEncryptionConfig(resources []string, keys []encryptionKey, pending string) ([]byte, error) {
+	identity := apiserverconfigv1.ProviderConfiguration{Identity: &apiserverconfigv1.IdentityConfiguration{}}
+	identityFirst := len(keys) > 0 && keys[0].key.Name == pending
+
+	providers := []apiserverconfigv1.ProviderConfiguration{}
+	if identityFirst {
+		providers = append(providers, identity)
+	}
+	for i := 0; i < len(keys); {
+		// Group consecutive keys of the same provider, preserving the order of the keys.
+		j := i
+		group := []apiserverconfigv1.Key{}
+		for ; j < len(keys) && keys[j].provider == keys[i].provider; j++ {
+			group = append(group, keys[j].key)
+		}
+
+		p := apiserverconfigv1.ProviderConfiguration{}
+		switch keys[i].provider {
+		case controlplanev1.AESGCMEncryptionProvider:
+			p.AESGCM = &apiserverconfigv1.AESConfiguration{Keys: group}
+		case controlplanev1.SecretboxEncryptionProvider:
+			p.Secretbox = &apiserverconfigv1.SecretboxConfiguration{Keys: group}
+		default:
+			p.AESCBC = &apiserverconfigv1.AESConfiguration{Keys: group}
+		}
+		providers = append(providers, p)
+		i = j
+	}
+	if !identityFirst {
+		providers = append(providers, identity)
+	}
+
+	config := &apiserverconfigv1.EncryptionConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiserverconfigv1.SchemeGroupVersion.String(),
+			Kind:       "EncryptionConfiguration",
+		},
+		Resources: []apiserverconfigv1.ResourceConfiguration{
+			{
+				Resources: resources,
+				Providers: providers,
+			},
+		},
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal EncryptionConfiguration")
+	}
+	return data, nil
+}
+
+// parseEncryptionKeys returns the keys in an EncryptionConfiguration, in the same order they are used by the API server.
+func parseEncryptionKeys(data []byte) ([]encryptionKey, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	config := &apiserverconfigv1.EncryptionConfiguration{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	keys := []encryptionKey{}
+	if len(config.Resources) == 0 {
+		return keys, nil
+	}
+	for _, p := range config.Resources[0].Providers {
+		switch {
+		case p.AESCBC != nil:
+			for _, k := range p.AESCBC.Keys {
+				keys = append(keys, encryptionKey{provider: controlplanev1.AESCBCEncryptionProvider, key: k})
+			}
+		case p.AESGCM != nil:
+			for _, k := range p.AESGCM.Keys {
+				keys = append(keys, encryptionKey{provider: controlplanev1.AESGCMEncryptionProvider, key: k})
+			}
+		case p.Secretbox != nil:
+			for _, k := range p.Secretbox.Keys {
+				keys = append(keys, encryptionKey{provider: controlplanev1.SecretboxEncryptionProvider, key: k})
+			}
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
+)
+
+func TestReconcileEncryptionAtRest(t *testing.T) {
+	newControlPlane := func(encryptionAtRest *controlplanev1.EncryptionAtRest) *internal.ControlPlane {
+		return &internal.ControlPlane{
+			KCP: &controlplanev1.KubeadmControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kcp",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					EncryptionAtRest: encryptionAtRest,
+				},
+			},
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster",
+					Namespace: metav1.NamespaceDefault,
+				},
+			},
+			Machines: collections.New(),
+		}
+	}
+	getKeys := func(g *WithT, r *KubeadmControlPlaneReconciler) (*corev1.Secret, []encryptionKey) {
+		secret := &corev1.Secret{}
+		g.Expect(r.Client.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: internal.EncryptionConfigSecretName("kcp")}, secret)).To(Succeed())
+		keys, err := parseEncryptionKeys(secret.Data[internal.EncryptionConfigSecretKey])
+		g.Expect(err).ToNot(HaveOccurred())
+		return secret, keys
+	}
+
+	t.Run("does nothing if encryption at rest is not enabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane := newControlPlane(nil)
+		r := &KubeadmControlPlaneReconciler{Client: fake.NewClientBuilder().Build()}
+
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest).To(BeNil())
+	})
+
+	t.Run("generates the EncryptionConfiguration and rotates keys in two phases", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane := newControlPlane(&controlplanev1.EncryptionAtRest{
+			Provider:  controlplanev1.AESGCMEncryptionProvider,
+			Resources: []string{"secrets", "configmaps"},
+		})
+		r := &KubeadmControlPlaneReconciler{Client: fake.NewClientBuilder().Build()}
+
+		// Initial key generation.
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		secret, keys := getKeys(g, r)
+		g.Expect(secret.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "cluster"))
+		g.Expect(secret.Data[internal.EncryptionConfigSecretKey]).To(ContainSubstring("configmaps"))
+		g.Expect(keys).To(HaveLen(1))
+		g.Expect(keys[0].provider).To(Equal(controlplanev1.AESGCMEncryptionProvider))
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest).ToNot(BeNil())
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest.KeyRotationInProgress).To(BeFalse())
+		initialHash := controlPlane.KCP.Status.EncryptionAtRest.ConfigHash
+		g.Expect(initialHash).ToNot(BeEmpty())
+
+		// Reconciling again without changes is a no-op.
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest.ConfigHash).To(Equal(initialHash))
+
+		// Requesting a key rotation adds the new key as a secondary key.
+		rotateKeysAfter := metav1.NewTime(time.Now().Add(time.Hour))
+		controlPlane.KCP.Spec.EncryptionAtRest.RotateKeysAfter = &rotateKeysAfter
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest.ConfigHash).To(Equal(initialHash))
+
+		rotateKeysAfter = metav1.NewTime(time.Now().Add(time.Minute * -1))
+		secret, _ = getKeys(g, r)
+		secret.Annotations[encryptionKeysRotatedAtAnnotation] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		g.Expect(r.Client.Update(ctx, secret)).To(Succeed())
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		secret, rotatedKeys := getKeys(g, r)
+		g.Expect(rotatedKeys).To(HaveLen(2))
+		g.Expect(rotatedKeys[0]).To(Equal(keys[0]))
+		g.Expect(secret.Annotations).To(HaveKeyWithValue(encryptionKeyPendingAnnotation, rotatedKeys[1].key.Name))
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest.KeyRotationInProgress).To(BeTrue())
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest.ConfigHash).ToNot(Equal(initialHash))
+
+		// Once no machines need rollout, the new key is promoted to primary key.
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		secret, promotedKeys := getKeys(g, r)
+		g.Expect(promotedKeys).To(HaveLen(2))
+		g.Expect(promotedKeys[0]).To(Equal(rotatedKeys[1]))
+		g.Expect(promotedKeys[1]).To(Equal(rotatedKeys[0]))
+		g.Expect(secret.Annotations).ToNot(HaveKey(encryptionKeyPendingAnnotation))
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest.KeyRotationInProgress).To(BeFalse())
+
+		// A further reconcile does not rotate keys again.
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		_, keys = getKeys(g, r)
+		g.Expect(keys).To(Equal(promotedKeys))
+	})
+
+	t.Run("enables encryption on an existing control plane in two phases", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane := newControlPlane(&controlplanev1.EncryptionAtRest{})
+		controlPlane.Machines = collections.FromMachines(&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m1"}})
+		r := &KubeadmControlPlaneReconciler{Client: fake.NewClientBuilder().Build()}
+
+		// The new key is added after the identity provider, so it is used only for decrypting data.
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		secret, keys := getKeys(g, r)
+		g.Expect(keys).To(HaveLen(1))
+		g.Expect(keys[0].provider).To(Equal(controlplanev1.AESCBCEncryptionProvider))
+		g.Expect(secret.Annotations).To(HaveKeyWithValue(encryptionKeyPendingAnnotation, keys[0].key.Name))
+		config := string(secret.Data[internal.EncryptionConfigSecretKey])
+		g.Expect(strings.Index(config, "identity")).To(BeNumerically("<", strings.Index(config, "aescbc")))
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest.KeyRotationInProgress).To(BeTrue())
+
+		// Once no machines need rollout, the new key is promoted to primary key.
+		controlPlane.Machines = collections.New()
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		secret, promotedKeys := getKeys(g, r)
+		g.Expect(promotedKeys).To(Equal(keys))
+		g.Expect(secret.Annotations).ToNot(HaveKey(encryptionKeyPendingAnnotation))
+		config = string(secret.Data[internal.EncryptionConfigSecretKey])
+		g.Expect(strings.Index(config, "aescbc")).To(BeNumerically("<", strings.Index(config, "identity")))
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest.KeyRotationInProgress).To(BeFalse())
+	})
+
+	t.Run("preserves previous keys until the encrypted resources are rewritten with the primary key", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane := newControlPlane(&controlplanev1.EncryptionAtRest{})
+		workloadSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: metav1.NamespaceDefault}}
+		mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+		mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+		workloadClient := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(workloadSecret).Build()
+		r := &KubeadmControlPlaneReconciler{
+			Client: fake.NewClientBuilder().Build(),
+			managementCluster: &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{Workload: &internal.Workload{Client: workloadClient}},
+			},
+		}
+
+		// Without control plane machines there is no data to rewrite with the initial key.
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		_, keys := getKeys(g, r)
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest.StorageMigratedKey).To(Equal(keys[0].key.Name))
+
+		// Rotate keys more than maxEncryptionKeys times while the workload cluster is not reachable yet.
+		for i := 0; i < maxEncryptionKeys+1; i++ {
+			secret, _ := getKeys(g, r)
+			secret.Annotations[encryptionKeysRotatedAtAnnotation] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+			g.Expect(r.Client.Update(ctx, secret)).To(Succeed())
+			rotateKeysAfter := metav1.NewTime(time.Now().Add(-time.Minute))
+			controlPlane.KCP.Spec.EncryptionAtRest.RotateKeysAfter = &rotateKeysAfter
+
+			// Add the new key, then promote it.
+			g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+			g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+			g.Expect(controlPlane.KCP.Status.EncryptionAtRest.KeyRotationInProgress).To(BeFalse())
+		}
+
+		// All the previous keys are preserved, given that the encrypted resources have not been rewritten yet.
+		_, rotatedKeys := getKeys(g, r)
+		g.Expect(rotatedKeys).To(HaveLen(maxEncryptionKeys + 2))
+		g.Expect(rotatedKeys[len(rotatedKeys)-1]).To(Equal(keys[0]))
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest.StorageMigratedKey).To(Equal(keys[0].key.Name))
+
+		// Once the workload cluster is reachable, the encrypted resources are rewritten with the primary key,
+		// and then the oldest keys are dropped.
+		controlPlane.KCP.Status.Initialized = true
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		_, migratedKeys := getKeys(g, r)
+		g.Expect(migratedKeys).To(Equal(rotatedKeys[:maxEncryptionKeys]))
+		g.Expect(controlPlane.KCP.Status.EncryptionAtRest.StorageMigratedKey).To(Equal(rotatedKeys[0].key.Name))
+
+		rewrittenSecret := &corev1.Secret{}
+		g.Expect(workloadClient.Get(ctx, client.ObjectKeyFromObject(workloadSecret), rewrittenSecret)).To(Succeed())
+		g.Expect(rewrittenSecret.ResourceVersion).ToNot(Equal(workloadSecret.ResourceVersion))
+
+		// A further reconcile does not rewrite the encrypted resources again.
+		g.Expect(r.reconcileEncryptionAtRest(ctx, controlPlane)).To(Succeed())
+		g.Expect(workloadClient.Get(ctx, client.ObjectKeyFromObject(workloadSecret), workloadSecret)).To(Succeed())
+		g.Expect(workloadSecret.ResourceVersion).To(Equal(rewrittenSecret.ResourceVersion))
+	})
+}
+
+func TestRenderEncryptionConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	keys := []encryptionKey{
+		{provider: controlplanev1.SecretboxEncryptionProvider},
+		{provider: controlplanev1.AESCBCEncryptionProvider},
+		{provider: controlplanev1.AESCBCEncryptionProvider},
+	}
+	for i := range keys {
+		k, err := newEncryptionKey(keys[i].provider)
+		g.Expect(err).ToNot(HaveOccurred())
+		k.key.Name = fmt.Sprintf("key-%d", i)
+		keys[i] = k
+	}
+
+	data, err := renderEncryptionConfig([]string{"secrets"}, keys, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(ContainSubstring("identity: {}"))
+
+	parsed, err := parseEncryptionKeys(data)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(parsed).To(Equal(keys))
+
+	g.Expect(promoteEncryptionKey(keys, keys[2].key.Name)).To(Equal([]encryptionKey{keys[2], keys[0], keys[1]}))
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+const (
+	// etcdCertificatesRotationRequeueAfter is the interval used for checking the progress of the etcd certificates
+	// rotation on a machine.
+	etcdCertificatesRotationRequeueAfter = 10 * time.Second
+
+	// etcdCertificatesRotationFailedRequeueAfter is the interval used for checking if a failed etcd certificates
+	// rotation Pod has been deleted by the user.
+	etcdCertificatesRotationFailedRequeueAfter = 1 * time.Minute
+)
+
+// reconcileEtcdCertificatesRotation rotates the etcd certificates in place on the control plane machines when
+// requested with the RotateEtcdCertificatesAnnotation, one machine at a time.
+//
+// The rotation on a machine is performed by a privileged Pod running on the corresponding node, which renews
+// the certificates with kubeadm and restarts etcd and the API server; the machine is considered done once the Pod
+// has completed and the etcd member is healthy again, and it is then marked with the EtcdCertificatesRotatedAnnotation.
+// NOTE: This is a no-op if the EtcdCertificatesRotation feature gate is disabled or if etcd is not managed by KCP.
+func (r *KubeadmControlPlaneReconciler) reconcileEtcdCertificatesRotation(ctx context.Context, controlPlane *internal.ControlPlane, workloadCluster internal.WorkloadCluster) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	kcp := controlPlane.KCP
+
+	if !feature.Gates.Enabled(feature.EtcdCertificatesRotation) || !controlPlane.IsEtcdManaged() {
+		return ctrl.Result{}, nil
+	}
+	token, ok := kcp.Annotations[controlplanev1.RotateEtcdCertificatesAnnotation]
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+
+	pendingMachines := etcdCertificatesRotationPending(controlPlane.Machines, token)
+	if len(pendingMachines) == 0 {
+		conditions.MarkTrue(kcp, controlplanev1.EtcdCertificatesRotatedCondition)
+		return ctrl.Result{}, nil
+	}
+
+	// Rotating the certificates restarts the etcd member, so rotation proceeds only if the etcd cluster is healthy.
+	if !conditions.IsTrue(kcp, controlplanev1.EtcdClusterHealthyCondition) {
+		log.Info("Waiting for the etcd cluster to be healthy before rotating etcd certificates")
+		return ctrl.Result{RequeueAfter: etcdCertificatesRotationRequeueAfter}, nil
+	}
+
+	machine := pendingMachines.Oldest()
+	log = log.WithValues("Machine", klog.KObj(machine))
+	if machine.Status.NodeRef == nil {
+		log.Info("Waiting for the Machine to have a Node before rotating etcd certificates")
+		return ctrl.Result{RequeueAfter: etcdCertificatesRotationRequeueAfter}, nil
+	}
+	nodeName := machine.Status.NodeRef.Name
+	rotated := len(controlPlane.Machines) - len(pendingMachines)
+
+	pod, err := workloadCluster.GetEtcdCertificatesRotationPod(ctx, nodeName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case pod == nil:
+		log.Info("Rotating etcd certificates", "Node", nodeName)
+		if err := workloadCluster.CreateEtcdCertificatesRotationPod(ctx, nodeName, r.hostCommandImage()); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.recorder.Eventf(kcp, corev1.EventTypeNormal, "EtcdCertificatesRotationStarted", "Rotating etcd certificates on Machine %s", machine.Name)
+		conditions.MarkFalse(kcp, controlplanev1.EtcdCertificatesRotatedCondition, controlplanev1.EtcdCertificatesRotationInProgressReason, clusterv1.ConditionSeverityInfo,
+			"Rotating etcd certificates on Machine %s (%d of %d)", machine.Name, rotated+1, len(controlPlane.Machines))
+		return ctrl.Result{RequeueAfter: etcdCertificatesRotationRequeueAfter}, nil
+	case pod.Status.Phase == corev1.PodFailed:
+		log.Info("Failed to rotate etcd certificates", "Pod", klog.KObj(pod))
+		conditions.MarkFalse(kcp, controlplanev1.EtcdCertificatesRotatedCondition, controlplanev1.EtcdCertificatesRotationFailedReason, clusterv1.ConditionSeverityError,
+			"Failed to rotate etcd certificates on Machine %s, check the logs of Pod %s and delete it to retry", machine.Name, klog.KObj(pod))
+		return ctrl.Result{RequeueAfter: etcdCertificatesRotationFailedRequeueAfter}, nil
+	case pod.Status.Phase != corev1.PodSucceeded:
+		conditions.MarkFalse(kcp, controlplanev1.EtcdCertificatesRotatedCondition, controlplanev1.EtcdCertificatesRotationInProgressReason, clusterv1.ConditionSeverityInfo,
+			"Rotating etcd certificates on Machine %s (%d of %d)", machine.Name, rotated+1, len(controlPlane.Machines))
+		return ctrl.Result{RequeueAfter: etcdCertificatesRotationRequeueAfter}, nil
+	}
+
+	// Wait for the etcd member to be back healthy after the restart before moving to the next machine.
+	if !conditions.IsTrue(machine, controlplanev1.MachineEtcdMemberHealthyCondition) {
+		log.Info("Waiting for the etcd member to be healthy after rotating etcd certificates")
+		return ctrl.Result{RequeueAfter: etcdCertificatesRotationRequeueAfter}, nil
+	}
+
+	if err := workloadCluster.DeleteEtcdCertificatesRotationPod(ctx, nodeName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	patchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get PatchHelper for Machine %s", machine.Name)
+	}
+	if machine.Annotations == nil {
+		machine.Annotations = map[string]string{}
+	}
+	machine.Annotations[controlplanev1.EtcdCertificatesRotatedAnnotation] = token
+	if err := patchHelper.Patch(ctx, machine); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to patch Machine %s", machine.Name)
+	}
+
+	log.Info("Rotated etcd certificates", "Node", nodeName)
+	r.recorder.Eventf(kcp, corev1.EventTypeNormal, "EtcdCertificatesRotated", "Rotated etcd certificates on Machine %s", machine.Name)
+	if rotated+1 == len(controlPlane.Machines) {
+		conditions.MarkTrue(kcp, controlplanev1.EtcdCertificatesRotatedCondition)
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// etcdCertificatesRotationPending returns the machines still waiting for the etcd certificates to be rotated.
+func etcdCertificatesRotationPending(machines collections.Machines, token string) collections.Machines {
+	return machines.Filter(func(m *clusterv1.Machine) bool {
+		return m != nil && m.Annotations[controlplanev1.EtcdCertificatesRotatedAnnotation] != token
+	})
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileEtcdCertificatesRotation(t *testing.T) {
+	newMachine := func(name string, creationTimestamp time.Time) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         metav1.NamespaceDefault,
+				CreationTimestamp: metav1.NewTime(creationTimestamp),
+			},
+			Status: clusterv1.MachineStatus{
+				NodeRef: &corev1.ObjectReference{Name: name},
+			},
+		}
+		conditions.MarkTrue(m, controlplanev1.MachineEtcdMemberHealthyCondition)
+		return m
+	}
+	newControlPlane := func(token string, machines ...*clusterv1.Machine) *internal.ControlPlane {
+		kcp := &controlplanev1.KubeadmControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kcp",
+				Namespace: metav1.NamespaceDefault,
+			},
+		}
+		if token != "" {
+			kcp.Annotations = map[string]string{controlplanev1.RotateEtcdCertificatesAnnotation: token}
+		}
+		conditions.MarkTrue(kcp, controlplanev1.EtcdClusterHealthyCondition)
+		return &internal.ControlPlane{
+			KCP:      kcp,
+			Machines: collections.FromMachines(machines...),
+		}
+	}
+	setPodPhase := func(g *WithT, c client.Client, nodeName string, phase corev1.PodPhase) {
+		pod := &corev1.Pod{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: internal.EtcdCertificatesRotationPodName(nodeName)}, pod)).To(Succeed())
+		pod.Status.Phase = phase
+		g.Expect(c.Update(ctx, pod)).To(Succeed())
+	}
+
+	t.Run("does nothing if the feature gate is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane := newControlPlane("token", newMachine("m1", time.Now()))
+		r := &KubeadmControlPlaneReconciler{Client: fake.NewClientBuilder().Build(), recorder: record.NewFakeRecorder(32)}
+		workloadCluster := fakeWorkloadCluster{Workload: &internal.Workload{Client: fake.NewClientBuilder().Build()}}
+
+		result, err := r.reconcileEtcdCertificatesRotation(ctx, controlPlane, workloadCluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		g.Expect(conditions.Has(controlPlane.KCP, controlplanev1.EtcdCertificatesRotatedCondition)).To(BeFalse())
+	})
+
+	t.Run("does nothing if a rotation is not requested", func(t *testing.T) {
+		defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.EtcdCertificatesRotation, true)()
+		g := NewWithT(t)
+
+		controlPlane := newControlPlane("", newMachine("m1", time.Now()))
+		r := &KubeadmControlPlaneReconciler{Client: fake.NewClientBuilder().Build(), recorder: record.NewFakeRecorder(32)}
+		workloadCluster := fakeWorkloadCluster{Workload: &internal.Workload{Client: fake.NewClientBuilder().Build()}}
+
+		result, err := r.reconcileEtcdCertificatesRotation(ctx, controlPlane, workloadCluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		g.Expect(conditions.Has(controlPlane.KCP, controlplanev1.EtcdCertificatesRotatedCondition)).To(BeFalse())
+	})
+
+	t.Run("rotates etcd certificates one machine at a time", func(t *testing.T) {
+		defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.EtcdCertificatesRotation, true)()
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", time.Now().Add(-time.Hour))
+		m2 := newMachine("m2", time.Now())
+		controlPlane := newControlPlane("token", m1, m2)
+		r := &KubeadmControlPlaneReconciler{
+			Client:           fake.NewClientBuilder().WithObjects(m1.DeepCopy(), m2.DeepCopy()).Build(),
+			recorder:         record.NewFakeRecorder(32),
+			HostCommandImage: "busybox",
+		}
+		workloadClient := fake.NewClientBuilder().Build()
+		workloadCluster := fakeWorkloadCluster{Workload: &internal.Workload{Client: workloadClient}}
+
+		// The rotation starts on the oldest machine.
+		result, err := r.reconcileEtcdCertificatesRotation(ctx, controlPlane, workloadCluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeFalse())
+		g.Expect(conditions.GetReason(controlPlane.KCP, controlplanev1.EtcdCertificatesRotatedCondition)).To(Equal(controlplanev1.EtcdCertificatesRotationInProgressReason))
+		g.Expect(conditions.GetMessage(controlPlane.KCP, controlplanev1.EtcdCertificatesRotatedCondition)).To(ContainSubstring("m1 (1 of 2)"))
+		pod, err := workloadCluster.GetEtcdCertificatesRotationPod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pod).ToNot(BeNil())
+		g.Expect(pod.Spec.Containers[0].Image).To(Equal("busybox"))
+
+		// A failed rotation is surfaced and blocks the rotation until the Pod is deleted.
+		setPodPhase(g, workloadClient, "m1", corev1.PodFailed)
+		_, err = r.reconcileEtcdCertificatesRotation(ctx, controlPlane, workloadCluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conditions.GetReason(controlPlane.KCP, controlplanev1.EtcdCertificatesRotatedCondition)).To(Equal(controlplanev1.EtcdCertificatesRotationFailedReason))
+		g.Expect(*conditions.GetSeverity(controlPlane.KCP, controlplanev1.EtcdCertificatesRotatedCondition)).To(Equal(clusterv1.ConditionSeverityError))
+
+		// Once the Pod succeeds, the machine is marked as rotated and the Pod is deleted.
+		setPodPhase(g, workloadClient, "m1", corev1.PodSucceeded)
+		result, err = r.reconcileEtcdCertificatesRotation(ctx, controlPlane, workloadCluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeFalse())
+		g.Expect(controlPlane.Machines["m1"].Annotations).To(HaveKeyWithValue(controlplanev1.EtcdCertificatesRotatedAnnotation, "token"))
+		pod, err = workloadCluster.GetEtcdCertificatesRotationPod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pod).To(BeNil())
+
+		machine := &clusterv1.Machine{}
+		g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(m1), machine)).To(Succeed())
+		g.Expect(machine.Annotations).To(HaveKeyWithValue(controlplanev1.EtcdCertificatesRotatedAnnotation, "token"))
+
+		// Then the rotation moves to the next machine.
+		_, err = r.reconcileEtcdCertificatesRotation(ctx, controlPlane, workloadCluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conditions.GetMessage(controlPlane.KCP, controlplanev1.EtcdCertificatesRotatedCondition)).To(ContainSubstring("m2 (2 of 2)"))
+
+		setPodPhase(g, workloadClient, "m2", corev1.PodSucceeded)
+		result, err = r.reconcileEtcdCertificatesRotation(ctx, controlPlane, workloadCluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		g.Expect(conditions.IsTrue(controlPlane.KCP, controlplanev1.EtcdCertificatesRotatedCondition)).To(BeTrue())
+	})
+
+	t.Run("waits for the etcd cluster to be healthy", func(t *testing.T) {
+		defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.EtcdCertificatesRotation, true)()
+		g := NewWithT(t)
+
+		controlPlane := newControlPlane("token", newMachine("m1", time.Now()))
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityError, "")
+		r := &KubeadmControlPlaneReconciler{Client: fake.NewClientBuilder().Build(), recorder: record.NewFakeRecorder(32)}
+		workloadCluster := fakeWorkloadCluster{Workload: &internal.Workload{Client: fake.NewClientBuilder().Build()}}
+
+		result, err := r.reconcileEtcdCertificatesRotation(ctx, controlPlane, workloadCluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(etcdCertificatesRotationRequeueAfter))
+		pod, err := workloadCluster.GetEtcdCertificatesRotationPod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pod).To(BeNil())
+	})
+}
@@ -37,6 +37,7 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/external"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/util/hash"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/certs"
@@ -131,25 +132,27 @@ func (r *KubeadmControlPlaneReconciler) adoptKubeconfigSecret(ctx context.Contex
 	return nil
 }
 
-func (r *KubeadmControlPlaneReconciler) reconcileExternalReference(ctx context.Context, cluster *clusterv1.Cluster, ref *corev1.ObjectReference) error {
+// reconcileExternalReference ensures the Cluster owns the referenced template and returns it; nil is returned if the
+// reference is not a template.
+func (r *KubeadmControlPlaneReconciler) reconcileExternalReference(ctx context.Context, cluster *clusterv1.Cluster, ref *corev1.ObjectReference) (*unstructured.Unstructured, error) {
 	if !strings.HasSuffix(ref.Kind, clusterv1.TemplateSuffix) {
-		return nil
+		return nil, nil
 	}
 
 	if err := utilconversion.UpdateReferenceAPIContract(ctx, r.Client, ref); err != nil {
-		return err
+		return nil, err
 	}
 
 	obj, err := external.Get(ctx, r.Client, ref, cluster.Namespace)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Note: We intentionally do not handle checking for the paused label on an external template reference
 
 	patchHelper, err := patch.NewHelper(obj, r.Client)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	obj.SetOwnerReferences(util.EnsureOwnerRef(obj.GetOwnerReferences(), metav1.OwnerReference{
@@ -159,10 +162,32 @@ func (r *KubeadmControlPlaneReconciler) reconcileExternalReference(ctx context.C
 		UID:        cluster.UID,
 	}))
 
-	return patchHelper.Patch(ctx, obj)
+	if err := patchHelper.Patch(ctx, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
 }
 
-func (r *KubeadmControlPlaneReconciler) cloneConfigsAndGenerateMachine(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane, bootstrapSpec *bootstrapv1.KubeadmConfigSpec, failureDomain *string) error {
+// setInfrastructureTemplateChecksum sets the InfrastructureTemplateChecksumAnnotation on the KubeadmControlPlane with the
+// checksum of the content of the referenced infrastructure machine template; the annotation is removed if the reference
+// is not a template.
+func setInfrastructureTemplateChecksum(kcp *controlplanev1.KubeadmControlPlane, infraTemplate *unstructured.Unstructured) error {
+	if infraTemplate == nil {
+		delete(kcp.Annotations, controlplanev1.InfrastructureTemplateChecksumAnnotation)
+		return nil
+	}
+	checksum, err := hash.ComputeTemplatesChecksum(infraTemplate)
+	if err != nil {
+		return errors.Wrap(err, "failed to compute infrastructure template checksum")
+	}
+	if kcp.Annotations == nil {
+		kcp.Annotations = map[string]string{}
+	}
+	kcp.Annotations[controlplanev1.InfrastructureTemplateChecksumAnnotation] = checksum
+	return nil
+}
+
+func (r *KubeadmControlPlaneReconciler) cloneConfigsAndGenerateMachine(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane, bootstrapSpec *bootstrapv1.KubeadmConfigSpec, bootstrapAnnotations map[string]string, failureDomain *string) error {
 	var errs []error
 
 	// Since the cloned resource should eventually have a controller ref for the Machine, we create an
@@ -192,7 +217,7 @@ func (r *KubeadmControlPlaneReconciler) cloneConfigsAndGenerateMachine(ctx conte
 	}
 
 	// Clone the bootstrap configuration
-	bootstrapRef, err := r.generateKubeadmConfig(ctx, kcp, cluster, bootstrapSpec)
+	bootstrapRef, err := r.generateKubeadmConfig(ctx, kcp, cluster, bootstrapSpec, bootstrapAnnotations)
 	if err != nil {
 		conditions.MarkFalse(kcp, controlplanev1.MachinesCreatedCondition, controlplanev1.BootstrapTemplateCloningFailedReason,
 			clusterv1.ConditionSeverityError, err.Error())
@@ -241,7 +266,7 @@ func (r *KubeadmControlPlaneReconciler) cleanupFromGeneration(ctx context.Contex
 	return kerrors.NewAggregate(errs)
 }
 
-func (r *KubeadmControlPlaneReconciler) generateKubeadmConfig(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, cluster *clusterv1.Cluster, spec *bootstrapv1.KubeadmConfigSpec) (*corev1.ObjectReference, error) {
+func (r *KubeadmControlPlaneReconciler) generateKubeadmConfig(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, cluster *clusterv1.Cluster, spec *bootstrapv1.KubeadmConfigSpec, bootstrapAnnotations map[string]string) (*corev1.ObjectReference, error) {
 	// Create an owner reference without a controller reference because the owning controller is the machine controller
 	owner := metav1.OwnerReference{
 		APIVersion: controlplanev1.GroupVersion.String(),
@@ -250,12 +275,26 @@ func (r *KubeadmControlPlaneReconciler) generateKubeadmConfig(ctx context.Contex
 		UID:        kcp.UID,
 	}
 
+	// Record the checksum of the KCP inputs rendered into the KubeadmConfig, so the KubeadmConfig keeps matching as long as they don't change.
+	checksum, err := internal.KubeadmConfigSpecChecksum(kcp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute KubeadmConfigSpec checksum")
+	}
+	annotations := map[string]string{}
+	for k, v := range kcp.Spec.MachineTemplate.ObjectMeta.Annotations {
+		annotations[k] = v
+	}
+	for k, v := range bootstrapAnnotations {
+		annotations[k] = v
+	}
+	annotations[controlplanev1.KubeadmConfigSpecChecksumAnnotation] = checksum
+
 	bootstrapConfig := &bootstrapv1.KubeadmConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            names.SimpleNameGenerator.GenerateName(kcp.Name + "-"),
 			Namespace:       kcp.Namespace,
 			Labels:          internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name),
-			Annotations:     kcp.Spec.MachineTemplate.ObjectMeta.Annotations,
+			Annotations:     annotations,
 			OwnerReferences: []metav1.OwnerReference{owner},
 		},
 		Spec: *spec,
@@ -361,6 +400,11 @@ func (r *KubeadmControlPlaneReconciler) computeDesiredMachine(kcp *controlplanev
 		if remediationData, ok := kcp.Annotations[controlplanev1.RemediationInProgressAnnotation]; ok {
 			annotations[controlplanev1.RemediationForAnnotation] = remediationData
 		}
+		// Machines created after an etcd certificates rotation has been requested get fresh certificates,
+		// so there is no need to rotate them.
+		if token, ok := kcp.Annotations[controlplanev1.RotateEtcdCertificatesAnnotation]; ok {
+			annotations[controlplanev1.EtcdCertificatesRotatedAnnotation] = token
+		}
 	} else {
 		// Updating an existing machine
 		machineName = existingMachine.Name
@@ -379,6 +423,11 @@ func (r *KubeadmControlPlaneReconciler) computeDesiredMachine(kcp *controlplanev
 		if remediationData, ok := existingMachine.Annotations[controlplanev1.RemediationForAnnotation]; ok {
 			annotations[controlplanev1.RemediationForAnnotation] = remediationData
 		}
+
+		// If the machine already has the etcd certificates rotated annotation then preserve it.
+		if token, ok := existingMachine.Annotations[controlplanev1.EtcdCertificatesRotatedAnnotation]; ok {
+			annotations[controlplanev1.EtcdCertificatesRotatedAnnotation] = token
+		}
 	}
 
 	// Construct the basic Machine.
@@ -425,6 +474,10 @@ func (r *KubeadmControlPlaneReconciler) computeDesiredMachine(kcp *controlplanev
 	for k, v := range annotations {
 		desiredMachine.Annotations[k] = v
 	}
+	// Add the pre-terminate hook used to clean up the host when the machine is deleted, if a cleanup is configured.
+	if kcp.Spec.MachineTemplate.HostCleanup != nil {
+		desiredMachine.Annotations[controlplanev1.HostCleanupPreTerminateHookAnnotation] = ""
+	}
 
 	// Set other in-place mutable fields
 	desiredMachine.Spec.NodeDrainTimeout = kcp.Spec.MachineTemplate.NodeDrainTimeout
@@ -433,3 +486,17 @@ func (r *KubeadmControlPlaneReconciler) computeDesiredMachine(kcp *controlplanev
 
 	return desiredMachine, nil
 }
+
+// isRolloutPaused returns true if the rollout of machines with an outdated spec is paused with the RolloutPausedAnnotation.
+func isRolloutPaused(kcp *controlplanev1.KubeadmControlPlane) bool {
+	_, ok := kcp.GetAnnotations()[controlplanev1.RolloutPausedAnnotation]
+	return ok
+}
+
+// rolloutPauseReasonMessage returns the reason why the rollout is paused, if any, formatted to be appended to a message.
+func rolloutPauseReasonMessage(kcp *controlplanev1.KubeadmControlPlane) string {
+	if reason := kcp.GetAnnotations()[clusterv1.RolloutPauseReasonAnnotation]; reason != "" {
+		return ": " + reason
+	}
+	return ""
+}
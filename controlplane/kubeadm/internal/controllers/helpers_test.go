@@ -34,6 +34,7 @@ import (
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/secret"
@@ -341,7 +342,7 @@ func TestCloneConfigsAndGenerateMachine(t *testing.T) {
 	bootstrapSpec := &bootstrapv1.KubeadmConfigSpec{
 		JoinConfiguration: &bootstrapv1.JoinConfiguration{},
 	}
-	g.Expect(r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, nil)).To(Succeed())
+	g.Expect(r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, nil, nil)).To(Succeed())
 
 	machineList := &clusterv1.MachineList{}
 	g.Expect(env.GetAPIReader().List(ctx, machineList, client.InNamespace(cluster.Namespace))).To(Succeed())
@@ -356,6 +357,7 @@ func TestCloneConfigsAndGenerateMachine(t *testing.T) {
 		g.Expect(err).NotTo(HaveOccurred())
 		g.Expect(infraObj.GetAnnotations()).To(HaveKeyWithValue(clusterv1.TemplateClonedFromNameAnnotation, genericInfrastructureMachineTemplate.GetName()))
 		g.Expect(infraObj.GetAnnotations()).To(HaveKeyWithValue(clusterv1.TemplateClonedFromGroupKindAnnotation, genericInfrastructureMachineTemplate.GroupVersionKind().GroupKind().String()))
+		g.Expect(infraObj.GetAnnotations()).To(HaveKey(clusterv1.TemplateClonedFromChecksumAnnotation))
 
 		g.Expect(m.Spec.InfrastructureRef.Namespace).To(Equal(cluster.Namespace))
 		g.Expect(m.Spec.InfrastructureRef.Name).To(HavePrefix(genericInfrastructureMachineTemplate.GetName()))
@@ -428,7 +430,7 @@ func TestCloneConfigsAndGenerateMachineFail(t *testing.T) {
 
 	// Try to break Infra Cloning
 	kcp.Spec.MachineTemplate.InfrastructureRef.Name = "something_invalid"
-	g.Expect(r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, nil)).To(HaveOccurred())
+	g.Expect(r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, nil, nil)).To(HaveOccurred())
 	g.Expect(&kcp.GetConditions()[0]).Should(conditions.HaveSameStateOf(&clusterv1.Condition{
 		Type:     controlplanev1.MachinesCreatedCondition,
 		Status:   corev1.ConditionFalse,
@@ -545,6 +547,20 @@ func TestKubeadmControlPlaneReconciler_computeDesiredMachine(t *testing.T) {
 		g.Expect(kcp.Spec.MachineTemplate.ObjectMeta.Annotations).To(Equal(kcpMachineTemplateObjectMetaCopy.Annotations))
 	})
 
+	t.Run("should add the host cleanup hook when a host cleanup is configured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		kcpWithHostCleanup := kcp.DeepCopy()
+		kcpWithHostCleanup.Spec.MachineTemplate.HostCleanup = &controlplanev1.HostCleanup{}
+		createdMachine, err := (&KubeadmControlPlaneReconciler{}).computeDesiredMachine(
+			kcpWithHostCleanup, cluster,
+			infraRef, bootstrapRef,
+			nil, nil,
+		)
+		g.Expect(err).To(BeNil())
+		g.Expect(createdMachine.Annotations).To(HaveKey(controlplanev1.HostCleanupPreTerminateHookAnnotation))
+	})
+
 	t.Run("should return the correct Machine object when updating an existing Machine", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -659,7 +675,7 @@ func TestKubeadmControlPlaneReconciler_generateKubeadmConfig(t *testing.T) {
 		recorder: record.NewFakeRecorder(32),
 	}
 
-	got, err := r.generateKubeadmConfig(ctx, kcp, cluster, spec.DeepCopy())
+	got, err := r.generateKubeadmConfig(ctx, kcp, cluster, spec.DeepCopy(), nil)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(got).NotTo(BeNil())
 	g.Expect(got.Name).To(HavePrefix(kcp.Name))
@@ -673,6 +689,9 @@ func TestKubeadmControlPlaneReconciler_generateKubeadmConfig(t *testing.T) {
 	g.Expect(bootstrapConfig.OwnerReferences).To(HaveLen(1))
 	g.Expect(bootstrapConfig.OwnerReferences).To(ContainElement(expectedOwner))
 	g.Expect(bootstrapConfig.Spec).To(Equal(spec))
+	checksum, err := internal.KubeadmConfigSpecChecksum(kcp)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(bootstrapConfig.Annotations).To(HaveKeyWithValue(controlplanev1.KubeadmConfigSpecChecksumAnnotation, checksum))
 }
 
 func TestKubeadmControlPlaneReconciler_adoptKubeconfigSecret(t *testing.T) {
@@ -772,3 +791,45 @@ func TestKubeadmControlPlaneReconciler_adoptKubeconfigSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestIsRolloutPaused(t *testing.T) {
+	g := NewWithT(t)
+
+	kcp := &controlplanev1.KubeadmControlPlane{}
+	g.Expect(isRolloutPaused(kcp)).To(BeFalse())
+	g.Expect(rolloutPauseReasonMessage(kcp)).To(BeEmpty())
+
+	kcp.Annotations = map[string]string{controlplanev1.RolloutPausedAnnotation: ""}
+	g.Expect(isRolloutPaused(kcp)).To(BeTrue())
+	g.Expect(rolloutPauseReasonMessage(kcp)).To(BeEmpty())
+
+	kcp.Annotations[clusterv1.RolloutPauseReasonAnnotation] = "change freeze"
+	g.Expect(rolloutPauseReasonMessage(kcp)).To(Equal(": change freeze"))
+}
+
+func TestSetInfrastructureTemplateChecksum(t *testing.T) {
+	g := NewWithT(t)
+
+	newTemplate := func(name string) *unstructured.Unstructured {
+		template := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{"foo": "bar"}}},
+		}}
+		template.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta1")
+		template.SetKind("GenericInfrastructureMachineTemplate")
+		template.SetName(name)
+		return template
+	}
+
+	kcp := &controlplanev1.KubeadmControlPlane{}
+	g.Expect(setInfrastructureTemplateChecksum(kcp, newTemplate("template-1"))).To(Succeed())
+	checksum := kcp.Annotations[controlplanev1.InfrastructureTemplateChecksumAnnotation]
+	g.Expect(checksum).ToNot(BeEmpty())
+
+	// The checksum does not depend on the name of the template.
+	g.Expect(setInfrastructureTemplateChecksum(kcp, newTemplate("template-2"))).To(Succeed())
+	g.Expect(kcp.Annotations).To(HaveKeyWithValue(controlplanev1.InfrastructureTemplateChecksumAnnotation, checksum))
+
+	// The annotation is removed if the reference is not a template.
+	g.Expect(setInfrastructureTemplateChecksum(kcp, nil)).To(Succeed())
+	g.Expect(kcp.Annotations).ToNot(HaveKey(controlplanev1.InfrastructureTemplateChecksumAnnotation))
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+const (
+	// defaultHostCleanupTimeout is the maximum time the deletion of a machine waits for the host cleanup
+	// to complete, if not configured otherwise.
+	defaultHostCleanupTimeout = 5 * time.Minute
+
+	// hostCleanupRequeueAfter is the interval used for checking the progress of the host cleanup on a machine.
+	hostCleanupRequeueAfter = 10 * time.Second
+)
+
+// reconcileHostCleanup cleans up the hosts of the control plane machines being deleted, when a host cleanup is
+// configured in the KCP machine template.
+//
+// Control plane machines are created with the HostCleanupPreTerminateHookAnnotation, so the Machine controller waits
+// before deleting the infrastructure, after the Node has been drained; at this stage the etcd member of the machine
+// has already been removed, and the host is cleaned up by a privileged Pod running on the Node. The hook is removed
+// once the Pod completes, or when the cleanup timeout expires, thus unblocking the deletion of the machine.
+func (r *KubeadmControlPlaneReconciler) reconcileHostCleanup(ctx context.Context, controlPlane *internal.ControlPlane) (ctrl.Result, error) {
+	// If the cluster is not yet initialized, there is no way to connect to the workload cluster.
+	if !controlPlane.KCP.Status.Initialized {
+		return ctrl.Result{}, nil
+	}
+
+	machines := controlPlane.Machines.Filter(
+		collections.HasDeletionTimestamp,
+		collections.HasAnnotationKey(controlplanev1.HostCleanupPreTerminateHookAnnotation),
+	)
+	if len(machines) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "cannot get remote client to workload cluster")
+	}
+
+	result := ctrl.Result{}
+	for _, machine := range machines {
+		done, err := r.reconcileMachineHostCleanup(ctx, controlPlane.KCP, machine, workloadCluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			result = ctrl.Result{RequeueAfter: hostCleanupRequeueAfter}
+		}
+	}
+	return result, nil
+}
+
+// reconcileMachineHostCleanup cleans up the host of a control plane machine being deleted, and returns true once
+// the pre-terminate hook has been removed from the machine.
+func (r *KubeadmControlPlaneReconciler) reconcileMachineHostCleanup(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, machine *clusterv1.Machine, workloadCluster internal.WorkloadCluster) (bool, error) {
+	log := ctrl.LoggerFrom(ctx).WithValues("Machine", klog.KObj(machine))
+
+	// If the host cleanup has been dropped from KCP after the deletion started, or there is no Node to clean up,
+	// just unblock the deletion of the machine.
+	hostCleanup := kcp.Spec.MachineTemplate.HostCleanup
+	if hostCleanup == nil || machine.Status.NodeRef == nil {
+		return true, r.removeHostCleanupHook(ctx, machine)
+	}
+
+	// Wait for the Machine controller to reach the pre-terminate hook, i.e. for the Node to be drained.
+	if !conditions.IsFalse(machine, clusterv1.PreTerminateDeleteHookSucceededCondition) {
+		return false, nil
+	}
+
+	nodeName := machine.Status.NodeRef.Name
+	pod, err := workloadCluster.GetHostCleanupPod(ctx, nodeName)
+	if err != nil {
+		return false, err
+	}
+
+	if pod == nil {
+		log.Info("Cleaning up the host of the Machine", "Node", nodeName)
+		image := hostCleanup.Image
+		if image == "" {
+			image = r.hostCommandImage()
+		}
+		mode := hostCleanup.Mode
+		if mode == "" {
+			mode = controlplanev1.HostCleanupModeRemoveManifests
+		}
+		if err := workloadCluster.CreateHostCleanupPod(ctx, nodeName, mode, image); err != nil {
+			return false, err
+		}
+		r.recorder.Eventf(kcp, corev1.EventTypeNormal, "HostCleanupStarted", "Cleaning up the host of Machine %s", machine.Name)
+		return false, nil
+	}
+
+	timeout := defaultHostCleanupTimeout
+	if hostCleanup.Timeout != nil {
+		timeout = hostCleanup.Timeout.Duration
+	}
+
+	switch {
+	case pod.Status.Phase == corev1.PodSucceeded:
+		log.Info("Cleaned up the host of the Machine", "Node", nodeName)
+		r.recorder.Eventf(kcp, corev1.EventTypeNormal, "HostCleanupCompleted", "Cleaned up the host of Machine %s", machine.Name)
+	case pod.Status.Phase == corev1.PodFailed:
+		log.Info("Failed to clean up the host of the Machine, proceeding with the deletion", "Pod", klog.KObj(pod))
+		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "HostCleanupFailed", "Failed to clean up the host of Machine %s, check the logs of Pod %s", machine.Name, klog.KObj(pod))
+	case time.Since(pod.CreationTimestamp.Time) > timeout:
+		log.Info("Timed out waiting for the host cleanup of the Machine, proceeding with the deletion", "Pod", klog.KObj(pod))
+		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "HostCleanupFailed", "Timed out waiting for the host cleanup of Machine %s", machine.Name)
+	default:
+		return false, nil
+	}
+
+	// NOTE: A failed Pod is not deleted, so its logs can be inspected; it is going to be garbage collected
+	// together with the Node.
+	if pod.Status.Phase == corev1.PodSucceeded {
+		if err := workloadCluster.DeleteHostCleanupPod(ctx, nodeName); err != nil {
+			return false, err
+		}
+	}
+	return true, r.removeHostCleanupHook(ctx, machine)
+}
+
+// removeHostCleanupHook removes the HostCleanupPreTerminateHookAnnotation from a machine.
+func (r *KubeadmControlPlaneReconciler) removeHostCleanupHook(ctx context.Context, machine *clusterv1.Machine) error {
+	patchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get PatchHelper for Machine %s", machine.Name)
+	}
+	delete(machine.Annotations, controlplanev1.HostCleanupPreTerminateHookAnnotation)
+	if err := patchHelper.Patch(ctx, machine); err != nil {
+		return errors.Wrapf(err, "failed to patch Machine %s", machine.Name)
+	}
+	return nil
+}
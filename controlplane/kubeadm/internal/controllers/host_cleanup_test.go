@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileHostCleanup(t *testing.T) {
+	newMachine := func(name string, deleting, waitingForHook bool) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   metav1.NamespaceDefault,
+				Annotations: map[string]string{controlplanev1.HostCleanupPreTerminateHookAnnotation: ""},
+				Finalizers:  []string{clusterv1.MachineFinalizer},
+			},
+			Status: clusterv1.MachineStatus{
+				NodeRef: &corev1.ObjectReference{Name: name},
+			},
+		}
+		if deleting {
+			m.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		if waitingForHook {
+			conditions.MarkFalse(m, clusterv1.PreTerminateDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "")
+		}
+		return m
+	}
+	newControlPlane := func(hostCleanup *controlplanev1.HostCleanup, machines ...*clusterv1.Machine) *internal.ControlPlane {
+		return &internal.ControlPlane{
+			KCP: &controlplanev1.KubeadmControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kcp",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+						HostCleanup: hostCleanup,
+					},
+				},
+				Status: controlplanev1.KubeadmControlPlaneStatus{
+					Initialized: true,
+				},
+			},
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster",
+					Namespace: metav1.NamespaceDefault,
+				},
+			},
+			Machines: collections.FromMachines(machines...),
+		}
+	}
+	newReconciler := func(workloadClient client.Client, machines ...*clusterv1.Machine) *KubeadmControlPlaneReconciler {
+		objs := []client.Object{}
+		for _, m := range machines {
+			objs = append(objs, m.DeepCopy())
+		}
+		return &KubeadmControlPlaneReconciler{
+			Client:   fake.NewClientBuilder().WithObjects(objs...).Build(),
+			recorder: record.NewFakeRecorder(32),
+			managementCluster: &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{Workload: &internal.Workload{Client: workloadClient}},
+			},
+			HostCommandImage: "busybox",
+		}
+	}
+	setPodPhase := func(g *WithT, c client.Client, nodeName string, phase corev1.PodPhase) {
+		pod := &corev1.Pod{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: internal.HostCleanupPodName(nodeName)}, pod)).To(Succeed())
+		pod.Status.Phase = phase
+		// NOTE: The fake client does not set the creation timestamp.
+		pod.CreationTimestamp = metav1.Now()
+		g.Expect(c.Update(ctx, pod)).To(Succeed())
+	}
+	hasHook := func(g *WithT, c client.Client, m *clusterv1.Machine) bool {
+		machine := &clusterv1.Machine{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(m), machine)).To(Succeed())
+		_, ok := machine.Annotations[controlplanev1.HostCleanupPreTerminateHookAnnotation]
+		return ok
+	}
+
+	t.Run("does nothing for machines not being deleted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", false, false)
+		controlPlane := newControlPlane(&controlplanev1.HostCleanup{}, m1)
+		workloadClient := fake.NewClientBuilder().Build()
+		r := newReconciler(workloadClient, m1)
+
+		result, err := r.reconcileHostCleanup(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		g.Expect(hasHook(g, r.Client, m1)).To(BeTrue())
+	})
+
+	t.Run("waits for the machine to be drained", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", true, false)
+		controlPlane := newControlPlane(&controlplanev1.HostCleanup{}, m1)
+		workloadClient := fake.NewClientBuilder().Build()
+		r := newReconciler(workloadClient, m1)
+
+		result, err := r.reconcileHostCleanup(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(hostCleanupRequeueAfter))
+		pod, err := (&internal.Workload{Client: workloadClient}).GetHostCleanupPod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pod).To(BeNil())
+	})
+
+	t.Run("cleans up the host and removes the hook", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", true, true)
+		controlPlane := newControlPlane(&controlplanev1.HostCleanup{Mode: controlplanev1.HostCleanupModeKubeadmReset}, m1)
+		workloadClient := fake.NewClientBuilder().Build()
+		r := newReconciler(workloadClient, m1)
+		workload := &internal.Workload{Client: workloadClient}
+
+		result, err := r.reconcileHostCleanup(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(hostCleanupRequeueAfter))
+		pod, err := workload.GetHostCleanupPod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pod).ToNot(BeNil())
+		g.Expect(pod.Spec.Containers[0].Image).To(Equal("busybox"))
+		g.Expect(pod.Spec.Containers[0].Command).To(ContainElement(ContainSubstring("kubeadm reset")))
+
+		// The hook is preserved while the cleanup is in progress.
+		setPodPhase(g, workloadClient, "m1", corev1.PodRunning)
+		result, err = r.reconcileHostCleanup(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(hostCleanupRequeueAfter))
+		g.Expect(hasHook(g, r.Client, m1)).To(BeTrue())
+
+		// Once the Pod succeeds, the Pod is deleted and the hook removed.
+		setPodPhase(g, workloadClient, "m1", corev1.PodSucceeded)
+		result, err = r.reconcileHostCleanup(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		g.Expect(hasHook(g, r.Client, m1)).To(BeFalse())
+		pod, err = workload.GetHostCleanupPod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pod).To(BeNil())
+	})
+
+	t.Run("removes the hook when the cleanup fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", true, true)
+		controlPlane := newControlPlane(&controlplanev1.HostCleanup{}, m1)
+		workloadClient := fake.NewClientBuilder().Build()
+		r := newReconciler(workloadClient, m1)
+
+		_, err := r.reconcileHostCleanup(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		setPodPhase(g, workloadClient, "m1", corev1.PodFailed)
+		result, err := r.reconcileHostCleanup(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		g.Expect(hasHook(g, r.Client, m1)).To(BeFalse())
+	})
+
+	t.Run("removes the hook when the cleanup times out", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", true, true)
+		controlPlane := newControlPlane(&controlplanev1.HostCleanup{Timeout: &metav1.Duration{Duration: time.Minute}}, m1)
+		// The cleanup Pod has been created before the timeout and it is still running.
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              internal.HostCleanupPodName("m1"),
+				Namespace:         metav1.NamespaceSystem,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		workloadClient := fake.NewClientBuilder().WithObjects(pod).Build()
+		r := newReconciler(workloadClient, m1)
+
+		result, err := r.reconcileHostCleanup(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		g.Expect(hasHook(g, r.Client, m1)).To(BeFalse())
+	})
+
+	t.Run("removes the hook when the host cleanup is not configured anymore", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", true, false)
+		controlPlane := newControlPlane(nil, m1)
+		workloadClient := fake.NewClientBuilder().Build()
+		r := newReconciler(workloadClient, m1)
+
+		result, err := r.reconcileHostCleanup(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		g.Expect(hasHook(g, r.Client, m1)).To(BeFalse())
+	})
+}
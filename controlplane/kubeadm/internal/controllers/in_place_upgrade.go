@@ -0,0 +1,231 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+const (
+	// defaultInPlaceUpgradeBinariesURL is the base URL the binaries of the new version are downloaded from,
+	// if not configured otherwise, either in the KubeadmControlPlane or with the --in-place-upgrade-binaries-url flag.
+	defaultInPlaceUpgradeBinariesURL = "https://dl.k8s.io/release"
+
+	// defaultInPlaceUpgradeTimeout is the maximum time for the in-place upgrade of a machine to complete,
+	// if not configured otherwise.
+	defaultInPlaceUpgradeTimeout = 10 * time.Minute
+
+	// inPlaceUpgradeRequeueAfter is the interval used for checking the progress of the in-place upgrade of a machine.
+	inPlaceUpgradeRequeueAfter = 10 * time.Second
+)
+
+// reconcileInPlaceUpgrade upgrades in place, one machine at a time, the control plane machines only requiring an
+// upgrade to a newer patch version, when the InPlacePatch upgrade policy is used; the other machines needing a rollout,
+// including the ones for which the in-place upgrade failed, are replaced afterwards by the rolling update.
+//
+// The upgrade of a machine is performed by a privileged Pod running on the corresponding Node, which installs the
+// binaries of the new version, runs kubeadm upgrade node and restarts the kubelet; the machine is considered done
+// once the Pod has completed and the Node reports the new kubelet version, and the version of the Machine is then
+// updated, so it no longer needs a rollout.
+func (r *KubeadmControlPlaneReconciler) reconcileInPlaceUpgrade(ctx context.Context, controlPlane *internal.ControlPlane) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	kcp := controlPlane.KCP
+
+	if kcp.Spec.UpgradePolicy != controlplanev1.UpgradePolicyInPlacePatch || isRolloutPaused(kcp) {
+		return ctrl.Result{}, nil
+	}
+
+	machines := controlPlane.MachinesNeedingInPlaceUpgrade()
+	if len(machines) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "cannot get remote client to workload cluster")
+	}
+
+	timeout := defaultInPlaceUpgradeTimeout
+	if kcp.Spec.InPlaceUpgrade != nil && kcp.Spec.InPlaceUpgrade.Timeout != nil {
+		timeout = kcp.Spec.InPlaceUpgrade.Timeout.Duration
+	}
+
+	// Continue with the machine being upgraded, if any.
+	var machine *clusterv1.Machine
+	var pod *corev1.Pod
+	for _, m := range machines.SortedByCreationTimestamp() {
+		if m.Status.NodeRef == nil {
+			continue
+		}
+		p, err := workloadCluster.GetInPlaceUpgradePod(ctx, m.Status.NodeRef.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if p == nil {
+			continue
+		}
+
+		// A Pod upgrading the machine to another version, e.g. a failed Pod left over from a previous in-place upgrade
+		// before the version of the KubeadmControlPlane changed, must not determine the outcome of the upgrade to the
+		// current version; it is deleted once completed or timed out, so the machine is upgraded again with a new Pod.
+		if p.Annotations[internal.InPlaceUpgradeVersionAnnotation] != kcp.Spec.Version {
+			if p.Status.Phase != corev1.PodSucceeded && p.Status.Phase != corev1.PodFailed && time.Since(p.CreationTimestamp.Time) <= timeout {
+				log.Info("Waiting for the in-place upgrade of the Machine to another version to complete", "Machine", klog.KObj(m), "Pod", klog.KObj(p))
+				return ctrl.Result{RequeueAfter: inPlaceUpgradeRequeueAfter}, nil
+			}
+			log.Info("Deleting the in-place upgrade Pod for another version", "Machine", klog.KObj(m), "Pod", klog.KObj(p), "version", p.Annotations[internal.InPlaceUpgradeVersionAnnotation])
+			if err := workloadCluster.DeleteInPlaceUpgradePod(ctx, m.Status.NodeRef.Name); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
+
+		machine, pod = m, p
+		break
+	}
+
+	if machine == nil {
+		// Upgrading a machine restarts its control plane components, so the upgrade starts only if the control plane is healthy.
+		if result, err := r.preflightChecks(ctx, controlPlane); err != nil || !result.IsZero() {
+			return result, err
+		}
+
+		machine = machines.Oldest()
+		if machine.Status.NodeRef == nil {
+			log.Info("Waiting for the Machine to have a Node before upgrading it in place", "Machine", klog.KObj(machine))
+			return ctrl.Result{RequeueAfter: inPlaceUpgradeRequeueAfter}, nil
+		}
+
+		// kubeadm upgrade node reads the desired configuration from the kubeadm-config ConfigMap.
+		if err := r.updateWorkloadClusterForUpgrade(ctx, kcp, workloadCluster); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		nodeName := machine.Status.NodeRef.Name
+		log.Info("Upgrading the Machine in place", "Machine", klog.KObj(machine), "Node", nodeName, "version", kcp.Spec.Version)
+		image, binariesURL := r.hostCommandImage(), r.inPlaceUpgradeBinariesURL()
+		if kcp.Spec.InPlaceUpgrade != nil && kcp.Spec.InPlaceUpgrade.Image != "" {
+			image = kcp.Spec.InPlaceUpgrade.Image
+		}
+		if kcp.Spec.InPlaceUpgrade != nil && kcp.Spec.InPlaceUpgrade.BinariesURL != "" {
+			binariesURL = kcp.Spec.InPlaceUpgrade.BinariesURL
+		}
+		if err := workloadCluster.CreateInPlaceUpgradePod(ctx, nodeName, kcp.Spec.Version, binariesURL, image); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.recorder.Eventf(kcp, corev1.EventTypeNormal, "InPlaceUpgradeStarted", "Upgrading in place Machine %s to %s", machine.Name, kcp.Spec.Version)
+		markInPlaceUpgradeInProgress(kcp, machine, len(machines))
+		return ctrl.Result{RequeueAfter: inPlaceUpgradeRequeueAfter}, nil
+	}
+
+	log = log.WithValues("Machine", klog.KObj(machine))
+	nodeName := machine.Status.NodeRef.Name
+
+	// NOTE: When the in-place upgrade fails, the Pod is not deleted, so its logs can be inspected; it is going to be
+	// garbage collected together with the Node when the machine is replaced, or deleted above if the version of the
+	// KubeadmControlPlane changes before that.
+	switch {
+	case pod.Status.Phase == corev1.PodFailed:
+		log.Info("Failed to upgrade the Machine in place, the Machine is going to be replaced", "Pod", klog.KObj(pod))
+		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "InPlaceUpgradeFailed", "Failed to upgrade in place Machine %s to %s, the Machine is going to be replaced; check the logs of Pod %s", machine.Name, kcp.Spec.Version, klog.KObj(pod))
+		return ctrl.Result{Requeue: true}, r.markInPlaceUpgradeFailed(ctx, machine, kcp.Spec.Version)
+	case time.Since(pod.CreationTimestamp.Time) > timeout:
+		log.Info("Timed out waiting for the in-place upgrade of the Machine, the Machine is going to be replaced", "Pod", klog.KObj(pod))
+		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "InPlaceUpgradeFailed", "Timed out waiting for the in-place upgrade of Machine %s to %s, the Machine is going to be replaced", machine.Name, kcp.Spec.Version)
+		return ctrl.Result{Requeue: true}, r.markInPlaceUpgradeFailed(ctx, machine, kcp.Spec.Version)
+	case pod.Status.Phase != corev1.PodSucceeded:
+		markInPlaceUpgradeInProgress(kcp, machine, len(machines))
+		return ctrl.Result{RequeueAfter: inPlaceUpgradeRequeueAfter}, nil
+	}
+
+	// Wait for the kubelet to be restarted with the new version.
+	if !nodeReportsKubeletVersion(machine, kcp.Spec.Version) {
+		log.Info("Waiting for the Node to report the new kubelet version", "Node", nodeName)
+		markInPlaceUpgradeInProgress(kcp, machine, len(machines))
+		return ctrl.Result{RequeueAfter: inPlaceUpgradeRequeueAfter}, nil
+	}
+
+	if err := workloadCluster.DeleteInPlaceUpgradePod(ctx, nodeName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	patchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get PatchHelper for Machine %s", machine.Name)
+	}
+	version := kcp.Spec.Version
+	machine.Spec.Version = &version
+	if err := patchHelper.Patch(ctx, machine); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to patch Machine %s", machine.Name)
+	}
+
+	log.Info("Upgraded the Machine in place", "Node", nodeName, "version", kcp.Spec.Version)
+	r.recorder.Eventf(kcp, corev1.EventTypeNormal, "InPlaceUpgradeCompleted", "Upgraded in place Machine %s to %s", machine.Name, kcp.Spec.Version)
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// markInPlaceUpgradeInProgress documents the in-place upgrade of a machine in the MachinesSpecUpToDate condition.
+func markInPlaceUpgradeInProgress(kcp *controlplanev1.KubeadmControlPlane, machine *clusterv1.Machine, pending int) {
+	conditions.MarkFalse(kcp, controlplanev1.MachinesSpecUpToDateCondition, controlplanev1.InPlaceUpgradeInProgressReason, clusterv1.ConditionSeverityWarning,
+		"Upgrading in place Machine %s to %s (%d replicas to be upgraded in place)", machine.Name, kcp.Spec.Version, pending)
+}
+
+// markInPlaceUpgradeFailed sets the InPlaceUpgradeFailedAnnotation on a machine, so it is replaced instead.
+func (r *KubeadmControlPlaneReconciler) markInPlaceUpgradeFailed(ctx context.Context, machine *clusterv1.Machine, version string) error {
+	patchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get PatchHelper for Machine %s", machine.Name)
+	}
+	if machine.Annotations == nil {
+		machine.Annotations = map[string]string{}
+	}
+	machine.Annotations[controlplanev1.InPlaceUpgradeFailedAnnotation] = version
+	if err := patchHelper.Patch(ctx, machine); err != nil {
+		return errors.Wrapf(err, "failed to patch Machine %s", machine.Name)
+	}
+	return nil
+}
+
+// nodeReportsKubeletVersion returns true if the Node of a machine reports the given kubelet version.
+func nodeReportsKubeletVersion(machine *clusterv1.Machine, version string) bool {
+	if machine.Status.NodeInfo == nil {
+		return false
+	}
+	kubeletVersion, err := semver.ParseTolerant(machine.Status.NodeInfo.KubeletVersion)
+	if err != nil {
+		return false
+	}
+	desiredVersion, err := semver.ParseTolerant(version)
+	if err != nil {
+		return false
+	}
+	return kubeletVersion.Equals(desiredVersion)
+}
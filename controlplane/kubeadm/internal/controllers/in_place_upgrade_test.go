@@ -0,0 +1,271 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileInPlaceUpgrade(t *testing.T) {
+	newMachine := func(name, version string) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: clusterv1.MachineSpec{
+				Version: &version,
+			},
+			Status: clusterv1.MachineStatus{
+				NodeRef:  &corev1.ObjectReference{Name: name},
+				NodeInfo: &corev1.NodeSystemInfo{KubeletVersion: version},
+			},
+		}
+		for _, condition := range []clusterv1.ConditionType{
+			controlplanev1.MachineAPIServerPodHealthyCondition,
+			controlplanev1.MachineControllerManagerPodHealthyCondition,
+			controlplanev1.MachineSchedulerPodHealthyCondition,
+			controlplanev1.MachineEtcdPodHealthyCondition,
+			controlplanev1.MachineEtcdMemberHealthyCondition,
+		} {
+			conditions.MarkTrue(m, condition)
+		}
+		return m
+	}
+	newControlPlane := func(policy controlplanev1.UpgradePolicyType, machines ...*clusterv1.Machine) *internal.ControlPlane {
+		return &internal.ControlPlane{
+			KCP: &controlplanev1.KubeadmControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kcp",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					Version:       "v1.26.3",
+					UpgradePolicy: policy,
+					InPlaceUpgrade: &controlplanev1.InPlaceUpgrade{
+						BinariesURL: "https://example.com/release",
+					},
+				},
+			},
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster",
+					Namespace: metav1.NamespaceDefault,
+				},
+			},
+			Machines: collections.FromMachines(machines...),
+		}
+	}
+	newReconciler := func(workloadClient client.Client, machines ...*clusterv1.Machine) *KubeadmControlPlaneReconciler {
+		objs := []client.Object{}
+		for _, m := range machines {
+			objs = append(objs, m.DeepCopy())
+		}
+		return &KubeadmControlPlaneReconciler{
+			Client:   fake.NewClientBuilder().WithObjects(objs...).Build(),
+			recorder: record.NewFakeRecorder(32),
+			managementCluster: &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{Workload: &internal.Workload{Client: workloadClient}},
+			},
+			HostCommandImage: "busybox",
+		}
+	}
+	setPodPhase := func(g *WithT, c client.Client, nodeName string, phase corev1.PodPhase) {
+		pod := &corev1.Pod{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: internal.InPlaceUpgradePodName(nodeName)}, pod)).To(Succeed())
+		pod.Status.Phase = phase
+		// NOTE: The fake client does not set the creation timestamp.
+		pod.CreationTimestamp = metav1.Now()
+		g.Expect(c.Update(ctx, pod)).To(Succeed())
+	}
+	getMachine := func(g *WithT, c client.Client, m *clusterv1.Machine) *clusterv1.Machine {
+		machine := &clusterv1.Machine{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(m), machine)).To(Succeed())
+		return machine
+	}
+
+	t.Run("does nothing without the InPlacePatch upgrade policy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", "v1.26.1")
+		controlPlane := newControlPlane(controlplanev1.UpgradePolicyReplace, m1)
+		workloadClient := fake.NewClientBuilder().Build()
+		r := newReconciler(workloadClient, m1)
+
+		result, err := r.reconcileInPlaceUpgrade(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		pod, err := (&internal.Workload{Client: workloadClient}).GetInPlaceUpgradePod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pod).To(BeNil())
+	})
+
+	t.Run("does nothing for machines requiring a minor version upgrade", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", "v1.25.3")
+		controlPlane := newControlPlane(controlplanev1.UpgradePolicyInPlacePatch, m1)
+		workloadClient := fake.NewClientBuilder().Build()
+		r := newReconciler(workloadClient, m1)
+
+		result, err := r.reconcileInPlaceUpgrade(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+		pod, err := (&internal.Workload{Client: workloadClient}).GetInPlaceUpgradePod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pod).To(BeNil())
+	})
+
+	t.Run("upgrades the machine in place and updates its version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", "v1.26.1")
+		controlPlane := newControlPlane(controlplanev1.UpgradePolicyInPlacePatch, m1)
+		workloadClient := fake.NewClientBuilder().Build()
+		r := newReconciler(workloadClient, m1)
+		workload := &internal.Workload{Client: workloadClient}
+
+		result, err := r.reconcileInPlaceUpgrade(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(inPlaceUpgradeRequeueAfter))
+		pod, err := workload.GetInPlaceUpgradePod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pod).ToNot(BeNil())
+		g.Expect(pod.Spec.Containers[0].Image).To(Equal("busybox"))
+		g.Expect(pod.Spec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: "VERSION", Value: "v1.26.3"},
+			corev1.EnvVar{Name: "BINARIES_URL", Value: "https://example.com/release"},
+		))
+		g.Expect(conditions.GetReason(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition)).To(Equal(controlplanev1.InPlaceUpgradeInProgressReason))
+
+		// The machine is not updated until the Node reports the new kubelet version.
+		setPodPhase(g, workloadClient, "m1", corev1.PodSucceeded)
+		result, err = r.reconcileInPlaceUpgrade(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(inPlaceUpgradeRequeueAfter))
+		g.Expect(*getMachine(g, r.Client, m1).Spec.Version).To(Equal("v1.26.1"))
+
+		m1.Status.NodeInfo.KubeletVersion = "v1.26.3"
+		result, err = r.reconcileInPlaceUpgrade(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Requeue).To(BeTrue())
+		g.Expect(*getMachine(g, r.Client, m1).Spec.Version).To(Equal("v1.26.3"))
+		pod, err = workload.GetInPlaceUpgradePod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(pod).To(BeNil())
+	})
+
+	t.Run("marks the machine for replacement when the in-place upgrade fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", "v1.26.1")
+		controlPlane := newControlPlane(controlplanev1.UpgradePolicyInPlacePatch, m1)
+		workloadClient := fake.NewClientBuilder().Build()
+		r := newReconciler(workloadClient, m1)
+
+		_, err := r.reconcileInPlaceUpgrade(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		setPodPhase(g, workloadClient, "m1", corev1.PodFailed)
+		result, err := r.reconcileInPlaceUpgrade(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Requeue).To(BeTrue())
+		g.Expect(getMachine(g, r.Client, m1).Annotations).To(HaveKeyWithValue(controlplanev1.InPlaceUpgradeFailedAnnotation, "v1.26.3"))
+
+		// The machine is then left to the rolling update.
+		result, err = r.reconcileInPlaceUpgrade(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+	})
+
+	t.Run("marks the machine for replacement when the in-place upgrade times out", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := newMachine("m1", "v1.26.1")
+		controlPlane := newControlPlane(controlplanev1.UpgradePolicyInPlacePatch, m1)
+		controlPlane.KCP.Spec.InPlaceUpgrade.Timeout = &metav1.Duration{Duration: time.Minute}
+		// The in-place upgrade Pod has been created before the timeout and it is still running.
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              internal.InPlaceUpgradePodName("m1"),
+				Namespace:         metav1.NamespaceSystem,
+				Annotations:       map[string]string{internal.InPlaceUpgradeVersionAnnotation: "v1.26.3"},
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		workloadClient := fake.NewClientBuilder().WithObjects(pod).Build()
+		r := newReconciler(workloadClient, m1)
+
+		result, err := r.reconcileInPlaceUpgrade(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Requeue).To(BeTrue())
+		g.Expect(getMachine(g, r.Client, m1).Annotations).To(HaveKeyWithValue(controlplanev1.InPlaceUpgradeFailedAnnotation, "v1.26.3"))
+	})
+	t.Run("upgrades the machine in place again after a failed in-place upgrade to another version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// The in-place upgrade to v1.26.2 failed, and the version of the KubeadmControlPlane has then been bumped to v1.26.3.
+		m1 := newMachine("m1", "v1.26.1")
+		m1.Annotations = map[string]string{controlplanev1.InPlaceUpgradeFailedAnnotation: "v1.26.2"}
+		controlPlane := newControlPlane(controlplanev1.UpgradePolicyInPlacePatch, m1)
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              internal.InPlaceUpgradePodName("m1"),
+				Namespace:         metav1.NamespaceSystem,
+				Annotations:       map[string]string{internal.InPlaceUpgradeVersionAnnotation: "v1.26.2"},
+				CreationTimestamp: metav1.Now(),
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodFailed},
+		}
+		workloadClient := fake.NewClientBuilder().WithObjects(pod).Build()
+		r := newReconciler(workloadClient, m1)
+		workload := &internal.Workload{Client: workloadClient}
+
+		// The stale Pod is deleted without marking the machine as failed for the new version.
+		result, err := r.reconcileInPlaceUpgrade(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Requeue).To(BeTrue())
+		g.Expect(getMachine(g, r.Client, m1).Annotations).To(HaveKeyWithValue(controlplanev1.InPlaceUpgradeFailedAnnotation, "v1.26.2"))
+		p, err := workload.GetInPlaceUpgradePod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(p).To(BeNil())
+
+		// A new Pod upgrading the machine to the new version is then created.
+		result, err = r.reconcileInPlaceUpgrade(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(inPlaceUpgradeRequeueAfter))
+		p, err = workload.GetInPlaceUpgradePod(ctx, "m1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(p).ToNot(BeNil())
+		g.Expect(p.Annotations).To(HaveKeyWithValue(internal.InPlaceUpgradeVersionAnnotation, "v1.26.3"))
+	})
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// joinPermitRequeueAfter is the interval used for checking the progress of the machines waiting for a join permit.
+const joinPermitRequeueAfter = 10 * time.Second
+
+// parallelJoinBootstrapAnnotations are the annotations set on the KubeadmConfigs of the machines created in parallel
+// during the initial provisioning, making their bootstrap data wait for a join permit before running kubeadm join.
+var parallelJoinBootstrapAnnotations = map[string]string{
+	bootstrapv1.ReportBootstrapProgressAnnotation: "",
+	bootstrapv1.WaitForJoinPermitAnnotation:       "",
+}
+
+// reconcileJoinPermits serializes the joins of the control plane machines created in parallel during the initial
+// provisioning; their infrastructure is provisioned in parallel, but each machine waits for a join permit before
+// running kubeadm join.
+//
+// A join is permitted, to the oldest machine waiting for it, only when the machines already permitted to join have
+// joined the control plane, and the control plane is healthy; this ensures that etcd members are added one at a time,
+// each one after the previous one has been started.
+func (r *KubeadmControlPlaneReconciler) reconcileJoinPermits(ctx context.Context, controlPlane *internal.ControlPlane) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// If the cluster is not yet initialized, there is no way to connect to the workload cluster.
+	if !controlPlane.KCP.Status.Initialized {
+		return ctrl.Result{}, nil
+	}
+
+	var joining, waiting []*clusterv1.Machine
+	for _, machine := range controlPlane.Machines.Filter(collections.Not(collections.HasDeletionTimestamp)).SortedByCreationTimestamp() {
+		if machine.Status.NodeRef != nil {
+			continue
+		}
+		config, ok := controlPlane.GetKubeadmConfig(machine.Name)
+		if !ok {
+			continue
+		}
+		if _, ok := config.Annotations[bootstrapv1.WaitForJoinPermitAnnotation]; !ok {
+			continue
+		}
+		if _, ok := config.Annotations[controlplanev1.JoinPermittedAnnotation]; ok {
+			joining = append(joining, machine)
+			continue
+		}
+		waiting = append(waiting, machine)
+	}
+	if len(joining) == 0 && len(waiting) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "cannot get remote client to workload cluster")
+	}
+
+	// Wait for the machines already permitted to join the control plane; the join permit is granted again to the
+	// machines still waiting for it, e.g. if the permit failed after the KubeadmConfig had been annotated.
+	if len(joining) > 0 {
+		for _, machine := range joining {
+			config, _ := controlPlane.GetKubeadmConfig(machine.Name)
+			if isWaitingForJoinPermit(config) {
+				if err := workloadCluster.PermitJoin(ctx, config.Name); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+		}
+		log.Info("Waiting for the control plane machine permitted to join to join the control plane", "Machine", klog.KObj(joining[0]))
+		return ctrl.Result{RequeueAfter: joinPermitRequeueAfter}, nil
+	}
+
+	// Run preflight checks to ensure that the machines already part of the control plane are stable before permitting
+	// the next join; if not, wait.
+	if result, err := r.preflightChecks(ctx, controlPlane, waiting...); err != nil || !result.IsZero() {
+		return result, err
+	}
+
+	for _, machine := range waiting {
+		config, _ := controlPlane.GetKubeadmConfig(machine.Name)
+		if !isWaitingForJoinPermit(config) {
+			continue
+		}
+
+		log.Info("Permitting the control plane machine to join the control plane", "Machine", klog.KObj(machine))
+		patchHelper, err := patch.NewHelper(config, r.Client)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to get PatchHelper for KubeadmConfig %s", config.Name)
+		}
+		if config.Annotations == nil {
+			config.Annotations = map[string]string{}
+		}
+		config.Annotations[controlplanev1.JoinPermittedAnnotation] = ""
+		if err := patchHelper.Patch(ctx, config); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to patch KubeadmConfig %s", config.Name)
+		}
+		if err := workloadCluster.PermitJoin(ctx, config.Name); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeNormal, "JoinPermitted", "Permitted Machine %s to join the control plane", machine.Name)
+		break
+	}
+	return ctrl.Result{RequeueAfter: joinPermitRequeueAfter}, nil
+}
+
+// isWaitingForJoinPermit returns true if the machine bootstrapped with a KubeadmConfig reported it is waiting for a join permit.
+func isWaitingForJoinPermit(config *bootstrapv1.KubeadmConfig) bool {
+	return config.Status.BootstrapProgress != nil && config.Status.BootstrapProgress.Phase == bootstrapv1.WaitingForJoinPermitBootstrapPhase
+}
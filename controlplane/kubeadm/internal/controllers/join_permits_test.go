@@ -0,0 +1,231 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
+)
+
+func TestReconcileJoinPermits(t *testing.T) {
+	now := time.Now()
+	newMachine := func(name string, age time.Duration, joined bool) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         metav1.NamespaceDefault,
+				CreationTimestamp: metav1.Time{Time: now.Add(-age)},
+			},
+		}
+		if joined {
+			m.Status.NodeRef = &corev1.ObjectReference{Name: name}
+		}
+		setMachineHealthy(m)
+		return m
+	}
+	newConfig := func(name string, phase string, permitted bool) *bootstrapv1.KubeadmConfig {
+		c := &bootstrapv1.KubeadmConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   metav1.NamespaceDefault,
+				Annotations: map[string]string{bootstrapv1.WaitForJoinPermitAnnotation: ""},
+			},
+		}
+		if phase != "" {
+			c.Status.BootstrapProgress = &clusterv1.MachineBootstrapProgress{Phase: phase}
+		}
+		if permitted {
+			c.Annotations[controlplanev1.JoinPermittedAnnotation] = ""
+		}
+		return c
+	}
+	newProgressConfigMap := func(name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bootstrapv1.BootstrapProgressConfigMapNamePrefix + name,
+				Namespace: metav1.NamespaceSystem,
+			},
+		}
+	}
+	newControlPlane := func(machines []*clusterv1.Machine, configs map[string]*bootstrapv1.KubeadmConfig) *internal.ControlPlane {
+		kcp := &controlplanev1.KubeadmControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kcp",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Status: controlplanev1.KubeadmControlPlaneStatus{
+				Initialized: true,
+			},
+		}
+		setKCPHealthy(kcp)
+		return &internal.ControlPlane{
+			KCP: kcp,
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster",
+					Namespace: metav1.NamespaceDefault,
+				},
+			},
+			Machines:       collections.FromMachines(machines...),
+			KubeadmConfigs: configs,
+		}
+	}
+	newReconciler := func(workloadClient client.Client, configs map[string]*bootstrapv1.KubeadmConfig) *KubeadmControlPlaneReconciler {
+		objs := []client.Object{}
+		for _, c := range configs {
+			objs = append(objs, c.DeepCopy())
+		}
+		return &KubeadmControlPlaneReconciler{
+			Client:   fake.NewClientBuilder().WithObjects(objs...).Build(),
+			recorder: record.NewFakeRecorder(32),
+			managementCluster: &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{Workload: &internal.Workload{Client: workloadClient}},
+			},
+		}
+	}
+	isPermitted := func(g *WithT, r *KubeadmControlPlaneReconciler, workloadClient client.Client, name string) bool {
+		config := &bootstrapv1.KubeadmConfig{}
+		g.Expect(r.Client.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: name}, config)).To(Succeed())
+		_, annotated := config.Annotations[controlplanev1.JoinPermittedAnnotation]
+
+		configMap := newProgressConfigMap(name)
+		g.Expect(workloadClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+		permitted := configMap.Data[bootstrapv1.JoinPermittedBootstrapProgressKey] == "true"
+
+		g.Expect(annotated).To(Equal(permitted))
+		return permitted
+	}
+
+	t.Run("permits the join of the oldest machine waiting for it", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machines := []*clusterv1.Machine{
+			newMachine("m1", 3*time.Hour, true),
+			newMachine("m2", 2*time.Hour, false),
+			newMachine("m3", 1*time.Hour, false),
+		}
+		configs := map[string]*bootstrapv1.KubeadmConfig{
+			"m2": newConfig("m2", bootstrapv1.WaitingForJoinPermitBootstrapPhase, false),
+			"m3": newConfig("m3", bootstrapv1.WaitingForJoinPermitBootstrapPhase, false),
+		}
+		workloadClient := fake.NewClientBuilder().WithObjects(newProgressConfigMap("m2"), newProgressConfigMap("m3")).Build()
+		r := newReconciler(workloadClient, configs)
+
+		result, err := r.reconcileJoinPermits(ctx, newControlPlane(machines, configs))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: joinPermitRequeueAfter}))
+		g.Expect(isPermitted(g, r, workloadClient, "m2")).To(BeTrue())
+		g.Expect(isPermitted(g, r, workloadClient, "m3")).To(BeFalse())
+	})
+
+	t.Run("skips machines not waiting for the join permit yet", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machines := []*clusterv1.Machine{
+			newMachine("m1", 3*time.Hour, true),
+			newMachine("m2", 2*time.Hour, false),
+			newMachine("m3", 1*time.Hour, false),
+		}
+		configs := map[string]*bootstrapv1.KubeadmConfig{
+			"m2": newConfig("m2", bootstrapv1.RunningPreKubeadmCommandsBootstrapPhase, false),
+			"m3": newConfig("m3", bootstrapv1.WaitingForJoinPermitBootstrapPhase, false),
+		}
+		workloadClient := fake.NewClientBuilder().WithObjects(newProgressConfigMap("m2"), newProgressConfigMap("m3")).Build()
+		r := newReconciler(workloadClient, configs)
+
+		_, err := r.reconcileJoinPermits(ctx, newControlPlane(machines, configs))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(isPermitted(g, r, workloadClient, "m2")).To(BeFalse())
+		g.Expect(isPermitted(g, r, workloadClient, "m3")).To(BeTrue())
+	})
+
+	t.Run("waits for the machine permitted to join before permitting the next one", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machines := []*clusterv1.Machine{
+			newMachine("m1", 3*time.Hour, true),
+			newMachine("m2", 2*time.Hour, false),
+			newMachine("m3", 1*time.Hour, false),
+		}
+		configs := map[string]*bootstrapv1.KubeadmConfig{
+			"m2": newConfig("m2", bootstrapv1.RunningKubeadmBootstrapPhase, true),
+			"m3": newConfig("m3", bootstrapv1.WaitingForJoinPermitBootstrapPhase, false),
+		}
+		configMap := newProgressConfigMap("m2")
+		configMap.Data = map[string]string{bootstrapv1.JoinPermittedBootstrapProgressKey: "true"}
+		workloadClient := fake.NewClientBuilder().WithObjects(configMap, newProgressConfigMap("m3")).Build()
+		r := newReconciler(workloadClient, configs)
+
+		result, err := r.reconcileJoinPermits(ctx, newControlPlane(machines, configs))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: joinPermitRequeueAfter}))
+		g.Expect(isPermitted(g, r, workloadClient, "m3")).To(BeFalse())
+	})
+
+	t.Run("waits for the control plane to be healthy before permitting the next join", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m2 := newMachine("m2", 2*time.Hour, true)
+		m2.Status.Conditions = nil
+		machines := []*clusterv1.Machine{
+			newMachine("m1", 3*time.Hour, true),
+			m2,
+			newMachine("m3", 1*time.Hour, false),
+		}
+		configs := map[string]*bootstrapv1.KubeadmConfig{
+			"m3": newConfig("m3", bootstrapv1.WaitingForJoinPermitBootstrapPhase, false),
+		}
+		workloadClient := fake.NewClientBuilder().WithObjects(newProgressConfigMap("m3")).Build()
+		r := newReconciler(workloadClient, configs)
+
+		result, err := r.reconcileJoinPermits(ctx, newControlPlane(machines, configs))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}))
+		g.Expect(isPermitted(g, r, workloadClient, "m3")).To(BeFalse())
+	})
+
+	t.Run("does nothing for machines not waiting for a join permit", func(t *testing.T) {
+		g := NewWithT(t)
+
+		config := newConfig("m2", bootstrapv1.RunningPreKubeadmCommandsBootstrapPhase, false)
+		config.Annotations = nil
+		machines := []*clusterv1.Machine{
+			newMachine("m1", 3*time.Hour, true),
+			newMachine("m2", 2*time.Hour, false),
+		}
+		configs := map[string]*bootstrapv1.KubeadmConfig{"m2": config}
+		r := newReconciler(fake.NewClientBuilder().Build(), configs)
+
+		result, err := r.reconcileJoinPermits(ctx, newControlPlane(machines, configs))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.IsZero()).To(BeTrue())
+	})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	capilabels "sigs.k8s.io/cluster-api/internal/labels"
+)
+
+// revisionHistoryLimit is the number of ControllerRevisions retained for each KubeadmControlPlane.
+const revisionHistoryLimit = 10
+
+// reconcileRevisionHistory records the machine template and the KubeadmConfigSpec of the KubeadmControlPlane
+// in a ControllerRevision every time they change, so it is possible to rollback to a previous revision,
+// e.g. by using `clusterctl alpha rollout undo`.
+// NOTE: ControllerRevisions must have the control plane name label, given that the manager only caches
+// ControllerRevisions with this label.
+func (r *KubeadmControlPlaneReconciler) reconcileRevisionHistory(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	data, err := revisionData(kcp)
+	if err != nil {
+		return err
+	}
+
+	revisionList := &appsv1.ControllerRevisionList{}
+	if err := r.Client.List(ctx, revisionList, client.InNamespace(kcp.Namespace), client.MatchingLabels{clusterv1.MachineControlPlaneNameLabel: capilabels.MustFormatValue(kcp.Name)}); err != nil {
+		return errors.Wrap(err, "failed to list ControllerRevisions")
+	}
+	revisions := make([]*appsv1.ControllerRevision, 0, len(revisionList.Items))
+	for i := range revisionList.Items {
+		if metav1.IsControlledBy(&revisionList.Items[i], kcp) {
+			revisions = append(revisions, &revisionList.Items[i])
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+
+	// If the current spec is already recorded in the latest revision, there is nothing to do.
+	// NOTE: revision names are computed from a hash of the revision data.
+	name := revisionName(kcp, data)
+	nextRevision := int64(1)
+	if len(revisions) > 0 {
+		latest := revisions[len(revisions)-1]
+		if latest.Name == name {
+			return nil
+		}
+		nextRevision = latest.Revision + 1
+	}
+
+	// If the current spec matches a previous revision, e.g. after a rollback, bump the existing revision
+	// instead of creating a new one.
+	for _, revision := range revisions {
+		if revision.Name != name {
+			continue
+		}
+		log.V(4).Info("Bumping ControllerRevision", "ControllerRevision", revision.Name, "revision", nextRevision)
+		revisionPatch := client.MergeFrom(revision.DeepCopy())
+		revision.Revision = nextRevision
+		if err := r.Client.Patch(ctx, revision, revisionPatch); err != nil {
+			return errors.Wrapf(err, "failed to update ControllerRevision %s", revision.Name)
+		}
+		return r.cleanupRevisionHistory(ctx, revisions)
+	}
+
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: kcp.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel:             kcp.Labels[clusterv1.ClusterNameLabel],
+				clusterv1.MachineControlPlaneNameLabel: capilabels.MustFormatValue(kcp.Name),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind(kubeadmControlPlaneKind)),
+			},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: nextRevision,
+	}
+	log.V(4).Info("Creating ControllerRevision", "ControllerRevision", revision.Name, "revision", nextRevision)
+	if err := r.Client.Create(ctx, revision); err != nil {
+		return errors.Wrapf(err, "failed to create ControllerRevision %s", revision.Name)
+	}
+	return r.cleanupRevisionHistory(ctx, append(revisions, revision))
+}
+
+// cleanupRevisionHistory deletes the oldest revisions exceeding the revision history limit.
+func (r *KubeadmControlPlaneReconciler) cleanupRevisionHistory(ctx context.Context, revisions []*appsv1.ControllerRevision) error {
+	sort.SliceStable(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	for i := 0; i < len(revisions)-revisionHistoryLimit; i++ {
+		if err := r.Client.Delete(ctx, revisions[i]); err != nil {
+			return errors.Wrapf(err, "failed to delete ControllerRevision %s", revisions[i].Name)
+		}
+	}
+	return nil
+}
+
+// revisionData returns the serialized subset of the KubeadmControlPlane spec tracked by the revision history.
+func revisionData(kcp *controlplanev1.KubeadmControlPlane) ([]byte, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"machineTemplate":   kcp.Spec.MachineTemplate,
+			"kubeadmConfigSpec": kcp.Spec.KubeadmConfigSpec,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal KubeadmControlPlane revision")
+	}
+	return data, nil
+}
+
+// revisionName returns a name for the ControllerRevision which is unique for the given revision data.
+func revisionName(kcp *controlplanev1.KubeadmControlPlane, data []byte) string {
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(data)
+	return fmt.Sprintf("%s-%s", kcp.Name, rand.SafeEncodeString(fmt.Sprint(hasher.Sum32())))
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+func TestReconcileRevisionHistory(t *testing.T) {
+	g := NewWithT(t)
+
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kcp",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "kcp-uid",
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				PreKubeadmCommands: []string{"echo 1"},
+			},
+		},
+	}
+	fakeClient := newFakeClient(kcp.DeepCopy())
+	r := &KubeadmControlPlaneReconciler{Client: fakeClient}
+
+	listRevisions := func() []appsv1.ControllerRevision {
+		revisions := &appsv1.ControllerRevisionList{}
+		g.Expect(fakeClient.List(ctx, revisions, client.InNamespace(kcp.Namespace))).To(Succeed())
+		sort.Slice(revisions.Items, func(i, j int) bool { return revisions.Items[i].Revision < revisions.Items[j].Revision })
+		return revisions.Items
+	}
+
+	// The first reconcile records the first revision.
+	g.Expect(r.reconcileRevisionHistory(ctx, kcp)).To(Succeed())
+	revisions := listRevisions()
+	g.Expect(revisions).To(HaveLen(1))
+	g.Expect(revisions[0].Revision).To(Equal(int64(1)))
+	g.Expect(metav1.IsControlledBy(&revisions[0], kcp)).To(BeTrue())
+	firstRevisionName := revisions[0].Name
+
+	// Reconciling an unchanged spec does not record a new revision.
+	g.Expect(r.reconcileRevisionHistory(ctx, kcp)).To(Succeed())
+	g.Expect(listRevisions()).To(HaveLen(1))
+
+	// Changing the spec records a new revision.
+	kcp.Spec.KubeadmConfigSpec.PreKubeadmCommands = []string{"echo 2"}
+	g.Expect(r.reconcileRevisionHistory(ctx, kcp)).To(Succeed())
+	revisions = listRevisions()
+	g.Expect(revisions).To(HaveLen(2))
+	g.Expect(revisions[1].Revision).To(Equal(int64(2)))
+
+	// Going back to a previous spec bumps the existing revision.
+	kcp.Spec.KubeadmConfigSpec.PreKubeadmCommands = []string{"echo 1"}
+	g.Expect(r.reconcileRevisionHistory(ctx, kcp)).To(Succeed())
+	revisions = listRevisions()
+	g.Expect(revisions).To(HaveLen(2))
+	g.Expect(revisions[1].Name).To(Equal(firstRevisionName))
+	g.Expect(revisions[1].Revision).To(Equal(int64(3)))
+
+	// Revisions exceeding the history limit are deleted.
+	for i := 0; i < revisionHistoryLimit; i++ {
+		kcp.Spec.KubeadmConfigSpec.PreKubeadmCommands = []string{fmt.Sprintf("echo %d", i+10)}
+		g.Expect(r.reconcileRevisionHistory(ctx, kcp)).To(Succeed())
+	}
+	revisions = listRevisions()
+	g.Expect(revisions).To(HaveLen(revisionHistoryLimit))
+	g.Expect(revisions[len(revisions)-1].Revision).To(Equal(int64(3 + revisionHistoryLimit)))
+}
@@ -31,6 +31,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/util/deletion"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -55,7 +56,7 @@ func (r *KubeadmControlPlaneReconciler) initializeControlPlane(ctx context.Conte
 
 	bootstrapSpec := controlPlane.InitialControlPlaneConfig()
 	fd := controlPlane.NextFailureDomainForScaleUp()
-	if err := r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, fd); err != nil {
+	if err := r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, nil, fd); err != nil {
 		logger.Error(err, "Failed to create initial control plane Machine")
 		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedInitialization", "Failed to create initial control plane Machine for cluster %s/%s control plane: %v", cluster.Namespace, cluster.Name, err)
 		return ctrl.Result{}, err
@@ -68,6 +69,10 @@ func (r *KubeadmControlPlaneReconciler) initializeControlPlane(ctx context.Conte
 func (r *KubeadmControlPlaneReconciler) scaleUpControlPlane(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane, controlPlane *internal.ControlPlane) (ctrl.Result, error) {
 	logger := ctrl.LoggerFrom(ctx)
 
+	if isParallelInitialProvisioning(kcp) {
+		return r.scaleUpControlPlaneInParallel(ctx, cluster, kcp, controlPlane)
+	}
+
 	// Run preflight checks to ensure that the control plane is stable before proceeding with a scale up/scale down operation; if not, wait.
 	if result, err := r.preflightChecks(ctx, controlPlane); err != nil || !result.IsZero() {
 		return result, err
@@ -76,7 +81,7 @@ func (r *KubeadmControlPlaneReconciler) scaleUpControlPlane(ctx context.Context,
 	// Create the bootstrap configuration
 	bootstrapSpec := controlPlane.JoinControlPlaneConfig()
 	fd := controlPlane.NextFailureDomainForScaleUp()
-	if err := r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, fd); err != nil {
+	if err := r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, nil, fd); err != nil {
 		logger.Error(err, "Failed to create additional control plane Machine")
 		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedScaleUp", "Failed to create additional control plane Machine for cluster %s/%s control plane: %v", cluster.Namespace, cluster.Name, err)
 		return ctrl.Result{}, err
@@ -86,6 +91,50 @@ func (r *KubeadmControlPlaneReconciler) scaleUpControlPlane(ctx context.Context,
 	return ctrl.Result{Requeue: true}, nil
 }
 
+// isParallelInitialProvisioning returns true if the control plane is being created using the Parallel initial
+// provisioning strategy; the initial provisioning completes when the desired number of machines is created for the
+// first time, and this is latched in the KCP status, so later scale ups are always sequential.
+func isParallelInitialProvisioning(kcp *controlplanev1.KubeadmControlPlane) bool {
+	return kcp.Spec.InitialProvisioningStrategy == controlplanev1.ParallelInitialProvisioningStrategyType &&
+		!kcp.Status.InitialProvisioningCompleted
+}
+
+// scaleUpControlPlaneInParallel creates all the missing control plane machines at once, so their infrastructure is provisioned
+// in parallel; machines that are still provisioning are excluded from preflight checks, but at least one machine
+// must have joined the control plane, which ensures the control plane is initialized before joining other machines.
+// NOTE: The machines are created with bootstrap data waiting for a join permit before running kubeadm join, and
+// joins are permitted one at a time by reconcileJoinPermits.
+func (r *KubeadmControlPlaneReconciler) scaleUpControlPlaneInParallel(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane, controlPlane *internal.ControlPlane) (ctrl.Result, error) {
+	logger := ctrl.LoggerFrom(ctx)
+
+	provisioningMachines := controlPlane.Machines.Filter(func(machine *clusterv1.Machine) bool {
+		return machine.Status.NodeRef == nil
+	})
+	if !kcp.Status.Initialized || provisioningMachines.Len() == controlPlane.Machines.Len() {
+		logger.Info("Waiting for the control plane to be initialized before creating the remaining control plane machines in parallel")
+		return ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}, nil
+	}
+
+	// Run preflight checks to ensure that the machines already part of the control plane are stable before proceeding; if not, wait.
+	if result, err := r.preflightChecks(ctx, controlPlane, provisioningMachines.UnsortedList()...); err != nil || !result.IsZero() {
+		return result, err
+	}
+
+	missingReplicas := int(*kcp.Spec.Replicas) - controlPlane.Machines.Len()
+	logger.Info("Creating control plane machines in parallel", "count", missingReplicas)
+	for _, fd := range controlPlane.NextFailureDomainsForScaleUp(missingReplicas) {
+		bootstrapSpec := controlPlane.JoinControlPlaneConfig()
+		if err := r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, parallelJoinBootstrapAnnotations, fd); err != nil {
+			logger.Error(err, "Failed to create additional control plane Machine")
+			r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedScaleUp", "Failed to create additional control plane Machine for cluster %s/%s control plane: %v", cluster.Namespace, cluster.Name, err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Requeue the control plane, in case there are other operations to perform
+	return ctrl.Result{Requeue: true}, nil
+}
+
 func (r *KubeadmControlPlaneReconciler) scaleDownControlPlane(
 	ctx context.Context,
 	cluster *clusterv1.Cluster,
@@ -141,8 +190,14 @@ func (r *KubeadmControlPlaneReconciler) scaleDownControlPlane(
 		return ctrl.Result{}, err
 	}
 
+	// Machines are deleted because of a rollout when KCP is replacing outdated Machines, otherwise because KCP has been scaled down.
+	deletionReason := clusterv1.DeletionReasonScaleDown
+	if len(outdatedMachines) > 0 {
+		deletionReason = clusterv1.DeletionReasonRollout
+	}
+
 	logger = logger.WithValues("Machine", klog.KObj(machineToDelete))
-	if err := r.Client.Delete(ctx, machineToDelete); err != nil && !apierrors.IsNotFound(err) {
+	if err := deletion.Delete(ctx, r.Client, r.recorder, machineToDelete, deletionReason, deletion.Initiator(kubeadmControlPlaneKind, kcp)); err != nil && !apierrors.IsNotFound(err) {
 		logger.Error(err, "Failed to delete control plane machine")
 		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedScaleDown",
 			"Failed to delete control plane Machine %s for cluster %s/%s control plane: %v", machineToDelete.Name, cluster.Namespace, cluster.Name, err)
@@ -240,7 +295,7 @@ func selectMachineForScaleDown(controlPlane *internal.ControlPlane, outdatedMach
 	case controlPlane.MachineWithDeleteAnnotation(machines).Len() > 0:
 		machines = controlPlane.MachineWithDeleteAnnotation(machines)
 	case outdatedMachines.Len() > 0:
-		machines = outdatedMachines
+		return controlPlane.NextMachineForRollout(outdatedMachines)
 	}
 	return controlPlane.MachineInFailureDomainWithMostMachines(machines)
 }
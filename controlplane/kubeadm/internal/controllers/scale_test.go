@@ -235,6 +235,85 @@ func TestKubeadmControlPlaneReconciler_scaleUpControlPlane(t *testing.T) {
 			g.Expect(m).To(Equal(bm))
 		}
 	})
+	t.Run("creates all the missing control plane Machines at once with the Parallel initial provisioning strategy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ns, err := env.CreateNamespace(ctx, "test-kcp-reconciler-scaleupcontrolplane")
+		g.Expect(err).To(BeNil())
+		defer func() {
+			g.Expect(env.Delete(ctx, ns)).To(Succeed())
+		}()
+
+		cluster, kcp, genericInfrastructureMachineTemplate := createClusterWithControlPlane(ns.Name)
+		g.Expect(env.Create(ctx, genericInfrastructureMachineTemplate, client.FieldOwner("manager"))).To(Succeed())
+		kcp.UID = types.UID(util.RandomString(10))
+		kcp.Spec.InitialProvisioningStrategy = controlplanev1.ParallelInitialProvisioningStrategyType
+		setKCPHealthy(kcp)
+
+		// The first control plane machine is initialized.
+		m, _ := createMachineNodePair("test-0", cluster, kcp, true)
+		setMachineHealthy(m)
+		fmc := &fakeManagementCluster{
+			Machines: collections.FromMachines(m),
+			Workload: fakeWorkloadCluster{},
+		}
+
+		r := &KubeadmControlPlaneReconciler{
+			Client:                    env,
+			managementCluster:         fmc,
+			managementClusterUncached: fmc,
+			recorder:                  record.NewFakeRecorder(32),
+		}
+		controlPlane := &internal.ControlPlane{
+			KCP:      kcp,
+			Cluster:  cluster,
+			Machines: fmc.Machines,
+		}
+
+		// Wait for the control plane to be initialized.
+		kcp.Status.Initialized = false
+		result, err := r.scaleUpControlPlane(ctx, cluster, kcp, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}))
+
+		kcp.Status.Initialized = true
+		result, err = r.scaleUpControlPlane(ctx, cluster, kcp, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{Requeue: true}))
+
+		controlPlaneMachines := clusterv1.MachineList{}
+		g.Expect(env.GetAPIReader().List(ctx, &controlPlaneMachines, client.InNamespace(ns.Name))).To(Succeed())
+		// All the missing machines should have been created.
+		// Note: the first machine is in-memory only during the test.
+		g.Expect(controlPlaneMachines.Items).To(HaveLen(2))
+
+		// The machines wait for a join permit before running kubeadm join.
+		kubeadmConfigs := bootstrapv1.KubeadmConfigList{}
+		g.Expect(env.GetAPIReader().List(ctx, &kubeadmConfigs, client.InNamespace(ns.Name))).To(Succeed())
+		g.Expect(kubeadmConfigs.Items).To(HaveLen(2))
+		for _, config := range kubeadmConfigs.Items {
+			g.Expect(config.Annotations).To(HaveKey(bootstrapv1.WaitForJoinPermitAnnotation))
+			g.Expect(config.Annotations).To(HaveKey(bootstrapv1.ReportBootstrapProgressAnnotation))
+		}
+	})
+}
+
+func TestIsParallelInitialProvisioning(t *testing.T) {
+	g := NewWithT(t)
+
+	kcp := &controlplanev1.KubeadmControlPlane{}
+	g.Expect(isParallelInitialProvisioning(kcp)).To(BeFalse())
+
+	kcp.Spec.InitialProvisioningStrategy = controlplanev1.ParallelInitialProvisioningStrategyType
+	g.Expect(isParallelInitialProvisioning(kcp)).To(BeTrue())
+
+	// Once the desired number of machines is created, the initial provisioning is completed.
+	kcp.Status.InitialProvisioningCompleted = true
+	g.Expect(isParallelInitialProvisioning(kcp)).To(BeFalse())
+
+	// The initial provisioning doesn't restart when creating machines fails afterwards.
+	conditions.MarkFalse(kcp, controlplanev1.MachinesCreatedCondition, controlplanev1.MachineGenerationFailedReason, clusterv1.ConditionSeverityError, "")
+	g.Expect(isParallelInitialProvisioning(kcp)).To(BeFalse())
 }
 
 func TestKubeadmControlPlaneReconciler_scaleDownControlPlane_NoError(t *testing.T) {
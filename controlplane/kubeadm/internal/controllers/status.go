@@ -18,13 +18,17 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/util/progress"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -34,6 +38,7 @@ import (
 // resource status subresourcs up-to-date.
 func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, cluster *clusterv1.Cluster) error {
 	log := ctrl.LoggerFrom(ctx)
+	previous := progressState(kcp)
 
 	selector := collections.ControlPlaneSelectorForCluster(cluster.Name)
 	// Copy label selector to its status counterpart in string format.
@@ -51,6 +56,7 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, kcp *c
 		return err
 	}
 	kcp.Status.UpdatedReplicas = int32(len(controlPlane.UpToDateMachines()))
+	kcp.Status.CertificatesExpiryDate = certificatesExpiryDate(ownedMachines)
 
 	replicas := int32(len(ownedMachines))
 	desiredReplicas := *kcp.Spec.Replicas
@@ -82,6 +88,7 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, kcp *c
 
 		// This means that there was no error in generating the desired number of machine objects
 		conditions.MarkTrue(kcp, controlplanev1.MachinesCreatedCondition)
+		kcp.Status.InitialProvisioningCompleted = true
 	default:
 		// make sure last resize operation is marked as completed.
 		// NOTE: we are checking the number of machines ready so we report resize completed only when the machines
@@ -93,6 +100,7 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, kcp *c
 
 		// This means that there was no error in generating the desired number of machine objects
 		conditions.MarkTrue(kcp, controlplanev1.MachinesCreatedCondition)
+		kcp.Status.InitialProvisioningCompleted = true
 	}
 
 	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(cluster))
@@ -116,6 +124,19 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, kcp *c
 		kcp.Status.Ready = true
 	}
 
+	// Track if the rollout or scale operation in progress, if any, is making progress.
+	// NOTE: status.observedGeneration is updated only when patching the object, so the current generation is used.
+	current := progressState(kcp)
+	current.Generation = kcp.Generation
+	kcp.Status.ProgressDeadline = progress.Reconcile(r.recorder, progress.Input{
+		Object:                  kcp,
+		ProgressDeadlineSeconds: kcp.Spec.ProgressDeadlineSeconds,
+		ProgressDeadline:        kcp.Status.ProgressDeadline,
+		Previous:                previous,
+		Current:                 current,
+		Completed:               current.Replicas == desiredReplicas && current.UpdatedReplicas == desiredReplicas && current.ReadyReplicas == desiredReplicas,
+	}, time.Now())
+
 	// Surface lastRemediation data in status.
 	// LastRemediation is the remediation currently in progress, in any, or the
 	// most recent of the remediation we are keeping track on machines.
@@ -146,3 +167,42 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, kcp *c
 	}
 	return nil
 }
+
+// progressState returns the state of the rollout or scale operation of a KubeadmControlPlane, as reported in its status.
+func progressState(kcp *controlplanev1.KubeadmControlPlane) progress.State {
+	return progress.State{
+		Generation:      kcp.Status.ObservedGeneration,
+		DesiredReplicas: pointer.Int32Deref(kcp.Spec.Replicas, 0),
+		Replicas:        kcp.Status.Replicas,
+		UpdatedReplicas: kcp.Status.UpdatedReplicas,
+		ReadyReplicas:   kcp.Status.ReadyReplicas,
+	}
+}
+
+// certificatesExpiryDate returns the earliest certificates expiry date of the machines, if any.
+func certificatesExpiryDate(machines collections.Machines) *metav1.Time {
+	var earliest *metav1.Time
+	for _, m := range machines {
+		if m.Status.CertificatesExpiryDate == nil {
+			continue
+		}
+		if earliest == nil || m.Status.CertificatesExpiryDate.Before(earliest) {
+			earliest = m.Status.CertificatesExpiryDate.DeepCopy()
+		}
+	}
+	return earliest
+}
+
+// certificatesRolloutRequeueAfter returns the time until the machine with the earliest certificates expiry date
+// should be rolled out according to spec.rolloutBefore.certificatesExpiryDays, so KCP can requeue and start the
+// rollout on time even if nothing else changes; it returns zero if no rollout is planned or if it is already due.
+func certificatesRolloutRequeueAfter(kcp *controlplanev1.KubeadmControlPlane, now time.Time) time.Duration {
+	if kcp.Spec.RolloutBefore == nil || kcp.Spec.RolloutBefore.CertificatesExpiryDays == nil || kcp.Status.CertificatesExpiryDate == nil {
+		return 0
+	}
+	rolloutAt := kcp.Status.CertificatesExpiryDate.Add(-time.Duration(*kcp.Spec.RolloutBefore.CertificatesExpiryDays) * 24 * time.Hour)
+	if !now.Before(rolloutAt) {
+		return 0
+	}
+	return rolloutAt.Sub(now)
+}
@@ -19,10 +19,12 @@ package controllers
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2/klogr"
 	"k8s.io/utils/pointer"
@@ -32,6 +34,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
@@ -89,6 +92,11 @@ func TestKubeadmControlPlaneReconciler_updateStatusNoMachines(t *testing.T) {
 	g.Expect(kcp.Status.Selector).NotTo(BeEmpty())
 	g.Expect(kcp.Status.FailureMessage).To(BeNil())
 	g.Expect(kcp.Status.FailureReason).To(BeEquivalentTo(""))
+
+	// The selector reported in status, used by the scale subresource, must match the Machines created by the KubeadmControlPlane.
+	selector, err := labels.Parse(kcp.Status.Selector)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(selector.Matches(labels.Set(internal.ControlPlaneMachineLabelsForCluster(kcp, cluster.Name)))).To(BeTrue())
 }
 
 func TestKubeadmControlPlaneReconciler_updateStatusAllMachinesNotReady(t *testing.T) {
@@ -379,3 +387,69 @@ func kubeadmConfigMap() *corev1.ConfigMap {
 		},
 	}
 }
+
+func TestCertificatesExpiryDate(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	earliest := metav1.NewTime(now.Add(24 * time.Hour))
+	latest := metav1.NewTime(now.Add(48 * time.Hour))
+
+	g.Expect(certificatesExpiryDate(collections.New())).To(BeNil())
+	g.Expect(certificatesExpiryDate(collections.FromMachines(
+		&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m1"}},
+	))).To(BeNil())
+	g.Expect(certificatesExpiryDate(collections.FromMachines(
+		&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m1"}, Status: clusterv1.MachineStatus{CertificatesExpiryDate: &latest}},
+		&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m2"}, Status: clusterv1.MachineStatus{CertificatesExpiryDate: &earliest}},
+		&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m3"}},
+	))).To(Equal(&earliest))
+}
+
+func TestCertificatesRolloutRequeueAfter(t *testing.T) {
+	now := time.Now()
+	expiry := metav1.NewTime(now.Add(10 * 24 * time.Hour))
+
+	tests := []struct {
+		name       string
+		expiryDays *int32
+		expiry     *metav1.Time
+		want       time.Duration
+	}{
+		{
+			name:   "no requeue without rolloutBefore",
+			expiry: &expiry,
+			want:   0,
+		},
+		{
+			name:       "no requeue without a certificates expiry date",
+			expiryDays: pointer.Int32(7),
+			want:       0,
+		},
+		{
+			name:       "requeue when the rollout is due",
+			expiryDays: pointer.Int32(7),
+			expiry:     &expiry,
+			want:       3 * 24 * time.Hour,
+		},
+		{
+			name:       "no requeue if the rollout is already due",
+			expiryDays: pointer.Int32(14),
+			expiry:     &expiry,
+			want:       0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kcp := &controlplanev1.KubeadmControlPlane{}
+			if tt.expiryDays != nil {
+				kcp.Spec.RolloutBefore = &controlplanev1.RolloutBefore{CertificatesExpiryDays: tt.expiryDays}
+			}
+			kcp.Status.CertificatesExpiryDate = tt.expiry
+
+			g.Expect(certificatesRolloutRequeueAfter(kcp, now)).To(Equal(tt.want))
+		})
+	}
+}
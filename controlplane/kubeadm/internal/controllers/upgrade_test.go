@@ -221,6 +221,7 @@ func TestKubeadmControlPlaneReconciler_RolloutStrategy_ScaleDown(t *testing.T) {
 	r := &KubeadmControlPlaneReconciler{
 		APIReader:                 fakeClient,
 		Client:                    fakeClient,
+		recorder:                  record.NewFakeRecorder(32),
 		managementCluster:         fmc,
 		managementClusterUncached: fmc,
 	}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+const (
+	// EncryptionConfigSecretKey is the key used to store the EncryptionConfiguration in the encryption config Secret.
+	EncryptionConfigSecretKey = "encryption-config.yaml"
+
+	encryptionConfigDir         = "/etc/kubernetes/encryption"
+	encryptionConfigVolumeName  = "encryption-config"
+	encryptionProviderConfigArg = "encryption-provider-config"
+)
+
+// EncryptionConfigSecretName returns the name of the Secret storing the EncryptionConfiguration for a KubeadmControlPlane.
+func EncryptionConfigSecretName(kcpName string) string {
+	return fmt.Sprintf("%s-encryption-config", kcpName)
+}
+
+// encryptionConfigPath returns the path of the EncryptionConfiguration on control plane machines.
+// NOTE: The path includes the hash of the EncryptionConfiguration, so any change to the EncryptionConfiguration,
+// e.g. a key rotation, changes the KubeadmConfigSpec and thus triggers a rollout of the control plane machines.
+func encryptionConfigPath(kcp *controlplanev1.KubeadmControlPlane) (string, bool) {
+	if kcp.Spec.EncryptionAtRest == nil || kcp.Status.EncryptionAtRest == nil || kcp.Status.EncryptionAtRest.ConfigHash == "" {
+		return "", false
+	}
+	return fmt.Sprintf("%s/config-%s.yaml", encryptionConfigDir, kcp.Status.EncryptionAtRest.ConfigHash), true
+}
+
+// withEncryptionConfigFile adds to the KubeadmConfigSpec the file with the EncryptionConfiguration, if encryption
+// at rest is enabled for the KubeadmControlPlane.
+func withEncryptionConfigFile(kcp *controlplanev1.KubeadmControlPlane, spec *bootstrapv1.KubeadmConfigSpec) {
+	path, ok := encryptionConfigPath(kcp)
+	if !ok {
+		return
+	}
+	spec.Files = append(spec.Files, bootstrapv1.File{
+		Path:        path,
+		Owner:       "root:root",
+		Permissions: "0600",
+		ContentFrom: &bootstrapv1.FileSource{
+			Secret: &bootstrapv1.SecretFileSource{
+				Name: EncryptionConfigSecretName(kcp.Name),
+				Key:  EncryptionConfigSecretKey,
+			},
+		},
+	})
+}
+
+// APIServerWithEncryptionAtRest returns the API server configuration for the KubeadmControlPlane, configuring the API
+// server to use the EncryptionConfiguration, if encryption at rest is enabled for the KubeadmControlPlane.
+func APIServerWithEncryptionAtRest(kcp *controlplanev1.KubeadmControlPlane, apiServer bootstrapv1.APIServer) bootstrapv1.APIServer {
+	path, ok := encryptionConfigPath(kcp)
+	if !ok {
+		return apiServer
+	}
+
+	apiServer = *apiServer.DeepCopy()
+	if apiServer.ExtraArgs == nil {
+		apiServer.ExtraArgs = map[string]string{}
+	}
+	apiServer.ExtraArgs[encryptionProviderConfigArg] = path
+	for _, volume := range apiServer.ExtraVolumes {
+		if volume.Name == encryptionConfigVolumeName {
+			return apiServer
+		}
+	}
+	apiServer.ExtraVolumes = append(apiServer.ExtraVolumes, bootstrapv1.HostPathMount{
+		Name:      encryptionConfigVolumeName,
+		HostPath:  encryptionConfigDir,
+		MountPath: encryptionConfigDir,
+		ReadOnly:  true,
+		PathType:  corev1.HostPathDirectoryOrCreate,
+	})
+	return apiServer
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	kubeadmtypes "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types"
+)
+
+const (
+	// defaultEtcdClientPort is the port etcd listens on for client requests if not otherwise configured.
+	defaultEtcdClientPort = 2379
+
+	// etcdListenClientURLsArg is the etcd arg defining the URLs etcd listens on for client requests.
+	etcdListenClientURLsArg = "listen-client-urls"
+)
+
+// etcdClientEndpoint defines how to connect to the etcd members for client requests.
+type etcdClientEndpoint struct {
+	// Port is the port the etcd members listen on for client requests.
+	Port int
+
+	// Insecure is true if the etcd members serve client requests without TLS.
+	Insecure bool
+}
+
+// defaultEtcdClientEndpoint is the endpoint used by the etcd members deployed by kubeadm with the default configuration.
+var defaultEtcdClientEndpoint = etcdClientEndpoint{Port: defaultEtcdClientPort}
+
+// etcdClientEndpointGetter returns how to connect to the etcd members for client requests.
+type etcdClientEndpointGetter func(ctx context.Context) (etcdClientEndpoint, error)
+
+// getEtcdClientEndpoint returns how to connect to the etcd members for client requests, according to the
+// ClusterConfiguration stored in the kubeadm-config ConfigMap; if the ClusterConfiguration is not yet available,
+// the default endpoint is returned.
+func (w *Workload) getEtcdClientEndpoint(ctx context.Context) (etcdClientEndpoint, error) {
+	configMap := &corev1.ConfigMap{}
+	key := ctrlclient.ObjectKey{Name: kubeadmConfigKey, Namespace: metav1.NamespaceSystem}
+	if err := w.Client.Get(ctx, key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return defaultEtcdClientEndpoint, nil
+		}
+		return etcdClientEndpoint{}, errors.Wrapf(err, "failed to get %s/%s configmap from target cluster", key.Namespace, key.Name)
+	}
+
+	data, ok := configMap.Data[clusterConfigurationKey]
+	if !ok {
+		return defaultEtcdClientEndpoint, nil
+	}
+	clusterConfiguration, err := kubeadmtypes.UnmarshalClusterConfiguration(data)
+	if err != nil {
+		return etcdClientEndpoint{}, errors.Wrapf(err, "unable to decode %q in the kubeadm-config ConfigMap's from YAML", clusterConfigurationKey)
+	}
+	return etcdClientEndpointFromClusterConfiguration(clusterConfiguration)
+}
+
+// etcdClientEndpointFromClusterConfiguration returns how to connect to the etcd members for client requests,
+// according to the listen-client-urls extra arg of the local etcd in the ClusterConfiguration.
+// NOTE: The etcd client connects to the etcd members using a port-forward to the etcd Pods, and thus only URLs
+// on a loopback or unspecified address can be used; unix sockets are ignored, because they cannot be port-forwarded.
+func etcdClientEndpointFromClusterConfiguration(clusterConfiguration *bootstrapv1.ClusterConfiguration) (etcdClientEndpoint, error) {
+	if clusterConfiguration == nil || clusterConfiguration.Etcd.Local == nil {
+		return defaultEtcdClientEndpoint, nil
+	}
+	listenClientURLs, ok := clusterConfiguration.Etcd.Local.ExtraArgs[etcdListenClientURLsArg]
+	if !ok || listenClientURLs == "" {
+		return defaultEtcdClientEndpoint, nil
+	}
+
+	for _, rawURL := range strings.Split(listenClientURLs, ",") {
+		u, err := url.Parse(strings.TrimSpace(rawURL))
+		if err != nil {
+			return etcdClientEndpoint{}, errors.Wrapf(err, "failed to parse %q in the %s etcd arg", rawURL, etcdListenClientURLsArg)
+		}
+
+		switch u.Scheme {
+		case "https", "http":
+		case "unix", "unixs":
+			continue
+		default:
+			return etcdClientEndpoint{}, errors.Errorf("unsupported scheme %q for %q in the %s etcd arg", u.Scheme, rawURL, etcdListenClientURLsArg)
+		}
+
+		if !isLocalHost(u.Hostname()) {
+			continue
+		}
+
+		endpoint := etcdClientEndpoint{Port: defaultEtcdClientPort, Insecure: u.Scheme == "http"}
+		if u.Port() != "" {
+			port, err := strconv.Atoi(u.Port())
+			if err != nil {
+				return etcdClientEndpoint{}, errors.Wrapf(err, "invalid port for %q in the %s etcd arg", rawURL, etcdListenClientURLsArg)
+			}
+			endpoint.Port = port
+		}
+		return endpoint, nil
+	}
+	return etcdClientEndpoint{}, errors.Errorf("the %s etcd arg %q must contain a URL on a loopback or unspecified address to allow the KubeadmControlPlane controller to connect to etcd", etcdListenClientURLsArg, listenClientURLs)
+}
+
+// isLocalHost returns true if a port-forward to a Pod can reach the given host.
+func isLocalHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/yaml"
+)
+
+func TestEtcdClientEndpointFromClusterConfiguration(t *testing.T) {
+	tests := []struct {
+		name             string
+		listenClientURLs *string
+		external         bool
+		want             etcdClientEndpoint
+		wantErr          bool
+	}{
+		{
+			name: "default endpoint if listen-client-urls is not set",
+			want: defaultEtcdClientEndpoint,
+		},
+		{
+			name:     "default endpoint if using an external etcd",
+			external: true,
+			want:     defaultEtcdClientEndpoint,
+		},
+		{
+			name:             "custom port on a loopback address",
+			listenClientURLs: pointer.String("https://127.0.0.1:12379,https://10.0.0.1:12379"),
+			want:             etcdClientEndpoint{Port: 12379},
+		},
+		{
+			name:             "skip URLs not on a loopback or unspecified address",
+			listenClientURLs: pointer.String("https://10.0.0.1:2379, https://[::]:22379"),
+			want:             etcdClientEndpoint{Port: 22379},
+		},
+		{
+			name:             "skip unix sockets",
+			listenClientURLs: pointer.String("unixs://etcd.sock,https://localhost:32379"),
+			want:             etcdClientEndpoint{Port: 32379},
+		},
+		{
+			name:             "insecure endpoint without port",
+			listenClientURLs: pointer.String("http://127.0.0.1"),
+			want:             etcdClientEndpoint{Port: defaultEtcdClientPort, Insecure: true},
+		},
+		{
+			name:             "fails if listening only on unix sockets",
+			listenClientURLs: pointer.String("unix://etcd.sock"),
+			wantErr:          true,
+		},
+		{
+			name:             "fails if listening only on non local addresses",
+			listenClientURLs: pointer.String("https://10.0.0.1:2379"),
+			wantErr:          true,
+		},
+		{
+			name:             "fails with unsupported schemes",
+			listenClientURLs: pointer.String("tcp://127.0.0.1:2379"),
+			wantErr:          true,
+		},
+		{
+			name:             "fails with invalid ports",
+			listenClientURLs: pointer.String("https://127.0.0.1:foo"),
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterConfiguration := &bootstrapv1.ClusterConfiguration{}
+			if tt.external {
+				clusterConfiguration.Etcd.External = &bootstrapv1.ExternalEtcd{}
+			} else {
+				clusterConfiguration.Etcd.Local = &bootstrapv1.LocalEtcd{}
+				if tt.listenClientURLs != nil {
+					clusterConfiguration.Etcd.Local.ExtraArgs = map[string]string{etcdListenClientURLsArg: *tt.listenClientURLs}
+				}
+			}
+
+			got, err := etcdClientEndpointFromClusterConfiguration(clusterConfiguration)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetEtcdClientEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		objs    []client.Object
+		want    etcdClientEndpoint
+		wantErr bool
+	}{
+		{
+			name: "default endpoint if the kubeadm-config ConfigMap does not exist",
+			want: defaultEtcdClientEndpoint,
+		},
+		{
+			name: "endpoint from the ClusterConfiguration in the kubeadm-config ConfigMap",
+			objs: []client.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      kubeadmConfigKey,
+					Namespace: metav1.NamespaceSystem,
+				},
+				Data: map[string]string{
+					clusterConfigurationKey: yaml.Raw(`
+						apiVersion: kubeadm.k8s.io/v1beta2
+						kind: ClusterConfiguration
+						etcd:
+						  local:
+						    extraArgs:
+						      listen-client-urls: https://127.0.0.1:12379
+						`),
+				},
+			}},
+			want: etcdClientEndpoint{Port: 12379},
+		},
+		{
+			name: "fails if the ClusterConfiguration cannot be decoded",
+			objs: []client.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      kubeadmConfigKey,
+					Namespace: metav1.NamespaceSystem,
+				},
+				Data: map[string]string{
+					clusterConfigurationKey: "foo",
+				},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			w := &Workload{
+				Client: fake.NewClientBuilder().WithObjects(tt.objs...).Build(),
+			}
+			got, err := w.getEtcdClientEndpoint(ctx)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestEtcdClientGeneratorResolveClientEndpoint(t *testing.T) {
+	g := NewWithT(t)
+
+	calls := 0
+	generator := NewEtcdClientGenerator(nil, nil, 0, 0, func(_ context.Context) (etcdClientEndpoint, error) {
+		calls++
+		return etcdClientEndpoint{Port: 12379}, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		got, err := generator.resolveClientEndpoint(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(Equal(etcdClientEndpoint{Port: 12379}))
+	}
+	// The endpoint is resolved only once.
+	g.Expect(calls).To(Equal(1))
+
+	// The default endpoint is used if there is no getter.
+	got, err := NewEtcdClientGenerator(nil, nil, 0, 0, nil).resolveClientEndpoint(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(defaultEtcdClientEndpoint))
+}
@@ -36,6 +36,9 @@ type EtcdClientGenerator struct {
 	restConfig   *rest.Config
 	tlsConfig    *tls.Config
 	createClient clientCreator
+
+	getClientEndpoint etcdClientEndpointGetter
+	clientEndpoint    *etcdClientEndpoint
 }
 
 type clientCreator func(ctx context.Context, endpoints []string) (*etcd.Client, error)
@@ -43,20 +46,30 @@ type clientCreator func(ctx context.Context, endpoints []string) (*etcd.Client,
 var errEtcdNodeConnection = errors.New("failed to connect to etcd node")
 
 // NewEtcdClientGenerator returns a new etcdClientGenerator instance.
-func NewEtcdClientGenerator(restConfig *rest.Config, tlsConfig *tls.Config, etcdDialTimeout, etcdCallTimeout time.Duration) *EtcdClientGenerator {
-	ecg := &EtcdClientGenerator{restConfig: restConfig, tlsConfig: tlsConfig}
+// If getClientEndpoint is nil, the etcd members are expected to listen on the default port for client requests.
+func NewEtcdClientGenerator(restConfig *rest.Config, tlsConfig *tls.Config, etcdDialTimeout, etcdCallTimeout time.Duration, getClientEndpoint etcdClientEndpointGetter) *EtcdClientGenerator {
+	ecg := &EtcdClientGenerator{restConfig: restConfig, tlsConfig: tlsConfig, getClientEndpoint: getClientEndpoint}
 
 	ecg.createClient = func(ctx context.Context, endpoints []string) (*etcd.Client, error) {
+		clientEndpoint, err := ecg.resolveClientEndpoint(ctx)
+		if err != nil {
+			return nil, err
+		}
+
 		p := proxy.Proxy{
 			Kind:       "pods",
 			Namespace:  metav1.NamespaceSystem,
 			KubeConfig: ecg.restConfig,
-			Port:       2379,
+			Port:       clientEndpoint.Port,
+		}
+		clientTLSConfig := tlsConfig
+		if clientEndpoint.Insecure {
+			clientTLSConfig = nil
 		}
 		return etcd.NewClient(ctx, etcd.ClientConfiguration{
 			Endpoints:   endpoints,
 			Proxy:       p,
-			TLSConfig:   tlsConfig,
+			TLSConfig:   clientTLSConfig,
 			DialTimeout: etcdDialTimeout,
 			CallTimeout: etcdCallTimeout,
 		})
@@ -65,6 +78,23 @@ func NewEtcdClientGenerator(restConfig *rest.Config, tlsConfig *tls.Config, etcd
 	return ecg
 }
 
+// resolveClientEndpoint returns how to connect to the etcd members for client requests;
+// the endpoint is resolved only once for the lifetime of the generator.
+func (c *EtcdClientGenerator) resolveClientEndpoint(ctx context.Context) (etcdClientEndpoint, error) {
+	if c.clientEndpoint != nil {
+		return *c.clientEndpoint, nil
+	}
+	if c.getClientEndpoint == nil {
+		return defaultEtcdClientEndpoint, nil
+	}
+	clientEndpoint, err := c.getClientEndpoint(ctx)
+	if err != nil {
+		return etcdClientEndpoint{}, errors.Wrap(err, "failed to get the etcd client endpoint")
+	}
+	c.clientEndpoint = &clientEndpoint
+	return clientEndpoint, nil
+}
+
 // forFirstAvailableNode takes a list of nodes and returns a client for the first one that connects.
 func (c *EtcdClientGenerator) forFirstAvailableNode(ctx context.Context, nodeNames []string) (*etcd.Client, error) {
 	// This is an additional safeguard for avoiding this func to return nil, nil.
@@ -20,13 +20,17 @@ import (
 	"encoding/json"
 	"reflect"
 
+	"github.com/blang/semver"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/hash"
 	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 // MatchesMachineSpec returns a filter to find all machines that matches with KCP config and do not require any rollout.
@@ -53,9 +57,74 @@ func NeedsRollout(reconciliationTime, rolloutAfter *metav1.Time, rolloutBefore *
 		collections.ShouldRolloutAfter(reconciliationTime, rolloutAfter),
 		// Machines that do not match with KCP config.
 		collections.Not(MatchesMachineSpec(infraConfigs, machineConfigs, kcp)),
+		// Machines whose files populated from Secrets or ConfigMaps changed after the bootstrap data has been generated.
+		HasOutdatedFileSources(machineConfigs),
+		// Machines whose users populated from Secrets changed after the bootstrap data has been generated, e.g. rotated ssh keys.
+		HasOutdatedUserSources(machineConfigs),
 	)
 }
 
+// NeedsInPlacePatchUpgrade returns a filter to find the machines that can be upgraded in place to the KCP version,
+// i.e. the machines at an older patch version of the same minor version, which would not need a rollout if they were
+// already at the KCP version.
+func NeedsInPlacePatchUpgrade(reconciliationTime *metav1.Time, infraConfigs map[string]*unstructured.Unstructured, machineConfigs map[string]*bootstrapv1.KubeadmConfig, kcp *controlplanev1.KubeadmControlPlane) collections.Func {
+	return func(machine *clusterv1.Machine) bool {
+		if machine == nil || machine.Spec.Version == nil {
+			return false
+		}
+		if !IsPatchUpgrade(*machine.Spec.Version, kcp.Spec.Version) {
+			return false
+		}
+
+		kcpAtMachineVersion := kcp.DeepCopy()
+		kcpAtMachineVersion.Spec.Version = *machine.Spec.Version
+		return !NeedsRollout(reconciliationTime, kcp.Spec.RolloutAfter, kcp.Spec.RolloutBefore, infraConfigs, machineConfigs, kcpAtMachineVersion)(machine)
+	}
+}
+
+// IsPatchUpgrade returns true if the desired version is a newer patch version of the same minor version of current.
+func IsPatchUpgrade(current, desired string) bool {
+	currentVersion, err := semver.ParseTolerant(current)
+	if err != nil {
+		return false
+	}
+	desiredVersion, err := semver.ParseTolerant(desired)
+	if err != nil {
+		return false
+	}
+	return currentVersion.Major == desiredVersion.Major && currentVersion.Minor == desiredVersion.Minor && desiredVersion.GT(currentVersion)
+}
+
+// HasOutdatedFileSources returns a filter to find all machines whose KubeadmConfig reports that the content of the
+// files populated from Secrets or ConfigMaps changed after the bootstrap data has been generated.
+func HasOutdatedFileSources(machineConfigs map[string]*bootstrapv1.KubeadmConfig) collections.Func {
+	return func(machine *clusterv1.Machine) bool {
+		if machine == nil {
+			return false
+		}
+		machineConfig, found := machineConfigs[machine.Name]
+		if !found {
+			return false
+		}
+		return conditions.GetReason(machineConfig, bootstrapv1.FileSourcesUpToDateCondition) == bootstrapv1.FileSourcesChangedReason
+	}
+}
+
+// HasOutdatedUserSources returns a filter to find all machines whose KubeadmConfig reports that the passwords or the
+// ssh authorized keys populated from Secrets changed after the bootstrap data has been generated.
+func HasOutdatedUserSources(machineConfigs map[string]*bootstrapv1.KubeadmConfig) collections.Func {
+	return func(machine *clusterv1.Machine) bool {
+		if machine == nil {
+			return false
+		}
+		machineConfig, found := machineConfigs[machine.Name]
+		if !found {
+			return false
+		}
+		return conditions.GetReason(machineConfig, bootstrapv1.UserSourcesUpToDateCondition) == bootstrapv1.UserSourcesChangedReason
+	}
+}
+
 // MatchesTemplateClonedFrom returns a filter to find all machines that have a corresponding infrastructure machine that
 // matches a given KCP infra template.
 // Note: Differences to the labels and annotations on the infrastructure machine are not considered for matching
@@ -82,12 +151,17 @@ func MatchesTemplateClonedFrom(infraConfigs map[string]*unstructured.Unstructure
 		}
 
 		// Check if the machine's infrastructure reference has been created from the current KCP infrastructure template.
-		if clonedFromName != kcp.Spec.MachineTemplate.InfrastructureRef.Name ||
-			clonedFromGroupKind != kcp.Spec.MachineTemplate.InfrastructureRef.GroupVersionKind().GroupKind().String() {
+		if clonedFromGroupKind != kcp.Spec.MachineTemplate.InfrastructureRef.GroupVersionKind().GroupKind().String() {
 			return false
 		}
+		if clonedFromName == kcp.Spec.MachineTemplate.InfrastructureRef.Name {
+			return true
+		}
 
-		return true
+		// Otherwise, check if it has been created from a template with the same content as the current KCP infrastructure
+		// template, e.g. because the template has been re-created with an identical spec under a new name.
+		checksum := kcp.Annotations[controlplanev1.InfrastructureTemplateChecksumAnnotation]
+		return checksum != "" && infraObj.GetAnnotations()[clusterv1.TemplateClonedFromChecksumAnnotation] == checksum
 	}
 }
 
@@ -100,6 +174,12 @@ func MatchesKubeadmBootstrapConfig(machineConfigs map[string]*bootstrapv1.Kubead
 			return false
 		}
 
+		// A KubeadmConfig generated from a KubeadmConfigSpec with the same content as the current KCP KubeadmConfigSpec
+		// matches, regardless of the changes applied to it afterwards, e.g. defaulting by a newer bootstrap provider.
+		if machineConfig, found := machineConfigs[machine.Name]; found && matchKubeadmConfigSpecChecksum(machineConfig, kcp) {
+			return true
+		}
+
 		// Check if KCP and machine ClusterConfiguration matches, if not return
 		if match := matchClusterConfiguration(kcp, machine); !match {
 			return false
@@ -126,6 +206,39 @@ func MatchesKubeadmBootstrapConfig(machineConfigs map[string]*bootstrapv1.Kubead
 	}
 }
 
+// matchKubeadmConfigSpecChecksum verifies if the machine KubeadmConfig has been generated from the same inputs as the ones
+// currently defined by KCP, see KubeadmConfigSpecChecksum.
+// NOTE: KubeadmConfigs without the KubeadmConfigSpecChecksumAnnotation (machines are either old or adopted) never match,
+// so they are compared field by field as usual.
+func matchKubeadmConfigSpecChecksum(machineConfig *bootstrapv1.KubeadmConfig, kcp *controlplanev1.KubeadmControlPlane) bool {
+	machineChecksum, ok := machineConfig.GetAnnotations()[controlplanev1.KubeadmConfigSpecChecksumAnnotation]
+	if !ok || machineChecksum == "" {
+		return false
+	}
+	checksum, err := KubeadmConfigSpecChecksum(kcp)
+	if err != nil {
+		return false
+	}
+	return machineChecksum == checksum
+}
+
+// KubeadmConfigSpecChecksum returns the checksum of all the KCP inputs rendered into the KubeadmConfig of a machine,
+// i.e. the KubeadmConfigSpec, the init and join configuration overrides and the file with the EncryptionConfiguration.
+func KubeadmConfigSpecChecksum(kcp *controlplanev1.KubeadmControlPlane) (string, error) {
+	encryptionConfigFile, _ := encryptionConfigPath(kcp)
+	return hash.ComputeChecksum(struct {
+		KubeadmConfigSpec    bootstrapv1.KubeadmConfigSpec
+		InitConfigOverrides  *controlplanev1.KubeadmConfigOverrides
+		JoinConfigOverrides  *controlplanev1.KubeadmConfigOverrides
+		EncryptionConfigFile string
+	}{
+		KubeadmConfigSpec:    kcp.Spec.KubeadmConfigSpec,
+		InitConfigOverrides:  kcp.Spec.InitConfigOverrides,
+		JoinConfigOverrides:  kcp.Spec.JoinConfigOverrides,
+		EncryptionConfigFile: encryptionConfigFile,
+	})
+}
+
 // matchClusterConfiguration verifies if KCP and machine ClusterConfiguration matches.
 // NOTE: Machines that have KubeadmClusterConfigurationAnnotation will have to match with KCP ClusterConfiguration.
 // If the annotation is not present (machine is either old or adopted), we won't roll out on any possible changes
@@ -156,10 +269,24 @@ func matchClusterConfiguration(kcp *controlplanev1.KubeadmControlPlane, machine
 		kcpLocalClusterConfiguration = &bootstrapv1.ClusterConfiguration{}
 	}
 
+	// The order of the API server certificate SANs and duplicated SANs are not relevant, so changing them should not trigger a rollout.
+	machineClusterConfig = normalizeCertSANs(machineClusterConfig)
+	kcpLocalClusterConfiguration = normalizeCertSANs(kcpLocalClusterConfiguration)
+
 	// Compare and return.
 	return reflect.DeepEqual(machineClusterConfig, kcpLocalClusterConfiguration)
 }
 
+// normalizeCertSANs returns a copy of the ClusterConfiguration with sorted and deduplicated API server certificate SANs.
+func normalizeCertSANs(clusterConfiguration *bootstrapv1.ClusterConfiguration) *bootstrapv1.ClusterConfiguration {
+	if len(clusterConfiguration.APIServer.CertSANs) == 0 {
+		return clusterConfiguration
+	}
+	normalized := clusterConfiguration.DeepCopy()
+	normalized.APIServer.CertSANs = sets.List(sets.New[string](normalized.APIServer.CertSANs...))
+	return normalized
+}
+
 // matchInitOrJoinConfiguration verifies if KCP and machine InitConfiguration or JoinConfiguration matches.
 // NOTE: By extension this method takes care of detecting changes in other fields of the KubeadmConfig configuration (e.g. Files, Mounts etc.)
 func matchInitOrJoinConfiguration(machineConfig *bootstrapv1.KubeadmConfig, kcp *controlplanev1.KubeadmControlPlane) bool {
@@ -197,6 +324,9 @@ func getAdjustedKcpConfig(kcp *controlplanev1.KubeadmControlPlane, machineConfig
 	// Machine's join configuration is nil when it is the first machine in the control plane.
 	if machineConfig.Spec.JoinConfiguration == nil {
 		kcpConfig.JoinConfiguration = nil
+		withInitConfigOverrides(kcpConfig, kcp.Spec.InitConfigOverrides)
+	} else {
+		withJoinConfigOverrides(kcpConfig, kcp.Spec.JoinConfigOverrides)
 	}
 
 	// Machine's init configuration is nil when the control plane is already initialized.
@@ -204,6 +334,9 @@ func getAdjustedKcpConfig(kcp *controlplanev1.KubeadmControlPlane, machineConfig
 		kcpConfig.InitConfiguration = nil
 	}
 
+	// The KCP controller adds the file with the EncryptionConfiguration to the KubeadmConfig, if encryption at rest is enabled.
+	withEncryptionConfigFile(kcp, kcpConfig)
+
 	return kcpConfig
 }
 
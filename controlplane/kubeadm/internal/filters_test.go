@@ -27,6 +27,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestMatchClusterConfiguration(t *testing.T) {
@@ -88,6 +89,74 @@ func TestMatchClusterConfiguration(t *testing.T) {
 		}
 		g.Expect(matchClusterConfiguration(kcp, m)).To(BeFalse())
 	})
+	t.Run("Return true if only the order of the API server certificate SANs differs", func(t *testing.T) {
+		g := NewWithT(t)
+		kcp := &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+					ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+						APIServer: bootstrapv1.APIServer{
+							CertSANs: []string{"b.example.com", "a.example.com"},
+						},
+					},
+				},
+			},
+		}
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					controlplanev1.KubeadmClusterConfigurationAnnotation: "{\n  \"apiServer\": {\"certSANs\": [\"a.example.com\", \"b.example.com\"]}\n}",
+				},
+			},
+		}
+		g.Expect(matchClusterConfiguration(kcp, m)).To(BeTrue())
+		// The KCP object must not be mutated by the comparison.
+		g.Expect(kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer.CertSANs).To(Equal([]string{"b.example.com", "a.example.com"}))
+	})
+	t.Run("Return true if only duplicated API server certificate SANs differ", func(t *testing.T) {
+		g := NewWithT(t)
+		kcp := &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+					ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+						APIServer: bootstrapv1.APIServer{
+							CertSANs: []string{"b.example.com", "a.example.com", "b.example.com"},
+						},
+					},
+				},
+			},
+		}
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					controlplanev1.KubeadmClusterConfigurationAnnotation: "{\n  \"apiServer\": {\"certSANs\": [\"a.example.com\", \"b.example.com\"]}\n}",
+				},
+			},
+		}
+		g.Expect(matchClusterConfiguration(kcp, m)).To(BeTrue())
+	})
+	t.Run("Return false if the API server certificate SANs differ", func(t *testing.T) {
+		g := NewWithT(t)
+		kcp := &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+					ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+						APIServer: bootstrapv1.APIServer{
+							CertSANs: []string{"a.example.com", "c.example.com"},
+						},
+					},
+				},
+			},
+		}
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					controlplanev1.KubeadmClusterConfigurationAnnotation: "{\n  \"apiServer\": {\"certSANs\": [\"a.example.com\", \"b.example.com\"]}\n}",
+				},
+			},
+		}
+		g.Expect(matchClusterConfiguration(kcp, m)).To(BeFalse())
+	})
 	t.Run("Return true if cluster configuration is nil (special case)", func(t *testing.T) {
 		g := NewWithT(t)
 		kcp := &controlplanev1.KubeadmControlPlane{
@@ -145,6 +214,35 @@ func TestGetAdjustedKcpConfig(t *testing.T) {
 		g.Expect(kcpConfig.InitConfiguration).To(BeNil())
 		g.Expect(kcpConfig.JoinConfiguration).ToNot(BeNil())
 	})
+	t.Run("kcp config should get the overrides for the role of the machine", func(t *testing.T) {
+		g := NewWithT(t)
+		kcp := &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+					InitConfiguration: &bootstrapv1.InitConfiguration{},
+					JoinConfiguration: &bootstrapv1.JoinConfiguration{},
+				},
+				InitConfigOverrides: &controlplanev1.KubeadmConfigOverrides{
+					PreKubeadmCommands: []string{"echo init"},
+				},
+				JoinConfigOverrides: &controlplanev1.KubeadmConfigOverrides{
+					PreKubeadmCommands: []string{"echo join"},
+				},
+			},
+		}
+		initMachineConfig := &bootstrapv1.KubeadmConfig{
+			Spec: bootstrapv1.KubeadmConfigSpec{
+				InitConfiguration: &bootstrapv1.InitConfiguration{}, // first control-plane
+			},
+		}
+		joinMachineConfig := &bootstrapv1.KubeadmConfig{
+			Spec: bootstrapv1.KubeadmConfigSpec{
+				JoinConfiguration: &bootstrapv1.JoinConfiguration{}, // joining control-plane
+			},
+		}
+		g.Expect(getAdjustedKcpConfig(kcp, initMachineConfig).PreKubeadmCommands).To(Equal([]string{"echo init"}))
+		g.Expect(getAdjustedKcpConfig(kcp, joinMachineConfig).PreKubeadmCommands).To(Equal([]string{"echo join"}))
+	})
 }
 
 func TestCleanupConfigFields(t *testing.T) {
@@ -579,6 +677,64 @@ func TestMatchInitOrJoinConfiguration(t *testing.T) {
 }
 
 func TestMatchesKubeadmBootstrapConfig(t *testing.T) {
+	t.Run("returns true if the KubeadmConfig has been generated from a KubeadmConfigSpec with the same checksum", func(t *testing.T) {
+		g := NewWithT(t)
+		kcp := &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+					ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+						ClusterName: "foo",
+					},
+					JoinConfiguration: &bootstrapv1.JoinConfiguration{
+						NodeRegistration: bootstrapv1.NodeRegistrationOptions{Name: "A new name"},
+					},
+				},
+			},
+		}
+		checksum, err := KubeadmConfigSpecChecksum(kcp)
+		g.Expect(err).ToNot(HaveOccurred())
+		m := &clusterv1.Machine{
+			Spec: clusterv1.MachineSpec{
+				Bootstrap: clusterv1.Bootstrap{
+					ConfigRef: &corev1.ObjectReference{Kind: "KubeadmConfig", Name: "test"},
+				},
+			},
+		}
+		// The KubeadmConfig has been changed after being generated, e.g. by defaulting.
+		machineConfigs := map[string]*bootstrapv1.KubeadmConfig{
+			m.Name: {
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{controlplanev1.KubeadmConfigSpecChecksumAnnotation: checksum},
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					JoinConfiguration: &bootstrapv1.JoinConfiguration{
+						NodeRegistration: bootstrapv1.NodeRegistrationOptions{Name: "A new name", IgnorePreflightErrors: []string{"all"}},
+					},
+				},
+			},
+		}
+		f := MatchesKubeadmBootstrapConfig(machineConfigs, kcp)
+		g.Expect(f(m)).To(BeTrue())
+
+		// The checksum doesn't match anymore after the join configuration overrides of KCP change.
+		kcp.Spec.JoinConfigOverrides = &controlplanev1.KubeadmConfigOverrides{PreKubeadmCommands: []string{"echo join"}}
+		f = MatchesKubeadmBootstrapConfig(machineConfigs, kcp)
+		g.Expect(f(m)).To(BeFalse())
+		kcp.Spec.JoinConfigOverrides = nil
+
+		// The checksum doesn't match anymore after the EncryptionConfiguration of KCP changes.
+		kcp.Spec.EncryptionAtRest = &controlplanev1.EncryptionAtRest{}
+		kcp.Status.EncryptionAtRest = &controlplanev1.EncryptionAtRestStatus{ConfigHash: "hash"}
+		f = MatchesKubeadmBootstrapConfig(machineConfigs, kcp)
+		g.Expect(f(m)).To(BeFalse())
+		kcp.Spec.EncryptionAtRest = nil
+		kcp.Status.EncryptionAtRest = nil
+
+		// The checksum doesn't match anymore after the KubeadmConfigSpec of KCP changes.
+		kcp.Spec.KubeadmConfigSpec.JoinConfiguration.NodeRegistration.Name = "Another name"
+		f = MatchesKubeadmBootstrapConfig(machineConfigs, kcp)
+		g.Expect(f(m)).To(BeFalse())
+	})
 	t.Run("returns true if ClusterConfiguration is equal", func(t *testing.T) {
 		g := NewWithT(t)
 		kcp := &controlplanev1.KubeadmControlPlane{
@@ -971,6 +1127,113 @@ func TestMatchesKubeadmBootstrapConfig(t *testing.T) {
 	})
 }
 
+func TestHasOutdatedFileSources(t *testing.T) {
+	g := NewWithT(t)
+
+	upToDate := &bootstrapv1.KubeadmConfig{}
+	conditions.MarkTrue(upToDate, bootstrapv1.FileSourcesUpToDateCondition)
+	changed := &bootstrapv1.KubeadmConfig{}
+	conditions.MarkFalse(changed, bootstrapv1.FileSourcesUpToDateCondition, bootstrapv1.FileSourcesChangedReason, clusterv1.ConditionSeverityWarning, "")
+	unavailable := &bootstrapv1.KubeadmConfig{}
+	conditions.MarkFalse(unavailable, bootstrapv1.FileSourcesUpToDateCondition, bootstrapv1.FileSourcesUnavailableReason, clusterv1.ConditionSeverityWarning, "")
+
+	machineConfigs := map[string]*bootstrapv1.KubeadmConfig{
+		"no-file-sources": {},
+		"up-to-date":      upToDate,
+		"changed":         changed,
+		"unavailable":     unavailable,
+	}
+	f := HasOutdatedFileSources(machineConfigs)
+
+	g.Expect(f(nil)).To(BeFalse())
+	g.Expect(f(&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "missing"}})).To(BeFalse())
+	g.Expect(f(&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "no-file-sources"}})).To(BeFalse())
+	g.Expect(f(&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "up-to-date"}})).To(BeFalse())
+	g.Expect(f(&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "changed"}})).To(BeTrue())
+	// Sources that cannot be retrieved should not trigger a rollout, given that new machines would fail to bootstrap.
+	g.Expect(f(&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "unavailable"}})).To(BeFalse())
+}
+
+func TestHasOutdatedUserSources(t *testing.T) {
+	g := NewWithT(t)
+
+	upToDate := &bootstrapv1.KubeadmConfig{}
+	conditions.MarkTrue(upToDate, bootstrapv1.UserSourcesUpToDateCondition)
+	changed := &bootstrapv1.KubeadmConfig{}
+	conditions.MarkFalse(changed, bootstrapv1.UserSourcesUpToDateCondition, bootstrapv1.UserSourcesChangedReason, clusterv1.ConditionSeverityWarning, "")
+	unavailable := &bootstrapv1.KubeadmConfig{}
+	conditions.MarkFalse(unavailable, bootstrapv1.UserSourcesUpToDateCondition, bootstrapv1.UserSourcesUnavailableReason, clusterv1.ConditionSeverityWarning, "")
+
+	machineConfigs := map[string]*bootstrapv1.KubeadmConfig{
+		"no-user-sources": {},
+		"up-to-date":      upToDate,
+		"changed":         changed,
+		"unavailable":     unavailable,
+	}
+	f := HasOutdatedUserSources(machineConfigs)
+
+	g.Expect(f(nil)).To(BeFalse())
+	g.Expect(f(&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "missing"}})).To(BeFalse())
+	g.Expect(f(&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "no-user-sources"}})).To(BeFalse())
+	g.Expect(f(&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "up-to-date"}})).To(BeFalse())
+	g.Expect(f(&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "changed"}})).To(BeTrue())
+	g.Expect(f(&clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "unavailable"}})).To(BeFalse())
+}
+
+func TestNeedsInPlacePatchUpgrade(t *testing.T) {
+	g := NewWithT(t)
+
+	kcp := &controlplanev1.KubeadmControlPlane{
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Version: "v1.26.3",
+		},
+	}
+	changed := &bootstrapv1.KubeadmConfig{}
+	conditions.MarkFalse(changed, bootstrapv1.FileSourcesUpToDateCondition, bootstrapv1.FileSourcesChangedReason, clusterv1.ConditionSeverityWarning, "")
+	machineConfigs := map[string]*bootstrapv1.KubeadmConfig{
+		"changed": changed,
+	}
+	newMachine := func(name, version string) *clusterv1.Machine {
+		m := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if version != "" {
+			m.Spec.Version = &version
+		}
+		return m
+	}
+	f := NeedsInPlacePatchUpgrade(nil, nil, machineConfigs, kcp)
+
+	g.Expect(f(nil)).To(BeFalse())
+	g.Expect(f(newMachine("no-version", ""))).To(BeFalse())
+	g.Expect(f(newMachine("patch", "v1.26.1"))).To(BeTrue())
+	g.Expect(f(newMachine("up-to-date", "v1.26.3"))).To(BeFalse())
+	g.Expect(f(newMachine("minor", "v1.25.3"))).To(BeFalse())
+	// Machines needing a rollout for other reasons than the version must be replaced.
+	g.Expect(f(newMachine("changed", "v1.26.1"))).To(BeFalse())
+}
+
+func TestIsPatchUpgrade(t *testing.T) {
+	tests := []struct {
+		current string
+		desired string
+		expect  bool
+	}{
+		{current: "v1.26.1", desired: "v1.26.3", expect: true},
+		{current: "1.26.1", desired: "v1.26.3", expect: true},
+		{current: "v1.26.3", desired: "v1.26.3", expect: false},
+		{current: "v1.26.3", desired: "v1.26.1", expect: false},
+		{current: "v1.25.3", desired: "v1.26.3", expect: false},
+		{current: "v1.26.1", desired: "v2.26.3", expect: false},
+		{current: "invalid", desired: "v1.26.3", expect: false},
+		{current: "v1.26.1", desired: "invalid", expect: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.current+" to "+tt.desired, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsPatchUpgrade(tt.current, tt.desired)).To(Equal(tt.expect))
+		})
+	}
+}
+
 func TestMatchesTemplateClonedFrom(t *testing.T) {
 	t.Run("nil machine returns false", func(t *testing.T) {
 		g := NewWithT(t)
@@ -1097,6 +1360,9 @@ func TestMatchesTemplateClonedFrom_WithClonedFromAnnotations(t *testing.T) {
 	kcp := &controlplanev1.KubeadmControlPlane{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "default",
+			Annotations: map[string]string{
+				controlplanev1.InfrastructureTemplateChecksumAnnotation: "checksum",
+			},
 		},
 		Spec: controlplanev1.KubeadmControlPlaneSpec{
 			MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
@@ -1153,6 +1419,33 @@ func TestMatchesTemplateClonedFrom_WithClonedFromAnnotations(t *testing.T) {
 			},
 			expectMatch: true,
 		},
+		{
+			name: "returns true if TemplateClonedFromNameAnnotation doesn't match but TemplateClonedFromChecksumAnnotation does",
+			annotations: map[string]interface{}{
+				clusterv1.TemplateClonedFromNameAnnotation:      "infra-foo-old",
+				clusterv1.TemplateClonedFromGroupKindAnnotation: "GenericMachineTemplate.generic.io",
+				clusterv1.TemplateClonedFromChecksumAnnotation:  "checksum",
+			},
+			expectMatch: true,
+		},
+		{
+			name: "returns false if neither TemplateClonedFromNameAnnotation nor TemplateClonedFromChecksumAnnotation match",
+			annotations: map[string]interface{}{
+				clusterv1.TemplateClonedFromNameAnnotation:      "infra-foo-old",
+				clusterv1.TemplateClonedFromGroupKindAnnotation: "GenericMachineTemplate.generic.io",
+				clusterv1.TemplateClonedFromChecksumAnnotation:  "another-checksum",
+			},
+			expectMatch: false,
+		},
+		{
+			name: "returns false if TemplateClonedFromChecksumAnnotation matches but TemplateClonedFromGroupKindAnnotation doesn't",
+			annotations: map[string]interface{}{
+				clusterv1.TemplateClonedFromNameAnnotation:      "infra-foo-old",
+				clusterv1.TemplateClonedFromGroupKindAnnotation: "AnotherMachineTemplate.generic.io",
+				clusterv1.TemplateClonedFromChecksumAnnotation:  "checksum",
+			},
+			expectMatch: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+// withInitConfigOverrides applies to the KubeadmConfigSpec the overrides defined in the KubeadmControlPlane for
+// the control plane machine initializing the cluster.
+func withInitConfigOverrides(spec *bootstrapv1.KubeadmConfigSpec, overrides *controlplanev1.KubeadmConfigOverrides) {
+	if overrides == nil {
+		return
+	}
+	withConfigOverrides(spec, overrides)
+	if len(overrides.KubeletExtraArgs) == 0 && overrides.Patches == nil {
+		return
+	}
+	if spec.InitConfiguration == nil {
+		spec.InitConfiguration = &bootstrapv1.InitConfiguration{}
+	}
+	withNodeOverrides(&spec.InitConfiguration.NodeRegistration, &spec.InitConfiguration.Patches, overrides)
+}
+
+// withJoinConfigOverrides applies to the KubeadmConfigSpec the overrides defined in the KubeadmControlPlane for
+// the control plane machines joining the cluster.
+func withJoinConfigOverrides(spec *bootstrapv1.KubeadmConfigSpec, overrides *controlplanev1.KubeadmConfigOverrides) {
+	if overrides == nil {
+		return
+	}
+	withConfigOverrides(spec, overrides)
+	if len(overrides.KubeletExtraArgs) == 0 && overrides.Patches == nil {
+		return
+	}
+	if spec.JoinConfiguration == nil {
+		spec.JoinConfiguration = &bootstrapv1.JoinConfiguration{}
+	}
+	withNodeOverrides(&spec.JoinConfiguration.NodeRegistration, &spec.JoinConfiguration.Patches, overrides)
+}
+
+// withConfigOverrides applies the files and the commands of the overrides to the KubeadmConfigSpec.
+// NOTE: Files in the overrides replace the files with the same path, while commands are appended.
+func withConfigOverrides(spec *bootstrapv1.KubeadmConfigSpec, overrides *controlplanev1.KubeadmConfigOverrides) {
+	for _, file := range overrides.Files {
+		replaced := false
+		for i := range spec.Files {
+			if spec.Files[i].Path == file.Path {
+				spec.Files[i] = *file.DeepCopy()
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			spec.Files = append(spec.Files, *file.DeepCopy())
+		}
+	}
+	spec.PreKubeadmCommands = append(spec.PreKubeadmCommands, overrides.PreKubeadmCommands...)
+	spec.PostKubeadmCommands = append(spec.PostKubeadmCommands, overrides.PostKubeadmCommands...)
+}
+
+// withNodeOverrides applies the kubelet extra args and the patches of the overrides to the node registration
+// options and the patches of an init or a join configuration.
+// NOTE: Kubelet extra args in the overrides replace the args with the same name, while patches are replaced.
+func withNodeOverrides(nodeRegistration *bootstrapv1.NodeRegistrationOptions, patches **bootstrapv1.Patches, overrides *controlplanev1.KubeadmConfigOverrides) {
+	if len(overrides.KubeletExtraArgs) > 0 && nodeRegistration.KubeletExtraArgs == nil {
+		nodeRegistration.KubeletExtraArgs = map[string]string{}
+	}
+	for k, v := range overrides.KubeletExtraArgs {
+		nodeRegistration.KubeletExtraArgs[k] = v
+	}
+	if overrides.Patches != nil {
+		*patches = overrides.Patches.DeepCopy()
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+func TestControlPlaneConfigOverrides(t *testing.T) {
+	kcp := &controlplanev1.KubeadmControlPlane{
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				InitConfiguration: &bootstrapv1.InitConfiguration{
+					NodeRegistration: bootstrapv1.NodeRegistrationOptions{
+						KubeletExtraArgs: map[string]string{"v": "2", "node-labels": "shared"},
+					},
+				},
+				JoinConfiguration: &bootstrapv1.JoinConfiguration{
+					Patches: &bootstrapv1.Patches{Directory: "/etc/kubernetes/patches"},
+				},
+				Files: []bootstrapv1.File{
+					{Path: "/etc/kubernetes/shared.yaml", Content: "shared"},
+					{Path: "/etc/kubernetes/overridden.yaml", Content: "shared"},
+				},
+				PreKubeadmCommands:  []string{"echo pre"},
+				PostKubeadmCommands: []string{"echo post"},
+			},
+			InitConfigOverrides: &controlplanev1.KubeadmConfigOverrides{
+				Files: []bootstrapv1.File{
+					{Path: "/etc/kubernetes/overridden.yaml", Content: "init"},
+					{Path: "/etc/kubernetes/init-only.yaml", Content: "init"},
+				},
+				PreKubeadmCommands: []string{"echo pre-init"},
+				KubeletExtraArgs:   map[string]string{"node-labels": "init"},
+			},
+			JoinConfigOverrides: &controlplanev1.KubeadmConfigOverrides{
+				PostKubeadmCommands: []string{"echo post-join"},
+				KubeletExtraArgs:    map[string]string{"node-labels": "join"},
+				Patches:             &bootstrapv1.Patches{Directory: "/etc/kubernetes/join-patches"},
+			},
+		},
+	}
+	controlPlane := &ControlPlane{KCP: kcp}
+
+	t.Run("the initial control plane config gets the init overrides", func(t *testing.T) {
+		g := NewWithT(t)
+
+		spec := controlPlane.InitialControlPlaneConfig()
+		g.Expect(spec.JoinConfiguration).To(BeNil())
+		g.Expect(spec.Files).To(Equal([]bootstrapv1.File{
+			{Path: "/etc/kubernetes/shared.yaml", Content: "shared"},
+			{Path: "/etc/kubernetes/overridden.yaml", Content: "init"},
+			{Path: "/etc/kubernetes/init-only.yaml", Content: "init"},
+		}))
+		g.Expect(spec.PreKubeadmCommands).To(Equal([]string{"echo pre", "echo pre-init"}))
+		g.Expect(spec.PostKubeadmCommands).To(Equal([]string{"echo post"}))
+		g.Expect(spec.InitConfiguration.NodeRegistration.KubeletExtraArgs).To(Equal(map[string]string{"v": "2", "node-labels": "init"}))
+		g.Expect(spec.InitConfiguration.Patches).To(BeNil())
+	})
+
+	t.Run("the join control plane config gets the join overrides", func(t *testing.T) {
+		g := NewWithT(t)
+
+		spec := controlPlane.JoinControlPlaneConfig()
+		g.Expect(spec.InitConfiguration).To(BeNil())
+		g.Expect(spec.Files).To(Equal(kcp.Spec.KubeadmConfigSpec.Files))
+		g.Expect(spec.PreKubeadmCommands).To(Equal([]string{"echo pre"}))
+		g.Expect(spec.PostKubeadmCommands).To(Equal([]string{"echo post", "echo post-join"}))
+		g.Expect(spec.JoinConfiguration.NodeRegistration.KubeletExtraArgs).To(Equal(map[string]string{"node-labels": "join"}))
+		g.Expect(spec.JoinConfiguration.Patches).To(Equal(&bootstrapv1.Patches{Directory: "/etc/kubernetes/join-patches"}))
+	})
+
+	t.Run("the overrides do not change the KubeadmControlPlane", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(kcp.Spec.KubeadmConfigSpec.Files[1].Content).To(Equal("shared"))
+		g.Expect(kcp.Spec.KubeadmConfigSpec.PreKubeadmCommands).To(Equal([]string{"echo pre"}))
+		g.Expect(kcp.Spec.KubeadmConfigSpec.PostKubeadmCommands).To(Equal([]string{"echo post"}))
+		g.Expect(kcp.Spec.KubeadmConfigSpec.InitConfiguration.NodeRegistration.KubeletExtraArgs).To(Equal(map[string]string{"v": "2", "node-labels": "shared"}))
+		g.Expect(kcp.Spec.KubeadmConfigSpec.JoinConfiguration.Patches).To(Equal(&bootstrapv1.Patches{Directory: "/etc/kubernetes/patches"}))
+	})
+
+	t.Run("the overrides create the init or join configuration if missing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		spec := &bootstrapv1.KubeadmConfigSpec{}
+		withJoinConfigOverrides(spec, kcp.Spec.JoinConfigOverrides)
+		g.Expect(spec.JoinConfiguration).ToNot(BeNil())
+		g.Expect(spec.JoinConfiguration.NodeRegistration.KubeletExtraArgs).To(Equal(map[string]string{"node-labels": "join"}))
+	})
+}
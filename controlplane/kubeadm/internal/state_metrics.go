@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/metrics/state"
+)
+
+// StateMetricsResource returns the Resource exporting state metrics for KubeadmControlPlanes.
+func StateMetricsResource() state.Resource {
+	return state.Resource{
+		Name:    "kubeadmcontrolplane",
+		NewList: func() client.ObjectList { return &controlplanev1.KubeadmControlPlaneList{} },
+		Gauges: []state.Gauge{
+			{
+				Name:  "spec_replicas",
+				Help:  "The number of desired machines for a kubeadmcontrolplane.",
+				Value: state.Int32PtrValue(func(kcp *controlplanev1.KubeadmControlPlane) *int32 { return kcp.Spec.Replicas }),
+			},
+			{
+				Name:  "status_replicas",
+				Help:  "The number of replicas per kubeadmcontrolplane.",
+				Value: state.Int32Value(func(kcp *controlplanev1.KubeadmControlPlane) int32 { return kcp.Status.Replicas }),
+			},
+			{
+				Name:  "status_replicas_ready",
+				Help:  "The number of ready replicas per kubeadmcontrolplane.",
+				Value: state.Int32Value(func(kcp *controlplanev1.KubeadmControlPlane) int32 { return kcp.Status.ReadyReplicas }),
+			},
+			{
+				Name:  "status_replicas_unavailable",
+				Help:  "The number of unavailable replicas per kubeadmcontrolplane.",
+				Value: state.Int32Value(func(kcp *controlplanev1.KubeadmControlPlane) int32 { return kcp.Status.UnavailableReplicas }),
+			},
+			{
+				Name:  "status_replicas_updated",
+				Help:  "The number of updated replicas per kubeadmcontrolplane.",
+				Value: state.Int32Value(func(kcp *controlplanev1.KubeadmControlPlane) int32 { return kcp.Status.UpdatedReplicas }),
+			},
+			{
+				Name:  "status_certificates_expiry_timestamp_seconds",
+				Help:  "The earliest certificates expiry date of the machines of a kubeadmcontrolplane, in unix seconds.",
+				Value: state.TimePtrValue(func(kcp *controlplanev1.KubeadmControlPlane) *metav1.Time { return kcp.Status.CertificatesExpiryDate }),
+			},
+		},
+	}
+}
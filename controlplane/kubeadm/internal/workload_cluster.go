@@ -120,6 +120,25 @@ type WorkloadCluster interface {
 	RemoveNodeFromKubeadmConfigMap(ctx context.Context, nodeName string, version semver.Version) error
 	ForwardEtcdLeadership(ctx context.Context, machine *clusterv1.Machine, leaderCandidate *clusterv1.Machine) error
 	AllowBootstrapTokensToGetNodes(ctx context.Context) error
+	PermitJoin(ctx context.Context, configName string) error
+
+	// Etcd certificates rotation tasks.
+	GetEtcdCertificatesRotationPod(ctx context.Context, nodeName string) (*corev1.Pod, error)
+	CreateEtcdCertificatesRotationPod(ctx context.Context, nodeName, image string) error
+	DeleteEtcdCertificatesRotationPod(ctx context.Context, nodeName string) error
+
+	// Host cleanup tasks.
+	GetHostCleanupPod(ctx context.Context, nodeName string) (*corev1.Pod, error)
+	CreateHostCleanupPod(ctx context.Context, nodeName string, mode controlplanev1.HostCleanupMode, image string) error
+	DeleteHostCleanupPod(ctx context.Context, nodeName string) error
+
+	// In-place upgrade tasks.
+	GetInPlaceUpgradePod(ctx context.Context, nodeName string) (*corev1.Pod, error)
+	CreateInPlaceUpgradePod(ctx context.Context, nodeName, version, binariesURL, image string) error
+	DeleteInPlaceUpgradePod(ctx context.Context, nodeName string) error
+
+	// Encryption at rest tasks.
+	MigrateEncryptedResources(ctx context.Context, resources []string) error
 
 	// State recovery tasks.
 	ReconcileEtcdMembers(ctx context.Context, nodeNames []string, version semver.Version) ([]string, error)
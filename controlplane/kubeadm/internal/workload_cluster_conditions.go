@@ -76,6 +76,8 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 		clusterID *uint64
 		// members is used to store the list of etcd members and compare with all the other nodes in the cluster.
 		members []*etcd.Member
+		// observed is used to store the machines whose etcd member has been observed.
+		observed []*clusterv1.Machine
 	)
 
 	for _, node := range controlPlaneNodes.Items {
@@ -103,10 +105,18 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 			continue
 		}
 
-		currentMembers, err := w.getCurrentEtcdMembers(ctx, machine, node.Name)
+		currentMembers, leaderID, err := w.getCurrentEtcdMembers(ctx, machine, node.Name)
 		if err != nil {
 			continue
 		}
+		observed = append(observed, machine)
+
+		// Keep track of the etcd leader, so it can be used e.g. to define the rollout order.
+		for _, member := range currentMembers {
+			if member.ID == leaderID {
+				controlPlane.etcdLeaderNodeName = member.Name
+			}
+		}
 
 		// Check if the list of members IDs reported is the same as all other members.
 		// NOTE: the first member reporting this information is the baseline for this information.
@@ -150,6 +160,11 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 		conditions.MarkTrue(machine, controlplanev1.MachineEtcdMemberHealthyCondition)
 	}
 
+	// Track the observations once the conditions have been set.
+	for _, machine := range observed {
+		conditions.MarkObserved(machine, controlplanev1.MachineEtcdMemberHealthyCondition)
+	}
+
 	// Make sure that the list of etcd members and machines is consistent.
 	kcpErrors = compareMachinesAndMembers(controlPlane, members, kcpErrors)
 
@@ -165,19 +180,19 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 	})
 }
 
-func (w *Workload) getCurrentEtcdMembers(ctx context.Context, machine *clusterv1.Machine, nodeName string) ([]*etcd.Member, error) {
+func (w *Workload) getCurrentEtcdMembers(ctx context.Context, machine *clusterv1.Machine, nodeName string) ([]*etcd.Member, uint64, error) {
 	// Create the etcd Client for the etcd Pod scheduled on the Node
 	etcdClient, err := w.etcdClientGenerator.forFirstAvailableNode(ctx, []string{nodeName})
 	if err != nil {
 		conditions.MarkUnknown(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberInspectionFailedReason, "Failed to connect to the etcd pod on the %s node: %s", nodeName, err)
-		return nil, errors.Wrapf(err, "failed to get current etcd members: failed to connect to the etcd pod on the %s node", nodeName)
+		return nil, 0, errors.Wrapf(err, "failed to get current etcd members: failed to connect to the etcd pod on the %s node", nodeName)
 	}
 	defer etcdClient.Close()
 
 	// While creating a new client, forFirstAvailableNode retrieves the status for the endpoint; check if the endpoint has errors.
 	if len(etcdClient.Errors) > 0 {
 		conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "Etcd member status reports errors: %s", strings.Join(etcdClient.Errors, ", "))
-		return nil, errors.Errorf("failed to get current etcd members: etcd member status reports errors: %s", strings.Join(etcdClient.Errors, ", "))
+		return nil, 0, errors.Errorf("failed to get current etcd members: etcd member status reports errors: %s", strings.Join(etcdClient.Errors, ", "))
 	}
 
 	// Gets the list etcd members known by this member.
@@ -186,10 +201,10 @@ func (w *Workload) getCurrentEtcdMembers(ctx context.Context, machine *clusterv1
 		// NB. We should never be in here, given that we just received answer to the etcd calls included in forFirstAvailableNode;
 		// however, we are considering the calls to Members a signal of etcd not being stable.
 		conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "Failed get answer from the etcd member on the %s node", nodeName)
-		return nil, errors.Errorf("failed to get current etcd members: failed get answer from the etcd member on the %s node", nodeName)
+		return nil, 0, errors.Errorf("failed to get current etcd members: failed get answer from the etcd member on the %s node", nodeName)
 	}
 
-	return currentMembers, nil
+	return currentMembers, etcdClient.LeaderID, nil
 }
 
 func compareMachinesAndMembers(controlPlane *ControlPlane, members []*etcd.Member, kcpErrors []string) []string {
@@ -44,6 +44,7 @@ import (
 const (
 	corefileKey            = "Corefile"
 	corefileBackupKey      = "Corefile-backup"
+	corefileBaseKey        = "Corefile-base"
 	coreDNSKey             = "coredns"
 	coreDNSVolumeKey       = "config-volume"
 	coreDNSClusterRoleName = "system:coredns"
@@ -92,6 +93,9 @@ type coreDNSInfo struct {
 	Corefile   string
 	Deployment *appsv1.Deployment
 
+	// BaseCorefile is the Corefile without the CorefileOverrides; it is empty if no overrides have been applied.
+	BaseCorefile string
+
 	FromImageTag string
 	ToImageTag   string
 
@@ -134,9 +138,10 @@ func (w *Workload) UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.Kubead
 		return err
 	}
 
-	// Return early if the from/to image is the same.
+	// Return early if the from/to image is the same, after applying the Corefile overrides
+	// which can change independently of the CoreDNS version.
 	if info.FromImage == info.ToImage {
-		return nil
+		return w.updateCoreDNSCorefileOverrides(ctx, info, kcp.Spec.CorefileOverrides)
 	}
 
 	// Validate the image tag.
@@ -148,7 +153,7 @@ func (w *Workload) UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.Kubead
 	if err := w.updateCoreDNSImageInfoInKubeadmConfigMap(ctx, &clusterConfig.DNS, version); err != nil {
 		return err
 	}
-	if err := w.updateCoreDNSCorefile(ctx, info); err != nil {
+	if err := w.updateCoreDNSCorefile(ctx, info, kcp.Spec.CorefileOverrides); err != nil {
 		return err
 	}
 
@@ -240,6 +245,7 @@ func (w *Workload) getCoreDNSInfo(ctx context.Context, clusterConfig *bootstrapv
 
 	return &coreDNSInfo{
 		Corefile:               corefile,
+		BaseCorefile:           cm.Data[corefileBaseKey],
 		Deployment:             deployment,
 		CurrentMajorMinorPatch: currentMajorMinorPatch.String(),
 		TargetMajorMinorPatch:  targetMajorMinorPatch.String(),
@@ -358,24 +364,34 @@ func generateClusterRolePolicies(policyRules []rbacv1.PolicyRule) map[string]map
 // updateCoreDNSCorefile migrates the coredns corefile if there is an increase
 // in version number. It also creates a corefile backup and patches the
 // deployment to point to the backup corefile before migrating.
-func (w *Workload) updateCoreDNSCorefile(ctx context.Context, info *coreDNSInfo) error {
+// The Corefile without overrides is migrated, and the overrides are applied
+// on top of the migrated Corefile afterwards.
+func (w *Workload) updateCoreDNSCorefile(ctx context.Context, info *coreDNSInfo, overrides *controlplanev1.CorefileOverrides) error {
 	// Run the CoreDNS migration tool first because if it cannot migrate the
 	// corefile, then there's no point in continuing further.
-	updatedCorefile, err := w.CoreDNSMigrator.Migrate(info.CurrentMajorMinorPatch, info.TargetMajorMinorPatch, info.Corefile, false)
+	migratedCorefile, err := w.CoreDNSMigrator.Migrate(info.CurrentMajorMinorPatch, info.TargetMajorMinorPatch, info.baseCorefile(), false)
 	if err != nil {
 		return errors.Wrap(err, "unable to migrate CoreDNS corefile")
 	}
+	updatedCorefile, err := applyCorefileOverrides(migratedCorefile, overrides)
+	if err != nil {
+		return err
+	}
 
 	// First we backup the Corefile by backing it up.
+	data := map[string]string{
+		corefileKey:       info.Corefile,
+		corefileBackupKey: info.Corefile,
+	}
+	if info.BaseCorefile != "" {
+		data[corefileBaseKey] = info.BaseCorefile
+	}
 	if err := w.Client.Update(ctx, &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      coreDNSKey,
 			Namespace: metav1.NamespaceSystem,
 		},
-		Data: map[string]string{
-			corefileKey:       info.Corefile,
-			corefileBackupKey: info.Corefile,
-		},
+		Data: data,
 	}); err != nil {
 		return errors.Wrap(err, "unable to update CoreDNS config map with backup Corefile")
 	}
@@ -391,15 +407,19 @@ func (w *Workload) updateCoreDNSCorefile(ctx context.Context, info *coreDNSInfo)
 		return err
 	}
 
+	data = map[string]string{
+		corefileKey:       updatedCorefile,
+		corefileBackupKey: info.Corefile,
+	}
+	if overrides != nil {
+		data[corefileBaseKey] = migratedCorefile
+	}
 	if err := w.Client.Update(ctx, &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      coreDNSKey,
 			Namespace: metav1.NamespaceSystem,
 		},
-		Data: map[string]string{
-			corefileKey:       updatedCorefile,
-			corefileBackupKey: info.Corefile,
-		},
+		Data: data,
 	}); err != nil {
 		return errors.Wrap(err, "unable to update CoreDNS config map")
 	}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/coredns/corefile-migration/migration/corefile"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+const (
+	forwardPluginName = "forward"
+)
+
+// baseCorefile returns the Corefile without the CorefileOverrides.
+func (i *coreDNSInfo) baseCorefile() string {
+	if i.BaseCorefile != "" {
+		return i.BaseCorefile
+	}
+	return i.Corefile
+}
+
+// updateCoreDNSCorefileOverrides applies the CorefileOverrides to the CoreDNS Corefile when CoreDNS is not being upgraded.
+// The Corefile without overrides is preserved in the Corefile-base key, so the overrides can be re-applied on top of the
+// migrated Corefile during CoreDNS upgrades, and the original Corefile can be restored when the overrides are removed.
+// NOTE: CoreDNS picks up the changes to the Corefile if the reload plugin is enabled, like in the Corefile generated by kubeadm.
+func (w *Workload) updateCoreDNSCorefileOverrides(ctx context.Context, info *coreDNSInfo, overrides *controlplanev1.CorefileOverrides) error {
+	// Nothing to do if overrides have never been applied.
+	if overrides == nil && info.BaseCorefile == "" {
+		return nil
+	}
+
+	updatedCorefile, err := applyCorefileOverrides(info.baseCorefile(), overrides)
+	if err != nil {
+		return err
+	}
+
+	key := ctrlclient.ObjectKey{Name: coreDNSKey, Namespace: metav1.NamespaceSystem}
+	cm, err := w.getConfigMap(ctx, key)
+	if err != nil {
+		return errors.Wrapf(err, "error getting %v config map from target cluster", key)
+	}
+	original := cm.DeepCopy()
+	cm.Data[corefileKey] = updatedCorefile
+	if overrides != nil {
+		cm.Data[corefileBaseKey] = info.baseCorefile()
+	} else {
+		delete(cm.Data, corefileBaseKey)
+	}
+	if reflect.DeepEqual(original.Data, cm.Data) {
+		return nil
+	}
+	if err := w.Client.Update(ctx, cm); err != nil {
+		return errors.Wrap(err, "unable to update CoreDNS config map with Corefile overrides")
+	}
+	return nil
+}
+
+// applyCorefileOverrides applies the CorefileOverrides to a Corefile.
+// Plugins and forward servers are applied to the default server block, i.e. the one serving the root zone, while zones
+// are added as additional server blocks, replacing existing server blocks for the same zone; as a consequence,
+// applying the same overrides more than once returns the same Corefile.
+func applyCorefileOverrides(corefileData string, overrides *controlplanev1.CorefileOverrides) (string, error) {
+	if overrides == nil {
+		return corefileData, nil
+	}
+
+	cf, err := corefile.New(corefileData)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to parse CoreDNS Corefile")
+	}
+
+	var defaultServer *corefile.Server
+	for _, server := range cf.Servers {
+		if isDefaultCorefileServer(server) {
+			defaultServer = server
+			break
+		}
+	}
+	if defaultServer == nil {
+		return "", errors.New("unable to apply Corefile overrides: CoreDNS Corefile has no server block for the root zone")
+	}
+
+	removePlugins := sets.Set[string]{}.Insert(overrides.RemovePlugins...)
+	plugins := []*corefile.Plugin{}
+	for _, plugin := range defaultServer.Plugins {
+		if !removePlugins.Has(plugin.Name) {
+			plugins = append(plugins, plugin)
+		}
+	}
+	defaultServer.Plugins = plugins
+
+	for _, plugin := range overrides.AddPlugins {
+		defaultServer.Plugins = setCorefilePlugin(defaultServer.Plugins, toCorefilePlugin(plugin))
+	}
+
+	if len(overrides.ForwardServers) > 0 {
+		defaultServer.Plugins = setCorefileForwardServers(defaultServer.Plugins, overrides.ForwardServers)
+	}
+
+	for _, zone := range overrides.Zones {
+		server := &corefile.Server{
+			DomPorts: []string{zone.Name},
+			Plugins: []*corefile.Plugin{
+				{Name: "errors"},
+				{Name: "cache", Args: []string{"30"}},
+			},
+		}
+		if len(zone.ForwardServers) > 0 {
+			server.Plugins = setCorefileForwardServers(server.Plugins, zone.ForwardServers)
+		}
+		for _, plugin := range zone.Plugins {
+			server.Plugins = setCorefilePlugin(server.Plugins, toCorefilePlugin(plugin))
+		}
+
+		replaced := false
+		for i := range cf.Servers {
+			if reflect.DeepEqual(cf.Servers[i].DomPorts, server.DomPorts) {
+				cf.Servers[i] = server
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			cf.Servers = append(cf.Servers, server)
+		}
+	}
+
+	return cf.ToString(), nil
+}
+
+// isDefaultCorefileServer returns true if the server block serves the root zone, e.g. ".:53".
+func isDefaultCorefileServer(server *corefile.Server) bool {
+	for _, domPort := range server.DomPorts {
+		domPort = strings.TrimPrefix(domPort, "dns://")
+		if domPort == "." || strings.HasPrefix(domPort, ".:") {
+			return true
+		}
+	}
+	return false
+}
+
+// setCorefilePlugin replaces the plugin with the same name, or adds the plugin if it doesn't exist.
+func setCorefilePlugin(plugins []*corefile.Plugin, plugin *corefile.Plugin) []*corefile.Plugin {
+	for i := range plugins {
+		if plugins[i].Name == plugin.Name {
+			plugins[i] = plugin
+			return plugins
+		}
+	}
+	return append(plugins, plugin)
+}
+
+// setCorefileForwardServers sets the upstream servers of the forward plugin, preserving
+// the zone the plugin forwards queries for and its options.
+func setCorefileForwardServers(plugins []*corefile.Plugin, servers []string) []*corefile.Plugin {
+	for _, plugin := range plugins {
+		if plugin.Name == forwardPluginName {
+			from := "."
+			if len(plugin.Args) > 0 {
+				from = plugin.Args[0]
+			}
+			plugin.Args = append([]string{from}, servers...)
+			return plugins
+		}
+	}
+	return append(plugins, &corefile.Plugin{Name: forwardPluginName, Args: append([]string{"."}, servers...)})
+}
+
+func toCorefilePlugin(plugin controlplanev1.CorefilePlugin) *corefile.Plugin {
+	out := &corefile.Plugin{
+		Name: plugin.Name,
+		Args: plugin.Args,
+	}
+	for _, option := range plugin.Options {
+		out.Options = append(out.Options, &corefile.Option{
+			Name: option.Name,
+			Args: option.Args,
+		})
+	}
+	return out
+}
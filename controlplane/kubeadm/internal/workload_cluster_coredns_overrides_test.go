@@ -0,0 +1,269 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+const kubeadmCorefile = `.:53 {
+    errors
+    health {
+       lameduck 5s
+    }
+    ready
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+       pods insecure
+       fallthrough in-addr.arpa ip6.arpa
+       ttl 30
+    }
+    prometheus :9153
+    forward . /etc/resolv.conf {
+       max_concurrent 1000
+    }
+    cache 30
+    loop
+    reload
+    loadbalance
+}
+`
+
+func TestApplyCorefileOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		corefile  string
+		overrides *controlplanev1.CorefileOverrides
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:      "returns the Corefile as is without overrides",
+			corefile:  kubeadmCorefile,
+			overrides: nil,
+			expected:  kubeadmCorefile,
+		},
+		{
+			name:     "adds, replaces and removes plugins",
+			corefile: kubeadmCorefile,
+			overrides: &controlplanev1.CorefileOverrides{
+				AddPlugins: []controlplanev1.CorefilePlugin{
+					{Name: "log"},
+					{Name: "cache", Args: []string{"60"}, Options: []controlplanev1.CorefilePluginOption{{Name: "prefetch", Args: []string{"10"}}}},
+				},
+				RemovePlugins: []string{"loop", "loadbalance"},
+			},
+			expected: `.:53 {
+    errors
+    health {
+        lameduck 5s
+    }
+    ready
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+        pods insecure
+        fallthrough in-addr.arpa ip6.arpa
+        ttl 30
+    }
+    prometheus :9153
+    forward . /etc/resolv.conf {
+        max_concurrent 1000
+    }
+    cache 60 {
+        prefetch 10
+    }
+    reload
+    log
+}
+`,
+		},
+		{
+			name:     "sets forward servers preserving the forward options",
+			corefile: kubeadmCorefile,
+			overrides: &controlplanev1.CorefileOverrides{
+				ForwardServers: []string{"8.8.8.8", "tls://1.1.1.1"},
+				RemovePlugins:  []string{"health", "kubernetes", "ready", "prometheus", "cache", "loop", "reload", "loadbalance"},
+			},
+			expected: `.:53 {
+    errors
+    forward . 8.8.8.8 tls://1.1.1.1 {
+        max_concurrent 1000
+    }
+}
+`,
+		},
+		{
+			name:     "adds zones",
+			corefile: ".:53 {\n    errors\n}\n",
+			overrides: &controlplanev1.CorefileOverrides{
+				Zones: []controlplanev1.CorefileZone{
+					{
+						Name:           "example.com:53",
+						ForwardServers: []string{"10.0.0.10"},
+						Plugins:        []controlplanev1.CorefilePlugin{{Name: "log"}},
+					},
+				},
+			},
+			expected: `.:53 {
+    errors
+}
+
+example.com:53 {
+    errors
+    cache 30
+    forward . 10.0.0.10
+    log
+}
+`,
+		},
+		{
+			name:     "replaces existing zones",
+			corefile: ".:53 {\n    errors\n}\nexample.com:53 {\n    forward . 10.0.0.1\n}\n",
+			overrides: &controlplanev1.CorefileOverrides{
+				Zones: []controlplanev1.CorefileZone{
+					{
+						Name:           "example.com:53",
+						ForwardServers: []string{"10.0.0.10"},
+					},
+				},
+			},
+			expected: `.:53 {
+    errors
+}
+
+example.com:53 {
+    errors
+    cache 30
+    forward . 10.0.0.10
+}
+`,
+		},
+		{
+			name:     "returns error if the Corefile has no server block for the root zone",
+			corefile: "example.com:53 {\n    errors\n}\n",
+			overrides: &controlplanev1.CorefileOverrides{
+				AddPlugins: []controlplanev1.CorefilePlugin{{Name: "log"}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := applyCorefileOverrides(tt.corefile, tt.overrides)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(actual).To(Equal(tt.expected))
+
+			// Applying the overrides again doesn't change the Corefile.
+			again, err := applyCorefileOverrides(actual, tt.overrides)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(again).To(Equal(actual))
+		})
+	}
+}
+
+func TestUpdateCoreDNSCorefileOverrides(t *testing.T) {
+	overrides := &controlplanev1.CorefileOverrides{
+		AddPlugins: []controlplanev1.CorefilePlugin{{Name: "log"}},
+	}
+	overriddenCorefile, err := applyCorefileOverrides(kubeadmCorefile, overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      coreDNSKey,
+				Namespace: metav1.NamespaceSystem,
+			},
+			Data: data,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		configMap    *corev1.ConfigMap
+		overrides    *controlplanev1.CorefileOverrides
+		expectedData map[string]string
+	}{
+		{
+			name:         "does nothing without overrides",
+			configMap:    newConfigMap(map[string]string{corefileKey: kubeadmCorefile}),
+			overrides:    nil,
+			expectedData: map[string]string{corefileKey: kubeadmCorefile},
+		},
+		{
+			name:      "applies overrides and preserves the base Corefile",
+			configMap: newConfigMap(map[string]string{corefileKey: kubeadmCorefile, corefileBackupKey: "backup"}),
+			overrides: overrides,
+			expectedData: map[string]string{
+				corefileKey:       overriddenCorefile,
+				corefileBaseKey:   kubeadmCorefile,
+				corefileBackupKey: "backup",
+			},
+		},
+		{
+			name:      "applies overrides to the base Corefile",
+			configMap: newConfigMap(map[string]string{corefileKey: "outdated", corefileBaseKey: kubeadmCorefile}),
+			overrides: overrides,
+			expectedData: map[string]string{
+				corefileKey:     overriddenCorefile,
+				corefileBaseKey: kubeadmCorefile,
+			},
+		},
+		{
+			name:         "restores the base Corefile when overrides are removed",
+			configMap:    newConfigMap(map[string]string{corefileKey: overriddenCorefile, corefileBaseKey: kubeadmCorefile}),
+			overrides:    nil,
+			expectedData: map[string]string{corefileKey: kubeadmCorefile},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeClient := fake.NewClientBuilder().WithObjects(tt.configMap).Build()
+			w := &Workload{
+				Client: fakeClient,
+			}
+			info := &coreDNSInfo{
+				Corefile:     tt.configMap.Data[corefileKey],
+				BaseCorefile: tt.configMap.Data[corefileBaseKey],
+			}
+
+			g.Expect(w.updateCoreDNSCorefileOverrides(ctx, info, tt.overrides)).To(Succeed())
+
+			var actualConfigMap corev1.ConfigMap
+			g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: coreDNSKey, Namespace: metav1.NamespaceSystem}, &actualConfigMap)).To(Succeed())
+			g.Expect(actualConfigMap.Data).To(Equal(tt.expectedData))
+		})
+	}
+}
@@ -1003,7 +1003,7 @@ func TestUpdateCoreDNSCorefile(t *testing.T) {
 			TargetMajorMinorPatch:  "1.7.2",
 		}
 
-		err := w.updateCoreDNSCorefile(ctx, info)
+		err := w.updateCoreDNSCorefile(ctx, info, nil)
 		g.Expect(err).To(HaveOccurred())
 		g.Expect(fakeMigrator.migrateCalled).To(BeTrue())
 
@@ -1035,7 +1035,7 @@ func TestUpdateCoreDNSCorefile(t *testing.T) {
 			TargetMajorMinorPatch:  "1.7.2",
 		}
 
-		err := w.updateCoreDNSCorefile(ctx, info)
+		err := w.updateCoreDNSCorefile(ctx, info, nil)
 		g.Expect(err).To(HaveOccurred())
 
 		var expectedConfigMap corev1.ConfigMap
@@ -1067,7 +1067,7 @@ func TestUpdateCoreDNSCorefile(t *testing.T) {
 			TargetMajorMinorPatch:  "1.7.2",
 		}
 
-		err := w.updateCoreDNSCorefile(ctx, info)
+		err := w.updateCoreDNSCorefile(ctx, info, nil)
 		g.Expect(err).ToNot(HaveOccurred())
 
 		expectedVolume := corev1.Volume{
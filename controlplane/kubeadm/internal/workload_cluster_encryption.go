@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// migrateEncryptedResourcesPageSize is the number of objects read at once while migrating encrypted resources.
+const migrateEncryptedResourcesPageSize = 500
+
+// MigrateEncryptedResources rewrites all the objects of the given resources, e.g. "secrets" or "deployments.apps",
+// so the API server stores them again encrypted with the key currently used for encrypting data.
+// NOTE: Objects are updated without changes; the API server writes them anyway to etcd given that they have been
+// encrypted with a key which is not the current one, like the kube-storage-version-migrator does.
+func (w *Workload) MigrateEncryptedResources(ctx context.Context, resources []string) error {
+	for _, resource := range resources {
+		gvr := schema.ParseGroupResource(resource).WithVersion("")
+		gvk, err := w.Client.RESTMapper().KindFor(gvr)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the kind of resource %q", resource)
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		for {
+			if err := w.Client.List(ctx, list, ctrlclient.Limit(migrateEncryptedResourcesPageSize), ctrlclient.Continue(list.GetContinue())); err != nil {
+				return errors.Wrapf(err, "failed to list %q", resource)
+			}
+			for i := range list.Items {
+				// Objects deleted or updated in the meantime do not need to be rewritten anymore.
+				if err := w.Client.Update(ctx, &list.Items[i]); err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+					return errors.Wrapf(err, "failed to rewrite %s %s", gvk.Kind, ctrlclient.ObjectKeyFromObject(&list.Items[i]))
+				}
+			}
+			if list.GetContinue() == "" {
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWorkload_MigrateEncryptedResources(t *testing.T) {
+	g := NewWithT(t)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: metav1.NamespaceDefault}}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config-map", Namespace: metav1.NamespaceSystem}}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	w := &Workload{Client: fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(secret, configMap).Build()}
+
+	g.Expect(w.MigrateEncryptedResources(ctx, []string{"secrets", "configmaps"})).To(Succeed())
+
+	// All the objects of the encrypted resources are written again.
+	migratedSecret := &corev1.Secret{}
+	g.Expect(w.Client.Get(ctx, ctrlclient.ObjectKeyFromObject(secret), migratedSecret)).To(Succeed())
+	g.Expect(migratedSecret.ResourceVersion).ToNot(Equal(secret.ResourceVersion))
+	migratedConfigMap := &corev1.ConfigMap{}
+	g.Expect(w.Client.Get(ctx, ctrlclient.ObjectKeyFromObject(configMap), migratedConfigMap)).To(Succeed())
+	g.Expect(migratedConfigMap.ResourceVersion).ToNot(Equal(configMap.ResourceVersion))
+
+	// Unknown resources can't be rewritten.
+	g.Expect(w.MigrateEncryptedResources(ctx, []string{"unknowns"})).ToNot(Succeed())
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// EtcdCertificatesRotationLabel is set on the Pods rotating the etcd certificates on control plane nodes.
+	EtcdCertificatesRotationLabel = "controlplane.cluster.x-k8s.io/etcd-certificates-rotation"
+
+	// etcdCertificatesRotationScript renews the etcd certificates with kubeadm, then restarts the etcd and the
+	// kube-apiserver static pods, one after the other, by temporarily moving their manifests out of the manifests
+	// directory, so they pick up the new certificates.
+	// NOTE: The script runs in the host filesystem, so it uses the kubeadm binary installed on the node.
+	etcdCertificatesRotationScript = `set -e
+for cert in etcd-server etcd-peer etcd-healthcheck-client apiserver-etcd-client; do
+  kubeadm certs renew "${cert}"
+done
+for component in etcd kube-apiserver; do
+  mv "/etc/kubernetes/manifests/${component}.yaml" "/etc/kubernetes/${component}.yaml.rotation"
+  sleep 20
+  mv "/etc/kubernetes/${component}.yaml.rotation" "/etc/kubernetes/manifests/${component}.yaml"
+  sleep 20
+done
+`
+)
+
+// EtcdCertificatesRotationPodName returns the name of the Pod rotating the etcd certificates on a control plane node.
+func EtcdCertificatesRotationPodName(nodeName string) string {
+	return fmt.Sprintf("etcd-certificates-rotation-%s", nodeName)
+}
+
+// GetEtcdCertificatesRotationPod returns the Pod rotating the etcd certificates on a control plane node, if any.
+func (w *Workload) GetEtcdCertificatesRotationPod(ctx context.Context, nodeName string) (*corev1.Pod, error) {
+	pod, err := w.getHostCommandPod(ctx, EtcdCertificatesRotationPodName(nodeName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get etcd certificates rotation Pod for Node %s", nodeName)
+	}
+	return pod, nil
+}
+
+// CreateEtcdCertificatesRotationPod creates a privileged Pod rotating the etcd certificates on a control plane node
+// using the given image; the image must provide the chroot and sh commands.
+func (w *Workload) CreateEtcdCertificatesRotationPod(ctx context.Context, nodeName, image string) error {
+	pod := hostCommandPod(EtcdCertificatesRotationPodName(nodeName), nodeName, EtcdCertificatesRotationLabel, "rotate", image, etcdCertificatesRotationScript)
+	if err := w.createHostCommandPod(ctx, pod); err != nil {
+		return errors.Wrapf(err, "failed to create etcd certificates rotation Pod for Node %s", nodeName)
+	}
+	return nil
+}
+
+// DeleteEtcdCertificatesRotationPod deletes the Pod rotating the etcd certificates on a control plane node.
+func (w *Workload) DeleteEtcdCertificatesRotationPod(ctx context.Context, nodeName string) error {
+	if err := w.deleteHostCommandPod(ctx, EtcdCertificatesRotationPodName(nodeName)); err != nil {
+		return errors.Wrapf(err, "failed to delete etcd certificates rotation Pod for Node %s", nodeName)
+	}
+	return nil
+}
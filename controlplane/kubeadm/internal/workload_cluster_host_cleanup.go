@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+const (
+	// HostCleanupLabel is set on the Pods cleaning up the host of control plane machines being deleted.
+	HostCleanupLabel = "controlplane.cluster.x-k8s.io/host-cleanup"
+
+	// hostCleanupRemoveManifestsScript removes the static pod manifests, waits for the kubelet to stop the
+	// corresponding static pods, and then removes the etcd data directory.
+	hostCleanupRemoveManifestsScript = `set -e
+rm -f /etc/kubernetes/manifests/*.yaml
+sleep 20
+rm -rf /var/lib/etcd
+`
+
+	// hostCleanupKubeadmResetScript runs kubeadm reset on the host; the etcd member is skipped given that it has
+	// already been removed from the etcd cluster by KCP.
+	// NOTE: kubeadm reset stops the kubelet, which then can't report the Pod as completed, so the reset is run
+	// in a transient systemd unit, and the Pod completes as soon as the unit has been started.
+	hostCleanupKubeadmResetScript = `set -e
+systemd-run --unit=kubeadm-reset --no-block /bin/sh -c "sleep 10 && kubeadm reset --force --skip-phases=remove-etcd-member"
+`
+)
+
+// HostCleanupPodName returns the name of the Pod cleaning up the host of a control plane node.
+func HostCleanupPodName(nodeName string) string {
+	return fmt.Sprintf("host-cleanup-%s", nodeName)
+}
+
+// GetHostCleanupPod returns the Pod cleaning up the host of a control plane node, if any.
+func (w *Workload) GetHostCleanupPod(ctx context.Context, nodeName string) (*corev1.Pod, error) {
+	pod, err := w.getHostCommandPod(ctx, HostCleanupPodName(nodeName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get host cleanup Pod for Node %s", nodeName)
+	}
+	return pod, nil
+}
+
+// CreateHostCleanupPod creates a privileged Pod cleaning up the host of a control plane node with the given mode
+// using the given image; the image must provide the chroot and sh commands.
+func (w *Workload) CreateHostCleanupPod(ctx context.Context, nodeName string, mode controlplanev1.HostCleanupMode, image string) error {
+	script := hostCleanupRemoveManifestsScript
+	if mode == controlplanev1.HostCleanupModeKubeadmReset {
+		script = hostCleanupKubeadmResetScript
+	}
+
+	pod := hostCommandPod(HostCleanupPodName(nodeName), nodeName, HostCleanupLabel, "cleanup", image, script)
+	if err := w.createHostCommandPod(ctx, pod); err != nil {
+		return errors.Wrapf(err, "failed to create host cleanup Pod for Node %s", nodeName)
+	}
+	return nil
+}
+
+// DeleteHostCleanupPod deletes the Pod cleaning up the host of a control plane node.
+func (w *Workload) DeleteHostCleanupPod(ctx context.Context, nodeName string) error {
+	if err := w.deleteHostCommandPod(ctx, HostCleanupPodName(nodeName)); err != nil {
+		return errors.Wrapf(err, "failed to delete host cleanup Pod for Node %s", nodeName)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+)
+
+func TestWorkload_HostCleanupPod(t *testing.T) {
+	g := NewWithT(t)
+
+	w := &Workload{Client: fake.NewClientBuilder().Build()}
+
+	// No Pod exists yet.
+	pod, err := w.GetHostCleanupPod(ctx, "node-1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pod).To(BeNil())
+
+	// Create the Pod; creating it again is a no-op.
+	g.Expect(w.CreateHostCleanupPod(ctx, "node-1", controlplanev1.HostCleanupModeRemoveManifests, "busybox")).To(Succeed())
+	g.Expect(w.CreateHostCleanupPod(ctx, "node-1", controlplanev1.HostCleanupModeRemoveManifests, "busybox")).To(Succeed())
+
+	pod, err = w.GetHostCleanupPod(ctx, "node-1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pod).ToNot(BeNil())
+	g.Expect(pod.Name).To(Equal("host-cleanup-node-1"))
+	g.Expect(pod.Labels).To(HaveKey(HostCleanupLabel))
+	g.Expect(pod.Spec.NodeName).To(Equal("node-1"))
+	g.Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+	g.Expect(pod.Spec.Containers).To(HaveLen(1))
+	g.Expect(pod.Spec.Containers[0].Image).To(Equal("busybox"))
+	g.Expect(pod.Spec.Containers[0].Command).To(ContainElement(ContainSubstring("rm -rf /var/lib/etcd")))
+	g.Expect(*pod.Spec.Containers[0].SecurityContext.Privileged).To(BeTrue())
+	g.Expect(pod.Spec.Volumes[0].HostPath.Path).To(Equal("/"))
+
+	// Delete the Pod; deleting it again is a no-op.
+	g.Expect(w.DeleteHostCleanupPod(ctx, "node-1")).To(Succeed())
+	g.Expect(w.DeleteHostCleanupPod(ctx, "node-1")).To(Succeed())
+
+	pod, err = w.GetHostCleanupPod(ctx, "node-1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pod).To(BeNil())
+
+	// The KubeadmReset mode runs kubeadm reset.
+	g.Expect(w.CreateHostCleanupPod(ctx, "node-2", controlplanev1.HostCleanupModeKubeadmReset, "busybox")).To(Succeed())
+	pod, err = w.GetHostCleanupPod(ctx, "node-2")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pod.Spec.Containers[0].Command).To(ContainElement(ContainSubstring("kubeadm reset --force")))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// hostCommandPod returns a privileged Pod running a shell script in the host filesystem of a control plane node,
+// using the given image; the image must provide the chroot and sh commands.
+// The Pod runs in the host PID namespace, is never restarted and tolerates all the taints, so it can run on nodes
+// being drained or not ready.
+func hostCommandPod(name, nodeName, label, containerName, image, script string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+			Labels: map[string]string{
+				label: "",
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:          nodeName,
+			HostPID:           true,
+			RestartPolicy:     corev1.RestartPolicyNever,
+			PriorityClassName: "system-node-critical",
+			Tolerations: []corev1.Toleration{
+				{Operator: corev1.TolerationOpExists},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:    containerName,
+					Image:   image,
+					Command: []string{"chroot", "/host", "/bin/sh", "-c", script},
+					SecurityContext: &corev1.SecurityContext{
+						Privileged: pointer.Bool(true),
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "host", MountPath: "/host"},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "host",
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{Path: "/"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getHostCommandPod returns the host command Pod with the given name, or nil if it does not exist.
+func (w *Workload) getHostCommandPod(ctx context.Context, name string) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	key := ctrlclient.ObjectKey{Namespace: metav1.NamespaceSystem, Name: name}
+	if err := w.Client.Get(ctx, key, pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pod, nil
+}
+
+// createHostCommandPod creates a host command Pod; this is a no-op if the Pod already exists.
+func (w *Workload) createHostCommandPod(ctx context.Context, pod *corev1.Pod) error {
+	if err := w.Client.Create(ctx, pod); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteHostCommandPod deletes the host command Pod with the given name; this is a no-op if the Pod does not exist.
+func (w *Workload) deleteHostCommandPod(ctx context.Context, name string) error {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+		},
+	}
+	if err := w.Client.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHostCommandPod(t *testing.T) {
+	g := NewWithT(t)
+
+	pod := hostCommandPod("pod-1", "node-1", "test-label", "test", "busybox", "echo hello")
+
+	g.Expect(pod.Name).To(Equal("pod-1"))
+	g.Expect(pod.Namespace).To(Equal(metav1.NamespaceSystem))
+	g.Expect(pod.Labels).To(HaveKey("test-label"))
+	g.Expect(pod.Spec.NodeName).To(Equal("node-1"))
+	g.Expect(pod.Spec.HostPID).To(BeTrue())
+	g.Expect(pod.Spec.HostNetwork).To(BeFalse())
+	g.Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+	g.Expect(pod.Spec.Tolerations).To(ConsistOf(corev1.Toleration{Operator: corev1.TolerationOpExists}))
+	g.Expect(pod.Spec.Containers).To(HaveLen(1))
+	g.Expect(pod.Spec.Containers[0].Name).To(Equal("test"))
+	g.Expect(pod.Spec.Containers[0].Image).To(Equal("busybox"))
+	g.Expect(pod.Spec.Containers[0].Command).To(Equal([]string{"chroot", "/host", "/bin/sh", "-c", "echo hello"}))
+	g.Expect(*pod.Spec.Containers[0].SecurityContext.Privileged).To(BeTrue())
+	g.Expect(pod.Spec.Containers[0].VolumeMounts).To(ConsistOf(corev1.VolumeMount{Name: "host", MountPath: "/host"}))
+	g.Expect(pod.Spec.Volumes).To(HaveLen(1))
+	g.Expect(pod.Spec.Volumes[0].HostPath.Path).To(Equal("/"))
+}
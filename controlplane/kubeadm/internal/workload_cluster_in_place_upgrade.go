@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// InPlaceUpgradeLabel is set on the Pods upgrading in place the control plane nodes.
+	InPlaceUpgradeLabel = "controlplane.cluster.x-k8s.io/in-place-upgrade"
+
+	// InPlaceUpgradeVersionAnnotation is set on the Pods upgrading in place the control plane nodes, and it
+	// documents the version the node is being upgraded to.
+	InPlaceUpgradeVersionAnnotation = "controlplane.cluster.x-k8s.io/in-place-upgrade-version"
+
+	// inPlaceUpgradeScript installs the kubeadm, kubelet and, if present, kubectl binaries of the target version
+	// in place of the existing ones, upgrades the control plane components with kubeadm upgrade node, using the
+	// version in the kubeadm-config ConfigMap, and then restarts the kubelet.
+	// Each binary is verified against the SHA256 checksum published next to it, i.e. <binary>.sha256, before
+	// replacing the existing one, so a corrupted or partial download never replaces a working binary.
+	// NOTE: Restarting the kubelet from the Pod would prevent the Pod from being reported as completed, so the restart
+	// is run in a transient systemd unit, and the Pod completes as soon as the unit has been started.
+	inPlaceUpgradeScript = `set -e
+case "$(uname -m)" in
+  x86_64) arch=amd64 ;;
+  aarch64) arch=arm64 ;;
+  *) arch="$(uname -m)" ;;
+esac
+for binary in kubeadm kubelet kubectl; do
+  path="$(command -v "${binary}" || true)"
+  if [ -z "${path}" ]; then
+    [ "${binary}" = kubectl ] && continue
+    path="/usr/bin/${binary}"
+  fi
+  url="${BINARIES_URL}/${VERSION}/bin/linux/${arch}/${binary}"
+  curl -fsSL --retry 5 -o "${path}.new" "${url}"
+  checksum="$(curl -fsSL --retry 5 "${url}.sha256")"
+  if ! echo "${checksum%% *}  ${path}.new" | sha256sum -c - >/dev/null; then
+    rm -f "${path}.new"
+    echo "checksum verification failed for ${url}" >&2
+    exit 1
+  fi
+  chmod +x "${path}.new"
+  mv -f "${path}.new" "${path}"
+done
+kubeadm upgrade node
+systemd-run --unit=kcp-in-place-upgrade-kubelet-restart --no-block /bin/sh -c "sleep 10 && systemctl daemon-reload && systemctl restart kubelet"
+`
+)
+
+// InPlaceUpgradePodName returns the name of the Pod upgrading in place a control plane node.
+func InPlaceUpgradePodName(nodeName string) string {
+	return fmt.Sprintf("in-place-upgrade-%s", nodeName)
+}
+
+// GetInPlaceUpgradePod returns the Pod upgrading in place a control plane node, if any.
+func (w *Workload) GetInPlaceUpgradePod(ctx context.Context, nodeName string) (*corev1.Pod, error) {
+	pod, err := w.getHostCommandPod(ctx, InPlaceUpgradePodName(nodeName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get in-place upgrade Pod for Node %s", nodeName)
+	}
+	return pod, nil
+}
+
+// CreateInPlaceUpgradePod creates a privileged Pod upgrading in place a control plane node to the given version,
+// downloading the binaries from binariesURL, using the given image; the image must provide the chroot and sh commands.
+// The target version is recorded in the InPlaceUpgradeVersionAnnotation of the Pod.
+// NOTE: The upgrade runs in a Pod on the host, instead of executing the bootstrap data of the machine again, because
+// bootstrap data is run only once by the machine, e.g. by cloud-init at first boot, and it runs kubeadm join, which
+// can't be run on a node which is already part of the cluster; the workload cluster API is the only way to run
+// commands on an existing host which doesn't depend on the infrastructure provider.
+func (w *Workload) CreateInPlaceUpgradePod(ctx context.Context, nodeName, version, binariesURL, image string) error {
+	pod := hostCommandPod(InPlaceUpgradePodName(nodeName), nodeName, InPlaceUpgradeLabel, "upgrade", image, inPlaceUpgradeScript)
+	pod.Annotations = map[string]string{InPlaceUpgradeVersionAnnotation: version}
+	// The binaries are downloaded from the host network namespace.
+	pod.Spec.HostNetwork = true
+	pod.Spec.Containers[0].Env = []corev1.EnvVar{
+		{Name: "VERSION", Value: version},
+		{Name: "BINARIES_URL", Value: binariesURL},
+	}
+	if err := w.createHostCommandPod(ctx, pod); err != nil {
+		return errors.Wrapf(err, "failed to create in-place upgrade Pod for Node %s", nodeName)
+	}
+	return nil
+}
+
+// DeleteInPlaceUpgradePod deletes the Pod upgrading in place a control plane node.
+func (w *Workload) DeleteInPlaceUpgradePod(ctx context.Context, nodeName string) error {
+	if err := w.deleteHostCommandPod(ctx, InPlaceUpgradePodName(nodeName)); err != nil {
+		return errors.Wrapf(err, "failed to delete in-place upgrade Pod for Node %s", nodeName)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+// PermitJoin permits the join of a machine bootstrapped with a KubeadmConfig with the WaitForJoinPermitAnnotation,
+// by setting the JoinPermittedBootstrapProgressKey in the ConfigMap where the machine reports the progress of the bootstrap process.
+func (w *Workload) PermitJoin(ctx context.Context, configName string) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapv1.BootstrapProgressConfigMapNamePrefix + configName,
+			Namespace: metav1.NamespaceSystem,
+		},
+	}
+	patch := ctrlclient.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"data":{%q:"true"}}`, bootstrapv1.JoinPermittedBootstrapProgressKey)))
+	if err := w.Client.Patch(ctx, configMap, patch); err != nil {
+		return errors.Wrapf(err, "failed to permit the join of the machine bootstrapped with KubeadmConfig %s", configName)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWorkload_PermitJoin(t *testing.T) {
+	g := NewWithT(t)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-api-bootstrap-progress-config-1",
+			Namespace: metav1.NamespaceSystem,
+		},
+		Data: map[string]string{"phase": "WaitingForJoinPermit"},
+	}
+	w := &Workload{Client: fake.NewClientBuilder().WithObjects(configMap).Build()}
+
+	g.Expect(w.PermitJoin(ctx, "config-1")).To(Succeed())
+
+	g.Expect(w.Client.Get(ctx, ctrlclient.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+	g.Expect(configMap.Data).To(Equal(map[string]string{"phase": "WaitingForJoinPermit", "joinPermitted": "true"}))
+
+	// The ConfigMap is created by the KubeadmConfig controller, so the join can't be permitted before.
+	g.Expect(w.PermitJoin(ctx, "config-2")).ToNot(Succeed())
+}
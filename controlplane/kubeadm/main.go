@@ -28,12 +28,15 @@ import (
 	"time"
 
 	// +kubebuilder:scaffold:imports
+	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
@@ -43,9 +46,11 @@ import (
 	_ "k8s.io/component-base/logs/json/register"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
@@ -54,9 +59,12 @@ import (
 	controlplanev1alpha4 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	kubeadmcontrolplanecontrollers "sigs.k8s.io/cluster-api/controlplane/kubeadm/controllers"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
 	kcpwebhooks "sigs.k8s.io/cluster-api/controlplane/kubeadm/webhooks"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/metrics/state"
+	"sigs.k8s.io/cluster-api/internal/profiling"
 	"sigs.k8s.io/cluster-api/util/flags"
 	"sigs.k8s.io/cluster-api/version"
 )
@@ -88,13 +96,22 @@ var (
 	watchFilterValue               string
 	watchNamespace                 string
 	profilerAddress                string
+	restConfigQPS                  float32
+	restConfigBurst                int
+	clusterCacheTrackerClientQPS   float32
+	clusterCacheTrackerClientBurst int
 	kubeadmControlPlaneConcurrency int
 	syncPeriod                     time.Duration
 	webhookPort                    int
 	webhookCertDir                 string
 	healthAddr                     string
+	enableStateMetrics             bool
+	enableReconcileProfiling       bool
+	reconcileProfilingRetention    time.Duration
 	etcdDialTimeout                time.Duration
 	etcdCallTimeout                time.Duration
+	hostCommandImage               string
+	inPlaceUpgradeBinariesURL      string
 	tlsOptions                     = flags.TLSOptions{}
 	logOptions                     = logs.NewOptions()
 )
@@ -124,6 +141,18 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&profilerAddress, "profiler-address", "",
 		"Bind address to expose the pprof profiler (e.g. localhost:6060)")
 
+	fs.Float32Var(&restConfigQPS, "kube-api-qps", 20,
+		"Maximum queries per second from the controller client to the Kubernetes API server of the management cluster.")
+
+	fs.IntVar(&restConfigBurst, "kube-api-burst", 30,
+		"Maximum number of queries that should be allowed in one burst from the controller client to the Kubernetes API server of the management cluster.")
+
+	fs.Float32Var(&clusterCacheTrackerClientQPS, "clustercachetracker-client-qps", 0,
+		fmt.Sprintf("Maximum queries per second from the cluster cache tracker clients to the Kubernetes API server of workload clusters. If unset, the client-go default is used. Can be overridden per Cluster with the %s annotation.", clusterv1.RemoteClientQPSAnnotation))
+
+	fs.IntVar(&clusterCacheTrackerClientBurst, "clustercachetracker-client-burst", 0,
+		fmt.Sprintf("Maximum number of queries that should be allowed in one burst from the cluster cache tracker clients to the Kubernetes API server of workload clusters. If unset, the client-go default is used. Can be overridden per Cluster with the %s annotation.", clusterv1.RemoteClientBurstAnnotation))
+
 	fs.IntVar(&kubeadmControlPlaneConcurrency, "kubeadmcontrolplane-concurrency", 10,
 		"Number of kubeadm control planes to process simultaneously")
 
@@ -142,12 +171,27 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
 
+	fs.BoolVar(&enableStateMetrics, "state-metrics", false,
+		"Enable exporting state metrics (phases, conditions and replica counts) for KubeadmControlPlane objects on the metrics endpoint.")
+
+	fs.BoolVar(&enableReconcileProfiling, "reconcile-profiling", false,
+		fmt.Sprintf("Enable recording reconcile durations per controller and per Cluster, broken down by phase, and exposing them at %s on the profiler address. Requires --profiler-address.", profiling.HandlerPath))
+
+	fs.DurationVar(&reconcileProfilingRetention, "reconcile-profiling-retention", profiling.DefaultRetention,
+		"Duration reconcile profiles are retained for after the last recorded reconcile, e.g. the profiles of deleted Clusters. Set to 0 to retain profiles until they are reset.")
+
 	fs.DurationVar(&etcdDialTimeout, "etcd-dial-timeout-duration", 10*time.Second,
 		"Duration that the etcd client waits at most to establish a connection with etcd")
 
 	fs.DurationVar(&etcdCallTimeout, "etcd-call-timeout-duration", etcd.DefaultCallTimeout,
 		"Duration that the etcd client waits at most for read and write operations to etcd.")
 
+	fs.StringVar(&hostCommandImage, "host-command-image", "docker.io/library/busybox:1.36",
+		"Image used for the privileged Pods running commands on the hosts of control plane machines, i.e. for rotating the etcd certificates, cleaning up the hosts and upgrading in place, if not set in the KubeadmControlPlane; it must provide the chroot and sh commands.")
+
+	fs.StringVar(&inPlaceUpgradeBinariesURL, "in-place-upgrade-binaries-url", "https://dl.k8s.io/release",
+		"Base URL the kubeadm, kubelet and kubectl binaries are downloaded from when upgrading control plane machines in place, if not set in the KubeadmControlPlane, e.g. a mirror of the Kubernetes release binaries for air-gapped clusters.")
+
 	flags.AddTLSOptions(fs, &tlsOptions)
 
 	feature.MutableGates.AddFlag(fs)
@@ -168,6 +212,16 @@ func main() {
 	// klog.Background will automatically use the right logger.
 	ctrl.SetLogger(klog.Background())
 
+	if enableReconcileProfiling {
+		if profilerAddress == "" {
+			setupLog.Error(errors.New("--profiler-address is required"), "unable to enable reconcile profiling")
+			os.Exit(1)
+		}
+		profiling.Enable()
+		profiling.DefaultRecorder.SetRetention(reconcileProfilingRetention)
+		http.Handle(profiling.HandlerPath, profiling.Handler(profiling.DefaultRecorder))
+	}
+
 	if profilerAddress != "" {
 		setupLog.Info(fmt.Sprintf("Profiler listening for requests at %s", profilerAddress))
 		go func() {
@@ -179,6 +233,8 @@ func main() {
 	}
 
 	restConfig := ctrl.GetConfigOrDie()
+	restConfig.QPS = restConfigQPS
+	restConfig.Burst = restConfigBurst
 	restConfig.UserAgent = remote.DefaultClusterAPIUserAgent("cluster-api-kubeadm-control-plane-manager")
 
 	tlsOptionOverrides, err := flags.GetTLSOptionOverrideFuncs(tlsOptions)
@@ -187,6 +243,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Only cache the ControllerRevisions recording the revision history of KubeadmControlPlanes, so the manager
+	// does not cache the ControllerRevisions of all the DaemonSets and StatefulSets in the management cluster.
+	controlPlaneRevisionRequirement, err := labels.NewRequirement(clusterv1.MachineControlPlaneNameLabel, selection.Exists, nil)
+	if err != nil {
+		setupLog.Error(err, "unable to create the label selector for ControllerRevisions")
+		os.Exit(1)
+	}
+
 	ctrlOptions := ctrl.Options{
 		Scheme:                     scheme,
 		MetricsBindAddress:         metricsBindAddr,
@@ -202,6 +266,11 @@ func main() {
 			&corev1.ConfigMap{},
 			&corev1.Secret{},
 		},
+		NewCache: cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: cache.SelectorsByObject{
+				&appsv1.ControllerRevision{}: {Label: labels.NewSelector().Add(*controlPlaneRevisionRequirement)},
+			},
+		}),
 		Port:                   webhookPort,
 		HealthProbeBindAddress: healthAddr,
 		CertDir:                webhookCertDir,
@@ -226,6 +295,7 @@ func main() {
 	setupChecks(mgr)
 	setupReconcilers(ctx, mgr)
 	setupWebhooks(mgr)
+	setupStateMetrics(mgr)
 
 	// +kubebuilder:scaffold:builder
 	setupLog.Info("starting manager", "version", version.Get().String())
@@ -252,8 +322,10 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	// requiring a connection to a remote cluster
 	log := ctrl.Log.WithName("remote").WithName("ClusterCacheTracker")
 	tracker, err := remote.NewClusterCacheTracker(mgr, remote.ClusterCacheTrackerOptions{
-		Log:     &log,
-		Indexes: remote.DefaultIndexes,
+		Log:         &log,
+		Indexes:     remote.DefaultIndexes,
+		ClientQPS:   clusterCacheTrackerClientQPS,
+		ClientBurst: clusterCacheTrackerClientBurst,
 		ClientUncachedObjects: []client.Object{
 			&corev1.ConfigMap{},
 			&corev1.Secret{},
@@ -282,6 +354,9 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		WatchFilterValue: watchFilterValue,
 		EtcdDialTimeout:  etcdDialTimeout,
 		EtcdCallTimeout:  etcdCallTimeout,
+
+		HostCommandImage:          hostCommandImage,
+		InPlaceUpgradeBinariesURL: inPlaceUpgradeBinariesURL,
 	}).SetupWithManager(ctx, mgr, concurrency(kubeadmControlPlaneConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubeadmControlPlane")
 		os.Exit(1)
@@ -307,6 +382,17 @@ func setupWebhooks(mgr ctrl.Manager) {
 	}
 }
 
+func setupStateMetrics(mgr ctrl.Manager) {
+	if !enableStateMetrics {
+		return
+	}
+
+	if err := ctrlmetrics.Registry.Register(state.NewCollector(mgr.GetClient(), internal.StateMetricsResource())); err != nil {
+		setupLog.Error(err, "unable to register state metrics")
+		os.Exit(1)
+	}
+}
+
 func concurrency(c int) controller.Options {
 	return controller.Options{MaxConcurrentReconciles: c}
 }
@@ -27,13 +27,28 @@ import (
 func (src *ClusterResourceSet) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*addonsv1.ClusterResourceSet)
 
-	return Convert_v1alpha4_ClusterResourceSet_To_v1beta1_ClusterResourceSet(src, dst, nil)
+	if err := Convert_v1alpha4_ClusterResourceSet_To_v1beta1_ClusterResourceSet(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &addonsv1.ClusterResourceSet{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.DependsOn = restored.Spec.DependsOn
+	return nil
 }
 
 func (dst *ClusterResourceSet) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*addonsv1.ClusterResourceSet)
 
-	return Convert_v1beta1_ClusterResourceSet_To_v1alpha4_ClusterResourceSet(src, dst, nil)
+	if err := Convert_v1beta1_ClusterResourceSet_To_v1alpha4_ClusterResourceSet(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
 }
 
 func (src *ClusterResourceSetList) ConvertTo(dstRaw conversion.Hub) error {
@@ -60,6 +75,22 @@ func (src *ClusterResourceSetBinding) ConvertTo(dstRaw conversion.Hub) error {
 		return err
 	}
 	dst.Spec.ClusterName = restored.Spec.ClusterName
+	dst.Status = restored.Status
+	for _, binding := range dst.Spec.Bindings {
+		if binding == nil {
+			continue
+		}
+		restoredBinding := restored.GetBinding(binding.ClusterResourceSetName)
+		if restoredBinding == nil {
+			continue
+		}
+		for i := range binding.Resources {
+			if restoredResource := restoredBinding.GetResource(binding.Resources[i].ResourceRef); restoredResource != nil {
+				binding.Resources[i].Result = restoredResource.Result
+				binding.Resources[i].Message = restoredResource.Message
+			}
+		}
+	}
 	return nil
 }
 
@@ -95,3 +126,21 @@ func Convert_v1beta1_ClusterResourceSetBindingSpec_To_v1alpha4_ClusterResourceSe
 	// Spec.ClusterName does not exist in ClusterResourceSetBinding v1alpha4 API.
 	return autoConvert_v1beta1_ClusterResourceSetBindingSpec_To_v1alpha4_ClusterResourceSetBindingSpec(in, out, s)
 }
+
+// Convert_v1beta1_ClusterResourceSetBinding_To_v1alpha4_ClusterResourceSetBinding is a conversion function.
+func Convert_v1beta1_ClusterResourceSetBinding_To_v1alpha4_ClusterResourceSetBinding(in *addonsv1.ClusterResourceSetBinding, out *ClusterResourceSetBinding, s apiconversion.Scope) error {
+	// Status does not exist in ClusterResourceSetBinding v1alpha4 API.
+	return autoConvert_v1beta1_ClusterResourceSetBinding_To_v1alpha4_ClusterResourceSetBinding(in, out, s)
+}
+
+// Convert_v1beta1_ClusterResourceSetSpec_To_v1alpha4_ClusterResourceSetSpec is a conversion function.
+func Convert_v1beta1_ClusterResourceSetSpec_To_v1alpha4_ClusterResourceSetSpec(in *addonsv1.ClusterResourceSetSpec, out *ClusterResourceSetSpec, s apiconversion.Scope) error {
+	// Spec.DependsOn does not exist in ClusterResourceSet v1alpha4 API.
+	return autoConvert_v1beta1_ClusterResourceSetSpec_To_v1alpha4_ClusterResourceSetSpec(in, out, s)
+}
+
+// Convert_v1beta1_ResourceBinding_To_v1alpha4_ResourceBinding is a conversion function.
+func Convert_v1beta1_ResourceBinding_To_v1alpha4_ResourceBinding(in *addonsv1.ResourceBinding, out *ResourceBinding, s apiconversion.Scope) error {
+	// Result and Message do not exist in ResourceBinding v1alpha4 API.
+	return autoConvert_v1beta1_ResourceBinding_To_v1alpha4_ResourceBinding(in, out, s)
+}
@@ -46,9 +46,16 @@ type ClusterResourceSetSpec struct {
 	Resources []ResourceRef `json:"resources,omitempty"`
 
 	// Strategy is the strategy to be used during applying resources. Defaults to ApplyOnce. This field is immutable.
-	// +kubebuilder:validation:Enum=ApplyOnce;Reconcile
+	// +kubebuilder:validation:Enum=ApplyOnce;Reconcile;ApplyOnceThenOwn
 	// +optional
 	Strategy string `json:"strategy,omitempty"`
+
+	// DependsOn is a list of names of ClusterResourceSets in the same namespace whose resources must be
+	// successfully applied to a Cluster before the resources of this ClusterResourceSet are applied to it,
+	// e.g. a ClusterResourceSet installing a CNI before another one installing a monitoring stack.
+	// Dependencies that do not select a Cluster are ignored for that Cluster.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // ANCHOR_END: ClusterResourceSetSpec
@@ -83,6 +90,10 @@ const (
 	// ClusterResourceSetStrategyReconcile reapplies the resources managed by a ClusterResourceSet
 	// if their normalized hash changes.
 	ClusterResourceSetStrategyReconcile ClusterResourceSetStrategy = "Reconcile"
+	// ClusterResourceSetStrategyApplyOnceThenOwn applies the resources managed by a ClusterResourceSet only once,
+	// like ApplyOnce, and then checks the applied objects in the workload cluster, reporting (but not reverting)
+	// changes made by other controllers or users in the status of the ClusterResourceSetBinding.
+	ClusterResourceSetStrategyApplyOnceThenOwn ClusterResourceSetStrategy = "ApplyOnceThenOwn"
 )
 
 // SetTypedStrategy sets the Strategy field to the string representation of ClusterResourceSetStrategy.
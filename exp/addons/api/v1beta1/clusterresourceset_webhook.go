@@ -23,6 +23,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -96,6 +97,22 @@ func (m *ClusterResourceSet) validate(old *ClusterResourceSet) error {
 		)
 	}
 
+	// Validate that dependencies are valid names and that a ClusterResourceSet does not depend on itself.
+	dependencies := map[string]bool{}
+	for i, dependency := range m.Spec.DependsOn {
+		fldPath := field.NewPath("spec", "dependsOn").Index(i)
+		for _, msg := range validation.IsDNS1123Subdomain(dependency) {
+			allErrs = append(allErrs, field.Invalid(fldPath, dependency, msg))
+		}
+		if dependency == m.Name {
+			allErrs = append(allErrs, field.Invalid(fldPath, dependency, "a ClusterResourceSet cannot depend on itself"))
+		}
+		if dependencies[dependency] {
+			allErrs = append(allErrs, field.Duplicate(fldPath, dependency))
+		}
+		dependencies[dependency] = true
+	}
+
 	if old != nil && old.Spec.Strategy != "" && old.Spec.Strategy != m.Spec.Strategy {
 		allErrs = append(
 			allErrs,
@@ -190,3 +190,54 @@ func TestClusterResourceSetSelectorNotEmptyValidation(t *testing.T) {
 	g.Expect(err).ToNot(BeNil())
 	g.Expect(err.Error()).To(ContainSubstring("selector must not be empty"))
 }
+
+func TestClusterResourceSetDependsOnValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		dependsOn []string
+		expectErr bool
+	}{
+		{
+			name:      "should not return error for valid dependencies",
+			dependsOn: []string{"cni", "storage"},
+			expectErr: false,
+		},
+		{
+			name:      "should return error for invalid names",
+			dependsOn: []string{"CNI_crs"},
+			expectErr: true,
+		},
+		{
+			name:      "should return error for duplicated dependencies",
+			dependsOn: []string{"cni", "cni"},
+			expectErr: true,
+		},
+		{
+			name:      "should return error if the ClusterResourceSet depends on itself",
+			dependsOn: []string{"monitoring"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterResourceSet := &ClusterResourceSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "monitoring",
+				},
+				Spec: ClusterResourceSetSpec{
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					DependsOn: tt.dependsOn,
+				},
+			}
+			if tt.expectErr {
+				g.Expect(clusterResourceSet.ValidateCreate()).NotTo(Succeed())
+			} else {
+				g.Expect(clusterResourceSet.ValidateCreate()).To(Succeed())
+			}
+		})
+	}
+}
@@ -42,10 +42,35 @@ type ResourceBinding struct {
 
 	// Applied is to track if a resource is applied to the cluster or not.
 	Applied bool `json:"applied"`
+
+	// Result is the outcome of the last reconciliation of the resource for the cluster.
+	// +optional
+	// +kubebuilder:validation:Enum=Applied;Failed;Pruned
+	Result ResourceBindingResult `json:"result,omitempty"`
+
+	// Message is a human-readable message explaining why the resource failed to be applied to the cluster.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ANCHOR_END: ResourceBinding
 
+// ResourceBindingResult is the outcome of the last reconciliation of a resource for a cluster.
+type ResourceBindingResult string
+
+const (
+	// ResourceBindingResultApplied documents that the resource has been applied to the cluster.
+	ResourceBindingResultApplied ResourceBindingResult = "Applied"
+
+	// ResourceBindingResultFailed documents that the resource failed to be applied to the cluster;
+	// the error is reported in the message of the ResourceBinding.
+	ResourceBindingResultFailed ResourceBindingResult = "Failed"
+
+	// ResourceBindingResultPruned documents that the resource has been removed from the ClusterResourceSet
+	// after being applied to the cluster; the objects already applied to the cluster are not deleted.
+	ResourceBindingResultPruned ResourceBindingResult = "Pruned"
+)
+
 // ResourceSetBinding keeps info on all of the resources in a ClusterResourceSet.
 type ResourceSetBinding struct {
 	// ClusterResourceSetName is the name of the ClusterResourceSet that is applied to the owner cluster of the binding.
@@ -84,19 +109,38 @@ func (r *ResourceSetBinding) SetBinding(resourceBinding ResourceBinding) {
 	r.Resources = append(r.Resources, resourceBinding)
 }
 
+// FailedResources returns the resources which failed to be applied to the cluster.
+func (r *ResourceSetBinding) FailedResources() []ResourceBinding {
+	var failed []ResourceBinding
+	for _, resource := range r.Resources {
+		if resource.Result == ResourceBindingResultFailed {
+			failed = append(failed, resource)
+		}
+	}
+	return failed
+}
+
 // GetOrCreateBinding returns the ResourceSetBinding for a given ClusterResourceSet if exists,
 // otherwise creates one and updates ClusterResourceSet with it.
 func (c *ClusterResourceSetBinding) GetOrCreateBinding(clusterResourceSet *ClusterResourceSet) *ResourceSetBinding {
-	for _, binding := range c.Spec.Bindings {
-		if binding.ClusterResourceSetName == clusterResourceSet.Name {
-			return binding
-		}
+	if binding := c.GetBinding(clusterResourceSet.Name); binding != nil {
+		return binding
 	}
 	binding := &ResourceSetBinding{ClusterResourceSetName: clusterResourceSet.Name, Resources: []ResourceBinding{}}
 	c.Spec.Bindings = append(c.Spec.Bindings, binding)
 	return binding
 }
 
+// GetBinding returns the ResourceSetBinding for a given ClusterResourceSet, if any.
+func (c *ClusterResourceSetBinding) GetBinding(clusterResourceSetName string) *ResourceSetBinding {
+	for _, binding := range c.Spec.Bindings {
+		if binding != nil && binding.ClusterResourceSetName == clusterResourceSetName {
+			return binding
+		}
+	}
+	return nil
+}
+
 // DeleteBinding removes the ClusterResourceSet from the ClusterResourceSetBinding Bindings list.
 func (c *ClusterResourceSetBinding) DeleteBinding(clusterResourceSet *ClusterResourceSet) {
 	for i, binding := range c.Spec.Bindings {
@@ -106,6 +150,7 @@ func (c *ClusterResourceSetBinding) DeleteBinding(clusterResourceSet *ClusterRes
 			break
 		}
 	}
+	c.DeleteDrifts(clusterResourceSet.Name)
 	c.OwnerReferences = util.RemoveOwnerRef(c.GetOwnerReferences(), metav1.OwnerReference{
 		APIVersion: clusterResourceSet.APIVersion,
 		Kind:       clusterResourceSet.Kind,
@@ -113,6 +158,50 @@ func (c *ClusterResourceSetBinding) DeleteBinding(clusterResourceSet *ClusterRes
 	})
 }
 
+// GetDrift returns the drift detected for a resource of a ClusterResourceSet, if any.
+func (c *ClusterResourceSetBinding) GetDrift(clusterResourceSetName string, resourceRef ResourceRef) *ResourceDrift {
+	for i := range c.Status.Drift {
+		if c.Status.Drift[i].ClusterResourceSetName == clusterResourceSetName && reflect.DeepEqual(c.Status.Drift[i].ResourceRef, resourceRef) {
+			return &c.Status.Drift[i]
+		}
+	}
+	return nil
+}
+
+// SetDrift sets the drift detected for a resource of a ClusterResourceSet either by updating the existing one or
+// adding a new one.
+func (c *ClusterResourceSetBinding) SetDrift(drift ResourceDrift) {
+	if existing := c.GetDrift(drift.ClusterResourceSetName, drift.ResourceRef); existing != nil {
+		*existing = drift
+		return
+	}
+	c.Status.Drift = append(c.Status.Drift, drift)
+}
+
+// DeleteDrift removes the drift detected for a resource of a ClusterResourceSet, if any.
+func (c *ClusterResourceSetBinding) DeleteDrift(clusterResourceSetName string, resourceRef ResourceRef) {
+	var drift []ResourceDrift
+	for _, d := range c.Status.Drift {
+		if d.ClusterResourceSetName == clusterResourceSetName && reflect.DeepEqual(d.ResourceRef, resourceRef) {
+			continue
+		}
+		drift = append(drift, d)
+	}
+	c.Status.Drift = drift
+}
+
+// DeleteDrifts removes the drift detected for all the resources of a ClusterResourceSet.
+func (c *ClusterResourceSetBinding) DeleteDrifts(clusterResourceSetName string) {
+	var drift []ResourceDrift
+	for _, d := range c.Status.Drift {
+		if d.ClusterResourceSetName == clusterResourceSetName {
+			continue
+		}
+		drift = append(drift, d)
+	}
+	c.Status.Drift = drift
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=clusterresourcesetbindings,scope=Namespaced,categories=cluster-api
 // +kubebuilder:subresource:status
@@ -123,7 +212,8 @@ func (c *ClusterResourceSetBinding) DeleteBinding(clusterResourceSet *ClusterRes
 type ClusterResourceSetBinding struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              ClusterResourceSetBindingSpec `json:"spec,omitempty"`
+	Spec              ClusterResourceSetBindingSpec   `json:"spec,omitempty"`
+	Status            ClusterResourceSetBindingStatus `json:"status,omitempty"`
 }
 
 // ANCHOR: ClusterResourceSetBindingSpec
@@ -142,6 +232,41 @@ type ClusterResourceSetBindingSpec struct {
 
 // ANCHOR_END: ClusterResourceSetBindingSpec
 
+// ANCHOR: ClusterResourceSetBindingStatus
+
+// ClusterResourceSetBindingStatus defines the observed state of ClusterResourceSetBinding.
+type ClusterResourceSetBindingStatus struct {
+	// Drift lists the resources applied with the "ApplyOnceThenOwn" ClusterResourceSet.spec.strategy whose objects
+	// in the workload cluster differ from the ones defined in the resource.
+	// +optional
+	Drift []ResourceDrift `json:"drift,omitempty"`
+}
+
+// ANCHOR_END: ClusterResourceSetBindingStatus
+
+// ResourceDrift documents the differences between the objects defined in a resource and the corresponding objects
+// in the workload cluster, e.g. because they have been changed by other controllers or users.
+type ResourceDrift struct {
+	// ClusterResourceSetName is the name of the ClusterResourceSet the resource belongs to.
+	ClusterResourceSetName string `json:"clusterResourceSetName"`
+
+	// ResourceRef specifies the resource.
+	ResourceRef `json:",inline"`
+
+	// Diff lists the fields of the objects in the workload cluster which differ from the resource, e.g.
+	// "ConfigMap kube-system/my-config: .data.key"; values are not reported, given that they might be sensitive.
+	Diff []string `json:"diff"`
+
+	// Managers lists the field managers which changed the objects in the workload cluster after the resource
+	// has been applied; they usually identify the controllers or users conflicting with the ClusterResourceSet.
+	// +optional
+	Managers []string `json:"managers,omitempty"`
+
+	// DetectedTime identifies when the drift has been detected first.
+	// +optional
+	DetectedTime *metav1.Time `json:"detectedTime,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // ClusterResourceSetBindingList contains a list of ClusterResourceSetBinding.
@@ -216,3 +216,62 @@ func TestSetResourceBinding(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterResourceSetBindingDrift(t *testing.T) {
+	g := NewWithT(t)
+
+	resourceRef := ResourceRef{
+		Name: "resource",
+		Kind: "ConfigMap",
+	}
+	otherResourceRef := ResourceRef{
+		Name: "other-resource",
+		Kind: "ConfigMap",
+	}
+	binding := &ClusterResourceSetBinding{}
+
+	binding.SetDrift(ResourceDrift{ClusterResourceSetName: "crs", ResourceRef: resourceRef, Diff: []string{"ConfigMap default/a: .data.key"}})
+	binding.SetDrift(ResourceDrift{ClusterResourceSetName: "crs", ResourceRef: otherResourceRef, Diff: []string{"ConfigMap default/b: deleted"}})
+	binding.SetDrift(ResourceDrift{ClusterResourceSetName: "other-crs", ResourceRef: resourceRef, Diff: []string{"ConfigMap default/a: .data.key"}})
+	g.Expect(binding.Status.Drift).To(HaveLen(3))
+
+	// Setting the drift for an existing resource updates it.
+	binding.SetDrift(ResourceDrift{ClusterResourceSetName: "crs", ResourceRef: resourceRef, Diff: []string{"ConfigMap default/a: .data.other-key"}})
+	g.Expect(binding.Status.Drift).To(HaveLen(3))
+	g.Expect(binding.GetDrift("crs", resourceRef).Diff).To(ConsistOf("ConfigMap default/a: .data.other-key"))
+
+	binding.DeleteDrift("crs", resourceRef)
+	g.Expect(binding.GetDrift("crs", resourceRef)).To(BeNil())
+	g.Expect(binding.GetDrift("crs", otherResourceRef)).ToNot(BeNil())
+
+	binding.DeleteBinding(&ClusterResourceSet{ObjectMeta: metav1.ObjectMeta{Name: "crs"}})
+	g.Expect(binding.GetDrift("crs", otherResourceRef)).To(BeNil())
+	g.Expect(binding.GetDrift("other-crs", resourceRef)).ToNot(BeNil())
+}
+
+func TestResourceSetBindingFailedResources(t *testing.T) {
+	g := NewWithT(t)
+
+	failed := ResourceBinding{
+		ResourceRef: ResourceRef{Name: "failed", Kind: "ConfigMap"},
+		Result:      ResourceBindingResultFailed,
+		Message:     "failed to create object",
+	}
+	binding := &ClusterResourceSetBinding{
+		Spec: ClusterResourceSetBindingSpec{
+			Bindings: []*ResourceSetBinding{
+				{
+					ClusterResourceSetName: "crs",
+					Resources: []ResourceBinding{
+						{ResourceRef: ResourceRef{Name: "applied", Kind: "ConfigMap"}, Applied: true, Result: ResourceBindingResultApplied},
+						failed,
+						{ResourceRef: ResourceRef{Name: "pruned", Kind: "Secret"}, Applied: true, Result: ResourceBindingResultPruned},
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(binding.GetBinding("other-crs")).To(BeNil())
+	g.Expect(binding.GetBinding("crs").FailedResources()).To(Equal([]ResourceBinding{failed}))
+}
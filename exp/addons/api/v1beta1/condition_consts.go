@@ -37,6 +37,14 @@ const (
 	// RetrievingResourceFailedReason (Severity=Warning) documents at least one of the resources are not successfully retrieved.
 	RetrievingResourceFailedReason = "RetrievingResourceFailed"
 
+	// WaitingForDependenciesReason (Severity=Info) documents the resources of a ClusterResourceSet are not applied to
+	// at least one of the matching clusters because some of the ClusterResourceSets it depends on are not applied yet.
+	WaitingForDependenciesReason = "WaitingForDependencies"
+
+	// DependencyCycleReason (Severity=Error) documents the resources of a ClusterResourceSet are not applied because
+	// its dependencies form a cycle, e.g. two ClusterResourceSets depending on each other.
+	DependencyCycleReason = "DependencyCycle"
+
 	// WrongSecretTypeReason (Severity=Warning) documents at least one of the Secret's type in the resource list is not supported.
 	WrongSecretTypeReason = "WrongSecretType"
 )
@@ -59,6 +59,7 @@ func (in *ClusterResourceSetBinding) DeepCopyInto(out *ClusterResourceSetBinding
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceSetBinding.
@@ -137,6 +138,28 @@ func (in *ClusterResourceSetBindingSpec) DeepCopy() *ClusterResourceSetBindingSp
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceSetBindingStatus) DeepCopyInto(out *ClusterResourceSetBindingStatus) {
+	*out = *in
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]ResourceDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceSetBindingStatus.
+func (in *ClusterResourceSetBindingStatus) DeepCopy() *ClusterResourceSetBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceSetBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterResourceSetList) DeepCopyInto(out *ClusterResourceSetList) {
 	*out = *in
@@ -178,6 +201,11 @@ func (in *ClusterResourceSetSpec) DeepCopyInto(out *ClusterResourceSetSpec) {
 		*out = make([]ResourceRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceSetSpec.
@@ -232,6 +260,36 @@ func (in *ResourceBinding) DeepCopy() *ResourceBinding {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceDrift) DeepCopyInto(out *ResourceDrift) {
+	*out = *in
+	out.ResourceRef = in.ResourceRef
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Managers != nil {
+		in, out := &in.Managers, &out.Managers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DetectedTime != nil {
+		in, out := &in.DetectedTime, &out.DetectedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDrift.
+func (in *ResourceDrift) DeepCopy() *ResourceDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceRef) DeepCopyInto(out *ResourceRef) {
 	*out = *in
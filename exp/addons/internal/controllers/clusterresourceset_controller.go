@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -73,6 +74,10 @@ func (r *ClusterResourceSetReconciler) SetupWithManager(ctx context.Context, mgr
 			&source.Kind{Type: &clusterv1.Cluster{}},
 			handler.EnqueueRequestsFromMapFunc(r.clusterToClusterResourceSet),
 		).
+		Watches(
+			&source.Kind{Type: &addonsv1.ClusterResourceSetBinding{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterResourceSetBindingToClusterResourceSet),
+		).
 		Watches(
 			&source.Kind{Type: &corev1.ConfigMap{}},
 			handler.EnqueueRequestsFromMapFunc(r.resourceToClusterResourceSet),
@@ -145,18 +150,86 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return r.reconcileDelete(ctx, clusters, clusterResourceSet)
 	}
 
+	// Resources are not applied if the dependencies of the ClusterResourceSet form a cycle, given that the
+	// ClusterResourceSets in the cycle would wait for each other forever.
+	cycle, err := r.getDependencyCycle(ctx, clusterResourceSet)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if cycle != nil {
+		err := errors.Errorf("dependencies of ClusterResourceSet form a cycle: %s", strings.Join(cycle, " -> "))
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.DependencyCycleReason, clusterv1.ConditionSeverityError, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	blockedClusters := []string{}
+	failedClusters := map[string][]addonsv1.ResourceBinding{}
+	clusterFailures := map[string]applyFailure{}
+	requeue := false
+	errs := []error{}
 	for _, cluster := range clusters {
-		if err := r.ApplyClusterResourceSet(ctx, cluster, clusterResourceSet); err != nil {
+		// Resources are applied to a Cluster only after the resources of all the ClusterResourceSets
+		// this ClusterResourceSet depends on have been applied.
+		pendingDependencies, err := r.getPendingDependencies(ctx, cluster, clusterResourceSet)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(pendingDependencies) > 0 {
+			log.V(4).Info("Waiting for dependencies to be applied", "Cluster", klog.KObj(cluster), "dependencies", pendingDependencies)
+			blockedClusters = append(blockedClusters, fmt.Sprintf("%s (waiting for %s)", cluster.Name, strings.Join(pendingDependencies, ", ")))
+			continue
+		}
+
+		// Apply the resources to all the matching clusters, even if applying them to some of the clusters fails,
+		// so a single failing cluster does not block the rollout of the addons to the other clusters.
+		failedResources, failure, err := r.ApplyClusterResourceSet(ctx, cluster, clusterResourceSet)
+		if len(failedResources) > 0 {
+			failedClusters[cluster.Name] = failedResources
+		}
+		if failure != nil {
+			clusterFailures[cluster.Name] = *failure
+		}
+		if err != nil {
 			// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
 			// the current cluster because of concurrent access.
 			if errors.Is(err, remote.ErrClusterLocked) {
-				log.V(5).Info("Requeuing because another worker has the lock on the ClusterCacheTracker")
-				return ctrl.Result{Requeue: true}, nil
+				log.V(5).Info("Requeuing because another worker has the lock on the ClusterCacheTracker", "Cluster", klog.KObj(cluster))
+				requeue = true
+				continue
 			}
-			return ctrl.Result{}, err
+			errs = append(errs, err)
 		}
 	}
 
+	// Set the ResourcesApplied condition once for all the matching clusters, so the outcome for a cluster
+	// does not hide the failures for the other clusters.
+	switch {
+	case len(clusterFailures) > 0:
+		reason, severity, message := clusterFailuresSummary(clusterFailures, len(clusters))
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, reason, severity, "%s", message)
+	case len(failedClusters) > 0:
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning,
+			"%s", applyFailuresMessage(failedClusters, len(clusters)))
+	case len(blockedClusters) > 0:
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.WaitingForDependenciesReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for ClusterResourceSets to be applied to Clusters: %s", strings.Join(blockedClusters, "; "))
+	case len(errs) == 0 && !requeue:
+		conditions.MarkTrue(clusterResourceSet, addonsv1.ResourcesAppliedCondition)
+	}
+
+	if len(errs) > 0 {
+		return ctrl.Result{}, kerrors.NewAggregate(errs)
+	}
+	if requeue {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Changes to the objects in the workload clusters are not watched, so the drift of the objects applied
+	// with the ApplyOnceThenOwn strategy is checked periodically.
+	if clusterResourceSet.Spec.Strategy == string(addonsv1.ClusterResourceSetStrategyApplyOnceThenOwn) {
+		return ctrl.Result{RequeueAfter: driftCheckInterval}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -238,34 +311,41 @@ func (r *ClusterResourceSetReconciler) getClustersByClusterResourceSetSelector(c
 // It applies resources best effort and continue on scenarios like: unsupported resource types, failure during creation, missing resources.
 // In Reconcile strategy, resources are re-applied to a particular cluster when their definition changes. The hash in ClusterResourceSetBinding is used to check
 // if a resource has changed or not.
+// In ApplyOnceThenOwn strategy, resources are applied only once like in ApplyOnce strategy, and then the applied objects are checked for changes
+// made by other controllers or users; the drift is reported in ClusterResourceSetBinding status, but it is not reverted.
+// The outcome of each resource is recorded in the ClusterResourceSetBinding; the resources which failed to be applied
+// are also returned, together with the failure which prevented applying the resources, if any, so the failures can be
+// summarized in the ResourcesApplied condition across all the matching clusters.
 // TODO: If a resource already exists in the cluster but not applied by ClusterResourceSet, the resource will be updated ?
-func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet) error {
+func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet) ([]addonsv1.ResourceBinding, *applyFailure, error) {
 	log := ctrl.LoggerFrom(ctx, "Cluster", klog.KObj(cluster))
 	ctx = ctrl.LoggerInto(ctx, log)
 
 	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
 	if err != nil {
-		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.RemoteClusterClientFailedReason, clusterv1.ConditionSeverityError, err.Error())
-		return err
+		if errors.Is(err, remote.ErrClusterLocked) {
+			return nil, nil, err
+		}
+		return nil, &applyFailure{reason: addonsv1.RemoteClusterClientFailedReason, severity: clusterv1.ConditionSeverityError, message: err.Error()}, err
 	}
 
 	// Ensure that the Kubernetes API Server service has been created in the remote cluster before applying the ClusterResourceSet to avoid service IP conflict.
 	// This action is required when the remote cluster Kubernetes version is lower than v1.25.
 	// TODO: Remove this action once CAPI no longer supports Kubernetes versions below v1.25. See: https://github.com/kubernetes-sigs/cluster-api/issues/7804
 	if err = ensureKubernetesServiceCreated(ctx, remoteClient); err != nil {
-		return errors.Wrapf(err, "failed to retrieve the Service for Kubernetes API Server of the cluster %s/%s", cluster.Namespace, cluster.Name)
+		return nil, nil, errors.Wrapf(err, "failed to retrieve the Service for Kubernetes API Server of the cluster %s/%s", cluster.Namespace, cluster.Name)
 	}
 
 	// Get ClusterResourceSetBinding object for the cluster.
 	clusterResourceSetBinding, err := r.getOrCreateClusterResourceSetBinding(ctx, cluster, clusterResourceSet)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Initialize the patch helper.
 	patchHelper, err := patch.NewHelper(clusterResourceSetBinding, r.Client)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	defer func() {
@@ -283,6 +363,7 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 		UID:        clusterResourceSet.UID,
 	}))
 	errList := []error{}
+	var failure *applyFailure
 	resourceSetBinding := clusterResourceSetBinding.GetOrCreateBinding(clusterResourceSet)
 
 	// Iterate all resources and apply them to the cluster and update the resource status in the ClusterResourceSetBinding object.
@@ -290,9 +371,9 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 		unstructuredObj, err := r.getResource(ctx, resource, cluster.GetNamespace())
 		if err != nil {
 			if err == ErrSecretTypeNotSupported {
-				conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.WrongSecretTypeReason, clusterv1.ConditionSeverityWarning, err.Error())
+				failure = &applyFailure{reason: addonsv1.WrongSecretTypeReason, severity: clusterv1.ConditionSeverityWarning, message: err.Error()}
 			} else {
-				conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.RetrievingResourceFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+				failure = &applyFailure{reason: addonsv1.RetrievingResourceFailedReason, severity: clusterv1.ConditionSeverityWarning, message: err.Error()}
 
 				// Continue without adding the error to the aggregate if we can't find the resource.
 				if apierrors.IsNotFound(err) {
@@ -317,6 +398,8 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 				Hash:            "",
 				Applied:         false,
 				LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
+				Result:          addonsv1.ResourceBindingResultFailed,
+				Message:         err.Error(),
 			})
 
 			errList = append(errList, err)
@@ -324,6 +407,17 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 		}
 
 		if !resourceScope.needsApply() {
+			// Record the resource as applied in case it was previously pruned and then added back to the ClusterResourceSet.
+			if resourceBinding := resourceSetBinding.GetResource(resource); resourceBinding != nil && resourceBinding.Result != addonsv1.ResourceBindingResultApplied {
+				resourceBinding.Result = addonsv1.ResourceBindingResultApplied
+				resourceBinding.Message = ""
+				resourceSetBinding.SetBinding(*resourceBinding)
+			}
+			if driftScope, ok := resourceScope.(resourceDriftScope); ok {
+				if err := reconcileResourceDrift(ctx, remoteClient, clusterResourceSetBinding, clusterResourceSet, resource, driftScope); err != nil {
+					errList = append(errList, err)
+				}
+			}
 			continue
 		}
 
@@ -339,10 +433,11 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 		// Apply all values in the key-value pair of the resource to the cluster.
 		// As there can be multiple key-value pairs in a resource, each value may have multiple objects in it.
 		isSuccessful := true
+		result, message := addonsv1.ResourceBindingResultApplied, ""
 		if err := resourceScope.apply(ctx, remoteClient); err != nil {
 			isSuccessful = false
+			result, message = addonsv1.ResourceBindingResultFailed, err.Error()
 			log.Error(err, "failed to apply ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name)
-			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 			errList = append(errList, err)
 		}
 
@@ -351,14 +446,57 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			Hash:            resourceScope.hash(),
 			Applied:         isSuccessful,
 			LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
+			Result:          result,
+			Message:         message,
 		})
+		clusterResourceSetBinding.DeleteDrift(clusterResourceSet.Name, resource)
+	}
+
+	// Record the resources removed from the ClusterResourceSet as pruned; the objects applied to the cluster are not deleted.
+	for _, resourceBinding := range resourceSetBinding.Resources {
+		if resourceBinding.Result == addonsv1.ResourceBindingResultPruned || containsResource(clusterResourceSet.Spec.Resources, resourceBinding.ResourceRef) {
+			continue
+		}
+		resourceBinding.Result = addonsv1.ResourceBindingResultPruned
+		resourceBinding.Message = ""
+		resourceSetBinding.SetBinding(resourceBinding)
+		clusterResourceSetBinding.DeleteDrift(clusterResourceSet.Name, resourceBinding.ResourceRef)
 	}
+
+	failedResources := resourceSetBinding.FailedResources()
 	if len(errList) > 0 {
-		return kerrors.NewAggregate(errList)
+		return failedResources, failure, kerrors.NewAggregate(errList)
 	}
+	return failedResources, failure, nil
+}
 
-	conditions.MarkTrue(clusterResourceSet, addonsv1.ResourcesAppliedCondition)
+// reconcileResourceDrift checks if the objects applied from a resource have been changed in the workload cluster
+// and reports the drift in the ClusterResourceSetBinding status; the drift is not reverted.
+func reconcileResourceDrift(ctx context.Context, c client.Client, clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding, clusterResourceSet *addonsv1.ClusterResourceSet, resource addonsv1.ResourceRef, driftScope resourceDriftScope) error {
+	log := ctrl.LoggerFrom(ctx)
 
+	diff, managers, err := driftScope.detectDrift(ctx, c)
+	if err != nil {
+		return errors.Wrapf(err, "failed to detect drift for ClusterResourceSet resource %s %s", resource.Kind, resource.Name)
+	}
+	if len(diff) == 0 {
+		clusterResourceSetBinding.DeleteDrift(clusterResourceSet.Name, resource)
+		return nil
+	}
+
+	detectedTime := &metav1.Time{Time: time.Now().UTC()}
+	if existing := clusterResourceSetBinding.GetDrift(clusterResourceSet.Name, resource); existing != nil && existing.DetectedTime != nil {
+		detectedTime = existing.DetectedTime
+	} else {
+		log.Info("Detected drift for ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name, "managers", managers)
+	}
+	clusterResourceSetBinding.SetDrift(addonsv1.ResourceDrift{
+		ClusterResourceSetName: clusterResourceSet.Name,
+		ResourceRef:            resource,
+		Diff:                   diff,
+		Managers:               managers,
+		DetectedTime:           detectedTime,
+	})
 	return nil
 }
 
@@ -874,8 +874,11 @@ metadata:
 				switch r.ResourceRef.Name {
 				case testConfigmap.Name:
 					g.Expect(r.Applied).To(BeFalse(), "test-configmap should be not applied bc of missing namespace")
+					g.Expect(r.Result).To(Equal(addonsv1.ResourceBindingResultFailed))
+					g.Expect(r.Message).To(ContainSubstring("creating object /v1, Kind=ConfigMap %s/cm-missing-namespace", missingNamespace))
 				case secretName:
 					g.Expect(r.Applied).To(BeTrue(), "test-secret should be applied")
+					g.Expect(r.Result).To(Equal(addonsv1.ResourceBindingResultApplied))
 				}
 			}
 		}, timeout).Should(Succeed())
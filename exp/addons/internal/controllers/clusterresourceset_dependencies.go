@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+// getPendingDependencies returns the names of the ClusterResourceSets the given ClusterResourceSet depends on
+// whose resources are not applied yet to the Cluster.
+// Dependencies that do not exist are reported as pending, while dependencies that do not select the Cluster are ignored.
+func (r *ClusterResourceSetReconciler) getPendingDependencies(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet) ([]string, error) {
+	if len(clusterResourceSet.Spec.DependsOn) == 0 {
+		return nil, nil
+	}
+
+	clusterResourceSetBinding := &addonsv1.ClusterResourceSetBinding{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(cluster), clusterResourceSetBinding); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get ClusterResourceSetBinding for Cluster %s/%s", cluster.Namespace, cluster.Name)
+		}
+		clusterResourceSetBinding = nil
+	}
+
+	pending := []string{}
+	for _, name := range clusterResourceSet.Spec.DependsOn {
+		dependency := &addonsv1.ClusterResourceSet{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: clusterResourceSet.Namespace, Name: name}, dependency); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "failed to get ClusterResourceSet %s the ClusterResourceSet %s depends on", name, clusterResourceSet.Name)
+			}
+			pending = append(pending, fmt.Sprintf("%s (not found)", name))
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(&dependency.Spec.ClusterSelector)
+		if err != nil || selector.Empty() || !selector.Matches(labels.Set(cluster.GetLabels())) {
+			continue
+		}
+
+		if !isClusterResourceSetApplied(clusterResourceSetBinding, dependency) {
+			pending = append(pending, name)
+		}
+	}
+	return pending, nil
+}
+
+// getDependencyCycle returns the ClusterResourceSets forming a dependency cycle which includes the given
+// ClusterResourceSet, e.g. [crs1 crs2 crs1], or nil if there is no cycle.
+// NOTE: ClusterResourceSets in a cycle would wait for each other forever, so their resources are never applied.
+// Dependencies that do not exist are ignored, given they can't be part of a cycle.
+func (r *ClusterResourceSetReconciler) getDependencyCycle(ctx context.Context, clusterResourceSet *addonsv1.ClusterResourceSet) ([]string, error) {
+	visited := map[string]bool{}
+	var visit func(path []string, dependsOn []string) ([]string, error)
+	visit = func(path []string, dependsOn []string) ([]string, error) {
+		for _, name := range dependsOn {
+			if name == clusterResourceSet.Name {
+				return append(path, name), nil
+			}
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+
+			dependency := &addonsv1.ClusterResourceSet{}
+			if err := r.Client.Get(ctx, client.ObjectKey{Namespace: clusterResourceSet.Namespace, Name: name}, dependency); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, errors.Wrapf(err, "failed to get ClusterResourceSet %s", name)
+			}
+			cycle, err := visit(append(path, name), dependency.Spec.DependsOn)
+			if err != nil || cycle != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+	return visit([]string{clusterResourceSet.Name}, clusterResourceSet.Spec.DependsOn)
+}
+
+// isClusterResourceSetApplied returns true if all the resources of the ClusterResourceSet are recorded as applied
+// in the ClusterResourceSetBinding.
+func isClusterResourceSetApplied(clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding, clusterResourceSet *addonsv1.ClusterResourceSet) bool {
+	if clusterResourceSetBinding == nil {
+		return false
+	}
+	for _, binding := range clusterResourceSetBinding.Spec.Bindings {
+		if binding.ClusterResourceSetName != clusterResourceSet.Name {
+			continue
+		}
+		for _, resource := range clusterResourceSet.Spec.Resources {
+			if !binding.IsApplied(resource) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// clusterResourceSetBindingToClusterResourceSet is mapper function that maps a ClusterResourceSetBinding to the
+// ClusterResourceSets depending on any of the ClusterResourceSets in the binding.
+func (r *ClusterResourceSetReconciler) clusterResourceSetBindingToClusterResourceSet(o client.Object) []ctrl.Request {
+	result := []ctrl.Request{}
+
+	clusterResourceSetBinding, ok := o.(*addonsv1.ClusterResourceSetBinding)
+	if !ok {
+		panic(fmt.Sprintf("Expected a ClusterResourceSetBinding but got a %T", o))
+	}
+
+	bound := map[string]bool{}
+	for _, binding := range clusterResourceSetBinding.Spec.Bindings {
+		bound[binding.ClusterResourceSetName] = true
+	}
+
+	crsList := &addonsv1.ClusterResourceSetList{}
+	if err := r.Client.List(context.TODO(), crsList, client.InNamespace(clusterResourceSetBinding.Namespace)); err != nil {
+		return nil
+	}
+	for _, crs := range crsList.Items {
+		for _, dependency := range crs.Spec.DependsOn {
+			if bound[dependency] {
+				name := client.ObjectKey{Namespace: crs.Namespace, Name: crs.Name}
+				result = append(result, ctrl.Request{NamespacedName: name})
+				break
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func TestGetPendingDependencies(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{"cni": "calico"},
+		},
+	}
+
+	newClusterResourceSet := func(name string, selector map[string]string, dependsOn ...string) *addonsv1.ClusterResourceSet {
+		return &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				ClusterSelector: metav1.LabelSelector{MatchLabels: selector},
+				Resources: []addonsv1.ResourceRef{
+					{Name: name, Kind: string(addonsv1.ConfigMapClusterResourceSetResourceKind)},
+				},
+				DependsOn: dependsOn,
+			},
+		}
+	}
+	newBinding := func(crsName string, applied bool) *addonsv1.ResourceSetBinding {
+		return &addonsv1.ResourceSetBinding{
+			ClusterResourceSetName: crsName,
+			Resources: []addonsv1.ResourceBinding{
+				{
+					ResourceRef: addonsv1.ResourceRef{Name: crsName, Kind: string(addonsv1.ConfigMapClusterResourceSetResourceKind)},
+					Applied:     applied,
+				},
+			},
+		}
+	}
+
+	cni := newClusterResourceSet("cni", map[string]string{"cni": "calico"})
+	storage := newClusterResourceSet("storage", map[string]string{"cni": "calico"})
+	otherCNI := newClusterResourceSet("other-cni", map[string]string{"cni": "cilium"})
+
+	tests := []struct {
+		name               string
+		clusterResourceSet *addonsv1.ClusterResourceSet
+		bindings           []*addonsv1.ResourceSetBinding
+		want               []string
+	}{
+		{
+			name:               "no dependencies",
+			clusterResourceSet: newClusterResourceSet("monitoring", map[string]string{"cni": "calico"}),
+			want:               nil,
+		},
+		{
+			name:               "dependencies not applied yet",
+			clusterResourceSet: newClusterResourceSet("monitoring", map[string]string{"cni": "calico"}, "cni", "storage"),
+			bindings:           []*addonsv1.ResourceSetBinding{newBinding("cni", true), newBinding("storage", false)},
+			want:               []string{"storage"},
+		},
+		{
+			name:               "all dependencies applied",
+			clusterResourceSet: newClusterResourceSet("monitoring", map[string]string{"cni": "calico"}, "cni", "storage"),
+			bindings:           []*addonsv1.ResourceSetBinding{newBinding("cni", true), newBinding("storage", true)},
+			want:               []string{},
+		},
+		{
+			name:               "dependencies not selecting the Cluster are ignored",
+			clusterResourceSet: newClusterResourceSet("monitoring", map[string]string{"cni": "calico"}, "other-cni"),
+			want:               []string{},
+		},
+		{
+			name:               "missing dependencies are pending",
+			clusterResourceSet: newClusterResourceSet("monitoring", map[string]string{"cni": "calico"}, "does-not-exist"),
+			want:               []string{"does-not-exist (not found)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs := []client.Object{cni, storage, otherCNI}
+			if tt.bindings != nil {
+				objs = append(objs, &addonsv1.ClusterResourceSetBinding{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      cluster.Name,
+						Namespace: cluster.Namespace,
+					},
+					Spec: addonsv1.ClusterResourceSetBindingSpec{
+						Bindings: tt.bindings,
+					},
+				})
+			}
+			r := &ClusterResourceSetReconciler{
+				Client: fake.NewClientBuilder().WithObjects(objs...).Build(),
+			}
+
+			got, err := r.getPendingDependencies(context.TODO(), cluster, tt.clusterResourceSet)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetDependencyCycle(t *testing.T) {
+	newClusterResourceSet := func(name string, dependsOn ...string) *addonsv1.ClusterResourceSet {
+		return &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				DependsOn: dependsOn,
+			},
+		}
+	}
+
+	tests := []struct {
+		name               string
+		clusterResourceSet *addonsv1.ClusterResourceSet
+		objs               []client.Object
+		want               []string
+	}{
+		{
+			name:               "no dependencies",
+			clusterResourceSet: newClusterResourceSet("cni"),
+			want:               nil,
+		},
+		{
+			name:               "dependencies without a cycle",
+			clusterResourceSet: newClusterResourceSet("monitoring", "cni", "storage"),
+			objs:               []client.Object{newClusterResourceSet("cni"), newClusterResourceSet("storage", "cni")},
+			want:               nil,
+		},
+		{
+			name:               "missing dependencies are ignored",
+			clusterResourceSet: newClusterResourceSet("monitoring", "does-not-exist"),
+			want:               nil,
+		},
+		{
+			name:               "dependency on itself",
+			clusterResourceSet: newClusterResourceSet("cni", "cni"),
+			want:               []string{"cni", "cni"},
+		},
+		{
+			name:               "dependencies forming a cycle",
+			clusterResourceSet: newClusterResourceSet("monitoring", "storage"),
+			objs:               []client.Object{newClusterResourceSet("storage", "cni"), newClusterResourceSet("cni", "monitoring")},
+			want:               []string{"monitoring", "storage", "cni", "monitoring"},
+		},
+		{
+			name:               "cycles between dependencies only are ignored",
+			clusterResourceSet: newClusterResourceSet("monitoring", "storage"),
+			objs:               []client.Object{newClusterResourceSet("storage", "cni"), newClusterResourceSet("cni", "storage")},
+			want:               nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &ClusterResourceSetReconciler{
+				Client: fake.NewClientBuilder().WithObjects(tt.objs...).Build(),
+			}
+
+			got, err := r.getDependencyCycle(context.TODO(), tt.clusterResourceSet)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestClusterResourceSetBindingToClusterResourceSet(t *testing.T) {
+	g := NewWithT(t)
+
+	monitoring := &addonsv1.ClusterResourceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitoring", Namespace: metav1.NamespaceDefault},
+		Spec:       addonsv1.ClusterResourceSetSpec{DependsOn: []string{"cni"}},
+	}
+	logging := &addonsv1.ClusterResourceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "logging", Namespace: metav1.NamespaceDefault},
+		Spec:       addonsv1.ClusterResourceSetSpec{DependsOn: []string{"storage"}},
+	}
+	binding := &addonsv1.ClusterResourceSetBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: metav1.NamespaceDefault},
+		Spec: addonsv1.ClusterResourceSetBindingSpec{
+			Bindings: []*addonsv1.ResourceSetBinding{{ClusterResourceSetName: "cni"}},
+		},
+	}
+
+	r := &ClusterResourceSetReconciler{
+		Client: fake.NewClientBuilder().WithObjects(monitoring, logging).Build(),
+	}
+	g.Expect(r.clusterResourceSetBindingToClusterResourceSet(binding)).To(ConsistOf(
+		ctrl.Request{NamespacedName: client.ObjectKeyFromObject(monitoring)},
+	))
+}
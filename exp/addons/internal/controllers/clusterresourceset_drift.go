@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// driftCheckInterval is the interval used to check the drift of the objects applied with
+	// the ApplyOnceThenOwn strategy.
+	driftCheckInterval = 5 * time.Minute
+
+	// maxDriftDiffEntries is the maximum number of entries reported in the diff of a resource drift.
+	maxDriftDiffEntries = 20
+)
+
+// objectDriftPaths returns the paths of the fields defined in the desired object having a different value
+// in the current object.
+// NOTE: Only the fields defined in the desired object are compared, so fields defaulted by the API server
+// or added by other controllers are not considered a drift; also metadata other than labels and annotations,
+// status, and write-only fields like Secret's stringData are ignored.
+func objectDriftPaths(desired, current map[string]interface{}) []string {
+	paths := []string{}
+	for _, key := range sortedKeys(desired) {
+		switch key {
+		case "apiVersion", "kind", "status", "stringData":
+			continue
+		case "metadata":
+			desiredMetadata, _ := desired[key].(map[string]interface{})
+			currentMetadata, _ := current[key].(map[string]interface{})
+			for _, metadataKey := range []string{"labels", "annotations"} {
+				if _, ok := desiredMetadata[metadataKey]; !ok {
+					continue
+				}
+				paths = append(paths, valueDriftPaths(desiredMetadata[metadataKey], currentMetadata[metadataKey], ".metadata."+metadataKey)...)
+			}
+			continue
+		}
+		paths = append(paths, valueDriftPaths(desired[key], current[key], "."+key)...)
+	}
+	return paths
+}
+
+// valueDriftPaths returns the paths where the current value differs from the desired value.
+func valueDriftPaths(desired, current interface{}, path string) []string {
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		currentValue, ok := current.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+		paths := []string{}
+		for _, key := range sortedKeys(desiredValue) {
+			paths = append(paths, valueDriftPaths(desiredValue[key], currentValue[key], path+"."+key)...)
+		}
+		return paths
+	case []interface{}:
+		currentValue, ok := current.([]interface{})
+		if !ok || len(currentValue) != len(desiredValue) {
+			return []string{path}
+		}
+		paths := []string{}
+		for i := range desiredValue {
+			paths = append(paths, valueDriftPaths(desiredValue[i], currentValue[i], fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return paths
+	default:
+		// Compare JSON representations, so e.g. int64 and float64 representing the same number are considered equal.
+		desiredJSON, _ := json.Marshal(desired)
+		currentJSON, _ := json.Marshal(current)
+		if string(desiredJSON) != string(currentJSON) {
+			return []string{path}
+		}
+		return nil
+	}
+}
+
+// fieldManagersAfter returns the field managers which changed the object after the given time; if the time is nil
+// all the field managers are returned.
+func fieldManagersAfter(obj *unstructured.Unstructured, t *metav1.Time) []string {
+	managers := []string{}
+	for _, entry := range obj.GetManagedFields() {
+		if t != nil && (entry.Time == nil || !entry.Time.After(t.Time)) {
+			continue
+		}
+		managers = append(managers, entry.Manager)
+	}
+	return managers
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
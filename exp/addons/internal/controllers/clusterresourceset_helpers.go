@@ -24,7 +24,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"sort"
+	"strings"
 	"unicode"
 
 	"github.com/pkg/errors"
@@ -45,6 +47,10 @@ import (
 
 var jsonListPrefix = []byte("[")
 
+// maxFailedClustersInMessage is the max number of clusters reported in the message of the ResourcesApplied condition
+// when resources fail to be applied.
+const maxFailedClustersInMessage = 3
+
 // objsFromYamlData parses a collection of yaml documents into Unstructured objects.
 // The returned objects are sorted for creation priority within the objects defined
 // in the same document. The flattening of the documents preserves the original order.
@@ -254,3 +260,66 @@ func ensureKubernetesServiceCreated(ctx context.Context, client client.Client) e
 	}
 	return nil
 }
+
+// containsResource returns true if the list of resources contains the given resource.
+func containsResource(resources []addonsv1.ResourceRef, resource addonsv1.ResourceRef) bool {
+	for _, r := range resources {
+		if reflect.DeepEqual(r, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFailure is a failure which prevented applying the resources of a ClusterResourceSet to a cluster,
+// with the reason and the severity used for reporting it in the ResourcesApplied condition.
+type applyFailure struct {
+	reason   string
+	severity clusterv1.ConditionSeverity
+	message  string
+}
+
+// clusterFailuresSummary returns the reason, the severity and a message summarizing the failures which prevented
+// applying the resources to the matching clusters; the reason and the severity are the ones of the failure of the
+// first cluster in alphabetical order.
+func clusterFailuresSummary(clusterFailures map[string]applyFailure, matchingClusters int) (string, clusterv1.ConditionSeverity, string) {
+	clusterNames := make([]string, 0, len(clusterFailures))
+	for name := range clusterFailures {
+		clusterNames = append(clusterNames, name)
+	}
+	sort.Strings(clusterNames)
+
+	details := []string{}
+	for i, name := range clusterNames {
+		if i == maxFailedClustersInMessage {
+			details = append(details, fmt.Sprintf("and %d more Clusters", len(clusterNames)-maxFailedClustersInMessage))
+			break
+		}
+		details = append(details, fmt.Sprintf("%s: %s", name, clusterFailures[name].message))
+	}
+	first := clusterFailures[clusterNames[0]]
+	return first.reason, first.severity, fmt.Sprintf("Failed to apply resources to %d of %d matching Clusters: %s", len(clusterFailures), matchingClusters, strings.Join(details, "; "))
+}
+
+// applyFailuresMessage returns a message summarizing the resources which failed to be applied to the matching clusters.
+func applyFailuresMessage(failedClusters map[string][]addonsv1.ResourceBinding, matchingClusters int) string {
+	clusterNames := make([]string, 0, len(failedClusters))
+	failedResources := 0
+	for name, resources := range failedClusters {
+		clusterNames = append(clusterNames, name)
+		failedResources += len(resources)
+	}
+	sort.Strings(clusterNames)
+
+	details := []string{}
+	for i, name := range clusterNames {
+		if i == maxFailedClustersInMessage {
+			details = append(details, fmt.Sprintf("and %d more Clusters", len(clusterNames)-maxFailedClustersInMessage))
+			break
+		}
+		for _, resource := range failedClusters[name] {
+			details = append(details, fmt.Sprintf("%s: %s %s: %s", name, resource.Kind, resource.Name, resource.Message))
+		}
+	}
+	return fmt.Sprintf("Failed to apply %d resources to %d of %d matching Clusters: %s", failedResources, len(failedClusters), matchingClusters, strings.Join(details, "; "))
+}
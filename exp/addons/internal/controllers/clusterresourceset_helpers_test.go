@@ -271,3 +271,60 @@ func TestEnsureKubernetesServiceCreated(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyFailuresMessage(t *testing.T) {
+	failed := func(name, message string) addonsv1.ResourceBinding {
+		return addonsv1.ResourceBinding{
+			ResourceRef: addonsv1.ResourceRef{Name: name, Kind: "ConfigMap"},
+			Result:      addonsv1.ResourceBindingResultFailed,
+			Message:     message,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		failedClusters map[string][]addonsv1.ResourceBinding
+		want           string
+	}{
+		{
+			name: "single cluster",
+			failedClusters: map[string][]addonsv1.ResourceBinding{
+				"cluster-a": {failed("cm-1", "namespace not found"), failed("cm-2", "forbidden")},
+			},
+			want: "Failed to apply 2 resources to 1 of 5 matching Clusters: cluster-a: ConfigMap cm-1: namespace not found; cluster-a: ConfigMap cm-2: forbidden",
+		},
+		{
+			name: "more clusters than reported in the message",
+			failedClusters: map[string][]addonsv1.ResourceBinding{
+				"cluster-d": {failed("cm-1", "forbidden")},
+				"cluster-b": {failed("cm-1", "forbidden")},
+				"cluster-a": {failed("cm-1", "forbidden")},
+				"cluster-c": {failed("cm-1", "forbidden")},
+				"cluster-e": {failed("cm-1", "forbidden")},
+			},
+			want: "Failed to apply 5 resources to 5 of 5 matching Clusters: cluster-a: ConfigMap cm-1: forbidden; cluster-b: ConfigMap cm-1: forbidden; cluster-c: ConfigMap cm-1: forbidden; and 2 more Clusters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(applyFailuresMessage(tt.failedClusters, 5)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestClusterFailuresSummary(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterFailures := map[string]applyFailure{
+		"cluster-b": {reason: addonsv1.RetrievingResourceFailedReason, severity: clusterv1.ConditionSeverityWarning, message: "configmap not found"},
+		"cluster-a": {reason: addonsv1.RemoteClusterClientFailedReason, severity: clusterv1.ConditionSeverityError, message: "connection refused"},
+	}
+
+	reason, severity, message := clusterFailuresSummary(clusterFailures, 3)
+	g.Expect(reason).To(Equal(addonsv1.RemoteClusterClientFailedReason))
+	g.Expect(severity).To(Equal(clusterv1.ConditionSeverityError))
+	g.Expect(message).To(Equal("Failed to apply resources to 2 of 3 matching Clusters: cluster-a: connection refused; cluster-b: configmap not found"))
+}
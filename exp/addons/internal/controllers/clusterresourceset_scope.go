@@ -18,11 +18,14 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -42,6 +45,14 @@ type resourceReconcileScope interface {
 	hash() string
 }
 
+// resourceDriftScope is implemented by the resourceReconcileScopes reporting the drift of the objects applied to
+// the target cluster.
+type resourceDriftScope interface {
+	// detectDrift returns the fields of the objects in the target cluster which differ from the objects defined
+	// by the resource, together with the field managers which changed the objects after the resource has been applied.
+	detectDrift(ctx context.Context, c client.Client) (diff, managers []string, err error)
+}
+
 func reconcileScopeForResource(
 	crs *addonsv1.ClusterResourceSet,
 	resourceRef addonsv1.ResourceRef,
@@ -82,6 +93,8 @@ func newResourceReconcileScope(
 		return &reconcileApplyOnceScope{base}
 	case addonsv1.ClusterResourceSetStrategyReconcile:
 		return &reconcileStrategyScope{base}
+	case addonsv1.ClusterResourceSetStrategyApplyOnceThenOwn:
+		return &reconcileApplyOnceThenOwnScope{reconcileApplyOnceScope{base}}
 	default:
 		return nil
 	}
@@ -171,6 +184,54 @@ func (r *reconcileApplyOnceScope) applyObj(ctx context.Context, c client.Client,
 	return nil
 }
 
+// reconcileApplyOnceThenOwnScope applies objects like reconcileApplyOnceScope, and then reports the drift of
+// the applied objects without reverting it.
+type reconcileApplyOnceThenOwnScope struct {
+	reconcileApplyOnceScope
+}
+
+func (r *reconcileApplyOnceThenOwnScope) detectDrift(ctx context.Context, c client.Client) ([]string, []string, error) {
+	var appliedTime *metav1.Time
+	if resourceBinding := r.resourceSetBinding.GetResource(r.resourceRef); resourceBinding != nil {
+		appliedTime = resourceBinding.LastAppliedTime
+	}
+
+	diff := []string{}
+	managers := sets.Set[string]{}
+	objs := r.objs()
+	for i := range objs {
+		obj := &objs[i]
+		currentObj := &unstructured.Unstructured{}
+		currentObj.SetAPIVersion(obj.GetAPIVersion())
+		currentObj.SetKind(obj.GetKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), currentObj); err != nil {
+			if apierrors.IsNotFound(err) {
+				diff = append(diff, fmt.Sprintf("%s %s: deleted", obj.GetKind(), klog.KObj(obj)))
+				continue
+			}
+			return nil, nil, errors.Wrapf(
+				err,
+				"reading object %s %s",
+				obj.GroupVersionKind(),
+				klog.KObj(obj),
+			)
+		}
+
+		paths := objectDriftPaths(obj.Object, currentObj.Object)
+		for _, path := range paths {
+			diff = append(diff, fmt.Sprintf("%s %s: %s", obj.GetKind(), klog.KObj(obj), path))
+		}
+		if len(paths) > 0 {
+			managers.Insert(fieldManagersAfter(currentObj, appliedTime)...)
+		}
+	}
+
+	if len(diff) > maxDriftDiffEntries {
+		diff = append(diff[:maxDriftDiffEntries], fmt.Sprintf("... and %d more", len(diff)-maxDriftDiffEntries))
+	}
+	return diff, sets.List(managers), nil
+}
+
 type applyObj func(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error
 
 // apply reconciles unstructured objects using applyObj and aggreates the error if present.
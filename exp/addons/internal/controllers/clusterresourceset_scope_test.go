@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -249,3 +250,118 @@ func TestReconcileApplyOnceScopeApplyObj(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileApplyOnceThenOwnScopeDetectDrift(t *testing.T) {
+	resourceRef := addonsv1.ResourceRef{
+		Name: "cm",
+		Kind: "ConfigMap",
+	}
+	appliedTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	desiredObj := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "my-cm",
+				"namespace": "that-ns",
+				"labels": map[string]interface{}{
+					"foo": "bar",
+				},
+			},
+			"data": map[string]interface{}{
+				"key": "value",
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		existingObjs []client.Object
+		wantDiff     []string
+		wantManagers []string
+	}{
+		{
+			name: "no drift",
+			existingObjs: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-cm",
+						Namespace: "that-ns",
+						Labels: map[string]string{
+							"foo": "bar",
+							// Labels added by other controllers are not considered a drift.
+							"other": "label",
+						},
+					},
+					Data: map[string]string{
+						"key": "value",
+					},
+				},
+			},
+			wantDiff:     []string{},
+			wantManagers: []string{},
+		},
+		{
+			name: "object changed",
+			existingObjs: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-cm",
+						Namespace: "that-ns",
+						ManagedFields: []metav1.ManagedFieldsEntry{
+							{
+								Manager: "capi-controller-manager",
+								Time:    &appliedTime,
+							},
+							{
+								Manager: "kubectl-edit",
+								Time:    &metav1.Time{Time: appliedTime.Add(time.Minute)},
+							},
+						},
+					},
+					Data: map[string]string{
+						"key": "changed",
+					},
+				},
+			},
+			wantDiff: []string{
+				"ConfigMap that-ns/my-cm: .data.key",
+				"ConfigMap that-ns/my-cm: .metadata.labels",
+			},
+			wantManagers: []string{"kubectl-edit"},
+		},
+		{
+			name:         "object deleted",
+			wantDiff:     []string{"ConfigMap that-ns/my-cm: deleted"},
+			wantManagers: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := NewWithT(t)
+			ctx := context.Background()
+			client := fake.NewClientBuilder().WithObjects(tt.existingObjs...).Build()
+			scope := &reconcileApplyOnceThenOwnScope{
+				reconcileApplyOnceScope{
+					baseResourceReconcileScope: baseResourceReconcileScope{
+						resourceRef: resourceRef,
+						resourceSetBinding: &addonsv1.ResourceSetBinding{
+							Resources: []addonsv1.ResourceBinding{
+								{
+									ResourceRef:     resourceRef,
+									Applied:         true,
+									LastAppliedTime: &appliedTime,
+								},
+							},
+						},
+						normalizedObjs: []unstructured.Unstructured{*desiredObj.DeepCopy()},
+					},
+				},
+			}
+			diff, managers, err := scope.detectDrift(ctx, client)
+			gs.Expect(err).NotTo(HaveOccurred())
+			gs.Expect(diff).To(Equal(tt.wantDiff))
+			gs.Expect(managers).To(Equal(tt.wantManagers))
+		})
+	}
+}
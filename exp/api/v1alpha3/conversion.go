@@ -72,6 +72,15 @@ func (src *MachinePool) ConvertTo(dstRaw conversion.Hub) error {
 	}
 	dst.Spec.Template.Spec.NodeDeletionTimeout = restored.Spec.Template.Spec.NodeDeletionTimeout
 	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.Taints = restored.Spec.Template.Spec.Taints
+	dst.Spec.Template.Spec.InfrastructureReusePolicy = restored.Spec.Template.Spec.InfrastructureReusePolicy
+	dst.Spec.Template.Spec.NodeDrainOptions = restored.Spec.Template.Spec.NodeDrainOptions
+	dst.Spec.Template.Spec.AuxiliaryInfrastructureRefs = restored.Spec.Template.Spec.AuxiliaryInfrastructureRefs
+	dst.Status.Selector = restored.Status.Selector
+	dst.Status.Capacity = restored.Status.Capacity
+	dst.Status.NodeLabels = restored.Status.NodeLabels
+	dst.Status.NodeTaints = restored.Status.NodeTaints
+	dst.Status.FailureDomainDistribution = restored.Status.FailureDomainDistribution
 	return nil
 }
 
@@ -96,3 +105,8 @@ func (dst *MachinePoolList) ConvertFrom(srcRaw conversion.Hub) error {
 
 	return Convert_v1beta1_MachinePoolList_To_v1alpha3_MachinePoolList(src, dst, nil)
 }
+
+func Convert_v1beta1_MachinePoolStatus_To_v1alpha3_MachinePoolStatus(in *expv1.MachinePoolStatus, out *MachinePoolStatus, s apimachineryconversion.Scope) error {
+	// .Selector, .Capacity, .NodeLabels, .NodeTaints and .FailureDomainDistribution were added in v1beta1.
+	return autoConvert_v1beta1_MachinePoolStatus_To_v1alpha3_MachinePoolStatus(in, out, s)
+}
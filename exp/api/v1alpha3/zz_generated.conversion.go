@@ -211,6 +211,7 @@ func Convert_v1alpha3_MachinePoolStatus_To_v1beta1_MachinePoolStatus(in *Machine
 func autoConvert_v1beta1_MachinePoolStatus_To_v1alpha3_MachinePoolStatus(in *v1beta1.MachinePoolStatus, out *MachinePoolStatus, s conversion.Scope) error {
 	out.NodeRefs = *(*[]v1.ObjectReference)(unsafe.Pointer(&in.NodeRefs))
 	out.Replicas = in.Replicas
+	// WARNING: in.Selector requires manual conversion: does not exist in peer-type
 	out.ReadyReplicas = in.ReadyReplicas
 	out.AvailableReplicas = in.AvailableReplicas
 	out.UnavailableReplicas = in.UnavailableReplicas
@@ -220,6 +221,10 @@ func autoConvert_v1beta1_MachinePoolStatus_To_v1alpha3_MachinePoolStatus(in *v1b
 	out.BootstrapReady = in.BootstrapReady
 	out.InfrastructureReady = in.InfrastructureReady
 	out.ObservedGeneration = in.ObservedGeneration
+	// WARNING: in.Capacity requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeLabels requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeTaints requires manual conversion: does not exist in peer-type
+	// WARNING: in.FailureDomainDistribution requires manual conversion: does not exist in peer-type
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(apiv1alpha3.Conditions, len(*in))
@@ -233,8 +238,3 @@ func autoConvert_v1beta1_MachinePoolStatus_To_v1alpha3_MachinePoolStatus(in *v1b
 	}
 	return nil
 }
-
-// Convert_v1beta1_MachinePoolStatus_To_v1alpha3_MachinePoolStatus is an autogenerated conversion function.
-func Convert_v1beta1_MachinePoolStatus_To_v1alpha3_MachinePoolStatus(in *v1beta1.MachinePoolStatus, out *MachinePoolStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_MachinePoolStatus_To_v1alpha3_MachinePoolStatus(in, out, s)
-}
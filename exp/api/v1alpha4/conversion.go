@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apimachineryconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
@@ -37,6 +38,15 @@ func (src *MachinePool) ConvertTo(dstRaw conversion.Hub) error {
 	}
 	dst.Spec.Template.Spec.NodeDeletionTimeout = restored.Spec.Template.Spec.NodeDeletionTimeout
 	dst.Spec.Template.Spec.NodeVolumeDetachTimeout = restored.Spec.Template.Spec.NodeVolumeDetachTimeout
+	dst.Spec.Template.Spec.Taints = restored.Spec.Template.Spec.Taints
+	dst.Spec.Template.Spec.InfrastructureReusePolicy = restored.Spec.Template.Spec.InfrastructureReusePolicy
+	dst.Spec.Template.Spec.NodeDrainOptions = restored.Spec.Template.Spec.NodeDrainOptions
+	dst.Spec.Template.Spec.AuxiliaryInfrastructureRefs = restored.Spec.Template.Spec.AuxiliaryInfrastructureRefs
+	dst.Status.Selector = restored.Status.Selector
+	dst.Status.Capacity = restored.Status.Capacity
+	dst.Status.NodeLabels = restored.Status.NodeLabels
+	dst.Status.NodeTaints = restored.Status.NodeTaints
+	dst.Status.FailureDomainDistribution = restored.Status.FailureDomainDistribution
 	return nil
 }
 
@@ -60,3 +70,8 @@ func (dst *MachinePoolList) ConvertFrom(srcRaw conversion.Hub) error {
 
 	return Convert_v1beta1_MachinePoolList_To_v1alpha4_MachinePoolList(src, dst, nil)
 }
+
+func Convert_v1beta1_MachinePoolStatus_To_v1alpha4_MachinePoolStatus(in *expv1.MachinePoolStatus, out *MachinePoolStatus, s apimachineryconversion.Scope) error {
+	// .Selector, .Capacity, .NodeLabels, .NodeTaints and .FailureDomainDistribution were added in v1beta1.
+	return autoConvert_v1beta1_MachinePoolStatus_To_v1alpha4_MachinePoolStatus(in, out, s)
+}
@@ -28,3 +28,20 @@ const (
 	// to be ready.
 	WaitingForReplicasReadyReason = "WaitingForReplicasReady"
 )
+
+const (
+	// FailureDomainsSpreadCondition reports whether the replicas of the MachinePool are spread across the targeted
+	// failure domains, i.e. the failure domains in spec.failureDomains or, if empty, all the failure domains of the Cluster,
+	// according to the failure domain distribution reported by the infrastructure provider.
+	// NOTE: Unless the MachinePool targets failure domains not defined in the Cluster, the condition is not set
+	// if the infrastructure provider does not report the failure domain distribution.
+	FailureDomainsSpreadCondition clusterv1.ConditionType = "FailureDomainsSpread"
+
+	// UnknownFailureDomainsReason (Severity=Warning) documents a MachinePool targeting failure domains
+	// not defined in the Cluster.
+	UnknownFailureDomainsReason = "UnknownFailureDomains"
+
+	// FailureDomainsNotSpreadReason (Severity=Info) documents a MachinePool with replicas outside the targeted
+	// failure domains, or not evenly spread across them.
+	FailureDomainsNotSpreadReason = "FailureDomainsNotSpread"
+)
@@ -58,6 +58,8 @@ type MachinePoolSpec struct {
 	ProviderIDList []string `json:"providerIDList,omitempty"`
 
 	// FailureDomains is the list of failure domains this MachinePool should be attached to.
+	// Infrastructure providers are expected to spread the replicas of the MachinePool across these failure domains,
+	// or across all the failure domains of the Cluster if empty.
 	// +optional
 	FailureDomains []string `json:"failureDomains,omitempty"`
 }
@@ -76,6 +78,12 @@ type MachinePoolStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas"`
 
+	// Selector is the same as the label selector but in the string format to avoid introspection
+	// by clients. The string will be in the same format as the query-param syntax.
+	// More info about label selectors: http://kubernetes.io/docs/user-guide/labels#label-selectors
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
 	// The number of ready replicas for this MachinePool. A machine is considered ready when the node has been created and is "Ready".
 	// +optional
 	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
@@ -119,6 +127,31 @@ type MachinePoolStatus struct {
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// Capacity is the resource capacity of a single node of the MachinePool, as reported by the infrastructure
+	// provider in status.capacity of the InfraMachinePool; it is used by the cluster autoscaler for scaling
+	// the MachinePool from zero replicas.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+
+	// NodeLabels are the labels of the nodes of the MachinePool, as reported by the infrastructure provider
+	// in status.nodeLabels of the InfraMachinePool; it is used by the cluster autoscaler for scaling
+	// the MachinePool from zero replicas.
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+	// NodeTaints are the taints of the nodes of the MachinePool, as reported by the infrastructure provider
+	// in status.nodeTaints of the InfraMachinePool; it is used by the cluster autoscaler for scaling
+	// the MachinePool from zero replicas.
+	// +optional
+	NodeTaints []corev1.Taint `json:"nodeTaints,omitempty"`
+
+	// FailureDomainDistribution is the number of replicas of the MachinePool in each failure domain, as reported by the
+	// infrastructure provider in status.failureDomainDistribution of the InfraMachinePool.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	FailureDomainDistribution []MachinePoolFailureDomainReplicas `json:"failureDomainDistribution,omitempty"`
+
 	// Conditions define the current service state of the MachinePool.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
@@ -126,6 +159,15 @@ type MachinePoolStatus struct {
 
 // ANCHOR_END: MachinePoolStatus
 
+// MachinePoolFailureDomainReplicas is the number of replicas of a MachinePool in a failure domain.
+type MachinePoolFailureDomainReplicas struct {
+	// Name is the name of the failure domain.
+	Name string `json:"name"`
+
+	// Replicas is the number of replicas of the MachinePool in the failure domain.
+	Replicas int32 `json:"replicas"`
+}
+
 // MachinePoolPhase is a string representation of a MachinePool Phase.
 //
 // This type is a high-level indicator of the status of the MachinePool as it is provisioned,
@@ -208,7 +250,7 @@ func (m *MachinePoolStatus) GetTypedPhase() MachinePoolPhase {
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=machinepools,shortName=mp,scope=Namespaced,categories=cluster-api
 // +kubebuilder:subresource:status
-// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Cluster"
 // +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=".spec.replicas",description="Total number of machines desired by this MachinePool",priority=10
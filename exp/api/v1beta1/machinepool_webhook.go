@@ -136,6 +136,15 @@ func (m *MachinePool) validate(old *MachinePool) error {
 		)
 	}
 
+	if len(m.Spec.Template.Spec.AuxiliaryInfrastructureRefs) > 0 {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(
+				specPath.Child("template", "spec", "auxiliaryInfrastructureRefs"),
+				"auxiliary infrastructure objects are not supported in MachinePools"),
+		)
+	}
+
 	if old != nil && old.Spec.ClusterName != m.Spec.ClusterName {
 		allErrs = append(
 			allErrs,
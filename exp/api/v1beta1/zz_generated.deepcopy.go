@@ -55,6 +55,21 @@ func (in *MachinePool) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolFailureDomainReplicas) DeepCopyInto(out *MachinePoolFailureDomainReplicas) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolFailureDomainReplicas.
+func (in *MachinePoolFailureDomainReplicas) DeepCopy() *MachinePoolFailureDomainReplicas {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolFailureDomainReplicas)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachinePoolList) DeepCopyInto(out *MachinePoolList) {
 	*out = *in
@@ -141,6 +156,32 @@ func (in *MachinePoolStatus) DeepCopyInto(out *MachinePoolStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeTaints != nil {
+		in, out := &in.NodeTaints, &out.NodeTaints
+		*out = make([]v1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailureDomainDistribution != nil {
+		in, out := &in.FailureDomainDistribution, &out.FailureDomainDistribution
+		*out = make([]MachinePoolFailureDomainReplicas, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(apiv1beta1.Conditions, len(*in))
@@ -26,6 +26,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -44,10 +45,12 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	capilabels "sigs.k8s.io/cluster-api/internal/labels"
+	"sigs.k8s.io/cluster-api/internal/util/autoscaler"
 	"sigs.k8s.io/cluster-api/util"
-	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/paused"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
 
@@ -92,7 +95,10 @@ func (r *MachinePoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.M
 			// TODO: should this wait for Cluster.Status.InfrastructureReady similar to Infra Machine resources?
 			builder.WithPredicates(
 				predicates.All(ctrl.LoggerFrom(ctx),
-					predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+					predicates.Any(ctrl.LoggerFrom(ctx),
+						predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+						predicates.ClusterPauseChanged(ctrl.LoggerFrom(ctx)),
+					),
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			),
@@ -131,10 +137,21 @@ func (r *MachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			mp.Spec.ClusterName, mp.Name, mp.Namespace)
 	}
 
+	// Exclude the MachinePool from autoscaling while autoscaling is paused, including when the object or the Cluster
+	// are paused, so the autoscaler does not fight with upgrades or with objects not being reconciled.
+	autoscalerRequeueAfter, err := autoscaler.ReconcilePause(ctx, r.Client, cluster, mp)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Return early if the object or Cluster is paused.
-	if annotations.IsPaused(cluster, mp) {
+	isPaused, requeueAfter, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, mp)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if isPaused {
 		log.Info("Reconciliation is paused for this object")
-		return ctrl.Result{}, nil
+		return util.LowestNonZeroResult(ctrl.Result{RequeueAfter: requeueAfter}, ctrl.Result{RequeueAfter: autoscalerRequeueAfter}), nil
 	}
 
 	// Initialize the patch helper.
@@ -168,6 +185,7 @@ func (r *MachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 					clusterv1.BootstrapReadyCondition,
 					clusterv1.InfrastructureReadyCondition,
 					expv1.ReplicasReadyCondition,
+					expv1.FailureDomainsSpreadCondition,
 				}},
 			)
 		}
@@ -194,7 +212,8 @@ func (r *MachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	// Handle normal reconciliation loop.
-	return r.reconcile(ctx, cluster, mp)
+	result, err := r.reconcile(ctx, cluster, mp)
+	return util.LowestNonZeroResult(result, ctrl.Result{RequeueAfter: autoscalerRequeueAfter}), err
 }
 
 func (r *MachinePoolReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster, mp *expv1.MachinePool) (ctrl.Result, error) {
@@ -206,6 +225,10 @@ func (r *MachinePoolReconciler) reconcile(ctx context.Context, cluster *clusterv
 		UID:        cluster.UID,
 	}))
 
+	// Copy label selector to its status counterpart in string format.
+	// This is necessary for CRDs including scale subresources.
+	mp.Status.Selector = machinePoolSelector(mp).String()
+
 	phases := []func(context.Context, *clusterv1.Cluster, *expv1.MachinePool) (ctrl.Result, error){
 		r.reconcileBootstrap,
 		r.reconcileInfrastructure,
@@ -229,6 +252,19 @@ func (r *MachinePoolReconciler) reconcile(ctx context.Context, cluster *clusterv
 	return res, kerrors.NewAggregate(errs)
 }
 
+// machinePoolSelector returns the label selector for the objects controlled by a MachinePool.
+func machinePoolSelector(mp *expv1.MachinePool) labels.Selector {
+	return labels.SelectorFromSet(machinePoolLabels(mp))
+}
+
+// machinePoolLabels returns the labels set on the objects controlled by a MachinePool, i.e. its Nodes.
+func machinePoolLabels(mp *expv1.MachinePool) map[string]string {
+	return map[string]string{
+		clusterv1.ClusterNameLabel:     mp.Spec.ClusterName,
+		clusterv1.MachinePoolNameLabel: capilabels.MustFormatValue(mp.Name),
+	}
+}
+
 func (r *MachinePoolReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster, mp *expv1.MachinePool) (ctrl.Result, error) {
 	if ok, err := r.reconcileDeleteExternal(ctx, mp); !ok || err != nil {
 		// Return early and don't remove the finalizer if we got an error or
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+// Annotations used by the cluster autoscaler for scaling node groups from zero replicas.
+// NOTE: The annotations are defined by the cluster autoscaler.
+const (
+	autoscalerCapacityCPUAnnotation           = "capacity.cluster-autoscaler.kubernetes.io/cpu"
+	autoscalerCapacityMemoryAnnotation        = "capacity.cluster-autoscaler.kubernetes.io/memory"
+	autoscalerCapacityEphemeralDiskAnnotation = "capacity.cluster-autoscaler.kubernetes.io/ephemeral-disk"
+	autoscalerCapacityMaxPodsAnnotation       = "capacity.cluster-autoscaler.kubernetes.io/maxPods"
+	autoscalerCapacityGPUTypeAnnotation       = "capacity.cluster-autoscaler.kubernetes.io/gpu-type"
+	autoscalerCapacityGPUCountAnnotation      = "capacity.cluster-autoscaler.kubernetes.io/gpu-count"
+	autoscalerCapacityLabelsAnnotation        = "capacity.cluster-autoscaler.kubernetes.io/labels"
+	autoscalerCapacityTaintsAnnotation        = "capacity.cluster-autoscaler.kubernetes.io/taints"
+)
+
+// reconcileAutoscalerCapacity sets the node capacity, labels and taints reported by the infrastructure provider in the
+// MachinePool status, and the corresponding cluster autoscaler annotations, so the cluster autoscaler can scale the
+// MachinePool from zero replicas.
+// NOTE: Annotations set by users take precedence over the information reported by the infrastructure provider,
+// so annotations are only set if missing or if they are still set to the value previously computed by this function.
+func reconcileAutoscalerCapacity(mp *expv1.MachinePool, infraConfig *unstructured.Unstructured) error {
+	previous := autoscalerCapacityAnnotations(mp.Status)
+
+	var capacity corev1.ResourceList
+	if err := util.UnstructuredUnmarshalField(infraConfig, &capacity, "status", "capacity"); err != nil && !errors.Is(err, util.ErrUnstructuredFieldNotFound) {
+		return errors.Wrapf(err, "failed to retrieve capacity from infrastructure provider for MachinePool %q in namespace %q", mp.Name, mp.Namespace)
+	}
+	var nodeLabels map[string]string
+	if err := util.UnstructuredUnmarshalField(infraConfig, &nodeLabels, "status", "nodeLabels"); err != nil && !errors.Is(err, util.ErrUnstructuredFieldNotFound) {
+		return errors.Wrapf(err, "failed to retrieve node labels from infrastructure provider for MachinePool %q in namespace %q", mp.Name, mp.Namespace)
+	}
+	var nodeTaints []corev1.Taint
+	if err := util.UnstructuredUnmarshalField(infraConfig, &nodeTaints, "status", "nodeTaints"); err != nil && !errors.Is(err, util.ErrUnstructuredFieldNotFound) {
+		return errors.Wrapf(err, "failed to retrieve node taints from infrastructure provider for MachinePool %q in namespace %q", mp.Name, mp.Namespace)
+	}
+	// Sort taints so the status does not change when the infrastructure provider reports them in a different order.
+	sort.SliceStable(nodeTaints, func(i, j int) bool {
+		if nodeTaints[i].Key != nodeTaints[j].Key {
+			return nodeTaints[i].Key < nodeTaints[j].Key
+		}
+		if nodeTaints[i].Effect != nodeTaints[j].Effect {
+			return nodeTaints[i].Effect < nodeTaints[j].Effect
+		}
+		return nodeTaints[i].Value < nodeTaints[j].Value
+	})
+	mp.Status.Capacity = capacity
+	mp.Status.NodeLabels = nodeLabels
+	mp.Status.NodeTaints = nodeTaints
+
+	desired := autoscalerCapacityAnnotations(mp.Status)
+	annotations := mp.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for _, key := range []string{
+		autoscalerCapacityCPUAnnotation,
+		autoscalerCapacityMemoryAnnotation,
+		autoscalerCapacityEphemeralDiskAnnotation,
+		autoscalerCapacityMaxPodsAnnotation,
+		autoscalerCapacityGPUTypeAnnotation,
+		autoscalerCapacityGPUCountAnnotation,
+		autoscalerCapacityLabelsAnnotation,
+		autoscalerCapacityTaintsAnnotation,
+	} {
+		// Preserve annotations set by users.
+		if current, ok := annotations[key]; ok && current != previous[key] {
+			continue
+		}
+		if value, ok := desired[key]; ok {
+			annotations[key] = value
+			continue
+		}
+		delete(annotations, key)
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	mp.SetAnnotations(annotations)
+	return nil
+}
+
+// autoscalerCapacityAnnotations returns the cluster autoscaler annotations corresponding to the node capacity,
+// labels and taints in the MachinePool status.
+func autoscalerCapacityAnnotations(status expv1.MachinePoolStatus) map[string]string {
+	annotations := map[string]string{}
+
+	resourceAnnotations := map[corev1.ResourceName]string{
+		corev1.ResourceCPU:              autoscalerCapacityCPUAnnotation,
+		corev1.ResourceMemory:           autoscalerCapacityMemoryAnnotation,
+		corev1.ResourceEphemeralStorage: autoscalerCapacityEphemeralDiskAnnotation,
+		corev1.ResourcePods:             autoscalerCapacityMaxPodsAnnotation,
+	}
+	gpuTypes := []string{}
+	for name, quantity := range status.Capacity {
+		if annotation, ok := resourceAnnotations[name]; ok {
+			annotations[annotation] = quantity.String()
+			continue
+		}
+		// Extended resources like nvidia.com/gpu are reported as GPUs.
+		if strings.HasSuffix(string(name), "/gpu") {
+			gpuTypes = append(gpuTypes, string(name))
+		}
+	}
+	// The cluster autoscaler supports only one GPU type; pick the first one so the result is stable.
+	if len(gpuTypes) > 0 {
+		sort.Strings(gpuTypes)
+		quantity := status.Capacity[corev1.ResourceName(gpuTypes[0])]
+		annotations[autoscalerCapacityGPUTypeAnnotation] = gpuTypes[0]
+		annotations[autoscalerCapacityGPUCountAnnotation] = quantity.String()
+	}
+
+	if len(status.NodeLabels) > 0 {
+		labels := make([]string, 0, len(status.NodeLabels))
+		for key, value := range status.NodeLabels {
+			labels = append(labels, fmt.Sprintf("%s=%s", key, value))
+		}
+		sort.Strings(labels)
+		annotations[autoscalerCapacityLabelsAnnotation] = strings.Join(labels, ",")
+	}
+
+	if len(status.NodeTaints) > 0 {
+		taints := make([]string, 0, len(status.NodeTaints))
+		for _, taint := range status.NodeTaints {
+			taints = append(taints, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+		}
+		sort.Strings(taints)
+		annotations[autoscalerCapacityTaintsAnnotation] = strings.Join(taints, ",")
+	}
+
+	return annotations
+}
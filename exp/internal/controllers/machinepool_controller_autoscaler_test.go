@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+func TestReconcileAutoscalerCapacity(t *testing.T) {
+	infraConfig := func(status map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachinePool",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"status": status,
+			},
+		}
+	}
+
+	t.Run("sets capacity, labels and taints reported by the infrastructure provider", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mp := &expv1.MachinePool{ObjectMeta: metav1.ObjectMeta{Name: "mp", Namespace: metav1.NamespaceDefault}}
+		g.Expect(reconcileAutoscalerCapacity(mp, infraConfig(map[string]interface{}{
+			"capacity": map[string]interface{}{
+				"cpu":            "4",
+				"memory":         "16Gi",
+				"nvidia.com/gpu": "2",
+			},
+			"nodeLabels": map[string]interface{}{
+				"b": "2",
+				"a": "1",
+			},
+			"nodeTaints": []interface{}{
+				map[string]interface{}{"key": "gpu", "value": "true", "effect": "NoSchedule"},
+				map[string]interface{}{"key": "dedicated", "value": "ml", "effect": "NoExecute"},
+			},
+		}))).To(Succeed())
+
+		g.Expect(mp.Status.Capacity).To(HaveKeyWithValue(BeEquivalentTo("memory"), resource.MustParse("16Gi")))
+		g.Expect(mp.Status.NodeLabels).To(HaveLen(2))
+		g.Expect(mp.Status.NodeTaints).To(HaveLen(2))
+		g.Expect(mp.Status.NodeTaints[0].Key).To(Equal("dedicated"))
+		g.Expect(mp.Annotations).To(Equal(map[string]string{
+			autoscalerCapacityCPUAnnotation:      "4",
+			autoscalerCapacityMemoryAnnotation:   "16Gi",
+			autoscalerCapacityGPUTypeAnnotation:  "nvidia.com/gpu",
+			autoscalerCapacityGPUCountAnnotation: "2",
+			autoscalerCapacityLabelsAnnotation:   "a=1,b=2",
+			autoscalerCapacityTaintsAnnotation:   "dedicated=ml:NoExecute,gpu=true:NoSchedule",
+		}))
+
+		// Annotations are updated when the capacity changes, and removed when it is not reported anymore.
+		g.Expect(reconcileAutoscalerCapacity(mp, infraConfig(map[string]interface{}{
+			"capacity": map[string]interface{}{
+				"cpu": "8",
+			},
+		}))).To(Succeed())
+		g.Expect(mp.Status.NodeLabels).To(BeNil())
+		g.Expect(mp.Annotations).To(Equal(map[string]string{
+			autoscalerCapacityCPUAnnotation: "8",
+		}))
+
+		g.Expect(reconcileAutoscalerCapacity(mp, infraConfig(map[string]interface{}{}))).To(Succeed())
+		g.Expect(mp.Status.Capacity).To(BeNil())
+		g.Expect(mp.Annotations).To(BeEmpty())
+	})
+
+	t.Run("preserves annotations set by users", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mp := &expv1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "mp",
+				Namespace: metav1.NamespaceDefault,
+				Annotations: map[string]string{
+					autoscalerCapacityMemoryAnnotation: "8Gi",
+					"foo":                              "bar",
+				},
+			},
+		}
+		g.Expect(reconcileAutoscalerCapacity(mp, infraConfig(map[string]interface{}{
+			"capacity": map[string]interface{}{
+				"cpu":    "4",
+				"memory": "16Gi",
+			},
+		}))).To(Succeed())
+		g.Expect(mp.Annotations).To(Equal(map[string]string{
+			autoscalerCapacityCPUAnnotation:    "4",
+			autoscalerCapacityMemoryAnnotation: "8Gi",
+			"foo":                              "bar",
+		}))
+	})
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileFailureDomainDistribution sets the failure domain distribution reported by the infrastructure provider in the
+// MachinePool status, and the FailureDomainsSpread condition reporting if the replicas of the MachinePool are spread across
+// the targeted failure domains, i.e. the failure domains in spec.failureDomains or, if empty, all the failure domains of the Cluster.
+func reconcileFailureDomainDistribution(cluster *clusterv1.Cluster, mp *expv1.MachinePool, infraConfig *unstructured.Unstructured) error {
+	var distribution []expv1.MachinePoolFailureDomainReplicas
+	if err := util.UnstructuredUnmarshalField(infraConfig, &distribution, "status", "failureDomainDistribution"); err != nil && !errors.Is(err, util.ErrUnstructuredFieldNotFound) {
+		return errors.Wrapf(err, "failed to retrieve failure domain distribution from infrastructure provider for MachinePool %q in namespace %q", mp.Name, mp.Namespace)
+	}
+	sort.Slice(distribution, func(i, j int) bool {
+		return distribution[i].Name < distribution[j].Name
+	})
+	mp.Status.FailureDomainDistribution = distribution
+
+	// Surface failure domains targeted by the MachinePool but not defined in the Cluster; this check is skipped if the
+	// Cluster does not report failure domains, e.g. when the infrastructure provider does not support them.
+	if len(cluster.Status.FailureDomains) > 0 {
+		unknown := []string{}
+		for _, fd := range mp.Spec.FailureDomains {
+			if _, ok := cluster.Status.FailureDomains[fd]; !ok {
+				unknown = append(unknown, fd)
+			}
+		}
+		if len(unknown) > 0 {
+			conditions.MarkFalse(mp, expv1.FailureDomainsSpreadCondition, expv1.UnknownFailureDomainsReason, clusterv1.ConditionSeverityWarning,
+				"Failure domains %s are not defined in the Cluster", strings.Join(unknown, ", "))
+			return nil
+		}
+	}
+
+	if distribution == nil {
+		conditions.Delete(mp, expv1.FailureDomainsSpreadCondition)
+		return nil
+	}
+
+	targeted := sets.New[string](mp.Spec.FailureDomains...)
+	if targeted.Len() == 0 {
+		for fd := range cluster.Status.FailureDomains {
+			targeted.Insert(fd)
+		}
+	}
+
+	replicas := map[string]int32{}
+	outside := []string{}
+	for _, d := range distribution {
+		if d.Replicas <= 0 {
+			continue
+		}
+		if targeted.Len() > 0 && !targeted.Has(d.Name) {
+			outside = append(outside, d.Name)
+		}
+		replicas[d.Name] = d.Replicas
+	}
+	if len(outside) > 0 {
+		conditions.MarkFalse(mp, expv1.FailureDomainsSpreadCondition, expv1.FailureDomainsNotSpreadReason, clusterv1.ConditionSeverityInfo,
+			"Replicas are running in failure domains %s not targeted by the MachinePool", strings.Join(outside, ", "))
+		return nil
+	}
+
+	// Replicas are considered spread if the difference between the most and the least populated failure domain is at most one.
+	if targeted.Len() > 1 {
+		var minReplicas, maxReplicas int32 = -1, 0
+		distributionSummary := []string{}
+		for _, fd := range sets.List(targeted) {
+			r := replicas[fd]
+			if minReplicas < 0 || r < minReplicas {
+				minReplicas = r
+			}
+			if r > maxReplicas {
+				maxReplicas = r
+			}
+			distributionSummary = append(distributionSummary, fmt.Sprintf("%s=%d", fd, r))
+		}
+		if maxReplicas-minReplicas > 1 {
+			conditions.MarkFalse(mp, expv1.FailureDomainsSpreadCondition, expv1.FailureDomainsNotSpreadReason, clusterv1.ConditionSeverityInfo,
+				"Replicas are not evenly spread across failure domains: %s", strings.Join(distributionSummary, ", "))
+			return nil
+		}
+	}
+
+	conditions.MarkTrue(mp, expv1.FailureDomainsSpreadCondition)
+	return nil
+}
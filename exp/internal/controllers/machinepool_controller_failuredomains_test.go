@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileFailureDomainDistribution(t *testing.T) {
+	infraConfig := func(distribution ...interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachinePool",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"status": map[string]interface{}{},
+			},
+		}
+		if distribution != nil {
+			obj.Object["status"] = map[string]interface{}{"failureDomainDistribution": distribution}
+		}
+		return obj
+	}
+	fd := func(name string, replicas int64) interface{} {
+		return map[string]interface{}{"name": name, "replicas": replicas}
+	}
+	cluster := &clusterv1.Cluster{
+		Status: clusterv1.ClusterStatus{
+			FailureDomains: clusterv1.FailureDomains{
+				"fd1": clusterv1.FailureDomainSpec{},
+				"fd2": clusterv1.FailureDomainSpec{},
+				"fd3": clusterv1.FailureDomainSpec{},
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		failureDomains   []string
+		infraConfig      *unstructured.Unstructured
+		wantDistribution []expv1.MachinePoolFailureDomainReplicas
+		wantCondition    *clusterv1.Condition
+	}{
+		{
+			name:        "does not set the condition if the distribution is not reported",
+			infraConfig: infraConfig(),
+		},
+		{
+			name:           "reports unknown failure domains even if the distribution is not reported",
+			failureDomains: []string{"fd1", "fd4"},
+			infraConfig:    infraConfig(),
+			wantCondition:  conditions.FalseCondition(expv1.FailureDomainsSpreadCondition, expv1.UnknownFailureDomainsReason, clusterv1.ConditionSeverityWarning, "Failure domains fd4 are not defined in the Cluster"),
+		},
+		{
+			name:             "replicas spread across all the failure domains of the Cluster",
+			infraConfig:      infraConfig(fd("fd3", 1), fd("fd1", 2), fd("fd2", 2)),
+			wantDistribution: []expv1.MachinePoolFailureDomainReplicas{{Name: "fd1", Replicas: 2}, {Name: "fd2", Replicas: 2}, {Name: "fd3", Replicas: 1}},
+			wantCondition:    conditions.TrueCondition(expv1.FailureDomainsSpreadCondition),
+		},
+		{
+			name:             "replicas not evenly spread across all the failure domains of the Cluster",
+			infraConfig:      infraConfig(fd("fd1", 3), fd("fd2", 1)),
+			wantDistribution: []expv1.MachinePoolFailureDomainReplicas{{Name: "fd1", Replicas: 3}, {Name: "fd2", Replicas: 1}},
+			wantCondition:    conditions.FalseCondition(expv1.FailureDomainsSpreadCondition, expv1.FailureDomainsNotSpreadReason, clusterv1.ConditionSeverityInfo, "Replicas are not evenly spread across failure domains: fd1=3, fd2=1, fd3=0"),
+		},
+		{
+			name:             "replicas spread across the targeted failure domains",
+			failureDomains:   []string{"fd1", "fd2"},
+			infraConfig:      infraConfig(fd("fd1", 2), fd("fd2", 1), fd("fd3", 0)),
+			wantDistribution: []expv1.MachinePoolFailureDomainReplicas{{Name: "fd1", Replicas: 2}, {Name: "fd2", Replicas: 1}, {Name: "fd3", Replicas: 0}},
+			wantCondition:    conditions.TrueCondition(expv1.FailureDomainsSpreadCondition),
+		},
+		{
+			name:             "replicas outside the targeted failure domains",
+			failureDomains:   []string{"fd1"},
+			infraConfig:      infraConfig(fd("fd1", 2), fd("fd3", 1)),
+			wantDistribution: []expv1.MachinePoolFailureDomainReplicas{{Name: "fd1", Replicas: 2}, {Name: "fd3", Replicas: 1}},
+			wantCondition:    conditions.FalseCondition(expv1.FailureDomainsSpreadCondition, expv1.FailureDomainsNotSpreadReason, clusterv1.ConditionSeverityInfo, "Replicas are running in failure domains fd3 not targeted by the MachinePool"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mp := &expv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "mp", Namespace: metav1.NamespaceDefault},
+				Spec:       expv1.MachinePoolSpec{FailureDomains: tt.failureDomains},
+			}
+			// Start with a stale condition, to check it is updated or removed.
+			conditions.MarkTrue(mp, expv1.FailureDomainsSpreadCondition)
+
+			g.Expect(reconcileFailureDomainDistribution(cluster, mp, tt.infraConfig)).To(Succeed())
+			g.Expect(mp.Status.FailureDomainDistribution).To(Equal(tt.wantDistribution))
+
+			got := conditions.Get(mp, expv1.FailureDomainsSpreadCondition)
+			if tt.wantCondition == nil {
+				g.Expect(got).To(BeNil())
+				return
+			}
+			g.Expect(got).ToNot(BeNil())
+			g.Expect(got.Status).To(Equal(tt.wantCondition.Status))
+			g.Expect(got.Reason).To(Equal(tt.wantCondition.Reason))
+			g.Expect(got.Severity).To(Equal(tt.wantCondition.Severity))
+			g.Expect(got.Message).To(Equal(tt.wantCondition.Message))
+			if tt.wantCondition.Status == corev1.ConditionTrue {
+				g.Expect(got.Reason).To(BeEmpty())
+			}
+		})
+	}
+}
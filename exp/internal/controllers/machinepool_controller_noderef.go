@@ -23,6 +23,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -209,7 +210,8 @@ func (r *MachinePoolReconciler) getNodeReferences(ctx context.Context, c client.
 	return getNodeReferencesResult{nodeRefs, available, ready}, nil
 }
 
-// patchNodes patches the nodes with the cluster name and cluster namespace annotations.
+// patchNodes patches the nodes with the cluster name and cluster namespace annotations, and with the labels
+// matched by the selector of the MachinePool.
 func (r *MachinePoolReconciler) patchNodes(ctx context.Context, c client.Client, references []corev1.ObjectReference, mp *expv1.MachinePool) error {
 	log := ctrl.LoggerFrom(ctx)
 	for _, nodeRef := range references {
@@ -228,11 +230,12 @@ func (r *MachinePoolReconciler) patchNodes(ctx context.Context, c client.Client,
 			clusterv1.OwnerKindAnnotation:        mp.Kind,
 			clusterv1.OwnerNameAnnotation:        mp.Name,
 		}
-		// Add annotations and drop NodeUninitializedTaint.
+		// Add annotations and labels and drop NodeUninitializedTaint.
 		hasAnnotationChanges := annotations.AddAnnotations(node, desired)
+		hasLabelChanges := addLabels(node, machinePoolLabels(mp))
 		hasTaintChanges := taints.RemoveNodeTaint(node, clusterv1.NodeUninitializedTaint)
 		// Patch the node if needed.
-		if hasAnnotationChanges || hasTaintChanges {
+		if hasAnnotationChanges || hasLabelChanges || hasTaintChanges {
 			if err := patchHelper.Patch(ctx, node); err != nil {
 				log.V(2).Info("Failed patch node to set annotations and drop taints", "err", err, "node name", node.Name)
 				return err
@@ -242,6 +245,23 @@ func (r *MachinePoolReconciler) patchNodes(ctx context.Context, c client.Client,
 	return nil
 }
 
+// addLabels sets the desired labels on an object and returns true if the labels have been changed.
+func addLabels(o metav1.Object, desired map[string]string) bool {
+	labels := o.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	hasChanged := false
+	for k, v := range desired {
+		if cur, ok := labels[k]; !ok || cur != v {
+			labels[k] = v
+			hasChanged = true
+		}
+	}
+	o.SetLabels(labels)
+	return hasChanged
+}
+
 func nodeIsReady(node *corev1.Node) bool {
 	for _, n := range node.Status.Conditions {
 		if n.Type == corev1.NodeReady {
@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -315,6 +316,10 @@ func TestMachinePoolPatchNodes(t *testing.T) {
 							"cluster.x-k8s.io/owner-kind":        "MachinePool",
 							"cluster.x-k8s.io/owner-name":        "machinepool-1",
 						},
+						Labels: map[string]string{
+							"cluster.x-k8s.io/cluster-name": "cluster-1",
+							"cluster.x-k8s.io/pool-name":    "machinepool-1",
+						},
 					},
 					Spec: corev1.NodeSpec{
 						Taints: nil,
@@ -353,6 +358,10 @@ func TestMachinePoolPatchNodes(t *testing.T) {
 							"cluster.x-k8s.io/owner-name":        "machinepool-2",
 							"foo":                                "bar",
 						},
+						Labels: map[string]string{
+							"cluster.x-k8s.io/cluster-name": "cluster-1",
+							"cluster.x-k8s.io/pool-name":    "machinepool-2",
+						},
 					},
 					Spec: corev1.NodeSpec{
 						Taints: []corev1.Taint{
@@ -372,6 +381,10 @@ func TestMachinePoolPatchNodes(t *testing.T) {
 							"cluster.x-k8s.io/owner-kind":        "MachinePool",
 							"cluster.x-k8s.io/owner-name":        "machinepool-2",
 						},
+						Labels: map[string]string{
+							"cluster.x-k8s.io/cluster-name": "cluster-1",
+							"cluster.x-k8s.io/pool-name":    "machinepool-2",
+						},
 					},
 					Spec: corev1.NodeSpec{
 						Taints: []corev1.Taint{
@@ -391,6 +404,10 @@ func TestMachinePoolPatchNodes(t *testing.T) {
 							"cluster.x-k8s.io/owner-kind":        "MachinePool",
 							"cluster.x-k8s.io/owner-name":        "machinepool-2",
 						},
+						Labels: map[string]string{
+							"cluster.x-k8s.io/cluster-name": "cluster-1",
+							"cluster.x-k8s.io/pool-name":    "machinepool-2",
+						},
 					},
 					Spec: corev1.NodeSpec{
 						Taints: []corev1.Taint{
@@ -425,7 +442,11 @@ func TestMachinePoolPatchNodes(t *testing.T) {
 				err := fakeClient.Get(ctx, client.ObjectKey{Name: expected.Name}, node)
 				g.Expect(err).To(BeNil())
 				g.Expect(node.Annotations).To(Equal(expected.Annotations))
+				g.Expect(node.Labels).To(Equal(expected.Labels))
 				g.Expect(node.Spec.Taints).To(Equal(expected.Spec.Taints))
+
+				// The Node must be selected by the selector exposed in the MachinePool status.
+				g.Expect(machinePoolSelector(test.machinePool).Matches(labels.Set(node.Labels))).To(BeTrue())
 			}
 		})
 	}
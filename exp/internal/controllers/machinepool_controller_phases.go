@@ -284,6 +284,18 @@ func (r *MachinePoolReconciler) reconcileInfrastructure(ctx context.Context, clu
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
 	)
 
+	// Get the node capacity, labels and taints from the infrastructure provider, if reported, so the
+	// cluster autoscaler can scale the MachinePool from zero replicas.
+	if err := reconcileAutoscalerCapacity(mp, infraConfig); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Get the failure domain distribution from the infrastructure provider, if reported, and check
+	// the replicas are spread across the targeted failure domains.
+	if err := reconcileFailureDomainDistribution(cluster, mp, infraConfig); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if !mp.Status.InfrastructureReady {
 		log.Info("Infrastructure provider is not ready, requeuing")
 		return ctrl.Result{RequeueAfter: externalReadyWait}, nil
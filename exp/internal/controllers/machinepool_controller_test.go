@@ -17,12 +17,14 @@ limitations under the License.
 package controllers
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -32,6 +34,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	capilabels "sigs.k8s.io/cluster-api/internal/labels"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -251,6 +254,43 @@ func TestMachinePoolOwnerReference(t *testing.T) {
 	}
 }
 
+func TestMachinePoolSelector(t *testing.T) {
+	tests := []struct {
+		name        string
+		machinePool *expv1.MachinePool
+		want        string
+	}{
+		{
+			name: "selects objects by cluster and MachinePool name",
+			machinePool: &expv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "mp1"},
+				Spec:       expv1.MachinePoolSpec{ClusterName: "test-cluster"},
+			},
+			want: "cluster.x-k8s.io/cluster-name=test-cluster,cluster.x-k8s.io/pool-name=mp1",
+		},
+		{
+			name: "hashes MachinePool names longer than 63 characters",
+			machinePool: &expv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 64)},
+				Spec:       expv1.MachinePoolSpec{ClusterName: "test-cluster"},
+			},
+			want: "cluster.x-k8s.io/cluster-name=test-cluster,cluster.x-k8s.io/pool-name=" + capilabels.MustFormatValue(strings.Repeat("a", 64)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			selector := machinePoolSelector(tt.machinePool)
+			g.Expect(selector.String()).To(Equal(tt.want))
+
+			// The selector must be valid, given that it is exposed via the scale subresource.
+			_, err := labels.Parse(selector.String())
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
 func TestReconcileMachinePoolRequest(t *testing.T) {
 	infraConfig := unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -0,0 +1,41 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+// Conditions and condition Reasons for the IPAddressClaim object.
+
+const (
+	// AddressAllocatedCondition reports whether an IPAddress has been allocated for the IPAddressClaim.
+	AddressAllocatedCondition clusterv1.ConditionType = "AddressAllocated"
+
+	// PoolNotFoundReason (Severity=Warning) documents an IPAddressClaim referencing a pool which does not exist.
+	PoolNotFoundReason = "PoolNotFound"
+
+	// PoolExhaustedReason (Severity=Warning) documents an IPAddressClaim for which no address can be allocated
+	// because all the addresses of the pool are already in use.
+	PoolExhaustedReason = "PoolExhausted"
+
+	// PoolDeletingReason (Severity=Warning) documents an IPAddressClaim for which no address can be allocated
+	// because the pool is being deleted.
+	PoolDeletingReason = "PoolDeleting"
+
+	// InvalidPoolReason (Severity=Error) documents an IPAddressClaim for which no address can be allocated
+	// because the spec of the pool is invalid.
+	InvalidPoolReason = "InvalidPool"
+)
@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// InClusterIPPoolKind is the kind of the InClusterIPPool; it must be used in the poolRef of
+	// IPAddressClaims to allocate addresses from an InClusterIPPool.
+	InClusterIPPoolKind = "InClusterIPPool"
+
+	// InClusterIPPoolFinalizer is used to prevent the deletion of an InClusterIPPool while
+	// IPAddresses allocated from it still exist, and to release IPAddresses when IPAddressClaims are deleted.
+	InClusterIPPoolFinalizer = "ipam.cluster.x-k8s.io/in-cluster-ip-pool"
+)
+
+// InClusterIPPoolSpec defines the desired state of an InClusterIPPool.
+type InClusterIPPoolSpec struct {
+	// Addresses is the list of addresses to allocate from; each entry can be a single IP address
+	// (e.g. 10.0.0.10), a range (e.g. 10.0.0.10-10.0.0.20) or a CIDR (e.g. 10.0.0.0/24).
+	// For IPv4 CIDRs, the network and broadcast addresses are never allocated.
+	// All the addresses must belong to the same IP family.
+	// +kubebuilder:validation:MinItems=1
+	Addresses []string `json:"addresses"`
+
+	// ExcludedAddresses is the list of addresses which must not be allocated, e.g. because they
+	// are already used outside of Cluster API; each entry can be a single IP address, a range or a CIDR.
+	// +optional
+	ExcludedAddresses []string `json:"excludedAddresses,omitempty"`
+
+	// Prefix is the network prefix of the allocated addresses.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=128
+	Prefix int `json:"prefix"`
+
+	// Gateway is the network gateway of the allocated addresses; the gateway is never allocated.
+	Gateway string `json:"gateway"`
+}
+
+// InClusterIPPoolStatus defines the observed state of an InClusterIPPool.
+type InClusterIPPoolStatus struct {
+	// Addresses reports the count of the addresses in the pool.
+	// +optional
+	Addresses *InClusterIPPoolStatusAddresses `json:"addresses,omitempty"`
+}
+
+// InClusterIPPoolStatusAddresses reports the count of the addresses in an InClusterIPPool.
+// NOTE: Counts larger than the maximum value of an int64 are capped, e.g. for large IPv6 pools.
+type InClusterIPPoolStatusAddresses struct {
+	// Total is the count of the addresses in the pool, excluding the excluded addresses and the gateway.
+	Total int64 `json:"total"`
+
+	// Used is the count of the addresses allocated from the pool.
+	Used int64 `json:"used"`
+
+	// Free is the count of the addresses which can still be allocated from the pool.
+	Free int64 `json:"free"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=inclusterippools,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Addresses",type="string",JSONPath=".spec.addresses",description="List of addresses to allocate from"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.addresses.total",description="Count of the addresses in the pool"
+// +kubebuilder:printcolumn:name="Free",type="integer",JSONPath=".status.addresses.free",description="Count of the addresses which can still be allocated"
+// +kubebuilder:printcolumn:name="Used",type="integer",JSONPath=".status.addresses.used",description="Count of the allocated addresses"
+
+// InClusterIPPool is the Schema for the inclusterippools API; it is a reference IPAM provider
+// allocating IPAddresses for IPAddressClaims from a list of addresses, without requiring an external IPAM system.
+type InClusterIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InClusterIPPoolSpec   `json:"spec,omitempty"`
+	Status InClusterIPPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// InClusterIPPoolList is a list of InClusterIPPools.
+type InClusterIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InClusterIPPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InClusterIPPool{}, &InClusterIPPoolList{})
+}
@@ -1,5 +1,5 @@
 /*
-Copyright 2021 The Kubernetes Authors.
+Copyright 2023 The Kubernetes Authors.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,6 +14,5 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package contract provides support for the ClusterReconciler to handle with providers objects
-// according to the Cluster API contract.
-package contract
+// Package controllers implements the exp/ipam controllers.
+package controllers
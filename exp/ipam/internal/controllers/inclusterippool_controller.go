@@ -0,0 +1,170 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/exp/ipam/internal/poolutil"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=inclusterippools;inclusterippools/status,verbs=get;list;watch;update;patch
+
+// InClusterIPPoolReconciler reconciles an InClusterIPPool object.
+type InClusterIPPoolReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *InClusterIPPoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&ipamv1.InClusterIPPool{}).
+		Watches(
+			&source.Kind{Type: &ipamv1.IPAddress{}},
+			handler.EnqueueRequestsFromMapFunc(ipAddressToInClusterIPPool),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
+
+func (r *InClusterIPPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	pool := &ipamv1.InClusterIPPool{}
+	if err := r.Client.Get(ctx, req.NamespacedName, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	patchHelper, err := patch.NewHelper(pool, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, pool); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	addresses := &ipamv1.IPAddressList{}
+	if err := r.Client.List(ctx, addresses, client.InNamespace(pool.Namespace)); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list IPAddresses")
+	}
+	allocated := []ipamv1.IPAddress{}
+	for i := range addresses.Items {
+		if isAllocatedFrom(&addresses.Items[i], pool) {
+			allocated = append(allocated, addresses.Items[i])
+		}
+	}
+
+	// The pool can be deleted only after all the addresses allocated from it have been released.
+	if !pool.DeletionTimestamp.IsZero() {
+		if len(allocated) > 0 {
+			log.Info("Waiting for IPAddresses allocated from the InClusterIPPool to be released", "count", len(allocated))
+			return ctrl.Result{}, nil
+		}
+		controllerutil.RemoveFinalizer(pool, ipamv1.InClusterIPPoolFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(pool, ipamv1.InClusterIPPoolFinalizer) {
+		controllerutil.AddFinalizer(pool, ipamv1.InClusterIPPoolFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	ranges, err := poolutil.PoolRanges(pool)
+	if err != nil {
+		log.Error(err, "Invalid InClusterIPPool")
+		pool.Status.Addresses = nil
+		return ctrl.Result{}, nil
+	}
+	pool.Status.Addresses = computeStatusAddresses(ranges, allocated)
+	return ctrl.Result{}, nil
+}
+
+// computeStatusAddresses returns the count of the addresses of a pool; addresses allocated before being
+// removed from the pool are counted as used, but they do not reduce the count of the free addresses.
+func computeStatusAddresses(ranges []poolutil.IPRange, allocated []ipamv1.IPAddress) *ipamv1.InClusterIPPoolStatusAddresses {
+	total := poolutil.Count(ranges)
+	inRange := int64(0)
+	for _, address := range allocated {
+		addr, err := netip.ParseAddr(address.Spec.Address)
+		if err != nil {
+			continue
+		}
+		for _, r := range ranges {
+			if r.Contains(addr) {
+				inRange++
+				break
+			}
+		}
+	}
+
+	free := total - inRange
+	if free < 0 {
+		free = 0
+	}
+	return &ipamv1.InClusterIPPoolStatusAddresses{
+		Total: total,
+		Used:  int64(len(allocated)),
+		Free:  free,
+	}
+}
+
+// ipAddressToInClusterIPPool maps an IPAddress to the InClusterIPPool it has been allocated from.
+func ipAddressToInClusterIPPool(o client.Object) []reconcile.Request {
+	address, ok := o.(*ipamv1.IPAddress)
+	if !ok {
+		return nil
+	}
+	if address.Spec.PoolRef.APIGroup == nil || *address.Spec.PoolRef.APIGroup != ipamv1.GroupVersion.Group ||
+		address.Spec.PoolRef.Kind != ipamv1.InClusterIPPoolKind {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: address.Namespace, Name: address.Spec.PoolRef.Name}}}
+}
+
+// isAllocatedFrom returns true if the IPAddress has been allocated from the pool.
+func isAllocatedFrom(address *ipamv1.IPAddress, pool *ipamv1.InClusterIPPool) bool {
+	return address.Spec.PoolRef.APIGroup != nil && *address.Spec.PoolRef.APIGroup == ipamv1.GroupVersion.Group &&
+		address.Spec.PoolRef.Kind == ipamv1.InClusterIPPoolKind &&
+		address.Spec.PoolRef.Name == pool.Name
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/exp/ipam/internal/poolutil"
+)
+
+func TestComputeStatusAddresses(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &ipamv1.InClusterIPPool{
+		Spec: ipamv1.InClusterIPPoolSpec{
+			Addresses: []string{"10.0.0.10-10.0.0.19"},
+			Gateway:   "10.0.0.1",
+		},
+	}
+	ranges, err := poolutil.PoolRanges(pool)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	allocated := []ipamv1.IPAddress{
+		{Spec: ipamv1.IPAddressSpec{Address: "10.0.0.10"}},
+		{Spec: ipamv1.IPAddressSpec{Address: "10.0.0.11"}},
+		// Address allocated before being removed from the pool.
+		{Spec: ipamv1.IPAddressSpec{Address: "10.0.0.30"}},
+	}
+	g.Expect(computeStatusAddresses(ranges, allocated)).To(Equal(&ipamv1.InClusterIPPoolStatusAddresses{
+		Total: 10,
+		Used:  3,
+		Free:  8,
+	}))
+}
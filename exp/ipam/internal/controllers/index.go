@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+)
+
+const (
+	// inClusterIPPoolNameField is used by the IPAddressClaim controller for indexing IPAddresses
+	// by the name of the InClusterIPPool they have been allocated from.
+	inClusterIPPoolNameField = "spec.poolRef.inClusterIPPoolName"
+)
+
+// indexIPAddressByInClusterIPPoolName adds the index by InClusterIPPool name to the managers cache.
+func indexIPAddressByInClusterIPPoolName(ctx context.Context, mgr ctrl.Manager) error {
+	if err := mgr.GetCache().IndexField(ctx, &ipamv1.IPAddress{},
+		inClusterIPPoolNameField,
+		ipAddressByInClusterIPPoolName,
+	); err != nil {
+		return errors.Wrap(err, "error setting index field for InClusterIPPool name")
+	}
+	return nil
+}
+
+func ipAddressByInClusterIPPoolName(o client.Object) []string {
+	address, ok := o.(*ipamv1.IPAddress)
+	if !ok {
+		panic(fmt.Sprintf("Expected IPAddress but got a %T", o))
+	}
+	poolRef := address.Spec.PoolRef
+	if poolRef.APIGroup == nil || *poolRef.APIGroup != ipamv1.GroupVersion.Group || poolRef.Kind != ipamv1.InClusterIPPoolKind {
+		return nil
+	}
+	return []string{poolRef.Name}
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/exp/ipam/internal/poolutil"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims;ipaddressclaims/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=inclusterippools,verbs=get;list;watch
+
+const (
+	// allocationConfirmationTimeout is the amount of time allowed to wait for an allocated IPAddress to be in the cache.
+	allocationConfirmationTimeout = 10 * time.Second
+
+	// allocationConfirmationInterval is the amount of time between polling for an allocated IPAddress to be in the cache.
+	allocationConfirmationInterval = 100 * time.Millisecond
+)
+
+// IPAddressClaimReconciler allocates IPAddresses for the IPAddressClaims referencing an InClusterIPPool.
+type IPAddressClaimReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+
+	// poolLocks ensures the same address of an InClusterIPPool is not allocated to more than one claim by concurrent
+	// reconciles; it maps the key of an InClusterIPPool to a *sync.Mutex.
+	poolLocks sync.Map
+}
+
+func (r *IPAddressClaimReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	if err := indexIPAddressByInClusterIPPoolName(ctx, mgr); err != nil {
+		return err
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&ipamv1.IPAddressClaim{}, builder.WithPredicates(predicate.NewPredicateFuncs(isInClusterIPPoolClaim))).
+		Watches(
+			&source.Kind{Type: &ipamv1.InClusterIPPool{}},
+			handler.EnqueueRequestsFromMapFunc(r.inClusterIPPoolToIPAddressClaims),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
+
+func (r *IPAddressClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	claim := &ipamv1.IPAddressClaim{}
+	if err := r.Client.Get(ctx, req.NamespacedName, claim); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !isInClusterIPPoolClaim(claim) {
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(claim, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, claim, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			ipamv1.AddressAllocatedCondition,
+		}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	if !claim.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDelete(ctx, claim)
+	}
+
+	if !controllerutil.ContainsFinalizer(claim, ipamv1.InClusterIPPoolFinalizer) {
+		controllerutil.AddFinalizer(claim, ipamv1.InClusterIPPoolFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	pool := &ipamv1.InClusterIPPool{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: claim.Spec.PoolRef.Name}, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			conditions.MarkFalse(claim, ipamv1.AddressAllocatedCondition, ipamv1.PoolNotFoundReason, clusterv1.ConditionSeverityWarning,
+				"InClusterIPPool %s does not exist", claim.Spec.PoolRef.Name)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// NOTE: IPAddresses are read from the cache; this is safe because allocations from a pool are serialized, and each
+	// allocation completes only after the allocated IPAddress is in the cache.
+	unlock := r.lockPool(client.ObjectKeyFromObject(pool))
+	defer unlock()
+
+	addresses := &ipamv1.IPAddressList{}
+	if err := r.Client.List(ctx, addresses, client.InNamespace(claim.Namespace), client.MatchingFields{inClusterIPPoolNameField: pool.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list IPAddresses")
+	}
+
+	// IPAddresses are named after their claim, so an address already allocated is found also if the
+	// status of the claim was not updated, e.g. because the patch failed.
+	inUse := sets.Set[string]{}
+	for i := range addresses.Items {
+		address := &addresses.Items[i]
+		if address.Spec.ClaimRef.Name == claim.Name {
+			claim.Status.AddressRef.Name = address.Name
+			conditions.MarkTrue(claim, ipamv1.AddressAllocatedCondition)
+			return ctrl.Result{}, nil
+		}
+		inUse.Insert(address.Spec.Address)
+	}
+
+	if !pool.DeletionTimestamp.IsZero() {
+		conditions.MarkFalse(claim, ipamv1.AddressAllocatedCondition, ipamv1.PoolDeletingReason, clusterv1.ConditionSeverityWarning,
+			"InClusterIPPool %s is being deleted", pool.Name)
+		return ctrl.Result{}, nil
+	}
+
+	ranges, err := poolutil.PoolRanges(pool)
+	if err != nil {
+		conditions.MarkFalse(claim, ipamv1.AddressAllocatedCondition, ipamv1.InvalidPoolReason, clusterv1.ConditionSeverityError,
+			"InClusterIPPool %s is invalid: %v", pool.Name, err)
+		return ctrl.Result{}, nil
+	}
+	addr, err := poolutil.FindFreeAddress(ranges, inUse)
+	if err != nil {
+		conditions.MarkFalse(claim, ipamv1.AddressAllocatedCondition, ipamv1.PoolExhaustedReason, clusterv1.ConditionSeverityWarning,
+			"InClusterIPPool %s has no free addresses", pool.Name)
+		return ctrl.Result{}, nil
+	}
+
+	address := &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claim.Name,
+			Namespace: claim.Namespace,
+			Labels:    claim.Labels,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         ipamv1.GroupVersion.String(),
+					Kind:               "IPAddressClaim",
+					Name:               claim.Name,
+					UID:                claim.UID,
+					Controller:         pointer.Bool(true),
+					BlockOwnerDeletion: pointer.Bool(true),
+				},
+				{
+					APIVersion: ipamv1.GroupVersion.String(),
+					Kind:       ipamv1.InClusterIPPoolKind,
+					Name:       pool.Name,
+					UID:        pool.UID,
+				},
+			},
+		},
+		Spec: ipamv1.IPAddressSpec{
+			ClaimRef: corev1.LocalObjectReference{Name: claim.Name},
+			PoolRef:  claim.Spec.PoolRef,
+			Address:  addr.String(),
+			Prefix:   pool.Spec.Prefix,
+			Gateway:  pool.Spec.Gateway,
+		},
+	}
+	if err := r.Client.Create(ctx, address); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to create IPAddress for IPAddressClaim %s", claim.Name)
+	}
+	if err := r.waitForAllocation(ctx, address); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.Info("Allocated IPAddress", "IPAddress", address.Name, "address", address.Spec.Address, "InClusterIPPool", pool.Name)
+
+	claim.Status.AddressRef.Name = address.Name
+	conditions.MarkTrue(claim, ipamv1.AddressAllocatedCondition)
+	return ctrl.Result{}, nil
+}
+
+// lockPool locks the allocation of addresses from an InClusterIPPool, and it returns the function to unlock it.
+func (r *IPAddressClaimReconciler) lockPool(key client.ObjectKey) func() {
+	lock, _ := r.poolLocks.LoadOrStore(key, &sync.Mutex{})
+	mutex := lock.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+// waitForAllocation waits for an allocated IPAddress to be in the cache, so it is taken into account by the next allocations.
+func (r *IPAddressClaimReconciler) waitForAllocation(ctx context.Context, address *ipamv1.IPAddress) error {
+	pollErr := util.PollImmediate(allocationConfirmationInterval, allocationConfirmationTimeout, func() (bool, error) {
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(address), &ipamv1.IPAddress{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	})
+	if pollErr != nil {
+		return errors.Wrapf(pollErr, "failed waiting for IPAddress %s to be created", address.Name)
+	}
+	return nil
+}
+
+// reconcileDelete releases the IPAddress allocated for the claim.
+func (r *IPAddressClaimReconciler) reconcileDelete(ctx context.Context, claim *ipamv1.IPAddressClaim) error {
+	if !controllerutil.ContainsFinalizer(claim, ipamv1.InClusterIPPoolFinalizer) {
+		return nil
+	}
+
+	address := &ipamv1.IPAddress{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: claim.Name}, address); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get IPAddress for IPAddressClaim %s", claim.Name)
+	} else if err == nil && address.Spec.ClaimRef.Name == claim.Name {
+		if err := r.Client.Delete(ctx, address); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete IPAddress for IPAddressClaim %s", claim.Name)
+		}
+		ctrl.LoggerFrom(ctx).Info("Released IPAddress", "IPAddress", address.Name, "address", address.Spec.Address)
+	}
+
+	controllerutil.RemoveFinalizer(claim, ipamv1.InClusterIPPoolFinalizer)
+	return nil
+}
+
+// inClusterIPPoolToIPAddressClaims maps an InClusterIPPool to the IPAddressClaims without an address referencing it,
+// so addresses are allocated as soon as the pool is created or new addresses are added to it.
+func (r *IPAddressClaimReconciler) inClusterIPPoolToIPAddressClaims(o client.Object) []reconcile.Request {
+	claims := &ipamv1.IPAddressClaimList{}
+	if err := r.Client.List(context.Background(), claims, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if !isInClusterIPPoolClaim(claim) || claim.Spec.PoolRef.Name != o.GetName() || claim.Status.AddressRef.Name != "" {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+	}
+	return requests
+}
+
+// isInClusterIPPoolClaim returns true if the object is an IPAddressClaim referencing an InClusterIPPool.
+func isInClusterIPPoolClaim(o client.Object) bool {
+	claim, ok := o.(*ipamv1.IPAddressClaim)
+	if !ok {
+		return false
+	}
+	return claim.Spec.PoolRef.APIGroup != nil && *claim.Spec.PoolRef.APIGroup == ipamv1.GroupVersion.Group &&
+		claim.Spec.PoolRef.Kind == ipamv1.InClusterIPPoolKind
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func newClaim(name, pool string) *ipamv1.IPAddressClaim {
+	return &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: ipamv1.IPAddressClaimSpec{
+			PoolRef: corev1.TypedLocalObjectReference{
+				APIGroup: pointer.String(ipamv1.GroupVersion.Group),
+				Kind:     ipamv1.InClusterIPPoolKind,
+				Name:     pool,
+			},
+		},
+	}
+}
+
+func TestIPAddressClaimReconciler(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(ipamv1.AddToScheme(scheme)).To(Succeed())
+
+	pool := &ipamv1.InClusterIPPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pool",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: ipamv1.InClusterIPPoolSpec{
+			// Only 10.0.0.2 can be allocated: 10.0.0.0 and 10.0.0.3 are the network and broadcast
+			// addresses, 10.0.0.1 is the gateway.
+			Addresses: []string{"10.0.0.0/30"},
+			Prefix:    24,
+			Gateway:   "10.0.0.1",
+		},
+	}
+	claim1 := newClaim("claim-1", "pool")
+	claim2 := newClaim("claim-2", "pool")
+	otherClaim := newClaim("other", "pool")
+	otherClaim.Spec.PoolRef.Kind = "OtherPool"
+	missingPoolClaim := newClaim("missing-pool", "does-not-exist")
+
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(pool, claim1, claim2, otherClaim, missingPoolClaim).
+		WithIndex(&ipamv1.IPAddress{}, inClusterIPPoolNameField, ipAddressByInClusterIPPoolName).
+		Build()
+	r := &IPAddressClaimReconciler{Client: c}
+
+	reconcile := func(claim *ipamv1.IPAddressClaim) *ipamv1.IPAddressClaim {
+		// The first reconcile adds the finalizer, the second one allocates the address.
+		for i := 0; i < 2; i++ {
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+			g.Expect(err).ToNot(HaveOccurred())
+		}
+		got := &ipamv1.IPAddressClaim{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claim), got)).To(Succeed())
+		return got
+	}
+
+	t.Run("allocates an address from the pool", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got := reconcile(claim1)
+		g.Expect(got.Finalizers).To(ContainElement(ipamv1.InClusterIPPoolFinalizer))
+		g.Expect(got.Status.AddressRef.Name).To(Equal(claim1.Name))
+		g.Expect(conditions.IsTrue(got, ipamv1.AddressAllocatedCondition)).To(BeTrue())
+
+		address := &ipamv1.IPAddress{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claim1), address)).To(Succeed())
+		g.Expect(address.Spec.Address).To(Equal("10.0.0.2"))
+		g.Expect(address.Spec.Prefix).To(Equal(24))
+		g.Expect(address.Spec.Gateway).To(Equal("10.0.0.1"))
+		g.Expect(address.Spec.ClaimRef.Name).To(Equal(claim1.Name))
+		g.Expect(address.Spec.PoolRef).To(Equal(claim1.Spec.PoolRef))
+
+		// Reconciling again does not allocate another address.
+		got = reconcile(claim1)
+		g.Expect(got.Status.AddressRef.Name).To(Equal(claim1.Name))
+		addresses := &ipamv1.IPAddressList{}
+		g.Expect(c.List(ctx, addresses)).To(Succeed())
+		g.Expect(addresses.Items).To(HaveLen(1))
+	})
+
+	t.Run("reports the pool is exhausted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got := reconcile(claim2)
+		g.Expect(got.Status.AddressRef.Name).To(BeEmpty())
+		g.Expect(conditions.IsFalse(got, ipamv1.AddressAllocatedCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(got, ipamv1.AddressAllocatedCondition)).To(Equal(ipamv1.PoolExhaustedReason))
+	})
+
+	t.Run("reports the pool does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got := reconcile(missingPoolClaim)
+		g.Expect(conditions.GetReason(got, ipamv1.AddressAllocatedCondition)).To(Equal(ipamv1.PoolNotFoundReason))
+	})
+
+	t.Run("ignores claims for other pools", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got := reconcile(otherClaim)
+		g.Expect(got.Finalizers).To(BeEmpty())
+		g.Expect(conditions.Has(got, ipamv1.AddressAllocatedCondition)).To(BeFalse())
+	})
+
+	t.Run("releases the address when the claim is deleted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(c.Delete(ctx, claim1)).To(Succeed())
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim1)})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = c.Get(ctx, client.ObjectKeyFromObject(claim1), &ipamv1.IPAddress{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		err = c.Get(ctx, client.ObjectKeyFromObject(claim1), &ipamv1.IPAddressClaim{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		// The released address is allocated to the next claim.
+		got := reconcile(claim2)
+		g.Expect(got.Status.AddressRef.Name).To(Equal(claim2.Name))
+		g.Expect(conditions.IsTrue(got, ipamv1.AddressAllocatedCondition)).To(BeTrue())
+	})
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package poolutil implements utilities for computing and allocating the addresses of an InClusterIPPool.
+package poolutil
+
+import (
+	"math"
+	"math/big"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+)
+
+// IPRange is a range of IP addresses, including First and Last.
+type IPRange struct {
+	First netip.Addr
+	Last  netip.Addr
+}
+
+// Contains returns true if addr is in the range.
+func (r IPRange) Contains(addr netip.Addr) bool {
+	return r.First.Compare(addr) <= 0 && addr.Compare(r.Last) <= 0
+}
+
+// Size returns the count of the addresses in the range.
+func (r IPRange) Size() *big.Int {
+	first, last := r.First.As16(), r.Last.As16()
+	size := new(big.Int).Sub(new(big.Int).SetBytes(last[:]), new(big.Int).SetBytes(first[:]))
+	return size.Add(size, big.NewInt(1))
+}
+
+// ParseRange parses an IP address (e.g. 10.0.0.10), a range (e.g. 10.0.0.10-10.0.0.20)
+// or a CIDR (e.g. 10.0.0.0/24) into an IPRange.
+func ParseRange(s string) (IPRange, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.Contains(s, "-"):
+		parts := strings.SplitN(s, "-", 2)
+		first, err := netip.ParseAddr(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return IPRange{}, errors.Wrapf(err, "invalid range %q", s)
+		}
+		last, err := netip.ParseAddr(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return IPRange{}, errors.Wrapf(err, "invalid range %q", s)
+		}
+		if first.Is4() != last.Is4() {
+			return IPRange{}, errors.Errorf("invalid range %q: the first and the last address must belong to the same IP family", s)
+		}
+		if last.Less(first) {
+			return IPRange{}, errors.Errorf("invalid range %q: the first address must not be greater than the last address", s)
+		}
+		return IPRange{First: first, Last: last}, nil
+	case strings.Contains(s, "/"):
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return IPRange{}, errors.Wrapf(err, "invalid CIDR %q", s)
+		}
+		prefix = prefix.Masked()
+		return IPRange{First: prefix.Addr(), Last: lastAddr(prefix)}, nil
+	default:
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return IPRange{}, errors.Wrapf(err, "invalid address %q", s)
+		}
+		return IPRange{First: addr, Last: addr}, nil
+	}
+}
+
+// PoolRanges returns the ranges of the addresses which can be allocated from an InClusterIPPool, i.e. the
+// addresses of the pool without the excluded addresses, the gateway and, for IPv4 CIDRs, the network and
+// broadcast addresses. The ranges are sorted and do not overlap.
+func PoolRanges(pool *ipamv1.InClusterIPPool) ([]IPRange, error) {
+	ranges := []IPRange{}
+	excluded := []IPRange{}
+	for _, a := range pool.Spec.Addresses {
+		r, err := ParseRange(a)
+		if err != nil {
+			return nil, err
+		}
+		if len(ranges) > 0 && ranges[0].First.Is4() != r.First.Is4() {
+			return nil, errors.Errorf("address %q does not belong to the same IP family as the other addresses of the pool", a)
+		}
+		ranges = append(ranges, r)
+
+		// Network and broadcast addresses of IPv4 CIDRs can't be assigned to hosts.
+		if prefix, err := netip.ParsePrefix(strings.TrimSpace(a)); err == nil && prefix.Addr().Is4() && prefix.Bits() < 31 {
+			excluded = append(excluded, IPRange{First: r.First, Last: r.First}, IPRange{First: r.Last, Last: r.Last})
+		}
+	}
+
+	for _, a := range pool.Spec.ExcludedAddresses {
+		r, err := ParseRange(a)
+		if err != nil {
+			return nil, err
+		}
+		excluded = append(excluded, r)
+	}
+	if pool.Spec.Gateway != "" {
+		gateway, err := netip.ParseAddr(pool.Spec.Gateway)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid gateway %q", pool.Spec.Gateway)
+		}
+		excluded = append(excluded, IPRange{First: gateway, Last: gateway})
+	}
+
+	return subtract(merge(ranges), merge(excluded)), nil
+}
+
+// Count returns the count of the addresses in ranges, capped to the maximum value of an int64.
+func Count(ranges []IPRange) int64 {
+	total := new(big.Int)
+	for _, r := range ranges {
+		total.Add(total, r.Size())
+	}
+	if !total.IsInt64() {
+		return math.MaxInt64
+	}
+	return total.Int64()
+}
+
+// FindFreeAddress returns the first address in ranges which is not in use.
+func FindFreeAddress(ranges []IPRange, inUse sets.Set[string]) (netip.Addr, error) {
+	for _, r := range ranges {
+		for addr := r.First; addr.IsValid() && r.Contains(addr); addr = addr.Next() {
+			if !inUse.Has(addr.String()) {
+				return addr, nil
+			}
+		}
+	}
+	return netip.Addr{}, errors.New("no free address in the pool")
+}
+
+// merge sorts ranges and merges the ranges which overlap or are adjacent.
+func merge(ranges []IPRange) []IPRange {
+	if len(ranges) == 0 {
+		return ranges
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].First.Less(ranges[j].First) })
+
+	merged := []IPRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		current := &merged[len(merged)-1]
+		next := current.Last.Next()
+		if r.First.Compare(current.Last) <= 0 || (next.IsValid() && r.First == next) {
+			if current.Last.Less(r.Last) {
+				current.Last = r.Last
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// subtract removes the addresses in excluded from ranges; both must be sorted and must not overlap.
+func subtract(ranges, excluded []IPRange) []IPRange {
+	result := []IPRange{}
+	for _, r := range ranges {
+		current := r
+		valid := true
+		for _, e := range excluded {
+			if e.Last.Less(current.First) || current.Last.Less(e.First) {
+				continue
+			}
+			if current.First.Less(e.First) {
+				result = append(result, IPRange{First: current.First, Last: e.First.Prev()})
+			}
+			if !e.Last.Less(current.Last) {
+				valid = false
+				break
+			}
+			current.First = e.Last.Next()
+		}
+		if valid {
+			result = append(result, current)
+		}
+	}
+	return result
+}
+
+// lastAddr returns the last address of prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	a := prefix.Addr().AsSlice()
+	bits := prefix.Bits()
+	for i := range a {
+		switch {
+		case bits >= 8:
+			bits -= 8
+		case bits > 0:
+			a[i] |= byte(0xff >> bits)
+			bits = 0
+		default:
+			a[i] = 0xff
+		}
+	}
+	addr, _ := netip.AddrFromSlice(a)
+	return addr
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poolutil
+
+import (
+	"math"
+	"net/netip"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        string
+		wantFirst string
+		wantLast  string
+		wantErr   bool
+	}{
+		{name: "single address", in: "10.0.0.10", wantFirst: "10.0.0.10", wantLast: "10.0.0.10"},
+		{name: "range", in: "10.0.0.10-10.0.0.20", wantFirst: "10.0.0.10", wantLast: "10.0.0.20"},
+		{name: "CIDR", in: "10.0.0.0/24", wantFirst: "10.0.0.0", wantLast: "10.0.0.255"},
+		{name: "CIDR not masked", in: "10.0.0.7/30", wantFirst: "10.0.0.4", wantLast: "10.0.0.7"},
+		{name: "IPv6 CIDR", in: "fd00::/120", wantFirst: "fd00::", wantLast: "fd00::ff"},
+		{name: "IPv6 range", in: "fd00::1-fd00::a", wantFirst: "fd00::1", wantLast: "fd00::a"},
+		{name: "invalid address", in: "10.0.0", wantErr: true},
+		{name: "invalid CIDR", in: "10.0.0.0/33", wantErr: true},
+		{name: "reversed range", in: "10.0.0.20-10.0.0.10", wantErr: true},
+		{name: "mixed families range", in: "10.0.0.1-fd00::1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r, err := ParseRange(tt.in)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(r.First.String()).To(Equal(tt.wantFirst))
+			g.Expect(r.Last.String()).To(Equal(tt.wantLast))
+		})
+	}
+}
+
+func TestPoolRanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      ipamv1.InClusterIPPoolSpec
+		want      []string
+		wantCount int64
+		wantErr   bool
+	}{
+		{
+			name: "CIDR without network, broadcast and gateway",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.0.0/29"},
+				Gateway:   "10.0.0.1",
+			},
+			want:      []string{"10.0.0.2-10.0.0.6"},
+			wantCount: 5,
+		},
+		{
+			name: "overlapping and adjacent ranges are merged",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.0.20-10.0.0.30", "10.0.0.10-10.0.0.19", "10.0.0.25"},
+				Gateway:   "10.0.0.1",
+			},
+			want:      []string{"10.0.0.10-10.0.0.30"},
+			wantCount: 21,
+		},
+		{
+			name: "excluded addresses split ranges",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses:         []string{"10.0.0.10-10.0.0.30"},
+				ExcludedAddresses: []string{"10.0.0.15-10.0.0.20", "10.0.0.30", "10.0.1.0/24"},
+				Gateway:           "10.0.0.10",
+			},
+			want:      []string{"10.0.0.11-10.0.0.14", "10.0.0.21-10.0.0.29"},
+			wantCount: 13,
+		},
+		{
+			name: "IPv6 CIDRs include all the addresses",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"fd00::/126"},
+				Gateway:   "fd00::1",
+			},
+			want:      []string{"fd00::-fd00::", "fd00::2-fd00::3"},
+			wantCount: 3,
+		},
+		{
+			name: "large IPv6 pools are capped",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"fd00::/32"},
+				Gateway:   "fd00::1",
+			},
+			want:      []string{"fd00::-fd00::", "fd00::2-fd00:0:ffff:ffff:ffff:ffff:ffff:ffff"},
+			wantCount: math.MaxInt64,
+		},
+		{
+			name: "mixed families",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.0.0/24", "fd00::/120"},
+				Gateway:   "10.0.0.1",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ranges, err := PoolRanges(&ipamv1.InClusterIPPool{Spec: tt.spec})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			got := []string{}
+			for _, r := range ranges {
+				got = append(got, r.First.String()+"-"+r.Last.String())
+			}
+			g.Expect(got).To(Equal(tt.want))
+			g.Expect(Count(ranges)).To(Equal(tt.wantCount))
+		})
+	}
+}
+
+func TestFindFreeAddress(t *testing.T) {
+	g := NewWithT(t)
+
+	ranges := []IPRange{
+		{First: netip.MustParseAddr("10.0.0.10"), Last: netip.MustParseAddr("10.0.0.11")},
+		{First: netip.MustParseAddr("10.0.0.20"), Last: netip.MustParseAddr("10.0.0.21")},
+	}
+
+	addr, err := FindFreeAddress(ranges, sets.New[string]())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(addr.String()).To(Equal("10.0.0.10"))
+
+	addr, err = FindFreeAddress(ranges, sets.New[string]("10.0.0.10", "10.0.0.11", "10.0.0.20"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(addr.String()).To(Equal("10.0.0.21"))
+
+	_, err = FindFreeAddress(ranges, sets.New[string]("10.0.0.10", "10.0.0.11", "10.0.0.20", "10.0.0.21"))
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/exp/ipam/internal/poolutil"
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+// SetupWebhookWithManager sets up InClusterIPPool webhooks.
+func (webhook *InClusterIPPool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&ipamv1.InClusterIPPool{}).
+		WithValidator(webhook).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-ipam-cluster-x-k8s-io-v1alpha1-inclusterippool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=ipam.cluster.x-k8s.io,resources=inclusterippools,versions=v1alpha1,name=validation.inclusterippool.ipam.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// InClusterIPPool implements a validating webhook for InClusterIPPool.
+type InClusterIPPool struct {
+}
+
+var _ webhook.CustomValidator = &InClusterIPPool{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (webhook *InClusterIPPool) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	pool, ok := obj.(*ipamv1.InClusterIPPool)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an InClusterIPPool but got a %T", obj))
+	}
+	return webhook.validate(pool)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+// NOTE: Addresses can be removed from the pool also if they are in use; IPAddresses already allocated are not
+// affected, and the addresses can't be allocated anymore once released.
+func (webhook *InClusterIPPool) ValidateUpdate(_ context.Context, _, newObj runtime.Object) error {
+	newPool, ok := newObj.(*ipamv1.InClusterIPPool)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an InClusterIPPool but got a %T", newObj))
+	}
+	return webhook.validate(newPool)
+}
+
+// ValidateDelete implements webhook.CustomValidator.
+func (webhook *InClusterIPPool) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (webhook *InClusterIPPool) validate(pool *ipamv1.InClusterIPPool) error {
+	// NOTE: InClusterIPPool is behind the InClusterIPAM feature gate flag; the web hook
+	// must prevent creating or updating new objects in case the feature flag is disabled.
+	if !feature.Gates.Enabled(feature.InClusterIPAM) {
+		return field.Forbidden(
+			field.NewPath("spec"),
+			"can be set only if the InClusterIPAM feature flag is enabled",
+		)
+	}
+
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if len(pool.Spec.Addresses) == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("addresses"), "at least one address is required"))
+	}
+
+	var is4 *bool
+	for i, a := range pool.Spec.Addresses {
+		r, err := poolutil.ParseRange(a)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("addresses").Index(i), a, err.Error()))
+			continue
+		}
+		family := r.First.Is4()
+		if is4 == nil {
+			is4 = &family
+		}
+		if *is4 != family {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("addresses").Index(i), a,
+				"all the addresses must belong to the same IP family"))
+		}
+	}
+
+	for i, a := range pool.Spec.ExcludedAddresses {
+		if _, err := poolutil.ParseRange(a); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("excludedAddresses").Index(i), a, err.Error()))
+		}
+	}
+
+	if pool.Spec.Prefix < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("prefix"), pool.Spec.Prefix, "prefix cannot be negative"))
+	}
+	if is4 != nil && *is4 && pool.Spec.Prefix > 32 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("prefix"), pool.Spec.Prefix, "prefix is too large for IPv4 addresses"))
+	}
+	if pool.Spec.Prefix > 128 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("prefix"), pool.Spec.Prefix, "prefix is too large for IPv6 addresses"))
+	}
+
+	gateway, err := netip.ParseAddr(pool.Spec.Gateway)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("gateway"), pool.Spec.Gateway, "not a valid IP address"))
+	} else if is4 != nil && gateway.Is4() != *is4 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("gateway"), pool.Spec.Gateway,
+			"the gateway must belong to the same IP family as the addresses"))
+	}
+
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(ipamv1.GroupVersion.WithKind(ipamv1.InClusterIPPoolKind).GroupKind(), pool.Name, allErrs)
+	}
+	return nil
+}
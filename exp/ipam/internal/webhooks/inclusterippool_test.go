@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+func TestInClusterIPPoolValidateCreate(t *testing.T) {
+	getPool := func(fn func(pool *ipamv1.InClusterIPPool)) ipamv1.InClusterIPPool {
+		pool := ipamv1.InClusterIPPool{
+			Spec: ipamv1.InClusterIPPoolSpec{
+				Addresses:         []string{"10.0.0.0/24", "10.0.1.10-10.0.1.20", "10.0.2.1"},
+				ExcludedAddresses: []string{"10.0.0.10-10.0.0.20"},
+				Prefix:            16,
+				Gateway:           "10.0.0.1",
+			},
+		}
+		fn(&pool)
+		return pool
+	}
+
+	tests := []struct {
+		name        string
+		pool        ipamv1.InClusterIPPool
+		featureGate bool
+		expectErr   bool
+	}{
+		{
+			name:        "should accept a valid pool",
+			pool:        getPool(func(pool *ipamv1.InClusterIPPool) {}),
+			featureGate: true,
+			expectErr:   false,
+		},
+		{
+			name: "should accept a valid IPv6 pool",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Addresses = []string{"fd00::/64"}
+				pool.Spec.ExcludedAddresses = nil
+				pool.Spec.Prefix = 64
+				pool.Spec.Gateway = "fd00::1"
+			}),
+			featureGate: true,
+			expectErr:   false,
+		},
+		{
+			name:        "should reject a pool if the feature gate is disabled",
+			pool:        getPool(func(pool *ipamv1.InClusterIPPool) {}),
+			featureGate: false,
+			expectErr:   true,
+		},
+		{
+			name: "should reject a pool without addresses",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Addresses = nil
+			}),
+			featureGate: true,
+			expectErr:   true,
+		},
+		{
+			name: "should reject invalid addresses",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Addresses = []string{"10.0.0.20-10.0.0.10"}
+			}),
+			featureGate: true,
+			expectErr:   true,
+		},
+		{
+			name: "should reject addresses of different IP families",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Addresses = []string{"10.0.0.0/24", "fd00::/64"}
+			}),
+			featureGate: true,
+			expectErr:   true,
+		},
+		{
+			name: "should reject invalid excluded addresses",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.ExcludedAddresses = []string{"10.0.0.256"}
+			}),
+			featureGate: true,
+			expectErr:   true,
+		},
+		{
+			name: "should reject a prefix too large for IPv4",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Prefix = 33
+			}),
+			featureGate: true,
+			expectErr:   true,
+		},
+		{
+			name: "should reject an invalid gateway",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Gateway = "10.0.0"
+			}),
+			featureGate: true,
+			expectErr:   true,
+		},
+		{
+			name: "should reject a gateway of a different IP family",
+			pool: getPool(func(pool *ipamv1.InClusterIPPool) {
+				pool.Spec.Gateway = "fd00::1"
+			}),
+			featureGate: true,
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.InClusterIPAM, tt.featureGate)()
+			g := NewWithT(t)
+			wh := InClusterIPPool{}
+			if tt.expectErr {
+				g.Expect(wh.ValidateCreate(context.Background(), &tt.pool)).NotTo(Succeed())
+				g.Expect(wh.ValidateUpdate(context.Background(), &tt.pool, &tt.pool)).NotTo(Succeed())
+			} else {
+				g.Expect(wh.ValidateCreate(context.Background(), &tt.pool)).To(Succeed())
+				g.Expect(wh.ValidateUpdate(context.Background(), &tt.pool, &tt.pool)).To(Succeed())
+			}
+		})
+	}
+}
@@ -40,6 +40,60 @@ type ExtensionConfigSpec struct {
 	// Note: Settings can be overridden on the ClusterClass.
 	// +optional
 	Settings map[string]string `json:"settings,omitempty"`
+
+	// HandlerPolicies overrides the timeout and the failure policy reported by the Extension server for
+	// its ExtensionHandlers, and defines how calls failing because of transient errors are retried.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	HandlerPolicies []ExtensionHandlerPolicy `json:"handlerPolicies,omitempty"`
+
+	// DiscoveryCacheTTLSeconds defines for how long the result of the last successful discovery is reused
+	// instead of calling the Extension server again; discovery is always performed if the spec of the
+	// ExtensionConfig changes.
+	// Defaults to 0, which means discovery is performed on every reconcile.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	DiscoveryCacheTTLSeconds *int32 `json:"discoveryCacheTTLSeconds,omitempty"`
+}
+
+// ExtensionHandlerPolicy defines the policies used when calling an ExtensionHandler.
+type ExtensionHandlerPolicy struct {
+	// Name is the name of the ExtensionHandler, as returned by the Extension server in the discovery response.
+	Name string `json:"name"`
+
+	// TimeoutSeconds overrides the timeout duration for client calls to the ExtensionHandler.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=30
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// FailurePolicy overrides how failures in calls to the ExtensionHandler are handled by a client.
+	// +optional
+	// +kubebuilder:validation:Enum=Ignore;Fail
+	FailurePolicy *FailurePolicy `json:"failurePolicy,omitempty"`
+
+	// RetryPolicy defines how calls to the ExtensionHandler failing because of transient errors,
+	// e.g. network errors or timeouts, are retried.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// RetryPolicy defines how calls to an ExtensionHandler failing because of transient errors are retried.
+// NOTE: Responses with Status Failure are never retried.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a failed call is retried.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=5
+	MaxRetries int32 `json:"maxRetries"`
+
+	// BackoffSeconds is the time to wait before the first retry; the time is doubled for every
+	// subsequent retry.
+	// Defaults to 1 if not set.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=30
+	BackoffSeconds *int32 `json:"backoffSeconds,omitempty"`
 }
 
 // ClientConfig contains the information to make a client
@@ -131,6 +185,11 @@ type ExtensionHandler struct {
 	// Defaults to Fail if not set.
 	// +optional
 	FailurePolicy *FailurePolicy `json:"failurePolicy,omitempty"`
+
+	// RetryPolicy defines how calls to the ExtensionHandler failing because of transient errors are retried.
+	// Calls are not retried if not set.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
 }
 
 // GroupVersionHook defines the runtime hook when the ExtensionHandler is called.
@@ -217,6 +276,12 @@ const (
 	// the intent will be removed as soon as the hook call completes successfully.
 	PendingHooksAnnotation string = "runtime.cluster.x-k8s.io/pending-hooks"
 
+	// FailedCallAttemptsAnnotation is the annotation used to keep track of the failed calls to extension handlers with
+	// a RetryPolicy made for an object, so retries are executed by requeuing the object instead of blocking the calling
+	// controller. The value is a comma separated list of <extension handler name>=<failed calls>@<time of the first failed call>;
+	// entries are removed as soon as a call to the extension handler completes or the retries are exhausted.
+	FailedCallAttemptsAnnotation string = "runtime.cluster.x-k8s.io/failed-call-attempts"
+
 	// OkToDeleteAnnotation is the annotation used to indicate if a cluster is ready to be fully deleted.
 	// This annotation is added to the cluster after the BeforeClusterDelete hook has passed.
 	OkToDeleteAnnotation string = "runtime.cluster.x-k8s.io/ok-to-delete"
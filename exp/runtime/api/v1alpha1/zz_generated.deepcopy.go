@@ -132,6 +132,18 @@ func (in *ExtensionConfigSpec) DeepCopyInto(out *ExtensionConfigSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.HandlerPolicies != nil {
+		in, out := &in.HandlerPolicies, &out.HandlerPolicies
+		*out = make([]ExtensionHandlerPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DiscoveryCacheTTLSeconds != nil {
+		in, out := &in.DiscoveryCacheTTLSeconds, &out.DiscoveryCacheTTLSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionConfigSpec.
@@ -187,6 +199,11 @@ func (in *ExtensionHandler) DeepCopyInto(out *ExtensionHandler) {
 		*out = new(FailurePolicy)
 		**out = **in
 	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionHandler.
@@ -199,6 +216,36 @@ func (in *ExtensionHandler) DeepCopy() *ExtensionHandler {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionHandlerPolicy) DeepCopyInto(out *ExtensionHandlerPolicy) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailurePolicy != nil {
+		in, out := &in.FailurePolicy, &out.FailurePolicy
+		*out = new(FailurePolicy)
+		**out = **in
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionHandlerPolicy.
+func (in *ExtensionHandlerPolicy) DeepCopy() *ExtensionHandlerPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionHandlerPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GroupVersionHook) DeepCopyInto(out *GroupVersionHook) {
 	*out = *in
@@ -214,6 +261,26 @@ func (in *GroupVersionHook) DeepCopy() *GroupVersionHook {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.BackoffSeconds != nil {
+		in, out := &in.BackoffSeconds, &out.BackoffSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceReference) DeepCopyInto(out *ServiceReference) {
 	*out = *in
@@ -196,6 +196,96 @@ type BeforeClusterDeleteResponse struct {
 // and before the cluster and its underlying objects are deleted.
 func BeforeClusterDelete(*BeforeClusterDeleteRequest, *BeforeClusterDeleteResponse) {}
 
+// BeforeMachineCreateRequest is the request of the BeforeMachineCreate hook.
+// +kubebuilder:object:root=true
+type BeforeMachineCreateRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRequest contains fields common to all request types.
+	CommonRequest `json:",inline"`
+
+	// Cluster is the cluster object the Machine belongs to.
+	Cluster clusterv1.Cluster `json:"cluster"`
+
+	// Machine is the machine object the lifecycle hook corresponds to.
+	Machine clusterv1.Machine `json:"machine"`
+}
+
+var _ RetryResponseObject = &BeforeMachineCreateResponse{}
+
+// BeforeMachineCreateResponse is the response of the BeforeMachineCreate hook.
+// +kubebuilder:object:root=true
+type BeforeMachineCreateResponse struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRetryResponse contains Status, Message and RetryAfterSeconds fields.
+	CommonRetryResponse `json:",inline"`
+}
+
+// BeforeMachineCreate is the hook that will be called before the bootstrap and the infrastructure
+// of a Machine are provisioned.
+func BeforeMachineCreate(*BeforeMachineCreateRequest, *BeforeMachineCreateResponse) {}
+
+// AfterMachineJoinRequest is the request of the AfterMachineJoin hook.
+// +kubebuilder:object:root=true
+type AfterMachineJoinRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRequest contains fields common to all request types.
+	CommonRequest `json:",inline"`
+
+	// Cluster is the cluster object the Machine belongs to.
+	Cluster clusterv1.Cluster `json:"cluster"`
+
+	// Machine is the machine object the lifecycle hook corresponds to.
+	Machine clusterv1.Machine `json:"machine"`
+}
+
+var _ ResponseObject = &AfterMachineJoinResponse{}
+
+// AfterMachineJoinResponse is the response of the AfterMachineJoin hook.
+// +kubebuilder:object:root=true
+type AfterMachineJoinResponse struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonResponse contains Status and Message fields common to all response types.
+	CommonResponse `json:",inline"`
+}
+
+// AfterMachineJoin is the hook that will be called after the Node of a Machine has joined the cluster
+// and it is ready for the first time.
+func AfterMachineJoin(*AfterMachineJoinRequest, *AfterMachineJoinResponse) {}
+
+// BeforeMachineDeleteRequest is the request of the BeforeMachineDelete hook.
+// +kubebuilder:object:root=true
+type BeforeMachineDeleteRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRequest contains fields common to all request types.
+	CommonRequest `json:",inline"`
+
+	// Cluster is the cluster object the Machine belongs to.
+	Cluster clusterv1.Cluster `json:"cluster"`
+
+	// Machine is the machine object the lifecycle hook corresponds to.
+	Machine clusterv1.Machine `json:"machine"`
+}
+
+var _ RetryResponseObject = &BeforeMachineDeleteResponse{}
+
+// BeforeMachineDeleteResponse is the response of the BeforeMachineDelete hook.
+// +kubebuilder:object:root=true
+type BeforeMachineDeleteResponse struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonRetryResponse contains Status, Message and RetryAfterSeconds fields.
+	CommonRetryResponse `json:",inline"`
+}
+
+// BeforeMachineDelete is the hook that is called after delete is issued on a Machine
+// and before its Node is drained and its infrastructure is deleted.
+func BeforeMachineDelete(*BeforeMachineDeleteRequest, *BeforeMachineDeleteResponse) {}
+
 func init() {
 	catalogBuilder.RegisterHook(BeforeClusterCreate, &runtimecatalog.HookMeta{
 		Tags:    []string{"Lifecycle Hooks"},
@@ -271,4 +361,42 @@ func init() {
 			"- This is a blocking hook; Runtime Extension implementers can use this hook  to execute " +
 			"tasks before objects of the Cluster are deleted",
 	})
+
+	catalogBuilder.RegisterHook(BeforeMachineCreate, &runtimecatalog.HookMeta{
+		Tags:    []string{"Lifecycle Hooks"},
+		Summary: "Cluster API Runtime will call this hook before a Machine is provisioned",
+		Description: "Cluster API Runtime will call this hook after a Machine is created, " +
+			"and immediately before its bootstrap and infrastructure are going to be provisioned.\n" +
+			"\n" +
+			"Notes:\n" +
+			"- This hook will be called for all the Machines, including Machines not belonging to Clusters with a managed topology\n" +
+			"- The call's request contains the Cluster object and the Machine object\n" +
+			"- This is a blocking hook; Runtime Extension implementers can use this hook to execute " +
+			"tasks before the Machine is provisioned, e.g. reserving a DNS record or a license",
+	})
+
+	catalogBuilder.RegisterHook(AfterMachineJoin, &runtimecatalog.HookMeta{
+		Tags:    []string{"Lifecycle Hooks"},
+		Summary: "Cluster API Runtime will call this hook after the Node of a Machine joined the Cluster",
+		Description: "Cluster API Runtime will call this hook after the Node of a Machine has joined the Cluster " +
+			"and it is ready for the first time.\n" +
+			"\n" +
+			"Notes:\n" +
+			"- This hook will be called only for Machines which called the BeforeMachineCreate hook\n" +
+			"- The call's request contains the Cluster object and the Machine object\n" +
+			"- This is a non-blocking hook",
+	})
+
+	catalogBuilder.RegisterHook(BeforeMachineDelete, &runtimecatalog.HookMeta{
+		Tags:    []string{"Lifecycle Hooks"},
+		Summary: "Cluster API Runtime will call this hook before a Machine is deleted",
+		Description: "Cluster API Runtime will call this hook after the Machine deletion has been triggered, " +
+			"and immediately before its Node is going to be drained and its infrastructure deleted.\n" +
+			"\n" +
+			"Notes:\n" +
+			"- This hook will be called for all the Machines, including Machines not belonging to Clusters with a managed topology\n" +
+			"- The call's request contains the Cluster object and the Machine object\n" +
+			"- This is a blocking hook; Runtime Extension implementers can use this hook to execute " +
+			"tasks before the Machine is deleted, e.g. releasing a DNS record or a license",
+	})
 }
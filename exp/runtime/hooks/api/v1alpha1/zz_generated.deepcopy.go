@@ -179,6 +179,58 @@ func (in *AfterControlPlaneUpgradeResponse) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AfterMachineJoinRequest) DeepCopyInto(out *AfterMachineJoinRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.CommonRequest.DeepCopyInto(&out.CommonRequest)
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	in.Machine.DeepCopyInto(&out.Machine)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AfterMachineJoinRequest.
+func (in *AfterMachineJoinRequest) DeepCopy() *AfterMachineJoinRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(AfterMachineJoinRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AfterMachineJoinRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AfterMachineJoinResponse) DeepCopyInto(out *AfterMachineJoinResponse) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.CommonResponse = in.CommonResponse
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AfterMachineJoinResponse.
+func (in *AfterMachineJoinResponse) DeepCopy() *AfterMachineJoinResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(AfterMachineJoinResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AfterMachineJoinResponse) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BeforeClusterCreateRequest) DeepCopyInto(out *BeforeClusterCreateRequest) {
 	*out = *in
@@ -332,6 +384,110 @@ func (in *BeforeClusterUpgradeResponse) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BeforeMachineCreateRequest) DeepCopyInto(out *BeforeMachineCreateRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.CommonRequest.DeepCopyInto(&out.CommonRequest)
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	in.Machine.DeepCopyInto(&out.Machine)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeforeMachineCreateRequest.
+func (in *BeforeMachineCreateRequest) DeepCopy() *BeforeMachineCreateRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(BeforeMachineCreateRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BeforeMachineCreateRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BeforeMachineCreateResponse) DeepCopyInto(out *BeforeMachineCreateResponse) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.CommonRetryResponse = in.CommonRetryResponse
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeforeMachineCreateResponse.
+func (in *BeforeMachineCreateResponse) DeepCopy() *BeforeMachineCreateResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(BeforeMachineCreateResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BeforeMachineCreateResponse) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BeforeMachineDeleteRequest) DeepCopyInto(out *BeforeMachineDeleteRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.CommonRequest.DeepCopyInto(&out.CommonRequest)
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	in.Machine.DeepCopyInto(&out.Machine)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeforeMachineDeleteRequest.
+func (in *BeforeMachineDeleteRequest) DeepCopy() *BeforeMachineDeleteRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(BeforeMachineDeleteRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BeforeMachineDeleteRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BeforeMachineDeleteResponse) DeepCopyInto(out *BeforeMachineDeleteResponse) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.CommonRetryResponse = in.CommonRetryResponse
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeforeMachineDeleteResponse.
+func (in *BeforeMachineDeleteResponse) DeepCopy() *BeforeMachineDeleteResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(BeforeMachineDeleteResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BeforeMachineDeleteResponse) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonRequest) DeepCopyInto(out *CommonRequest) {
 	*out = *in
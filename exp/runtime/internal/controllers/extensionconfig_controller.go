@@ -20,9 +20,12 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -59,6 +62,19 @@ type Reconciler struct {
 	RuntimeClient runtimeclient.Client
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	discoveryCacheLock sync.Mutex
+	discoveryCache     map[string]discoveryCacheEntry
+}
+
+// discoveryCacheEntry is the result of the last successful discovery for an ExtensionConfig.
+type discoveryCacheEntry struct {
+	// spec is the spec of the ExtensionConfig, including the injected CA bundle, used for the discovery.
+	spec runtimev1.ExtensionConfigSpec
+	// discoveredAt is the time of the discovery.
+	discoveredAt time.Time
+	// handlers are the ExtensionHandlers returned by the discovery.
+	handlers []runtimev1.ExtensionHandler
 }
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -136,10 +152,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
-	// discoverExtensionConfig will return a discovered ExtensionConfig with the appropriate conditions.
-	discoveredExtensionConfig, err := discoverExtensionConfig(ctx, r.RuntimeClient, extensionConfig)
-	if err != nil {
-		errs = append(errs, err)
+	// Reuse the result of the last discovery if it is still valid, otherwise discoverExtensionConfig will
+	// return a discovered ExtensionConfig with the appropriate conditions.
+	discoveredExtensionConfig, ok := r.getCachedDiscovery(extensionConfig)
+	if ok {
+		log.V(4).Info("Using cached discovery for ExtensionConfig")
+	} else {
+		discoveredExtensionConfig, err = discoverExtensionConfig(ctx, r.RuntimeClient, extensionConfig)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			r.setCachedDiscovery(extensionConfig, discoveredExtensionConfig)
+		}
 	}
 
 	// Always patch the ExtensionConfig as it may contain updates in conditions or clientConfig.caBundle.
@@ -159,6 +183,54 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	return ctrl.Result{}, nil
 }
 
+// getCachedDiscovery returns the ExtensionConfig with the ExtensionHandlers of the last successful discovery, if
+// the discovery happened within DiscoveryCacheTTLSeconds and the spec of the ExtensionConfig did not change since then.
+func (r *Reconciler) getCachedDiscovery(extensionConfig *runtimev1.ExtensionConfig) (*runtimev1.ExtensionConfig, bool) {
+	ttl := extensionConfig.Spec.DiscoveryCacheTTLSeconds
+	if ttl == nil || *ttl <= 0 {
+		return nil, false
+	}
+
+	r.discoveryCacheLock.Lock()
+	defer r.discoveryCacheLock.Unlock()
+
+	entry, ok := r.discoveryCache[extensionConfig.Name]
+	if !ok {
+		return nil, false
+	}
+	if time.Since(entry.discoveredAt) >= time.Duration(*ttl)*time.Second ||
+		!apiequality.Semantic.DeepEqual(entry.spec, extensionConfig.Spec) {
+		delete(r.discoveryCache, extensionConfig.Name)
+		return nil, false
+	}
+
+	cachedExtensionConfig := extensionConfig.DeepCopy()
+	cachedExtensionConfig.Status.Handlers = []runtimev1.ExtensionHandler{}
+	for i := range entry.handlers {
+		cachedExtensionConfig.Status.Handlers = append(cachedExtensionConfig.Status.Handlers, *entry.handlers[i].DeepCopy())
+	}
+	conditions.MarkTrue(cachedExtensionConfig, runtimev1.RuntimeExtensionDiscoveredCondition)
+	return cachedExtensionConfig, true
+}
+
+// setCachedDiscovery stores the result of a successful discovery.
+func (r *Reconciler) setCachedDiscovery(extensionConfig, discoveredExtensionConfig *runtimev1.ExtensionConfig) {
+	r.discoveryCacheLock.Lock()
+	defer r.discoveryCacheLock.Unlock()
+
+	if r.discoveryCache == nil {
+		r.discoveryCache = map[string]discoveryCacheEntry{}
+	}
+	entry := discoveryCacheEntry{
+		spec:         *extensionConfig.Spec.DeepCopy(),
+		discoveredAt: time.Now(),
+	}
+	for i := range discoveredExtensionConfig.Status.Handlers {
+		entry.handlers = append(entry.handlers, *discoveredExtensionConfig.Status.Handlers[i].DeepCopy())
+	}
+	r.discoveryCache[extensionConfig.Name] = entry
+}
+
 func patchExtensionConfig(ctx context.Context, client client.Client, original, modified *runtimev1.ExtensionConfig, options ...patch.Option) error {
 	patchHelper, err := patch.NewHelper(original, client)
 	if err != nil {
@@ -179,6 +251,11 @@ func patchExtensionConfig(ctx context.Context, client client.Client, original, m
 // effort deletion that may not catch all cases.
 func (r *Reconciler) reconcileDelete(ctx context.Context, extensionConfig *runtimev1.ExtensionConfig) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
+
+	r.discoveryCacheLock.Lock()
+	delete(r.discoveryCache, extensionConfig.Name)
+	r.discoveryCacheLock.Unlock()
+
 	log.Info("Unregistering ExtensionConfig information from registry")
 	if err := r.RuntimeClient.Unregister(extensionConfig); err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to unregister %s", tlog.KObj{Obj: extensionConfig})
@@ -45,6 +45,7 @@ import (
 	runtimeregistry "sigs.k8s.io/cluster-api/internal/runtime/registry"
 	fakev1alpha1 "sigs.k8s.io/cluster-api/internal/runtime/test/v1alpha1"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestExtensionReconciler_Reconcile(t *testing.T) {
@@ -272,6 +273,57 @@ func TestExtensionReconciler_discoverExtensionConfig(t *testing.T) {
 	})
 }
 
+func TestExtensionReconciler_discoveryCache(t *testing.T) {
+	g := NewWithT(t)
+
+	extensionConfig := fakeExtensionConfigForURL("", "ext1", "https://localhost:31239")
+	extensionConfig.Spec.DiscoveryCacheTTLSeconds = pointer.Int32(300)
+	discoveredExtensionConfig := extensionConfig.DeepCopy()
+	discoveredExtensionConfig.Status.Handlers = []runtimev1.ExtensionHandler{
+		{
+			Name: "first.ext1",
+			RequestHook: runtimev1.GroupVersionHook{
+				Hook:       "FakeHook",
+				APIVersion: fakev1alpha1.GroupVersion.String(),
+			},
+		},
+	}
+
+	r := &Reconciler{}
+
+	// Nothing is cached before the first discovery.
+	_, ok := r.getCachedDiscovery(extensionConfig)
+	g.Expect(ok).To(BeFalse())
+
+	r.setCachedDiscovery(extensionConfig, discoveredExtensionConfig)
+
+	// The discovery is reused if the spec did not change.
+	cachedExtensionConfig, ok := r.getCachedDiscovery(extensionConfig)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(cachedExtensionConfig.Status.Handlers).To(Equal(discoveredExtensionConfig.Status.Handlers))
+	g.Expect(conditions.IsTrue(cachedExtensionConfig, runtimev1.RuntimeExtensionDiscoveredCondition)).To(BeTrue())
+
+	// The discovery is not reused if the cache is disabled.
+	withoutCache := extensionConfig.DeepCopy()
+	withoutCache.Spec.DiscoveryCacheTTLSeconds = nil
+	_, ok = r.getCachedDiscovery(withoutCache)
+	g.Expect(ok).To(BeFalse())
+
+	// The discovery is not reused if the spec changed, e.g. the caBundle has been rotated.
+	changed := extensionConfig.DeepCopy()
+	changed.Spec.ClientConfig.CABundle = testcerts.CACert
+	_, ok = r.getCachedDiscovery(changed)
+	g.Expect(ok).To(BeFalse())
+
+	// The discovery is not reused after the TTL expired.
+	r.setCachedDiscovery(extensionConfig, discoveredExtensionConfig)
+	entry := r.discoveryCache[extensionConfig.Name]
+	entry.discoveredAt = time.Now().Add(-301 * time.Second)
+	r.discoveryCache[extensionConfig.Name] = entry
+	_, ok = r.getCachedDiscovery(extensionConfig)
+	g.Expect(ok).To(BeFalse())
+}
+
 func Test_reconcileCABundle(t *testing.T) {
 	g := NewWithT(t)
 
@@ -60,6 +60,30 @@ const (
 	//
 	// alpha: v1.4
 	LazyRestmapper featuregate.Feature = "LazyRestmapper"
+
+	// KubeletServingCertificateApproval is a feature gate for the approval of kubelet serving
+	// CertificateSigningRequests for Nodes owned by Machines.
+	//
+	// alpha: v1.5
+	KubeletServingCertificateApproval featuregate.Feature = "KubeletServingCertificateApproval"
+
+	// EtcdCertificatesRotation is a feature gate for rotating the etcd certificates in place on KubeadmControlPlane
+	// machines, without replacing them.
+	//
+	// alpha: v1.5
+	EtcdCertificatesRotation featuregate.Feature = "EtcdCertificatesRotation"
+
+	// NodeDriftDetection is a feature gate for detecting out-of-band changes to the Nodes of workload clusters,
+	// e.g. Nodes added manually or kubelets upgraded in place, which are not reflected by the Machines.
+	//
+	// alpha: v1.5
+	NodeDriftDetection featuregate.Feature = "NodeDriftDetection"
+
+	// InClusterIPAM is a feature gate for the in-cluster IPAM provider, allocating IPAddresses for
+	// IPAddressClaims from InClusterIPPools.
+	//
+	// alpha: v1.5
+	InClusterIPAM featuregate.Feature = "InClusterIPAM"
 )
 
 func init() {
@@ -70,10 +94,14 @@ func init() {
 // To add a new feature, define a key for it above and add it here.
 var defaultClusterAPIFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	// Every feature should be initiated here:
-	MachinePool:                    {Default: false, PreRelease: featuregate.Alpha},
-	ClusterResourceSet:             {Default: true, PreRelease: featuregate.Beta},
-	ClusterTopology:                {Default: false, PreRelease: featuregate.Alpha},
-	KubeadmBootstrapFormatIgnition: {Default: false, PreRelease: featuregate.Alpha},
-	RuntimeSDK:                     {Default: false, PreRelease: featuregate.Alpha},
-	LazyRestmapper:                 {Default: false, PreRelease: featuregate.Alpha},
+	MachinePool:                       {Default: false, PreRelease: featuregate.Alpha},
+	ClusterResourceSet:                {Default: true, PreRelease: featuregate.Beta},
+	ClusterTopology:                   {Default: false, PreRelease: featuregate.Alpha},
+	KubeadmBootstrapFormatIgnition:    {Default: false, PreRelease: featuregate.Alpha},
+	RuntimeSDK:                        {Default: false, PreRelease: featuregate.Alpha},
+	LazyRestmapper:                    {Default: false, PreRelease: featuregate.Alpha},
+	KubeletServingCertificateApproval: {Default: false, PreRelease: featuregate.Alpha},
+	EtcdCertificatesRotation:          {Default: false, PreRelease: featuregate.Alpha},
+	NodeDriftDetection:                {Default: false, PreRelease: featuregate.Alpha},
+	InClusterIPAM:                     {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -31,23 +31,28 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/hooks"
+	"sigs.k8s.io/cluster-api/internal/profiling"
 	"sigs.k8s.io/cluster-api/util"
-	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/paused"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
 
@@ -60,6 +65,7 @@ const (
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;patch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io;controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status;clusters/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
@@ -68,21 +74,63 @@ const (
 type Reconciler struct {
 	Client    client.Client
 	APIReader client.Reader
+	Tracker   *remote.ClusterCacheTracker
+
+	// UnstructuredCachingClient provides a client that forces caching of unstructured objects,
+	// thus allowing to optimize reads for templates or provider specific objects.
+	UnstructuredCachingClient client.Client
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	controller      controller.Controller
 	recorder        record.EventRecorder
 	externalTracker external.ObjectTracker
+	// controlPlaneEndpointTracker tracks the watches on the objects referenced by Spec.ControlPlaneEndpointRef;
+	// it is separated from externalTracker because those objects are mapped to Clusters with a different handler.
+	controlPlaneEndpointTracker external.ObjectTracker
 }
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
-	c, err := ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.Cluster{}).
 		Watches(
 			&source.Kind{Type: &clusterv1.Machine{}},
 			handler.EnqueueRequestsFromMapFunc(r.controlPlaneMachineToCluster),
 		).
+		Watches(
+			&source.Kind{Type: &clusterv1.Machine{}},
+			handler.EnqueueRequestsFromMapFunc(r.metadataPropagationObjectToCluster),
+			// Metadata has to be propagated again only when labels or annotations of an object change.
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})),
+		).
+		Watches(
+			&source.Kind{Type: &clusterv1.MachineSet{}},
+			handler.EnqueueRequestsFromMapFunc(r.metadataPropagationObjectToCluster),
+			// Metadata has to be propagated again only when labels or annotations of an object change.
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})),
+		).
+		Watches(
+			&source.Kind{Type: &clusterv1.MachineDeployment{}},
+			handler.EnqueueRequestsFromMapFunc(r.summarizedObjectToCluster),
+		).
+		Watches(
+			&source.Kind{Type: &clusterv1.MachineHealthCheck{}},
+			handler.EnqueueRequestsFromMapFunc(r.summarizedObjectToCluster),
+		).
+		Watches(
+			&source.Kind{Type: &clusterv1.Machine{}},
+			handler.EnqueueRequestsFromMapFunc(r.summarizedObjectToCluster),
+			// The summary has to be computed again only when the result of the MachineHealthChecks on a Machine changes.
+			builder.WithPredicates(machineHealthCheckSucceededChanged()),
+		)
+	if feature.Gates.Enabled(feature.MachinePool) {
+		b = b.Watches(
+			&source.Kind{Type: &expv1.MachinePool{}},
+			handler.EnqueueRequestsFromMapFunc(r.summarizedObjectToCluster),
+		)
+	}
+	c, err := b.
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		Build(r)
@@ -91,19 +139,29 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
+	r.controller = c
 	r.recorder = mgr.GetEventRecorderFor("cluster-controller")
 	r.externalTracker = external.ObjectTracker{
 		Controller: c,
 	}
+	r.controlPlaneEndpointTracker = external.ObjectTracker{
+		Controller: c,
+	}
 	return nil
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
 	log := ctrl.LoggerFrom(ctx)
 
+	ctx, done := profiling.StartReconcile(ctx, "cluster")
+	defer done()
+	profiling.SetCluster(ctx, req.NamespacedName)
+
 	// Fetch the Cluster instance.
 	cluster := &clusterv1.Cluster{}
+	fetchDone := profiling.StartPhase(ctx, profiling.PhaseFetch)
 	if err := r.Client.Get(ctx, req.NamespacedName, cluster); err != nil {
+		fetchDone()
 		if apierrors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
@@ -113,11 +171,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		// Error reading the object - requeue the request.
 		return ctrl.Result{}, err
 	}
+	fetchDone()
 
 	// Return early if the object or Cluster is paused.
-	if annotations.IsPaused(cluster, cluster) {
+	isPaused, requeueAfter, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if isPaused {
 		log.Info("Reconciliation is paused for this object")
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	// Initialize the patch helper.
@@ -132,6 +195,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 		// Always attempt to Patch the Cluster object and status after each reconciliation.
 		// Patch ObservedGeneration only if the reconciliation completed successfully
+		defer profiling.StartPhase(ctx, profiling.PhasePatch)()
 		patchOpts := []patch.Option{}
 		if reterr == nil {
 			patchOpts = append(patchOpts, patch.WithStatusObservedGeneration{})
@@ -173,6 +237,10 @@ func patchCluster(ctx context.Context, patchHelper *patch.Helper, cluster *clust
 			clusterv1.ReadyCondition,
 			clusterv1.ControlPlaneReadyCondition,
 			clusterv1.InfrastructureReadyCondition,
+			clusterv1.ControlPlaneEndpointAvailableCondition,
+			clusterv1.WorkersReadyCondition,
+			clusterv1.MachinesHealthyCondition,
+			clusterv1.DeletionProgressCondition,
 		}},
 	)
 	return patchHelper.Patch(ctx, cluster, options...)
@@ -192,9 +260,13 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster)
 
 	phases := []func(context.Context, *clusterv1.Cluster) (ctrl.Result, error){
 		r.reconcileInfrastructure,
+		r.reconcileControlPlaneEndpoint,
 		r.reconcileControlPlane,
 		r.reconcileKubeconfig,
+		r.reconcileMetadataPropagation,
 		r.reconcileControlPlaneInitialized,
+		r.reconcileSummary,
+		r.reconcileNodeDrift,
 	}
 
 	res := ctrl.Result{}
@@ -263,6 +335,11 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 	}
 
 	if descendantCount := descendants.length(); descendantCount > 0 {
+		if descendants.workersLength() > 0 {
+			setDeletionProgress(cluster, clusterv1.DeletingWorkersReason, descendants.workersProgressMessage(), descendants.blockedWorkers())
+		} else {
+			setDeletionProgress(cluster, clusterv1.DeletingControlPlaneReason, descendants.controlPlaneProgressMessage(), descendants.blockedControlPlaneMachines())
+		}
 		indirect := descendantCount - len(children)
 		log.Info("Cluster still has descendants - need to requeue", "descendants", descendants.descendantNames(), "indirect descendants count", indirect)
 		// Requeue so we can check the next time to see if there are still any descendants left.
@@ -294,6 +371,13 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 					obj.GroupVersionKind(), obj.GetName(), cluster.Name, cluster.Namespace)
 			}
 
+			message := fmt.Sprintf("Waiting for %s %s to be deleted", obj.GetKind(), obj.GetName())
+			if remaining := descendants.controlPlaneProgressMessage(); remaining != "" {
+				message = fmt.Sprintf("%s. %s", message, remaining)
+			}
+			blocked := append(blockedByForeignFinalizersObject(obj.GetKind(), obj), descendants.blockedControlPlaneMachines()...)
+			setDeletionProgress(cluster, clusterv1.DeletingControlPlaneReason, message, blocked)
+
 			// Return here so we don't remove the finalizer yet.
 			log.Info("Cluster still has descendants - need to requeue", "controlPlaneRef", cluster.Spec.ControlPlaneRef.Name)
 			return ctrl.Result{}, nil
@@ -325,6 +409,10 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 					obj.GroupVersionKind(), obj.GetName(), cluster.Name, cluster.Namespace)
 			}
 
+			setDeletionProgress(cluster, clusterv1.DeletingInfrastructureReason,
+				fmt.Sprintf("Waiting for %s %s to be deleted", obj.GetKind(), obj.GetName()),
+				blockedByForeignFinalizersObject(obj.GetKind(), obj))
+
 			// Return here so we don't remove the finalizer yet.
 			log.Info("Cluster still has descendants - need to requeue", "infrastructureRef", cluster.Spec.InfrastructureRef.Name)
 			return ctrl.Result{}, nil
@@ -342,6 +430,10 @@ type clusterDescendants struct {
 	controlPlaneMachines clusterv1.MachineList
 	workerMachines       clusterv1.MachineList
 	machinePools         expv1.MachinePoolList
+
+	// providerControlPlaneMachines are the control plane machines managed by a control plane provider; they are
+	// deleted by the control plane provider, and thus they are only used to report the deletion progress.
+	providerControlPlaneMachines clusterv1.MachineList
 }
 
 // length returns the number of descendants.
@@ -430,13 +522,15 @@ func (r *Reconciler) listDescendants(ctx context.Context, cluster *clusterv1.Clu
 	// Only count control plane machines as descendants if there is no control plane provider.
 	if cluster.Spec.ControlPlaneRef == nil {
 		descendants.controlPlaneMachines = collections.ToMachineList(controlPlaneMachines)
+	} else {
+		descendants.providerControlPlaneMachines = collections.ToMachineList(controlPlaneMachines)
 	}
 
 	return descendants, nil
 }
 
 // filterOwnedDescendants returns an array of runtime.Objects containing only those descendants that have the cluster
-// as an owner reference, with control plane machines sorted last.
+// as an owner reference; control plane machines are returned only after all the worker descendants have been deleted.
 func (c clusterDescendants) filterOwnedDescendants(cluster *clusterv1.Cluster) ([]client.Object, error) {
 	var ownedDescendants []client.Object
 	eachFunc := func(o runtime.Object) error {
@@ -457,11 +551,15 @@ func (c clusterDescendants) filterOwnedDescendants(cluster *clusterv1.Cluster) (
 		&c.machineDeployments,
 		&c.machineSets,
 		&c.workerMachines,
-		&c.controlPlaneMachines,
 	}
 	if feature.Gates.Enabled(feature.MachinePool) {
 		lists = append([]client.ObjectList{&c.machinePools}, lists...)
 	}
+	// Delete control plane machines only after all the workers are gone, so workloads can be drained
+	// while the control plane is still available.
+	if c.workersLength() == 0 {
+		lists = append(lists, &c.controlPlaneMachines)
+	}
 
 	for _, list := range lists {
 		if err := meta.EachListItem(list, eachFunc); err != nil {
@@ -534,3 +632,51 @@ func (r *Reconciler) controlPlaneMachineToCluster(o client.Object) []ctrl.Reques
 		NamespacedName: util.ObjectKey(cluster),
 	}}
 }
+
+// summarizedObjectToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update its status.summary field when one of the objects it summarizes gets updated.
+func (r *Reconciler) summarizedObjectToCluster(o client.Object) []ctrl.Request {
+	var clusterName string
+	switch obj := o.(type) {
+	case *clusterv1.MachineDeployment:
+		clusterName = obj.Spec.ClusterName
+	case *clusterv1.MachineHealthCheck:
+		clusterName = obj.Spec.ClusterName
+	case *expv1.MachinePool:
+		clusterName = obj.Spec.ClusterName
+	case *clusterv1.Machine:
+		clusterName = obj.Spec.ClusterName
+	default:
+		panic(fmt.Sprintf("Expected a MachineDeployment, MachineHealthCheck, MachinePool or Machine but got a %T", o))
+	}
+	if clusterName == "" {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: client.ObjectKey{
+			Namespace: o.GetNamespace(),
+			Name:      clusterName,
+		},
+	}}
+}
+
+// machineHealthCheckSucceededChanged returns a predicate filtering Machine updates which do not change the status
+// of the MachineHealthCheckSucceeded condition, which is used for counting the unhealthy Machines in the summary.
+func machineHealthCheckSucceededChanged() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldMachine, ok := e.ObjectOld.(*clusterv1.Machine)
+			if !ok {
+				return false
+			}
+			newMachine, ok := e.ObjectNew.(*clusterv1.Machine)
+			if !ok {
+				return false
+			}
+			return conditions.IsFalse(oldMachine, clusterv1.MachineHealthCheckSucceededCondition) !=
+				conditions.IsFalse(newMachine, clusterv1.MachineHealthCheckSucceededCondition)
+		},
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
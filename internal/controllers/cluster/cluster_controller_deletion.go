@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// maxBlockedObjectsInMessage is the max number of objects blocked by foreign finalizers
+// reported in the message of the DeletionProgress condition.
+const maxBlockedObjectsInMessage = 5
+
+// workersLength returns the number of worker descendants, i.e. MachinePools, MachineDeployments,
+// MachineSets and worker Machines.
+func (c *clusterDescendants) workersLength() int {
+	return len(c.machineDeployments.Items) +
+		len(c.machineSets.Items) +
+		len(c.workerMachines.Items) +
+		len(c.machinePools.Items)
+}
+
+// workersProgressMessage returns a message with the count of the remaining worker descendants.
+func (c *clusterDescendants) workersProgressMessage() string {
+	return remainingMessage(
+		remaining{len(c.machinePools.Items), "MachinePools"},
+		remaining{len(c.machineDeployments.Items), "MachineDeployments"},
+		remaining{len(c.machineSets.Items), "MachineSets"},
+		remaining{len(c.workerMachines.Items), "worker Machines"},
+	)
+}
+
+// controlPlaneProgressMessage returns a message with the count of the remaining control plane Machines,
+// including the ones managed by a control plane provider.
+func (c *clusterDescendants) controlPlaneProgressMessage() string {
+	return remainingMessage(
+		remaining{len(c.controlPlaneMachines.Items) + len(c.providerControlPlaneMachines.Items), "control plane Machines"},
+	)
+}
+
+// blockedWorkers returns the worker descendants whose deletion is blocked by foreign finalizers.
+func (c *clusterDescendants) blockedWorkers() []string {
+	var blocked []string
+	blocked = append(blocked, blockedByForeignFinalizers("MachinePool", &c.machinePools)...)
+	blocked = append(blocked, blockedByForeignFinalizers("MachineDeployment", &c.machineDeployments)...)
+	blocked = append(blocked, blockedByForeignFinalizers("MachineSet", &c.machineSets)...)
+	return append(blocked, blockedByForeignFinalizers("Machine", &c.workerMachines)...)
+}
+
+// blockedControlPlaneMachines returns the control plane Machines whose deletion is blocked by foreign finalizers,
+// including the ones managed by a control plane provider.
+func (c *clusterDescendants) blockedControlPlaneMachines() []string {
+	blocked := blockedByForeignFinalizers("Machine", &c.controlPlaneMachines)
+	return append(blocked, blockedByForeignFinalizers("Machine", &c.providerControlPlaneMachines)...)
+}
+
+type remaining struct {
+	count int
+	kind  string
+}
+
+// remainingMessage returns a message listing the non-zero counts of the remaining objects.
+func remainingMessage(items ...remaining) string {
+	counts := []string{}
+	for _, item := range items {
+		if item.count > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", item.count, item.kind))
+		}
+	}
+	if len(counts) == 0 {
+		return ""
+	}
+	return "Remaining: " + strings.Join(counts, ", ")
+}
+
+// blockedByForeignFinalizers returns the objects in the list whose deletion is blocked by foreign finalizers.
+func blockedByForeignFinalizers(kind string, list client.ObjectList) []string {
+	var blocked []string
+	_ = meta.EachListItem(list, func(o runtime.Object) error {
+		if obj, ok := o.(client.Object); ok {
+			blocked = append(blocked, blockedByForeignFinalizersObject(kind, obj)...)
+		}
+		return nil
+	})
+	return blocked
+}
+
+// blockedByForeignFinalizersObject returns the object, if it is being deleted and its deletion is blocked by
+// foreign finalizers.
+func blockedByForeignFinalizersObject(kind string, obj client.Object) []string {
+	if obj.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+	finalizers := foreignFinalizers(obj)
+	if len(finalizers) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s %s (%s)", kind, obj.GetName(), strings.Join(finalizers, ", "))}
+}
+
+// foreignFinalizers returns the finalizers of an object not owned by Cluster API or by a Cluster API provider,
+// i.e. finalizers not in the cluster.x-k8s.io domain or in one of its subdomains.
+// NOTE: The finalizers used by the Kubernetes garbage collector are not considered foreign, because they are
+// removed by the garbage collector as soon as the dependent objects are deleted.
+func foreignFinalizers(obj metav1.Object) []string {
+	var foreign []string
+	for _, finalizer := range obj.GetFinalizers() {
+		if finalizer == metav1.FinalizerDeleteDependents || finalizer == metav1.FinalizerOrphanDependents {
+			continue
+		}
+		domain := strings.SplitN(finalizer, "/", 2)[0]
+		if domain == clusterv1.GroupVersion.Group || strings.HasSuffix(domain, "."+clusterv1.GroupVersion.Group) {
+			continue
+		}
+		foreign = append(foreign, finalizer)
+	}
+	return foreign
+}
+
+// setDeletionProgress sets the DeletionProgress condition on the cluster; if the deletion is blocked by foreign
+// finalizers, the severity of the condition is Warning and the blocked objects are added to the message.
+func setDeletionProgress(cluster *clusterv1.Cluster, reason, message string, blocked []string) {
+	severity := clusterv1.ConditionSeverityInfo
+	if len(blocked) > 0 {
+		severity = clusterv1.ConditionSeverityWarning
+		blockedMessage := strings.Join(blocked, "; ")
+		if len(blocked) > maxBlockedObjectsInMessage {
+			blockedMessage = fmt.Sprintf("%s; and %d more", strings.Join(blocked[:maxBlockedObjectsInMessage], "; "), len(blocked)-maxBlockedObjectsInMessage)
+		}
+		message = strings.TrimPrefix(fmt.Sprintf("%s. Deletion blocked by foreign finalizers on: %s", message, blockedMessage), ". ")
+	}
+	conditions.MarkFalse(cluster, clusterv1.DeletionProgressCondition, reason, severity, "%s", message)
+}
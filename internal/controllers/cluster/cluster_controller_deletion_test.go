@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestForeignFinalizers(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &metav1.ObjectMeta{
+		Finalizers: []string{
+			clusterv1.MachineFinalizer,
+			"dockermachine.infrastructure.cluster.x-k8s.io",
+			"addons.cluster.x-k8s.io/protect",
+			metav1.FinalizerDeleteDependents,
+			metav1.FinalizerOrphanDependents,
+			"example.com/protect",
+			"cluster.x-k8s.io.example.com",
+			"kubernetes",
+		},
+	}
+
+	g.Expect(foreignFinalizers(obj)).To(Equal([]string{"example.com/protect", "cluster.x-k8s.io.example.com", "kubernetes"}))
+}
+
+func TestSetDeletionProgress(t *testing.T) {
+	tests := []struct {
+		name         string
+		message      string
+		blocked      []string
+		wantSeverity clusterv1.ConditionSeverity
+		wantMessage  string
+	}{
+		{
+			name:         "not blocked",
+			message:      "Remaining: 2 MachineDeployments",
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+			wantMessage:  "Remaining: 2 MachineDeployments",
+		},
+		{
+			name:         "blocked by foreign finalizers",
+			message:      "Remaining: 1 worker Machines",
+			blocked:      []string{"Machine m1 (example.com/protect)"},
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+			wantMessage:  "Remaining: 1 worker Machines. Deletion blocked by foreign finalizers on: Machine m1 (example.com/protect)",
+		},
+		{
+			name:         "blocked by foreign finalizers without remaining objects",
+			blocked:      []string{"DockerCluster c1 (example.com/protect)"},
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+			wantMessage:  "Deletion blocked by foreign finalizers on: DockerCluster c1 (example.com/protect)",
+		},
+		{
+			name:         "blocked by foreign finalizers on many objects",
+			message:      "Remaining: 6 worker Machines",
+			blocked:      []string{"Machine m1 (f)", "Machine m2 (f)", "Machine m3 (f)", "Machine m4 (f)", "Machine m5 (f)", "Machine m6 (f)", "Machine m7 (f)"},
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+			wantMessage:  "Remaining: 6 worker Machines. Deletion blocked by foreign finalizers on: Machine m1 (f); Machine m2 (f); Machine m3 (f); Machine m4 (f); Machine m5 (f); and 2 more",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{}
+			setDeletionProgress(cluster, clusterv1.DeletingWorkersReason, tt.message, tt.blocked)
+
+			c := conditions.Get(cluster, clusterv1.DeletionProgressCondition)
+			g.Expect(c).ToNot(BeNil())
+			g.Expect(c.Status).To(Equal(corev1.ConditionFalse))
+			g.Expect(c.Reason).To(Equal(clusterv1.DeletingWorkersReason))
+			g.Expect(c.Severity).To(Equal(tt.wantSeverity))
+			g.Expect(c.Message).To(Equal(tt.wantMessage))
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileDeleteOrder(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster("test-ns", "test-cluster").Build()
+	cluster.Kind = "Cluster"
+	cluster.APIVersion = clusterv1.GroupVersion.String()
+
+	newMachine := func(name string, finalizers ...string) *clusterv1.Machine {
+		m := newMachineBuilder().named(name).ownedBy(cluster).build()
+		m.Namespace = cluster.Namespace
+		m.Labels = map[string]string{clusterv1.ClusterNameLabel: cluster.Name}
+		m.Finalizers = finalizers
+		return &m
+	}
+	worker := newMachine("worker", "example.com/protect")
+	controlPlane := newMachine("control-plane")
+	controlPlane.Labels[clusterv1.MachineControlPlaneLabel] = ""
+
+	fakeClient := fake.NewClientBuilder().WithObjects(cluster, worker, controlPlane).Build()
+	r := &Reconciler{
+		Client:    fakeClient,
+		APIReader: fakeClient,
+	}
+
+	// The worker Machine is deleted first, while the control plane Machine is preserved.
+	res, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(worker), worker)).To(Succeed())
+	g.Expect(worker.DeletionTimestamp.IsZero()).To(BeFalse())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(controlPlane), controlPlane)).To(Succeed())
+	g.Expect(controlPlane.DeletionTimestamp.IsZero()).To(BeTrue())
+
+	c := conditions.Get(cluster, clusterv1.DeletionProgressCondition)
+	g.Expect(c).ToNot(BeNil())
+	g.Expect(c.Reason).To(Equal(clusterv1.DeletingWorkersReason))
+	g.Expect(c.Severity).To(Equal(clusterv1.ConditionSeverityInfo))
+	g.Expect(c.Message).To(Equal("Remaining: 1 worker Machines"))
+
+	// The worker Machine is now blocked by a foreign finalizer.
+	_, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	c = conditions.Get(cluster, clusterv1.DeletionProgressCondition)
+	g.Expect(c.Reason).To(Equal(clusterv1.DeletingWorkersReason))
+	g.Expect(c.Severity).To(Equal(clusterv1.ConditionSeverityWarning))
+	g.Expect(c.Message).To(Equal("Remaining: 1 worker Machines. Deletion blocked by foreign finalizers on: Machine worker (example.com/protect)"))
+
+	// Once the worker Machine is gone, the control plane Machine is deleted.
+	worker.Finalizers = nil
+	g.Expect(fakeClient.Update(ctx, worker)).To(Succeed())
+
+	_, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(controlPlane), controlPlane))).To(BeTrue())
+
+	c = conditions.Get(cluster, clusterv1.DeletionProgressCondition)
+	g.Expect(c.Reason).To(Equal(clusterv1.DeletingControlPlaneReason))
+	g.Expect(c.Message).To(Equal("Remaining: 1 control plane Machines"))
+}
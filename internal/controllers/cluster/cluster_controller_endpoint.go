@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileControlPlaneEndpoint sets Spec.ControlPlaneEndpoint from the object referenced by Spec.ControlPlaneEndpointRef,
+// if any; the referenced object is watched, so the Cluster is reconciled as soon as the endpoint is reported.
+func (r *Reconciler) reconcileControlPlaneEndpoint(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	ref := cluster.Spec.ControlPlaneEndpointRef
+	if ref == nil {
+		conditions.Delete(cluster, clusterv1.ControlPlaneEndpointAvailableCondition)
+		return ctrl.Result{}, nil
+	}
+
+	obj, err := external.Get(ctx, r.Client, ref, cluster.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			log.Info("Could not find control plane endpoint object for cluster, requeuing", "refGroupVersionKind", ref.GroupVersionKind(), "refName", ref.Name)
+			conditions.MarkFalse(cluster, clusterv1.ControlPlaneEndpointAvailableCondition, clusterv1.ControlPlaneEndpointRefNotFoundReason, clusterv1.ConditionSeverityWarning,
+				"%s %s does not exist", ref.Kind, ref.Name)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Ensure we add a watcher to the referenced object.
+	// NOTE: The referenced object is not owned by the Cluster, e.g. it can be a Service managed by someone else,
+	// so it is mapped to the Clusters referencing it instead of relying on owner references.
+	if err := r.controlPlaneEndpointTracker.Watch(log, obj, handler.EnqueueRequestsFromMapFunc(r.controlPlaneEndpointObjectToCluster)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	endpoint, err := controlPlaneEndpointFrom(obj)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve the control plane endpoint from %s %s for Cluster %q in namespace %q",
+			ref.Kind, ref.Name, cluster.Name, cluster.Namespace)
+	}
+
+	if !endpoint.IsValid() {
+		log.V(3).Info("Waiting for the control plane endpoint to be reported", "refGroupVersionKind", ref.GroupVersionKind(), "refName", ref.Name)
+		conditions.MarkFalse(cluster, clusterv1.ControlPlaneEndpointAvailableCondition, clusterv1.WaitingForControlPlaneEndpointReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for %s %s to report the control plane endpoint", ref.Kind, ref.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if !cluster.Spec.ControlPlaneEndpoint.IsValid() {
+		cluster.Spec.ControlPlaneEndpoint = endpoint
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "ControlPlaneEndpointSet", "Cluster %s control plane endpoint set to %s from %s %s",
+			cluster.Name, endpoint.String(), ref.Kind, ref.Name)
+	}
+
+	// The control plane endpoint can't be changed once set, because it is used in certificates and kubeconfigs.
+	if cluster.Spec.ControlPlaneEndpoint != endpoint {
+		conditions.MarkFalse(cluster, clusterv1.ControlPlaneEndpointAvailableCondition, clusterv1.ControlPlaneEndpointMismatchReason, clusterv1.ConditionSeverityWarning,
+			"%s %s reports %s, but the Cluster control plane endpoint is %s", ref.Kind, ref.Name, endpoint.String(), cluster.Spec.ControlPlaneEndpoint.String())
+		return ctrl.Result{}, nil
+	}
+
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneEndpointAvailableCondition)
+	return ctrl.Result{}, nil
+}
+
+// controlPlaneEndpointFrom returns the control plane endpoint reported by an object referenced by Spec.ControlPlaneEndpointRef:
+// for a Service, the endpoint is composed by the first ingress point of the load balancer and by the first port of
+// the Service; for any other object, the endpoint is read from status.controlPlaneEndpoint.
+func controlPlaneEndpointFrom(obj *unstructured.Unstructured) (clusterv1.APIEndpoint, error) {
+	endpoint := clusterv1.APIEndpoint{}
+
+	if obj.GroupVersionKind().GroupKind() == corev1.SchemeGroupVersion.WithKind("Service").GroupKind() {
+		service := &corev1.Service{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), service); err != nil {
+			return endpoint, err
+		}
+		if len(service.Status.LoadBalancer.Ingress) == 0 || len(service.Spec.Ports) == 0 {
+			return endpoint, nil
+		}
+		endpoint.Host = service.Status.LoadBalancer.Ingress[0].IP
+		if endpoint.Host == "" {
+			endpoint.Host = service.Status.LoadBalancer.Ingress[0].Hostname
+		}
+		endpoint.Port = service.Spec.Ports[0].Port
+		return endpoint, nil
+	}
+
+	if err := util.UnstructuredUnmarshalField(obj, &endpoint, "status", "controlPlaneEndpoint"); err != nil && err != util.ErrUnstructuredFieldNotFound {
+		return endpoint, err
+	}
+	return endpoint, nil
+}
+
+// controlPlaneEndpointObjectToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for the Clusters referencing an object in Spec.ControlPlaneEndpointRef.
+func (r *Reconciler) controlPlaneEndpointObjectToCluster(o client.Object) []reconcile.Request {
+	clusters := &clusterv1.ClusterList{}
+	if err := r.Client.List(context.TODO(), clusters, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	gk := o.GetObjectKind().GroupVersionKind().GroupKind()
+	requests := []reconcile.Request{}
+	for i := range clusters.Items {
+		ref := clusters.Items[i].Spec.ControlPlaneEndpointRef
+		if ref == nil || ref.Name != o.GetName() || ref.GroupVersionKind().GroupKind() != gk {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: util.ObjectKey(&clusters.Items[i])})
+	}
+	return requests
+}
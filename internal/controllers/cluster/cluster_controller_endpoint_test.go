@@ -0,0 +1,266 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestControlPlaneEndpointFrom(t *testing.T) {
+	tests := []struct {
+		name   string
+		obj    map[string]interface{}
+		expect clusterv1.APIEndpoint
+	}{
+		{
+			name: "Service with a load balancer IP",
+			obj:  newControlPlaneEndpointService("lb", corev1.LoadBalancerIngress{IP: "10.0.0.1"}),
+			expect: clusterv1.APIEndpoint{
+				Host: "10.0.0.1",
+				Port: 6443,
+			},
+		},
+		{
+			name: "Service with a load balancer hostname",
+			obj:  newControlPlaneEndpointService("lb", corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			expect: clusterv1.APIEndpoint{
+				Host: "lb.example.com",
+				Port: 6443,
+			},
+		},
+		{
+			name:   "Service without a load balancer ingress point",
+			obj:    newControlPlaneEndpointService("lb"),
+			expect: clusterv1.APIEndpoint{},
+		},
+		{
+			name: "object reporting status.controlPlaneEndpoint",
+			obj: newControlPlaneEndpointObject("lb", map[string]interface{}{
+				"host": "lb.example.com",
+				"port": int64(443),
+			}),
+			expect: clusterv1.APIEndpoint{
+				Host: "lb.example.com",
+				Port: 443,
+			},
+		},
+		{
+			name:   "object not reporting status.controlPlaneEndpoint yet",
+			obj:    newControlPlaneEndpointObject("lb", nil),
+			expect: clusterv1.APIEndpoint{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			endpoint, err := controlPlaneEndpointFrom(&unstructured.Unstructured{Object: tt.obj})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(endpoint).To(Equal(tt.expect))
+		})
+	}
+}
+
+func TestClusterReconciler_reconcileControlPlaneEndpoint(t *testing.T) {
+	tests := []struct {
+		name            string
+		endpoint        clusterv1.APIEndpoint
+		ref             *corev1.ObjectReference
+		obj             map[string]interface{}
+		expectResult    ctrl.Result
+		expectEndpoint  clusterv1.APIEndpoint
+		expectCondition *clusterv1.Condition
+	}{
+		{
+			name:           "no control plane endpoint reference",
+			endpoint:       clusterv1.APIEndpoint{Host: "1.2.3.4", Port: 6443},
+			expectEndpoint: clusterv1.APIEndpoint{Host: "1.2.3.4", Port: 6443},
+		},
+		{
+			name:         "control plane endpoint object does not exist",
+			ref:          &corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Name: "lb"},
+			expectResult: ctrl.Result{RequeueAfter: 30 * time.Second},
+			expectCondition: conditions.FalseCondition(clusterv1.ControlPlaneEndpointAvailableCondition,
+				clusterv1.ControlPlaneEndpointRefNotFoundReason, clusterv1.ConditionSeverityWarning, ""),
+		},
+		{
+			name: "control plane endpoint not reported yet",
+			ref:  &corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Name: "lb"},
+			obj:  newControlPlaneEndpointService("lb"),
+			expectCondition: conditions.FalseCondition(clusterv1.ControlPlaneEndpointAvailableCondition,
+				clusterv1.WaitingForControlPlaneEndpointReason, clusterv1.ConditionSeverityInfo, ""),
+		},
+		{
+			name:            "control plane endpoint set from a Service",
+			ref:             &corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Name: "lb"},
+			obj:             newControlPlaneEndpointService("lb", corev1.LoadBalancerIngress{IP: "10.0.0.1"}),
+			expectEndpoint:  clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443},
+			expectCondition: conditions.TrueCondition(clusterv1.ControlPlaneEndpointAvailableCondition),
+		},
+		{
+			name: "control plane endpoint set from an object reporting status.controlPlaneEndpoint",
+			ref:  &corev1.ObjectReference{APIVersion: builder.InfrastructureGroupVersion.String(), Kind: "GenericLoadBalancer", Name: "lb"},
+			obj: newControlPlaneEndpointObject("lb", map[string]interface{}{
+				"host": "lb.example.com",
+				"port": int64(443),
+			}),
+			expectEndpoint:  clusterv1.APIEndpoint{Host: "lb.example.com", Port: 443},
+			expectCondition: conditions.TrueCondition(clusterv1.ControlPlaneEndpointAvailableCondition),
+		},
+		{
+			name:            "control plane endpoint already set",
+			endpoint:        clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443},
+			ref:             &corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Name: "lb"},
+			obj:             newControlPlaneEndpointService("lb", corev1.LoadBalancerIngress{IP: "10.0.0.1"}),
+			expectEndpoint:  clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443},
+			expectCondition: conditions.TrueCondition(clusterv1.ControlPlaneEndpointAvailableCondition),
+		},
+		{
+			name:           "control plane endpoint not matching the one reported",
+			endpoint:       clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443},
+			ref:            &corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Name: "lb"},
+			obj:            newControlPlaneEndpointService("lb", corev1.LoadBalancerIngress{IP: "10.0.0.2"}),
+			expectEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443},
+			expectCondition: conditions.FalseCondition(clusterv1.ControlPlaneEndpointAvailableCondition,
+				clusterv1.ControlPlaneEndpointMismatchReason, clusterv1.ConditionSeverityWarning, ""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneEndpoint:    tt.endpoint,
+					ControlPlaneEndpointRef: tt.ref,
+				},
+			}
+
+			objs := []runtime.Object{cluster}
+			if tt.obj != nil {
+				objs = append(objs, &unstructured.Unstructured{Object: tt.obj})
+			}
+			r := &Reconciler{
+				Client:   fake.NewClientBuilder().WithRuntimeObjects(objs...).Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			res, err := r.reconcileControlPlaneEndpoint(ctx, cluster)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(res).To(Equal(tt.expectResult))
+			g.Expect(cluster.Spec.ControlPlaneEndpoint).To(Equal(tt.expectEndpoint))
+			if tt.expectCondition == nil {
+				g.Expect(conditions.Has(cluster, clusterv1.ControlPlaneEndpointAvailableCondition)).To(BeFalse())
+				return
+			}
+			c := conditions.Get(cluster, clusterv1.ControlPlaneEndpointAvailableCondition)
+			g.Expect(c).ToNot(BeNil())
+			g.Expect(c.Status).To(Equal(tt.expectCondition.Status))
+			g.Expect(c.Reason).To(Equal(tt.expectCondition.Reason))
+		})
+	}
+}
+
+func TestClusterReconciler_controlPlaneEndpointObjectToCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	newCluster := func(name string, ref *corev1.ObjectReference) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneEndpointRef: ref,
+			},
+		}
+	}
+
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().WithObjects(
+			newCluster("referencing", &corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Name: "lb"}),
+			newCluster("referencing-another-object", &corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Name: "another-lb"}),
+			newCluster("referencing-another-kind", &corev1.ObjectReference{APIVersion: builder.InfrastructureGroupVersion.String(), Kind: "GenericLoadBalancer", Name: "lb"}),
+			newCluster("not-referencing", nil),
+		).Build(),
+	}
+
+	requests := r.controlPlaneEndpointObjectToCluster(&unstructured.Unstructured{Object: newControlPlaneEndpointService("lb")})
+	g.Expect(requests).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "referencing"}}))
+}
+
+func newControlPlaneEndpointService(name string, ingress ...corev1.LoadBalancerIngress) map[string]interface{} {
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{{Port: 6443}},
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{Ingress: ingress},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(service)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+func newControlPlaneEndpointObject(name string, endpoint map[string]interface{}) map[string]interface{} {
+	obj := map[string]interface{}{
+		"apiVersion": builder.InfrastructureGroupVersion.String(),
+		"kind":       "GenericLoadBalancer",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": metav1.NamespaceDefault,
+		},
+	}
+	if endpoint != nil {
+		obj["status"] = map[string]interface{}{
+			"controlPlaneEndpoint": endpoint,
+		}
+	}
+	return obj
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// reconcileMetadataPropagation propagates the labels and annotations selected in spec.metadataPropagation
+// to the objects of the Cluster.
+// NOTE: Values are propagated only if there is no different value already set on an object, e.g. by users or by
+// other controllers; the values propagated last are tracked in the PropagatedLabelsAnnotation and
+// PropagatedAnnotationsAnnotation annotations, so they can be updated or removed when the values on the Cluster
+// change or when they are not selected for propagation anymore.
+func (r *Reconciler) reconcileMetadataPropagation(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if cluster.Spec.MetadataPropagation == nil {
+		return ctrl.Result{}, nil
+	}
+
+	labels := selectedValues(cluster.GetLabels(), cluster.Spec.MetadataPropagation.Labels)
+	annotations := selectedValues(cluster.GetAnnotations(), cluster.Spec.MetadataPropagation.Annotations)
+
+	objs, err := r.listMetadataPropagationTargets(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	errs := []error{}
+	for _, obj := range objs {
+		patchHelper, err := patch.NewHelper(obj, r.Client)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		changed, err := propagateMetadata(obj, labels, annotations)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to propagate metadata to %T %s", obj, klog.KObj(obj)))
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		log.V(4).Info("Propagating Cluster metadata", "object", klog.KObj(obj))
+		if err := patchHelper.Patch(ctx, obj); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to patch %T %s", obj, klog.KObj(obj)))
+		}
+	}
+	return ctrl.Result{}, kerrors.NewAggregate(errs)
+}
+
+// listMetadataPropagationTargets returns the objects of the Cluster the metadata of the Cluster is propagated to,
+// i.e. MachineDeployments, MachineSets, MachinePools, Machines, bootstrap configs and Secrets.
+func (r *Reconciler) listMetadataPropagationTargets(ctx context.Context, cluster *clusterv1.Cluster) ([]client.Object, error) {
+	listOptions := []client.ListOption{
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name},
+	}
+	objs := []client.Object{}
+
+	mdList := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, mdList, listOptions...); err != nil {
+		return nil, errors.Wrapf(err, "failed to list MachineDeployments for cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	for i := range mdList.Items {
+		objs = append(objs, &mdList.Items[i])
+	}
+
+	msList := &clusterv1.MachineSetList{}
+	if err := r.Client.List(ctx, msList, listOptions...); err != nil {
+		return nil, errors.Wrapf(err, "failed to list MachineSets for cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	for i := range msList.Items {
+		objs = append(objs, &msList.Items[i])
+	}
+
+	if feature.Gates.Enabled(feature.MachinePool) {
+		mpList := &expv1.MachinePoolList{}
+		if err := r.Client.List(ctx, mpList, listOptions...); err != nil {
+			return nil, errors.Wrapf(err, "failed to list MachinePools for cluster %s/%s", cluster.Namespace, cluster.Name)
+		}
+		for i := range mpList.Items {
+			objs = append(objs, &mpList.Items[i])
+		}
+	}
+
+	machineList := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machineList, listOptions...); err != nil {
+		return nil, errors.Wrapf(err, "failed to list Machines for cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	for i := range machineList.Items {
+		machine := &machineList.Items[i]
+		objs = append(objs, machine)
+
+		if machine.Spec.Bootstrap.ConfigRef == nil {
+			continue
+		}
+		// NOTE: The bootstrap config is read from the cache; the Machine controller already watches bootstrap configs,
+		// so this does not add informers.
+		config, err := external.Get(ctx, r.UnstructuredCachingClient, machine.Spec.Bootstrap.ConfigRef, machine.Namespace)
+		if err != nil {
+			if apierrors.IsNotFound(errors.Cause(err)) {
+				// The bootstrap config has not been created yet, or it has been deleted; nothing to propagate.
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get bootstrap config for Machine %s", klog.KObj(machine))
+		}
+		objs = append(objs, config)
+	}
+
+	// NOTE: Only the metadata of the Secrets is required for propagating labels and annotations, so Secrets are listed
+	// as PartialObjectMetadata from the cache; this avoids a live list of Secrets, which are not cached by the default
+	// client, and it avoids caching Secrets data.
+	secretList := &metav1.PartialObjectMetadataList{}
+	secretList.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("SecretList"))
+	if err := r.UnstructuredCachingClient.List(ctx, secretList, listOptions...); err != nil {
+		return nil, errors.Wrapf(err, "failed to list Secrets for cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	for i := range secretList.Items {
+		secret := &secretList.Items[i]
+		secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+		objs = append(objs, secret)
+	}
+
+	return objs, nil
+}
+
+// selectedValues returns the values of the given keys; keys without a value are ignored.
+func selectedValues(values map[string]string, keys []string) map[string]string {
+	selected := map[string]string{}
+	for _, key := range keys {
+		if value, ok := values[key]; ok {
+			selected[key] = value
+		}
+	}
+	return selected
+}
+
+// propagateMetadata propagates labels and annotations to an object, and it returns true if the object has been changed.
+func propagateMetadata(obj metav1.Object, labels, annotations map[string]string) (bool, error) {
+	originalLabels := obj.GetLabels()
+	originalAnnotations := obj.GetAnnotations()
+
+	newLabels := copyValues(originalLabels)
+	newAnnotations := copyValues(originalAnnotations)
+
+	lastPropagatedLabels, err := lastPropagatedValues(newAnnotations, clusterv1.PropagatedLabelsAnnotation)
+	if err != nil {
+		return false, err
+	}
+	lastPropagatedAnnotations, err := lastPropagatedValues(newAnnotations, clusterv1.PropagatedAnnotationsAnnotation)
+	if err != nil {
+		return false, err
+	}
+
+	propagatedLabels := syncPropagatedValues(newLabels, labels, lastPropagatedLabels)
+	propagatedAnnotations := syncPropagatedValues(newAnnotations, annotations, lastPropagatedAnnotations)
+
+	if err := setPropagatedValues(newAnnotations, clusterv1.PropagatedLabelsAnnotation, propagatedLabels); err != nil {
+		return false, err
+	}
+	if err := setPropagatedValues(newAnnotations, clusterv1.PropagatedAnnotationsAnnotation, propagatedAnnotations); err != nil {
+		return false, err
+	}
+
+	changed := false
+	if !equalValues(originalLabels, newLabels) {
+		obj.SetLabels(newLabels)
+		changed = true
+	}
+	if !equalValues(originalAnnotations, newAnnotations) {
+		obj.SetAnnotations(newAnnotations)
+		changed = true
+	}
+	return changed, nil
+}
+
+// syncPropagatedValues updates values with the desired propagated values, and it returns the values which are
+// propagated after the update.
+// Values propagated last which have been changed or removed in the meantime are not owned by the propagation anymore,
+// and they are left untouched; in the same way, desired values are not propagated if a different value is already set.
+func syncPropagatedValues(values, desired, lastPropagated map[string]string) map[string]string {
+	propagated := map[string]string{}
+
+	for key, lastValue := range lastPropagated {
+		if value, ok := values[key]; !ok || value != lastValue {
+			continue
+		}
+		if _, ok := desired[key]; !ok {
+			delete(values, key)
+			continue
+		}
+		propagated[key] = lastValue
+	}
+
+	for key, desiredValue := range desired {
+		_, owned := propagated[key]
+		if value, ok := values[key]; ok && !owned && value != desiredValue {
+			continue
+		}
+		values[key] = desiredValue
+		propagated[key] = desiredValue
+	}
+	return propagated
+}
+
+// lastPropagatedValues returns the values propagated last, as recorded in the given tracking annotation.
+func lastPropagatedValues(annotations map[string]string, trackingAnnotation string) (map[string]string, error) {
+	values := map[string]string{}
+	data, ok := annotations[trackingAnnotation]
+	if !ok || data == "" {
+		return values, nil
+	}
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the %s annotation", trackingAnnotation)
+	}
+	return values, nil
+}
+
+// setPropagatedValues records the propagated values in the given tracking annotation; the annotation is removed
+// when there are no propagated values.
+func setPropagatedValues(annotations map[string]string, trackingAnnotation string, values map[string]string) error {
+	if len(values) == 0 {
+		delete(annotations, trackingAnnotation)
+		return nil
+	}
+	// NOTE: json.Marshal sorts map keys, so the annotation is stable across reconciles.
+	data, err := json.Marshal(values)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the %s annotation", trackingAnnotation)
+	}
+	annotations[trackingAnnotation] = string(data)
+	return nil
+}
+
+// copyValues returns a copy of a labels or annotations map.
+func copyValues(values map[string]string) map[string]string {
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		out[k] = v
+	}
+	return out
+}
+
+// equalValues returns true if two labels or annotations maps are equal; nil and empty maps are considered equal.
+func equalValues(a, b map[string]string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// metadataPropagationObjectToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to propagate its metadata when objects of a Cluster with spec.metadataPropagation set are created or updated.
+func (r *Reconciler) metadataPropagationObjectToCluster(o client.Object) []ctrl.Request {
+	var clusterName string
+	switch obj := o.(type) {
+	case *clusterv1.Machine:
+		clusterName = obj.Spec.ClusterName
+	case *clusterv1.MachineSet:
+		clusterName = obj.Spec.ClusterName
+	default:
+		panic(fmt.Sprintf("Expected a Machine or MachineSet but got a %T", o))
+	}
+	if clusterName == "" {
+		return nil
+	}
+
+	cluster, err := util.GetClusterByName(context.TODO(), r.Client, o.GetNamespace(), clusterName)
+	if err != nil {
+		return nil
+	}
+	if cluster.Spec.MetadataPropagation == nil {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: util.ObjectKey(cluster),
+	}}
+}
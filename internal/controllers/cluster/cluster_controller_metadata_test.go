@@ -0,0 +1,246 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestSyncPropagatedValues(t *testing.T) {
+	tests := []struct {
+		name             string
+		values           map[string]string
+		desired          map[string]string
+		lastPropagated   map[string]string
+		expectValues     map[string]string
+		expectPropagated map[string]string
+	}{
+		{
+			name:             "propagates new values",
+			values:           map[string]string{"foo": "bar"},
+			desired:          map[string]string{"team": "a"},
+			expectValues:     map[string]string{"foo": "bar", "team": "a"},
+			expectPropagated: map[string]string{"team": "a"},
+		},
+		{
+			name:             "does not override values set explicitly",
+			values:           map[string]string{"team": "b"},
+			desired:          map[string]string{"team": "a"},
+			expectValues:     map[string]string{"team": "b"},
+			expectPropagated: map[string]string{},
+		},
+		{
+			name:             "adopts values already equal to the propagated values",
+			values:           map[string]string{"team": "a"},
+			desired:          map[string]string{"team": "a"},
+			expectValues:     map[string]string{"team": "a"},
+			expectPropagated: map[string]string{"team": "a"},
+		},
+		{
+			name:             "updates values propagated before",
+			values:           map[string]string{"team": "a"},
+			desired:          map[string]string{"team": "b"},
+			lastPropagated:   map[string]string{"team": "a"},
+			expectValues:     map[string]string{"team": "b"},
+			expectPropagated: map[string]string{"team": "b"},
+		},
+		{
+			name:             "removes values not propagated anymore",
+			values:           map[string]string{"team": "a", "foo": "bar"},
+			desired:          map[string]string{},
+			lastPropagated:   map[string]string{"team": "a"},
+			expectValues:     map[string]string{"foo": "bar"},
+			expectPropagated: map[string]string{},
+		},
+		{
+			name:             "leaves alone values propagated before and then changed by someone else",
+			values:           map[string]string{"team": "c"},
+			desired:          map[string]string{"team": "b"},
+			lastPropagated:   map[string]string{"team": "a"},
+			expectValues:     map[string]string{"team": "c"},
+			expectPropagated: map[string]string{},
+		},
+		{
+			name:             "does not remove values propagated before and then changed by someone else",
+			values:           map[string]string{"team": "c"},
+			desired:          map[string]string{},
+			lastPropagated:   map[string]string{"team": "a"},
+			expectValues:     map[string]string{"team": "c"},
+			expectPropagated: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(syncPropagatedValues(tt.values, tt.desired, tt.lastPropagated)).To(Equal(tt.expectPropagated))
+			g.Expect(tt.values).To(Equal(tt.expectValues))
+		})
+	}
+}
+
+func TestPropagateMetadata(t *testing.T) {
+	t.Run("tracks the propagated values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &clusterv1.MachineSet{}
+		changed, err := propagateMetadata(obj, map[string]string{"team": "a"}, map[string]string{"owner": "b"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(changed).To(BeTrue())
+		g.Expect(obj.Labels).To(Equal(map[string]string{"team": "a"}))
+		g.Expect(obj.Annotations).To(Equal(map[string]string{
+			"owner":                              "b",
+			clusterv1.PropagatedLabelsAnnotation: `{"team":"a"}`,
+			clusterv1.PropagatedAnnotationsAnnotation: `{"owner":"b"}`,
+		}))
+
+		changed, err = propagateMetadata(obj, map[string]string{"team": "a"}, map[string]string{"owner": "b"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(changed).To(BeFalse())
+
+		changed, err = propagateMetadata(obj, map[string]string{}, map[string]string{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(changed).To(BeTrue())
+		g.Expect(obj.Labels).To(BeEmpty())
+		g.Expect(obj.Annotations).To(BeEmpty())
+	})
+
+	t.Run("does not change objects when there is nothing to propagate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &clusterv1.MachineSet{}
+		changed, err := propagateMetadata(obj, map[string]string{}, map[string]string{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(changed).To(BeFalse())
+		g.Expect(obj.Labels).To(BeNil())
+		g.Expect(obj.Annotations).To(BeNil())
+	})
+
+	t.Run("fails with an invalid tracking annotation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{clusterv1.PropagatedLabelsAnnotation: "not-json"},
+			},
+		}
+		_, err := propagateMetadata(obj, map[string]string{"team": "a"}, nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestClusterReconcilePhases_reconcileMetadataPropagation(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterLabels := map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-cluster",
+			Namespace:   "test-namespace",
+			Labels:      map[string]string{"team": "a", "not-propagated": "true"},
+			Annotations: map[string]string{"cost-center": "1234"},
+		},
+		Spec: clusterv1.ClusterSpec{
+			MetadataPropagation: &clusterv1.ClusterMetadataPropagation{
+				Labels:      []string{"team"},
+				Annotations: []string{"cost-center"},
+			},
+		},
+	}
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "md", Namespace: "test-namespace", Labels: clusterLabels},
+	}
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ms",
+			Namespace: "test-namespace",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster", "team": "b"},
+		},
+	}
+	bootstrapConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       builder.GenericBootstrapConfigKind,
+			"apiVersion": builder.BootstrapGroupVersion.String(),
+			"metadata": map[string]interface{}{
+				"name":      "bootstrap-config",
+				"namespace": "test-namespace",
+			},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "test-namespace", Labels: clusterLabels},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "test-cluster",
+			Bootstrap: clusterv1.Bootstrap{
+				ConfigRef: &corev1.ObjectReference{
+					APIVersion: builder.BootstrapGroupVersion.String(),
+					Kind:       builder.GenericBootstrapConfigKind,
+					Name:       "bootstrap-config",
+				},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-kubeconfig", Namespace: "test-namespace", Labels: clusterLabels},
+	}
+	otherClusterMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-machine",
+			Namespace: "test-namespace",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "other-cluster"},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithObjects(cluster, md, ms, bootstrapConfig, machine, secret, otherClusterMachine).Build()
+	r := &Reconciler{
+		Client:                    c,
+		UnstructuredCachingClient: c,
+		recorder:                  record.NewFakeRecorder(32),
+	}
+
+	_, err := r.reconcileMetadataPropagation(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	for _, obj := range []client.Object{md, machine, secret} {
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+		g.Expect(obj.GetLabels()).To(HaveKeyWithValue("team", "a"))
+		g.Expect(obj.GetLabels()).ToNot(HaveKey("not-propagated"))
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue("cost-center", "1234"))
+	}
+
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(bootstrapConfig), bootstrapConfig)).To(Succeed())
+	g.Expect(bootstrapConfig.GetLabels()).To(HaveKeyWithValue("team", "a"))
+
+	// The value set explicitly on the MachineSet takes precedence.
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(ms), ms)).To(Succeed())
+	g.Expect(ms.Labels).To(HaveKeyWithValue("team", "b"))
+	g.Expect(ms.Annotations).To(HaveKeyWithValue("cost-center", "1234"))
+
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(otherClusterMachine), otherClusterMachine)).To(Succeed())
+	g.Expect(otherClusterMachine.Labels).ToNot(HaveKey("team"))
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/version"
+)
+
+// maxNodeDriftDetails is the maximum number of drift details reported in the NodeDriftDetected condition message.
+const maxNodeDriftDetails = 10
+
+// reconcileNodeDrift compares the Machines of the cluster with the Nodes of the workload cluster, and sets the
+// NodeDriftDetected condition if Nodes have been changed out-of-band, e.g. Nodes added manually or kubelets
+// upgraded in place.
+func (r *Reconciler) reconcileNodeDrift(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !feature.Gates.Enabled(feature.NodeDriftDetection) || r.Tracker == nil {
+		conditions.Delete(cluster, clusterv1.NodeDriftDetectedCondition)
+		return ctrl.Result{}, nil
+	}
+
+	// Nodes can be compared only after the control plane is initialized.
+	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) || !cluster.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Watch the Nodes of the workload cluster, so drift is detected also for Nodes not linked to any Machine.
+	clusterKey := util.ObjectKey(cluster)
+	if err := r.Tracker.Watch(ctx, remote.WatchInput{
+		Name:    "cluster-watchNodes",
+		Cluster: clusterKey,
+		Watcher: r.controller,
+		Kind:    &corev1.Node{},
+		EventHandler: handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: clusterKey}}
+		}),
+		Predicates: []predicate.Predicate{nodeDriftChanged()},
+	}); err != nil {
+		if errors.Is(err, remote.ErrClusterLocked) {
+			log.V(5).Info("Requeuing because another worker has the lock on the ClusterCacheTracker")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, errors.Wrap(err, "failed to watch Nodes of the workload cluster")
+	}
+
+	remoteClient, err := r.Tracker.GetClient(ctx, clusterKey)
+	if err != nil {
+		if errors.Is(err, remote.ErrClusterLocked) {
+			log.V(5).Info("Requeuing because another worker has the lock on the ClusterCacheTracker")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, errors.Wrap(err, "failed to get client for the workload cluster")
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := remoteClient.List(ctx, nodes); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list Nodes of the workload cluster")
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list Machines")
+	}
+
+	// Nodes of MachinePools are not backed by Machines, so they are considered managed if they are referenced by a MachinePool.
+	poolNodes := sets.Set[string]{}
+	if feature.Gates.Enabled(feature.MachinePool) {
+		machinePools := &expv1.MachinePoolList{}
+		if err := r.Client.List(ctx, machinePools, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to list MachinePools")
+		}
+		for _, mp := range machinePools.Items {
+			for _, ref := range mp.Status.NodeRefs {
+				poolNodes.Insert(ref.Name)
+			}
+		}
+	}
+
+	drift := detectNodeDrift(machines.Items, poolNodes, nodes.Items)
+	if len(drift) == 0 {
+		conditions.Set(cluster, &clusterv1.Condition{
+			Type:   clusterv1.NodeDriftDetectedCondition,
+			Status: corev1.ConditionFalse,
+		})
+		return ctrl.Result{}, nil
+	}
+
+	if len(drift) > maxNodeDriftDetails {
+		drift = append(drift[:maxNodeDriftDetails], fmt.Sprintf("and %d more", len(drift)-maxNodeDriftDetails))
+	}
+	message := strings.Join(drift, "; ")
+	if !conditions.IsTrue(cluster, clusterv1.NodeDriftDetectedCondition) {
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, clusterv1.NodeDriftReason, "Node drift detected: %s", message)
+	}
+	conditions.Set(cluster, &clusterv1.Condition{
+		Type:     clusterv1.NodeDriftDetectedCondition,
+		Status:   corev1.ConditionTrue,
+		Reason:   clusterv1.NodeDriftReason,
+		Severity: clusterv1.ConditionSeverityWarning,
+		Message:  message,
+	})
+	return ctrl.Result{}, nil
+}
+
+// nodeDriftChanged returns a predicate filtering Node updates which can't change the drift detected by detectNodeDrift,
+// e.g. the frequent updates of the Node status reporting heartbeats or the images on the Node.
+func nodeDriftChanged() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			return !equality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels) ||
+				!equality.Semantic.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints) ||
+				oldNode.Spec.ProviderID != newNode.Spec.ProviderID ||
+				oldNode.Status.NodeInfo.KubeletVersion != newNode.Status.NodeInfo.KubeletVersion
+		},
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// detectNodeDrift returns the differences between the Machines of a cluster and the Nodes of the workload cluster:
+//   - Nodes not linked to a Machine, e.g. Nodes added manually; Nodes of MachinePools are ignored.
+//   - Machines linked to a Node which does not exist anymore.
+//   - Nodes with a kubelet version different from the version of their Machine.
+//   - Nodes without the labels managed by Cluster API, i.e. in the node-role.kubernetes.io, node-restriction.kubernetes.io
+//     and node.cluster.x-k8s.io domains, set on their Machine.
+//   - Nodes without the taints set on their Machine.
+//
+// Machines being deleted or not yet linked to a Node are ignored.
+func detectNodeDrift(machines []clusterv1.Machine, poolNodes sets.Set[string], nodes []corev1.Node) []string {
+	nodesByName := map[string]*corev1.Node{}
+	for i := range nodes {
+		nodesByName[nodes[i].Name] = &nodes[i]
+	}
+
+	drift := []string{}
+	managedNodes := sets.Set[string]{}
+	providerIDs := sets.Set[string]{}
+	sort.Slice(machines, func(i, j int) bool { return machines[i].Name < machines[j].Name })
+	for i := range machines {
+		m := &machines[i]
+		if m.Spec.ProviderID != nil {
+			providerIDs.Insert(*m.Spec.ProviderID)
+		}
+		if m.Status.NodeRef == nil {
+			continue
+		}
+		managedNodes.Insert(m.Status.NodeRef.Name)
+		if !m.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		node, ok := nodesByName[m.Status.NodeRef.Name]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("Node %s of Machine %s does not exist", m.Status.NodeRef.Name, m.Name))
+			continue
+		}
+
+		if m.Spec.Version != nil && !sameVersion(*m.Spec.Version, node.Status.NodeInfo.KubeletVersion) {
+			drift = append(drift, fmt.Sprintf("Node %s runs kubelet %s, Machine %s has version %s", node.Name, node.Status.NodeInfo.KubeletVersion, m.Name, *m.Spec.Version))
+		}
+
+		for _, key := range sortedKeys(m.Labels) {
+			if !isManagedNodeLabel(key) {
+				continue
+			}
+			if value, ok := node.Labels[key]; !ok || value != m.Labels[key] {
+				drift = append(drift, fmt.Sprintf("Node %s does not have label %s=%s of Machine %s", node.Name, key, m.Labels[key], m.Name))
+			}
+		}
+
+		for _, taint := range m.Spec.Taints {
+			if !hasTaint(node.Spec.Taints, taint) {
+				drift = append(drift, fmt.Sprintf("Node %s does not have taint %s of Machine %s", node.Name, taint.ToString(), m.Name))
+			}
+		}
+	}
+
+	unmanaged := []string{}
+	for _, node := range nodes {
+		if managedNodes.Has(node.Name) || poolNodes.Has(node.Name) || (node.Spec.ProviderID != "" && providerIDs.Has(node.Spec.ProviderID)) {
+			continue
+		}
+		unmanaged = append(unmanaged, node.Name)
+	}
+	if len(unmanaged) > 0 {
+		sort.Strings(unmanaged)
+		drift = append([]string{fmt.Sprintf("%d Nodes not managed by Machines: %s", len(unmanaged), strings.Join(unmanaged, ", "))}, drift...)
+	}
+	return drift
+}
+
+// sameVersion returns true if two Kubernetes versions have the same major, minor and patch; the versions are
+// compared as strings if they can't be parsed.
+func sameVersion(a, b string) bool {
+	va, errA := version.ParseMajorMinorPatchTolerant(a)
+	vb, errB := version.ParseMajorMinorPatchTolerant(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return va.Major == vb.Major && va.Minor == vb.Minor && va.Patch == vb.Patch
+}
+
+// isManagedNodeLabel returns true for the labels which are propagated from Machines to Nodes by default.
+func isManagedNodeLabel(key string) bool {
+	domain := strings.Split(key, "/")[0]
+	if domain == clusterv1.NodeRoleLabelPrefix {
+		return true
+	}
+	for _, d := range []string{clusterv1.NodeRestrictionLabelDomain, clusterv1.ManagedNodeLabelDomain} {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTaint(taints []corev1.Taint, taint corev1.Taint) bool {
+	for i := range taints {
+		if taints[i].MatchTaint(&taint) && taints[i].Value == taint.Value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
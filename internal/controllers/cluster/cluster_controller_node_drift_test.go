@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestDetectNodeDrift(t *testing.T) {
+	machine := func(name, nodeName string, mutate ...func(*clusterv1.Machine)) clusterv1.Machine {
+		m := clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"node-role.kubernetes.io/worker": "", "app": "foo"},
+			},
+			Spec: clusterv1.MachineSpec{
+				Version: pointer.String("v1.27.3"),
+				Taints:  []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+			},
+		}
+		if nodeName != "" {
+			m.Status.NodeRef = &corev1.ObjectReference{Name: nodeName}
+		}
+		for _, f := range mutate {
+			f(&m)
+		}
+		return m
+	}
+	node := func(name string, mutate ...func(*corev1.Node)) corev1.Node {
+		n := corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"node-role.kubernetes.io/worker": ""},
+			},
+			Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+			},
+			Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.27.3"}},
+		}
+		for _, f := range mutate {
+			f(&n)
+		}
+		return n
+	}
+
+	tests := []struct {
+		name      string
+		machines  []clusterv1.Machine
+		poolNodes sets.Set[string]
+		nodes     []corev1.Node
+		want      []string
+	}{
+		{
+			name:     "no drift",
+			machines: []clusterv1.Machine{machine("m1", "n1"), machine("m2", "n2")},
+			nodes:    []corev1.Node{node("n1"), node("n2")},
+			want:     []string{},
+		},
+		{
+			name:      "Nodes added manually",
+			machines:  []clusterv1.Machine{machine("m1", "n1")},
+			poolNodes: sets.New[string]("pool-n1"),
+			nodes:     []corev1.Node{node("n1"), node("manual-2"), node("manual-1"), node("pool-n1")},
+			want:      []string{"2 Nodes not managed by Machines: manual-1, manual-2"},
+		},
+		{
+			name: "Nodes of Machines without a NodeRef yet are matched by provider ID",
+			machines: []clusterv1.Machine{machine("m1", "", func(m *clusterv1.Machine) {
+				m.Spec.ProviderID = pointer.String("test://n1")
+			})},
+			nodes: []corev1.Node{node("n1", func(n *corev1.Node) {
+				n.Spec.ProviderID = "test://n1"
+			})},
+			want: []string{},
+		},
+		{
+			name:     "Node of a Machine deleted",
+			machines: []clusterv1.Machine{machine("m1", "n1")},
+			nodes:    []corev1.Node{},
+			want:     []string{"Node n1 of Machine m1 does not exist"},
+		},
+		{
+			name:     "kubelet upgraded in place",
+			machines: []clusterv1.Machine{machine("m1", "n1")},
+			nodes: []corev1.Node{node("n1", func(n *corev1.Node) {
+				n.Status.NodeInfo.KubeletVersion = "v1.28.0"
+			})},
+			want: []string{"Node n1 runs kubelet v1.28.0, Machine m1 has version v1.27.3"},
+		},
+		{
+			name: "kubelet version with build metadata",
+			machines: []clusterv1.Machine{machine("m1", "n1", func(m *clusterv1.Machine) {
+				m.Spec.Version = pointer.String("v1.27.3+build.1")
+			})},
+			nodes: []corev1.Node{node("n1")},
+			want:  []string{},
+		},
+		{
+			name:     "managed labels and taints removed",
+			machines: []clusterv1.Machine{machine("m1", "n1")},
+			nodes: []corev1.Node{node("n1", func(n *corev1.Node) {
+				n.Labels = nil
+				n.Spec.Taints = nil
+			})},
+			want: []string{
+				"Node n1 does not have label node-role.kubernetes.io/worker= of Machine m1",
+				"Node n1 does not have taint dedicated=gpu:NoSchedule of Machine m1",
+			},
+		},
+		{
+			name: "Machines being deleted are ignored",
+			machines: []clusterv1.Machine{machine("m1", "n1", func(m *clusterv1.Machine) {
+				m.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			})},
+			nodes: []corev1.Node{},
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(detectNodeDrift(tt.machines, tt.poolNodes, tt.nodes)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNodeDriftChanged(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"node-role.kubernetes.io/worker": ""}},
+		Spec:       corev1.NodeSpec{ProviderID: "test://node-1"},
+		Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.27.3"}},
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(n *corev1.Node)
+		expect bool
+	}{
+		{
+			name: "status heartbeat",
+			mutate: func(n *corev1.Node) {
+				n.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, LastHeartbeatTime: metav1.Now()}}
+			},
+			expect: false,
+		},
+		{
+			name:   "labels changed",
+			mutate: func(n *corev1.Node) { n.Labels = map[string]string{} },
+			expect: true,
+		},
+		{
+			name: "taints changed",
+			mutate: func(n *corev1.Node) {
+				n.Spec.Taints = []corev1.Taint{{Key: "foo", Effect: corev1.TaintEffectNoSchedule}}
+			},
+			expect: true,
+		},
+		{
+			name:   "kubelet version changed",
+			mutate: func(n *corev1.Node) { n.Status.NodeInfo.KubeletVersion = "v1.27.4" },
+			expect: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			newNode := node.DeepCopy()
+			tt.mutate(newNode)
+			g.Expect(nodeDriftChanged().Update(event.UpdateEvent{ObjectOld: node, ObjectNew: newNode})).To(Equal(tt.expect))
+		})
+	}
+
+	g := NewWithT(t)
+	g.Expect(nodeDriftChanged().Create(event.CreateEvent{Object: node})).To(BeTrue())
+	g.Expect(nodeDriftChanged().Delete(event.DeleteEvent{Object: node})).To(BeTrue())
+}
@@ -24,17 +24,22 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/contract"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/patch"
@@ -146,6 +151,23 @@ func (r *Reconciler) reconcileExternal(ctx context.Context, cluster *clusterv1.C
 	return external.ReconcileOutput{Result: obj}, nil
 }
 
+// reconcileInfrastructureDeleted reports that the Spec.InfrastructureRef object of a provisioned Cluster has been deleted
+// while the Cluster still exists.
+func (r *Reconciler) reconcileInfrastructureDeleted(ctx context.Context, cluster *clusterv1.Cluster) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition) == clusterv1.InfrastructureDeletedReason {
+		return
+	}
+
+	ref := cluster.Spec.InfrastructureRef
+	log.Info("Infrastructure object has been deleted while the Cluster still exists", "refGroupVersionKind", ref.GroupVersionKind(), "refName", ref.Name)
+	conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureDeletedReason, clusterv1.ConditionSeverityError,
+		"%s %s has been deleted while the Cluster still exists; restore it or delete the Cluster", ref.Kind, ref.Name)
+	r.recorder.Eventf(cluster, corev1.EventTypeWarning, "InfrastructureDeleted",
+		"%s %s has been deleted while the Cluster still exists; restore it or delete the Cluster", ref.Kind, ref.Name)
+}
+
 // reconcileInfrastructure reconciles the Spec.InfrastructureRef object on a Cluster.
 func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -160,7 +182,15 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 		return ctrl.Result{}, err
 	}
 	// Return early if we need to requeue.
+	// NOTE: reconcileExternal requeues only if the infrastructure object is not found.
 	if infraReconcileResult.RequeueAfter > 0 {
+		// If the Cluster infrastructure was already provisioned, the infrastructure object has been deleted underneath the Cluster;
+		// this is not going to be fixed by retrying, so surface it and wait for users to restore the infrastructure object
+		// or to delete the Cluster.
+		if cluster.Status.InfrastructureReady {
+			r.reconcileInfrastructureDeleted(ctx, cluster)
+			return ctrl.Result{}, nil
+		}
 		return ctrl.Result{RequeueAfter: infraReconcileResult.RequeueAfter}, nil
 	}
 	// If the external object is paused, return without any further processing.
@@ -197,8 +227,9 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 		return ctrl.Result{}, nil
 	}
 
-	// Get and parse Spec.ControlPlaneEndpoint field from the infrastructure provider.
-	if !cluster.Spec.ControlPlaneEndpoint.IsValid() {
+	// Get and parse Spec.ControlPlaneEndpoint field from the infrastructure provider, unless
+	// it is sourced from the object referenced by Spec.ControlPlaneEndpointRef.
+	if !cluster.Spec.ControlPlaneEndpoint.IsValid() && cluster.Spec.ControlPlaneEndpointRef == nil {
 		if err := util.UnstructuredUnmarshalField(infraConfig, &cluster.Spec.ControlPlaneEndpoint, "spec", "controlPlaneEndpoint"); err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve Spec.ControlPlaneEndpoint from infrastructure provider for Cluster %q in namespace %q",
 				cluster.Name, cluster.Namespace)
@@ -307,3 +338,112 @@ func (r *Reconciler) reconcileKubeconfig(ctx context.Context, cluster *clusterv1
 
 	return ctrl.Result{}, nil
 }
+
+// reconcileSummary aggregates the replica counts of the control plane, MachineDeployments and MachinePools belonging to the
+// cluster, together with the results of the MachineHealthChecks targeting it, into status.summary and the
+// WorkersReady and MachinesHealthy conditions.
+func (r *Reconciler) reconcileSummary(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	summary := &clusterv1.ClusterSummary{}
+
+	if cluster.Spec.ControlPlaneRef != nil {
+		controlPlane, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		switch {
+		case apierrors.IsNotFound(errors.Cause(err)):
+			// The control plane has not been created yet, or it has been deleted; nothing to summarize.
+		case err != nil:
+			return ctrl.Result{}, err
+		default:
+			summary.ControlPlane = summarizeControlPlane(controlPlane)
+		}
+	}
+
+	mdList := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, mdList, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list MachineDeployments for cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	hasWorkers := len(mdList.Items) > 0
+	for _, md := range mdList.Items {
+		summary.Workers.Replicas += pointer.Int32Deref(md.Spec.Replicas, 0)
+		summary.Workers.ReadyReplicas += md.Status.ReadyReplicas
+		summary.Workers.UnavailableReplicas += md.Status.UnavailableReplicas
+	}
+
+	if feature.Gates.Enabled(feature.MachinePool) {
+		mpList := &expv1.MachinePoolList{}
+		if err := r.Client.List(ctx, mpList, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to list MachinePools for cluster %s/%s", cluster.Namespace, cluster.Name)
+		}
+		hasWorkers = hasWorkers || len(mpList.Items) > 0
+		for _, mp := range mpList.Items {
+			summary.Workers.Replicas += pointer.Int32Deref(mp.Spec.Replicas, 0)
+			summary.Workers.ReadyReplicas += mp.Status.ReadyReplicas
+			summary.Workers.UnavailableReplicas += mp.Status.UnavailableReplicas
+		}
+	}
+
+	mhcList := &clusterv1.MachineHealthCheckList{}
+	if err := r.Client.List(ctx, mhcList, client.InNamespace(cluster.Namespace)); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list MachineHealthChecks for cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	hasHealthChecks := false
+	for _, mhc := range mhcList.Items {
+		if mhc.Spec.ClusterName == cluster.Name {
+			hasHealthChecks = true
+			break
+		}
+	}
+	if hasHealthChecks {
+		// Count the Machines MachineHealthChecks reported as unhealthy, instead of using the MachineHealthCheck status,
+		// given that the Machines not yet reported as healthy include the Machines still within the node startup timeout.
+		machineList := &clusterv1.MachineList{}
+		if err := r.Client.List(ctx, machineList, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to list Machines for cluster %s/%s", cluster.Namespace, cluster.Name)
+		}
+		for i := range machineList.Items {
+			if conditions.IsFalse(&machineList.Items[i], clusterv1.MachineHealthCheckSucceededCondition) {
+				summary.UnhealthyMachines++
+			}
+		}
+	}
+
+	cluster.Status.Summary = summary
+
+	switch {
+	case !hasWorkers:
+		conditions.Delete(cluster, clusterv1.WorkersReadyCondition)
+	case summary.Workers.ReadyReplicas < summary.Workers.Replicas:
+		conditions.MarkFalse(cluster, clusterv1.WorkersReadyCondition, clusterv1.WaitingForWorkersReason, clusterv1.ConditionSeverityInfo,
+			"%d of %d worker replicas are ready", summary.Workers.ReadyReplicas, summary.Workers.Replicas)
+	default:
+		conditions.MarkTrue(cluster, clusterv1.WorkersReadyCondition)
+	}
+
+	switch {
+	case !hasHealthChecks:
+		conditions.Delete(cluster, clusterv1.MachinesHealthyCondition)
+	case summary.UnhealthyMachines > 0:
+		conditions.MarkFalse(cluster, clusterv1.MachinesHealthyCondition, clusterv1.UnhealthyMachinesReason, clusterv1.ConditionSeverityWarning,
+			"%d Machines are failing their MachineHealthChecks", summary.UnhealthyMachines)
+	default:
+		conditions.MarkTrue(cluster, clusterv1.MachinesHealthyCondition)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// summarizeControlPlane returns the replica counts of a control plane object, or nil if the control plane
+// provider does not implement replicas.
+func summarizeControlPlane(controlPlane *unstructured.Unstructured) *clusterv1.ReplicasSummary {
+	replicas, err := contract.ControlPlane().Replicas().Get(controlPlane)
+	if err != nil {
+		return nil
+	}
+	summary := &clusterv1.ReplicasSummary{Replicas: int32(*replicas)}
+	if readyReplicas, err := contract.ControlPlane().ReadyReplicas().Get(controlPlane); err == nil {
+		summary.ReadyReplicas = int32(*readyReplicas)
+	}
+	if unavailableReplicas, err := contract.ControlPlane().UnavailableReplicas().Get(controlPlane); err == nil {
+		summary.UnavailableReplicas = int32(*unavailableReplicas)
+	}
+	return summary
+}
@@ -25,13 +25,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestClusterReconcilePhases(t *testing.T) {
@@ -57,12 +60,16 @@ func TestClusterReconcilePhases(t *testing.T) {
 			},
 		}
 
+		clusterNotProvisioned := cluster.DeepCopy()
+		clusterNotProvisioned.Status.InfrastructureReady = false
+
 		tests := []struct {
-			name         string
-			cluster      *clusterv1.Cluster
-			infraRef     map[string]interface{}
-			expectErr    bool
-			expectResult ctrl.Result
+			name            string
+			cluster         *clusterv1.Cluster
+			infraRef        map[string]interface{}
+			expectErr       bool
+			expectResult    ctrl.Result
+			expectCondition *clusterv1.Condition
 		}{
 			{
 				name:      "returns no error if infrastructure ref is nil",
@@ -71,10 +78,17 @@ func TestClusterReconcilePhases(t *testing.T) {
 			},
 			{
 				name:         "returns error if unable to reconcile infrastructure ref",
-				cluster:      cluster,
+				cluster:      clusterNotProvisioned,
 				expectErr:    false,
 				expectResult: ctrl.Result{RequeueAfter: 30 * time.Second},
 			},
+			{
+				name:            "reports infrastructure deleted if the infrastructure ref of a provisioned cluster is not found",
+				cluster:         cluster.DeepCopy(),
+				expectErr:       false,
+				expectResult:    ctrl.Result{},
+				expectCondition: conditions.FalseCondition(clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureDeletedReason, clusterv1.ConditionSeverityError, "GenericInfrastructureMachine test has been deleted while the Cluster still exists; restore it or delete the Cluster"),
+			},
 			{
 				name:    "returns no error if infra config is marked for deletion",
 				cluster: cluster,
@@ -148,6 +162,9 @@ func TestClusterReconcilePhases(t *testing.T) {
 				} else {
 					g.Expect(err).NotTo(HaveOccurred())
 				}
+				if tt.expectCondition != nil {
+					g.Expect(*conditions.Get(tt.cluster, tt.expectCondition.Type)).To(conditions.MatchCondition(*tt.expectCondition))
+				}
 			})
 		}
 	})
@@ -524,3 +541,178 @@ func generateInfraRef(withFailureDomain bool) map[string]interface{} {
 
 	return infraRef
 }
+
+func TestClusterReconcilePhases_reconcileSummary(t *testing.T) {
+	controlPlane := builder.ControlPlane("test-namespace", "test-control-plane").
+		WithReplicas(3).
+		WithStatusFields(map[string]interface{}{
+			"status.readyReplicas":       int64(2),
+			"status.unavailableReplicas": int64(1),
+		}).
+		Build()
+	controlPlaneRef := &corev1.ObjectReference{
+		APIVersion: builder.ControlPlaneGroupVersion.String(),
+		Kind:       builder.GenericControlPlaneKind,
+		Namespace:  "test-namespace",
+		Name:       "test-control-plane",
+	}
+
+	machineDeployment := func(name string, replicas, readyReplicas, unavailableReplicas int32) *clusterv1.MachineDeployment {
+		return &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test-namespace",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				ClusterName: "test-cluster",
+				Replicas:    pointer.Int32(replicas),
+			},
+			Status: clusterv1.MachineDeploymentStatus{
+				ReadyReplicas:       readyReplicas,
+				UnavailableReplicas: unavailableReplicas,
+			},
+		}
+	}
+	machineHealthCheck := func(name, clusterName string) *clusterv1.MachineHealthCheck {
+		return &clusterv1.MachineHealthCheck{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test-namespace",
+			},
+			Spec: clusterv1.MachineHealthCheckSpec{
+				ClusterName: clusterName,
+			},
+		}
+	}
+	// machine returns a Machine with the MachineHealthCheckSucceeded condition set to the given status;
+	// the condition is not set if the status is empty, e.g. for Machines within the node startup timeout.
+	machine := func(name, clusterName string, healthCheckSucceeded corev1.ConditionStatus) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test-namespace",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName: clusterName,
+			},
+		}
+		switch healthCheckSucceeded {
+		case corev1.ConditionTrue:
+			conditions.MarkTrue(m, clusterv1.MachineHealthCheckSucceededCondition)
+		case corev1.ConditionFalse:
+			conditions.MarkFalse(m, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.NodeNotFoundReason, clusterv1.ConditionSeverityWarning, "")
+		}
+		return m
+	}
+
+	tests := []struct {
+		name                  string
+		controlPlaneRef       *corev1.ObjectReference
+		objs                  []client.Object
+		expectSummary         *clusterv1.ClusterSummary
+		expectWorkersReady    *clusterv1.Condition
+		expectMachinesHealthy *clusterv1.Condition
+	}{
+		{
+			name:          "cluster without descendants has an empty summary and no roll-up conditions",
+			expectSummary: &clusterv1.ClusterSummary{},
+		},
+		{
+			name:            "control plane that does not exist yet is not summarized",
+			controlPlaneRef: controlPlaneRef,
+			expectSummary:   &clusterv1.ClusterSummary{},
+		},
+		{
+			name:            "cluster with workers not ready and unhealthy machines",
+			controlPlaneRef: controlPlaneRef,
+			objs: []client.Object{
+				controlPlane,
+				machineDeployment("md-1", 3, 3, 0),
+				machineDeployment("md-2", 2, 1, 1),
+				machineHealthCheck("mhc-1", "test-cluster"),
+				machineHealthCheck("mhc-other-cluster", "other-cluster"),
+				machine("m-healthy", "test-cluster", corev1.ConditionTrue),
+				machine("m-unhealthy", "test-cluster", corev1.ConditionFalse),
+				machine("m-starting", "test-cluster", ""),
+				machine("m-other-cluster", "other-cluster", corev1.ConditionFalse),
+			},
+			expectSummary: &clusterv1.ClusterSummary{
+				ControlPlane:      &clusterv1.ReplicasSummary{Replicas: 3, ReadyReplicas: 2, UnavailableReplicas: 1},
+				Workers:           clusterv1.ReplicasSummary{Replicas: 5, ReadyReplicas: 4, UnavailableReplicas: 1},
+				UnhealthyMachines: 1,
+			},
+			expectWorkersReady:    conditions.FalseCondition(clusterv1.WorkersReadyCondition, clusterv1.WaitingForWorkersReason, clusterv1.ConditionSeverityInfo, "4 of 5 worker replicas are ready"),
+			expectMachinesHealthy: conditions.FalseCondition(clusterv1.MachinesHealthyCondition, clusterv1.UnhealthyMachinesReason, clusterv1.ConditionSeverityWarning, "1 Machines are failing their MachineHealthChecks"),
+		},
+		{
+			name: "cluster with all workers ready and healthy machines",
+			objs: []client.Object{
+				machineDeployment("md-1", 3, 3, 0),
+				machineHealthCheck("mhc-1", "test-cluster"),
+				machine("m-healthy", "test-cluster", corev1.ConditionTrue),
+				machine("m-starting", "test-cluster", ""),
+			},
+			expectSummary: &clusterv1.ClusterSummary{
+				Workers: clusterv1.ReplicasSummary{Replicas: 3, ReadyReplicas: 3},
+			},
+			expectWorkersReady:    conditions.TrueCondition(clusterv1.WorkersReadyCondition),
+			expectMachinesHealthy: conditions.TrueCondition(clusterv1.MachinesHealthyCondition),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef: tt.controlPlaneRef,
+				},
+			}
+			r := &Reconciler{
+				Client:   fake.NewClientBuilder().WithObjects(append(tt.objs, cluster)...).Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			_, err := r.reconcileSummary(ctx, cluster)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cluster.Status.Summary).To(Equal(tt.expectSummary))
+
+			if tt.expectWorkersReady == nil {
+				g.Expect(conditions.Has(cluster, clusterv1.WorkersReadyCondition)).To(BeFalse())
+			} else {
+				g.Expect(*conditions.Get(cluster, clusterv1.WorkersReadyCondition)).To(conditions.MatchCondition(*tt.expectWorkersReady))
+			}
+			if tt.expectMachinesHealthy == nil {
+				g.Expect(conditions.Has(cluster, clusterv1.MachinesHealthyCondition)).To(BeFalse())
+			} else {
+				g.Expect(*conditions.Get(cluster, clusterv1.MachinesHealthyCondition)).To(conditions.MatchCondition(*tt.expectMachinesHealthy))
+			}
+		})
+	}
+}
+
+func TestMachineHealthCheckSucceededChanged(t *testing.T) {
+	healthy := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m", Namespace: "test-namespace"}}
+	conditions.MarkTrue(healthy, clusterv1.MachineHealthCheckSucceededCondition)
+	unhealthy := healthy.DeepCopy()
+	conditions.MarkFalse(unhealthy, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.NodeNotFoundReason, clusterv1.ConditionSeverityWarning, "")
+	relabeled := healthy.DeepCopy()
+	relabeled.Labels = map[string]string{"foo": "bar"}
+	starting := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m", Namespace: "test-namespace"}}
+
+	g := NewWithT(t)
+	g.Expect(machineHealthCheckSucceededChanged().Update(event.UpdateEvent{ObjectOld: healthy, ObjectNew: unhealthy})).To(BeTrue())
+	g.Expect(machineHealthCheckSucceededChanged().Update(event.UpdateEvent{ObjectOld: unhealthy, ObjectNew: healthy})).To(BeTrue())
+	g.Expect(machineHealthCheckSucceededChanged().Update(event.UpdateEvent{ObjectOld: starting, ObjectNew: unhealthy})).To(BeTrue())
+	g.Expect(machineHealthCheckSucceededChanged().Update(event.UpdateEvent{ObjectOld: starting, ObjectNew: healthy})).To(BeFalse())
+	g.Expect(machineHealthCheckSucceededChanged().Update(event.UpdateEvent{ObjectOld: healthy, ObjectNew: relabeled})).To(BeFalse())
+	g.Expect(machineHealthCheckSucceededChanged().Create(event.CreateEvent{Object: healthy})).To(BeTrue())
+	g.Expect(machineHealthCheckSucceededChanged().Delete(event.DeleteEvent{Object: unhealthy})).To(BeTrue())
+}
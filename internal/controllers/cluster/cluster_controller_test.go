@@ -732,6 +732,20 @@ func TestFilterOwnedDescendants(t *testing.T) {
 		&ms4OwnedByCluster,
 		&m2OwnedByCluster,
 		&m5OwnedByCluster,
+	}
+
+	g.Expect(actual).To(Equal(expected))
+
+	// Control plane machines are returned only after all the workers are gone.
+	d.machineDeployments.Items = nil
+	d.machineSets.Items = nil
+	d.workerMachines.Items = nil
+	d.machinePools.Items = nil
+
+	actual, err = d.filterOwnedDescendants(&c)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	expected = []client.Object{
 		&m3ControlPlaneOwnedByCluster,
 		&m6ControlPlaneOwnedByCluster,
 	}
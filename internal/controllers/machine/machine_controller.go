@@ -39,6 +39,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -47,6 +48,9 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/internal/profiling"
+	runtimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
+	"sigs.k8s.io/cluster-api/internal/util/deletion"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -54,6 +58,7 @@ import (
 	"sigs.k8s.io/cluster-api/util/conditions"
 	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/paused"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
 
@@ -72,6 +77,7 @@ var (
 
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;create;delete
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status;machines/finalizers,verbs=get;list;watch;create;update;patch;delete
@@ -86,6 +92,18 @@ type Reconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// AdditionalSyncMachineLabelDomains is a list of additional label domains which are synced from
+	// Machines to Nodes, on top of the node-role.kubernetes.io, node-restriction.kubernetes.io and
+	// node.cluster.x-k8s.io ones.
+	AdditionalSyncMachineLabelDomains []string
+
+	// NodeSnapshotTTL is the duration for which the snapshot of the Node of a Machine, captured before deletion,
+	// is retained; snapshots are not captured if it is zero.
+	NodeSnapshotTTL time.Duration
+
+	// RuntimeClient is a client for calling runtime extensions.
+	RuntimeClient runtimeclient.Client
+
 	controller      controller.Controller
 	recorder        record.EventRecorder
 	externalTracker external.ObjectTracker
@@ -118,12 +136,13 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 				predicates.All(ctrl.LoggerFrom(ctx),
 					predicates.Any(ctrl.LoggerFrom(ctx),
 						predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+						predicates.ClusterPauseChanged(ctrl.LoggerFrom(ctx)),
 						predicates.ClusterControlPlaneInitialized(ctrl.LoggerFrom(ctx)),
 					),
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			)).
-		Build(r)
+		Build(runtimeclient.RequeueRetryableCallErrors(r))
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
@@ -135,12 +154,31 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		Controller: c,
 	}
 	r.ssaCache = ssa.NewCache()
+
+	if r.NodeSnapshotTTL > 0 {
+		err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			wait.UntilWithContext(ctx, func(ctx context.Context) {
+				if err := r.deleteExpiredNodeSnapshots(ctx); err != nil {
+					ctrl.LoggerFrom(ctx).Error(err, "Failed to garbage collect expired Node snapshots")
+				}
+			}, nodeSnapshotGCInterval)
+			return nil
+		}))
+		if err != nil {
+			return errors.Wrap(err, "failed setting up Node snapshots garbage collection")
+		}
+	}
 	return nil
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, done := profiling.StartReconcile(ctx, "machine")
+	defer done()
+
 	// Fetch the Machine instance
 	m := &clusterv1.Machine{}
+	fetchDone := profiling.StartPhase(ctx, profiling.PhaseFetch)
+	defer fetchDone()
 	if err := r.Client.Get(ctx, req.NamespacedName, m); err != nil {
 		if apierrors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
@@ -162,16 +200,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	log = log.WithValues("Cluster", klog.KRef(m.ObjectMeta.Namespace, m.Spec.ClusterName))
 	ctx = ctrl.LoggerInto(ctx, log)
 
+	profiling.SetCluster(ctx, types.NamespacedName{Namespace: m.Namespace, Name: m.Spec.ClusterName})
 	cluster, err := util.GetClusterByName(ctx, r.Client, m.ObjectMeta.Namespace, m.Spec.ClusterName)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to get cluster %q for machine %q in namespace %q",
 			m.Spec.ClusterName, m.Name, m.Namespace)
 	}
+	fetchDone()
 
 	// Return early if the object or Cluster is paused.
-	if annotations.IsPaused(cluster, m) {
+	isPaused, requeueAfter, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, m)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if isPaused {
 		log.Info("Reconciliation is paused for this object")
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	// Initialize the patch helper
@@ -185,6 +229,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 		// Always attempt to patch the object and status after each reconciliation.
 		// Patch ObservedGeneration only if the reconciliation completed successfully
+		defer profiling.StartPhase(ctx, profiling.PhasePatch)()
 		patchOpts := []patch.Option{}
 		if reterr == nil {
 			patchOpts = append(patchOpts, patch.WithStatusObservedGeneration{})
@@ -237,6 +282,7 @@ func patchMachine(ctx context.Context, patchHelper *patch.Helper, machine *clust
 		conditions.WithConditions(
 			// Infrastructure problems should take precedence over all the other conditions
 			clusterv1.InfrastructureReadyCondition,
+			clusterv1.AuxiliaryInfrastructureReadyCondition,
 			// Bootstrap comes after, but it is relevant only during initial machine provisioning.
 			clusterv1.BootstrapReadyCondition,
 			// MHC reported condition should take precedence over the remediation progress
@@ -258,6 +304,8 @@ func patchMachine(ctx context.Context, patchHelper *patch.Helper, machine *clust
 			clusterv1.ReadyCondition,
 			clusterv1.BootstrapReadyCondition,
 			clusterv1.InfrastructureReadyCondition,
+			clusterv1.AuxiliaryInfrastructureReadyCondition,
+			clusterv1.ClusterInfrastructureAvailableCondition,
 			clusterv1.DrainingSucceededCondition,
 			clusterv1.MachineHealthCheckSucceededCondition,
 			clusterv1.MachineOwnerRemediatedCondition,
@@ -279,12 +327,23 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 		}))
 	}
 
+	// Surface on the Machine if the infrastructure object of the Cluster has been deleted.
+	reconcileClusterInfrastructureAvailable(cluster, m)
+
+	// Call the BeforeMachineCreate hook before provisioning the Machine; provisioning does not start
+	// until all the registered extensions return a non-blocking response.
+	if res, err := r.callBeforeMachineCreate(ctx, cluster, m); err != nil || !res.IsZero() {
+		return res, err
+	}
+
 	phases := []func(context.Context, *clusterv1.Cluster, *clusterv1.Machine) (ctrl.Result, error){
 		r.reconcileBootstrap,
 		r.reconcileInfrastructure,
+		r.reconcileAuxiliaryInfrastructure,
 		r.reconcileNode,
 		r.reconcileInterruptibleNodeLabel,
 		r.reconcileCertificateExpiry,
+		r.reconcileKubeletServingCertificates,
 	}
 
 	res := ctrl.Result{}
@@ -300,12 +359,40 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 		}
 		res = util.LowestNonZeroResult(res, phaseResult)
 	}
-	return res, kerrors.NewAggregate(errs)
+	if len(errs) > 0 {
+		return res, kerrors.NewAggregate(errs)
+	}
+
+	// Call the AfterMachineJoin hook once the Node of the Machine is ready.
+	if err := r.callAfterMachineJoin(ctx, cluster, m); err != nil {
+		return res, err
+	}
+	return res, nil
 }
 
 func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) { //nolint:gocyclo
 	log := ctrl.LoggerFrom(ctx)
 
+	// Record why the Machine is being deleted, if the initiating controller did not already do it;
+	// in this case the Machine was deleted either directly by a user or as a consequence of the Cluster being deleted.
+	if reason, _ := deletion.Get(m); reason == "" {
+		reason, initiator := clusterv1.DeletionReasonUser, ""
+		if !cluster.DeletionTimestamp.IsZero() {
+			reason, initiator = clusterv1.DeletionReasonClusterDeletion, deletion.Initiator("Cluster", cluster)
+		}
+		deletion.Set(m, reason, initiator)
+		r.recorder.Eventf(m, corev1.EventTypeNormal, "DeletionRequested", "Deletion requested, reason: %s", reason)
+	}
+
+	// Call the BeforeMachineDelete hook; the Node is not drained and the Machine is not deleted
+	// until all the registered extensions return a non-blocking response.
+	if res, err := r.callBeforeMachineDelete(ctx, cluster, m); err != nil || !res.IsZero() {
+		return res, err
+	}
+
+	// Capture the last-known state of the Node before it is drained and deleted.
+	r.reconcileNodeSnapshot(ctx, cluster, m)
+
 	err := r.isDeleteNodeAllowed(ctx, cluster, m)
 	isDeleteNodeAllowed := err == nil
 	if err != nil {
@@ -349,7 +436,7 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 				return ctrl.Result{}, errors.Wrap(err, "failed to patch Machine")
 			}
 
-			if result, err := r.drainNode(ctx, cluster, m.Status.NodeRef.Name); !result.IsZero() || err != nil {
+			if result, err := r.drainNode(ctx, cluster, m); !result.IsZero() || err != nil {
 				if err != nil {
 					conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 					r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedDrainNode", "error draining Machine's node %q: %v", m.Status.NodeRef.Name, err)
@@ -357,6 +444,7 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 				return result, err
 			}
 
+			observeConditionDuration(drainDuration, m, clusterv1.DrainingSucceededCondition)
 			conditions.MarkTrue(m, clusterv1.DrainingSucceededCondition)
 			r.recorder.Eventf(m, corev1.EventTypeNormal, "SuccessfulDrainNode", "success draining Machine's node %q", m.Status.NodeRef.Name)
 		}
@@ -379,6 +467,7 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 				log.Info("Waiting for node volumes to be detached", "Node", klog.KRef("", m.Status.NodeRef.Name))
 				return ctrl.Result{}, nil
 			}
+			observeConditionDuration(volumeDetachDuration, m, clusterv1.VolumeDetachSucceededCondition)
 			conditions.MarkTrue(m, clusterv1.VolumeDetachSucceededCondition)
 			r.recorder.Eventf(m, corev1.EventTypeNormal, "NodeVolumesDetached", "success waiting for node volumes detaching Machine's node %q", m.Status.NodeRef.Name)
 		}
@@ -414,6 +503,16 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 		return ctrl.Result{}, nil
 	}
 
+	// Auxiliary infrastructure objects are deleted only after the infrastructure is gone, given that the
+	// infrastructure provider might depend on them while deprovisioning, e.g. to power off a host using its BMC.
+	auxiliaryInfrastructureDeleted, err := r.reconcileDeleteAuxiliaryInfrastructure(ctx, m)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !auxiliaryInfrastructureDeleted {
+		return ctrl.Result{}, nil
+	}
+
 	bootstrapDeleted, err := r.reconcileDeleteBootstrap(ctx, m)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -448,6 +547,7 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 		}
 	}
 
+	observeDeletionDuration(m)
 	controllerutil.RemoveFinalizer(m, clusterv1.MachineFinalizer)
 	return ctrl.Result{}, nil
 }
@@ -577,7 +677,8 @@ func (r *Reconciler) isDeleteNodeAllowed(ctx context.Context, cluster *clusterv1
 	return nil
 }
 
-func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, nodeName string) (ctrl.Result, error) {
+func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) (ctrl.Result, error) {
+	nodeName := machine.Status.NodeRef.Name
 	log := ctrl.LoggerFrom(ctx, "Node", klog.KRef("", nodeName))
 
 	restConfig, err := remote.RESTConfig(ctx, controllerName, r.Client, util.ObjectKey(cluster))
@@ -601,28 +702,9 @@ func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster,
 		return ctrl.Result{}, errors.Wrapf(err, "unable to get node %v", nodeName)
 	}
 
-	drainer := &kubedrain.Helper{
-		Client:              kubeClient,
-		Ctx:                 ctx,
-		Force:               true,
-		IgnoreAllDaemonSets: true,
-		DeleteEmptyDirData:  true,
-		GracePeriodSeconds:  -1,
-		// If a pod is not evicted in 20 seconds, retry the eviction next time the
-		// machine gets reconciled again (to allow other machines to be reconciled).
-		Timeout: 20 * time.Second,
-		OnPodDeletedOrEvicted: func(pod *corev1.Pod, usingEviction bool) {
-			verbStr := "Deleted"
-			if usingEviction {
-				verbStr = "Evicted"
-			}
-			log.Info(fmt.Sprintf("%s pod from Node", verbStr),
-				"Pod", klog.KObj(pod))
-		},
-		Out: writer{log.Info},
-		ErrOut: writer{func(msg string, keysAndValues ...interface{}) {
-			log.Error(nil, msg, keysAndValues...)
-		}},
+	drainer, err := newNodeDrainer(ctx, kubeClient, machine)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
 	if noderefutil.IsNodeUnreachable(node) {
@@ -639,6 +721,8 @@ func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster,
 	if err := kubedrain.RunNodeDrain(drainer, node.Name); err != nil {
 		// Machine will be re-reconciled after a drain failure.
 		log.Error(err, "Drain failed, retry in 20s")
+		conditions.MarkFalse(machine, clusterv1.DrainingSucceededCondition, clusterv1.DrainingFailedReason, clusterv1.ConditionSeverityWarning,
+			drainFailureMessage(drainer, node.Name, err))
 		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
 	}
 
@@ -713,6 +797,11 @@ func (r *Reconciler) reconcileDeleteBootstrap(ctx context.Context, m *clusterv1.
 }
 
 func (r *Reconciler) reconcileDeleteInfrastructure(ctx context.Context, m *clusterv1.Machine) (bool, error) {
+	// If the infrastructure has to be reused, mark it to be released before deleting it.
+	if err := r.reconcileReleaseInfrastructure(ctx, m); err != nil {
+		return false, err
+	}
+
 	obj, err := r.reconcileDeleteExternal(ctx, m, &m.Spec.InfrastructureRef)
 	if err != nil {
 		return false, err
@@ -746,6 +835,18 @@ func (r *Reconciler) reconcileDeleteExternal(ctx context.Context, m *clusterv1.M
 	}
 
 	if obj != nil {
+		// Propagate the reason why the Machine is being deleted to the external object, so it can be surfaced by providers.
+		if reason, initiator := deletion.Get(m); reason != "" {
+			original := obj.DeepCopy()
+			if deletion.Set(obj, reason, initiator) {
+				if err := r.Client.Patch(ctx, obj, client.MergeFrom(original)); err != nil && !apierrors.IsNotFound(err) {
+					return obj, errors.Wrapf(err,
+						"failed to set deletion reason on %v %q for Machine %q in namespace %q",
+						obj.GroupVersionKind(), obj.GetName(), m.Name, m.Namespace)
+				}
+			}
+		}
+
 		// Issue a delete request.
 		if err := r.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
 			return obj, errors.Wrapf(err,
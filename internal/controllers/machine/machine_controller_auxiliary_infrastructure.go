@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileAuxiliaryInfrastructure reconciles the Spec.AuxiliaryInfrastructureRefs objects on a Machine, and reports
+// if all of them are ready with the AuxiliaryInfrastructureReady condition.
+// NOTE: Auxiliary infrastructure objects are reconciled like the InfrastructureRef, i.e. they are owned by the Machine
+// and get the Cluster label, but they are not expected to provide the providerID, addresses or failure domain.
+func (r *Reconciler) reconcileAuxiliaryInfrastructure(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if len(m.Spec.AuxiliaryInfrastructureRefs) == 0 {
+		conditions.Delete(m, clusterv1.AuxiliaryInfrastructureReadyCondition)
+		return ctrl.Result{}, nil
+	}
+
+	notReady := []string{}
+	for i := range m.Spec.AuxiliaryInfrastructureRefs {
+		ref := &m.Spec.AuxiliaryInfrastructureRefs[i]
+
+		// Call generic external reconciler.
+		reconcileResult, err := r.reconcileExternal(ctx, cluster, m, ref)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		// if the external object is paused, return without any further processing
+		if reconcileResult.Paused {
+			return ctrl.Result{}, nil
+		}
+		if reconcileResult.RequeueAfter > 0 {
+			notReady = append(notReady, fmt.Sprintf("%s %s (not found)", ref.Kind, ref.Name))
+			continue
+		}
+		obj := reconcileResult.Result
+
+		ready, err := external.IsReady(obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !ready {
+			notReady = append(notReady, fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName()))
+		}
+	}
+
+	if len(notReady) > 0 {
+		log.Info("Waiting for auxiliary infrastructure to report status.ready", "objects", strings.Join(notReady, ", "))
+		conditions.MarkFalse(m, clusterv1.AuxiliaryInfrastructureReadyCondition, clusterv1.WaitingForAuxiliaryInfrastructureReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for %s to report status.ready", strings.Join(notReady, ", "))
+		return ctrl.Result{RequeueAfter: externalReadyWait}, nil
+	}
+
+	conditions.MarkTrue(m, clusterv1.AuxiliaryInfrastructureReadyCondition)
+	return ctrl.Result{}, nil
+}
+
+// reconcileDeleteAuxiliaryInfrastructure deletes the auxiliary infrastructure objects of a Machine, and returns true
+// once all of them are gone.
+func (r *Reconciler) reconcileDeleteAuxiliaryInfrastructure(ctx context.Context, m *clusterv1.Machine) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	deleted := true
+	for i := range m.Spec.AuxiliaryInfrastructureRefs {
+		ref := &m.Spec.AuxiliaryInfrastructureRefs[i]
+		obj, err := r.reconcileDeleteExternal(ctx, m, ref)
+		if err != nil {
+			return false, err
+		}
+		if obj != nil {
+			log.Info("Waiting for auxiliary infrastructure to be deleted", ref.Kind, klog.KRef(m.Namespace, ref.Name))
+			deleted = false
+		}
+	}
+
+	if len(m.Spec.AuxiliaryInfrastructureRefs) > 0 {
+		reason := clusterv1.DeletingReason
+		if deleted {
+			reason = clusterv1.DeletedReason
+		}
+		conditions.MarkFalse(m, clusterv1.AuxiliaryInfrastructureReadyCondition, reason, clusterv1.ConditionSeverityInfo, "")
+	}
+	return deleted, nil
+}
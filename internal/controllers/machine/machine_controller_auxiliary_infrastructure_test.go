@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileAuxiliaryInfrastructure(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	newAuxiliaryObject := func(name string, ready bool) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": metav1.NamespaceDefault,
+				},
+				"status": map[string]interface{}{
+					"ready": ready,
+				},
+			},
+		}
+	}
+	newMachine := func(refs ...corev1.ObjectReference) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine-test",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName:                 cluster.Name,
+				AuxiliaryInfrastructureRefs: refs,
+			},
+		}
+	}
+	ref := func(name string) corev1.ObjectReference {
+		return corev1.ObjectReference{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+			Kind:       "GenericInfrastructureMachine",
+			Name:       name,
+		}
+	}
+
+	t.Run("removes the condition if there are no auxiliary infrastructure objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := newMachine()
+		conditions.MarkTrue(machine, clusterv1.AuxiliaryInfrastructureReadyCondition)
+
+		r := &Reconciler{Client: fake.NewClientBuilder().Build(), recorder: record.NewFakeRecorder(32)}
+		res, err := r.reconcileAuxiliaryInfrastructure(ctx, cluster, machine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal(ctrl.Result{}))
+		g.Expect(conditions.Has(machine, clusterv1.AuxiliaryInfrastructureReadyCondition)).To(BeFalse())
+	})
+
+	t.Run("waits for all the auxiliary infrastructure objects to be ready", func(t *testing.T) {
+		g := NewWithT(t)
+
+		bmc := newAuxiliaryObject("bmc", true)
+		nic := newAuxiliaryObject("nic", false)
+		machine := newMachine(ref("bmc"), ref("nic"), ref("missing"))
+
+		c := fake.NewClientBuilder().WithObjects(builder.GenericInfrastructureMachineCRD.DeepCopy(), machine, bmc, nic).Build()
+		r := &Reconciler{Client: c, recorder: record.NewFakeRecorder(32)}
+
+		res, err := r.reconcileAuxiliaryInfrastructure(ctx, cluster, machine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal(ctrl.Result{RequeueAfter: externalReadyWait}))
+
+		c1 := conditions.Get(machine, clusterv1.AuxiliaryInfrastructureReadyCondition)
+		g.Expect(c1).ToNot(BeNil())
+		g.Expect(c1.Status).To(Equal(corev1.ConditionFalse))
+		g.Expect(c1.Reason).To(Equal(clusterv1.WaitingForAuxiliaryInfrastructureReason))
+		g.Expect(c1.Message).To(Equal("Waiting for GenericInfrastructureMachine nic, GenericInfrastructureMachine missing (not found) to report status.ready"))
+
+		// The auxiliary infrastructure objects are owned by the Machine and get the Cluster label.
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(bmc), bmc)).To(Succeed())
+		g.Expect(bmc.GetOwnerReferences()).To(HaveLen(1))
+		g.Expect(bmc.GetOwnerReferences()[0].Name).To(Equal(machine.Name))
+		g.Expect(bmc.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, cluster.Name))
+	})
+
+	t.Run("marks the condition true once all the auxiliary infrastructure objects are ready", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := newMachine(ref("bmc"), ref("nic"))
+		c := fake.NewClientBuilder().WithObjects(builder.GenericInfrastructureMachineCRD.DeepCopy(), machine, newAuxiliaryObject("bmc", true), newAuxiliaryObject("nic", true)).Build()
+		r := &Reconciler{Client: c, recorder: record.NewFakeRecorder(32)}
+
+		res, err := r.reconcileAuxiliaryInfrastructure(ctx, cluster, machine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res).To(Equal(ctrl.Result{}))
+		g.Expect(conditions.IsTrue(machine, clusterv1.AuxiliaryInfrastructureReadyCondition)).To(BeTrue())
+	})
+}
+
+func TestReconcileDeleteAuxiliaryInfrastructure(t *testing.T) {
+	g := NewWithT(t)
+
+	bmc := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "GenericInfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"metadata": map[string]interface{}{
+				"name":      "bmc",
+				"namespace": metav1.NamespaceDefault,
+			},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-test",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.MachineSpec{
+			AuxiliaryInfrastructureRefs: []corev1.ObjectReference{
+				{
+					APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+					Kind:       "GenericInfrastructureMachine",
+					Name:       "bmc",
+				},
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithObjects(builder.GenericInfrastructureMachineCRD.DeepCopy(), bmc).Build()
+	r := &Reconciler{Client: c, recorder: record.NewFakeRecorder(32)}
+
+	// The first reconcile issues the delete request.
+	deleted, err := r.reconcileDeleteAuxiliaryInfrastructure(ctx, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(deleted).To(BeFalse())
+	g.Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(bmc), bmc))).To(BeTrue())
+	g.Expect(conditions.GetReason(machine, clusterv1.AuxiliaryInfrastructureReadyCondition)).To(Equal(clusterv1.DeletingReason))
+
+	// The next reconcile observes the objects are gone.
+	deleted, err = r.reconcileDeleteAuxiliaryInfrastructure(ctx, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(deleted).To(BeTrue())
+	g.Expect(conditions.GetReason(machine, clusterv1.AuxiliaryInfrastructureReadyCondition)).To(Equal(clusterv1.DeletedReason))
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	kubedrain "k8s.io/kubectl/pkg/drain"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// maxPodsInDrainFailureMessage is the maximum number of Pods listed in the DrainingSucceeded condition
+// when a drain does not complete.
+const maxPodsInDrainFailureMessage = 5
+
+// newNodeDrainer returns the helper used to drain the Node hosted by a Machine, configured with the Machine's drain options.
+func newNodeDrainer(ctx context.Context, kubeClient kubernetes.Interface, machine *clusterv1.Machine) (*kubedrain.Helper, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	drainer := &kubedrain.Helper{
+		Client:              kubeClient,
+		Ctx:                 ctx,
+		Force:               true,
+		IgnoreAllDaemonSets: true,
+		DeleteEmptyDirData:  true,
+		GracePeriodSeconds:  -1,
+		// If a pod is not evicted in 20 seconds, retry the eviction next time the
+		// machine gets reconciled again (to allow other machines to be reconciled).
+		Timeout: 20 * time.Second,
+		OnPodDeletedOrEvicted: func(pod *corev1.Pod, usingEviction bool) {
+			verbStr := "Deleted"
+			if usingEviction {
+				verbStr = "Evicted"
+			}
+			log.Info(fmt.Sprintf("%s pod from Node", verbStr),
+				"Pod", klog.KObj(pod))
+		},
+		Out: writer{log.Info},
+		ErrOut: writer{func(msg string, keysAndValues ...interface{}) {
+			log.Error(nil, msg, keysAndValues...)
+		}},
+	}
+
+	options := machine.Spec.NodeDrainOptions
+	if options == nil {
+		return drainer, nil
+	}
+	if options.GracePeriodSeconds != nil {
+		drainer.GracePeriodSeconds = int(*options.GracePeriodSeconds)
+	}
+	if options.Force != nil {
+		drainer.Force = *options.Force
+	}
+	if options.IgnoreDaemonSets != nil {
+		drainer.IgnoreAllDaemonSets = *options.IgnoreDaemonSets
+	}
+	drainer.DisableEviction = options.DisableEviction
+	if len(options.SkipPodSelectors) > 0 {
+		selectors := make([]labels.Selector, 0, len(options.SkipPodSelectors))
+		for i := range options.SkipPodSelectors {
+			selector, err := metav1.LabelSelectorAsSelector(&options.SkipPodSelectors[i])
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid skipPodSelectors in the drain options of Machine %s", klog.KObj(machine))
+			}
+			selectors = append(selectors, selector)
+		}
+		drainer.AdditionalFilters = append(drainer.AdditionalFilters, skipPodsFilter(selectors))
+	}
+	return drainer, nil
+}
+
+// skipPodsFilter returns a drain filter skipping the Pods matching any of the given selectors.
+func skipPodsFilter(selectors []labels.Selector) kubedrain.PodFilter {
+	return func(pod corev1.Pod) kubedrain.PodDeleteStatus {
+		for _, selector := range selectors {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				return kubedrain.MakePodDeleteStatusSkip()
+			}
+		}
+		return kubedrain.MakePodDeleteStatusOkay()
+	}
+}
+
+// drainFailureMessage returns a message describing why the drain of a Node did not complete, listing the Pods
+// which are still to be removed from the Node, or the Pods preventing the drain.
+func drainFailureMessage(drainer *kubedrain.Helper, nodeName string, drainErr error) string {
+	podDeleteList, errs := drainer.GetPodsForDeletion(nodeName)
+	if len(errs) > 0 {
+		// Errors from the drain filters already point to the Pods preventing the drain, e.g. Pods not managed
+		// by a controller when force is disabled.
+		return kerrors.NewAggregate(errs).Error()
+	}
+
+	pods := podDeleteList.Pods()
+	if len(pods) == 0 {
+		return drainErr.Error()
+	}
+	podNames := make([]string, 0, maxPodsInDrainFailureMessage)
+	for i := range pods {
+		if i == maxPodsInDrainFailureMessage {
+			podNames = append(podNames, fmt.Sprintf("... (%d more)", len(pods)-maxPodsInDrainFailureMessage))
+			break
+		}
+		podNames = append(podNames, klog.KObj(&pods[i]).String())
+	}
+	return fmt.Sprintf("Drain not completed, %d Pods still to be removed from the Node: %s: %v", len(pods), strings.Join(podNames, ", "), drainErr)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestNewNodeDrainer(t *testing.T) {
+	t.Run("uses the default drain settings when no drain options are set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		drainer, err := newNodeDrainer(ctx, fake.NewSimpleClientset(), &clusterv1.Machine{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(drainer.Force).To(BeTrue())
+		g.Expect(drainer.IgnoreAllDaemonSets).To(BeTrue())
+		g.Expect(drainer.DeleteEmptyDirData).To(BeTrue())
+		g.Expect(drainer.GracePeriodSeconds).To(Equal(-1))
+		g.Expect(drainer.DisableEviction).To(BeFalse())
+		g.Expect(drainer.AdditionalFilters).To(BeEmpty())
+	})
+
+	t.Run("applies the drain options of the Machine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := &clusterv1.Machine{
+			Spec: clusterv1.MachineSpec{
+				NodeDrainOptions: &clusterv1.NodeDrainOptions{
+					GracePeriodSeconds: pointer.Int32(30),
+					SkipPodSelectors: []metav1.LabelSelector{
+						{MatchLabels: map[string]string{"app": "skip"}},
+					},
+					Force:            pointer.Bool(false),
+					IgnoreDaemonSets: pointer.Bool(false),
+					DisableEviction:  true,
+				},
+			},
+		}
+		drainer, err := newNodeDrainer(ctx, fake.NewSimpleClientset(), machine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(drainer.Force).To(BeFalse())
+		g.Expect(drainer.IgnoreAllDaemonSets).To(BeFalse())
+		g.Expect(drainer.GracePeriodSeconds).To(Equal(30))
+		g.Expect(drainer.DisableEviction).To(BeTrue())
+		g.Expect(drainer.AdditionalFilters).To(HaveLen(1))
+	})
+
+	t.Run("fails with an invalid skip Pod selector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := &clusterv1.Machine{
+			Spec: clusterv1.MachineSpec{
+				NodeDrainOptions: &clusterv1.NodeDrainOptions{
+					SkipPodSelectors: []metav1.LabelSelector{
+						{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Unknown"}}},
+					},
+				},
+			},
+		}
+		_, err := newNodeDrainer(ctx, fake.NewSimpleClientset(), machine)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestNodeDrainerSkipPodSelectors(t *testing.T) {
+	g := NewWithT(t)
+
+	kubeClient := fake.NewSimpleClientset(
+		drainTestPod("skipped", map[string]string{"app": "skip"}),
+		drainTestPod("drained", map[string]string{"app": "other"}),
+	)
+	machine := &clusterv1.Machine{
+		Spec: clusterv1.MachineSpec{
+			NodeDrainOptions: &clusterv1.NodeDrainOptions{
+				SkipPodSelectors: []metav1.LabelSelector{
+					{MatchLabels: map[string]string{"app": "skip"}},
+				},
+			},
+		},
+	}
+	drainer, err := newNodeDrainer(ctx, kubeClient, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	podDeleteList, errs := drainer.GetPodsForDeletion("test-node")
+	g.Expect(errs).To(BeEmpty())
+	g.Expect(podDeleteList.Pods()).To(HaveLen(1))
+	g.Expect(podDeleteList.Pods()[0].Name).To(Equal("drained"))
+}
+
+func TestDrainFailureMessage(t *testing.T) {
+	drainErr := errors.New("global timeout reached: 20s")
+
+	t.Run("lists the Pods still to be removed from the Node", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objs := []runtime.Object{}
+		for i := 0; i < 7; i++ {
+			objs = append(objs, drainTestPod(fmt.Sprintf("pod-%d", i), nil))
+		}
+		drainer, err := newNodeDrainer(ctx, fake.NewSimpleClientset(objs...), &clusterv1.Machine{})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(drainFailureMessage(drainer, "test-node", drainErr)).To(Equal(
+			"Drain not completed, 7 Pods still to be removed from the Node: " +
+				"default/pod-0, default/pod-1, default/pod-2, default/pod-3, default/pod-4, ... (2 more): global timeout reached: 20s"))
+	})
+
+	t.Run("reports the Pods preventing the drain", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := &clusterv1.Machine{
+			Spec: clusterv1.MachineSpec{
+				NodeDrainOptions: &clusterv1.NodeDrainOptions{
+					Force: pointer.Bool(false),
+				},
+			},
+		}
+		drainer, err := newNodeDrainer(ctx, fake.NewSimpleClientset(drainTestPod("orphaned", nil)), machine)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(drainFailureMessage(drainer, "test-node", drainErr)).To(ContainSubstring("default/orphaned"))
+	})
+
+	t.Run("falls back to the drain error when there are no Pods left", func(t *testing.T) {
+		g := NewWithT(t)
+
+		drainer, err := newNodeDrainer(ctx, fake.NewSimpleClientset(), &clusterv1.Machine{})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(drainFailureMessage(drainer, "test-node", drainErr)).To(Equal(drainErr.Error()))
+	})
+}
+
+func drainTestPod(name string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			Labels:    labels,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "test-node",
+		},
+	}
+}
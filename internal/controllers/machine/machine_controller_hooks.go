@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/hooks"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// callBeforeMachineCreate calls the BeforeMachineCreate hook before the bootstrap and the infrastructure of a
+// Machine are provisioned; once the hook returns a non-blocking response the intent to call the AfterMachineJoin hook
+// is tracked on the Machine, and this also prevents the BeforeMachineCreate hook from being called again.
+func (r *Reconciler) callBeforeMachineCreate(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
+	if !feature.Gates.Enabled(feature.RuntimeSDK) || r.RuntimeClient == nil {
+		return ctrl.Result{}, nil
+	}
+
+	// Skip the hook if it already returned a non-blocking response, or if the Machine
+	// started provisioning before the hook was introduced.
+	if hooks.IsPending(runtimehooksv1.AfterMachineJoin, m) || isMachineProvisioningStarted(m) {
+		return ctrl.Result{}, nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	hookRequest := &runtimehooksv1.BeforeMachineCreateRequest{
+		Cluster: *cluster,
+		Machine: *m,
+	}
+	hookResponse := &runtimehooksv1.BeforeMachineCreateResponse{}
+	if err := r.RuntimeClient.CallAllExtensions(ctx, runtimehooksv1.BeforeMachineCreate, m, hookRequest, hookResponse); err != nil {
+		return ctrl.Result{}, err
+	}
+	if hookResponse.RetryAfterSeconds != 0 {
+		log.Info(fmt.Sprintf("Machine provisioning is blocked by %q hook", runtimecatalog.HookName(runtimehooksv1.BeforeMachineCreate)))
+		return ctrl.Result{RequeueAfter: time.Duration(hookResponse.RetryAfterSeconds) * time.Second}, nil
+	}
+
+	// The BeforeMachineCreate hook returned a non-blocking response. Track the intent to call
+	// the AfterMachineJoin hook once the Node of the Machine is ready.
+	if err := hooks.MarkAsPending(ctx, r.Client, m, runtimehooksv1.AfterMachineJoin); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// callAfterMachineJoin calls the AfterMachineJoin hook the first time the Node of the Machine is ready,
+// if the intent to call the hook has been tracked on the Machine.
+func (r *Reconciler) callAfterMachineJoin(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) error {
+	if !feature.Gates.Enabled(feature.RuntimeSDK) || r.RuntimeClient == nil {
+		return nil
+	}
+
+	if !hooks.IsPending(runtimehooksv1.AfterMachineJoin, m) || m.Status.NodeRef == nil || !conditions.IsTrue(m, clusterv1.MachineNodeHealthyCondition) {
+		return nil
+	}
+
+	hookRequest := &runtimehooksv1.AfterMachineJoinRequest{
+		Cluster: *cluster,
+		Machine: *m,
+	}
+	hookResponse := &runtimehooksv1.AfterMachineJoinResponse{}
+	if err := r.RuntimeClient.CallAllExtensions(ctx, runtimehooksv1.AfterMachineJoin, m, hookRequest, hookResponse); err != nil {
+		return err
+	}
+	return hooks.MarkAsDone(ctx, r.Client, m, runtimehooksv1.AfterMachineJoin)
+}
+
+// callBeforeMachineDelete calls the BeforeMachineDelete hook if the 'ok-to-delete' annotation is not set
+// and adds the annotation to the Machine after receiving a successful non-blocking response.
+func (r *Reconciler) callBeforeMachineDelete(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
+	if !feature.Gates.Enabled(feature.RuntimeSDK) || r.RuntimeClient == nil || hooks.IsOkToDelete(m) {
+		return ctrl.Result{}, nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	hookRequest := &runtimehooksv1.BeforeMachineDeleteRequest{
+		Cluster: *cluster,
+		Machine: *m,
+	}
+	hookResponse := &runtimehooksv1.BeforeMachineDeleteResponse{}
+	if err := r.RuntimeClient.CallAllExtensions(ctx, runtimehooksv1.BeforeMachineDelete, m, hookRequest, hookResponse); err != nil {
+		return ctrl.Result{}, err
+	}
+	if hookResponse.RetryAfterSeconds != 0 {
+		log.Info(fmt.Sprintf("Machine deletion is blocked by %q hook", runtimecatalog.HookName(runtimehooksv1.BeforeMachineDelete)))
+		return ctrl.Result{RequeueAfter: time.Duration(hookResponse.RetryAfterSeconds) * time.Second}, nil
+	}
+
+	// The BeforeMachineDelete hook returned a non-blocking response. Now the Machine is ready to be deleted.
+	if err := hooks.MarkAsOkToDelete(ctx, r.Client, m); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// isMachineProvisioningStarted returns true if the bootstrap or the infrastructure of the Machine
+// have already been (at least partially) provisioned.
+func isMachineProvisioningStarted(m *clusterv1.Machine) bool {
+	return m.Status.BootstrapReady || m.Status.InfrastructureReady || m.Spec.ProviderID != nil || m.Status.NodeRef != nil
+}
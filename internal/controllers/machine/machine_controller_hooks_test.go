@@ -0,0 +1,389 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
+	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/hooks"
+	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
+)
+
+func TestCallBeforeMachineCreate(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.RuntimeSDK, true)()
+
+	catalog := runtimecatalog.New()
+	_ = runtimehooksv1.AddToCatalog(catalog)
+	gvh, err := catalog.GroupVersionHook(runtimehooksv1.BeforeMachineCreate)
+	if err != nil {
+		panic(err)
+	}
+
+	blockingResponse := &runtimehooksv1.BeforeMachineCreateResponse{
+		CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+			RetryAfterSeconds: int32(10),
+			CommonResponse: runtimehooksv1.CommonResponse{
+				Status: runtimehooksv1.ResponseStatusSuccess,
+			},
+		},
+	}
+	nonBlockingResponse := &runtimehooksv1.BeforeMachineCreateResponse{
+		CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+			CommonResponse: runtimehooksv1.CommonResponse{
+				Status: runtimehooksv1.ResponseStatusSuccess,
+			},
+		},
+	}
+	failureResponse := &runtimehooksv1.BeforeMachineCreateResponse{
+		CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+			CommonResponse: runtimehooksv1.CommonResponse{
+				Status: runtimehooksv1.ResponseStatusFailure,
+			},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		machine            *clusterv1.Machine
+		hookResponse       *runtimehooksv1.BeforeMachineCreateResponse
+		wantHookToBeCalled bool
+		wantResult         ctrl.Result
+		wantPending        bool
+		wantErr            bool
+	}{
+		{
+			name:               "should track AfterMachineJoin if the hook returns a non-blocking response",
+			machine:            newHooksTestMachine(),
+			hookResponse:       nonBlockingResponse,
+			wantHookToBeCalled: true,
+			wantResult:         ctrl.Result{},
+			wantPending:        true,
+		},
+		{
+			name:               "should requeue if the hook returns a blocking response",
+			machine:            newHooksTestMachine(),
+			hookResponse:       blockingResponse,
+			wantHookToBeCalled: true,
+			wantResult:         ctrl.Result{RequeueAfter: 10 * time.Second},
+			wantPending:        false,
+		},
+		{
+			name:               "should fail if the hook returns a failure response",
+			machine:            newHooksTestMachine(),
+			hookResponse:       failureResponse,
+			wantHookToBeCalled: true,
+			wantErr:            true,
+		},
+		{
+			name: "should not call the hook if AfterMachineJoin is already tracked",
+			machine: func() *clusterv1.Machine {
+				m := newHooksTestMachine()
+				m.Annotations = map[string]string{runtimev1.PendingHooksAnnotation: "AfterMachineJoin"}
+				return m
+			}(),
+			hookResponse:       blockingResponse,
+			wantHookToBeCalled: false,
+			wantResult:         ctrl.Result{},
+			wantPending:        true,
+		},
+		{
+			name: "should not call the hook if the Machine is already provisioned",
+			machine: func() *clusterv1.Machine {
+				m := newHooksTestMachine()
+				m.Spec.ProviderID = pointer.String("test://id")
+				m.Status.InfrastructureReady = true
+				return m
+			}(),
+			hookResponse:       blockingResponse,
+			wantHookToBeCalled: false,
+			wantResult:         ctrl.Result{},
+			wantPending:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.machine).Build()
+			fakeRuntimeClient := fakeruntimeclient.NewRuntimeClientBuilder().
+				WithCallAllExtensionResponses(map[runtimecatalog.GroupVersionHook]runtimehooksv1.ResponseObject{
+					gvh: tt.hookResponse,
+				}).
+				WithCatalog(catalog).
+				Build()
+
+			r := &Reconciler{
+				Client:        fakeClient,
+				RuntimeClient: fakeRuntimeClient,
+			}
+
+			res, err := r.callBeforeMachineCreate(ctx, newHooksTestCluster(), tt.machine)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(res).To(Equal(tt.wantResult))
+				g.Expect(hooks.IsPending(runtimehooksv1.AfterMachineJoin, tt.machine)).To(Equal(tt.wantPending))
+			}
+			g.Expect(fakeRuntimeClient.CallAllCount(runtimehooksv1.BeforeMachineCreate) == 1).To(Equal(tt.wantHookToBeCalled))
+		})
+	}
+}
+
+func TestCallAfterMachineJoin(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.RuntimeSDK, true)()
+
+	catalog := runtimecatalog.New()
+	_ = runtimehooksv1.AddToCatalog(catalog)
+	gvh, err := catalog.GroupVersionHook(runtimehooksv1.AfterMachineJoin)
+	if err != nil {
+		panic(err)
+	}
+
+	successResponse := &runtimehooksv1.AfterMachineJoinResponse{
+		CommonResponse: runtimehooksv1.CommonResponse{
+			Status: runtimehooksv1.ResponseStatusSuccess,
+		},
+	}
+	failureResponse := &runtimehooksv1.AfterMachineJoinResponse{
+		CommonResponse: runtimehooksv1.CommonResponse{
+			Status: runtimehooksv1.ResponseStatusFailure,
+		},
+	}
+
+	withNodeReady := func(m *clusterv1.Machine) *clusterv1.Machine {
+		m.Status.NodeRef = &corev1.ObjectReference{Name: "test-node"}
+		m.Status.Conditions = clusterv1.Conditions{{Type: clusterv1.MachineNodeHealthyCondition, Status: corev1.ConditionTrue}}
+		return m
+	}
+	withPending := func(m *clusterv1.Machine) *clusterv1.Machine {
+		m.Annotations = map[string]string{runtimev1.PendingHooksAnnotation: "AfterMachineJoin"}
+		return m
+	}
+
+	tests := []struct {
+		name               string
+		machine            *clusterv1.Machine
+		hookResponse       *runtimehooksv1.AfterMachineJoinResponse
+		wantHookToBeCalled bool
+		wantPending        bool
+		wantErr            bool
+	}{
+		{
+			name:               "should call the hook and mark it as done if the Node is ready",
+			machine:            withPending(withNodeReady(newHooksTestMachine())),
+			hookResponse:       successResponse,
+			wantHookToBeCalled: true,
+			wantPending:        false,
+		},
+		{
+			name:               "should keep the hook pending if the hook fails",
+			machine:            withPending(withNodeReady(newHooksTestMachine())),
+			hookResponse:       failureResponse,
+			wantHookToBeCalled: true,
+			wantPending:        true,
+			wantErr:            true,
+		},
+		{
+			name:               "should not call the hook if the Node is not ready yet",
+			machine:            withPending(newHooksTestMachine()),
+			hookResponse:       successResponse,
+			wantHookToBeCalled: false,
+			wantPending:        true,
+		},
+		{
+			name:               "should not call the hook if it is not pending",
+			machine:            withNodeReady(newHooksTestMachine()),
+			hookResponse:       successResponse,
+			wantHookToBeCalled: false,
+			wantPending:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.machine).Build()
+			fakeRuntimeClient := fakeruntimeclient.NewRuntimeClientBuilder().
+				WithCallAllExtensionResponses(map[runtimecatalog.GroupVersionHook]runtimehooksv1.ResponseObject{
+					gvh: tt.hookResponse,
+				}).
+				WithCatalog(catalog).
+				Build()
+
+			r := &Reconciler{
+				Client:        fakeClient,
+				RuntimeClient: fakeRuntimeClient,
+			}
+
+			err := r.callAfterMachineJoin(ctx, newHooksTestCluster(), tt.machine)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(hooks.IsPending(runtimehooksv1.AfterMachineJoin, tt.machine)).To(Equal(tt.wantPending))
+			g.Expect(fakeRuntimeClient.CallAllCount(runtimehooksv1.AfterMachineJoin) == 1).To(Equal(tt.wantHookToBeCalled))
+		})
+	}
+}
+
+func TestCallBeforeMachineDelete(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.RuntimeSDK, true)()
+
+	catalog := runtimecatalog.New()
+	_ = runtimehooksv1.AddToCatalog(catalog)
+	gvh, err := catalog.GroupVersionHook(runtimehooksv1.BeforeMachineDelete)
+	if err != nil {
+		panic(err)
+	}
+
+	blockingResponse := &runtimehooksv1.BeforeMachineDeleteResponse{
+		CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+			RetryAfterSeconds: int32(10),
+			CommonResponse: runtimehooksv1.CommonResponse{
+				Status: runtimehooksv1.ResponseStatusSuccess,
+			},
+		},
+	}
+	nonBlockingResponse := &runtimehooksv1.BeforeMachineDeleteResponse{
+		CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+			CommonResponse: runtimehooksv1.CommonResponse{
+				Status: runtimehooksv1.ResponseStatusSuccess,
+			},
+		},
+	}
+	failureResponse := &runtimehooksv1.BeforeMachineDeleteResponse{
+		CommonRetryResponse: runtimehooksv1.CommonRetryResponse{
+			CommonResponse: runtimehooksv1.CommonResponse{
+				Status: runtimehooksv1.ResponseStatusFailure,
+			},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		machine            *clusterv1.Machine
+		hookResponse       *runtimehooksv1.BeforeMachineDeleteResponse
+		wantHookToBeCalled bool
+		wantResult         ctrl.Result
+		wantOkToDelete     bool
+		wantErr            bool
+	}{
+		{
+			name:               "should apply the ok-to-delete annotation if the hook returns a non-blocking response",
+			machine:            newHooksTestMachine(),
+			hookResponse:       nonBlockingResponse,
+			wantHookToBeCalled: true,
+			wantResult:         ctrl.Result{},
+			wantOkToDelete:     true,
+		},
+		{
+			name:               "should requeue if the hook returns a blocking response",
+			machine:            newHooksTestMachine(),
+			hookResponse:       blockingResponse,
+			wantHookToBeCalled: true,
+			wantResult:         ctrl.Result{RequeueAfter: 10 * time.Second},
+			wantOkToDelete:     false,
+		},
+		{
+			name:               "should fail if the hook returns a failure response",
+			machine:            newHooksTestMachine(),
+			hookResponse:       failureResponse,
+			wantHookToBeCalled: true,
+			wantErr:            true,
+		},
+		{
+			name: "should not call the hook if the ok-to-delete annotation is already present",
+			machine: func() *clusterv1.Machine {
+				m := newHooksTestMachine()
+				m.Annotations = map[string]string{runtimev1.OkToDeleteAnnotation: ""}
+				return m
+			}(),
+			hookResponse:       blockingResponse,
+			wantHookToBeCalled: false,
+			wantResult:         ctrl.Result{},
+			wantOkToDelete:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.machine).Build()
+			fakeRuntimeClient := fakeruntimeclient.NewRuntimeClientBuilder().
+				WithCallAllExtensionResponses(map[runtimecatalog.GroupVersionHook]runtimehooksv1.ResponseObject{
+					gvh: tt.hookResponse,
+				}).
+				WithCatalog(catalog).
+				Build()
+
+			r := &Reconciler{
+				Client:        fakeClient,
+				RuntimeClient: fakeRuntimeClient,
+			}
+
+			res, err := r.callBeforeMachineDelete(ctx, newHooksTestCluster(), tt.machine)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(res).To(Equal(tt.wantResult))
+				g.Expect(hooks.IsOkToDelete(tt.machine)).To(Equal(tt.wantOkToDelete))
+			}
+			g.Expect(fakeRuntimeClient.CallAllCount(runtimehooksv1.BeforeMachineDelete) == 1).To(Equal(tt.wantHookToBeCalled))
+		})
+	}
+}
+
+func newHooksTestCluster() *clusterv1.Cluster {
+	return &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+}
+
+func newHooksTestMachine() *clusterv1.Machine {
+	return &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-machine",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "test-cluster",
+		},
+	}
+}
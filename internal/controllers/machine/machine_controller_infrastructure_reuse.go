@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// reconcileReleaseInfrastructure prepares the infrastructure of a Machine with the Release infrastructure reuse policy
+// to be released back to the infrastructure provider pool instead of being destroyed:
+//   - the bootstrap data secret is deleted, so the credentials it contains can't be used anymore once the host is reused;
+//   - the InfraMachine is annotated with the release infrastructure annotation, signaling the infrastructure provider
+//     to release the host when the InfraMachine is deleted.
+func (r *Reconciler) reconcileReleaseInfrastructure(ctx context.Context, m *clusterv1.Machine) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if m.Spec.InfrastructureReusePolicy != clusterv1.InfrastructureReusePolicyRelease {
+		return nil
+	}
+
+	obj, err := external.Get(ctx, r.Client, &m.Spec.InfrastructureRef, m.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get %s %q for Machine %q in namespace %q",
+			m.Spec.InfrastructureRef.GroupVersionKind(), m.Spec.InfrastructureRef.Name, m.Name, m.Namespace)
+	}
+	if _, ok := obj.GetAnnotations()[clusterv1.InfrastructureReleaseAnnotation]; ok {
+		return nil
+	}
+
+	// Delete the bootstrap data secret generated by the bootstrap provider before releasing the infrastructure.
+	// NOTE: Bootstrap data secrets provided by users (Machines without a bootstrap config) are never deleted.
+	if m.Spec.Bootstrap.ConfigRef != nil && m.Spec.Bootstrap.DataSecretName != nil {
+		secret := &corev1.Secret{}
+		secret.SetNamespace(m.Namespace)
+		secret.SetName(*m.Spec.Bootstrap.DataSecretName)
+		if err := r.Client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete bootstrap data secret %s before releasing the infrastructure", klog.KObj(secret))
+		}
+		log.Info("Deleted bootstrap data secret before releasing the infrastructure", "Secret", klog.KObj(secret))
+	}
+
+	patchHelper, err := patch.NewHelper(obj, r.Client)
+	if err != nil {
+		return err
+	}
+	// NOTE: Annotations of unstructured objects are copies, so they must be set explicitly.
+	objAnnotations := obj.GetAnnotations()
+	if objAnnotations == nil {
+		objAnnotations = map[string]string{}
+	}
+	objAnnotations[clusterv1.InfrastructureReleaseAnnotation] = ""
+	obj.SetAnnotations(objAnnotations)
+	if err := patchHelper.Patch(ctx, obj); err != nil {
+		return errors.Wrapf(err, "failed to mark %s %s to be released", obj.GetKind(), klog.KObj(obj))
+	}
+
+	log.Info("Releasing infrastructure back to the infrastructure provider pool", obj.GetKind(), klog.KObj(obj))
+	r.recorder.Eventf(m, corev1.EventTypeNormal, "ReleasingInfrastructure", "Releasing %s %s back to the infrastructure provider pool", obj.GetKind(), obj.GetName())
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestReconcileReleaseInfrastructure(t *testing.T) {
+	newInfraMachine := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+			},
+		}
+	}
+	newBootstrapSecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bootstrap-data",
+				Namespace: metav1.NamespaceDefault,
+			},
+		}
+	}
+	newMachine := func(policy clusterv1.InfrastructureReusePolicy, configRef *corev1.ObjectReference) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName: "test-cluster",
+				InfrastructureRef: corev1.ObjectReference{
+					APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+					Kind:       "GenericInfrastructureMachine",
+					Name:       "infra-config1",
+				},
+				Bootstrap: clusterv1.Bootstrap{
+					ConfigRef:      configRef,
+					DataSecretName: pointer.String("bootstrap-data"),
+				},
+				InfrastructureReusePolicy: policy,
+			},
+		}
+	}
+	configRef := &corev1.ObjectReference{
+		APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+		Kind:       "GenericBootstrapConfig",
+		Name:       "bootstrap-config1",
+	}
+
+	tests := []struct {
+		name              string
+		machine           *clusterv1.Machine
+		wantReleased      bool
+		wantSecretDeleted bool
+	}{
+		{
+			name:    "does nothing without an infrastructure reuse policy",
+			machine: newMachine("", configRef),
+		},
+		{
+			name:    "does nothing with the Delete infrastructure reuse policy",
+			machine: newMachine(clusterv1.InfrastructureReusePolicyDelete, configRef),
+		},
+		{
+			name:              "marks the infrastructure to be released and deletes the bootstrap data secret with the Release infrastructure reuse policy",
+			machine:           newMachine(clusterv1.InfrastructureReusePolicyRelease, configRef),
+			wantReleased:      true,
+			wantSecretDeleted: true,
+		},
+		{
+			name:         "does not delete bootstrap data secrets provided by users",
+			machine:      newMachine(clusterv1.InfrastructureReusePolicyRelease, nil),
+			wantReleased: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fake.NewClientBuilder().WithObjects(tt.machine, newInfraMachine(), newBootstrapSecret()).Build()
+			r := &Reconciler{
+				Client:   c,
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			g.Expect(r.reconcileReleaseInfrastructure(ctx, tt.machine)).To(Succeed())
+
+			infraMachine := newInfraMachine()
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(infraMachine), infraMachine)).To(Succeed())
+			if tt.wantReleased {
+				g.Expect(infraMachine.GetAnnotations()).To(HaveKey(clusterv1.InfrastructureReleaseAnnotation))
+			} else {
+				g.Expect(infraMachine.GetAnnotations()).ToNot(HaveKey(clusterv1.InfrastructureReleaseAnnotation))
+			}
+
+			err := c.Get(ctx, client.ObjectKeyFromObject(newBootstrapSecret()), &corev1.Secret{})
+			if tt.wantSecretDeleted {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			// Calling the func again once the infrastructure is marked to be released is a no-op.
+			g.Expect(r.reconcileReleaseInfrastructure(ctx, tt.machine)).To(Succeed())
+		})
+	}
+
+	t.Run("does nothing if the infrastructure is already gone", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := newMachine(clusterv1.InfrastructureReusePolicyRelease, configRef)
+		c := fake.NewClientBuilder().WithObjects(machine, newBootstrapSecret()).Build()
+		r := &Reconciler{
+			Client:   c,
+			recorder: record.NewFakeRecorder(32),
+		}
+
+		g.Expect(r.reconcileReleaseInfrastructure(ctx, machine)).To(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(newBootstrapSecret()), &corev1.Secret{})).To(Succeed())
+	})
+}
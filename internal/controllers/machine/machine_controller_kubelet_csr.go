@@ -0,0 +1,259 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/api/v1beta1/index"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// nodeUserPrefix is the prefix of the username used by kubelets to authenticate with the API server.
+	nodeUserPrefix = "system:node:"
+
+	// nodesGroup is the group kubelets belong to.
+	nodesGroup = "system:nodes"
+
+	// kubeletServingCSRApprovedReason is the reason set on the Approved condition of the kubelet serving
+	// CertificateSigningRequests approved by the Machine controller.
+	kubeletServingCSRApprovedReason = "ApprovedByClusterAPI"
+)
+
+// kubeletServingUsages are the key usages allowed for kubelet serving certificates.
+var kubeletServingUsages = sets.New[certificatesv1.KeyUsage](
+	certificatesv1.UsageDigitalSignature,
+	certificatesv1.UsageKeyEncipherment,
+	certificatesv1.UsageServerAuth,
+)
+
+// reconcileKubeletServingCertificates approves the pending kubelet serving CertificateSigningRequests issued by the
+// Machine's Node, after verifying that the identity and the addresses requested match the ones reported by the
+// Machine and its InfraMachine.
+// NOTE: Machine.Status.Addresses are copied from the InfraMachine status by reconcileInfrastructure; addresses reported
+// by the Node itself are not trusted, given that they are set by the kubelet which is issuing the request.
+func (r *Reconciler) reconcileKubeletServingCertificates(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) (ctrl.Result, error) {
+	if !feature.Gates.Enabled(feature.KubeletServingCertificateApproval) {
+		return ctrl.Result{}, nil
+	}
+
+	// Check that the Machine hasn't been deleted or in the process
+	// and that the Machine has a NodeRef.
+	if !machine.DeletionTimestamp.IsZero() || machine.Status.NodeRef == nil {
+		return ctrl.Result{}, nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+
+	// Create a watch on the CertificateSigningRequests in the Cluster.
+	if err := r.watchClusterCertificateSigningRequests(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	csrList := &certificatesv1.CertificateSigningRequestList{}
+	if err := remoteClient.List(ctx, csrList); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list CertificateSigningRequests")
+	}
+
+	nodeName := machine.Status.NodeRef.Name
+	errs := []error{}
+	for i := range csrList.Items {
+		csr := &csrList.Items[i]
+		if csr.Spec.SignerName != certificatesv1.KubeletServingSignerName || csr.Spec.Username != nodeUserPrefix+nodeName {
+			continue
+		}
+		if isCertificateSigningRequestDecided(csr) {
+			continue
+		}
+
+		if err := validateKubeletServingCertificateSigningRequest(csr, nodeName, machine.Status.Addresses); err != nil {
+			log.Info("Skipping approval of kubelet serving CertificateSigningRequest", "CertificateSigningRequest", klog.KObj(csr), "reason", err.Error())
+			continue
+		}
+
+		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+			Type:           certificatesv1.CertificateApproved,
+			Status:         corev1.ConditionTrue,
+			Reason:         kubeletServingCSRApprovedReason,
+			Message:        fmt.Sprintf("Approved by Cluster API for Machine %s", klog.KObj(machine)),
+			LastUpdateTime: metav1.Now(),
+		})
+		if err := remoteClient.SubResource("approval").Update(ctx, csr); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to approve CertificateSigningRequest %s", csr.Name))
+			continue
+		}
+
+		log.Info("Approved kubelet serving CertificateSigningRequest", "CertificateSigningRequest", klog.KObj(csr), "node", klog.KRef("", nodeName))
+		r.recorder.Eventf(machine, corev1.EventTypeNormal, "SuccessfulApproveKubeletServingCertificate", "Approved kubelet serving CertificateSigningRequest %s", csr.Name)
+	}
+
+	return ctrl.Result{}, kerrors.NewAggregate(errs)
+}
+
+// isCertificateSigningRequestDecided returns true if a CertificateSigningRequest has been already approved, denied or failed.
+func isCertificateSigningRequestDecided(csr *certificatesv1.CertificateSigningRequest) bool {
+	for _, c := range csr.Status.Conditions {
+		switch c.Type {
+		case certificatesv1.CertificateApproved, certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			return true
+		}
+	}
+	return false
+}
+
+// validateKubeletServingCertificateSigningRequest checks that a kubelet serving CertificateSigningRequest has been issued
+// by the kubelet of the given Node, and that it requests only the addresses of the Machine.
+func validateKubeletServingCertificateSigningRequest(csr *certificatesv1.CertificateSigningRequest, nodeName string, addresses clusterv1.MachineAddresses) error {
+	if !sets.New[string](csr.Spec.Groups...).Has(nodesGroup) {
+		return errors.Errorf("requestor is not in the %s group", nodesGroup)
+	}
+
+	usages := sets.New[certificatesv1.KeyUsage](csr.Spec.Usages...)
+	if !usages.Has(certificatesv1.UsageServerAuth) {
+		return errors.Errorf("usages must include %q", certificatesv1.UsageServerAuth)
+	}
+	if !kubeletServingUsages.IsSuperset(usages) {
+		return errors.Errorf("usages %v are not allowed", sets.List(usages.Difference(kubeletServingUsages)))
+	}
+
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return errors.New("request is not a PEM encoded certificate request")
+	}
+	x509cr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse certificate request")
+	}
+	if err := x509cr.CheckSignature(); err != nil {
+		return errors.Wrap(err, "invalid certificate request signature")
+	}
+
+	if x509cr.Subject.CommonName != nodeUserPrefix+nodeName {
+		return errors.Errorf("subject common name %q does not match Node %q", x509cr.Subject.CommonName, nodeName)
+	}
+	if len(x509cr.Subject.Organization) != 1 || x509cr.Subject.Organization[0] != nodesGroup {
+		return errors.Errorf("subject organization must be %q", nodesGroup)
+	}
+	if len(x509cr.EmailAddresses) > 0 || len(x509cr.URIs) > 0 {
+		return errors.New("email addresses and URIs are not allowed")
+	}
+	if len(x509cr.DNSNames) == 0 && len(x509cr.IPAddresses) == 0 {
+		return errors.New("at least one DNS name or IP address is required")
+	}
+
+	allowedDNSNames := sets.New[string](nodeName)
+	allowedIPs := sets.New[string]()
+	for _, address := range addresses {
+		switch address.Type {
+		case clusterv1.MachineHostName, clusterv1.MachineInternalDNS, clusterv1.MachineExternalDNS:
+			allowedDNSNames.Insert(strings.ToLower(address.Address))
+		case clusterv1.MachineInternalIP, clusterv1.MachineExternalIP:
+			if ip := net.ParseIP(address.Address); ip != nil {
+				allowedIPs.Insert(ip.String())
+			}
+		}
+	}
+	for _, dnsName := range x509cr.DNSNames {
+		if !allowedDNSNames.Has(strings.ToLower(dnsName)) {
+			return errors.Errorf("DNS name %q is not an address of the Machine", dnsName)
+		}
+	}
+	for _, ip := range x509cr.IPAddresses {
+		if !allowedIPs.Has(ip.String()) {
+			return errors.Errorf("IP address %q is not an address of the Machine", ip.String())
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) watchClusterCertificateSigningRequests(ctx context.Context, cluster *clusterv1.Cluster) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		log.V(5).Info("Skipping CertificateSigningRequest watching setup because control plane is not initialized")
+		return nil
+	}
+
+	// If there is no tracker, don't watch remote CertificateSigningRequests
+	if r.Tracker == nil {
+		return nil
+	}
+
+	return r.Tracker.Watch(ctx, remote.WatchInput{
+		Name:         "machine-watchCertificateSigningRequests",
+		Cluster:      util.ObjectKey(cluster),
+		Watcher:      r.controller,
+		Kind:         &certificatesv1.CertificateSigningRequest{},
+		EventHandler: handler.EnqueueRequestsFromMapFunc(r.certificateSigningRequestToMachines),
+	})
+}
+
+// certificateSigningRequestToMachines maps kubelet serving CertificateSigningRequests to the Machines
+// whose NodeRef matches the requesting Node.
+func (r *Reconciler) certificateSigningRequestToMachines(o client.Object) []reconcile.Request {
+	csr, ok := o.(*certificatesv1.CertificateSigningRequest)
+	if !ok {
+		panic(fmt.Sprintf("Expected a CertificateSigningRequest but got a %T", o))
+	}
+
+	if csr.Spec.SignerName != certificatesv1.KubeletServingSignerName || !strings.HasPrefix(csr.Spec.Username, nodeUserPrefix) {
+		return nil
+	}
+
+	// NOTE: CertificateSigningRequests do not carry information about the Cluster they belong to, so all the
+	// Machines with a matching NodeRef are reconciled; each Machine only considers requests from its own Cluster.
+	machineList := &clusterv1.MachineList{}
+	if err := r.Client.List(
+		context.TODO(),
+		machineList,
+		client.MatchingFields{index.MachineNodeNameField: strings.TrimPrefix(csr.Spec.Username, nodeUserPrefix)}); err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(machineList.Items))
+	for i := range machineList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: util.ObjectKey(&machineList.Items[i])})
+	}
+	return requests
+}
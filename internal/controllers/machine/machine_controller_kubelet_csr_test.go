@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+func TestReconcileKubeletServingCertificates(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-machine",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: cluster.Name,
+		},
+		Status: clusterv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{
+				Name: "test-node",
+			},
+			Addresses: clusterv1.MachineAddresses{
+				{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+				{Type: clusterv1.MachineInternalDNS, Address: "test-node.example.com"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		featureGate  bool
+		csr          *certificatesv1.CertificateSigningRequest
+		wantApproved bool
+	}{
+		{
+			name:         "approves a valid kubelet serving CSR",
+			featureGate:  true,
+			csr:          newKubeletServingCSR(t, "test-node", []string{"test-node", "test-node.example.com"}, []string{"10.0.0.1"}),
+			wantApproved: true,
+		},
+		{
+			name:         "does not approve a CSR if the feature gate is disabled",
+			featureGate:  false,
+			csr:          newKubeletServingCSR(t, "test-node", []string{"test-node"}, []string{"10.0.0.1"}),
+			wantApproved: false,
+		},
+		{
+			name:         "does not approve a CSR requesting an IP address not reported by the Machine",
+			featureGate:  true,
+			csr:          newKubeletServingCSR(t, "test-node", []string{"test-node"}, []string{"10.0.0.2"}),
+			wantApproved: false,
+		},
+		{
+			name:         "does not approve a CSR requesting a DNS name not reported by the Machine",
+			featureGate:  true,
+			csr:          newKubeletServingCSR(t, "test-node", []string{"other.example.com"}, nil),
+			wantApproved: false,
+		},
+		{
+			name:         "does not approve a CSR for another Node",
+			featureGate:  true,
+			csr:          newKubeletServingCSR(t, "other-node", []string{"test-node"}, []string{"10.0.0.1"}),
+			wantApproved: false,
+		},
+		{
+			name:        "does not approve a CSR with a subject not matching the requestor",
+			featureGate: true,
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := newKubeletServingCSR(t, "other-node", []string{"test-node"}, []string{"10.0.0.1"})
+				csr.Spec.Username = "system:node:test-node"
+				return csr
+			}(),
+			wantApproved: false,
+		},
+		{
+			name:        "does not approve a CSR with client auth usage",
+			featureGate: true,
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := newKubeletServingCSR(t, "test-node", []string{"test-node"}, []string{"10.0.0.1"})
+				csr.Spec.Usages = append(csr.Spec.Usages, certificatesv1.UsageClientAuth)
+				return csr
+			}(),
+			wantApproved: false,
+		},
+		{
+			name:        "does not approve a CSR already denied",
+			featureGate: true,
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := newKubeletServingCSR(t, "test-node", []string{"test-node"}, []string{"10.0.0.1"})
+				csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{
+					Type:   certificatesv1.CertificateDenied,
+					Status: corev1.ConditionTrue,
+				}}
+				return csr
+			}(),
+			wantApproved: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.KubeletServingCertificateApproval, tt.featureGate)()
+
+			c := fake.NewClientBuilder().WithObjects(cluster, machine, tt.csr).Build()
+			r := &Reconciler{
+				Client:   c,
+				Tracker:  remote.NewTestClusterCacheTracker(logr.New(log.NullLogSink{}), c, scheme.Scheme, client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			_, err := r.reconcileKubeletServingCertificates(ctx, cluster, machine)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got := &certificatesv1.CertificateSigningRequest{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(tt.csr), got)).To(Succeed())
+			approved := false
+			for _, condition := range got.Status.Conditions {
+				if condition.Type == certificatesv1.CertificateApproved && condition.Status == corev1.ConditionTrue {
+					approved = true
+				}
+			}
+			g.Expect(approved).To(Equal(tt.wantApproved))
+		})
+	}
+}
+
+func newKubeletServingCSR(t *testing.T, nodeName string, dnsNames, ips []string) *certificatesv1.CertificateSigningRequest {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   "system:node:" + nodeName,
+			Organization: []string{"system:nodes"},
+		},
+		DNSNames: dnsNames,
+	}
+	for _, ip := range ips {
+		template.IPAddresses = append(template.IPAddresses, net.ParseIP(ip))
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "csr-" + nodeName,
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+			SignerName: certificatesv1.KubeletServingSignerName,
+			Username:   "system:node:" + nodeName,
+			Groups:     []string{"system:nodes", "system:authenticated"},
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageKeyEncipherment,
+				certificatesv1.UsageServerAuth,
+			},
+		},
+	}
+}
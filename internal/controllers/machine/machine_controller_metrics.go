@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(drainDuration)
+	ctrlmetrics.Registry.MustRegister(volumeDetachDuration)
+	ctrlmetrics.Registry.MustRegister(infrastructureDeletionDuration)
+	ctrlmetrics.Registry.MustRegister(deletionDuration)
+}
+
+// machineSubsystem is the metrics subsystem of the Machine controller.
+const machineSubsystem = "capi_machine"
+
+// deletionDurationBuckets are the buckets of the deletion duration histograms, from 1s to ~4.5h.
+var deletionDurationBuckets = prometheus.ExponentialBuckets(1, 2, 15)
+
+var (
+	// drainDuration reports the time spent draining the Node of a Machine being deleted.
+	drainDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: machineSubsystem,
+		Name:      "drain_duration_seconds",
+		Help:      "Time spent draining the Node of a Machine before deletion in seconds, broken down by cluster.",
+		Buckets:   deletionDurationBuckets,
+	}, []string{"namespace", "cluster_name"})
+
+	// volumeDetachDuration reports the time spent waiting for the volumes of the Node of a Machine being deleted to be detached.
+	volumeDetachDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: machineSubsystem,
+		Name:      "volume_detach_duration_seconds",
+		Help:      "Time spent waiting for the volumes of the Node of a Machine to be detached before deletion in seconds, broken down by cluster.",
+		Buckets:   deletionDurationBuckets,
+	}, []string{"namespace", "cluster_name"})
+
+	// infrastructureDeletionDuration reports the time from the start of the infrastructure deletion of a Machine
+	// to the removal of the Machine finalizer.
+	infrastructureDeletionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: machineSubsystem,
+		Name:      "infrastructure_deletion_duration_seconds",
+		Help:      "Time from the start of the infrastructure deletion of a Machine to the removal of the Machine finalizer in seconds, broken down by cluster.",
+		Buckets:   deletionDurationBuckets,
+	}, []string{"namespace", "cluster_name"})
+
+	// deletionDuration reports the time from the deletion request of a Machine to the removal of the Machine finalizer.
+	deletionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: machineSubsystem,
+		Name:      "deletion_duration_seconds",
+		Help:      "Time from the deletion request of a Machine to the removal of the Machine finalizer in seconds, broken down by cluster.",
+		Buckets:   deletionDurationBuckets,
+	}, []string{"namespace", "cluster_name"})
+)
+
+// observeConditionDuration observes the time elapsed since the given condition transitioned to false, if the
+// condition is currently false; it must be called before marking the condition as true, so the duration of
+// each step of the deletion workflow is observed only once.
+func observeConditionDuration(histogram *prometheus.HistogramVec, m *clusterv1.Machine, t clusterv1.ConditionType) {
+	if !conditions.IsFalse(m, t) {
+		return
+	}
+	histogram.WithLabelValues(m.Namespace, m.Spec.ClusterName).Observe(time.Since(conditions.GetLastTransitionTime(m, t).Time).Seconds())
+}
+
+// observeDeletionDuration observes the duration of the deletion of a Machine, and of the deletion of its
+// infrastructure; it must be called when the Machine finalizer is removed.
+func observeDeletionDuration(m *clusterv1.Machine) {
+	if !m.DeletionTimestamp.IsZero() {
+		deletionDuration.WithLabelValues(m.Namespace, m.Spec.ClusterName).Observe(time.Since(m.DeletionTimestamp.Time).Seconds())
+	}
+	// The PreTerminateDeleteHookSucceededCondition is set to true right before deleting the infrastructure,
+	// so its transition time can be used to record the start of the infrastructure deletion.
+	if conditions.IsTrue(m, clusterv1.PreTerminateDeleteHookSucceededCondition) {
+		infrastructureDeletionDuration.WithLabelValues(m.Namespace, m.Spec.ClusterName).Observe(time.Since(conditions.GetLastTransitionTime(m, clusterv1.PreTerminateDeleteHookSucceededCondition).Time).Seconds())
+	}
+}
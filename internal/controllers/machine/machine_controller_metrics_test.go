@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestObserveConditionDuration(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-test",
+			Namespace: "observe-condition-duration",
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "test-cluster",
+		},
+	}
+
+	// Nothing is observed if the condition does not exist.
+	observeConditionDuration(drainDuration, m, clusterv1.DrainingSucceededCondition)
+	g.Expect(testutil.CollectAndCount(drainDuration)).To(Equal(0))
+
+	conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo, "")
+	conditions.Get(m, clusterv1.DrainingSucceededCondition).LastTransitionTime = metav1.NewTime(time.Now().Add(-time.Minute))
+	observeConditionDuration(drainDuration, m, clusterv1.DrainingSucceededCondition)
+	g.Expect(testutil.CollectAndCount(drainDuration)).To(Equal(1))
+
+	// Nothing is observed once the condition is true.
+	conditions.MarkTrue(m, clusterv1.DrainingSucceededCondition)
+	observeConditionDuration(volumeDetachDuration, m, clusterv1.DrainingSucceededCondition)
+	g.Expect(testutil.CollectAndCount(volumeDetachDuration)).To(Equal(0))
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/deletion"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+const (
+	// nodeSnapshotDataKey is the key of the ConfigMap data storing the Node snapshot.
+	nodeSnapshotDataKey = "snapshot.json"
+
+	// nodeSnapshotGCInterval is the interval at which expired Node snapshots are garbage collected.
+	nodeSnapshotGCInterval = 10 * time.Minute
+)
+
+// nodeSnapshot is a compact representation of the last-known state of the Node of a Machine,
+// captured before deletion for post-mortem investigations.
+type nodeSnapshot struct {
+	Machine           string                 `json:"machine"`
+	Node              string                 `json:"node"`
+	CapturedAt        metav1.Time            `json:"capturedAt"`
+	MachineConditions clusterv1.Conditions   `json:"machineConditions,omitempty"`
+	Conditions        []corev1.NodeCondition `json:"conditions,omitempty"`
+	Taints            []corev1.Taint         `json:"taints,omitempty"`
+	Unschedulable     bool                   `json:"unschedulable,omitempty"`
+	Capacity          corev1.ResourceList    `json:"capacity,omitempty"`
+	Allocatable       corev1.ResourceList    `json:"allocatable,omitempty"`
+	KubeletVersion    string                 `json:"kubeletVersion,omitempty"`
+	DeletionReason    string                 `json:"deletionReason,omitempty"`
+	DeletionInitiator string                 `json:"deletionInitiator,omitempty"`
+}
+
+// nodeSnapshotName returns the name of the ConfigMap storing the Node snapshot of a Machine.
+func nodeSnapshotName(machine *clusterv1.Machine) string {
+	return fmt.Sprintf("%s-node-snapshot", machine.Name)
+}
+
+// reconcileNodeSnapshot persists the last-known state of the Node of a Machine being deleted into a ConfigMap,
+// so it is possible to investigate why the Node was unhealthy after it is gone.
+// The snapshot is captured only once, before the Node is drained, and it is garbage collected after NodeSnapshotTTL.
+// NOTE: Failures are logged and ignored, given that the snapshot must not block the deletion of the Machine.
+func (r *Reconciler) reconcileNodeSnapshot(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if r.NodeSnapshotTTL <= 0 || machine.Status.NodeRef == nil {
+		return
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: machine.Namespace, Name: nodeSnapshotName(machine)}, configMap)
+	if err == nil {
+		return
+	}
+	if !apierrors.IsNotFound(err) {
+		log.Error(err, "Failed to get Node snapshot, skipping")
+		return
+	}
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		log.Error(err, "Failed to create a remote client for capturing the Node snapshot, skipping")
+		return
+	}
+
+	node := &corev1.Node{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{Name: machine.Status.NodeRef.Name}, node); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to get Node for capturing the Node snapshot, skipping", "Node", klog.KRef("", machine.Status.NodeRef.Name))
+		}
+		return
+	}
+
+	configMap, err = newNodeSnapshotConfigMap(cluster, machine, node, time.Now(), r.NodeSnapshotTTL)
+	if err != nil {
+		log.Error(err, "Failed to generate Node snapshot, skipping")
+		return
+	}
+	if err := r.Client.Create(ctx, configMap); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			log.Error(err, "Failed to create Node snapshot, skipping")
+		}
+		return
+	}
+
+	log.Info("Captured Node snapshot before deletion", "Node", klog.KObj(node), "ConfigMap", klog.KObj(configMap))
+	r.recorder.Eventf(machine, corev1.EventTypeNormal, "SuccessfulCaptureNodeSnapshot", "captured snapshot of Machine's node %q in ConfigMap %q", node.Name, configMap.Name)
+}
+
+// newNodeSnapshotConfigMap returns the ConfigMap storing the snapshot of the Node of a Machine.
+// The ConfigMap is owned by the Cluster, so it outlives the Machine and gets deleted together with the Cluster.
+func newNodeSnapshotConfigMap(cluster *clusterv1.Cluster, machine *clusterv1.Machine, node *corev1.Node, now time.Time, ttl time.Duration) (*corev1.ConfigMap, error) {
+	deletionReason, deletionInitiator := deletion.Get(machine)
+	snapshot := nodeSnapshot{
+		Machine:           machine.Name,
+		Node:              node.Name,
+		CapturedAt:        metav1.NewTime(now),
+		MachineConditions: machine.Status.Conditions,
+		Conditions:        node.Status.Conditions,
+		Taints:            node.Spec.Taints,
+		Unschedulable:     node.Spec.Unschedulable,
+		Capacity:          node.Status.Capacity,
+		Allocatable:       node.Status.Allocatable,
+		KubeletVersion:    node.Status.NodeInfo.KubeletVersion,
+		DeletionReason:    deletionReason,
+		DeletionInitiator: deletionInitiator,
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal snapshot of Node %s", node.Name)
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeSnapshotName(machine),
+			Namespace: machine.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel:  cluster.Name,
+				clusterv1.NodeSnapshotLabel: machine.Name,
+			},
+			Annotations: map[string]string{
+				clusterv1.NodeSnapshotExpirationAnnotation: now.Add(ttl).UTC().Format(time.RFC3339),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cluster, clusterv1.GroupVersion.WithKind("Cluster")),
+			},
+		},
+		Data: map[string]string{
+			nodeSnapshotDataKey: string(data),
+		},
+	}, nil
+}
+
+// deleteExpiredNodeSnapshots deletes the ConfigMaps storing Node snapshots whose expiration is in the past.
+// ConfigMaps with an invalid expiration are deleted as well, given that they could otherwise linger until the Cluster is deleted.
+func (r *Reconciler) deleteExpiredNodeSnapshots(ctx context.Context) error {
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.Client.List(ctx, configMaps, client.HasLabels{clusterv1.NodeSnapshotLabel}); err != nil {
+		return errors.Wrap(err, "failed to list Node snapshots")
+	}
+
+	var errs []error
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		expiration, err := time.Parse(time.RFC3339, configMap.Annotations[clusterv1.NodeSnapshotExpirationAnnotation])
+		if err == nil && time.Now().Before(expiration) {
+			continue
+		}
+		if err := r.Client.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete Node snapshot %s", klog.KObj(configMap)))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+)
+
+func TestReconcileNodeSnapshot(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-machine",
+			Namespace: metav1.NamespaceDefault,
+			Annotations: map[string]string{
+				clusterv1.DeletionReasonAnnotation:    clusterv1.DeletionReasonRemediation,
+				clusterv1.DeletionInitiatorAnnotation: "MachineSet/test-machineset",
+			},
+		},
+		Status: clusterv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{Name: "test-node"},
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.MachineHealthCheckSucceededCondition, Status: corev1.ConditionFalse, Reason: clusterv1.UnhealthyNodeConditionReason},
+			},
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{
+				{Key: "node.kubernetes.io/memory-pressure", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Reason: "KubeletNotReady"},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue, Reason: "KubeletHasInsufficientMemory"},
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+			NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.26.0"},
+		},
+	}
+
+	t.Run("captures the snapshot of the Node", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(cluster, machine, node).Build()
+		r := &Reconciler{
+			Client:          c,
+			Tracker:         remote.NewTestClusterCacheTracker(logr.New(log.NullLogSink{}), c, fakeScheme, client.ObjectKeyFromObject(cluster)),
+			NodeSnapshotTTL: time.Hour,
+			recorder:        record.NewFakeRecorder(32),
+		}
+
+		r.reconcileNodeSnapshot(ctx, cluster, machine)
+
+		configMap := &corev1.ConfigMap{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: machine.Namespace, Name: "test-machine-node-snapshot"}, configMap)).To(Succeed())
+		g.Expect(configMap.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, cluster.Name))
+		g.Expect(configMap.Labels).To(HaveKeyWithValue(clusterv1.NodeSnapshotLabel, machine.Name))
+		g.Expect(configMap.OwnerReferences).To(HaveLen(1))
+		g.Expect(configMap.OwnerReferences[0].Kind).To(Equal("Cluster"))
+
+		expiration, err := time.Parse(time.RFC3339, configMap.Annotations[clusterv1.NodeSnapshotExpirationAnnotation])
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(expiration).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+
+		snapshot := &nodeSnapshot{}
+		g.Expect(json.Unmarshal([]byte(configMap.Data[nodeSnapshotDataKey]), snapshot)).To(Succeed())
+		g.Expect(snapshot.Machine).To(Equal(machine.Name))
+		g.Expect(snapshot.Node).To(Equal(node.Name))
+		g.Expect(snapshot.MachineConditions).To(HaveLen(1))
+		g.Expect(snapshot.Conditions).To(HaveLen(2))
+		g.Expect(snapshot.Taints).To(Equal(node.Spec.Taints))
+		g.Expect(snapshot.Allocatable.Memory().String()).To(Equal("1Gi"))
+		g.Expect(snapshot.KubeletVersion).To(Equal("v1.26.0"))
+		g.Expect(snapshot.DeletionReason).To(Equal(clusterv1.DeletionReasonRemediation))
+		g.Expect(snapshot.DeletionInitiator).To(Equal("MachineSet/test-machineset"))
+
+		// The snapshot is not overridden once captured.
+		node.Spec.Taints = nil
+		g.Expect(c.Update(ctx, node)).To(Succeed())
+		r.reconcileNodeSnapshot(ctx, cluster, machine)
+
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+		g.Expect(json.Unmarshal([]byte(configMap.Data[nodeSnapshotDataKey]), snapshot)).To(Succeed())
+		g.Expect(snapshot.Taints).To(HaveLen(1))
+	})
+
+	t.Run("does not capture the snapshot if the TTL is not set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(cluster, machine, node).Build()
+		r := &Reconciler{
+			Client:   c,
+			Tracker:  remote.NewTestClusterCacheTracker(logr.New(log.NullLogSink{}), c, fakeScheme, client.ObjectKeyFromObject(cluster)),
+			recorder: record.NewFakeRecorder(32),
+		}
+
+		r.reconcileNodeSnapshot(ctx, cluster, machine)
+
+		err := c.Get(ctx, client.ObjectKey{Namespace: machine.Namespace, Name: "test-machine-node-snapshot"}, &corev1.ConfigMap{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+}
+
+func TestDeleteExpiredNodeSnapshots(t *testing.T) {
+	g := NewWithT(t)
+
+	newConfigMap := func(name string, labels, annotations map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   metav1.NamespaceDefault,
+				Labels:      labels,
+				Annotations: annotations,
+			},
+		}
+	}
+	snapshotLabels := map[string]string{clusterv1.NodeSnapshotLabel: "test-machine"}
+
+	expired := newConfigMap("expired", snapshotLabels, map[string]string{
+		clusterv1.NodeSnapshotExpirationAnnotation: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+	})
+	invalid := newConfigMap("invalid", snapshotLabels, map[string]string{
+		clusterv1.NodeSnapshotExpirationAnnotation: "tomorrow",
+	})
+	notExpired := newConfigMap("not-expired", snapshotLabels, map[string]string{
+		clusterv1.NodeSnapshotExpirationAnnotation: time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	})
+	notASnapshot := newConfigMap("not-a-snapshot", nil, map[string]string{
+		clusterv1.NodeSnapshotExpirationAnnotation: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+	})
+
+	c := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(expired, invalid, notExpired, notASnapshot).Build()
+	r := &Reconciler{
+		Client: c,
+	}
+	g.Expect(r.deleteExpiredNodeSnapshots(ctx)).To(Succeed())
+
+	configMaps := &corev1.ConfigMapList{}
+	g.Expect(c.List(ctx, configMaps)).To(Succeed())
+	names := []string{}
+	for _, configMap := range configMaps.Items {
+		names = append(names, configMap.Name)
+	}
+	g.Expect(names).To(ConsistOf("not-expired", "not-a-snapshot"))
+}
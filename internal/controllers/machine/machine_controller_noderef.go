@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,6 +46,10 @@ var (
 func (r *Reconciler) reconcileNode(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
+	// Mark the NodeHealthy condition as stale if it was not refreshed recently, e.g. because the workload
+	// cluster is not reachable; the marker is cleared as soon as the Node is observed again.
+	conditions.MarkStaleObservations(machine, conditions.DefaultStaleObservationThreshold)
+
 	// Create a watch on the nodes in the Cluster.
 	if err := r.watchClusterNodes(ctx, cluster); err != nil {
 		return ctrl.Result{}, err
@@ -69,6 +74,10 @@ func (r *Reconciler) reconcileNode(ctx context.Context, cluster *clusterv1.Clust
 
 	// Even if Status.NodeRef exists, continue to do the following checks to make sure Node is healthy
 	node, err := r.getNode(ctx, remoteClient, providerID)
+	if err == nil || err == ErrNodeNotFound {
+		// Track the observation once the NodeHealthy condition has been set.
+		defer conditions.MarkObserved(machine, clusterv1.MachineNodeHealthyCondition)
+	}
 	if err != nil {
 		if err == ErrNodeNotFound {
 			// While a NodeRef is set in the status, failing to get that node means the node is deleted.
@@ -116,10 +125,10 @@ func (r *Reconciler) reconcileNode(ctx context.Context, cluster *clusterv1.Clust
 	// Compute labels to be propagated from Machines to nodes.
 	// NOTE: CAPI should manage only a subset of node labels, everything else should be preserved.
 	// NOTE: Once we reconcile node labels for the first time, the NodeUninitializedTaint is removed from the node.
-	nodeLabels := getManagedLabels(machine.Labels)
+	nodeLabels := getManagedLabels(machine.Labels, r.AdditionalSyncMachineLabelDomains)
 
-	// Reconcile node taints
-	if err := r.patchNode(ctx, remoteClient, node, nodeLabels, nodeAnnotations); err != nil {
+	// Reconcile node labels, annotations and taints.
+	if err := r.patchNode(ctx, remoteClient, node, nodeLabels, nodeAnnotations, machine.Spec.Taints); err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile Node %s", klog.KObj(node))
 	}
 
@@ -140,10 +149,16 @@ func (r *Reconciler) reconcileNode(ctx context.Context, cluster *clusterv1.Clust
 
 // getManagedLabels gets a map[string]string and returns another map[string]string
 // filtering out labels not managed by CAPI.
-func getManagedLabels(labels map[string]string) map[string]string {
+// Labels in the additionalDomains, or in any of their subdomains, are managed by CAPI as well.
+func getManagedLabels(labels map[string]string, additionalDomains []string) map[string]string {
 	managedLabels := make(map[string]string)
 	for key, value := range labels {
 		dnsSubdomainOrName := strings.Split(key, "/")[0]
+		for _, domain := range additionalDomains {
+			if dnsSubdomainOrName == domain || strings.HasSuffix(dnsSubdomainOrName, "."+domain) {
+				managedLabels[key] = value
+			}
+		}
 		if dnsSubdomainOrName == clusterv1.NodeRoleLabelPrefix {
 			managedLabels[key] = value
 		}
@@ -242,7 +257,7 @@ func (r *Reconciler) getNode(ctx context.Context, c client.Reader, providerID *n
 
 // PatchNode is required to workaround an issue on Node.Status.Address which is incorrectly annotated as patchStrategy=merge
 // and this causes SSA patch to fail in case there are two addresses with the same key https://github.com/kubernetes-sigs/cluster-api/issues/8417
-func (r *Reconciler) patchNode(ctx context.Context, remoteClient client.Client, node *corev1.Node, newLabels, newAnnotations map[string]string) error {
+func (r *Reconciler) patchNode(ctx context.Context, remoteClient client.Client, node *corev1.Node, newLabels, newAnnotations map[string]string, newTaints []corev1.Taint) error {
 	newNode := node.DeepCopy()
 
 	// Adds the annotations CAPI sets on the node.
@@ -279,6 +294,36 @@ func (r *Reconciler) patchNode(ctx context.Context, remoteClient client.Client,
 	// Drop the NodeUninitializedTaint taint on the node given that we are reconciling labels.
 	hasTaintChanges := taints.RemoveNodeTaint(newNode, clusterv1.NodeUninitializedTaint)
 
+	// Adds the taints from the Machine.
+	// NOTE: similarly to labels, we are tracking the taints set from the Machine in an annotation, so we
+	// can delete taints previously set by the Machine, but not present anymore. Taints are identified by
+	// key and effect; taints not set from machines should be always preserved.
+	taintsFromPreviousReconcile := strings.Split(newNode.Annotations[clusterv1.TaintsFromMachineAnnotation], ",")
+	if len(taintsFromPreviousReconcile) == 1 && taintsFromPreviousReconcile[0] == "" {
+		taintsFromPreviousReconcile = []string{}
+	}
+	taintsFromCurrentReconcile := sets.Set[string]{}
+	for _, taint := range newTaints {
+		if taints.EnsureNodeTaint(newNode, taint) {
+			hasTaintChanges = true
+		}
+		taintsFromCurrentReconcile.Insert(fmt.Sprintf("%s:%s", taint.Key, taint.Effect))
+	}
+	for _, t := range taintsFromPreviousReconcile {
+		if taintsFromCurrentReconcile.Has(t) {
+			continue
+		}
+		key, effect, _ := strings.Cut(t, ":")
+		if taints.RemoveNodeTaint(newNode, corev1.Taint{Key: key, Effect: corev1.TaintEffect(effect)}) {
+			hasTaintChanges = true
+		}
+	}
+	if taintsFromCurrentReconcile.Len() > 0 {
+		annotations.AddAnnotations(newNode, map[string]string{clusterv1.TaintsFromMachineAnnotation: strings.Join(sets.List(taintsFromCurrentReconcile), ",")})
+	} else {
+		delete(newNode.Annotations, clusterv1.TaintsFromMachineAnnotation)
+	}
+
 	if !hasAnnotationChanges && !hasLabelChanges && !hasTaintChanges {
 		return nil
 	}
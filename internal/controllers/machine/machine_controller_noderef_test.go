@@ -21,19 +21,26 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 )
 
@@ -468,7 +475,18 @@ func TestGetManagedLabels(t *testing.T) {
 	}
 
 	g := NewWithT(t)
-	got := getManagedLabels(allLabels)
+	got := getManagedLabels(allLabels, nil)
+	g.Expect(got).To(BeEquivalentTo(managedLabels))
+
+	// Labels in additional domains, and in their subdomains, are managed as well.
+	allLabels["example.com/gpu"] = "true"
+	allLabels["team.example.com/owner"] = "foo"
+	allLabels["example.com.evil/gpu"] = "not-managed"
+	allLabels["anotherexample.com/gpu"] = "not-managed"
+	managedLabels["example.com/gpu"] = "true"
+	managedLabels["team.example.com/owner"] = "foo"
+
+	got = getManagedLabels(allLabels, []string{"example.com"})
 	g.Expect(got).To(BeEquivalentTo(managedLabels))
 }
 
@@ -478,6 +496,7 @@ func TestPatchNode(t *testing.T) {
 		oldNode             *corev1.Node
 		newLabels           map[string]string
 		newAnnotations      map[string]string
+		newTaints           []corev1.Taint
 		expectedLabels      map[string]string
 		expectedAnnotations map[string]string
 		expectedTaints      []corev1.Taint
@@ -697,6 +716,110 @@ func TestPatchNode(t *testing.T) {
 				{Key: "node.kubernetes.io/not-ready", Effect: "NoSchedule"}, // Added by the API server
 			},
 		},
+		// Taints from machines (CAPI owns the taints set from machines, everything else should be preserved)
+		{
+			name: "Add taints from machines must preserve existing taints",
+			oldNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("node-%s", util.RandomString(6)),
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{Key: "not-managed-by-capi", Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+			newTaints: []corev1.Taint{
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectPreferNoSchedule},
+			},
+			expectedAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "",
+				clusterv1.TaintsFromMachineAnnotation: "example.com/gpu:NoSchedule,example.com/gpu:PreferNoSchedule",
+			},
+			expectedTaints: []corev1.Taint{
+				{Key: "not-managed-by-capi", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/not-ready", Effect: "NoSchedule"}, // Added by the API server
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectPreferNoSchedule},
+			},
+		},
+		{
+			name: "CAPI takes ownership of existing taints if they are set from machines, and the value from the machine wins",
+			oldNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("node-%s", util.RandomString(6)),
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{Key: "example.com/gpu", Value: "false", Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+			newTaints: []corev1.Taint{
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectedAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "",
+				clusterv1.TaintsFromMachineAnnotation: "example.com/gpu:NoSchedule",
+			},
+			expectedTaints: []corev1.Taint{
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/not-ready", Effect: "NoSchedule"}, // Added by the API server
+			},
+		},
+		{
+			name: "Delete a taint previously set from machines",
+			oldNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("node-%s", util.RandomString(6)),
+					Annotations: map[string]string{
+						clusterv1.TaintsFromMachineAnnotation: "example.com/gpu:NoSchedule,example.com/gpu:PreferNoSchedule",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+						{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectPreferNoSchedule},
+						{Key: "not-managed-by-capi", Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+			newTaints: []corev1.Taint{
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectedAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "",
+				clusterv1.TaintsFromMachineAnnotation: "example.com/gpu:NoSchedule",
+			},
+			expectedTaints: []corev1.Taint{
+				{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "not-managed-by-capi", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/not-ready", Effect: "NoSchedule"}, // Added by the API server
+			},
+		},
+		{
+			name: "Delete all the taints previously set from machines, annotation should be cleaned up",
+			oldNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("node-%s", util.RandomString(6)),
+					Annotations: map[string]string{
+						clusterv1.TaintsFromMachineAnnotation: "example.com/gpu:NoSchedule",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+			expectedAnnotations: map[string]string{
+				clusterv1.LabelsFromMachineAnnotation: "",
+			},
+			expectedTaints: []corev1.Taint{
+				{Key: "node.kubernetes.io/not-ready", Effect: "NoSchedule"}, // Added by the API server
+			},
+		},
 	}
 
 	r := Reconciler{Client: env}
@@ -710,7 +833,7 @@ func TestPatchNode(t *testing.T) {
 				_ = env.Cleanup(ctx, oldNode)
 			})
 
-			err := r.patchNode(ctx, env, oldNode, tc.newLabels, tc.newAnnotations)
+			err := r.patchNode(ctx, env, oldNode, tc.newLabels, tc.newAnnotations, tc.newTaints)
 			g.Expect(err).ToNot(HaveOccurred())
 
 			g.Eventually(func(g Gomega) {
@@ -725,3 +848,80 @@ func TestPatchNode(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileNodeConditionObservations(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID: "test://id-1",
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	newMachine := func() *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-machine",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName: cluster.Name,
+				ProviderID:  pointer.String("test://id-1"),
+			},
+			Status: clusterv1.MachineStatus{
+				ConditionObservations: map[clusterv1.ConditionType]clusterv1.ConditionObservation{
+					clusterv1.MachineNodeHealthyCondition: {
+						LastObservedTime: metav1.NewTime(time.Now().Add(-2 * conditions.DefaultStaleObservationThreshold)),
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("refreshes the NodeHealthy observation when the Node is observed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := newMachine()
+		c := fake.NewClientBuilder().WithObjects(cluster, machine, node).WithIndex(&corev1.Node{}, index.NodeProviderIDField, index.NodeByProviderID).Build()
+		r := &Reconciler{
+			Client:   c,
+			Tracker:  remote.NewTestClusterCacheTracker(logr.New(log.NullLogSink{}), c, scheme.Scheme, client.ObjectKeyFromObject(cluster)),
+			recorder: record.NewFakeRecorder(32),
+		}
+
+		_, err := r.reconcileNode(ctx, cluster, machine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conditions.IsTrue(machine, clusterv1.MachineNodeHealthyCondition)).To(BeTrue())
+		g.Expect(conditions.IsStale(machine, clusterv1.MachineNodeHealthyCondition)).To(BeFalse())
+		g.Expect(conditions.GetLastObservedTime(machine, clusterv1.MachineNodeHealthyCondition).Time).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	t.Run("marks the NodeHealthy observation as stale when the Node is not observed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// The Machine does not report a ProviderID yet, so the Node cannot be observed.
+		machine := newMachine()
+		machine.Spec.ProviderID = nil
+		c := fake.NewClientBuilder().WithObjects(cluster, machine).Build()
+		r := &Reconciler{
+			Client:   c,
+			Tracker:  remote.NewTestClusterCacheTracker(logr.New(log.NullLogSink{}), c, scheme.Scheme, client.ObjectKeyFromObject(cluster)),
+			recorder: record.NewFakeRecorder(32),
+		}
+
+		_, err := r.reconcileNode(ctx, cluster, machine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conditions.IsStale(machine, clusterv1.MachineNodeHealthyCondition)).To(BeTrue())
+	})
+}
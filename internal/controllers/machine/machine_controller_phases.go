@@ -39,6 +39,7 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/contract"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
@@ -75,9 +76,10 @@ func (r *Reconciler) reconcilePhase(_ context.Context, m *clusterv1.Machine) {
 		m.Status.SetTypedPhase(clusterv1.MachinePhaseFailed)
 	}
 
-	// Set the phase to "deleting" if the deletion timestamp is set.
+	// Set the phase to "deleting" if the deletion timestamp is set, or to "draining" or "waitingForVolumeDetach"
+	// while the Node hosted on the Machine is being drained or its volumes are being detached.
 	if !m.DeletionTimestamp.IsZero() {
-		m.Status.SetTypedPhase(clusterv1.MachinePhaseDeleting)
+		m.Status.SetTypedPhase(r.deletionPhase(m))
 	}
 
 	// If the phase has changed, update the LastUpdated timestamp
@@ -87,6 +89,19 @@ func (r *Reconciler) reconcilePhase(_ context.Context, m *clusterv1.Machine) {
 	}
 }
 
+// deletionPhase returns the phase of a Machine being deleted; the Draining and the VolumeDetach conditions,
+// which are set only while the corresponding step of the deletion workflow is in progress or completed,
+// are used to determine whether the Node is being drained or its volumes are being detached.
+func (r *Reconciler) deletionPhase(m *clusterv1.Machine) clusterv1.MachinePhase {
+	if conditions.IsFalse(m, clusterv1.DrainingSucceededCondition) && r.isNodeDrainAllowed(m) {
+		return clusterv1.MachinePhaseDraining
+	}
+	if conditions.IsFalse(m, clusterv1.VolumeDetachSucceededCondition) && r.isNodeVolumeDetachingAllowed(m) {
+		return clusterv1.MachinePhaseWaitingForVolumeDetach
+	}
+	return clusterv1.MachinePhaseDeleting
+}
+
 // reconcileExternal handles generic unstructured objects referenced by a Machine.
 func (r *Reconciler) reconcileExternal(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine, ref *corev1.ObjectReference) (external.ReconcileOutput, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -189,13 +204,27 @@ func (r *Reconciler) reconcileBootstrap(ctx context.Context, cluster *clusterv1.
 		return ctrl.Result{RequeueAfter: externalResult.RequeueAfter}, nil
 	}
 
+	bootstrapConfig := externalResult.Result
+
+	// Surface the progress of the bootstrap process running on the machine, if reported by the bootstrap provider.
+	if err := reconcileBootstrapProgress(m, bootstrapConfig); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// If the bootstrap data is populated, set ready and return.
+	// NOTE: If a re-bootstrap has been requested, wait for the bootstrap provider to re-generate the bootstrap data first.
 	if m.Spec.Bootstrap.DataSecretName != nil {
+		waiting, err := r.reconcileRebootstrapData(ctx, m, bootstrapConfig)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if waiting {
+			return ctrl.Result{RequeueAfter: externalReadyWait}, nil
+		}
 		m.Status.BootstrapReady = true
 		conditions.MarkTrue(m, clusterv1.BootstrapReadyCondition)
 		return ctrl.Result{}, nil
 	}
-	bootstrapConfig := externalResult.Result
 
 	// If the bootstrap config is being deleted, return early.
 	if !bootstrapConfig.GetDeletionTimestamp().IsZero() {
@@ -235,6 +264,26 @@ func (r *Reconciler) reconcileBootstrap(ctx context.Context, cluster *clusterv1.
 	return ctrl.Result{}, nil
 }
 
+// reconcileBootstrapProgress sets the progress of the bootstrap process running on the machine, as reported in the
+// optional status.bootstrapProgress field of the bootstrap object; progress is not reported once the Machine has a Node.
+func reconcileBootstrapProgress(m *clusterv1.Machine, bootstrapConfig *unstructured.Unstructured) error {
+	if m.Status.NodeRef != nil {
+		m.Status.BootstrapProgress = nil
+		return nil
+	}
+
+	progress, err := contract.Bootstrap().BootstrapProgress().Get(bootstrapConfig)
+	if err != nil {
+		if contract.IsNotFound(err) {
+			m.Status.BootstrapProgress = nil
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve bootstrap progress from %s %s", bootstrapConfig.GetKind(), klog.KObj(bootstrapConfig))
+	}
+	m.Status.BootstrapProgress = progress
+	return nil
+}
+
 // reconcileInfrastructure reconciles the Spec.InfrastructureRef object on a Machine.
 func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -281,12 +330,25 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
 	)
 
+	// Get and set Status.Addresses from the infrastructure provider.
+	// NOTE: Addresses are surfaced also before the infrastructure is ready, so the bootstrap provider
+	// can use them when generating the bootstrap data, if the infrastructure provider publishes them early.
+	err = util.UnstructuredUnmarshalField(infraConfig, &m.Status.Addresses, "status", "addresses")
+	if err != nil && err != util.ErrUnstructuredFieldNotFound {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve addresses from infrastructure provider for Machine %q in namespace %q", m.Name, m.Namespace)
+	}
+
 	// If the infrastructure provider is not ready, return early.
 	if !ready {
 		log.Info("Waiting for infrastructure provider to create machine infrastructure and report status.ready", infraConfig.GetKind(), klog.KObj(infraConfig))
 		return ctrl.Result{RequeueAfter: externalReadyWait}, nil
 	}
 
+	// Propagate the re-bootstrap request of the Machine, if any, to the infrastructure provider.
+	if err := r.reconcileRebootstrapInfrastructure(ctx, m, infraConfig); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Get Spec.ProviderID from the infrastructure provider.
 	var providerID string
 	if err := util.UnstructuredUnmarshalField(infraConfig, &providerID, "spec", "providerID"); err != nil {
@@ -295,12 +357,6 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 		return ctrl.Result{}, errors.Errorf("retrieved empty Spec.ProviderID from infrastructure provider for Machine %q in namespace %q", m.Name, m.Namespace)
 	}
 
-	// Get and set Status.Addresses from the infrastructure provider.
-	err = util.UnstructuredUnmarshalField(infraConfig, &m.Status.Addresses, "status", "addresses")
-	if err != nil && err != util.ErrUnstructuredFieldNotFound {
-		return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve addresses from infrastructure provider for Machine %q in namespace %q", m.Name, m.Namespace)
-	}
-
 	// Get and set the failure domain from the infrastructure provider.
 	var failureDomain string
 	err = util.UnstructuredUnmarshalField(infraConfig, &failureDomain, "spec", "failureDomain")
@@ -316,6 +372,17 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 	return ctrl.Result{}, nil
 }
 
+// reconcileClusterInfrastructureAvailable sets the ClusterInfrastructureAvailable condition to false on a Machine if
+// the infrastructure object of the Cluster has been deleted while the Cluster still exists, and removes it otherwise.
+func reconcileClusterInfrastructureAvailable(cluster *clusterv1.Cluster, m *clusterv1.Machine) {
+	if conditions.GetReason(cluster, clusterv1.InfrastructureReadyCondition) != clusterv1.InfrastructureDeletedReason {
+		conditions.Delete(m, clusterv1.ClusterInfrastructureAvailableCondition)
+		return
+	}
+	conditions.MarkFalse(m, clusterv1.ClusterInfrastructureAvailableCondition, clusterv1.InfrastructureDeletedReason, clusterv1.ConditionSeverityError,
+		"%s", conditions.GetMessage(cluster, clusterv1.InfrastructureReadyCondition))
+}
+
 func (r *Reconciler) reconcileCertificateExpiry(ctx context.Context, _ *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
 	var annotations map[string]string
 
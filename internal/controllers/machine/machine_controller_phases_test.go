@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -892,6 +893,95 @@ func TestReconcileBootstrap(t *testing.T) {
 				g.Expect(m.GetOwnerReferences()).NotTo(ContainRefOfGroupKind("cluster.x-k8s.io", "MachineSet"))
 			},
 		},
+		{
+			name: "existing machine, re-bootstrap requested, bootstrap config has not re-generated data yet",
+			bootstrapConfig: map[string]interface{}{
+				"kind":       "GenericBootstrapConfig",
+				"apiVersion": "bootstrap.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "bootstrap-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{},
+				"status": map[string]interface{}{
+					"ready":          true,
+					"dataSecretName": "secret-data",
+				},
+			},
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "bootstrap-test-existing",
+					Namespace:   metav1.NamespaceDefault,
+					Annotations: map[string]string{clusterv1.RebootstrapAnnotation: "1"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						ConfigRef: &corev1.ObjectReference{
+							APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+							Kind:       "GenericBootstrapConfig",
+							Name:       "bootstrap-config1",
+						},
+						DataSecretName: pointer.String("secret-data"),
+					},
+				},
+				Status: clusterv1.MachineStatus{
+					BootstrapReady: true,
+				},
+			},
+			expectResult: ctrl.Result{RequeueAfter: externalReadyWait},
+			expectError:  false,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(conditions.IsFalse(m, clusterv1.BootstrapReadyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(m, clusterv1.BootstrapReadyCondition)).To(Equal(clusterv1.WaitingForRebootstrapReason))
+			},
+		},
+		{
+			name: "existing machine, re-bootstrap requested, bootstrap config has re-generated data",
+			bootstrapConfig: map[string]interface{}{
+				"kind":       "GenericBootstrapConfig",
+				"apiVersion": "bootstrap.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "bootstrap-config1",
+					"namespace": metav1.NamespaceDefault,
+					"annotations": map[string]interface{}{
+						clusterv1.RebootstrapAnnotation:         "1",
+						clusterv1.ObservedRebootstrapAnnotation: "1",
+					},
+				},
+				"spec": map[string]interface{}{},
+				"status": map[string]interface{}{
+					"ready":          true,
+					"dataSecretName": "secret-data-rebootstrap",
+				},
+			},
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "bootstrap-test-existing",
+					Namespace:   metav1.NamespaceDefault,
+					Annotations: map[string]string{clusterv1.RebootstrapAnnotation: "1"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						ConfigRef: &corev1.ObjectReference{
+							APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+							Kind:       "GenericBootstrapConfig",
+							Name:       "bootstrap-config1",
+						},
+						DataSecretName: pointer.String("secret-data"),
+					},
+				},
+				Status: clusterv1.MachineStatus{
+					BootstrapReady: true,
+				},
+			},
+			expectResult: ctrl.Result{},
+			expectError:  false,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.BootstrapReady).To(BeTrue())
+				g.Expect(conditions.IsTrue(m, clusterv1.BootstrapReadyCondition)).To(BeTrue())
+				g.Expect(m.Spec.Bootstrap.DataSecretName).To(HaveValue(Equal("secret-data-rebootstrap")))
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -910,6 +1000,7 @@ func TestReconcileBootstrap(t *testing.T) {
 						builder.GenericInfrastructureMachineCRD.DeepCopy(),
 						bootstrapConfig,
 					).Build(),
+				recorder: record.NewFakeRecorder(32),
 			}
 
 			res, err := r.reconcileBootstrap(ctx, defaultCluster, tc.machine)
@@ -1012,6 +1103,35 @@ func TestReconcileInfrastructure(t *testing.T) {
 				g.Expect(m.GetOwnerReferences()).NotTo(ContainRefOfGroupKind("cluster.x-k8s.io", "MachineSet"))
 			},
 		},
+		{
+			name: "new machine, infrastructure config not ready with addresses",
+			infraConfig: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{},
+				"status": map[string]interface{}{
+					"ready": false,
+					"addresses": []interface{}{
+						map[string]interface{}{
+							"type":    "InternalIP",
+							"address": "10.0.0.1",
+						},
+					},
+				},
+			},
+			expectResult: ctrl.Result{RequeueAfter: externalReadyWait},
+			expectError:  false,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.InfrastructureReady).To(BeFalse())
+				g.Expect(m.Status.Addresses).To(Equal(clusterv1.MachineAddresses{
+					{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+				}))
+			},
+		},
 		{
 			name: "ready bootstrap, infra, and nodeRef, machine is running, infra object is deleted, expect failed",
 			machine: &clusterv1.Machine{
@@ -1138,6 +1258,70 @@ func TestReconcileInfrastructure(t *testing.T) {
 	}
 }
 
+func TestReconcileClusterInfrastructureAvailable(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: metav1.NamespaceDefault},
+	}
+	m := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine-test", Namespace: metav1.NamespaceDefault},
+	}
+
+	// The condition is not set if the Cluster infrastructure is available.
+	conditions.MarkTrue(cluster, clusterv1.InfrastructureReadyCondition)
+	reconcileClusterInfrastructureAvailable(cluster, m)
+	g.Expect(conditions.Has(m, clusterv1.ClusterInfrastructureAvailableCondition)).To(BeFalse())
+
+	// The condition is set to false if the Cluster infrastructure has been deleted.
+	conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureDeletedReason, clusterv1.ConditionSeverityError, "GenericInfrastructureCluster test has been deleted")
+	reconcileClusterInfrastructureAvailable(cluster, m)
+	g.Expect(*conditions.Get(m, clusterv1.ClusterInfrastructureAvailableCondition)).To(conditions.MatchCondition(*conditions.FalseCondition(
+		clusterv1.ClusterInfrastructureAvailableCondition, clusterv1.InfrastructureDeletedReason, clusterv1.ConditionSeverityError, "GenericInfrastructureCluster test has been deleted")))
+
+	// The condition is removed once the Cluster infrastructure is restored.
+	conditions.MarkTrue(cluster, clusterv1.InfrastructureReadyCondition)
+	reconcileClusterInfrastructureAvailable(cluster, m)
+	g.Expect(conditions.Has(m, clusterv1.ClusterInfrastructureAvailableCondition)).To(BeFalse())
+}
+
+func TestReconcileBootstrapProgress(t *testing.T) {
+	g := NewWithT(t)
+
+	bootstrapConfig := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "GenericBootstrapConfig",
+		"metadata": map[string]interface{}{
+			"name":      "bootstrap-config1",
+			"namespace": metav1.NamespaceDefault,
+		},
+		"status": map[string]interface{}{},
+	}}
+	m := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine-test", Namespace: metav1.NamespaceDefault},
+	}
+
+	// Progress is not set if not reported by the bootstrap provider.
+	g.Expect(reconcileBootstrapProgress(m, bootstrapConfig)).To(Succeed())
+	g.Expect(m.Status.BootstrapProgress).To(BeNil())
+
+	// Progress is surfaced while the bootstrap process is running.
+	g.Expect(unstructured.SetNestedMap(bootstrapConfig.Object, map[string]interface{}{
+		"phase":             "RunningKubeadm",
+		"message":           "kubeadm join in progress",
+		"lastHeartbeatTime": "2020-01-01T00:00:00Z",
+	}, "status", "bootstrapProgress")).To(Succeed())
+	g.Expect(reconcileBootstrapProgress(m, bootstrapConfig)).To(Succeed())
+	g.Expect(m.Status.BootstrapProgress).ToNot(BeNil())
+	g.Expect(m.Status.BootstrapProgress.Phase).To(Equal("RunningKubeadm"))
+	g.Expect(m.Status.BootstrapProgress.Message).To(Equal("kubeadm join in progress"))
+	g.Expect(m.Status.BootstrapProgress.LastHeartbeatTime).ToNot(BeNil())
+
+	// Progress is removed once the Machine has a Node.
+	m.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: "machine-test"}
+	g.Expect(reconcileBootstrapProgress(m, bootstrapConfig)).To(Succeed())
+	g.Expect(m.Status.BootstrapProgress).To(BeNil())
+}
+
 func TestReconcileCertificateExpiry(t *testing.T) {
 	fakeTimeString := "2020-01-01T00:00:00Z"
 	fakeTime, _ := time.Parse(time.RFC3339, fakeTimeString)
@@ -1370,3 +1554,81 @@ func TestReconcileCertificateExpiry(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcilePhaseDeletion(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+
+	testCases := []struct {
+		name          string
+		conditions    clusterv1.Conditions
+		annotations   map[string]string
+		expectedPhase clusterv1.MachinePhase
+	}{
+		{
+			name:          "deleting if the node is not yet drained",
+			expectedPhase: clusterv1.MachinePhaseDeleting,
+		},
+		{
+			name: "draining while the node is being drained",
+			conditions: clusterv1.Conditions{
+				*conditions.FalseCondition(clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo, ""),
+			},
+			expectedPhase: clusterv1.MachinePhaseDraining,
+		},
+		{
+			name: "deleting if draining the node is excluded",
+			conditions: clusterv1.Conditions{
+				*conditions.FalseCondition(clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo, ""),
+			},
+			annotations:   map[string]string{clusterv1.ExcludeNodeDrainingAnnotation: ""},
+			expectedPhase: clusterv1.MachinePhaseDeleting,
+		},
+		{
+			name: "waiting for volume detach after the node is drained",
+			conditions: clusterv1.Conditions{
+				*conditions.TrueCondition(clusterv1.DrainingSucceededCondition),
+				*conditions.FalseCondition(clusterv1.VolumeDetachSucceededCondition, clusterv1.WaitingForVolumeDetachReason, clusterv1.ConditionSeverityInfo, ""),
+			},
+			expectedPhase: clusterv1.MachinePhaseWaitingForVolumeDetach,
+		},
+		{
+			name: "deleting if waiting for volume detach is excluded",
+			conditions: clusterv1.Conditions{
+				*conditions.TrueCondition(clusterv1.DrainingSucceededCondition),
+				*conditions.FalseCondition(clusterv1.VolumeDetachSucceededCondition, clusterv1.WaitingForVolumeDetachReason, clusterv1.ConditionSeverityInfo, ""),
+			},
+			annotations:   map[string]string{clusterv1.ExcludeWaitForNodeVolumeDetachAnnotation: ""},
+			expectedPhase: clusterv1.MachinePhaseDeleting,
+		},
+		{
+			name: "deleting after volumes are detached",
+			conditions: clusterv1.Conditions{
+				*conditions.TrueCondition(clusterv1.DrainingSucceededCondition),
+				*conditions.TrueCondition(clusterv1.VolumeDetachSucceededCondition),
+			},
+			expectedPhase: clusterv1.MachinePhaseDeleting,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "machine-test",
+					Namespace:         metav1.NamespaceDefault,
+					DeletionTimestamp: &deletionTimestamp,
+					Annotations:       tc.annotations,
+				},
+				Status: clusterv1.MachineStatus{
+					Conditions: tc.conditions,
+				},
+			}
+
+			r := &Reconciler{}
+			r.reconcilePhase(ctx, m)
+			g.Expect(m.Status.GetTypedPhase()).To(Equal(tc.expectedPhase))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileRebootstrapData handles a re-bootstrap request for a Machine whose bootstrap data has already been generated.
+// The request is propagated to the bootstrap config, and it returns true while waiting for the bootstrap provider
+// to re-generate the bootstrap data. Once the bootstrap data has been re-generated, the name of the data secret
+// is refreshed, given that bootstrap providers are allowed to change it when re-bootstrapping.
+func (r *Reconciler) reconcileRebootstrapData(ctx context.Context, m *clusterv1.Machine, bootstrapConfig *unstructured.Unstructured) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	token, ok := m.Annotations[clusterv1.RebootstrapAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	if err := r.propagateRebootstrap(ctx, m, bootstrapConfig); err != nil {
+		return false, err
+	}
+
+	ready, err := external.IsReady(bootstrapConfig)
+	if err != nil {
+		return false, err
+	}
+	if !ready || bootstrapConfig.GetAnnotations()[clusterv1.ObservedRebootstrapAnnotation] != token {
+		log.Info("Waiting for bootstrap provider to re-generate data secret for re-bootstrap", bootstrapConfig.GetKind(), klog.KObj(bootstrapConfig))
+		conditions.MarkFalse(m, clusterv1.BootstrapReadyCondition, clusterv1.WaitingForRebootstrapReason, clusterv1.ConditionSeverityInfo, "")
+		return true, nil
+	}
+
+	secretName, _, err := unstructured.NestedString(bootstrapConfig.Object, "status", "dataSecretName")
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to retrieve dataSecretName from bootstrap provider for Machine %q in namespace %q", m.Name, m.Namespace)
+	}
+	if secretName != "" {
+		m.Spec.Bootstrap.DataSecretName = pointer.String(secretName)
+	}
+	return false, nil
+}
+
+// reconcileRebootstrapInfrastructure propagates a re-bootstrap request to the infrastructure machine, so
+// the infrastructure provider can re-run bootstrap on the existing infrastructure.
+// NOTE: The request is propagated only after the bootstrap data has been re-generated, if the Machine has a bootstrap config.
+func (r *Reconciler) reconcileRebootstrapInfrastructure(ctx context.Context, m *clusterv1.Machine, infraConfig *unstructured.Unstructured) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	token, ok := m.Annotations[clusterv1.RebootstrapAnnotation]
+	if !ok {
+		return nil
+	}
+	if m.Spec.Bootstrap.ConfigRef != nil && !conditions.IsTrue(m, clusterv1.BootstrapReadyCondition) {
+		return nil
+	}
+
+	if err := r.propagateRebootstrap(ctx, m, infraConfig); err != nil {
+		return err
+	}
+
+	if infraConfig.GetAnnotations()[clusterv1.ObservedRebootstrapAnnotation] != token {
+		log.Info("Waiting for infrastructure provider to re-run bootstrap", infraConfig.GetKind(), klog.KObj(infraConfig))
+	}
+	return nil
+}
+
+// propagateRebootstrap copies the re-bootstrap request of the Machine to an external object.
+func (r *Reconciler) propagateRebootstrap(ctx context.Context, m *clusterv1.Machine, obj *unstructured.Unstructured) error {
+	original := obj.DeepCopy()
+	if !annotations.AddAnnotations(obj, map[string]string{clusterv1.RebootstrapAnnotation: m.Annotations[clusterv1.RebootstrapAnnotation]}) {
+		return nil
+	}
+	if err := r.Client.Patch(ctx, obj, client.MergeFrom(original)); err != nil {
+		return errors.Wrapf(err, "failed to request re-bootstrap to %v %q for Machine %q in namespace %q",
+			obj.GroupVersionKind(), obj.GetName(), m.Name, m.Namespace)
+	}
+	r.recorder.Eventf(m, corev1.EventTypeNormal, "RebootstrapRequested", "Requested re-bootstrap to %s %s", obj.GetKind(), obj.GetName())
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileRebootstrapInfrastructure(t *testing.T) {
+	newInfraMachine := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+			},
+		}
+	}
+	newMachine := func() *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "machine-test",
+				Namespace:   metav1.NamespaceDefault,
+				Annotations: map[string]string{clusterv1.RebootstrapAnnotation: "1"},
+			},
+			Spec: clusterv1.MachineSpec{
+				Bootstrap: clusterv1.Bootstrap{
+					ConfigRef: &corev1.ObjectReference{
+						APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+						Kind:       "GenericBootstrapConfig",
+						Name:       "bootstrap-config1",
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("does not propagate the request until the bootstrap data has been re-generated", func(t *testing.T) {
+		g := NewWithT(t)
+
+		infraMachine := newInfraMachine()
+		machine := newMachine()
+		conditions.MarkFalse(machine, clusterv1.BootstrapReadyCondition, clusterv1.WaitingForRebootstrapReason, clusterv1.ConditionSeverityInfo, "")
+
+		c := fake.NewClientBuilder().WithObjects(infraMachine).Build()
+		r := &Reconciler{Client: c, recorder: record.NewFakeRecorder(32)}
+
+		g.Expect(r.reconcileRebootstrapInfrastructure(ctx, machine, infraMachine)).To(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(infraMachine), infraMachine)).To(Succeed())
+		g.Expect(infraMachine.GetAnnotations()).ToNot(HaveKey(clusterv1.RebootstrapAnnotation))
+	})
+
+	t.Run("propagates the request once the bootstrap data has been re-generated", func(t *testing.T) {
+		g := NewWithT(t)
+
+		infraMachine := newInfraMachine()
+		machine := newMachine()
+		conditions.MarkTrue(machine, clusterv1.BootstrapReadyCondition)
+
+		c := fake.NewClientBuilder().WithObjects(infraMachine).Build()
+		r := &Reconciler{Client: c, recorder: record.NewFakeRecorder(32)}
+
+		g.Expect(r.reconcileRebootstrapInfrastructure(ctx, machine, infraMachine)).To(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(infraMachine), infraMachine)).To(Succeed())
+		g.Expect(infraMachine.GetAnnotations()).To(HaveKeyWithValue(clusterv1.RebootstrapAnnotation, "1"))
+	})
+
+	t.Run("propagates the request immediately if the Machine has no bootstrap config", func(t *testing.T) {
+		g := NewWithT(t)
+
+		infraMachine := newInfraMachine()
+		machine := newMachine()
+		machine.Spec.Bootstrap.ConfigRef = nil
+
+		c := fake.NewClientBuilder().WithObjects(infraMachine).Build()
+		r := &Reconciler{Client: c, recorder: record.NewFakeRecorder(32)}
+
+		g.Expect(r.reconcileRebootstrapInfrastructure(ctx, machine, infraMachine)).To(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(infraMachine), infraMachine)).To(Succeed())
+		g.Expect(infraMachine.GetAnnotations()).To(HaveKeyWithValue(clusterv1.RebootstrapAnnotation, "1"))
+	})
+}
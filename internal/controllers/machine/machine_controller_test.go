@@ -730,6 +730,7 @@ func TestReconcileRequest(t *testing.T) {
 				Client:   clientFake,
 				Tracker:  remote.NewTestClusterCacheTracker(logr.New(log.NullLogSink{}), clientFake, scheme.Scheme, client.ObjectKey{Name: testCluster.Name, Namespace: testCluster.Namespace}),
 				ssaCache: ssa.NewCache(),
+				recorder: record.NewFakeRecorder(32),
 			}
 
 			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: util.ObjectKey(&tc.machine)})
@@ -1078,6 +1079,57 @@ func TestReconcileDeleteExternal(t *testing.T) {
 	}
 }
 
+func TestReconcileDeleteExternalPropagatesDeletionReason(t *testing.T) {
+	g := NewWithT(t)
+
+	bootstrapConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "BootstrapConfig",
+			"apiVersion": "bootstrap.cluster.x-k8s.io/v1beta1",
+			"metadata": map[string]interface{}{
+				"name":       "delete-bootstrap",
+				"namespace":  metav1.NamespaceDefault,
+				"finalizers": []interface{}{"test"},
+			},
+		},
+	}
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "delete",
+			Namespace: metav1.NamespaceDefault,
+			Annotations: map[string]string{
+				clusterv1.DeletionReasonAnnotation:    clusterv1.DeletionReasonScaleDown,
+				clusterv1.DeletionInitiatorAnnotation: "MachineSet/test-machineset",
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "test-cluster",
+			Bootstrap: clusterv1.Bootstrap{
+				ConfigRef: &corev1.ObjectReference{
+					APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+					Kind:       "BootstrapConfig",
+					Name:       "delete-bootstrap",
+				},
+			},
+		},
+	}
+
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().WithObjects(machine, bootstrapConfig).Build(),
+	}
+
+	_, err := r.reconcileDeleteExternal(ctx, machine, machine.Spec.Bootstrap.ConfigRef)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(bootstrapConfig.GroupVersionKind())
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(bootstrapConfig), obj)).To(Succeed())
+	g.Expect(obj.GetDeletionTimestamp().IsZero()).To(BeFalse())
+	g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(clusterv1.DeletionReasonAnnotation, clusterv1.DeletionReasonScaleDown))
+	g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(clusterv1.DeletionInitiatorAnnotation, "MachineSet/test-machineset"))
+}
+
 func TestRemoveMachineFinalizerAfterDeleteReconcile(t *testing.T) {
 	g := NewWithT(t)
 
@@ -1106,7 +1158,8 @@ func TestRemoveMachineFinalizerAfterDeleteReconcile(t *testing.T) {
 	}
 	key := client.ObjectKey{Namespace: m.Namespace, Name: m.Name}
 	mr := &Reconciler{
-		Client: fake.NewClientBuilder().WithObjects(testCluster, m).Build(),
+		Client:   fake.NewClientBuilder().WithObjects(testCluster, m).Build(),
+		recorder: record.NewFakeRecorder(32),
 	}
 	_, err := mr.Reconcile(ctx, reconcile.Request{NamespacedName: key})
 	g.Expect(err).ToNot(HaveOccurred())
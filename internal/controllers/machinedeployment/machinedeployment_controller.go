@@ -20,12 +20,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -38,12 +41,16 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/profiling"
+	"sigs.k8s.io/cluster-api/internal/util/autoscaler"
+	"sigs.k8s.io/cluster-api/internal/util/hash"
+	"sigs.k8s.io/cluster-api/internal/util/progress"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
-	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/paused"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
 
@@ -95,7 +102,10 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 			builder.WithPredicates(
 				// TODO: should this wait for Cluster.Status.InfrastructureReady similar to Infra Machine resources?
 				predicates.All(ctrl.LoggerFrom(ctx),
-					predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+					predicates.Any(ctrl.LoggerFrom(ctx),
+						predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+						predicates.ClusterPauseChanged(ctrl.LoggerFrom(ctx)),
+					),
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			),
@@ -112,8 +122,13 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
 	log := ctrl.LoggerFrom(ctx)
 
+	ctx, done := profiling.StartReconcile(ctx, "machinedeployment")
+	defer done()
+
 	// Fetch the MachineDeployment instance.
 	deployment := &clusterv1.MachineDeployment{}
+	fetchDone := profiling.StartPhase(ctx, profiling.PhaseFetch)
+	defer fetchDone()
 	if err := r.Client.Get(ctx, req.NamespacedName, deployment); err != nil {
 		if apierrors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
@@ -127,15 +142,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	log = log.WithValues("Cluster", klog.KRef(deployment.Namespace, deployment.Spec.ClusterName))
 	ctx = ctrl.LoggerInto(ctx, log)
 
+	profiling.SetCluster(ctx, types.NamespacedName{Namespace: deployment.Namespace, Name: deployment.Spec.ClusterName})
 	cluster, err := util.GetClusterByName(ctx, r.Client, deployment.Namespace, deployment.Spec.ClusterName)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	fetchDone()
+
+	// Exclude the MachineDeployment from autoscaling while autoscaling is paused, including when the object or the Cluster
+	// are paused, so the autoscaler does not fight with upgrades or with objects not being reconciled.
+	autoscalerRequeueAfter, err := autoscaler.ReconcilePause(ctx, r.Client, cluster, deployment)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// Return early if the object or Cluster is paused.
-	if annotations.IsPaused(cluster, deployment) {
+	isPaused, requeueAfter, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, deployment)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if isPaused {
 		log.Info("Reconciliation is paused for this object")
-		return ctrl.Result{}, nil
+		return util.LowestNonZeroResult(ctrl.Result{RequeueAfter: requeueAfter}, ctrl.Result{RequeueAfter: autoscalerRequeueAfter}), nil
 	}
 
 	// Initialize the patch helper
@@ -147,6 +175,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	defer func() {
 		// Always attempt to patch the object and status after each reconciliation.
 		// Patch ObservedGeneration only if the reconciliation completed successfully
+		defer profiling.StartPhase(ctx, profiling.PhasePatch)()
 		patchOpts := []patch.Option{}
 		if reterr == nil {
 			patchOpts = append(patchOpts, patch.WithStatusObservedGeneration{})
@@ -167,7 +196,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		log.Error(err, "Failed to reconcile MachineDeployment")
 		r.recorder.Eventf(deployment, corev1.EventTypeWarning, "ReconcileError", "%v", err)
 	}
-	return result, err
+	// Requeue when the progress deadline expires, so operations not making progress are detected even if nothing changes.
+	progressRequeueAfter := progress.RequeueAfter(deployment.Status.ProgressDeadline, time.Now())
+	return util.LowestNonZeroResult(result, util.LowestNonZeroResult(
+		ctrl.Result{RequeueAfter: autoscalerRequeueAfter},
+		ctrl.Result{RequeueAfter: progressRequeueAfter},
+	)), err
 }
 
 func patchMachineDeployment(ctx context.Context, patchHelper *patch.Helper, md *clusterv1.MachineDeployment, options ...patch.Option) error {
@@ -183,6 +217,7 @@ func patchMachineDeployment(ctx context.Context, patchHelper *patch.Helper, md *
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
 			clusterv1.MachineDeploymentAvailableCondition,
+			clusterv1.ProgressingCondition,
 		}},
 	)
 	return patchHelper.Patch(ctx, md, options...)
@@ -214,14 +249,32 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 	}))
 
 	// Make sure to reconcile the external infrastructure reference.
-	if err := reconcileExternalTemplateReference(ctx, r.Client, cluster, &md.Spec.Template.Spec.InfrastructureRef); err != nil {
+	infraTemplate, err := reconcileExternalTemplateReference(ctx, r.Client, cluster, &md.Spec.Template.Spec.InfrastructureRef)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	templates := []*unstructured.Unstructured{infraTemplate}
 	// Make sure to reconcile the external bootstrap reference, if any.
 	if md.Spec.Template.Spec.Bootstrap.ConfigRef != nil {
-		if err := reconcileExternalTemplateReference(ctx, r.Client, cluster, md.Spec.Template.Spec.Bootstrap.ConfigRef); err != nil {
+		bootstrapTemplate, err := reconcileExternalTemplateReference(ctx, r.Client, cluster, md.Spec.Template.Spec.Bootstrap.ConfigRef)
+		if err != nil {
 			return ctrl.Result{}, err
 		}
+		templates = append(templates, bootstrapTemplate)
+	}
+	// Make sure to reconcile the external auxiliary infrastructure references, if any.
+	for i := range md.Spec.Template.Spec.AuxiliaryInfrastructureRefs {
+		auxiliaryTemplate, err := reconcileExternalTemplateReference(ctx, r.Client, cluster, &md.Spec.Template.Spec.AuxiliaryInfrastructureRefs[i])
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		templates = append(templates, auxiliaryTemplate)
+	}
+
+	// Record the checksum of the content of the referenced templates, so MachineSets referencing templates
+	// with the same content can be reused instead of rolling out new Machines.
+	if err := setTemplatesChecksum(md, templates); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	msList, err := r.getMachineSetsForDeployment(ctx, md)
@@ -267,6 +320,13 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 		return ctrl.Result{}, r.sync(ctx, md, msList)
 	}
 
+	// Rollback the machine template to the requested revision, if any; the MachineDeployment is then
+	// rolled out to the restored template on the next reconcile.
+	if md.Spec.RollbackTo != nil {
+		r.rollback(ctx, md, msList)
+		return ctrl.Result{}, nil
+	}
+
 	if md.Spec.Strategy == nil {
 		return ctrl.Result{}, errors.Errorf("missing MachineDeployment strategy")
 	}
@@ -410,23 +470,23 @@ func (r *Reconciler) MachineSetToDeployments(o client.Object) []ctrl.Request {
 	return result
 }
 
-func reconcileExternalTemplateReference(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, ref *corev1.ObjectReference) error {
+func reconcileExternalTemplateReference(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, ref *corev1.ObjectReference) (*unstructured.Unstructured, error) {
 	if !strings.HasSuffix(ref.Kind, clusterv1.TemplateSuffix) {
-		return nil
+		return nil, nil
 	}
 
 	if err := utilconversion.UpdateReferenceAPIContract(ctx, c, ref); err != nil {
-		return err
+		return nil, err
 	}
 
 	obj, err := external.Get(ctx, c, ref, cluster.Namespace)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	patchHelper, err := patch.NewHelper(obj, c)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	obj.SetOwnerReferences(util.EnsureOwnerRef(obj.GetOwnerReferences(), metav1.OwnerReference{
@@ -436,5 +496,25 @@ func reconcileExternalTemplateReference(ctx context.Context, c client.Client, cl
 		UID:        cluster.UID,
 	}))
 
-	return patchHelper.Patch(ctx, obj)
+	return obj, patchHelper.Patch(ctx, obj)
+}
+
+// setTemplatesChecksum sets the TemplatesChecksumAnnotation on the MachineDeployment with the checksum of the content
+// of the referenced templates; the annotation is removed if any of the references is not a template.
+func setTemplatesChecksum(md *clusterv1.MachineDeployment, templates []*unstructured.Unstructured) error {
+	for _, template := range templates {
+		if template == nil {
+			delete(md.Annotations, clusterv1.TemplatesChecksumAnnotation)
+			return nil
+		}
+	}
+	checksum, err := hash.ComputeTemplatesChecksum(templates...)
+	if err != nil {
+		return errors.Wrap(err, "failed to compute templates checksum")
+	}
+	if md.Annotations == nil {
+		md.Annotations = map[string]string{}
+	}
+	md.Annotations[clusterv1.TemplatesChecksumAnnotation] = checksum
+	return nil
 }
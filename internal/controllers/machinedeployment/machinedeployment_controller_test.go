@@ -968,3 +968,29 @@ func TestGetMachineSetsForDeployment(t *testing.T) {
 		})
 	}
 }
+
+func TestSetTemplatesChecksum(t *testing.T) {
+	g := NewWithT(t)
+
+	infraTemplate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":       "GenericInfrastructureMachineTemplate",
+		"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+		"metadata":   map[string]interface{}{"name": "infra-template-1"},
+		"spec":       map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{"size": "large"}}},
+	}}
+
+	md := &clusterv1.MachineDeployment{}
+	g.Expect(setTemplatesChecksum(md, []*unstructured.Unstructured{infraTemplate})).To(Succeed())
+	g.Expect(md.Annotations).To(HaveKey(clusterv1.TemplatesChecksumAnnotation))
+	checksum := md.Annotations[clusterv1.TemplatesChecksumAnnotation]
+
+	// Re-creating the template with a different name does not change the checksum.
+	recreated := infraTemplate.DeepCopy()
+	recreated.SetName("infra-template-2")
+	g.Expect(setTemplatesChecksum(md, []*unstructured.Unstructured{recreated})).To(Succeed())
+	g.Expect(md.Annotations).To(HaveKeyWithValue(clusterv1.TemplatesChecksumAnnotation, checksum))
+
+	// The annotation is removed if a reference is not a template.
+	g.Expect(setTemplatesChecksum(md, []*unstructured.Unstructured{infraTemplate, nil})).To(Succeed())
+	g.Expect(md.Annotations).ToNot(HaveKey(clusterv1.TemplatesChecksumAnnotation))
+}
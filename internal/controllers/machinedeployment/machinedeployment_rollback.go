@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+)
+
+// rollback rolls back the MachineDeployment to the revision specified in spec.rollbackTo by copying the
+// machine template of the MachineSet with that revision into the MachineDeployment.
+// NOTE: spec.rollbackTo is always cleared, so the rollback is attempted only once; the actual rollout
+// is performed by the following reconciles, as for any other change to the machine template.
+func (r *Reconciler) rollback(ctx context.Context, md *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet) {
+	log := ctrl.LoggerFrom(ctx)
+
+	toRevision := md.Spec.RollbackTo.Revision
+	md.Spec.RollbackTo = nil
+
+	// If rollbackTo.revision is 0, rollback to the last revision.
+	if toRevision == 0 {
+		if toRevision = mdutil.LastRevision(msList, log); toRevision == 0 {
+			log.Info("Unable to rollback MachineDeployment, no rollout history found")
+			r.recorder.Event(md, corev1.EventTypeWarning, "RollbackRevisionNotFound", "Unable to find last revision")
+			return
+		}
+	}
+
+	for _, ms := range msList {
+		v, err := mdutil.Revision(ms)
+		if err != nil {
+			log.V(4).Info("Unable to extract revision from MachineSet", "MachineSet", klog.KObj(ms), "err", err)
+			continue
+		}
+		if v != toRevision {
+			continue
+		}
+
+		// Copy the template of the MachineSet into the MachineDeployment (excluding the hash).
+		template := ms.Spec.Template.DeepCopy()
+		delete(template.Labels, clusterv1.MachineDeploymentUniqueLabel)
+
+		if mdutil.EqualMachineTemplate(&md.Spec.Template, template) {
+			log.Info("Skipping rollback of MachineDeployment, machine template is unchanged", "revision", toRevision)
+			r.recorder.Eventf(md, corev1.EventTypeWarning, "RollbackTemplateUnchanged", "The rollback revision %d contains the same template as the current one", toRevision)
+			return
+		}
+
+		log.Info("Rolling back MachineDeployment", "revision", toRevision, "MachineSet", klog.KObj(ms))
+		md.Spec.Template = *template
+		r.recorder.Eventf(md, corev1.EventTypeNormal, "RollbackDone", "Rolled back MachineDeployment to revision %d", toRevision)
+		return
+	}
+
+	log.Info("Unable to rollback MachineDeployment, revision not found", "revision", toRevision)
+	r.recorder.Eventf(md, corev1.EventTypeWarning, "RollbackRevisionNotFound", "Unable to find revision %d", toRevision)
+}
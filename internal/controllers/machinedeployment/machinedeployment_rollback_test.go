@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestRollback(t *testing.T) {
+	machineSetWithRevision := func(name, revision, infraTemplate string) *clusterv1.MachineSet {
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+				Annotations: map[string]string{
+					clusterv1.RevisionAnnotation: revision,
+				},
+			},
+			Spec: clusterv1.MachineSetSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					ObjectMeta: clusterv1.ObjectMeta{
+						Labels: map[string]string{
+							clusterv1.MachineDeploymentUniqueLabel: name,
+						},
+					},
+					Spec: clusterv1.MachineSpec{
+						InfrastructureRef: corev1.ObjectReference{Name: infraTemplate},
+					},
+				},
+			},
+		}
+	}
+	msList := []*clusterv1.MachineSet{
+		machineSetWithRevision("ms-1", "1", "infra-1"),
+		machineSetWithRevision("ms-2", "2", "infra-2"),
+		machineSetWithRevision("ms-3", "3", "infra-3"),
+	}
+
+	tests := []struct {
+		name                  string
+		rollbackTo            int64
+		expectedInfraTemplate string
+	}{
+		{
+			name:                  "rollback to the last revision",
+			rollbackTo:            0,
+			expectedInfraTemplate: "infra-2",
+		},
+		{
+			name:                  "rollback to a specific revision",
+			rollbackTo:            1,
+			expectedInfraTemplate: "infra-1",
+		},
+		{
+			name:                  "rollback to the current revision does not change the template",
+			rollbackTo:            3,
+			expectedInfraTemplate: "infra-3",
+		},
+		{
+			name:                  "rollback to a non existing revision does not change the template",
+			rollbackTo:            10,
+			expectedInfraTemplate: "infra-3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			md := &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "md",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: clusterv1.MachineDeploymentSpec{
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							InfrastructureRef: corev1.ObjectReference{Name: "infra-3"},
+						},
+					},
+					RollbackTo: &clusterv1.MachineDeploymentRollbackConfig{
+						Revision: tt.rollbackTo,
+					},
+				},
+			}
+
+			r := &Reconciler{
+				recorder: record.NewFakeRecorder(32),
+			}
+			r.rollback(ctx, md, msList)
+
+			g.Expect(md.Spec.RollbackTo).To(BeNil())
+			g.Expect(md.Spec.Template.Spec.InfrastructureRef.Name).To(Equal(tt.expectedInfraTemplate))
+			g.Expect(md.Spec.Template.Labels).ToNot(HaveKey(clusterv1.MachineDeploymentUniqueLabel))
+		})
+	}
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+)
+
+// maxRolloutReasonFieldChanges is the maximum number of field changes listed for a template in a rollout reason.
+const maxRolloutReasonFieldChanges = 5
+
+// computeRolloutReason computes the reason for a rollout, i.e. for creating a new MachineSet.
+// If the MachineDeployment has the RolloutReasonAnnotation its value is used, otherwise the reason is
+// a summary of the changes between the machine template of the latest MachineSet and the one of the MachineDeployment.
+func (r *Reconciler) computeRolloutReason(ctx context.Context, md *clusterv1.MachineDeployment, oldMSs []*clusterv1.MachineSet) string {
+	if reason := md.Annotations[clusterv1.RolloutReasonAnnotation]; reason != "" {
+		return reason
+	}
+
+	latestMS := latestMachineSet(oldMSs)
+	if latestMS == nil {
+		return "initial rollout"
+	}
+
+	from := &latestMS.Spec.Template.Spec
+	to := &md.Spec.Template.Spec
+	reasons := []string{}
+
+	if v := changeSummary(pointer.StringDeref(from.Version, ""), pointer.StringDeref(to.Version, "")); v != "" {
+		reasons = append(reasons, fmt.Sprintf("version changed: %s", v))
+	}
+
+	if !sameTemplateRef(&from.InfrastructureRef, &to.InfrastructureRef) {
+		reasons = append(reasons, fmt.Sprintf("infraTemplate changed: %s", r.templateChangeSummary(ctx, md.Namespace, &from.InfrastructureRef, &to.InfrastructureRef)))
+	}
+
+	if !sameTemplateRef(from.Bootstrap.ConfigRef, to.Bootstrap.ConfigRef) {
+		reasons = append(reasons, fmt.Sprintf("bootstrapTemplate changed: %s", r.templateChangeSummary(ctx, md.Namespace, from.Bootstrap.ConfigRef, to.Bootstrap.ConfigRef)))
+	}
+
+	if v := changeSummary(pointer.StringDeref(from.Bootstrap.DataSecretName, ""), pointer.StringDeref(to.Bootstrap.DataSecretName, "")); v != "" {
+		reasons = append(reasons, fmt.Sprintf("bootstrap dataSecretName changed: %s", v))
+	}
+
+	if v := changeSummary(pointer.StringDeref(from.FailureDomain, ""), pointer.StringDeref(to.FailureDomain, "")); v != "" {
+		reasons = append(reasons, fmt.Sprintf("failureDomain changed: %s", v))
+	}
+
+	if len(reasons) == 0 && md.Spec.RolloutAfter != nil && md.Spec.RolloutAfter.After(latestMS.CreationTimestamp.Time) {
+		reasons = append(reasons, fmt.Sprintf("rolloutAfter reached: %s", md.Spec.RolloutAfter.UTC().Format(time.RFC3339)))
+	}
+
+	if len(reasons) == 0 {
+		return "machine template changed"
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// templateChangeSummary returns a summary of the changes between two templates, e.g. "instanceType t3.large→t3.xlarge".
+// If the templates cannot be read, e.g. because the old template has been deleted, or if there are no changes in
+// the template spec, a summary of the changes to the reference is returned.
+func (r *Reconciler) templateChangeSummary(ctx context.Context, namespace string, from, to *corev1.ObjectReference) string {
+	refSummary := changeSummary(templateRefString(from), templateRefString(to))
+	if from == nil || to == nil {
+		return refSummary
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	fromTemplate, err := external.Get(ctx, r.Client, from, namespace)
+	if err != nil {
+		log.V(4).Info("Failed to get template for computing the rollout reason", "ref", templateRefString(from), "err", err.Error())
+		return refSummary
+	}
+	toTemplate, err := external.Get(ctx, r.Client, to, namespace)
+	if err != nil {
+		log.V(4).Info("Failed to get template for computing the rollout reason", "ref", templateRefString(to), "err", err.Error())
+		return refSummary
+	}
+
+	fromSpec, _, _ := unstructured.NestedMap(fromTemplate.Object, "spec", "template", "spec")
+	toSpec, _, _ := unstructured.NestedMap(toTemplate.Object, "spec", "template", "spec")
+	changes := fieldChanges(fromSpec, toSpec)
+	if len(changes) == 0 {
+		return refSummary
+	}
+	if len(changes) > maxRolloutReasonFieldChanges {
+		changes = append(changes[:maxRolloutReasonFieldChanges], "...")
+	}
+	return strings.Join(changes, ", ")
+}
+
+// fieldChanges returns the list of changed fields between two objects, sorted by field path.
+// Nested objects are compared field by field, while lists are compared as a whole.
+func fieldChanges(from, to map[string]interface{}) []string {
+	fromFields := map[string]string{}
+	flattenFields("", from, fromFields)
+	toFields := map[string]string{}
+	flattenFields("", to, toFields)
+
+	paths := map[string]bool{}
+	for p := range fromFields {
+		paths[p] = true
+	}
+	for p := range toFields {
+		paths[p] = true
+	}
+
+	changes := []string{}
+	for p := range paths {
+		if v := changeSummary(fromFields[p], toFields[p]); v != "" {
+			changes = append(changes, fmt.Sprintf("%s %s", p, v))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+func flattenFields(prefix string, obj map[string]interface{}, fields map[string]string) {
+	for k, v := range obj {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		switch value := v.(type) {
+		case map[string]interface{}:
+			flattenFields(path, value, fields)
+		case string:
+			fields[path] = value
+		default:
+			raw, err := json.Marshal(value)
+			if err != nil {
+				fields[path] = fmt.Sprintf("%v", value)
+				continue
+			}
+			fields[path] = string(raw)
+		}
+	}
+}
+
+// changeSummary returns "from→to" if the values are different, an empty string otherwise.
+func changeSummary(from, to string) string {
+	if from == to {
+		return ""
+	}
+	if from == "" {
+		from = "<unset>"
+	}
+	if to == "" {
+		to = "<unset>"
+	}
+	return fmt.Sprintf("%s→%s", from, to)
+}
+
+// sameTemplateRef returns true if the references point to the same template, ignoring the version of the
+// template API, which can be changed without triggering a rollout.
+func sameTemplateRef(a, b *corev1.ObjectReference) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	aGV, _ := schema.ParseGroupVersion(a.APIVersion)
+	bGV, _ := schema.ParseGroupVersion(b.APIVersion)
+	return aGV.Group == bGV.Group && a.Kind == b.Kind && a.Name == b.Name
+}
+
+func templateRefString(ref *corev1.ObjectReference) string {
+	if ref == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", ref.Kind, ref.Name)
+}
+
+// latestMachineSet returns the MachineSet with the highest revision.
+func latestMachineSet(machineSets []*clusterv1.MachineSet) *clusterv1.MachineSet {
+	var latest *clusterv1.MachineSet
+	latestRevision := int64(-1)
+	for _, ms := range machineSets {
+		v, err := mdutil.Revision(ms)
+		if err != nil {
+			continue
+		}
+		if v > latestRevision {
+			latest = ms
+			latestRevision = v
+		}
+	}
+	return latest
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestComputeRolloutReason(t *testing.T) {
+	oldInfraTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra-template-1").
+		WithSpecFields(map[string]interface{}{
+			"spec.template.spec.instanceType":    "t3.large",
+			"spec.template.spec.rootVolume.size": int64(50),
+		}).Build()
+	newInfraTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra-template-2").
+		WithSpecFields(map[string]interface{}{
+			"spec.template.spec.instanceType":    "t3.xlarge",
+			"spec.template.spec.rootVolume.size": int64(50),
+		}).Build()
+
+	infraRef := func(name string) corev1.ObjectReference {
+		return corev1.ObjectReference{
+			APIVersion: builder.InfrastructureGroupVersion.String(),
+			Kind:       builder.GenericInfrastructureMachineTemplateKind,
+			Name:       name,
+		}
+	}
+
+	machineSet := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "ms-1",
+			Namespace:         metav1.NamespaceDefault,
+			CreationTimestamp: metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+			Annotations: map[string]string{
+				clusterv1.RevisionAnnotation: "1",
+			},
+		},
+		Spec: clusterv1.MachineSetSpec{
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Version:           pointer.String("v1.25.0"),
+					InfrastructureRef: infraRef("infra-template-1"),
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		md           *clusterv1.MachineDeployment
+		oldMSs       []*clusterv1.MachineSet
+		wantReason   string
+		templateObjs []client.Object
+	}{
+		{
+			name: "uses the reason from the MachineDeployment annotation",
+			md: &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{clusterv1.RolloutReasonAnnotation: "rotate to the hardened image"},
+				},
+			},
+			oldMSs:     []*clusterv1.MachineSet{machineSet},
+			wantReason: "rotate to the hardened image",
+		},
+		{
+			name:       "initial rollout",
+			md:         &clusterv1.MachineDeployment{},
+			wantReason: "initial rollout",
+		},
+		{
+			name: "version changed",
+			md: &clusterv1.MachineDeployment{
+				Spec: clusterv1.MachineDeploymentSpec{
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Version:           pointer.String("v1.26.0"),
+							InfrastructureRef: infraRef("infra-template-1"),
+						},
+					},
+				},
+			},
+			oldMSs:     []*clusterv1.MachineSet{machineSet},
+			wantReason: "version changed: v1.25.0→v1.26.0",
+		},
+		{
+			name: "infrastructure template changed",
+			md: &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault},
+				Spec: clusterv1.MachineDeploymentSpec{
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Version:           pointer.String("v1.25.0"),
+							InfrastructureRef: infraRef("infra-template-2"),
+						},
+					},
+				},
+			},
+			oldMSs:       []*clusterv1.MachineSet{machineSet},
+			templateObjs: []client.Object{oldInfraTemplate, newInfraTemplate},
+			wantReason:   "infraTemplate changed: instanceType t3.large→t3.xlarge",
+		},
+		{
+			name: "infrastructure template changed, but the old template does not exist anymore",
+			md: &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault},
+				Spec: clusterv1.MachineDeploymentSpec{
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Version:           pointer.String("v1.25.0"),
+							InfrastructureRef: infraRef("infra-template-2"),
+						},
+					},
+				},
+			},
+			oldMSs:       []*clusterv1.MachineSet{machineSet},
+			templateObjs: []client.Object{newInfraTemplate},
+			wantReason:   "infraTemplate changed: GenericInfrastructureMachineTemplate/infra-template-1→GenericInfrastructureMachineTemplate/infra-template-2",
+		},
+		{
+			name: "rolloutAfter reached",
+			md: &clusterv1.MachineDeployment{
+				Spec: clusterv1.MachineDeploymentSpec{
+					RolloutAfter: &metav1.Time{Time: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Version:           pointer.String("v1.25.0"),
+							InfrastructureRef: infraRef("infra-template-1"),
+						},
+					},
+				},
+			},
+			oldMSs:     []*clusterv1.MachineSet{machineSet},
+			wantReason: "rolloutAfter reached: 2023-02-01T00:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &Reconciler{
+				Client: fake.NewClientBuilder().WithObjects(tt.templateObjs...).Build(),
+			}
+			g.Expect(r.computeRolloutReason(ctx, tt.md, tt.oldMSs)).To(Equal(tt.wantReason))
+		})
+	}
+}
+
+func TestFieldChanges(t *testing.T) {
+	g := NewWithT(t)
+
+	from := map[string]interface{}{
+		"instanceType": "t3.large",
+		"rootVolume":   map[string]interface{}{"size": int64(50), "type": "gp2"},
+		"subnets":      []interface{}{"a", "b"},
+		"removed":      "foo",
+	}
+	to := map[string]interface{}{
+		"instanceType": "t3.xlarge",
+		"rootVolume":   map[string]interface{}{"size": int64(100), "type": "gp2"},
+		"subnets":      []interface{}{"a", "c"},
+		"added":        true,
+	}
+
+	g.Expect(fieldChanges(from, to)).To(Equal([]string{
+		"added <unset>→true",
+		"instanceType t3.large→t3.xlarge",
+		"removed foo→<unset>",
+		"rootVolume.size 50→100",
+		`subnets ["a","b"]→["a","c"]`,
+	}))
+}
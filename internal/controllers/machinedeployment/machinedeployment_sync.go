@@ -41,6 +41,8 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
 	"sigs.k8s.io/cluster-api/internal/util/hash"
+	"sigs.k8s.io/cluster-api/internal/util/naming"
+	"sigs.k8s.io/cluster-api/internal/util/progress"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -177,13 +179,18 @@ func (r *Reconciler) createMachineSetAndWait(ctx context.Context, deployment *cl
 		return nil, errors.Wrap(err, "failed to create new MachineSet")
 	}
 
+	// Record why the new MachineSet is being created, on the MachineSet and on its Machines.
+	rolloutReason := r.computeRolloutReason(ctx, deployment, oldMSs)
+	newMS.Annotations[clusterv1.RolloutReasonAnnotation] = rolloutReason
+	newMS.Spec.Template.Annotations[clusterv1.RolloutReasonAnnotation] = rolloutReason
+
 	// Create the MachineSet.
 	if err := ssa.Patch(ctx, r.Client, machineDeploymentManagerName, newMS); err != nil {
 		r.recorder.Eventf(deployment, corev1.EventTypeWarning, "FailedCreate", "Failed to create MachineSet %s: %v", klog.KObj(newMS), err)
 		return nil, errors.Wrapf(err, "failed to create new MachineSet %s", klog.KObj(newMS))
 	}
 	log.V(4).Info("Created new MachineSet", "MachineSet", klog.KObj(newMS))
-	r.recorder.Eventf(deployment, corev1.EventTypeNormal, "SuccessfulCreate", "Created MachineSet %s", klog.KObj(newMS))
+	r.recorder.Eventf(deployment, corev1.EventTypeNormal, "SuccessfulCreate", "Created MachineSet %s: %s", klog.KObj(newMS), rolloutReason)
 
 	// Keep trying to get the MachineSet. This will force the cache to update and prevent any future reconciliation of
 	// the MachineDeployment to reconcile with an outdated list of MachineSets which could lead to unwanted creation of
@@ -243,6 +250,15 @@ func (r *Reconciler) computeDesiredMachineSet(deployment *clusterv1.MachineDeplo
 		uniqueIdentifierLabelValue = fmt.Sprintf("%d-%s", templateHash, apirand.String(5))
 
 		name = computeNewMachineSetName(deployment.Name+"-", apirand.SafeEncodeString(uniqueIdentifierLabelValue))
+		if deployment.Spec.MachineNamingStrategy != nil && deployment.Spec.MachineNamingStrategy.Template != "" {
+			name, err = naming.Generate(deployment.Spec.MachineNamingStrategy.Template, naming.Values{
+				Cluster:           deployment.Spec.ClusterName,
+				MachineDeployment: deployment.Name,
+			})
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to compute desired MachineSet: failed to compute name")
+			}
+		}
 
 		// Add foregroundDeletion finalizer to MachineSet if the MachineDeployment has it.
 		if sets.New[string](deployment.Finalizers...).Has(metav1.FinalizerDeleteDependents) {
@@ -332,6 +348,12 @@ func (r *Reconciler) computeDesiredMachineSet(deployment *clusterv1.MachineDeplo
 		return nil, errors.Wrap(err, "failed to compute desired MachineSet: failed to compute annotations")
 	}
 	desiredMS.Spec.Template.Annotations = cloneStringMap(deployment.Spec.Template.Annotations)
+	// Preserve the rollout reason on existing MachineSets, so it is propagated to all their Machines.
+	if existingMS != nil {
+		if rolloutReason, ok := existingMS.Spec.Template.Annotations[clusterv1.RolloutReasonAnnotation]; ok {
+			desiredMS.Spec.Template.Annotations[clusterv1.RolloutReasonAnnotation] = rolloutReason
+		}
+	}
 
 	// Set all other in-place mutable fields.
 	desiredMS.Spec.MinReadySeconds = pointer.Int32Deref(deployment.Spec.MinReadySeconds, 0)
@@ -343,6 +365,19 @@ func (r *Reconciler) computeDesiredMachineSet(deployment *clusterv1.MachineDeplo
 	desiredMS.Spec.Template.Spec.NodeDrainTimeout = deployment.Spec.Template.Spec.NodeDrainTimeout
 	desiredMS.Spec.Template.Spec.NodeDeletionTimeout = deployment.Spec.Template.Spec.NodeDeletionTimeout
 	desiredMS.Spec.Template.Spec.NodeVolumeDetachTimeout = deployment.Spec.Template.Spec.NodeVolumeDetachTimeout
+	desiredMS.Spec.Template.Spec.Taints = deployment.Spec.Template.Spec.Taints
+	desiredMS.Spec.Template.Spec.InfrastructureReusePolicy = deployment.Spec.Template.Spec.InfrastructureReusePolicy
+	desiredMS.Spec.Template.Spec.NodeDrainOptions = deployment.Spec.Template.Spec.NodeDrainOptions
+	desiredMS.Spec.MachineNamingStrategy = deployment.Spec.MachineNamingStrategy.DeepCopy()
+
+	// If the existing MachineSet has been matched because it references templates with the same content, update the
+	// references in-place, so the MachineSet does not depend on the previous templates anymore.
+	if existingMS != nil && !mdutil.EqualMachineTemplate(&existingMS.Spec.Template, &deployment.Spec.Template) &&
+		mdutil.EqualTemplatesChecksum(existingMS, deployment) {
+		desiredMS.Spec.Template.Spec.InfrastructureRef = deployment.Spec.Template.Spec.InfrastructureRef
+		desiredMS.Spec.Template.Spec.Bootstrap.ConfigRef = deployment.Spec.Template.Spec.Bootstrap.ConfigRef.DeepCopy()
+		desiredMS.Spec.Template.Spec.AuxiliaryInfrastructureRefs = deployment.Spec.Template.Spec.AuxiliaryInfrastructureRefs
+	}
 
 	return desiredMS, nil
 }
@@ -488,8 +523,20 @@ func (r *Reconciler) scale(ctx context.Context, deployment *clusterv1.MachineDep
 
 // syncDeploymentStatus checks if the status is up-to-date and sync it if necessary.
 func (r *Reconciler) syncDeploymentStatus(allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet, md *clusterv1.MachineDeployment) error {
+	previous := progressState(md)
 	md.Status = calculateStatus(allMSs, newMS, md)
 
+	// Track if the rollout or scale operation in progress, if any, is making progress.
+	// NOTE: Progress is not tracked while the MachineDeployment is paused.
+	md.Status.ProgressDeadline = progress.Reconcile(r.recorder, progress.Input{
+		Object:                  md,
+		ProgressDeadlineSeconds: md.Spec.ProgressDeadlineSeconds,
+		ProgressDeadline:        md.Status.ProgressDeadline,
+		Previous:                previous,
+		Current:                 progressState(md),
+		Completed:               md.Spec.Paused || mdutil.DeploymentComplete(md, &md.Status),
+	}, time.Now())
+
 	// minReplicasNeeded will be equal to md.Spec.Replicas when the strategy is not RollingUpdateMachineDeploymentStrategyType.
 	minReplicasNeeded := *(md.Spec.Replicas) - mdutil.MaxUnavailable(*md)
 
@@ -502,6 +549,18 @@ func (r *Reconciler) syncDeploymentStatus(allMSs []*clusterv1.MachineSet, newMS
 	return nil
 }
 
+// progressState returns the state of the rollout or scale operation of a MachineDeployment, as reported in its status.
+func progressState(md *clusterv1.MachineDeployment) progress.State {
+	return progress.State{
+		Generation:        md.Status.ObservedGeneration,
+		DesiredReplicas:   pointer.Int32Deref(md.Spec.Replicas, 0),
+		Replicas:          md.Status.Replicas,
+		UpdatedReplicas:   md.Status.UpdatedReplicas,
+		ReadyReplicas:     md.Status.ReadyReplicas,
+		AvailableReplicas: md.Status.AvailableReplicas,
+	}
+}
+
 // calculateStatus calculates the latest status for the provided deployment by looking into the provided MachineSets.
 func calculateStatus(allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet, deployment *clusterv1.MachineDeployment) clusterv1.MachineDeploymentStatus {
 	availableReplicas := mdutil.GetAvailableReplicaCountForMachineSets(allMSs)
@@ -526,6 +585,8 @@ func calculateStatus(allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet
 		ReadyReplicas:       mdutil.GetReadyReplicaCountForMachineSets(allMSs),
 		AvailableReplicas:   availableReplicas,
 		UnavailableReplicas: unavailableReplicas,
+		ProgressDeadline:    deployment.Status.ProgressDeadline,
+		Rollout:             aggregateRolloutStatus(allMSs),
 		Conditions:          deployment.Status.Conditions,
 	}
 
@@ -551,6 +612,39 @@ func calculateStatus(allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet
 	return status
 }
 
+// aggregateRolloutStatus aggregates the machines holding a rollout or scale operation reported by the MachineSets;
+// nil is returned if there are no such machines.
+func aggregateRolloutStatus(allMSs []*clusterv1.MachineSet) *clusterv1.MachineRolloutStatus {
+	rollout := &clusterv1.MachineRolloutStatus{}
+	for _, ms := range allMSs {
+		if ms == nil || ms.Status.Rollout == nil {
+			continue
+		}
+		rollout.PendingCreation = append(rollout.PendingCreation, ms.Status.Rollout.PendingCreation...)
+		rollout.PendingCreationCount += rolloutBlockersCount(ms.Status.Rollout.PendingCreationCount, ms.Status.Rollout.PendingCreation)
+		rollout.PendingDeletion = append(rollout.PendingDeletion, ms.Status.Rollout.PendingDeletion...)
+		rollout.PendingDeletionCount += rolloutBlockersCount(ms.Status.Rollout.PendingDeletionCount, ms.Status.Rollout.PendingDeletion)
+		rollout.FailingDrain = append(rollout.FailingDrain, ms.Status.Rollout.FailingDrain...)
+		rollout.FailingDrainCount += rolloutBlockersCount(ms.Status.Rollout.FailingDrainCount, ms.Status.Rollout.FailingDrain)
+		rollout.FailingInfrastructureProvisioning = append(rollout.FailingInfrastructureProvisioning, ms.Status.Rollout.FailingInfrastructureProvisioning...)
+		rollout.FailingInfrastructureProvisioningCount += rolloutBlockersCount(ms.Status.Rollout.FailingInfrastructureProvisioningCount, ms.Status.Rollout.FailingInfrastructureProvisioning)
+	}
+	if rollout.IsEmpty() {
+		return nil
+	}
+	mdutil.LimitRolloutBlockers(rollout)
+	return rollout
+}
+
+// rolloutBlockersCount returns the number of machines in a list of a MachineRolloutStatus reported by a MachineSet.
+// NOTE: MachineSets reconciled by a previous version of the controller don't report the count.
+func rolloutBlockersCount(count int32, blockers []clusterv1.MachineRolloutBlocker) int32 {
+	if count < int32(len(blockers)) {
+		return int32(len(blockers))
+	}
+	return count
+}
+
 func (r *Reconciler) scaleMachineSet(ctx context.Context, ms *clusterv1.MachineSet, newScale int32, deployment *clusterv1.MachineDeployment) error {
 	if ms.Spec.Replicas == nil {
 		return errors.Errorf("spec.replicas for MachineSet %v is nil, this is unexpected", client.ObjectKeyFromObject(ms))
@@ -566,8 +660,12 @@ func (r *Reconciler) scaleMachineSet(ctx context.Context, ms *clusterv1.MachineS
 		*(deployment.Spec.Replicas)+mdutil.MaxSurge(*deployment),
 	)
 
+	// Machines deleted when scaling down MachineSets from previous revisions are replaced by the rollout;
+	// record this on the MachineSet, so the MachineSet controller can propagate the deletion reason to the Machines.
+	deletionReasonNeedsUpdate := mdutil.DeletionReasonNeedsUpdate(ms, deployment)
+
 	// No need to scale nor setting annotations, return.
-	if *(ms.Spec.Replicas) == newScale && !annotationsNeedUpdate {
+	if *(ms.Spec.Replicas) == newScale && !annotationsNeedUpdate && !deletionReasonNeedsUpdate {
 		return nil
 	}
 
@@ -583,6 +681,7 @@ func (r *Reconciler) scaleMachineSet(ctx context.Context, ms *clusterv1.MachineS
 	// Mutate replicas and the related annotation.
 	ms.Spec.Replicas = &newScale
 	mdutil.SetReplicasAnnotations(ms, *(deployment.Spec.Replicas), *(deployment.Spec.Replicas)+mdutil.MaxSurge(*deployment))
+	mdutil.SetDeletionReason(ms, deployment)
 
 	if err := patchHelper.Patch(ctx, ms); err != nil {
 		r.recorder.Eventf(deployment, corev1.EventTypeWarning, "FailedScale", "Failed to scale MachineSet %v: %v",
@@ -26,6 +26,7 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	apirand "k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/tools/record"
@@ -234,6 +235,99 @@ func TestCalculateStatus(t *testing.T) {
 	}
 }
 
+func TestCalculateStatusSelector(t *testing.T) {
+	g := NewWithT(t)
+
+	deployment := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "md1",
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: "test-cluster",
+			Replicas:    pointer.Int32(1),
+			Strategy: &clusterv1.MachineDeploymentStrategy{
+				Type: clusterv1.RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{
+					MaxSurge:       intOrStrPtr(1),
+					MaxUnavailable: intOrStrPtr(0),
+				},
+			},
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					clusterv1.ClusterNameLabel:           "test-cluster",
+					clusterv1.MachineDeploymentNameLabel: "md1",
+				},
+			},
+			Template: clusterv1.MachineTemplateSpec{
+				ObjectMeta: clusterv1.ObjectMeta{
+					Labels: map[string]string{
+						clusterv1.ClusterNameLabel:           "test-cluster",
+						clusterv1.MachineDeploymentNameLabel: "md1",
+					},
+				},
+			},
+		},
+	}
+
+	newMS, err := (&Reconciler{}).computeDesiredMachineSet(deployment, nil, nil, klogr.New())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The selector reported in status, used by the scale subresource, must match the MachineSets
+	// and the Machines of the MachineDeployment.
+	status := calculateStatus([]*clusterv1.MachineSet{newMS}, newMS, deployment)
+	selector, err := labels.Parse(status.Selector)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(selector.Matches(labels.Set(newMS.Labels))).To(BeTrue())
+	g.Expect(selector.Matches(labels.Set(newMS.Spec.Template.Labels))).To(BeTrue())
+}
+
+func TestAggregateRolloutStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(aggregateRolloutStatus([]*clusterv1.MachineSet{{}, {}})).To(BeNil())
+
+	// NOTE: oldMS has been reconciled by a previous version of the controller, which doesn't report the counts.
+	oldMS := &clusterv1.MachineSet{
+		Status: clusterv1.MachineSetStatus{
+			Rollout: &clusterv1.MachineRolloutStatus{
+				PendingDeletion: []clusterv1.MachineRolloutBlocker{{Name: "old-2", Reason: clusterv1.DrainingReason}},
+				FailingDrain:    []clusterv1.MachineRolloutBlocker{{Name: "old-1", Reason: clusterv1.DrainingFailedReason}},
+			},
+		},
+	}
+	newMS := &clusterv1.MachineSet{
+		Status: clusterv1.MachineSetStatus{
+			Rollout: &clusterv1.MachineRolloutStatus{
+				PendingCreation:      []clusterv1.MachineRolloutBlocker{{Name: "new-2"}, {Name: "new-1"}},
+				PendingCreationCount: 2,
+			},
+		},
+	}
+	g.Expect(aggregateRolloutStatus([]*clusterv1.MachineSet{newMS, {}, oldMS})).To(Equal(&clusterv1.MachineRolloutStatus{
+		PendingCreation:      []clusterv1.MachineRolloutBlocker{{Name: "new-1"}, {Name: "new-2"}},
+		PendingCreationCount: 2,
+		PendingDeletion:      []clusterv1.MachineRolloutBlocker{{Name: "old-2", Reason: clusterv1.DrainingReason}},
+		PendingDeletionCount: 1,
+		FailingDrain:         []clusterv1.MachineRolloutBlocker{{Name: "old-1", Reason: clusterv1.DrainingFailedReason}},
+		FailingDrainCount:    1,
+	}))
+
+	// The MachineDeployment reports the total number of machines, but at most MaxRolloutBlockers machines.
+	var blockers1, blockers2 []clusterv1.MachineRolloutBlocker
+	for i := 0; i < 8; i++ {
+		blockers1 = append(blockers1, clusterv1.MachineRolloutBlocker{Name: fmt.Sprintf("ms1-%d", i)})
+		blockers2 = append(blockers2, clusterv1.MachineRolloutBlocker{Name: fmt.Sprintf("ms2-%d", i)})
+	}
+	ms1 := &clusterv1.MachineSet{Status: clusterv1.MachineSetStatus{Rollout: &clusterv1.MachineRolloutStatus{FailingDrain: blockers1, FailingDrainCount: 12}}}
+	ms2 := &clusterv1.MachineSet{Status: clusterv1.MachineSetStatus{Rollout: &clusterv1.MachineRolloutStatus{FailingDrain: blockers2, FailingDrainCount: 8}}}
+	got := aggregateRolloutStatus([]*clusterv1.MachineSet{ms2, ms1})
+	g.Expect(got.FailingDrainCount).To(Equal(int32(20)))
+	g.Expect(got.FailingDrain).To(HaveLen(mdutil.MaxRolloutBlockers))
+	g.Expect(got.FailingDrain[0].Name).To(Equal("ms1-0"))
+	g.Expect(got.FailingDrain[9].Name).To(Equal("ms2-1"))
+}
+
 func TestScaleMachineSet(t *testing.T) {
 	testCases := []struct {
 		name              string
@@ -594,6 +688,22 @@ func TestComputeDesiredMachineSet(t *testing.T) {
 		assertMachineSet(g, actualMS, expectedMS)
 	})
 
+	t.Run("should compute a new MachineSet using the MachineNamingStrategy", func(t *testing.T) {
+		deployment := deployment.DeepCopy()
+		deployment.Spec.MachineNamingStrategy = &clusterv1.MachineNamingStrategy{
+			Template: "{{ .cluster }}-{{ .machineDeployment }}-{{ .random }}",
+		}
+
+		expectedMS := skeletonMSBasedOnMD.DeepCopy()
+		expectedMS.Spec.MachineNamingStrategy = deployment.Spec.MachineNamingStrategy.DeepCopy()
+
+		g := NewWithT(t)
+		actualMS, err := (&Reconciler{}).computeDesiredMachineSet(deployment, nil, nil, log)
+		g.Expect(err).To(BeNil())
+		assertMachineSet(g, actualMS, expectedMS)
+		g.Expect(actualMS.Name).To(MatchRegexp("^test-cluster-md1-[a-z0-9]{5}$"))
+	})
+
 	t.Run("should compute the updated MachineSet when no old MachineSets exists", func(t *testing.T) {
 		uniqueID := apirand.String(5)
 		existingMS := skeletonMSBasedOnMD.DeepCopy()
@@ -672,6 +782,42 @@ func TestComputeDesiredMachineSet(t *testing.T) {
 		assertMachineSet(g, actualMS, expectedMS)
 	})
 
+	t.Run("should update the template references of a MachineSet referencing templates with the same content", func(t *testing.T) {
+		deployment := deployment.DeepCopy()
+		deployment.Annotations[clusterv1.TemplatesChecksumAnnotation] = "checksum"
+
+		uniqueID := apirand.String(5)
+		existingMS := skeletonMSBasedOnMD.DeepCopy()
+		existingMS.UID = types.UID("abc-123-uid")
+		existingMS.Name = deployment.Name + "-" + uniqueID
+		existingMS.Labels = map[string]string{clusterv1.MachineDeploymentUniqueLabel: uniqueID}
+		existingMS.Annotations = map[string]string{clusterv1.TemplatesChecksumAnnotation: "checksum"}
+		existingMS.Spec.Template.Labels = map[string]string{clusterv1.MachineDeploymentUniqueLabel: uniqueID}
+		existingMS.Spec.Template.Spec.InfrastructureRef.Name = "infra-template-0"
+		existingMS.Spec.Template.Spec.Bootstrap.ConfigRef.Name = "bootstrap-template-0"
+
+		expectedMS := skeletonMSBasedOnMD.DeepCopy()
+		expectedMS.UID = existingMS.UID
+		expectedMS.Name = existingMS.Name
+		expectedMS.Annotations[clusterv1.TemplatesChecksumAnnotation] = "checksum"
+		expectedMS.Labels[clusterv1.MachineDeploymentUniqueLabel] = uniqueID
+		expectedMS.Spec.Template.Labels[clusterv1.MachineDeploymentUniqueLabel] = uniqueID
+
+		g := NewWithT(t)
+		actualMS, err := (&Reconciler{}).computeDesiredMachineSet(deployment, existingMS, nil, log)
+		g.Expect(err).To(BeNil())
+		assertMachineSet(g, actualMS, expectedMS)
+		g.Expect(actualMS.Spec.Template.Spec.InfrastructureRef.Name).To(Equal("infra-template-1"))
+		g.Expect(actualMS.Spec.Template.Spec.Bootstrap.ConfigRef.Name).To(Equal("bootstrap-template-1"))
+
+		// Template references are not updated if the content of the templates is different.
+		existingMS.Annotations[clusterv1.TemplatesChecksumAnnotation] = "another-checksum"
+		actualMS, err = (&Reconciler{}).computeDesiredMachineSet(deployment, existingMS, nil, log)
+		g.Expect(err).To(BeNil())
+		g.Expect(actualMS.Spec.Template.Spec.InfrastructureRef.Name).To(Equal("infra-template-0"))
+		g.Expect(actualMS.Spec.Template.Spec.Bootstrap.ConfigRef.Name).To(Equal("bootstrap-template-0"))
+	})
+
 	t.Run("should compute the updated MachineSet when no old MachineSets exists (", func(t *testing.T) {
 		// Set rollout strategy to "OnDelete".
 		deployment := deployment.DeepCopy()
@@ -764,6 +910,9 @@ func assertMachineSet(g *WithT, actualMS *clusterv1.MachineSet, expectedMS *clus
 	// Check DeletePolicy
 	g.Expect(actualMS.Spec.DeletePolicy).Should(Equal(expectedMS.Spec.DeletePolicy))
 
+	// Check MachineNamingStrategy
+	g.Expect(actualMS.Spec.MachineNamingStrategy).Should(Equal(expectedMS.Spec.MachineNamingStrategy))
+
 	// Check MachineTemplateSpec
 	g.Expect(actualMS.Spec.Template.Spec).Should(Equal(expectedMS.Spec.Template.Spec))
 }
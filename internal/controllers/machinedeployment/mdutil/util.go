@@ -35,6 +35,7 @@ import (
 	"k8s.io/utils/integer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/deletion"
 	"sigs.k8s.io/cluster-api/util/conversion"
 )
 
@@ -128,6 +129,24 @@ func MaxRevision(allMSs []*clusterv1.MachineSet, logger logr.Logger) int64 {
 	return max
 }
 
+// LastRevision finds the second max revision number in all machine sets (the last revision).
+func LastRevision(allMSs []*clusterv1.MachineSet, logger logr.Logger) int64 {
+	max, secMax := int64(0), int64(0)
+	for _, ms := range allMSs {
+		if v, err := Revision(ms); err != nil {
+			// Skip the machine sets when it failed to parse their revision information
+			logger.Error(err, "Couldn't parse revision for machine set, deployment controller will skip it when reconciling revisions",
+				"machineset", ms.Name)
+		} else if v >= max {
+			secMax = max
+			max = v
+		} else if v > secMax {
+			secMax = v
+		}
+	}
+	return secMax
+}
+
 // Revision returns the revision number of the input object.
 func Revision(obj runtime.Object) (int64, error) {
 	acc, err := meta.Accessor(obj)
@@ -148,6 +167,14 @@ var annotationsToSkip = map[string]bool{
 	clusterv1.DesiredReplicasAnnotation: true,
 	clusterv1.MaxReplicasAnnotation:     true,
 
+	// Exclude the rollout reason annotation, which is recorded only on the MachineSet created by the
+	// rollout it describes; copying it to all the MachineSets would override the reason of previous rollouts.
+	clusterv1.RolloutReasonAnnotation: true,
+
+	// Exclude the released provider IDs annotation, which is used by the MachineSet controller to track the infrastructure
+	// hosts that can be reused when the node reuse policy is enabled.
+	clusterv1.ReleasedProviderIDsAnnotation: true,
+
 	// Exclude the conversion annotation, to avoid infinite loops between the conversion webhook
 	// and the MachineDeployment controller syncing the annotations between a MachineDeployment
 	// and its linked MachineSets.
@@ -221,6 +248,11 @@ func ComputeMachineSetAnnotations(log logr.Logger, deployment *clusterv1.Machine
 			annotations[clusterv1.RevisionHistoryAnnotation] = revisionHistory
 		}
 
+		// Ensure we preserve the rollout reason annotation, which is set only when the MachineSet is created.
+		if rolloutReason, ok := newMS.Annotations[clusterv1.RolloutReasonAnnotation]; ok {
+			annotations[clusterv1.RolloutReasonAnnotation] = rolloutReason
+		}
+
 		// If the revision changes then add the old revision to the revision history annotation
 		if currentRevisionExists && currentRevision != newRevision {
 			oldRevisions := strings.Split(revisionHistory, ",")
@@ -296,6 +328,34 @@ func ReplicasAnnotationsNeedUpdate(ms *clusterv1.MachineSet, desiredReplicas, ma
 	return false
 }
 
+// isOldMachineSet returns true if the MachineSet belongs to a previous revision of the MachineDeployment.
+func isOldMachineSet(ms *clusterv1.MachineSet, deployment *clusterv1.MachineDeployment) bool {
+	msRevision, err := Revision(ms)
+	if err != nil {
+		return false
+	}
+	deploymentRevision, err := Revision(deployment)
+	if err != nil {
+		return false
+	}
+	return msRevision < deploymentRevision
+}
+
+// SetDeletionReason records on MachineSets from previous revisions that their Machines are deleted because of
+// a rollout, and removes the deletion reason from the other MachineSets, e.g. a MachineSet reused by a rollback.
+// It returns true if the annotations are modified, false otherwise.
+func SetDeletionReason(ms *clusterv1.MachineSet, deployment *clusterv1.MachineDeployment) bool {
+	if isOldMachineSet(ms, deployment) {
+		return deletion.Set(ms, clusterv1.DeletionReasonRollout, deletion.Initiator("MachineDeployment", deployment))
+	}
+	return deletion.Remove(ms)
+}
+
+// DeletionReasonNeedsUpdate returns true if the deletion reason recorded on the MachineSet needs to be updated.
+func DeletionReasonNeedsUpdate(ms *clusterv1.MachineSet, deployment *clusterv1.MachineDeployment) bool {
+	return SetDeletionReason(ms.DeepCopy(), deployment)
+}
+
 // MaxUnavailable returns the maximum unavailable machines a rolling deployment can take.
 func MaxUnavailable(deployment clusterv1.MachineDeployment) int32 {
 	if !IsRollingUpdate(&deployment) || *(deployment.Spec.Replicas) == 0 {
@@ -390,12 +450,24 @@ func MachineTemplateDeepCopyRolloutFields(template *clusterv1.MachineTemplateSpe
 	templateCopy.Spec.NodeDeletionTimeout = nil
 	templateCopy.Spec.NodeVolumeDetachTimeout = nil
 
+	// Drop node taints
+	templateCopy.Spec.Taints = nil
+
+	// Drop the infrastructure reuse policy
+	templateCopy.Spec.InfrastructureReusePolicy = ""
+
+	// Drop the node drain options
+	templateCopy.Spec.NodeDrainOptions = nil
+
 	// Remove the version part from the references APIVersion field,
 	// for more details see issue #2183 and #2140.
 	templateCopy.Spec.InfrastructureRef.APIVersion = templateCopy.Spec.InfrastructureRef.GroupVersionKind().Group
 	if templateCopy.Spec.Bootstrap.ConfigRef != nil {
 		templateCopy.Spec.Bootstrap.ConfigRef.APIVersion = templateCopy.Spec.Bootstrap.ConfigRef.GroupVersionKind().Group
 	}
+	for i := range templateCopy.Spec.AuxiliaryInfrastructureRefs {
+		templateCopy.Spec.AuxiliaryInfrastructureRefs[i].APIVersion = templateCopy.Spec.AuxiliaryInfrastructureRefs[i].GroupVersionKind().Group
+	}
 
 	return templateCopy
 }
@@ -424,10 +496,38 @@ func FindNewMachineSet(deployment *clusterv1.MachineDeployment, msList []*cluste
 			return msList[i]
 		}
 	}
+	// If no MachineSet has the same machine template, look for a MachineSet referencing templates with the same content,
+	// so that e.g. re-creating an identical template under a new name does not trigger a rollout.
+	for i := range msList {
+		if EqualTemplatesChecksum(msList[i], deployment) &&
+			!shouldRolloutAfter(msList[i], reconciliationTime, deployment.Spec.RolloutAfter) {
+			return msList[i]
+		}
+	}
 	// new MachineSet does not exist.
 	return nil
 }
 
+// EqualTemplatesChecksum returns true if the MachineSet and the MachineDeployment have the same machine template, ignoring
+// the names of the referenced templates, and the referenced templates have the same content according to the
+// TemplatesChecksumAnnotation.
+func EqualTemplatesChecksum(ms *clusterv1.MachineSet, deployment *clusterv1.MachineDeployment) bool {
+	checksum, ok := deployment.Annotations[clusterv1.TemplatesChecksumAnnotation]
+	if !ok || checksum == "" || ms.Annotations[clusterv1.TemplatesChecksumAnnotation] != checksum {
+		return false
+	}
+
+	t1Copy := MachineTemplateDeepCopyRolloutFields(&ms.Spec.Template)
+	t2Copy := MachineTemplateDeepCopyRolloutFields(&deployment.Spec.Template)
+	for _, t := range []*clusterv1.MachineTemplateSpec{t1Copy, t2Copy} {
+		t.Spec.InfrastructureRef.Name = ""
+		if t.Spec.Bootstrap.ConfigRef != nil {
+			t.Spec.Bootstrap.ConfigRef.Name = ""
+		}
+	}
+	return apiequality.Semantic.DeepEqual(t1Copy, t2Copy)
+}
+
 func shouldRolloutAfter(ms *clusterv1.MachineSet, reconciliationTime *metav1.Time, rolloutAfter *metav1.Time) bool {
 	if ms == nil {
 		return false
@@ -704,3 +804,35 @@ func GetDeletingMachineCount(machineList *clusterv1.MachineList) int32 {
 	}
 	return deletingMachineCount
 }
+
+const (
+	// MaxRolloutBlockers is the maximum number of machines reported in each list of a MachineRolloutStatus.
+	MaxRolloutBlockers = 10
+
+	// MaxRolloutBlockerMessageLength is the maximum length of the message of a MachineRolloutBlocker.
+	MaxRolloutBlockerMessageLength = 256
+)
+
+// LimitRolloutBlockers sorts the machines in each list of a MachineRolloutStatus by name, so the status is stable
+// across reconciles, and then keeps only the first MaxRolloutBlockers machines of each list, truncating their messages
+// to MaxRolloutBlockerMessageLength characters, so the status does not grow with the size of the MachineDeployment.
+// NOTE: The total number of machines in each list must be recorded in the corresponding count field before calling this func.
+func LimitRolloutBlockers(rollout *clusterv1.MachineRolloutStatus) {
+	rollout.PendingCreation = limitRolloutBlockers(rollout.PendingCreation)
+	rollout.PendingDeletion = limitRolloutBlockers(rollout.PendingDeletion)
+	rollout.FailingDrain = limitRolloutBlockers(rollout.FailingDrain)
+	rollout.FailingInfrastructureProvisioning = limitRolloutBlockers(rollout.FailingInfrastructureProvisioning)
+}
+
+func limitRolloutBlockers(blockers []clusterv1.MachineRolloutBlocker) []clusterv1.MachineRolloutBlocker {
+	sort.Slice(blockers, func(i, j int) bool { return blockers[i].Name < blockers[j].Name })
+	if len(blockers) > MaxRolloutBlockers {
+		blockers = blockers[:MaxRolloutBlockers]
+	}
+	for i := range blockers {
+		if len(blockers[i].Message) > MaxRolloutBlockerMessageLength {
+			blockers[i].Message = blockers[i].Message[:MaxRolloutBlockerMessageLength-3] + "..."
+		}
+	}
+	return blockers
+}
@@ -21,6 +21,7 @@ import (
 	"math/rand"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -210,6 +211,9 @@ func TestEqualMachineTemplate(t *testing.T) {
 	machineTemplateWithDifferentInPlaceMutableSpecFields.Spec.NodeDrainTimeout = &metav1.Duration{Duration: 20 * time.Second}
 	machineTemplateWithDifferentInPlaceMutableSpecFields.Spec.NodeDeletionTimeout = &metav1.Duration{Duration: 20 * time.Second}
 	machineTemplateWithDifferentInPlaceMutableSpecFields.Spec.NodeVolumeDetachTimeout = &metav1.Duration{Duration: 20 * time.Second}
+	machineTemplateWithDifferentInPlaceMutableSpecFields.Spec.Taints = []corev1.Taint{{Key: "example.com/gpu", Effect: corev1.TaintEffectNoSchedule}}
+	machineTemplateWithDifferentInPlaceMutableSpecFields.Spec.InfrastructureReusePolicy = clusterv1.InfrastructureReusePolicyRelease
+	machineTemplateWithDifferentInPlaceMutableSpecFields.Spec.NodeDrainOptions = &clusterv1.NodeDrainOptions{DisableEviction: true}
 
 	machineTemplateWithDifferentInfraRef := machineTemplate.DeepCopy()
 	machineTemplateWithDifferentInfraRef.Spec.InfrastructureRef.Name = "infra2"
@@ -330,6 +334,21 @@ func TestFindNewMachineSet(t *testing.T) {
 	msCreatedAfterRolloutAfter := generateMS(deployment)
 	msCreatedAfterRolloutAfter.CreationTimestamp = oneAfterRolloutAfter
 
+	deploymentWithChecksum := *deployment.DeepCopy()
+	deploymentWithChecksum.Annotations = map[string]string{clusterv1.TemplatesChecksumAnnotation: "checksum"}
+
+	msSameTemplatesChecksum := generateMS(deployment)
+	msSameTemplatesChecksum.Spec.Template.Spec.InfrastructureRef.Name = "recreated-infra-ref"
+	msSameTemplatesChecksum.Annotations = map[string]string{clusterv1.TemplatesChecksumAnnotation: "checksum"}
+
+	msDifferentTemplatesChecksum := generateMS(deployment)
+	msDifferentTemplatesChecksum.Spec.Template.Spec.InfrastructureRef.Name = "changed-infra-ref"
+	msDifferentTemplatesChecksum.Annotations = map[string]string{clusterv1.TemplatesChecksumAnnotation: "another-checksum"}
+
+	msSameTemplatesChecksumDifferentKind := generateMS(deployment)
+	msSameTemplatesChecksumDifferentKind.Spec.Template.Spec.InfrastructureRef.Kind = "AnotherInfrastructureMachineTemplate"
+	msSameTemplatesChecksumDifferentKind.Annotations = map[string]string{clusterv1.TemplatesChecksumAnnotation: "checksum"}
+
 	tests := []struct {
 		Name               string
 		deployment         clusterv1.MachineDeployment
@@ -337,6 +356,30 @@ func TestFindNewMachineSet(t *testing.T) {
 		reconciliationTime *metav1.Time
 		expected           *clusterv1.MachineSet
 	}{
+		{
+			Name:       "Get the MachineSet referencing templates with the same content as the MachineDeployment",
+			deployment: deploymentWithChecksum,
+			msList:     []*clusterv1.MachineSet{&msDifferentTemplatesChecksum, &msSameTemplatesChecksum},
+			expected:   &msSameTemplatesChecksum,
+		},
+		{
+			Name:       "Prefer the MachineSet with the same MachineTemplate over the MachineSet referencing templates with the same content",
+			deployment: deploymentWithChecksum,
+			msList:     []*clusterv1.MachineSet{&msSameTemplatesChecksum, &matchingMS},
+			expected:   &matchingMS,
+		},
+		{
+			Name:       "Get nil if the MachineSet references templates with the same content but the MachineDeployment has no checksum",
+			deployment: deployment,
+			msList:     []*clusterv1.MachineSet{&msSameTemplatesChecksum},
+			expected:   nil,
+		},
+		{
+			Name:       "Get nil if the MachineSet has the same checksum but references templates of another kind",
+			deployment: deploymentWithChecksum,
+			msList:     []*clusterv1.MachineSet{&msSameTemplatesChecksumDifferentKind},
+			expected:   nil,
+		},
 		{
 			Name:       "Get the MachineSet with the MachineTemplate that matches the intent of the MachineDeployment",
 			deployment: deployment,
@@ -837,6 +880,12 @@ func TestComputeMachineSetAnnotations(t *testing.T) {
 		"key1":                             "value1",
 	}
 
+	deploymentWithRolloutReason := deployment.DeepCopy()
+	deploymentWithRolloutReason.Annotations[clusterv1.RolloutReasonAnnotation] = "new reason"
+
+	machineSetWithRolloutReason := machineSetWithRevisionAndHistory("1", "")
+	machineSetWithRolloutReason.Annotations[clusterv1.RolloutReasonAnnotation] = "old reason"
+
 	tests := []struct {
 		name       string
 		deployment *clusterv1.MachineDeployment
@@ -934,6 +983,20 @@ func TestComputeMachineSetAnnotations(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:       "Calculating annotations for a existing MachineSet - rollout reason is preserved and not copied from the MachineDeployment",
+			deployment: deploymentWithRolloutReason,
+			oldMSs:     nil,
+			ms:         machineSetWithRolloutReason,
+			want: map[string]string{
+				"key1":                              "value1",
+				clusterv1.RevisionAnnotation:        "1",
+				clusterv1.RolloutReasonAnnotation:   "old reason",
+				clusterv1.DesiredReplicasAnnotation: "3",
+				clusterv1.MaxReplicasAnnotation:     "4",
+			},
+			wantErr: false,
+		},
 	}
 
 	log := klogr.New()
@@ -1036,3 +1099,97 @@ func TestReplicasAnnotationsNeedUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestSetDeletionReason(t *testing.T) {
+	deployment := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "md",
+			Namespace:   metav1.NamespaceDefault,
+			Annotations: map[string]string{clusterv1.RevisionAnnotation: "2"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		revision       string
+		annotations    map[string]string
+		expectModified bool
+		expectReason   string
+	}{
+		{
+			name:           "old MachineSet gets the rollout reason",
+			revision:       "1",
+			expectModified: true,
+			expectReason:   clusterv1.DeletionReasonRollout,
+		},
+		{
+			name:     "old MachineSet with the rollout reason is not modified",
+			revision: "1",
+			annotations: map[string]string{
+				clusterv1.DeletionReasonAnnotation:    clusterv1.DeletionReasonRollout,
+				clusterv1.DeletionInitiatorAnnotation: "MachineDeployment/md",
+			},
+			expectModified: false,
+			expectReason:   clusterv1.DeletionReasonRollout,
+		},
+		{
+			name:     "new MachineSet gets the deletion reason removed",
+			revision: "2",
+			annotations: map[string]string{
+				clusterv1.DeletionReasonAnnotation:    clusterv1.DeletionReasonRollout,
+				clusterv1.DeletionInitiatorAnnotation: "MachineDeployment/md",
+			},
+			expectModified: true,
+			expectReason:   "",
+		},
+		{
+			name:           "new MachineSet without deletion reason is not modified",
+			revision:       "2",
+			expectModified: false,
+			expectReason:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ms := &clusterv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ms",
+					Namespace:   metav1.NamespaceDefault,
+					Annotations: map[string]string{clusterv1.RevisionAnnotation: tt.revision},
+				},
+			}
+			for k, v := range tt.annotations {
+				ms.Annotations[k] = v
+			}
+
+			g.Expect(DeletionReasonNeedsUpdate(ms, deployment)).To(Equal(tt.expectModified))
+			g.Expect(SetDeletionReason(ms, deployment)).To(Equal(tt.expectModified))
+			g.Expect(ms.Annotations[clusterv1.DeletionReasonAnnotation]).To(Equal(tt.expectReason))
+		})
+	}
+}
+
+func TestLimitRolloutBlockers(t *testing.T) {
+	g := NewWithT(t)
+
+	rollout := &clusterv1.MachineRolloutStatus{
+		PendingCreation: []clusterv1.MachineRolloutBlocker{{Name: "m2"}, {Name: "m1", Message: "short message"}},
+	}
+	for i := MaxRolloutBlockers + 5; i > 0; i-- {
+		rollout.FailingDrain = append(rollout.FailingDrain, clusterv1.MachineRolloutBlocker{Name: fmt.Sprintf("m%02d", i), Message: strings.Repeat("x", MaxRolloutBlockerMessageLength+1)})
+	}
+
+	LimitRolloutBlockers(rollout)
+
+	g.Expect(rollout.PendingCreation).To(Equal([]clusterv1.MachineRolloutBlocker{{Name: "m1", Message: "short message"}, {Name: "m2"}}))
+	g.Expect(rollout.FailingDrain).To(HaveLen(MaxRolloutBlockers))
+	g.Expect(rollout.FailingDrain[0].Name).To(Equal("m01"))
+	g.Expect(rollout.FailingDrain[MaxRolloutBlockers-1].Name).To(Equal("m10"))
+	for _, b := range rollout.FailingDrain {
+		g.Expect(b.Message).To(HaveLen(MaxRolloutBlockerMessageLength))
+		g.Expect(b.Message).To(HaveSuffix("..."))
+	}
+}
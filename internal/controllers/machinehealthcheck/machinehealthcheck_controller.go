@@ -52,6 +52,7 @@ import (
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/paused"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
 
@@ -83,6 +84,8 @@ type Reconciler struct {
 
 	controller controller.Controller
 	recorder   record.EventRecorder
+
+	unhealthyNodeExpressions unhealthyNodeExpressionsCache
 }
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -100,7 +103,10 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 			builder.WithPredicates(
 				// TODO: should this wait for Cluster.Status.InfrastructureReady similar to Infra Machine resources?
 				predicates.All(ctrl.LoggerFrom(ctx),
-					predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+					predicates.Any(ctrl.LoggerFrom(ctx),
+						predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+						predicates.ClusterPauseChanged(ctrl.LoggerFrom(ctx)),
+					),
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			),
@@ -123,6 +129,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		if apierrors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			r.unhealthyNodeExpressions.delete(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 
@@ -141,8 +148,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	}
 
 	// Return early if the object or Cluster is paused.
-	if annotations.IsPaused(cluster, m) {
+	isPaused, requeueAfter, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, m)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if isPaused {
 		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	// Return early if the object is being moved to another management cluster, so remediation is not triggered
+	// while Machines and Nodes are not reconciled.
+	if _, ok := m.Annotations[clusterv1.PreMoveHookAnnotation]; ok {
+		log.Info("Reconciliation is paused while the object is being moved")
 		return ctrl.Result{}, nil
 	}
 
@@ -228,6 +246,7 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 	}
 	// do sort to avoid keep changing m.Status as the returned machines are not in order
 	sort.Strings(m.Status.Targets)
+	m.Status.DryRunRemediationTargets = nil
 
 	nodeStartupTimeout := m.Spec.NodeStartupTimeout
 	if nodeStartupTimeout == nil {
@@ -320,7 +339,24 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 	m.Status.RemediationsAllowed = remediationCount
 	conditions.MarkTrue(m, clusterv1.RemediationAllowedCondition)
 
-	errList := r.patchUnhealthyTargets(ctx, logger, unhealthy, cluster, m)
+	errList := []error{}
+	switch {
+	case m.Spec.Mode == clusterv1.MachineHealthCheckDryRunMode:
+		// In DryRun mode unhealthy machines are only reported, without requesting reboots or remediations.
+		errList = append(errList, r.reportUnhealthyTargets(ctx, logger, unhealthy, cluster, m)...)
+	case m.Spec.Reboot != nil:
+		// Request a reboot of unhealthy machines first, and remediate only the ones which
+		// didn't become healthy within the reboot timeout.
+		var rebootNextCheckTimes []time.Duration
+		var rebootErrList []error
+		unhealthy, rebootNextCheckTimes, rebootErrList = r.reconcileReboot(ctx, logger, unhealthy, cluster, m)
+		nextCheckTimes = append(nextCheckTimes, rebootNextCheckTimes...)
+		errList = append(errList, rebootErrList...)
+		errList = append(errList, r.clearRebootRequests(ctx, logger, healthy)...)
+		errList = append(errList, r.patchUnhealthyTargets(ctx, logger, unhealthy, cluster, m)...)
+	default:
+		errList = append(errList, r.patchUnhealthyTargets(ctx, logger, unhealthy, cluster, m)...)
+	}
 	errList = append(errList, r.patchHealthyTargets(ctx, logger, healthy, m)...)
 
 	// handle update errors
@@ -329,6 +365,16 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 		return reconcile.Result{}, kerrors.NewAggregate(errList)
 	}
 
+	// Unhealthy node expressions can depend on the evaluation time, so they are evaluated again periodically.
+	if len(m.Spec.UnhealthyNodeExpressions) > 0 {
+		nextCheckTimes = append(nextCheckTimes, unhealthyNodeExpressionsCheckInterval)
+	}
+
+	// External remediation requests report their result in their status, so they are checked again periodically.
+	if m.Spec.RemediationTemplate != nil && m.Spec.Mode != clusterv1.MachineHealthCheckDryRunMode && len(unhealthy) > 0 {
+		nextCheckTimes = append(nextCheckTimes, externalRemediationRequestCheckInterval)
+	}
+
 	if minNextCheck := minDuration(nextCheckTimes); minNextCheck > 0 {
 		logger.V(3).Info("Some targets might go unhealthy. Ensuring a requeue happens", "requeueIn", minNextCheck.Truncate(time.Second).String())
 		return ctrl.Result{RequeueAfter: minNextCheck}, nil
@@ -350,17 +396,19 @@ func (r *Reconciler) patchHealthyTargets(ctx context.Context, logger logr.Logger
 				if !apierrors.IsNotFound(errors.Cause(err)) {
 					wrappedErr := errors.Wrapf(err, "failed to fetch remediation request for machine %q in namespace %q within cluster %q", t.Machine.Name, t.Machine.Namespace, t.Machine.Spec.ClusterName)
 					errList = append(errList, wrappedErr)
+					continue
 				}
-				continue
-			}
-			// Check that obj has no DeletionTimestamp to avoid hot loop
-			if obj.GetDeletionTimestamp() == nil {
-				// Issue a delete for remediation request.
+			} else if obj.GetDeletionTimestamp() == nil {
+				// Issue a delete for remediation request; obj is checked to have no DeletionTimestamp to avoid hot loop.
 				if err := r.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
 					errList = append(errList, errors.Wrapf(err, "failed to delete %v %q for Machine %q", obj.GroupVersionKind(), obj.GetName(), t.Machine.Name))
 					continue
 				}
 			}
+			if conditions.Has(t.Machine, clusterv1.MachineExternallyRemediatedCondition) {
+				conditions.MarkTrue(t.Machine, clusterv1.MachineExternallyRemediatedCondition)
+			}
+			delete(t.Machine.Annotations, clusterv1.ExternalRemediationRetriesAnnotation)
 		}
 
 		if err := t.patchHelper.Patch(ctx, t.Machine); err != nil {
@@ -383,9 +431,12 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 		} else {
 			if m.Spec.RemediationTemplate != nil {
 				// If external remediation request already exists,
-				// return early
-				if r.externalRemediationRequestExists(ctx, m, t.Machine.Name) {
-					return errList
+				// consume the result it reports.
+				if obj, err := r.getExternalRemediationRequest(ctx, m, t.Machine.Name); err == nil {
+					if err := r.reconcileExternalRemediationRequest(ctx, logger, t, obj); err != nil {
+						errList = append(errList, err)
+					}
+					continue
 				}
 
 				cloneOwnerRef := &metav1.OwnerReference{
@@ -430,6 +481,7 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 					errList = append(errList, errors.Wrapf(err, "error creating remediation request for machine %q in namespace %q within cluster %q", t.Machine.Name, t.Machine.Namespace, t.Machine.Spec.ClusterName))
 					return errList
 				}
+				conditions.MarkFalse(t.Machine, clusterv1.MachineExternallyRemediatedCondition, clusterv1.WaitingForExternalRemediationReason, clusterv1.ConditionSeverityWarning, "")
 			} else {
 				logger.Info("Target has failed health check, marking for remediation", "target", t.string(), "reason", condition.Reason, "message", condition.Message)
 				// NOTE: MHC is responsible for creating MachineOwnerRemediatedCondition if missing or to trigger another remediation if the previous one is completed;
@@ -455,6 +507,38 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 	return errList
 }
 
+// reportUnhealthyTargets patches unhealthy machines with the MachineHealthCheckSucceededCondition and reports
+// them as the machines that would have been remediated, without marking them for remediation; it is used
+// for MachineHealthChecks in DryRun mode.
+func (r *Reconciler) reportUnhealthyTargets(ctx context.Context, logger logr.Logger, unhealthy []healthCheckTarget, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck) []error {
+	errList := []error{}
+	for _, t := range unhealthy {
+		if err := t.patchHelper.Patch(ctx, t.Machine); err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to patch unhealthy machine status for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
+			continue
+		}
+
+		// Paused machines would not have been remediated.
+		if annotations.IsPaused(cluster, t.Machine) {
+			continue
+		}
+
+		condition := conditions.Get(t.Machine, clusterv1.MachineHealthCheckSucceededCondition)
+		logger.Info("Target has failed health check, but MachineHealthCheck is in DryRun mode so skipping remediation", "target", t.string(), "reason", condition.Reason, "message", condition.Message)
+		m.Status.DryRunRemediationTargets = append(m.Status.DryRunRemediationTargets, t.Machine.Name)
+		r.recorder.Eventf(
+			t.Machine,
+			corev1.EventTypeNormal,
+			EventMachineWouldBeRemediated,
+			"Machine %v has failed health check and it would have been remediated, but MachineHealthCheck %v is in DryRun mode",
+			t.string(),
+			m.Name,
+		)
+	}
+	sort.Strings(m.Status.DryRunRemediationTargets)
+	return errList
+}
+
 // clusterToMachineHealthCheck maps events from Cluster objects to
 // MachineHealthCheck objects that belong to the Cluster.
 func (r *Reconciler) clusterToMachineHealthCheck(o client.Object) []reconcile.Request {
@@ -657,13 +741,3 @@ func (r *Reconciler) getExternalRemediationRequest(ctx context.Context, m *clust
 	}
 	return remediationReq, nil
 }
-
-// externalRemediationRequestExists checks if the External Remediation Request is created
-// for the machine.
-func (r *Reconciler) externalRemediationRequestExists(ctx context.Context, m *clusterv1.MachineHealthCheck, machineName string) bool {
-	remediationReq, err := r.getExternalRemediationRequest(ctx, m, machineName)
-	if err != nil {
-		return false
-	}
-	return remediationReq != nil
-}
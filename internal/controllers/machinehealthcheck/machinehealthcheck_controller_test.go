@@ -1248,7 +1248,6 @@ func TestMachineHealthCheck_Reconcile(t *testing.T) {
 				},
 			},
 		}
-		machineSet.Default()
 		g.Expect(env.Create(ctx, machineSet)).To(Succeed())
 
 		// Ensure machines have been created.
@@ -2628,3 +2627,62 @@ func TestPatchTargets(t *testing.T) {
 	// Target with wrong patch helper will fail but the other one will be patched.
 	g.Expect(r.patchHealthyTargets(context.TODO(), logr.New(log.NullLogSink{}), []healthCheckTarget{target1, target3}, mhc)).ToNot(BeEmpty())
 }
+
+func TestReportUnhealthyTargets(t *testing.T) {
+	g := NewWithT(t)
+
+	namespace := metav1.NamespaceDefault
+	clusterName := testClusterName
+	defaultCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterName,
+			Namespace: namespace,
+		},
+	}
+	labels := map[string]string{"cluster": "foo", "nodepool": "bar"}
+
+	mhc := newMachineHealthCheckWithLabels("mhc", namespace, clusterName, labels)
+	mhc.Spec.Mode = clusterv1.MachineHealthCheckDryRunMode
+	machine1 := newTestMachine("machine1", namespace, clusterName, "nodeName", labels)
+	machine1.ResourceVersion = "999"
+	machine2 := machine1.DeepCopy()
+	machine2.Name = "machine2"
+	machine2.Annotations = map[string]string{clusterv1.PausedAnnotation: ""}
+
+	cl := fake.NewClientBuilder().WithObjects(
+		machine1,
+		machine2,
+		mhc,
+	).Build()
+	recorder := record.NewFakeRecorder(32)
+	r := &Reconciler{
+		Client:   cl,
+		recorder: recorder,
+	}
+
+	var targets []healthCheckTarget
+	for _, m := range []*clusterv1.Machine{machine1, machine2} {
+		patchHelper, err := patch.NewHelper(m, cl)
+		g.Expect(err).ToNot(HaveOccurred())
+		conditions.MarkFalse(m, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.NodeNotFoundReason, clusterv1.ConditionSeverityWarning, "")
+		targets = append(targets, healthCheckTarget{
+			MHC:         mhc,
+			Machine:     m,
+			patchHelper: patchHelper,
+			Node:        &corev1.Node{},
+		})
+	}
+
+	g.Expect(r.reportUnhealthyTargets(ctx, logr.New(log.NullLogSink{}), targets, defaultCluster, mhc)).To(BeEmpty())
+
+	// Only the machine which is not paused is reported as a machine which would have been remediated.
+	g.Expect(mhc.Status.DryRunRemediationTargets).To(Equal([]string{"machine1"}))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring(EventMachineWouldBeRemediated)))
+
+	// Machines get the health check result, but they are not marked for remediation.
+	for _, m := range []*clusterv1.Machine{machine1, machine2} {
+		g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(m), m)).To(Succeed())
+		g.Expect(conditions.IsFalse(m, clusterv1.MachineHealthCheckSucceededCondition)).To(BeTrue())
+		g.Expect(conditions.Has(m, clusterv1.MachineOwnerRemediatedCondition)).To(BeFalse())
+	}
+}
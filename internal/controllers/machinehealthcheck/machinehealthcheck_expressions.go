@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/nodeexpression"
+)
+
+// unhealthyNodeExpression is an unhealthy node expression of a MachineHealthCheck, compiled.
+type unhealthyNodeExpression struct {
+	name       string
+	expression *nodeexpression.Expression
+}
+
+// unhealthyNodeExpressionsCache caches the compiled unhealthy node expressions of MachineHealthChecks, so
+// expressions are compiled only when a MachineHealthCheck is created or its spec changes instead of at every reconcile.
+type unhealthyNodeExpressionsCache struct {
+	lock  sync.Mutex
+	items map[types.NamespacedName]unhealthyNodeExpressionsCacheItem
+}
+
+type unhealthyNodeExpressionsCacheItem struct {
+	uid         types.UID
+	generation  int64
+	expressions []unhealthyNodeExpression
+}
+
+// get returns the compiled unhealthy node expressions of a MachineHealthCheck, compiling them if the
+// MachineHealthCheck is not in the cache or it changed since the expressions were compiled.
+// NOTE: Invalid expressions are logged and skipped; they are prevented by the MachineHealthCheck webhook.
+func (c *unhealthyNodeExpressionsCache) get(logger logr.Logger, mhc *clusterv1.MachineHealthCheck) []unhealthyNodeExpression {
+	if len(mhc.Spec.UnhealthyNodeExpressions) == 0 {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := types.NamespacedName{Namespace: mhc.Namespace, Name: mhc.Name}
+	if item, ok := c.items[key]; ok && item.uid == mhc.UID && item.generation == mhc.Generation {
+		return item.expressions
+	}
+
+	expressions := make([]unhealthyNodeExpression, 0, len(mhc.Spec.UnhealthyNodeExpressions))
+	for _, e := range mhc.Spec.UnhealthyNodeExpressions {
+		expression, err := nodeexpression.Compile(e.Expression)
+		if err != nil {
+			logger.Error(err, "Skipping invalid unhealthy node expression", "expression", e.Name)
+			continue
+		}
+		expressions = append(expressions, unhealthyNodeExpression{name: e.Name, expression: expression})
+	}
+
+	if c.items == nil {
+		c.items = map[types.NamespacedName]unhealthyNodeExpressionsCacheItem{}
+	}
+	c.items[key] = unhealthyNodeExpressionsCacheItem{uid: mhc.UID, generation: mhc.Generation, expressions: expressions}
+	return expressions
+}
+
+// delete removes the compiled unhealthy node expressions of a deleted MachineHealthCheck from the cache.
+func (c *unhealthyNodeExpressionsCache) delete(key types.NamespacedName) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.items, key)
+}
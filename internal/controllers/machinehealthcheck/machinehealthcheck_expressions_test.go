@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestUnhealthyNodeExpressionsCache(t *testing.T) {
+	g := NewWithT(t)
+
+	mhc := &clusterv1.MachineHealthCheck{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "mhc",
+			Namespace:  metav1.NamespaceDefault,
+			UID:        "uid",
+			Generation: 1,
+		},
+		Spec: clusterv1.MachineHealthCheckSpec{
+			UnhealthyNodeExpressions: []clusterv1.UnhealthyNodeExpression{
+				{Name: "always", Expression: "true"},
+				{Name: "invalid", Expression: "node.status +"},
+			},
+		},
+	}
+	cache := &unhealthyNodeExpressionsCache{}
+
+	// Invalid expressions are skipped.
+	expressions := cache.get(ctrl.LoggerFrom(ctx), mhc)
+	g.Expect(expressions).To(HaveLen(1))
+	g.Expect(expressions[0].name).To(Equal("always"))
+
+	// Expressions are not compiled again until the MachineHealthCheck changes.
+	g.Expect(cache.get(ctrl.LoggerFrom(ctx), mhc)[0].expression).To(BeIdenticalTo(expressions[0].expression))
+
+	mhc.Generation = 2
+	mhc.Spec.UnhealthyNodeExpressions = []clusterv1.UnhealthyNodeExpression{
+		{Name: "never", Expression: "false"},
+	}
+	expressions = cache.get(ctrl.LoggerFrom(ctx), mhc)
+	g.Expect(expressions).To(HaveLen(1))
+	g.Expect(expressions[0].name).To(Equal("never"))
+
+	// Expressions of deleted MachineHealthChecks are removed from the cache.
+	cache.delete(types.NamespacedName{Namespace: mhc.Namespace, Name: mhc.Name})
+	g.Expect(cache.items).To(BeEmpty())
+}
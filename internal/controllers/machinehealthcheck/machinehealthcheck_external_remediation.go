@@ -0,0 +1,180 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// EventExternalRemediationSucceeded is emitted when an external remediation request
+	// reports that an unhealthy machine has been remediated.
+	EventExternalRemediationSucceeded string = "ExternalRemediationSucceeded"
+
+	// EventExternalRemediationFailed is emitted when an external remediation request
+	// reports that the remediation of an unhealthy machine failed.
+	EventExternalRemediationFailed string = "ExternalRemediationFailed"
+
+	// externalRemediationRequestCheckInterval is the interval at which external remediation requests
+	// for unhealthy machines are checked again, given that changes to them do not trigger a reconcile.
+	externalRemediationRequestCheckInterval = 30 * time.Second
+
+	// maxExternalRemediationRetries is the number of times a failed external remediation request is retried
+	// before escalating remediation to the machine owner.
+	maxExternalRemediationRetries = 3
+)
+
+// reconcileExternalRemediationRequest consumes the result reported by the external remediation request of an
+// unhealthy target, and reflects it in the target's MachineExternallyRemediatedCondition:
+//   - If status.phase is Succeeded, the remediation request is deleted and the machine is marked as healthy;
+//     the time of the remediation is recorded, so the health check starts again from scratch.
+//   - If status.phase is Failed and status.failureReason is not set, the remediation request is deleted, so
+//     a new one is created on the next reconcile, up to maxExternalRemediationRetries times.
+//   - If status.phase is Failed and status.failureReason is set, or the retries are exhausted, the failure is
+//     permanent and remediation is escalated to the machine owner, which is going to delete the machine.
+//   - Otherwise, the remediation is in progress and the machine is left untouched.
+func (r *Reconciler) reconcileExternalRemediationRequest(ctx context.Context, logger logr.Logger, t healthCheckTarget, obj *unstructured.Unstructured) error {
+	// Wait for a remediation request being deleted to go away before acting on it again.
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	phase, _, err := unstructured.NestedString(obj.Object, "status", "phase")
+	if err != nil {
+		return errors.Wrapf(err, "failed to read status.phase from %v %q", obj.GroupVersionKind(), obj.GetName())
+	}
+	failureReason, _, err := unstructured.NestedString(obj.Object, "status", "failureReason")
+	if err != nil {
+		return errors.Wrapf(err, "failed to read status.failureReason from %v %q", obj.GroupVersionKind(), obj.GetName())
+	}
+	failureMessage, _, err := unstructured.NestedString(obj.Object, "status", "failureMessage")
+	if err != nil {
+		return errors.Wrapf(err, "failed to read status.failureMessage from %v %q", obj.GroupVersionKind(), obj.GetName())
+	}
+
+	retries := externalRemediationRetries(t.Machine)
+	if phase == clusterv1.ExternalRemediationFailedPhase && failureReason == "" && retries >= maxExternalRemediationRetries {
+		failureReason = "RetriesExhausted"
+		failureMessage = fmt.Sprintf("remediation failed %d times, last failure: %s", retries+1, failureMessage)
+	}
+
+	switch {
+	case phase == clusterv1.ExternalRemediationSucceededPhase:
+		if err := r.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete %v %q for Machine %q", obj.GroupVersionKind(), obj.GetName(), t.Machine.Name)
+		}
+		logger.Info("External remediation request succeeded, marking the target as healthy", "remediation request name", obj.GetName(), "target", t.string())
+		delete(t.Machine.Annotations, clusterv1.ExternalRemediationRetriesAnnotation)
+		annotations.AddAnnotations(t.Machine, map[string]string{clusterv1.ExternallyRemediatedAtAnnotation: time.Now().UTC().Format(time.RFC3339)})
+		conditions.MarkTrue(t.Machine, clusterv1.MachineExternallyRemediatedCondition)
+		conditions.MarkTrue(t.Machine, clusterv1.MachineHealthCheckSucceededCondition)
+		r.recorder.Eventf(
+			t.Machine,
+			corev1.EventTypeNormal,
+			EventExternalRemediationSucceeded,
+			"Machine %v has been remediated by %v %v",
+			t.string(),
+			obj.GetKind(),
+			obj.GetName(),
+		)
+	case phase == clusterv1.ExternalRemediationFailedPhase && failureReason == "":
+		if err := r.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete %v %q for Machine %q", obj.GroupVersionKind(), obj.GetName(), t.Machine.Name)
+		}
+		logger.Info("External remediation request failed, retrying", "remediation request name", obj.GetName(), "target", t.string(), "retries", retries+1, "message", failureMessage)
+		annotations.AddAnnotations(t.Machine, map[string]string{clusterv1.ExternalRemediationRetriesAnnotation: strconv.Itoa(retries + 1)})
+		conditions.MarkFalse(t.Machine, clusterv1.MachineExternallyRemediatedCondition, clusterv1.ExternalRemediationRetryReason, clusterv1.ConditionSeverityWarning, failureMessage)
+		r.recorder.Eventf(
+			t.Machine,
+			corev1.EventTypeWarning,
+			EventExternalRemediationFailed,
+			"Remediation of Machine %v by %v %v failed, retrying: %s",
+			t.string(),
+			obj.GetKind(),
+			obj.GetName(),
+			failureMessage,
+		)
+	case phase == clusterv1.ExternalRemediationFailedPhase:
+		if conditions.GetReason(t.Machine, clusterv1.MachineExternallyRemediatedCondition) != clusterv1.ExternalRemediationFailedReason {
+			logger.Info("External remediation request failed permanently, marking the target for remediation", "remediation request name", obj.GetName(), "target", t.string(), "reason", failureReason, "message", failureMessage)
+			r.recorder.Eventf(
+				t.Machine,
+				corev1.EventTypeWarning,
+				EventExternalRemediationFailed,
+				"Remediation of Machine %v by %v %v failed permanently (%s): %s",
+				t.string(),
+				obj.GetKind(),
+				obj.GetName(),
+				failureReason,
+				failureMessage,
+			)
+		}
+		conditions.MarkFalse(t.Machine, clusterv1.MachineExternallyRemediatedCondition, clusterv1.ExternalRemediationFailedReason, clusterv1.ConditionSeverityError, "%s: %s", failureReason, failureMessage)
+		// NOTE: The remediation owner is responsible for completing the process, see patchUnhealthyTargets.
+		if !conditions.Has(t.Machine, clusterv1.MachineOwnerRemediatedCondition) || conditions.IsTrue(t.Machine, clusterv1.MachineOwnerRemediatedCondition) {
+			conditions.MarkFalse(t.Machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+		}
+	case phase == "":
+		logger.V(3).Info("Waiting for the external remediation request to report progress", "remediation request name", obj.GetName(), "target", t.string())
+		conditions.MarkFalse(t.Machine, clusterv1.MachineExternallyRemediatedCondition, clusterv1.WaitingForExternalRemediationReason, clusterv1.ConditionSeverityWarning, "")
+	default:
+		logger.V(3).Info("External remediation in progress", "remediation request name", obj.GetName(), "target", t.string(), "phase", phase)
+		conditions.MarkFalse(t.Machine, clusterv1.MachineExternallyRemediatedCondition, clusterv1.RemediationInProgressReason, clusterv1.ConditionSeverityInfo, "%s %s is in phase %s", obj.GetKind(), obj.GetName(), phase)
+	}
+
+	if err := t.patchHelper.Patch(ctx, t.Machine); err != nil {
+		return errors.Wrapf(err, "failed to patch unhealthy machine status for machine: %s/%s", t.Machine.Namespace, t.Machine.Name)
+	}
+	return nil
+}
+
+// externalRemediationRetries returns the number of times the external remediation of the machine failed and has been retried.
+// NOTE: Invalid values are ignored, and the retries start again from scratch.
+func externalRemediationRetries(machine *clusterv1.Machine) int {
+	retries, err := strconv.Atoi(machine.GetAnnotations()[clusterv1.ExternalRemediationRetriesAnnotation])
+	if err != nil || retries < 0 {
+		return 0
+	}
+	return retries
+}
+
+// externallyRemediatedAt returns the time the machine has been remediated by the last external remediation request which succeeded, if any.
+// NOTE: Values not in RFC3339 format are ignored.
+func externallyRemediatedAt(machine *clusterv1.Machine) (time.Time, bool) {
+	value, ok := machine.GetAnnotations()[clusterv1.ExternallyRemediatedAtAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	remediatedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return remediatedAt, true
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+func TestReconcileExternalRemediationRequest(t *testing.T) {
+	namespace := metav1.NamespaceDefault
+	clusterName := testClusterName
+	labels := map[string]string{"cluster": "foo", "nodepool": "bar"}
+
+	tests := []struct {
+		name                   string
+		annotations            map[string]string
+		status                 map[string]interface{}
+		expectRequestDeleted   bool
+		expectHealthy          bool
+		expectConditionStatus  corev1.ConditionStatus
+		expectConditionReason  string
+		expectOwnerRemediation bool
+		expectAnnotations      []string
+	}{
+		{
+			name:                  "waits for the remediation request to report progress",
+			expectConditionStatus: corev1.ConditionFalse,
+			expectConditionReason: clusterv1.WaitingForExternalRemediationReason,
+		},
+		{
+			name:                  "reports the phase of a remediation in progress",
+			status:                map[string]interface{}{"phase": "Rebooting"},
+			expectConditionStatus: corev1.ConditionFalse,
+			expectConditionReason: clusterv1.RemediationInProgressReason,
+		},
+		{
+			name:                  "marks the machine as healthy and records the remediation time when the remediation succeeded",
+			annotations:           map[string]string{clusterv1.ExternalRemediationRetriesAnnotation: "1"},
+			status:                map[string]interface{}{"phase": clusterv1.ExternalRemediationSucceededPhase},
+			expectRequestDeleted:  true,
+			expectHealthy:         true,
+			expectConditionStatus: corev1.ConditionTrue,
+			expectAnnotations:     []string{clusterv1.ExternallyRemediatedAtAnnotation},
+		},
+		{
+			name:                  "retries a failed remediation",
+			status:                map[string]interface{}{"phase": clusterv1.ExternalRemediationFailedPhase, "failureMessage": "power cycle timed out"},
+			expectRequestDeleted:  true,
+			expectConditionStatus: corev1.ConditionFalse,
+			expectConditionReason: clusterv1.ExternalRemediationRetryReason,
+			expectAnnotations:     []string{clusterv1.ExternalRemediationRetriesAnnotation},
+		},
+		{
+			name:                   "escalates to the machine owner a failed remediation once the retries are exhausted",
+			annotations:            map[string]string{clusterv1.ExternalRemediationRetriesAnnotation: "3"},
+			status:                 map[string]interface{}{"phase": clusterv1.ExternalRemediationFailedPhase, "failureMessage": "power cycle timed out"},
+			expectConditionStatus:  corev1.ConditionFalse,
+			expectConditionReason:  clusterv1.ExternalRemediationFailedReason,
+			expectOwnerRemediation: true,
+			expectAnnotations:      []string{clusterv1.ExternalRemediationRetriesAnnotation},
+		},
+		{
+			name:                   "escalates to the machine owner a remediation failed permanently",
+			status:                 map[string]interface{}{"phase": clusterv1.ExternalRemediationFailedPhase, "failureReason": "HostNotFound", "failureMessage": "the host does not exist anymore"},
+			expectConditionStatus:  corev1.ConditionFalse,
+			expectConditionReason:  clusterv1.ExternalRemediationFailedReason,
+			expectOwnerRemediation: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mhc := newMachineHealthCheckWithLabels("mhc", namespace, clusterName, labels)
+			machine := newTestMachine("machine1", namespace, clusterName, "nodeName", labels)
+			machine.Annotations = tt.annotations
+			conditions.MarkFalse(machine, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.UnhealthyNodeConditionReason, clusterv1.ConditionSeverityWarning, "")
+
+			remediationRequest := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       "GenericExternalRemediation",
+					"apiVersion": builder.RemediationGroupVersion.String(),
+					"metadata": map[string]interface{}{
+						"name":      machine.Name,
+						"namespace": namespace,
+					},
+				},
+			}
+			if tt.status != nil {
+				remediationRequest.Object["status"] = tt.status
+			}
+
+			cl := fake.NewClientBuilder().WithObjects(machine, remediationRequest, mhc).Build()
+			r := &Reconciler{
+				Client:   cl,
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			// Read the machine back, so the patch helper is created from the object as it is stored.
+			g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(machine), machine)).To(Succeed())
+			patchHelper, err := patch.NewHelper(machine, cl)
+			g.Expect(err).ToNot(HaveOccurred())
+			target := healthCheckTarget{
+				MHC:         mhc,
+				Machine:     machine,
+				Node:        newTestNode("nodeName"),
+				patchHelper: patchHelper,
+			}
+
+			g.Expect(r.reconcileExternalRemediationRequest(context.TODO(), logr.New(log.NullLogSink{}), target, remediationRequest)).To(Succeed())
+
+			err = cl.Get(ctx, client.ObjectKeyFromObject(remediationRequest), remediationRequest)
+			if tt.expectRequestDeleted {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(machine), machine)).To(Succeed())
+			g.Expect(conditions.IsTrue(machine, clusterv1.MachineHealthCheckSucceededCondition)).To(Equal(tt.expectHealthy))
+			c := conditions.Get(machine, clusterv1.MachineExternallyRemediatedCondition)
+			g.Expect(c).ToNot(BeNil())
+			g.Expect(c.Status).To(Equal(tt.expectConditionStatus))
+			g.Expect(c.Reason).To(Equal(tt.expectConditionReason))
+			g.Expect(conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition)).To(Equal(tt.expectOwnerRemediation))
+			for _, annotation := range []string{clusterv1.ExternallyRemediatedAtAnnotation, clusterv1.ExternalRemediationRetriesAnnotation} {
+				if sets.New[string](tt.expectAnnotations...).Has(annotation) {
+					g.Expect(machine.Annotations).To(HaveKey(annotation))
+				} else {
+					g.Expect(machine.Annotations).ToNot(HaveKey(annotation))
+				}
+			}
+		})
+	}
+}
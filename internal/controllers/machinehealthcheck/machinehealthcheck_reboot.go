@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+const (
+	// EventRebootRequested is emitted when a reboot of the infrastructure machine
+	// backing an unhealthy machine has been requested.
+	EventRebootRequested string = "RebootRequested"
+)
+
+// reconcileReboot implements escalating remediation: it requests a reboot of the infrastructure machine
+// backing each unhealthy target, and returns only the targets that should be remediated because the reboot
+// did not make them healthy within the configured timeout (or because a reboot can't help them).
+// For targets still waiting for a reboot to complete, the duration after which they should be checked again is returned.
+func (r *Reconciler) reconcileReboot(ctx context.Context, logger logr.Logger, unhealthy []healthCheckTarget, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck) ([]healthCheckTarget, []time.Duration, []error) {
+	timeout := clusterv1.DefaultRebootTimeout
+	if m.Spec.Reboot.Timeout != nil {
+		timeout = *m.Spec.Reboot.Timeout
+	}
+
+	toRemediate := []healthCheckTarget{}
+	nextCheckTimes := []time.Duration{}
+	errList := []error{}
+	for _, t := range unhealthy {
+		// Paused machines are skipped when patching unhealthy targets, and rebooting doesn't help machines
+		// which have failed or which never got a node.
+		if annotations.IsPaused(cluster, t.Machine) || !t.canBeRebooted() {
+			toRemediate = append(toRemediate, t)
+			continue
+		}
+
+		infraMachine, err := external.Get(ctx, r.Client, &t.Machine.Spec.InfrastructureRef, t.Machine.Namespace)
+		if err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to get infrastructure machine for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
+			continue
+		}
+
+		requestedAt, ok := rebootRequestedAt(infraMachine.GetAnnotations())
+		if !ok {
+			patchHelper, err := patch.NewHelper(infraMachine, r.Client)
+			if err != nil {
+				errList = append(errList, err)
+				continue
+			}
+			infraAnnotations := infraMachine.GetAnnotations()
+			if infraAnnotations == nil {
+				infraAnnotations = map[string]string{}
+			}
+			requestedAt := time.Now().UTC().Format(time.RFC3339)
+			infraAnnotations[clusterv1.RebootRequestedAnnotation] = requestedAt
+			infraMachine.SetAnnotations(infraAnnotations)
+			if err := patchHelper.Patch(ctx, infraMachine); err != nil {
+				errList = append(errList, errors.Wrapf(err, "failed to request reboot for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
+				continue
+			}
+			// Track the request on the Machine too, so only the infrastructure machines of Machines with a pending
+			// request are read when clearing reboot requests.
+			annotations.AddAnnotations(t.Machine, map[string]string{clusterv1.RebootRequestedAnnotation: requestedAt})
+
+			logger.Info("Target has failed health check, requesting a reboot", "target", t.string(), "timeout", timeout.Duration.String())
+			r.recorder.Eventf(
+				t.Machine,
+				corev1.EventTypeNormal,
+				EventRebootRequested,
+				"Reboot requested for Machine %v",
+				t.string(),
+			)
+			nextCheckTimes = append(nextCheckTimes, timeout.Duration)
+		} else {
+			if elapsed := time.Since(requestedAt); elapsed < timeout.Duration {
+				logger.V(3).Info("Waiting for the target to become healthy after reboot", "target", t.string(), "rebootRequestedAt", requestedAt)
+				nextCheckTimes = append(nextCheckTimes, timeout.Duration-elapsed)
+			} else {
+				logger.Info("Target did not become healthy after reboot, escalating to remediation", "target", t.string(), "rebootRequestedAt", requestedAt)
+				toRemediate = append(toRemediate, t)
+				continue
+			}
+		}
+
+		// The machine is not going to be remediated yet, but its MachineHealthCheckSucceeded condition still needs to be updated.
+		if err := t.patchHelper.Patch(ctx, t.Machine); err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to patch unhealthy machine status for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
+		}
+	}
+	return toRemediate, nextCheckTimes, errList
+}
+
+// clearRebootRequests removes the reboot requested annotation from the infrastructure machines of healthy targets
+// with a pending reboot request, so the next time those machines fail a health check a new reboot is requested.
+// NOTE: The annotation is removed from the Machine by the caller when patching healthy targets.
+func (r *Reconciler) clearRebootRequests(ctx context.Context, logger logr.Logger, healthy []healthCheckTarget) []error {
+	errList := []error{}
+	for _, t := range healthy {
+		if _, ok := t.Machine.GetAnnotations()[clusterv1.RebootRequestedAnnotation]; !ok {
+			continue
+		}
+
+		infraMachine, err := external.Get(ctx, r.Client, &t.Machine.Spec.InfrastructureRef, t.Machine.Namespace)
+		if err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to get infrastructure machine for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
+			continue
+		}
+		if _, ok := infraMachine.GetAnnotations()[clusterv1.RebootRequestedAnnotation]; !ok {
+			delete(t.Machine.Annotations, clusterv1.RebootRequestedAnnotation)
+			continue
+		}
+
+		patchHelper, err := patch.NewHelper(infraMachine, r.Client)
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+		infraAnnotations := infraMachine.GetAnnotations()
+		delete(infraAnnotations, clusterv1.RebootRequestedAnnotation)
+		infraMachine.SetAnnotations(infraAnnotations)
+		if err := patchHelper.Patch(ctx, infraMachine); err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to clear reboot request for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
+			continue
+		}
+		delete(t.Machine.Annotations, clusterv1.RebootRequestedAnnotation)
+		logger.V(3).Info("Target is healthy after reboot", "target", t.string())
+	}
+	return errList
+}
+
+// canBeRebooted returns true if rebooting the infrastructure machine could make the target healthy,
+// that is if the Machine has a Node and did not report a failure.
+func (t *healthCheckTarget) canBeRebooted() bool {
+	return t.Node != nil && !t.nodeMissing &&
+		t.Machine.Status.FailureReason == nil && t.Machine.Status.FailureMessage == nil
+}
+
+// rebootRequestedAt returns the time a reboot has been requested at, if any.
+func rebootRequestedAt(infraAnnotations map[string]string) (time.Time, bool) {
+	value, ok := infraAnnotations[clusterv1.RebootRequestedAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	requestedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		// An invalid value is treated as a missing one, so a new reboot is requested.
+		return time.Time{}, false
+	}
+	return requestedAt, true
+}
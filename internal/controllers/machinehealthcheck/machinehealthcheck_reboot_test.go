@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+func TestReconcileReboot(t *testing.T) {
+	namespace := metav1.NamespaceDefault
+	clusterName := testClusterName
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterName,
+			Namespace: namespace,
+		},
+	}
+	labels := map[string]string{"cluster": "foo", "nodepool": "bar"}
+	timeout := metav1.Duration{Duration: 5 * time.Minute}
+
+	tests := []struct {
+		name                 string
+		rebootRequestedAt    *time.Time
+		nodeMissing          bool
+		expectRemediation    bool
+		expectRebootRequest  bool
+		expectNextCheckTimes int
+	}{
+		{
+			name:                 "requests a reboot for a new unhealthy machine",
+			expectRemediation:    false,
+			expectRebootRequest:  true,
+			expectNextCheckTimes: 1,
+		},
+		{
+			name:                 "waits for a reboot requested within the timeout",
+			rebootRequestedAt:    timePtr(time.Now().Add(-1 * time.Minute)),
+			expectRemediation:    false,
+			expectRebootRequest:  true,
+			expectNextCheckTimes: 1,
+		},
+		{
+			name:                 "remediates if the machine is still unhealthy after the timeout",
+			rebootRequestedAt:    timePtr(time.Now().Add(-10 * time.Minute)),
+			expectRemediation:    true,
+			expectRebootRequest:  true,
+			expectNextCheckTimes: 0,
+		},
+		{
+			name:                 "remediates without reboot if the node is missing",
+			nodeMissing:          true,
+			expectRemediation:    true,
+			expectRebootRequest:  false,
+			expectNextCheckTimes: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mhc := newMachineHealthCheckWithLabels("mhc", namespace, clusterName, labels)
+			mhc.Spec.Reboot = &clusterv1.MachineHealthCheckReboot{Timeout: &timeout}
+			machine := newTestMachine("machine1", namespace, clusterName, "nodeName", labels)
+			infraMachine := newRebootTestInfraMachine(machine, tt.rebootRequestedAt)
+			conditions.MarkFalse(machine, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.UnhealthyNodeConditionReason, clusterv1.ConditionSeverityWarning, "")
+
+			cl := fake.NewClientBuilder().WithObjects(machine, infraMachine, mhc).Build()
+			r := &Reconciler{
+				Client:   cl,
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			patchHelper, err := patch.NewHelper(machine, cl)
+			g.Expect(err).ToNot(HaveOccurred())
+			target := healthCheckTarget{
+				Cluster:     cluster,
+				MHC:         mhc,
+				Machine:     machine,
+				patchHelper: patchHelper,
+				nodeMissing: tt.nodeMissing,
+			}
+			if !tt.nodeMissing {
+				target.Node = newTestNode("nodeName")
+			}
+
+			toRemediate, nextCheckTimes, errList := r.reconcileReboot(context.TODO(), logr.New(log.NullLogSink{}), []healthCheckTarget{target}, cluster, mhc)
+			g.Expect(errList).To(BeEmpty())
+			g.Expect(nextCheckTimes).To(HaveLen(tt.expectNextCheckTimes))
+			if tt.expectRemediation {
+				g.Expect(toRemediate).To(HaveLen(1))
+			} else {
+				g.Expect(toRemediate).To(BeEmpty())
+			}
+
+			g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(infraMachine), infraMachine)).To(Succeed())
+			if tt.expectRebootRequest {
+				g.Expect(infraMachine.GetAnnotations()).To(HaveKey(clusterv1.RebootRequestedAnnotation))
+				g.Expect(machine.GetAnnotations()).To(HaveKeyWithValue(clusterv1.RebootRequestedAnnotation, infraMachine.GetAnnotations()[clusterv1.RebootRequestedAnnotation]))
+			} else {
+				g.Expect(infraMachine.GetAnnotations()).ToNot(HaveKey(clusterv1.RebootRequestedAnnotation))
+			}
+		})
+	}
+}
+
+func TestClearRebootRequests(t *testing.T) {
+	g := NewWithT(t)
+
+	namespace := metav1.NamespaceDefault
+	labels := map[string]string{"cluster": "foo", "nodepool": "bar"}
+
+	mhc := newMachineHealthCheckWithLabels("mhc", namespace, testClusterName, labels)
+	machine := newTestMachine("machine1", namespace, testClusterName, "nodeName", labels)
+	infraMachine := newRebootTestInfraMachine(machine, timePtr(time.Now().Add(-1*time.Minute)))
+
+	cl := fake.NewClientBuilder().WithObjects(machine, infraMachine, mhc).Build()
+	r := &Reconciler{
+		Client:   cl,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	target := healthCheckTarget{
+		MHC:     mhc,
+		Machine: machine,
+		Node:    newTestNode("nodeName"),
+	}
+	g.Expect(r.clearRebootRequests(context.TODO(), logr.New(log.NullLogSink{}), []healthCheckTarget{target})).To(BeEmpty())
+
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(infraMachine), infraMachine)).To(Succeed())
+	g.Expect(infraMachine.GetAnnotations()).ToNot(HaveKey(clusterv1.RebootRequestedAnnotation))
+	g.Expect(machine.GetAnnotations()).ToNot(HaveKey(clusterv1.RebootRequestedAnnotation))
+
+	// The infrastructure machines of targets without a pending reboot request are not read.
+	machineWithoutRequest := newTestMachine("machine2", namespace, testClusterName, "nodeName", labels)
+	_ = newRebootTestInfraMachine(machineWithoutRequest, nil)
+	target = healthCheckTarget{
+		MHC:     mhc,
+		Machine: machineWithoutRequest,
+		Node:    newTestNode("nodeName"),
+	}
+	g.Expect(r.clearRebootRequests(context.TODO(), logr.New(log.NullLogSink{}), []healthCheckTarget{target})).To(BeEmpty())
+}
+
+func newRebootTestInfraMachine(machine *clusterv1.Machine, rebootRequestedAt *time.Time) *unstructured.Unstructured {
+	infraMachine := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"kind":       "GenericInfrastructureMachine",
+			"metadata": map[string]interface{}{
+				"name":      machine.Name,
+				"namespace": machine.Namespace,
+			},
+		},
+	}
+	if rebootRequestedAt != nil {
+		infraMachine.SetAnnotations(map[string]string{
+			clusterv1.RebootRequestedAnnotation: rebootRequestedAt.UTC().Format(time.RFC3339),
+		})
+		if machine.Annotations == nil {
+			machine.Annotations = map[string]string{}
+		}
+		machine.Annotations[clusterv1.RebootRequestedAnnotation] = rebootRequestedAt.UTC().Format(time.RFC3339)
+	}
+
+	machine.Spec.InfrastructureRef = corev1.ObjectReference{
+		APIVersion: infraMachine.GetAPIVersion(),
+		Kind:       infraMachine.GetKind(),
+		Name:       infraMachine.GetName(),
+		Namespace:  infraMachine.GetNamespace(),
+	}
+	return infraMachine
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
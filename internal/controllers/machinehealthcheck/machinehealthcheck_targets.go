@@ -42,6 +42,9 @@ const (
 
 	// EventMachineMarkedUnhealthy is emitted when machine was successfully marked as unhealthy.
 	EventMachineMarkedUnhealthy string = "MachineMarkedUnhealthy"
+	// EventMachineWouldBeRemediated is emitted when an unhealthy machine is not remediated because
+	// the machine health check is in DryRun mode.
+	EventMachineWouldBeRemediated string = "MachineWouldBeRemediated"
 	// EventDetectedUnhealthy is emitted in case a node associated with a
 	// machine was detected unhealthy.
 	EventDetectedUnhealthy string = "DetectedUnhealthy"
@@ -50,6 +53,10 @@ const (
 var (
 	// We allow users to disable the nodeStartupTimeout by setting the duration to 0.
 	disabledNodeStartupTimeout = clusterv1.ZeroDuration
+
+	// unhealthyNodeExpressionsCheckInterval is the interval at which unhealthy node expressions are evaluated
+	// again, given that they can depend on the evaluation time.
+	unhealthyNodeExpressionsCheckInterval = 1 * time.Minute
 )
 
 // healthCheckTarget contains the information required to perform a health check
@@ -85,11 +92,12 @@ func (t *healthCheckTarget) nodeName() string {
 // - The Machine has failed for some reason
 // - The Machine did not get a node before `timeoutForMachineToHaveNode` elapses
 // - The Node has gone away
-// - Any condition on the node is matched for the given timeout
+// - Any condition on the node is matched for the given timeout, since the last external remediation if any
+// - Any expression on the node evaluates to true
 // If the target doesn't currently need rememdiation, provide a duration after
 // which the target should next be checked.
 // The target should be requeued after this duration.
-func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachineToHaveNode metav1.Duration) (bool, time.Duration) {
+func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachineToHaveNode metav1.Duration, expressions []unhealthyNodeExpression) (bool, time.Duration) {
 	var nextCheckTimes []time.Duration
 	now := time.Now()
 
@@ -148,6 +156,9 @@ func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachi
 		if conditions.IsTrue(t.Cluster, clusterv1.InfrastructureReadyCondition) && clusterInfraReady != nil && clusterInfraReady.Time.After(comparisonTime) {
 			comparisonTime = clusterInfraReady.Time
 		}
+		if remediatedAt, ok := externallyRemediatedAt(t.Machine); ok && remediatedAt.After(comparisonTime) {
+			comparisonTime = remediatedAt
+		}
 		logger.V(3).Info("Using comparison time", "time", comparisonTime)
 
 		timeoutDuration := timeoutForMachineToHaveNode.Duration
@@ -173,20 +184,40 @@ func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachi
 			continue
 		}
 
+		// The condition is considered in the unhealthy state since the last external remediation, if later.
+		unhealthySince := nodeCondition.LastTransitionTime.Time
+		if remediatedAt, ok := externallyRemediatedAt(t.Machine); ok && remediatedAt.After(unhealthySince) {
+			unhealthySince = remediatedAt
+		}
+
 		// If the condition has been in the unhealthy state for longer than the
 		// timeout, return true with no requeue time.
-		if nodeCondition.LastTransitionTime.Add(c.Timeout.Duration).Before(now) {
+		if unhealthySince.Add(c.Timeout.Duration).Before(now) {
 			conditions.MarkFalse(t.Machine, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.UnhealthyNodeConditionReason, clusterv1.ConditionSeverityWarning, "Condition %s on node is reporting status %s for more than %s", c.Type, c.Status, c.Timeout.Duration.String())
 			logger.V(3).Info("Target is unhealthy: condition is in state longer than allowed timeout", "condition", c.Type, "state", c.Status, "timeout", c.Timeout.Duration.String())
 			return true, time.Duration(0)
 		}
 
-		durationUnhealthy := now.Sub(nodeCondition.LastTransitionTime.Time)
+		durationUnhealthy := now.Sub(unhealthySince)
 		nextCheck := c.Timeout.Duration - durationUnhealthy + time.Second
 		if nextCheck > 0 {
 			nextCheckTimes = append(nextCheckTimes, nextCheck)
 		}
 	}
+
+	// check expressions
+	for _, e := range expressions {
+		unhealthy, err := e.expression.Eval(t.Node, now)
+		if err != nil {
+			logger.V(3).Info("Skipping unhealthy node expression which failed to evaluate", "expression", e.name, "error", err.Error())
+			continue
+		}
+		if unhealthy {
+			conditions.MarkFalse(t.Machine, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.UnhealthyNodeExpressionReason, clusterv1.ConditionSeverityWarning, "Expression %s on node evaluated to true", e.name)
+			logger.V(3).Info("Target is unhealthy: expression evaluated to true", "expression", e.name)
+			return true, time.Duration(0)
+		}
+	}
 	return false, minDuration(nextCheckTimes)
 }
 
@@ -288,7 +319,7 @@ func (r *Reconciler) healthCheckTargets(targets []healthCheckTarget, logger logr
 	for _, t := range targets {
 		logger = logger.WithValues("Target", t.string())
 		logger.V(3).Info("Health checking target")
-		needsRemediation, nextCheck := t.needsRemediation(logger, timeoutForMachineToHaveNode)
+		needsRemediation, nextCheck := t.needsRemediation(logger, timeoutForMachineToHaveNode, r.unhealthyNodeExpressions.get(logger, t.MHC))
 
 		if needsRemediation {
 			unhealthy = append(unhealthy, t)
@@ -22,6 +22,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -340,6 +341,44 @@ func TestHealthCheckTargets(t *testing.T) {
 	}
 	machineFailureMsgCondition := newFailedHealthCheckCondition(clusterv1.MachineHasFailureReason, "FailureMessage: %s", failureMsg)
 
+	// Targets for when the MHC has unhealthy node expressions
+	testMHCWithExpressions := testMHC.DeepCopy()
+	testMHCWithExpressions.Spec.UnhealthyNodeExpressions = []clusterv1.UnhealthyNodeExpression{
+		{
+			Name:       "low-ephemeral-storage",
+			Expression: "quantity(node.status.allocatable['ephemeral-storage']) < quantity('10Gi')",
+		},
+	}
+	testNodeLowStorage := newTestNode("node1")
+	testNodeLowStorage.Status.Allocatable = corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("5Gi")}
+	nodeLowStorage := healthCheckTarget{
+		Cluster:     cluster,
+		MHC:         testMHCWithExpressions,
+		Machine:     testMachine.DeepCopy(),
+		Node:        testNodeLowStorage,
+		nodeMissing: false,
+	}
+	nodeLowStorageCondition := newFailedHealthCheckCondition(clusterv1.UnhealthyNodeExpressionReason, "Expression low-ephemeral-storage on node evaluated to true")
+
+	testNodeEnoughStorage := newTestNode("node1")
+	testNodeEnoughStorage.Status.Allocatable = corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("20Gi")}
+	nodeEnoughStorage := healthCheckTarget{
+		Cluster:     cluster,
+		MHC:         testMHCWithExpressions,
+		Machine:     testMachine.DeepCopy(),
+		Node:        testNodeEnoughStorage,
+		nodeMissing: false,
+	}
+
+	// Expressions failing to evaluate, e.g. because allocatable is not set, are ignored
+	nodeWithoutAllocatable := healthCheckTarget{
+		Cluster:     cluster,
+		MHC:         testMHCWithExpressions,
+		Machine:     testMachine.DeepCopy(),
+		Node:        newTestNode("node1"),
+		nodeMissing: false,
+	}
+
 	testCases := []struct {
 		desc                              string
 		targets                           []healthCheckTarget
@@ -426,6 +465,28 @@ func TestHealthCheckTargets(t *testing.T) {
 			expectedNeedsRemediationCondition: []clusterv1.Condition{machineFailureMsgCondition},
 			expectedNextCheckTimes:            []time.Duration{},
 		},
+		{
+			desc:                              "when an unhealthy node expression evaluates to true",
+			targets:                           []healthCheckTarget{nodeLowStorage},
+			expectedHealthy:                   []healthCheckTarget{},
+			expectedNeedsRemediation:          []healthCheckTarget{nodeLowStorage},
+			expectedNeedsRemediationCondition: []clusterv1.Condition{nodeLowStorageCondition},
+			expectedNextCheckTimes:            []time.Duration{},
+		},
+		{
+			desc:                     "when unhealthy node expressions evaluate to false",
+			targets:                  []healthCheckTarget{nodeEnoughStorage},
+			expectedHealthy:          []healthCheckTarget{nodeEnoughStorage},
+			expectedNeedsRemediation: []healthCheckTarget{},
+			expectedNextCheckTimes:   []time.Duration{},
+		},
+		{
+			desc:                     "when unhealthy node expressions fail to evaluate",
+			targets:                  []healthCheckTarget{nodeWithoutAllocatable},
+			expectedHealthy:          []healthCheckTarget{nodeWithoutAllocatable},
+			expectedNeedsRemediation: []healthCheckTarget{},
+			expectedNextCheckTimes:   []time.Duration{},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -477,6 +538,45 @@ func TestHealthCheckTargets(t *testing.T) {
 	}
 }
 
+func TestNeedsRemediationAfterExternalRemediation(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{}
+	conditions.MarkTrue(cluster, clusterv1.InfrastructureReadyCondition)
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+
+	mhc := &clusterv1.MachineHealthCheck{
+		Spec: clusterv1.MachineHealthCheckSpec{
+			UnhealthyConditions: []clusterv1.UnhealthyCondition{
+				{
+					Type:    corev1.NodeReady,
+					Status:  corev1.ConditionUnknown,
+					Timeout: metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+		},
+	}
+	machine := newTestMachine("machine1", metav1.NamespaceDefault, "cluster", "node1", nil)
+	target := healthCheckTarget{
+		Cluster: cluster,
+		MHC:     mhc,
+		Machine: machine,
+		Node:    newTestUnhealthyNode("node1", corev1.NodeReady, corev1.ConditionUnknown, 400*time.Second),
+	}
+
+	// The node condition has been in the unhealthy state for longer than the timeout.
+	needsRemediation, _ := target.needsRemediation(ctrl.LoggerFrom(ctx), metav1.Duration{Duration: 10 * time.Minute}, nil)
+	g.Expect(needsRemediation).To(BeTrue())
+
+	// The timeout is evaluated starting from the last external remediation.
+	machine.Annotations = map[string]string{
+		clusterv1.ExternallyRemediatedAtAnnotation: time.Now().Add(-200 * time.Second).UTC().Format(time.RFC3339),
+	}
+	needsRemediation, nextCheck := target.needsRemediation(ctrl.LoggerFrom(ctx), metav1.Duration{Duration: 10 * time.Minute}, nil)
+	g.Expect(needsRemediation).To(BeFalse())
+	g.Expect(nextCheck).To(BeNumerically("~", 100*time.Second, 2*time.Second))
+}
+
 func newTestMachine(name, namespace, clusterName, nodeName string, labels map[string]string) *clusterv1.Machine {
 	// Copy the labels so that the map is unique to each test Machine
 	l := make(map[string]string)
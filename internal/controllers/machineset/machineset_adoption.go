@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+// adoptMachines adopts the orphaned Machines matching the label selector in the AdoptMachinesAnnotation, if set.
+// Machines are adopted only if they are compatible with the MachineSet template; adopted Machines get the labels
+// of the MachineSet template, so they match the MachineSet selector and they are reconciled as any other Machine
+// of the MachineSet afterwards.
+func (r *Reconciler) adoptMachines(ctx context.Context, machineSet *clusterv1.MachineSet) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	value, ok := machineSet.Annotations[clusterv1.AdoptMachinesAnnotation]
+	if !ok {
+		return nil
+	}
+	selector, err := labels.Parse(value)
+	if err != nil || selector.Empty() {
+		log.Info("Ignoring invalid value for the adopt machines annotation", "value", value)
+		return nil
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines,
+		client.InNamespace(machineSet.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: machineSet.Spec.ClusterName},
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return errors.Wrap(err, "failed to list Machines to adopt")
+	}
+
+	for i := range machines.Items {
+		machine := &machines.Items[i]
+		if metav1.GetControllerOf(machine) != nil || !machine.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		log := log.WithValues("Machine", klog.KObj(machine))
+		err := validateMachineForAdoption(machineSet, machine)
+		if err == nil {
+			err = r.validateClonedFromTemplates(ctx, machineSet, machine)
+		}
+		if err != nil {
+			log.Info("Skipping adoption of Machine", "reason", err.Error())
+			r.recorder.Eventf(machineSet, corev1.EventTypeWarning, "FailedAdopt", "Failed to adopt Machine %q: %v", machine.Name, err)
+			continue
+		}
+
+		patch := client.MergeFrom(machine.DeepCopy())
+		if machine.Labels == nil {
+			machine.Labels = map[string]string{}
+		}
+		for k, v := range machineLabelsFromMachineSet(machineSet) {
+			machine.Labels[k] = v
+		}
+		machine.SetOwnerReferences(util.EnsureOwnerRef(machine.GetOwnerReferences(), *metav1.NewControllerRef(machineSet, machineSetKind)))
+		if err := r.Client.Patch(ctx, machine, patch); err != nil {
+			return errors.Wrapf(err, "failed to adopt Machine %s", klog.KObj(machine))
+		}
+		log.Info("Adopted Machine")
+		r.recorder.Eventf(machineSet, corev1.EventTypeNormal, "SuccessfulAdopt", "Adopted Machine %q", machine.Name)
+	}
+	return nil
+}
+
+// validateMachineForAdoption returns an error if the Machine is not compatible with the MachineSet template,
+// i.e. if it is a control plane Machine, if it belongs to another Cluster, if it runs another Kubernetes version or
+// if its bootstrap config or its infrastructure machine are not of the kind created from the templates of the MachineSet.
+func validateMachineForAdoption(machineSet *clusterv1.MachineSet, machine *clusterv1.Machine) error {
+	if util.IsControlPlaneMachine(machine) {
+		return errors.New("Machine is a control plane Machine")
+	}
+
+	if machine.Spec.ClusterName != machineSet.Spec.ClusterName {
+		return errors.Errorf("Machine belongs to Cluster %q", machine.Spec.ClusterName)
+	}
+
+	template := machineSet.Spec.Template.Spec
+	if template.Version != nil && (machine.Spec.Version == nil || *machine.Spec.Version != *template.Version) {
+		return errors.Errorf("Machine version does not match the MachineSet version %s", *template.Version)
+	}
+
+	if !refMatchesTemplate(&machine.Spec.InfrastructureRef, &template.InfrastructureRef) {
+		return errors.Errorf("infrastructure machine %s is not compatible with the infrastructure template %s",
+			machine.Spec.InfrastructureRef.Kind, template.InfrastructureRef.Kind)
+	}
+
+	switch {
+	case template.Bootstrap.ConfigRef == nil && machine.Spec.Bootstrap.ConfigRef != nil:
+		return errors.Errorf("bootstrap config %s is not compatible with a MachineSet without a bootstrap template", machine.Spec.Bootstrap.ConfigRef.Kind)
+	case template.Bootstrap.ConfigRef != nil && !refMatchesTemplate(machine.Spec.Bootstrap.ConfigRef, template.Bootstrap.ConfigRef):
+		kind := "<none>"
+		if machine.Spec.Bootstrap.ConfigRef != nil {
+			kind = machine.Spec.Bootstrap.ConfigRef.Kind
+		}
+		return errors.Errorf("bootstrap config %s is not compatible with the bootstrap template %s", kind, template.Bootstrap.ConfigRef.Kind)
+	}
+	return nil
+}
+
+// validateClonedFromTemplates returns an error if the infrastructure machine or the bootstrap config of the Machine
+// have been cloned from templates other than the ones of the MachineSet, as recorded in their cloned-from annotations;
+// objects without these annotations, e.g. objects created manually, are not checked.
+// NOTE: This func assumes the Machine has been validated by validateMachineForAdoption.
+func (r *Reconciler) validateClonedFromTemplates(ctx context.Context, machineSet *clusterv1.MachineSet, machine *clusterv1.Machine) error {
+	type clonedRef struct {
+		ref, templateRef *corev1.ObjectReference
+	}
+	refs := []clonedRef{{ref: &machine.Spec.InfrastructureRef, templateRef: &machineSet.Spec.Template.Spec.InfrastructureRef}}
+	if machine.Spec.Bootstrap.ConfigRef != nil {
+		refs = append(refs, clonedRef{ref: machine.Spec.Bootstrap.ConfigRef, templateRef: machineSet.Spec.Template.Spec.Bootstrap.ConfigRef})
+	}
+
+	for _, c := range refs {
+		ref, templateRef := c.ref, c.templateRef
+		obj, err := external.Get(ctx, r.Client, ref, machine.Namespace)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get %s %s", ref.Kind, ref.Name)
+		}
+		name, ok := obj.GetAnnotations()[clusterv1.TemplateClonedFromNameAnnotation]
+		if !ok {
+			continue
+		}
+		groupKind := obj.GetAnnotations()[clusterv1.TemplateClonedFromGroupKindAnnotation]
+		if name != templateRef.Name || groupKind != templateRef.GroupVersionKind().GroupKind().String() {
+			return errors.Errorf("%s %s has been cloned from %s %s, not from the template %s of the MachineSet",
+				ref.Kind, ref.Name, groupKind, name, templateRef.Name)
+		}
+	}
+	return nil
+}
+
+// refMatchesTemplate returns true if ref is of the kind created from templateRef, e.g. a DockerMachine
+// for a DockerMachineTemplate, in the same API group.
+func refMatchesTemplate(ref, templateRef *corev1.ObjectReference) bool {
+	if ref == nil || templateRef == nil {
+		return false
+	}
+	refGV, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false
+	}
+	templateGV, err := schema.ParseGroupVersion(templateRef.APIVersion)
+	if err != nil {
+		return false
+	}
+	return refGV.Group == templateGV.Group && ref.Kind == strings.TrimSuffix(templateRef.Kind, clusterv1.TemplateSuffix)
+}
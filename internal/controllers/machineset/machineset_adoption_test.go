@@ -0,0 +1,219 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestValidateMachineForAdoption(t *testing.T) {
+	ms := &clusterv1.MachineSet{
+		Spec: clusterv1.MachineSetSpec{
+			ClusterName: "cluster",
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Version: pointer.String("v1.26.0"),
+					Bootstrap: clusterv1.Bootstrap{
+						ConfigRef: &corev1.ObjectReference{APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1", Kind: "KubeadmConfigTemplate"},
+					},
+					InfrastructureRef: corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "DockerMachineTemplate"},
+				},
+			},
+		},
+	}
+	compatibleMachine := func() *clusterv1.Machine {
+		return &clusterv1.Machine{
+			Spec: clusterv1.MachineSpec{
+				ClusterName: "cluster",
+				Version:     pointer.String("v1.26.0"),
+				Bootstrap: clusterv1.Bootstrap{
+					ConfigRef: &corev1.ObjectReference{APIVersion: "bootstrap.cluster.x-k8s.io/v1alpha4", Kind: "KubeadmConfig"},
+				},
+				InfrastructureRef: corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "DockerMachine"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(m *clusterv1.Machine)
+		wantErr bool
+	}{
+		{
+			name:   "compatible Machine, also with another API version",
+			mutate: func(m *clusterv1.Machine) {},
+		},
+		{
+			name: "control plane Machine",
+			mutate: func(m *clusterv1.Machine) {
+				m.Labels = map[string]string{clusterv1.MachineControlPlaneLabel: ""}
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Machine of another Cluster",
+			mutate:  func(m *clusterv1.Machine) { m.Spec.ClusterName = "other" },
+			wantErr: true,
+		},
+		{
+			name:    "Machine with another version",
+			mutate:  func(m *clusterv1.Machine) { m.Spec.Version = pointer.String("v1.25.0") },
+			wantErr: true,
+		},
+		{
+			name:    "Machine with another infrastructure kind",
+			mutate:  func(m *clusterv1.Machine) { m.Spec.InfrastructureRef.Kind = "AWSMachine" },
+			wantErr: true,
+		},
+		{
+			name: "Machine with another infrastructure API group",
+			mutate: func(m *clusterv1.Machine) {
+				m.Spec.InfrastructureRef.APIVersion = "infrastructure.example.com/v1beta1"
+			},
+			wantErr: true,
+		},
+		{
+			name: "Machine without a bootstrap config",
+			mutate: func(m *clusterv1.Machine) {
+				m.Spec.Bootstrap = clusterv1.Bootstrap{DataSecretName: pointer.String("bootstrap-data")}
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Machine with another bootstrap kind",
+			mutate:  func(m *clusterv1.Machine) { m.Spec.Bootstrap.ConfigRef.Kind = "TalosConfig" },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := compatibleMachine()
+			tt.mutate(m)
+			err := validateMachineForAdoption(ms, m)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestAdoptMachines(t *testing.T) {
+	g := NewWithT(t)
+
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   metav1.NamespaceDefault,
+			Name:        "ms",
+			UID:         "ms-uid",
+			Annotations: map[string]string{clusterv1.AdoptMachinesAnnotation: "cluster.x-k8s.io/set-name=old-ms"},
+		},
+		Spec: clusterv1.MachineSetSpec{
+			ClusterName: "cluster",
+			Template: clusterv1.MachineTemplateSpec{
+				ObjectMeta: clusterv1.ObjectMeta{Labels: map[string]string{"foo": "bar"}},
+				Spec: clusterv1.MachineSpec{
+					InfrastructureRef: corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "DockerMachineTemplate", Name: "md-template"},
+				},
+			},
+		},
+	}
+	newInfraMachine := func(name, clonedFrom string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta1")
+		u.SetKind("DockerMachine")
+		u.SetNamespace(metav1.NamespaceDefault)
+		u.SetName(name)
+		if clonedFrom != "" {
+			u.SetAnnotations(map[string]string{
+				clusterv1.TemplateClonedFromNameAnnotation:      clonedFrom,
+				clusterv1.TemplateClonedFromGroupKindAnnotation: "DockerMachineTemplate.infrastructure.cluster.x-k8s.io",
+			})
+		}
+		return u
+	}
+	newMachine := func(name, kind string, controlled bool) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel:    "cluster",
+					clusterv1.MachineSetNameLabel: "old-ms",
+				},
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName:       "cluster",
+				InfrastructureRef: corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: kind, Name: name},
+			},
+		}
+		if controlled {
+			m.OwnerReferences = []metav1.OwnerReference{{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineSet", Name: "other", UID: "other-uid", Controller: pointer.Bool(true)}}
+		}
+		return m
+	}
+	orphan := newMachine("orphan", "DockerMachine", false)
+	manual := newMachine("manual", "DockerMachine", false)
+	otherTemplate := newMachine("other-template", "DockerMachine", false)
+	incompatible := newMachine("incompatible", "AWSMachine", false)
+	controlled := newMachine("controlled", "DockerMachine", true)
+
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().WithObjects(
+			orphan, newInfraMachine("orphan", "md-template"),
+			manual, newInfraMachine("manual", ""),
+			otherTemplate, newInfraMachine("other-template", "other-template"),
+			incompatible, controlled,
+		).Build(),
+		recorder: record.NewFakeRecorder(10),
+	}
+	g.Expect(r.adoptMachines(ctx, ms)).To(Succeed())
+
+	got := &clusterv1.Machine{}
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(orphan), got)).To(Succeed())
+	g.Expect(metav1.IsControlledBy(got, ms)).To(BeTrue())
+	g.Expect(got.Labels).To(HaveKeyWithValue("foo", "bar"))
+	g.Expect(got.Labels).To(HaveKeyWithValue(clusterv1.MachineSetNameLabel, "ms"))
+
+	// Infrastructure machines created manually are not checked.
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(manual), got)).To(Succeed())
+	g.Expect(metav1.IsControlledBy(got, ms)).To(BeTrue())
+
+	// Infrastructure machines cloned from other templates are not adopted.
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(otherTemplate), got)).To(Succeed())
+	g.Expect(metav1.GetControllerOf(got)).To(BeNil())
+
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(incompatible), got)).To(Succeed())
+	g.Expect(metav1.GetControllerOf(got)).To(BeNil())
+
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(controlled), got)).To(Succeed())
+	g.Expect(metav1.IsControlledBy(got, ms)).To(BeFalse())
+}
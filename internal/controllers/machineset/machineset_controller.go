@@ -28,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/client-go/tools/record"
@@ -43,17 +44,20 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/controllers/remote"
-	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/machine"
 	capilabels "sigs.k8s.io/cluster-api/internal/labels"
+	"sigs.k8s.io/cluster-api/internal/profiling"
+	"sigs.k8s.io/cluster-api/internal/util/deletion"
+	"sigs.k8s.io/cluster-api/internal/util/naming"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
-	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/contract"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/paused"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
 
@@ -111,7 +115,10 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 			builder.WithPredicates(
 				// TODO: should this wait for Cluster.Status.InfrastructureReady similar to Infra Machine resources?
 				predicates.All(ctrl.LoggerFrom(ctx),
-					predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+					predicates.Any(ctrl.LoggerFrom(ctx),
+						predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+						predicates.ClusterPauseChanged(ctrl.LoggerFrom(ctx)),
+					),
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			),
@@ -126,7 +133,12 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, done := profiling.StartReconcile(ctx, "machineset")
+	defer done()
+
 	machineSet := &clusterv1.MachineSet{}
+	fetchDone := profiling.StartPhase(ctx, profiling.PhaseFetch)
+	defer fetchDone()
 	if err := r.Client.Get(ctx, req.NamespacedName, machineSet); err != nil {
 		if apierrors.IsNotFound(err) {
 			// Object not found, return. Created objects are automatically garbage collected.
@@ -147,15 +159,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	log = log.WithValues("Cluster", klog.KRef(machineSet.ObjectMeta.Namespace, machineSet.Spec.ClusterName))
 	ctx = ctrl.LoggerInto(ctx, log)
 
+	profiling.SetCluster(ctx, types.NamespacedName{Namespace: machineSet.Namespace, Name: machineSet.Spec.ClusterName})
 	cluster, err := util.GetClusterByName(ctx, r.Client, machineSet.ObjectMeta.Namespace, machineSet.Spec.ClusterName)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	fetchDone()
 
 	// Return early if the object or Cluster is paused.
-	if annotations.IsPaused(cluster, machineSet) {
+	isPaused, requeueAfter, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, machineSet)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if isPaused {
 		log.Info("Reconciliation is paused for this object")
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	// Initialize the patch helper
@@ -166,6 +184,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	defer func() {
 		// Always attempt to patch the object and status after each reconciliation.
+		defer profiling.StartPhase(ctx, profiling.PhasePatch)()
 		if err := patchMachineSet(ctx, patchHelper, machineSet); err != nil {
 			reterr = kerrors.NewAggregate([]error{reterr, err})
 		}
@@ -243,6 +262,12 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 			return ctrl.Result{}, err
 		}
 	}
+	// Make sure to reconcile the external auxiliary infrastructure references, if any.
+	for i := range machineSet.Spec.Template.Spec.AuxiliaryInfrastructureRefs {
+		if err := reconcileExternalTemplateReference(ctx, r.Client, cluster, &machineSet.Spec.Template.Spec.AuxiliaryInfrastructureRefs[i]); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
 	// Make sure selector and template to be in the same cluster.
 	if machineSet.Spec.Selector.MatchLabels == nil {
@@ -261,6 +286,11 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 		return ctrl.Result{}, errors.Wrapf(err, "failed to convert MachineSet %q label selector to a map", machineSet.Name)
 	}
 
+	// Adopt orphaned Machines if requested, so they are linked to this MachineSet.
+	if err := r.adoptMachines(ctx, machineSet); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Get all Machines linked to this MachineSet.
 	allMachines := &clusterv1.MachineList{}
 	err = r.Client.List(ctx,
@@ -307,11 +337,15 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 		}
 		if conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition) {
 			log.Info("Deleting machine because marked as unhealthy by the MachineHealthCheck controller")
-			patch := client.MergeFrom(machine.DeepCopy())
-			if err := r.Client.Delete(ctx, machine); err != nil {
+			if err := r.releaseProviderID(ctx, machineSet, machine); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := deletion.Delete(ctx, r.Client, r.recorder, machine, clusterv1.DeletionReasonRemediation, deletion.Initiator("MachineSet", machineSet)); err != nil {
 				errs = append(errs, errors.Wrap(err, "failed to delete"))
 				continue
 			}
+			patch := client.MergeFrom(machine.DeepCopy())
 			conditions.MarkTrue(machine, clusterv1.MachineOwnerRemediatedCondition)
 			if err := r.Client.Status().Patch(ctx, machine, patch); err != nil && !apierrors.IsNotFound(err) {
 				errs = append(errs, errors.Wrap(err, "failed to update status"))
@@ -330,9 +364,14 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 	}
 
 	syncErr := r.syncReplicas(ctx, machineSet, filteredMachines)
+	if syncErr == nil {
+		syncErr = r.syncStandbyReplicas(ctx, machineSet, filteredMachines)
+	}
 
 	// Always updates status as machines come up or die.
-	if err := r.updateStatus(ctx, cluster, machineSet, filteredMachines); err != nil {
+	// NOTE: Standby Machines are not counted as replicas.
+	activeMachines, _ := splitStandbyMachines(filteredMachines)
+	if err := r.updateStatus(ctx, cluster, machineSet, activeMachines); err != nil {
 		return ctrl.Result{}, errors.Wrapf(kerrors.NewAggregate([]error{err, syncErr}), "failed to update MachineSet's Status")
 	}
 
@@ -392,8 +431,11 @@ func (r *Reconciler) syncMachines(ctx context.Context, machineSet *clusterv1.Mac
 		}
 
 		// Update Machine to propagate in-place mutable fields from the MachineSet.
-		updatedMachine := r.computeDesiredMachine(machineSet, m)
-		err := ssa.Patch(ctx, r.Client, machineSetManagerName, updatedMachine, ssa.WithCachingProxy{Cache: r.ssaCache, Original: m})
+		updatedMachine, err := r.computeDesiredMachine(machineSet, m)
+		if err != nil {
+			return errors.Wrapf(err, "failed to update Machine %q", klog.KObj(m))
+		}
+		err = ssa.Patch(ctx, r.Client, machineSetManagerName, updatedMachine, ssa.WithCachingProxy{Cache: r.ssaCache, Original: m})
 		if err != nil {
 			log.Error(err, "failed to update Machine", "Machine", klog.KObj(updatedMachine))
 			return errors.Wrapf(err, "failed to update Machine %q", klog.KObj(updatedMachine))
@@ -444,67 +486,73 @@ func (r *Reconciler) syncMachines(ctx context.Context, machineSet *clusterv1.Mac
 }
 
 // syncReplicas scales Machine resources up or down.
+// Standby Machines are not counted as replicas; when scaling up, standby Machines are promoted before creating new Machines.
 func (r *Reconciler) syncReplicas(ctx context.Context, ms *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
 	log := ctrl.LoggerFrom(ctx)
 	if ms.Spec.Replicas == nil {
 		return errors.Errorf("the Replicas field in Spec for machineset %v is nil, this should not be allowed", ms.Name)
 	}
-	diff := len(machines) - int(*(ms.Spec.Replicas))
+	activeMachines, _ := splitStandbyMachines(machines)
+	diff := len(activeMachines) - int(*(ms.Spec.Replicas))
 	switch {
 	case diff < 0:
 		diff *= -1
-		log.Info(fmt.Sprintf("MachineSet is scaling up to %d replicas by creating %d machines", *(ms.Spec.Replicas), diff), "replicas", *(ms.Spec.Replicas), "machineCount", len(machines))
+		log.Info(fmt.Sprintf("MachineSet is scaling up to %d replicas by creating %d machines", *(ms.Spec.Replicas), diff), "replicas", *(ms.Spec.Replicas), "machineCount", len(activeMachines))
 		if ms.Annotations != nil {
 			if _, ok := ms.Annotations[clusterv1.DisableMachineCreateAnnotation]; ok {
 				log.Info("Automatic creation of new machines disabled for machine set")
 				return nil
 			}
 		}
-		var (
-			machineList []*clusterv1.Machine
-			errs        []error
-		)
-
-		for i := 0; i < diff; i++ {
-			// Create a new logger so the global logger is not modified.
-			log := log
-			machine := r.computeDesiredMachine(ms, nil)
-			// Clone and set the infrastructure and bootstrap references.
-			var (
-				infraRef, bootstrapRef *corev1.ObjectReference
-				err                    error
-			)
-
-			// Create the BootstrapConfig if necessary.
-			if ms.Spec.Template.Spec.Bootstrap.ConfigRef != nil {
-				bootstrapRef, err = external.CreateFromTemplate(ctx, &external.CreateFromTemplateInput{
-					Client:      r.Client,
-					TemplateRef: ms.Spec.Template.Spec.Bootstrap.ConfigRef,
-					Namespace:   machine.Namespace,
-					ClusterName: machine.Spec.ClusterName,
-					Labels:      machine.Labels,
-					Annotations: machine.Annotations,
-					OwnerRef: &metav1.OwnerReference{
-						APIVersion: clusterv1.GroupVersion.String(),
-						Kind:       "MachineSet",
-						Name:       ms.Name,
-						UID:        ms.UID,
-					},
-				})
-				if err != nil {
-					conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.BootstrapTemplateCloningFailedReason, clusterv1.ConditionSeverityError, err.Error())
-					return errors.Wrapf(err, "failed to clone bootstrap configuration from %s %s while creating a machine",
-						ms.Spec.Template.Spec.Bootstrap.ConfigRef.Kind,
-						klog.KRef(ms.Spec.Template.Spec.Bootstrap.ConfigRef.Namespace, ms.Spec.Template.Spec.Bootstrap.ConfigRef.Name))
-				}
-				machine.Spec.Bootstrap.ConfigRef = bootstrapRef
-				log = log.WithValues(bootstrapRef.Kind, klog.KRef(bootstrapRef.Namespace, bootstrapRef.Name))
-			}
 
-			// Create the InfraMachine.
-			infraRef, err = external.CreateFromTemplate(ctx, &external.CreateFromTemplateInput{
+		promoted, err := r.promoteStandbyMachines(ctx, ms, machines, diff)
+		if err != nil {
+			return err
+		}
+		if diff -= promoted; diff == 0 {
+			return nil
+		}
+		return r.createMachines(ctx, ms, diff, false)
+	case diff > 0:
+		log.Info(fmt.Sprintf("MachineSet is scaling down to %d replicas by deleting %d machines", *(ms.Spec.Replicas), diff), "replicas", *(ms.Spec.Replicas), "machineCount", len(activeMachines), "deletePolicy", ms.Spec.DeletePolicy)
+
+		deletePriorityFunc, err := getDeletePriorityFunc(ms)
+		if err != nil {
+			return err
+		}
+
+		machinesToDelete := getMachinesToDeletePrioritized(activeMachines, diff, deletePriorityFunc)
+		return r.deleteMachines(ctx, ms, machinesToDelete)
+	}
+
+	return nil
+}
+
+// createMachines creates the given number of Machines, together with their InfrastructureMachines and BootstrapConfigs;
+// if standby is true, the Machines are created as standby Machines.
+func (r *Reconciler) createMachines(ctx context.Context, ms *clusterv1.MachineSet, count int, standby bool) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	var (
+		machineList []*clusterv1.Machine
+		errs        []error
+	)
+
+	for i := 0; i < count; i++ {
+		// Create a new logger so the global logger is not modified.
+		log := log
+		machine, err := r.computeDesiredMachine(ms, nil)
+		if err != nil {
+			return errors.Wrap(err, "failed to create Machine")
+		}
+		// Clone and set the infrastructure and bootstrap references.
+		var infraRef, bootstrapRef *corev1.ObjectReference
+
+		// Create the BootstrapConfig if necessary.
+		if ms.Spec.Template.Spec.Bootstrap.ConfigRef != nil {
+			bootstrapRef, err = external.CreateFromTemplate(ctx, &external.CreateFromTemplateInput{
 				Client:      r.Client,
-				TemplateRef: &ms.Spec.Template.Spec.InfrastructureRef,
+				TemplateRef: ms.Spec.Template.Spec.Bootstrap.ConfigRef,
 				Namespace:   machine.Namespace,
 				ClusterName: machine.Spec.ClusterName,
 				Labels:      machine.Labels,
@@ -517,76 +565,156 @@ func (r *Reconciler) syncReplicas(ctx context.Context, ms *clusterv1.MachineSet,
 				},
 			})
 			if err != nil {
-				conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.InfrastructureTemplateCloningFailedReason, clusterv1.ConditionSeverityError, err.Error())
-				return errors.Wrapf(err, "failed to clone infrastructure machine from %s %s while creating a machine",
-					ms.Spec.Template.Spec.InfrastructureRef.Kind,
-					klog.KRef(ms.Spec.Template.Spec.InfrastructureRef.Namespace, ms.Spec.Template.Spec.InfrastructureRef.Name))
+				conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.BootstrapTemplateCloningFailedReason, clusterv1.ConditionSeverityError, err.Error())
+				return errors.Wrapf(err, "failed to clone bootstrap configuration from %s %s while creating a machine",
+					ms.Spec.Template.Spec.Bootstrap.ConfigRef.Kind,
+					klog.KRef(ms.Spec.Template.Spec.Bootstrap.ConfigRef.Namespace, ms.Spec.Template.Spec.Bootstrap.ConfigRef.Name))
 			}
-			log = log.WithValues(infraRef.Kind, klog.KRef(infraRef.Namespace, infraRef.Name))
-			machine.Spec.InfrastructureRef = *infraRef
-
-			// Create the Machine.
-			if err := ssa.Patch(ctx, r.Client, machineSetManagerName, machine); err != nil {
-				log.Error(err, "Error while creating a machine")
-				r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedCreate", "Failed to create machine: %v", err)
-				errs = append(errs, err)
-				conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.MachineCreationFailedReason,
-					clusterv1.ConditionSeverityError, err.Error())
+			machine.Spec.Bootstrap.ConfigRef = bootstrapRef
+			log = log.WithValues(bootstrapRef.Kind, klog.KRef(bootstrapRef.Namespace, bootstrapRef.Name))
+		}
 
-				// Try to cleanup the external objects if the Machine creation failed.
-				if err := r.Client.Delete(ctx, util.ObjectReferenceToUnstructured(*infraRef)); !apierrors.IsNotFound(err) {
-					log.Error(err, "Failed to cleanup infrastructure machine object after Machine creation error", infraRef.Kind, klog.KRef(infraRef.Namespace, infraRef.Name))
-				}
-				if bootstrapRef != nil {
-					if err := r.Client.Delete(ctx, util.ObjectReferenceToUnstructured(*bootstrapRef)); !apierrors.IsNotFound(err) {
-						log.Error(err, "Failed to cleanup bootstrap configuration object after Machine creation error", bootstrapRef.Kind, klog.KRef(bootstrapRef.Namespace, bootstrapRef.Name))
-					}
-				}
-				continue
+		// Create the InfraMachine.
+		// If the node reuse policy is enabled, hint the infrastructure provider to reuse a released host.
+		// NOTE: The hint is set only on the InfraMachine given that annotations on the Machine are kept in sync with the MachineSet.
+		infraAnnotations := machine.Annotations
+		reuseProviderID, err := r.claimProviderID(ctx, ms)
+		if err != nil {
+			// Try to cleanup the BootstrapConfig created so far.
+			r.cleanupFailedMachineCreation(ctx, ms, machine, "")
+			return errors.Wrap(err, "failed to create Machine")
+		}
+		if reuseProviderID != "" {
+			infraAnnotations = map[string]string{}
+			for k, v := range machine.Annotations {
+				infraAnnotations[k] = v
 			}
-
-			log.Info(fmt.Sprintf("Created machine %d of %d", i+1, diff), "Machine", klog.KObj(machine))
-			r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulCreate", "Created machine %q", machine.Name)
-			machineList = append(machineList, machine)
+			infraAnnotations[clusterv1.ReuseProviderIDAnnotation] = reuseProviderID
+			log = log.WithValues("reuseProviderID", reuseProviderID)
 		}
-
-		if len(errs) > 0 {
-			return kerrors.NewAggregate(errs)
+		infraRef, err = external.CreateFromTemplate(ctx, &external.CreateFromTemplateInput{
+			Client:      r.Client,
+			TemplateRef: &ms.Spec.Template.Spec.InfrastructureRef,
+			Namespace:   machine.Namespace,
+			ClusterName: machine.Spec.ClusterName,
+			Labels:      machine.Labels,
+			Annotations: infraAnnotations,
+			OwnerRef: &metav1.OwnerReference{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "MachineSet",
+				Name:       ms.Name,
+				UID:        ms.UID,
+			},
+		})
+		if err != nil {
+			conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.InfrastructureTemplateCloningFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			// Try to cleanup the BootstrapConfig created so far and to return the claimed provider ID for reuse.
+			r.cleanupFailedMachineCreation(ctx, ms, machine, reuseProviderID)
+			return errors.Wrapf(err, "failed to clone infrastructure machine from %s %s while creating a machine",
+				ms.Spec.Template.Spec.InfrastructureRef.Kind,
+				klog.KRef(ms.Spec.Template.Spec.InfrastructureRef.Namespace, ms.Spec.Template.Spec.InfrastructureRef.Name))
 		}
-		return r.waitForMachineCreation(ctx, machineList)
-	case diff > 0:
-		log.Info(fmt.Sprintf("MachineSet is scaling down to %d replicas by deleting %d machines", *(ms.Spec.Replicas), diff), "replicas", *(ms.Spec.Replicas), "machineCount", len(machines), "deletePolicy", ms.Spec.DeletePolicy)
+		log = log.WithValues(infraRef.Kind, klog.KRef(infraRef.Namespace, infraRef.Name))
+		machine.Spec.InfrastructureRef = *infraRef
 
-		deletePriorityFunc, err := getDeletePriorityFunc(ms)
+		// Create the auxiliary infrastructure objects, if any.
+		machine.Spec.AuxiliaryInfrastructureRefs, err = r.createAuxiliaryInfrastructure(ctx, ms, machine)
 		if err != nil {
+			// Try to cleanup the objects created so far and to return the claimed provider ID for reuse.
+			r.cleanupFailedMachineCreation(ctx, ms, machine, reuseProviderID)
 			return err
 		}
 
-		var errs []error
-		machinesToDelete := getMachinesToDeletePrioritized(machines, diff, deletePriorityFunc)
-		for i, machine := range machinesToDelete {
-			log := log.WithValues("Machine", klog.KObj(machine))
-			if machine.GetDeletionTimestamp().IsZero() {
-				log.Info(fmt.Sprintf("Deleting machine %d of %d", i+1, diff))
-				if err := r.Client.Delete(ctx, machine); err != nil {
-					log.Error(err, "Unable to delete Machine")
-					r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedDelete", "Failed to delete machine %q: %v", machine.Name, err)
-					errs = append(errs, err)
-					continue
-				}
-				r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulDelete", "Deleted machine %q", machine.Name)
-			} else {
-				log.Info(fmt.Sprintf("Waiting for machine %d of %d to be deleted", i+1, diff))
-			}
+		// Create the Machine.
+		if standby {
+			markStandbyMachine(machine)
+		}
+		if err := ssa.Patch(ctx, r.Client, machineSetManagerName, machine); err != nil {
+			log.Error(err, "Error while creating a machine")
+			r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedCreate", "Failed to create machine: %v", err)
+			errs = append(errs, err)
+			conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.MachineCreationFailedReason,
+				clusterv1.ConditionSeverityError, err.Error())
+
+			// Try to cleanup the external objects and to return the claimed provider ID for reuse if the Machine creation failed.
+			r.cleanupFailedMachineCreation(ctx, ms, machine, reuseProviderID)
+			continue
 		}
 
-		if len(errs) > 0 {
-			return kerrors.NewAggregate(errs)
+		log.Info(fmt.Sprintf("Created machine %d of %d", i+1, count), "Machine", klog.KObj(machine))
+		r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulCreate", "Created machine %q", machine.Name)
+		machineList = append(machineList, machine)
+	}
+
+	if len(errs) > 0 {
+		return kerrors.NewAggregate(errs)
+	}
+	return r.waitForMachineCreation(ctx, machineList)
+}
+
+// cleanupFailedMachineCreation deletes the external objects created for a Machine whose creation failed, and it returns
+// the provider ID claimed for the Machine, if any, to the released provider IDs.
+// NOTE: Errors are only logged, given that they must not hide the error which made the Machine creation fail.
+func (r *Reconciler) cleanupFailedMachineCreation(ctx context.Context, ms *clusterv1.MachineSet, machine *clusterv1.Machine, claimedProviderID string) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if ref := machine.Spec.InfrastructureRef; ref.Name != "" {
+		if err := r.Client.Delete(ctx, util.ObjectReferenceToUnstructured(ref)); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to cleanup infrastructure machine object after Machine creation error", ref.Kind, klog.KRef(ref.Namespace, ref.Name))
+		}
+	}
+	if ref := machine.Spec.Bootstrap.ConfigRef; ref != nil {
+		if err := r.Client.Delete(ctx, util.ObjectReferenceToUnstructured(*ref)); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to cleanup bootstrap configuration object after Machine creation error", ref.Kind, klog.KRef(ref.Namespace, ref.Name))
 		}
-		return r.waitForMachineDeletion(ctx, machinesToDelete)
 	}
+	for _, ref := range machine.Spec.AuxiliaryInfrastructureRefs {
+		if err := r.Client.Delete(ctx, util.ObjectReferenceToUnstructured(ref)); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to cleanup auxiliary infrastructure object after Machine creation error", ref.Kind, klog.KRef(ref.Namespace, ref.Name))
+		}
+	}
+	if err := r.unclaimProviderID(ctx, ms, claimedProviderID); err != nil {
+		log.Error(err, "Failed to return the claimed provider ID for reuse after Machine creation error", "providerID", claimedProviderID)
+	}
+}
 
-	return nil
+// deleteMachines deletes the given Machines and waits for the deletion to be observed in the cache.
+func (r *Reconciler) deleteMachines(ctx context.Context, ms *clusterv1.MachineSet, machinesToDelete []*clusterv1.Machine) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Machines are deleted because the MachineSet has been scaled down, unless the MachineDeployment
+	// owning the MachineSet recorded a different reason, e.g. a rollout.
+	reason, initiator := deletion.Get(ms)
+	if reason == "" {
+		reason, initiator = clusterv1.DeletionReasonScaleDown, deletion.Initiator("MachineSet", ms)
+	}
+
+	var errs []error
+	for i, machine := range machinesToDelete {
+		log := log.WithValues("Machine", klog.KObj(machine))
+		if machine.GetDeletionTimestamp().IsZero() {
+			log.Info(fmt.Sprintf("Deleting machine %d of %d", i+1, len(machinesToDelete)))
+			if err := r.releaseProviderID(ctx, ms, machine); err != nil {
+				log.Error(err, "Unable to release provider ID for reuse")
+				errs = append(errs, err)
+				continue
+			}
+			if err := deletion.Delete(ctx, r.Client, r.recorder, machine, reason, initiator); err != nil {
+				log.Error(err, "Unable to delete Machine")
+				r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedDelete", "Failed to delete machine %q: %v", machine.Name, err)
+				errs = append(errs, err)
+				continue
+			}
+			r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulDelete", "Deleted machine %q, reason: %s", machine.Name, reason)
+		} else {
+			log.Info(fmt.Sprintf("Waiting for machine %d of %d to be deleted", i+1, len(machinesToDelete)))
+		}
+	}
+
+	if len(errs) > 0 {
+		return kerrors.NewAggregate(errs)
+	}
+	return r.waitForMachineDeletion(ctx, machinesToDelete)
 }
 
 // computeDesiredMachine computes the desired Machine.
@@ -597,14 +725,27 @@ func (r *Reconciler) syncReplicas(ctx context.Context, ms *clusterv1.MachineSet,
 // There are small differences in how we calculate the Machine depending on if it
 // is a create or update. Example: for a new Machine we have to calculate a new name,
 // while for an existing Machine we have to use the name of the existing Machine.
-func (r *Reconciler) computeDesiredMachine(machineSet *clusterv1.MachineSet, existingMachine *clusterv1.Machine) *clusterv1.Machine {
+func (r *Reconciler) computeDesiredMachine(machineSet *clusterv1.MachineSet, existingMachine *clusterv1.Machine) (*clusterv1.Machine, error) {
+	name := names.SimpleNameGenerator.GenerateName(fmt.Sprintf("%s-", machineSet.Name))
+	if existingMachine == nil && machineSet.Spec.MachineNamingStrategy != nil && machineSet.Spec.MachineNamingStrategy.Template != "" {
+		var err error
+		name, err = naming.Generate(machineSet.Spec.MachineNamingStrategy.Template, naming.Values{
+			Cluster:           machineSet.Spec.ClusterName,
+			MachineDeployment: machineSet.Labels[clusterv1.MachineDeploymentNameLabel],
+			MachineSet:        machineSet.Name,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to compute Machine name")
+		}
+	}
+
 	desiredMachine := &clusterv1.Machine{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: clusterv1.GroupVersion.String(),
 			Kind:       "Machine",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      names.SimpleNameGenerator.GenerateName(fmt.Sprintf("%s-", machineSet.Name)),
+			Name:      name,
 			Namespace: machineSet.Namespace,
 			// Note: By setting the ownerRef on creation we signal to the Machine controller that this is not a stand-alone Machine.
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(machineSet, machineSetKind)},
@@ -625,6 +766,7 @@ func (r *Reconciler) computeDesiredMachine(machineSet *clusterv1.MachineSet, exi
 	// objects are created.
 	desiredMachine.Spec.InfrastructureRef = corev1.ObjectReference{}
 	desiredMachine.Spec.Bootstrap.ConfigRef = nil
+	desiredMachine.Spec.AuxiliaryInfrastructureRefs = nil
 
 	// If we are updating an existing Machine reuse the name, uid, infrastructureRef and bootstrap.configRef
 	// from the existingMachine.
@@ -635,6 +777,7 @@ func (r *Reconciler) computeDesiredMachine(machineSet *clusterv1.MachineSet, exi
 		desiredMachine.SetUID(existingMachine.UID)
 		desiredMachine.Spec.Bootstrap.ConfigRef = existingMachine.Spec.Bootstrap.ConfigRef
 		desiredMachine.Spec.InfrastructureRef = existingMachine.Spec.InfrastructureRef
+		desiredMachine.Spec.AuxiliaryInfrastructureRefs = existingMachine.Spec.AuxiliaryInfrastructureRefs
 	}
 
 	// Set the in-place mutable fields.
@@ -651,8 +794,47 @@ func (r *Reconciler) computeDesiredMachine(machineSet *clusterv1.MachineSet, exi
 	desiredMachine.Spec.NodeDrainTimeout = machineSet.Spec.Template.Spec.NodeDrainTimeout
 	desiredMachine.Spec.NodeDeletionTimeout = machineSet.Spec.Template.Spec.NodeDeletionTimeout
 	desiredMachine.Spec.NodeVolumeDetachTimeout = machineSet.Spec.Template.Spec.NodeVolumeDetachTimeout
+	desiredMachine.Spec.Taints = machineSet.Spec.Template.Spec.Taints
+	desiredMachine.Spec.InfrastructureReusePolicy = machineSet.Spec.Template.Spec.InfrastructureReusePolicy
+	desiredMachine.Spec.NodeDrainOptions = machineSet.Spec.Template.Spec.NodeDrainOptions
 
-	return desiredMachine
+	// Preserve the standby marker on existing standby Machines; it is dropped only when the Machine is promoted.
+	if existingMachine != nil && isStandbyMachine(existingMachine) {
+		markStandbyMachine(desiredMachine)
+	}
+
+	return desiredMachine, nil
+}
+
+// createAuxiliaryInfrastructure clones the auxiliary infrastructure templates of the MachineSet for a new Machine,
+// and returns the references to the cloned objects; in case of error, the references to the objects cloned before
+// the error are returned, so they can be cleaned up.
+func (r *Reconciler) createAuxiliaryInfrastructure(ctx context.Context, ms *clusterv1.MachineSet, machine *clusterv1.Machine) ([]corev1.ObjectReference, error) {
+	var refs []corev1.ObjectReference
+	for i := range ms.Spec.Template.Spec.AuxiliaryInfrastructureRefs {
+		templateRef := &ms.Spec.Template.Spec.AuxiliaryInfrastructureRefs[i]
+		ref, err := external.CreateFromTemplate(ctx, &external.CreateFromTemplateInput{
+			Client:      r.Client,
+			TemplateRef: templateRef,
+			Namespace:   machine.Namespace,
+			ClusterName: machine.Spec.ClusterName,
+			Labels:      machine.Labels,
+			Annotations: machine.Annotations,
+			OwnerRef: &metav1.OwnerReference{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "MachineSet",
+				Name:       ms.Name,
+				UID:        ms.UID,
+			},
+		})
+		if err != nil {
+			conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.InfrastructureTemplateCloningFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return refs, errors.Wrapf(err, "failed to clone auxiliary infrastructure from %s %s while creating a machine",
+				templateRef.Kind, klog.KRef(templateRef.Namespace, templateRef.Name))
+		}
+		refs = append(refs, *ref)
+	}
+	return refs, nil
 }
 
 // updateExternalObject updates the external object passed in with the
@@ -924,6 +1106,11 @@ func (r *Reconciler) updateStatus(ctx context.Context, cluster *clusterv1.Cluste
 		newStatus.ObservedGeneration = ms.Generation
 		newStatus.DeepCopyInto(&ms.Status)
 	}
+
+	// Surface the machines holding the rollout or scale operation, so users don't have to correlate the conditions of
+	// the machines to find out why the operation is not progressing.
+	ms.Status.Rollout = calculateRolloutStatus(filteredMachines)
+
 	switch {
 	// We are scaling up
 	case newStatus.Replicas < desiredReplicas:
@@ -33,11 +33,11 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
-	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/contract"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
 
@@ -894,6 +894,126 @@ func TestMachineSetReconcile_MachinesCreatedConditionFalseOnBadInfraRef(t *testi
 	g.Expect(gotCond.Reason).To(Equal(clusterv1.InfrastructureTemplateCloningFailedReason))
 }
 
+func TestMachineSetReconciler_createMachinesCleanupOnAuxiliaryInfrastructureError(t *testing.T) {
+	g := NewWithT(t)
+
+	infraTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra-template").Build()
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ms",
+			Namespace: metav1.NamespaceDefault,
+			Annotations: map[string]string{
+				clusterv1.NodeReuseAnnotation:           "",
+				clusterv1.ReleasedProviderIDsAnnotation: "host-1,host-2",
+			},
+		},
+		Spec: clusterv1.MachineSetSpec{
+			ClusterName: "cluster",
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "cluster",
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: infraTemplate.GetAPIVersion(),
+						Kind:       infraTemplate.GetKind(),
+						Name:       infraTemplate.GetName(),
+						Namespace:  infraTemplate.GetNamespace(),
+					},
+					AuxiliaryInfrastructureRefs: []corev1.ObjectReference{
+						{
+							APIVersion: infraTemplate.GetAPIVersion(),
+							Kind:       infraTemplate.GetKind(),
+							Name:       infraTemplate.GetName(),
+							Namespace:  infraTemplate.GetNamespace(),
+						},
+						{
+							// Try to break the cloning of the second auxiliary infrastructure object.
+							APIVersion: infraTemplate.GetAPIVersion(),
+							Kind:       infraTemplate.GetKind(),
+							Name:       "does-not-exist",
+							Namespace:  infraTemplate.GetNamespace(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithObjects(infraTemplate, builder.GenericInfrastructureMachineTemplateCRD.DeepCopy()).Build()
+	r := &Reconciler{
+		Client:   fakeClient,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	g.Expect(r.createMachines(ctx, ms, 1, false)).ToNot(Succeed())
+
+	// The InfraMachine and the auxiliary infrastructure object created before the error are deleted.
+	infraMachines := &unstructured.UnstructuredList{}
+	infraMachines.SetAPIVersion(builder.InfrastructureGroupVersion.String())
+	infraMachines.SetKind(builder.GenericInfrastructureMachineKind + "List")
+	g.Expect(fakeClient.List(ctx, infraMachines, client.InNamespace(metav1.NamespaceDefault))).To(Succeed())
+	g.Expect(infraMachines.Items).To(BeEmpty())
+
+	// The claimed provider ID is returned for reuse.
+	g.Expect(ms.Annotations).To(HaveKeyWithValue(clusterv1.ReleasedProviderIDsAnnotation, "host-1,host-2"))
+}
+
+func TestMachineSetReconciler_createMachinesCleanupOnInfrastructureError(t *testing.T) {
+	g := NewWithT(t)
+
+	bootstrapTemplate := builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap-template").Build()
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ms",
+			Namespace: metav1.NamespaceDefault,
+			Annotations: map[string]string{
+				clusterv1.NodeReuseAnnotation:           "",
+				clusterv1.ReleasedProviderIDsAnnotation: "host-1",
+			},
+		},
+		Spec: clusterv1.MachineSetSpec{
+			ClusterName: "cluster",
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "cluster",
+					Bootstrap: clusterv1.Bootstrap{
+						ConfigRef: &corev1.ObjectReference{
+							APIVersion: bootstrapTemplate.GetAPIVersion(),
+							Kind:       bootstrapTemplate.GetKind(),
+							Name:       bootstrapTemplate.GetName(),
+							Namespace:  bootstrapTemplate.GetNamespace(),
+						},
+					},
+					InfrastructureRef: corev1.ObjectReference{
+						// Try to break Infra Cloning
+						APIVersion: builder.InfrastructureGroupVersion.String(),
+						Kind:       builder.GenericInfrastructureMachineTemplateKind,
+						Name:       "does-not-exist",
+						Namespace:  metav1.NamespaceDefault,
+					},
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithObjects(bootstrapTemplate, builder.GenericBootstrapConfigTemplateCRD.DeepCopy()).Build()
+	r := &Reconciler{
+		Client:   fakeClient,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	g.Expect(r.createMachines(ctx, ms, 1, false)).ToNot(Succeed())
+
+	// The BootstrapConfig created before the error is deleted.
+	bootstrapConfigs := &unstructured.UnstructuredList{}
+	bootstrapConfigs.SetAPIVersion(builder.BootstrapGroupVersion.String())
+	bootstrapConfigs.SetKind(builder.GenericBootstrapConfigKind + "List")
+	g.Expect(fakeClient.List(ctx, bootstrapConfigs, client.InNamespace(metav1.NamespaceDefault))).To(Succeed())
+	g.Expect(bootstrapConfigs.Items).To(BeEmpty())
+
+	// The claimed provider ID is returned for reuse.
+	g.Expect(ms.Annotations).To(HaveKeyWithValue(clusterv1.ReleasedProviderIDsAnnotation, "host-1"))
+}
+
 func TestMachineSetReconciler_updateStatusResizedCondition(t *testing.T) {
 	cluster := &clusterv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1250,6 +1370,9 @@ func TestMachineSetReconciler_syncMachines(t *testing.T) {
 	ms.Spec.Template.Spec.NodeDrainTimeout = duration10s
 	ms.Spec.Template.Spec.NodeDeletionTimeout = duration10s
 	ms.Spec.Template.Spec.NodeVolumeDetachTimeout = duration10s
+	ms.Spec.Template.Spec.Taints = []corev1.Taint{{Key: "example.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}}
+	ms.Spec.Template.Spec.InfrastructureReusePolicy = clusterv1.InfrastructureReusePolicyRelease
+	ms.Spec.Template.Spec.NodeDrainOptions = &clusterv1.NodeDrainOptions{GracePeriodSeconds: pointer.Int32(30)}
 	g.Expect(reconciler.syncMachines(ctx, ms, []*clusterv1.Machine{updatedInPlaceMutatingMachine, deletingMachine})).To(Succeed())
 
 	// Verify in-place mutable fields are updated on the Machine.
@@ -1273,6 +1396,12 @@ func TestMachineSetReconciler_syncMachines(t *testing.T) {
 			Not(BeNil()),
 			HaveValue(Equal(*ms.Spec.Template.Spec.NodeVolumeDetachTimeout)),
 		))
+		// Verify Node taints
+		g.Expect(updatedInPlaceMutatingMachine.Spec.Taints).Should(Equal(ms.Spec.Template.Spec.Taints))
+		// Verify infrastructure reuse policy
+		g.Expect(updatedInPlaceMutatingMachine.Spec.InfrastructureReusePolicy).Should(Equal(ms.Spec.Template.Spec.InfrastructureReusePolicy))
+		// Verify node drain options
+		g.Expect(updatedInPlaceMutatingMachine.Spec.NodeDrainOptions).Should(Equal(ms.Spec.Template.Spec.NodeDrainOptions))
 	}, timeout).Should(Succeed())
 
 	// Verify in-place mutable fields are updated on InfrastructureMachine
@@ -1422,28 +1551,54 @@ func TestComputeDesiredMachine(t *testing.T) {
 	expectedUpdatedMachine.Spec.InfrastructureRef = *existingMachine.Spec.InfrastructureRef.DeepCopy()
 	expectedUpdatedMachine.Spec.Bootstrap.ConfigRef = existingMachine.Spec.Bootstrap.ConfigRef.DeepCopy()
 
+	machineNamingStrategy := &clusterv1.MachineNamingStrategy{
+		Template: "{{ .cluster }}-{{ .machineDeployment }}-{{ .random }}",
+	}
+
 	tests := []struct {
-		name            string
-		existingMachine *clusterv1.Machine
-		want            *clusterv1.Machine
+		name                  string
+		machineNamingStrategy *clusterv1.MachineNamingStrategy
+		existingMachine       *clusterv1.Machine
+		want                  *clusterv1.Machine
+		wantName              string
 	}{
 		{
 			name:            "creating a new Machine",
 			existingMachine: nil,
 			want:            expectedNewMachine,
+			wantName:        "^ms1-[a-z0-9]{5}$",
+		},
+		{
+			name:                  "creating a new Machine with a MachineNamingStrategy",
+			machineNamingStrategy: machineNamingStrategy,
+			existingMachine:       nil,
+			want:                  expectedNewMachine,
+			wantName:              "^test-cluster-md1-[a-z0-9]{5}$",
 		},
 		{
 			name:            "updating an existing Machine",
 			existingMachine: existingMachine,
 			want:            expectedUpdatedMachine,
 		},
+		{
+			name:                  "updating an existing Machine with a MachineNamingStrategy",
+			machineNamingStrategy: machineNamingStrategy,
+			existingMachine:       existingMachine,
+			want:                  expectedUpdatedMachine,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
-			got := (&Reconciler{}).computeDesiredMachine(ms, tt.existingMachine)
+			ms := ms.DeepCopy()
+			ms.Spec.MachineNamingStrategy = tt.machineNamingStrategy
+			got, err := (&Reconciler{}).computeDesiredMachine(ms, tt.existingMachine)
+			g.Expect(err).ToNot(HaveOccurred())
 			assertMachine(g, got, tt.want)
+			if tt.wantName != "" {
+				g.Expect(got.Name).To(MatchRegexp(tt.wantName))
+			}
 		})
 	}
 }
@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// maxReleasedProviderIDs is the maximum number of released provider IDs tracked for reuse.
+const maxReleasedProviderIDs = 10
+
+// isNodeReuseEnabled returns true if the node reuse policy is enabled for the MachineSet.
+func isNodeReuseEnabled(ms *clusterv1.MachineSet) bool {
+	_, ok := ms.Annotations[clusterv1.NodeReuseAnnotation]
+	return ok
+}
+
+// releaseProviderID records the provider ID of a Machine being deleted by the MachineSet, so the corresponding
+// infrastructure host can be reused by the Machines created afterwards.
+// NOTE: Released provider IDs are tracked on the MachineDeployment owning the MachineSet, if any, so they can be
+// reused also by the Machines of the new MachineSet during a rollout.
+func (r *Reconciler) releaseProviderID(ctx context.Context, ms *clusterv1.MachineSet, machine *clusterv1.Machine) error {
+	if !isNodeReuseEnabled(ms) || machine.Spec.ProviderID == nil || *machine.Spec.ProviderID == "" {
+		return nil
+	}
+
+	log := ctrl.LoggerFrom(ctx).WithValues("Machine", klog.KObj(machine))
+	log.V(4).Info("Releasing provider ID for reuse", "providerID", *machine.Spec.ProviderID)
+	return r.updateReleasedProviderIDs(ctx, ms, func(providerIDs []string) []string {
+		for _, providerID := range providerIDs {
+			if providerID == *machine.Spec.ProviderID {
+				return providerIDs
+			}
+		}
+		providerIDs = append(providerIDs, *machine.Spec.ProviderID)
+		if len(providerIDs) > maxReleasedProviderIDs {
+			providerIDs = providerIDs[len(providerIDs)-maxReleasedProviderIDs:]
+		}
+		return providerIDs
+	})
+}
+
+// claimProviderID returns the first released provider ID and removes it from the released provider IDs;
+// it returns an empty string if there are no released provider IDs.
+func (r *Reconciler) claimProviderID(ctx context.Context, ms *clusterv1.MachineSet) (string, error) {
+	if !isNodeReuseEnabled(ms) {
+		return "", nil
+	}
+
+	claimed := ""
+	err := r.updateReleasedProviderIDs(ctx, ms, func(providerIDs []string) []string {
+		if len(providerIDs) == 0 {
+			return providerIDs
+		}
+		claimed = providerIDs[0]
+		return providerIDs[1:]
+	})
+	return claimed, err
+}
+
+// unclaimProviderID returns a claimed provider ID to the released provider IDs, e.g. when the creation of the Machine
+// it was claimed for failed; the provider ID is returned first in line, so it is claimed again by the next Machine.
+func (r *Reconciler) unclaimProviderID(ctx context.Context, ms *clusterv1.MachineSet, providerID string) error {
+	if providerID == "" {
+		return nil
+	}
+
+	return r.updateReleasedProviderIDs(ctx, ms, func(providerIDs []string) []string {
+		for _, id := range providerIDs {
+			if id == providerID {
+				return providerIDs
+			}
+		}
+		providerIDs = append([]string{providerID}, providerIDs...)
+		if len(providerIDs) > maxReleasedProviderIDs {
+			providerIDs = providerIDs[:maxReleasedProviderIDs]
+		}
+		return providerIDs
+	})
+}
+
+// updateReleasedProviderIDs updates the released provider IDs tracked on the MachineDeployment owning the MachineSet,
+// if any, otherwise on the MachineSet itself.
+// NOTE: Changes to the MachineSet are persisted when the MachineSet is patched at the end of the reconcile.
+// NOTE: The MachineDeployment is patched with an optimistic lock, given that the MachineSets of a MachineDeployment,
+// e.g. the old and the new MachineSet during a rollout, are reconciled concurrently and they must not claim the same
+// provider ID; in case of conflicts, the update is retried on the latest version of the MachineDeployment.
+func (r *Reconciler) updateReleasedProviderIDs(ctx context.Context, ms *clusterv1.MachineSet, updateFunc func([]string) []string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		md, err := r.getOwnerMachineDeployment(ctx, ms)
+		if err != nil {
+			return err
+		}
+		if md == nil {
+			setReleasedProviderIDs(ms, updateFunc(getReleasedProviderIDs(ms)))
+			return nil
+		}
+
+		patch := client.MergeFromWithOptions(md.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		setReleasedProviderIDs(md, updateFunc(getReleasedProviderIDs(md)))
+		if err := r.Client.Patch(ctx, md, patch); err != nil {
+			if apierrors.IsConflict(err) {
+				return err
+			}
+			return errors.Wrapf(err, "failed to update released provider IDs on MachineDeployment %s", klog.KObj(md))
+		}
+		return nil
+	})
+}
+
+// getOwnerMachineDeployment returns the MachineDeployment controlling the MachineSet, if any.
+func (r *Reconciler) getOwnerMachineDeployment(ctx context.Context, ms *clusterv1.MachineSet) (*clusterv1.MachineDeployment, error) {
+	ref := metav1.GetControllerOf(ms)
+	if ref == nil || ref.Kind != "MachineDeployment" {
+		return nil, nil
+	}
+
+	md := &clusterv1.MachineDeployment{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: ms.Namespace, Name: ref.Name}, md); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get MachineDeployment %s", klog.KRef(ms.Namespace, ref.Name))
+	}
+	return md, nil
+}
+
+func getReleasedProviderIDs(obj metav1.Object) []string {
+	value := obj.GetAnnotations()[clusterv1.ReleasedProviderIDsAnnotation]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func setReleasedProviderIDs(obj metav1.Object, providerIDs []string) {
+	annotations := obj.GetAnnotations()
+	if len(providerIDs) == 0 {
+		delete(annotations, clusterv1.ReleasedProviderIDsAnnotation)
+		obj.SetAnnotations(annotations)
+		return
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[clusterv1.ReleasedProviderIDsAnnotation] = strings.Join(providerIDs, ",")
+	obj.SetAnnotations(annotations)
+}
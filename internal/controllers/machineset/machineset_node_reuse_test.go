@@ -0,0 +1,198 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestNodeReuse(t *testing.T) {
+	machineWithProviderID := func(providerID string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: clusterv1.MachineSpec{
+				ProviderID: pointer.String(providerID),
+			},
+		}
+	}
+
+	t.Run("does nothing if node reuse is not enabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ms := &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ms",
+				Namespace: metav1.NamespaceDefault,
+			},
+		}
+		r := &Reconciler{
+			Client: fake.NewClientBuilder().Build(),
+		}
+
+		g.Expect(r.releaseProviderID(ctx, ms, machineWithProviderID("host-1"))).To(Succeed())
+		g.Expect(ms.Annotations).ToNot(HaveKey(clusterv1.ReleasedProviderIDsAnnotation))
+
+		providerID, err := r.claimProviderID(ctx, ms)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(providerID).To(BeEmpty())
+	})
+
+	t.Run("tracks released provider IDs on the MachineSet", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ms := &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ms",
+				Namespace: metav1.NamespaceDefault,
+				Annotations: map[string]string{
+					clusterv1.NodeReuseAnnotation: "",
+				},
+			},
+		}
+		r := &Reconciler{
+			Client: fake.NewClientBuilder().Build(),
+		}
+
+		g.Expect(r.releaseProviderID(ctx, ms, machineWithProviderID("host-1"))).To(Succeed())
+		g.Expect(r.releaseProviderID(ctx, ms, machineWithProviderID("host-2"))).To(Succeed())
+		// Releasing the same provider ID twice is a no-op.
+		g.Expect(r.releaseProviderID(ctx, ms, machineWithProviderID("host-2"))).To(Succeed())
+		g.Expect(ms.Annotations).To(HaveKeyWithValue(clusterv1.ReleasedProviderIDsAnnotation, "host-1,host-2"))
+
+		providerID, err := r.claimProviderID(ctx, ms)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(providerID).To(Equal("host-1"))
+		providerID, err = r.claimProviderID(ctx, ms)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(providerID).To(Equal("host-2"))
+		g.Expect(ms.Annotations).ToNot(HaveKey(clusterv1.ReleasedProviderIDsAnnotation))
+
+		providerID, err = r.claimProviderID(ctx, ms)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(providerID).To(BeEmpty())
+	})
+
+	t.Run("limits the number of released provider IDs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ms := &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ms",
+				Namespace: metav1.NamespaceDefault,
+				Annotations: map[string]string{
+					clusterv1.NodeReuseAnnotation: "",
+				},
+			},
+		}
+		r := &Reconciler{
+			Client: fake.NewClientBuilder().Build(),
+		}
+
+		for i := 0; i <= maxReleasedProviderIDs; i++ {
+			g.Expect(r.releaseProviderID(ctx, ms, machineWithProviderID(fmt.Sprintf("host-%d", i)))).To(Succeed())
+		}
+		g.Expect(getReleasedProviderIDs(ms)).To(HaveLen(maxReleasedProviderIDs))
+
+		// The oldest released provider ID is dropped.
+		providerID, err := r.claimProviderID(ctx, ms)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(providerID).To(Equal("host-1"))
+	})
+
+	t.Run("returns unclaimed provider IDs first in line", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ms := &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ms",
+				Namespace: metav1.NamespaceDefault,
+				Annotations: map[string]string{
+					clusterv1.NodeReuseAnnotation:           "",
+					clusterv1.ReleasedProviderIDsAnnotation: "host-1,host-2",
+				},
+			},
+		}
+		r := &Reconciler{
+			Client: fake.NewClientBuilder().Build(),
+		}
+
+		providerID, err := r.claimProviderID(ctx, ms)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(providerID).To(Equal("host-1"))
+
+		g.Expect(r.unclaimProviderID(ctx, ms, providerID)).To(Succeed())
+		// Returning the same provider ID twice is a no-op.
+		g.Expect(r.unclaimProviderID(ctx, ms, providerID)).To(Succeed())
+		g.Expect(ms.Annotations).To(HaveKeyWithValue(clusterv1.ReleasedProviderIDsAnnotation, "host-1,host-2"))
+	})
+
+	t.Run("tracks released provider IDs on the owning MachineDeployment", func(t *testing.T) {
+		g := NewWithT(t)
+
+		md := &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "md",
+				Namespace: metav1.NamespaceDefault,
+			},
+		}
+		ms := &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ms",
+				Namespace: metav1.NamespaceDefault,
+				Annotations: map[string]string{
+					clusterv1.NodeReuseAnnotation: "",
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: clusterv1.GroupVersion.String(),
+						Kind:       "MachineDeployment",
+						Name:       md.Name,
+						Controller: pointer.Bool(true),
+					},
+				},
+			},
+		}
+		r := &Reconciler{
+			Client: fake.NewClientBuilder().WithObjects(md).Build(),
+		}
+
+		g.Expect(r.releaseProviderID(ctx, ms, machineWithProviderID("host-1"))).To(Succeed())
+		g.Expect(ms.Annotations).ToNot(HaveKey(clusterv1.ReleasedProviderIDsAnnotation))
+
+		g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(md), md)).To(Succeed())
+		g.Expect(md.Annotations).To(HaveKeyWithValue(clusterv1.ReleasedProviderIDsAnnotation, "host-1"))
+
+		providerID, err := r.claimProviderID(ctx, ms)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(providerID).To(Equal("host-1"))
+
+		g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(md), md)).To(Succeed())
+		g.Expect(md.Annotations).ToNot(HaveKey(clusterv1.ReleasedProviderIDsAnnotation))
+	})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// deletionConditions are the conditions reporting the progress of the deletion of a machine, in the order they are
+// processed by the machine controller.
+var deletionConditions = []clusterv1.ConditionType{
+	clusterv1.PreDrainDeleteHookSucceededCondition,
+	clusterv1.DrainingSucceededCondition,
+	clusterv1.VolumeDetachSucceededCondition,
+	clusterv1.PreTerminateDeleteHookSucceededCondition,
+}
+
+// calculateRolloutStatus returns the machines holding a rollout or scale operation of a MachineSet, together with
+// the reason why, as reported by the conditions of the machines; nil is returned if there are no such machines.
+func calculateRolloutStatus(machines []*clusterv1.Machine) *clusterv1.MachineRolloutStatus {
+	rollout := &clusterv1.MachineRolloutStatus{}
+	for _, m := range machines {
+		if !m.DeletionTimestamp.IsZero() {
+			if conditions.GetReason(m, clusterv1.DrainingSucceededCondition) == clusterv1.DrainingFailedReason {
+				rollout.FailingDrain = append(rollout.FailingDrain, rolloutBlockerFromCondition(m, clusterv1.DrainingSucceededCondition))
+				continue
+			}
+			blocker := clusterv1.MachineRolloutBlocker{Name: m.Name, Reason: "Deleting"}
+			for _, condition := range deletionConditions {
+				if conditions.IsFalse(m, condition) {
+					blocker = rolloutBlockerFromCondition(m, condition)
+					break
+				}
+			}
+			rollout.PendingDeletion = append(rollout.PendingDeletion, blocker)
+			continue
+		}
+
+		if m.Status.FailureReason != nil || m.Status.FailureMessage != nil {
+			blocker := clusterv1.MachineRolloutBlocker{Name: m.Name}
+			if m.Status.FailureReason != nil {
+				blocker.Reason = string(*m.Status.FailureReason)
+			}
+			if m.Status.FailureMessage != nil {
+				blocker.Message = *m.Status.FailureMessage
+			}
+			rollout.FailingInfrastructureProvisioning = append(rollout.FailingInfrastructureProvisioning, blocker)
+			continue
+		}
+
+		// NOTE: Infrastructure providers report provisioning failures which might be recovered with severity
+		// Warning or Error, while the provisioning in progress is reported with severity Info.
+		if c := conditions.Get(m, clusterv1.InfrastructureReadyCondition); c != nil && c.Status == corev1.ConditionFalse &&
+			(c.Severity == clusterv1.ConditionSeverityWarning || c.Severity == clusterv1.ConditionSeverityError) {
+			rollout.FailingInfrastructureProvisioning = append(rollout.FailingInfrastructureProvisioning, rolloutBlockerFromCondition(m, clusterv1.InfrastructureReadyCondition))
+			continue
+		}
+
+		if m.Status.NodeRef == nil {
+			blocker := rolloutBlockerFromCondition(m, clusterv1.ReadyCondition)
+			if conditions.IsTrue(m, clusterv1.ReadyCondition) || blocker.Reason == "" {
+				blocker = clusterv1.MachineRolloutBlocker{Name: m.Name, Reason: "WaitingForNodeRef"}
+			}
+			rollout.PendingCreation = append(rollout.PendingCreation, blocker)
+		}
+	}
+
+	if rollout.IsEmpty() {
+		return nil
+	}
+	rollout.PendingCreationCount = int32(len(rollout.PendingCreation))
+	rollout.PendingDeletionCount = int32(len(rollout.PendingDeletion))
+	rollout.FailingDrainCount = int32(len(rollout.FailingDrain))
+	rollout.FailingInfrastructureProvisioningCount = int32(len(rollout.FailingInfrastructureProvisioning))
+	mdutil.LimitRolloutBlockers(rollout)
+	return rollout
+}
+
+// rolloutBlockerFromCondition returns a MachineRolloutBlocker for a machine, with the reason and the message of a condition.
+func rolloutBlockerFromCondition(m *clusterv1.Machine, t clusterv1.ConditionType) clusterv1.MachineRolloutBlocker {
+	return clusterv1.MachineRolloutBlocker{
+		Name:    m.Name,
+		Reason:  conditions.GetReason(m, t),
+		Message: conditions.GetMessage(m, t),
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestCalculateRolloutStatus(t *testing.T) {
+	newMachine := func(name string, opts ...func(m *clusterv1.Machine)) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+			},
+			Status: clusterv1.MachineStatus{
+				NodeRef: &corev1.ObjectReference{Name: name},
+			},
+		}
+		for _, opt := range opts {
+			opt(m)
+		}
+		return m
+	}
+	deleting := func(m *clusterv1.Machine) {
+		m.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	}
+	withoutNode := func(m *clusterv1.Machine) {
+		m.Status.NodeRef = nil
+	}
+	withFalseCondition := func(t clusterv1.ConditionType, reason string, severity clusterv1.ConditionSeverity, message string) func(m *clusterv1.Machine) {
+		return func(m *clusterv1.Machine) {
+			conditions.MarkFalse(m, t, reason, severity, message)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		machines []*clusterv1.Machine
+		expect   *clusterv1.MachineRolloutStatus
+	}{
+		{
+			name: "no machines holding the rollout",
+			machines: []*clusterv1.Machine{
+				newMachine("m1"),
+				newMachine("m2"),
+			},
+			expect: nil,
+		},
+		{
+			name: "machines being provisioned",
+			machines: []*clusterv1.Machine{
+				newMachine("m2", withoutNode, withFalseCondition(clusterv1.ReadyCondition, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, "1 of 2 completed")),
+				newMachine("m1", withoutNode),
+				newMachine("m3"),
+			},
+			expect: &clusterv1.MachineRolloutStatus{
+				PendingCreation: []clusterv1.MachineRolloutBlocker{
+					{Name: "m1", Reason: "WaitingForNodeRef"},
+					{Name: "m2", Reason: clusterv1.WaitingForInfrastructureFallbackReason, Message: "1 of 2 completed"},
+				},
+				PendingCreationCount: 2,
+			},
+		},
+		{
+			name: "machines failing the infrastructure provisioning",
+			machines: []*clusterv1.Machine{
+				newMachine("m1", withoutNode, withFalseCondition(clusterv1.InfrastructureReadyCondition, "InstanceProvisionFailed", clusterv1.ConditionSeverityError, "quota exceeded")),
+				newMachine("m2", withoutNode, func(m *clusterv1.Machine) {
+					m.Status.FailureReason = (*capierrors.MachineStatusError)(pointer.String(string(capierrors.CreateMachineError)))
+					m.Status.FailureMessage = pointer.String("instance terminated")
+				}),
+				newMachine("m3", withoutNode, withFalseCondition(clusterv1.InfrastructureReadyCondition, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, "")),
+			},
+			expect: &clusterv1.MachineRolloutStatus{
+				PendingCreation: []clusterv1.MachineRolloutBlocker{
+					{Name: "m3", Reason: "WaitingForNodeRef"},
+				},
+				PendingCreationCount: 1,
+				FailingInfrastructureProvisioning: []clusterv1.MachineRolloutBlocker{
+					{Name: "m1", Reason: "InstanceProvisionFailed", Message: "quota exceeded"},
+					{Name: "m2", Reason: string(capierrors.CreateMachineError), Message: "instance terminated"},
+				},
+				FailingInfrastructureProvisioningCount: 2,
+			},
+		},
+		{
+			name: "machines being deleted",
+			machines: []*clusterv1.Machine{
+				newMachine("m1", deleting),
+				newMachine("m2", deleting, withFalseCondition(clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo, "")),
+				newMachine("m3", deleting, withFalseCondition(clusterv1.VolumeDetachSucceededCondition, clusterv1.WaitingForVolumeDetachReason, clusterv1.ConditionSeverityInfo, "Waiting for node volumes to be detached")),
+				newMachine("m4", deleting, withFalseCondition(clusterv1.DrainingSucceededCondition, clusterv1.DrainingFailedReason, clusterv1.ConditionSeverityWarning, "Cannot evict pod as it would violate the pod's disruption budget.")),
+			},
+			expect: &clusterv1.MachineRolloutStatus{
+				PendingDeletion: []clusterv1.MachineRolloutBlocker{
+					{Name: "m1", Reason: "Deleting"},
+					{Name: "m2", Reason: clusterv1.DrainingReason},
+					{Name: "m3", Reason: clusterv1.WaitingForVolumeDetachReason, Message: "Waiting for node volumes to be detached"},
+				},
+				PendingDeletionCount: 3,
+				FailingDrain: []clusterv1.MachineRolloutBlocker{
+					{Name: "m4", Reason: clusterv1.DrainingFailedReason, Message: "Cannot evict pod as it would violate the pod's disruption budget."},
+				},
+				FailingDrainCount: 1,
+			},
+		},
+		{
+			name: "more machines than reported",
+			machines: []*clusterv1.Machine{
+				newMachine("m11", withoutNode), newMachine("m10", withoutNode), newMachine("m09", withoutNode), newMachine("m08", withoutNode),
+				newMachine("m07", withoutNode), newMachine("m06", withoutNode), newMachine("m05", withoutNode), newMachine("m04", withoutNode),
+				newMachine("m03", withoutNode), newMachine("m02", withoutNode), newMachine("m01", withoutNode),
+			},
+			expect: &clusterv1.MachineRolloutStatus{
+				PendingCreation: []clusterv1.MachineRolloutBlocker{
+					{Name: "m01", Reason: "WaitingForNodeRef"}, {Name: "m02", Reason: "WaitingForNodeRef"}, {Name: "m03", Reason: "WaitingForNodeRef"},
+					{Name: "m04", Reason: "WaitingForNodeRef"}, {Name: "m05", Reason: "WaitingForNodeRef"}, {Name: "m06", Reason: "WaitingForNodeRef"},
+					{Name: "m07", Reason: "WaitingForNodeRef"}, {Name: "m08", Reason: "WaitingForNodeRef"}, {Name: "m09", Reason: "WaitingForNodeRef"},
+					{Name: "m10", Reason: "WaitingForNodeRef"},
+				},
+				PendingCreationCount: 11,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(calculateRolloutStatus(tt.machines)).To(Equal(tt.expect))
+		})
+	}
+}
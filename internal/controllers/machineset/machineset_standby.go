@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/ssa"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// isStandbyMachine returns true if the Machine is a standby Machine.
+func isStandbyMachine(machine *clusterv1.Machine) bool {
+	_, ok := machine.Labels[clusterv1.MachineStandbyLabel]
+	return ok
+}
+
+// markStandbyMachine marks the Machine as a standby Machine, adding the standby label and the NodeStandbyTaint.
+func markStandbyMachine(machine *clusterv1.Machine) {
+	if machine.Labels == nil {
+		machine.Labels = map[string]string{}
+	}
+	machine.Labels[clusterv1.MachineStandbyLabel] = ""
+
+	for _, taint := range machine.Spec.Taints {
+		if taint.MatchTaint(&clusterv1.NodeStandbyTaint) {
+			return
+		}
+	}
+	// NOTE: Taints are copied to avoid modifying the taints of the MachineSet template shared with the Machine.
+	machine.Spec.Taints = append(append([]corev1.Taint{}, machine.Spec.Taints...), clusterv1.NodeStandbyTaint)
+}
+
+// splitStandbyMachines splits the Machines into active Machines and standby Machines.
+func splitStandbyMachines(machines []*clusterv1.Machine) (active, standby []*clusterv1.Machine) {
+	for _, machine := range machines {
+		if isStandbyMachine(machine) {
+			standby = append(standby, machine)
+			continue
+		}
+		active = append(active, machine)
+	}
+	return active, standby
+}
+
+// getDesiredStandbyReplicas returns the number of standby Machines the MachineSet should keep.
+// Standby Machines are kept only when the MachineSet has replicas and, if the MachineSet is owned by a MachineDeployment,
+// only for the latest MachineSet, so old MachineSets being scaled down during a rollout do not provision standby Machines.
+func (r *Reconciler) getDesiredStandbyReplicas(ctx context.Context, ms *clusterv1.MachineSet) (int, error) {
+	value, ok := ms.Annotations[clusterv1.StandbyReplicasAnnotation]
+	if !ok || pointer.Int32Deref(ms.Spec.Replicas, 0) == 0 {
+		return 0, nil
+	}
+	standbyReplicas, err := strconv.Atoi(value)
+	if err != nil || standbyReplicas < 0 {
+		ctrl.LoggerFrom(ctx).Info(fmt.Sprintf("Ignoring invalid value %q for the %s annotation", value, clusterv1.StandbyReplicasAnnotation))
+		return 0, nil
+	}
+
+	md, err := r.getOwnerMachineDeployment(ctx, ms)
+	if err != nil {
+		return 0, err
+	}
+	if md != nil && md.Annotations[clusterv1.RevisionAnnotation] != ms.Annotations[clusterv1.RevisionAnnotation] {
+		return 0, nil
+	}
+	return standbyReplicas, nil
+}
+
+// promoteStandbyMachines promotes up to count standby Machines, preferring the ones with a healthy Node,
+// and returns the number of promoted Machines.
+// Promoted Machines are updated in the given list of Machines.
+func (r *Reconciler) promoteStandbyMachines(ctx context.Context, ms *clusterv1.MachineSet, machines []*clusterv1.Machine, count int) (int, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	candidates := []int{}
+	for i, machine := range machines {
+		if isStandbyMachine(machine) && machine.DeletionTimestamp.IsZero() {
+			candidates = append(candidates, i)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return standbyMachinePriority(machines[candidates[i]], machines[candidates[j]])
+	})
+
+	promoted := 0
+	for _, i := range candidates {
+		if promoted == count {
+			break
+		}
+		machine := machines[i]
+
+		// Promote the Machine by computing the desired Machine without the standby marker; given that the standby
+		// label and the NodeStandbyTaint are owned by the MachineSet controller, they are removed by Server-Side-Apply.
+		existingMachine := machine.DeepCopy()
+		delete(existingMachine.Labels, clusterv1.MachineStandbyLabel)
+		desiredMachine, err := r.computeDesiredMachine(ms, existingMachine)
+		if err != nil {
+			return promoted, errors.Wrapf(err, "failed to promote standby Machine %s", klog.KObj(machine))
+		}
+		if err := ssa.Patch(ctx, r.Client, machineSetManagerName, desiredMachine, ssa.WithCachingProxy{Cache: r.ssaCache, Original: machine}); err != nil {
+			r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedPromote", "Failed to promote standby machine %q: %v", machine.Name, err)
+			return promoted, errors.Wrapf(err, "failed to promote standby Machine %s", klog.KObj(machine))
+		}
+		machines[i] = desiredMachine
+
+		promoted++
+		log.Info(fmt.Sprintf("Promoted standby machine %d of %d", promoted, count), "Machine", klog.KObj(machine))
+		r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulPromote", "Promoted standby machine %q", machine.Name)
+	}
+	return promoted, nil
+}
+
+// syncStandbyReplicas creates or deletes standby Machines to match the number of standby Machines the MachineSet should keep.
+func (r *Reconciler) syncStandbyReplicas(ctx context.Context, ms *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	desiredStandbyReplicas, err := r.getDesiredStandbyReplicas(ctx, ms)
+	if err != nil {
+		return err
+	}
+
+	_, standbyMachines := splitStandbyMachines(machines)
+	candidates := []*clusterv1.Machine{}
+	for _, machine := range standbyMachines {
+		if machine.DeletionTimestamp.IsZero() {
+			candidates = append(candidates, machine)
+		}
+	}
+
+	diff := len(candidates) - desiredStandbyReplicas
+	switch {
+	case diff < 0:
+		if _, ok := ms.Annotations[clusterv1.DisableMachineCreateAnnotation]; ok {
+			return nil
+		}
+		log.Info(fmt.Sprintf("MachineSet is creating %d standby machines", -diff), "standbyReplicas", desiredStandbyReplicas, "standbyMachineCount", len(candidates))
+		return r.createMachines(ctx, ms, -diff, true)
+	case diff > 0:
+		log.Info(fmt.Sprintf("MachineSet is deleting %d standby machines", diff), "standbyReplicas", desiredStandbyReplicas, "standbyMachineCount", len(candidates))
+		// Delete the standby Machines with the lowest priority for promotion first.
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return standbyMachinePriority(candidates[j], candidates[i])
+		})
+		return r.deleteMachines(ctx, ms, candidates[:diff])
+	}
+	return nil
+}
+
+// standbyMachinePriority returns true if the standby Machine a should be promoted before the standby Machine b;
+// Machines with a healthy Node come first, then Machines with a Node, then older Machines.
+func standbyMachinePriority(a, b *clusterv1.Machine) bool {
+	if aHealthy, bHealthy := conditions.IsTrue(a, clusterv1.MachineNodeHealthyCondition), conditions.IsTrue(b, clusterv1.MachineNodeHealthyCondition); aHealthy != bHealthy {
+		return aHealthy
+	}
+	if aHasNode, bHasNode := a.Status.NodeRef != nil, b.Status.NodeRef != nil; aHasNode != bHasNode {
+		return aHasNode
+	}
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
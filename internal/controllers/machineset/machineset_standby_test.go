@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machineset
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestMarkStandbyMachine(t *testing.T) {
+	g := NewWithT(t)
+
+	templateTaints := []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine"},
+		Spec:       clusterv1.MachineSpec{Taints: templateTaints[:1:1]},
+	}
+
+	markStandbyMachine(machine)
+	// Marking a Machine twice is a no-op.
+	markStandbyMachine(machine)
+
+	g.Expect(isStandbyMachine(machine)).To(BeTrue())
+	g.Expect(machine.Spec.Taints).To(ConsistOf(templateTaints[0], clusterv1.NodeStandbyTaint))
+	g.Expect(templateTaints).To(HaveLen(1))
+
+	active, standby := splitStandbyMachines([]*clusterv1.Machine{machine, {ObjectMeta: metav1.ObjectMeta{Name: "active"}}})
+	g.Expect(active).To(HaveLen(1))
+	g.Expect(active[0].Name).To(Equal("active"))
+	g.Expect(standby).To(ConsistOf(machine))
+}
+
+func TestGetDesiredStandbyReplicas(t *testing.T) {
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "md",
+			Namespace:   metav1.NamespaceDefault,
+			Annotations: map[string]string{clusterv1.RevisionAnnotation: "2"},
+		},
+	}
+	newMachineSet := func(replicas int32, annotations map[string]string, owned bool) *clusterv1.MachineSet {
+		ms := &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "ms",
+				Namespace:   metav1.NamespaceDefault,
+				Annotations: annotations,
+			},
+			Spec: clusterv1.MachineSetSpec{
+				Replicas: pointer.Int32(replicas),
+			},
+		}
+		if owned {
+			ms.OwnerReferences = []metav1.OwnerReference{
+				{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "MachineDeployment",
+					Name:       md.Name,
+					Controller: pointer.Bool(true),
+				},
+			}
+		}
+		return ms
+	}
+
+	tests := []struct {
+		name string
+		ms   *clusterv1.MachineSet
+		want int
+	}{
+		{
+			name: "no standby Machines if the annotation is not set",
+			ms:   newMachineSet(3, nil, false),
+			want: 0,
+		},
+		{
+			name: "standby Machines for a stand-alone MachineSet",
+			ms:   newMachineSet(3, map[string]string{clusterv1.StandbyReplicasAnnotation: "2"}, false),
+			want: 2,
+		},
+		{
+			name: "no standby Machines if the MachineSet has no replicas",
+			ms:   newMachineSet(0, map[string]string{clusterv1.StandbyReplicasAnnotation: "2"}, false),
+			want: 0,
+		},
+		{
+			name: "invalid values are ignored",
+			ms:   newMachineSet(3, map[string]string{clusterv1.StandbyReplicasAnnotation: "-1"}, false),
+			want: 0,
+		},
+		{
+			name: "standby Machines for the latest MachineSet of a MachineDeployment",
+			ms:   newMachineSet(3, map[string]string{clusterv1.StandbyReplicasAnnotation: "2", clusterv1.RevisionAnnotation: "2"}, true),
+			want: 2,
+		},
+		{
+			name: "no standby Machines for old MachineSets of a MachineDeployment",
+			ms:   newMachineSet(3, map[string]string{clusterv1.StandbyReplicasAnnotation: "2", clusterv1.RevisionAnnotation: "1"}, true),
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &Reconciler{
+				Client: fake.NewClientBuilder().WithObjects(md).Build(),
+			}
+
+			got, err := r.getDesiredStandbyReplicas(ctx, tt.ms)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestStandbyMachinePriority(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	newMachine := func(name string, age time.Duration, hasNode, healthy bool) *clusterv1.Machine {
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+		}
+		if hasNode {
+			machine.Status.NodeRef = &corev1.ObjectReference{Name: name}
+		}
+		if healthy {
+			machine.Status.Conditions = clusterv1.Conditions{
+				{Type: clusterv1.MachineNodeHealthyCondition, Status: corev1.ConditionTrue},
+			}
+		}
+		return machine
+	}
+
+	machines := []*clusterv1.Machine{
+		newMachine("no-node", 3*time.Hour, false, false),
+		newMachine("unhealthy-node", time.Hour, true, false),
+		newMachine("healthy-node-new", time.Minute, true, true),
+		newMachine("healthy-node-old", time.Hour, true, true),
+	}
+	sort.SliceStable(machines, func(i, j int) bool {
+		return standbyMachinePriority(machines[i], machines[j])
+	})
+
+	names := []string{}
+	for _, machine := range machines {
+		names = append(names, machine.Name)
+	}
+	g.Expect(names).To(Equal([]string{"healthy-node-old", "healthy-node-new", "unhealthy-node", "no-node"}))
+}
+
+func TestComputeDesiredMachinePreservesStandby(t *testing.T) {
+	g := NewWithT(t)
+
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ms",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.MachineSetSpec{
+			ClusterName: testClusterName,
+		},
+	}
+	existingMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine",
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{clusterv1.MachineStandbyLabel: ""},
+		},
+	}
+
+	desiredMachine, err := (&Reconciler{}).computeDesiredMachine(ms, existingMachine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(desiredMachine.Labels).To(HaveKey(clusterv1.MachineStandbyLabel))
+	g.Expect(desiredMachine.Spec.Taints).To(ConsistOf(clusterv1.NodeStandbyTaint))
+
+	// Promoted Machines drop the standby label and the NodeStandbyTaint.
+	delete(existingMachine.Labels, clusterv1.MachineStandbyLabel)
+	desiredMachine, err = (&Reconciler{}).computeDesiredMachine(ms, existingMachine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(desiredMachine.Labels).ToNot(HaveKey(clusterv1.MachineStandbyLabel))
+	g.Expect(desiredMachine.Spec.Taints).To(BeEmpty())
+}
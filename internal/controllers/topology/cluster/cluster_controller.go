@@ -106,7 +106,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		).
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
-		Build(r)
+		Build(runtimeclient.RequeueRetryableCallErrors(r))
 
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
@@ -262,6 +262,9 @@ func (r *Reconciler) reconcile(ctx context.Context, s *scope.Scope) (ctrl.Result
 		return ctrl.Result{}, errors.Wrap(err, "error computing the desired state of the Cluster topology")
 	}
 
+	// Surface the resolved variables and the applied patches, so users can debug variable precedence and patch interactions.
+	s.Current.Cluster.Status.Topology = computeTopologyStatus(s)
+
 	// Reconciles current and desired state of the Cluster
 	if err := r.reconcileState(ctx, s); err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "error reconciling the Cluster topology")
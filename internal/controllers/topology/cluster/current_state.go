@@ -28,9 +28,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
-	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	tlog "sigs.k8s.io/cluster-api/internal/log"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/contract"
 	"sigs.k8s.io/cluster-api/util/labels"
 )
 
@@ -86,7 +87,9 @@ func (r *Reconciler) getCurrentInfrastructureClusterState(ctx context.Context, b
 	// check that the referenced object has the ClusterTopologyOwnedLabel label.
 	// Nb. This is to make sure that a managed topology cluster does not have a reference to an object that is not
 	// owned by the topology.
-	if !labels.IsTopologyOwned(infra) {
+	// NOTE: An externally managed InfrastructureCluster is allowed to be referenced by a managed topology cluster,
+	// e.g. when the Cluster is part of a shared network model; such object is never created nor patched by the topology.
+	if !labels.IsTopologyOwned(infra) && !annotations.IsExternallyManaged(infra) {
 		return nil, fmt.Errorf("infra cluster object %s referenced from cluster %s is not topology owned", tlog.KObj{Obj: infra}, tlog.KObj{Obj: cluster})
 	}
 	return infra, nil
@@ -53,6 +53,9 @@ func TestGetCurrentState(t *testing.T) {
 	infraCluster.SetLabels(map[string]string{clusterv1.ClusterTopologyOwnedLabel: ""})
 	infraClusterNotTopologyOwned := builder.InfrastructureCluster(metav1.NamespaceDefault, "infraOne").
 		Build()
+	infraClusterExternallyManaged := builder.InfrastructureCluster(metav1.NamespaceDefault, "infraOne").
+		Build()
+	infraClusterExternallyManaged.SetAnnotations(map[string]string{clusterv1.ManagedByAnnotation: ""})
 	infraClusterTemplate := builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infraTemplateOne").
 		Build()
 
@@ -205,6 +208,26 @@ func TestGetCurrentState(t *testing.T) {
 			},
 			wantErr: true, // this test fails as partial reconcile is undefined.
 		},
+		{
+			name: "Should read an externally managed InfrastructureCluster that is not topology owned",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithInfrastructureCluster(infraClusterExternallyManaged).
+				Build(),
+			blueprint: &scope.ClusterBlueprint{
+				InfrastructureClusterTemplate: infraClusterTemplate,
+			},
+			objects: []client.Object{
+				infraClusterExternallyManaged,
+			},
+			want: &scope.ClusterState{
+				Cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+					WithInfrastructureCluster(infraClusterExternallyManaged).
+					Build(),
+				ControlPlane:          &scope.ControlPlaneState{},
+				InfrastructureCluster: infraClusterExternallyManaged,
+				MachineDeployments:    emptyMachineDeployments,
+			},
+		},
 		{
 			name: "Fails if the Cluster references an Control Plane that is not topology owned",
 			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
@@ -34,11 +34,14 @@ import (
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
-	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	tlog "sigs.k8s.io/cluster-api/internal/log"
+	"sigs.k8s.io/cluster-api/internal/util/autoscaler"
+	"sigs.k8s.io/cluster-api/internal/webhooks"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/contract"
 )
 
 // computeDesiredState computes the desired state of the cluster topology.
@@ -72,11 +75,14 @@ func (r *Reconciler) computeDesiredState(ctx context.Context, s *scope.Scope) (*
 	// Compute the desired state of the ControlPlane MachineHealthCheck if defined.
 	// The MachineHealthCheck will have the same name as the ControlPlane Object and a selector for the ControlPlane InfrastructureMachines.
 	if s.Blueprint.IsControlPlaneMachineHealthCheckEnabled() {
-		desiredState.ControlPlane.MachineHealthCheck = computeMachineHealthCheck(
+		if desiredState.ControlPlane.MachineHealthCheck, err = computeMachineHealthCheck(
+			ctx,
 			desiredState.ControlPlane.Object,
 			selectorForControlPlaneMHC(),
 			s.Current.Cluster.Name,
-			s.Blueprint.ControlPlaneMachineHealthCheckClass())
+			s.Blueprint.ControlPlaneMachineHealthCheckClass()); err != nil {
+			return nil, errors.Wrapf(err, "failed to compute ControlPlane MachineHealthCheck")
+		}
 	}
 
 	// Compute the desired state for the Cluster object adding a reference to the
@@ -101,16 +107,23 @@ func (r *Reconciler) computeDesiredState(ctx context.Context, s *scope.Scope) (*
 	// are preserved during patching. When desired objects are computed their spec is copied from a template, in some cases
 	// further modifications to the spec are made afterwards. In those cases we have to make sure those fields are not overwritten
 	// in apply patches. Some examples are .spec.machineTemplate and .spec.version in control planes.
-	if err := r.patchEngine.Apply(ctx, s.Blueprint, desiredState); err != nil {
+	appliedPatches, err := r.patchEngine.Apply(ctx, s.Blueprint, desiredState)
+	if err != nil {
 		return nil, errors.Wrap(err, "failed to apply patches")
 	}
+	s.AppliedPatches = appliedPatches
 
 	return desiredState, nil
 }
 
 // computeInfrastructureCluster computes the desired state for the InfrastructureCluster object starting from the
 // corresponding template defined in the blueprint.
+// NOTE: If the Cluster references an externally managed InfrastructureCluster, the current object is used as desired state.
 func computeInfrastructureCluster(_ context.Context, s *scope.Scope) (*unstructured.Unstructured, error) {
+	if s.Current.InfrastructureCluster != nil && annotations.IsExternallyManaged(s.Current.InfrastructureCluster) {
+		return s.Current.InfrastructureCluster.DeepCopy(), nil
+	}
+
 	template := s.Blueprint.InfrastructureClusterTemplate
 	templateClonedFromRef := s.Blueprint.ClusterClass.Spec.Infrastructure.Ref
 	cluster := s.Current.Cluster
@@ -543,7 +556,7 @@ func computeMachineDeployments(ctx context.Context, s *scope.Scope, desiredContr
 // computeMachineDeployment computes the desired state for a MachineDeploymentTopology.
 // The generated machineDeployment object is calculated using the values from the machineDeploymentTopology and
 // the machineDeployment class.
-func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlPlaneState *scope.ControlPlaneState, machineDeploymentTopology clusterv1.MachineDeploymentTopology) (*scope.MachineDeploymentState, error) {
+func computeMachineDeployment(ctx context.Context, s *scope.Scope, desiredControlPlaneState *scope.ControlPlaneState, machineDeploymentTopology clusterv1.MachineDeploymentTopology) (*scope.MachineDeploymentState, error) {
 	desiredMachineDeployment := &scope.MachineDeploymentState{}
 
 	// Gets the blueprint for the MachineDeployment class.
@@ -700,7 +713,13 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 	// Ensure the annotations used to control the upgrade sequence are never propagated.
 	delete(machineDeploymentAnnotations, clusterv1.ClusterTopologyHoldUpgradeSequenceAnnotation)
 	delete(machineDeploymentAnnotations, clusterv1.ClusterTopologyDeferUpgradeAnnotation)
-	desiredMachineDeploymentObj.SetAnnotations(machineDeploymentAnnotations)
+	// While autoscaling of the current MachineDeployment is paused, apply the autoscaler annotations as the
+	// autoscaler-paused ones, otherwise the MachineDeployment would be included in autoscaling again.
+	desiredAnnotations := machineDeploymentAnnotations
+	if currentMachineDeployment != nil && currentMachineDeployment.Object != nil {
+		desiredAnnotations = autoscaler.ComputeAnnotations(s.Current.Cluster, currentMachineDeployment.Object, machineDeploymentAnnotations)
+	}
+	desiredMachineDeploymentObj.SetAnnotations(desiredAnnotations)
 	desiredMachineDeploymentObj.Spec.Template.Annotations = machineDeploymentAnnotations
 
 	// Apply Labels
@@ -736,11 +755,16 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 	// If the ClusterClass defines a MachineHealthCheck for the MachineDeployment add it to the desired state.
 	if s.Blueprint.IsMachineDeploymentMachineHealthCheckEnabled(&machineDeploymentTopology) {
 		// Note: The MHC is going to use a selector that provides a minimal set of labels which are common to all MachineSets belonging to the MachineDeployment.
-		desiredMachineDeployment.MachineHealthCheck = computeMachineHealthCheck(
+		mhc, err := computeMachineHealthCheck(
+			ctx,
 			desiredMachineDeploymentObj,
 			selectorForMachineDeploymentMHC(desiredMachineDeploymentObj),
 			s.Current.Cluster.Name,
 			s.Blueprint.MachineDeploymentMachineHealthCheckClass(&machineDeploymentTopology))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compute MachineHealthCheck for MachineDeployment topology %s", machineDeploymentTopology.Name)
+		}
+		desiredMachineDeployment.MachineHealthCheck = mhc
 	}
 	return desiredMachineDeployment, nil
 }
@@ -1004,7 +1028,7 @@ func ownerReferenceTo(obj client.Object) *metav1.OwnerReference {
 	}
 }
 
-func computeMachineHealthCheck(healthCheckTarget client.Object, selector *metav1.LabelSelector, clusterName string, check *clusterv1.MachineHealthCheckClass) *clusterv1.MachineHealthCheck {
+func computeMachineHealthCheck(ctx context.Context, healthCheckTarget client.Object, selector *metav1.LabelSelector, clusterName string, check *clusterv1.MachineHealthCheckClass) (*clusterv1.MachineHealthCheck, error) {
 	// Create a MachineHealthCheck with the spec given in the ClusterClass.
 	mhc := &clusterv1.MachineHealthCheck{
 		TypeMeta: metav1.TypeMeta{
@@ -1028,9 +1052,11 @@ func computeMachineHealthCheck(healthCheckTarget client.Object, selector *metav1
 
 	// Default all fields in the MachineHealthCheck using the same function called in the webhook. This ensures the desired
 	// state of the object won't be different from the current state due to webhook Defaulting.
-	mhc.Default()
+	if err := (&webhooks.MachineHealthCheck{}).Default(ctx, mhc); err != nil {
+		return nil, err
+	}
 
-	return mhc
+	return mhc, nil
 }
 
 func selectorForControlPlaneMHC() *metav1.LabelSelector {
@@ -36,12 +36,12 @@ import (
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
-	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/contract"
 )
 
 var (
@@ -150,6 +150,28 @@ func TestComputeInfrastructureCluster(t *testing.T) {
 		g.Expect(obj).ToNot(BeNil())
 		g.Expect(hasOwnerReferenceFrom(obj, shim)).To(BeTrue())
 	})
+	t.Run("Uses the current infrastructureCluster if it is externally managed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// current cluster objects for the test scenario
+		clusterWithInfrastructureRef := cluster.DeepCopy()
+		clusterWithInfrastructureRef.Spec.InfrastructureRef = fakeRef1
+
+		externallyManagedInfrastructureCluster := builder.InfrastructureCluster(metav1.NamespaceDefault, "shared-infra").
+			WithSpecFields(map[string]interface{}{"spec.controlPlaneEndpoint.host": "1.2.3.4"}).
+			Build()
+		externallyManagedInfrastructureCluster.SetAnnotations(map[string]string{clusterv1.ManagedByAnnotation: ""})
+
+		// aggregating current cluster objects into ClusterState (simulating getCurrentState)
+		scope := scope.New(clusterWithInfrastructureRef)
+		scope.Current.InfrastructureCluster = externallyManagedInfrastructureCluster
+		scope.Blueprint = blueprint
+
+		obj, err := computeInfrastructureCluster(ctx, scope)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(obj).To(Equal(externallyManagedInfrastructureCluster))
+		g.Expect(obj).ToNot(BeIdenticalTo(externallyManagedInfrastructureCluster))
+	})
 }
 
 func TestComputeControlPlaneInfrastructureMachineTemplate(t *testing.T) {
@@ -1605,6 +1627,52 @@ func TestComputeMachineDeployment(t *testing.T) {
 		g.Expect(actualMd.Spec.Template.Spec.Bootstrap.ConfigRef.Name).To(Equal("linux-worker-bootstraptemplate"))
 	})
 
+	t.Run("If autoscaling of the current machine deployment is paused, it applies the autoscaler annotations as the autoscaler-paused ones", func(t *testing.T) {
+		g := NewWithT(t)
+		s := scope.New(cluster)
+		s.Blueprint = blueprint
+
+		currentMd := &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "existing-deployment-1",
+				Annotations: map[string]string{clusterv1.AutoscalerPausedAnnotation: ""},
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						Version: pointer.String("v1.21.2"),
+						Bootstrap: clusterv1.Bootstrap{
+							ConfigRef: contract.ObjToRef(workerBootstrapTemplate),
+						},
+						InfrastructureRef: *contract.ObjToRef(workerInfrastructureMachineTemplate),
+					},
+				},
+			},
+		}
+		s.Current.MachineDeployments = map[string]*scope.MachineDeploymentState{
+			"big-pool-of-machines": {
+				Object:                        currentMd,
+				BootstrapTemplate:             workerBootstrapTemplate,
+				InfrastructureMachineTemplate: workerInfrastructureMachineTemplate,
+			},
+		}
+
+		autoscaledMdTopology := mdTopology.DeepCopy()
+		autoscaledMdTopology.Metadata.Annotations = map[string]string{
+			clusterv1.AutoscalerMinSizeAnnotation: "1",
+			clusterv1.AutoscalerMaxSizeAnnotation: "5",
+		}
+
+		actual, err := computeMachineDeployment(ctx, s, nil, *autoscaledMdTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		actualMd := actual.Object
+		g.Expect(actualMd.Annotations).To(HaveKeyWithValue(clusterv1.AutoscalerPausedMinSizeAnnotation, "1"))
+		g.Expect(actualMd.Annotations).To(HaveKeyWithValue(clusterv1.AutoscalerPausedMaxSizeAnnotation, "5"))
+		g.Expect(actualMd.Annotations).ToNot(HaveKey(clusterv1.AutoscalerMinSizeAnnotation))
+		g.Expect(actualMd.Annotations).ToNot(HaveKey(clusterv1.AutoscalerMaxSizeAnnotation))
+	})
+
 	t.Run("If a machine deployment references a topology class that does not exist, machine deployment generation fails", func(t *testing.T) {
 		g := NewWithT(t)
 		scope := scope.New(cluster)
@@ -2394,7 +2462,8 @@ func Test_computeMachineHealthCheck(t *testing.T) {
 	t.Run("set all fields correctly", func(t *testing.T) {
 		g := NewWithT(t)
 
-		got := computeMachineHealthCheck(healthCheckTarget, selector, clusterName, mhcSpec)
+		got, err := computeMachineHealthCheck(ctx, healthCheckTarget, selector, clusterName, mhcSpec)
+		g.Expect(err).ToNot(HaveOccurred())
 
 		g.Expect(got).To(Equal(want), cmp.Diff(got, want))
 	})
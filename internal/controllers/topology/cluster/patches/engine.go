@@ -18,6 +18,7 @@ limitations under the License.
 package patches
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"runtime/debug"
@@ -25,13 +26,13 @@ import (
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
-	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/api"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/external"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/inline"
@@ -39,11 +40,13 @@ import (
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	tlog "sigs.k8s.io/cluster-api/internal/log"
 	runtimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/contract"
 )
 
 // Engine is a patch engine which applies patches defined in a ClusterBlueprint to a ClusterState.
 type Engine interface {
-	Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, desired *scope.ClusterState) error
+	Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, desired *scope.ClusterState) ([]clusterv1.ClusterTopologyPatchStatus, error)
 }
 
 // NewEngine creates a new patch engine.
@@ -64,10 +67,12 @@ type engine struct {
 //   - Then for all ClusterClassPatches of a ClusterClass, JSON or JSON merge patches are generated
 //     and successively applied to the templates in the GeneratePatchesRequest.
 //   - Eventually the patched templates are used to update the specs of the desired objects.
-func (e *engine) Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, desired *scope.ClusterState) error {
+//
+// It returns the list of the patches applied, together with the objects each patch modified.
+func (e *engine) Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, desired *scope.ClusterState) ([]clusterv1.ClusterTopologyPatchStatus, error) {
 	// Return if there are no patches.
 	if len(blueprint.ClusterClass.Spec.Patches) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	log := tlog.LoggerFrom(ctx)
@@ -75,11 +80,12 @@ func (e *engine) Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, d
 	// Create a patch generation request.
 	req, err := createRequest(blueprint, desired)
 	if err != nil {
-		return errors.Wrapf(err, "failed to generate patch request")
+		return nil, errors.Wrapf(err, "failed to generate patch request")
 	}
 
 	// Loop over patches in ClusterClass, generate patches and apply them to the request,
 	// respecting the order in which they are defined.
+	appliedPatches := make([]clusterv1.ClusterTopologyPatchStatus, 0, len(blueprint.ClusterClass.Spec.Patches))
 	for i := range blueprint.ClusterClass.Spec.Patches {
 		clusterClassPatch := blueprint.ClusterClass.Spec.Patches[i]
 		ctx, log := log.WithValues("patch", clusterClassPatch.Name).Into(ctx)
@@ -90,14 +96,14 @@ func (e *engine) Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, d
 			definitionFrom = clusterv1.VariableDefinitionFromInline
 		}
 		if err := addVariablesForPatch(blueprint, desired, req, definitionFrom); err != nil {
-			return errors.Wrapf(err, "failed to calculate variables for patch %q", clusterClassPatch.Name)
+			return nil, errors.Wrapf(err, "failed to calculate variables for patch %q", clusterClassPatch.Name)
 		}
 		log.V(5).Infof("Applying patch to templates")
 
 		// Create patch generator for the current patch.
 		generator, err := createPatchGenerator(e.runtimeClient, &clusterClassPatch)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		// Generate patches.
@@ -106,13 +112,21 @@ func (e *engine) Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, d
 		// version of the request (including the patched version of the templates).
 		resp, err := generator.Generate(ctx, desired.Cluster, req)
 		if err != nil {
-			return errors.Wrapf(err, "failed to generate patches for patch %q", clusterClassPatch.Name)
+			return nil, errors.Wrapf(err, "failed to generate patches for patch %q", clusterClassPatch.Name)
 		}
 
-		// Apply patches to the request.
+		// Apply patches to the request, keeping track of the templates modified by the patch.
+		original := make(map[types.UID][]byte, len(req.Items))
+		for _, item := range req.Items {
+			original[item.UID] = item.Object.Raw
+		}
 		if err := applyPatchesToRequest(ctx, req, resp); err != nil {
-			return errors.Wrapf(err, "failed to apply patches for patch %q", clusterClassPatch.Name)
+			return nil, errors.Wrapf(err, "failed to apply patches for patch %q", clusterClassPatch.Name)
 		}
+		appliedPatches = append(appliedPatches, clusterv1.ClusterTopologyPatchStatus{
+			Name:    clusterClassPatch.Name,
+			Targets: modifiedTargets(req, original),
+		})
 	}
 
 	// Convert request to validation request.
@@ -135,17 +149,34 @@ func (e *engine) Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, d
 
 		_, err := validator.Validate(ctx, desired.Cluster, validationRequest)
 		if err != nil {
-			return errors.Wrapf(err, "validation of patch %q failed", clusterClassPatch.Name)
+			return nil, errors.Wrapf(err, "validation of patch %q failed", clusterClassPatch.Name)
 		}
 	}
 
 	// Use patched templates to update the desired state objects.
 	log.V(5).Infof("Applying patched templates to desired state")
 	if err := updateDesiredState(ctx, req, blueprint, desired); err != nil {
-		return errors.Wrapf(err, "failed to apply patches to desired state")
+		return nil, errors.Wrapf(err, "failed to apply patches to desired state")
 	}
 
-	return nil
+	return appliedPatches, nil
+}
+
+// modifiedTargets returns the targets of the items of a GeneratePatchesRequest whose template changed
+// compared to the original version.
+func modifiedTargets(req *runtimehooksv1.GeneratePatchesRequest, original map[types.UID][]byte) []clusterv1.ClusterTopologyPatchTarget {
+	var targets []clusterv1.ClusterTopologyPatchTarget
+	for _, item := range req.Items {
+		if bytes.Equal(original[item.UID], item.Object.Raw) {
+			continue
+		}
+		targets = append(targets, clusterv1.ClusterTopologyPatchTarget{
+			HolderKind: item.HolderReference.Kind,
+			HolderName: item.HolderReference.Name,
+			FieldPath:  item.HolderReference.FieldPath,
+		})
+	}
+	return targets
 }
 
 // addVariablesForPatch adds variables for a given ClusterClassPatch to the items in the PatchRequest.
@@ -427,12 +458,15 @@ func updateDesiredState(ctx context.Context, req *runtimehooksv1.GeneratePatches
 	var err error
 
 	// Update the InfrastructureCluster.
-	infrastructureClusterTemplate, err := getTemplateAsUnstructured(req, "Cluster", "spec.infrastructureRef", "")
-	if err != nil {
-		return err
-	}
-	if err := patchObject(ctx, desired.InfrastructureCluster, infrastructureClusterTemplate); err != nil {
-		return err
+	// NOTE: Patches are not applied to an externally managed InfrastructureCluster.
+	if !annotations.IsExternallyManaged(desired.InfrastructureCluster) {
+		infrastructureClusterTemplate, err := getTemplateAsUnstructured(req, "Cluster", "spec.infrastructureRef", "")
+		if err != nil {
+			return err
+		}
+		if err := patchObject(ctx, desired.InfrastructureCluster, infrastructureClusterTemplate); err != nil {
+			return err
+		}
 	}
 
 	// Update the ControlPlane.
@@ -57,6 +57,7 @@ func TestApply(t *testing.T) {
 		varDefinitions         []clusterv1.ClusterClassStatusVariable
 		externalPatchResponses map[string]runtimehooksv1.ResponseObject
 		expectedFields         expectedFields
+		expectedAppliedPatches []clusterv1.ClusterTopologyPatchStatus
 		wantErr                bool
 	}{
 		{
@@ -132,6 +133,16 @@ func TestApply(t *testing.T) {
 					"spec.template.spec.resource": "controlPlaneInfrastructureMachineTemplate",
 				},
 			},
+			expectedAppliedPatches: []clusterv1.ClusterTopologyPatchStatus{
+				{
+					Name: "fake-patch1",
+					Targets: []clusterv1.ClusterTopologyPatchTarget{
+						{HolderKind: "Cluster", HolderName: "cluster1", FieldPath: "spec.infrastructureRef"},
+						{HolderKind: "Cluster", HolderName: "cluster1", FieldPath: "spec.controlPlaneRef"},
+						{HolderKind: builder.GenericControlPlaneKind, HolderName: "controlPlane1", FieldPath: "spec.machineTemplate.infrastructureRef"},
+					},
+				},
+			},
 		},
 		{
 			name: "Should apply JSON patches to MachineDeployment templates",
@@ -783,13 +794,23 @@ func TestApply(t *testing.T) {
 			}
 
 			// Apply patches.
-			if err := patchEngine.Apply(context.Background(), blueprint, desired); err != nil {
+			appliedPatches, err := patchEngine.Apply(context.Background(), blueprint, desired)
+			if err != nil {
 				if !tt.wantErr {
 					t.Fatal(err)
 				}
 				return
 			}
 
+			// Check the applied patches are reported in order.
+			g.Expect(appliedPatches).To(HaveLen(len(tt.patches)))
+			for i := range tt.patches {
+				g.Expect(appliedPatches[i].Name).To(Equal(tt.patches[i].Name))
+			}
+			if tt.expectedAppliedPatches != nil {
+				g.Expect(appliedPatches).To(Equal(tt.expectedAppliedPatches))
+			}
+
 			// Compare the patched desired objects with the expected desired objects.
 			g.Expect(desired.Cluster).To(EqualObject(expectedCluster))
 			g.Expect(desired.InfrastructureCluster).To(EqualObject(expectedInfrastructureCluster))
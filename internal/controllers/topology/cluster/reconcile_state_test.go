@@ -41,13 +41,14 @@ import (
 	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
-	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/structuredmerge"
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
 	"sigs.k8s.io/cluster-api/internal/util/ssa"
+	"sigs.k8s.io/cluster-api/internal/webhooks"
+	"sigs.k8s.io/cluster-api/util/contract"
 )
 
 var (
@@ -1007,6 +1008,14 @@ func TestReconcileInfrastructureCluster(t *testing.T) {
 	clusterInfrastructure1WithIncompatibleChanges := clusterInfrastructure1.DeepCopy()
 	clusterInfrastructure1WithIncompatibleChanges.SetName("infrastructure-cluster1-changed")
 
+	// build an externally managed infrastructure cluster, and a desired infrastructure cluster with changes that should not be applied.
+	clusterInfrastructure2ExternallyManaged := builder.TestInfrastructureCluster(metav1.NamespaceDefault, "infrastructure-cluster2").
+		WithSpecFields(map[string]interface{}{"spec.foo": "foo"}).
+		Build()
+	clusterInfrastructure2ExternallyManaged.SetAnnotations(map[string]string{clusterv1.ManagedByAnnotation: ""})
+	clusterInfrastructure2WithChanges := clusterInfrastructure2ExternallyManaged.DeepCopy()
+	g.Expect(unstructured.SetNestedField(clusterInfrastructure2WithChanges.UnstructuredContent(), "foo-changed", "spec", "foo")).To(Succeed())
+
 	tests := []struct {
 		name            string
 		original        *unstructured.Unstructured
@@ -1051,6 +1060,13 @@ func TestReconcileInfrastructureCluster(t *testing.T) {
 			desired:  clusterInfrastructure1WithIncompatibleChanges,
 			wantErr:  true,
 		},
+		{
+			name:     "Should not patch an externally managed InfrastructureCluster",
+			original: clusterInfrastructure2ExternallyManaged,
+			desired:  clusterInfrastructure2WithChanges,
+			want:     clusterInfrastructure2ExternallyManaged,
+			wantErr:  false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1501,9 +1517,7 @@ func TestReconcileControlPlaneMachineHealthCheck(t *testing.T) {
 				Object:                        controlPlane1.DeepCopy(),
 				InfrastructureMachineTemplate: infrastructureMachineTemplate.DeepCopy(),
 				MachineHealthCheck:            mhcBuilder.Build()},
-			want: mhcBuilder.DeepCopy().
-				WithDefaulter(true).
-				Build(),
+			want: defaultMachineHealthCheck(t, mhcBuilder.DeepCopy().Build()),
 		},
 		{
 			name:  "Should not create ControlPlane MachineHealthCheck when no MachineInfrastructure is defined",
@@ -1532,9 +1546,7 @@ func TestReconcileControlPlaneMachineHealthCheck(t *testing.T) {
 				MachineHealthCheck:            mhcBuilder.WithMaxUnhealthy(&maxUnhealthy).Build(),
 			},
 			// Want to get the updated version of the MachineHealthCheck after reconciliation.
-			want: mhcBuilder.DeepCopy().WithMaxUnhealthy(&maxUnhealthy).
-				WithDefaulter(true).
-				Build(),
+			want: defaultMachineHealthCheck(t, mhcBuilder.DeepCopy().WithMaxUnhealthy(&maxUnhealthy).Build()),
 		},
 		{
 			name:  "Should delete ControlPlane MachineHealthCheck when removed from desired state",
@@ -2478,7 +2490,7 @@ func TestReconcileMachineDeploymentMachineHealthCheck(t *testing.T) {
 					mhcBuilder.DeepCopy().Build()),
 			},
 			want: []*clusterv1.MachineHealthCheck{
-				mhcBuilder.DeepCopy().WithDefaulter(true).Build()},
+				defaultMachineHealthCheck(t, mhcBuilder.DeepCopy().Build())},
 		},
 		{
 			name: "Create a new MachineHealthCheck if the MachineDeployment is modified to include one",
@@ -2491,7 +2503,7 @@ func TestReconcileMachineDeploymentMachineHealthCheck(t *testing.T) {
 					mhcBuilder.DeepCopy().Build()),
 			},
 			want: []*clusterv1.MachineHealthCheck{
-				mhcBuilder.DeepCopy().WithDefaulter(true).Build()}},
+				defaultMachineHealthCheck(t, mhcBuilder.DeepCopy().Build())}},
 		{
 			name: "Update MachineHealthCheck spec adding a field if the spec adds a field",
 			current: []*scope.MachineDeploymentState{
@@ -2502,10 +2514,7 @@ func TestReconcileMachineDeploymentMachineHealthCheck(t *testing.T) {
 				newFakeMachineDeploymentTopologyState("md-1", infrastructureMachineTemplate, bootstrapTemplate,
 					mhcBuilder.DeepCopy().WithMaxUnhealthy(&maxUnhealthy).Build())},
 			want: []*clusterv1.MachineHealthCheck{
-				mhcBuilder.DeepCopy().
-					WithMaxUnhealthy(&maxUnhealthy).
-					WithDefaulter(true).
-					Build()},
+				defaultMachineHealthCheck(t, mhcBuilder.DeepCopy().WithMaxUnhealthy(&maxUnhealthy).Build())},
 		},
 		{
 			name: "Update MachineHealthCheck spec removing a field if the spec removes a field",
@@ -2518,7 +2527,7 @@ func TestReconcileMachineDeploymentMachineHealthCheck(t *testing.T) {
 					mhcBuilder.DeepCopy().Build()),
 			},
 			want: []*clusterv1.MachineHealthCheck{
-				mhcBuilder.DeepCopy().WithDefaulter(true).Build(),
+				defaultMachineHealthCheck(t, mhcBuilder.DeepCopy().Build()),
 			},
 		},
 		{
@@ -2674,7 +2683,7 @@ func TestReconciler_reconcileMachineHealthCheck(t *testing.T) {
 			name:    "Create a MachineHealthCheck",
 			current: nil,
 			desired: mhcBuilder.DeepCopy().Build(),
-			want:    mhcBuilder.DeepCopy().WithDefaulter(true).Build(),
+			want:    defaultMachineHealthCheck(t, mhcBuilder.DeepCopy().Build()),
 		},
 		{
 			name:    "Update a MachineHealthCheck with changes",
@@ -2687,20 +2696,20 @@ func TestReconciler_reconcileMachineHealthCheck(t *testing.T) {
 					Timeout: metav1.Duration{Duration: 1000 * time.Minute},
 				},
 			}).Build(),
-			want: mhcBuilder.DeepCopy().WithUnhealthyConditions([]clusterv1.UnhealthyCondition{
+			want: defaultMachineHealthCheck(t, mhcBuilder.DeepCopy().WithUnhealthyConditions([]clusterv1.UnhealthyCondition{
 				{
 					Type:    corev1.NodeReady,
 					Status:  corev1.ConditionUnknown,
 					Timeout: metav1.Duration{Duration: 1000 * time.Minute},
 				},
-			}).WithDefaulter(true).Build(),
+			}).Build()),
 		},
 		{
 			name:    "Don't change a MachineHealthCheck with no difference between desired and current",
 			current: mhcBuilder.DeepCopy().Build(),
 			// update the unhealthy conditions in the MachineHealthCheck
 			desired: mhcBuilder.DeepCopy().Build(),
-			want:    mhcBuilder.DeepCopy().WithDefaulter(true).Build(),
+			want:    defaultMachineHealthCheck(t, mhcBuilder.DeepCopy().Build()),
 		},
 		{
 			name:    "Delete a MachineHealthCheck",
@@ -3028,3 +3037,13 @@ func Test_createErrorWithoutObjectName(t *testing.T) {
 		})
 	}
 }
+
+// defaultMachineHealthCheck runs the defaulting of the MachineHealthCheck webhook on the MachineHealthCheck object.
+func defaultMachineHealthCheck(t *testing.T, mhc *clusterv1.MachineHealthCheck) *clusterv1.MachineHealthCheck {
+	t.Helper()
+
+	if err := (&webhooks.MachineHealthCheck{}).Default(ctx, mhc); err != nil {
+		t.Fatalf("failed to default MachineHealthCheck: %v", err)
+	}
+	return mhc
+}
@@ -39,6 +39,10 @@ type Scope struct {
 	// HookResponseTracker holds the hook responses that will be used to
 	// calculate a combined reconcile result.
 	HookResponseTracker *HookResponseTracker
+
+	// AppliedPatches holds the patches applied while computing the desired state,
+	// together with the objects each patch modified.
+	AppliedPatches []clusterv1.ClusterTopologyPatchStatus
 }
 
 // New returns a new Scope with only the cluster; while processing a request in the topology/ClusterReconciler controller
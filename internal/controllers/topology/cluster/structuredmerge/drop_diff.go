@@ -16,7 +16,7 @@ limitations under the License.
 
 package structuredmerge
 
-import "sigs.k8s.io/cluster-api/internal/contract"
+import "sigs.k8s.io/cluster-api/util/contract"
 
 // dropDiff allow to change the modified object so the generated patch will not contain changes
 // that match the shouldDropDiff criteria.
@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+)
+
+// passwordFormat is the OpenAPI format used for marking variables with sensitive values.
+const passwordFormat = "password"
+
+// computeTopologyStatus computes the topology status of the Cluster, reporting the resolved values of the variables
+// and the patches applied while computing the desired state.
+// NOTE: This func should be called after the variables have been defaulted and the desired state has been computed.
+func computeTopologyStatus(s *scope.Scope) *clusterv1.ClusterTopologyStatus {
+	status := &clusterv1.ClusterTopologyStatus{
+		Patches: s.AppliedPatches,
+	}
+
+	topology := s.Current.Cluster.Spec.Topology
+	for _, variable := range topology.Variables {
+		status.Variables = append(status.Variables, variableStatus(s.Blueprint.ClusterClass, variable, ""))
+	}
+	if topology.Workers != nil {
+		for _, md := range topology.Workers.MachineDeployments {
+			if md.Variables == nil {
+				continue
+			}
+			for _, variable := range md.Variables.Overrides {
+				status.Variables = append(status.Variables, variableStatus(s.Blueprint.ClusterClass, variable, md.Name))
+			}
+		}
+	}
+	return status
+}
+
+// variableStatus returns the status of a variable, redacting its value if sensitive.
+func variableStatus(clusterClass *clusterv1.ClusterClass, variable clusterv1.ClusterVariable, machineDeployment string) clusterv1.ClusterTopologyVariableStatus {
+	status := clusterv1.ClusterTopologyVariableStatus{
+		Name:              variable.Name,
+		DefinitionFrom:    variable.DefinitionFrom,
+		MachineDeployment: machineDeployment,
+	}
+	if isSensitiveVariable(clusterClass, variable) {
+		status.Redacted = true
+		return status
+	}
+	status.Value = variable.Value.DeepCopy()
+	return status
+}
+
+// isSensitiveVariable returns true if any of the definitions of the variable in the ClusterClass applying to the given
+// value uses the password format, either at top level or for nested fields.
+func isSensitiveVariable(clusterClass *clusterv1.ClusterClass, variable clusterv1.ClusterVariable) bool {
+	for _, definitions := range clusterClass.Status.Variables {
+		if definitions.Name != variable.Name {
+			continue
+		}
+		for _, definition := range definitions.Definitions {
+			if variable.DefinitionFrom != "" && definition.From != variable.DefinitionFrom {
+				continue
+			}
+			if hasPasswordFormat(&definition.Schema.OpenAPIV3Schema) {
+				return true
+			}
+		}
+	}
+	for _, definition := range clusterClass.Spec.Variables {
+		if definition.Name == variable.Name && hasPasswordFormat(&definition.Schema.OpenAPIV3Schema) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPasswordFormat returns true if the schema or any of its nested schemas uses the password format.
+func hasPasswordFormat(schema *clusterv1.JSONSchemaProps) bool {
+	if schema == nil {
+		return false
+	}
+	if schema.Format == passwordFormat {
+		return true
+	}
+	for name := range schema.Properties {
+		property := schema.Properties[name]
+		if hasPasswordFormat(&property) {
+			return true
+		}
+	}
+	return hasPasswordFormat(schema.AdditionalProperties) || hasPasswordFormat(schema.Items)
+}
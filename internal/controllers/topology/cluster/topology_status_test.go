@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+)
+
+func TestComputeTopologyStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterClass := &clusterv1.ClusterClass{
+		Status: clusterv1.ClusterClassStatus{
+			Variables: []clusterv1.ClusterClassStatusVariable{
+				{
+					Name: "region",
+					Definitions: []clusterv1.ClusterClassStatusVariableDefinition{
+						{From: clusterv1.VariableDefinitionFromInline, Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "string"}}},
+					},
+				},
+				{
+					Name: "credentials",
+					Definitions: []clusterv1.ClusterClassStatusVariableDefinition{
+						{From: clusterv1.VariableDefinitionFromInline, Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]clusterv1.JSONSchemaProps{
+								"user":     {Type: "string"},
+								"password": {Type: "string", Format: "password"},
+							},
+						}}},
+					},
+				},
+			},
+		},
+	}
+	cluster := &clusterv1.Cluster{
+		Spec: clusterv1.ClusterSpec{
+			Topology: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+					{Name: "credentials", Value: apiextensionsv1.JSON{Raw: []byte(`{"user":"admin","password":"secret"}`)}},
+				},
+				Workers: &clusterv1.WorkersTopology{
+					MachineDeployments: []clusterv1.MachineDeploymentTopology{
+						{Name: "md1"},
+						{
+							Name: "md2",
+							Variables: &clusterv1.MachineDeploymentVariables{
+								Overrides: []clusterv1.ClusterVariable{
+									{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-west-2"`)}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	appliedPatches := []clusterv1.ClusterTopologyPatchStatus{
+		{
+			Name: "patch1",
+			Targets: []clusterv1.ClusterTopologyPatchTarget{
+				{HolderKind: "Cluster", HolderName: "cluster1", FieldPath: "spec.infrastructureRef"},
+			},
+		},
+		{Name: "patch2"},
+	}
+
+	s := scope.New(cluster)
+	s.Blueprint.ClusterClass = clusterClass
+	s.AppliedPatches = appliedPatches
+
+	g.Expect(computeTopologyStatus(s)).To(Equal(&clusterv1.ClusterTopologyStatus{
+		Variables: []clusterv1.ClusterTopologyVariableStatus{
+			{Name: "region", Value: &apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+			{Name: "credentials", Redacted: true},
+			{Name: "region", MachineDeployment: "md2", Value: &apiextensionsv1.JSON{Raw: []byte(`"us-west-2"`)}},
+		},
+		Patches: appliedPatches,
+	}))
+}
@@ -19,10 +19,17 @@ package hooks
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
@@ -134,6 +141,89 @@ func MarkAsOkToDelete(ctx context.Context, c client.Client, obj client.Object) e
 	return nil
 }
 
+// FailedCallAttempts returns the number of failed calls to an extension handler tracked in the object's
+// FailedCallAttemptsAnnotation, together with the time of the first failed call.
+func FailedCallAttempts(obj metav1.Object, handlerName string) (int32, time.Time) {
+	value, ok := failedCallAttemptsEntries(obj)[handlerName]
+	if !ok {
+		return 0, time.Time{}
+	}
+	attempts, since, ok := strings.Cut(value, "@")
+	if !ok {
+		return 0, time.Time{}
+	}
+	n, err := strconv.ParseInt(attempts, 10, 32)
+	if err != nil {
+		return 0, time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return 0, time.Time{}
+	}
+	return int32(n), t
+}
+
+// MarkCallFailed records in the object's FailedCallAttemptsAnnotation the number of failed calls to an extension
+// handler, together with the time of the first failed call.
+// NOTE: The annotation is patched without modifying the object passed to this func, given that it could be an object
+// computed in memory, e.g. the desired state of a Cluster, and the calling controller could patch it afterwards.
+func MarkCallFailed(ctx context.Context, c client.Client, obj client.Object, handlerName string, attempts int32, since time.Time) error {
+	entries := failedCallAttemptsEntries(obj)
+	entries[handlerName] = fmt.Sprintf("%d@%s", attempts, since.UTC().Format(time.RFC3339))
+	if err := patchFailedCallAttempts(ctx, c, obj, entries); err != nil {
+		return errors.Wrapf(err, "failed to mark call to extension handler %q as failed", handlerName)
+	}
+	return nil
+}
+
+// MarkCallCompleted removes an extension handler from the object's FailedCallAttemptsAnnotation; this is a no-op
+// if there are no failed calls tracked for the extension handler.
+// NOTE: As for MarkCallFailed, the object passed to this func is not modified.
+func MarkCallCompleted(ctx context.Context, c client.Client, obj client.Object, handlerName string) error {
+	entries := failedCallAttemptsEntries(obj)
+	if _, ok := entries[handlerName]; !ok {
+		return nil
+	}
+	delete(entries, handlerName)
+	if err := patchFailedCallAttempts(ctx, c, obj, entries); err != nil {
+		return errors.Wrapf(err, "failed to mark call to extension handler %q as completed", handlerName)
+	}
+	return nil
+}
+
+func failedCallAttemptsEntries(obj metav1.Object) map[string]string {
+	entries := map[string]string{}
+	for _, entry := range strings.Split(obj.GetAnnotations()[runtimev1.FailedCallAttemptsAnnotation], ",") {
+		if name, value, ok := strings.Cut(entry, "="); ok {
+			entries[name] = value
+		}
+	}
+	return entries
+}
+
+func patchFailedCallAttempts(ctx context.Context, c client.Client, obj client.Object, entries map[string]string) error {
+	var value *string
+	if len(entries) > 0 {
+		list := []string{}
+		for _, name := range sets.List(sets.KeySet(entries)) {
+			list = append(list, name+"="+entries[name])
+		}
+		value = pointer.String(strings.Join(list, ","))
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]*string{runtimev1.FailedCallAttemptsAnnotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.Patch(ctx, obj.DeepCopyObject().(client.Object), client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: obj})
+	}
+	return nil
+}
+
 func addToCommaSeparatedList(list string, items ...string) string {
 	set := sets.Set[string]{}.Insert(strings.Split(list, ",")...)
 
@@ -19,6 +19,7 @@ package hooks
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -251,6 +252,46 @@ func TestMarkAsDone(t *testing.T) {
 	}
 }
 
+func TestFailedCallAttempts(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-ns",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(obj).Build()
+	ctx := context.Background()
+	reload := func() *corev1.ConfigMap {
+		g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+		return obj
+	}
+
+	attempts, since := FailedCallAttempts(obj, "handler-a.extension")
+	g.Expect(attempts).To(BeZero())
+	g.Expect(since.IsZero()).To(BeTrue())
+
+	// Failed calls are tracked per extension handler; the object passed is not modified.
+	firstFailure := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	g.Expect(MarkCallFailed(ctx, fakeClient, obj, "handler-a.extension", 1, firstFailure)).To(Succeed())
+	g.Expect(obj.Annotations).ToNot(HaveKey(runtimev1.FailedCallAttemptsAnnotation))
+	g.Expect(MarkCallFailed(ctx, fakeClient, reload(), "handler-b.extension", 2, firstFailure)).To(Succeed())
+	g.Expect(reload().Annotations).To(HaveKeyWithValue(runtimev1.FailedCallAttemptsAnnotation,
+		"handler-a.extension=1@2023-01-01T10:00:00Z,handler-b.extension=2@2023-01-01T10:00:00Z"))
+
+	attempts, since = FailedCallAttempts(obj, "handler-b.extension")
+	g.Expect(attempts).To(Equal(int32(2)))
+	g.Expect(since).To(Equal(firstFailure))
+
+	// Completed calls are not tracked anymore, and the annotation is removed once empty.
+	g.Expect(MarkCallCompleted(ctx, fakeClient, obj, "handler-a.extension")).To(Succeed())
+	g.Expect(reload().Annotations).To(HaveKeyWithValue(runtimev1.FailedCallAttemptsAnnotation, "handler-b.extension=2@2023-01-01T10:00:00Z"))
+	g.Expect(MarkCallCompleted(ctx, fakeClient, obj, "handler-b.extension")).To(Succeed())
+	g.Expect(reload().Annotations).ToNot(HaveKey(runtimev1.FailedCallAttemptsAnnotation))
+	g.Expect(MarkCallCompleted(ctx, fakeClient, obj, "handler-b.extension")).To(Succeed())
+}
+
 func TestIsOkToDelete(t *testing.T) {
 	tests := []struct {
 		name string
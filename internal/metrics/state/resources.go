@@ -0,0 +1,223 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+// ClusterResource returns the Resource exporting state metrics for Clusters.
+func ClusterResource() Resource {
+	return Resource{
+		Name:    "cluster",
+		NewList: func() client.ObjectList { return &clusterv1.ClusterList{} },
+		Phases: []string{
+			string(clusterv1.ClusterPhasePending),
+			string(clusterv1.ClusterPhaseProvisioning),
+			string(clusterv1.ClusterPhaseProvisioned),
+			string(clusterv1.ClusterPhaseDeleting),
+			string(clusterv1.ClusterPhaseFailed),
+			string(clusterv1.ClusterPhaseUnknown),
+		},
+		Phase: func(obj client.Object) string { return obj.(*clusterv1.Cluster).Status.Phase },
+	}
+}
+
+// MachineResource returns the Resource exporting state metrics for Machines.
+func MachineResource() Resource {
+	return Resource{
+		Name:    "machine",
+		NewList: func() client.ObjectList { return &clusterv1.MachineList{} },
+		Phases: []string{
+			string(clusterv1.MachinePhasePending),
+			string(clusterv1.MachinePhaseProvisioning),
+			string(clusterv1.MachinePhaseProvisioned),
+			string(clusterv1.MachinePhaseRunning),
+			string(clusterv1.MachinePhaseDeleting),
+			string(clusterv1.MachinePhaseDraining),
+			string(clusterv1.MachinePhaseWaitingForVolumeDetach),
+			string(clusterv1.MachinePhaseDeleted),
+			string(clusterv1.MachinePhaseFailed),
+			string(clusterv1.MachinePhaseUnknown),
+		},
+		Phase: func(obj client.Object) string { return obj.(*clusterv1.Machine).Status.Phase },
+	}
+}
+
+// MachineSetResource returns the Resource exporting state metrics for MachineSets.
+func MachineSetResource() Resource {
+	return Resource{
+		Name:    "machineset",
+		NewList: func() client.ObjectList { return &clusterv1.MachineSetList{} },
+		Gauges: []Gauge{
+			{
+				Name:  "spec_replicas",
+				Help:  "The number of desired machines for a machineset.",
+				Value: Int32PtrValue(func(ms *clusterv1.MachineSet) *int32 { return ms.Spec.Replicas }),
+			},
+			{
+				Name:  "status_replicas",
+				Help:  "The number of replicas per machineset.",
+				Value: Int32Value(func(ms *clusterv1.MachineSet) int32 { return ms.Status.Replicas }),
+			},
+			{
+				Name:  "status_fully_labeled_replicas",
+				Help:  "The number of fully labeled replicas per machineset.",
+				Value: Int32Value(func(ms *clusterv1.MachineSet) int32 { return ms.Status.FullyLabeledReplicas }),
+			},
+			{
+				Name:  "status_ready_replicas",
+				Help:  "The number of ready replicas per machineset.",
+				Value: Int32Value(func(ms *clusterv1.MachineSet) int32 { return ms.Status.ReadyReplicas }),
+			},
+			{
+				Name:  "status_available_replicas",
+				Help:  "The number of available replicas per machineset.",
+				Value: Int32Value(func(ms *clusterv1.MachineSet) int32 { return ms.Status.AvailableReplicas }),
+			},
+		},
+	}
+}
+
+// MachineDeploymentResource returns the Resource exporting state metrics for MachineDeployments.
+func MachineDeploymentResource() Resource {
+	return Resource{
+		Name:    "machinedeployment",
+		NewList: func() client.ObjectList { return &clusterv1.MachineDeploymentList{} },
+		Phases: []string{
+			string(clusterv1.MachineDeploymentPhaseScalingUp),
+			string(clusterv1.MachineDeploymentPhaseScalingDown),
+			string(clusterv1.MachineDeploymentPhaseRunning),
+			string(clusterv1.MachineDeploymentPhaseFailed),
+			string(clusterv1.MachineDeploymentPhaseUnknown),
+		},
+		Phase: func(obj client.Object) string { return obj.(*clusterv1.MachineDeployment).Status.Phase },
+		Gauges: []Gauge{
+			{
+				Name:  "spec_replicas",
+				Help:  "The number of desired machines for a machinedeployment.",
+				Value: Int32PtrValue(func(md *clusterv1.MachineDeployment) *int32 { return md.Spec.Replicas }),
+			},
+			{
+				Name:  "status_replicas",
+				Help:  "The number of replicas per machinedeployment.",
+				Value: Int32Value(func(md *clusterv1.MachineDeployment) int32 { return md.Status.Replicas }),
+			},
+			{
+				Name:  "status_replicas_ready",
+				Help:  "The number of ready replicas per machinedeployment.",
+				Value: Int32Value(func(md *clusterv1.MachineDeployment) int32 { return md.Status.ReadyReplicas }),
+			},
+			{
+				Name:  "status_replicas_available",
+				Help:  "The number of available replicas per machinedeployment.",
+				Value: Int32Value(func(md *clusterv1.MachineDeployment) int32 { return md.Status.AvailableReplicas }),
+			},
+			{
+				Name:  "status_replicas_unavailable",
+				Help:  "The number of unavailable replicas per machinedeployment.",
+				Value: Int32Value(func(md *clusterv1.MachineDeployment) int32 { return md.Status.UnavailableReplicas }),
+			},
+			{
+				Name:  "status_replicas_updated",
+				Help:  "The number of updated replicas per machinedeployment.",
+				Value: Int32Value(func(md *clusterv1.MachineDeployment) int32 { return md.Status.UpdatedReplicas }),
+			},
+		},
+	}
+}
+
+// MachineHealthCheckResource returns the Resource exporting state metrics for MachineHealthChecks.
+func MachineHealthCheckResource() Resource {
+	return Resource{
+		Name:    "machinehealthcheck",
+		NewList: func() client.ObjectList { return &clusterv1.MachineHealthCheckList{} },
+		Gauges: []Gauge{
+			{
+				Name:  "status_expected_machines",
+				Help:  "The number of machines monitored by a machinehealthcheck.",
+				Value: Int32Value(func(m *clusterv1.MachineHealthCheck) int32 { return m.Status.ExpectedMachines }),
+			},
+			{
+				Name:  "status_current_healthy",
+				Help:  "The number of healthy machines per machinehealthcheck.",
+				Value: Int32Value(func(m *clusterv1.MachineHealthCheck) int32 { return m.Status.CurrentHealthy }),
+			},
+			{
+				Name:  "status_remediations_allowed",
+				Help:  "The number of further remediations allowed by a machinehealthcheck.",
+				Value: Int32Value(func(m *clusterv1.MachineHealthCheck) int32 { return m.Status.RemediationsAllowed }),
+			},
+			{
+				Name:  "status_dry_run_remediation_targets",
+				Help:  "The number of machines a machinehealthcheck in DryRun mode would have remediated.",
+				Value: Int32Value(func(m *clusterv1.MachineHealthCheck) int32 { return int32(len(m.Status.DryRunRemediationTargets)) }),
+			},
+		},
+	}
+}
+
+// MachinePoolResource returns the Resource exporting state metrics for MachinePools.
+func MachinePoolResource() Resource {
+	return Resource{
+		Name:    "machinepool",
+		NewList: func() client.ObjectList { return &expv1.MachinePoolList{} },
+		Phases: []string{
+			string(expv1.MachinePoolPhasePending),
+			string(expv1.MachinePoolPhaseProvisioning),
+			string(expv1.MachinePoolPhaseProvisioned),
+			string(expv1.MachinePoolPhaseRunning),
+			string(expv1.MachinePoolPhaseScalingUp),
+			string(expv1.MachinePoolPhaseScalingDown),
+			string(expv1.MachinePoolPhaseScaling),
+			string(expv1.MachinePoolPhaseDeleting),
+			string(expv1.MachinePoolPhaseFailed),
+			string(expv1.MachinePoolPhaseUnknown),
+		},
+		Phase: func(obj client.Object) string { return obj.(*expv1.MachinePool).Status.Phase },
+		Gauges: []Gauge{
+			{
+				Name:  "spec_replicas",
+				Help:  "The number of desired machines for a machinepool.",
+				Value: Int32PtrValue(func(mp *expv1.MachinePool) *int32 { return mp.Spec.Replicas }),
+			},
+			{
+				Name:  "status_replicas",
+				Help:  "The number of replicas per machinepool.",
+				Value: Int32Value(func(mp *expv1.MachinePool) int32 { return mp.Status.Replicas }),
+			},
+			{
+				Name:  "status_replicas_ready",
+				Help:  "The number of ready replicas per machinepool.",
+				Value: Int32Value(func(mp *expv1.MachinePool) int32 { return mp.Status.ReadyReplicas }),
+			},
+			{
+				Name:  "status_replicas_available",
+				Help:  "The number of available replicas per machinepool.",
+				Value: Int32Value(func(mp *expv1.MachinePool) int32 { return mp.Status.AvailableReplicas }),
+			},
+			{
+				Name:  "status_replicas_unavailable",
+				Help:  "The number of unavailable replicas per machinepool.",
+				Value: Int32Value(func(mp *expv1.MachinePool) int32 { return mp.Status.UnavailableReplicas }),
+			},
+		},
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package state provides a Prometheus collector exporting kube-state-metrics style metrics
+// for Cluster API objects, e.g. phases, conditions and replica counts.
+package state
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// metricNamePrefix is the prefix of all the state metrics.
+	metricNamePrefix = "capi"
+
+	// listTimeout is the timeout for listing the objects of a Resource when collecting metrics.
+	listTimeout = 30 * time.Second
+)
+
+// conditionStatuses are the values of the status label of the status_condition metrics.
+var conditionStatuses = []string{"True", "False", "Unknown"}
+
+// objectLabels are the labels common to all the state metrics.
+var objectLabels = []string{"name", "namespace", "cluster_name"}
+
+// Resource describes how to export state metrics for a kind of Cluster API object.
+// NOTE: The objects must implement conditions.Getter.
+type Resource struct {
+	// Name of the resource, used in the name of the metrics, e.g. capi_<name>_status_phase.
+	Name string
+
+	// NewList returns an empty list for the objects of the resource.
+	NewList func() client.ObjectList
+
+	// Phases is the list of the known phases of the resource; if empty the status_phase metric is not exported.
+	Phases []string
+
+	// Phase returns the phase of an object.
+	Phase func(obj client.Object) string
+
+	// Gauges exported for each object, e.g. replica counts.
+	Gauges []Gauge
+}
+
+// Gauge describes a gauge exported for each object of a Resource.
+type Gauge struct {
+	// Name of the gauge, used in the name of the metric, e.g. capi_<resource>_<name>.
+	Name string
+
+	// Help text of the gauge.
+	Help string
+
+	// Value returns the value of the gauge for an object; false is returned if the value is not set.
+	Value func(obj client.Object) (float64, bool)
+}
+
+// Collector is a prometheus.Collector exporting state metrics for Cluster API objects.
+// Objects are read at every scrape, so it should be used with a cached client.Reader.
+type Collector struct {
+	reader    client.Reader
+	resources []resourceDescs
+}
+
+type resourceDescs struct {
+	Resource
+	created   *prometheus.Desc
+	phase     *prometheus.Desc
+	condition *prometheus.Desc
+	gauges    []*prometheus.Desc
+}
+
+var _ prometheus.Collector = &Collector{}
+
+// NewCollector returns a Collector exporting state metrics for the given resources.
+func NewCollector(reader client.Reader, resources ...Resource) *Collector {
+	c := &Collector{reader: reader}
+	for _, r := range resources {
+		descs := resourceDescs{
+			Resource: r,
+			created: prometheus.NewDesc(
+				prometheus.BuildFQName(metricNamePrefix, r.Name, "created"),
+				"Unix creation timestamp.",
+				objectLabels, nil),
+			condition: prometheus.NewDesc(
+				prometheus.BuildFQName(metricNamePrefix, r.Name, "status_condition"),
+				"The condition of a "+r.Name+".",
+				append(objectLabels, "type", "status"), nil),
+		}
+		if len(r.Phases) > 0 {
+			descs.phase = prometheus.NewDesc(
+				prometheus.BuildFQName(metricNamePrefix, r.Name, "status_phase"),
+				"The "+r.Name+"s current phase.",
+				append(objectLabels, "phase"), nil)
+		}
+		for _, g := range r.Gauges {
+			descs.gauges = append(descs.gauges, prometheus.NewDesc(
+				prometheus.BuildFQName(metricNamePrefix, r.Name, g.Name),
+				g.Help,
+				objectLabels, nil))
+		}
+		c.resources = append(c.resources, descs)
+	}
+	return c
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, r := range c.resources {
+		ch <- r.created
+		ch <- r.condition
+		if r.phase != nil {
+			ch <- r.phase
+		}
+		for _, g := range r.gauges {
+			ch <- g
+		}
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+	defer cancel()
+
+	log := ctrl.LoggerFrom(ctx).WithName("state-metrics")
+	for _, r := range c.resources {
+		objs, err := c.list(ctx, r.NewList())
+		if err != nil {
+			log.Error(err, "Failed to list objects for state metrics", "resource", r.Name)
+			continue
+		}
+		for _, obj := range objs {
+			r.collect(ch, obj)
+		}
+	}
+}
+
+func (c *Collector) list(ctx context.Context, list client.ObjectList) ([]client.Object, error) {
+	if err := c.reader.List(ctx, list); err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]client.Object, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(client.Object); ok {
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+func (r *resourceDescs) collect(ch chan<- prometheus.Metric, obj client.Object) {
+	labels := []string{obj.GetName(), obj.GetNamespace(), obj.GetLabels()[clusterv1.ClusterNameLabel]}
+
+	ch <- prometheus.MustNewConstMetric(r.created, prometheus.GaugeValue, float64(obj.GetCreationTimestamp().Unix()), labels...)
+
+	if r.phase != nil {
+		phase := r.Phase(obj)
+		for _, p := range r.Phases {
+			ch <- prometheus.MustNewConstMetric(r.phase, prometheus.GaugeValue, boolFloat64(p == phase), append(labels, p)...)
+		}
+	}
+
+	if getter, ok := obj.(conditions.Getter); ok {
+		for _, condition := range getter.GetConditions() {
+			for _, status := range conditionStatuses {
+				ch <- prometheus.MustNewConstMetric(r.condition, prometheus.GaugeValue, boolFloat64(string(condition.Status) == status), append(labels, string(condition.Type), status)...)
+			}
+		}
+	}
+
+	for i, g := range r.Gauges {
+		if value, ok := g.Value(obj); ok {
+			ch <- prometheus.MustNewConstMetric(r.gauges[i], prometheus.GaugeValue, value, labels...)
+		}
+	}
+}
+
+func boolFloat64(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Int32Value returns a Gauge value func reading an int32 from an object of type T.
+func Int32Value[T runtime.Object](f func(obj T) int32) func(client.Object) (float64, bool) {
+	return func(obj client.Object) (float64, bool) {
+		o, ok := obj.(T)
+		if !ok {
+			return 0, false
+		}
+		return float64(f(o)), true
+	}
+}
+
+// Int32PtrValue returns a Gauge value func reading an optional int32 from an object of type T.
+func Int32PtrValue[T runtime.Object](f func(obj T) *int32) func(client.Object) (float64, bool) {
+	return func(obj client.Object) (float64, bool) {
+		o, ok := obj.(T)
+		if !ok {
+			return 0, false
+		}
+		v := f(o)
+		if v == nil {
+			return 0, false
+		}
+		return float64(*v), true
+	}
+}
+
+// TimePtrValue returns a Gauge value func reading an optional time from an object of type T, as unix seconds.
+func TimePtrValue[T runtime.Object](f func(obj T) *metav1.Time) func(client.Object) (float64, bool) {
+	return func(obj client.Object) (float64, bool) {
+		o, ok := obj.(T)
+		if !ok {
+			return 0, false
+		}
+		v := f(o)
+		if v == nil {
+			return 0, false
+		}
+		return float64(v.Unix()), true
+	}
+}
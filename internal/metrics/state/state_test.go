@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestCollector(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	created := metav1.NewTime(time.Unix(1000, 0))
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cluster1",
+			Namespace:         metav1.NamespaceDefault,
+			CreationTimestamp: created,
+		},
+		Status: clusterv1.ClusterStatus{
+			Phase: string(clusterv1.ClusterPhaseProvisioned),
+		},
+	}
+	conditions.MarkTrue(cluster, clusterv1.ReadyCondition)
+	machineSet := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "ms1",
+			Namespace:         metav1.NamespaceDefault,
+			CreationTimestamp: created,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel: "cluster1",
+			},
+		},
+		Spec: clusterv1.MachineSetSpec{
+			Replicas: pointer.Int32(3),
+		},
+		Status: clusterv1.MachineSetStatus{
+			Replicas:             3,
+			FullyLabeledReplicas: 3,
+			ReadyReplicas:        2,
+			AvailableReplicas:    1,
+		},
+	}
+
+	mhc := &clusterv1.MachineHealthCheck{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "mhc1",
+			Namespace:         metav1.NamespaceDefault,
+			CreationTimestamp: created,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel: "cluster1",
+			},
+		},
+		Spec: clusterv1.MachineHealthCheckSpec{
+			Mode: clusterv1.MachineHealthCheckDryRunMode,
+		},
+		Status: clusterv1.MachineHealthCheckStatus{
+			ExpectedMachines:         3,
+			CurrentHealthy:           1,
+			RemediationsAllowed:      2,
+			DryRunRemediationTargets: []string{"m1", "m2"},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, machineSet, mhc).Build()
+	collector := NewCollector(c, ClusterResource(), MachineSetResource(), MachineHealthCheckResource())
+
+	expected := `
+# HELP capi_cluster_created Unix creation timestamp.
+# TYPE capi_cluster_created gauge
+capi_cluster_created{cluster_name="",name="cluster1",namespace="default"} 1000
+# HELP capi_cluster_status_condition The condition of a cluster.
+# TYPE capi_cluster_status_condition gauge
+capi_cluster_status_condition{cluster_name="",name="cluster1",namespace="default",status="False",type="Ready"} 0
+capi_cluster_status_condition{cluster_name="",name="cluster1",namespace="default",status="True",type="Ready"} 1
+capi_cluster_status_condition{cluster_name="",name="cluster1",namespace="default",status="Unknown",type="Ready"} 0
+# HELP capi_cluster_status_phase The clusters current phase.
+# TYPE capi_cluster_status_phase gauge
+capi_cluster_status_phase{cluster_name="",name="cluster1",namespace="default",phase="Deleting"} 0
+capi_cluster_status_phase{cluster_name="",name="cluster1",namespace="default",phase="Failed"} 0
+capi_cluster_status_phase{cluster_name="",name="cluster1",namespace="default",phase="Pending"} 0
+capi_cluster_status_phase{cluster_name="",name="cluster1",namespace="default",phase="Provisioned"} 1
+capi_cluster_status_phase{cluster_name="",name="cluster1",namespace="default",phase="Provisioning"} 0
+capi_cluster_status_phase{cluster_name="",name="cluster1",namespace="default",phase="Unknown"} 0
+# HELP capi_machinehealthcheck_created Unix creation timestamp.
+# TYPE capi_machinehealthcheck_created gauge
+capi_machinehealthcheck_created{cluster_name="cluster1",name="mhc1",namespace="default"} 1000
+# HELP capi_machinehealthcheck_status_current_healthy The number of healthy machines per machinehealthcheck.
+# TYPE capi_machinehealthcheck_status_current_healthy gauge
+capi_machinehealthcheck_status_current_healthy{cluster_name="cluster1",name="mhc1",namespace="default"} 1
+# HELP capi_machinehealthcheck_status_dry_run_remediation_targets The number of machines a machinehealthcheck in DryRun mode would have remediated.
+# TYPE capi_machinehealthcheck_status_dry_run_remediation_targets gauge
+capi_machinehealthcheck_status_dry_run_remediation_targets{cluster_name="cluster1",name="mhc1",namespace="default"} 2
+# HELP capi_machinehealthcheck_status_expected_machines The number of machines monitored by a machinehealthcheck.
+# TYPE capi_machinehealthcheck_status_expected_machines gauge
+capi_machinehealthcheck_status_expected_machines{cluster_name="cluster1",name="mhc1",namespace="default"} 3
+# HELP capi_machinehealthcheck_status_remediations_allowed The number of further remediations allowed by a machinehealthcheck.
+# TYPE capi_machinehealthcheck_status_remediations_allowed gauge
+capi_machinehealthcheck_status_remediations_allowed{cluster_name="cluster1",name="mhc1",namespace="default"} 2
+# HELP capi_machineset_created Unix creation timestamp.
+# TYPE capi_machineset_created gauge
+capi_machineset_created{cluster_name="cluster1",name="ms1",namespace="default"} 1000
+# HELP capi_machineset_spec_replicas The number of desired machines for a machineset.
+# TYPE capi_machineset_spec_replicas gauge
+capi_machineset_spec_replicas{cluster_name="cluster1",name="ms1",namespace="default"} 3
+# HELP capi_machineset_status_available_replicas The number of available replicas per machineset.
+# TYPE capi_machineset_status_available_replicas gauge
+capi_machineset_status_available_replicas{cluster_name="cluster1",name="ms1",namespace="default"} 1
+# HELP capi_machineset_status_fully_labeled_replicas The number of fully labeled replicas per machineset.
+# TYPE capi_machineset_status_fully_labeled_replicas gauge
+capi_machineset_status_fully_labeled_replicas{cluster_name="cluster1",name="ms1",namespace="default"} 3
+# HELP capi_machineset_status_ready_replicas The number of ready replicas per machineset.
+# TYPE capi_machineset_status_ready_replicas gauge
+capi_machineset_status_ready_replicas{cluster_name="cluster1",name="ms1",namespace="default"} 2
+# HELP capi_machineset_status_replicas The number of replicas per machineset.
+# TYPE capi_machineset_status_replicas gauge
+capi_machineset_status_replicas{cluster_name="cluster1",name="ms1",namespace="default"} 3
+`
+	g.Expect(testutil.CollectAndCompare(collector, strings.NewReader(expected))).To(Succeed())
+}
+
+func TestTimePtrValue(t *testing.T) {
+	g := NewWithT(t)
+
+	value := TimePtrValue(func(m *clusterv1.Machine) *metav1.Time { return m.Status.CertificatesExpiryDate })
+
+	_, ok := value(&clusterv1.Machine{})
+	g.Expect(ok).To(BeFalse())
+
+	_, ok = value(&clusterv1.Cluster{})
+	g.Expect(ok).To(BeFalse())
+
+	expiry := metav1.NewTime(time.Unix(2000, 0))
+	v, ok := value(&clusterv1.Machine{Status: clusterv1.MachineStatus{CertificatesExpiryDate: &expiry}})
+	g.Expect(ok).To(BeTrue())
+	g.Expect(v).To(Equal(float64(2000)))
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profiling
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewPhaseClient returns a client recording all the calls as the given phase of the reconcile profile
+// in the context of each call, e.g. for recording calls to workload clusters as PhaseRemote.
+func NewPhaseClient(c client.Client, phase Phase) client.Client {
+	return &phaseClient{Client: c, phase: phase}
+}
+
+type phaseClient struct {
+	client.Client
+	phase Phase
+}
+
+func (c *phaseClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	defer StartPhase(ctx, c.phase)()
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *phaseClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	defer StartPhase(ctx, c.phase)()
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *phaseClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	defer StartPhase(ctx, c.phase)()
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *phaseClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	defer StartPhase(ctx, c.phase)()
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *phaseClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	defer StartPhase(ctx, c.phase)()
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *phaseClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	defer StartPhase(ctx, c.phase)()
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *phaseClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	defer StartPhase(ctx, c.phase)()
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
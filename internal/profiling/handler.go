@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profiling
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HandlerPath is the path the reconcile profiles are exposed at.
+const HandlerPath = "/debug/reconcile-profiles"
+
+// profileView is the representation of a Profile exposed by the Handler, with durations in seconds.
+type profileView struct {
+	Controller    string            `json:"controller"`
+	Cluster       string            `json:"cluster"`
+	Reconciles    int64             `json:"reconciles"`
+	Total         float64           `json:"totalSeconds"`
+	Average       float64           `json:"averageSeconds"`
+	Max           float64           `json:"maxSeconds"`
+	Phases        map[Phase]float64 `json:"phasesSeconds"`
+	LastReconcile time.Time         `json:"lastReconcile"`
+}
+
+// Handler returns an http.Handler exposing the profiles recorded by the given Recorder as JSON, sorted by
+// total duration in descending order. The following query parameters are supported:
+// - controller: only return the profiles of the given controller.
+// - top: only return the given number of profiles.
+// DELETE requests reset the recorded profiles.
+func Handler(r *Recorder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+		case http.MethodDelete:
+			r.Reset()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		top := -1
+		if v := req.URL.Query().Get("top"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				http.Error(w, "top must be a non-negative integer", http.StatusBadRequest)
+				return
+			}
+			top = n
+		}
+		controller := req.URL.Query().Get("controller")
+
+		views := []profileView{}
+		for _, p := range r.Profiles() {
+			if controller != "" && p.Controller != controller {
+				continue
+			}
+			if top >= 0 && len(views) >= top {
+				break
+			}
+			view := profileView{
+				Controller:    p.Controller,
+				Cluster:       p.Cluster,
+				Reconciles:    p.Reconciles,
+				Total:         p.Total.Seconds(),
+				Max:           p.Max.Seconds(),
+				Phases:        map[Phase]float64{},
+				LastReconcile: p.LastReconcile,
+			}
+			if p.Reconciles > 0 {
+				view.Average = (p.Total / time.Duration(p.Reconciles)).Seconds()
+			}
+			for phase, d := range p.Phases {
+				view.Phases[phase] = d.Seconds()
+			}
+			views = append(views, view)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(views)
+	})
+}
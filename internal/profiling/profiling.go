@@ -0,0 +1,286 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profiling
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Phase is a phase of a reconcile.
+type Phase string
+
+const (
+	// PhaseFetch is the phase reading the reconciled object and related objects from the management cluster.
+	PhaseFetch Phase = "fetch"
+
+	// PhaseCompute is the time of a reconcile not spent in any other phase.
+	PhaseCompute Phase = "compute"
+
+	// PhaseRemote is the phase calling the API server of a workload cluster.
+	PhaseRemote Phase = "remote"
+
+	// PhasePatch is the phase patching the reconciled object.
+	PhasePatch Phase = "patch"
+)
+
+const (
+	// DefaultRetention is the default duration profiles are retained for after the last recorded reconcile.
+	DefaultRetention = time.Hour
+
+	// pruneInterval is the minimum interval between two prunings of expired profiles when recording reconciles.
+	pruneInterval = time.Minute
+)
+
+var enabled atomic.Bool
+
+// DefaultRecorder is the Recorder used to record reconcile profiles.
+var DefaultRecorder = NewRecorder()
+
+// Enable enables the recording of reconcile profiles.
+func Enable() {
+	enabled.Store(true)
+}
+
+// Enabled returns true if the recording of reconcile profiles is enabled.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Profile is the aggregated profile of the reconciles of a controller for a Cluster.
+type Profile struct {
+	// Controller is the name of the controller.
+	Controller string `json:"controller"`
+
+	// Cluster is the Cluster the reconciled objects belong to, in the namespace/name format;
+	// it is empty if the reconciled objects could not be associated to a Cluster.
+	Cluster string `json:"cluster"`
+
+	// Reconciles is the number of reconciles.
+	Reconciles int64 `json:"reconciles"`
+
+	// Total is the total duration of the reconciles.
+	Total time.Duration `json:"total"`
+
+	// Max is the duration of the longest reconcile.
+	Max time.Duration `json:"max"`
+
+	// Phases is the total duration of the reconciles by phase.
+	Phases map[Phase]time.Duration `json:"phases"`
+
+	// LastReconcile is the time the last reconcile was recorded.
+	LastReconcile time.Time `json:"lastReconcile"`
+}
+
+type profileKey struct {
+	controller string
+	cluster    string
+}
+
+// Recorder aggregates reconcile profiles by controller and by Cluster.
+// Profiles without reconciles recorded within the retention, e.g. the profiles of deleted Clusters, are deleted,
+// so the memory used by the Recorder does not grow indefinitely with the churn of Clusters.
+type Recorder struct {
+	lock      sync.Mutex
+	profiles  map[profileKey]*Profile
+	retention time.Duration
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// NewRecorder returns a new Recorder with the DefaultRetention.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		profiles:  map[profileKey]*Profile{},
+		retention: DefaultRetention,
+		now:       time.Now,
+	}
+}
+
+// SetRetention sets the duration profiles are retained for after the last recorded reconcile;
+// profiles are retained until reset if the retention is 0.
+func (r *Recorder) SetRetention(retention time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.retention = retention
+}
+
+// Record records a reconcile.
+func (r *Recorder) Record(controller, cluster string, total time.Duration, phases map[Phase]time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	if now.Sub(r.lastPrune) >= pruneInterval {
+		r.prune(now)
+	}
+
+	key := profileKey{controller: controller, cluster: cluster}
+	p, ok := r.profiles[key]
+	if !ok {
+		p = &Profile{
+			Controller: controller,
+			Cluster:    cluster,
+			Phases:     map[Phase]time.Duration{},
+		}
+		r.profiles[key] = p
+	}
+	p.Reconciles++
+	p.Total += total
+	if total > p.Max {
+		p.Max = total
+	}
+	for phase, d := range phases {
+		p.Phases[phase] += d
+	}
+	p.LastReconcile = now
+}
+
+// prune deletes the profiles without reconciles recorded within the retention.
+// NOTE: this func assumes the lock is already held.
+func (r *Recorder) prune(now time.Time) {
+	r.lastPrune = now
+	if r.retention <= 0 {
+		return
+	}
+	for key, p := range r.profiles {
+		if now.Sub(p.LastReconcile) > r.retention {
+			delete(r.profiles, key)
+		}
+	}
+}
+
+// Profiles returns a copy of the recorded profiles, sorted by total duration in descending order.
+func (r *Recorder) Profiles() []Profile {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.prune(r.now())
+
+	profiles := make([]Profile, 0, len(r.profiles))
+	for _, p := range r.profiles {
+		c := *p
+		c.Phases = make(map[Phase]time.Duration, len(p.Phases))
+		for phase, d := range p.Phases {
+			c.Phases[phase] = d
+		}
+		profiles = append(profiles, c)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Total != profiles[j].Total {
+			return profiles[i].Total > profiles[j].Total
+		}
+		if profiles[i].Controller != profiles[j].Controller {
+			return profiles[i].Controller < profiles[j].Controller
+		}
+		return profiles[i].Cluster < profiles[j].Cluster
+	})
+	return profiles
+}
+
+// Reset deletes all the recorded profiles.
+func (r *Recorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.profiles = map[profileKey]*Profile{}
+}
+
+// reconcileProfile is the profile of an in-flight reconcile.
+type reconcileProfile struct {
+	lock        sync.Mutex
+	cluster     string
+	phases      map[Phase]time.Duration
+	activePhase Phase
+}
+
+type reconcileProfileKey struct{}
+
+// StartReconcile starts the profile of a reconcile; the returned function must be called when the reconcile
+// completes in order to record the profile in the DefaultRecorder.
+func StartReconcile(ctx context.Context, controller string) (context.Context, func()) {
+	if !Enabled() {
+		return ctx, func() {}
+	}
+
+	start := time.Now()
+	p := &reconcileProfile{phases: map[Phase]time.Duration{}}
+	return context.WithValue(ctx, reconcileProfileKey{}, p), func() {
+		total := time.Since(start)
+
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		// The time not spent in any other phase is considered compute time.
+		compute := total
+		for _, d := range p.phases {
+			compute -= d
+		}
+		if compute > 0 {
+			p.phases[PhaseCompute] += compute
+		}
+		DefaultRecorder.Record(controller, p.cluster, total, p.phases)
+	}
+}
+
+// SetCluster sets the Cluster the object reconciled by the reconcile profile in the context belongs to.
+func SetCluster(ctx context.Context, cluster types.NamespacedName) {
+	p, ok := ctx.Value(reconcileProfileKey{}).(*reconcileProfile)
+	if !ok {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.cluster = cluster.String()
+}
+
+// StartPhase starts a phase of the reconcile profile in the context; the returned function must be called
+// when the phase completes and it can be called multiple times, e.g. explicitly and deferred for the error paths.
+// Phases started while another phase is in progress, e.g. remote calls while fetching objects, are not recorded,
+// so the time is not counted twice.
+func StartPhase(ctx context.Context, phase Phase) func() {
+	p, ok := ctx.Value(reconcileProfileKey{}).(*reconcileProfile)
+	if !ok {
+		return func() {}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.activePhase != "" {
+		return func() {}
+	}
+	p.activePhase = phase
+
+	start := time.Now()
+	stopped := false
+	return func() {
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		if stopped {
+			return
+		}
+		stopped = true
+		p.phases[phase] += time.Since(start)
+		p.activePhase = ""
+	}
+}
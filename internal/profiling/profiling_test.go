@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profiling
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestStartReconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	Enable()
+	DefaultRecorder.Reset()
+	defer DefaultRecorder.Reset()
+
+	ctx, done := StartReconcile(context.Background(), "machine")
+	SetCluster(ctx, types.NamespacedName{Namespace: "ns1", Name: "cluster1"})
+
+	fetchDone := StartPhase(ctx, PhaseFetch)
+	// Nested phases are not recorded.
+	remoteDone := StartPhase(ctx, PhaseRemote)
+	time.Sleep(10 * time.Millisecond)
+	remoteDone()
+	fetchDone()
+	// Stopping a phase multiple times does not change the recorded duration.
+	fetchDone()
+
+	patchDone := StartPhase(ctx, PhasePatch)
+	time.Sleep(10 * time.Millisecond)
+	patchDone()
+	done()
+
+	profiles := DefaultRecorder.Profiles()
+	g.Expect(profiles).To(HaveLen(1))
+	p := profiles[0]
+	g.Expect(p.Controller).To(Equal("machine"))
+	g.Expect(p.Cluster).To(Equal("ns1/cluster1"))
+	g.Expect(p.Reconciles).To(Equal(int64(1)))
+	g.Expect(p.Phases).To(HaveKey(PhaseFetch))
+	g.Expect(p.Phases).To(HaveKey(PhasePatch))
+	g.Expect(p.Phases).ToNot(HaveKey(PhaseRemote))
+	g.Expect(p.Phases[PhaseFetch]).To(BeNumerically(">=", 10*time.Millisecond))
+
+	var sum time.Duration
+	for _, d := range p.Phases {
+		sum += d
+	}
+	g.Expect(sum).To(BeNumerically("~", p.Total, time.Millisecond))
+}
+
+func TestHandler(t *testing.T) {
+	g := NewWithT(t)
+
+	r := NewRecorder()
+	r.Record("machine", "ns1/cluster1", 1*time.Second, map[Phase]time.Duration{PhaseCompute: 1 * time.Second})
+	r.Record("machine", "ns1/cluster2", 3*time.Second, map[Phase]time.Duration{PhaseRemote: 3 * time.Second})
+	r.Record("machine", "ns1/cluster2", 1*time.Second, map[Phase]time.Duration{PhaseRemote: 1 * time.Second})
+	r.Record("cluster", "ns1/cluster1", 2*time.Second, map[Phase]time.Duration{PhasePatch: 2 * time.Second})
+
+	get := func(query string) []profileView {
+		rec := httptest.NewRecorder()
+		Handler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, HandlerPath+query, http.NoBody))
+		g.Expect(rec.Code).To(Equal(http.StatusOK))
+		views := []profileView{}
+		g.Expect(json.Unmarshal(rec.Body.Bytes(), &views)).To(Succeed())
+		return views
+	}
+
+	views := get("")
+	g.Expect(views).To(HaveLen(3))
+	g.Expect(views[0].Cluster).To(Equal("ns1/cluster2"))
+	g.Expect(views[0].Reconciles).To(Equal(int64(2)))
+	g.Expect(views[0].Total).To(Equal(4.0))
+	g.Expect(views[0].Average).To(Equal(2.0))
+	g.Expect(views[0].Max).To(Equal(3.0))
+	g.Expect(views[0].Phases).To(HaveKeyWithValue(PhaseRemote, 4.0))
+	g.Expect(views[1].Controller).To(Equal("cluster"))
+
+	g.Expect(get("?top=1")).To(HaveLen(1))
+	g.Expect(get("?controller=cluster")).To(HaveLen(1))
+
+	rec := httptest.NewRecorder()
+	Handler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, HandlerPath, http.NoBody))
+	g.Expect(rec.Code).To(Equal(http.StatusNoContent))
+	g.Expect(get("")).To(BeEmpty())
+}
+
+func TestRecorderRetention(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	r := NewRecorder()
+	r.now = func() time.Time { return now }
+
+	r.Record("machine", "ns1/cluster1", 1*time.Second, nil)
+	now = now.Add(DefaultRetention / 2)
+	r.Record("machine", "ns1/cluster2", 1*time.Second, nil)
+	g.Expect(r.Profiles()).To(HaveLen(2))
+
+	// Profiles without reconciles within the retention are deleted.
+	now = now.Add(DefaultRetention/2 + time.Second)
+	profiles := r.Profiles()
+	g.Expect(profiles).To(HaveLen(1))
+	g.Expect(profiles[0].Cluster).To(Equal("ns1/cluster2"))
+	g.Expect(profiles[0].LastReconcile).To(Equal(now.Add(-DefaultRetention/2 - time.Second)))
+
+	// Profiles are retained until reset if the retention is 0.
+	r.SetRetention(0)
+	now = now.Add(10 * DefaultRetention)
+	g.Expect(r.Profiles()).To(HaveLen(1))
+}
@@ -43,10 +43,12 @@ import (
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/internal/hooks"
 	runtimemetrics "sigs.k8s.io/cluster-api/internal/runtime/metrics"
 	runtimeregistry "sigs.k8s.io/cluster-api/internal/runtime/registry"
 	"sigs.k8s.io/cluster-api/util"
@@ -54,7 +56,47 @@ import (
 
 type errCallingExtensionHandler error
 
-const defaultDiscoveryTimeout = 10 * time.Second
+const (
+	defaultDiscoveryTimeout = 10 * time.Second
+
+	// maxRetryTime is the maximum time calls to an extension handler with a RetryPolicy are retried for, starting
+	// from the first failed call.
+	maxRetryTime = 10 * time.Minute
+)
+
+// RetryableCallError is returned when a call to an extension handler with a RetryPolicy failed and it is going to be
+// retried, or when a previous call failed and the backoff is not expired yet; the object the call is made for should
+// be requeued after RequeueAfter.
+type RetryableCallError struct {
+	Name         string
+	RequeueAfter time.Duration
+	Err          error
+}
+
+func (e *RetryableCallError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("waiting %s before retrying the call to extension handler %q", e.RequeueAfter.Round(time.Second), e.Name)
+	}
+	return fmt.Sprintf("call to extension handler %q failed, retrying in %s: %v", e.Name, e.RequeueAfter.Round(time.Second), e.Err)
+}
+
+func (e *RetryableCallError) Unwrap() error {
+	return e.Err
+}
+
+// RequeueRetryableCallErrors wraps a reconciler so RetryableCallErrors requeue the object after the backoff of the
+// extension handler being retried, instead of being returned to controller-runtime, which would requeue the object
+// according to the rate limiter of the controller.
+func RequeueRetryableCallErrors(r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		result, err := r.Reconcile(ctx, req)
+		var retryableCallError *RetryableCallError
+		if errors.As(err, &retryableCallError) {
+			return reconcile.Result{RequeueAfter: retryableCallError.RequeueAfter}, nil
+		}
+		return result, err
+	})
+}
 
 // Options are creation options for a Client.
 type Options struct {
@@ -153,24 +195,45 @@ func (c *client) Discover(ctx context.Context, extensionConfig *runtimev1.Extens
 	// Reset the handlers that were previously registered with the ExtensionConfig.
 	modifiedExtensionConfig.Status.Handlers = []runtimev1.ExtensionHandler{}
 
+	policies := map[string]runtimev1.ExtensionHandlerPolicy{}
+	for _, policy := range extensionConfig.Spec.HandlerPolicies {
+		policies[policy.Name] = policy
+	}
+
 	for _, handler := range response.Handlers {
-		modifiedExtensionConfig.Status.Handlers = append(
-			modifiedExtensionConfig.Status.Handlers,
-			runtimev1.ExtensionHandler{
-				Name: handler.Name + "." + extensionConfig.Name, // Uniquely identifies a handler of an Extension.
-				RequestHook: runtimev1.GroupVersionHook{
-					APIVersion: handler.RequestHook.APIVersion,
-					Hook:       handler.RequestHook.Hook,
-				},
-				TimeoutSeconds: handler.TimeoutSeconds,
-				FailurePolicy:  (*runtimev1.FailurePolicy)(handler.FailurePolicy),
+		extensionHandler := runtimev1.ExtensionHandler{
+			Name: handler.Name + "." + extensionConfig.Name, // Uniquely identifies a handler of an Extension.
+			RequestHook: runtimev1.GroupVersionHook{
+				APIVersion: handler.RequestHook.APIVersion,
+				Hook:       handler.RequestHook.Hook,
 			},
-		)
+			TimeoutSeconds: handler.TimeoutSeconds,
+			FailurePolicy:  (*runtimev1.FailurePolicy)(handler.FailurePolicy),
+		}
+		if policy, ok := policies[handler.Name]; ok {
+			applyHandlerPolicy(&extensionHandler, policy)
+		}
+		modifiedExtensionConfig.Status.Handlers = append(modifiedExtensionConfig.Status.Handlers, extensionHandler)
 	}
 
 	return modifiedExtensionConfig, nil
 }
 
+// applyHandlerPolicy overrides the values returned by the Extension server for an ExtensionHandler
+// with the values defined in the corresponding ExtensionHandlerPolicy.
+func applyHandlerPolicy(handler *runtimev1.ExtensionHandler, policy runtimev1.ExtensionHandlerPolicy) {
+	if policy.TimeoutSeconds != nil {
+		handler.TimeoutSeconds = pointer.Int32(*policy.TimeoutSeconds)
+	}
+	if policy.FailurePolicy != nil {
+		failurePolicy := *policy.FailurePolicy
+		handler.FailurePolicy = &failurePolicy
+	}
+	if policy.RetryPolicy != nil {
+		handler.RetryPolicy = policy.RetryPolicy.DeepCopy()
+	}
+}
+
 func (c *client) Register(extensionConfig *runtimev1.ExtensionConfig) error {
 	if err := c.registry.Add(extensionConfig); err != nil {
 		return errors.Wrapf(err, "failed to register ExtensionConfig %q", extensionConfig.Name)
@@ -278,7 +341,13 @@ func aggregateSuccessfulResponses(aggregatedResponse runtimehooksv1.ResponseObje
 // If the ExtensionHandler returns a response with `Status` set to `Failure` the function returns an error
 // and the response object is updated with the response received from the extension handler.
 //
-// FailurePolicy of the ExtensionHandler is used to handle errors that occur when performing the external call to the extension.
+// RetryPolicy of the ExtensionHandler is used to retry calls failing because of errors that occur when performing the
+// external call to the extension, e.g. network errors or timeouts; the time between retries is doubled at every retry.
+// Retries are not executed in place: a RetryableCallError is returned instead, and the number of failed calls is tracked
+// in the FailedCallAttemptsAnnotation of forObject, which must be a client.Object for calls to be retried.
+//
+// FailurePolicy of the ExtensionHandler is used to handle errors that occur when performing the external call to the extension,
+// after all the retries are exhausted.
 // - If FailurePolicy is set to Ignore, the error is ignored and the response object is updated to be the default success response.
 // - If FailurePolicy is set to Fail, an error is returned and the response object may or may not be updated.
 // Nb. FailurePolicy does not affect the following kinds of errors:
@@ -335,7 +404,22 @@ func (c *client) CallExtension(ctx context.Context, hook runtimecatalog.Hook, fo
 		name:            strings.TrimSuffix(registration.Name, "."+registration.ExtensionConfigName),
 		timeout:         timeoutDuration,
 	}
+	// If a previous call to an extension handler with a RetryPolicy failed, wait for the backoff to expire before calling it again.
+	var attempts int32
+	var since time.Time
+	if registration.RetryPolicy != nil {
+		attempts, since = hooks.FailedCallAttempts(forObject, registration.Name)
+		if next := nextRetryTime(registration.RetryPolicy, attempts, since); attempts > 0 && time.Now().Before(next) {
+			return &RetryableCallError{Name: name, RequeueAfter: time.Until(next)}
+		}
+	}
+
 	err = httpCall(ctx, request, response, opts)
+	if registration.RetryPolicy != nil {
+		if err := c.trackFailedCall(ctx, forObject, registration, attempts, since, err); err != nil {
+			return err
+		}
+	}
 	if err != nil {
 		// If the error is errCallingExtensionHandler then apply failure policy to calculate
 		// the effective result of the operation.
@@ -386,6 +470,38 @@ func cloneAndAddSettings(request runtimehooksv1.RequestObject, registrationSetti
 	return request
 }
 
+// trackFailedCall tracks the failed calls to an extension handler with a RetryPolicy in the FailedCallAttemptsAnnotation
+// of the object the call is made for, and returns a RetryableCallError if the call failed and it has to be retried.
+// NOTE: Retries are not executed in place, which would block the calling controller, but by requeuing the object after
+// the backoff; retries stop after MaxRetries, or when maxRetryTime has passed since the first failed call.
+func (c *client) trackFailedCall(ctx context.Context, forObject metav1.Object, registration *runtimeregistry.ExtensionRegistration, attempts int32, since time.Time, callErr error) error {
+	obj, ok := forObject.(ctrlclient.Object)
+	if !ok {
+		return nil
+	}
+	if callErr == nil || attempts >= registration.RetryPolicy.MaxRetries || (attempts > 0 && time.Since(since) >= maxRetryTime) {
+		return hooks.MarkCallCompleted(ctx, c.client, obj, registration.Name)
+	}
+
+	if attempts == 0 {
+		since = time.Now()
+	}
+	attempts++
+	if err := hooks.MarkCallFailed(ctx, c.client, obj, registration.Name, attempts, since); err != nil {
+		return err
+	}
+	requeueAfter := time.Until(nextRetryTime(registration.RetryPolicy, attempts, since))
+	ctrl.LoggerFrom(ctx).Info(fmt.Sprintf("retrying call to extension handler in %s", requeueAfter.Round(time.Second)), "retry", attempts, "error", callErr.Error())
+	return &RetryableCallError{Name: registration.Name, RequeueAfter: requeueAfter, Err: callErr}
+}
+
+// nextRetryTime returns the time of the next retry of a call to an extension handler which failed the given number
+// of times since the first failed call; the time between retries is doubled at every retry.
+func nextRetryTime(retryPolicy *runtimev1.RetryPolicy, attempts int32, since time.Time) time.Time {
+	backoff := time.Duration(pointer.Int32Deref(retryPolicy.BackoffSeconds, 1)) * time.Second
+	return since.Add(backoff * time.Duration((1<<attempts)-1))
+}
+
 type httpCallOptions struct {
 	catalog         *runtimecatalog.Catalog
 	config          runtimev1.ClientConfig
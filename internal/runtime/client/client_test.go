@@ -25,9 +25,12 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"regexp"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -41,6 +44,7 @@ import (
 	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/internal/hooks"
 	runtimeregistry "sigs.k8s.io/cluster-api/internal/runtime/registry"
 	fakev1alpha1 "sigs.k8s.io/cluster-api/internal/runtime/test/v1alpha1"
 	fakev1alpha2 "sigs.k8s.io/cluster-api/internal/runtime/test/v1alpha2"
@@ -789,6 +793,233 @@ func TestClient_CallExtension(t *testing.T) {
 	}
 }
 
+func TestClient_CallExtensionWithRetryPolicy(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+	}
+	fpFail := runtimev1.FailurePolicyFail
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+
+	// expireBackoff moves the time of the first failed call back, as if the backoff before the next retry was expired.
+	expireBackoff := func(g *WithT, c ctrlclient.Client, obj *clusterv1.Cluster, retryPolicy *runtimev1.RetryPolicy, elapsed time.Duration) {
+		g.Expect(c.Get(context.Background(), ctrlclient.ObjectKeyFromObject(obj), obj)).To(Succeed())
+		attempts, _ := hooks.FailedCallAttempts(obj, "valid-extension")
+		g.Expect(attempts).ToNot(BeZero())
+		since := time.Now().Add(-elapsed)
+		if elapsed == 0 {
+			since = time.Now().Add(-time.Until(nextRetryTime(retryPolicy, attempts, time.Now())) - time.Second)
+		}
+		g.Expect(hooks.MarkCallFailed(context.Background(), c, obj, "valid-extension", attempts, since)).To(Succeed())
+		g.Expect(c.Get(context.Background(), ctrlclient.ObjectKeyFromObject(obj), obj)).To(Succeed())
+	}
+
+	tests := []struct {
+		name            string
+		retryPolicy     *runtimev1.RetryPolicy
+		failedCalls     int32
+		elapsed         time.Duration
+		wantRetryErrors int
+		wantErr         bool
+		wantCallCount   int32
+	}{
+		{
+			name:            "should fail without retries if RetryPolicy is not set",
+			retryPolicy:     nil,
+			failedCalls:     1,
+			wantRetryErrors: 0,
+			wantErr:         true,
+			wantCallCount:   1,
+		},
+		{
+			name:            "should succeed if the call succeeds within MaxRetries",
+			retryPolicy:     &runtimev1.RetryPolicy{MaxRetries: 2, BackoffSeconds: pointer.Int32(1)},
+			failedCalls:     2,
+			wantRetryErrors: 2,
+			wantErr:         false,
+			wantCallCount:   3,
+		},
+		{
+			name:            "should fail if the call fails after MaxRetries",
+			retryPolicy:     &runtimev1.RetryPolicy{MaxRetries: 1, BackoffSeconds: pointer.Int32(1)},
+			failedCalls:     3,
+			wantRetryErrors: 1,
+			wantErr:         true,
+			wantCallCount:   2,
+		},
+		{
+			name:            "should fail if the call fails after the maximum retry time",
+			retryPolicy:     &runtimev1.RetryPolicy{MaxRetries: 5, BackoffSeconds: pointer.Int32(1)},
+			failedCalls:     6,
+			elapsed:         maxRetryTime,
+			wantRetryErrors: 1,
+			wantErr:         true,
+			wantCallCount:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var callCount int32
+			mux := http.NewServeMux()
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&callCount, 1) <= tt.failedCalls {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				respBody, err := json.Marshal(fakeSuccessResponse(""))
+				if err != nil {
+					panic(err)
+				}
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(respBody)
+			})
+			srv := newUnstartedTLSServer(mux)
+			srv.StartTLS()
+			defer srv.Close()
+
+			extensionConfig := runtimev1.ExtensionConfig{
+				Spec: runtimev1.ExtensionConfigSpec{
+					ClientConfig: runtimev1.ClientConfig{
+						URL:      pointer.String(fmt.Sprintf("https://%s/", srv.Listener.Addr().String())),
+						CABundle: testcerts.CACert,
+					},
+					NamespaceSelector: &metav1.LabelSelector{},
+				},
+				Status: runtimev1.ExtensionConfigStatus{
+					Handlers: []runtimev1.ExtensionHandler{
+						{
+							Name: "valid-extension",
+							RequestHook: runtimev1.GroupVersionHook{
+								APIVersion: fakev1alpha1.GroupVersion.String(),
+								Hook:       "FakeHook",
+							},
+							TimeoutSeconds: pointer.Int32(1),
+							FailurePolicy:  &fpFail,
+							RetryPolicy:    tt.retryPolicy,
+						},
+					},
+				},
+			}
+
+			obj := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster",
+					Namespace: "foo",
+				},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns, obj).Build()
+
+			cat := runtimecatalog.New()
+			_ = fakev1alpha1.AddToCatalog(cat)
+			c := New(Options{
+				Catalog:  cat,
+				Registry: registry([]runtimev1.ExtensionConfig{extensionConfig}),
+				Client:   fakeClient,
+			})
+
+			call := func() error {
+				return c.CallExtension(context.Background(), fakev1alpha1.FakeHook, obj, "valid-extension", &fakev1alpha1.FakeRequest{}, &fakev1alpha1.FakeResponse{})
+			}
+
+			// Failed calls are retried by requeuing the object instead of retrying in place.
+			for i := 0; i < tt.wantRetryErrors; i++ {
+				var retryableCallError *RetryableCallError
+				g.Expect(errors.As(call(), &retryableCallError)).To(BeTrue())
+				g.Expect(retryableCallError.RequeueAfter).To(BeNumerically(">", 0))
+
+				// The extension handler is not called again until the backoff expires.
+				callCountBefore := atomic.LoadInt32(&callCount)
+				g.Expect(c.CallExtension(context.Background(), fakev1alpha1.FakeHook, reload(g, fakeClient, obj), "valid-extension", &fakev1alpha1.FakeRequest{}, &fakev1alpha1.FakeResponse{})).To(HaveOccurred())
+				g.Expect(atomic.LoadInt32(&callCount)).To(Equal(callCountBefore))
+
+				expireBackoff(g, fakeClient, obj, tt.retryPolicy, tt.elapsed)
+			}
+
+			err := call()
+			if tt.wantErr {
+				var retryableCallError *RetryableCallError
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(errors.As(err, &retryableCallError)).To(BeFalse())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(atomic.LoadInt32(&callCount)).To(Equal(tt.wantCallCount))
+
+			// Once the retries are completed, the failed calls are not tracked anymore.
+			g.Expect(fakeClient.Get(context.Background(), ctrlclient.ObjectKeyFromObject(obj), obj)).To(Succeed())
+			g.Expect(obj.Annotations).ToNot(HaveKey(runtimev1.FailedCallAttemptsAnnotation))
+		})
+	}
+}
+
+func reload(g *WithT, c ctrlclient.Client, obj *clusterv1.Cluster) *clusterv1.Cluster {
+	g.Expect(c.Get(context.Background(), ctrlclient.ObjectKeyFromObject(obj), obj)).To(Succeed())
+	return obj
+}
+
+func Test_applyHandlerPolicy(t *testing.T) {
+	fpFail := runtimev1.FailurePolicyFail
+	fpIgnore := runtimev1.FailurePolicyIgnore
+
+	tests := []struct {
+		name    string
+		handler runtimev1.ExtensionHandler
+		policy  runtimev1.ExtensionHandlerPolicy
+		want    runtimev1.ExtensionHandler
+	}{
+		{
+			name: "should keep the values of the discovery if the policy is empty",
+			handler: runtimev1.ExtensionHandler{
+				Name:           "handler.extension",
+				TimeoutSeconds: pointer.Int32(10),
+				FailurePolicy:  &fpFail,
+			},
+			policy: runtimev1.ExtensionHandlerPolicy{
+				Name: "handler",
+			},
+			want: runtimev1.ExtensionHandler{
+				Name:           "handler.extension",
+				TimeoutSeconds: pointer.Int32(10),
+				FailurePolicy:  &fpFail,
+			},
+		},
+		{
+			name: "should override the values of the discovery with the values of the policy",
+			handler: runtimev1.ExtensionHandler{
+				Name:           "handler.extension",
+				TimeoutSeconds: pointer.Int32(10),
+				FailurePolicy:  &fpFail,
+			},
+			policy: runtimev1.ExtensionHandlerPolicy{
+				Name:           "handler",
+				TimeoutSeconds: pointer.Int32(30),
+				FailurePolicy:  &fpIgnore,
+				RetryPolicy:    &runtimev1.RetryPolicy{MaxRetries: 3},
+			},
+			want: runtimev1.ExtensionHandler{
+				Name:           "handler.extension",
+				TimeoutSeconds: pointer.Int32(30),
+				FailurePolicy:  &fpIgnore,
+				RetryPolicy:    &runtimev1.RetryPolicy{MaxRetries: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			applyHandlerPolicy(&tt.handler, tt.policy)
+			g.Expect(tt.handler).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestPrepareRequest(t *testing.T) {
 	t.Run("request should have the correct settings", func(t *testing.T) {
 		tests := []struct {
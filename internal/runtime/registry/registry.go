@@ -79,6 +79,9 @@ type ExtensionRegistration struct {
 	// FailurePolicy defines how failures in calls to the RuntimeExtension should be handled by a client.
 	FailurePolicy *runtimev1.FailurePolicy
 
+	// RetryPolicy defines how calls to the RuntimeExtension failing because of transient errors should be retried.
+	RetryPolicy *runtimev1.RetryPolicy
+
 	// Settings captures additional information sent in call to the RuntimeExtensions.
 	Settings map[string]string
 }
@@ -258,6 +261,7 @@ func (r *extensionRegistry) add(extensionConfig *runtimev1.ExtensionConfig) erro
 			ClientConfig:      extensionConfig.Spec.ClientConfig,
 			TimeoutSeconds:    e.TimeoutSeconds,
 			FailurePolicy:     e.FailurePolicy,
+			RetryPolicy:       e.RetryPolicy,
 			Settings:          extensionConfig.Spec.Settings,
 		})
 	}
@@ -257,6 +257,7 @@ type ClusterClassBuilder struct {
 	variables                                 []clusterv1.ClusterClassVariable
 	statusVariables                           []clusterv1.ClusterClassStatusVariable
 	patches                                   []clusterv1.ClusterClassPatch
+	lineage                                   *clusterv1.ClusterClassLineage
 }
 
 // ClusterClass returns a ClusterClassBuilder with the given name and namespace.
@@ -336,6 +337,12 @@ func (c *ClusterClassBuilder) WithPatches(patches []clusterv1.ClusterClassPatch)
 	return c
 }
 
+// WithLineage sets the lineage of the ClusterClassBuilder.
+func (c *ClusterClassBuilder) WithLineage(name string, revision int32) *ClusterClassBuilder {
+	c.lineage = &clusterv1.ClusterClassLineage{Name: name, Revision: revision}
+	return c
+}
+
 // WithWorkerMachineDeploymentClasses adds the variables and objects needed to create MachineDeploymentTemplates for a ClusterClassBuilder.
 func (c *ClusterClassBuilder) WithWorkerMachineDeploymentClasses(mdcs ...clusterv1.MachineDeploymentClass) *ClusterClassBuilder {
 	if c.machineDeploymentClasses == nil {
@@ -359,6 +366,7 @@ func (c *ClusterClassBuilder) Build() *clusterv1.ClusterClass {
 		Spec: clusterv1.ClusterClassSpec{
 			Variables: c.variables,
 			Patches:   c.patches,
+			Lineage:   c.lineage,
 		},
 		Status: clusterv1.ClusterClassStatus{
 			Variables: c.statusVariables,
@@ -1488,7 +1496,6 @@ type MachineHealthCheckBuilder struct {
 	clusterName  string
 	conditions   []clusterv1.UnhealthyCondition
 	maxUnhealthy *intstr.IntOrString
-	defaulter    bool
 }
 
 // MachineHealthCheck returns a MachineHealthCheckBuilder with the given name and namespace.
@@ -1529,12 +1536,6 @@ func (m *MachineHealthCheckBuilder) WithMaxUnhealthy(maxUnhealthy *intstr.IntOrS
 	return m
 }
 
-// WithDefaulter runs the Default function on the MachineHealthCheck object.
-func (m *MachineHealthCheckBuilder) WithDefaulter(defaulter bool) *MachineHealthCheckBuilder {
-	m.defaulter = defaulter
-	return m
-}
-
 // Build returns a MachineHealthCheck with the supplied details.
 func (m *MachineHealthCheckBuilder) Build() *clusterv1.MachineHealthCheck {
 	// create a MachineHealthCheck with the spec given in the ClusterClass
@@ -1558,8 +1559,5 @@ func (m *MachineHealthCheckBuilder) Build() *clusterv1.MachineHealthCheck {
 	if m.clusterName != "" {
 		mhc.Labels = map[string]string{clusterv1.ClusterNameLabel: m.clusterName}
 	}
-	if m.defaulter {
-		mhc.Default()
-	}
 	return mhc
 }
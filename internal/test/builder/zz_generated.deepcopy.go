@@ -162,6 +162,11 @@ func (in *ClusterClassBuilder) DeepCopyInto(out *ClusterClassBuilder) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.lineage != nil {
+		in, out := &in.lineage, &out.lineage
+		*out = new(v1beta1.ClusterClassLineage)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClassBuilder.
@@ -221,6 +221,48 @@ func ClusterClassesAreCompatible(current, desired *clusterv1.ClusterClass) field
 	return allErrs
 }
 
+// ClusterClassLineageIsImmutable checks that the lineage of a ClusterClass is not changed or removed once set,
+// because the revision identifies the content of the ClusterClass in the lineage.
+// NOTE: Setting the lineage on an existing ClusterClass is allowed, so it can be used as the first revision.
+func ClusterClassLineageIsImmutable(current, desired *clusterv1.ClusterClass) field.ErrorList {
+	if current == nil || current.Spec.Lineage == nil {
+		return nil
+	}
+
+	if desired.Spec.Lineage == nil || *desired.Spec.Lineage != *current.Spec.Lineage {
+		return field.ErrorList{
+			field.Forbidden(
+				field.NewPath("spec", "lineage"),
+				"lineage cannot be changed or removed once set; create a new ClusterClass with a new revision instead",
+			),
+		}
+	}
+	return nil
+}
+
+// ClusterClassRevisionsAreCompatible checks if a Cluster can be rebased from the current to the desired ClusterClass
+// based on their lineage; rebasing to an older or to the same revision of the same lineage is not allowed.
+// NOTE: Rebasing between ClusterClasses without a lineage or with a different lineage is not affected by this check.
+func ClusterClassRevisionsAreCompatible(current, desired *clusterv1.ClusterClass) field.ErrorList {
+	if current == nil || current.Spec.Lineage == nil || desired.Spec.Lineage == nil {
+		return nil
+	}
+	if current.Spec.Lineage.Name != desired.Spec.Lineage.Name {
+		return nil
+	}
+
+	if desired.Spec.Lineage.Revision <= current.Spec.Lineage.Revision {
+		return field.ErrorList{
+			field.Forbidden(
+				field.NewPath("spec", "topology", "class"),
+				fmt.Sprintf("cannot rebase from revision %d of ClusterClass lineage %q to revision %d; only newer revisions are allowed unless the %s annotation is set on the Cluster",
+					current.Spec.Lineage.Revision, current.Spec.Lineage.Name, desired.Spec.Lineage.Revision, clusterv1.ClusterTopologyUnsafeAllowClassDowngradeAnnotation),
+			),
+		}
+	}
+	return nil
+}
+
 // MachineDeploymentClassesAreCompatible checks if each MachineDeploymentClass in the new ClusterClass is a compatible change from the previous ClusterClass.
 // It checks if:
 // 1) Any MachineDeploymentClass has been removed.
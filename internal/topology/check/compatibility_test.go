@@ -17,6 +17,7 @@ limitations under the License.
 package check
 
 import (
+	"fmt"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -575,6 +576,126 @@ func TestClusterClassesAreCompatible(t *testing.T) {
 	}
 }
 
+func TestClusterClassLineageIsImmutable(t *testing.T) {
+	withLineage := func(name string, revision int32) *clusterv1.ClusterClass {
+		clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+		if name != "" {
+			clusterClass.Spec.Lineage = &clusterv1.ClusterClassLineage{Name: name, Revision: revision}
+		}
+		return clusterClass
+	}
+
+	tests := []struct {
+		name    string
+		current *clusterv1.ClusterClass
+		desired *clusterv1.ClusterClass
+		wantErr bool
+	}{
+		{
+			name:    "pass for a new ClusterClass",
+			current: nil,
+			desired: withLineage("lineage", 1),
+			wantErr: false,
+		},
+		{
+			name:    "pass if the lineage is set on an existing ClusterClass",
+			current: withLineage("", 0),
+			desired: withLineage("lineage", 1),
+			wantErr: false,
+		},
+		{
+			name:    "pass if the lineage is not changed",
+			current: withLineage("lineage", 1),
+			desired: withLineage("lineage", 1),
+			wantErr: false,
+		},
+		{
+			name:    "error if the lineage is removed",
+			current: withLineage("lineage", 1),
+			desired: withLineage("", 0),
+			wantErr: true,
+		},
+		{
+			name:    "error if the revision is changed",
+			current: withLineage("lineage", 1),
+			desired: withLineage("lineage", 2),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			allErrs := ClusterClassLineageIsImmutable(tt.current, tt.desired)
+			if tt.wantErr {
+				g.Expect(allErrs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(allErrs).To(BeEmpty())
+		})
+	}
+}
+
+func TestClusterClassRevisionsAreCompatible(t *testing.T) {
+	withLineage := func(name string, revision int32) *clusterv1.ClusterClass {
+		clusterClass := builder.ClusterClass(metav1.NamespaceDefault, fmt.Sprintf("%s-v%d", name, revision)).Build()
+		if name != "" {
+			clusterClass.Spec.Lineage = &clusterv1.ClusterClassLineage{Name: name, Revision: revision}
+		}
+		return clusterClass
+	}
+
+	tests := []struct {
+		name    string
+		current *clusterv1.ClusterClass
+		desired *clusterv1.ClusterClass
+		wantErr bool
+	}{
+		{
+			name:    "pass when rebasing to a newer revision",
+			current: withLineage("lineage", 1),
+			desired: withLineage("lineage", 2),
+			wantErr: false,
+		},
+		{
+			name:    "pass when rebasing to a ClusterClass without a lineage",
+			current: withLineage("lineage", 2),
+			desired: withLineage("", 0),
+			wantErr: false,
+		},
+		{
+			name:    "pass when rebasing to a ClusterClass of another lineage",
+			current: withLineage("lineage", 2),
+			desired: withLineage("another-lineage", 1),
+			wantErr: false,
+		},
+		{
+			name:    "error when rebasing to an older revision",
+			current: withLineage("lineage", 2),
+			desired: withLineage("lineage", 1),
+			wantErr: true,
+		},
+		{
+			name:    "error when rebasing to the same revision",
+			current: withLineage("lineage", 2),
+			desired: withLineage("lineage", 2),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			allErrs := ClusterClassRevisionsAreCompatible(tt.current, tt.desired)
+			if tt.wantErr {
+				g.Expect(allErrs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(allErrs).To(BeEmpty())
+		})
+	}
+}
+
 func TestMachineDeploymentClassesAreCompatible(t *testing.T) {
 	ref := &corev1.ObjectReference{
 		APIVersion: "group.test.io/foo",
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package autoscaler implements helper functions for pausing the Kubernetes cluster autoscaler on scalable resources.
+package autoscaler
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// ReconcilePause excludes a MachineDeployment or a MachinePool from autoscaling while autoscaling is paused, and
+// includes it again once autoscaling is resumed; expired autoscaler pauses are removed from the object.
+// If autoscaling is paused with an expiry, the returned duration is the time until the earliest expiry, so callers
+// can requeue and resume autoscaling when the pause ends.
+// NOTE: This func is expected to be called also when the object or the Cluster are paused, so the autoscaler does
+// not scale objects that Cluster API controllers are not reconciling.
+// NOTE: For MachineDeployments with autoscaler annotations set in the Cluster topology, the topology controller
+// applies the autoscaler-paused annotations instead while autoscaling is paused, see ComputeAnnotations.
+func ReconcilePause(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, obj client.Object) (time.Duration, error) {
+	patchHelper, err := patch.NewHelper(obj, c)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	removeExpiredPause(obj, now)
+
+	isPaused, requeueAfter := IsPaused(cluster, obj, now)
+	if isPaused {
+		Pause(obj)
+	} else {
+		Resume(obj)
+	}
+
+	if err := patchHelper.Patch(ctx, obj); err != nil {
+		return requeueAfter, errors.Wrapf(err, "failed to patch %s", obj.GetName())
+	}
+	return requeueAfter, nil
+}
+
+// IsPaused returns true if autoscaling is paused for the object, i.e. if the object has the autoscaler-paused
+// annotation or if the object or the Cluster are paused.
+// If autoscaling is paused with an expiry, the returned duration is the time until the earliest expiry.
+func IsPaused(cluster *clusterv1.Cluster, obj metav1.Object, now time.Time) (bool, time.Duration) {
+	isPaused := false
+	var requeueAfter time.Duration
+	addExpiry := func(expiresAt time.Time) {
+		if d := expiresAt.Sub(now); requeueAfter == 0 || d < requeueAfter {
+			requeueAfter = d
+		}
+	}
+
+	if cluster.Spec.Paused {
+		isPaused = true
+	} else if cluster.Spec.Pause.IsActive(now) {
+		isPaused = true
+		if cluster.Spec.Pause.ExpiresAt != nil {
+			addExpiry(cluster.Spec.Pause.ExpiresAt.Time)
+		}
+	}
+
+	if annotations.HasPaused(obj) {
+		isPaused = true
+		if until, ok := annotations.PausedUntil(obj); ok {
+			addExpiry(until)
+		}
+	}
+
+	if _, ok := obj.GetAnnotations()[clusterv1.AutoscalerPausedAnnotation]; ok {
+		until, hasExpiry := pausedUntil(obj)
+		if !hasExpiry || now.Before(until) {
+			isPaused = true
+			if hasExpiry {
+				addExpiry(until)
+			}
+		}
+	}
+
+	return isPaused, requeueAfter
+}
+
+// ComputeAnnotations returns the annotations to be applied by the topology controller to a MachineDeployment, given
+// the annotations from the Cluster topology and the current MachineDeployment.
+// While autoscaling of the current MachineDeployment is paused, the autoscaler min and max size annotations are
+// returned as the autoscaler-paused ones, so the topology controller does not include the MachineDeployment in
+// autoscaling again, and the values from the Cluster topology are applied as soon as autoscaling is resumed.
+func ComputeAnnotations(cluster *clusterv1.Cluster, current metav1.Object, desiredAnnotations map[string]string) map[string]string {
+	if isPaused, _ := IsPaused(cluster, current, time.Now()); !isPaused {
+		return desiredAnnotations
+	}
+
+	// NOTE: Pause modifies the annotations in place, so it is called on a copy of them.
+	obj := &metav1.ObjectMeta{Annotations: map[string]string{}}
+	for k, v := range desiredAnnotations {
+		obj.Annotations[k] = v
+	}
+	if !Pause(obj) {
+		return desiredAnnotations
+	}
+	return obj.Annotations
+}
+
+// Pause excludes the object from autoscaling by moving the autoscaler min and max size annotations to the
+// autoscaler-paused ones, so the cluster autoscaler does not consider the object as a node group.
+// NOTE: If the autoscaler annotations are set again while autoscaling is paused, e.g. by a GitOps tool,
+// they are moved again and the latest values are preserved.
+// It returns true if the annotations are modified, false otherwise.
+func Pause(obj metav1.Object) bool {
+	objAnnotations := obj.GetAnnotations()
+	modified := false
+	for from, to := range map[string]string{
+		clusterv1.AutoscalerMinSizeAnnotation: clusterv1.AutoscalerPausedMinSizeAnnotation,
+		clusterv1.AutoscalerMaxSizeAnnotation: clusterv1.AutoscalerPausedMaxSizeAnnotation,
+	} {
+		if value, ok := objAnnotations[from]; ok {
+			objAnnotations[to] = value
+			delete(objAnnotations, from)
+			modified = true
+		}
+	}
+	if modified {
+		obj.SetAnnotations(objAnnotations)
+	}
+	return modified
+}
+
+// Resume includes the object in autoscaling again by restoring the autoscaler min and max size annotations
+// preserved by Pause.
+// NOTE: Autoscaler annotations set while autoscaling was paused take precedence over the preserved values.
+// It returns true if the annotations are modified, false otherwise.
+func Resume(obj metav1.Object) bool {
+	objAnnotations := obj.GetAnnotations()
+	modified := false
+	for from, to := range map[string]string{
+		clusterv1.AutoscalerPausedMinSizeAnnotation: clusterv1.AutoscalerMinSizeAnnotation,
+		clusterv1.AutoscalerPausedMaxSizeAnnotation: clusterv1.AutoscalerMaxSizeAnnotation,
+	} {
+		if value, ok := objAnnotations[from]; ok {
+			if _, ok := objAnnotations[to]; !ok {
+				objAnnotations[to] = value
+			}
+			delete(objAnnotations, from)
+			modified = true
+		}
+	}
+	if modified {
+		obj.SetAnnotations(objAnnotations)
+	}
+	return modified
+}
+
+// pausedUntil returns the expiry of the autoscaler pause set with the autoscaler-paused-until annotation, if any.
+// NOTE: Values not in RFC3339 format are ignored, and the pause never expires.
+func pausedUntil(obj metav1.Object) (time.Time, bool) {
+	value, ok := obj.GetAnnotations()[clusterv1.AutoscalerPausedUntilAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// removeExpiredPause removes the autoscaler-paused annotations from the object if the pause has expired.
+func removeExpiredPause(obj metav1.Object, now time.Time) {
+	if until, ok := pausedUntil(obj); ok && !now.Before(until) {
+		objAnnotations := obj.GetAnnotations()
+		delete(objAnnotations, clusterv1.AutoscalerPausedAnnotation)
+		delete(objAnnotations, clusterv1.AutoscalerPausedUntilAnnotation)
+		obj.SetAnnotations(objAnnotations)
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestIsPaused(t *testing.T) {
+	now := time.Now()
+	future := now.Add(time.Hour).UTC().Format(time.RFC3339)
+	past := now.Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name             string
+		cluster          *clusterv1.Cluster
+		annotations      map[string]string
+		wantPaused       bool
+		wantRequeueAfter bool
+	}{
+		{
+			name:       "not paused",
+			cluster:    &clusterv1.Cluster{},
+			wantPaused: false,
+		},
+		{
+			name:       "paused if the Cluster is paused",
+			cluster:    &clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Paused: true}},
+			wantPaused: true,
+		},
+		{
+			name: "paused with expiry if the Cluster is paused until a time in the future",
+			cluster: &clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Pause: &clusterv1.ClusterPause{
+				ExpiresAt: &metav1.Time{Time: now.Add(time.Hour)},
+			}}},
+			wantPaused:       true,
+			wantRequeueAfter: true,
+		},
+		{
+			name:        "paused if the object is paused",
+			cluster:     &clusterv1.Cluster{},
+			annotations: map[string]string{clusterv1.PausedAnnotation: ""},
+			wantPaused:  true,
+		},
+		{
+			name:        "paused if autoscaling is paused",
+			cluster:     &clusterv1.Cluster{},
+			annotations: map[string]string{clusterv1.AutoscalerPausedAnnotation: ""},
+			wantPaused:  true,
+		},
+		{
+			name:    "paused with expiry if autoscaling is paused until a time in the future",
+			cluster: &clusterv1.Cluster{},
+			annotations: map[string]string{
+				clusterv1.AutoscalerPausedAnnotation:      "",
+				clusterv1.AutoscalerPausedUntilAnnotation: future,
+			},
+			wantPaused:       true,
+			wantRequeueAfter: true,
+		},
+		{
+			name:    "not paused if autoscaling pause is expired",
+			cluster: &clusterv1.Cluster{},
+			annotations: map[string]string{
+				clusterv1.AutoscalerPausedAnnotation:      "",
+				clusterv1.AutoscalerPausedUntilAnnotation: past,
+			},
+			wantPaused: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			md := &clusterv1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{Name: "md", Annotations: tt.annotations}}
+			isPaused, requeueAfter := IsPaused(tt.cluster, md, now)
+			g.Expect(isPaused).To(Equal(tt.wantPaused))
+			g.Expect(requeueAfter > 0).To(Equal(tt.wantRequeueAfter))
+		})
+	}
+}
+
+func TestPauseAndResume(t *testing.T) {
+	g := NewWithT(t)
+
+	md := &clusterv1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{
+		Name: "md",
+		Annotations: map[string]string{
+			clusterv1.AutoscalerMinSizeAnnotation: "1",
+			clusterv1.AutoscalerMaxSizeAnnotation: "5",
+		},
+	}}
+
+	g.Expect(Pause(md)).To(BeTrue())
+	g.Expect(md.Annotations).To(Equal(map[string]string{
+		clusterv1.AutoscalerPausedMinSizeAnnotation: "1",
+		clusterv1.AutoscalerPausedMaxSizeAnnotation: "5",
+	}))
+	g.Expect(Pause(md)).To(BeFalse())
+
+	// Autoscaler annotations set while autoscaling is paused take precedence over the preserved values.
+	md.Annotations[clusterv1.AutoscalerMaxSizeAnnotation] = "10"
+
+	g.Expect(Resume(md)).To(BeTrue())
+	g.Expect(md.Annotations).To(Equal(map[string]string{
+		clusterv1.AutoscalerMinSizeAnnotation: "1",
+		clusterv1.AutoscalerMaxSizeAnnotation: "10",
+	}))
+	g.Expect(Resume(md)).To(BeFalse())
+}
+
+func TestReconcilePause(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	md := &clusterv1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{
+		Namespace: metav1.NamespaceDefault,
+		Name:      "md",
+		Annotations: map[string]string{
+			clusterv1.AutoscalerPausedAnnotation:        "",
+			clusterv1.AutoscalerPausedUntilAnnotation:   time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+			clusterv1.AutoscalerPausedMinSizeAnnotation: "1",
+			clusterv1.AutoscalerPausedMaxSizeAnnotation: "5",
+		},
+	}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(md).Build()
+
+	// The expired autoscaler pause is removed and the autoscaler annotations are restored.
+	requeueAfter, err := ReconcilePause(context.Background(), c, &clusterv1.Cluster{}, md)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(requeueAfter).To(BeZero())
+
+	got := &clusterv1.MachineDeployment{}
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(md), got)).To(Succeed())
+	g.Expect(got.Annotations).To(Equal(map[string]string{
+		clusterv1.AutoscalerMinSizeAnnotation: "1",
+		clusterv1.AutoscalerMaxSizeAnnotation: "5",
+	}))
+
+	// Autoscaling is paused while the Cluster is paused.
+	pausedCluster := &clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Paused: true}}
+	_, err = ReconcilePause(context.Background(), c, pausedCluster, got)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(md), got)).To(Succeed())
+	g.Expect(got.Annotations).To(Equal(map[string]string{
+		clusterv1.AutoscalerPausedMinSizeAnnotation: "1",
+		clusterv1.AutoscalerPausedMaxSizeAnnotation: "5",
+	}))
+
+	// Autoscaling is resumed once the Cluster is no longer paused.
+	_, err = ReconcilePause(context.Background(), c, &clusterv1.Cluster{}, got)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(md), got)).To(Succeed())
+	g.Expect(got.Annotations).To(Equal(map[string]string{
+		clusterv1.AutoscalerMinSizeAnnotation: "1",
+		clusterv1.AutoscalerMaxSizeAnnotation: "5",
+	}))
+}
+
+func TestComputeAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	desiredAnnotations := map[string]string{
+		"foo":                                 "bar",
+		clusterv1.AutoscalerMinSizeAnnotation: "1",
+		clusterv1.AutoscalerMaxSizeAnnotation: "5",
+	}
+	md := &clusterv1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{Name: "md"}}
+
+	// The annotations are applied as is while autoscaling is not paused.
+	g.Expect(ComputeAnnotations(&clusterv1.Cluster{}, md, desiredAnnotations)).To(Equal(desiredAnnotations))
+
+	// The autoscaler annotations are applied as the autoscaler-paused ones while autoscaling is paused,
+	// without modifying the annotations from the Cluster topology.
+	md.Annotations = map[string]string{clusterv1.AutoscalerPausedAnnotation: ""}
+	g.Expect(ComputeAnnotations(&clusterv1.Cluster{}, md, desiredAnnotations)).To(Equal(map[string]string{
+		"foo": "bar",
+		clusterv1.AutoscalerPausedMinSizeAnnotation: "1",
+		clusterv1.AutoscalerPausedMaxSizeAnnotation: "5",
+	}))
+	g.Expect(desiredAnnotations).To(HaveKey(clusterv1.AutoscalerMinSizeAnnotation))
+}
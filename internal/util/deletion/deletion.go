@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package deletion implements helper functions for recording why objects are deleted.
+package deletion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// Initiator returns the value of the DeletionInitiatorAnnotation for the given object, i.e. Kind/name.
+func Initiator(kind string, obj metav1.Object) string {
+	return fmt.Sprintf("%s/%s", kind, obj.GetName())
+}
+
+// Get returns the deletion reason and the deletion initiator recorded on an object, if any.
+func Get(obj metav1.Object) (reason, initiator string) {
+	return obj.GetAnnotations()[clusterv1.DeletionReasonAnnotation], obj.GetAnnotations()[clusterv1.DeletionInitiatorAnnotation]
+}
+
+// Set records the deletion reason and the deletion initiator on an object.
+// It returns true if the annotations are modified, false otherwise.
+func Set(obj metav1.Object, reason, initiator string) bool {
+	if currentReason, currentInitiator := Get(obj); currentReason == reason && currentInitiator == initiator {
+		return false
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[clusterv1.DeletionReasonAnnotation] = reason
+	annotations[clusterv1.DeletionInitiatorAnnotation] = initiator
+	obj.SetAnnotations(annotations)
+	return true
+}
+
+// Remove removes the deletion reason and the deletion initiator from an object.
+// It returns true if the annotations are modified, false otherwise.
+func Remove(obj metav1.Object) bool {
+	annotations := obj.GetAnnotations()
+	_, hasReason := annotations[clusterv1.DeletionReasonAnnotation]
+	_, hasInitiator := annotations[clusterv1.DeletionInitiatorAnnotation]
+	if !hasReason && !hasInitiator {
+		return false
+	}
+	delete(annotations, clusterv1.DeletionReasonAnnotation)
+	delete(annotations, clusterv1.DeletionInitiatorAnnotation)
+	obj.SetAnnotations(annotations)
+	return true
+}
+
+// Delete records the deletion reason and the deletion initiator on an object, then deletes it
+// and emits an event on the object documenting who initiated the deletion and why.
+// Errors are returned unwrapped, so callers can check for NotFound errors.
+func Delete(ctx context.Context, c client.Client, recorder record.EventRecorder, obj client.Object, reason, initiator string) error {
+	original, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return errors.Errorf("failed to copy %T", obj)
+	}
+	if Set(obj, reason, initiator) {
+		if err := c.Patch(ctx, obj, client.MergeFrom(original)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Delete(ctx, obj); err != nil {
+		return err
+	}
+
+	recorder.Eventf(obj, corev1.EventTypeNormal, "DeletionRequested", "Deletion requested by %s, reason: %s", initiator, reason)
+	return nil
+}
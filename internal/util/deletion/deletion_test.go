@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestSetAndRemove(t *testing.T) {
+	g := NewWithT(t)
+
+	machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine"}}
+
+	g.Expect(Set(machine, clusterv1.DeletionReasonScaleDown, "MachineSet/ms")).To(BeTrue())
+	reason, initiator := Get(machine)
+	g.Expect(reason).To(Equal(clusterv1.DeletionReasonScaleDown))
+	g.Expect(initiator).To(Equal("MachineSet/ms"))
+
+	// Setting the same values again is a no-op.
+	g.Expect(Set(machine, clusterv1.DeletionReasonScaleDown, "MachineSet/ms")).To(BeFalse())
+	g.Expect(Set(machine, clusterv1.DeletionReasonRollout, "MachineSet/ms")).To(BeTrue())
+
+	g.Expect(Remove(machine)).To(BeTrue())
+	g.Expect(machine.Annotations).To(BeEmpty())
+	g.Expect(Remove(machine)).To(BeFalse())
+}
+
+func TestDelete(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	machineSet := &clusterv1.MachineSet{ObjectMeta: metav1.ObjectMeta{Name: "ms", Namespace: metav1.NamespaceDefault}}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "machine",
+			Namespace:  metav1.NamespaceDefault,
+			Finalizers: []string{clusterv1.MachineFinalizer},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machine).Build()
+	recorder := record.NewFakeRecorder(1)
+
+	g.Expect(Delete(context.Background(), c, recorder, machine, clusterv1.DeletionReasonRemediation, Initiator("MachineSet", machineSet))).To(Succeed())
+
+	got := &clusterv1.Machine{}
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(machine), got)).To(Succeed())
+	g.Expect(got.DeletionTimestamp.IsZero()).To(BeFalse())
+	g.Expect(got.Annotations).To(HaveKeyWithValue(clusterv1.DeletionReasonAnnotation, clusterv1.DeletionReasonRemediation))
+	g.Expect(got.Annotations).To(HaveKeyWithValue(clusterv1.DeletionInitiatorAnnotation, "MachineSet/ms"))
+	g.Expect(<-recorder.Events).To(Equal("Normal DeletionRequested Deletion requested by MachineSet/ms, reason: Remediation"))
+}
@@ -18,10 +18,15 @@ limitations under the License.
 package hash
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"hash/fnv"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
 )
 
 // Compute computes the hash of an object using the spew library.
@@ -43,3 +48,30 @@ func Compute(obj interface{}) (uint32, error) {
 
 	return hasher.Sum32(), nil
 }
+
+// ComputeChecksum computes a checksum of the JSON serialization of an object; given that json.Marshal sorts
+// map keys, the checksum does not depend on the order of the fields of the object.
+func ComputeChecksum(obj interface{}) (string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal object")
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// ComputeTemplatesChecksum returns a checksum of the content of the given templates, i.e. their kind and their
+// spec.template; the checksum does not depend on the names of the templates nor on the order of the fields.
+func ComputeTemplatesChecksum(templates ...*unstructured.Unstructured) (string, error) {
+	contents := make([]interface{}, 0, len(templates))
+	for _, template := range templates {
+		spec, _, err := unstructured.NestedFieldNoCopy(template.Object, "spec", "template")
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get spec.template from %s %s", template.GetKind(), klog.KObj(template))
+		}
+		contents = append(contents, map[string]interface{}{
+			"groupKind": template.GroupVersionKind().GroupKind().String(),
+			"template":  spec,
+		})
+	}
+	return ComputeChecksum(contents)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hash
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestComputeChecksum(t *testing.T) {
+	g := NewWithT(t)
+
+	checksum, err := ComputeChecksum(map[string]interface{}{"a": "1", "b": "2"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(checksum).ToNot(BeEmpty())
+
+	// The checksum does not depend on the order of the fields.
+	reordered, err := ComputeChecksum(map[string]interface{}{"b": "2", "a": "1"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(reordered).To(Equal(checksum))
+
+	// The checksum changes if the content changes.
+	changed, err := ComputeChecksum(map[string]interface{}{"a": "1", "b": "3"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changed).ToNot(Equal(checksum))
+}
+
+func TestComputeTemplatesChecksum(t *testing.T) {
+	g := NewWithT(t)
+
+	newTemplate := func(name string, spec map[string]interface{}) *unstructured.Unstructured {
+		template := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": spec}},
+		}}
+		template.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta1")
+		template.SetKind("GenericInfrastructureMachineTemplate")
+		template.SetName(name)
+		return template
+	}
+
+	checksum, err := ComputeTemplatesChecksum(newTemplate("template-1", map[string]interface{}{"a": "1", "b": "2"}))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(checksum).ToNot(BeEmpty())
+
+	// The checksum does not depend on the name of the template.
+	recreated, err := ComputeTemplatesChecksum(newTemplate("template-2", map[string]interface{}{"b": "2", "a": "1"}))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(recreated).To(Equal(checksum))
+
+	// The checksum changes if the content changes.
+	changed, err := ComputeTemplatesChecksum(newTemplate("template-1", map[string]interface{}{"a": "1", "b": "3"}))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changed).ToNot(Equal(checksum))
+
+	// The checksum changes if the kind changes.
+	otherKind := newTemplate("template-1", map[string]interface{}{"a": "1", "b": "2"})
+	otherKind.SetKind("AnotherInfrastructureMachineTemplate")
+	changed, err = ComputeTemplatesChecksum(otherKind)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changed).ToNot(Equal(checksum))
+
+	// The checksum covers all the templates.
+	changed, err = ComputeTemplatesChecksum(newTemplate("template-1", map[string]interface{}{"a": "1", "b": "2"}), newTemplate("bootstrap", nil))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changed).ToNot(Equal(checksum))
+}
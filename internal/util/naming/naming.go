@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package naming implements the generation of object names from a naming strategy template.
+package naming
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// randomLength is the length of the random string available in templates.
+	randomLength = 5
+
+	// maxNameLength is the maximum length of the generated names; names are
+	// commonly used as hostnames, so they must fit in a DNS label.
+	maxNameLength = 63
+
+	// randomVariable is the template variable returning a random string.
+	randomVariable = ".random"
+)
+
+// Values are the values available when rendering a naming strategy template.
+type Values struct {
+	// Cluster is the name of the Cluster.
+	Cluster string
+
+	// MachineDeployment is the name of the MachineDeployment, if any.
+	MachineDeployment string
+
+	// MachineSet is the name of the MachineSet, if any.
+	MachineSet string
+}
+
+// Generate renders the given naming strategy template and returns the resulting name.
+// An error is returned if the template is not valid or if the generated name is not
+// a valid DNS subdomain with at most 63 characters.
+func Generate(tpl string, values Values) (string, error) {
+	if !strings.Contains(tpl, randomVariable) {
+		return "", errors.Errorf("template %q must reference %s to guarantee unique names", tpl, randomVariable)
+	}
+
+	t, err := template.New("name").Option("missingkey=error").Parse(tpl)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse template %q", tpl)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, map[string]interface{}{
+		"cluster":           values.Cluster,
+		"machineDeployment": values.MachineDeployment,
+		"machineSet":        values.MachineSet,
+		"random":            utilrand.String(randomLength),
+	}); err != nil {
+		return "", errors.Wrapf(err, "failed to render template %q", tpl)
+	}
+
+	name := buf.String()
+	if len(name) > maxNameLength {
+		return "", errors.Errorf("generated name %q must be no more than %d characters", name, maxNameLength)
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", errors.Errorf("generated name %q is not valid: %s", name, strings.Join(errs, "; "))
+	}
+	return name, nil
+}
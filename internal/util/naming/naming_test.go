@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGenerate(t *testing.T) {
+	values := Values{
+		Cluster:           "cluster1",
+		MachineDeployment: "md1",
+		MachineSet:        "ms1",
+	}
+
+	tests := []struct {
+		name       string
+		template   string
+		wantPrefix string
+		wantErr    bool
+	}{
+		{
+			name:       "renders all the variables",
+			template:   "{{ .cluster }}-{{ .machineDeployment }}-{{ .machineSet }}-{{ .random }}",
+			wantPrefix: "cluster1-md1-ms1-",
+		},
+		{
+			name:       "renders constant strings",
+			template:   "corp-{{ .cluster }}-{{ .random }}",
+			wantPrefix: "corp-cluster1-",
+		},
+		{
+			name:     "fails if the template does not reference random",
+			template: "{{ .cluster }}-{{ .machineDeployment }}",
+			wantErr:  true,
+		},
+		{
+			name:     "fails if the template cannot be parsed",
+			template: "{{ .cluster }-{{ .random }}",
+			wantErr:  true,
+		},
+		{
+			name:     "fails if the template references unknown variables",
+			template: "{{ .unknown }}-{{ .random }}",
+			wantErr:  true,
+		},
+		{
+			name:     "fails if the generated name is not a valid DNS subdomain",
+			template: "{{ .cluster }}_{{ .random }}",
+			wantErr:  true,
+		},
+		{
+			name:     "fails if the generated name is too long",
+			template: strings.Repeat("a", 60) + "-{{ .random }}",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			name, err := Generate(tt.template, values)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(name).To(HavePrefix(tt.wantPrefix))
+			g.Expect(name).To(HaveLen(len(tt.wantPrefix) + randomLength))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodeexpression implements the compilation and the evaluation of CEL expressions over Nodes,
+// as used by MachineHealthChecks to determine if a Node is unhealthy.
+package nodeexpression
+
+import (
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// NodeVariable is the name of the variable exposing the Node, e.g. node.status.nodeInfo.kubeletVersion.
+	NodeVariable = "node"
+
+	// NowVariable is the name of the variable exposing the evaluation time as a timestamp, e.g. to compare
+	// it with the lastTransitionTime of a Node condition.
+	NowVariable = "now"
+
+	// costLimit is the maximum cost of evaluating an expression; it prevents expensive expressions to block
+	// the MachineHealthCheck controller.
+	costLimit = 1000000
+)
+
+// Expression is a compiled CEL expression over a Node.
+type Expression struct {
+	program cel.Program
+}
+
+// Compile compiles a CEL expression over a Node; the expression must evaluate to a bool.
+//
+// The following variables are available to expressions:
+// - node: the Node object, e.g. node.status.allocatable['ephemeral-storage'].
+// - now: the evaluation time, e.g. now - timestamp(c.lastTransitionTime) > duration('5m').
+// The following functions are available to expressions, in addition to the CEL standard library:
+// - quantity(string) double: parses a resource quantity, e.g. quantity(node.status.allocatable['memory']) < quantity('1Gi').
+func Compile(expression string) (*Expression, error) {
+	env, err := cel.NewEnv(
+		cel.Variable(NodeVariable, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(NowVariable, cel.TimestampType),
+		cel.Function("quantity",
+			cel.Overload("quantity_string", []*cel.Type{cel.StringType}, cel.DoubleType,
+				cel.UnaryBinding(parseQuantity),
+			),
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CEL environment")
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Wrapf(issues.Err(), "failed to compile expression %q", expression)
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, errors.Errorf("expression %q must evaluate to a bool, got %s", expression, ast.OutputType())
+	}
+
+	program, err := env.Program(ast, cel.CostLimit(costLimit))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compile expression %q", expression)
+	}
+	return &Expression{program: program}, nil
+}
+
+// Eval evaluates the expression against a Node at the given time.
+func (e *Expression) Eval(node *corev1.Node, now time.Time) (bool, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(node)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to convert Node %s", node.Name)
+	}
+
+	out, _, err := e.program.Eval(map[string]interface{}{
+		NodeVariable: content,
+		NowVariable:  now,
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to evaluate expression on Node %s", node.Name)
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, errors.Errorf("expression evaluated to %v on Node %s, expected a bool", out.Value(), node.Name)
+	}
+	return result, nil
+}
+
+// parseQuantity implements the quantity function.
+func parseQuantity(arg ref.Val) ref.Val {
+	s, ok := arg.Value().(string)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(arg)
+	}
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return types.NewErr("invalid quantity %q: %v", s, err)
+	}
+	return types.Double(q.AsApproximateFloat64())
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeexpression
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{
+			name:       "valid expression",
+			expression: "node.status.nodeInfo.kubeletVersion != 'v1.27.1'",
+		},
+		{
+			name:       "valid expression using quantity",
+			expression: "quantity(node.status.allocatable['ephemeral-storage']) < quantity('10Gi')",
+		},
+		{
+			name:       "invalid syntax",
+			expression: "node.status.(",
+			wantErr:    true,
+		},
+		{
+			name:       "undeclared variable",
+			expression: "machine.spec.version == 'v1.27.1'",
+			wantErr:    true,
+		},
+		{
+			name:       "expression not evaluating to a bool",
+			expression: "'foo'",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := Compile(tt.expression)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	now := time.Now()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-1",
+			Labels: map[string]string{"role": "worker"},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse("5Gi"),
+			},
+			NodeInfo: corev1.NodeSystemInfo{
+				KubeletVersion: "v1.27.1",
+			},
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:               "KernelDeadlock",
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(now.Add(-10 * time.Minute)),
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       bool
+		wantErr    bool
+	}{
+		{
+			name:       "kubelet version mismatch",
+			expression: "node.status.nodeInfo.kubeletVersion != 'v1.27.1'",
+			want:       false,
+		},
+		{
+			name:       "low ephemeral storage",
+			expression: "quantity(node.status.allocatable['ephemeral-storage']) < quantity('10Gi')",
+			want:       true,
+		},
+		{
+			name:       "custom condition true for more than 5 minutes",
+			expression: "node.status.conditions.exists(c, c.type == 'KernelDeadlock' && c.status == 'True' && now - timestamp(c.lastTransitionTime) > duration('5m'))",
+			want:       true,
+		},
+		{
+			name:       "custom condition true for more than 15 minutes",
+			expression: "node.status.conditions.exists(c, c.type == 'KernelDeadlock' && c.status == 'True' && now - timestamp(c.lastTransitionTime) > duration('15m'))",
+			want:       false,
+		},
+		{
+			name:       "labels",
+			expression: "'role' in node.metadata.labels && node.metadata.labels['role'] == 'worker'",
+			want:       true,
+		},
+		{
+			name:       "missing field",
+			expression: "node.spec.providerID == ''",
+			wantErr:    true,
+		},
+		{
+			name:       "invalid quantity",
+			expression: "quantity('foo') > 0.0",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			e, err := Compile(tt.expression)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got, err := e.Eval(node, now)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package progress implements helper functions for detecting rollout and scale operations not making progress.
+package progress
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// State describes the state of the rollout or scale operation of an object; any change of the state
+// is considered progress.
+type State struct {
+	Generation        int64
+	DesiredReplicas   int32
+	Replicas          int32
+	UpdatedReplicas   int32
+	ReadyReplicas     int32
+	AvailableReplicas int32
+}
+
+// String returns a human-readable description of the state.
+func (s State) String() string {
+	return fmt.Sprintf("%d of %d replicas updated, %d ready, %d total", s.UpdatedReplicas, s.DesiredReplicas, s.ReadyReplicas, s.Replicas)
+}
+
+// Input is the input for Reconcile.
+type Input struct {
+	// Object is the object whose progress is reconciled, e.g. a MachineDeployment.
+	Object conditions.Setter
+
+	// ProgressDeadlineSeconds is the maximum time for the operation to make progress; if nil, progress is not tracked.
+	ProgressDeadlineSeconds *int32
+
+	// ProgressDeadline is the current progress deadline, as reported in the status of the object.
+	ProgressDeadline *metav1.Time
+
+	// Previous is the state of the operation observed in the previous reconcile.
+	Previous State
+
+	// Current is the state of the operation observed in the current reconcile.
+	Current State
+
+	// Completed is true if no operation is in progress, or if progress should not be estimated, e.g. while paused.
+	Completed bool
+}
+
+// Reconcile sets the Progressing condition on the object and returns the progress deadline to be reported in its status.
+// The deadline is moved forward every time the operation makes progress; if the deadline is exceeded the condition
+// is set to false with the ProgressDeadlineExceeded reason and a warning event is emitted.
+// NOTE: No automatic action, e.g. rollback, is performed when the progress deadline is exceeded.
+func Reconcile(recorder record.EventRecorder, in Input, now time.Time) *metav1.Time {
+	if in.ProgressDeadlineSeconds == nil {
+		conditions.Delete(in.Object, clusterv1.ProgressingCondition)
+		return nil
+	}
+
+	if in.Completed {
+		conditions.MarkTrue(in.Object, clusterv1.ProgressingCondition)
+		return nil
+	}
+
+	deadline := time.Duration(*in.ProgressDeadlineSeconds) * time.Second
+	if in.ProgressDeadline == nil || in.Current != in.Previous {
+		conditions.MarkTrue(in.Object, clusterv1.ProgressingCondition)
+		return &metav1.Time{Time: now.Add(deadline)}
+	}
+
+	if now.Before(in.ProgressDeadline.Time) {
+		conditions.MarkTrue(in.Object, clusterv1.ProgressingCondition)
+		return in.ProgressDeadline
+	}
+
+	if !conditions.IsFalse(in.Object, clusterv1.ProgressingCondition) {
+		recorder.Eventf(in.Object, corev1.EventTypeWarning, clusterv1.ProgressDeadlineExceededReason,
+			"No progress in %s: %s", deadline, in.Current)
+	}
+	conditions.MarkFalse(in.Object, clusterv1.ProgressingCondition, clusterv1.ProgressDeadlineExceededReason, clusterv1.ConditionSeverityWarning,
+		"No progress since %s: %s", in.ProgressDeadline.Add(-deadline).UTC().Format(time.RFC3339), in.Current)
+	return in.ProgressDeadline
+}
+
+// RequeueAfter returns the time until the progress deadline, so controllers can requeue and detect operations
+// not making progress; it returns zero if there is no deadline or if the deadline is already exceeded.
+func RequeueAfter(progressDeadline *metav1.Time, now time.Time) time.Duration {
+	if progressDeadline == nil || !now.Before(progressDeadline.Time) {
+		return 0
+	}
+	return progressDeadline.Sub(now)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Now()
+	inProgress := State{DesiredReplicas: 3, Replicas: 3, UpdatedReplicas: 1, ReadyReplicas: 3}
+	progressed := State{DesiredReplicas: 3, Replicas: 4, UpdatedReplicas: 2, ReadyReplicas: 3}
+
+	tests := []struct {
+		name             string
+		seconds          *int32
+		deadline         *metav1.Time
+		previous         State
+		current          State
+		completed        bool
+		wantDeadline     *metav1.Time
+		wantCondition    corev1.ConditionStatus
+		wantEvents       int
+		wantNoCondition  bool
+		existingExceeded bool
+	}{
+		{
+			name:            "progress is not tracked without a progress deadline",
+			seconds:         nil,
+			current:         inProgress,
+			wantNoCondition: true,
+		},
+		{
+			name:          "no deadline if the operation is completed",
+			seconds:       pointer.Int32(600),
+			deadline:      &metav1.Time{Time: now},
+			completed:     true,
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name:          "set the deadline when an operation starts",
+			seconds:       pointer.Int32(600),
+			previous:      inProgress,
+			current:       inProgress,
+			wantDeadline:  &metav1.Time{Time: now.Add(600 * time.Second)},
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name:          "move the deadline forward when the operation makes progress",
+			seconds:       pointer.Int32(600),
+			deadline:      &metav1.Time{Time: now.Add(-time.Minute)},
+			previous:      inProgress,
+			current:       progressed,
+			wantDeadline:  &metav1.Time{Time: now.Add(600 * time.Second)},
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name:          "keep the deadline when the operation does not make progress",
+			seconds:       pointer.Int32(600),
+			deadline:      &metav1.Time{Time: now.Add(time.Minute)},
+			previous:      inProgress,
+			current:       inProgress,
+			wantDeadline:  &metav1.Time{Time: now.Add(time.Minute)},
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name:          "report the deadline is exceeded when the operation does not make progress",
+			seconds:       pointer.Int32(600),
+			deadline:      &metav1.Time{Time: now.Add(-time.Minute)},
+			previous:      inProgress,
+			current:       inProgress,
+			wantDeadline:  &metav1.Time{Time: now.Add(-time.Minute)},
+			wantCondition: corev1.ConditionFalse,
+			wantEvents:    1,
+		},
+		{
+			name:             "do not emit an event again if the deadline is already reported as exceeded",
+			seconds:          pointer.Int32(600),
+			deadline:         &metav1.Time{Time: now.Add(-time.Minute)},
+			previous:         inProgress,
+			current:          inProgress,
+			existingExceeded: true,
+			wantDeadline:     &metav1.Time{Time: now.Add(-time.Minute)},
+			wantCondition:    corev1.ConditionFalse,
+			wantEvents:       0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			md := &clusterv1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{Name: "md"}}
+			if tt.existingExceeded {
+				conditions.MarkFalse(md, clusterv1.ProgressingCondition, clusterv1.ProgressDeadlineExceededReason, clusterv1.ConditionSeverityWarning, "")
+			}
+			recorder := record.NewFakeRecorder(10)
+
+			got := Reconcile(recorder, Input{
+				Object:                  md,
+				ProgressDeadlineSeconds: tt.seconds,
+				ProgressDeadline:        tt.deadline,
+				Previous:                tt.previous,
+				Current:                 tt.current,
+				Completed:               tt.completed,
+			}, now)
+
+			g.Expect(got).To(Equal(tt.wantDeadline))
+			g.Expect(recorder.Events).To(HaveLen(tt.wantEvents))
+			if tt.wantNoCondition {
+				g.Expect(conditions.Has(md, clusterv1.ProgressingCondition)).To(BeFalse())
+				return
+			}
+			g.Expect(conditions.Get(md, clusterv1.ProgressingCondition).Status).To(Equal(tt.wantCondition))
+			if tt.wantCondition == corev1.ConditionFalse {
+				g.Expect(conditions.GetReason(md, clusterv1.ProgressingCondition)).To(Equal(clusterv1.ProgressDeadlineExceededReason))
+			}
+		})
+	}
+}
+
+func TestRequeueAfter(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	g.Expect(RequeueAfter(nil, now)).To(BeZero())
+	g.Expect(RequeueAfter(&metav1.Time{Time: now.Add(-time.Minute)}, now)).To(BeZero())
+	g.Expect(RequeueAfter(&metav1.Time{Time: now.Add(time.Minute)}, now)).To(Equal(time.Minute))
+}
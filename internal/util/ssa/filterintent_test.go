@@ -21,7 +21,7 @@ import (
 
 	. "github.com/onsi/gomega"
 
-	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/util/contract"
 )
 
 func Test_filterNotAllowedPaths(t *testing.T) {
@@ -36,3 +36,21 @@ func RemoveNodeTaint(node *corev1.Node, drop corev1.Taint) bool {
 	node.Spec.Taints = taints
 	return droppedTaint
 }
+
+// EnsureNodeTaint makes sure the node has the taint, adding it or updating the value of
+// an existing taint with the same key and effect.
+// It returns true if the taints are modified, false otherwise.
+func EnsureNodeTaint(node *corev1.Node, taint corev1.Taint) bool {
+	for i := range node.Spec.Taints {
+		if !node.Spec.Taints[i].MatchTaint(&taint) {
+			continue
+		}
+		if node.Spec.Taints[i].Value == taint.Value {
+			return false
+		}
+		node.Spec.Taints[i].Value = taint.Value
+		return true
+	}
+	node.Spec.Taints = append(node.Spec.Taints, taint)
+	return true
+}
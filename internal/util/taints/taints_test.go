@@ -76,3 +76,68 @@ func TestRemoveNodeTaint(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureNodeTaint(t *testing.T) {
+	taint1 := corev1.Taint{Key: "taint1", Value: "foo", Effect: corev1.TaintEffectNoSchedule}
+	taint1NewValue := corev1.Taint{Key: "taint1", Value: "bar", Effect: corev1.TaintEffectNoSchedule}
+	taint1NoExecute := corev1.Taint{Key: "taint1", Value: "foo", Effect: corev1.TaintEffectNoExecute}
+	taint2 := corev1.Taint{Key: "taint2", Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name         string
+		node         *corev1.Node
+		taint        corev1.Taint
+		wantTaints   []corev1.Taint
+		wantModified bool
+	}{
+		{
+			name:         "adding taint to node without taints should return true",
+			node:         &corev1.Node{},
+			taint:        taint1,
+			wantTaints:   []corev1.Taint{taint1},
+			wantModified: true,
+		},
+		{
+			name: "adding taint with a different effect should return true",
+			node: &corev1.Node{Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{
+					taint1,
+					taint2,
+				}}},
+			taint:        taint1NoExecute,
+			wantTaints:   []corev1.Taint{taint1, taint2, taint1NoExecute},
+			wantModified: true,
+		},
+		{
+			name: "updating the value of an existing taint should return true",
+			node: &corev1.Node{Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{
+					taint1,
+					taint2,
+				}}},
+			taint:        taint1NewValue,
+			wantTaints:   []corev1.Taint{taint1NewValue, taint2},
+			wantModified: true,
+		},
+		{
+			name: "ensure existing taint should return false",
+			node: &corev1.Node{Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{
+					taint1,
+					taint2,
+				}}},
+			taint:        taint1,
+			wantTaints:   []corev1.Taint{taint1, taint2},
+			wantModified: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got := EnsureNodeTaint(tt.node, tt.taint)
+			g.Expect(got).To(Equal(tt.wantModified))
+			g.Expect(tt.node.Spec.Taints).To(Equal(tt.wantTaints))
+		})
+	}
+}
@@ -83,6 +83,10 @@ func (webhook *Cluster) Default(ctx context.Context, obj runtime.Object) error {
 		cluster.Spec.ControlPlaneRef.Namespace = cluster.Namespace
 	}
 
+	if cluster.Spec.ControlPlaneEndpointRef != nil && cluster.Spec.ControlPlaneEndpointRef.Namespace == "" {
+		cluster.Spec.ControlPlaneEndpointRef.Namespace = cluster.Namespace
+	}
+
 	// Additional defaulting if the Cluster uses a managed topology.
 	if cluster.Spec.Topology != nil {
 		// Tolerate version strings without a "v" prefix: prepend it if it's not there.
@@ -173,6 +177,17 @@ func (webhook *Cluster) validate(ctx context.Context, oldCluster, newCluster *cl
 			),
 		)
 	}
+
+	if newCluster.Spec.ControlPlaneEndpointRef != nil && newCluster.Spec.ControlPlaneEndpointRef.Namespace != newCluster.Namespace {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				specPath.Child("controlPlaneEndpointRef", "namespace"),
+				newCluster.Spec.ControlPlaneEndpointRef.Namespace,
+				"must match metadata.namespace",
+			),
+		)
+	}
 	if newCluster.Spec.ClusterNetwork != nil {
 		// Ensure that the CIDR blocks defined under ClusterNetwork are valid.
 		if newCluster.Spec.ClusterNetwork.Pods != nil {
@@ -186,6 +201,13 @@ func (webhook *Cluster) validate(ctx context.Context, oldCluster, newCluster *cl
 		}
 	}
 
+	if newCluster.Spec.MetadataPropagation != nil {
+		allErrs = append(allErrs, validateMetadataPropagationKeys(specPath.Child("metadataPropagation", "labels"),
+			newCluster.Spec.MetadataPropagation.Labels)...)
+		allErrs = append(allErrs, validateMetadataPropagationKeys(specPath.Child("metadataPropagation", "annotations"),
+			newCluster.Spec.MetadataPropagation.Annotations)...)
+	}
+
 	topologyPath := specPath.Child("topology")
 
 	// Validate the managed topology, if defined.
@@ -280,6 +302,11 @@ func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newClu
 	if clusterClassPollErr == nil {
 		// If there's no error validate the Cluster based on the ClusterClass.
 		allErrs = append(allErrs, ValidateClusterForClusterClass(newCluster, clusterClass)...)
+
+		// Validate the replicas of the MachineDeployments managed by the autoscaler.
+		// NOTE: The old Cluster is validated against the current ClusterClass as well, because the ClusterClass
+		// used by the old Cluster is not available.
+		allErrs = append(allErrs, validateAutoscalerAnnotationsForCluster(oldCluster, newCluster, clusterClass, clusterClass)...)
 	}
 	if oldCluster != nil { // On update
 		// The ClusterClass must exist to proceed with update validation. Return an error if the ClusterClass was
@@ -359,6 +386,12 @@ func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newClu
 			)
 		}
 
+		// A minor version upgrade is not allowed if MachineDeployments are still on an older minor version, e.g.
+		// because their upgrade has been deferred; otherwise those MachineDeployments would violate the version skew policy.
+		if inVersion.NE(semver.Version{}) && oldVersion.NE(semver.Version{}) && inVersion.Minor > oldVersion.Minor {
+			allErrs = append(allErrs, webhook.validateMachineDeploymentsVersion(ctx, newCluster, oldVersion, inVersion, fldPath.Child("version"))...)
+		}
+
 		// If the ClusterClass referenced in the Topology has changed compatibility checks are needed.
 		if oldCluster.Spec.Topology.Class != newCluster.Spec.Topology.Class {
 			// Check to see if the ClusterClass referenced in the old version of the Cluster exists.
@@ -376,11 +409,61 @@ func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newClu
 
 			// Check if the new and old ClusterClasses are compatible with one another.
 			allErrs = append(allErrs, check.ClusterClassesAreCompatible(oldClusterClass, clusterClass)...)
+
+			// Check if the new ClusterClass is a newer revision of the old one, if they are in the same lineage,
+			// unless downgrades are allowed with the ClusterTopologyUnsafeAllowClassDowngradeAnnotation.
+			if _, ok := newCluster.Annotations[clusterv1.ClusterTopologyUnsafeAllowClassDowngradeAnnotation]; !ok {
+				allErrs = append(allErrs, check.ClusterClassRevisionsAreCompatible(oldClusterClass, clusterClass)...)
+			}
 		}
 	}
 	return allErrs
 }
 
+// validateMachineDeploymentsVersion checks that all the MachineDeployments of the Cluster topology are on the same minor
+// version of the Cluster topology before allowing a minor version upgrade.
+// NOTE: MachineDeployments can lag behind the Cluster topology version when their upgrade is deferred using
+// the ClusterTopologyDeferUpgradeAnnotation or the ClusterTopologyHoldUpgradeSequenceAnnotation annotations.
+func (webhook *Cluster) validateMachineDeploymentsVersion(ctx context.Context, cluster *clusterv1.Cluster, oldVersion, newVersion semver.Version, fldPath *field.Path) field.ErrorList {
+	mds := &clusterv1.MachineDeploymentList{}
+	if err := webhook.Client.List(ctx, mds,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			clusterv1.ClusterNameLabel:          cluster.Name,
+			clusterv1.ClusterTopologyOwnedLabel: "",
+		},
+	); err != nil {
+		return field.ErrorList{field.InternalError(fldPath, errors.Wrap(err, "failed to list MachineDeployments"))}
+	}
+
+	laggingMDs := []string{}
+	for _, md := range mds.Items {
+		if md.Spec.Template.Spec.Version == nil {
+			continue
+		}
+		mdVersion, err := semver.ParseTolerant(*md.Spec.Template.Spec.Version)
+		if err != nil {
+			continue
+		}
+		if mdVersion.Major < oldVersion.Major || (mdVersion.Major == oldVersion.Major && mdVersion.Minor < oldVersion.Minor) {
+			laggingMDs = append(laggingMDs, md.Name)
+		}
+	}
+	if len(laggingMDs) == 0 {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Forbidden(
+			fldPath,
+			fmt.Sprintf("version cannot be increased from %q to %q because MachineDeployments %s are still on an older minor version; "+
+				"remove the %q and %q annotations from the corresponding MachineDeployment topologies and wait for the upgrade to complete first",
+				oldVersion, newVersion, strings.Join(laggingMDs, ", "),
+				clusterv1.ClusterTopologyDeferUpgradeAnnotation, clusterv1.ClusterTopologyHoldUpgradeSequenceAnnotation),
+		),
+	}
+}
+
 func validateMachineHealthChecks(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -450,6 +533,73 @@ func validateMachineHealthChecks(cluster *clusterv1.Cluster, clusterClass *clust
 	return allErrs
 }
 
+// validateAutoscalerAnnotationsForCluster ensures replicas are not set for MachineDeploymentTopologies managed by the
+// autoscaler, i.e. if the MachineDeploymentTopology or the corresponding MachineDeploymentClass have the autoscaler
+// min size and max size annotations; otherwise the topology controller would reset the replicas set by the autoscaler
+// on every reconcile.
+// Only combinations newly introduced with respect to the old Cluster and the old ClusterClass, if any, are rejected,
+// so existing Clusters can still be updated.
+// NOTE: If replicas are not set, the MachineDeployment webhook defaults replicas within the (min size, max size) range
+// when the MachineDeployment is created, and the topology controller never changes them afterwards.
+func validateAutoscalerAnnotationsForCluster(oldCluster, newCluster *clusterv1.Cluster, oldClusterClass, newClusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if newCluster.Spec.Topology == nil || newCluster.Spec.Topology.Workers == nil {
+		return allErrs
+	}
+
+	oldMachineDeployments := map[string]clusterv1.MachineDeploymentTopology{}
+	if oldCluster != nil && oldCluster.Spec.Topology != nil && oldCluster.Spec.Topology.Workers != nil {
+		for _, md := range oldCluster.Spec.Topology.Workers.MachineDeployments {
+			oldMachineDeployments[md.Name] = md
+		}
+	}
+
+	fldPath := field.NewPath("spec", "topology", "workers", "machineDeployments")
+	for i, md := range newCluster.Spec.Topology.Workers.MachineDeployments {
+		msg, conflict := autoscalerReplicasConflict(md, newClusterClass)
+		if !conflict {
+			continue
+		}
+		if oldMD, ok := oldMachineDeployments[md.Name]; ok {
+			if _, oldConflict := autoscalerReplicasConflict(oldMD, oldClusterClass); oldConflict {
+				continue
+			}
+		}
+		allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("replicas"), *md.Replicas, msg))
+	}
+
+	return allErrs
+}
+
+// autoscalerReplicasConflict returns true, together with the reason, if replicas are set for a
+// MachineDeploymentTopology with the autoscaler annotations, or with a MachineDeploymentClass with the autoscaler
+// annotations in the given ClusterClass, if any.
+func autoscalerReplicasConflict(md clusterv1.MachineDeploymentTopology, clusterClass *clusterv1.ClusterClass) (string, bool) {
+	if md.Replicas == nil {
+		return "", false
+	}
+
+	if hasAutoscalerAnnotations(md.Metadata.Annotations) {
+		return "cannot be set if the MachineDeploymentTopology has the autoscaler annotations, replicas are managed by the autoscaler", true
+	}
+
+	if clusterClass == nil {
+		return "", false
+	}
+	if mdClass := machineDeploymentClassOfName(clusterClass, md.Class); mdClass != nil && hasAutoscalerAnnotations(mdClass.Template.Metadata.Annotations) {
+		return fmt.Sprintf("cannot be set if the MachineDeploymentClass %q has the autoscaler annotations, replicas are managed by the autoscaler", md.Class), true
+	}
+	return "", false
+}
+
+// hasAutoscalerAnnotations returns true if both the autoscaler min size and max size annotations are set.
+func hasAutoscalerAnnotations(annotations map[string]string) bool {
+	_, hasMinSize := annotations[clusterv1.AutoscalerMinSizeAnnotation]
+	_, hasMaxSize := annotations[clusterv1.AutoscalerMaxSizeAnnotation]
+	return hasMinSize && hasMaxSize
+}
+
 // machineDeploymentClassOfName find a MachineDeploymentClass of the given name in the provided ClusterClass.
 // Returns nil if it can not find one.
 // TODO: Check if there is already a helper function that can do this.
@@ -476,6 +626,26 @@ func validateCIDRBlocks(fldPath *field.Path, cidrs []string) field.ErrorList {
 	return allErrs
 }
 
+// validateMetadataPropagationKeys validates the keys of the labels or annotations propagated to the objects of a Cluster.
+// NOTE: Keys in the cluster.x-k8s.io domain are reserved to Cluster API, and they cannot be propagated.
+func validateMetadataPropagationKeys(fldPath *field.Path, keys []string) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := map[string]bool{}
+	for i, key := range keys {
+		for _, msg := range validation.IsQualifiedName(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), key, msg))
+		}
+		if prefix, _, ok := strings.Cut(key, "/"); ok && (prefix == clusterv1.GroupVersion.Group || strings.HasSuffix(prefix, "."+clusterv1.GroupVersion.Group)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), key, fmt.Sprintf("keys in the %s domain cannot be propagated", clusterv1.GroupVersion.Group)))
+		}
+		if seen[key] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), key))
+		}
+		seen[key] = true
+	}
+	return allErrs
+}
+
 // DefaultAndValidateVariables defaults and validates variables in the Cluster and MachineDeployment topologies based
 // on the definitions in the ClusterClass.
 func DefaultAndValidateVariables(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
@@ -44,8 +44,9 @@ func TestClusterDefaultNamespaces(t *testing.T) {
 			Namespace: "fooboo",
 		},
 		Spec: clusterv1.ClusterSpec{
-			InfrastructureRef: &corev1.ObjectReference{},
-			ControlPlaneRef:   &corev1.ObjectReference{},
+			InfrastructureRef:       &corev1.ObjectReference{},
+			ControlPlaneRef:         &corev1.ObjectReference{},
+			ControlPlaneEndpointRef: &corev1.ObjectReference{},
 		},
 	}
 	webhook := &Cluster{}
@@ -55,6 +56,7 @@ func TestClusterDefaultNamespaces(t *testing.T) {
 
 	g.Expect(c.Spec.InfrastructureRef.Namespace).To(Equal(c.Namespace))
 	g.Expect(c.Spec.ControlPlaneRef.Namespace).To(Equal(c.Namespace))
+	g.Expect(c.Spec.ControlPlaneEndpointRef.Namespace).To(Equal(c.Namespace))
 }
 
 // TestClusterDefaultAndValidateVariables cases where cluster.spec.topology.class is altered.
@@ -808,6 +810,15 @@ func TestClusterDefaultTopologyVersion(t *testing.T) {
 func TestClusterValidation(t *testing.T) {
 	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to set Cluster.Topologies.
 
+	clusterWithMetadataPropagation := func(labels, annotations []string) *clusterv1.Cluster {
+		cluster := builder.Cluster("fooNamespace", "cluster1").Build()
+		cluster.Spec.MetadataPropagation = &clusterv1.ClusterMetadataPropagation{
+			Labels:      labels,
+			Annotations: annotations,
+		}
+		return cluster
+	}
+
 	var (
 		tests = []struct {
 			name      string
@@ -995,6 +1006,31 @@ func TestClusterValidation(t *testing.T) {
 				in:        builder.Cluster("fooNamespace", "thisNameContainsInvalid!@NonAlphanumerics").Build(),
 				expectErr: true,
 			},
+			{
+				name:      "pass with valid metadata propagation keys",
+				in:        clusterWithMetadataPropagation([]string{"team", "example.com/cost-center"}, []string{"example.com/owner"}),
+				expectErr: false,
+			},
+			{
+				name:      "error when a propagated label key is invalid",
+				in:        clusterWithMetadataPropagation([]string{"not a key"}, nil),
+				expectErr: true,
+			},
+			{
+				name:      "error when a propagated annotation key is in the cluster.x-k8s.io domain",
+				in:        clusterWithMetadataPropagation(nil, []string{clusterv1.PausedAnnotation}),
+				expectErr: true,
+			},
+			{
+				name:      "error when a propagated label key is in a subdomain of cluster.x-k8s.io",
+				in:        clusterWithMetadataPropagation([]string{"topology.cluster.x-k8s.io/owned"}, nil),
+				expectErr: true,
+			},
+			{
+				name:      "error when a propagated label key is duplicated",
+				in:        clusterWithMetadataPropagation([]string{"team", "team"}, nil),
+				expectErr: true,
+			},
 		}
 	)
 	for _, tt := range tests {
@@ -1558,6 +1594,98 @@ func TestClusterTopologyValidationWithClient(t *testing.T) {
 	}
 }
 
+func TestClusterTopologyValidationWithDeferredMachineDeployments(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	machineDeployment := func(name, version string) *clusterv1.MachineDeployment {
+		return builder.MachineDeployment(metav1.NamespaceDefault, name).
+			WithClusterName("cluster1").
+			WithLabels(map[string]string{
+				clusterv1.ClusterNameLabel:          "cluster1",
+				clusterv1.ClusterTopologyOwnedLabel: "",
+			}).
+			WithVersion(version).
+			Build()
+	}
+
+	tests := []struct {
+		name       string
+		oldVersion string
+		newVersion string
+		objects    []client.Object
+		wantErr    bool
+	}{
+		{
+			name:       "Accept a minor version upgrade if all MachineDeployments are on the current minor version",
+			oldVersion: "v1.22.2",
+			newVersion: "v1.23.0",
+			objects: []client.Object{
+				machineDeployment("md1", "v1.22.2"),
+				machineDeployment("md2", "v1.22.0"),
+			},
+			wantErr: false,
+		},
+		{
+			name:       "Accept a patch version upgrade if a MachineDeployment is on an older minor version",
+			oldVersion: "v1.22.2",
+			newVersion: "v1.22.3",
+			objects: []client.Object{
+				machineDeployment("md1", "v1.21.2"),
+			},
+			wantErr: false,
+		},
+		{
+			name:       "Reject a minor version upgrade if a MachineDeployment is on an older minor version",
+			oldVersion: "v1.22.2",
+			newVersion: "v1.23.0",
+			objects: []client.Object{
+				machineDeployment("md1", "v1.22.2"),
+				machineDeployment("md2", "v1.21.2"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			class := builder.ClusterClass(metav1.NamespaceDefault, "clusterclass").
+				WithWorkerMachineDeploymentClasses(
+					*builder.MachineDeploymentClass("worker-class").Build(),
+				).
+				Build()
+			// Mark this condition to true so the webhook sees the ClusterClass as up to date.
+			conditions.MarkTrue(class, clusterv1.ClusterClassVariablesReconciledCondition)
+
+			oldCluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(
+					builder.ClusterTopology().
+						WithClass("clusterclass").
+						WithVersion(tt.oldVersion).
+						Build()).
+				Build()
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Spec.Topology.Version = tt.newVersion
+
+			// Sets up the fakeClient for the test case.
+			fakeClient := fake.NewClientBuilder().
+				WithObjects(append(tt.objects, class)...).
+				WithScheme(fakeScheme).
+				Build()
+
+			// Create the webhook and add the fakeClient as its client. This is required because the test uses a Managed Topology.
+			c := &Cluster{Client: fakeClient}
+
+			// Checks the return error.
+			if tt.wantErr {
+				g.Expect(c.ValidateUpdate(ctx, oldCluster, newCluster)).NotTo(Succeed())
+			} else {
+				g.Expect(c.ValidateUpdate(ctx, oldCluster, newCluster)).To(Succeed())
+			}
+		})
+	}
+}
+
 // TestClusterTopologyValidationForTopologyClassChange cases where cluster.spec.topology.class is altered.
 func TestClusterTopologyValidationForTopologyClassChange(t *testing.T) {
 	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
@@ -1979,6 +2107,221 @@ func TestClusterTopologyValidationForTopologyClassChange(t *testing.T) {
 	}
 }
 
+func TestClusterTopologyValidationForTopologyClassRevisionChange(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	ref := &corev1.ObjectReference{
+		APIVersion: "group.test.io/foo",
+		Kind:       "barTemplate",
+		Name:       "baz",
+		Namespace:  "default",
+	}
+	clusterClass := func(name string, revision int32) *clusterv1.ClusterClass {
+		cc := builder.ClusterClass(metav1.NamespaceDefault, name).
+			WithLineage("class", revision).
+			WithInfrastructureClusterTemplate(refToUnstructured(ref)).
+			WithControlPlaneTemplate(refToUnstructured(ref)).
+			WithControlPlaneInfrastructureMachineTemplate(refToUnstructured(ref)).
+			Build()
+		// Mark this condition to true so the webhook sees the ClusterClass as up to date.
+		conditions.MarkTrue(cc, clusterv1.ClusterClassVariablesReconciledCondition)
+		return cc
+	}
+	classV1 := clusterClass("class-v1", 1)
+	classV2 := clusterClass("class-v2", 2)
+
+	tests := []struct {
+		name        string
+		fromClass   string
+		toClass     string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{
+			name:      "Accept cluster.topology.class change to a newer revision",
+			fromClass: "class-v1",
+			toClass:   "class-v2",
+			wantErr:   false,
+		},
+		{
+			name:      "Reject cluster.topology.class change to an older revision",
+			fromClass: "class-v2",
+			toClass:   "class-v1",
+			wantErr:   true,
+		},
+		{
+			name:        "Accept cluster.topology.class change to an older revision if ClusterTopologyUnsafeAllowClassDowngradeAnnotation is set",
+			fromClass:   "class-v2",
+			toClass:     "class-v1",
+			annotations: map[string]string{clusterv1.ClusterTopologyUnsafeAllowClassDowngradeAnnotation: ""},
+			wantErr:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeClient := fake.NewClientBuilder().
+				WithObjects(classV1, classV2).
+				WithScheme(fakeScheme).
+				Build()
+			c := &Cluster{Client: fakeClient}
+
+			oldCluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(
+					builder.ClusterTopology().
+						WithClass(tt.fromClass).
+						WithVersion("v1.22.2").
+						WithControlPlaneReplicas(3).
+						Build()).
+				Build()
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Spec.Topology.Class = tt.toClass
+			newCluster.SetAnnotations(tt.annotations)
+
+			if tt.wantErr {
+				g.Expect(c.ValidateUpdate(ctx, oldCluster, newCluster)).NotTo(Succeed())
+			} else {
+				g.Expect(c.ValidateUpdate(ctx, oldCluster, newCluster)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestValidateAutoscalerAnnotationsForCluster(t *testing.T) {
+	autoscalerAnnotations := map[string]string{
+		clusterv1.AutoscalerMinSizeAnnotation: "1",
+		clusterv1.AutoscalerMaxSizeAnnotation: "5",
+	}
+	clusterClass := &clusterv1.ClusterClass{
+		Spec: clusterv1.ClusterClassSpec{
+			Workers: clusterv1.WorkersClass{
+				MachineDeployments: []clusterv1.MachineDeploymentClass{
+					{Class: "default-worker"},
+					{
+						Class: "autoscaled-worker",
+						Template: clusterv1.MachineDeploymentClassTemplate{
+							Metadata: clusterv1.ObjectMeta{Annotations: autoscalerAnnotations},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		oldMD     *clusterv1.MachineDeploymentTopology
+		md        clusterv1.MachineDeploymentTopology
+		expectErr bool
+	}{
+		{
+			name: "pass if replicas are set without autoscaler annotations",
+			md: clusterv1.MachineDeploymentTopology{
+				Class:    "default-worker",
+				Replicas: pointer.Int32(3),
+			},
+		},
+		{
+			name: "pass if replicas are not set with autoscaler annotations on the MachineDeploymentTopology",
+			md: clusterv1.MachineDeploymentTopology{
+				Class:    "default-worker",
+				Metadata: clusterv1.ObjectMeta{Annotations: autoscalerAnnotations},
+			},
+		},
+		{
+			name: "pass if replicas are not set with autoscaler annotations on the MachineDeploymentClass",
+			md: clusterv1.MachineDeploymentTopology{
+				Class: "autoscaled-worker",
+			},
+		},
+		{
+			name: "pass if replicas are set and only the min size annotation is set",
+			md: clusterv1.MachineDeploymentTopology{
+				Class:    "default-worker",
+				Replicas: pointer.Int32(3),
+				Metadata: clusterv1.ObjectMeta{Annotations: map[string]string{clusterv1.AutoscalerMinSizeAnnotation: "1"}},
+			},
+		},
+		{
+			name: "fail if replicas are set with autoscaler annotations on the MachineDeploymentTopology",
+			md: clusterv1.MachineDeploymentTopology{
+				Class:    "default-worker",
+				Replicas: pointer.Int32(3),
+				Metadata: clusterv1.ObjectMeta{Annotations: autoscalerAnnotations},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail if replicas are set with autoscaler annotations on the MachineDeploymentClass",
+			md: clusterv1.MachineDeploymentTopology{
+				Class:    "autoscaled-worker",
+				Replicas: pointer.Int32(3),
+			},
+			expectErr: true,
+		},
+		{
+			name: "pass if replicas were already set with autoscaler annotations on the old Cluster",
+			oldMD: &clusterv1.MachineDeploymentTopology{
+				Name:     "md1",
+				Class:    "default-worker",
+				Replicas: pointer.Int32(3),
+				Metadata: clusterv1.ObjectMeta{Annotations: autoscalerAnnotations},
+			},
+			md: clusterv1.MachineDeploymentTopology{
+				Name:     "md1",
+				Class:    "default-worker",
+				Replicas: pointer.Int32(5),
+				Metadata: clusterv1.ObjectMeta{Annotations: autoscalerAnnotations},
+			},
+		},
+		{
+			name: "fail if replicas are set on update with autoscaler annotations on the MachineDeploymentClass",
+			oldMD: &clusterv1.MachineDeploymentTopology{
+				Name:  "md1",
+				Class: "autoscaled-worker",
+			},
+			md: clusterv1.MachineDeploymentTopology{
+				Name:     "md1",
+				Class:    "autoscaled-worker",
+				Replicas: pointer.Int32(3),
+			},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("class1").
+					WithVersion("v1.22.2").
+					WithMachineDeployment(tt.md).
+					Build()).
+				Build()
+
+			var oldCluster *clusterv1.Cluster
+			if tt.oldMD != nil {
+				oldCluster = builder.Cluster(metav1.NamespaceDefault, "cluster1").
+					WithTopology(builder.ClusterTopology().
+						WithClass("class1").
+						WithVersion("v1.22.2").
+						WithMachineDeployment(*tt.oldMD).
+						Build()).
+					Build()
+			}
+
+			errs := validateAutoscalerAnnotationsForCluster(oldCluster, cluster, clusterClass, clusterClass)
+			if tt.expectErr {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
+
 // TestMovingBetweenManagedAndUnmanaged cluster tests cases where a clusterClass is added or removed during a cluster update.
 func TestMovingBetweenManagedAndUnmanaged(t *testing.T) {
 	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
@@ -2232,3 +2575,41 @@ func refToUnstructured(ref *corev1.ObjectReference) *unstructured.Unstructured {
 	output.SetNamespace(ref.Namespace)
 	return output
 }
+
+func TestValidateAutoscalerAnnotationsForClusterClass(t *testing.T) {
+	g := NewWithT(t)
+
+	autoscalerAnnotations := map[string]string{
+		clusterv1.AutoscalerMinSizeAnnotation: "1",
+		clusterv1.AutoscalerMaxSizeAnnotation: "5",
+	}
+	oldClusterClass := &clusterv1.ClusterClass{
+		Spec: clusterv1.ClusterClassSpec{
+			Workers: clusterv1.WorkersClass{
+				MachineDeployments: []clusterv1.MachineDeploymentClass{
+					{Class: "default-worker"},
+				},
+			},
+		},
+	}
+	newClusterClass := oldClusterClass.DeepCopy()
+	newClusterClass.Spec.Workers.MachineDeployments[0].Template.Metadata.Annotations = autoscalerAnnotations
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+		WithTopology(builder.ClusterTopology().
+			WithClass("class1").
+			WithVersion("v1.22.2").
+			WithMachineDeployment(clusterv1.MachineDeploymentTopology{
+				Name:     "md1",
+				Class:    "default-worker",
+				Replicas: pointer.Int32(3),
+			}).
+			Build()).
+		Build()
+
+	// The autoscaler annotations cannot be added to a MachineDeploymentClass used by Clusters setting replicas.
+	g.Expect(validateAutoscalerAnnotationsForClusterClass([]clusterv1.Cluster{*cluster}, oldClusterClass, newClusterClass)).ToNot(BeEmpty())
+
+	// The ClusterClass can still be updated if the autoscaler annotations were already there.
+	g.Expect(validateAutoscalerAnnotationsForClusterClass([]clusterv1.Cluster{*cluster}, newClusterClass, newClusterClass)).To(BeEmpty())
+}
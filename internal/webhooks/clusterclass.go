@@ -31,6 +31,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
@@ -40,10 +41,16 @@ import (
 )
 
 func (webhook *ClusterClass) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	// NOTE: The validating webhook is registered explicitly, instead of using WithValidator, so the rollout impact
+	// of ClusterClass changes can be returned as warnings.
+	mgr.GetWebhookServer().Register("/validate-cluster-x-k8s-io-v1beta1-clusterclass", &admission.Webhook{
+		Handler: &rolloutImpactWarningsHandler{
+			handler: admission.WithCustomValidator(&clusterv1.ClusterClass{}, webhook).Handler,
+		},
+	})
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&clusterv1.ClusterClass{}).
 		WithDefaulter(webhook).
-		WithValidator(webhook).
 		Complete()
 }
 
@@ -155,11 +162,17 @@ func (webhook *ClusterClass) validate(ctx context.Context, oldClusterClass, newC
 	// Validate patches.
 	allErrs = append(allErrs, validatePatches(newClusterClass)...)
 
+	// Ensure the revision is unique in the lineage, if any.
+	allErrs = append(allErrs, webhook.validateLineageRevisionIsUnique(ctx, newClusterClass)...)
+
 	// If this is an update run additional validation.
 	if oldClusterClass != nil {
 		// Ensure spec changes are compatible.
 		allErrs = append(allErrs, check.ClusterClassesAreCompatible(oldClusterClass, newClusterClass)...)
 
+		// Ensure the lineage is not changed.
+		allErrs = append(allErrs, check.ClusterClassLineageIsImmutable(oldClusterClass, newClusterClass)...)
+
 		// Retrieve all clusters using the ClusterClass.
 		clusters, err := webhook.getClustersUsingClusterClass(ctx, oldClusterClass)
 		if err != nil {
@@ -175,6 +188,14 @@ func (webhook *ClusterClass) validate(ctx context.Context, oldClusterClass, newC
 		// Ensure no MachineHealthCheck currently in use has been removed from the ClusterClass.
 		allErrs = append(allErrs,
 			validateUpdatesToMachineHealthCheckClasses(clusters, oldClusterClass, newClusterClass)...)
+
+		// Report the Clusters rolling out machines because of the change, and ensure the change has been acknowledged, if required.
+		allErrs = append(allErrs,
+			validateRolloutAcknowledgement(ctx, clusters, oldClusterClass, newClusterClass)...)
+
+		// Ensure the autoscaler annotations are not added to MachineDeploymentClasses used by Clusters setting replicas.
+		allErrs = append(allErrs,
+			validateAutoscalerAnnotationsForClusterClass(clusters, oldClusterClass, newClusterClass)...)
 	}
 
 	if len(allErrs) > 0 {
@@ -183,6 +204,22 @@ func (webhook *ClusterClass) validate(ctx context.Context, oldClusterClass, newC
 	return nil
 }
 
+// validateAutoscalerAnnotationsForClusterClass checks that the autoscaler annotations are not added to
+// MachineDeploymentClasses for which the Clusters using the ClusterClass set replicas.
+func validateAutoscalerAnnotationsForClusterClass(clusters []clusterv1.Cluster, oldClusterClass, newClusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+	for i := range clusters {
+		cluster := &clusters[i]
+		if errs := validateAutoscalerAnnotationsForCluster(cluster, cluster, oldClusterClass, newClusterClass); len(errs) > 0 {
+			allErrs = append(allErrs, field.Forbidden(
+				field.NewPath("spec", "workers", "machineDeployments"),
+				fmt.Sprintf("autoscaler annotations cannot be set for MachineDeploymentClasses used by Cluster %q with replicas set: %s", cluster.Name, errs.ToAggregate().Error()),
+			))
+		}
+	}
+	return allErrs
+}
+
 // validateUpdatesToMachineHealthCheckClasses checks if the updates made to MachineHealthChecks are valid.
 // It makes sure that if a MachineHealthCheck definition is dropped from the ClusterClass then none of the
 // clusters using the ClusterClass rely on it to create a MachineHealthCheck.
@@ -306,6 +343,29 @@ func (webhook *ClusterClass) getClustersUsingClusterClass(ctx context.Context, c
 	return clusters.Items, nil
 }
 
+// validateLineageRevisionIsUnique checks that no other ClusterClass in the namespace has the same lineage and revision.
+func (webhook *ClusterClass) validateLineageRevisionIsUnique(ctx context.Context, clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	if clusterClass.Spec.Lineage == nil {
+		return nil
+	}
+
+	clusterClasses := &clusterv1.ClusterClassList{}
+	if err := webhook.Client.List(ctx, clusterClasses, client.InNamespace(clusterClass.Namespace)); err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("spec", "lineage"),
+			errors.Wrap(err, "ClusterClasses can not be retrieved"))}
+	}
+	for _, other := range clusterClasses.Items {
+		if other.Name == clusterClass.Name || other.Spec.Lineage == nil {
+			continue
+		}
+		if *other.Spec.Lineage == *clusterClass.Spec.Lineage {
+			return field.ErrorList{field.Duplicate(field.NewPath("spec", "lineage", "revision"),
+				fmt.Sprintf("revision %d of lineage %q is already used by ClusterClass %q", clusterClass.Spec.Lineage.Revision, clusterClass.Spec.Lineage.Name, other.Name))}
+		}
+	}
+	return nil
+}
+
 func getClusterClassVariablesMapWithReverseIndex(clusterClassVariables []clusterv1.ClusterClassVariable) (map[string]*clusterv1.ClusterClassVariable, map[string]int) {
 	variablesMap := map[string]*clusterv1.ClusterClassVariable{}
 	variablesIndexMap := map[string]int{}
@@ -362,5 +422,5 @@ func validateMachineHealthCheckClass(fldPath *field.Path, namepace string, m *cl
 			RemediationTemplate: m.RemediationTemplate,
 		}}
 
-	return mhc.ValidateCommonFields(fldPath)
+	return validateMachineHealthCheckCommonFields(&mhc, fldPath)
 }
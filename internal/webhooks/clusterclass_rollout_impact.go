@@ -0,0 +1,190 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/hash"
+)
+
+// maxReportedRolloutImpact is the maximum number of Clusters reported when a change to a ClusterClass
+// rolling out machines is not acknowledged.
+const maxReportedRolloutImpact = 10
+
+// rolloutImpactWarningsKey is the context key for the warnings returned by the ClusterClass validation webhook.
+type rolloutImpactWarningsKey struct{}
+
+// rolloutImpactWarnings collects the rollout impact of a ClusterClass change while validating it.
+type rolloutImpactWarnings struct {
+	warnings []string
+}
+
+// rolloutImpactWarningsHandler wraps the ClusterClass validation handler to return the rollout impact of the
+// admitted changes as warnings, so the impact is reported also when acknowledgement is not required.
+// NOTE: This is required because webhook.CustomValidator does not support returning warnings.
+type rolloutImpactWarningsHandler struct {
+	handler admission.Handler
+}
+
+var _ admission.DecoderInjector = &rolloutImpactWarningsHandler{}
+
+// InjectDecoder injects the decoder into the wrapped handler.
+func (h *rolloutImpactWarningsHandler) InjectDecoder(d *admission.Decoder) error {
+	_, err := admission.InjectDecoderInto(d, h.handler)
+	return err
+}
+
+// Handle handles admission requests.
+func (h *rolloutImpactWarningsHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	w := &rolloutImpactWarnings{}
+	resp := h.handler.Handle(context.WithValue(ctx, rolloutImpactWarningsKey{}, w), req)
+	if !resp.Allowed {
+		// The rollout impact is already part of the error if the change is rejected because it is not acknowledged.
+		return resp
+	}
+	return resp.WithWarnings(w.warnings...)
+}
+
+// validateRolloutAcknowledgement reports the Clusters rolling out machines because of a change to a ClusterClass and
+// ensures that the change has been explicitly acknowledged, if the ClusterClass requires it.
+func validateRolloutAcknowledgement(ctx context.Context, clusters []clusterv1.Cluster, oldClusterClass, newClusterClass *clusterv1.ClusterClass) field.ErrorList {
+	impact := clusterClassRolloutImpact(clusters, oldClusterClass, newClusterClass)
+	if len(impact) == 0 {
+		return nil
+	}
+
+	reported := impact
+	if len(impact) > maxReportedRolloutImpact {
+		reported = append([]string{}, impact[:maxReportedRolloutImpact]...)
+		reported = append(reported, fmt.Sprintf("... (%d more)", len(impact)-maxReportedRolloutImpact))
+	}
+	message := fmt.Sprintf("this change rolls out machines of %d Cluster(s): %s", len(impact), strings.Join(reported, ", "))
+	if w, ok := ctx.Value(rolloutImpactWarningsKey{}).(*rolloutImpactWarnings); ok {
+		w.warnings = append(w.warnings, message)
+	}
+
+	if _, ok := newClusterClass.Annotations[clusterv1.ClusterClassRolloutAcknowledgementRequiredAnnotation]; !ok {
+		return nil
+	}
+
+	token, err := rolloutAcknowledgementToken(newClusterClass)
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("spec"), err)}
+	}
+	if newClusterClass.Annotations[clusterv1.ClusterClassRolloutAcknowledgedAnnotation] == token {
+		return nil
+	}
+
+	return field.ErrorList{field.Forbidden(
+		field.NewPath("metadata", "annotations").Key(clusterv1.ClusterClassRolloutAcknowledgedAnnotation),
+		fmt.Sprintf("%s; set the %s annotation to %q to acknowledge it",
+			message, clusterv1.ClusterClassRolloutAcknowledgedAnnotation, token),
+	)}
+}
+
+// clusterClassRolloutImpact returns the Clusters which are going to roll out machines if the change to the ClusterClass
+// is admitted, together with the parts of the topology rolling out, e.g. "cluster1 [control plane, MachineDeploymentClass md1]".
+// NOTE: The analysis is based on changes to the template references and to patches; changes to patches are considered
+// to impact all the Clusters, given that it is not possible to determine which templates are modified without
+// computing the desired state of each Cluster.
+func clusterClassRolloutImpact(clusters []clusterv1.Cluster, oldClusterClass, newClusterClass *clusterv1.ClusterClass) []string {
+	controlPlaneChanged := templateRefChanged(&oldClusterClass.Spec.ControlPlane.LocalObjectTemplate, &newClusterClass.Spec.ControlPlane.LocalObjectTemplate) ||
+		templateRefChanged(oldClusterClass.Spec.ControlPlane.MachineInfrastructure, newClusterClass.Spec.ControlPlane.MachineInfrastructure)
+	patchesChanged := !reflect.DeepEqual(oldClusterClass.Spec.Patches, newClusterClass.Spec.Patches)
+
+	changedMachineDeploymentClasses := sets.Set[string]{}
+	for _, newMdClass := range newClusterClass.Spec.Workers.MachineDeployments {
+		oldMdClass := machineDeploymentClassOfName(oldClusterClass, newMdClass.Class)
+		if oldMdClass == nil {
+			continue
+		}
+		if templateRefChanged(&oldMdClass.Template.Bootstrap, &newMdClass.Template.Bootstrap) ||
+			templateRefChanged(&oldMdClass.Template.Infrastructure, &newMdClass.Template.Infrastructure) {
+			changedMachineDeploymentClasses.Insert(newMdClass.Class)
+		}
+	}
+
+	impact := []string{}
+	for _, cluster := range clusters {
+		if cluster.Spec.Topology == nil {
+			continue
+		}
+
+		reasons := []string{}
+		if patchesChanged {
+			reasons = append(reasons, "patches")
+		}
+		if controlPlaneChanged {
+			reasons = append(reasons, "control plane")
+		}
+		if cluster.Spec.Topology.Workers != nil {
+			classes := sets.Set[string]{}
+			for _, md := range cluster.Spec.Topology.Workers.MachineDeployments {
+				if changedMachineDeploymentClasses.Has(md.Class) {
+					classes.Insert(md.Class)
+				}
+			}
+			for _, class := range sets.List(classes) {
+				reasons = append(reasons, fmt.Sprintf("MachineDeploymentClass %s", class))
+			}
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+		impact = append(impact, fmt.Sprintf("%s [%s]", cluster.Name, strings.Join(reasons, ", ")))
+	}
+	sort.Strings(impact)
+	return impact
+}
+
+// templateRefChanged returns true if the template referenced by a LocalObjectTemplate changed.
+// NOTE: Changes to the apiVersion only are ignored, given that they do not change the template.
+func templateRefChanged(oldTemplate, newTemplate *clusterv1.LocalObjectTemplate) bool {
+	var oldRef, newRef *corev1.ObjectReference
+	if oldTemplate != nil {
+		oldRef = oldTemplate.Ref
+	}
+	if newTemplate != nil {
+		newRef = newTemplate.Ref
+	}
+	if oldRef == nil || newRef == nil {
+		return oldRef != newRef
+	}
+	return oldRef.Kind != newRef.Kind || oldRef.Name != newRef.Name
+}
+
+// rolloutAcknowledgementToken returns the token to be used for acknowledging a change to a ClusterClass;
+// the token is computed from the spec of the ClusterClass, so it is different for every change.
+func rolloutAcknowledgementToken(clusterClass *clusterv1.ClusterClass) (string, error) {
+	h, err := hash.Compute(clusterClass.Spec)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to compute rollout acknowledgement token")
+	}
+	return fmt.Sprintf("%d", h), nil
+}
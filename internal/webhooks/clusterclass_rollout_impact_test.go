@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestValidateRolloutAcknowledgement(t *testing.T) {
+	templateRef := func(apiVersion, name string) clusterv1.LocalObjectTemplate {
+		return clusterv1.LocalObjectTemplate{
+			Ref: &corev1.ObjectReference{
+				APIVersion: apiVersion,
+				Kind:       "GenericTemplate",
+				Name:       name,
+			},
+		}
+	}
+	clusterClass := func(controlPlaneTemplate, mdInfrastructureTemplate string, annotations map[string]string) *clusterv1.ClusterClass {
+		return &clusterv1.ClusterClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "class",
+				Namespace:   metav1.NamespaceDefault,
+				Annotations: annotations,
+			},
+			Spec: clusterv1.ClusterClassSpec{
+				ControlPlane: clusterv1.ControlPlaneClass{
+					LocalObjectTemplate: templateRef("controlplane.cluster.x-k8s.io/v1beta1", controlPlaneTemplate),
+				},
+				Workers: clusterv1.WorkersClass{
+					MachineDeployments: []clusterv1.MachineDeploymentClass{
+						{
+							Class: "md1",
+							Template: clusterv1.MachineDeploymentClassTemplate{
+								Bootstrap:      templateRef("bootstrap.cluster.x-k8s.io/v1beta1", "bootstrap"),
+								Infrastructure: templateRef("infrastructure.cluster.x-k8s.io/v1beta1", mdInfrastructureTemplate),
+							},
+						},
+						{
+							Class: "md2",
+							Template: clusterv1.MachineDeploymentClassTemplate{
+								Bootstrap:      templateRef("bootstrap.cluster.x-k8s.io/v1beta1", "bootstrap"),
+								Infrastructure: templateRef("infrastructure.cluster.x-k8s.io/v1beta1", "infrastructure"),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	cluster := func(name string, mdClasses ...string) clusterv1.Cluster {
+		c := clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: clusterv1.ClusterSpec{
+				Topology: &clusterv1.Topology{
+					Class:   "class",
+					Workers: &clusterv1.WorkersTopology{},
+				},
+			},
+		}
+		for _, class := range mdClasses {
+			c.Spec.Topology.Workers.MachineDeployments = append(c.Spec.Topology.Workers.MachineDeployments, clusterv1.MachineDeploymentTopology{
+				Class: class,
+				Name:  class,
+			})
+		}
+		return c
+	}
+	ackRequired := map[string]string{clusterv1.ClusterClassRolloutAcknowledgementRequiredAnnotation: ""}
+	clusters := []clusterv1.Cluster{cluster("cluster1", "md2"), cluster("cluster2", "md1", "md2")}
+
+	t.Run("does not require an acknowledgement if not enabled, but reports the impact as a warning", func(t *testing.T) {
+		g := NewWithT(t)
+
+		oldClusterClass := clusterClass("cp", "infrastructure", nil)
+		newClusterClass := clusterClass("cp-v2", "infrastructure", nil)
+		w := &rolloutImpactWarnings{}
+		g.Expect(validateRolloutAcknowledgement(context.WithValue(ctx, rolloutImpactWarningsKey{}, w), clusters, oldClusterClass, newClusterClass)).To(BeEmpty())
+		g.Expect(w.warnings).To(ConsistOf("this change rolls out machines of 2 Cluster(s): cluster1 [control plane], cluster2 [control plane]"))
+	})
+
+	t.Run("does not require an acknowledgement for changes not rolling out machines", func(t *testing.T) {
+		g := NewWithT(t)
+
+		oldClusterClass := clusterClass("cp", "infrastructure", ackRequired)
+		newClusterClass := clusterClass("cp", "infrastructure", ackRequired)
+		newClusterClass.Spec.ControlPlane.Ref.APIVersion = "controlplane.cluster.x-k8s.io/v1beta2"
+		g.Expect(validateRolloutAcknowledgement(ctx, clusters, oldClusterClass, newClusterClass)).To(BeEmpty())
+	})
+
+	t.Run("requires an acknowledgement for changes rolling out the control plane", func(t *testing.T) {
+		g := NewWithT(t)
+
+		oldClusterClass := clusterClass("cp", "infrastructure", ackRequired)
+		newClusterClass := clusterClass("cp-v2", "infrastructure", ackRequired)
+		errs := validateRolloutAcknowledgement(ctx, clusters, oldClusterClass, newClusterClass)
+		g.Expect(errs).To(HaveLen(1))
+		g.Expect(errs[0].Error()).To(ContainSubstring("rolls out machines of 2 Cluster(s): cluster1 [control plane], cluster2 [control plane]"))
+
+		token, err := rolloutAcknowledgementToken(newClusterClass)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(errs[0].Error()).To(ContainSubstring(token))
+
+		// The change is admitted once acknowledged with the token.
+		newClusterClass.Annotations = map[string]string{
+			clusterv1.ClusterClassRolloutAcknowledgementRequiredAnnotation: "",
+			clusterv1.ClusterClassRolloutAcknowledgedAnnotation:            token,
+		}
+		g.Expect(validateRolloutAcknowledgement(ctx, clusters, oldClusterClass, newClusterClass)).To(BeEmpty())
+
+		// An acknowledgement does not apply to a different change.
+		newClusterClass.Spec.ControlPlane.Ref.Name = "cp-v3"
+		g.Expect(validateRolloutAcknowledgement(ctx, clusters, oldClusterClass, newClusterClass)).To(HaveLen(1))
+	})
+
+	t.Run("requires an acknowledgement only for Clusters using changed MachineDeploymentClasses", func(t *testing.T) {
+		g := NewWithT(t)
+
+		oldClusterClass := clusterClass("cp", "infrastructure", ackRequired)
+		newClusterClass := clusterClass("cp", "infrastructure-v2", ackRequired)
+		errs := validateRolloutAcknowledgement(ctx, clusters, oldClusterClass, newClusterClass)
+		g.Expect(errs).To(HaveLen(1))
+		g.Expect(errs[0].Error()).To(ContainSubstring("rolls out machines of 1 Cluster(s): cluster2 [MachineDeploymentClass md1]"))
+	})
+}
+
+func TestRolloutImpactWarningsHandler(t *testing.T) {
+	handler := func(resp admission.Response) admission.HandlerFunc {
+		return func(ctx context.Context, _ admission.Request) admission.Response {
+			w := ctx.Value(rolloutImpactWarningsKey{}).(*rolloutImpactWarnings)
+			w.warnings = append(w.warnings, "this change rolls out machines of 1 Cluster(s): cluster1 [control plane]")
+			return resp
+		}
+	}
+
+	t.Run("returns the rollout impact as warnings if the change is allowed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		resp := (&rolloutImpactWarningsHandler{handler: handler(admission.Allowed(""))}).Handle(ctx, admission.Request{})
+		g.Expect(resp.Allowed).To(BeTrue())
+		g.Expect(resp.Warnings).To(ConsistOf("this change rolls out machines of 1 Cluster(s): cluster1 [control plane]"))
+	})
+
+	t.Run("does not return warnings if the change is denied", func(t *testing.T) {
+		g := NewWithT(t)
+
+		resp := (&rolloutImpactWarningsHandler{handler: handler(admission.Denied("not acknowledged"))}).Handle(ctx, admission.Request{})
+		g.Expect(resp.Allowed).To(BeFalse())
+		g.Expect(resp.Warnings).To(BeEmpty())
+	})
+}
@@ -1617,6 +1617,56 @@ func TestClusterClassValidationWithClusterAwareChecks(t *testing.T) {
 				Build(),
 			expectErr: false,
 		},
+		{
+			name: "pass if the revision is unique in the lineage",
+			clusters: []client.Object{
+				builder.ClusterClass(metav1.NamespaceDefault, "class-v1").WithLineage("class", 1).Build(),
+			},
+			newClusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class-v2").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "inf").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithLineage("class", 2).
+				Build(),
+			expectErr: false,
+		},
+		{
+			name: "error if the revision is already used in the lineage",
+			clusters: []client.Object{
+				builder.ClusterClass(metav1.NamespaceDefault, "class-v1").WithLineage("class", 1).Build(),
+			},
+			newClusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class-v2").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "inf").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithLineage("class", 1).
+				Build(),
+			expectErr: true,
+		},
+		{
+			name: "error if the lineage is changed",
+			oldClusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class-v1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "inf").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithLineage("class", 1).
+				Build(),
+			newClusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class-v1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "inf").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithLineage("class", 2).
+				Build(),
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1,5 +1,5 @@
 /*
-Copyright 2021 The Kubernetes Authors.
+Copyright 2023 The Kubernetes Authors.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,9 +14,10 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package v1beta1
+package webhooks
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -24,21 +25,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/nodeexpression"
 )
 
 var (
-	// DefaultNodeStartupTimeout is the time allowed for a node to start up.
-	// Can be made longer as part of spec if required for particular provider.
-	// 10 minutes should allow the instance to start and the node to join the
-	// cluster on most providers.
-	DefaultNodeStartupTimeout = metav1.Duration{Duration: 10 * time.Minute}
 	// Minimum time allowed for a node to start up.
 	minNodeStartupTimeout = metav1.Duration{Duration: 30 * time.Second}
 	// We allow users to disable the nodeStartupTimeout by setting the duration to 0.
-	disabledNodeStartupTimeout = ZeroDuration
+	disabledNodeStartupTimeout = clusterv1.ZeroDuration
 )
 
 // SetMinNodeStartupTimeout allows users to optionally set a custom timeout
@@ -50,24 +50,37 @@ func SetMinNodeStartupTimeout(d metav1.Duration) {
 	minNodeStartupTimeout = d
 }
 
-func (m *MachineHealthCheck) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// SetupWebhookWithManager sets up MachineHealthCheck webhooks.
+func (webhook *MachineHealthCheck) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
-		For(m).
+		For(&clusterv1.MachineHealthCheck{}).
+		WithDefaulter(webhook).
+		WithValidator(webhook).
 		Complete()
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-cluster-x-k8s-io-v1beta1-machinehealthcheck,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=machinehealthchecks,versions=v1beta1,name=validation.machinehealthcheck.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-cluster-x-k8s-io-v1beta1-machinehealthcheck,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=machinehealthchecks,versions=v1beta1,name=default.machinehealthcheck.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
-var _ webhook.Defaulter = &MachineHealthCheck{}
-var _ webhook.Validator = &MachineHealthCheck{}
+// MachineHealthCheck implements a validating and defaulting webhook for MachineHealthCheck.
+// NOTE: The webhook is implemented outside of the API package because validating unhealthy node expressions
+// requires a CEL compiler, which should not become a dependency of the API.
+type MachineHealthCheck struct{}
+
+var _ webhook.CustomDefaulter = &MachineHealthCheck{}
+var _ webhook.CustomValidator = &MachineHealthCheck{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type.
+func (webhook *MachineHealthCheck) Default(_ context.Context, obj runtime.Object) error {
+	m, ok := obj.(*clusterv1.MachineHealthCheck)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a MachineHealthCheck but got a %T", obj))
+	}
 
-// Default implements webhook.Defaulter so a webhook will be registered for the type.
-func (m *MachineHealthCheck) Default() {
 	if m.Labels == nil {
 		m.Labels = make(map[string]string)
 	}
-	m.Labels[ClusterNameLabel] = m.Spec.ClusterName
+	m.Labels[clusterv1.ClusterNameLabel] = m.Spec.ClusterName
 
 	if m.Spec.MaxUnhealthy == nil {
 		defaultMaxUnhealthy := intstr.FromString("100%")
@@ -75,43 +88,56 @@ func (m *MachineHealthCheck) Default() {
 	}
 
 	if m.Spec.NodeStartupTimeout == nil {
-		m.Spec.NodeStartupTimeout = &DefaultNodeStartupTimeout
+		m.Spec.NodeStartupTimeout = &clusterv1.DefaultNodeStartupTimeout
 	}
 
 	if m.Spec.RemediationTemplate != nil && m.Spec.RemediationTemplate.Namespace == "" {
 		m.Spec.RemediationTemplate.Namespace = m.Namespace
 	}
+
+	if m.Spec.Reboot != nil && m.Spec.Reboot.Timeout == nil {
+		m.Spec.Reboot.Timeout = &clusterv1.DefaultRebootTimeout
+	}
+	return nil
 }
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (m *MachineHealthCheck) ValidateCreate() error {
-	return m.validate(nil)
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (webhook *MachineHealthCheck) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	m, ok := obj.(*clusterv1.MachineHealthCheck)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a MachineHealthCheck but got a %T", obj))
+	}
+	return webhook.validate(nil, m)
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (m *MachineHealthCheck) ValidateUpdate(old runtime.Object) error {
-	mhc, ok := old.(*MachineHealthCheck)
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (webhook *MachineHealthCheck) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) error {
+	newMHC, ok := newObj.(*clusterv1.MachineHealthCheck)
 	if !ok {
-		return apierrors.NewBadRequest(fmt.Sprintf("expected a MachineHealthCheck but got a %T", old))
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a MachineHealthCheck but got a %T", newObj))
 	}
-	return m.validate(mhc)
+	oldMHC, ok := oldObj.(*clusterv1.MachineHealthCheck)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a MachineHealthCheck but got a %T", oldObj))
+	}
+	return webhook.validate(oldMHC, newMHC)
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
-func (m *MachineHealthCheck) ValidateDelete() error {
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (webhook *MachineHealthCheck) ValidateDelete(_ context.Context, _ runtime.Object) error {
 	return nil
 }
 
-func (m *MachineHealthCheck) validate(old *MachineHealthCheck) error {
+func (webhook *MachineHealthCheck) validate(oldMHC, newMHC *clusterv1.MachineHealthCheck) error {
 	var allErrs field.ErrorList
 	specPath := field.NewPath("spec")
 
 	// Validate selector parses as Selector
-	selector, err := metav1.LabelSelectorAsSelector(&m.Spec.Selector)
+	selector, err := metav1.LabelSelectorAsSelector(&newMHC.Spec.Selector)
 	if err != nil {
 		allErrs = append(
 			allErrs,
-			field.Invalid(specPath.Child("selector"), m.Spec.Selector, err.Error()),
+			field.Invalid(specPath.Child("selector"), newMHC.Spec.Selector, err.Error()),
 		)
 	}
 
@@ -123,30 +149,30 @@ func (m *MachineHealthCheck) validate(old *MachineHealthCheck) error {
 		)
 	}
 
-	if clusterName, ok := m.Spec.Selector.MatchLabels[ClusterNameLabel]; ok && clusterName != m.Spec.ClusterName {
+	if clusterName, ok := newMHC.Spec.Selector.MatchLabels[clusterv1.ClusterNameLabel]; ok && clusterName != newMHC.Spec.ClusterName {
 		allErrs = append(
 			allErrs,
-			field.Invalid(specPath.Child("selector"), m.Spec.Selector, "cannot specify a cluster selector other than the one specified by ClusterName"))
+			field.Invalid(specPath.Child("selector"), newMHC.Spec.Selector, "cannot specify a cluster selector other than the one specified by ClusterName"))
 	}
 
-	if old != nil && old.Spec.ClusterName != m.Spec.ClusterName {
+	if oldMHC != nil && oldMHC.Spec.ClusterName != newMHC.Spec.ClusterName {
 		allErrs = append(
 			allErrs,
 			field.Forbidden(specPath.Child("clusterName"), "field is immutable"),
 		)
 	}
 
-	allErrs = append(allErrs, m.ValidateCommonFields(specPath)...)
+	allErrs = append(allErrs, validateMachineHealthCheckCommonFields(newMHC, specPath)...)
 
 	if len(allErrs) == 0 {
 		return nil
 	}
-	return apierrors.NewInvalid(GroupVersion.WithKind("MachineHealthCheck").GroupKind(), m.Name, allErrs)
+	return apierrors.NewInvalid(clusterv1.GroupVersion.WithKind("MachineHealthCheck").GroupKind(), newMHC.Name, allErrs)
 }
 
-// ValidateCommonFields validates UnhealthyConditions NodeStartupTimeout, MaxUnhealthy, and RemediationTemplate of the MHC.
+// validateMachineHealthCheckCommonFields validates UnhealthyConditions, UnhealthyNodeExpressions, NodeStartupTimeout, MaxUnhealthy, RemediationTemplate and Reboot of the MHC.
 // These are the fields in common with other types which define MachineHealthChecks such as MachineHealthCheckClass and MachineHealthCheckTopology.
-func (m *MachineHealthCheck) ValidateCommonFields(fldPath *field.Path) field.ErrorList {
+func validateMachineHealthCheckCommonFields(m *clusterv1.MachineHealthCheck, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
 	if m.Spec.NodeStartupTimeout != nil &&
@@ -176,6 +202,13 @@ func (m *MachineHealthCheck) ValidateCommonFields(fldPath *field.Path) field.Err
 		)
 	}
 
+	if m.Spec.Reboot != nil && m.Spec.Reboot.Timeout != nil && m.Spec.Reboot.Timeout.Seconds() <= 0 {
+		allErrs = append(
+			allErrs,
+			field.Invalid(fldPath.Child("reboot", "timeout"), m.Spec.Reboot.Timeout.String(), "must be greater than 0"),
+		)
+	}
+
 	if len(m.Spec.UnhealthyConditions) == 0 {
 		allErrs = append(allErrs, field.Forbidden(
 			fldPath.Child("unhealthyConditions"),
@@ -183,5 +216,16 @@ func (m *MachineHealthCheck) ValidateCommonFields(fldPath *field.Path) field.Err
 		))
 	}
 
+	names := sets.Set[string]{}
+	for i, e := range m.Spec.UnhealthyNodeExpressions {
+		if names.Has(e.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("unhealthyNodeExpressions").Index(i).Child("name"), e.Name))
+		}
+		names.Insert(e.Name)
+		if _, err := nodeexpression.Compile(e.Expression); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("unhealthyNodeExpressions").Index(i).Child("expression"), e.Expression, err.Error()))
+		}
+	}
+
 	return allErrs
 }
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package v1beta1
+package webhooks
 
 import (
 	"testing"
@@ -25,21 +25,25 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
-	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/webhooks/util"
 )
 
 func TestMachineHealthCheckDefault(t *testing.T) {
+	webhook := &MachineHealthCheck{}
+
 	g := NewWithT(t)
-	mhc := &MachineHealthCheck{
+	mhc := &clusterv1.MachineHealthCheck{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "foo",
 		},
-		Spec: MachineHealthCheckSpec{
+		Spec: clusterv1.MachineHealthCheckSpec{
 			Selector: metav1.LabelSelector{
 				MatchLabels: map[string]string{"foo": "bar"},
 			},
 			RemediationTemplate: &corev1.ObjectReference{},
-			UnhealthyConditions: []UnhealthyCondition{
+			Reboot:              &clusterv1.MachineHealthCheckReboot{},
+			UnhealthyConditions: []clusterv1.UnhealthyCondition{
 				{
 					Type:   corev1.NodeReady,
 					Status: corev1.ConditionFalse,
@@ -47,17 +51,21 @@ func TestMachineHealthCheckDefault(t *testing.T) {
 			},
 		},
 	}
-	t.Run("for MachineHealthCheck", utildefaulting.DefaultValidateTest(mhc))
-	mhc.Default()
+	t.Run("for MachineHealthCheck", util.CustomDefaultValidateTest(ctx, mhc, webhook))
+	g.Expect(webhook.Default(ctx, mhc)).To(Succeed())
 
-	g.Expect(mhc.Labels[ClusterNameLabel]).To(Equal(mhc.Spec.ClusterName))
+	g.Expect(mhc.Labels[clusterv1.ClusterNameLabel]).To(Equal(mhc.Spec.ClusterName))
 	g.Expect(mhc.Spec.MaxUnhealthy.String()).To(Equal("100%"))
 	g.Expect(mhc.Spec.NodeStartupTimeout).ToNot(BeNil())
 	g.Expect(*mhc.Spec.NodeStartupTimeout).To(Equal(metav1.Duration{Duration: 10 * time.Minute}))
 	g.Expect(mhc.Spec.RemediationTemplate.Namespace).To(Equal(mhc.Namespace))
+	g.Expect(mhc.Spec.Reboot.Timeout).ToNot(BeNil())
+	g.Expect(*mhc.Spec.Reboot.Timeout).To(Equal(metav1.Duration{Duration: 10 * time.Minute}))
 }
 
 func TestMachineHealthCheckLabelSelectorAsSelectorValidation(t *testing.T) {
+	webhook := &MachineHealthCheck{}
+
 	tests := []struct {
 		name      string
 		selectors map[string]string
@@ -78,12 +86,12 @@ func TestMachineHealthCheckLabelSelectorAsSelectorValidation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
-			mhc := &MachineHealthCheck{
-				Spec: MachineHealthCheckSpec{
+			mhc := &clusterv1.MachineHealthCheck{
+				Spec: clusterv1.MachineHealthCheckSpec{
 					Selector: metav1.LabelSelector{
 						MatchLabels: tt.selectors,
 					},
-					UnhealthyConditions: []UnhealthyCondition{
+					UnhealthyConditions: []clusterv1.UnhealthyCondition{
 						{
 							Type:   corev1.NodeReady,
 							Status: corev1.ConditionFalse,
@@ -92,17 +100,19 @@ func TestMachineHealthCheckLabelSelectorAsSelectorValidation(t *testing.T) {
 				},
 			}
 			if tt.expectErr {
-				g.Expect(mhc.ValidateCreate()).NotTo(Succeed())
-				g.Expect(mhc.ValidateUpdate(mhc)).NotTo(Succeed())
+				g.Expect(webhook.ValidateCreate(ctx, mhc)).NotTo(Succeed())
+				g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).NotTo(Succeed())
 			} else {
-				g.Expect(mhc.ValidateCreate()).To(Succeed())
-				g.Expect(mhc.ValidateUpdate(mhc)).To(Succeed())
+				g.Expect(webhook.ValidateCreate(ctx, mhc)).To(Succeed())
+				g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).To(Succeed())
 			}
 		})
 	}
 }
 
 func TestMachineHealthCheckClusterNameImmutable(t *testing.T) {
+	webhook := &MachineHealthCheck{}
+
 	tests := []struct {
 		name           string
 		oldClusterName string
@@ -127,15 +137,15 @@ func TestMachineHealthCheckClusterNameImmutable(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			newMHC := &MachineHealthCheck{
-				Spec: MachineHealthCheckSpec{
+			newMHC := &clusterv1.MachineHealthCheck{
+				Spec: clusterv1.MachineHealthCheckSpec{
 					ClusterName: tt.newClusterName,
 					Selector: metav1.LabelSelector{
 						MatchLabels: map[string]string{
 							"test": "test",
 						},
 					},
-					UnhealthyConditions: []UnhealthyCondition{
+					UnhealthyConditions: []clusterv1.UnhealthyCondition{
 						{
 							Type:   corev1.NodeReady,
 							Status: corev1.ConditionFalse,
@@ -143,15 +153,15 @@ func TestMachineHealthCheckClusterNameImmutable(t *testing.T) {
 					},
 				},
 			}
-			oldMHC := &MachineHealthCheck{
-				Spec: MachineHealthCheckSpec{
+			oldMHC := &clusterv1.MachineHealthCheck{
+				Spec: clusterv1.MachineHealthCheckSpec{
 					ClusterName: tt.oldClusterName,
 					Selector: metav1.LabelSelector{
 						MatchLabels: map[string]string{
 							"test": "test",
 						},
 					},
-					UnhealthyConditions: []UnhealthyCondition{
+					UnhealthyConditions: []clusterv1.UnhealthyCondition{
 						{
 							Type:   corev1.NodeReady,
 							Status: corev1.ConditionFalse,
@@ -161,23 +171,25 @@ func TestMachineHealthCheckClusterNameImmutable(t *testing.T) {
 			}
 
 			if tt.expectErr {
-				g.Expect(newMHC.ValidateUpdate(oldMHC)).NotTo(Succeed())
+				g.Expect(webhook.ValidateUpdate(ctx, oldMHC, newMHC)).NotTo(Succeed())
 			} else {
-				g.Expect(newMHC.ValidateUpdate(oldMHC)).To(Succeed())
+				g.Expect(webhook.ValidateUpdate(ctx, oldMHC, newMHC)).To(Succeed())
 			}
 		})
 	}
 }
 
 func TestMachineHealthCheckUnhealthyConditions(t *testing.T) {
+	webhook := &MachineHealthCheck{}
+
 	tests := []struct {
 		name               string
-		unhealthConditions []UnhealthyCondition
+		unhealthConditions []clusterv1.UnhealthyCondition
 		expectErr          bool
 	}{
 		{
 			name: "pass with correctly defined unhealthyConditions",
-			unhealthConditions: []UnhealthyCondition{
+			unhealthConditions: []clusterv1.UnhealthyCondition{
 				{
 					Type:   corev1.NodeReady,
 					Status: corev1.ConditionFalse,
@@ -192,7 +204,7 @@ func TestMachineHealthCheckUnhealthyConditions(t *testing.T) {
 		},
 		{
 			name:               "fail if the UnhealthCondition array is empty",
-			unhealthConditions: []UnhealthyCondition{},
+			unhealthConditions: []clusterv1.UnhealthyCondition{},
 			expectErr:          true,
 		},
 	}
@@ -200,8 +212,8 @@ func TestMachineHealthCheckUnhealthyConditions(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
-			mhc := &MachineHealthCheck{
-				Spec: MachineHealthCheckSpec{
+			mhc := &clusterv1.MachineHealthCheck{
+				Spec: clusterv1.MachineHealthCheckSpec{
 					Selector: metav1.LabelSelector{
 						MatchLabels: map[string]string{
 							"test": "test",
@@ -211,17 +223,19 @@ func TestMachineHealthCheckUnhealthyConditions(t *testing.T) {
 				},
 			}
 			if tt.expectErr {
-				g.Expect(mhc.ValidateCreate()).NotTo(Succeed())
-				g.Expect(mhc.ValidateUpdate(mhc)).NotTo(Succeed())
+				g.Expect(webhook.ValidateCreate(ctx, mhc)).NotTo(Succeed())
+				g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).NotTo(Succeed())
 			} else {
-				g.Expect(mhc.ValidateCreate()).To(Succeed())
-				g.Expect(mhc.ValidateUpdate(mhc)).To(Succeed())
+				g.Expect(webhook.ValidateCreate(ctx, mhc)).To(Succeed())
+				g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).To(Succeed())
 			}
 		})
 	}
 }
 
 func TestMachineHealthCheckNodeStartupTimeout(t *testing.T) {
+	webhook := &MachineHealthCheck{}
+
 	zero := metav1.Duration{Duration: 0}
 	twentyNineSeconds := metav1.Duration{Duration: 29 * time.Second}
 	thirtySeconds := metav1.Duration{Duration: 30 * time.Second}
@@ -268,15 +282,15 @@ func TestMachineHealthCheckNodeStartupTimeout(t *testing.T) {
 	for _, tt := range tests {
 		g := NewWithT(t)
 
-		mhc := &MachineHealthCheck{
-			Spec: MachineHealthCheckSpec{
+		mhc := &clusterv1.MachineHealthCheck{
+			Spec: clusterv1.MachineHealthCheckSpec{
 				NodeStartupTimeout: tt.timeout,
 				Selector: metav1.LabelSelector{
 					MatchLabels: map[string]string{
 						"test": "test",
 					},
 				},
-				UnhealthyConditions: []UnhealthyCondition{
+				UnhealthyConditions: []clusterv1.UnhealthyCondition{
 					{
 						Type:   corev1.NodeReady,
 						Status: corev1.ConditionFalse,
@@ -286,16 +300,166 @@ func TestMachineHealthCheckNodeStartupTimeout(t *testing.T) {
 		}
 
 		if tt.expectErr {
-			g.Expect(mhc.ValidateCreate()).NotTo(Succeed())
-			g.Expect(mhc.ValidateUpdate(mhc)).NotTo(Succeed())
+			g.Expect(webhook.ValidateCreate(ctx, mhc)).NotTo(Succeed())
+			g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).NotTo(Succeed())
 		} else {
-			g.Expect(mhc.ValidateCreate()).To(Succeed())
-			g.Expect(mhc.ValidateUpdate(mhc)).To(Succeed())
+			g.Expect(webhook.ValidateCreate(ctx, mhc)).To(Succeed())
+			g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).To(Succeed())
 		}
 	}
 }
 
+func TestMachineHealthCheckRebootTimeout(t *testing.T) {
+	webhook := &MachineHealthCheck{}
+
+	zero := metav1.Duration{Duration: 0}
+	oneMinute := metav1.Duration{Duration: 1 * time.Minute}
+	minusOneMinute := metav1.Duration{Duration: -1 * time.Minute}
+
+	tests := []struct {
+		name      string
+		reboot    *clusterv1.MachineHealthCheckReboot
+		expectErr bool
+	}{
+		{
+			name:      "when reboot is not given",
+			reboot:    nil,
+			expectErr: false,
+		},
+		{
+			name:      "when the reboot timeout is not given",
+			reboot:    &clusterv1.MachineHealthCheckReboot{},
+			expectErr: false,
+		},
+		{
+			name:      "when the reboot timeout is greater than 0",
+			reboot:    &clusterv1.MachineHealthCheckReboot{Timeout: &oneMinute},
+			expectErr: false,
+		},
+		{
+			name:      "when the reboot timeout is 0",
+			reboot:    &clusterv1.MachineHealthCheckReboot{Timeout: &zero},
+			expectErr: true,
+		},
+		{
+			name:      "when the reboot timeout is less than 0",
+			reboot:    &clusterv1.MachineHealthCheckReboot{Timeout: &minusOneMinute},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mhc := &clusterv1.MachineHealthCheck{
+				Spec: clusterv1.MachineHealthCheckSpec{
+					Reboot: tt.reboot,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+					UnhealthyConditions: []clusterv1.UnhealthyCondition{
+						{
+							Type:   corev1.NodeReady,
+							Status: corev1.ConditionFalse,
+						},
+					},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(webhook.ValidateCreate(ctx, mhc)).NotTo(Succeed())
+				g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).NotTo(Succeed())
+			} else {
+				g.Expect(webhook.ValidateCreate(ctx, mhc)).To(Succeed())
+				g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestMachineHealthCheckUnhealthyNodeExpressions(t *testing.T) {
+	webhook := &MachineHealthCheck{}
+
+	tests := []struct {
+		name        string
+		expressions []clusterv1.UnhealthyNodeExpression
+		expectErr   bool
+	}{
+		{
+			name:        "when no expressions are given",
+			expressions: nil,
+			expectErr:   false,
+		},
+		{
+			name: "when valid expressions are given",
+			expressions: []clusterv1.UnhealthyNodeExpression{
+				{Name: "low-ephemeral-storage", Expression: "quantity(node.status.allocatable['ephemeral-storage']) < quantity('10Gi')"},
+				{Name: "kubelet-version", Expression: "node.status.nodeInfo.kubeletVersion != 'v1.27.1'"},
+			},
+			expectErr: false,
+		},
+		{
+			name: "when an expression is invalid",
+			expressions: []clusterv1.UnhealthyNodeExpression{
+				{Name: "invalid", Expression: "node.status.("},
+			},
+			expectErr: true,
+		},
+		{
+			name: "when an expression does not evaluate to a bool",
+			expressions: []clusterv1.UnhealthyNodeExpression{
+				{Name: "not-a-bool", Expression: "'foo'"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "when expression names are duplicated",
+			expressions: []clusterv1.UnhealthyNodeExpression{
+				{Name: "duplicated", Expression: "true"},
+				{Name: "duplicated", Expression: "false"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mhc := &clusterv1.MachineHealthCheck{
+				Spec: clusterv1.MachineHealthCheckSpec{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+					UnhealthyConditions: []clusterv1.UnhealthyCondition{
+						{
+							Type:   corev1.NodeReady,
+							Status: corev1.ConditionFalse,
+						},
+					},
+					UnhealthyNodeExpressions: tt.expressions,
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(webhook.ValidateCreate(ctx, mhc)).NotTo(Succeed())
+				g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).NotTo(Succeed())
+			} else {
+				g.Expect(webhook.ValidateCreate(ctx, mhc)).To(Succeed())
+				g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).To(Succeed())
+			}
+		})
+	}
+}
+
 func TestMachineHealthCheckMaxUnhealthy(t *testing.T) {
+	webhook := &MachineHealthCheck{}
+
 	tests := []struct {
 		name      string
 		value     intstr.IntOrString
@@ -327,15 +491,15 @@ func TestMachineHealthCheckMaxUnhealthy(t *testing.T) {
 		g := NewWithT(t)
 
 		maxUnhealthy := tt.value
-		mhc := &MachineHealthCheck{
-			Spec: MachineHealthCheckSpec{
+		mhc := &clusterv1.MachineHealthCheck{
+			Spec: clusterv1.MachineHealthCheckSpec{
 				MaxUnhealthy: &maxUnhealthy,
 				Selector: metav1.LabelSelector{
 					MatchLabels: map[string]string{
 						"test": "test",
 					},
 				},
-				UnhealthyConditions: []UnhealthyCondition{
+				UnhealthyConditions: []clusterv1.UnhealthyCondition{
 					{
 						Type:   corev1.NodeReady,
 						Status: corev1.ConditionFalse,
@@ -345,20 +509,22 @@ func TestMachineHealthCheckMaxUnhealthy(t *testing.T) {
 		}
 
 		if tt.expectErr {
-			g.Expect(mhc.ValidateCreate()).NotTo(Succeed())
-			g.Expect(mhc.ValidateUpdate(mhc)).NotTo(Succeed())
+			g.Expect(webhook.ValidateCreate(ctx, mhc)).NotTo(Succeed())
+			g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).NotTo(Succeed())
 		} else {
-			g.Expect(mhc.ValidateCreate()).To(Succeed())
-			g.Expect(mhc.ValidateUpdate(mhc)).To(Succeed())
+			g.Expect(webhook.ValidateCreate(ctx, mhc)).To(Succeed())
+			g.Expect(webhook.ValidateUpdate(ctx, mhc, mhc)).To(Succeed())
 		}
 	}
 }
 
 func TestMachineHealthCheckSelectorValidation(t *testing.T) {
+	webhook := &MachineHealthCheck{}
+
 	g := NewWithT(t)
-	mhc := &MachineHealthCheck{
-		Spec: MachineHealthCheckSpec{
-			UnhealthyConditions: []UnhealthyCondition{
+	mhc := &clusterv1.MachineHealthCheck{
+		Spec: clusterv1.MachineHealthCheckSpec{
+			UnhealthyConditions: []clusterv1.UnhealthyCondition{
 				{
 					Type:   corev1.NodeReady,
 					Status: corev1.ConditionFalse,
@@ -366,23 +532,25 @@ func TestMachineHealthCheckSelectorValidation(t *testing.T) {
 			},
 		},
 	}
-	err := mhc.validate(nil)
+	err := webhook.validate(nil, mhc)
 	g.Expect(err).ToNot(BeNil())
 	g.Expect(err.Error()).To(ContainSubstring("selector must not be empty"))
 }
 
 func TestMachineHealthCheckClusterNameSelectorValidation(t *testing.T) {
+	webhook := &MachineHealthCheck{}
+
 	g := NewWithT(t)
-	mhc := &MachineHealthCheck{
-		Spec: MachineHealthCheckSpec{
+	mhc := &clusterv1.MachineHealthCheck{
+		Spec: clusterv1.MachineHealthCheckSpec{
 			ClusterName: "foo",
 			Selector: metav1.LabelSelector{
 				MatchLabels: map[string]string{
-					ClusterNameLabel: "bar",
-					"baz":            "qux",
+					clusterv1.ClusterNameLabel: "bar",
+					"baz":                      "qux",
 				},
 			},
-			UnhealthyConditions: []UnhealthyCondition{
+			UnhealthyConditions: []clusterv1.UnhealthyCondition{
 				{
 					Type:   corev1.NodeReady,
 					Status: corev1.ConditionFalse,
@@ -390,25 +558,27 @@ func TestMachineHealthCheckClusterNameSelectorValidation(t *testing.T) {
 			},
 		},
 	}
-	err := mhc.validate(nil)
+	err := webhook.validate(nil, mhc)
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(err.Error()).To(ContainSubstring("cannot specify a cluster selector other than the one specified by ClusterName"))
 
-	mhc.Spec.Selector.MatchLabels[ClusterNameLabel] = "foo"
-	g.Expect(mhc.validate(nil)).To(Succeed())
-	delete(mhc.Spec.Selector.MatchLabels, ClusterNameLabel)
-	g.Expect(mhc.validate(nil)).To(Succeed())
+	mhc.Spec.Selector.MatchLabels[clusterv1.ClusterNameLabel] = "foo"
+	g.Expect(webhook.validate(nil, mhc)).To(Succeed())
+	delete(mhc.Spec.Selector.MatchLabels, clusterv1.ClusterNameLabel)
+	g.Expect(webhook.validate(nil, mhc)).To(Succeed())
 }
 
 func TestMachineHealthCheckRemediationTemplateNamespaceValidation(t *testing.T) {
-	valid := &MachineHealthCheck{
+	webhook := &MachineHealthCheck{}
+
+	valid := &clusterv1.MachineHealthCheck{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "foo",
 		},
-		Spec: MachineHealthCheckSpec{
+		Spec: clusterv1.MachineHealthCheckSpec{
 			Selector:            metav1.LabelSelector{MatchLabels: map[string]string{"foo": "bar"}},
 			RemediationTemplate: &corev1.ObjectReference{Namespace: "foo"},
-			UnhealthyConditions: []UnhealthyCondition{
+			UnhealthyConditions: []clusterv1.UnhealthyCondition{
 				{
 					Type:   corev1.NodeReady,
 					Status: corev1.ConditionFalse,
@@ -422,7 +592,7 @@ func TestMachineHealthCheckRemediationTemplateNamespaceValidation(t *testing.T)
 	tests := []struct {
 		name      string
 		expectErr bool
-		c         *MachineHealthCheck
+		c         *clusterv1.MachineHealthCheck
 	}{
 		{
 			name:      "should return error when MachineHealthCheck namespace and RemediationTemplate ref namespace mismatch",
@@ -441,9 +611,9 @@ func TestMachineHealthCheckRemediationTemplateNamespaceValidation(t *testing.T)
 			g := NewWithT(t)
 
 			if tt.expectErr {
-				g.Expect(tt.c.validate(nil)).NotTo(Succeed())
+				g.Expect(webhook.validate(nil, tt.c)).NotTo(Succeed())
 			} else {
-				g.Expect(tt.c.validate(nil)).To(Succeed())
+				g.Expect(webhook.validate(nil, tt.c)).To(Succeed())
 			}
 		})
 	}
@@ -25,6 +25,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
@@ -234,5 +235,71 @@ func validateExtensionConfigSpec(e *runtimev1.ExtensionConfig) field.ErrorList {
 			err.Error(),
 		))
 	}
+
+	if e.Spec.DiscoveryCacheTTLSeconds != nil && *e.Spec.DiscoveryCacheTTLSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(
+			specPath.Child("discoveryCacheTTLSeconds"),
+			*e.Spec.DiscoveryCacheTTLSeconds,
+			"must be greater than or equal to 0",
+		))
+	}
+
+	allErrs = append(allErrs, validateHandlerPolicies(e.Spec.HandlerPolicies, specPath.Child("handlerPolicies"))...)
+	return allErrs
+}
+
+func validateHandlerPolicies(policies []runtimev1.ExtensionHandlerPolicy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := sets.Set[string]{}
+	for i, policy := range policies {
+		policyPath := fldPath.Index(i)
+
+		if policy.Name == "" {
+			allErrs = append(allErrs, field.Required(
+				policyPath.Child("name"),
+				"must not be empty",
+			))
+		} else if names.Has(policy.Name) {
+			allErrs = append(allErrs, field.Duplicate(
+				policyPath.Child("name"),
+				policy.Name,
+			))
+		}
+		names.Insert(policy.Name)
+
+		if policy.TimeoutSeconds != nil && (*policy.TimeoutSeconds < 0 || *policy.TimeoutSeconds > 30) {
+			allErrs = append(allErrs, field.Invalid(
+				policyPath.Child("timeoutSeconds"),
+				*policy.TimeoutSeconds,
+				"must be between 0 and 30",
+			))
+		}
+
+		if policy.FailurePolicy != nil && *policy.FailurePolicy != runtimev1.FailurePolicyIgnore && *policy.FailurePolicy != runtimev1.FailurePolicyFail {
+			allErrs = append(allErrs, field.NotSupported(
+				policyPath.Child("failurePolicy"),
+				*policy.FailurePolicy,
+				[]string{string(runtimev1.FailurePolicyIgnore), string(runtimev1.FailurePolicyFail)},
+			))
+		}
+
+		if policy.RetryPolicy != nil {
+			if policy.RetryPolicy.MaxRetries < 0 || policy.RetryPolicy.MaxRetries > 5 {
+				allErrs = append(allErrs, field.Invalid(
+					policyPath.Child("retryPolicy", "maxRetries"),
+					policy.RetryPolicy.MaxRetries,
+					"must be between 0 and 5",
+				))
+			}
+			if policy.RetryPolicy.BackoffSeconds != nil && (*policy.RetryPolicy.BackoffSeconds < 1 || *policy.RetryPolicy.BackoffSeconds > 30) {
+				allErrs = append(allErrs, field.Invalid(
+					policyPath.Child("retryPolicy", "backoffSeconds"),
+					*policy.RetryPolicy.BackoffSeconds,
+					"must be between 1 and 30",
+				))
+			}
+		}
+	}
 	return allErrs
 }
@@ -215,6 +215,44 @@ func TestExtensionConfigValidate(t *testing.T) {
 		},
 	}
 
+	failurePolicyIgnore := runtimev1.FailurePolicyIgnore
+	extensionWithValidHandlerPolicies := extensionWithService.DeepCopy()
+	extensionWithValidHandlerPolicies.Spec.DiscoveryCacheTTLSeconds = pointer.Int32(300)
+	extensionWithValidHandlerPolicies.Spec.HandlerPolicies = []runtimev1.ExtensionHandlerPolicy{
+		{
+			Name:           "before-cluster-upgrade",
+			TimeoutSeconds: pointer.Int32(30),
+			FailurePolicy:  &failurePolicyIgnore,
+			RetryPolicy: &runtimev1.RetryPolicy{
+				MaxRetries:     3,
+				BackoffSeconds: pointer.Int32(2),
+			},
+		},
+		{
+			Name:          "after-control-plane-upgrade",
+			FailurePolicy: &failurePolicyIgnore,
+		},
+	}
+
+	extensionWithDuplicateHandlerPolicies := extensionWithValidHandlerPolicies.DeepCopy()
+	extensionWithDuplicateHandlerPolicies.Spec.HandlerPolicies[1].Name = "before-cluster-upgrade"
+
+	extensionWithInvalidHandlerPolicyTimeout := extensionWithValidHandlerPolicies.DeepCopy()
+	extensionWithInvalidHandlerPolicyTimeout.Spec.HandlerPolicies[0].TimeoutSeconds = pointer.Int32(31)
+
+	extensionWithInvalidHandlerPolicyFailurePolicy := extensionWithValidHandlerPolicies.DeepCopy()
+	unknownFailurePolicy := runtimev1.FailurePolicy("Unknown")
+	extensionWithInvalidHandlerPolicyFailurePolicy.Spec.HandlerPolicies[0].FailurePolicy = &unknownFailurePolicy
+
+	extensionWithInvalidHandlerPolicyRetries := extensionWithValidHandlerPolicies.DeepCopy()
+	extensionWithInvalidHandlerPolicyRetries.Spec.HandlerPolicies[0].RetryPolicy.MaxRetries = 6
+
+	extensionWithInvalidHandlerPolicyBackoff := extensionWithValidHandlerPolicies.DeepCopy()
+	extensionWithInvalidHandlerPolicyBackoff.Spec.HandlerPolicies[0].RetryPolicy.BackoffSeconds = pointer.Int32(0)
+
+	extensionWithInvalidDiscoveryCacheTTL := extensionWithService.DeepCopy()
+	extensionWithInvalidDiscoveryCacheTTL.Spec.DiscoveryCacheTTLSeconds = pointer.Int32(-1)
+
 	tests := []struct {
 		name        string
 		in          *runtimev1.ExtensionConfig
@@ -327,6 +365,48 @@ func TestExtensionConfigValidate(t *testing.T) {
 			featureGate: true,
 			expectErr:   false,
 		},
+		{
+			name:        "creation should pass if handlerPolicies are valid",
+			in:          extensionWithValidHandlerPolicies,
+			featureGate: true,
+			expectErr:   false,
+		},
+		{
+			name:        "creation should fail if handlerPolicies have duplicate names",
+			in:          extensionWithDuplicateHandlerPolicies,
+			featureGate: true,
+			expectErr:   true,
+		},
+		{
+			name:        "creation should fail if a handlerPolicy timeoutSeconds is invalid",
+			in:          extensionWithInvalidHandlerPolicyTimeout,
+			featureGate: true,
+			expectErr:   true,
+		},
+		{
+			name:        "creation should fail if a handlerPolicy failurePolicy is invalid",
+			in:          extensionWithInvalidHandlerPolicyFailurePolicy,
+			featureGate: true,
+			expectErr:   true,
+		},
+		{
+			name:        "creation should fail if a handlerPolicy maxRetries is invalid",
+			in:          extensionWithInvalidHandlerPolicyRetries,
+			featureGate: true,
+			expectErr:   true,
+		},
+		{
+			name:        "creation should fail if a handlerPolicy backoffSeconds is invalid",
+			in:          extensionWithInvalidHandlerPolicyBackoff,
+			featureGate: true,
+			expectErr:   true,
+		},
+		{
+			name:        "creation should fail if discoveryCacheTTLSeconds is negative",
+			in:          extensionWithInvalidDiscoveryCacheTTL,
+			featureGate: true,
+			expectErr:   true,
+		},
 	}
 
 	for _, tt := range tests {
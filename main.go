@@ -28,6 +28,7 @@ import (
 	"time"
 
 	// +kubebuilder:scaffold:imports
+	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -45,6 +46,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	clusterv1alpha3 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	clusterv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
@@ -61,12 +63,15 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	expcontrollers "sigs.k8s.io/cluster-api/exp/controllers"
 	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	ipamcontrollers "sigs.k8s.io/cluster-api/exp/ipam/controllers"
 	expipamwebhooks "sigs.k8s.io/cluster-api/exp/ipam/webhooks"
 	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimecontrollers "sigs.k8s.io/cluster-api/exp/runtime/controllers"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/metrics/state"
+	"sigs.k8s.io/cluster-api/internal/profiling"
 	runtimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
 	runtimeregistry "sigs.k8s.io/cluster-api/internal/runtime/registry"
 	runtimewebhooks "sigs.k8s.io/cluster-api/internal/webhooks/runtime"
@@ -81,30 +86,40 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 
 	// flags.
-	metricsBindAddr               string
-	enableLeaderElection          bool
-	leaderElectionLeaseDuration   time.Duration
-	leaderElectionRenewDeadline   time.Duration
-	leaderElectionRetryPeriod     time.Duration
-	watchNamespace                string
-	watchFilterValue              string
-	profilerAddress               string
-	clusterTopologyConcurrency    int
-	clusterClassConcurrency       int
-	clusterConcurrency            int
-	extensionConfigConcurrency    int
-	machineConcurrency            int
-	machineSetConcurrency         int
-	machineDeploymentConcurrency  int
-	machinePoolConcurrency        int
-	clusterResourceSetConcurrency int
-	machineHealthCheckConcurrency int
-	syncPeriod                    time.Duration
-	webhookPort                   int
-	webhookCertDir                string
-	healthAddr                    string
-	tlsOptions                    = flags.TLSOptions{}
-	logOptions                    = logs.NewOptions()
+	metricsBindAddr                   string
+	enableLeaderElection              bool
+	leaderElectionLeaseDuration       time.Duration
+	leaderElectionRenewDeadline       time.Duration
+	leaderElectionRetryPeriod         time.Duration
+	watchNamespace                    string
+	watchFilterValue                  string
+	profilerAddress                   string
+	restConfigQPS                     float32
+	restConfigBurst                   int
+	clusterCacheTrackerClientQPS      float32
+	clusterCacheTrackerClientBurst    int
+	clusterTopologyConcurrency        int
+	clusterClassConcurrency           int
+	clusterConcurrency                int
+	extensionConfigConcurrency        int
+	machineConcurrency                int
+	machineSetConcurrency             int
+	machineDeploymentConcurrency      int
+	machinePoolConcurrency            int
+	clusterResourceSetConcurrency     int
+	machineHealthCheckConcurrency     int
+	inClusterIPPoolConcurrency        int
+	additionalSyncMachineLabelDomains []string
+	machineNodeSnapshotTTL            time.Duration
+	syncPeriod                        time.Duration
+	webhookPort                       int
+	webhookCertDir                    string
+	healthAddr                        string
+	enableStateMetrics                bool
+	enableReconcileProfiling          bool
+	reconcileProfilingRetention       time.Duration
+	tlsOptions                        = flags.TLSOptions{}
+	logOptions                        = logs.NewOptions()
 )
 
 func init() {
@@ -160,6 +175,18 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&profilerAddress, "profiler-address", "",
 		"Bind address to expose the pprof profiler (e.g. localhost:6060)")
 
+	fs.Float32Var(&restConfigQPS, "kube-api-qps", 20,
+		"Maximum queries per second from the controller client to the Kubernetes API server of the management cluster.")
+
+	fs.IntVar(&restConfigBurst, "kube-api-burst", 30,
+		"Maximum number of queries that should be allowed in one burst from the controller client to the Kubernetes API server of the management cluster.")
+
+	fs.Float32Var(&clusterCacheTrackerClientQPS, "clustercachetracker-client-qps", 0,
+		fmt.Sprintf("Maximum queries per second from the cluster cache tracker clients to the Kubernetes API server of workload clusters. If unset, the client-go default is used. Can be overridden per Cluster with the %s annotation.", clusterv1.RemoteClientQPSAnnotation))
+
+	fs.IntVar(&clusterCacheTrackerClientBurst, "clustercachetracker-client-burst", 0,
+		fmt.Sprintf("Maximum number of queries that should be allowed in one burst from the cluster cache tracker clients to the Kubernetes API server of workload clusters. If unset, the client-go default is used. Can be overridden per Cluster with the %s annotation.", clusterv1.RemoteClientBurstAnnotation))
+
 	fs.IntVar(&clusterTopologyConcurrency, "clustertopology-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
@@ -190,6 +217,15 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&machineHealthCheckConcurrency, "machinehealthcheck-concurrency", 10,
 		"Number of machine health checks to process simultaneously")
 
+	fs.IntVar(&inClusterIPPoolConcurrency, "inclusterippool-concurrency", 10,
+		"Number of in-cluster IP pools and IP address claims to process simultaneously")
+
+	fs.StringSliceVar(&additionalSyncMachineLabelDomains, "additional-sync-machine-label-domains", []string{},
+		"Comma-separated list of additional label domains, e.g. example.com, which are synced from Machines to Nodes together with their subdomains. Labels in the node-role.kubernetes.io, node-restriction.kubernetes.io and node.cluster.x-k8s.io domains are always synced.")
+
+	fs.DurationVar(&machineNodeSnapshotTTL, "machine-node-snapshot-ttl", 0,
+		"The duration for which the last-known state of the Node of a deleted Machine is retained in a ConfigMap for post-mortem investigations (e.g. 24h). Snapshots are not captured if set to 0.")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
@@ -202,6 +238,15 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
 
+	fs.BoolVar(&enableStateMetrics, "state-metrics", false,
+		"Enable exporting state metrics (phases, conditions and replica counts) for Cluster API objects on the metrics endpoint.")
+
+	fs.BoolVar(&enableReconcileProfiling, "reconcile-profiling", false,
+		fmt.Sprintf("Enable recording reconcile durations per controller and per Cluster, broken down by phase, and exposing them at %s on the profiler address. Requires --profiler-address.", profiling.HandlerPath))
+
+	fs.DurationVar(&reconcileProfilingRetention, "reconcile-profiling-retention", profiling.DefaultRetention,
+		"Duration reconcile profiles are retained for after the last recorded reconcile, e.g. the profiles of deleted Clusters. Set to 0 to retain profiles until they are reset.")
+
 	flags.AddTLSOptions(fs, &tlsOptions)
 
 	feature.MutableGates.AddFlag(fs)
@@ -223,6 +268,16 @@ func main() {
 	// klog.Background will automatically use the right logger.
 	ctrl.SetLogger(klog.Background())
 
+	if enableReconcileProfiling {
+		if profilerAddress == "" {
+			setupLog.Error(errors.New("--profiler-address is required"), "unable to enable reconcile profiling")
+			os.Exit(1)
+		}
+		profiling.Enable()
+		profiling.DefaultRecorder.SetRetention(reconcileProfilingRetention)
+		http.Handle(profiling.HandlerPath, profiling.Handler(profiling.DefaultRecorder))
+	}
+
 	if profilerAddress != "" {
 		setupLog.Info(fmt.Sprintf("Profiler listening for requests at %s", profilerAddress))
 		go func() {
@@ -234,6 +289,8 @@ func main() {
 	}
 
 	restConfig := ctrl.GetConfigOrDie()
+	restConfig.QPS = restConfigQPS
+	restConfig.Burst = restConfigBurst
 	restConfig.UserAgent = remote.DefaultClusterAPIUserAgent("cluster-api-controller-manager")
 
 	minVer := version.MinimumKubernetesVersion
@@ -292,6 +349,7 @@ func main() {
 	setupIndexes(ctx, mgr)
 	setupReconcilers(ctx, mgr)
 	setupWebhooks(mgr)
+	setupStateMetrics(mgr)
 
 	// +kubebuilder:scaffold:builder
 	setupLog.Info("starting manager", "version", version.Get().String())
@@ -327,8 +385,11 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	tracker, err := remote.NewClusterCacheTracker(
 		mgr,
 		remote.ClusterCacheTrackerOptions{
-			Log:     &log,
-			Indexes: remote.DefaultIndexes,
+			Log:                &log,
+			Indexes:            remote.DefaultIndexes,
+			ReportReachability: true,
+			ClientQPS:          clusterCacheTrackerClientQPS,
+			ClientBurst:        clusterCacheTrackerClientBurst,
 		},
 	)
 	if err != nil {
@@ -354,22 +415,22 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		})
 	}
 
-	if feature.Gates.Enabled(feature.ClusterTopology) {
-		unstructuredCachingClient, err := client.NewDelegatingClient(
-			client.NewDelegatingClientInput{
-				// Use the default client for write operations.
-				Client: mgr.GetClient(),
-				// For read operations, use the same cache used by all the controllers but ensure
-				// unstructured objects will be also cached (this does not happen with the default client).
-				CacheReader:       mgr.GetCache(),
-				CacheUnstructured: true,
-			},
-		)
-		if err != nil {
-			setupLog.Error(err, "unable to create unstructured caching client", "controller", "ClusterTopology")
-			os.Exit(1)
-		}
+	unstructuredCachingClient, err := client.NewDelegatingClient(
+		client.NewDelegatingClientInput{
+			// Use the default client for write operations.
+			Client: mgr.GetClient(),
+			// For read operations, use the same cache used by all the controllers but ensure
+			// unstructured objects will be also cached (this does not happen with the default client).
+			CacheReader:       mgr.GetCache(),
+			CacheUnstructured: true,
+		},
+	)
+	if err != nil {
+		setupLog.Error(err, "unable to create unstructured caching client")
+		os.Exit(1)
+	}
 
+	if feature.Gates.Enabled(feature.ClusterTopology) {
 		if err := (&controllers.ClusterClassReconciler{
 			Client:                    mgr.GetClient(),
 			APIReader:                 mgr.GetAPIReader(),
@@ -424,18 +485,23 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	}
 
 	if err := (&controllers.ClusterReconciler{
-		Client:           mgr.GetClient(),
-		APIReader:        mgr.GetAPIReader(),
-		WatchFilterValue: watchFilterValue,
+		Client:                    mgr.GetClient(),
+		UnstructuredCachingClient: unstructuredCachingClient,
+		APIReader:                 mgr.GetAPIReader(),
+		Tracker:                   tracker,
+		WatchFilterValue:          watchFilterValue,
 	}).SetupWithManager(ctx, mgr, concurrency(clusterConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		os.Exit(1)
 	}
 	if err := (&controllers.MachineReconciler{
-		Client:           mgr.GetClient(),
-		APIReader:        mgr.GetAPIReader(),
-		Tracker:          tracker,
-		WatchFilterValue: watchFilterValue,
+		Client:                            mgr.GetClient(),
+		APIReader:                         mgr.GetAPIReader(),
+		Tracker:                           tracker,
+		WatchFilterValue:                  watchFilterValue,
+		AdditionalSyncMachineLabelDomains: additionalSyncMachineLabelDomains,
+		NodeSnapshotTTL:                   machineNodeSnapshotTTL,
+		RuntimeClient:                     runtimeClient,
 	}).SetupWithManager(ctx, mgr, concurrency(machineConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Machine")
 		os.Exit(1)
@@ -488,6 +554,23 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		}
 	}
 
+	if feature.Gates.Enabled(feature.InClusterIPAM) {
+		if err := (&ipamcontrollers.InClusterIPPoolReconciler{
+			Client:           mgr.GetClient(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(inClusterIPPoolConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "InClusterIPPool")
+			os.Exit(1)
+		}
+		if err := (&ipamcontrollers.IPAddressClaimReconciler{
+			Client:           mgr.GetClient(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(inClusterIPPoolConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "IPAddressClaim")
+			os.Exit(1)
+		}
+	}
+
 	if err := (&controllers.MachineHealthCheckReconciler{
 		Client:           mgr.GetClient(),
 		Tracker:          tracker,
@@ -548,7 +631,7 @@ func setupWebhooks(mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
-	if err := (&clusterv1.MachineHealthCheck{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&webhooks.MachineHealthCheck{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "MachineHealthCheck")
 		os.Exit(1)
 	}
@@ -571,6 +654,33 @@ func setupWebhooks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "IPAddressClaim")
 		os.Exit(1)
 	}
+	// NOTE: InClusterIPPool is behind the InClusterIPAM feature gate flag; the webhook
+	// is going to prevent creating or updating new objects in case the feature flag is disabled.
+	if err := (&expipamwebhooks.InClusterIPPool{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "InClusterIPPool")
+		os.Exit(1)
+	}
+}
+
+func setupStateMetrics(mgr ctrl.Manager) {
+	if !enableStateMetrics {
+		return
+	}
+
+	resources := []state.Resource{
+		state.ClusterResource(),
+		state.MachineResource(),
+		state.MachineSetResource(),
+		state.MachineDeploymentResource(),
+		state.MachineHealthCheckResource(),
+	}
+	if feature.Gates.Enabled(feature.MachinePool) {
+		resources = append(resources, state.MachinePoolResource())
+	}
+	if err := ctrlmetrics.Registry.Register(state.NewCollector(mgr.GetClient(), resources...)); err != nil {
+		setupLog.Error(err, "unable to register state metrics")
+		os.Exit(1)
+	}
 }
 
 func concurrency(c int) controller.Options {
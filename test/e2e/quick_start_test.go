@@ -49,7 +49,7 @@ var _ = Describe("When following the Cluster API quick-start [PR-Blocking]", fun
 	})
 })
 
-var _ = Describe("When following the Cluster API quick-start with ClusterClass [PR-Informing] [ClusterClass]", func() {
+var _ = Describe("When following the Cluster API quick-start with ClusterClass [PR-Blocking] [ClusterClass]", func() {
 	QuickStartSpec(ctx, func() QuickStartSpecInput {
 		return QuickStartSpecInput{
 			E2EConfig:             e2eConfig,
@@ -87,6 +87,21 @@ var _ = Describe("When following the Cluster API quick-start with IPv6 [IPv6] [P
 	})
 })
 
+// NOTE: This test requires the docker network used by the management cluster to have IPv6 enabled, as it is
+// by default for the network created by kind when the Docker engine supports IPv6.
+var _ = Describe("When following the Cluster API quick-start with dual-stack [PR-Informing]", func() {
+	QuickStartSpec(ctx, func() QuickStartSpecInput {
+		return QuickStartSpecInput{
+			E2EConfig:             e2eConfig,
+			ClusterctlConfigPath:  clusterctlConfigPath,
+			BootstrapClusterProxy: bootstrapClusterProxy,
+			ArtifactFolder:        artifactFolder,
+			SkipCleanup:           skipCleanup,
+			Flavor:                pointer.String("dualstack"),
+		}
+	})
+})
+
 var _ = Describe("When following the Cluster API quick-start with Ignition", func() {
 	QuickStartSpec(ctx, func() QuickStartSpecInput {
 		return QuickStartSpecInput{
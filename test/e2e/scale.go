@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/cluster-api/test/framework/clusterctl"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+const (
+	scaleClusterCount = "CAPI_SCALE_CLUSTER_COUNT"
+	scaleConcurrency  = "CAPI_SCALE_CONCURRENCY"
+)
+
+// ScaleSpecInput is the input for ScaleSpec.
+type ScaleSpecInput struct {
+	E2EConfig             *clusterctl.E2EConfig
+	ClusterctlConfigPath  string
+	BootstrapClusterProxy framework.ClusterProxy
+	ArtifactFolder        string
+	SkipCleanup           bool
+	ControlPlaneWaiters   clusterctl.ControlPlaneWaiters
+
+	// Flavor, if specified is the template flavor used to create the clusters for testing.
+	// If not specified, the default flavor for the selected infrastructure provider is used.
+	Flavor *string
+
+	// ClusterCount is the number of clusters to create.
+	// If not specified, the value of the CAPI_SCALE_CLUSTER_COUNT variable is used, or 10 if the variable is not defined.
+	ClusterCount *int64
+
+	// Concurrency is the number of clusters created at the same time.
+	// If not specified, the value of the CAPI_SCALE_CONCURRENCY variable is used, or 5 if the variable is not defined.
+	Concurrency *int64
+
+	// ResourceUsageBudgetPerCluster, if specified, is the budget for the resources used by the controllers and the
+	// API server for each cluster created; the budget is scaled by the number of clusters before checking it.
+	ResourceUsageBudgetPerCluster *framework.ResourceUsageBudget
+}
+
+// ScaleSpec implements a scale test that creates a number of workload clusters, measuring the resources used by
+// the controllers and the requests served by the API server of the management cluster while doing so.
+func ScaleSpec(ctx context.Context, inputGetter func() ScaleSpecInput) {
+	var (
+		specName      = "scale"
+		input         ScaleSpecInput
+		namespace     *corev1.Namespace
+		cancelWatches context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		Expect(ctx).NotTo(BeNil(), "ctx is required for %s spec", specName)
+		input = inputGetter()
+		Expect(input.E2EConfig).ToNot(BeNil(), "Invalid argument. input.E2EConfig can't be nil when calling %s spec", specName)
+		Expect(input.ClusterctlConfigPath).To(BeAnExistingFile(), "Invalid argument. input.ClusterctlConfigPath must be an existing file when calling %s spec", specName)
+		Expect(input.BootstrapClusterProxy).ToNot(BeNil(), "Invalid argument. input.BootstrapClusterProxy can't be nil when calling %s spec", specName)
+		Expect(os.MkdirAll(input.ArtifactFolder, 0750)).To(Succeed(), "Invalid argument. input.ArtifactFolder can't be created for %s spec", specName)
+		Expect(input.E2EConfig.Variables).To(HaveKey(KubernetesVersion))
+
+		// Setup a Namespace where to host objects for this spec and create a watcher for the namespace events.
+		namespace, cancelWatches = setupSpecNamespace(ctx, specName, input.BootstrapClusterProxy, input.ArtifactFolder)
+	})
+
+	It("Should create many clusters within the resource usage budget", func() {
+		clusterCount := int64(10)
+		if input.ClusterCount != nil {
+			clusterCount = *input.ClusterCount
+		} else if input.E2EConfig.HasVariable(scaleClusterCount) {
+			clusterCount = *input.E2EConfig.GetInt64PtrVariable(scaleClusterCount)
+		}
+		concurrency := int64(5)
+		if input.Concurrency != nil {
+			concurrency = *input.Concurrency
+		} else if input.E2EConfig.HasVariable(scaleConcurrency) {
+			concurrency = *input.E2EConfig.GetInt64PtrVariable(scaleConcurrency)
+		}
+		Expect(clusterCount).To(BeNumerically(">", 0), "Invalid argument. The number of clusters must be greater than 0 when calling %s spec", specName)
+		Expect(concurrency).To(BeNumerically(">", 0), "Invalid argument. The concurrency must be greater than 0 when calling %s spec", specName)
+
+		flavor := clusterctl.DefaultFlavor
+		if input.Flavor != nil {
+			flavor = *input.Flavor
+		}
+
+		captureInput := framework.CaptureResourceUsageInput{
+			Lister:    input.BootstrapClusterProxy.GetClient(),
+			ClientSet: input.BootstrapClusterProxy.GetClientSet(),
+			Deployments: framework.GetControllerDeployments(ctx, framework.GetControllerDeploymentsInput{
+				Lister: input.BootstrapClusterProxy.GetClient(),
+			}),
+		}
+
+		By("Capturing the resource usage before creating the clusters")
+		before := framework.CaptureResourceUsage(ctx, captureInput)
+
+		Byf("Creating %d clusters, %d at a time", clusterCount, concurrency)
+		clusterNames := make(chan string, clusterCount)
+		for i := int64(0); i < clusterCount; i++ {
+			clusterNames <- fmt.Sprintf("%s-%s", specName, util.RandomString(6))
+		}
+		close(clusterNames)
+
+		wg := sync.WaitGroup{}
+		for i := int64(0); i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for clusterName := range clusterNames {
+					clusterctl.ApplyClusterTemplateAndWait(ctx, clusterctl.ApplyClusterTemplateAndWaitInput{
+						ClusterProxy: input.BootstrapClusterProxy,
+						ConfigCluster: clusterctl.ConfigClusterInput{
+							LogFolder:                filepath.Join(input.ArtifactFolder, "clusters", input.BootstrapClusterProxy.GetName()),
+							ClusterctlConfigPath:     input.ClusterctlConfigPath,
+							KubeconfigPath:           input.BootstrapClusterProxy.GetKubeconfigPath(),
+							InfrastructureProvider:   clusterctl.DefaultInfrastructureProvider,
+							Flavor:                   flavor,
+							Namespace:                namespace.Name,
+							ClusterName:              clusterName,
+							KubernetesVersion:        input.E2EConfig.GetVariable(KubernetesVersion),
+							ControlPlaneMachineCount: pointer.Int64(1),
+							WorkerMachineCount:       pointer.Int64(0),
+						},
+						ControlPlaneWaiters:          input.ControlPlaneWaiters,
+						WaitForClusterIntervals:      input.E2EConfig.GetIntervals(specName, "wait-cluster"),
+						WaitForControlPlaneIntervals: input.E2EConfig.GetIntervals(specName, "wait-control-plane"),
+						WaitForMachineDeployments:    input.E2EConfig.GetIntervals(specName, "wait-worker-nodes"),
+					}, &clusterctl.ApplyClusterTemplateAndWaitResult{})
+				}
+			}()
+		}
+		wg.Wait()
+
+		By("Capturing the resource usage after creating the clusters")
+		usage := framework.CaptureResourceUsage(ctx, captureInput).Sub(before)
+		framework.DumpResourceUsage(usage, filepath.Join(input.ArtifactFolder, "clusters", input.BootstrapClusterProxy.GetName(), "resource-usage.json"))
+
+		if input.ResourceUsageBudgetPerCluster != nil {
+			By("Checking the resource usage is within the budget")
+			framework.ExpectResourceUsageWithinBudget(usage, input.ResourceUsageBudgetPerCluster.Scale(float64(clusterCount)))
+		}
+
+		By("PASSED!")
+	})
+
+	AfterEach(func() {
+		Byf("Dumping all the Cluster API resources in the %q namespace", namespace.Name)
+		framework.DumpAllResources(ctx, framework.DumpAllResourcesInput{
+			Lister:    input.BootstrapClusterProxy.GetClient(),
+			Namespace: namespace.Name,
+			LogPath:   filepath.Join(input.ArtifactFolder, "clusters", input.BootstrapClusterProxy.GetName(), "resources"),
+		})
+
+		if !input.SkipCleanup {
+			Byf("Deleting all the clusters in the %q namespace", namespace.Name)
+			framework.DeleteAllClustersAndWait(ctx, framework.DeleteAllClustersAndWaitInput{
+				Client:    input.BootstrapClusterProxy.GetClient(),
+				Namespace: namespace.Name,
+			}, input.E2EConfig.GetIntervals(specName, "wait-delete-cluster")...)
+
+			Byf("Deleting namespace used for hosting the %q test spec", specName)
+			framework.DeleteNamespace(ctx, framework.DeleteNamespaceInput{
+				Deleter: input.BootstrapClusterProxy.GetClient(),
+				Name:    namespace.Name,
+			})
+		}
+		cancelWatches()
+	})
+}
@@ -0,0 +1,67 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+
+	"sigs.k8s.io/cluster-api/test/framework"
+)
+
+var _ = Describe("When testing the resource usage of the controllers at scale [Scale]", func() {
+	ScaleSpec(ctx, func() ScaleSpecInput {
+		return ScaleSpecInput{
+			E2EConfig:             e2eConfig,
+			ClusterctlConfigPath:  clusterctlConfigPath,
+			BootstrapClusterProxy: bootstrapClusterProxy,
+			ArtifactFolder:        artifactFolder,
+			SkipCleanup:           skipCleanup,
+			// NOTE: The budget is intentionally generous, so it catches significant regressions without
+			// making the test flaky; it should be lowered as the controllers get more efficient.
+			ResourceUsageBudgetPerCluster: &framework.ResourceUsageBudget{
+				MaxCPUSeconds:  30,
+				MaxMemoryBytes: 1 << 30,
+				MaxReconciles: map[string]float64{
+					"cluster":                   100,
+					"machine":                   200,
+					"kubeadmconfig":             200,
+					"kubeadmcontrolplane":       300,
+					"dockercluster":             100,
+					"dockermachine":             200,
+					"machinedeployment":         50,
+					"machineset":                50,
+					"machinehealthcheck":        50,
+					"clusterresourceset":        50,
+					"topology/cluster":          100,
+					"clusterresourcesetbinding": 50,
+				},
+				MaxControllerAPIRequests: 2000,
+				MaxAPIServerRequests:     5000,
+				APIServerRequestGroups: []string{
+					"cluster.x-k8s.io",
+					"bootstrap.cluster.x-k8s.io",
+					"controlplane.cluster.x-k8s.io",
+					"infrastructure.cluster.x-k8s.io",
+					"addons.cluster.x-k8s.io",
+				},
+			},
+		}
+	})
+})
@@ -74,6 +74,14 @@ func WithIPv6Family() KindClusterOption {
 	})
 }
 
+// WithDualStackFamily implements a New Option that instruct the kindClusterProvider to set the IPFamily to dual in
+// the new kind cluster.
+func WithDualStackFamily() KindClusterOption {
+	return kindClusterOptionAdapter(func(k *KindClusterProvider) {
+		k.ipFamily = clusterv1.DualStackIPFamily
+	})
+}
+
 // LogFolder implements a New Option that instruct the kindClusterProvider to dump bootstrap logs in a folder in case of errors.
 func LogFolder(path string) KindClusterOption {
 	return kindClusterOptionAdapter(func(k *KindClusterProvider) {
@@ -133,8 +141,11 @@ func (k *KindClusterProvider) createKindCluster() {
 		},
 	}
 
-	if k.ipFamily == clusterv1.IPv6IPFamily {
+	switch k.ipFamily {
+	case clusterv1.IPv6IPFamily:
 		cfg.Networking.IPFamily = kindv1.IPv6Family
+	case clusterv1.DualStackIPFamily:
+		cfg.Networking.IPFamily = kindv1.DualStackFamily
 	}
 	kindv1.SetDefaultsCluster(cfg)
 
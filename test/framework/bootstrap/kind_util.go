@@ -47,7 +47,7 @@ type CreateKindBootstrapClusterAndLoadImagesInput struct {
 	// Images to be loaded in the cluster.
 	Images []clusterctl.ContainerImage
 
-	// IPFamily is either ipv4 or ipv6. Default is ipv4.
+	// IPFamily is either ipv4, ipv6 or dual. Default is ipv4.
 	IPFamily string
 
 	// LogFolder where to dump logs in case of errors
@@ -68,8 +68,11 @@ func CreateKindBootstrapClusterAndLoadImages(ctx context.Context, input CreateKi
 	if input.RequiresDockerSock {
 		options = append(options, WithDockerSockMount())
 	}
-	if input.IPFamily == "IPv6" {
+	switch input.IPFamily {
+	case "IPv6":
 		options = append(options, WithIPv6Family())
+	case "dual":
+		options = append(options, WithDualStackFamily())
 	}
 	if input.LogFolder != "" {
 		options = append(options, LogFolder(input.LogFolder))
@@ -29,6 +29,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/framework/internal/log"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
 
@@ -57,6 +58,49 @@ func GetClusterClassByName(ctx context.Context, input GetClusterClassByNameInput
 	return clusterClass
 }
 
+// WaitForClusterClassReconciledInput is the input for WaitForClusterClassReconciled.
+type WaitForClusterClassReconciledInput struct {
+	Getter       Getter
+	ClusterClass *clusterv1.ClusterClass
+}
+
+// WaitForClusterClassReconciled waits until the ClusterClass has been reconciled, i.e. its variables have been
+// discovered for the current generation of the ClusterClass.
+func WaitForClusterClassReconciled(ctx context.Context, input WaitForClusterClassReconciledInput, intervals ...interface{}) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for WaitForClusterClassReconciled")
+	Expect(input.Getter).ToNot(BeNil(), "Invalid argument. input.Getter can't be nil when calling WaitForClusterClassReconciled")
+	Expect(input.ClusterClass).ToNot(BeNil(), "Invalid argument. input.ClusterClass can't be nil when calling WaitForClusterClassReconciled")
+
+	clusterClass := &clusterv1.ClusterClass{}
+	Eventually(func(g Gomega) {
+		g.Expect(input.Getter.Get(ctx, client.ObjectKeyFromObject(input.ClusterClass), clusterClass)).To(Succeed())
+		g.Expect(clusterClass.Status.ObservedGeneration).To(Equal(clusterClass.Generation))
+		g.Expect(conditions.IsTrue(clusterClass, clusterv1.ClusterClassVariablesReconciledCondition)).To(BeTrue(), conditions.GetMessage(clusterClass, clusterv1.ClusterClassVariablesReconciledCondition))
+	}, intervals...).Should(Succeed(), "Failed to wait for ClusterClass %s to be reconciled", klog.KObj(input.ClusterClass))
+}
+
+// WaitForClusterTopologyReconciledInput is the input for WaitForClusterTopologyReconciled.
+type WaitForClusterTopologyReconciledInput struct {
+	Getter  Getter
+	Cluster *clusterv1.Cluster
+}
+
+// WaitForClusterTopologyReconciled waits until the topology of a Cluster with a managed topology has been reconciled,
+// i.e. the TopologyReconciled condition is true for the current generation of the Cluster.
+func WaitForClusterTopologyReconciled(ctx context.Context, input WaitForClusterTopologyReconciledInput, intervals ...interface{}) {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for WaitForClusterTopologyReconciled")
+	Expect(input.Getter).ToNot(BeNil(), "Invalid argument. input.Getter can't be nil when calling WaitForClusterTopologyReconciled")
+	Expect(input.Cluster).ToNot(BeNil(), "Invalid argument. input.Cluster can't be nil when calling WaitForClusterTopologyReconciled")
+	Expect(input.Cluster.Spec.Topology).ToNot(BeNil(), "Invalid argument. input.Cluster must have a managed topology when calling WaitForClusterTopologyReconciled")
+
+	cluster := &clusterv1.Cluster{}
+	Eventually(func(g Gomega) {
+		g.Expect(input.Getter.Get(ctx, client.ObjectKeyFromObject(input.Cluster), cluster)).To(Succeed())
+		g.Expect(cluster.Status.ObservedGeneration).To(Equal(cluster.Generation))
+		g.Expect(conditions.IsTrue(cluster, clusterv1.TopologyReconciledCondition)).To(BeTrue(), conditions.GetMessage(cluster, clusterv1.TopologyReconciledCondition))
+	}, intervals...).Should(Succeed(), "Failed to wait for the topology of Cluster %s to be reconciled", klog.KObj(input.Cluster))
+}
+
 // UpgradeClusterTopologyAndWaitForUpgradeInput is the input type for UpgradeClusterTopologyAndWaitForUpgrade.
 type UpgradeClusterTopologyAndWaitForUpgradeInput struct {
 	ClusterProxy                            ClusterProxy
@@ -330,6 +330,12 @@ func ApplyClusterTemplateAndWait(ctx context.Context, input ApplyClusterTemplate
 			Namespace: input.ConfigCluster.Namespace,
 			Name:      result.Cluster.Spec.Topology.Class,
 		})
+
+		log.Logf("Waiting for the ClusterClass to be reconciled")
+		framework.WaitForClusterClassReconciled(ctx, framework.WaitForClusterClassReconciledInput{
+			Getter:       input.ClusterProxy.GetClient(),
+			ClusterClass: result.ClusterClass,
+		}, input.WaitForClusterIntervals...)
 	}
 
 	log.Logf("Waiting for control plane to be initialized")
@@ -361,6 +367,14 @@ func ApplyClusterTemplateAndWait(ctx context.Context, input ApplyClusterTemplate
 		Cluster: result.Cluster,
 	}, input.WaitForMachinePools...)
 
+	if result.Cluster.Spec.Topology != nil {
+		log.Logf("Waiting for the cluster topology to be reconciled")
+		framework.WaitForClusterTopologyReconciled(ctx, framework.WaitForClusterTopologyReconciledInput{
+			Getter:  input.ClusterProxy.GetClient(),
+			Cluster: result.Cluster,
+		}, input.WaitForClusterIntervals...)
+	}
+
 	if input.PostMachinesProvisioned != nil {
 		log.Logf("Calling PostMachinesProvisioned")
 		input.PostMachinesProvisioned()
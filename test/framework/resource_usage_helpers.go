@@ -0,0 +1,374 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "sigs.k8s.io/cluster-api/test/framework/ginkgoextensions"
+)
+
+const (
+	cpuSecondsMetric        = "process_cpu_seconds_total"
+	memoryBytesMetric       = "process_resident_memory_bytes"
+	reconcileTotalMetric    = "controller_runtime_reconcile_total"
+	restClientRequestMetric = "rest_client_requests_total"
+	apiServerRequestMetric  = "apiserver_request_total"
+)
+
+// ResourceUsage is a snapshot of the resource usage of the controllers running in a management cluster
+// and of the requests served by its API server.
+type ResourceUsage struct {
+	// Controllers is the resource usage of each controller Pod, by namespace/name.
+	Controllers map[string]ControllerResourceUsage `json:"controllers"`
+
+	// APIServerRequests is the number of requests served by the API server, by "<verb> <resource>.<group>".
+	APIServerRequests map[string]float64 `json:"apiServerRequests,omitempty"`
+}
+
+// ControllerResourceUsage is the resource usage of a controller Pod.
+type ControllerResourceUsage struct {
+	// Deployment is the namespace/name of the Deployment owning the Pod.
+	Deployment string `json:"deployment"`
+
+	// CPUSeconds is the CPU time consumed by the controller.
+	CPUSeconds float64 `json:"cpuSeconds"`
+
+	// MemoryBytes is the resident memory of the controller.
+	MemoryBytes float64 `json:"memoryBytes"`
+
+	// Reconciles is the number of reconciles, by controller.
+	Reconciles map[string]float64 `json:"reconciles,omitempty"`
+
+	// APIRequests is the number of requests sent by the controller to the API server.
+	APIRequests float64 `json:"apiRequests"`
+}
+
+// Sub returns the resource usage between a previous snapshot and this one; counters are subtracted,
+// while the memory is the one of the latest snapshot.
+// Pods not existing in the previous snapshot, e.g. because a controller restarted, are reported with their full usage.
+func (u ResourceUsage) Sub(previous ResourceUsage) ResourceUsage {
+	delta := ResourceUsage{
+		Controllers:       map[string]ControllerResourceUsage{},
+		APIServerRequests: map[string]float64{},
+	}
+	for pod, usage := range u.Controllers {
+		previousUsage := previous.Controllers[pod]
+		podDelta := ControllerResourceUsage{
+			Deployment:  usage.Deployment,
+			CPUSeconds:  usage.CPUSeconds - previousUsage.CPUSeconds,
+			MemoryBytes: usage.MemoryBytes,
+			Reconciles:  map[string]float64{},
+			APIRequests: usage.APIRequests - previousUsage.APIRequests,
+		}
+		for controller, count := range usage.Reconciles {
+			podDelta.Reconciles[controller] = count - previousUsage.Reconciles[controller]
+		}
+		delta.Controllers[pod] = podDelta
+	}
+	for request, count := range u.APIServerRequests {
+		delta.APIServerRequests[request] = count - previous.APIServerRequests[request]
+	}
+	return delta
+}
+
+// ByDeployment aggregates the resource usage of the controller Pods by Deployment; CPU, reconciles and
+// API requests are summed, while the memory is the one of the Pod using most of it.
+func (u ResourceUsage) ByDeployment() map[string]ControllerResourceUsage {
+	deployments := map[string]ControllerResourceUsage{}
+	for _, usage := range u.Controllers {
+		deploymentUsage, ok := deployments[usage.Deployment]
+		if !ok {
+			deploymentUsage = ControllerResourceUsage{Deployment: usage.Deployment, Reconciles: map[string]float64{}}
+		}
+		deploymentUsage.CPUSeconds += usage.CPUSeconds
+		deploymentUsage.APIRequests += usage.APIRequests
+		if usage.MemoryBytes > deploymentUsage.MemoryBytes {
+			deploymentUsage.MemoryBytes = usage.MemoryBytes
+		}
+		for controller, count := range usage.Reconciles {
+			deploymentUsage.Reconciles[controller] += count
+		}
+		deployments[usage.Deployment] = deploymentUsage
+	}
+	return deployments
+}
+
+// TotalAPIServerRequests returns the number of requests served by the API server for resources in
+// the given API groups; if no groups are given, all the requests are counted.
+func (u ResourceUsage) TotalAPIServerRequests(groups ...string) float64 {
+	total := 0.0
+	for request, count := range u.APIServerRequests {
+		if len(groups) > 0 && !hasAnyGroup(request, groups) {
+			continue
+		}
+		total += count
+	}
+	return total
+}
+
+func hasAnyGroup(request string, groups []string) bool {
+	for _, group := range groups {
+		if strings.HasSuffix(request, "."+group) {
+			return true
+		}
+	}
+	return false
+}
+
+// CaptureResourceUsageInput is the input for CaptureResourceUsage.
+type CaptureResourceUsageInput struct {
+	Lister      Lister
+	ClientSet   *kubernetes.Clientset
+	Deployments []*appsv1.Deployment
+}
+
+// CaptureResourceUsage captures a snapshot of the resource usage of the controllers and of the API server of a
+// management cluster from their metrics endpoints. It expects to find port 8080 open on the controllers.
+func CaptureResourceUsage(ctx context.Context, input CaptureResourceUsageInput) ResourceUsage {
+	Expect(ctx).NotTo(BeNil(), "ctx is required for CaptureResourceUsage")
+	Expect(input.Lister).NotTo(BeNil(), "input.Lister is required for CaptureResourceUsage")
+	Expect(input.ClientSet).NotTo(BeNil(), "input.ClientSet is required for CaptureResourceUsage")
+
+	usage := ResourceUsage{
+		Controllers: map[string]ControllerResourceUsage{},
+	}
+	for _, deployment := range input.Deployments {
+		selector, err := metav1.LabelSelectorAsMap(deployment.Spec.Selector)
+		Expect(err).NotTo(HaveOccurred(), "Failed to create Pods selector for deployment %s", klog.KObj(deployment))
+
+		pods := &corev1.PodList{}
+		Eventually(func() error {
+			return input.Lister.List(ctx, pods, client.InNamespace(deployment.Namespace), client.MatchingLabels(selector))
+		}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to list Pods for deployment %s", klog.KObj(deployment))
+
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			var data []byte
+			Eventually(func() (err error) {
+				data, err = input.ClientSet.CoreV1().RESTClient().Get().
+					Namespace(pod.Namespace).
+					Resource("pods").
+					Name(fmt.Sprintf("%s:8080", pod.Name)).
+					SubResource("proxy").
+					Suffix("metrics").
+					DoRaw(ctx)
+				return err
+			}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to get metrics for pod %s", klog.KObj(pod))
+
+			podUsage, err := parseControllerResourceUsage(data)
+			Expect(err).NotTo(HaveOccurred(), "Failed to parse metrics for pod %s", klog.KObj(pod))
+			podUsage.Deployment = klog.KObj(deployment).String()
+			usage.Controllers[klog.KObj(pod).String()] = podUsage
+		}
+	}
+
+	var data []byte
+	Eventually(func() (err error) {
+		data, err = input.ClientSet.Discovery().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+		return err
+	}, retryableOperationTimeout, retryableOperationInterval).Should(Succeed(), "Failed to get the API server metrics")
+
+	apiServerRequests, err := parseAPIServerRequests(data)
+	Expect(err).NotTo(HaveOccurred(), "Failed to parse the API server metrics")
+	usage.APIServerRequests = apiServerRequests
+
+	return usage
+}
+
+// ResourceUsageBudget defines the maximum resources the controllers of a management cluster and
+// its API server are expected to use; zero values are not checked.
+type ResourceUsageBudget struct {
+	// MaxCPUSeconds is the maximum CPU time consumed by each controller Deployment.
+	MaxCPUSeconds float64
+
+	// MaxMemoryBytes is the maximum resident memory of each controller Pod.
+	MaxMemoryBytes float64
+
+	// MaxReconciles is the maximum number of reconciles of each controller, by controller name, e.g. "machine".
+	MaxReconciles map[string]float64
+
+	// MaxControllerAPIRequests is the maximum number of requests sent to the API server by each controller Deployment.
+	MaxControllerAPIRequests float64
+
+	// MaxAPIServerRequests is the maximum number of requests served by the API server for the resources
+	// in the APIServerRequestGroups; if no groups are defined, all the requests are counted.
+	MaxAPIServerRequests   float64
+	APIServerRequestGroups []string
+}
+
+// Scale returns a copy of the budget with the counters multiplied by the given factor, e.g. the number of clusters;
+// the memory budget is not scaled.
+func (b ResourceUsageBudget) Scale(factor float64) ResourceUsageBudget {
+	scaled := b
+	scaled.MaxCPUSeconds *= factor
+	scaled.MaxControllerAPIRequests *= factor
+	scaled.MaxAPIServerRequests *= factor
+	scaled.MaxReconciles = map[string]float64{}
+	for controller, max := range b.MaxReconciles {
+		scaled.MaxReconciles[controller] = max * factor
+	}
+	return scaled
+}
+
+// ExpectResourceUsageWithinBudget checks that the resource usage does not exceed the budget, reporting all the
+// exceeded budgets at once.
+func ExpectResourceUsageWithinBudget(usage ResourceUsage, budget ResourceUsageBudget) {
+	Expect(checkResourceUsageBudget(usage, budget)).To(BeEmpty(), "Resource usage exceeds the budget")
+}
+
+// checkResourceUsageBudget returns the list of the budgets exceeded by the resource usage.
+func checkResourceUsageBudget(usage ResourceUsage, budget ResourceUsageBudget) []string {
+	exceeded := []string{}
+	for pod, podUsage := range usage.Controllers {
+		if budget.MaxMemoryBytes > 0 && podUsage.MemoryBytes > budget.MaxMemoryBytes {
+			exceeded = append(exceeded, fmt.Sprintf("pod %s uses %.0f bytes of memory, budget is %.0f", pod, podUsage.MemoryBytes, budget.MaxMemoryBytes))
+		}
+	}
+	for deployment, deploymentUsage := range usage.ByDeployment() {
+		if budget.MaxCPUSeconds > 0 && deploymentUsage.CPUSeconds > budget.MaxCPUSeconds {
+			exceeded = append(exceeded, fmt.Sprintf("deployment %s used %.1fs of CPU, budget is %.1fs", deployment, deploymentUsage.CPUSeconds, budget.MaxCPUSeconds))
+		}
+		if budget.MaxControllerAPIRequests > 0 && deploymentUsage.APIRequests > budget.MaxControllerAPIRequests {
+			exceeded = append(exceeded, fmt.Sprintf("deployment %s sent %.0f API requests, budget is %.0f", deployment, deploymentUsage.APIRequests, budget.MaxControllerAPIRequests))
+		}
+		for controller, count := range deploymentUsage.Reconciles {
+			if max, ok := budget.MaxReconciles[controller]; ok && count > max {
+				exceeded = append(exceeded, fmt.Sprintf("controller %s in deployment %s reconciled %.0f times, budget is %.0f", controller, deployment, count, max))
+			}
+		}
+	}
+	if budget.MaxAPIServerRequests > 0 {
+		if total := usage.TotalAPIServerRequests(budget.APIServerRequestGroups...); total > budget.MaxAPIServerRequests {
+			exceeded = append(exceeded, fmt.Sprintf("the API server served %.0f requests, budget is %.0f", total, budget.MaxAPIServerRequests))
+		}
+	}
+	sort.Strings(exceeded)
+	return exceeded
+}
+
+// DumpResourceUsage writes the resource usage to a JSON file.
+func DumpResourceUsage(usage ResourceUsage, path string) {
+	Expect(os.MkdirAll(filepath.Dir(path), 0750)).To(Succeed(), "Failed to create directory for %s", path)
+	data, err := json.MarshalIndent(usage, "", "  ")
+	Expect(err).NotTo(HaveOccurred(), "Failed to marshal resource usage")
+	Expect(os.WriteFile(path, data, 0600)).To(Succeed(), "Failed to write resource usage to %s", path)
+
+	for deployment, deploymentUsage := range usage.ByDeployment() {
+		Byf("Resource usage of %s: %.1fs CPU, %.0f MiB memory, %.0f API requests, reconciles %v",
+			deployment, deploymentUsage.CPUSeconds, deploymentUsage.MemoryBytes/(1<<20), deploymentUsage.APIRequests, deploymentUsage.Reconciles)
+	}
+}
+
+// parseControllerResourceUsage computes the resource usage of a controller from its metrics.
+func parseControllerResourceUsage(data []byte) (ControllerResourceUsage, error) {
+	families, err := parseMetrics(data)
+	if err != nil {
+		return ControllerResourceUsage{}, err
+	}
+	usage := ControllerResourceUsage{
+		CPUSeconds:  sumMetric(families[cpuSecondsMetric]),
+		MemoryBytes: sumMetric(families[memoryBytesMetric]),
+		Reconciles:  map[string]float64{},
+		APIRequests: sumMetric(families[restClientRequestMetric]),
+	}
+	if family, ok := families[reconcileTotalMetric]; ok {
+		for _, m := range family.GetMetric() {
+			usage.Reconciles[labelValue(m, "controller")] += metricValue(m)
+		}
+	}
+	return usage, nil
+}
+
+// parseAPIServerRequests computes the requests served by an API server from its metrics.
+func parseAPIServerRequests(data []byte) (map[string]float64, error) {
+	families, err := parseMetrics(data)
+	if err != nil {
+		return nil, err
+	}
+	requests := map[string]float64{}
+	if family, ok := families[apiServerRequestMetric]; ok {
+		for _, m := range family.GetMetric() {
+			resource := labelValue(m, "resource")
+			if resource == "" {
+				continue
+			}
+			group := labelValue(m, "group")
+			if group == "" {
+				group = "core"
+			}
+			requests[fmt.Sprintf("%s %s.%s", labelValue(m, "verb"), resource, group)] += metricValue(m)
+		}
+	}
+	return requests, nil
+}
+
+func parseMetrics(data []byte) (map[string]*dto.MetricFamily, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse metrics")
+	}
+	return families, nil
+}
+
+func sumMetric(family *dto.MetricFamily) float64 {
+	total := 0.0
+	for _, m := range family.GetMetric() {
+		total += metricValue(m)
+	}
+	return total
+}
+
+func metricValue(m *dto.Metric) float64 {
+	switch {
+	case m.GetCounter() != nil:
+		return m.GetCounter().GetValue()
+	case m.GetGauge() != nil:
+		return m.GetGauge().GetValue()
+	case m.GetUntyped() != nil:
+		return m.GetUntyped().GetValue()
+	}
+	return 0
+}
+
+func labelValue(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseControllerResourceUsage(t *testing.T) {
+	g := NewWithT(t)
+
+	usage, err := parseControllerResourceUsage([]byte(`# TYPE process_cpu_seconds_total counter
+process_cpu_seconds_total 12.5
+# TYPE process_resident_memory_bytes gauge
+process_resident_memory_bytes 1.048576e+08
+# TYPE controller_runtime_reconcile_total counter
+controller_runtime_reconcile_total{controller="machine",result="success"} 90
+controller_runtime_reconcile_total{controller="machine",result="error"} 10
+controller_runtime_reconcile_total{controller="cluster",result="success"} 20
+# TYPE rest_client_requests_total counter
+rest_client_requests_total{code="200",host="10.96.0.1:443",method="GET"} 300
+rest_client_requests_total{code="200",host="10.96.0.1:443",method="PATCH"} 50
+`))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(usage).To(Equal(ControllerResourceUsage{
+		CPUSeconds:  12.5,
+		MemoryBytes: 104857600,
+		Reconciles:  map[string]float64{"machine": 100, "cluster": 20},
+		APIRequests: 350,
+	}))
+}
+
+func TestParseAPIServerRequests(t *testing.T) {
+	g := NewWithT(t)
+
+	requests, err := parseAPIServerRequests([]byte(`# TYPE apiserver_request_total counter
+apiserver_request_total{code="200",component="apiserver",group="cluster.x-k8s.io",resource="machines",verb="LIST",version="v1beta1"} 10
+apiserver_request_total{code="404",component="apiserver",group="cluster.x-k8s.io",resource="machines",verb="LIST",version="v1beta1"} 2
+apiserver_request_total{code="200",component="apiserver",group="",resource="secrets",verb="GET",version="v1"} 5
+apiserver_request_total{code="200",component="apiserver",group="",resource="",verb="GET",version=""} 7
+`))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(requests).To(Equal(map[string]float64{
+		"LIST machines.cluster.x-k8s.io": 12,
+		"GET secrets.core":               5,
+	}))
+
+	usage := ResourceUsage{APIServerRequests: requests}
+	g.Expect(usage.TotalAPIServerRequests()).To(Equal(17.0))
+	g.Expect(usage.TotalAPIServerRequests("cluster.x-k8s.io")).To(Equal(12.0))
+}
+
+func TestResourceUsageBudget(t *testing.T) {
+	before := ResourceUsage{
+		Controllers: map[string]ControllerResourceUsage{
+			"capi-system/capi-controller-manager-1": {
+				Deployment:  "capi-system/capi-controller-manager",
+				CPUSeconds:  10,
+				MemoryBytes: 100,
+				Reconciles:  map[string]float64{"machine": 10},
+				APIRequests: 100,
+			},
+		},
+		APIServerRequests: map[string]float64{"LIST machines.cluster.x-k8s.io": 10},
+	}
+	after := ResourceUsage{
+		Controllers: map[string]ControllerResourceUsage{
+			"capi-system/capi-controller-manager-1": {
+				Deployment:  "capi-system/capi-controller-manager",
+				CPUSeconds:  30,
+				MemoryBytes: 200,
+				Reconciles:  map[string]float64{"machine": 110},
+				APIRequests: 600,
+			},
+		},
+		APIServerRequests: map[string]float64{"LIST machines.cluster.x-k8s.io": 60, "GET secrets.core": 1000},
+	}
+
+	t.Run("Sub computes the usage between two snapshots", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(after.Sub(before)).To(Equal(ResourceUsage{
+			Controllers: map[string]ControllerResourceUsage{
+				"capi-system/capi-controller-manager-1": {
+					Deployment:  "capi-system/capi-controller-manager",
+					CPUSeconds:  20,
+					MemoryBytes: 200,
+					Reconciles:  map[string]float64{"machine": 100},
+					APIRequests: 500,
+				},
+			},
+			APIServerRequests: map[string]float64{"LIST machines.cluster.x-k8s.io": 50, "GET secrets.core": 1000},
+		}))
+	})
+
+	t.Run("usage within the budget", func(t *testing.T) {
+		g := NewWithT(t)
+
+		budget := ResourceUsageBudget{
+			MaxCPUSeconds:            2,
+			MaxMemoryBytes:           200,
+			MaxReconciles:            map[string]float64{"machine": 10},
+			MaxControllerAPIRequests: 50,
+			MaxAPIServerRequests:     5,
+			APIServerRequestGroups:   []string{"cluster.x-k8s.io"},
+		}.Scale(10)
+		g.Expect(checkResourceUsageBudget(after.Sub(before), budget)).To(BeEmpty())
+	})
+
+	t.Run("usage exceeding the budget", func(t *testing.T) {
+		g := NewWithT(t)
+
+		budget := ResourceUsageBudget{
+			MaxCPUSeconds:            10,
+			MaxMemoryBytes:           100,
+			MaxReconciles:            map[string]float64{"machine": 50},
+			MaxControllerAPIRequests: 100,
+			MaxAPIServerRequests:     10,
+			APIServerRequestGroups:   []string{"cluster.x-k8s.io"},
+		}
+		g.Expect(checkResourceUsageBudget(after.Sub(before), budget)).To(ConsistOf(
+			"pod capi-system/capi-controller-manager-1 uses 200 bytes of memory, budget is 100",
+			"deployment capi-system/capi-controller-manager used 20.0s of CPU, budget is 10.0s",
+			"deployment capi-system/capi-controller-manager sent 500 API requests, budget is 100",
+			"controller machine in deployment capi-system/capi-controller-manager reconciled 100 times, budget is 50",
+			"the API server served 50 requests, budget is 10",
+		))
+	})
+}
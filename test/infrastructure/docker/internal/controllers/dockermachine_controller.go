@@ -507,26 +507,28 @@ func (r *DockerMachineReconciler) getBootstrapData(ctx context.Context, machine
 	return base64.StdEncoding.EncodeToString(value), bootstrapv1.Format(format), nil
 }
 
-// setMachineAddress gets the address from the container corresponding to a docker node and sets it on the Machine object.
+// setMachineAddress gets the addresses from the container corresponding to a docker node and sets them on the Machine object.
 func setMachineAddress(ctx context.Context, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine) error {
-	machineAddress, err := externalMachine.Address(ctx)
+	machineAddresses, err := externalMachine.Address(ctx)
 	if err != nil {
 		return err
 	}
 
-	dockerMachine.Status.Addresses = []clusterv1.MachineAddress{
-		{
-			Type:    clusterv1.MachineHostName,
-			Address: externalMachine.ContainerName(),
-		},
-		{
-			Type:    clusterv1.MachineInternalIP,
-			Address: machineAddress,
-		},
-		{
-			Type:    clusterv1.MachineExternalIP,
-			Address: machineAddress,
-		},
+	dockerMachine.Status.Addresses = []clusterv1.MachineAddress{{
+		Type:    clusterv1.MachineHostName,
+		Address: externalMachine.ContainerName(),
+	}}
+	for _, addr := range machineAddresses {
+		dockerMachine.Status.Addresses = append(dockerMachine.Status.Addresses,
+			clusterv1.MachineAddress{
+				Type:    clusterv1.MachineInternalIP,
+				Address: addr,
+			},
+			clusterv1.MachineAddress{
+				Type:    clusterv1.MachineExternalIP,
+				Address: addr,
+			},
+		)
 	}
 	return nil
 }
@@ -171,6 +171,7 @@ func (s *LoadBalancer) UpdateConfiguration(ctx context.Context) error {
 		ControlPlanePort: s.controlPlanePort,
 		BackendServers:   backendServers,
 		IPv6:             s.ipFamily == clusterv1.IPv6IPFamily,
+		DualStack:        s.ipFamily == clusterv1.DualStackIPFamily,
 	})
 	if err != nil {
 		return errors.WithStack(err)
@@ -185,6 +186,7 @@ func (s *LoadBalancer) UpdateConfiguration(ctx context.Context) error {
 }
 
 // IP returns the load balancer IP address.
+// NOTE: For dual-stack clusters the IPv4 address is returned, so the control plane endpoint uses the primary IP family.
 func (s *LoadBalancer) IP(ctx context.Context) (string, error) {
 	lbIPv4, lbIPv6, err := s.container.IP(ctx)
 	if err != nil {
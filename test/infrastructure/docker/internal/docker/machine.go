@@ -173,18 +173,22 @@ func (m *Machine) ProviderID() string {
 	return fmt.Sprintf("docker:////%s", m.ContainerName())
 }
 
-// Address will get the IP address of the machine. If IPv6 is enabled, it will return
-// the IPv6 address, otherwise an IPv4 address.
-func (m *Machine) Address(ctx context.Context) (string, error) {
+// Address will get the IP addresses of the machine. If IPv6 is enabled, it will return
+// the IPv6 address, if dual-stack is enabled it will return both the IPv4 and the IPv6 address,
+// otherwise an IPv4 address.
+func (m *Machine) Address(ctx context.Context) ([]string, error) {
 	ipv4, ipv6, err := m.container.IP(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if m.ipFamily == clusterv1.IPv6IPFamily {
-		return ipv6, nil
+	switch m.ipFamily {
+	case clusterv1.IPv6IPFamily:
+		return []string{ipv6}, nil
+	case clusterv1.DualStackIPFamily:
+		return []string{ipv4, ipv6}, nil
 	}
-	return ipv4, nil
+	return []string{ipv4}, nil
 }
 
 // ContainerImage return the image of the container for this machine
@@ -28,6 +28,9 @@ type ConfigData struct {
 	ControlPlanePort int
 	BackendServers   map[string]string
 	IPv6             bool
+	// DualStack makes the load balancer listen also on IPv6, while backend servers are
+	// still reached using their IPv4 address.
+	DualStack bool
 }
 
 // ConfigTemplate is the loadbalancer config template.
@@ -56,7 +59,7 @@ defaults
 
 frontend control-plane
   bind *:{{ .ControlPlanePort }}
-  {{ if .IPv6 -}}
+  {{ if or .IPv6 .DualStack -}}
   bind :::{{ .ControlPlanePort }};
   {{- end }}
   default_backend kube-apiservers
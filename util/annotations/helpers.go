@@ -19,6 +19,7 @@ package annotations
 
 import (
 	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -26,8 +27,9 @@ import (
 )
 
 // IsPaused returns true if the Cluster is paused or the object has the `paused` annotation.
+// NOTE: Pauses with an expiry in the past are not considered.
 func IsPaused(cluster *clusterv1.Cluster, o metav1.Object) bool {
-	if cluster.Spec.Paused {
+	if cluster.Spec.Paused || cluster.Spec.Pause.IsActive(time.Now()) {
 		return true
 	}
 	return HasPaused(o)
@@ -38,9 +40,28 @@ func IsExternallyManaged(o metav1.Object) bool {
 	return hasAnnotation(o, clusterv1.ManagedByAnnotation)
 }
 
-// HasPaused returns true if the object has the `paused` annotation.
+// HasPaused returns true if the object has the `paused` annotation and the pause has not expired
+// according to the `paused-until` annotation.
 func HasPaused(o metav1.Object) bool {
-	return hasAnnotation(o, clusterv1.PausedAnnotation)
+	if !hasAnnotation(o, clusterv1.PausedAnnotation) {
+		return false
+	}
+	until, ok := PausedUntil(o)
+	return !ok || time.Now().Before(until)
+}
+
+// PausedUntil returns the expiry of the pause set with the `paused-until` annotation, if any.
+// NOTE: Values not in RFC3339 format are ignored, and the pause never expires.
+func PausedUntil(o metav1.Object) (time.Time, bool) {
+	value, ok := o.GetAnnotations()[clusterv1.PausedUntilAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return until, true
 }
 
 // HasSkipRemediation returns true if the object has the `skip-remediation` annotation.
@@ -63,6 +84,23 @@ func ReplicasManagedByExternalAutoscaler(o metav1.Object) bool {
 	return hasTruthyAnnotationValue(o, clusterv1.ReplicasManagedByAnnotation)
 }
 
+// IsRebootstrapRequested returns true if the object has the `rebootstrap` annotation and the request
+// has not been acted upon yet, i.e. the `observed-rebootstrap` annotation has a different value.
+func IsRebootstrapRequested(o metav1.Object) bool {
+	token, ok := o.GetAnnotations()[clusterv1.RebootstrapAnnotation]
+	return ok && o.GetAnnotations()[clusterv1.ObservedRebootstrapAnnotation] != token
+}
+
+// MarkRebootstrapObserved records on the object that the current re-bootstrap request has been acted upon
+// and returns true if the annotations have changed.
+func MarkRebootstrapObserved(o metav1.Object) bool {
+	token, ok := o.GetAnnotations()[clusterv1.RebootstrapAnnotation]
+	if !ok {
+		return false
+	}
+	return AddAnnotations(o, map[string]string{clusterv1.ObservedRebootstrapAnnotation: token})
+}
+
 // AddAnnotations sets the desired annotations on the object and returns true if the annotations have changed.
 func AddAnnotations(o metav1.Object, desired map[string]string) bool {
 	if len(desired) == 0 {
@@ -71,7 +109,6 @@ func AddAnnotations(o metav1.Object, desired map[string]string) bool {
 	annotations := o.GetAnnotations()
 	if annotations == nil {
 		annotations = make(map[string]string)
-		o.SetAnnotations(annotations)
 	}
 	hasChanged := false
 	for k, v := range desired {
@@ -80,6 +117,10 @@ func AddAnnotations(o metav1.Object, desired map[string]string) bool {
 			hasChanged = true
 		}
 	}
+	// NOTE: Annotations are always set back, given that GetAnnotations returns a copy for unstructured objects.
+	if hasChanged {
+		o.SetAnnotations(annotations)
+	}
 	return hasChanged
 }
 
@@ -18,10 +18,14 @@ package annotations
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 func TestAddAnnotations(t *testing.T) {
@@ -243,3 +247,102 @@ func TestHasTruthyAnnotationValue(t *testing.T) {
 		})
 	}
 }
+
+func TestRebootstrap(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &corev1.Node{}
+	g.Expect(IsRebootstrapRequested(obj)).To(BeFalse())
+	g.Expect(MarkRebootstrapObserved(obj)).To(BeFalse())
+	g.Expect(obj.Annotations).To(BeEmpty())
+
+	obj.Annotations = map[string]string{clusterv1.RebootstrapAnnotation: "1"}
+	g.Expect(IsRebootstrapRequested(obj)).To(BeTrue())
+	g.Expect(MarkRebootstrapObserved(obj)).To(BeTrue())
+	g.Expect(obj.Annotations).To(HaveKeyWithValue(clusterv1.ObservedRebootstrapAnnotation, "1"))
+	g.Expect(IsRebootstrapRequested(obj)).To(BeFalse())
+	g.Expect(MarkRebootstrapObserved(obj)).To(BeFalse())
+
+	// A new request is issued by changing the value of the annotation.
+	obj.Annotations[clusterv1.RebootstrapAnnotation] = "2"
+	g.Expect(IsRebootstrapRequested(obj)).To(BeTrue())
+
+	// Unstructured objects are supported as well.
+	u := &unstructured.Unstructured{}
+	u.SetAnnotations(map[string]string{clusterv1.RebootstrapAnnotation: "1"})
+	g.Expect(MarkRebootstrapObserved(u)).To(BeTrue())
+	g.Expect(u.GetAnnotations()).To(HaveKeyWithValue(clusterv1.ObservedRebootstrapAnnotation, "1"))
+	g.Expect(IsRebootstrapRequested(u)).To(BeFalse())
+}
+
+func TestIsPaused(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		clusterSpec clusterv1.ClusterSpec
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name:     "not paused",
+			expected: false,
+		},
+		{
+			name:        "paused cluster",
+			clusterSpec: clusterv1.ClusterSpec{Paused: true},
+			expected:    true,
+		},
+		{
+			name:        "cluster pause without expiry",
+			clusterSpec: clusterv1.ClusterSpec{Pause: &clusterv1.ClusterPause{Reason: "maintenance"}},
+			expected:    true,
+		},
+		{
+			name:        "cluster pause not yet expired",
+			clusterSpec: clusterv1.ClusterSpec{Pause: &clusterv1.ClusterPause{Reason: "maintenance", ExpiresAt: &metav1.Time{Time: future}}},
+			expected:    true,
+		},
+		{
+			name:        "cluster pause expired",
+			clusterSpec: clusterv1.ClusterSpec{Pause: &clusterv1.ClusterPause{Reason: "maintenance", ExpiresAt: &metav1.Time{Time: past}}},
+			expected:    false,
+		},
+		{
+			name:        "paused annotation",
+			annotations: map[string]string{clusterv1.PausedAnnotation: ""},
+			expected:    true,
+		},
+		{
+			name:        "paused annotation not yet expired",
+			annotations: map[string]string{clusterv1.PausedAnnotation: "", clusterv1.PausedUntilAnnotation: future.Format(time.RFC3339)},
+			expected:    true,
+		},
+		{
+			name:        "paused annotation expired",
+			annotations: map[string]string{clusterv1.PausedAnnotation: "", clusterv1.PausedUntilAnnotation: past.Format(time.RFC3339)},
+			expected:    false,
+		},
+		{
+			name:        "paused annotation with invalid expiry never expires",
+			annotations: map[string]string{clusterv1.PausedAnnotation: "", clusterv1.PausedUntilAnnotation: "tomorrow"},
+			expected:    true,
+		},
+		{
+			name:        "paused-until annotation without paused annotation",
+			annotations: map[string]string{clusterv1.PausedUntilAnnotation: future.Format(time.RFC3339)},
+			expected:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{Spec: tt.clusterSpec}
+			obj := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			g.Expect(IsPaused(cluster, obj)).To(Equal(tt.expected))
+		})
+	}
+}
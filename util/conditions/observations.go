@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// DefaultStaleObservationThreshold is the time after which a condition derived from observations of
+// the workload cluster is considered stale if it has not been refreshed.
+const DefaultStaleObservationThreshold = 10 * time.Minute
+
+// observationRefreshInterval is the interval after which an observation is refreshed even if the observed
+// condition did not change, so it does not become stale while the workload cluster is reachable.
+const observationRefreshInterval = DefaultStaleObservationThreshold / 2
+
+// ObservationsGetter interface defines methods that a Cluster API object should implement in order to
+// report the freshness of conditions derived from observations of the workload cluster.
+type ObservationsGetter interface {
+	Getter
+
+	// GetConditionObservations returns the condition observations for a cluster API object, keyed by condition type.
+	GetConditionObservations() map[clusterv1.ConditionType]clusterv1.ConditionObservation
+}
+
+// ObservationsSetter interface defines methods that a Cluster API object should implement in order to
+// track the freshness of conditions derived from observations of the workload cluster.
+type ObservationsSetter interface {
+	ObservationsGetter
+
+	// SetConditionObservations sets the condition observations for a cluster API object.
+	SetConditionObservations(map[clusterv1.ConditionType]clusterv1.ConditionObservation)
+}
+
+// MarkObserved records that the condition with the given type has just been refreshed from the workload cluster.
+// In order to avoid changing the object at every reconcile, the observation is updated only if the condition
+// status or reason or the object generation changed since the last observation, or if the last observation
+// is stale or older than the refresh interval.
+//
+// NOTE: MarkObserved must be called after setting the condition.
+func MarkObserved(to ObservationsSetter, t clusterv1.ConditionType) {
+	var status corev1.ConditionStatus
+	var reason string
+	if c := Get(to, t); c != nil {
+		status = c.Status
+		reason = c.Reason
+	}
+
+	observations := to.GetConditionObservations()
+	if observation, ok := observations[t]; ok && !observation.Stale &&
+		observation.Status == status && observation.Reason == reason && observation.ObservedGeneration == to.GetGeneration() &&
+		time.Since(observation.LastObservedTime.Time) < observationRefreshInterval {
+		return
+	}
+
+	if observations == nil {
+		observations = map[clusterv1.ConditionType]clusterv1.ConditionObservation{}
+	}
+	observations[t] = clusterv1.ConditionObservation{
+		// NOTE: The time is truncated to seconds, given that this is the precision used when serializing.
+		LastObservedTime:   metav1.NewTime(time.Now().UTC().Truncate(time.Second)),
+		Status:             status,
+		Reason:             reason,
+		ObservedGeneration: to.GetGeneration(),
+	}
+	to.SetConditionObservations(observations)
+}
+
+// MarkStaleObservations marks as stale the condition observations not refreshed within the given threshold.
+func MarkStaleObservations(to ObservationsSetter, threshold time.Duration) {
+	observations := to.GetConditionObservations()
+	for t, observation := range observations {
+		observation.Stale = time.Since(observation.LastObservedTime.Time) > threshold
+		observations[t] = observation
+	}
+	to.SetConditionObservations(observations)
+}
+
+// IsStale is true if the condition with the given type has been observed at least once
+// and it has not been refreshed within the staleness threshold.
+func IsStale(from ObservationsGetter, t clusterv1.ConditionType) bool {
+	observation, ok := from.GetConditionObservations()[t]
+	return ok && observation.Stale
+}
+
+// GetLastObservedTime returns the last time the condition with the given type has been refreshed from
+// the workload cluster; nil is returned if the condition has never been observed.
+func GetLastObservedTime(from ObservationsGetter, t clusterv1.ConditionType) *metav1.Time {
+	observation, ok := from.GetConditionObservations()[t]
+	if !ok {
+		return nil
+	}
+	return &observation.LastObservedTime
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestObservations(t *testing.T) {
+	g := NewWithT(t)
+
+	machine := &clusterv1.Machine{}
+	g.Expect(GetLastObservedTime(machine, clusterv1.MachineNodeHealthyCondition)).To(BeNil())
+	g.Expect(IsStale(machine, clusterv1.MachineNodeHealthyCondition)).To(BeFalse())
+
+	MarkObserved(machine, clusterv1.MachineNodeHealthyCondition)
+	g.Expect(GetLastObservedTime(machine, clusterv1.MachineNodeHealthyCondition)).ToNot(BeNil())
+	g.Expect(IsStale(machine, clusterv1.MachineNodeHealthyCondition)).To(BeFalse())
+
+	// Observations refreshed within the threshold are not stale.
+	MarkStaleObservations(machine, time.Minute)
+	g.Expect(IsStale(machine, clusterv1.MachineNodeHealthyCondition)).To(BeFalse())
+
+	// Observations not refreshed within the threshold are stale.
+	machine.Status.ConditionObservations[clusterv1.MachineNodeHealthyCondition] = clusterv1.ConditionObservation{
+		LastObservedTime: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+	}
+	MarkStaleObservations(machine, time.Minute)
+	g.Expect(IsStale(machine, clusterv1.MachineNodeHealthyCondition)).To(BeTrue())
+
+	// Refreshing an observation clears the stale marker.
+	MarkObserved(machine, clusterv1.MachineNodeHealthyCondition)
+	g.Expect(IsStale(machine, clusterv1.MachineNodeHealthyCondition)).To(BeFalse())
+}
+
+func TestMarkObservedOnlyOnChanges(t *testing.T) {
+	g := NewWithT(t)
+
+	lastObservedTime := metav1.NewTime(time.Now().Add(-time.Minute).UTC().Truncate(time.Second))
+	machine := &clusterv1.Machine{}
+	MarkTrue(machine, clusterv1.MachineNodeHealthyCondition)
+	machine.Status.ConditionObservations = map[clusterv1.ConditionType]clusterv1.ConditionObservation{
+		clusterv1.MachineNodeHealthyCondition: {
+			LastObservedTime: lastObservedTime,
+			Status:           corev1.ConditionTrue,
+		},
+	}
+
+	// The observation is not updated if the condition did not change.
+	MarkObserved(machine, clusterv1.MachineNodeHealthyCondition)
+	g.Expect(*GetLastObservedTime(machine, clusterv1.MachineNodeHealthyCondition)).To(Equal(lastObservedTime))
+
+	// The observation is updated if the condition changed.
+	MarkFalse(machine, clusterv1.MachineNodeHealthyCondition, clusterv1.NodeNotFoundReason, clusterv1.ConditionSeverityError, "")
+	MarkObserved(machine, clusterv1.MachineNodeHealthyCondition)
+	g.Expect(*GetLastObservedTime(machine, clusterv1.MachineNodeHealthyCondition)).ToNot(Equal(lastObservedTime))
+	observation := machine.Status.ConditionObservations[clusterv1.MachineNodeHealthyCondition]
+	g.Expect(observation.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(observation.Reason).To(Equal(clusterv1.NodeNotFoundReason))
+
+	// The observation is updated if it is older than the refresh interval.
+	observation.LastObservedTime = metav1.NewTime(time.Now().Add(-observationRefreshInterval - time.Minute))
+	machine.Status.ConditionObservations[clusterv1.MachineNodeHealthyCondition] = observation
+	MarkObserved(machine, clusterv1.MachineNodeHealthyCondition)
+	g.Expect(time.Since(GetLastObservedTime(machine, clusterv1.MachineNodeHealthyCondition).Time)).To(BeNumerically("<", observationRefreshInterval))
+}
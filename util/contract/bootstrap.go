@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// BootstrapContract encodes information about the Cluster API contract for bootstrap objects.
+type BootstrapContract struct{}
+
+var bootstrap *BootstrapContract
+var onceBootstrap sync.Once
+
+// Bootstrap provide access to the information about the Cluster API contract for bootstrap objects.
+func Bootstrap() *BootstrapContract {
+	onceBootstrap.Do(func() {
+		bootstrap = &BootstrapContract{}
+	})
+	return bootstrap
+}
+
+// Ready provide access to status.ready field in a bootstrap object.
+func (b *BootstrapContract) Ready() *Bool {
+	return &Bool{
+		path: []string{"status", "ready"},
+	}
+}
+
+// DataSecretName provide access to status.dataSecretName field in a bootstrap object.
+func (b *BootstrapContract) DataSecretName() *String {
+	return &String{
+		path: []string{"status", "dataSecretName"},
+	}
+}
+
+// FailureReason provides access to the status.failureReason field in an bootstrap object. Note that this field is optional.
+func (b *BootstrapContract) FailureReason() *String {
+	return &String{
+		path: []string{"status", "failureReason"},
+	}
+}
+
+// FailureMessage provides access to the status.failureMessage field in an bootstrap object. Note that this field is optional.
+func (b *BootstrapContract) FailureMessage() *String {
+	return &String{
+		path: []string{"status", "failureMessage"},
+	}
+}
+
+// BootstrapProgress provides access to the status.bootstrapProgress field in a bootstrap object, reporting the progress
+// of the bootstrap process running on the machine. Note that this field is optional.
+func (b *BootstrapContract) BootstrapProgress() *MachineBootstrapProgress {
+	return &MachineBootstrapProgress{
+		path: []string{"status", "bootstrapProgress"},
+	}
+}
+
+// MachineBootstrapProgress represents an accessor to a clusterv1.MachineBootstrapProgress path value.
+type MachineBootstrapProgress struct {
+	path Path
+}
+
+// Path returns the path to the clusterv1.MachineBootstrapProgress value.
+func (p *MachineBootstrapProgress) Path() Path {
+	return p.path
+}
+
+// Get gets the clusterv1.MachineBootstrapProgress value.
+func (p *MachineBootstrapProgress) Get(obj *unstructured.Unstructured) (*clusterv1.MachineBootstrapProgress, error) {
+	value, ok, err := unstructured.NestedMap(obj.UnstructuredContent(), p.path...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s from object", "."+strings.Join(p.path, "."))
+	}
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "path %s", "."+strings.Join(p.path, "."))
+	}
+
+	progress := &clusterv1.MachineBootstrapProgress{}
+	s, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshall field at %s to json", "."+strings.Join(p.path, "."))
+	}
+	if err := json.Unmarshal(s, progress); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshall field at %s to json", "."+strings.Join(p.path, "."))
+	}
+	return progress, nil
+}
+
+// Set sets the clusterv1.MachineBootstrapProgress value in the path.
+func (p *MachineBootstrapProgress) Set(obj *unstructured.Unstructured, value clusterv1.MachineBootstrapProgress) error {
+	s, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshall supplied value to json for path %s", "."+strings.Join(p.path, "."))
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(s, &m); err != nil {
+		return errors.Wrapf(err, "failed to unmarshall supplied value to json for path %s", "."+strings.Join(p.path, "."))
+	}
+
+	if err := unstructured.SetNestedMap(obj.UnstructuredContent(), m, p.path...); err != nil {
+		return errors.Wrapf(err, "failed to set path %s of object %v", "."+strings.Join(p.path, "."), obj.GroupVersionKind())
+	}
+	return nil
+}
@@ -18,9 +18,13 @@ package contract
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 func TestBootstrap(t *testing.T) {
@@ -78,4 +82,27 @@ func TestBootstrap(t *testing.T) {
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(*got).To(Equal("fake-message"))
 	})
+	t.Run("Manages optional status.bootstrapProgress", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Bootstrap().BootstrapProgress().Path()).To(Equal(Path{"status", "bootstrapProgress"}))
+
+		_, err := Bootstrap().BootstrapProgress().Get(obj)
+		g.Expect(IsNotFound(err)).To(BeTrue())
+
+		progress := clusterv1.MachineBootstrapProgress{
+			Phase:             "RunningKubeadm",
+			Message:           "kubeadm join in progress",
+			LastHeartbeatTime: &metav1.Time{Time: time.Now().Truncate(time.Second)},
+		}
+		err = Bootstrap().BootstrapProgress().Set(obj, progress)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got, err := Bootstrap().BootstrapProgress().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(got.Phase).To(Equal(progress.Phase))
+		g.Expect(got.Message).To(Equal(progress.Message))
+		g.Expect(got.LastHeartbeatTime.Equal(progress.LastHeartbeatTime)).To(BeTrue())
+	})
 }
@@ -14,5 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package contract contains utils related to the Cluster API contract.
+// Package contract contains utils related to the Cluster API contract, including the accessors for the fields
+// defined by the contract for the objects of providers and the helpers reading and writing those fields across the
+// versions of the contract.
 package contract
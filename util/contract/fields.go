@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// SupportedContracts are the versions of the Cluster API contract supported by the helpers reading and writing the
+// contract fields; the fields read and written by the helpers are defined at the same paths in all these versions, so
+// the helpers can be used for objects of any API version compatible with one of them.
+var SupportedContracts = []string{"v1alpha3", "v1alpha4", "v1beta1"}
+
+// BootstrapFields are the fields defined by the Cluster API contract for bootstrap objects.
+type BootstrapFields struct {
+	// Ready is status.ready.
+	Ready bool
+
+	// DataSecretName is status.dataSecretName, if set.
+	DataSecretName *string
+}
+
+// GetBootstrapFields reads the fields defined by the Cluster API contract from a bootstrap object.
+func GetBootstrapFields(obj *unstructured.Unstructured) (*BootstrapFields, error) {
+	fields := &BootstrapFields{}
+	ready, err := Bootstrap().Ready().Get(obj)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	fields.Ready = ready != nil && *ready
+	if fields.DataSecretName, err = Bootstrap().DataSecretName().Get(obj); err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// SetBootstrapFields writes the fields defined by the Cluster API contract to a bootstrap object; optional fields
+// which are not set are not written.
+func SetBootstrapFields(obj *unstructured.Unstructured, fields *BootstrapFields) error {
+	if err := Bootstrap().Ready().Set(obj, fields.Ready); err != nil {
+		return err
+	}
+	if fields.DataSecretName != nil {
+		if err := Bootstrap().DataSecretName().Set(obj, *fields.DataSecretName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ControlPlaneFields are the fields defined by the Cluster API contract for ControlPlane objects.
+type ControlPlaneFields struct {
+	// Ready is status.ready.
+	Ready bool
+
+	// Replicas is spec.replicas, if set.
+	Replicas *int64
+
+	// Version is spec.version, if set.
+	Version *string
+}
+
+// GetControlPlaneFields reads the fields defined by the Cluster API contract from a ControlPlane object.
+func GetControlPlaneFields(obj *unstructured.Unstructured) (*ControlPlaneFields, error) {
+	fields := &ControlPlaneFields{}
+	ready, err := ControlPlane().Ready().Get(obj)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	fields.Ready = ready != nil && *ready
+	if fields.Replicas, err = ControlPlane().Replicas().Get(obj); err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	if fields.Version, err = ControlPlane().Version().Get(obj); err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// SetControlPlaneFields writes the fields defined by the Cluster API contract to a ControlPlane object; optional
+// fields which are not set are not written.
+func SetControlPlaneFields(obj *unstructured.Unstructured, fields *ControlPlaneFields) error {
+	if err := ControlPlane().Ready().Set(obj, fields.Ready); err != nil {
+		return err
+	}
+	if fields.Replicas != nil {
+		if err := ControlPlane().Replicas().Set(obj, *fields.Replicas); err != nil {
+			return err
+		}
+	}
+	if fields.Version != nil {
+		if err := ControlPlane().Version().Set(obj, *fields.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InfrastructureClusterFields are the fields defined by the Cluster API contract for InfrastructureCluster objects.
+type InfrastructureClusterFields struct {
+	// Ready is status.ready.
+	Ready bool
+
+	// FailureDomains is status.failureDomains, if set.
+	FailureDomains clusterv1.FailureDomains
+}
+
+// GetInfrastructureClusterFields reads the fields defined by the Cluster API contract from an InfrastructureCluster object.
+func GetInfrastructureClusterFields(obj *unstructured.Unstructured) (*InfrastructureClusterFields, error) {
+	fields := &InfrastructureClusterFields{}
+	ready, err := InfrastructureCluster().Ready().Get(obj)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	fields.Ready = ready != nil && *ready
+	failureDomains, err := InfrastructureCluster().FailureDomains().Get(obj)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	if failureDomains != nil {
+		fields.FailureDomains = *failureDomains
+	}
+	return fields, nil
+}
+
+// SetInfrastructureClusterFields writes the fields defined by the Cluster API contract to an InfrastructureCluster
+// object; optional fields which are not set are not written.
+func SetInfrastructureClusterFields(obj *unstructured.Unstructured, fields *InfrastructureClusterFields) error {
+	if err := InfrastructureCluster().Ready().Set(obj, fields.Ready); err != nil {
+		return err
+	}
+	if fields.FailureDomains != nil {
+		if err := InfrastructureCluster().FailureDomains().Set(obj, fields.FailureDomains); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InfrastructureMachineFields are the fields defined by the Cluster API contract for InfrastructureMachine objects.
+type InfrastructureMachineFields struct {
+	// Ready is status.ready.
+	Ready bool
+
+	// ProviderID is spec.providerID, if set.
+	ProviderID *string
+
+	// FailureDomain is spec.failureDomain, if set.
+	FailureDomain *string
+}
+
+// GetInfrastructureMachineFields reads the fields defined by the Cluster API contract from an InfrastructureMachine object.
+func GetInfrastructureMachineFields(obj *unstructured.Unstructured) (*InfrastructureMachineFields, error) {
+	fields := &InfrastructureMachineFields{}
+	ready, err := InfrastructureMachine().Ready().Get(obj)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	fields.Ready = ready != nil && *ready
+	if fields.ProviderID, err = InfrastructureMachine().ProviderID().Get(obj); err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	if fields.FailureDomain, err = InfrastructureMachine().FailureDomain().Get(obj); err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// SetInfrastructureMachineFields writes the fields defined by the Cluster API contract to an InfrastructureMachine
+// object; optional fields which are not set are not written.
+func SetInfrastructureMachineFields(obj *unstructured.Unstructured, fields *InfrastructureMachineFields) error {
+	if err := InfrastructureMachine().Ready().Set(obj, fields.Ready); err != nil {
+		return err
+	}
+	if fields.ProviderID != nil {
+		if err := InfrastructureMachine().ProviderID().Set(obj, *fields.ProviderID); err != nil {
+			return err
+		}
+	}
+	if fields.FailureDomain != nil {
+		if err := InfrastructureMachine().FailureDomain().Set(obj, *fields.FailureDomain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestBootstrapFields(t *testing.T) {
+	g := NewWithT(t)
+
+	// Fields are read from an object of an older API version and written to an object of a newer API version.
+	from := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "bootstrap.cluster.x-k8s.io/v1alpha3",
+		"status": map[string]interface{}{
+			"ready":          true,
+			"dataSecretName": "bootstrap-data",
+		},
+	}}
+	fields, err := GetBootstrapFields(from)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fields).To(Equal(&BootstrapFields{Ready: true, DataSecretName: pointer.String("bootstrap-data")}))
+
+	to := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "bootstrap.cluster.x-k8s.io/v1beta1"}}
+	g.Expect(SetBootstrapFields(to, fields)).To(Succeed())
+	g.Expect(to.Object["status"]).To(Equal(from.Object["status"]))
+
+	// Fields not set are left empty.
+	fields, err = GetBootstrapFields(&unstructured.Unstructured{Object: map[string]interface{}{}})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fields).To(Equal(&BootstrapFields{}))
+}
+
+func TestControlPlaneFields(t *testing.T) {
+	g := NewWithT(t)
+
+	from := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "controlplane.cluster.x-k8s.io/v1alpha4",
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"version":  "v1.26.0",
+		},
+		"status": map[string]interface{}{
+			"ready": true,
+		},
+	}}
+	fields, err := GetControlPlaneFields(from)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fields).To(Equal(&ControlPlaneFields{Ready: true, Replicas: pointer.Int64(3), Version: pointer.String("v1.26.0")}))
+
+	to := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "controlplane.cluster.x-k8s.io/v1beta1"}}
+	g.Expect(SetControlPlaneFields(to, fields)).To(Succeed())
+	g.Expect(to.Object["spec"]).To(Equal(from.Object["spec"]))
+	g.Expect(to.Object["status"]).To(Equal(from.Object["status"]))
+
+	// Fields not set are left empty, and they are not written.
+	fields, err = GetControlPlaneFields(&unstructured.Unstructured{Object: map[string]interface{}{}})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fields).To(Equal(&ControlPlaneFields{}))
+
+	to = &unstructured.Unstructured{Object: map[string]interface{}{}}
+	g.Expect(SetControlPlaneFields(to, fields)).To(Succeed())
+	g.Expect(to.Object).ToNot(HaveKey("spec"))
+}
+
+func TestInfrastructureClusterFields(t *testing.T) {
+	g := NewWithT(t)
+
+	from := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+		"status": map[string]interface{}{
+			"ready": true,
+			"failureDomains": map[string]interface{}{
+				"fd1": map[string]interface{}{"controlPlane": true},
+			},
+		},
+	}}
+	fields, err := GetInfrastructureClusterFields(from)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fields).To(Equal(&InfrastructureClusterFields{Ready: true, FailureDomains: clusterv1.FailureDomains{"fd1": {ControlPlane: true}}}))
+
+	to := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1"}}
+	g.Expect(SetInfrastructureClusterFields(to, fields)).To(Succeed())
+	g.Expect(to.Object["status"]).To(Equal(from.Object["status"]))
+}
+
+func TestInfrastructureMachineFields(t *testing.T) {
+	g := NewWithT(t)
+
+	from := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha3",
+		"spec": map[string]interface{}{
+			"providerID":    "foo://machine-1",
+			"failureDomain": "fd1",
+		},
+		"status": map[string]interface{}{
+			"ready": false,
+		},
+	}}
+	fields, err := GetInfrastructureMachineFields(from)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fields).To(Equal(&InfrastructureMachineFields{ProviderID: pointer.String("foo://machine-1"), FailureDomain: pointer.String("fd1")}))
+
+	to := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1"}}
+	g.Expect(SetInfrastructureMachineFields(to, fields)).To(Succeed())
+	g.Expect(to.Object["spec"]).To(Equal(from.Object["spec"]))
+	g.Expect(to.Object["status"]).To(Equal(from.Object["status"]))
+}
@@ -116,4 +116,35 @@ func TestInfrastructureMachine(t *testing.T) {
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(*got).To(Equal("fake-failure-domain"))
 	})
+	t.Run("Reads fields of objects with different API versions", func(t *testing.T) {
+		for _, apiVersion := range []string{"infrastructure.cluster.x-k8s.io/v1alpha4", "infrastructure.cluster.x-k8s.io/v1beta1"} {
+			g := NewWithT(t)
+
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": apiVersion,
+				"kind":       "FooMachine",
+				"spec": map[string]interface{}{
+					"providerID": "foo://machine-1",
+				},
+				"status": map[string]interface{}{
+					"ready": true,
+					"addresses": []interface{}{
+						map[string]interface{}{"type": "InternalIP", "address": "10.0.0.1"},
+					},
+				},
+			}}
+
+			ready, err := InfrastructureMachine().Ready().Get(obj)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(*ready).To(BeTrue())
+
+			providerID, err := InfrastructureMachine().ProviderID().Get(obj)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(*providerID).To(Equal("foo://machine-1"))
+
+			addresses, err := InfrastructureMachine().Addresses().Get(obj)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(*addresses).To(Equal([]clusterv1.MachineAddress{{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"}}))
+		}
+	})
 }
@@ -27,6 +27,11 @@ import (
 
 var errNotFound = errors.New("not found")
 
+// IsNotFound returns true if the error is returned by an accessor because the field is not set in the object.
+func IsNotFound(err error) bool {
+	return errors.Is(err, errNotFound)
+}
+
 // Path defines a how to access a field in an Unstructured object.
 type Path []string
 
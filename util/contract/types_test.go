@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func TestPath_Append(t *testing.T) {
@@ -159,3 +160,17 @@ func TestPath_Overlaps(t *testing.T) {
 		})
 	}
 }
+
+func TestIsNotFound(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	_, err := ControlPlane().StatusVersion().Get(obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(IsNotFound(err)).To(BeTrue())
+
+	g.Expect(ControlPlane().Version().Set(obj, "v1.26.0")).To(Succeed())
+	_, err = ControlPlane().Replicas().Get(obj)
+	g.Expect(IsNotFound(err)).To(BeTrue())
+}
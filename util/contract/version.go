@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// APIVersionsForContract returns the API versions of a provider CRD compatible with a version of the Cluster API
+// contract, e.g. v1beta1, as declared by the cluster.x-k8s.io/<contract> label of the CRD.
+// Versions are sorted from the oldest to the latest.
+func APIVersionsForContract(crdMetadata metav1.Object, contract string) ([]string, error) {
+	label := clusterv1.GroupVersion.Group + "/" + contract
+
+	supportedVersions, ok := crdMetadata.GetLabels()[label]
+	if !ok || supportedVersions == "" {
+		return nil, errors.Errorf("cannot find any versions matching contract %q for CRD %v as contract version label(s) are either missing or empty (see https://cluster-api.sigs.k8s.io/developer/providers/contracts.html#api-version-labels)", label, crdMetadata.GetName())
+	}
+
+	versions := strings.Split(supportedVersions, "_")
+	sort.SliceStable(versions, func(i, j int) bool {
+		return k8sversion.CompareKubeAwareVersionStrings(versions[i], versions[j]) < 0
+	})
+	return versions, nil
+}
+
+// LatestAPIVersionForContract returns the latest API version of a provider CRD compatible with a version of
+// the Cluster API contract, e.g. v1beta1.
+func LatestAPIVersionForContract(crdMetadata metav1.Object, contract string) (string, error) {
+	versions, err := APIVersionsForContract(crdMetadata, contract)
+	if err != nil {
+		return "", err
+	}
+	return versions[len(versions)-1], nil
+}
+
+// ContractForAPIVersion returns the latest version of the Cluster API contract, e.g. v1beta1, an API version of
+// a provider CRD is compatible with, as declared by the cluster.x-k8s.io/<contract> labels of the CRD.
+func ContractForAPIVersion(crdMetadata metav1.Object, apiVersion string) (string, error) {
+	contracts := []string{}
+	prefix := clusterv1.GroupVersion.Group + "/"
+	for label, supportedVersions := range crdMetadata.GetLabels() {
+		if !strings.HasPrefix(label, prefix) {
+			continue
+		}
+		for _, version := range strings.Split(supportedVersions, "_") {
+			if version == apiVersion {
+				contracts = append(contracts, strings.TrimPrefix(label, prefix))
+			}
+		}
+	}
+	if len(contracts) == 0 {
+		return "", errors.Errorf("cannot find any contract matching version %q for CRD %v as contract version label(s) are either missing or not including it (see https://cluster-api.sigs.k8s.io/developer/providers/contracts.html#api-version-labels)", apiVersion, crdMetadata.GetName())
+	}
+	sort.SliceStable(contracts, func(i, j int) bool {
+		return k8sversion.CompareKubeAwareVersionStrings(contracts[i], contracts[j]) < 0
+	})
+	return contracts[len(contracts)-1], nil
+}
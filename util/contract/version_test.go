@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAPIVersionsForContract(t *testing.T) {
+	crdMetadata := &metav1.ObjectMeta{
+		Name: "foomachines.infrastructure.cluster.x-k8s.io",
+		Labels: map[string]string{
+			"cluster.x-k8s.io/v1alpha4": "v1alpha4",
+			"cluster.x-k8s.io/v1beta1":  "v1beta2_v1alpha4_v1beta1",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		contract string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "returns a single version",
+			contract: "v1alpha4",
+			want:     []string{"v1alpha4"},
+		},
+		{
+			name:     "returns versions sorted from the oldest to the latest",
+			contract: "v1beta1",
+			want:     []string{"v1alpha4", "v1beta1", "v1beta2"},
+		},
+		{
+			name:     "fails for a contract not supported by the CRD",
+			contract: "v1alpha3",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := APIVersionsForContract(crdMetadata, tt.contract)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+
+			latest, err := LatestAPIVersionForContract(crdMetadata, tt.contract)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(latest).To(Equal(tt.want[len(tt.want)-1]))
+		})
+	}
+}
+
+func TestContractForAPIVersion(t *testing.T) {
+	crdMetadata := &metav1.ObjectMeta{
+		Name: "foomachines.infrastructure.cluster.x-k8s.io",
+		Labels: map[string]string{
+			"cluster.x-k8s.io/v1alpha4": "v1alpha4",
+			"cluster.x-k8s.io/v1beta1":  "v1alpha4_v1beta1",
+			"another.io/v1":             "v1alpha3",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		apiVersion string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "returns the contract of a version",
+			apiVersion: "v1beta1",
+			want:       "v1beta1",
+		},
+		{
+			name:       "returns the latest contract of a version compatible with many contracts",
+			apiVersion: "v1alpha4",
+			want:       "v1beta1",
+		},
+		{
+			name:       "fails for a version not compatible with any contract",
+			apiVersion: "v1alpha3",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := ContractForAPIVersion(crdMetadata, tt.apiVersion)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
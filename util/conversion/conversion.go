@@ -20,8 +20,6 @@ package conversion
 import (
 	"context"
 	"math/rand"
-	"sort"
-	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -42,6 +40,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/contract"
 )
 
 const (
@@ -50,10 +49,6 @@ const (
 	DataAnnotation = "cluster.x-k8s.io/conversion-data"
 )
 
-var (
-	contract = clusterv1.GroupVersion.String()
-)
-
 // UpdateReferenceAPIContract takes a client and object reference, queries the API Server for
 // the Custom Resource Definition and looks which one is the stored version available.
 //
@@ -82,18 +77,7 @@ func UpdateReferenceAPIContract(ctx context.Context, c client.Client, ref *corev
 }
 
 func getLatestAPIVersionFromContract(metadata metav1.Object) (string, error) {
-	labels := metadata.GetLabels()
-
-	// If there is no label, return early without changing the reference.
-	supportedVersions, ok := labels[contract]
-	if !ok || supportedVersions == "" {
-		return "", errors.Errorf("cannot find any versions matching contract %q for CRD %v as contract version label(s) are either missing or empty (see https://cluster-api.sigs.k8s.io/developer/providers/contracts.html#api-version-labels)", contract, metadata.GetName())
-	}
-
-	// Pick the latest version in the slice and validate it.
-	kubeVersions := util.KubeAwareAPIVersions(strings.Split(supportedVersions, "_"))
-	sort.Sort(kubeVersions)
-	return kubeVersions[len(kubeVersions)-1], nil
+	return contract.LatestAPIVersionForContract(metadata, clusterv1.GroupVersion.Version)
 }
 
 // MarshalData stores the source object as json data in the destination object annotations map.
@@ -52,6 +52,18 @@ func FromSecret(ctx context.Context, c client.Reader, cluster client.ObjectKey)
 	return toKubeconfigBytes(out)
 }
 
+// User defines the identity of the client certificate embedded in a user Kubeconfig.
+type User struct {
+	// Name is the name of the user, used as the CommonName of the client certificate.
+	Name string
+
+	// Groups are the groups the user belongs to, used as the Organization of the client certificate.
+	Groups []string
+
+	// TTL is the lifespan of the client certificate; if not set, certs.DefaultCertDuration is used.
+	TTL time.Duration
+}
+
 // New creates a new Kubeconfig using the cluster name and specified endpoint.
 func New(clusterName, endpoint string, caCert *x509.Certificate, caKey crypto.Signer) (*api.Config, error) {
 	cfg := &certs.Config{
@@ -59,7 +71,26 @@ func New(clusterName, endpoint string, caCert *x509.Certificate, caKey crypto.Si
 		Organization: []string{"system:masters"},
 		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
 	}
+	return newWithClientCert(clusterName, fmt.Sprintf("%s-admin", clusterName), endpoint, cfg, caCert, caKey)
+}
+
+// NewForUser creates a new Kubeconfig using the cluster name and specified endpoint, with a client
+// certificate signed for the given user; the permissions of the user are defined by the RBAC rules
+// of the workload cluster.
+func NewForUser(clusterName, endpoint string, caCert *x509.Certificate, caKey crypto.Signer, user User) (*api.Config, error) {
+	if user.Name == "" {
+		return nil, errors.New("must specify a user name")
+	}
+	cfg := &certs.Config{
+		CommonName:   user.Name,
+		Organization: user.Groups,
+		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		Duration:     user.TTL,
+	}
+	return newWithClientCert(clusterName, user.Name, endpoint, cfg, caCert, caKey)
+}
 
+func newWithClientCert(clusterName, userName, endpoint string, cfg *certs.Config, caCert *x509.Certificate, caKey crypto.Signer) (*api.Config, error) {
 	clientKey, err := certs.NewPrivateKey()
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create private key")
@@ -70,7 +101,6 @@ func New(clusterName, endpoint string, caCert *x509.Certificate, caKey crypto.Si
 		return nil, errors.Wrap(err, "unable to sign certificate")
 	}
 
-	userName := fmt.Sprintf("%s-admin", clusterName)
 	contextName := fmt.Sprintf("%s@%s", userName, clusterName)
 
 	return &api.Config{
@@ -96,6 +126,39 @@ func New(clusterName, endpoint string, caCert *x509.Certificate, caKey crypto.Si
 	}, nil
 }
 
+// GenerateForUser generates a Kubeconfig for a Cluster with a client certificate signed for the given user.
+// The endpoint is read from the Kubeconfig secret of the Cluster, and the certificate is signed using the Cluster CA.
+func GenerateForUser(ctx context.Context, c client.Reader, cluster client.ObjectKey, user User) ([]byte, error) {
+	data, err := FromSecret(ctx, c, cluster)
+	if err != nil {
+		return nil, err
+	}
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert kubeconfig Secret into a clientcmdapi.Config")
+	}
+	clusterConfig, ok := config.Clusters[cluster.Name]
+	if !ok {
+		return nil, errors.Errorf("failed to get the endpoint of cluster %q from the kubeconfig Secret", cluster.Name)
+	}
+
+	caCert, caKey, err := getClusterCA(ctx, c, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := NewForUser(cluster.Name, clusterConfig.Server, caCert, caKey, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate a kubeconfig")
+	}
+
+	out, err := clientcmd.Write(*cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize config to yaml")
+	}
+	return out, nil
+}
+
 // CreateSecret creates the Kubeconfig secret for the given cluster.
 func CreateSecret(ctx context.Context, c client.Client, cluster *clusterv1.Cluster) error {
 	name := util.ObjectKey(cluster)
@@ -202,38 +265,46 @@ func RegenerateSecret(ctx context.Context, c client.Client, configSecret *corev1
 }
 
 func generateKubeconfig(ctx context.Context, c client.Client, clusterName client.ObjectKey, endpoint string) ([]byte, error) {
-	clusterCA, err := secret.GetFromNamespacedName(ctx, c, clusterName, secret.ClusterCA)
+	cert, key, err := getClusterCA(ctx, c, clusterName)
 	if err != nil {
-		if apierrors.IsNotFound(err) {
-			return nil, ErrDependentCertificateNotFound
-		}
 		return nil, err
 	}
 
-	cert, err := certs.DecodeCertPEM(clusterCA.Data[secret.TLSCrtDataName])
+	cfg, err := New(clusterName.Name, endpoint, cert, key)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode CA Cert")
-	} else if cert == nil {
-		return nil, errors.New("certificate not found in config")
+		return nil, errors.Wrap(err, "failed to generate a kubeconfig")
 	}
 
-	key, err := certs.DecodePrivateKeyPEM(clusterCA.Data[secret.TLSKeyDataName])
+	out, err := clientcmd.Write(*cfg)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode private key")
-	} else if key == nil {
-		return nil, errors.New("CA private key not found")
+		return nil, errors.Wrap(err, "failed to serialize config to yaml")
 	}
+	return out, nil
+}
 
-	cfg, err := New(clusterName.Name, endpoint, cert, key)
+func getClusterCA(ctx context.Context, c client.Reader, clusterName client.ObjectKey) (*x509.Certificate, crypto.Signer, error) {
+	clusterCA, err := secret.GetFromNamespacedName(ctx, c, clusterName, secret.ClusterCA)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to generate a kubeconfig")
+		if apierrors.IsNotFound(err) {
+			return nil, nil, ErrDependentCertificateNotFound
+		}
+		return nil, nil, err
 	}
 
-	out, err := clientcmd.Write(*cfg)
+	cert, err := certs.DecodeCertPEM(clusterCA.Data[secret.TLSCrtDataName])
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to serialize config to yaml")
+		return nil, nil, errors.Wrap(err, "failed to decode CA Cert")
+	} else if cert == nil {
+		return nil, nil, errors.New("certificate not found in config")
 	}
-	return out, nil
+
+	key, err := certs.DecodePrivateKeyPEM(clusterCA.Data[secret.TLSKeyDataName])
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode private key")
+	} else if key == nil {
+		return nil, nil, errors.New("CA private key not found")
+	}
+	return cert, key, nil
 }
 
 func toKubeconfigBytes(out *corev1.Secret) ([]byte, error) {
@@ -177,6 +177,85 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewForUser(t *testing.T) {
+	g := NewWithT(t)
+
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caCert, err := getTestCACert(caKey)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = NewForUser("foo", "https://127.0.0.1:4003", caCert, caKey, User{})
+	g.Expect(err).To(HaveOccurred())
+
+	config, err := NewForUser("foo", "https://127.0.0.1:4003", caCert, caKey, User{
+		Name:   "jane",
+		Groups: []string{"developers", "viewers"},
+		TTL:    time.Hour,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(config.CurrentContext).To(Equal("jane@foo"))
+	g.Expect(config.Contexts).To(Equal(map[string]*api.Context{
+		"jane@foo": {
+			Cluster:  "foo",
+			AuthInfo: "jane",
+		},
+	}))
+	g.Expect(config.AuthInfos).To(HaveKey("jane"))
+
+	cert, err := certs.DecodeCertPEM(config.AuthInfos["jane"].ClientCertificateData)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cert.Subject.CommonName).To(Equal("jane"))
+	g.Expect(cert.Subject.Organization).To(ConsistOf("developers", "viewers"))
+	g.Expect(cert.NotAfter).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+}
+
+func TestGenerateForUser(t *testing.T) {
+	g := NewWithT(t)
+
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caCert, err := getTestCACert(caKey)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1-ca",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			secret.TLSKeyDataName: certs.EncodePrivateKeyPEM(caKey),
+			secret.TLSCrtDataName: certs.EncodeCertPEM(caCert),
+		},
+	}
+
+	clusterKey := client.ObjectKey{Name: "test1", Namespace: "test"}
+	user := User{Name: "jane", Groups: []string{"developers"}, TTL: time.Hour}
+
+	// Fails if the Cluster CA does not exist.
+	c := fake.NewClientBuilder().WithObjects(validSecret.DeepCopy()).Build()
+	_, err = GenerateForUser(ctx, c, clusterKey, user)
+	g.Expect(err).To(MatchError(ErrDependentCertificateNotFound))
+
+	c = fake.NewClientBuilder().WithObjects(validSecret.DeepCopy(), caSecret).Build()
+	out, err := GenerateForUser(ctx, c, clusterKey, user)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	config, err := clientcmd.Load(out)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(config.CurrentContext).To(Equal("jane@test1"))
+	g.Expect(config.Clusters["test1"].Server).To(Equal("https://test-cluster-api:6443"))
+	g.Expect(config.Clusters["test1"].CertificateAuthorityData).To(Equal(certs.EncodeCertPEM(caCert)))
+
+	cert, err := certs.DecodeCertPEM(config.AuthInfos["jane"].ClientCertificateData)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cert.Subject.CommonName).To(Equal("jane"))
+	g.Expect(cert.Subject.Organization).To(ConsistOf("developers"))
+	g.Expect(cert.CheckSignatureFrom(caCert)).To(Succeed())
+}
+
 func TestGenerateSecretWithOwner(t *testing.T) {
 	g := NewWithT(t)
 
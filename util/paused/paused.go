@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package paused implements helpers for surfacing the paused state of Cluster API objects.
+package paused
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// EnsurePausedCondition sets the Paused condition on the object according to the paused state of the Cluster
+// and of the object itself, and returns true if the object is paused.
+// Expired pauses set on the object are removed; if the object is paused with an expiry, the returned duration
+// is the time until the earliest expiry, so callers can requeue and resume reconciliation when the pause ends.
+func EnsurePausedCondition(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, obj conditions.Setter) (bool, time.Duration, error) {
+	patchHelper, err := patch.NewHelper(obj, c)
+	if err != nil {
+		return false, 0, err
+	}
+
+	now := time.Now()
+	removeExpiredPauses(cluster, obj, now)
+
+	var reason string
+	var messages []string
+	var requeueAfter time.Duration
+	addExpiry := func(expiresAt time.Time) {
+		if d := expiresAt.Sub(now); requeueAfter == 0 || d < requeueAfter {
+			requeueAfter = d
+		}
+	}
+
+	switch {
+	case cluster.Spec.Paused:
+		reason = clusterv1.ClusterPausedReason
+		messages = append(messages, fmt.Sprintf("Cluster %s is paused", cluster.Name))
+	case cluster.Spec.Pause.IsActive(now):
+		reason = clusterv1.ClusterPausedReason
+		pause := cluster.Spec.Pause
+		var expiresAt *time.Time
+		if pause.ExpiresAt != nil {
+			expiresAt = &pause.ExpiresAt.Time
+			addExpiry(pause.ExpiresAt.Time)
+		}
+		messages = append(messages, pauseMessage(fmt.Sprintf("Cluster %s", cluster.Name), pause.Actor, expiresAt, pause.Reason))
+	}
+
+	if annotations.HasPaused(obj) {
+		if reason == "" {
+			reason = clusterv1.ObjectPausedReason
+		}
+		var expiresAt *time.Time
+		if until, ok := annotations.PausedUntil(obj); ok {
+			expiresAt = &until
+			addExpiry(until)
+		}
+		objAnnotations := obj.GetAnnotations()
+		messages = append(messages, pauseMessage(fmt.Sprintf("Object %s", obj.GetName()), objAnnotations[clusterv1.PausedByAnnotation], expiresAt, objAnnotations[clusterv1.PausedReasonAnnotation]))
+	}
+
+	isPaused := reason != ""
+	if isPaused {
+		conditions.Set(obj, &clusterv1.Condition{
+			Type:     clusterv1.PausedCondition,
+			Status:   corev1.ConditionTrue,
+			Severity: clusterv1.ConditionSeverityNone,
+			Reason:   reason,
+			Message:  strings.Join(messages, "; "),
+		})
+	} else {
+		conditions.Delete(obj, clusterv1.PausedCondition)
+	}
+
+	if err := patchHelper.Patch(ctx, obj, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{clusterv1.PausedCondition}}); err != nil {
+		return isPaused, requeueAfter, errors.Wrapf(err, "failed to patch %s", obj.GetName())
+	}
+	return isPaused, requeueAfter, nil
+}
+
+// removeExpiredPauses removes the paused annotations from the object if the pause has expired, as well as
+// the expired pause of the Cluster when the object being reconciled is the Cluster itself.
+func removeExpiredPauses(cluster *clusterv1.Cluster, obj client.Object, now time.Time) {
+	if until, ok := annotations.PausedUntil(obj); ok && !now.Before(until) {
+		objAnnotations := obj.GetAnnotations()
+		delete(objAnnotations, clusterv1.PausedAnnotation)
+		delete(objAnnotations, clusterv1.PausedReasonAnnotation)
+		delete(objAnnotations, clusterv1.PausedByAnnotation)
+		delete(objAnnotations, clusterv1.PausedUntilAnnotation)
+		obj.SetAnnotations(objAnnotations)
+	}
+	if c, ok := obj.(*clusterv1.Cluster); ok && c == cluster && c.Spec.Pause != nil && !c.Spec.Pause.IsActive(now) {
+		c.Spec.Pause = nil
+	}
+}
+
+// pauseMessage returns a human readable description of a pause.
+func pauseMessage(subject, actor string, expiresAt *time.Time, reason string) string {
+	msg := subject + " is paused"
+	if actor != "" {
+		msg += " by " + actor
+	}
+	if expiresAt != nil {
+		msg += " until " + expiresAt.UTC().Format(time.RFC3339)
+	}
+	if reason != "" {
+		msg += ": " + reason
+	}
+	return msg
+}
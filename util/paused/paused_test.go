@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package paused
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestEnsurePausedCondition(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+
+	ctx := context.Background()
+	expiresAt := metav1.NewTime(time.Now().Add(time.Hour))
+
+	newCluster := func() *clusterv1.Cluster {
+		return &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: metav1.NamespaceDefault}}
+	}
+	newMachine := func() *clusterv1.Machine {
+		return &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: metav1.NamespaceDefault}}
+	}
+
+	t.Run("not paused", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster, machine := newCluster(), newMachine()
+		conditions.MarkTrue(machine, clusterv1.PausedCondition)
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, machine).Build()
+
+		isPaused, requeueAfter, err := EnsurePausedCondition(ctx, c, cluster, machine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(isPaused).To(BeFalse())
+		g.Expect(requeueAfter).To(BeZero())
+
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), machine)).To(Succeed())
+		g.Expect(conditions.Has(machine, clusterv1.PausedCondition)).To(BeFalse())
+	})
+
+	t.Run("paused by the Cluster with reason, actor and expiry", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster, machine := newCluster(), newMachine()
+		cluster.Spec.Pause = &clusterv1.ClusterPause{Reason: "maintenance window", Actor: "ops", ExpiresAt: &expiresAt}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, machine).Build()
+
+		isPaused, requeueAfter, err := EnsurePausedCondition(ctx, c, cluster, machine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(isPaused).To(BeTrue())
+		g.Expect(requeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), machine)).To(Succeed())
+		g.Expect(conditions.IsTrue(machine, clusterv1.PausedCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(machine, clusterv1.PausedCondition)).To(Equal(clusterv1.ClusterPausedReason))
+		g.Expect(conditions.GetMessage(machine, clusterv1.PausedCondition)).To(Equal(
+			"Cluster cluster is paused by ops until " + expiresAt.UTC().Format(time.RFC3339) + ": maintenance window"))
+	})
+
+	t.Run("paused with annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster, machine := newCluster(), newMachine()
+		machine.Annotations = map[string]string{
+			clusterv1.PausedAnnotation:       "",
+			clusterv1.PausedReasonAnnotation: "debugging",
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, machine).Build()
+
+		isPaused, requeueAfter, err := EnsurePausedCondition(ctx, c, cluster, machine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(isPaused).To(BeTrue())
+		g.Expect(requeueAfter).To(BeZero())
+		g.Expect(conditions.GetReason(machine, clusterv1.PausedCondition)).To(Equal(clusterv1.ObjectPausedReason))
+		g.Expect(conditions.GetMessage(machine, clusterv1.PausedCondition)).To(Equal("Object machine is paused: debugging"))
+	})
+
+	t.Run("expired annotations are removed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster, machine := newCluster(), newMachine()
+		machine.Annotations = map[string]string{
+			clusterv1.PausedAnnotation:      "",
+			clusterv1.PausedByAnnotation:    "ops",
+			clusterv1.PausedUntilAnnotation: time.Now().Add(-time.Minute).Format(time.RFC3339),
+			"foo":                           "bar",
+		}
+		conditions.MarkTrue(machine, clusterv1.PausedCondition)
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, machine).Build()
+
+		isPaused, _, err := EnsurePausedCondition(ctx, c, cluster, machine)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(isPaused).To(BeFalse())
+
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), machine)).To(Succeed())
+		g.Expect(machine.Annotations).To(Equal(map[string]string{"foo": "bar"}))
+		g.Expect(conditions.Has(machine, clusterv1.PausedCondition)).To(BeFalse())
+	})
+
+	t.Run("expired Cluster pause is removed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := newCluster()
+		cluster.Spec.Pause = &clusterv1.ClusterPause{Reason: "maintenance window", ExpiresAt: &metav1.Time{Time: time.Now().Add(-time.Minute)}}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+
+		isPaused, _, err := EnsurePausedCondition(ctx, c, cluster, cluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(isPaused).To(BeFalse())
+
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		g.Expect(cluster.Spec.Pause).To(BeNil())
+	})
+}
@@ -19,8 +19,10 @@ package predicates
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -73,8 +75,8 @@ func ClusterCreateNotPaused(logger logr.Logger) predicate.Funcs {
 			}
 			log = log.WithValues("Cluster", klog.KObj(c))
 
-			// Only need to trigger a reconcile if the Cluster.Spec.Paused is false
-			if !c.Spec.Paused {
+			// Only need to trigger a reconcile if the Cluster is not paused
+			if !isClusterPaused(c) {
 				log.V(6).Info("Cluster is not paused, allowing further processing")
 				return true
 			}
@@ -120,6 +122,8 @@ func ClusterUpdateInfraReady(logger logr.Logger) predicate.Funcs {
 
 // ClusterUpdateUnpaused returns a predicate that returns true for an update event when a cluster has Spec.Paused changed from true to false
 // it also returns true if the resource provided is not a Cluster to allow for use with controller-runtime NewControllerManagedBy.
+// NOTE: The old Cluster is considered paused as long as it has a pause, even if expired, so the event removing an
+// expired pause is not dropped; the expiry itself is handled by controllers requeueing until the pause expires.
 func ClusterUpdateUnpaused(logger logr.Logger) predicate.Funcs {
 	return predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
@@ -134,7 +138,7 @@ func ClusterUpdateUnpaused(logger logr.Logger) predicate.Funcs {
 
 			newCluster := e.ObjectNew.(*clusterv1.Cluster)
 
-			if oldCluster.Spec.Paused && !newCluster.Spec.Paused {
+			if hasClusterPause(oldCluster) && !isClusterPaused(newCluster) {
 				log.V(4).Info("Cluster was unpaused, allowing further processing")
 				return true
 			}
@@ -150,8 +154,8 @@ func ClusterUpdateUnpaused(logger logr.Logger) predicate.Funcs {
 	}
 }
 
-// ClusterUnpaused returns a Predicate that returns true on Cluster creation events where Cluster.Spec.Paused is false
-// and Update events when Cluster.Spec.Paused transitions to false.
+// ClusterUnpaused returns a Predicate that returns true on Cluster creation events where the Cluster is not paused
+// and Update events when the Cluster transitions to not paused, either via Cluster.Spec.Paused or Cluster.Spec.Pause.
 // This implements a common requirement for many cluster-api and provider controllers (such as Cluster Infrastructure
 // controllers) to resume reconciliation when the Cluster is unpaused.
 // Example use:
@@ -170,6 +174,37 @@ func ClusterUnpaused(logger logr.Logger) predicate.Funcs {
 	return Any(log, ClusterCreateNotPaused(log), ClusterUpdateUnpaused(log))
 }
 
+// ClusterPauseChanged returns a predicate that returns true for an update event when Cluster.Spec.Paused or
+// Cluster.Spec.Pause changed, so that controllers surfacing the paused state on descendant objects are notified
+// both when the Cluster is paused and when it is unpaused.
+func ClusterPauseChanged(logger logr.Logger) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			log := logger.WithValues("predicate", "ClusterPauseChanged", "eventType", "update")
+
+			oldCluster, ok := e.ObjectOld.(*clusterv1.Cluster)
+			if !ok {
+				log.V(4).Info("Expected Cluster", "type", fmt.Sprintf("%T", e.ObjectOld))
+				return false
+			}
+			log = log.WithValues("Cluster", klog.KObj(oldCluster))
+
+			newCluster := e.ObjectNew.(*clusterv1.Cluster)
+
+			if oldCluster.Spec.Paused != newCluster.Spec.Paused || !equality.Semantic.DeepEqual(oldCluster.Spec.Pause, newCluster.Spec.Pause) {
+				log.V(4).Info("Cluster pause changed, allowing further processing")
+				return true
+			}
+
+			log.V(6).Info("Cluster pause did not change, blocking further processing")
+			return false
+		},
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
 // ClusterControlPlaneInitialized returns a Predicate that returns true on Update events
 // when ControlPlaneInitializedCondition on a Cluster changes to true.
 // Example use:
@@ -273,3 +308,13 @@ func processIfTopologyManaged(logger logr.Logger, object client.Object) bool {
 	log.V(6).Info("Cluster does not have topology, blocking further processing")
 	return false
 }
+
+// isClusterPaused returns true if Cluster.Spec.Paused is set or the Cluster has an active pause.
+func isClusterPaused(c *clusterv1.Cluster) bool {
+	return c.Spec.Paused || c.Spec.Pause.IsActive(time.Now())
+}
+
+// hasClusterPause returns true if Cluster.Spec.Paused is set or the Cluster has a pause, regardless of its expiry.
+func hasClusterPause(c *clusterv1.Cluster) bool {
+	return c.Spec.Paused || c.Spec.Pause != nil
+}
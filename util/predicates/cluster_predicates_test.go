@@ -18,9 +18,11 @@ package predicates_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -96,3 +98,93 @@ func TestClusterControlplaneInitializedPredicate(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterPausePredicates(t *testing.T) {
+	notPaused := clusterv1.Cluster{}
+	paused := clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Paused: true}}
+	pausedWithReason := clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Pause: &clusterv1.ClusterPause{Reason: "maintenance"}}}
+	pausedWithOtherReason := clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Pause: &clusterv1.ClusterPause{Reason: "upgrade"}}}
+	pauseExpired := clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Pause: &clusterv1.ClusterPause{
+		Reason:    "maintenance",
+		ExpiresAt: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+	}}}
+
+	testcases := []struct {
+		name           string
+		oldCluster     clusterv1.Cluster
+		newCluster     clusterv1.Cluster
+		expectUnpaused bool
+		expectChanged  bool
+	}{
+		{
+			name:       "not paused -> not paused",
+			oldCluster: notPaused,
+			newCluster: notPaused,
+		},
+		{
+			name:          "not paused -> paused",
+			oldCluster:    notPaused,
+			newCluster:    paused,
+			expectChanged: true,
+		},
+		{
+			name:           "paused -> not paused",
+			oldCluster:     paused,
+			newCluster:     notPaused,
+			expectUnpaused: true,
+			expectChanged:  true,
+		},
+		{
+			name:          "not paused -> paused with reason",
+			oldCluster:    notPaused,
+			newCluster:    pausedWithReason,
+			expectChanged: true,
+		},
+		{
+			name:           "paused with reason -> not paused",
+			oldCluster:     pausedWithReason,
+			newCluster:     notPaused,
+			expectUnpaused: true,
+			expectChanged:  true,
+		},
+		{
+			name:          "paused with reason -> paused with another reason",
+			oldCluster:    pausedWithReason,
+			newCluster:    pausedWithOtherReason,
+			expectChanged: true,
+		},
+		{
+			name:       "paused with reason -> paused with reason",
+			oldCluster: pausedWithReason,
+			newCluster: pausedWithReason,
+		},
+		{
+			name:           "paused with reason -> pause expired",
+			oldCluster:     pausedWithReason,
+			newCluster:     pauseExpired,
+			expectUnpaused: true,
+			expectChanged:  true,
+		},
+		{
+			name:           "pause expired -> not paused",
+			oldCluster:     pauseExpired,
+			newCluster:     notPaused,
+			expectUnpaused: true,
+			expectChanged:  true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ev := event.UpdateEvent{
+				ObjectOld: &tc.oldCluster,
+				ObjectNew: &tc.newCluster,
+			}
+
+			g.Expect(predicates.ClusterUnpaused(logr.New(log.NullLogSink{})).Update(ev)).To(Equal(tc.expectUnpaused))
+			g.Expect(predicates.ClusterPauseChanged(logr.New(log.NullLogSink{})).Update(ev)).To(Equal(tc.expectChanged))
+		})
+	}
+}
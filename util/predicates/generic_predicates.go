@@ -148,7 +148,7 @@ func ResourceHasFilterLabel(logger logr.Logger, labelValue string) predicate.Fun
 }
 
 // ResourceNotPaused returns a Predicate that returns true only if the provided resource does not contain the
-// paused annotation.
+// paused annotation, or for update events where the paused annotation has been added or removed.
 // This implements a common requirement for all cluster-api and provider controllers skip reconciliation when the paused
 // annotation is present for a resource.
 // Example use:
@@ -164,6 +164,10 @@ func ResourceHasFilterLabel(logger logr.Logger, labelValue string) predicate.Fun
 func ResourceNotPaused(logger logr.Logger) predicate.Funcs {
 	return predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
+			// Changes to the paused state are always processed, so controllers can report it on the resource.
+			if annotations.HasPaused(e.ObjectOld) != annotations.HasPaused(e.ObjectNew) {
+				return true
+			}
 			return processIfNotPaused(logger.WithValues("predicate", "ResourceNotPaused", "eventType", "update"), e.ObjectNew)
 		},
 		CreateFunc: func(e event.CreateEvent) bool {
@@ -46,3 +46,11 @@ func (webhook *ClusterClass) SetupWebhookWithManager(mgr ctrl.Manager) error {
 		Client: webhook.Client,
 	}).SetupWebhookWithManager(mgr)
 }
+
+// MachineHealthCheck implements a validation and defaulting webhook for MachineHealthCheck.
+type MachineHealthCheck struct{}
+
+// SetupWebhookWithManager sets up MachineHealthCheck webhooks.
+func (webhook *MachineHealthCheck) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return (&webhooks.MachineHealthCheck{}).SetupWebhookWithManager(mgr)
+}